@@ -3,6 +3,7 @@ package main
 import (
 	"context"
 	"errors"
+	"io"
 	"os"
 	"os/signal"
 	"syscall"
@@ -12,7 +13,10 @@ import (
 	"github.com/volantvm/volant/internal/drift/controller"
 	"github.com/volantvm/volant/internal/drift/dataplane"
 	"github.com/volantvm/volant/internal/drift/httpapi"
+	"github.com/volantvm/volant/internal/drift/l7proxy"
 	"github.com/volantvm/volant/internal/drift/routes"
+	etcdroutes "github.com/volantvm/volant/internal/drift/routes/etcd"
+	sqliteroutes "github.com/volantvm/volant/internal/drift/routes/sqlite"
 	"github.com/volantvm/volant/internal/drift/vsockproxy"
 	"github.com/volantvm/volant/internal/shared/logging"
 )
@@ -34,11 +38,14 @@ func main() {
 		os.Exit(1)
 	}
 
-	store, err := routes.NewFileStore(cfg.RoutesPath)
+	store, err := openRouteStore(ctx, cfg)
 	if err != nil {
-		logger.Error("init route store", "error", err)
+		logger.Error("init route store", "store", cfg.RouteStore, "error", err)
 		os.Exit(1)
 	}
+	if closer, ok := store.(io.Closer); ok {
+		defer closer.Close()
+	}
 
 	var dp dataplane.Interface
 	if manager, err := dataplane.New(dataplane.Options{
@@ -58,7 +65,7 @@ func main() {
 	}
 
 	var vsockMgr vsockproxy.Manager
-	if mgr, err := vsockproxy.New(vsockproxy.Options{Logger: logger}); err != nil {
+	if mgr, err := vsockproxy.New(vsockproxy.Options{SocketDir: cfg.SocketDir, Logger: logger}); err != nil {
 		if errors.Is(err, vsockproxy.ErrUnsupported) {
 			logger.Warn("vsock proxy unavailable on this platform", "error", err)
 		} else {
@@ -70,14 +77,23 @@ func main() {
 		defer vsockMgr.Close()
 	}
 
-	ctrl := controller.New(store, dp, vsockMgr)
+	l7Mgr := l7proxy.New(l7proxy.Options{Logger: logger})
+	defer l7Mgr.Close()
+
+	ctrl := controller.New(store, dp, vsockMgr, l7Mgr)
 	if err := ctrl.Restore(context.Background()); err != nil {
 		logger.Error("restore routes", "error", err)
 		os.Exit(1)
 	}
 
+	go func() {
+		if err := ctrl.Watch(ctx, logger); err != nil && !errors.Is(err, context.Canceled) {
+			logger.Error("watch route store", "error", err)
+		}
+	}()
+
 	handler := httpapi.New(ctrl)
-	daemon := app.New(cfg, logger, handler)
+	daemon := app.New(cfg, logger, handler, httpapi.Metrics(ctrl))
 
 	if err := daemon.Run(ctx); err != nil && !errors.Is(err, context.Canceled) {
 		logger.Error("daemon exit", "error", err)
@@ -85,3 +101,21 @@ func main() {
 	}
 	logger.Info("shutdown complete", "addr", cfg.HTTPListen)
 }
+
+// openRouteStore opens the route store backend cfg selects.
+func openRouteStore(ctx context.Context, cfg config.Config) (routes.Store, error) {
+	switch cfg.RouteStore {
+	case config.RouteStoreSQLite:
+		return sqliteroutes.NewStore(cfg.RoutesPath)
+	case config.RouteStoreEtcd:
+		return etcdroutes.NewStore(ctx, etcdroutes.Options{
+			Endpoints: cfg.Etcd.Endpoints,
+			Prefix:    cfg.Etcd.Prefix,
+			CAFile:    cfg.Etcd.CAFile,
+			CertFile:  cfg.Etcd.CertFile,
+			KeyFile:   cfg.Etcd.KeyFile,
+		})
+	default:
+		return routes.NewFileStore(cfg.RoutesPath)
+	}
+}
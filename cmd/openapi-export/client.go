@@ -0,0 +1,465 @@
+// Copyright (c) 2025 HYPR. PTE. LTD.
+//
+// Business Source License 1.1
+// See LICENSE file in the project root for details.
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	openapi3 "github.com/getkin/kin-openapi/openapi3"
+)
+
+// clientAPI is the part of the OpenAPI document a generated client needs:
+// named types and operations, in a stable order.
+type clientAPI struct {
+	Title       string
+	Description string
+	APIVersion  string
+	ServerURL   string
+	// AuthHeader carries the API key, when the document declares one.
+	AuthHeader string
+	Types      []*namedType
+	Operations []*clientOperation
+
+	names map[*openapi3.Schema]string
+	taken map[string]bool
+}
+
+// namedType is a component schema, or an object schema nested in an
+// operation or another type that needs a name of its own.
+type namedType struct {
+	Name   string
+	Schema *openapi3.Schema
+}
+
+type clientOperation struct {
+	ID          string
+	Method      string
+	Path        string
+	Summary     string
+	Description string
+	Deprecated  bool
+	// PathParams are in the order they appear in Path.
+	PathParams []*clientParam
+	// Params are the query and header parameters.
+	Params []*clientParam
+	Body   *clientBody
+	Result clientResult
+}
+
+type clientParam struct {
+	Name        string
+	In          string
+	Description string
+	Required    bool
+	// Slashes marks path parameters whose value may span segments.
+	Slashes bool
+	Schema  *openapi3.SchemaRef
+}
+
+type clientBody struct {
+	Binary   bool
+	Required bool
+	Schema   *openapi3.SchemaRef
+}
+
+type resultKind int
+
+const (
+	resultNone resultKind = iota
+	resultJSON
+	resultBinary
+	resultStream
+)
+
+type clientResult struct {
+	Kind resultKind
+	// Schemas are the distinct JSON bodies of the success responses.
+	Schemas []*openapi3.SchemaRef
+	// Empty is set when some success response has no body.
+	Empty bool
+}
+
+// clientFile is one file of a generated package, relative to its root.
+type clientFile struct {
+	Path string
+	Data []byte
+}
+
+var methodOrder = []string{"GET", "HEAD", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"}
+
+var pathParamPattern = regexp.MustCompile(`\{([^}]+)\}`)
+
+// newClientAPI collects the types and operations of spec. Websocket
+// endpoints are left out: the generated clients speak plain HTTP.
+func newClientAPI(spec *openapi3.T) (*clientAPI, error) {
+	api := &clientAPI{
+		names: map[*openapi3.Schema]string{},
+		taken: map[string]bool{},
+	}
+	if spec.Info != nil {
+		api.Title = spec.Info.Title
+		api.Description = spec.Info.Description
+		api.APIVersion = spec.Info.Version
+	}
+	if len(spec.Servers) > 0 {
+		api.ServerURL = spec.Servers[0].URL
+	}
+
+	var components openapi3.Schemas
+	if spec.Components != nil {
+		components = spec.Components.Schemas
+		for _, name := range sortedKeys(spec.Components.SecuritySchemes) {
+			scheme := spec.Components.SecuritySchemes[name].Value
+			if scheme != nil && scheme.Type == "apiKey" && scheme.In == "header" {
+				api.AuthHeader = scheme.Name
+				break
+			}
+		}
+	}
+	componentNames := sortedKeys(components)
+	for _, name := range componentNames {
+		ref := components[name]
+		if ref == nil || ref.Value == nil {
+			continue
+		}
+		if exportName(name) != name {
+			return nil, fmt.Errorf("component schema %q is not an exported name", name)
+		}
+		api.addType(name, ref.Value)
+	}
+	for _, name := range componentNames {
+		if ref := components[name]; ref != nil && ref.Value != nil {
+			api.nameChildren(ref.Value, name)
+		}
+	}
+
+	paths := spec.Paths.Map()
+	for _, path := range sortedKeys(paths) {
+		if strings.HasPrefix(path, "/ws/") {
+			continue
+		}
+		ops := paths[path].Operations()
+		for _, method := range methodOrder {
+			op := ops[method]
+			if op == nil {
+				continue
+			}
+			cop, err := api.operation(path, method, op)
+			if err != nil {
+				return nil, fmt.Errorf("%s %s: %w", method, path, err)
+			}
+			api.Operations = append(api.Operations, cop)
+		}
+	}
+	return api, nil
+}
+
+func (api *clientAPI) operation(path, method string, op *openapi3.Operation) (*clientOperation, error) {
+	if op.OperationID == "" {
+		return nil, fmt.Errorf("missing operationId")
+	}
+	cop := &clientOperation{
+		ID:          op.OperationID,
+		Method:      method,
+		Path:        path,
+		Summary:     strings.TrimSpace(op.Summary),
+		Description: strings.TrimSpace(op.Description),
+		Deprecated:  op.Deprecated,
+	}
+	typeName := exportName(op.OperationID)
+
+	byName := map[string]*clientParam{}
+	for _, ref := range op.Parameters {
+		p := ref.Value
+		if p == nil {
+			continue
+		}
+		param := &clientParam{
+			Name:        p.Name,
+			In:          p.In,
+			Description: strings.TrimSpace(p.Description),
+			Required:    p.Required || p.In == openapi3.ParameterInPath,
+			Schema:      p.Schema,
+		}
+		if slashes, ok := p.Extensions["x-slashes"].(bool); ok {
+			param.Slashes = slashes
+		}
+		switch p.In {
+		case openapi3.ParameterInPath:
+			byName[p.Name] = param
+		case openapi3.ParameterInQuery, openapi3.ParameterInHeader:
+			cop.Params = append(cop.Params, param)
+		default:
+			continue
+		}
+		api.nameSchemas(p.Schema, typeName+exportName(p.Name))
+	}
+	for _, match := range pathParamPattern.FindAllStringSubmatch(path, -1) {
+		param := byName[match[1]]
+		if param == nil {
+			return nil, fmt.Errorf("path parameter %q is not declared", match[1])
+		}
+		cop.PathParams = append(cop.PathParams, param)
+	}
+
+	if op.RequestBody != nil && op.RequestBody.Value != nil {
+		body := op.RequestBody.Value
+		if media := body.Content.Get("application/json"); media != nil {
+			cop.Body = &clientBody{Required: body.Required, Schema: media.Schema}
+			api.nameSchemas(media.Schema, typeName+"Request")
+		} else if media := body.Content.Get("application/octet-stream"); media != nil {
+			cop.Body = &clientBody{Binary: true, Required: true, Schema: media.Schema}
+		} else {
+			return nil, fmt.Errorf("unsupported request body types %v", sortedKeys(body.Content))
+		}
+	}
+
+	responses := op.Responses.Map()
+	for _, code := range sortedKeys(responses) {
+		if len(code) != 3 || code[0] != '2' || responses[code].Value == nil {
+			continue
+		}
+		content := responses[code].Value.Content
+		switch {
+		case len(content) == 0:
+			cop.Result.Empty = true
+		case content.Get("application/json") != nil:
+			schema := content.Get("application/json").Schema
+			if !slices.ContainsFunc(cop.Result.Schemas, func(s *openapi3.SchemaRef) bool { return sameSchema(s, schema) }) {
+				cop.Result.Schemas = append(cop.Result.Schemas, schema)
+			}
+			cop.Result.Kind = max(cop.Result.Kind, resultJSON)
+		case content.Get("application/octet-stream") != nil:
+			cop.Result.Kind = max(cop.Result.Kind, resultBinary)
+		case content.Get("text/event-stream") != nil:
+			cop.Result.Kind = max(cop.Result.Kind, resultStream)
+		default:
+			return nil, fmt.Errorf("unsupported response types %v", sortedKeys(content))
+		}
+	}
+	if cop.Result.Kind != resultJSON {
+		cop.Result.Schemas = nil
+	}
+	for i, schema := range cop.Result.Schemas {
+		name := typeName + "Response"
+		if i > 0 {
+			name += strconv.Itoa(i + 1)
+		}
+		api.nameSchemas(schema, name)
+	}
+	return cop, nil
+}
+
+// nameSchemas names the inline object schemas in ref, calling the outermost
+// one name.
+func (api *clientAPI) nameSchemas(ref *openapi3.SchemaRef, name string) {
+	if ref == nil || ref.Ref != "" || ref.Value == nil {
+		return
+	}
+	s := ref.Value
+	if _, ok := api.names[s]; ok {
+		return
+	}
+	if len(s.Properties) > 0 {
+		name = api.addType(name, s)
+	}
+	api.nameChildren(s, name)
+}
+
+func (api *clientAPI) nameChildren(s *openapi3.Schema, name string) {
+	for _, prop := range sortedKeys(s.Properties) {
+		api.nameSchemas(s.Properties[prop], name+exportName(prop))
+	}
+	api.nameSchemas(s.Items, name+"Item")
+	api.nameSchemas(s.AdditionalProperties.Schema, name+"Value")
+	for _, members := range []openapi3.SchemaRefs{s.OneOf, s.AnyOf, s.AllOf} {
+		for i, member := range members {
+			api.nameSchemas(member, name+"Variant"+strconv.Itoa(i+1))
+		}
+	}
+}
+
+// addType names s, numbering the name when it is already taken.
+func (api *clientAPI) addType(name string, s *openapi3.Schema) string {
+	unique := name
+	for i := 2; api.taken[unique]; i++ {
+		unique = name + strconv.Itoa(i)
+	}
+	api.taken[unique] = true
+	api.names[s] = unique
+	api.Types = append(api.Types, &namedType{Name: unique, Schema: s})
+	return unique
+}
+
+// typeName returns the name of ref's schema, if it has one.
+func (api *clientAPI) typeName(ref *openapi3.SchemaRef) (string, bool) {
+	if ref.Ref != "" {
+		return strings.TrimPrefix(ref.Ref, "#/components/schemas/"), true
+	}
+	name, ok := api.names[ref.Value]
+	return name, ok
+}
+
+func sameSchema(a, b *openapi3.SchemaRef) bool {
+	if a.Ref != "" || b.Ref != "" {
+		return a.Ref == b.Ref
+	}
+	return a.Value == b.Value
+}
+
+// isObject reports whether s is rendered as a record type with fields.
+func isObject(s *openapi3.Schema) bool {
+	return len(s.Properties) > 0
+}
+
+// identWords splits a parameter or field name into lower-case words. The
+// X-Volant- prefix of header names is dropped.
+func identWords(name string) []string {
+	if len(name) > len("X-Volant-") && strings.EqualFold(name[:len("X-Volant-")], "X-Volant-") {
+		name = name[len("X-Volant-"):]
+	}
+	var words []string
+	for _, word := range strings.FieldsFunc(name, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		for _, part := range splitCamel(word) {
+			words = append(words, strings.ToLower(part))
+		}
+	}
+	return words
+}
+
+// splitCamel splits a camel-case word, keeping runs of capitals together:
+// getVMByName becomes get, VM, By, Name and listVMs list, VMs.
+func splitCamel(word string) []string {
+	runes := []rune(word)
+	var parts []string
+	start := 0
+	for i := 1; i < len(runes); i++ {
+		prev, cur := runes[i-1], runes[i]
+		next := rune(0)
+		if i+1 < len(runes) {
+			next = runes[i+1]
+		}
+		// A lone s after capitals pluralizes them, as in VMs.
+		plural := next == 's' && (i+2 == len(runes) || unicode.IsUpper(runes[i+2]))
+		if unicode.IsUpper(cur) && (!unicode.IsUpper(prev) || (next != 0 && unicode.IsLower(next) && !plural)) {
+			parts = append(parts, string(runes[start:i]))
+			start = i
+		}
+	}
+	parts = append(parts, string(runes[start:]))
+
+	var words []string
+	for _, part := range parts {
+		words = append(words, splitAcronyms(part)...)
+	}
+	return words
+}
+
+// acronyms are the capital runs of operation names that splitAcronyms
+// can tell apart, longest first.
+var acronyms = []string{"IOMMU", "AGUI", "DHCP", "VFIO", "API", "MCP", "SSH", "GC", "ID", "VM"}
+
+// splitAcronyms splits a run of capitals made of known acronyms, so that
+// getVMSSH becomes get_vm_ssh rather than get_vmssh. Other runs are kept.
+func splitAcronyms(run string) []string {
+	body, plural := strings.CutSuffix(run, "s")
+	if body == "" || strings.ToUpper(body) != body {
+		return []string{run}
+	}
+	var words []string
+	for body != "" {
+		i := slices.IndexFunc(acronyms, func(a string) bool { return strings.HasPrefix(body, a) })
+		if i < 0 {
+			return []string{run}
+		}
+		words = append(words, acronyms[i])
+		body = body[len(acronyms[i]):]
+	}
+	if plural {
+		words[len(words)-1] += "s"
+	}
+	return words
+}
+
+func camelName(name string) string {
+	words := identWords(name)
+	for i := 1; i < len(words); i++ {
+		words[i] = upperFirst(words[i])
+	}
+	return strings.Join(words, "")
+}
+
+func snakeName(name string) string {
+	return strings.Join(identWords(name), "_")
+}
+
+// exportName turns a field or operation name into a type name: cpu_cores
+// becomes CpuCores and listVMs ListVMs.
+func exportName(name string) string {
+	var b strings.Builder
+	for _, word := range strings.FieldsFunc(name, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		b.WriteString(upperFirst(word))
+	}
+	return b.String()
+}
+
+func upperFirst(s string) string {
+	if s == "" {
+		return s
+	}
+	r := []rune(s)
+	r[0] = unicode.ToUpper(r[0])
+	return string(r)
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// docLines splits a description into lines with trailing space removed.
+func docLines(text string) []string {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return nil
+	}
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight(line, " \t")
+	}
+	return lines
+}
+
+// writeClientFiles writes the files of a generated package under dir,
+// leaving other files there alone.
+func writeClientFiles(dir string, files []clientFile) error {
+	for _, file := range files {
+		path := filepath.Join(dir, filepath.FromSlash(file.Path))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			return err
+		}
+		if err := os.WriteFile(path, file.Data, 0o644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
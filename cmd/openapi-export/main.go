@@ -6,6 +6,7 @@
 package main
 
 import (
+	"bytes"
 	"encoding/json"
 	"flag"
 	"fmt"
@@ -13,6 +14,8 @@ import (
 	"strings"
 
 	openapi3 "github.com/getkin/kin-openapi/openapi3"
+	"gopkg.in/yaml.v3"
+
 	httpapi "github.com/volantvm/volant/internal/server/httpapi"
 )
 
@@ -21,11 +24,17 @@ func main() {
 		outPath   string
 		format    string
 		serverURL string
+		lang      string
+		pkgName   string
+		version   string
 	)
 
-	flag.StringVar(&outPath, "output", "", "Output path (default stdout)")
-	flag.StringVar(&format, "format", "json", "Output format: json")
+	flag.StringVar(&outPath, "output", "", "Output path (default stdout); the package directory with -lang")
+	flag.StringVar(&format, "format", "json", "Output format: json or yaml")
 	flag.StringVar(&serverURL, "server", "http://127.0.0.1:7777", "Server URL to include in OpenAPI servers list")
+	flag.StringVar(&lang, "lang", "", "Generate a client package instead of the spec: typescript or python")
+	flag.StringVar(&pkgName, "package", "", "Client package name (default @volantvm/client or volant-client)")
+	flag.StringVar(&version, "version", "0.1.0", "Client package version")
 	flag.Parse()
 
 	// Build spec using the same generator used by the HTTP handler
@@ -40,6 +49,16 @@ func main() {
 		spec.Servers = openapi3.Servers{&openapi3.Server{URL: serverURL}}
 	}
 
+	if lang != "" {
+		if outPath == "" {
+			fatalf("-lang needs -output, the directory to write the package to")
+		}
+		if err := generateClient(spec, strings.ToLower(lang), pkgName, version, outPath); err != nil {
+			fatalf("generate %s client: %v", lang, err)
+		}
+		return
+	}
+
 	// Marshal
 	var data []byte
 	switch strings.ToLower(format) {
@@ -48,8 +67,19 @@ func main() {
 		if err != nil {
 			fatalf("marshal json: %v", err)
 		}
+	case "yaml", "yml":
+		var buf bytes.Buffer
+		enc := yaml.NewEncoder(&buf)
+		enc.SetIndent(2)
+		if err := enc.Encode(spec); err != nil {
+			fatalf("marshal yaml: %v", err)
+		}
+		if err := enc.Close(); err != nil {
+			fatalf("marshal yaml: %v", err)
+		}
+		data = buf.Bytes()
 	default:
-		fatalf("unsupported format: %s (only json supported)", format)
+		fatalf("unsupported format: %s (json or yaml)", format)
 	}
 
 	// Write
@@ -62,6 +92,33 @@ func main() {
 	}
 }
 
+// generateClient writes a client package for the API in lang to dir.
+func generateClient(spec *openapi3.T, lang, pkgName, version, dir string) error {
+	api, err := newClientAPI(spec)
+	if err != nil {
+		return err
+	}
+	var files []clientFile
+	switch lang {
+	case "typescript", "ts":
+		if pkgName == "" {
+			pkgName = "@volantvm/client"
+		}
+		files, err = typeScriptClient(api, pkgName, version)
+	case "python", "py":
+		if pkgName == "" {
+			pkgName = "volant-client"
+		}
+		files, err = pythonClient(api, pkgName, version)
+	default:
+		return fmt.Errorf("unsupported language %q (typescript or python)", lang)
+	}
+	if err != nil {
+		return err
+	}
+	return writeClientFiles(dir, files)
+}
+
 func fatalf(format string, args ...any) {
 	fmt.Fprintf(os.Stderr, format+"\n", args...)
 	os.Exit(1)
@@ -0,0 +1,506 @@
+// Copyright (c) 2025 HYPR. PTE. LTD.
+//
+// Business Source License 1.1
+// See LICENSE file in the project root for details.
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	openapi3 "github.com/getkin/kin-openapi/openapi3"
+)
+
+// pyReserved are the words a generated argument name must not be.
+var pyReserved = map[string]bool{
+	"self": true, "body": true, "timeout": true, "headers": true,
+	"False": true, "None": true, "True": true, "and": true, "as": true, "assert": true,
+	"async": true, "await": true, "break": true, "class": true, "continue": true, "def": true,
+	"del": true, "elif": true, "else": true, "except": true, "finally": true, "for": true,
+	"from": true, "global": true, "if": true, "import": true, "in": true, "is": true,
+	"lambda": true, "nonlocal": true, "not": true, "or": true, "pass": true, "raise": true,
+	"return": true, "try": true, "while": true, "with": true, "yield": true,
+}
+
+// pythonClient generates a Python package: the types of the API as
+// TypedDicts in types.py and a Client built on urllib in client.py. It
+// needs nothing outside the standard library.
+func pythonClient(api *clientAPI, pkg, version string) ([]clientFile, error) {
+	module := strings.ReplaceAll(pkg, "-", "_")
+	g := &pyGen{api: api}
+	var project bytes.Buffer
+	project.WriteString("[build-system]\nrequires = [\"setuptools>=61\"]\nbuild-backend = \"setuptools.build_meta\"\n\n")
+	project.WriteString("[project]\n")
+	fmt.Fprintf(&project, "name = %s\nversion = %s\n", jsonString(pkg), jsonString(version))
+	fmt.Fprintf(&project, "description = %s\n", jsonString(fmt.Sprintf("Python client for the %s, generated from its OpenAPI document.", api.Title)))
+	project.WriteString("readme = \"README.md\"\nrequires-python = \">=3.11\"\nlicense = { text = \"BUSL-1.1\" }\ndependencies = []\n\n")
+	fmt.Fprintf(&project, "[tool.setuptools]\npackages = [%s]\n\n", jsonString(module))
+	fmt.Fprintf(&project, "[tool.setuptools.package-data]\n%s = [\"py.typed\"]\n", module)
+
+	return []clientFile{
+		{Path: "pyproject.toml", Data: project.Bytes()},
+		{Path: "README.md", Data: g.readme(pkg, module)},
+		{Path: module + "/__init__.py", Data: []byte(g.header() + "from .client import ApiError, Client\nfrom .types import *  # noqa: F401,F403\n\n__all__ = [\"ApiError\", \"Client\"]\n")},
+		{Path: module + "/py.typed", Data: nil},
+		{Path: module + "/types.py", Data: g.types()},
+		{Path: module + "/client.py", Data: g.client()},
+	}, nil
+}
+
+type pyGen struct {
+	api *clientAPI
+	// quote renders named types as forward references, which types.py
+	// needs since its TypedDicts refer to each other in any order.
+	quote bool
+	// used collects the named types the client refers to.
+	used map[string]bool
+}
+
+func (g *pyGen) header() string {
+	return fmt.Sprintf("# Code generated by openapi-export from the %s %s. DO NOT EDIT.\n\n", g.api.Title, g.api.APIVersion)
+}
+
+func (g *pyGen) types() []byte {
+	g.quote = true
+	defer func() { g.quote = false }()
+
+	var b bytes.Buffer
+	b.WriteString(g.header())
+	b.WriteString("from typing import Any, Literal, Optional, Required, TypedDict, Union\n")
+	for _, t := range g.api.Types {
+		b.WriteString("\n")
+		writePyComment(&b, "", docLines(t.Schema.Description), t.Schema.Deprecated)
+		if !isObject(t.Schema) {
+			fmt.Fprintf(&b, "%s = %s\n", t.Name, g.expr(t.Schema))
+			continue
+		}
+		// The functional form allows keys that are not identifiers.
+		fmt.Fprintf(&b, "%s = TypedDict(\n    %s,\n    {\n", t.Name, jsonString(t.Name))
+		for _, prop := range sortedKeys(t.Schema.Properties) {
+			ref := t.Schema.Properties[prop]
+			if ref.Value != nil {
+				writePyComment(&b, "        ", docLines(ref.Value.Description), ref.Value.Deprecated)
+			}
+			typ := g.typeOf(ref)
+			if slices.Contains(t.Schema.Required, prop) {
+				typ = "Required[" + typ + "]"
+			}
+			fmt.Fprintf(&b, "        %s: %s,\n", jsonString(prop), typ)
+		}
+		b.WriteString("    },\n    total=False,\n)\n")
+	}
+	return b.Bytes()
+}
+
+// typeOf renders ref, by name when it has one.
+func (g *pyGen) typeOf(ref *openapi3.SchemaRef) string {
+	if ref == nil {
+		return "Any"
+	}
+	if name, ok := g.api.typeName(ref); ok {
+		if g.used != nil {
+			g.used[name] = true
+		}
+		if g.quote {
+			return jsonString(name)
+		}
+		return name
+	}
+	if ref.Value == nil {
+		return "Any"
+	}
+	return g.expr(ref.Value)
+}
+
+// expr renders s structurally.
+func (g *pyGen) expr(s *openapi3.Schema) string {
+	var t string
+	switch {
+	case len(s.OneOf) > 0:
+		t = g.union(s.OneOf)
+	case len(s.AnyOf) > 0:
+		t = g.union(s.AnyOf)
+	case len(s.AllOf) > 0:
+		// TypedDicts cannot be intersected.
+		t = "dict[str, Any]"
+	case len(s.Enum) > 0:
+		values := make([]string, 0, len(s.Enum))
+		for _, value := range s.Enum {
+			values = append(values, pyLiteral(value))
+		}
+		t = "Literal[" + strings.Join(values, ", ") + "]"
+	case s.Type.Includes(openapi3.TypeString):
+		t = "str"
+		if s.Format == "binary" {
+			t = "bytes"
+		}
+	case s.Type.Includes(openapi3.TypeInteger):
+		t = "int"
+	case s.Type.Includes(openapi3.TypeNumber):
+		t = "float"
+	case s.Type.Includes(openapi3.TypeBoolean):
+		t = "bool"
+	case s.Type.Includes(openapi3.TypeArray):
+		t = "list[" + g.typeOf(s.Items) + "]"
+	case s.Type.Includes(openapi3.TypeObject):
+		if s.AdditionalProperties.Schema != nil {
+			t = "dict[str, " + g.typeOf(s.AdditionalProperties.Schema) + "]"
+		} else {
+			t = "dict[str, Any]"
+		}
+	default:
+		t = "Any"
+	}
+	if (s.Nullable || s.Type.Includes(openapi3.TypeNull)) && t != "Any" {
+		t = "Optional[" + t + "]"
+	}
+	return t
+}
+
+func (g *pyGen) union(refs openapi3.SchemaRefs) string {
+	parts := make([]string, 0, len(refs))
+	for _, ref := range refs {
+		parts = append(parts, g.typeOf(ref))
+	}
+	return "Union[" + strings.Join(parts, ", ") + "]"
+}
+
+func (g *pyGen) client() []byte {
+	g.used = map[string]bool{}
+	var methods bytes.Buffer
+	for _, op := range g.api.Operations {
+		g.operation(&methods, op)
+	}
+
+	var b bytes.Buffer
+	b.WriteString(g.header())
+	b.WriteString(pyRuntime)
+	if names := sortedKeys(g.used); len(names) > 0 {
+		b.WriteString("from .types import (\n")
+		for _, name := range names {
+			fmt.Fprintf(&b, "    %s,\n", name)
+		}
+		b.WriteString(")\n")
+	}
+	b.WriteString(strings.ReplaceAll(pyRuntimeClasses, "__AUTH_HEADER__", jsonString(g.api.AuthHeader)))
+	b.Write(methods.Bytes())
+	return b.Bytes()
+}
+
+func (g *pyGen) operation(b *bytes.Buffer, op *clientOperation) {
+	args := []string{"self"}
+	for _, p := range op.PathParams {
+		args = append(args, pyParamName(p.Name)+": "+g.typeOf(p.Schema))
+	}
+	if op.Body != nil {
+		bodyType := "Union[bytes, BinaryIO]"
+		if !op.Body.Binary {
+			bodyType = g.typeOf(op.Body.Schema)
+		}
+		if op.Body.Required {
+			args = append(args, "body: "+bodyType)
+		} else {
+			args = append(args, "body: Optional["+bodyType+"] = None")
+		}
+	}
+	args = append(args, "*")
+	for _, p := range op.Params {
+		if p.Required {
+			args = append(args, pyParamName(p.Name)+": "+g.typeOf(p.Schema))
+		}
+	}
+	for _, p := range op.Params {
+		if !p.Required {
+			args = append(args, pyParamName(p.Name)+": Optional["+g.typeOf(p.Schema)+"] = None")
+		}
+	}
+	args = append(args, "timeout: Optional[float] = None")
+
+	fmt.Fprintf(b, "\n    def %s(\n", snakeName(op.ID))
+	for _, arg := range args {
+		fmt.Fprintf(b, "        %s,\n", arg)
+	}
+	fmt.Fprintf(b, "    ) -> %s:\n", g.resultType(op.Result))
+
+	var doc []string
+	doc = append(doc, docLines(op.Summary)...)
+	if lines := docLines(op.Description); len(lines) > 0 {
+		if len(doc) > 0 {
+			doc = append(doc, "")
+		}
+		doc = append(doc, lines...)
+	}
+	if op.Deprecated {
+		doc = append(doc, "", "Deprecated.")
+	}
+	writePyDocstring(b, "        ", doc)
+
+	path := op.Path
+	for _, p := range op.PathParams {
+		encode := "_segment"
+		if p.Slashes {
+			encode = "_path"
+		}
+		path = strings.Replace(path, "{"+p.Name+"}", "{"+encode+"("+pyParamName(p.Name)+")}", 1)
+	}
+	fmt.Fprintf(b, "        return self._call(\n            %s,\n            f%s,\n", jsonString(op.Method), jsonString(path))
+	for _, in := range []string{openapi3.ParameterInQuery, openapi3.ParameterInHeader} {
+		var values []string
+		for _, p := range op.Params {
+			if p.In == in {
+				values = append(values, jsonString(p.Name)+": "+pyParamName(p.Name))
+			}
+		}
+		if len(values) > 0 {
+			fmt.Fprintf(b, "            %s={%s},\n", map[string]string{"query": "query", "header": "headers"}[in], strings.Join(values, ", "))
+		}
+	}
+	if op.Body != nil {
+		b.WriteString("            body=body,\n")
+		if op.Body.Binary {
+			b.WriteString("            binary=True,\n")
+		}
+	}
+	fmt.Fprintf(b, "            result=%s,\n            timeout=timeout,\n        )\n", jsonString(resultName(op.Result.Kind)))
+}
+
+func (g *pyGen) resultType(result clientResult) string {
+	switch result.Kind {
+	case resultBinary:
+		return "bytes"
+	case resultStream:
+		return "http.client.HTTPResponse"
+	case resultJSON:
+		parts := make([]string, 0, len(result.Schemas))
+		for _, schema := range result.Schemas {
+			parts = append(parts, g.typeOf(schema))
+		}
+		t := parts[0]
+		if len(parts) > 1 {
+			t = "Union[" + strings.Join(parts, ", ") + "]"
+		}
+		if result.Empty {
+			t = "Optional[" + t + "]"
+		}
+		return t
+	}
+	return "None"
+}
+
+func (g *pyGen) readme(pkg, module string) []byte {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "# %s\n\n", pkg)
+	fmt.Fprintf(&b, "Python client for the %s (%s), generated from its OpenAPI document by `openapi-export -lang python`. Do not edit it by hand; regenerate it with `make client-python`.\n\n", g.api.Title, g.api.APIVersion)
+	b.WriteString("It needs Python 3.11 or later and nothing outside the standard library.\n\n")
+	b.WriteString("## Build\n\n```sh\npython -m build\npython -m twine upload dist/*\n```\n\n")
+	b.WriteString("## Usage\n\n```python\nimport os\n\n")
+	fmt.Fprintf(&b, "from %s import ApiError, Client\n\n", module)
+	fmt.Fprintf(&b, "volant = Client(%s, api_key=os.environ.get(\"VOLANT_API_KEY\"))\n\n", jsonString(g.serverURL()))
+	b.WriteString("try:\n    for vm in volant.list_vms(status=\"running\"):\n        print(vm[\"name\"])\nexcept ApiError as err:\n    if err.code == \"UNAUTHORIZED\":\n        print(\"bad API key\")\n    raise\n```\n\n")
+	b.WriteString("Every method is the operationId of its endpoint in snake case, and responses are plain dicts typed with TypedDicts. Failed requests raise `ApiError` carrying the HTTP status and the stable error `code`. Event streams return the open `http.client.HTTPResponse`; read it as server-sent events and close it. Websocket endpoints are not covered.\n")
+	return b.Bytes()
+}
+
+func (g *pyGen) serverURL() string {
+	if g.api.ServerURL != "" {
+		return g.api.ServerURL
+	}
+	return "http://127.0.0.1:7777"
+}
+
+func writePyComment(b *bytes.Buffer, indent string, lines []string, deprecated bool) {
+	if deprecated {
+		lines = append(lines, "Deprecated.")
+	}
+	for _, line := range lines {
+		if line == "" {
+			fmt.Fprintf(b, "%s#\n", indent)
+			continue
+		}
+		fmt.Fprintf(b, "%s# %s\n", indent, line)
+	}
+}
+
+func writePyDocstring(b *bytes.Buffer, indent string, lines []string) {
+	if len(lines) == 0 {
+		return
+	}
+	for i, line := range lines {
+		lines[i] = strings.ReplaceAll(strings.ReplaceAll(line, `\`, `\\`), `"""`, `\"\"\"`)
+	}
+	if len(lines) == 1 {
+		fmt.Fprintf(b, "%s\"\"\"%s\"\"\"\n", indent, lines[0])
+		return
+	}
+	fmt.Fprintf(b, "%s\"\"\"%s\n", indent, lines[0])
+	for _, line := range lines[1:] {
+		if line == "" {
+			b.WriteString("\n")
+			continue
+		}
+		fmt.Fprintf(b, "%s%s\n", indent, line)
+	}
+	fmt.Fprintf(b, "%s\"\"\"\n", indent)
+}
+
+func pyParamName(name string) string {
+	name = snakeName(name)
+	if pyReserved[name] {
+		return name + "_"
+	}
+	return name
+}
+
+func pyLiteral(value any) string {
+	switch v := value.(type) {
+	case bool:
+		if v {
+			return "True"
+		}
+		return "False"
+	case nil:
+		return "None"
+	}
+	data, _ := json.Marshal(value)
+	return string(data)
+}
+
+const pyRuntime = `from __future__ import annotations
+
+import http.client
+import json
+import ssl
+import urllib.error
+import urllib.parse
+import urllib.request
+from typing import Any, BinaryIO, Mapping, Optional, Union
+
+`
+
+const pyRuntimeClasses = `
+# Header carrying the API key.
+AUTH_HEADER = __AUTH_HEADER__
+
+_NO_BODY = object()
+
+
+class ApiError(Exception):
+    """Raised for responses outside 2xx.
+
+    code is stable across releases; message is meant for people.
+    """
+
+    def __init__(self, status: int, code: str, message: str, details: Optional[dict[str, Any]] = None) -> None:
+        super().__init__(message)
+        self.status = status
+        self.code = code
+        self.message = message
+        self.details = details or {}
+
+
+def _segment(value: Any) -> str:
+    return urllib.parse.quote(str(value), safe="")
+
+
+def _path(value: Any) -> str:
+    return urllib.parse.quote(str(value).lstrip("/"), safe="/")
+
+
+def _value(value: Any) -> str:
+    if isinstance(value, bool):
+        return "true" if value else "false"
+    return str(value)
+
+
+def _api_error(status: int, data: bytes, reason: str) -> ApiError:
+    text = data.decode("utf-8", "replace")
+    payload: Any = None
+    try:
+        payload = json.loads(text)
+    except ValueError:
+        pass
+    if not isinstance(payload, dict):
+        payload = {}
+    message = payload.get("message") or payload.get("error") or text.strip() or reason
+    return ApiError(status, payload.get("code", ""), message, payload.get("details"))
+
+
+class Client:
+    """Client of the volantd REST API."""
+
+    def __init__(
+        self,
+        base_url: str,
+        *,
+        api_key: Optional[str] = None,
+        headers: Optional[Mapping[str, str]] = None,
+        timeout: float = 30.0,
+        ssl_context: Optional[ssl.SSLContext] = None,
+    ) -> None:
+        """Create a client of volantd at base_url, such as http://127.0.0.1:7777.
+
+        api_key is needed when volantd runs with VOLANT_API_KEY set. timeout
+        bounds each call in seconds; event streams are not bounded.
+        """
+        self._base_url = base_url.rstrip("/")
+        self._api_key = api_key
+        self._headers = dict(headers or {})
+        self._timeout = timeout
+        self._ssl_context = ssl_context
+
+    def _call(
+        self,
+        method: str,
+        path: str,
+        *,
+        query: Optional[Mapping[str, Any]] = None,
+        headers: Optional[Mapping[str, Any]] = None,
+        body: Any = _NO_BODY,
+        binary: bool = False,
+        result: str = "json",
+        timeout: Optional[float] = None,
+    ) -> Any:
+        url = self._base_url + path
+        params = {key: _value(value) for key, value in (query or {}).items() if value is not None}
+        if params:
+            url += "?" + urllib.parse.urlencode(params)
+
+        request_headers = dict(self._headers)
+        if self._api_key and AUTH_HEADER:
+            request_headers[AUTH_HEADER] = self._api_key
+        for key, value in (headers or {}).items():
+            if value is not None:
+                request_headers[key] = _value(value)
+        data = None
+        if body is not _NO_BODY and body is not None:
+            if binary:
+                data = body
+                request_headers["Content-Type"] = "application/octet-stream"
+            else:
+                data = json.dumps(body).encode("utf-8")
+                request_headers["Content-Type"] = "application/json"
+        if result == "json":
+            request_headers["Accept"] = "application/json"
+
+        if timeout is None and result != "stream":
+            timeout = self._timeout
+        request = urllib.request.Request(url, data=data, headers=request_headers, method=method)
+        try:
+            response = urllib.request.urlopen(request, timeout=timeout, context=self._ssl_context)
+        except urllib.error.HTTPError as err:
+            with err:
+                raise _api_error(err.code, err.read(), str(err.reason)) from None
+        if result == "stream":
+            return response
+        with response:
+            payload = response.read()
+        if result == "binary":
+            return payload
+        if result == "none" or not payload.strip():
+            return None
+        return json.loads(payload)
+`
@@ -0,0 +1,532 @@
+// Copyright (c) 2025 HYPR. PTE. LTD.
+//
+// Business Source License 1.1
+// See LICENSE file in the project root for details.
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	openapi3 "github.com/getkin/kin-openapi/openapi3"
+)
+
+var tsIdentPattern = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$]*$`)
+
+// tsReserved are the words a generated parameter name must not be.
+var tsReserved = map[string]bool{
+	"body": true, "options": true, "params": true,
+	"break": true, "case": true, "catch": true, "class": true, "const": true, "continue": true,
+	"debugger": true, "default": true, "delete": true, "do": true, "else": true, "enum": true,
+	"export": true, "extends": true, "false": true, "finally": true, "for": true, "function": true,
+	"if": true, "import": true, "in": true, "instanceof": true, "new": true, "null": true,
+	"return": true, "super": true, "switch": true, "this": true, "throw": true, "true": true,
+	"try": true, "typeof": true, "var": true, "void": true, "while": true, "with": true,
+}
+
+// typeScriptClient generates an npm package: the types of the API in
+// src/types.ts and a fetch-based VolantClient in src/client.ts.
+func typeScriptClient(api *clientAPI, pkg, version string) ([]clientFile, error) {
+	g := &tsGen{api: api}
+	manifest, err := json.MarshalIndent(map[string]any{
+		"name":        pkg,
+		"version":     version,
+		"description": fmt.Sprintf("TypeScript client for the %s, generated from its OpenAPI document.", api.Title),
+		"license":     "BUSL-1.1",
+		"type":        "module",
+		"main":        "dist/index.js",
+		"types":       "dist/index.d.ts",
+		"files":       []string{"dist"},
+		"scripts": map[string]string{
+			"build":          "tsc",
+			"prepublishOnly": "tsc",
+		},
+		"devDependencies": map[string]string{"typescript": "^5.4.0"},
+	}, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return []clientFile{
+		{Path: "package.json", Data: append(manifest, '\n')},
+		{Path: "tsconfig.json", Data: []byte(tsConfig)},
+		{Path: "README.md", Data: g.readme(pkg)},
+		{Path: "src/index.ts", Data: []byte(g.header() + "export * from \"./types.js\";\nexport * from \"./client.js\";\n")},
+		{Path: "src/types.ts", Data: g.types()},
+		{Path: "src/client.ts", Data: g.client()},
+	}, nil
+}
+
+type tsGen struct {
+	api *clientAPI
+	// used collects the named types the client refers to.
+	used map[string]bool
+}
+
+func (g *tsGen) header() string {
+	return fmt.Sprintf("// Code generated by openapi-export from the %s %s. DO NOT EDIT.\n\n", g.api.Title, g.api.APIVersion)
+}
+
+func (g *tsGen) types() []byte {
+	var b bytes.Buffer
+	b.WriteString(g.header())
+	for i, t := range g.api.Types {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		writeTSDoc(&b, "", docLines(t.Schema.Description), t.Schema.Deprecated)
+		if !isObject(t.Schema) {
+			fmt.Fprintf(&b, "export type %s = %s;\n", t.Name, g.expr(t.Schema))
+			continue
+		}
+		fmt.Fprintf(&b, "export interface %s {\n", t.Name)
+		for _, prop := range sortedKeys(t.Schema.Properties) {
+			ref := t.Schema.Properties[prop]
+			if ref.Value != nil {
+				writeTSDoc(&b, "  ", docLines(ref.Value.Description), ref.Value.Deprecated)
+			}
+			optional := "?"
+			if slices.Contains(t.Schema.Required, prop) {
+				optional = ""
+			}
+			fmt.Fprintf(&b, "  %s%s: %s;\n", tsKey(prop), optional, g.typeOf(ref))
+		}
+		b.WriteString("}\n")
+	}
+	return b.Bytes()
+}
+
+// typeOf renders ref, by name when it has one.
+func (g *tsGen) typeOf(ref *openapi3.SchemaRef) string {
+	if ref == nil {
+		return "unknown"
+	}
+	if name, ok := g.api.typeName(ref); ok {
+		if g.used != nil {
+			g.used[name] = true
+		}
+		return name
+	}
+	if ref.Value == nil {
+		return "unknown"
+	}
+	return g.expr(ref.Value)
+}
+
+// expr renders s structurally.
+func (g *tsGen) expr(s *openapi3.Schema) string {
+	var t string
+	switch {
+	case len(s.OneOf) > 0:
+		t = g.join(s.OneOf, " | ")
+	case len(s.AnyOf) > 0:
+		t = g.join(s.AnyOf, " | ")
+	case len(s.AllOf) > 0:
+		t = g.join(s.AllOf, " & ")
+	case len(s.Enum) > 0:
+		values := make([]string, 0, len(s.Enum))
+		for _, value := range s.Enum {
+			data, _ := json.Marshal(value)
+			values = append(values, string(data))
+		}
+		t = strings.Join(values, " | ")
+	case s.Type.Includes(openapi3.TypeString):
+		t = "string"
+		if s.Format == "binary" {
+			t = "Blob"
+		}
+	case s.Type.Includes(openapi3.TypeInteger), s.Type.Includes(openapi3.TypeNumber):
+		t = "number"
+	case s.Type.Includes(openapi3.TypeBoolean):
+		t = "boolean"
+	case s.Type.Includes(openapi3.TypeArray):
+		t = g.typeOf(s.Items)
+		if strings.ContainsAny(t, " |&") {
+			t = "(" + t + ")"
+		}
+		t += "[]"
+	case s.Type.Includes(openapi3.TypeObject):
+		if s.AdditionalProperties.Schema != nil {
+			t = "Record<string, " + g.typeOf(s.AdditionalProperties.Schema) + ">"
+		} else {
+			t = "Record<string, unknown>"
+		}
+	default:
+		t = "unknown"
+	}
+	if (s.Nullable || s.Type.Includes(openapi3.TypeNull)) && t != "unknown" {
+		t += " | null"
+	}
+	return t
+}
+
+func (g *tsGen) join(refs openapi3.SchemaRefs, sep string) string {
+	parts := make([]string, 0, len(refs))
+	for _, ref := range refs {
+		part := g.typeOf(ref)
+		if strings.ContainsAny(part, " |&") {
+			part = "(" + part + ")"
+		}
+		parts = append(parts, part)
+	}
+	return strings.Join(parts, sep)
+}
+
+func (g *tsGen) client() []byte {
+	g.used = map[string]bool{}
+	var params, methods bytes.Buffer
+	for _, op := range g.api.Operations {
+		g.operation(&params, &methods, op)
+	}
+
+	var b bytes.Buffer
+	b.WriteString(g.header())
+	if names := sortedKeys(g.used); len(names) > 0 {
+		fmt.Fprintf(&b, "import type { %s } from \"./types.js\";\n\n", strings.Join(names, ", "))
+	}
+	authHeader, _ := json.Marshal(g.api.AuthHeader)
+	b.WriteString(strings.ReplaceAll(tsRuntime, "__AUTH_HEADER__", string(authHeader)))
+	b.Write(params.Bytes())
+	fmt.Fprintf(&b, "\n/** Client of the %s. */\n", g.api.Title)
+	b.WriteString("export class VolantClient {\n")
+	b.WriteString(tsClientCore)
+	b.Write(methods.Bytes())
+	b.WriteString("}\n")
+	return b.Bytes()
+}
+
+func (g *tsGen) operation(params, methods *bytes.Buffer, op *clientOperation) {
+	var args []string
+	for _, p := range op.PathParams {
+		args = append(args, tsParamName(p.Name)+": "+g.typeOf(p.Schema))
+	}
+	if op.Body != nil {
+		bodyType := "BinaryBody"
+		if !op.Body.Binary {
+			bodyType = g.typeOf(op.Body.Schema)
+		}
+		if op.Body.Required {
+			args = append(args, "body: "+bodyType)
+		} else {
+			args = append(args, "body?: "+bodyType)
+		}
+	}
+	if len(op.Params) > 0 {
+		name := exportName(op.ID) + "Params"
+		required := false
+		fmt.Fprintf(params, "\n/** Parameters of %s. */\nexport interface %s {\n", op.ID, name)
+		for _, p := range op.Params {
+			writeTSDoc(params, "  ", docLines(p.Description), false)
+			optional := "?"
+			if p.Required {
+				optional = ""
+				required = true
+			}
+			fmt.Fprintf(params, "  %s%s: %s;\n", tsKey(camelName(p.Name)), optional, g.typeOf(p.Schema))
+		}
+		params.WriteString("}\n")
+		if required {
+			args = append(args, "params: "+name)
+		} else {
+			args = append(args, "params: "+name+" = {}")
+		}
+	}
+	args = append(args, "options?: RequestOptions")
+
+	var doc []string
+	doc = append(doc, docLines(op.Summary)...)
+	if lines := docLines(op.Description); len(lines) > 0 {
+		if len(doc) > 0 {
+			doc = append(doc, "")
+		}
+		doc = append(doc, lines...)
+	}
+	methods.WriteString("\n")
+	writeTSDoc(methods, "  ", doc, op.Deprecated)
+	fmt.Fprintf(methods, "  %s(%s): Promise<%s> {\n", op.ID, strings.Join(args, ", "), g.resultType(op.Result))
+
+	path := op.Path
+	for _, p := range op.PathParams {
+		encode := "encodeURIComponent"
+		if p.Slashes {
+			encode = "encodePath"
+		}
+		path = strings.Replace(path, "{"+p.Name+"}", "${"+encode+"(String("+tsParamName(p.Name)+"))}", 1)
+	}
+	fields := []string{
+		"method: " + jsonString(op.Method),
+		"path: `" + path + "`",
+	}
+	for _, in := range []string{openapi3.ParameterInQuery, openapi3.ParameterInHeader} {
+		var values []string
+		for _, p := range op.Params {
+			if p.In == in {
+				values = append(values, jsonString(p.Name)+": params."+camelName(p.Name))
+			}
+		}
+		if len(values) > 0 {
+			fields = append(fields, in+": { "+strings.Join(values, ", ")+" }")
+		}
+	}
+	if op.Body != nil {
+		fields = append(fields, "body")
+		if op.Body.Binary {
+			fields = append(fields, "binary: true")
+		}
+	}
+	fields = append(fields, "result: "+jsonString(resultName(op.Result.Kind)))
+	fmt.Fprintf(methods, "    return this.call({ %s }, options);\n  }\n", strings.Join(fields, ", "))
+}
+
+func (g *tsGen) resultType(result clientResult) string {
+	switch result.Kind {
+	case resultBinary:
+		return "Blob"
+	case resultStream:
+		return "Response"
+	case resultJSON:
+		parts := make([]string, 0, len(result.Schemas)+1)
+		for _, schema := range result.Schemas {
+			parts = append(parts, g.typeOf(schema))
+		}
+		if result.Empty {
+			parts = append(parts, "undefined")
+		}
+		return strings.Join(parts, " | ")
+	}
+	return "void"
+}
+
+func resultName(kind resultKind) string {
+	switch kind {
+	case resultJSON:
+		return "json"
+	case resultBinary:
+		return "binary"
+	case resultStream:
+		return "stream"
+	}
+	return "none"
+}
+
+func (g *tsGen) readme(pkg string) []byte {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "# %s\n\n", pkg)
+	fmt.Fprintf(&b, "TypeScript client for the %s (%s), generated from its OpenAPI document by `openapi-export -lang typescript`. Do not edit it by hand; regenerate it with `make client-typescript`.\n\n", g.api.Title, g.api.APIVersion)
+	b.WriteString("It uses the global `fetch`, so it runs in browsers and Node.js 18 or later.\n\n")
+	b.WriteString("## Build\n\n```sh\nnpm install\nnpm run build\nnpm publish\n```\n\n")
+	b.WriteString("## Usage\n\n```ts\n")
+	fmt.Fprintf(&b, "import { ApiError, VolantClient } from %s;\n\n", jsonString(pkg))
+	fmt.Fprintf(&b, "const volant = new VolantClient({ baseUrl: %s, apiKey: process.env.VOLANT_API_KEY });\n\n", jsonString(g.serverURL()))
+	b.WriteString("try {\n  const vms = await volant.listVMs({ status: \"running\" });\n  console.log(vms.map((vm) => vm.name));\n} catch (err) {\n  if (err instanceof ApiError && err.code === \"UNAUTHORIZED\") {\n    console.error(\"bad API key\");\n  }\n  throw err;\n}\n```\n\n")
+	b.WriteString("Every method is named after the operationId of its endpoint. Failed requests throw an `ApiError` carrying the HTTP status and the stable error `code`. Event streams resolve to the raw `Response`; read its body as server-sent events. Websocket endpoints are not covered.\n")
+	return b.Bytes()
+}
+
+func (g *tsGen) serverURL() string {
+	if g.api.ServerURL != "" {
+		return g.api.ServerURL
+	}
+	return "http://127.0.0.1:7777"
+}
+
+func writeTSDoc(b *bytes.Buffer, indent string, lines []string, deprecated bool) {
+	if deprecated {
+		lines = append(lines, "@deprecated")
+	}
+	if len(lines) == 0 {
+		return
+	}
+	if len(lines) == 1 {
+		fmt.Fprintf(b, "%s/** %s */\n", indent, strings.ReplaceAll(lines[0], "*/", "*\\/"))
+		return
+	}
+	fmt.Fprintf(b, "%s/**\n", indent)
+	for _, line := range lines {
+		line = strings.ReplaceAll(line, "*/", "*\\/")
+		if line == "" {
+			fmt.Fprintf(b, "%s *\n", indent)
+			continue
+		}
+		fmt.Fprintf(b, "%s * %s\n", indent, line)
+	}
+	fmt.Fprintf(b, "%s */\n", indent)
+}
+
+func tsKey(name string) string {
+	if tsIdentPattern.MatchString(name) {
+		return name
+	}
+	return jsonString(name)
+}
+
+func tsParamName(name string) string {
+	name = camelName(name)
+	if tsReserved[name] {
+		return name + "_"
+	}
+	return name
+}
+
+func jsonString(s string) string {
+	data, _ := json.Marshal(s)
+	return string(data)
+}
+
+const tsConfig = `{
+  "compilerOptions": {
+    "target": "ES2022",
+    "module": "NodeNext",
+    "moduleResolution": "NodeNext",
+    "lib": ["ES2022", "DOM"],
+    "declaration": true,
+    "outDir": "dist",
+    "rootDir": "src",
+    "strict": true,
+    "skipLibCheck": true
+  },
+  "include": ["src"]
+}
+`
+
+const tsRuntime = `/** Header carrying the API key. */
+const AUTH_HEADER = __AUTH_HEADER__;
+
+export interface ClientOptions {
+  /** Base URL of volantd, such as http://127.0.0.1:7777. */
+  baseUrl: string;
+  /** API key, needed when volantd runs with VOLANT_API_KEY set. */
+  apiKey?: string;
+  /** Headers sent with every request. */
+  headers?: Record<string, string>;
+  /** fetch implementation; the global fetch by default. */
+  fetch?: typeof fetch;
+}
+
+/** Per-call options. */
+export interface RequestOptions {
+  signal?: AbortSignal;
+  headers?: Record<string, string>;
+}
+
+/** Raw request bodies, for file and artifact uploads. */
+export type BinaryBody = Blob | ArrayBuffer | Uint8Array;
+
+/**
+ * ApiError is thrown for responses outside 2xx. code is stable across
+ * releases; message is meant for people.
+ */
+export class ApiError extends Error {
+  readonly status: number;
+  readonly code: string;
+  readonly details?: Record<string, unknown>;
+
+  constructor(status: number, code: string, message: string, details?: Record<string, unknown>) {
+    super(message);
+    this.name = "ApiError";
+    this.status = status;
+    this.code = code;
+    this.details = details;
+  }
+}
+
+type Values = Record<string, string | number | boolean | null | undefined>;
+
+interface Call {
+  method: string;
+  path: string;
+  query?: Values;
+  header?: Values;
+  body?: unknown;
+  binary?: boolean;
+  result: "json" | "binary" | "stream" | "none";
+}
+
+function encodePath(value: string): string {
+  return value.replace(/^\/+/, "").split("/").map(encodeURIComponent).join("/");
+}
+
+async function apiError(response: Response): Promise<ApiError> {
+  const text = await response.text().catch(() => "");
+  let payload: { code?: string; message?: string; error?: string; details?: Record<string, unknown> } = {};
+  try {
+    const parsed = JSON.parse(text);
+    if (parsed && typeof parsed === "object") {
+      payload = parsed;
+    }
+  } catch {
+    // Not JSON; the body becomes the message.
+  }
+  const message = payload.message ?? payload.error ?? (text.trim() || response.statusText);
+  return new ApiError(response.status, payload.code ?? "", message, payload.details);
+}
+`
+
+// tsClientCore opens the body of VolantClient.
+const tsClientCore = `  private readonly baseUrl: string;
+  private readonly options: ClientOptions;
+
+  constructor(options: ClientOptions) {
+    this.baseUrl = options.baseUrl.replace(/\/+$/, "");
+    this.options = options;
+  }
+
+  private async call(call: Call, options?: RequestOptions): Promise<any> {
+    let url = this.baseUrl + call.path;
+    const search = new URLSearchParams();
+    for (const [key, value] of Object.entries(call.query ?? {})) {
+      if (value !== undefined && value !== null) {
+        search.set(key, String(value));
+      }
+    }
+    const query = search.toString();
+    if (query) {
+      url += "?" + query;
+    }
+
+    const headers: Record<string, string> = { ...this.options.headers };
+    if (this.options.apiKey && AUTH_HEADER) {
+      headers[AUTH_HEADER] = this.options.apiKey;
+    }
+    for (const [key, value] of Object.entries(call.header ?? {})) {
+      if (value !== undefined && value !== null) {
+        headers[key] = String(value);
+      }
+    }
+    let body: BodyInit | undefined;
+    if (call.body !== undefined) {
+      if (call.binary) {
+        body = call.body as BodyInit;
+        headers["Content-Type"] = "application/octet-stream";
+      } else {
+        body = JSON.stringify(call.body);
+        headers["Content-Type"] = "application/json";
+      }
+    }
+    if (call.result === "json") {
+      headers["Accept"] = "application/json";
+    }
+    Object.assign(headers, options?.headers);
+
+    const fetchImpl = this.options.fetch ?? globalThis.fetch;
+    const response = await fetchImpl(url, { method: call.method, headers, body, signal: options?.signal });
+    if (!response.ok) {
+      throw await apiError(response);
+    }
+    switch (call.result) {
+      case "stream":
+        return response;
+      case "binary":
+        return response.blob();
+      case "none":
+        await response.body?.cancel();
+        return undefined;
+    }
+    const text = await response.text();
+    return text ? JSON.parse(text) : undefined;
+  }
+`
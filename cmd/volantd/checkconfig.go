@@ -0,0 +1,54 @@
+// Copyright (c) 2025 HYPR. PTE. LTD.
+//
+// Business Source License 1.1
+// See LICENSE file in the project root for details.
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+
+	"github.com/volantvm/volant/internal/server/admission"
+	"github.com/volantvm/volant/internal/server/config"
+)
+
+const checkConfigUsage = `usage: volantd [--config FILE] check-config
+
+Validates the config file (--config or VOLANT_CONFIG_FILE) together with the
+environment, reporting every problem found, and exits non-zero when volantd
+would refuse to start with them.
+`
+
+// runCheckConfig implements `volantd check-config` and returns the process
+// exit code.
+func runCheckConfig(path string, args []string, stdout, stderr io.Writer) int {
+	if len(args) > 0 {
+		fmt.Fprint(stderr, checkConfigUsage)
+		return 2
+	}
+	if _, err := config.NewReloader(path); err != nil {
+		var fileErr *config.FileError
+		if errors.As(err, &fileErr) {
+			fmt.Fprintf(stderr, "%s:\n", fileErr.Path)
+			for _, problem := range fileErr.Problems {
+				fmt.Fprintf(stderr, "  %s\n", problem)
+			}
+		} else {
+			fmt.Fprintln(stderr, err)
+		}
+		return 1
+	}
+	if _, err := config.FromEnv(); err != nil {
+		fmt.Fprintf(stderr, "config: %v\n", err)
+		return 1
+	}
+	if _, err := admission.FromEnv(slog.New(slog.NewTextHandler(io.Discard, nil))); err != nil {
+		fmt.Fprintf(stderr, "admission webhooks: %v\n", err)
+		return 1
+	}
+	fmt.Fprintln(stdout, "config ok")
+	return 0
+}
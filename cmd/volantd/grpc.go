@@ -0,0 +1,47 @@
+// Copyright (c) 2025 HYPR. PTE. LTD.
+//
+// Business Source License 1.1
+// See LICENSE file in the project root for details.
+
+//go:build grpc
+
+package main
+
+import (
+	"context"
+	"log/slog"
+	"net"
+
+	"github.com/volantvm/volant/internal/server/admission"
+	"github.com/volantvm/volant/internal/server/eventbus"
+	"github.com/volantvm/volant/internal/server/grpcapi"
+	"github.com/volantvm/volant/internal/server/orchestrator"
+	"github.com/volantvm/volant/internal/server/plugins"
+)
+
+// startGRPC serves the gRPC API on addr until ctx is cancelled.
+func startGRPC(ctx context.Context, addr string, logger *slog.Logger, engine orchestrator.Engine, bus eventbus.Bus, registry *plugins.Registry) error {
+	if addr == "" {
+		return nil
+	}
+	admit, err := admission.FromEnv(logger)
+	if err != nil {
+		return err
+	}
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	server := grpcapi.New(logger, engine, bus, registry, admit)
+	go func() {
+		<-ctx.Done()
+		server.GracefulStop()
+	}()
+	go func() {
+		logger.Info("grpc server listening", "addr", listener.Addr().String())
+		if err := server.Serve(listener); err != nil {
+			logger.Error("grpc server stopped", "error", err)
+		}
+	}()
+	return nil
+}
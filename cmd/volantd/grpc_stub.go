@@ -0,0 +1,26 @@
+// Copyright (c) 2025 HYPR. PTE. LTD.
+//
+// Business Source License 1.1
+// See LICENSE file in the project root for details.
+
+//go:build !grpc
+
+package main
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	"github.com/volantvm/volant/internal/server/eventbus"
+	"github.com/volantvm/volant/internal/server/orchestrator"
+	"github.com/volantvm/volant/internal/server/plugins"
+)
+
+// startGRPC rejects a gRPC listen address in builds without the grpc tag.
+func startGRPC(ctx context.Context, addr string, logger *slog.Logger, engine orchestrator.Engine, bus eventbus.Bus, registry *plugins.Registry) error {
+	if addr == "" {
+		return nil
+	}
+	return errors.New("VOLANT_GRPC_LISTEN is set but volantd was built without the grpc tag (make build-server-grpc)")
+}
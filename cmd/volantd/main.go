@@ -8,6 +8,7 @@ package main
 import (
 	"context"
 	"errors"
+	"flag"
 	"log/slog"
 	"net"
 	"net/http"
@@ -19,16 +20,32 @@ import (
 	"syscall"
 	"time"
 
+	"github.com/volantvm/volant/internal/server/admission"
+	"github.com/volantvm/volant/internal/server/apiauth"
 	"github.com/volantvm/volant/internal/server/app"
+	"github.com/volantvm/volant/internal/server/backup"
 	"github.com/volantvm/volant/internal/server/config"
+	"github.com/volantvm/volant/internal/server/db"
+	"github.com/volantvm/volant/internal/server/db/postgres"
 	"github.com/volantvm/volant/internal/server/db/sqlite"
 	"github.com/volantvm/volant/internal/server/driftclient"
 	"github.com/volantvm/volant/internal/server/eventbus/memory"
 	"github.com/volantvm/volant/internal/server/httpapi"
+	"github.com/volantvm/volant/internal/server/logship"
 	"github.com/volantvm/volant/internal/server/orchestrator"
+	"github.com/volantvm/volant/internal/server/orchestrator/capacity"
 	"github.com/volantvm/volant/internal/server/orchestrator/cloudhypervisor"
+	"github.com/volantvm/volant/internal/server/orchestrator/doctor"
+	"github.com/volantvm/volant/internal/server/orchestrator/hostfeatures"
+	"github.com/volantvm/volant/internal/server/orchestrator/ipam"
 	"github.com/volantvm/volant/internal/server/orchestrator/network"
+	"github.com/volantvm/volant/internal/server/orchestrator/topology"
+	"github.com/volantvm/volant/internal/server/orchestrator/vmlogs"
 	"github.com/volantvm/volant/internal/server/plugins"
+	"github.com/volantvm/volant/internal/server/scheduler"
+	"github.com/volantvm/volant/internal/server/secrets"
+	"github.com/volantvm/volant/internal/server/tracing"
+	"github.com/volantvm/volant/internal/server/webhooks"
 	"github.com/volantvm/volant/internal/shared/logging"
 )
 
@@ -38,13 +55,46 @@ func main() {
 
 	logger := logging.New("volantd")
 
+	flags := flag.NewFlagSet("volantd", flag.ExitOnError)
+	configPath := flags.String("config", "", "YAML, TOML or KEY=VALUE config file (default $"+config.EnvFileKey+")")
+	_ = flags.Parse(os.Args[1:])
+	args := flags.Args()
+
+	if len(args) > 0 && args[0] == "check-config" {
+		os.Exit(runCheckConfig(*configPath, args[1:], os.Stdout, os.Stderr))
+	}
+
+	reloader, err := config.NewReloader(*configPath)
+	if err != nil {
+		logger.Error("load config file", "error", err)
+		os.Exit(1)
+	}
+
+	if len(args) > 0 && args[0] == "migrate" {
+		os.Exit(runMigrate(ctx, config.DatabaseFromEnv(), args[1:], os.Stdout, os.Stderr))
+	}
+	if len(args) > 0 && args[0] == "setup" {
+		os.Exit(runSetup(ctx, *configPath, args[1:], os.Stdout, os.Stderr))
+	}
+	if len(args) > 0 && args[0] == "mcp-serve" {
+		os.Exit(runMCPServe(ctx, args[1:], os.Stdin, os.Stdout, os.Stderr))
+	}
+
 	cfg, err := config.FromEnv()
 	if err != nil {
 		logger.Error("load config", "error", err)
 		os.Exit(1)
 	}
+	reloader.Subscribe(func(settings config.Reloadable) {
+		logging.SetLevel(settings.LogLevel)
+		apiauth.Set(apiauth.New(settings.APIKey))
+		// Invalid entries are skipped; the REST API logs them.
+		networks, _ := apiauth.ParseNetworks(settings.AllowCIDRs)
+		apiauth.SetNetworks(networks)
+	})
+	go reloadOnSIGHUP(ctx, reloader, logger)
 
-	store, err := sqlite.Open(ctx, cfg.DatabasePath)
+	store, err := openStore(ctx, cfg)
 	if err != nil {
 		logger.Error("open database", "error", err)
 		os.Exit(1)
@@ -56,6 +106,12 @@ func main() {
 		logger.Error("parse host ip", "ip", cfg.HostIP)
 		os.Exit(1)
 	}
+	var subnet6 *net.IPNet
+	var hostIP6 net.IP
+	if cfg.SubnetCIDR6 != "" {
+		subnet6 = parseSubnetOrExit(cfg.SubnetCIDR6, logger)
+		hostIP6 = net.ParseIP(cfg.HostIP6)
+	}
 
 	runtimeDir := expandPath(cfg.RuntimeDir, logger)
 	logDir := expandPath(cfg.LogDir, logger)
@@ -67,33 +123,101 @@ func main() {
 		runtimeDir,
 		logDir,
 	)
+	launcher.Firmware = expandPath(cfg.FirmwarePath, logger)
+	launcher.Peers = cfg.ArtifactPeers
+	launcher.PeerAPIKey = cfg.ArtifactPeerKey
 
 	runtimeRegistry := plugins.NewRegistry(store.Queries().Plugins())
 
 	var netManager network.Manager
 	if runtime.GOOS == "linux" {
-		netManager = network.NewBridgeManager(cfg.BridgeName)
+		netManager = network.NewBridgeManager(cfg.BridgeName, cfg.NDPProxyInterface)
 	} else {
 		logger.Warn("using noop network manager (non-linux host)")
 		netManager = network.NewNoop()
 	}
 
+	allocator, err := ipam.New(ipam.Options{
+		Backend: cfg.IPAMBackend,
+		URL:     cfg.IPAMURL,
+		Token:   cfg.IPAMToken,
+		Pool:    cfg.IPAMPool,
+		App:     cfg.IPAMApp,
+		Subnet:  subnet,
+		Logger:  logger,
+	})
+	if err != nil {
+		logger.Error("init ipam", "error", err)
+		os.Exit(1)
+	}
+
 	events := memory.New()
 
-	engine, err := orchestrator.New(orchestrator.Params{
-		Store:            store,
-		Logger:           logger,
-		Subnet:           subnet,
-		HostIP:           hostIP,
-		APIListenAddr:    cfg.APIListenAddr,
-		APIAdvertiseAddr: cfg.APIAdvertiseAddr,
-		Launcher:         launcher,
-		Network:          netManager,
-		Bus:              events,
-		RuntimeDir:       runtimeDir,
+	features := hostfeatures.Probe()
+	for _, feature := range features.Features {
+		logger.Info("host feature", "feature", feature.Name, "available", feature.Available, "detail", feature.Detail)
+	}
+
+	hostTopology, err := topology.Read(topology.DefaultSysfs)
+	if err != nil {
+		logger.Warn("read host topology; cpu pinning and numa placement unavailable", "error", err)
+	} else {
+		logger.Info("host topology", "cpus", hostTopology.CPUs.String(), "numa_nodes", len(hostTopology.Nodes))
+	}
+
+	vmLogs := vmlogs.New(filepath.Join(logDir, "vms"), vmlogs.Retention{
+		MaxFileBytes: int64(cfg.VMLogMaxSizeMB) << 20,
+		MaxFiles:     cfg.VMLogMaxFiles,
+		MaxAge:       cfg.VMLogRetention,
+	})
+	defer vmLogs.Close()
+
+	hostname, _ := os.Hostname()
+
+	if cfg.TracingEndpoint != "" {
+		provider, err := tracing.New(logger, tracing.Options{
+			Endpoint:    cfg.TracingEndpoint,
+			ServiceName: "volantd",
+			Hostname:    hostname,
+			SampleRatio: cfg.TracingSampleRatio,
+		})
+		if err != nil {
+			logger.Error("init tracing", "error", err)
+			os.Exit(1)
+		}
+		tracing.SetProvider(provider)
+		defer provider.Close()
+		logger.Info("tracing enabled", "endpoint", cfg.TracingEndpoint, "sample_ratio", cfg.TracingSampleRatio)
+	}
+
+	var logShipper *logship.Shipper
+	if cfg.LogExport.Enabled() {
+		logShipper, err = logship.New(logger, cfg.LogExport, hostname)
+		if err != nil {
+			logger.Error("init log export", "error", err)
+			os.Exit(1)
+		}
+		defer logShipper.Close()
+		logger.Info("log export enabled", "exporters", strings.Join(logShipper.Kinds(), ","), "default", cfg.LogExport.Default)
+	}
+
+	hostCapacity := capacity.New(capacity.ReadHost(), capacity.Thresholds{
+		CPUPercent:    cfg.CapacityCPUPercent,
+		MemoryPercent: cfg.CapacityMemoryPercent,
 	})
+
+	secretsKey, err := secrets.KeySource{
+		Key:     cfg.SecretsKey,
+		Command: cfg.SecretsKeyCommand,
+		File:    cfg.SecretsKeyFile,
+	}.Load(ctx)
 	if err != nil {
-		logger.Error("init orchestrator", "error", err)
+		logger.Error("load secrets key", "error", err)
+		os.Exit(1)
+	}
+	secretStore, err := secrets.New(store, secretsKey)
+	if err != nil {
+		logger.Error("init secrets", "error", err)
 		os.Exit(1)
 	}
 
@@ -108,9 +232,82 @@ func main() {
 		driftClient = client
 	}
 
-	handler := httpapi.New(logger, engine, events, runtimeRegistry, driftClient)
+	engine, err := orchestrator.New(orchestrator.Params{
+		Store:                store,
+		Logger:               logger,
+		Subnet:               subnet,
+		HostIP:               hostIP,
+		Subnet6:              subnet6,
+		HostIP6:              hostIP6,
+		APIListenAddr:        cfg.APIListenAddr,
+		APIAdvertiseAddr:     cfg.APIAdvertiseAddr,
+		Launcher:             launcher,
+		Network:              netManager,
+		Bus:                  events,
+		RuntimeDir:           runtimeDir,
+		IPAM:                 allocator,
+		DHCP:                 cfg.DHCPEnabled,
+		HostFeatures:         features,
+		Topology:             hostTopology,
+		PassthroughDevices:   cfg.PassthroughDevices,
+		Capacity:             hostCapacity,
+		Logs:                 vmLogs,
+		LogShipper:           logShipper,
+		Secrets:              secretStore,
+		AutostartConcurrency: cfg.AutostartConcurrency,
+		SeedCacheSize:        cfg.SeedCacheSize,
+		TapPoolSize:          cfg.TapPoolSize,
+		Drift:                driftClient,
+	})
+	if err != nil {
+		logger.Error("init orchestrator", "error", err)
+		os.Exit(1)
+	}
+
+	sched := scheduler.New(engine, events, logger)
+
+	// Catch a broken webhook config at startup instead of rejecting every
+	// admitted request later.
+	if _, err := admission.FromEnv(logger); err != nil {
+		logger.Error("load admission webhooks", "error", err)
+		os.Exit(1)
+	}
 
-	daemon, err := app.New(cfg, logger, store, engine, events, runtimeRegistry, handler)
+	backups := backup.New(store, backup.Options{
+		Dir:      cfg.BackupDir,
+		Interval: cfg.BackupInterval,
+		Retain:   cfg.BackupRetain,
+	}, logger)
+
+	hooks := webhooks.New(store, events, logger)
+
+	// Report missing prerequisites at startup; GET /api/v1/system/doctor
+	// checks again on demand.
+	hostChecker := doctor.New(doctor.Options{
+		HypervisorBinary:   cfg.HypervisorBinary,
+		BridgeName:         cfg.BridgeName,
+		HostIP:             cfg.HostIP,
+		RuntimeDir:         runtimeDir,
+		PassthroughDevices: cfg.PassthroughDevices,
+	})
+	for _, check := range hostChecker.Run(ctx).Checks {
+		if check.Status != doctor.StatusPass {
+			logger.Warn("host check", "check", check.Name, "status", check.Status, "detail", check.Detail, "hint", check.Hint)
+		}
+	}
+
+	handler, err := httpapi.New(logger, engine, events, runtimeRegistry, driftClient, sched, backups, hooks, secretStore, reloader, hostChecker)
+	if err != nil {
+		logger.Error("init http api", "error", err)
+		os.Exit(1)
+	}
+
+	if err := startGRPC(ctx, cfg.GRPCListenAddr, logger, engine, events, runtimeRegistry); err != nil {
+		logger.Error("start grpc api", "error", err)
+		os.Exit(1)
+	}
+
+	daemon, err := app.New(cfg, logger, store, engine, events, runtimeRegistry, sched, backups, hooks, handler)
 	if err != nil {
 		logger.Error("init app", "error", err)
 		os.Exit(1)
@@ -122,6 +319,36 @@ func main() {
 	}
 }
 
+// reloadOnSIGHUP re-reads the config file on SIGHUP, like
+// POST /api/v1/system/reload, until ctx is done.
+func reloadOnSIGHUP(ctx context.Context, reloader *config.Reloader, logger *slog.Logger) {
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	defer signal.Stop(hup)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-hup:
+		}
+		result, err := reloader.Reload()
+		if err != nil {
+			logger.Error("reload config", "error", err)
+			continue
+		}
+		logger.Info("config reloaded", "applied", result.Applied, "restart_required", result.RestartRequired)
+	}
+}
+
+// openStore uses PostgreSQL when VOLANT_DATABASE_URL is set and the local
+// SQLite file otherwise.
+func openStore(ctx context.Context, cfg config.ServerConfig) (db.Store, error) {
+	if cfg.DatabaseURL != "" {
+		return postgres.Open(ctx, cfg.DatabaseURL)
+	}
+	return sqlite.Open(ctx, cfg.DatabasePath)
+}
+
 func parseSubnetOrExit(cidr string, logger *slog.Logger) *net.IPNet {
 	_, subnet, err := net.ParseCIDR(cidr)
 	if err != nil {
@@ -0,0 +1,60 @@
+// Copyright (c) 2025 HYPR. PTE. LTD.
+//
+// Business Source License 1.1
+// See LICENSE file in the project root for details.
+
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/volantvm/volant/internal/server/apiauth"
+	"github.com/volantvm/volant/internal/server/mcp"
+)
+
+const mcpServeUsage = `usage: volantd mcp-serve [--api URL]
+
+Serve the Model Context Protocol over stdin and stdout for local agents,
+forwarding tool calls to a running volantd. The API key, if any, is read
+from VOLANT_API_KEY. Logs go to stderr.
+
+  --api URL   volantd base URL (default $VOLANT_API_BASE or http://127.0.0.1:7777)
+`
+
+// runMCPServe implements `volantd mcp-serve` and returns the process exit code.
+func runMCPServe(ctx context.Context, args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	defaultAPI := strings.TrimSpace(os.Getenv("VOLANT_API_BASE"))
+	if defaultAPI == "" {
+		defaultAPI = "http://127.0.0.1:7777"
+	}
+	flags := flag.NewFlagSet("mcp-serve", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	flags.Usage = func() { fmt.Fprint(stderr, mcpServeUsage) }
+	api := flags.String("api", defaultAPI, "volantd base URL")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if flags.NArg() > 0 {
+		flags.Usage()
+		return 2
+	}
+
+	// stdout carries the protocol, so logs must not go there.
+	logger := slog.New(slog.NewJSONHandler(stderr, nil)).With("subsystem", "volantd")
+	server := mcp.New(http.DefaultClient, mcp.Options{
+		BaseURL: strings.TrimRight(*api, "/"),
+		APIKey:  strings.TrimSpace(os.Getenv(apiauth.EnvKey)),
+	}, logger)
+	if err := server.ServeStdio(ctx, stdin, stdout); err != nil {
+		logger.Error("serve mcp", "error", err)
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,132 @@
+// Copyright (c) 2025 HYPR. PTE. LTD.
+//
+// Business Source License 1.1
+// See LICENSE file in the project root for details.
+
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	"github.com/volantvm/volant/internal/server/config"
+	"github.com/volantvm/volant/internal/server/db/migrate"
+	"github.com/volantvm/volant/internal/server/db/postgres"
+	"github.com/volantvm/volant/internal/server/db/sqlite"
+)
+
+const migrateUsage = `usage: volantd migrate <status|up|down> [--to VERSION]
+
+  status          list migrations and whether they are applied (default)
+  up [--to N]     apply pending migrations, up to N or the latest
+  down --to N     roll back migrations newer than N (0 reverts everything)
+
+Stop volantd before rolling back. The database is selected by
+VOLANT_DATABASE_URL or VOLANT_DB_PATH, as for the daemon.
+`
+
+type migratableStore interface {
+	Close(ctx context.Context) error
+	Migrator() (*migrate.Migrator, error)
+}
+
+// runMigrate implements `volantd migrate` and returns the process exit code.
+func runMigrate(ctx context.Context, cfg config.ServerConfig, args []string, stdout, stderr io.Writer) int {
+	action := "status"
+	if len(args) > 0 && args[0] != "" && args[0][0] != '-' {
+		action, args = args[0], args[1:]
+	}
+
+	flags := flag.NewFlagSet("migrate "+action, flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	flags.Usage = func() { fmt.Fprint(stderr, migrateUsage) }
+	to := flags.Int("to", -1, "target schema version")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if flags.NArg() > 0 {
+		flags.Usage()
+		return 2
+	}
+
+	var store migratableStore
+	var err error
+	if cfg.DatabaseURL != "" {
+		store, err = postgres.OpenForMigration(ctx, cfg.DatabaseURL)
+	} else {
+		store, err = sqlite.OpenForMigration(ctx, cfg.DatabasePath)
+	}
+	if err != nil {
+		fmt.Fprintf(stderr, "open database: %v\n", err)
+		return 1
+	}
+	defer store.Close(context.WithoutCancel(ctx))
+
+	m, err := store.Migrator()
+	if err != nil {
+		fmt.Fprintf(stderr, "load migrations: %v\n", err)
+		return 1
+	}
+
+	switch action {
+	case "status":
+		status, err := m.Status(ctx)
+		if err != nil {
+			fmt.Fprintf(stderr, "migration status: %v\n", err)
+			return 1
+		}
+		w := tabwriter.NewWriter(stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "VERSION\tNAME\tAPPLIED\tROLLBACK")
+		for _, s := range status {
+			applied := "pending"
+			if s.Applied {
+				applied = s.AppliedAt.UTC().Format("2006-01-02 15:04:05")
+			}
+			rollback := "yes"
+			if s.Down == "" {
+				rollback = "no"
+			}
+			fmt.Fprintf(w, "%04d\t%s\t%s\t%s\n", s.Version, s.Name, applied, rollback)
+		}
+		_ = w.Flush()
+	case "up":
+		target := *to
+		if target < 0 {
+			target = 0
+		}
+		applied, err := m.Up(ctx, target)
+		for _, mig := range applied {
+			fmt.Fprintf(stdout, "applied %04d_%s\n", mig.Version, mig.Name)
+		}
+		if err != nil {
+			fmt.Fprintf(stderr, "migrate up: %v\n", err)
+			return 1
+		}
+		if len(applied) == 0 {
+			fmt.Fprintln(stdout, "schema is up to date")
+		}
+	case "down":
+		if *to < 0 {
+			fmt.Fprintln(stderr, "migrate down requires --to VERSION")
+			return 2
+		}
+		reverted, err := m.Down(ctx, *to)
+		for _, mig := range reverted {
+			fmt.Fprintf(stdout, "reverted %04d_%s\n", mig.Version, mig.Name)
+		}
+		if err != nil {
+			fmt.Fprintf(stderr, "migrate down: %v\n", err)
+			return 1
+		}
+		if len(reverted) == 0 {
+			fmt.Fprintln(stdout, "nothing to roll back")
+		}
+	default:
+		flags.Usage()
+		return 2
+	}
+	return 0
+}
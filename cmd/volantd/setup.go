@@ -0,0 +1,125 @@
+// Copyright (c) 2025 HYPR. PTE. LTD.
+//
+// Business Source License 1.1
+// See LICENSE file in the project root for details.
+
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/volantvm/volant/internal/server/config"
+	"github.com/volantvm/volant/internal/setup"
+)
+
+const setupUsage = `usage: volantd [--config FILE] setup [flags]
+
+Prepares this host for volantd, using the bridge, subnets, directories and
+kernel paths of its configuration: creates and addresses the bridge,
+enables IP forwarding and NAT, creates the runtime, log and work
+directories owned by --user, writes and enables the systemd unit, and with
+--install-hypervisor downloads a pinned cloud-hypervisor. Safe to run
+again. Must run as root unless --dry-run is given.
+
+Flags:
+`
+
+// runSetup implements `volantd setup` and returns the process exit code.
+func runSetup(ctx context.Context, configPath string, args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("setup", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	flags.Usage = func() {
+		fmt.Fprint(stderr, setupUsage)
+		flags.PrintDefaults()
+	}
+	dryRun := flags.Bool("dry-run", false, "print the steps without applying them")
+	serviceFile := flags.String("service-file", "/etc/systemd/system/volantd.service", "systemd unit to write and enable (empty to skip)")
+	workDir := flags.String("work-dir", "/var/lib/volant", "working directory of the volantd unit")
+	user := flags.String("user", "root", "user volantd runs as and that owns its directories")
+	group := flags.String("group", "", "group of that user (default its primary group)")
+	installHypervisor := flags.Bool("install-hypervisor", false, "download cloud-hypervisor unless the pinned version is installed")
+	hypervisorVersion := flags.String("hypervisor-version", setup.DefaultHypervisorVersion, "cloud-hypervisor release to install")
+	hypervisorSHA256 := flags.String("hypervisor-sha256", "", "expected sha256 of the cloud-hypervisor download")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if flags.NArg() > 0 {
+		flags.Usage()
+		return 2
+	}
+
+	cfg := config.HostFromEnv()
+	hostCIDR, err := setup.HostCIDR(cfg.SubnetCIDR, cfg.HostIP)
+	if err != nil {
+		fmt.Fprintf(stderr, "setup: %v\n", err)
+		return 1
+	}
+	var hostCIDR6 string
+	if cfg.SubnetCIDR6 != "" {
+		if hostCIDR6, err = setup.HostCIDR(cfg.SubnetCIDR6, cfg.HostIP6); err != nil {
+			fmt.Fprintf(stderr, "setup: %v\n", err)
+			return 1
+		}
+	}
+	binary, err := os.Executable()
+	if err != nil {
+		fmt.Fprintf(stderr, "setup: resolve executable: %v\n", err)
+		return 1
+	}
+	if configPath != "" {
+		if configPath, err = filepath.Abs(configPath); err != nil {
+			fmt.Fprintf(stderr, "setup: %v\n", err)
+			return 1
+		}
+	}
+
+	opts := setup.Options{
+		BridgeName:        cfg.BridgeName,
+		SubnetCIDR:        cfg.SubnetCIDR,
+		HostCIDR:          hostCIDR,
+		Subnet6CIDR:       cfg.SubnetCIDR6,
+		HostCIDR6:         hostCIDR6,
+		NDPProxyInterface: cfg.NDPProxyInterface,
+		DryRun:            *dryRun,
+		RuntimeDir:        cfg.RuntimeDir,
+		LogDir:            cfg.LogDir,
+		ServicePath:       *serviceFile,
+		BinaryPath:        binary,
+		BZImagePath:       cfg.BZImagePath,
+		VMLinuxPath:       cfg.VMLinuxPath,
+		WorkDir:           *workDir,
+		User:              *user,
+		Group:             *group,
+		ConfigFile:        configPath,
+	}
+	if filepath.IsAbs(cfg.HypervisorBinary) {
+		opts.HypervisorPath = cfg.HypervisorBinary
+	}
+	if *installHypervisor {
+		opts.HypervisorVersion = *hypervisorVersion
+		opts.HypervisorSHA256 = *hypervisorSHA256
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Minute)
+	defer cancel()
+	res, err := setup.Run(ctx, opts)
+	if err != nil {
+		fmt.Fprintf(stderr, "setup: %v\n", err)
+		return 1
+	}
+	for _, line := range res.Commands {
+		fmt.Fprintf(stdout, "  %s\n", line)
+	}
+	if *dryRun {
+		fmt.Fprintln(stdout, "Dry run complete. Re-run without --dry-run as root to apply changes.")
+	} else {
+		fmt.Fprintln(stdout, "Setup complete. Check the host with `volar system doctor`.")
+	}
+	return 0
+}
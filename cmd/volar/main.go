@@ -3,6 +3,7 @@
 package main
 
 import (
+	"errors"
 	"fmt"
 	"os"
 
@@ -10,10 +11,12 @@ import (
 )
 
 func main() {
-	// TUI temporarily disabled - using standard CLI for all invocations
-	// When invoked without arguments, will show help instead of TUI
-
+	// Without arguments volar prints help; the TUI is opened with volar tui.
 	if err := standard.Execute(); err != nil {
+		var exitErr *standard.ExitError
+		if errors.As(err, &exitErr) {
+			os.Exit(exitErr.Code)
+		}
 		fmt.Fprintf(os.Stderr, "command error: %v\n", err)
 		os.Exit(1)
 	}
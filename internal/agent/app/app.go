@@ -13,6 +13,7 @@ import (
 	"fmt"
 	"io"
 	"log"
+	"net"
 	"net/http"
 	"net/url"
 	"os"
@@ -44,6 +45,8 @@ const (
 	shellCommandEnvKey    = "volant_AGENT_SHELL"
 	shellArgsEnvKey       = "volant_AGENT_SHELL_ARGS"
 	shellTTYEnvKey        = "volant_AGENT_SHELL_TTY"
+	devModeEnvKey         = "volant_AGENT_DEV_MODE"
+	devSyncRootEnvKey     = "volant_AGENT_DEV_SYNC_ROOT"
 	defaultShellTTY       = "/dev/ttyS0"
 )
 
@@ -57,6 +60,10 @@ type Config struct {
 	EnableShell         bool
 	ShellCommand        []string
 	ShellTTY            string
+	// DevMode enables POST /v1/dev/sync, which may only write under
+	// DevSyncRoot.
+	DevMode     bool
+	DevSyncRoot string
 }
 
 type App struct {
@@ -67,15 +74,20 @@ type App struct {
 	started        time.Time
 	manifest       *pluginspec.Manifest
 	client         *http.Client
+	untimedClient  *http.Client
 	ctx            context.Context
 	mu             sync.Mutex
 	workloadCmd    *exec.Cmd
 	workloadDone   chan error
 	workloadCancel context.CancelFunc
 	workloadSpec   string
+	bundleEnv      []string
+	secretEnv      []string
 	shellMu        sync.Mutex
 	shellCancel    context.CancelFunc
 	shellDone      chan struct{}
+	cpuMu          sync.Mutex
+	cpuLast        cpuTimes
 }
 
 var errManifestFetch = errors.New("manifest fetch failed")
@@ -99,7 +111,9 @@ func Run(ctx context.Context) error {
 		log:     bootLog,
 		started: time.Now().UTC(),
 		client:  &http.Client{Timeout: cfg.DefaultTimeout + 30*time.Second},
-		ctx:     ctx,
+		// Actions with timeout_ms and streaming actions set their own deadline.
+		untimedClient: &http.Client{},
+		ctx:           ctx,
 	}
 
 	defer app.stopShell()
@@ -124,11 +138,15 @@ func Run(ctx context.Context) error {
 		}
 	}
 	if manifest != nil {
+		app.updateAgent(manifest)
 		app.manifest = manifest
 	} else {
 		logger.Printf("no manifest received at startup; waiting for configuration")
 	}
 
+	app.applyBundles()
+	app.applySecrets()
+
 	if app.manifest != nil {
 		if err := app.startWorkload(); app.handleFatal(err, "start workload") {
 			return err
@@ -155,12 +173,27 @@ func (a *App) run(ctx context.Context) error {
 	router.Use(middleware.RequestID)
 	router.Use(middleware.RealIP)
 	router.Use(middleware.Recoverer)
-	router.Use(middleware.Timeout(a.timeout + 30*time.Second))
+	// Streaming manifest actions are exempt from the request timeout.
+	timeout := middleware.Timeout(a.timeout + 30*time.Second)
 
-	router.Get("/healthz", a.handleHealth)
+	router.With(timeout).Get("/healthz", a.handleHealth)
 
 	router.Route("/v1", func(r chi.Router) {
-		if err := a.mountManifestRoutes(r); err != nil {
+		r.Group(func(r chi.Router) {
+			r.Use(timeout)
+			r.Get("/sysinfo", a.handleSysInfo)
+			r.Get("/metrics", a.handleMetrics)
+			r.Post("/dev/sync", a.handleDevSync)
+			r.Post("/hooks/pre-stop", a.handlePreStop)
+			r.Post("/hooks/post-upgrade", a.handlePostUpgrade)
+		})
+		// Transfers take as long as the file needs and commands as long as
+		// their own timeout allows.
+		r.Put("/files/*", a.handlePutFile)
+		r.Get("/files/*", a.handleGetFile)
+		r.Post("/exec", a.handleExec)
+		r.Get("/exec/stream", a.handleExecStream)
+		if err := a.mountManifestRoutes(r, timeout); err != nil {
 			a.log.Printf("manifest route mount error: %v", err)
 		}
 	})
@@ -351,11 +384,7 @@ func (a *App) launchShellDirect(ctx context.Context, ttyPath string, args []stri
 	cmd.Stdout = tty
 	cmd.Stderr = tty
 	cmd.Stdin = tty
-	cmd.SysProcAttr = &syscall.SysProcAttr{
-		Setsid:  true,
-		Setctty: true,
-		Ctty:    int(tty.Fd()),
-	}
+	cmd.SysProcAttr = shellProcAttr(tty)
 
 	if err := cmd.Start(); err != nil {
 		return fmt.Errorf("start shell: %w", err)
@@ -415,9 +444,9 @@ func (a *App) launchShellPTY(ctx context.Context, ttyPath string, args []string)
 func (a *App) waitShellProcess(ctx context.Context, cmd *exec.Cmd, errCh <-chan error) error {
 	select {
 	case <-ctx.Done():
-		pgid, pgErr := syscall.Getpgid(cmd.Process.Pid)
+		pgid, pgErr := processGroup(cmd.Process.Pid)
 		if pgErr == nil {
-			_ = syscall.Kill(-pgid, syscall.SIGTERM)
+			_ = signalGroup(pgid, syscall.SIGTERM)
 		} else {
 			_ = cmd.Process.Signal(syscall.SIGTERM)
 		}
@@ -426,7 +455,7 @@ func (a *App) waitShellProcess(ctx context.Context, cmd *exec.Cmd, errCh <-chan
 			return err
 		case <-time.After(5 * time.Second):
 			if pgErr == nil {
-				_ = syscall.Kill(-pgid, syscall.SIGKILL)
+				_ = signalGroup(pgid, syscall.SIGKILL)
 			} else {
 				_ = cmd.Process.Kill()
 			}
@@ -488,6 +517,8 @@ func loadConfig() Config {
 		EnableShell:         enableShell,
 		ShellCommand:        shellCommand,
 		ShellTTY:            shellTTY,
+		DevMode:             envBoolOrDefault(devModeEnvKey, false),
+		DevSyncRoot:         cleanDevSyncRoot(os.Getenv(devSyncRootEnvKey)),
 	}
 }
 
@@ -623,11 +654,11 @@ func (a *App) handleHealth(w http.ResponseWriter, r *http.Request) {
 	respondJSON(w, http.StatusOK, map[string]any{
 		"status":  "ok",
 		"uptime":  time.Since(a.started).Round(time.Second).String(),
-		"version": "v2.0",
+		"version": agentVersion,
 	})
 }
 
-func (a *App) mountManifestRoutes(router chi.Router) error {
+func (a *App) mountManifestRoutes(router chi.Router, timeout func(http.Handler) http.Handler) error {
 	if a.manifest == nil {
 		return nil
 	}
@@ -643,6 +674,7 @@ func (a *App) mountManifestRoutes(router chi.Router) error {
 		return err
 	}
 
+	bounded := router.With(timeout)
 	for actionName, action := range a.manifest.Actions {
 		method := strings.ToUpper(strings.TrimSpace(action.Method))
 		if method == "" {
@@ -656,18 +688,18 @@ func (a *App) mountManifestRoutes(router chi.Router) error {
 			path = "/" + path
 		}
 
-		var routeTimeout time.Duration
-		if action.TimeoutMs > 0 {
-			routeTimeout = time.Duration(action.TimeoutMs) * time.Millisecond
-		} else {
-			routeTimeout = a.timeout
+		if action.Stream || action.TimeoutMs > 0 {
+			// Only timeout_ms bounds these, in place of the agent's defaults.
+			timeout := time.Duration(action.TimeoutMs) * time.Millisecond
+			router.MethodFunc(method, path, a.streamManifestAction(parsedBase, path, timeout, actionName))
+			continue
 		}
 
-		handler := a.forwardManifestAction(parsedBase, path, routeTimeout, actionName)
-		router.MethodFunc(method, path, handler)
+		handler := a.forwardManifestAction(parsedBase, path, a.timeout, actionName)
+		bounded.MethodFunc(method, path, handler)
 	}
 
-	a.mountWorkloadPassthrough(router, parsedBase)
+	a.mountWorkloadPassthrough(bounded, parsedBase)
 	return nil
 }
 
@@ -699,7 +731,7 @@ func (a *App) forwardManifestAction(base *url.URL, actionPath string, timeout ti
 
 		resp, err := a.client.Do(proxyReq)
 		if err != nil {
-			errorJSON(w, http.StatusBadGateway, err)
+			errorJSON(w, proxyErrorStatus(err), err)
 			return
 		}
 		defer resp.Body.Close()
@@ -712,6 +744,80 @@ func (a *App) forwardManifestAction(base *url.URL, actionPath string, timeout ti
 	}
 }
 
+// streamManifestAction proxies an action to the workload free of the agent's
+// default timeouts, flushing each chunk of the response to the caller as it
+// arrives so streaming actions report progress.
+func (a *App) streamManifestAction(base *url.URL, actionPath string, timeout time.Duration, actionName string) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		ctx := req.Context()
+		if timeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, timeout)
+			defer cancel()
+		}
+
+		body, err := io.ReadAll(req.Body)
+		if err != nil {
+			errorJSON(w, http.StatusBadRequest, err)
+			return
+		}
+		_ = req.Body.Close()
+
+		rel := &url.URL{Path: actionPath, RawQuery: req.URL.RawQuery}
+		target := base.ResolveReference(rel)
+
+		proxyReq, err := http.NewRequestWithContext(ctx, req.Method, target.String(), bytes.NewReader(body))
+		if err != nil {
+			errorJSON(w, http.StatusBadGateway, err)
+			return
+		}
+		copyHeaders(proxyReq.Header, req.Header)
+
+		resp, err := a.untimedClient.Do(proxyReq)
+		if err != nil {
+			errorJSON(w, proxyErrorStatus(err), err)
+			return
+		}
+		defer resp.Body.Close()
+
+		clearDeadlines(w)
+		rc := http.NewResponseController(w)
+		copyHeaders(w.Header(), resp.Header)
+		w.WriteHeader(resp.StatusCode)
+		_ = rc.Flush()
+
+		chunk := make([]byte, 32<<10)
+		for {
+			n, err := resp.Body.Read(chunk)
+			if n > 0 {
+				if _, werr := w.Write(chunk[:n]); werr != nil {
+					return
+				}
+				_ = rc.Flush()
+			}
+			if err != nil {
+				if !errors.Is(err, io.EOF) {
+					a.log.Printf("manifest action %s stream error: %v", actionName, err)
+				}
+				return
+			}
+		}
+	}
+}
+
+// proxyErrorStatus is 504 for a workload that did not answer in time and
+// 502 for any other failure to reach it.
+func proxyErrorStatus(err error) int {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return http.StatusGatewayTimeout
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return http.StatusGatewayTimeout
+	}
+	return http.StatusBadGateway
+}
+
 func copyHeaders(dst, src http.Header) {
 	dst.Del("Host")
 	for key, values := range src {
@@ -821,7 +927,7 @@ func (a *App) forwardWorkload(base *url.URL, timeout time.Duration, stripPrefix
 
 		resp, err := a.client.Do(proxyReq)
 		if err != nil {
-			errorJSON(w, http.StatusBadGateway, err)
+			errorJSON(w, proxyErrorStatus(err), err)
 			return
 		}
 		defer resp.Body.Close()
@@ -875,6 +981,8 @@ func (a *App) startWorkload() error {
 	}
 	manifest := *a.manifest
 	ctx := a.ctx
+	bundleEnv := a.bundleEnv
+	secretEnv := a.secretEnv
 	existingCmd := a.workloadCmd
 	existingSpec := a.workloadSpec
 	existingCancel := a.workloadCancel
@@ -918,13 +1026,17 @@ func (a *App) startWorkload() error {
 	}
 	procCtx, cancel := context.WithCancel(ctx)
 	cmd := exec.CommandContext(procCtx, manifest.Workload.Entrypoint[0], manifest.Workload.Entrypoint[1:]...)
-	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	cmd.SysProcAttr = workloadProcAttr()
 
 	env := os.Environ()
 	env = ensurePath(env, []string{"/usr/local/bin", "/usr/bin", "/bin"})
 	for key, value := range manifest.Workload.Env {
 		env = append(env, fmt.Sprintf("%s=%s", key, value))
 	}
+	// Bundle values and secrets come last so they override manifest
+	// defaults.
+	env = append(env, bundleEnv...)
+	env = append(env, secretEnv...)
 	cmd.Env = env
 	if dir := strings.TrimSpace(manifest.Workload.WorkDir); dir != "" {
 		cmd.Dir = dir
@@ -1004,9 +1116,9 @@ func (a *App) stopWorkload() {
 		pgidErr error
 	)
 	if cmd != nil && cmd.Process != nil {
-		pgid, pgidErr = syscall.Getpgid(cmd.Process.Pid)
+		pgid, pgidErr = processGroup(cmd.Process.Pid)
 		if pgidErr == nil {
-			if killErr := syscall.Kill(-pgid, syscall.SIGTERM); killErr != nil && !errors.Is(killErr, syscall.ESRCH) {
+			if killErr := signalGroup(pgid, syscall.SIGTERM); killErr != nil && !errors.Is(killErr, syscall.ESRCH) {
 				a.log.Printf("workload process group kill error: %v", killErr)
 			}
 		} else if !errors.Is(pgidErr, syscall.ESRCH) {
@@ -1020,7 +1132,7 @@ func (a *App) stopWorkload() {
 		case <-time.After(10 * time.Second):
 			a.log.Printf("workload process shutdown timed out")
 			if pgidErr == nil && pgid != 0 {
-				if killErr := syscall.Kill(-pgid, syscall.SIGKILL); killErr != nil && !errors.Is(killErr, syscall.ESRCH) {
+				if killErr := signalGroup(pgid, syscall.SIGKILL); killErr != nil && !errors.Is(killErr, syscall.ESRCH) {
 					a.log.Printf("workload process group kill error: %v", killErr)
 				}
 			}
@@ -0,0 +1,151 @@
+// Copyright (c) 2025 HYPR. PTE. LTD.
+//
+// Business Source License 1.1
+// See LICENSE file in the project root for details.
+
+//go:build linux
+// +build linux
+
+package app
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/volantvm/volant/internal/pluginspec"
+)
+
+const (
+	bundleFetchAttempts = 5
+	bundleFetchBackoff  = time.Second
+)
+
+// applyBundles fetches the config bundles attached to this VM and writes
+// them into the guest before the workload starts. Bundle data keys are also
+// exported to the workload environment.
+func (a *App) applyBundles() {
+	vmName := cmdlineValue(pluginspec.VMNameKey)
+	if vmName == "" {
+		return
+	}
+	apiHost := cmdlineValue(pluginspec.APIHostKey)
+	apiPort := cmdlineValue(pluginspec.APIPortKey)
+	if apiHost == "" || apiPort == "" {
+		a.log.Printf("config bundles: api endpoint missing from cmdline")
+		return
+	}
+
+	bundlesURL := fmt.Sprintf("http://%s:%s/api/v1/vms/%s/bundles", apiHost, apiPort, url.PathEscape(vmName))
+	var (
+		bundles []pluginspec.Bundle
+		err     error
+	)
+	for attempt := 1; attempt <= bundleFetchAttempts; attempt++ {
+		bundles, err = fetchBundles(a.client, bundlesURL)
+		if err == nil {
+			break
+		}
+		a.log.Printf("config bundles: fetch attempt %d failed: %v", attempt, err)
+		time.Sleep(bundleFetchBackoff)
+	}
+	if err != nil {
+		a.log.Printf("config bundles: giving up; workload starts without them")
+		return
+	}
+
+	var env []string
+	for _, bundle := range bundles {
+		if err := writeBundle(bundle); err != nil {
+			a.log.Printf("config bundle %s: %v", bundle.Name, err)
+			continue
+		}
+		for key, value := range bundle.Data {
+			env = append(env, key+"="+value)
+		}
+		a.log.Printf("config bundle %s v%d applied", bundle.Name, bundle.Version)
+	}
+
+	a.mu.Lock()
+	a.bundleEnv = env
+	a.mu.Unlock()
+}
+
+func fetchBundles(client *http.Client, bundlesURL string) ([]pluginspec.Bundle, error) {
+	resp, err := client.Get(bundlesURL)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	var bundles []pluginspec.Bundle
+	if err := json.NewDecoder(resp.Body).Decode(&bundles); err != nil {
+		return nil, fmt.Errorf("decode bundles: %w", err)
+	}
+	return bundles, nil
+}
+
+func writeBundle(bundle pluginspec.Bundle) error {
+	bundle.Normalize()
+	if err := bundle.Validate(); err != nil {
+		return err
+	}
+	dataDir := filepath.Join(pluginspec.BundleDir, bundle.Name)
+	if err := os.MkdirAll(dataDir, 0o700); err != nil {
+		return fmt.Errorf("create %s: %w", dataDir, err)
+	}
+	for key, value := range bundle.Data {
+		if err := writeBundleFile(filepath.Join(dataDir, key), []byte(value), 0o600); err != nil {
+			return err
+		}
+	}
+	for _, file := range bundle.Files {
+		data, err := file.Bytes()
+		if err != nil {
+			return err
+		}
+		mode, err := file.FileMode()
+		if err != nil {
+			return err
+		}
+		if err := os.MkdirAll(filepath.Dir(file.Path), 0o755); err != nil {
+			return fmt.Errorf("create %s: %w", filepath.Dir(file.Path), err)
+		}
+		if err := writeBundleFile(file.Path, data, os.FileMode(mode)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeBundleFile replaces path atomically so a reader never sees a partial
+// secret, and applies mode regardless of the umask.
+func writeBundleFile(path string, data []byte, mode os.FileMode) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+strings.TrimPrefix(filepath.Base(path), ".")+".tmp-")
+	if err != nil {
+		return fmt.Errorf("write %s: %w", path, err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("write %s: %w", path, err)
+	}
+	if err := tmp.Chmod(mode); err != nil {
+		tmp.Close()
+		return fmt.Errorf("chmod %s: %w", path, err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("write %s: %w", path, err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("write %s: %w", path, err)
+	}
+	return nil
+}
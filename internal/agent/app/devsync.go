@@ -0,0 +1,222 @@
+// Copyright (c) 2025 HYPR. PTE. LTD.
+//
+// Business Source License 1.1
+// See LICENSE file in the project root for details.
+
+package app
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// maxDevSyncBody bounds a sync request. File contents travel base64
+// encoded, so a batch carries about three quarters of this in file data.
+const maxDevSyncBody = 64 << 20
+
+// devSyncFile is a single file pushed by `volar dev sync`.
+type devSyncFile struct {
+	Path    string      `json:"path"`
+	Mode    os.FileMode `json:"mode,omitempty"`
+	Content []byte      `json:"content"`
+}
+
+// devSyncRequest is one batch of changes relative to Root.
+type devSyncRequest struct {
+	Root            string        `json:"root"`
+	Files           []devSyncFile `json:"files,omitempty"`
+	Deletes         []string      `json:"deletes,omitempty"`
+	RestartWorkload bool          `json:"restart_workload,omitempty"`
+}
+
+type devSyncResponse struct {
+	Written   int  `json:"written"`
+	Deleted   int  `json:"deleted"`
+	Restarted bool `json:"restarted"`
+}
+
+func (a *App) handleDevSync(w http.ResponseWriter, r *http.Request) {
+	if !a.cfg.DevMode || a.cfg.DevSyncRoot == "" {
+		errorJSON(w, http.StatusForbidden, fmt.Errorf("dev sync is disabled; boot the VM with %s=1 and %s=<dir>", devModeEnvKey, devSyncRootEnvKey))
+		return
+	}
+	var req devSyncRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxDevSyncBody)).Decode(&req); err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			errorJSON(w, http.StatusRequestEntityTooLarge, fmt.Errorf("sync request exceeds %d bytes", maxDevSyncBody))
+			return
+		}
+		errorJSON(w, http.StatusBadRequest, fmt.Errorf("decode sync request: %w", err))
+		return
+	}
+	root, err := resolveSyncRoot(a.cfg.DevSyncRoot, req.Root)
+	if err != nil {
+		errorJSON(w, http.StatusForbidden, err)
+		return
+	}
+	if err := os.MkdirAll(a.cfg.DevSyncRoot, 0o755); err != nil {
+		errorJSON(w, http.StatusInternalServerError, fmt.Errorf("create sync root: %w", err))
+		return
+	}
+	realRoot, err := filepath.EvalSymlinks(a.cfg.DevSyncRoot)
+	if err != nil {
+		errorJSON(w, http.StatusInternalServerError, fmt.Errorf("resolve sync root: %w", err))
+		return
+	}
+	if err := checkSyncTarget(realRoot, root); err != nil {
+		errorJSON(w, http.StatusForbidden, err)
+		return
+	}
+	if err := os.MkdirAll(root, 0o755); err != nil {
+		errorJSON(w, http.StatusInternalServerError, fmt.Errorf("create sync root: %w", err))
+		return
+	}
+
+	var resp devSyncResponse
+	for _, file := range req.Files {
+		target, err := resolveSyncPath(root, file.Path)
+		if err == nil {
+			err = checkSyncTarget(realRoot, filepath.Dir(target))
+		}
+		if err != nil {
+			errorJSON(w, http.StatusBadRequest, err)
+			return
+		}
+		if err := writeFileAtomic(target, file.Content, file.Mode); err != nil {
+			errorJSON(w, http.StatusInternalServerError, err)
+			return
+		}
+		resp.Written++
+	}
+	for _, rel := range req.Deletes {
+		target, err := resolveSyncPath(root, rel)
+		if err == nil {
+			err = checkSyncTarget(realRoot, filepath.Dir(target))
+		}
+		if err != nil {
+			errorJSON(w, http.StatusBadRequest, err)
+			return
+		}
+		if err := os.RemoveAll(target); err != nil {
+			errorJSON(w, http.StatusInternalServerError, fmt.Errorf("delete %s: %w", rel, err))
+			return
+		}
+		resp.Deleted++
+	}
+
+	if req.RestartWorkload && a.manifest != nil {
+		a.stopWorkload()
+		if err := a.startWorkload(); err != nil {
+			errorJSON(w, http.StatusInternalServerError, fmt.Errorf("restart workload: %w", err))
+			return
+		}
+		resp.Restarted = true
+	}
+
+	a.log.Printf("dev sync %s: %d written, %d deleted", root, resp.Written, resp.Deleted)
+	respondJSON(w, http.StatusOK, resp)
+}
+
+// cleanDevSyncRoot cleans the configured sync root. Relative paths and "/"
+// leave dev sync disabled.
+func cleanDevSyncRoot(raw string) string {
+	root := filepath.Clean(strings.TrimSpace(raw))
+	if !filepath.IsAbs(root) || root == string(filepath.Separator) {
+		return ""
+	}
+	return root
+}
+
+// resolveSyncRoot checks that the root of a sync request is the configured
+// sync root or a directory below it.
+func resolveSyncRoot(syncRoot, requested string) (string, error) {
+	root := filepath.Clean(strings.TrimSpace(requested))
+	if !filepath.IsAbs(root) {
+		return "", fmt.Errorf("sync root must be an absolute path")
+	}
+	if root != syncRoot && !withinDir(syncRoot, root) {
+		return "", fmt.Errorf("sync root %s is outside %s", root, syncRoot)
+	}
+	return root, nil
+}
+
+// resolveSyncPath joins rel onto root and rejects paths that escape it or
+// name root itself.
+func resolveSyncPath(root, rel string) (string, error) {
+	rel = filepath.FromSlash(strings.TrimSpace(rel))
+	if rel == "" || filepath.IsAbs(rel) {
+		return "", fmt.Errorf("invalid sync path %q", rel)
+	}
+	target := filepath.Join(root, rel)
+	if !withinDir(root, target) {
+		return "", fmt.Errorf("sync path %q escapes root", rel)
+	}
+	return target, nil
+}
+
+// checkSyncTarget resolves symlinks in the deepest existing ancestor of
+// path, so a link inside the sync root cannot redirect writes outside it.
+func checkSyncTarget(realRoot, path string) error {
+	existing := path
+	for {
+		if _, err := os.Lstat(existing); err == nil {
+			break
+		}
+		parent := filepath.Dir(existing)
+		if parent == existing {
+			break
+		}
+		existing = parent
+	}
+	resolved, err := filepath.EvalSymlinks(existing)
+	if err != nil {
+		return fmt.Errorf("resolve %s: %w", path, err)
+	}
+	if resolved != realRoot && !withinDir(realRoot, resolved) {
+		return fmt.Errorf("%s resolves outside the sync root", path)
+	}
+	return nil
+}
+
+// withinDir reports whether path lies strictly below dir.
+func withinDir(dir, path string) bool {
+	return strings.HasPrefix(path, dir+string(filepath.Separator))
+}
+
+func writeFileAtomic(path string, content []byte, mode os.FileMode) error {
+	if mode == 0 {
+		mode = 0o644
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("create parent of %s: %w", path, err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".volar-sync-*")
+	if err != nil {
+		return fmt.Errorf("create temp for %s: %w", path, err)
+	}
+	tmpName := tmp.Name()
+	if _, err := tmp.Write(content); err != nil {
+		_ = tmp.Close()
+		_ = os.Remove(tmpName)
+		return fmt.Errorf("write %s: %w", path, err)
+	}
+	if err := tmp.Close(); err != nil {
+		_ = os.Remove(tmpName)
+		return fmt.Errorf("close %s: %w", path, err)
+	}
+	if err := os.Chmod(tmpName, mode.Perm()); err != nil {
+		_ = os.Remove(tmpName)
+		return fmt.Errorf("chmod %s: %w", path, err)
+	}
+	if err := os.Rename(tmpName, path); err != nil {
+		_ = os.Remove(tmpName)
+		return fmt.Errorf("rename %s: %w", path, err)
+	}
+	return nil
+}
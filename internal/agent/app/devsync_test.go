@@ -0,0 +1,122 @@
+// Copyright (c) 2025 HYPR. PTE. LTD.
+//
+// Business Source License 1.1
+// See LICENSE file in the project root for details.
+
+package app
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestResolveSyncPath(t *testing.T) {
+	root := filepath.FromSlash("/srv/app")
+	for _, tc := range []struct {
+		rel  string
+		want string
+	}{
+		{rel: "main.go", want: "/srv/app/main.go"},
+		{rel: "web/static/app.js", want: "/srv/app/web/static/app.js"},
+		{rel: "web/../main.go", want: "/srv/app/main.go"},
+		{rel: " lib/x.go ", want: "/srv/app/lib/x.go"},
+		{rel: ""},
+		{rel: "."},
+		{rel: "web/.."},
+		{rel: "/etc/passwd"},
+		{rel: "../app-other/x"},
+		{rel: "web/../../etc/passwd"},
+		{rel: ".."},
+	} {
+		got, err := resolveSyncPath(root, tc.rel)
+		if tc.want == "" {
+			if err == nil {
+				t.Errorf("resolveSyncPath(%q) = %q, expected it refused", tc.rel, got)
+			}
+			continue
+		}
+		if err != nil || got != filepath.FromSlash(tc.want) {
+			t.Errorf("resolveSyncPath(%q) = %q, %v; want %q", tc.rel, got, err, tc.want)
+		}
+	}
+}
+
+func TestResolveSyncRoot(t *testing.T) {
+	for _, tc := range []struct {
+		requested string
+		ok        bool
+	}{
+		{"/srv/app", true},
+		{"/srv/app/web/", true},
+		{"/srv/app/../app/web", true},
+		{"/srv/application", false},
+		{"/srv", false},
+		{"/etc", false},
+		{"srv/app", false},
+	} {
+		if _, err := resolveSyncRoot("/srv/app", tc.requested); (err == nil) != tc.ok {
+			t.Errorf("resolveSyncRoot(%q): err %v, want ok=%v", tc.requested, err, tc.ok)
+		}
+	}
+	if cleanDevSyncRoot("/") != "" || cleanDevSyncRoot("app") != "" || cleanDevSyncRoot(" /srv/app/ ") != "/srv/app" {
+		t.Fatal("unexpected cleaned sync roots")
+	}
+}
+
+func TestDevSync(t *testing.T) {
+	dir := t.TempDir()
+	root := filepath.Join(dir, "app")
+	outside := filepath.Join(dir, "outside")
+	if err := os.MkdirAll(outside, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	a := &App{log: log.New(io.Discard, "", 0)}
+	sync := func(req devSyncRequest) *httptest.ResponseRecorder {
+		body, err := json.Marshal(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		rec := httptest.NewRecorder()
+		a.handleDevSync(rec, httptest.NewRequest(http.MethodPost, "/v1/dev/sync", bytes.NewReader(body)))
+		return rec
+	}
+
+	if rec := sync(devSyncRequest{Root: root}); rec.Code != http.StatusForbidden {
+		t.Fatalf("expected dev sync disabled without dev mode, got %d", rec.Code)
+	}
+
+	a.cfg = Config{DevMode: true, DevSyncRoot: root}
+	if rec := sync(devSyncRequest{Root: outside, Files: []devSyncFile{{Path: "x", Content: []byte("x")}}}); rec.Code != http.StatusForbidden {
+		t.Fatalf("expected a root outside the sync root refused, got %d", rec.Code)
+	}
+	rec := sync(devSyncRequest{Root: root, Files: []devSyncFile{{Path: "web/index.html", Content: []byte("hi")}}})
+	if rec.Code != http.StatusOK {
+		t.Fatalf("sync: %d %s", rec.Code, rec.Body)
+	}
+	if data, err := os.ReadFile(filepath.Join(root, "web", "index.html")); err != nil || string(data) != "hi" {
+		t.Fatalf("expected file written: %q %v", data, err)
+	}
+
+	if err := os.Symlink(outside, filepath.Join(root, "escape")); err != nil {
+		t.Fatal(err)
+	}
+	if rec := sync(devSyncRequest{Root: root, Files: []devSyncFile{{Path: "escape/x", Content: []byte("x")}}}); rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected a write through a symlink refused, got %d", rec.Code)
+	}
+	if rec := sync(devSyncRequest{Root: filepath.Join(root, "escape")}); rec.Code != http.StatusForbidden {
+		t.Fatalf("expected a root through a symlink refused, got %d", rec.Code)
+	}
+	if _, err := os.Stat(filepath.Join(outside, "x")); !os.IsNotExist(err) {
+		t.Fatalf("expected nothing written outside the sync root: %v", err)
+	}
+	if rec := sync(devSyncRequest{Root: root, Deletes: []string{"."}}); rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected deleting the root refused, got %d", rec.Code)
+	}
+}
@@ -0,0 +1,362 @@
+// Copyright (c) 2025 HYPR. PTE. LTD.
+//
+// Business Source License 1.1
+// See LICENSE file in the project root for details.
+
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	"github.com/volantvm/volant/internal/pluginspec"
+)
+
+const (
+	// execWaitDelay is how long a finished command's children may hold its
+	// output open before they are cut off.
+	execWaitDelay = 2 * time.Second
+	// execStartTimeout bounds the wait for a streaming session's request.
+	execStartTimeout = 30 * time.Second
+	execChunkSize    = 32 << 10
+
+	codeExecNotAllowed = "EXEC_NOT_ALLOWED"
+)
+
+// execError is a request the agent refuses to run.
+type execError struct {
+	status int
+	code   string
+	err    error
+}
+
+func (e *execError) Error() string { return e.err.Error() }
+
+// prepareExec checks req against the manifest's exec policy and returns the
+// command to run and its context, bounded by the request timeout. The caller
+// releases the returned cancel func once the command is done.
+func (a *App) prepareExec(parent context.Context, req *pluginspec.ExecRequest) (*exec.Cmd, context.Context, context.CancelFunc, error) {
+	if err := req.Validate(); err != nil {
+		return nil, nil, nil, &execError{status: http.StatusBadRequest, code: "INVALID_REQUEST", err: err}
+	}
+
+	env := ensurePath(os.Environ(), []string{"/usr/local/bin", "/usr/bin", "/bin"})
+	var policy *pluginspec.Exec
+	dir := ""
+	a.mu.Lock()
+	if a.manifest != nil {
+		policy = a.manifest.Exec
+		for key, value := range a.manifest.Workload.Env {
+			env = append(env, fmt.Sprintf("%s=%s", key, value))
+		}
+		dir = strings.TrimSpace(a.manifest.Workload.WorkDir)
+	}
+	env = append(env, a.bundleEnv...)
+	env = append(env, a.secretEnv...)
+	a.mu.Unlock()
+
+	if policy == nil {
+		return nil, nil, nil, &execError{status: http.StatusForbidden, code: codeExecNotAllowed, err: errors.New("exec is not enabled by the plugin manifest")}
+	}
+	// Requests without a timeout get the default, lowered to the manifest's cap.
+	if req.TimeoutMs == 0 && policy.MaxTimeoutMs > 0 {
+		req.TimeoutMs = min(req.Timeout(), policy.MaxTimeoutMs)
+	}
+	if policy.MaxTimeoutMs > 0 && req.Timeout() > policy.MaxTimeoutMs {
+		return nil, nil, nil, &execError{status: http.StatusBadRequest, code: "INVALID_REQUEST", err: fmt.Errorf("timeout_ms exceeds the manifest's max_timeout_ms of %d", policy.MaxTimeoutMs)}
+	}
+	if req.WorkDir != "" {
+		dir = req.WorkDir
+	}
+	// Binaries are resolved with the agent's PATH, never the caller's, so
+	// request env cannot steer an allowed name to another binary.
+	binary, err := resolveBinary(req.Command[0], dir, env)
+	if err != nil {
+		return nil, nil, nil, &execError{status: http.StatusNotFound, code: "NOT_FOUND", err: err}
+	}
+	if !execAllowed(policy.Allow, binary, dir, env) {
+		return nil, nil, nil, &execError{status: http.StatusForbidden, code: codeExecNotAllowed, err: fmt.Errorf("%s is not in the manifest's exec allowlist", binary)}
+	}
+	for key, value := range req.Env {
+		env = append(env, key+"="+value)
+	}
+
+	ctx, cancel := context.WithTimeout(parent, time.Duration(req.Timeout())*time.Millisecond)
+	cmd := exec.CommandContext(ctx, binary, req.Command[1:]...)
+	cmd.Args[0] = req.Command[0]
+	cmd.Env = env
+	cmd.Dir = dir
+	// The command gets its own process group so a timeout also kills what
+	// it forked.
+	cmd.SysProcAttr = workloadProcAttr()
+	cmd.Cancel = func() error {
+		if err := signalGroup(cmd.Process.Pid, syscall.SIGKILL); err != nil {
+			return cmd.Process.Kill()
+		}
+		return nil
+	}
+	cmd.WaitDelay = execWaitDelay
+	return cmd, ctx, cancel, nil
+}
+
+// resolveBinary returns the absolute path of the executable name refers to,
+// looking bare names up in env's PATH and relative paths under dir.
+func resolveBinary(name, dir string, env []string) (string, error) {
+	if strings.Contains(name, "/") {
+		path := name
+		if !filepath.IsAbs(path) {
+			base := dir
+			if base == "" {
+				base = "/"
+			}
+			path = filepath.Join(base, path)
+		}
+		path = filepath.Clean(path)
+		if !isExecutable(path) {
+			return "", fmt.Errorf("%s is not an executable file", path)
+		}
+		return path, nil
+	}
+	pathValue := ""
+	for _, kv := range env {
+		if value, ok := strings.CutPrefix(kv, "PATH="); ok {
+			pathValue = value
+		}
+	}
+	for _, entry := range filepath.SplitList(pathValue) {
+		if !filepath.IsAbs(entry) {
+			continue
+		}
+		if path := filepath.Join(entry, name); isExecutable(path) {
+			return path, nil
+		}
+	}
+	return "", fmt.Errorf("command %q not found in PATH", name)
+}
+
+func isExecutable(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.Mode().IsRegular() && info.Mode().Perm()&0o111 != 0
+}
+
+// execAllowed reports whether binary, an absolute path, matches an allowlist
+// entry.
+func execAllowed(allow []string, binary, dir string, env []string) bool {
+	for _, entry := range allow {
+		switch {
+		case entry == "*":
+			return true
+		case strings.HasPrefix(entry, "/"):
+			if ok, _ := filepath.Match(entry, binary); ok {
+				return true
+			}
+		default:
+			if resolved, err := resolveBinary(entry, dir, env); err == nil && resolved == binary {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// execResult reports how cmd ended after running since started.
+func execResult(ctx context.Context, cmd *exec.Cmd, err error, started time.Time, timeoutMs int64) pluginspec.ExecResult {
+	result := pluginspec.ExecResult{ExitCode: -1, DurationMs: time.Since(started).Milliseconds()}
+	if cmd.ProcessState != nil {
+		result.ExitCode = cmd.ProcessState.ExitCode()
+	}
+	var exitErr *exec.ExitError
+	switch {
+	case errors.Is(ctx.Err(), context.DeadlineExceeded):
+		result.TimedOut = true
+		result.Error = fmt.Sprintf("timed out after %dms", timeoutMs)
+	case errors.As(err, &exitErr):
+		if result.ExitCode < 0 {
+			result.Error = exitErr.Error()
+		}
+	case err != nil:
+		result.Error = err.Error()
+	}
+	return result
+}
+
+// handleExec runs a command to completion and returns its exit code and
+// output, each stream capped at MaxExecOutputBytes.
+func (a *App) handleExec(w http.ResponseWriter, r *http.Request) {
+	var req pluginspec.ExecRequest
+	if err := json.NewDecoder(io.LimitReader(r.Body, 2*pluginspec.MaxExecOutputBytes)).Decode(&req); err != nil {
+		errorJSON(w, http.StatusBadRequest, fmt.Errorf("decode exec request: %w", err))
+		return
+	}
+	cmd, ctx, cancel, err := a.prepareExec(withTraceparent(r.Context(), r), &req)
+	if err != nil {
+		var refused *execError
+		if errors.As(err, &refused) {
+			respondJSON(w, refused.status, map[string]any{"error": refused.Error(), "code": refused.code})
+			return
+		}
+		errorJSON(w, http.StatusInternalServerError, err)
+		return
+	}
+	defer cancel()
+	clearDeadlines(w)
+
+	stdout := &cappedBuffer{limit: pluginspec.MaxExecOutputBytes}
+	stderr := &cappedBuffer{limit: pluginspec.MaxExecOutputBytes}
+	cmd.Stdin = strings.NewReader(req.Stdin)
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+	started := time.Now()
+	err = cmd.Run()
+	result := execResult(ctx, cmd, err, started, req.Timeout())
+	result.Stdout = stdout.String()
+	result.Stderr = stderr.String()
+	result.Truncated = stdout.truncated || stderr.truncated
+	a.log.Printf("exec %s: exit %d (%dms)", cmd.Path, result.ExitCode, result.DurationMs)
+	respondJSON(w, http.StatusOK, result)
+}
+
+// handleExecStream runs a command over a WebSocket. The first message is the
+// JSON ExecRequest; after it, binary messages carry stdin, stdout and stderr
+// behind a stream byte, and a final text message carries the ExecResult.
+// Hanging up kills the command.
+func (a *App) handleExecStream(w http.ResponseWriter, r *http.Request) {
+	upgrader := websocket.Upgrader{CheckOrigin: func(*http.Request) bool { return true }}
+	conn, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		a.log.Printf("exec stream upgrade: %v", err)
+		return
+	}
+	defer conn.Close()
+
+	var writeMu sync.Mutex
+	send := func(messageType int, data []byte) error {
+		writeMu.Lock()
+		defer writeMu.Unlock()
+		return conn.WriteMessage(messageType, data)
+	}
+	finish := func(result pluginspec.ExecResult, closeCode int) {
+		data, _ := json.Marshal(result)
+		_ = send(websocket.TextMessage, data)
+		writeMu.Lock()
+		_ = conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(closeCode, ""), time.Now().Add(time.Second))
+		writeMu.Unlock()
+	}
+
+	var req pluginspec.ExecRequest
+	_ = conn.SetReadDeadline(time.Now().Add(execStartTimeout))
+	if err := conn.ReadJSON(&req); err != nil {
+		finish(pluginspec.ExecResult{ExitCode: -1, Error: "read exec request: " + err.Error(), Code: "INVALID_REQUEST"}, websocket.CloseUnsupportedData)
+		return
+	}
+	_ = conn.SetReadDeadline(time.Time{})
+
+	session, hangUp := context.WithCancel(withTraceparent(r.Context(), r))
+	defer hangUp()
+	cmd, ctx, cancel, err := a.prepareExec(session, &req)
+	if err != nil {
+		result := pluginspec.ExecResult{ExitCode: -1, Error: err.Error()}
+		var refused *execError
+		if errors.As(err, &refused) {
+			result.Code = refused.code
+		}
+		finish(result, websocket.ClosePolicyViolation)
+		return
+	}
+	defer cancel()
+
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		finish(pluginspec.ExecResult{ExitCode: -1, Error: err.Error()}, websocket.CloseInternalServerErr)
+		return
+	}
+	cmd.Stdout = &execStreamWriter{stream: pluginspec.ExecStreamStdout, send: send}
+	cmd.Stderr = &execStreamWriter{stream: pluginspec.ExecStreamStderr, send: send}
+	started := time.Now()
+	if err := cmd.Start(); err != nil {
+		finish(execResult(ctx, cmd, err, started, req.Timeout()), websocket.CloseNormalClosure)
+		return
+	}
+
+	go func() {
+		defer stdin.Close()
+		if req.Stdin != "" {
+			if _, err := io.WriteString(stdin, req.Stdin); err != nil {
+				return
+			}
+		}
+		for {
+			messageType, data, err := conn.ReadMessage()
+			if err != nil {
+				// The caller is gone; nobody is left to read the output.
+				hangUp()
+				return
+			}
+			if messageType != websocket.BinaryMessage || len(data) == 0 || data[0] != pluginspec.ExecStreamStdin {
+				continue
+			}
+			if len(data) == 1 {
+				_ = stdin.Close()
+				continue
+			}
+			_, _ = stdin.Write(data[1:])
+		}
+	}()
+
+	err = cmd.Wait()
+	result := execResult(ctx, cmd, err, started, req.Timeout())
+	a.log.Printf("exec stream %s: exit %d (%dms)", cmd.Path, result.ExitCode, result.DurationMs)
+	finish(result, websocket.CloseNormalClosure)
+}
+
+// execStreamWriter sends a command's output as binary messages tagged with
+// its stream.
+type execStreamWriter struct {
+	stream byte
+	send   func(int, []byte) error
+}
+
+func (w *execStreamWriter) Write(p []byte) (int, error) {
+	for written := 0; written < len(p); {
+		n := min(len(p)-written, execChunkSize)
+		frame := make([]byte, 0, n+1)
+		frame = append(append(frame, w.stream), p[written:written+n]...)
+		if err := w.send(websocket.BinaryMessage, frame); err != nil {
+			return written, err
+		}
+		written += n
+	}
+	return len(p), nil
+}
+
+// cappedBuffer keeps the first limit bytes written to it and drops the rest.
+type cappedBuffer struct {
+	limit     int
+	buf       strings.Builder
+	truncated bool
+}
+
+func (b *cappedBuffer) Write(p []byte) (int, error) {
+	if room := b.limit - b.buf.Len(); room < len(p) {
+		b.truncated = true
+		b.buf.Write(p[:max(room, 0)])
+		return len(p), nil
+	}
+	b.buf.Write(p)
+	return len(p), nil
+}
+
+func (b *cappedBuffer) String() string { return b.buf.String() }
@@ -0,0 +1,43 @@
+// Copyright (c) 2025 HYPR. PTE. LTD.
+//
+// Business Source License 1.1
+// See LICENSE file in the project root for details.
+
+package app
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"slices"
+	"testing"
+
+	"github.com/volantvm/volant/internal/pluginspec"
+)
+
+func TestPrepareExecEnv(t *testing.T) {
+	a := &App{
+		log:      log.New(io.Discard, "", 0),
+		manifest: &pluginspec.Manifest{Exec: &pluginspec.Exec{Allow: []string{"*"}}},
+	}
+	for _, key := range []string{"LD_PRELOAD", "LD_LIBRARY_PATH", "GCONV_PATH", "PYTHONSTARTUP", "NODE_OPTIONS", "BASH_ENV", "BASH_FUNC_ls%%"} {
+		req := &pluginspec.ExecRequest{Command: []string{"true"}, Env: map[string]string{key: "/tmp/evil.so"}}
+		_, _, _, err := a.prepareExec(context.Background(), req)
+		var execErr *execError
+		if !errors.As(err, &execErr) || execErr.status != http.StatusBadRequest {
+			t.Errorf("env %s: expected the request refused with 400, got %v", key, err)
+		}
+	}
+
+	req := &pluginspec.ExecRequest{Command: []string{"true"}, Env: map[string]string{"GREETING": "hi"}}
+	cmd, _, cancel, err := a.prepareExec(context.Background(), req)
+	if err != nil {
+		t.Fatalf("prepare exec: %v", err)
+	}
+	defer cancel()
+	if !slices.Contains(cmd.Env, "GREETING=hi") {
+		t.Fatalf("expected caller env passed to the command, got %v", cmd.Env)
+	}
+}
@@ -0,0 +1,208 @@
+// Copyright (c) 2025 HYPR. PTE. LTD.
+//
+// Business Source License 1.1
+// See LICENSE file in the project root for details.
+
+package app
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+)
+
+const (
+	// maxFileTransferBytes caps a file uploaded to or downloaded from the
+	// guest, whatever volantd allows.
+	maxFileTransferBytes = 16 << 30
+
+	checksumHeader = "X-Volant-Checksum-Sha256"
+	fileModeHeader = "X-Volant-File-Mode"
+)
+
+// fileTransferResponse describes a file written by an upload.
+type fileTransferResponse struct {
+	Path   string `json:"path"`
+	Size   int64  `json:"size"`
+	SHA256 string `json:"sha256"`
+}
+
+// handlePutFile writes the request body to the guest path, replacing any
+// file there only once it is complete and matches the checksum the caller
+// sent, if any.
+func (a *App) handlePutFile(w http.ResponseWriter, r *http.Request) {
+	target, err := transferPath(chi.URLParam(r, "*"))
+	if err != nil {
+		errorJSON(w, http.StatusBadRequest, err)
+		return
+	}
+	if r.ContentLength > maxFileTransferBytes {
+		fileTooLarge(w)
+		return
+	}
+	want := strings.ToLower(strings.TrimSpace(r.Header.Get(checksumHeader)))
+	mode := os.FileMode(0o644)
+	if raw := strings.TrimSpace(r.Header.Get(fileModeHeader)); raw != "" {
+		parsed, err := strconv.ParseUint(raw, 8, 32)
+		if err != nil || parsed > 0o7777 {
+			errorJSON(w, http.StatusBadRequest, fmt.Errorf("invalid %s %q", fileModeHeader, raw))
+			return
+		}
+		mode = os.FileMode(parsed)
+	}
+	clearDeadlines(w)
+
+	if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+		errorJSON(w, http.StatusInternalServerError, fmt.Errorf("create parent of %s: %w", target, err))
+		return
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(target), ".volant-upload-*")
+	if err != nil {
+		errorJSON(w, http.StatusInternalServerError, fmt.Errorf("create temp for %s: %w", target, err))
+		return
+	}
+	tmpName := tmp.Name()
+	committed := false
+	defer func() {
+		if !committed {
+			_ = tmp.Close()
+			_ = os.Remove(tmpName)
+		}
+	}()
+
+	hash := sha256.New()
+	size, err := io.Copy(io.MultiWriter(tmp, hash), http.MaxBytesReader(w, r.Body, maxFileTransferBytes))
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			fileTooLarge(w)
+			return
+		}
+		errorJSON(w, http.StatusBadRequest, fmt.Errorf("read upload: %w", err))
+		return
+	}
+	sum := hex.EncodeToString(hash.Sum(nil))
+	if want != "" && want != sum {
+		respondJSON(w, http.StatusUnprocessableEntity, map[string]any{
+			"error": fmt.Sprintf("checksum mismatch: got sha256 %s, expected %s", sum, want),
+			"code":  "CHECKSUM_MISMATCH",
+		})
+		return
+	}
+	if err := tmp.Sync(); err != nil {
+		errorJSON(w, http.StatusInternalServerError, fmt.Errorf("sync %s: %w", target, err))
+		return
+	}
+	if err := tmp.Close(); err != nil {
+		errorJSON(w, http.StatusInternalServerError, fmt.Errorf("close %s: %w", target, err))
+		return
+	}
+	if err := os.Chmod(tmpName, mode.Perm()); err != nil {
+		errorJSON(w, http.StatusInternalServerError, fmt.Errorf("chmod %s: %w", target, err))
+		return
+	}
+	status := http.StatusCreated
+	if info, err := os.Stat(target); err == nil {
+		if info.IsDir() {
+			errorJSON(w, http.StatusConflict, fmt.Errorf("%s is a directory", target))
+			return
+		}
+		status = http.StatusOK
+	}
+	if err := os.Rename(tmpName, target); err != nil {
+		errorJSON(w, http.StatusInternalServerError, fmt.Errorf("rename %s: %w", target, err))
+		return
+	}
+	committed = true
+
+	a.log.Printf("file upload %s: %d bytes", target, size)
+	respondJSON(w, status, fileTransferResponse{Path: target, Size: size, SHA256: sum})
+}
+
+// handleGetFile serves a guest file with its SHA-256 in a header. Range
+// requests are honored; the checksum always covers the whole file.
+func (a *App) handleGetFile(w http.ResponseWriter, r *http.Request) {
+	target, err := transferPath(chi.URLParam(r, "*"))
+	if err != nil {
+		errorJSON(w, http.StatusBadRequest, err)
+		return
+	}
+	file, err := os.Open(target)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			errorJSON(w, http.StatusNotFound, fmt.Errorf("%s not found", target))
+			return
+		}
+		errorJSON(w, http.StatusInternalServerError, err)
+		return
+	}
+	defer file.Close()
+	info, err := file.Stat()
+	if err != nil {
+		errorJSON(w, http.StatusInternalServerError, err)
+		return
+	}
+	if !info.Mode().IsRegular() {
+		errorJSON(w, http.StatusBadRequest, fmt.Errorf("%s is not a regular file", target))
+		return
+	}
+	if info.Size() > maxFileTransferBytes {
+		fileTooLarge(w)
+		return
+	}
+	clearDeadlines(w)
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, file); err != nil {
+		errorJSON(w, http.StatusInternalServerError, fmt.Errorf("read %s: %w", target, err))
+		return
+	}
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		errorJSON(w, http.StatusInternalServerError, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/octet-stream")
+	w.Header().Set(checksumHeader, hex.EncodeToString(hash.Sum(nil)))
+	w.Header().Set(fileModeHeader, fmt.Sprintf("%04o", info.Mode().Perm()))
+	http.ServeContent(w, r, "", info.ModTime(), file)
+}
+
+// transferPath turns the wildcard of a files route, which chi leaves escaped
+// when the request path needed escaping, into an absolute guest path.
+func transferPath(raw string) (string, error) {
+	unescaped, err := url.PathUnescape(raw)
+	if err != nil {
+		return "", fmt.Errorf("invalid file path: %w", err)
+	}
+	target := filepath.Clean("/" + strings.TrimPrefix(unescaped, "/"))
+	if target == "/" {
+		return "", fmt.Errorf("file path required")
+	}
+	return target, nil
+}
+
+func fileTooLarge(w http.ResponseWriter) {
+	respondJSON(w, http.StatusRequestEntityTooLarge, map[string]any{
+		"error": fmt.Sprintf("file exceeds the agent limit of %d bytes", int64(maxFileTransferBytes)),
+		"code":  "FILE_TOO_LARGE",
+	})
+}
+
+// clearDeadlines lifts the server's read and write timeouts from a response
+// that takes as long as its transfer or action needs.
+func clearDeadlines(w http.ResponseWriter) {
+	rc := http.NewResponseController(w)
+	_ = rc.SetReadDeadline(time.Time{})
+	_ = rc.SetWriteDeadline(time.Time{})
+}
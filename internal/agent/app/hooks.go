@@ -0,0 +1,229 @@
+// Copyright (c) 2025 HYPR. PTE. LTD.
+//
+// Business Source License 1.1
+// See LICENSE file in the project root for details.
+
+package app
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/volantvm/volant/internal/pluginspec"
+)
+
+// maxHookOutput caps the output kept per hook so results fit in an event.
+const maxHookOutput = 4 << 10
+
+// traceparentHeader carries the W3C trace context of the host's request.
+// Hooks continue it: command hooks see it in TRACEPARENT, request hooks in
+// the header.
+const traceparentHeader = "traceparent"
+
+type traceparentKey struct{}
+
+// withTraceparent returns ctx carrying the trace context of r, if any.
+func withTraceparent(ctx context.Context, r *http.Request) context.Context {
+	if value := r.Header.Get(traceparentHeader); value != "" {
+		return context.WithValue(ctx, traceparentKey{}, value)
+	}
+	return ctx
+}
+
+func traceparentFrom(ctx context.Context) string {
+	value, _ := ctx.Value(traceparentKey{}).(string)
+	return value
+}
+
+type preStopRequest struct {
+	Hooks []pluginspec.Hook `json:"hooks,omitempty"`
+}
+
+type hookResponse struct {
+	Results []pluginspec.HookResult `json:"results"`
+}
+
+type postUpgradeRequest struct {
+	Hooks       []pluginspec.Hook `json:"hooks,omitempty"`
+	FromVersion string            `json:"from_version"`
+	ToVersion   string            `json:"to_version"`
+}
+
+// handlePreStop runs pre-stop hooks in order and reports each outcome. Hooks
+// in the request body take precedence over the ones in the agent manifest, so
+// the host can send the hooks of the VM's effective configuration.
+func (a *App) handlePreStop(w http.ResponseWriter, r *http.Request) {
+	var req preStopRequest
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+			errorJSON(w, http.StatusBadRequest, fmt.Errorf("decode hook request: %w", err))
+			return
+		}
+	}
+	hooks := req.Hooks
+	if len(hooks) == 0 {
+		a.mu.Lock()
+		if a.manifest != nil && a.manifest.Hooks != nil {
+			hooks = append(hooks, a.manifest.Hooks.PreStop...)
+		}
+		a.mu.Unlock()
+	}
+
+	resp := hookResponse{Results: make([]pluginspec.HookResult, 0, len(hooks))}
+	for _, hook := range hooks {
+		hook.Normalize()
+		result := a.runHook(withTraceparent(r.Context(), r), hook, nil)
+		a.log.Printf("pre-stop hook %s: %s (%dms)", result.Name, result.Status, result.DurationMs)
+		resp.Results = append(resp.Results, result)
+	}
+	respondJSON(w, http.StatusOK, resp)
+}
+
+// handlePostUpgrade runs post-upgrade hooks in order and reports each
+// outcome. Command hooks get the previous and new plugin versions in their
+// environment.
+func (a *App) handlePostUpgrade(w http.ResponseWriter, r *http.Request) {
+	var req postUpgradeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		errorJSON(w, http.StatusBadRequest, fmt.Errorf("decode hook request: %w", err))
+		return
+	}
+	hooks := req.Hooks
+	if len(hooks) == 0 {
+		a.mu.Lock()
+		if a.manifest != nil && a.manifest.Hooks != nil {
+			hooks = append(hooks, a.manifest.Hooks.PostUpgrade...)
+		}
+		a.mu.Unlock()
+	}
+	env := []string{
+		"VOLANT_UPGRADE_FROM=" + req.FromVersion,
+		"VOLANT_UPGRADE_TO=" + req.ToVersion,
+	}
+
+	resp := hookResponse{Results: make([]pluginspec.HookResult, 0, len(hooks))}
+	for _, hook := range hooks {
+		hook.Normalize()
+		result := a.runHook(withTraceparent(r.Context(), r), hook, env)
+		a.log.Printf("post-upgrade hook %s: %s (%dms)", result.Name, result.Status, result.DurationMs)
+		resp.Results = append(resp.Results, result)
+	}
+	respondJSON(w, http.StatusOK, resp)
+}
+
+// runHook runs hook; extraEnv is added to the environment of command hooks.
+func (a *App) runHook(parent context.Context, hook pluginspec.Hook, extraEnv []string) pluginspec.HookResult {
+	result := pluginspec.HookResult{Name: hook.Name}
+	if err := hook.Validate(); err != nil {
+		result.Status = pluginspec.HookStatusFailed
+		result.Error = err.Error()
+		return result
+	}
+
+	ctx, cancel := context.WithTimeout(parent, time.Duration(hook.Timeout())*time.Millisecond)
+	defer cancel()
+
+	started := time.Now()
+	var err error
+	if len(hook.Command) > 0 {
+		err = a.runHookCommand(ctx, hook, extraEnv, &result)
+	} else {
+		err = a.runHookRequest(ctx, hook, &result)
+	}
+	result.DurationMs = time.Since(started).Milliseconds()
+
+	switch {
+	case errors.Is(ctx.Err(), context.DeadlineExceeded):
+		result.Status = pluginspec.HookStatusTimeout
+		result.Error = fmt.Sprintf("timed out after %dms", hook.Timeout())
+	case err != nil:
+		result.Status = pluginspec.HookStatusFailed
+		result.Error = err.Error()
+	default:
+		result.Status = pluginspec.HookStatusOK
+	}
+	return result
+}
+
+func (a *App) runHookCommand(ctx context.Context, hook pluginspec.Hook, extraEnv []string, result *pluginspec.HookResult) error {
+	cmd := exec.CommandContext(ctx, hook.Command[0], hook.Command[1:]...)
+	env := ensurePath(os.Environ(), []string{"/usr/local/bin", "/usr/bin", "/bin"})
+	a.mu.Lock()
+	if a.manifest != nil {
+		for key, value := range a.manifest.Workload.Env {
+			env = append(env, fmt.Sprintf("%s=%s", key, value))
+		}
+		if dir := strings.TrimSpace(a.manifest.Workload.WorkDir); dir != "" {
+			cmd.Dir = dir
+		}
+	}
+	a.mu.Unlock()
+	cmd.Env = append(env, extraEnv...)
+	if traceparent := traceparentFrom(ctx); traceparent != "" {
+		cmd.Env = append(cmd.Env, "TRACEPARENT="+traceparent)
+	}
+
+	output, err := cmd.CombinedOutput()
+	result.Output = truncateHookOutput(output)
+	if cmd.ProcessState != nil {
+		code := cmd.ProcessState.ExitCode()
+		result.ExitCode = &code
+	}
+	return err
+}
+
+func (a *App) runHookRequest(ctx context.Context, hook pluginspec.Hook, result *pluginspec.HookResult) error {
+	a.mu.Lock()
+	var baseURL string
+	if a.manifest != nil {
+		baseURL = strings.TrimSpace(a.manifest.Workload.BaseURL)
+	}
+	a.mu.Unlock()
+	if baseURL == "" {
+		return errors.New("workload base_url required for request hooks")
+	}
+	base, err := url.Parse(baseURL)
+	if err != nil {
+		return fmt.Errorf("workload base_url invalid: %w", err)
+	}
+	target := resolveWorkloadURL(base, hook.Path, "")
+
+	req, err := http.NewRequestWithContext(ctx, hook.Method, target.String(), http.NoBody)
+	if err != nil {
+		return err
+	}
+	if traceparent := traceparentFrom(ctx); traceparent != "" {
+		req.Header.Set(traceparentHeader, traceparent)
+	}
+	resp, err := a.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, maxHookOutput+1))
+	result.HTTPStatus = resp.StatusCode
+	result.Output = truncateHookOutput(body)
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("workload returned status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+func truncateHookOutput(output []byte) string {
+	output = bytes.TrimSpace(output)
+	if len(output) > maxHookOutput {
+		output = output[len(output)-maxHookOutput:]
+	}
+	return string(output)
+}
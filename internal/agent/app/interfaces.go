@@ -0,0 +1,97 @@
+// Copyright (c) 2025 HYPR. PTE. LTD.
+//
+// Business Source License 1.1
+// See LICENSE file in the project root for details.
+
+//go:build linux
+// +build linux
+
+package app
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"strings"
+
+	"github.com/vishvananda/netlink"
+	"golang.org/x/sys/unix"
+
+	"github.com/volantvm/volant/internal/pluginspec"
+)
+
+// configureInterfaces brings up the additional NICs described by volant.if<N>
+// kernel arguments. Links are matched by MAC since guest interface names
+// depend on PCI enumeration order.
+func configureInterfaces(logger *log.Logger) error {
+	var errs []error
+	for index := 1; index <= pluginspec.MaxNetworkInterfaces; index++ {
+		raw := cmdlineValue(fmt.Sprintf("%s%d", pluginspec.InterfaceKeyPrefix, index))
+		if raw == "" {
+			continue
+		}
+		if err := configureInterface(logger, index, raw); err != nil {
+			errs = append(errs, fmt.Errorf("interface %d: %w", index, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func configureInterface(logger *log.Logger, index int, raw string) error {
+	parts := strings.Split(raw, ",")
+	mac, err := net.ParseMAC(parts[0])
+	if err != nil {
+		return fmt.Errorf("parse mac %q: %w", parts[0], err)
+	}
+	link, err := linkByMAC(mac)
+	if err != nil {
+		return err
+	}
+	name := link.Attrs().Name
+	if err := netlink.LinkSetUp(link); err != nil {
+		return fmt.Errorf("bring %s up: %w", name, err)
+	}
+	if len(parts) < 2 {
+		logger.Printf("brought up %s (%s) for dhcp", name, mac)
+		return nil
+	}
+
+	addr, err := netlink.ParseAddr(parts[1])
+	if err != nil {
+		return fmt.Errorf("parse address %q: %w", parts[1], err)
+	}
+	if err := netlink.AddrReplace(link, addr); err != nil {
+		return fmt.Errorf("assign %s: %w", addr.IPNet, err)
+	}
+	if len(parts) > 2 {
+		if gateway := net.ParseIP(parts[2]); gateway != nil {
+			// Keep eth0's default route preferred; secondary gateways only
+			// take over when the primary route is gone.
+			route := &netlink.Route{
+				LinkIndex: link.Attrs().Index,
+				Gw:        gateway,
+				Priority:  100 + index,
+			}
+			if err := netlink.RouteAdd(route); err != nil && !errors.Is(err, unix.EEXIST) {
+				return fmt.Errorf("add route via %s: %w", gateway, err)
+			}
+		}
+	}
+
+	logger.Printf("configured %s on %s", addr.IPNet, name)
+	return nil
+}
+
+func linkByMAC(mac net.HardwareAddr) (netlink.Link, error) {
+	links, err := netlink.LinkList()
+	if err != nil {
+		return nil, fmt.Errorf("list links: %w", err)
+	}
+	for _, link := range links {
+		if strings.EqualFold(link.Attrs().HardwareAddr.String(), mac.String()) {
+			return link, nil
+		}
+	}
+	return nil, fmt.Errorf("no link with mac %s", mac)
+}
@@ -0,0 +1,67 @@
+// Copyright (c) 2025 HYPR. PTE. LTD.
+//
+// Business Source License 1.1
+// See LICENSE file in the project root for details.
+
+//go:build linux
+// +build linux
+
+package app
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"os"
+
+	"github.com/vishvananda/netlink"
+	"golang.org/x/sys/unix"
+
+	"github.com/volantvm/volant/internal/pluginspec"
+)
+
+const guestInterface = "eth0"
+
+// configureIPv6 applies the IPv6 address and default route passed on the
+// kernel command line. Kernel ip= autoconfiguration only handles IPv4, so
+// dual-stack and IPv6-only guests rely on the agent for this step.
+func configureIPv6(logger *log.Logger) error {
+	rawAddr := cmdlineValue(pluginspec.IPv6AddressKey)
+	if rawAddr == "" {
+		return nil
+	}
+	addr, err := netlink.ParseAddr(rawAddr)
+	if err != nil {
+		return fmt.Errorf("parse %s: %w", pluginspec.IPv6AddressKey, err)
+	}
+	// The host hands out unique addresses; skip duplicate address detection
+	// so the address is usable immediately.
+	addr.Flags = unix.IFA_F_NODAD
+
+	link, err := netlink.LinkByName(guestInterface)
+	if err != nil {
+		return fmt.Errorf("lookup %s: %w", guestInterface, err)
+	}
+	_ = os.WriteFile("/proc/sys/net/ipv6/conf/"+guestInterface+"/disable_ipv6", []byte("0"), 0o644)
+	if err := netlink.LinkSetUp(link); err != nil {
+		return fmt.Errorf("bring %s up: %w", guestInterface, err)
+	}
+	if err := netlink.AddrReplace(link, addr); err != nil {
+		return fmt.Errorf("assign %s: %w", addr.IPNet, err)
+	}
+
+	if gateway := net.ParseIP(cmdlineValue(pluginspec.IPv6GatewayKey)); gateway != nil {
+		route := &netlink.Route{
+			LinkIndex: link.Attrs().Index,
+			Gw:        gateway,
+			Family:    netlink.FAMILY_V6,
+		}
+		if err := netlink.RouteAdd(route); err != nil && !errors.Is(err, unix.EEXIST) {
+			return fmt.Errorf("add ipv6 default route via %s: %w", gateway, err)
+		}
+	}
+
+	logger.Printf("configured %s on %s", addr.IPNet, guestInterface)
+	return nil
+}
@@ -0,0 +1,266 @@
+// Copyright (c) 2025 HYPR. PTE. LTD.
+//
+// Business Source License 1.1
+// See LICENSE file in the project root for details.
+
+package app
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/volantvm/volant/internal/pluginspec"
+)
+
+// userHZ is the unit of the CPU times in /proc/stat, fixed by the kernel ABI.
+const userHZ = 100
+
+// maxPluginStats caps the stats kept from a workload's metrics endpoint.
+const maxPluginStats = 256
+
+// cpuTimes holds the aggregate "cpu" line of /proc/stat, in ticks.
+type cpuTimes struct {
+	user, nice, system, idle, iowait, irq, softirq, steal uint64
+}
+
+func (t cpuTimes) total() uint64 {
+	return t.user + t.nice + t.system + t.idle + t.iowait + t.irq + t.softirq + t.steal
+}
+
+func (t cpuTimes) busy() uint64 {
+	return t.total() - t.idle - t.iowait
+}
+
+// handleMetrics samples the guest's CPU, memory, disk and process usage,
+// plus the workload's own stats when the manifest declares an endpoint.
+func (a *App) handleMetrics(w http.ResponseWriter, r *http.Request) {
+	metrics := pluginspec.GuestMetrics{
+		Timestamp: time.Now().UTC(),
+		Memory:    readMemory(),
+		Disks:     []pluginspec.GuestDisk{},
+		Processes: readProcesses(),
+	}
+	if fields := strings.Fields(readTrimmed("/proc/uptime")); len(fields) > 0 {
+		metrics.UptimeSeconds, _ = strconv.ParseFloat(fields[0], 64)
+	}
+	if fields := strings.Fields(readTrimmed("/proc/loadavg")); len(fields) >= 3 {
+		for i := range metrics.Load {
+			metrics.Load[i], _ = strconv.ParseFloat(fields[i], 64)
+		}
+	}
+	metrics.CPU = a.sampleCPU()
+	for _, disk := range readMounts() {
+		metrics.Disks = append(metrics.Disks, pluginspec.GuestDisk{
+			Device:     disk.Device,
+			MountPoint: disk.MountPoint,
+			FSType:     disk.FSType,
+			TotalBytes: disk.TotalBytes,
+			FreeBytes:  disk.FreeBytes,
+		})
+	}
+
+	a.mu.Lock()
+	var spec *pluginspec.Metrics
+	var baseURL string
+	if a.manifest != nil {
+		spec = a.manifest.Metrics
+		baseURL = a.manifest.Workload.BaseURL
+	}
+	a.mu.Unlock()
+	if spec != nil {
+		stats, err := a.fetchPluginStats(r.Context(), *spec, baseURL)
+		if err != nil {
+			metrics.PluginError = err.Error()
+		} else {
+			metrics.Plugin = stats
+		}
+	}
+	respondJSON(w, http.StatusOK, metrics)
+}
+
+// sampleCPU reads CPU times and works out the usage since the previous
+// sample; the first sample reports the usage since boot.
+func (a *App) sampleCPU() pluginspec.GuestCPU {
+	current, count := readCPUTimes()
+	a.cpuMu.Lock()
+	previous := a.cpuLast
+	a.cpuLast = current
+	a.cpuMu.Unlock()
+
+	cpu := pluginspec.GuestCPU{
+		Count:         count,
+		UserSeconds:   float64(current.user+current.nice) / userHZ,
+		SystemSeconds: float64(current.system+current.irq+current.softirq) / userHZ,
+		IdleSeconds:   float64(current.idle) / userHZ,
+		IOWaitSeconds: float64(current.iowait) / userHZ,
+		StealSeconds:  float64(current.steal) / userHZ,
+	}
+	if current.total() < previous.total() || current.busy() < previous.busy() {
+		previous = cpuTimes{}
+	}
+	if elapsed := current.total() - previous.total(); elapsed > 0 {
+		cpu.Percent = float64(current.busy()-previous.busy()) / float64(elapsed) * 100
+	}
+	return cpu
+}
+
+// readCPUTimes returns the aggregate CPU times and the number of CPUs.
+func readCPUTimes() (cpuTimes, int) {
+	var times cpuTimes
+	count := 0
+	f, err := os.Open("/proc/stat")
+	if err != nil {
+		return times, count
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 || !strings.HasPrefix(fields[0], "cpu") {
+			continue
+		}
+		if fields[0] != "cpu" {
+			count++
+			continue
+		}
+		values := make([]uint64, 8)
+		for i := range values {
+			if i+1 < len(fields) {
+				values[i], _ = strconv.ParseUint(fields[i+1], 10, 64)
+			}
+		}
+		times = cpuTimes{values[0], values[1], values[2], values[3], values[4], values[5], values[6], values[7]}
+	}
+	return times, count
+}
+
+// readMemory reads /proc/meminfo. Used memory excludes what the kernel can
+// reclaim, matching MemAvailable.
+func readMemory() pluginspec.GuestMemory {
+	var mem pluginspec.GuestMemory
+	f, err := os.Open("/proc/meminfo")
+	if err != nil {
+		return mem
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 2 {
+			continue
+		}
+		kb, err := strconv.ParseUint(fields[1], 10, 64)
+		if err != nil {
+			continue
+		}
+		switch strings.TrimSuffix(fields[0], ":") {
+		case "MemTotal":
+			mem.TotalBytes = kb << 10
+		case "MemAvailable":
+			mem.AvailableBytes = kb << 10
+		case "Cached":
+			mem.CachedBytes = kb << 10
+		case "SwapTotal":
+			mem.SwapTotalBytes = kb << 10
+		case "SwapFree":
+			mem.SwapFreeBytes = kb << 10
+		}
+	}
+	if mem.TotalBytes > mem.AvailableBytes {
+		mem.UsedBytes = mem.TotalBytes - mem.AvailableBytes
+	}
+	return mem
+}
+
+// readProcesses counts the processes in /proc and those runnable.
+func readProcesses() pluginspec.GuestProcesses {
+	var procs pluginspec.GuestProcesses
+	if entries, err := os.ReadDir("/proc"); err == nil {
+		for _, entry := range entries {
+			if _, err := strconv.Atoi(entry.Name()); err == nil && entry.IsDir() {
+				procs.Total++
+			}
+		}
+	}
+	f, err := os.Open("/proc/stat")
+	if err != nil {
+		return procs
+	}
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if value, ok := strings.CutPrefix(scanner.Text(), "procs_running "); ok {
+			procs.Running, _ = strconv.Atoi(strings.TrimSpace(value))
+			break
+		}
+	}
+	return procs
+}
+
+// fetchPluginStats asks the workload's metrics endpoint for its stats and
+// keeps the numeric fields of the JSON object it answers with.
+func (a *App) fetchPluginStats(ctx context.Context, spec pluginspec.Metrics, baseURL string) (map[string]float64, error) {
+	baseURL = strings.TrimSpace(baseURL)
+	if baseURL == "" {
+		return nil, errors.New("workload base_url required for plugin metrics")
+	}
+	base, err := url.Parse(baseURL)
+	if err != nil {
+		return nil, fmt.Errorf("workload base_url invalid: %w", err)
+	}
+	ctx, cancel := context.WithTimeout(ctx, spec.Timeout())
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, resolveWorkloadURL(base, spec.Endpoint, "").String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	resp, err := a.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("workload returned status %d", resp.StatusCode)
+	}
+
+	var raw map[string]any
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(&raw); err != nil {
+		return nil, fmt.Errorf("decode plugin stats: %w", err)
+	}
+	keys := make([]string, 0, len(raw))
+	for key := range raw {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	stats := make(map[string]float64, len(raw))
+	for _, key := range keys {
+		if len(stats) == maxPluginStats {
+			break
+		}
+		switch v := raw[key].(type) {
+		case float64:
+			stats[key] = v
+		case bool:
+			if v {
+				stats[key] = 1
+			} else {
+				stats[key] = 0
+			}
+		}
+	}
+	return stats, nil
+}
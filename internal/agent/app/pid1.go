@@ -29,6 +29,8 @@ import (
 
 const (
 	rootMountPoint   = "/mnt/volant-root"
+	lowerMountPoint  = "/mnt/volant-lower"
+	upperMountPoint  = "/mnt/volant-upper"
 	oldRootPivotName = ".pivot-old"
 )
 
@@ -45,6 +47,15 @@ func (a *App) bootstrapPID1() error {
 		return a.enterStage2(false)
 	}
 
+	// An agent update replaced the previous agent in place; the guest is
+	// already set up, so only take over reaping and signals.
+	if len(os.Args) > 1 && os.Args[1] == agentHandoffArg {
+		a.log.Printf("PID 1: took over from the previous agent as %s", agentVersion)
+		go reapZombies()
+		go handleSignals(a)
+		return nil
+	}
+
 	// If we're here, it means it's Stage 1. Run the full pivot logic.
 	// We keep the sync.Once just in case, but the logic above prevents re-entry.
 	var bootstrapErr error
@@ -131,6 +142,22 @@ func (a *App) enterStage2(fromInitramfs bool) error {
 		select {}
 	}
 
+	if err := configureIPv6(a.log); err != nil {
+		a.log.Printf("warning: ipv6 setup failed: %v", err)
+	}
+
+	if err := configureInterfaces(a.log); err != nil {
+		a.log.Printf("warning: interface setup failed: %v", err)
+	}
+
+	if err := mountShares(a.log); err != nil {
+		a.log.Printf("warning: share mount failed: %v", err)
+	}
+
+	if err := mountScratchDisk(a.log); err != nil {
+		a.log.Printf("warning: scratch disk mount failed: %v", err)
+	}
+
 	if err := ensureConsoleTTY(a.log); err != nil {
 		a.log.Printf("warning: console setup failed: %v", err)
 	}
@@ -245,12 +272,57 @@ func mountRootfs(device, fsType string) error {
 	if err := os.MkdirAll(rootMountPoint, 0o755); err != nil {
 		return err
 	}
+	if overlay := strings.TrimSpace(cmdlineValue(pluginspec.RootFSOverlayKey)); overlay != "" {
+		return mountOverlayRoot(device, fsType, overlay)
+	}
 	if err := unix.Mount(device, rootMountPoint, fsType, unix.MS_RELATIME, ""); err != nil {
 		return fmt.Errorf("mount rootfs %s on %s: %w", device, rootMountPoint, err)
 	}
 	return nil
 }
 
+// mountOverlayRoot mounts the root image read-only and stacks a writable
+// overlay on top, so the image itself is never modified. The upper layer is
+// a tmpfs or an ext4 block device prepared by the host.
+func mountOverlayRoot(device, fsType, overlay string) error {
+	for _, dir := range []string{lowerMountPoint, upperMountPoint} {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return err
+		}
+	}
+	if err := unix.Mount(device, lowerMountPoint, fsType, unix.MS_RDONLY, ""); err != nil {
+		return fmt.Errorf("mount rootfs %s read-only on %s: %w", device, lowerMountPoint, err)
+	}
+	if overlay == pluginspec.RootFSOverlayTmpfs {
+		if err := unix.Mount("tmpfs", upperMountPoint, "tmpfs", 0, "mode=0755"); err != nil {
+			return fmt.Errorf("mount overlay tmpfs: %w", err)
+		}
+	} else {
+		upperDevice := overlay
+		if !strings.HasPrefix(upperDevice, "/dev/") {
+			upperDevice = "/dev/" + upperDevice
+		}
+		if err := waitForDevice(upperDevice, 10*time.Second); err != nil {
+			return err
+		}
+		if err := unix.Mount(upperDevice, upperMountPoint, "ext4", unix.MS_RELATIME, ""); err != nil {
+			return fmt.Errorf("mount overlay device %s: %w", upperDevice, err)
+		}
+	}
+	upper := filepath.Join(upperMountPoint, "upper")
+	work := filepath.Join(upperMountPoint, "work")
+	for _, dir := range []string{upper, work} {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return err
+		}
+	}
+	opts := fmt.Sprintf("lowerdir=%s,upperdir=%s,workdir=%s", lowerMountPoint, upper, work)
+	if err := unix.Mount("overlay", rootMountPoint, "overlay", 0, opts); err != nil {
+		return fmt.Errorf("mount overlay root on %s: %w", rootMountPoint, err)
+	}
+	return nil
+}
+
 func copySelfToRoot() error {
 	self, err := os.Executable()
 	if err != nil {
@@ -406,3 +478,12 @@ func handleSignals(a *App) {
 	a.log.Printf("received signal %s, powering off", sig)
 	_ = syscall.Reboot(syscall.LINUX_REBOOT_CMD_POWER_OFF)
 }
+
+// diskUsage reports the size and free space of the filesystem mounted at path.
+func diskUsage(path string) (total, free uint64, ok bool) {
+	var st unix.Statfs_t
+	if err := unix.Statfs(path, &st); err != nil {
+		return 0, 0, false
+	}
+	return st.Blocks * uint64(st.Bsize), st.Bavail * uint64(st.Bsize), true
+}
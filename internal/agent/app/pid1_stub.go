@@ -7,6 +7,39 @@
 
 package app
 
+import (
+	"errors"
+	"os"
+	"syscall"
+
+	"github.com/volantvm/volant/internal/pluginspec"
+)
+
+// errNoProcessGroups reports that process groups are unavailable off Linux.
+var errNoProcessGroups = errors.New("process groups not supported on this platform")
+
 // bootstrapPID1 is a no-op on non-Linux platforms to allow local builds
 // on macOS/Windows. The real implementation lives in pid1.go with linux tag.
 func (a *App) bootstrapPID1() error { return nil }
+
+// updateAgent is a no-op off Linux; agent updates target guest VMs.
+func (a *App) updateAgent(*pluginspec.Manifest) {}
+
+// applyBundles is a no-op off Linux; config bundles target guest VMs.
+func (a *App) applyBundles() {}
+
+// applySecrets is a no-op off Linux; secret mounts target guest VMs.
+func (a *App) applySecrets() {}
+
+// diskUsage is unavailable off Linux; sysinfo omits filesystem sizes.
+func diskUsage(string) (uint64, uint64, bool) { return 0, 0, false }
+
+// shellProcAttr returns nil off Linux; the debug shell needs a Linux tty.
+func shellProcAttr(*os.File) *syscall.SysProcAttr { return nil }
+
+// workloadProcAttr returns nil off Linux; workloads run in guest VMs.
+func workloadProcAttr() *syscall.SysProcAttr { return nil }
+
+func processGroup(int) (int, error) { return 0, errNoProcessGroups }
+
+func signalGroup(int, syscall.Signal) error { return errNoProcessGroups }
@@ -0,0 +1,38 @@
+// Copyright (c) 2025 HYPR. PTE. LTD.
+//
+// Business Source License 1.1
+// See LICENSE file in the project root for details.
+
+//go:build linux
+// +build linux
+
+package app
+
+import (
+	"os"
+	"syscall"
+)
+
+// shellProcAttr starts the debug shell as a session leader with tty as its
+// controlling terminal.
+func shellProcAttr(tty *os.File) *syscall.SysProcAttr {
+	return &syscall.SysProcAttr{
+		Setsid:  true,
+		Setctty: true,
+		Ctty:    int(tty.Fd()),
+	}
+}
+
+// workloadProcAttr puts the workload in its own process group so stopping it
+// also reaches the children it forks.
+func workloadProcAttr() *syscall.SysProcAttr {
+	return &syscall.SysProcAttr{Setpgid: true}
+}
+
+func processGroup(pid int) (int, error) {
+	return syscall.Getpgid(pid)
+}
+
+func signalGroup(pgid int, sig syscall.Signal) error {
+	return syscall.Kill(-pgid, sig)
+}
@@ -0,0 +1,47 @@
+// Copyright (c) 2025 HYPR. PTE. LTD.
+//
+// Business Source License 1.1
+// See LICENSE file in the project root for details.
+
+//go:build linux
+// +build linux
+
+package app
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"golang.org/x/sys/unix"
+
+	"github.com/volantvm/volant/internal/pluginspec"
+)
+
+// mountScratchDisk mounts the scratch disk named by the volant.scratch kernel
+// argument.
+func mountScratchDisk(logger *log.Logger) error {
+	raw := strings.TrimSpace(cmdlineValue(pluginspec.ScratchDiskKey))
+	if raw == "" {
+		return nil
+	}
+	device, target, ok := strings.Cut(raw, ",")
+	if !ok || device == "" || target == "" {
+		return fmt.Errorf("scratch disk %q: expected device,mount_path", raw)
+	}
+	devicePath := "/dev/" + device
+	if err := waitForDevice(devicePath, 5*time.Second); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(target, 0o755); err != nil {
+		return fmt.Errorf("create %s: %w", target, err)
+	}
+	if err := unix.Mount(devicePath, target, "ext4", unix.MS_RELATIME, ""); err != nil && !errors.Is(err, unix.EBUSY) {
+		return fmt.Errorf("mount %s on %s: %w", devicePath, target, err)
+	}
+	logger.Printf("mounted scratch disk %s on %s", devicePath, target)
+	return nil
+}
@@ -0,0 +1,107 @@
+// Copyright (c) 2025 HYPR. PTE. LTD.
+//
+// Business Source License 1.1
+// See LICENSE file in the project root for details.
+
+//go:build linux
+// +build linux
+
+package app
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/volantvm/volant/internal/pluginspec"
+)
+
+// applySecrets fetches the secrets mounted into this VM and writes each to
+// its guest path before the workload starts. Secrets naming an env variable
+// are also exported to the workload environment.
+func (a *App) applySecrets() {
+	vmName := cmdlineValue(pluginspec.VMNameKey)
+	if vmName == "" || cmdlineValue(pluginspec.SecretMountsKey) == "" {
+		return
+	}
+	apiHost := cmdlineValue(pluginspec.APIHostKey)
+	apiPort := cmdlineValue(pluginspec.APIPortKey)
+	if apiHost == "" || apiPort == "" {
+		a.log.Printf("secrets: api endpoint missing from cmdline")
+		return
+	}
+
+	secretsURL := fmt.Sprintf("http://%s:%s/api/v1/vms/%s/secrets", apiHost, apiPort, url.PathEscape(vmName))
+	var (
+		mounted []pluginspec.MountedSecret
+		err     error
+	)
+	for attempt := 1; attempt <= bundleFetchAttempts; attempt++ {
+		mounted, err = fetchSecrets(a.client, secretsURL)
+		if err == nil {
+			break
+		}
+		a.log.Printf("secrets: fetch attempt %d failed: %v", attempt, err)
+		time.Sleep(bundleFetchBackoff)
+	}
+	if err != nil {
+		a.log.Printf("secrets: giving up; workload starts without them")
+		return
+	}
+
+	var env []string
+	for _, secret := range mounted {
+		if err := writeSecret(secret); err != nil {
+			a.log.Printf("secret %s: %v", secret.Name, err)
+			continue
+		}
+		if secret.Env != "" {
+			env = append(env, secret.Env+"="+secret.Value)
+		}
+		a.log.Printf("secret %s mounted at %s", secret.Name, secret.Target())
+	}
+
+	a.mu.Lock()
+	a.secretEnv = env
+	a.mu.Unlock()
+}
+
+func fetchSecrets(client *http.Client, secretsURL string) ([]pluginspec.MountedSecret, error) {
+	resp, err := client.Get(secretsURL)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	var mounted []pluginspec.MountedSecret
+	if err := json.NewDecoder(resp.Body).Decode(&mounted); err != nil {
+		return nil, fmt.Errorf("decode secrets: %w", err)
+	}
+	return mounted, nil
+}
+
+func writeSecret(secret pluginspec.MountedSecret) error {
+	secret.Normalize()
+	if err := secret.Validate(); err != nil {
+		return err
+	}
+	mode, err := secret.FileMode()
+	if err != nil {
+		return err
+	}
+	target := secret.Target()
+	dirMode := os.FileMode(0o755)
+	if filepath.Dir(target) == pluginspec.SecretDir {
+		dirMode = 0o700
+	}
+	if err := os.MkdirAll(filepath.Dir(target), dirMode); err != nil {
+		return fmt.Errorf("create %s: %w", filepath.Dir(target), err)
+	}
+	return writeBundleFile(target, []byte(secret.Value), os.FileMode(mode))
+}
@@ -0,0 +1,51 @@
+// Copyright (c) 2025 HYPR. PTE. LTD.
+//
+// Business Source License 1.1
+// See LICENSE file in the project root for details.
+
+//go:build linux
+// +build linux
+
+package app
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"os"
+
+	"golang.org/x/sys/unix"
+
+	"github.com/volantvm/volant/internal/pluginspec"
+)
+
+// mountShares mounts the virtio-fs shares described by volant.share<N>
+// kernel arguments at their mount points.
+func mountShares(logger *log.Logger) error {
+	var errs []error
+	for index := 1; index <= pluginspec.MaxShares; index++ {
+		raw := cmdlineValue(fmt.Sprintf("%s%d", pluginspec.ShareKeyPrefix, index))
+		if raw == "" {
+			continue
+		}
+		tag, target, readOnly, err := pluginspec.ParseShareArg(raw)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if err := os.MkdirAll(target, 0o755); err != nil {
+			errs = append(errs, fmt.Errorf("share %s: create %s: %w", tag, target, err))
+			continue
+		}
+		var flags uintptr
+		if readOnly {
+			flags |= unix.MS_RDONLY
+		}
+		if err := unix.Mount(tag, target, "virtiofs", flags, ""); err != nil && !errors.Is(err, unix.EBUSY) {
+			errs = append(errs, fmt.Errorf("share %s: mount on %s: %w", tag, target, err))
+			continue
+		}
+		logger.Printf("mounted share %s on %s", tag, target)
+	}
+	return errors.Join(errs...)
+}
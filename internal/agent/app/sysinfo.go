@@ -0,0 +1,203 @@
+// Copyright (c) 2025 HYPR. PTE. LTD.
+//
+// Business Source License 1.1
+// See LICENSE file in the project root for details.
+
+package app
+
+import (
+	"bufio"
+	"net"
+	"net/http"
+	"os"
+	goruntime "runtime"
+	"runtime/debug"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// agentVersion is reported by /healthz and /v1/sysinfo.
+const agentVersion = "v2.0"
+
+// sysInfo describes the booted guest so operators can check what actually
+// came up without attaching to the console.
+type sysInfo struct {
+	Hostname   string         `json:"hostname,omitempty"`
+	Kernel     sysKernel      `json:"kernel"`
+	Uptime     string         `json:"uptime,omitempty"`
+	Disks      []sysDisk      `json:"disks"`
+	Interfaces []sysInterface `json:"interfaces"`
+	Modules    []sysModule    `json:"modules"`
+	Agent      sysAgent       `json:"agent"`
+}
+
+type sysKernel struct {
+	Release string `json:"release,omitempty"`
+	Version string `json:"version,omitempty"`
+	Cmdline string `json:"cmdline,omitempty"`
+}
+
+type sysDisk struct {
+	Device     string `json:"device"`
+	MountPoint string `json:"mount_point"`
+	FSType     string `json:"fs_type"`
+	Options    string `json:"options,omitempty"`
+	TotalBytes uint64 `json:"total_bytes,omitempty"`
+	FreeBytes  uint64 `json:"free_bytes,omitempty"`
+}
+
+type sysInterface struct {
+	Name      string   `json:"name"`
+	MAC       string   `json:"mac,omitempty"`
+	MTU       int      `json:"mtu"`
+	Up        bool     `json:"up"`
+	Addresses []string `json:"addresses,omitempty"`
+}
+
+type sysModule struct {
+	Name      string `json:"name"`
+	SizeBytes int    `json:"size_bytes"`
+	State     string `json:"state,omitempty"`
+}
+
+type sysAgent struct {
+	Version   string    `json:"version"`
+	GoVersion string    `json:"go_version"`
+	Module    string    `json:"module,omitempty"`
+	Revision  string    `json:"revision,omitempty"`
+	BuildTime string    `json:"build_time,omitempty"`
+	Modified  bool      `json:"modified,omitempty"`
+	StartedAt time.Time `json:"started_at"`
+}
+
+func (a *App) handleSysInfo(w http.ResponseWriter, r *http.Request) {
+	info := sysInfo{
+		Kernel: sysKernel{
+			Release: readTrimmed("/proc/sys/kernel/osrelease"),
+			Version: readTrimmed("/proc/version"),
+			Cmdline: readTrimmed("/proc/cmdline"),
+		},
+		Disks:      readMounts(),
+		Interfaces: readInterfaces(),
+		Modules:    readModules(),
+		Agent:      readAgentBuild(a.started),
+	}
+	if hostname, err := os.Hostname(); err == nil {
+		info.Hostname = hostname
+	}
+	if fields := strings.Fields(readTrimmed("/proc/uptime")); len(fields) > 0 {
+		if secs, err := strconv.ParseFloat(fields[0], 64); err == nil {
+			info.Uptime = (time.Duration(secs) * time.Second).String()
+		}
+	}
+	respondJSON(w, http.StatusOK, info)
+}
+
+func readTrimmed(path string) string {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(data))
+}
+
+// readMounts lists block-device and shared-folder mounts; pseudo filesystems
+// such as proc and sysfs are omitted.
+func readMounts() []sysDisk {
+	disks := []sysDisk{}
+	f, err := os.Open("/proc/mounts")
+	if err != nil {
+		return disks
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 4 {
+			continue
+		}
+		device, mountPoint, fsType := fields[0], fields[1], fields[2]
+		if !strings.HasPrefix(device, "/dev/") && fsType != "virtiofs" && fsType != "9p" {
+			continue
+		}
+		disk := sysDisk{Device: device, MountPoint: mountPoint, FSType: fsType, Options: fields[3]}
+		if total, free, ok := diskUsage(mountPoint); ok {
+			disk.TotalBytes = total
+			disk.FreeBytes = free
+		}
+		disks = append(disks, disk)
+	}
+	return disks
+}
+
+func readInterfaces() []sysInterface {
+	out := []sysInterface{}
+	ifaces, err := net.Interfaces()
+	if err != nil {
+		return out
+	}
+	for _, iface := range ifaces {
+		entry := sysInterface{
+			Name: iface.Name,
+			MAC:  iface.HardwareAddr.String(),
+			MTU:  iface.MTU,
+			Up:   iface.Flags&net.FlagUp != 0,
+		}
+		if addrs, err := iface.Addrs(); err == nil {
+			for _, addr := range addrs {
+				entry.Addresses = append(entry.Addresses, addr.String())
+			}
+		}
+		out = append(out, entry)
+	}
+	return out
+}
+
+func readModules() []sysModule {
+	modules := []sysModule{}
+	f, err := os.Open("/proc/modules")
+	if err != nil {
+		return modules
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 2 {
+			continue
+		}
+		module := sysModule{Name: fields[0]}
+		module.SizeBytes, _ = strconv.Atoi(fields[1])
+		if len(fields) >= 5 {
+			module.State = fields[4]
+		}
+		modules = append(modules, module)
+	}
+	return modules
+}
+
+func readAgentBuild(started time.Time) sysAgent {
+	agent := sysAgent{Version: agentVersion, GoVersion: goruntime.Version(), StartedAt: started}
+	build, ok := debug.ReadBuildInfo()
+	if !ok {
+		return agent
+	}
+	agent.Module = build.Main.Path
+	if build.Main.Version != "" && build.Main.Version != "(devel)" {
+		agent.Version = agentVersion + " (" + build.Main.Version + ")"
+	}
+	for _, setting := range build.Settings {
+		switch setting.Key {
+		case "vcs.revision":
+			agent.Revision = setting.Value
+		case "vcs.time":
+			agent.BuildTime = setting.Value
+		case "vcs.modified":
+			agent.Modified = setting.Value == "true"
+		}
+	}
+	return agent
+}
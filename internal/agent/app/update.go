@@ -0,0 +1,193 @@
+// Copyright (c) 2025 HYPR. PTE. LTD.
+//
+// Business Source License 1.1
+// See LICENSE file in the project root for details.
+
+//go:build linux
+
+package app
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/volantvm/volant/internal/pluginspec"
+)
+
+const (
+	// agentHandoffArg tells a freshly exec'd agent that the previous one
+	// already bootstrapped the guest.
+	agentHandoffArg = "handoff"
+	// agentHandoffEnv carries the version handed over to, so an agent
+	// reporting a different version than pinned cannot loop.
+	agentHandoffEnv = "VOLANT_AGENT_HANDOFF"
+	agentBinaryDir  = "/usr/local/bin"
+	// maxAgentBinary caps agent downloads.
+	maxAgentBinary = 256 << 20
+)
+
+// updateAgent replaces the running agent with the version the manifest
+// pins when it is newer. The new binary is fetched from volantd once, kept
+// beside the current one as kestrel-<version> and checked against the
+// manifest's checksum for this architecture, or the one volantd serves.
+// On success it does not return; on failure the current agent carries on.
+func (a *App) updateAgent(manifest *pluginspec.Manifest) {
+	if manifest == nil || manifest.Agent == nil {
+		return
+	}
+	want := manifest.Agent.Version
+	if !newerAgentVersion(want, agentVersion) {
+		return
+	}
+	if os.Getenv(agentHandoffEnv) == want {
+		a.log.Printf("agent update: already handed off to %s, which reports %s; not updating again", want, agentVersion)
+		return
+	}
+	pinned := manifest.Agent.Checksums[runtime.GOARCH]
+
+	path := filepath.Join(agentBinaryDir, "kestrel-"+want)
+	if pinned == "" || fileSHA256(path) != pinned {
+		if err := downloadAgent(path, want, pinned); err != nil {
+			a.log.Printf("agent update to %s failed, staying on %s: %v", want, agentVersion, err)
+			return
+		}
+	}
+
+	a.log.Printf("agent update: handing off from %s to %s", agentVersion, want)
+	a.stopShell()
+	env := append(os.Environ(), agentHandoffEnv+"="+want)
+	err := syscall.Exec(path, []string{path, agentHandoffArg}, env)
+	a.log.Printf("agent update: exec %s failed, staying on %s: %v", path, agentVersion, err)
+	if startErr := a.startShell(); startErr != nil {
+		a.log.Printf("debug shell start failed: %v", startErr)
+	}
+}
+
+// downloadAgent fetches agent version for this architecture from volantd
+// into path, verifying it against pinned or, without a pin, the checksum
+// volantd sends.
+func downloadAgent(path, version, pinned string) error {
+	apiHost, apiPort := cmdlineValue(pluginspec.APIHostKey), cmdlineValue(pluginspec.APIPortKey)
+	if apiHost == "" || apiPort == "" {
+		return errors.New("volantd address missing from kernel command line")
+	}
+	url := fmt.Sprintf("http://%s:%s/api/v1/agent/artifacts/%s/%s", apiHost, apiPort, version, runtime.GOARCH)
+	client := &http.Client{Timeout: 2 * time.Minute}
+	resp, err := client.Get(url)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("fetch %s: unexpected status %d", url, resp.StatusCode)
+	}
+	want := pinned
+	if want == "" {
+		want = strings.ToLower(strings.TrimPrefix(strings.TrimSpace(resp.Header.Get("X-Volant-Checksum-Sha256")), "sha256:"))
+	}
+	if want == "" {
+		return errors.New("no checksum pinned or served; refusing unverified binary")
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".kestrel-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+	hash := sha256.New()
+	n, err := io.Copy(io.MultiWriter(tmp, hash), io.LimitReader(resp.Body, maxAgentBinary+1))
+	if err != nil {
+		return fmt.Errorf("download: %w", err)
+	}
+	if n > maxAgentBinary {
+		return fmt.Errorf("binary exceeds %d bytes", maxAgentBinary)
+	}
+	if got := hex.EncodeToString(hash.Sum(nil)); got != want {
+		return fmt.Errorf("checksum mismatch: got sha256 %s, expected %s", got, want)
+	}
+	if err := tmp.Chmod(0o755); err != nil {
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return err
+	}
+	syscall.Sync()
+	return nil
+}
+
+// fileSHA256 returns the hex SHA-256 of the file at path, or "" when it
+// cannot be read.
+func fileSHA256(path string) string {
+	f, err := os.Open(path)
+	if err != nil {
+		return ""
+	}
+	defer f.Close()
+	hash := sha256.New()
+	if _, err := io.Copy(hash, f); err != nil {
+		return ""
+	}
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// newerAgentVersion reports whether version want is newer than have.
+// Versions compare by dot-separated parts, ignoring a leading "v"; parts
+// compare numerically when both are numbers. A pre-release ("2.1-rc1") is
+// older than its release.
+func newerAgentVersion(want, have string) bool {
+	wantCore, wantPre, _ := strings.Cut(strings.TrimPrefix(want, "v"), "-")
+	haveCore, havePre, _ := strings.Cut(strings.TrimPrefix(have, "v"), "-")
+	wantParts, haveParts := strings.Split(wantCore, "."), strings.Split(haveCore, ".")
+	for i := 0; i < max(len(wantParts), len(haveParts)); i++ {
+		w, h := "0", "0"
+		if i < len(wantParts) {
+			w = wantParts[i]
+		}
+		if i < len(haveParts) {
+			h = haveParts[i]
+		}
+		if c := compareVersionPart(w, h); c != 0 {
+			return c > 0
+		}
+	}
+	switch {
+	case wantPre == havePre:
+		return false
+	case wantPre == "":
+		return true
+	case havePre == "":
+		return false
+	default:
+		return compareVersionPart(wantPre, havePre) > 0
+	}
+}
+
+func compareVersionPart(a, b string) int {
+	an, aErr := strconv.ParseUint(a, 10, 64)
+	bn, bErr := strconv.ParseUint(b, 10, 64)
+	if aErr == nil && bErr == nil {
+		switch {
+		case an > bn:
+			return 1
+		case an < bn:
+			return -1
+		}
+		return 0
+	}
+	return strings.Compare(a, b)
+}
@@ -0,0 +1,231 @@
+// Copyright (c) 2025 HYPR. PTE. LTD.
+//
+// Business Source License 1.1
+// See LICENSE file in the project root for details.
+
+// Package cliconfig stores volar's named contexts. Each context points at one
+// volantd with the API key and TLS settings needed to reach it.
+package cliconfig
+
+import (
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// EnvPath names the environment variable that overrides the config path.
+const EnvPath = "VOLAR_CONFIG"
+
+// Config is the contents of the config file.
+type Config struct {
+	CurrentContext string    `json:"current_context,omitempty" yaml:"current_context,omitempty"`
+	Contexts       []Context `json:"contexts" yaml:"contexts"`
+}
+
+// Context is one named volantd.
+type Context struct {
+	Name string `json:"name" yaml:"name"`
+	// API is the volantd base URL: http, https or unix.
+	API string `json:"api" yaml:"api"`
+	// APIKey is sent as X-Volant-API-Key. APIKeyEnv names an environment
+	// variable to read the key from instead, keeping it out of the file.
+	APIKey    string `json:"api_key,omitempty" yaml:"api_key,omitempty"`
+	APIKeyEnv string `json:"api_key_env,omitempty" yaml:"api_key_env,omitempty"`
+	TLS       TLS    `json:"tls,omitempty" yaml:"tls,omitempty"`
+}
+
+// TLS holds the client TLS settings of a context.
+type TLS struct {
+	CAFile             string `json:"ca_file,omitempty" yaml:"ca_file,omitempty"`
+	CertFile           string `json:"cert_file,omitempty" yaml:"cert_file,omitempty"`
+	KeyFile            string `json:"key_file,omitempty" yaml:"key_file,omitempty"`
+	ServerName         string `json:"server_name,omitempty" yaml:"server_name,omitempty"`
+	InsecureSkipVerify bool   `json:"insecure_skip_verify,omitempty" yaml:"insecure_skip_verify,omitempty"`
+}
+
+// DefaultPath returns $VOLAR_CONFIG, or ~/.volant/config.yaml.
+func DefaultPath() (string, error) {
+	if path := strings.TrimSpace(os.Getenv(EnvPath)); path != "" {
+		return path, nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("cliconfig: locate home directory: %w", err)
+	}
+	return filepath.Join(home, ".volant", "config.yaml"), nil
+}
+
+// Load reads the config at path. A missing file is an empty config.
+func Load(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return &Config{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("cliconfig: %w", err)
+	}
+	var cfg Config
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&cfg); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("cliconfig: parse %s: %w", path, err)
+	}
+	seen := make(map[string]bool, len(cfg.Contexts))
+	for _, ctx := range cfg.Contexts {
+		if err := ctx.Validate(); err != nil {
+			return nil, fmt.Errorf("cliconfig: %s: %w", path, err)
+		}
+		if seen[ctx.Name] {
+			return nil, fmt.Errorf("cliconfig: %s: context %q is defined twice", path, ctx.Name)
+		}
+		seen[ctx.Name] = true
+	}
+	return &cfg, nil
+}
+
+// Save writes the config to path, readable only by its owner since contexts
+// may hold API keys.
+func (c *Config) Save(path string) error {
+	data, err := yaml.Marshal(c)
+	if err != nil {
+		return fmt.Errorf("cliconfig: encode: %w", err)
+	}
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return fmt.Errorf("cliconfig: %w", err)
+	}
+	tmp, err := os.CreateTemp(dir, ".config-*.yaml")
+	if err != nil {
+		return fmt.Errorf("cliconfig: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("cliconfig: write %s: %w", path, err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("cliconfig: write %s: %w", path, err)
+	}
+	if err := os.Chmod(tmp.Name(), 0o600); err != nil {
+		return fmt.Errorf("cliconfig: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("cliconfig: write %s: %w", path, err)
+	}
+	return nil
+}
+
+// Context returns the context called name.
+func (c *Config) Context(name string) (Context, bool) {
+	for _, ctx := range c.Contexts {
+		if ctx.Name == name {
+			return ctx, true
+		}
+	}
+	return Context{}, false
+}
+
+// Set adds ctx, replacing a context of the same name.
+func (c *Config) Set(ctx Context) {
+	for i := range c.Contexts {
+		if c.Contexts[i].Name == ctx.Name {
+			c.Contexts[i] = ctx
+			return
+		}
+	}
+	c.Contexts = append(c.Contexts, ctx)
+}
+
+// Delete removes the context called name and reports whether it existed.
+// Deleting the current context leaves none current.
+func (c *Config) Delete(name string) bool {
+	for i := range c.Contexts {
+		if c.Contexts[i].Name == name {
+			c.Contexts = append(c.Contexts[:i], c.Contexts[i+1:]...)
+			if c.CurrentContext == name {
+				c.CurrentContext = ""
+			}
+			return true
+		}
+	}
+	return false
+}
+
+// Validate checks that the context has a usable name and API URL.
+func (ctx Context) Validate() error {
+	if ctx.Name == "" || strings.ContainsAny(ctx.Name, " \t\r\n") {
+		return fmt.Errorf("context name %q must be non-empty and contain no whitespace", ctx.Name)
+	}
+	parsed, err := url.Parse(ctx.API)
+	if err != nil {
+		return fmt.Errorf("context %q: api: %w", ctx.Name, err)
+	}
+	switch parsed.Scheme {
+	case "http", "https":
+		if parsed.Host == "" {
+			return fmt.Errorf("context %q: api %q has no host", ctx.Name, ctx.API)
+		}
+	case "unix":
+		if parsed.Path == "" {
+			return fmt.Errorf("context %q: api %q has no socket path", ctx.Name, ctx.API)
+		}
+	default:
+		return fmt.Errorf("context %q: api %q must be an http, https or unix URL", ctx.Name, ctx.API)
+	}
+	if ctx.APIKey != "" && ctx.APIKeyEnv != "" {
+		return fmt.Errorf("context %q: set api_key or api_key_env, not both", ctx.Name)
+	}
+	if (ctx.TLS.CertFile == "") != (ctx.TLS.KeyFile == "") {
+		return fmt.Errorf("context %q: tls cert_file and key_file must be set together", ctx.Name)
+	}
+	return nil
+}
+
+// Key returns the API key of the context, read from APIKeyEnv when set.
+func (ctx Context) Key() string {
+	if ctx.APIKeyEnv != "" {
+		return strings.TrimSpace(os.Getenv(ctx.APIKeyEnv))
+	}
+	return ctx.APIKey
+}
+
+// ClientConfig builds the TLS configuration, or nil when no setting differs
+// from the defaults.
+func (t TLS) ClientConfig() (*tls.Config, error) {
+	if t == (TLS{}) {
+		return nil, nil
+	}
+	cfg := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		ServerName:         t.ServerName,
+		InsecureSkipVerify: t.InsecureSkipVerify,
+	}
+	if t.CAFile != "" {
+		pem, err := os.ReadFile(t.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("cliconfig: tls ca_file: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("cliconfig: tls ca_file %s holds no PEM certificates", t.CAFile)
+		}
+		cfg.RootCAs = pool
+	}
+	if t.CertFile != "" {
+		cert, err := tls.LoadX509KeyPair(t.CertFile, t.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("cliconfig: tls client certificate: %w", err)
+		}
+		cfg.Certificates = []tls.Certificate{cert}
+	}
+	return cfg, nil
+}
@@ -141,6 +141,41 @@ func RequiredParameters(op *Operation, location string) []string {
 	return result
 }
 
+// PreferredContentType returns the content type to send op's request body
+// as: application/json when the operation accepts it, otherwise any type it
+// declares, or "" when it takes no body.
+func PreferredContentType(op *Operation) string {
+	if op == nil || op.RequestBody == nil {
+		return ""
+	}
+	body := op.RequestBody.Value
+	if body == nil || len(body.Content) == 0 {
+		return ""
+	}
+	if _, ok := body.Content["application/json"]; ok {
+		return "application/json"
+	}
+	types := make([]string, 0, len(body.Content))
+	for ct := range body.Content {
+		types = append(types, ct)
+	}
+	sort.Strings(types)
+	return types[0]
+}
+
+// RequestSchema returns the schema of op's request body in contentType, or
+// nil when the operation does not describe one.
+func RequestSchema(op *Operation, contentType string) *openapi3.Schema {
+	if op == nil || op.RequestBody == nil || op.RequestBody.Value == nil {
+		return nil
+	}
+	media := op.RequestBody.Value.Content.Get(contentType)
+	if media == nil || media.Schema == nil {
+		return nil
+	}
+	return media.Schema.Value
+}
+
 func collectParameters(refs openapi3.Parameters) []Parameter {
 	params := make([]Parameter, 0, len(refs))
 	for _, ref := range refs {
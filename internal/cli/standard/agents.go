@@ -0,0 +1,155 @@
+// Copyright (c) 2025 HYPR. PTE. LTD.
+//
+// Business Source License 1.1
+// See LICENSE file in the project root for details.
+
+package standard
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"runtime"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+func newSystemAgentCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "agent",
+		Short: "Manage the agent binaries guests upgrade to",
+		Long: `Manage the agent binaries volantd serves to guests.
+
+A plugin manifest pins an agent version with "agent": {"version": "..."}.
+Guests booting an older agent fetch that version for their architecture,
+verify its SHA-256 and hand over to it, so upgrading the agent needs no
+rootfs rebuild.`,
+	}
+	cmd.AddCommand(newSystemAgentListCmd())
+	cmd.AddCommand(newSystemAgentPushCmd())
+	cmd.AddCommand(newSystemAgentDeleteCmd())
+	return cmd
+}
+
+func newSystemAgentListCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List agent binaries",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			api, err := clientFromCmd(cmd)
+			if err != nil {
+				return err
+			}
+			ctx, cancel := context.WithTimeout(cmd.Context(), 15*time.Second)
+			defer cancel()
+
+			artifacts, err := api.ListAgentArtifacts(ctx)
+			if err != nil {
+				return err
+			}
+			if done, err := renderNames(cmd, artifacts, func() []string {
+				versions := make([]string, 0, len(artifacts))
+				for _, artifact := range artifacts {
+					versions = append(versions, artifact.Version)
+				}
+				return uniqueStrings(versions)
+			}); done {
+				return err
+			}
+			if len(artifacts) == 0 {
+				fmt.Fprintln(cmd.OutOrStdout(), "No agent binaries found")
+				return nil
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "%-16s %-6s %-10s %-64s\n", "VERSION", "ARCH", "SIZE", "SHA256")
+			for _, artifact := range artifacts {
+				fmt.Fprintf(cmd.OutOrStdout(), "%-16s %-6s %-10s %-64s\n", artifact.Version, artifact.Arch, formatBytes(artifact.SizeBytes), artifact.SHA256)
+			}
+			return nil
+		},
+	}
+	return cmd
+}
+
+func newSystemAgentPushCmd() *cobra.Command {
+	var arch string
+	cmd := &cobra.Command{
+		Use:   "push <version> <binary>",
+		Short: "Upload an agent binary",
+		Long: `Upload an agent binary for guests pinned to its version.
+
+Build the agent for the guest architecture (CGO_ENABLED=0) and push it under
+the version it reports. volantd verifies the upload's SHA-256 before
+replacing an existing binary.
+
+Examples:
+  volar system agent push v2.1 ./kestrel
+  volar system agent push v2.1 ./kestrel-arm64 --arch arm64`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			version, local := args[0], args[1]
+			file, err := os.Open(local)
+			if err != nil {
+				return err
+			}
+			defer file.Close()
+			info, err := file.Stat()
+			if err != nil {
+				return err
+			}
+			if !info.Mode().IsRegular() {
+				return fmt.Errorf("%s is not a regular file", local)
+			}
+			hash := sha256.New()
+			if _, err := io.Copy(hash, file); err != nil {
+				return fmt.Errorf("read %s: %w", local, err)
+			}
+			if _, err := file.Seek(0, io.SeekStart); err != nil {
+				return err
+			}
+
+			api, err := clientFromCmd(cmd)
+			if err != nil {
+				return err
+			}
+			artifact, err := api.UploadAgentArtifact(cmd.Context(), version, arch, file, info.Size(), hex.EncodeToString(hash.Sum(nil)))
+			if err != nil {
+				return err
+			}
+			return renderResult(cmd, artifact, "Agent %s for %s uploaded (%s, sha256 %s)", artifact.Version, artifact.Arch, formatBytes(artifact.SizeBytes), artifact.SHA256)
+		},
+	}
+	cmd.Flags().StringVar(&arch, "arch", runtime.GOARCH, "Guest architecture the binary is built for (amd64, arm64)")
+	return cmd
+}
+
+func newSystemAgentDeleteCmd() *cobra.Command {
+	var arch string
+	cmd := &cobra.Command{
+		Use:   "delete <version>",
+		Short: "Delete an agent binary",
+		Long: `Delete an agent binary. Guests pinned to its version keep running the
+agent they booted with.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			api, err := clientFromCmd(cmd)
+			if err != nil {
+				return err
+			}
+			ctx, cancel := context.WithTimeout(cmd.Context(), 15*time.Second)
+			defer cancel()
+
+			if err := api.DeleteAgentArtifact(ctx, args[0], arch); err != nil {
+				return err
+			}
+			infof(cmd, "Agent %s for %s deleted", args[0], arch)
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&arch, "arch", runtime.GOARCH, "Guest architecture of the binary (amd64, arm64)")
+	return cmd
+}
@@ -0,0 +1,369 @@
+// Copyright (c) 2025 HYPR. PTE. LTD.
+//
+// Business Source License 1.1
+// See LICENSE file in the project root for details.
+
+package standard
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+
+	"github.com/volantvm/volant/pkg/volantclient"
+)
+
+func newApplyCmd() *cobra.Command {
+	var files []string
+	cmd := &cobra.Command{
+		Use:   "apply -f <file|dir|->...",
+		Short: "Create or update plugins, VMs and deployments from YAML or JSON",
+		Long: `Make volantd match the plugins, VMs and deployments declared in YAML or JSON
+documents. Each resource is found by kind and name: missing ones are created,
+and ones whose declared fields differ are updated. Fields a document leaves
+out keep their current values, so applying the same files twice changes
+nothing. Preview the changes with "volar diff".
+
+A document declares one resource; a file may hold several YAML documents
+separated by "---", or a list:
+
+  kind: vm                  # plugin, vm or deployment
+  name: web-1
+  spec:
+    config:                 # the VM config, as in "volar vms config get"
+      plugin: nginx
+      resources: {cpu_cores: 2, memory_mb: 1024}
+    labels: {tier: web}     # merged into the VM's labels
+    restart: true           # restart the VM when its config changes
+
+A plugin's spec is its manifest. A deployment's spec holds replicas,
+max_surge, max_unavailable and config. Plugins are applied first, the rest in
+file order, stopping at the first failure.
+
+A file holding {"steps": [...]} is applied as a transaction instead: steps
+run in order, and if any fails the steps already applied are rolled back.
+op is one of plugin.install, network.create, config_bundle.create,
+deployment.create or route.upsert, and spec is the body of that operation's
+own endpoint.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			resources, tx, err := readResourceFiles(cmd, files)
+			if err != nil {
+				return err
+			}
+			api, err := clientFromCmd(cmd)
+			if err != nil {
+				return err
+			}
+			ctx, cancel := context.WithTimeout(cmd.Context(), 5*time.Minute)
+			defer cancel()
+			if tx != nil {
+				return applyTransaction(ctx, cmd, api, *tx)
+			}
+
+			result, applyErr := api.Apply(ctx, volantclient.ApplyRequest{Resources: resources})
+			if result == nil {
+				return applyErr
+			}
+			if done, err := render(cmd, result); done {
+				if applyErr != nil {
+					return applyErr
+				}
+				return err
+			}
+			if quiet(cmd) {
+				return applyErr
+			}
+			out := cmd.OutOrStdout()
+			fmt.Fprintf(out, "%-12s %-24s %-10s %-8s %s\n", "KIND", "NAME", "ACTION", "STATUS", "ERROR")
+			for _, res := range result.Resources {
+				fmt.Fprintf(out, "%-12s %-24s %-10s %-8s %s\n", res.Kind, res.Name, res.Action, res.Status, res.Error)
+				printResourceChanges(out, res.Changes)
+			}
+			return applyErr
+		},
+	}
+	cmd.Flags().StringArrayVarP(&files, "file", "f", nil, "YAML or JSON file, directory of them, or - for stdin (repeatable)")
+	return cmd
+}
+
+func newDiffCmd() *cobra.Command {
+	var (
+		files    []string
+		exitCode bool
+	)
+	cmd := &cobra.Command{
+		Use:   "diff -f <file|dir|->...",
+		Short: "Show what apply would change",
+		Long: `Compare the resources declared in YAML or JSON documents with volantd and
+show what "volar apply" would create or update, field by field. Nothing is
+changed. With --exit-code, exit with status 1 when there are changes.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			resources, tx, err := readResourceFiles(cmd, files)
+			if err != nil {
+				return err
+			}
+			if tx != nil {
+				return errors.New("transactions cannot be diffed; declare the resources with kind, name and spec")
+			}
+			api, err := clientFromCmd(cmd)
+			if err != nil {
+				return err
+			}
+			ctx, cancel := context.WithTimeout(cmd.Context(), 30*time.Second)
+			defer cancel()
+
+			result, diffErr := api.Apply(ctx, volantclient.ApplyRequest{Resources: resources, DryRun: true})
+			if result == nil {
+				return diffErr
+			}
+			changed := false
+			for _, res := range result.Resources {
+				if res.Action == "create" || res.Action == "update" {
+					changed = true
+				}
+			}
+			if done, err := render(cmd, result); done {
+				if diffErr != nil {
+					return diffErr
+				}
+				if err != nil {
+					return err
+				}
+			} else if !quiet(cmd) {
+				printDiff(cmd.OutOrStdout(), result)
+			}
+			if diffErr != nil {
+				return diffErr
+			}
+			if changed && exitCode {
+				cmd.SilenceErrors = true
+				cmd.SilenceUsage = true
+				return &ExitError{Code: 1}
+			}
+			return nil
+		},
+	}
+	cmd.Flags().StringArrayVarP(&files, "file", "f", nil, "YAML or JSON file, directory of them, or - for stdin (repeatable)")
+	cmd.Flags().BoolVar(&exitCode, "exit-code", false, "Exit with status 1 when there are changes")
+	return cmd
+}
+
+func applyTransaction(ctx context.Context, cmd *cobra.Command, api *volantclient.Client, payload volantclient.TransactionRequest) error {
+	result, applyErr := api.ApplyTransaction(ctx, payload)
+	if result == nil {
+		return applyErr
+	}
+	if done, err := render(cmd, result); done {
+		if applyErr != nil {
+			return applyErr
+		}
+		return err
+	}
+	if quiet(cmd) {
+		return applyErr
+	}
+	out := cmd.OutOrStdout()
+	fmt.Fprintf(out, "%-4s %-22s %-24s %-16s %s\n", "STEP", "OP", "NAME", "STATUS", "ERROR")
+	for _, step := range result.Steps {
+		fmt.Fprintf(out, "%-4d %-22s %-24s %-16s %s\n", step.Index, step.Op, step.Name, step.Status, step.Error)
+	}
+	fmt.Fprintf(out, "Transaction %s\n", result.Status)
+	return applyErr
+}
+
+func printDiff(out io.Writer, result *volantclient.ApplyResult) {
+	counts := make(map[string]int)
+	for _, res := range result.Resources {
+		ref := res.Kind + "/" + res.Name
+		switch {
+		case res.Status == "failed":
+			fmt.Fprintf(out, "! %s: %s\n", ref, res.Error)
+		case res.Action == "create":
+			fmt.Fprintf(out, "+ %s\n", ref)
+		case res.Action == "update":
+			fmt.Fprintf(out, "~ %s\n", ref)
+			printResourceChanges(out, res.Changes)
+		default:
+			fmt.Fprintf(out, "= %s\n", ref)
+		}
+		counts[res.Action]++
+	}
+	fmt.Fprintf(out, "%d to create, %d to update, %d unchanged\n", counts["create"], counts["update"], counts["unchanged"])
+}
+
+func printResourceChanges(out io.Writer, changes []volantclient.ResourceChange) {
+	for _, change := range changes {
+		fmt.Fprintf(out, "    %s: %s → %s\n", change.Field, formatChangeValue(change.From), formatChangeValue(change.To))
+	}
+}
+
+// formatChangeValue renders a changed value on one line, shortening long
+// objects and lists.
+func formatChangeValue(v any) string {
+	if v == nil {
+		return "<unset>"
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	text := string(data)
+	if len(text) > 60 {
+		text = text[:57] + "..."
+	}
+	return text
+}
+
+// readResourceFiles reads the resources declared in paths: files, directories
+// of .yaml, .yml and .json files, or - for stdin. A single document with
+// top-level steps is returned as a transaction instead.
+func readResourceFiles(cmd *cobra.Command, paths []string) ([]volantclient.Resource, *volantclient.TransactionRequest, error) {
+	if len(paths) == 0 {
+		return nil, nil, errors.New("--file is required")
+	}
+	var resources []volantclient.Resource
+	var docs int
+	for _, path := range paths {
+		files, err := expandResourcePath(path)
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, file := range files {
+			var data []byte
+			if file == "-" {
+				data, err = io.ReadAll(cmd.InOrStdin())
+			} else {
+				data, err = os.ReadFile(file)
+			}
+			if err != nil {
+				return nil, nil, fmt.Errorf("read %s: %w", file, err)
+			}
+			decoder := yaml.NewDecoder(bytes.NewReader(data))
+			for n := 1; ; n++ {
+				var doc any
+				if err := decoder.Decode(&doc); errors.Is(err, io.EOF) {
+					break
+				} else if err != nil {
+					return nil, nil, fmt.Errorf("parse %s: %w", file, err)
+				}
+				if doc == nil {
+					continue
+				}
+				docs++
+				if fields, ok := doc.(map[string]any); ok && fields["steps"] != nil {
+					tx, err := parseTransaction(fields)
+					if err != nil {
+						return nil, nil, fmt.Errorf("%s: %w", file, err)
+					}
+					if len(paths) > 1 || len(files) > 1 || docs > 1 || decoder.Decode(new(any)) == nil {
+						return nil, nil, fmt.Errorf("%s: a transaction must be the only document", file)
+					}
+					return nil, tx, nil
+				}
+				items, ok := doc.([]any)
+				if !ok {
+					items = []any{doc}
+				}
+				for i, item := range items {
+					res, err := parseResource(item)
+					if err != nil {
+						where := fmt.Sprintf("%s: document %d", file, n)
+						if len(items) > 1 {
+							where += fmt.Sprintf(" item %d", i+1)
+						}
+						return nil, nil, fmt.Errorf("%s: %w", where, err)
+					}
+					resources = append(resources, res)
+				}
+			}
+		}
+	}
+	if len(resources) == 0 {
+		return nil, nil, errors.New("no resources declared")
+	}
+	return resources, nil, nil
+}
+
+// expandResourcePath lists the files of a directory in name order, or
+// returns path itself.
+func expandResourcePath(path string) ([]string, error) {
+	if path == "-" {
+		return []string{path}, nil
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return []string{path}, nil
+	}
+	entries, err := os.ReadDir(path)
+	if err != nil {
+		return nil, err
+	}
+	var files []string
+	for _, entry := range entries {
+		switch strings.ToLower(filepath.Ext(entry.Name())) {
+		case ".yaml", ".yml", ".json":
+			if !entry.IsDir() {
+				files = append(files, filepath.Join(path, entry.Name()))
+			}
+		}
+	}
+	sort.Strings(files)
+	if len(files) == 0 {
+		return nil, fmt.Errorf("%s holds no .yaml, .yml or .json files", path)
+	}
+	return files, nil
+}
+
+func parseResource(doc any) (volantclient.Resource, error) {
+	fields, ok := doc.(map[string]any)
+	if !ok {
+		return volantclient.Resource{}, errors.New("expected a mapping with kind, name and spec")
+	}
+	for key := range fields {
+		if key != "kind" && key != "name" && key != "spec" {
+			return volantclient.Resource{}, fmt.Errorf("unknown field %q; expected kind, name and spec", key)
+		}
+	}
+	kind, _ := fields["kind"].(string)
+	name, _ := fields["name"].(string)
+	if kind == "" || name == "" {
+		return volantclient.Resource{}, errors.New("kind and name are required")
+	}
+	spec := fields["spec"]
+	if spec == nil {
+		spec = map[string]any{}
+	}
+	data, err := json.Marshal(spec)
+	if err != nil {
+		return volantclient.Resource{}, fmt.Errorf("%s %s: spec: %w", kind, name, err)
+	}
+	return volantclient.Resource{Kind: kind, Name: name, Spec: data}, nil
+}
+
+func parseTransaction(doc map[string]any) (*volantclient.TransactionRequest, error) {
+	data, err := json.Marshal(doc)
+	if err != nil {
+		return nil, fmt.Errorf("parse transaction: %w", err)
+	}
+	var tx volantclient.TransactionRequest
+	if err := json.Unmarshal(data, &tx); err != nil {
+		return nil, fmt.Errorf("parse transaction: %w", err)
+	}
+	return &tx, nil
+}
@@ -0,0 +1,56 @@
+// Copyright (c) 2025 HYPR. PTE. LTD.
+//
+// Business Source License 1.1
+// See LICENSE file in the project root for details.
+
+package standard
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+func newAuditCmd() *cobra.Command {
+	var vmName string
+	var limit int
+	cmd := &cobra.Command{
+		Use:   "audit",
+		Short: "Show the audit trail (console sessions and denials)",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			api, err := clientFromCmd(cmd)
+			if err != nil {
+				return err
+			}
+			ctx, cancel := context.WithTimeout(cmd.Context(), 15*time.Second)
+			defer cancel()
+
+			events, err := api.AuditEvents(ctx, vmName, limit)
+			if err != nil {
+				return err
+			}
+			if done, err := render(cmd, events); done {
+				return err
+			}
+			if len(events) == 0 {
+				fmt.Fprintln(cmd.OutOrStdout(), "No audit events recorded")
+				return nil
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "%-20s %-16s %-20s %-16s %-16s %-10s %s\n", "TIME", "ACTION", "TARGET", "ACTOR", "CLIENT", "DURATION", "MESSAGE")
+			for _, event := range events {
+				duration := "-"
+				if event.DurationMS > 0 {
+					duration = (time.Duration(event.DurationMS) * time.Millisecond).Round(time.Second).String()
+				}
+				fmt.Fprintf(cmd.OutOrStdout(), "%-20s %-16s %-20s %-16s %-16s %-10s %s\n", event.CreatedAt.Local().Format("2006-01-02 15:04:05"), event.Action, event.Target, event.Actor, event.ClientIP, duration, event.Message)
+			}
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&vmName, "vm", "", "Only show events for this VM")
+	cmd.Flags().IntVar(&limit, "limit", 50, "Number of events to show")
+	_ = cmd.RegisterFlagCompletionFunc("vm", completeFlag(completeVMNames))
+	return cmd
+}
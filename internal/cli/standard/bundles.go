@@ -0,0 +1,287 @@
+// Copyright (c) 2025 HYPR. PTE. LTD.
+//
+// Business Source License 1.1
+// See LICENSE file in the project root for details.
+
+package standard
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/volantvm/volant/internal/pluginspec"
+	"github.com/volantvm/volant/pkg/volantclient"
+)
+
+func newBundlesCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "bundles",
+		Aliases: []string{"bundle"},
+		Short:   "Manage config bundles attached to deployments",
+	}
+	cmd.AddCommand(newBundlesListCmd())
+	cmd.AddCommand(newBundlesCreateCmd())
+	cmd.AddCommand(newBundlesShowCmd())
+	cmd.AddCommand(newBundlesUpdateCmd())
+	cmd.AddCommand(newBundlesDeleteCmd())
+	return cmd
+}
+
+func newBundlesListCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List config bundles",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			api, err := clientFromCmd(cmd)
+			if err != nil {
+				return err
+			}
+			ctx, cancel := context.WithTimeout(cmd.Context(), 15*time.Second)
+			defer cancel()
+
+			bundles, err := api.ListConfigBundles(ctx)
+			if err != nil {
+				return err
+			}
+			if done, err := renderNames(cmd, bundles, func() []string {
+				names := make([]string, 0, len(bundles))
+				for _, bundle := range bundles {
+					names = append(names, bundle.Name)
+				}
+				return names
+			}); done {
+				return err
+			}
+			if len(bundles) == 0 {
+				fmt.Fprintln(cmd.OutOrStdout(), "No config bundles found")
+				return nil
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "%-20s %-8s %-6s %-6s %s\n", "NAME", "VERSION", "KEYS", "FILES", "UPDATED")
+			for _, bundle := range bundles {
+				fmt.Fprintf(cmd.OutOrStdout(), "%-20s %-8d %-6d %-6d %s\n", bundle.Name, bundle.Version, len(bundle.Keys), len(bundle.Files), bundle.UpdatedAt.Local().Format("2006-01-02 15:04"))
+			}
+			return nil
+		},
+	}
+	return cmd
+}
+
+// bundleFlags holds the content flags shared by create and update.
+type bundleFlags struct {
+	literals []string
+	envFiles []string
+	files    []string
+}
+
+func (f *bundleFlags) register(cmd *cobra.Command) {
+	cmd.Flags().StringArrayVar(&f.literals, "from-literal", nil, "Data key as KEY=VALUE (repeatable)")
+	cmd.Flags().StringArrayVar(&f.envFiles, "from-env-file", nil, "Read KEY=VALUE lines from a local file (repeatable)")
+	cmd.Flags().StringArrayVar(&f.files, "file", nil, "Guest file as GUEST_PATH=LOCAL_PATH[:MODE] (repeatable)")
+}
+
+func (f *bundleFlags) request() (volantclient.ConfigBundleRequest, error) {
+	var req volantclient.ConfigBundleRequest
+	data := make(map[string]string)
+	for _, path := range f.envFiles {
+		raw, err := os.ReadFile(path)
+		if err != nil {
+			return req, fmt.Errorf("read env file: %w", err)
+		}
+		for i, line := range strings.Split(string(raw), "\n") {
+			line = strings.TrimSpace(line)
+			if line == "" || strings.HasPrefix(line, "#") {
+				continue
+			}
+			key, value, ok := strings.Cut(line, "=")
+			if !ok {
+				return req, fmt.Errorf("%s:%d: expected KEY=VALUE", path, i+1)
+			}
+			data[strings.TrimSpace(key)] = value
+		}
+	}
+	for _, literal := range f.literals {
+		key, value, ok := strings.Cut(literal, "=")
+		if !ok {
+			return req, fmt.Errorf("invalid --from-literal %q (expected KEY=VALUE)", literal)
+		}
+		data[strings.TrimSpace(key)] = value
+	}
+	if len(data) > 0 {
+		req.Data = data
+	}
+	for _, spec := range f.files {
+		guestPath, local, ok := strings.Cut(spec, "=")
+		if !ok || guestPath == "" || local == "" {
+			return req, fmt.Errorf("invalid --file %q (expected GUEST_PATH=LOCAL_PATH[:MODE])", spec)
+		}
+		mode := ""
+		if idx := strings.LastIndex(local, ":"); idx > 0 {
+			local, mode = local[:idx], local[idx+1:]
+		}
+		content, err := os.ReadFile(local)
+		if err != nil {
+			return req, fmt.Errorf("read bundle file: %w", err)
+		}
+		req.Files = append(req.Files, pluginspec.BundleFile{
+			Path:     guestPath,
+			Content:  base64.StdEncoding.EncodeToString(content),
+			Encoding: "base64",
+			Mode:     mode,
+		})
+	}
+	if len(req.Data) == 0 && len(req.Files) == 0 {
+		return req, fmt.Errorf("bundle needs at least one --from-literal, --from-env-file or --file")
+	}
+	return req, nil
+}
+
+func newBundlesCreateCmd() *cobra.Command {
+	var flags bundleFlags
+	cmd := &cobra.Command{
+		Use:   "create <name>",
+		Short: "Create a config bundle",
+		Long: `Create a config bundle of key/value pairs and files. Deployments attach
+bundles by name in config.bundles; the agent exports data keys to the workload
+environment, writes them under /run/volant/bundles/<bundle>/<key>, and writes
+files to their guest paths before the workload starts.
+
+Examples:
+  volar bundles create db-creds --from-literal DB_USER=app --from-literal DB_PASSWORD=s3cret
+  volar bundles create tls --file /etc/app/tls.crt=./tls.crt:0644 --file /etc/app/tls.key=./tls.key`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			req, err := flags.request()
+			if err != nil {
+				return err
+			}
+			req.Name = args[0]
+			api, err := clientFromCmd(cmd)
+			if err != nil {
+				return err
+			}
+			ctx, cancel := context.WithTimeout(cmd.Context(), 15*time.Second)
+			defer cancel()
+
+			bundle, err := api.CreateConfigBundle(ctx, req)
+			if err != nil {
+				return err
+			}
+			return renderResult(cmd, bundle, "Config bundle %s created (%d keys, %d files)", bundle.Name, len(bundle.Keys), len(bundle.Files))
+		},
+	}
+	flags.register(cmd)
+	return cmd
+}
+
+func newBundlesShowCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "show <name>",
+		Short: "Show a config bundle's keys and files (values are not shown)",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			api, err := clientFromCmd(cmd)
+			if err != nil {
+				return err
+			}
+			ctx, cancel := context.WithTimeout(cmd.Context(), 15*time.Second)
+			defer cancel()
+
+			bundle, err := api.GetConfigBundle(ctx, args[0])
+			if err != nil {
+				return err
+			}
+			if done, err := render(cmd, bundle); done {
+				return err
+			}
+			out := cmd.OutOrStdout()
+			fmt.Fprintf(out, "Name:     %s\n", bundle.Name)
+			fmt.Fprintf(out, "Version:  %d\n", bundle.Version)
+			fmt.Fprintf(out, "Updated:  %s\n", bundle.UpdatedAt.Local().Format("2006-01-02 15:04"))
+			if len(bundle.Keys) > 0 {
+				fmt.Fprintf(out, "Keys:     %s\n", strings.Join(bundle.Keys, ", "))
+			}
+			for _, path := range bundle.Files {
+				fmt.Fprintf(out, "File:     %s\n", path)
+			}
+			return nil
+		},
+	}
+	return cmd
+}
+
+func newBundlesUpdateCmd() *cobra.Command {
+	var flags bundleFlags
+	cmd := &cobra.Command{
+		Use:   "update <name>",
+		Short: "Replace a config bundle and roll the deployments using it",
+		Long: `Replace a config bundle's contents. The new version is delivered by a
+rolling restart of every deployment that lists the bundle in config.bundles.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			req, err := flags.request()
+			if err != nil {
+				return err
+			}
+			api, err := clientFromCmd(cmd)
+			if err != nil {
+				return err
+			}
+			ctx, cancel := context.WithTimeout(cmd.Context(), 30*time.Second)
+			defer cancel()
+
+			bundle, err := api.UpdateConfigBundle(ctx, args[0], req)
+			if err != nil {
+				return err
+			}
+			if done, err := render(cmd, bundle); done || quiet(cmd) {
+				return err
+			}
+			out := cmd.OutOrStdout()
+			fmt.Fprintf(out, "Config bundle %s updated to version %d\n", bundle.Name, bundle.Version)
+			for _, name := range bundle.Restarted {
+				fmt.Fprintf(out, "  rolling deployment %s\n", name)
+			}
+			skipped := make([]string, 0, len(bundle.Skipped))
+			for name := range bundle.Skipped {
+				skipped = append(skipped, name)
+			}
+			sort.Strings(skipped)
+			for _, name := range skipped {
+				fmt.Fprintf(out, "  skipped deployment %s: %s\n", name, bundle.Skipped[name])
+			}
+			return nil
+		},
+	}
+	flags.register(cmd)
+	return cmd
+}
+
+func newBundlesDeleteCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "delete <name>",
+		Short: "Delete a config bundle no deployment uses",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			api, err := clientFromCmd(cmd)
+			if err != nil {
+				return err
+			}
+			ctx, cancel := context.WithTimeout(cmd.Context(), 15*time.Second)
+			defer cancel()
+
+			if err := api.DeleteConfigBundle(ctx, args[0]); err != nil {
+				return err
+			}
+			infof(cmd, "Config bundle %s deleted", args[0])
+			return nil
+		},
+	}
+	return cmd
+}
@@ -23,23 +23,52 @@ func newRootCmd() *cobra.Command {
 		Long: `volar provides access to the VOLANT control plane.
 
 Core commands:
-  vms       Manage microVMs
-  plugins   Install/remove plugin manifests
-  setup     Helper for host networking/service configuration
-  console   Inspect or attach to VM consoles
+  vms         Manage microVMs
+  plugins     Install/remove plugin manifests
+  setup       Helper for host networking/service configuration
+  console     Inspect or attach to VM consoles
+  dev         Development helpers (file sync into running VMs)
+  tui         Interactive terminal UI
+  up          Try volant locally (volar up --demo)
+  apply       Create or update plugins, VMs and deployments from YAML (preview: volar diff)
+  config      Switch between volantd hosts (volar config use-context <name>)
+  completion  Shell completion scripts with VM, deployment and plugin names
 `,
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			return validateOutputFlags(cmd)
+		},
 		RunE: func(cmd *cobra.Command, args []string) error {
 			return cmd.Help()
 		},
 	}
 
-	cmd.PersistentFlags().StringP("api", "a", envOrDefault("VOLANT_API_BASE", "http://127.0.0.1:7777"), "volantd base URL")
+	cmd.PersistentFlags().StringP("api", "a", "", "volantd base URL (default $VOLANT_API_BASE, else the current context's, else "+defaultAPIBase+")")
+	cmd.PersistentFlags().String("context", "", "Context from ~/.volant/config.yaml to use (default $VOLAR_CONTEXT or the current context)")
+	_ = cmd.RegisterFlagCompletionFunc("context", completeFlag(completeContextNames))
+	addOutputFlags(cmd)
 
 	cmd.AddCommand(newVersionCmd())
 	cmd.AddCommand(newVMsCmd())
 	cmd.AddCommand(newPluginsCmd())
 	cmd.AddCommand(newSetupCmd())
 	cmd.AddCommand(newDeploymentsCmd())
+	cmd.AddCommand(newDevCmd())
+	cmd.AddCommand(newSchedulesCmd())
+	cmd.AddCommand(newWebhooksCmd())
+	cmd.AddCommand(newSecretsCmd())
+	cmd.AddCommand(newMaintenanceCmd())
+	cmd.AddCommand(newNetworksCmd())
+	cmd.AddCommand(newWarmPoolsCmd())
+	cmd.AddCommand(newRoutesCmd())
+	cmd.AddCommand(newOperationsCmd())
+	cmd.AddCommand(newBundlesCmd())
+	cmd.AddCommand(newApplyCmd())
+	cmd.AddCommand(newDiffCmd())
+	cmd.AddCommand(newAuditCmd())
+	cmd.AddCommand(newSystemCmd())
+	cmd.AddCommand(newUpCmd())
+	cmd.AddCommand(newTUICmd())
+	cmd.AddCommand(newConfigCmd())
 	return cmd
 }
 
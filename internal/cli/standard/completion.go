@@ -0,0 +1,197 @@
+// Copyright (c) 2025 HYPR. PTE. LTD.
+//
+// Business Source License 1.1
+// See LICENSE file in the project root for details.
+
+package standard
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/volantvm/volant/internal/cli/openapiutil"
+	"github.com/volantvm/volant/pkg/volantclient"
+)
+
+const (
+	// completionTTL is how long candidates fetched from volantd are reused.
+	// Shells run volar on every Tab press, so without a cache each press
+	// would be a round trip.
+	completionTTL = 30 * time.Second
+	// completionTimeout bounds a fetch so a slow server never hangs the shell.
+	completionTimeout = 3 * time.Second
+)
+
+// completer returns the candidates for one positional argument, given the
+// arguments before it. Candidates may carry a description after a tab.
+type completer func(cmd *cobra.Command, args []string) []string
+
+// completeArgs completes positional arguments in order with completers. A
+// nil completer leaves the argument to the shell's file completion, and
+// arguments past the last completer get no completion.
+func completeArgs(completers ...completer) func(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective) {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) >= len(completers) {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		complete := completers[len(args)]
+		if complete == nil {
+			return nil, cobra.ShellCompDirectiveDefault
+		}
+		return filterCandidates(complete(cmd, args), toComplete), cobra.ShellCompDirectiveNoFileComp
+	}
+}
+
+// completeFlag completes the value of a flag with complete.
+func completeFlag(complete completer) func(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective) {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return filterCandidates(complete(cmd, args), toComplete), cobra.ShellCompDirectiveNoFileComp
+	}
+}
+
+// noCandidates completes nothing, for arguments such as guest paths that
+// neither the server nor the local file system can suggest.
+func noCandidates(cmd *cobra.Command, args []string) []string {
+	return nil
+}
+
+func filterCandidates(candidates []string, prefix string) []string {
+	matched := candidates[:0:0]
+	for _, candidate := range candidates {
+		if strings.HasPrefix(candidate, prefix) {
+			matched = append(matched, candidate)
+		}
+	}
+	return matched
+}
+
+func completeVMNames(cmd *cobra.Command, args []string) []string {
+	return cachedCandidates(cmd, "vms", func(ctx context.Context, api *volantclient.Client) ([]string, error) {
+		vms, err := api.ListVMs(ctx)
+		if err != nil {
+			return nil, err
+		}
+		names := make([]string, 0, len(vms))
+		for _, vm := range vms {
+			names = append(names, fmt.Sprintf("%s\t%s %s", vm.Name, vm.Status, vm.Runtime))
+		}
+		return names, nil
+	})
+}
+
+func completeDeploymentNames(cmd *cobra.Command, args []string) []string {
+	return cachedCandidates(cmd, "deployments", func(ctx context.Context, api *volantclient.Client) ([]string, error) {
+		deployments, err := api.ListDeployments(ctx)
+		if err != nil {
+			return nil, err
+		}
+		names := make([]string, 0, len(deployments))
+		for _, dep := range deployments {
+			names = append(names, fmt.Sprintf("%s\t%s, %d/%d ready", dep.Name, dep.Config.Plugin, dep.ReadyReplicas, dep.DesiredReplicas))
+		}
+		return names, nil
+	})
+}
+
+func completePluginNames(cmd *cobra.Command, args []string) []string {
+	return cachedCandidates(cmd, "plugins", func(ctx context.Context, api *volantclient.Client) ([]string, error) {
+		return api.ListPluginNames(ctx)
+	})
+}
+
+// completeOperations completes the plugin operations of the VM named by the
+// first argument, as accepted by `vms call`.
+func completeOperations(cmd *cobra.Command, args []string) []string {
+	if len(args) == 0 {
+		return nil
+	}
+	vm := args[0]
+	return cachedCandidates(cmd, "operations/"+vm, func(ctx context.Context, api *volantclient.Client) ([]string, error) {
+		data, _, err := api.GetVMOpenAPISpec(ctx, vm)
+		if err != nil {
+			return nil, err
+		}
+		doc, err := openapiutil.ParseDocument(data)
+		if err != nil {
+			return nil, err
+		}
+		var ids []string
+		for _, op := range openapiutil.ListOperations(doc) {
+			id := op.OperationID
+			if id == "" {
+				id = op.Method + ":" + op.Path
+			}
+			if op.Summary != "" {
+				id += "\t" + op.Summary
+			}
+			ids = append(ids, id)
+		}
+		return ids, nil
+	})
+}
+
+type completionCacheEntry struct {
+	FetchedAt  time.Time `json:"fetched_at"`
+	Candidates []string  `json:"candidates"`
+}
+
+// cachedCandidates returns the candidates stored under key for the selected
+// API base, fetching them again once they are older than completionTTL. A
+// failed fetch falls back to stale candidates, and completion degrades to
+// nothing rather than printing errors into the shell.
+func cachedCandidates(cmd *cobra.Command, key string, fetch func(context.Context, *volantclient.Client) ([]string, error)) []string {
+	conn, err := resolveConnection(cmd)
+	if err != nil {
+		return nil
+	}
+	api, err := conn.client()
+	if err != nil {
+		return nil
+	}
+	path := completionCachePath(conn.api, key)
+	var cached completionCacheEntry
+	if path != "" {
+		if data, err := os.ReadFile(path); err == nil && json.Unmarshal(data, &cached) == nil {
+			if time.Since(cached.FetchedAt) < completionTTL {
+				return cached.Candidates
+			}
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(cmd.Context(), completionTimeout)
+	defer cancel()
+	candidates, err := fetch(ctx, api)
+	if err != nil {
+		cobra.CompDebugln(fmt.Sprintf("volar: complete %s: %v", key, err), false)
+		return cached.Candidates
+	}
+	if path != "" {
+		if data, err := json.Marshal(completionCacheEntry{FetchedAt: time.Now(), Candidates: candidates}); err == nil {
+			if err := os.MkdirAll(filepath.Dir(path), 0o700); err == nil {
+				_ = os.WriteFile(path, data, 0o600)
+			}
+		}
+	}
+	return candidates
+}
+
+// completionCachePath names the cache file for key under the user's cache
+// directory, separated per API base so several servers don't mix. It is
+// empty when the system has no cache directory.
+func completionCachePath(base, key string) string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return ""
+	}
+	sum := sha256.Sum256([]byte(base + "\x00" + key))
+	return filepath.Join(dir, "volar", "completion", hex.EncodeToString(sum[:12])+".json")
+}
@@ -0,0 +1,399 @@
+// Copyright (c) 2025 HYPR. PTE. LTD.
+//
+// Business Source License 1.1
+// See LICENSE file in the project root for details.
+
+package standard
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/volantvm/volant/internal/cli/cliconfig"
+	"github.com/volantvm/volant/internal/server/apiauth"
+	"github.com/volantvm/volant/pkg/volantclient"
+)
+
+const (
+	defaultAPIBase = "http://127.0.0.1:7777"
+	// envContext selects a context like --context does.
+	envContext = "VOLAR_CONTEXT"
+)
+
+// connection is where volar sends requests and how it authenticates.
+type connection struct {
+	// context names the context in use, empty when none applies.
+	context string
+	api     string
+	apiKey  string
+	tls     cliconfig.TLS
+}
+
+// resolveConnection works out the volantd to talk to. In order:
+//   - --context or VOLAR_CONTEXT selects a context from the config file, and
+//     --api may still override its URL;
+//   - --api alone, or else VOLANT_API_BASE, names a URL without a context;
+//   - otherwise the current context of the config file applies, falling
+//     back to http://127.0.0.1:7777.
+//
+// VOLANT_API_KEY supplies the API key when the context does not.
+func resolveConnection(cmd *cobra.Command) (connection, error) {
+	flags := cmd.Root().PersistentFlags()
+	apiFlag, _ := flags.GetString("api")
+	apiSet := flags.Changed("api")
+	name, _ := flags.GetString("context")
+	if name == "" {
+		name = strings.TrimSpace(os.Getenv(envContext))
+	}
+
+	var conn connection
+	envBase := os.Getenv("VOLANT_API_BASE")
+	switch {
+	case name != "":
+		ctx, err := loadContext(name)
+		if err != nil {
+			return connection{}, err
+		}
+		conn = contextConnection(ctx)
+	case apiSet:
+		conn.api = apiFlag
+	case envBase != "":
+		conn.api = envBase
+	default:
+		cfg, _, err := loadCLIConfig()
+		if err != nil {
+			return connection{}, err
+		}
+		if cfg.CurrentContext != "" {
+			ctx, ok := cfg.Context(cfg.CurrentContext)
+			if !ok {
+				return connection{}, fmt.Errorf("current context %q is not defined; run \"volar config use-context\" with one of \"volar config get-contexts\"", cfg.CurrentContext)
+			}
+			conn = contextConnection(ctx)
+		} else {
+			conn.api = defaultAPIBase
+		}
+	}
+	if apiSet {
+		conn.api = apiFlag
+	}
+	if conn.apiKey == "" {
+		conn.apiKey = strings.TrimSpace(os.Getenv(apiauth.EnvKey))
+	}
+	return conn, nil
+}
+
+func contextConnection(ctx cliconfig.Context) connection {
+	return connection{context: ctx.Name, api: ctx.API, apiKey: ctx.Key(), tls: ctx.TLS}
+}
+
+// client builds an API client for the connection.
+func (c connection) client() (*volantclient.Client, error) {
+	tlsConfig, err := c.tls.ClientConfig()
+	if err != nil {
+		return nil, err
+	}
+	return volantclient.NewWithOptions(c.api, volantclient.Options{APIKey: c.apiKey, TLS: tlsConfig})
+}
+
+func loadCLIConfig() (*cliconfig.Config, string, error) {
+	path, err := cliconfig.DefaultPath()
+	if err != nil {
+		return nil, "", err
+	}
+	cfg, err := cliconfig.Load(path)
+	if err != nil {
+		return nil, "", err
+	}
+	return cfg, path, nil
+}
+
+func loadContext(name string) (cliconfig.Context, error) {
+	cfg, _, err := loadCLIConfig()
+	if err != nil {
+		return cliconfig.Context{}, err
+	}
+	ctx, ok := cfg.Context(name)
+	if !ok {
+		return cliconfig.Context{}, fmt.Errorf("context %q not found; \"volar config get-contexts\" lists them", name)
+	}
+	return ctx, nil
+}
+
+// completeContextNames completes context names from the local config file.
+func completeContextNames(cmd *cobra.Command, args []string) []string {
+	cfg, _, err := loadCLIConfig()
+	if err != nil {
+		return nil
+	}
+	names := make([]string, 0, len(cfg.Contexts))
+	for _, ctx := range cfg.Contexts {
+		names = append(names, ctx.Name+"\t"+ctx.API)
+	}
+	return names
+}
+
+func newConfigCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "config",
+		Short: "Manage contexts for several volantd hosts",
+		Long: `Manage contexts: named volantd hosts with their API key and TLS settings,
+stored in ~/.volant/config.yaml (or $VOLAR_CONFIG) readable only by you.
+
+Every command talks to the current context unless --context, VOLAR_CONTEXT,
+--api or VOLANT_API_BASE says otherwise. --context and VOLAR_CONTEXT pick a
+context by name; --api and VOLANT_API_BASE name a URL and bypass contexts.
+VOLANT_API_KEY is used when the selected context has no key.
+
+Examples:
+  volar config set-context prod --api https://volant.prod.example:7777 --api-key-env PROD_VOLANT_KEY --tls-ca-file ca.pem
+  volar config set-context lab --api unix:///run/volant.sock
+  volar config use-context prod
+  volar --context lab vms list`,
+	}
+	cmd.AddCommand(newConfigGetContextsCmd())
+	cmd.AddCommand(newConfigCurrentContextCmd())
+	cmd.AddCommand(newConfigUseContextCmd())
+	cmd.AddCommand(newConfigSetContextCmd())
+	cmd.AddCommand(newConfigDeleteContextCmd())
+	return cmd
+}
+
+// contextSummary is the structured output of `config get-contexts`. It says
+// how a context authenticates without printing its key.
+type contextSummary struct {
+	Name    string         `json:"name"`
+	API     string         `json:"api"`
+	Current bool           `json:"current"`
+	Auth    string         `json:"auth,omitempty"`
+	TLS     *cliconfig.TLS `json:"tls,omitempty"`
+}
+
+func newConfigGetContextsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "get-contexts",
+		Short: "List contexts; the current one is marked with *",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, _, err := loadCLIConfig()
+			if err != nil {
+				return err
+			}
+			summaries := make([]contextSummary, 0, len(cfg.Contexts))
+			for _, ctx := range cfg.Contexts {
+				summary := contextSummary{Name: ctx.Name, API: ctx.API, Current: ctx.Name == cfg.CurrentContext}
+				if ctx.TLS != (cliconfig.TLS{}) {
+					tls := ctx.TLS
+					summary.TLS = &tls
+				}
+				switch {
+				case ctx.APIKeyEnv != "":
+					summary.Auth = "api key from $" + ctx.APIKeyEnv
+				case ctx.APIKey != "":
+					summary.Auth = "api key"
+				}
+				summaries = append(summaries, summary)
+			}
+			if done, err := renderNames(cmd, summaries, func() []string {
+				names := make([]string, 0, len(summaries))
+				for _, summary := range summaries {
+					names = append(names, summary.Name)
+				}
+				return names
+			}); done {
+				return err
+			}
+			if len(summaries) == 0 {
+				fmt.Fprintln(cmd.OutOrStdout(), "No contexts defined; add one with \"volar config set-context\"")
+				return nil
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "%-8s %-20s %-40s %s\n", "CURRENT", "NAME", "API", "AUTH")
+			for _, summary := range summaries {
+				current := ""
+				if summary.Current {
+					current = "*"
+				}
+				fmt.Fprintf(cmd.OutOrStdout(), "%-8s %-20s %-40s %s\n", current, summary.Name, summary.API, summary.Auth)
+			}
+			return nil
+		},
+	}
+	return cmd
+}
+
+func newConfigCurrentContextCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "current-context",
+		Short: "Print the current context",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, _, err := loadCLIConfig()
+			if err != nil {
+				return err
+			}
+			if cfg.CurrentContext == "" {
+				return fmt.Errorf("no current context is set")
+			}
+			fmt.Fprintln(cmd.OutOrStdout(), cfg.CurrentContext)
+			return nil
+		},
+	}
+	return cmd
+}
+
+func newConfigUseContextCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "use-context <name>",
+		Short: "Make a context the current one",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, path, err := loadCLIConfig()
+			if err != nil {
+				return err
+			}
+			if _, ok := cfg.Context(args[0]); !ok {
+				return fmt.Errorf("context %q not found; \"volar config get-contexts\" lists them", args[0])
+			}
+			cfg.CurrentContext = args[0]
+			if err := cfg.Save(path); err != nil {
+				return err
+			}
+			infof(cmd, "Switched to context %s", args[0])
+			return nil
+		},
+	}
+	cmd.ValidArgsFunction = completeArgs(completeContextNames)
+	return cmd
+}
+
+func newConfigSetContextCmd() *cobra.Command {
+	var (
+		api         string
+		apiKey      string
+		apiKeyEnv   string
+		caFile      string
+		certFile    string
+		keyFile     string
+		serverName  string
+		insecure    bool
+		makeCurrent bool
+	)
+	cmd := &cobra.Command{
+		Use:   "set-context <name>",
+		Short: "Create a context or change the settings given",
+		Long: `Create a context or change the settings given on the command line; the
+others keep their values. A new context needs --api. Relative TLS file paths
+are stored as absolute paths.
+
+Prefer --api-key-env over --api-key: the key then stays in your environment
+or secret manager instead of the config file and shell history.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, path, err := loadCLIConfig()
+			if err != nil {
+				return err
+			}
+			ctx, exists := cfg.Context(args[0])
+			if !exists {
+				if !cmd.Flags().Changed("api") {
+					return fmt.Errorf("--api is required for a new context")
+				}
+				ctx.Name = args[0]
+			}
+			flags := cmd.Flags()
+			if flags.Changed("api") {
+				ctx.API = api
+			}
+			if flags.Changed("api-key") {
+				ctx.APIKey, ctx.APIKeyEnv = apiKey, ""
+			}
+			if flags.Changed("api-key-env") {
+				ctx.APIKey, ctx.APIKeyEnv = "", apiKeyEnv
+			}
+			for flag, field := range map[string]struct {
+				value  string
+				target *string
+			}{
+				"tls-ca-file":   {caFile, &ctx.TLS.CAFile},
+				"tls-cert-file": {certFile, &ctx.TLS.CertFile},
+				"tls-key-file":  {keyFile, &ctx.TLS.KeyFile},
+			} {
+				if !flags.Changed(flag) {
+					continue
+				}
+				value := field.value
+				if value != "" {
+					if value, err = filepath.Abs(value); err != nil {
+						return fmt.Errorf("--%s: %w", flag, err)
+					}
+				}
+				*field.target = value
+			}
+			if flags.Changed("tls-server-name") {
+				ctx.TLS.ServerName = serverName
+			}
+			if flags.Changed("tls-insecure-skip-verify") {
+				ctx.TLS.InsecureSkipVerify = insecure
+			}
+			if err := ctx.Validate(); err != nil {
+				return err
+			}
+			if _, err := ctx.TLS.ClientConfig(); err != nil {
+				return err
+			}
+
+			cfg.Set(ctx)
+			if makeCurrent {
+				cfg.CurrentContext = ctx.Name
+			}
+			if err := cfg.Save(path); err != nil {
+				return err
+			}
+			verb := "created"
+			if exists {
+				verb = "updated"
+			}
+			infof(cmd, "Context %s %s in %s", ctx.Name, verb, path)
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&api, "api", "", "volantd base URL (http, https or unix)")
+	cmd.Flags().StringVar(&apiKey, "api-key", "", "API key stored in the config file")
+	cmd.Flags().StringVar(&apiKeyEnv, "api-key-env", "", "Environment variable to read the API key from")
+	cmd.Flags().StringVar(&caFile, "tls-ca-file", "", "PEM CA bundle to verify the server with")
+	cmd.Flags().StringVar(&certFile, "tls-cert-file", "", "PEM client certificate for mutual TLS")
+	cmd.Flags().StringVar(&keyFile, "tls-key-file", "", "PEM client key for mutual TLS")
+	cmd.Flags().StringVar(&serverName, "tls-server-name", "", "Server name to verify instead of the API host")
+	cmd.Flags().BoolVar(&insecure, "tls-insecure-skip-verify", false, "Skip server certificate verification (testing only)")
+	cmd.Flags().BoolVar(&makeCurrent, "use", false, "Also make the context the current one")
+	cmd.ValidArgsFunction = completeArgs(completeContextNames)
+	return cmd
+}
+
+func newConfigDeleteContextCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "delete-context <name>",
+		Short: "Delete a context",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, path, err := loadCLIConfig()
+			if err != nil {
+				return err
+			}
+			if !cfg.Delete(args[0]) {
+				return fmt.Errorf("context %q not found", args[0])
+			}
+			if err := cfg.Save(path); err != nil {
+				return err
+			}
+			infof(cmd, "Context %s deleted\n", args[0])
+			return nil
+		},
+	}
+	cmd.ValidArgsFunction = completeArgs(completeContextNames)
+	return cmd
+}
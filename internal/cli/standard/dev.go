@@ -0,0 +1,355 @@
+// Copyright (c) 2025 HYPR. PTE. LTD.
+//
+// Business Source License 1.1
+// See LICENSE file in the project root for details.
+
+package standard
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/signal"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/volantvm/volant/pkg/volantclient"
+)
+
+const (
+	devSyncIgnoreFile   = ".volarignore"
+	devSyncMaxBatchSize = 8 << 20
+	// devSyncMaxFileSize is the largest file pushed. The agent caps a sync
+	// request at 64MB and contents travel base64 encoded, so larger files
+	// are skipped and reported instead.
+	devSyncMaxFileSize = 32 << 20
+)
+
+var defaultDevSyncIgnores = []string{".git", devSyncIgnoreFile}
+
+func newDevCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "dev",
+		Short: "Development workflow helpers",
+	}
+	cmd.AddCommand(newDevSyncCmd())
+	return cmd
+}
+
+func newDevSyncCmd() *cobra.Command {
+	var ignores []string
+	var interval time.Duration
+	var restart bool
+	var once bool
+
+	cmd := &cobra.Command{
+		Use:   "sync <vm> <local:remote>...",
+		Short: "Watch local directories and push changes into a VM",
+		Long: `Watch local directories and push changes into a running VM through its agent.
+
+Each mapping pairs a local directory with an absolute path in the guest.
+Changes are detected by polling, batched per interval and applied atomically
+by the agent. The VM must be booted in dev mode with a sync root, and remote
+paths must lie under it. Files over 32MB are skipped and reported. Patterns
+from --ignore and a .volarignore file in each local directory are skipped;
+.git is always ignored.
+
+Examples:
+  volar dev sync myvm ./src:/app
+  volar dev sync myvm ./src:/app ./static:/srv/static --ignore '*.tmp' --restart`,
+		Args: cobra.MinimumNArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			vmName := args[0]
+			mappings := make([]*devSyncMapping, 0, len(args)-1)
+			for _, raw := range args[1:] {
+				mapping, err := parseDevSyncMapping(raw)
+				if err != nil {
+					return err
+				}
+				mapping.ignores = append(mapping.ignores, ignores...)
+				mappings = append(mappings, mapping)
+			}
+			if interval <= 0 {
+				return fmt.Errorf("--interval must be positive")
+			}
+
+			api, err := clientFromCmd(cmd)
+			if err != nil {
+				return err
+			}
+
+			ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+			defer stop()
+
+			for {
+				for _, mapping := range mappings {
+					result, err := mapping.sync(ctx, api, vmName, restart)
+					if err != nil {
+						if ctx.Err() != nil {
+							return nil
+						}
+						fmt.Fprintf(cmd.ErrOrStderr(), "sync %s: %v\n", mapping, err)
+						continue
+					}
+					for _, rel := range result.skipped {
+						fmt.Fprintf(cmd.ErrOrStderr(), "sync %s: skipped %s: larger than %d MiB\n", mapping, rel, devSyncMaxFileSize>>20)
+					}
+					if result.Written > 0 || result.Deleted > 0 {
+						infof(cmd, "%s %s: %d written, %d deleted", time.Now().Format("15:04:05"), mapping, result.Written, result.Deleted)
+					}
+				}
+				if once {
+					return nil
+				}
+				select {
+				case <-ctx.Done():
+					return nil
+				case <-time.After(interval):
+				}
+			}
+		},
+	}
+
+	cmd.Flags().StringSliceVar(&ignores, "ignore", nil, "Glob patterns to skip (matched against names and relative paths)")
+	cmd.Flags().DurationVar(&interval, "interval", 500*time.Millisecond, "Polling interval used to detect and batch changes")
+	cmd.Flags().BoolVar(&restart, "restart", false, "Restart the plugin workload after each applied batch")
+	cmd.Flags().BoolVar(&once, "once", false, "Perform a single sync and exit")
+	cmd.ValidArgsFunction = completeArgs(completeVMNames)
+	return cmd
+}
+
+type devSyncFileState struct {
+	size    int64
+	modTime time.Time
+	mode    os.FileMode
+}
+
+type devSyncMapping struct {
+	local   string
+	remote  string
+	ignores []string
+	synced  map[string]devSyncFileState
+	// skipped holds files too large to push, so each is reported once per
+	// change.
+	skipped map[string]devSyncFileState
+}
+
+// devSyncResult is what one pass applied, plus the files skipped for size.
+type devSyncResult struct {
+	volantclient.SyncResult
+	skipped []string
+}
+
+func parseDevSyncMapping(raw string) (*devSyncMapping, error) {
+	idx := strings.LastIndex(raw, ":")
+	if idx <= 0 || idx == len(raw)-1 {
+		return nil, fmt.Errorf("invalid mapping %q (expected <local>:<remote>)", raw)
+	}
+	local, remote := raw[:idx], raw[idx+1:]
+	if !path.IsAbs(remote) {
+		return nil, fmt.Errorf("invalid mapping %q: remote path must be absolute", raw)
+	}
+	absLocal, err := filepath.Abs(local)
+	if err != nil {
+		return nil, fmt.Errorf("resolve %s: %w", local, err)
+	}
+	info, err := os.Stat(absLocal)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("%s is not a directory", local)
+	}
+	ignores := append([]string(nil), defaultDevSyncIgnores...)
+	fileIgnores, err := readDevSyncIgnoreFile(filepath.Join(absLocal, devSyncIgnoreFile))
+	if err != nil {
+		return nil, err
+	}
+	ignores = append(ignores, fileIgnores...)
+	return &devSyncMapping{
+		local:   absLocal,
+		remote:  path.Clean(remote),
+		ignores: ignores,
+		synced:  make(map[string]devSyncFileState),
+		skipped: make(map[string]devSyncFileState),
+	}, nil
+}
+
+func (m *devSyncMapping) String() string {
+	return m.local + ":" + m.remote
+}
+
+// sync scans the local tree and pushes everything that changed since the last
+// successful push, splitting large change sets into several requests.
+func (m *devSyncMapping) sync(ctx context.Context, api *volantclient.Client, vmName string, restart bool) (devSyncResult, error) {
+	var total devSyncResult
+
+	current, err := m.scan()
+	if err != nil {
+		return total, err
+	}
+
+	var changed, deleted []string
+	for rel, state := range current {
+		if prev, ok := m.synced[rel]; !ok || prev != state {
+			changed = append(changed, rel)
+		}
+	}
+	for rel := range m.synced {
+		if _, ok := current[rel]; !ok {
+			deleted = append(deleted, rel)
+		}
+	}
+	for rel := range m.skipped {
+		if _, ok := current[rel]; !ok {
+			delete(m.skipped, rel)
+		}
+	}
+	if len(changed) == 0 && len(deleted) == 0 {
+		return total, nil
+	}
+	sort.Strings(changed)
+	sort.Strings(deleted)
+
+	batch := volantclient.SyncBatch{Root: m.remote, Deletes: deleted}
+	batchSize := 0
+	pending := make(map[string]devSyncFileState)
+
+	flush := func(last bool) error {
+		if len(batch.Files) == 0 && len(batch.Deletes) == 0 {
+			return nil
+		}
+		batch.RestartWorkload = restart && last
+		result, err := api.SyncFiles(ctx, vmName, batch)
+		if err != nil {
+			return err
+		}
+		for rel, state := range pending {
+			m.synced[rel] = state
+		}
+		for _, rel := range batch.Deletes {
+			delete(m.synced, rel)
+		}
+		total.Written += result.Written
+		total.Deleted += result.Deleted
+		total.Restarted = total.Restarted || result.Restarted
+		batch = volantclient.SyncBatch{Root: m.remote}
+		batchSize = 0
+		pending = make(map[string]devSyncFileState)
+		return nil
+	}
+
+	for _, rel := range changed {
+		state := current[rel]
+		if state.size > devSyncMaxFileSize {
+			m.skip(&total, rel, state)
+			continue
+		}
+		content, err := os.ReadFile(filepath.Join(m.local, filepath.FromSlash(rel)))
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return total, err
+		}
+		if len(content) > devSyncMaxFileSize {
+			m.skip(&total, rel, state)
+			continue
+		}
+		delete(m.skipped, rel)
+		if batchSize > 0 && batchSize+len(content) > devSyncMaxBatchSize {
+			if err := flush(false); err != nil {
+				return total, err
+			}
+		}
+		batch.Files = append(batch.Files, volantclient.SyncFile{Path: rel, Mode: state.mode, Content: content})
+		batchSize += len(content)
+		pending[rel] = state
+	}
+	return total, flush(true)
+}
+
+// skip records a file too large to push, reporting it unless it was already
+// skipped unchanged.
+func (m *devSyncMapping) skip(total *devSyncResult, rel string, state devSyncFileState) {
+	if prev, ok := m.skipped[rel]; !ok || prev != state {
+		total.skipped = append(total.skipped, rel)
+	}
+	m.skipped[rel] = state
+}
+
+func (m *devSyncMapping) scan() (map[string]devSyncFileState, error) {
+	states := make(map[string]devSyncFileState)
+	err := filepath.WalkDir(m.local, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if p == m.local {
+			return nil
+		}
+		rel, err := filepath.Rel(m.local, p)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		if m.ignored(rel) {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		states[rel] = devSyncFileState{size: info.Size(), modTime: info.ModTime(), mode: info.Mode().Perm()}
+		return nil
+	})
+	return states, err
+}
+
+func (m *devSyncMapping) ignored(rel string) bool {
+	base := path.Base(rel)
+	for _, pattern := range m.ignores {
+		if ok, _ := path.Match(pattern, base); ok {
+			return true
+		}
+		if ok, _ := path.Match(pattern, rel); ok {
+			return true
+		}
+	}
+	return false
+}
+
+func readDevSyncIgnoreFile(name string) ([]string, error) {
+	file, err := os.Open(name)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer file.Close()
+
+	var patterns []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		patterns = append(patterns, strings.TrimSuffix(line, "/"))
+	}
+	return patterns, scanner.Err()
+}
@@ -0,0 +1,150 @@
+// Copyright (c) 2025 HYPR. PTE. LTD.
+//
+// Business Source License 1.1
+// See LICENSE file in the project root for details.
+
+package standard
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/volantvm/volant/pkg/volantclient"
+)
+
+func writeDevSyncFile(t *testing.T, dir, rel string, size int) {
+	t.Helper()
+	name := filepath.Join(dir, filepath.FromSlash(rel))
+	if err := os.MkdirAll(filepath.Dir(name), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(name, []byte(strings.Repeat("x", size)), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestDevSyncIgnores(t *testing.T) {
+	dir := t.TempDir()
+	for _, rel := range []string{"main.go", "notes.tmp", "build/out.bin", "web/app.js", "web/cache/page", ".git/HEAD"} {
+		writeDevSyncFile(t, dir, rel, 1)
+	}
+	if err := os.WriteFile(filepath.Join(dir, devSyncIgnoreFile), []byte("# generated\nbuild/\n\nweb/cache\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	mapping, err := parseDevSyncMapping(dir + ":/srv/app")
+	if err != nil {
+		t.Fatalf("parse mapping: %v", err)
+	}
+	mapping.ignores = append(mapping.ignores, "*.tmp")
+
+	states, err := mapping.scan()
+	if err != nil {
+		t.Fatalf("scan: %v", err)
+	}
+	var got []string
+	for rel := range states {
+		got = append(got, rel)
+	}
+	sort.Strings(got)
+	if want := []string{"main.go", "web/app.js"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("scanned %v, want %v", got, want)
+	}
+
+	if _, err := parseDevSyncMapping(dir + ":relative"); err == nil {
+		t.Fatal("expected a relative remote path refused")
+	}
+}
+
+func TestDevSyncBatches(t *testing.T) {
+	var (
+		mu      sync.Mutex
+		batches []volantclient.SyncBatch
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/vms/web-1/agent/v1/dev/sync" {
+			http.NotFound(w, r)
+			return
+		}
+		var batch volantclient.SyncBatch
+		if err := json.NewDecoder(r.Body).Decode(&batch); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		mu.Lock()
+		batches = append(batches, batch)
+		mu.Unlock()
+		_ = json.NewEncoder(w).Encode(volantclient.SyncResult{Written: len(batch.Files), Deleted: len(batch.Deletes), Restarted: batch.RestartWorkload})
+	}))
+	defer server.Close()
+	api, err := volantclient.New(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dir := t.TempDir()
+	for _, rel := range []string{"a", "b", "c"} {
+		writeDevSyncFile(t, dir, rel, 5<<20)
+	}
+	writeDevSyncFile(t, dir, "small", 10)
+	// Only the size is checked before skipping, so a sparse file will do.
+	large, err := os.Create(filepath.Join(dir, "large.iso"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := large.Truncate(devSyncMaxFileSize + 1); err != nil {
+		t.Fatal(err)
+	}
+	_ = large.Close()
+
+	mapping, err := parseDevSyncMapping(dir + ":/srv/app")
+	if err != nil {
+		t.Fatalf("parse mapping: %v", err)
+	}
+	ctx := context.Background()
+	result, err := mapping.sync(ctx, api, "web-1", true)
+	if err != nil {
+		t.Fatalf("sync: %v", err)
+	}
+	if result.Written != 4 || !result.Restarted || !reflect.DeepEqual(result.skipped, []string{"large.iso"}) {
+		t.Fatalf("unexpected result %+v", result)
+	}
+	if len(batches) != 3 {
+		t.Fatalf("expected the change set split into 3 batches, got %d", len(batches))
+	}
+	for i, batch := range batches {
+		if batch.Root != "/srv/app" || batch.RestartWorkload != (i == len(batches)-1) {
+			t.Fatalf("batch %d: root %s restart %v; only the last batch restarts", i, batch.Root, batch.RestartWorkload)
+		}
+		size := 0
+		for _, file := range batch.Files {
+			size += len(file.Content)
+		}
+		if size > devSyncMaxBatchSize && len(batch.Files) > 1 {
+			t.Fatalf("batch %d carries %d bytes in %d files", i, size, len(batch.Files))
+		}
+	}
+
+	batches = nil
+	if err := os.Remove(filepath.Join(dir, "b")); err != nil {
+		t.Fatal(err)
+	}
+	result, err = mapping.sync(ctx, api, "web-1", false)
+	if err != nil {
+		t.Fatalf("resync: %v", err)
+	}
+	if result.Deleted != 1 || result.Written != 0 || len(result.skipped) != 0 {
+		t.Fatalf("expected only the deletion pushed and the skipped file not reported again, got %+v", result)
+	}
+	if len(batches) != 1 || !reflect.DeepEqual(batches[0].Deletes, []string{"b"}) {
+		t.Fatalf("unexpected batches %+v", batches)
+	}
+}
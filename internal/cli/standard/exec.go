@@ -0,0 +1,98 @@
+// Copyright (c) 2025 HYPR. PTE. LTD.
+//
+// Business Source License 1.1
+// See LICENSE file in the project root for details.
+
+package standard
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/volantvm/volant/internal/pluginspec"
+)
+
+// ExitError asks main to exit with Code without printing anything, as when a
+// remote command failed and has already reported why.
+type ExitError struct {
+	Code int
+}
+
+func (e *ExitError) Error() string { return fmt.Sprintf("exit status %d", e.Code) }
+
+func newVMsExecCmd() *cobra.Command {
+	var (
+		envPairs    []string
+		workdir     string
+		timeout     time.Duration
+		attachStdin bool
+	)
+	cmd := &cobra.Command{
+		Use:   "exec <name> -- <command> [args...]",
+		Short: "Run a command in a microVM",
+		Long: `Run a command in a running microVM through its agent, streaming its output.
+
+The plugin manifest's exec allowlist decides which binaries may run. volar
+exits with the command's exit code.
+
+Examples:
+  volar vms exec web-1 -- uname -a
+  volar vms exec web-1 --timeout 5m -- /usr/local/bin/migrate
+  cat dump.sql | volar vms exec db-1 -i -- psql app`,
+		Args: cobra.MinimumNArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			req := pluginspec.ExecRequest{Command: args[1:], WorkDir: workdir, TimeoutMs: timeout.Milliseconds()}
+			if len(envPairs) > 0 {
+				req.Env = make(map[string]string, len(envPairs))
+				for _, pair := range envPairs {
+					key, value, ok := strings.Cut(pair, "=")
+					if !ok || key == "" {
+						return fmt.Errorf("invalid --env %q: expected KEY=VALUE", pair)
+					}
+					req.Env[key] = value
+				}
+			}
+			if err := req.Validate(); err != nil {
+				return err
+			}
+			api, err := clientFromCmd(cmd)
+			if err != nil {
+				return err
+			}
+			var stdin io.Reader
+			if attachStdin {
+				stdin = cmd.InOrStdin()
+			}
+			result, err := api.ExecStream(cmd.Context(), args[0], req, stdin, cmd.OutOrStdout(), cmd.ErrOrStderr())
+			if err != nil {
+				return err
+			}
+			if result.Code != "" {
+				return fmt.Errorf("%s (%s)", result.Error, result.Code)
+			}
+			if result.TimedOut {
+				fmt.Fprintf(cmd.ErrOrStderr(), "volar: %s\n", result.Error)
+			}
+			if result.ExitCode != 0 {
+				code := result.ExitCode
+				if code < 0 {
+					code = 137
+				}
+				cmd.SilenceErrors = true
+				cmd.SilenceUsage = true
+				return &ExitError{Code: code}
+			}
+			return nil
+		},
+	}
+	cmd.Flags().StringArrayVarP(&envPairs, "env", "e", nil, "Environment variable for the command as KEY=VALUE (repeatable)")
+	cmd.Flags().StringVarP(&workdir, "workdir", "w", "", "Working directory in the guest (defaults to the workload's)")
+	cmd.Flags().DurationVar(&timeout, "timeout", 0, "Kill the command after this long (default 1m, capped by the manifest)")
+	cmd.Flags().BoolVarP(&attachStdin, "stdin", "i", false, "Forward standard input to the command")
+	cmd.ValidArgsFunction = completeArgs(completeVMNames)
+	return cmd
+}
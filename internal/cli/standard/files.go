@@ -0,0 +1,134 @@
+// Copyright (c) 2025 HYPR. PTE. LTD.
+//
+// Business Source License 1.1
+// See LICENSE file in the project root for details.
+
+package standard
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/spf13/cobra"
+)
+
+func newVMsPushCmd() *cobra.Command {
+	var mode string
+	cmd := &cobra.Command{
+		Use:   "push <name> <local-file> <guest-path>",
+		Short: "Copy a file into a microVM",
+		Long: `Copy a local file into a running microVM through its agent.
+
+The guest verifies the file's SHA-256 before replacing anything at the guest
+path, and creates missing parent directories.
+
+Examples:
+  volar vms push web-1 ./app.conf /etc/app/app.conf
+  volar vms push web-1 ./run.sh /usr/local/bin/run.sh --mode 0755`,
+		Args: cobra.ExactArgs(3),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			name, local, guestPath := args[0], args[1], args[2]
+			file, err := os.Open(local)
+			if err != nil {
+				return err
+			}
+			defer file.Close()
+			info, err := file.Stat()
+			if err != nil {
+				return err
+			}
+			if !info.Mode().IsRegular() {
+				return fmt.Errorf("%s is not a regular file", local)
+			}
+			if mode == "" {
+				mode = fmt.Sprintf("%04o", info.Mode().Perm())
+			} else if _, err := strconv.ParseUint(mode, 8, 32); err != nil {
+				return fmt.Errorf("invalid mode %q: expected octal permission bits", mode)
+			}
+
+			hash := sha256.New()
+			if _, err := io.Copy(hash, file); err != nil {
+				return fmt.Errorf("read %s: %w", local, err)
+			}
+			if _, err := file.Seek(0, io.SeekStart); err != nil {
+				return err
+			}
+
+			api, err := clientFromCmd(cmd)
+			if err != nil {
+				return err
+			}
+			written, err := api.UploadFile(cmd.Context(), name, guestPath, file, info.Size(), hex.EncodeToString(hash.Sum(nil)), mode)
+			if err != nil {
+				return err
+			}
+			return renderResult(cmd, written, "%s -> %s:%s (%d bytes, sha256 %s)", local, name, written.Path, written.Size, written.SHA256)
+		},
+	}
+	cmd.Flags().StringVar(&mode, "mode", "", "Octal permission bits of the guest file (defaults to the local file's)")
+	cmd.ValidArgsFunction = completeArgs(completeVMNames, nil)
+	return cmd
+}
+
+func newVMsPullCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "pull <name> <guest-path> <local-file>",
+		Short: "Copy a file out of a microVM",
+		Long: `Copy a file from a running microVM through its agent.
+
+The download is written next to the local file and only renamed over it once
+its SHA-256 matches the guest's.
+
+Examples:
+  volar vms pull web-1 /var/log/app.log ./app.log`,
+		Args: cobra.ExactArgs(3),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			name, guestPath, local := args[0], args[1], args[2]
+			api, err := clientFromCmd(cmd)
+			if err != nil {
+				return err
+			}
+			tmp, err := os.CreateTemp(filepath.Dir(local), "."+filepath.Base(local)+".volar-*")
+			if err != nil {
+				return err
+			}
+			committed := false
+			defer func() {
+				if !committed {
+					_ = tmp.Close()
+					_ = os.Remove(tmp.Name())
+				}
+			}()
+
+			hash := sha256.New()
+			info, err := api.DownloadFile(cmd.Context(), name, guestPath, io.MultiWriter(tmp, hash))
+			if err != nil {
+				return err
+			}
+			sum := hex.EncodeToString(hash.Sum(nil))
+			if info.SHA256 != "" && info.SHA256 != sum {
+				return fmt.Errorf("checksum mismatch: received sha256 %s, guest reported %s", sum, info.SHA256)
+			}
+			if perm, err := strconv.ParseUint(info.Mode, 8, 32); err == nil {
+				if err := tmp.Chmod(os.FileMode(perm).Perm()); err != nil {
+					return err
+				}
+			}
+			if err := tmp.Close(); err != nil {
+				return err
+			}
+			if err := os.Rename(tmp.Name(), local); err != nil {
+				return err
+			}
+			committed = true
+			return renderResult(cmd, info, "%s:%s -> %s (%d bytes, sha256 %s)", name, guestPath, local, info.Size, sum)
+		},
+	}
+	cmd.ValidArgsFunction = completeArgs(completeVMNames, noCandidates, nil)
+	return cmd
+}
@@ -0,0 +1,143 @@
+// Copyright (c) 2025 HYPR. PTE. LTD.
+//
+// Business Source License 1.1
+// See LICENSE file in the project root for details.
+
+package standard
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/volantvm/volant/internal/server/labels"
+	"github.com/volantvm/volant/pkg/volantclient"
+)
+
+// parseLabelFlags turns repeated KEY=VALUE flags into a label set.
+func parseLabelFlags(values []string) (map[string]string, error) {
+	if len(values) == 0 {
+		return nil, nil
+	}
+	out := make(map[string]string, len(values))
+	for _, value := range values {
+		key, val, ok := strings.Cut(value, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid --label %q (expected KEY=VALUE)", value)
+		}
+		out[strings.TrimSpace(key)] = strings.TrimSpace(val)
+	}
+	if err := labels.Validate(out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func newVMsLabelCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "label <name> KEY=VALUE... KEY-...",
+		Short: "Add, change or remove microVM labels",
+		Long: `Set labels with KEY=VALUE and remove them with KEY-.
+
+Examples:
+  volar vms label web-1 env=prod tier=frontend
+  volar vms label web-1 canary-`,
+		Args: cobra.MinimumNArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			patch := make(map[string]*string, len(args)-1)
+			for _, arg := range args[1:] {
+				if key, val, ok := strings.Cut(arg, "="); ok {
+					val = strings.TrimSpace(val)
+					patch[strings.TrimSpace(key)] = &val
+					continue
+				}
+				if key, ok := strings.CutSuffix(arg, "-"); ok && key != "" {
+					patch[key] = nil
+					continue
+				}
+				return fmt.Errorf("invalid label %q (expected KEY=VALUE or KEY-)", arg)
+			}
+			api, err := clientFromCmd(cmd)
+			if err != nil {
+				return err
+			}
+			ctx, cancel := context.WithTimeout(cmd.Context(), 15*time.Second)
+			defer cancel()
+
+			vm, err := api.UpdateVMLabels(ctx, args[0], patch)
+			if err != nil {
+				return err
+			}
+			if done, err := render(cmd, vm); done || quiet(cmd) {
+				return err
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "VM %s labels updated\n", vm.Name)
+			for _, key := range labels.Keys(vm.Labels) {
+				fmt.Fprintf(cmd.OutOrStdout(), "  %s=%s\n", key, vm.Labels[key])
+			}
+			return nil
+		},
+	}
+	cmd.ValidArgsFunction = completeArgs(completeVMNames)
+	return cmd
+}
+
+func newVMsBulkCmd() *cobra.Command {
+	var selector string
+	cmd := &cobra.Command{
+		Use:   "bulk <start|stop|restart|delete>",
+		Short: "Start, stop, restart or delete every microVM matching a label selector",
+		Long: `Run a lifecycle action on every microVM whose labels match --selector.
+A selector is required so a typo cannot act on every VM on the host.
+
+Examples:
+  volar vms bulk stop --selector env=staging
+  volar vms bulk delete --selector volant.deployment=web,canary`,
+		Args:      cobra.ExactArgs(1),
+		ValidArgs: []string{"start", "stop", "restart", "delete"},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if strings.TrimSpace(selector) == "" {
+				return fmt.Errorf("--selector is required")
+			}
+			api, err := clientFromCmd(cmd)
+			if err != nil {
+				return err
+			}
+			ctx, cancel := context.WithTimeout(cmd.Context(), 5*time.Minute)
+			defer cancel()
+
+			resp, err := api.BulkVMAction(ctx, args[0], selector)
+			if err != nil {
+				return err
+			}
+			if done, err := render(cmd, resp); done {
+				if err != nil {
+					return err
+				}
+			} else if !quiet(cmd) {
+				printBulkResults(cmd.OutOrStdout(), resp)
+			}
+			if resp.Failed > 0 {
+				return fmt.Errorf("%d of %d VMs failed", resp.Failed, resp.Matched)
+			}
+			return nil
+		},
+	}
+	cmd.Flags().StringVarP(&selector, "selector", "l", "", "Label selector, e.g. env=prod,tier!=db")
+	return cmd
+}
+
+func printBulkResults(out io.Writer, resp *volantclient.BulkVMResponse) {
+	for _, result := range resp.Results {
+		if result.Error != "" {
+			fmt.Fprintf(out, "%-20s failed: %s\n", result.Name, result.Error)
+			continue
+		}
+		fmt.Fprintf(out, "%-20s ok\n", result.Name)
+	}
+	fmt.Fprintf(out, "%s: %d matched, %d succeeded, %d failed\n", resp.Action, resp.Matched, resp.Succeeded, resp.Failed)
+}
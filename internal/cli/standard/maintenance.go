@@ -0,0 +1,246 @@
+// Copyright (c) 2025 HYPR. PTE. LTD.
+//
+// Business Source License 1.1
+// See LICENSE file in the project root for details.
+
+package standard
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/volantvm/volant/pkg/volantclient"
+)
+
+func newMaintenanceCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "maintenance",
+		Short: "Manage maintenance windows that pause reconciliation",
+	}
+	cmd.AddCommand(newMaintenanceListCmd())
+	cmd.AddCommand(newMaintenanceCreateCmd())
+	cmd.AddCommand(newMaintenanceShowCmd())
+	cmd.AddCommand(newMaintenanceEndCmd())
+	cmd.AddCommand(newMaintenanceDeleteCmd())
+	return cmd
+}
+
+func newMaintenanceListCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List maintenance windows",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			api, err := clientFromCmd(cmd)
+			if err != nil {
+				return err
+			}
+			ctx, cancel := context.WithTimeout(cmd.Context(), 15*time.Second)
+			defer cancel()
+
+			windows, err := api.ListMaintenanceWindows(ctx)
+			if err != nil {
+				return err
+			}
+			if done, err := renderNames(cmd, windows, func() []string {
+				names := make([]string, 0, len(windows))
+				for _, window := range windows {
+					names = append(names, window.Name)
+				}
+				return names
+			}); done {
+				return err
+			}
+			if len(windows) == 0 {
+				fmt.Fprintln(cmd.OutOrStdout(), "No maintenance windows found")
+				return nil
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "%-20s %-28s %-17s %-17s %-7s\n", "NAME", "SCOPE", "STARTS", "ENDS", "ACTIVE")
+			for _, window := range windows {
+				fmt.Fprintf(cmd.OutOrStdout(), "%-20s %-28s %-17s %-17s %-7t\n", window.Name, maintenanceScope(window),
+					window.StartsAt.Local().Format("2006-01-02 15:04"), window.EndsAt.Local().Format("2006-01-02 15:04"), window.Active)
+			}
+			return nil
+		},
+	}
+	return cmd
+}
+
+func maintenanceScope(window volantclient.MaintenanceWindow) string {
+	if window.Target == "" {
+		return window.Scope
+	}
+	return window.Scope + "/" + window.Target
+}
+
+func newMaintenanceCreateCmd() *cobra.Command {
+	var vmName, deploymentName, pluginName string
+	var start, end, reason string
+	var duration time.Duration
+	cmd := &cobra.Command{
+		Use:   "create <name>",
+		Short: "Create a maintenance window",
+		Long: `Create a maintenance window. While it is open, volantd does not reconcile
+deployments after replicas exit or are deleted, and scheduled actions on the
+covered targets are held. Held actions are recorded and replayed once the
+window closes. Without --vm, --deployment or --plugin the window covers
+everything. Times use RFC 3339; --start defaults to now.
+
+Examples:
+  volar maintenance create host-patch --duration 2h --reason "kernel update"
+  volar maintenance create web-migration --deployment web --start 2025-03-01T02:00:00Z --end 2025-03-01T04:00:00Z`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			req := volantclient.CreateMaintenanceWindowRequest{Name: args[0], Scope: "all", Reason: reason}
+			targets := 0
+			for kind, value := range map[string]string{"vm": vmName, "deployment": deploymentName, "plugin": pluginName} {
+				if value != "" {
+					req.Scope, req.Target = kind, value
+					targets++
+				}
+			}
+			if targets > 1 {
+				return fmt.Errorf("at most one of --vm, --deployment or --plugin may be set")
+			}
+			if start != "" {
+				parsed, err := time.Parse(time.RFC3339, start)
+				if err != nil {
+					return fmt.Errorf("invalid --start: %w", err)
+				}
+				req.StartsAt = &parsed
+			}
+			switch {
+			case end != "" && duration > 0:
+				return fmt.Errorf("set either --end or --duration")
+			case end != "":
+				parsed, err := time.Parse(time.RFC3339, end)
+				if err != nil {
+					return fmt.Errorf("invalid --end: %w", err)
+				}
+				req.EndsAt = &parsed
+			case duration > 0:
+				req.Duration = duration.String()
+			default:
+				return fmt.Errorf("--end or --duration is required")
+			}
+
+			api, err := clientFromCmd(cmd)
+			if err != nil {
+				return err
+			}
+			ctx, cancel := context.WithTimeout(cmd.Context(), 15*time.Second)
+			defer cancel()
+
+			window, err := api.CreateMaintenanceWindow(ctx, req)
+			if err != nil {
+				return err
+			}
+			return renderResult(cmd, window, "Maintenance window %s created (%s until %s)", window.Name, maintenanceScope(*window), window.EndsAt.Local().Format("2006-01-02 15:04"))
+		},
+	}
+	cmd.Flags().StringVar(&vmName, "vm", "", "Limit the window to a VM")
+	cmd.Flags().StringVar(&deploymentName, "deployment", "", "Limit the window to a deployment")
+	cmd.Flags().StringVar(&pluginName, "plugin", "", "Limit the window to a plugin's VMs")
+	cmd.Flags().StringVar(&start, "start", "", "Start time (RFC 3339, default now)")
+	cmd.Flags().StringVar(&end, "end", "", "End time (RFC 3339)")
+	cmd.Flags().DurationVar(&duration, "duration", 0, "Window length, e.g. 90m")
+	_ = cmd.RegisterFlagCompletionFunc("vm", completeFlag(completeVMNames))
+	_ = cmd.RegisterFlagCompletionFunc("deployment", completeFlag(completeDeploymentNames))
+	_ = cmd.RegisterFlagCompletionFunc("plugin", completeFlag(completePluginNames))
+	cmd.Flags().StringVar(&reason, "reason", "", "Why the window exists")
+	return cmd
+}
+
+func newMaintenanceShowCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "show <name>",
+		Short: "Show a maintenance window and the actions it held",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			api, err := clientFromCmd(cmd)
+			if err != nil {
+				return err
+			}
+			ctx, cancel := context.WithTimeout(cmd.Context(), 15*time.Second)
+			defer cancel()
+
+			window, err := api.GetMaintenanceWindow(ctx, args[0])
+			if err != nil {
+				return err
+			}
+			if done, err := render(cmd, window); done {
+				return err
+			}
+			out := cmd.OutOrStdout()
+			fmt.Fprintf(out, "Name:    %s\n", window.Name)
+			fmt.Fprintf(out, "Scope:   %s\n", maintenanceScope(*window))
+			fmt.Fprintf(out, "Window:  %s - %s\n", window.StartsAt.Local().Format("2006-01-02 15:04"), window.EndsAt.Local().Format("2006-01-02 15:04"))
+			fmt.Fprintf(out, "Active:  %t\n", window.Active)
+			if window.Reason != "" {
+				fmt.Fprintf(out, "Reason:  %s\n", window.Reason)
+			}
+			if len(window.Intents) == 0 {
+				fmt.Fprintln(out, "No actions held")
+				return nil
+			}
+			fmt.Fprintf(out, "\n%-11s %-20s %-10s %-6s %s\n", "SOURCE", "TARGET", "ACTION", "COUNT", "OUTCOME")
+			for _, intent := range window.Intents {
+				outcome := "pending"
+				if intent.ReplayedAt != nil {
+					outcome = intent.Outcome
+				}
+				fmt.Fprintf(out, "%-11s %-20s %-10s %-6d %s\n", intent.Source, intent.Target, intent.Action, intent.Count, outcome)
+			}
+			return nil
+		},
+	}
+	return cmd
+}
+
+func newMaintenanceEndCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "end <name>",
+		Short: "Close a maintenance window now and replay held actions",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			api, err := clientFromCmd(cmd)
+			if err != nil {
+				return err
+			}
+			ctx, cancel := context.WithTimeout(cmd.Context(), 5*time.Minute)
+			defer cancel()
+
+			window, err := api.EndMaintenanceWindow(ctx, args[0])
+			if err != nil {
+				return err
+			}
+			return renderResult(cmd, window, "Maintenance window %s ended", args[0])
+		},
+	}
+	return cmd
+}
+
+func newMaintenanceDeleteCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "delete <name>",
+		Short: "Delete a closed maintenance window and its history",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			api, err := clientFromCmd(cmd)
+			if err != nil {
+				return err
+			}
+			ctx, cancel := context.WithTimeout(cmd.Context(), 15*time.Second)
+			defer cancel()
+
+			if err := api.DeleteMaintenanceWindow(ctx, args[0]); err != nil {
+				return err
+			}
+			infof(cmd, "Maintenance window %s deleted", args[0])
+			return nil
+		},
+	}
+	return cmd
+}
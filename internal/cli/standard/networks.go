@@ -0,0 +1,177 @@
+// Copyright (c) 2025 HYPR. PTE. LTD.
+//
+// Business Source License 1.1
+// See LICENSE file in the project root for details.
+
+package standard
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/volantvm/volant/pkg/volantclient"
+)
+
+func newNetworksCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "networks",
+		Aliases: []string{"network"},
+		Short:   "Manage user-defined networks",
+	}
+	cmd.AddCommand(newNetworksListCmd())
+	cmd.AddCommand(newNetworksCreateCmd())
+	cmd.AddCommand(newNetworksShowCmd())
+	cmd.AddCommand(newNetworksDeleteCmd())
+	return cmd
+}
+
+func newNetworksListCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List networks",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			api, err := clientFromCmd(cmd)
+			if err != nil {
+				return err
+			}
+			ctx, cancel := context.WithTimeout(cmd.Context(), 15*time.Second)
+			defer cancel()
+
+			networks, err := api.ListNetworks(ctx)
+			if err != nil {
+				return err
+			}
+			if done, err := renderNames(cmd, networks, func() []string {
+				names := make([]string, 0, len(networks))
+				for _, network := range networks {
+					names = append(names, network.Name)
+				}
+				return names
+			}); done {
+				return err
+			}
+			if len(networks) == 0 {
+				fmt.Fprintln(cmd.OutOrStdout(), "No networks found")
+				return nil
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "%-14s %-16s %-20s %-16s %-8s\n", "NAME", "BRIDGE", "SUBNET", "GATEWAY", "NAT")
+			for _, network := range networks {
+				fmt.Fprintf(cmd.OutOrStdout(), "%-14s %-16s %-20s %-16s %-8t\n", network.Name, network.Bridge, network.Subnet, network.Gateway, !network.Internal)
+			}
+			return nil
+		},
+	}
+	return cmd
+}
+
+func newNetworksCreateCmd() *cobra.Command {
+	var subnet, gateway, bridge string
+	var internal bool
+	cmd := &cobra.Command{
+		Use:   "create <name>",
+		Short: "Create a network",
+		Long: `Create a bridged network with its own IPv4 subnet. VMs join it by setting
+network.name in their config and get addresses from the network's pool.
+Internal networks are not masqueraded, so guests only reach each other and
+the host.
+
+Examples:
+  volar networks create backend --subnet 10.20.0.0/24
+  volar networks create isolated --subnet 10.30.0.0/24 --internal`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if subnet == "" {
+				return fmt.Errorf("--subnet is required")
+			}
+			api, err := clientFromCmd(cmd)
+			if err != nil {
+				return err
+			}
+			ctx, cancel := context.WithTimeout(cmd.Context(), 15*time.Second)
+			defer cancel()
+
+			network, err := api.CreateNetwork(ctx, volantclient.CreateNetworkRequest{
+				Name:     args[0],
+				Subnet:   subnet,
+				Gateway:  gateway,
+				Bridge:   bridge,
+				Internal: internal,
+			})
+			if err != nil {
+				return err
+			}
+			return renderResult(cmd, network, "Network %s created (bridge %s, subnet %s, gateway %s)", network.Name, network.Bridge, network.Subnet, network.Gateway)
+		},
+	}
+	cmd.Flags().StringVar(&subnet, "subnet", "", "IPv4 subnet in CIDR form")
+	cmd.Flags().StringVar(&gateway, "gateway", "", "Gateway address (default: first host address)")
+	cmd.Flags().StringVar(&bridge, "bridge", "", "Bridge name (default: vn-<name>)")
+	cmd.Flags().BoolVar(&internal, "internal", false, "Do not NAT traffic leaving the network")
+	return cmd
+}
+
+func newNetworksShowCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "show <name>",
+		Short: "Show a network and its leases",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			api, err := clientFromCmd(cmd)
+			if err != nil {
+				return err
+			}
+			ctx, cancel := context.WithTimeout(cmd.Context(), 15*time.Second)
+			defer cancel()
+
+			network, err := api.GetNetwork(ctx, args[0])
+			if err != nil {
+				return err
+			}
+			if done, err := render(cmd, network); done {
+				return err
+			}
+			out := cmd.OutOrStdout()
+			fmt.Fprintf(out, "Name:      %s\n", network.Name)
+			fmt.Fprintf(out, "Bridge:    %s\n", network.Bridge)
+			fmt.Fprintf(out, "Subnet:    %s\n", network.Subnet)
+			fmt.Fprintf(out, "Gateway:   %s\n", network.Gateway)
+			fmt.Fprintf(out, "Internal:  %t\n", network.Internal)
+			if len(network.Leases) == 0 {
+				fmt.Fprintln(out, "No VMs attached")
+				return nil
+			}
+			fmt.Fprintf(out, "\n%-16s %-24s %s\n", "IP", "VM", "LEASED")
+			for _, lease := range network.Leases {
+				fmt.Fprintf(out, "%-16s %-24s %s\n", lease.IPAddress, lease.VM, lease.LeasedAt.Local().Format("2006-01-02 15:04"))
+			}
+			return nil
+		},
+	}
+	return cmd
+}
+
+func newNetworksDeleteCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "delete <name>",
+		Short: "Delete a network with no attached VMs",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			api, err := clientFromCmd(cmd)
+			if err != nil {
+				return err
+			}
+			ctx, cancel := context.WithTimeout(cmd.Context(), 15*time.Second)
+			defer cancel()
+
+			if err := api.DeleteNetwork(ctx, args[0]); err != nil {
+				return err
+			}
+			infof(cmd, "Network %s deleted", args[0])
+			return nil
+		},
+	}
+	return cmd
+}
@@ -0,0 +1,81 @@
+// Copyright (c) 2025 HYPR. PTE. LTD.
+//
+// Business Source License 1.1
+// See LICENSE file in the project root for details.
+
+package standard
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+func newOperationsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "operations",
+		Aliases: []string{"operation", "ops"},
+		Short:   "Inspect asynchronous operations",
+	}
+	cmd.AddCommand(newOperationsShowCmd())
+	return cmd
+}
+
+func newOperationsShowCmd() *cobra.Command {
+	var wait bool
+	cmd := &cobra.Command{
+		Use:   "show <id>",
+		Short: "Show the status of an operation",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			api, err := clientFromCmd(cmd)
+			if err != nil {
+				return err
+			}
+			ctx := cmd.Context()
+			if !wait {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithTimeout(ctx, 15*time.Second)
+				defer cancel()
+			}
+
+			op, err := api.GetOperation(ctx, args[0])
+			if err != nil {
+				return err
+			}
+			for wait && !op.Done {
+				select {
+				case <-ctx.Done():
+					return ctx.Err()
+				case <-time.After(time.Second):
+				}
+				if op, err = api.GetOperation(ctx, args[0]); err != nil {
+					return err
+				}
+			}
+			if done, err := render(cmd, op); done {
+				return err
+			}
+			out := cmd.OutOrStdout()
+			fmt.Fprintf(out, "ID:       %s\n", op.ID)
+			fmt.Fprintf(out, "Kind:     %s\n", op.Kind)
+			fmt.Fprintf(out, "Target:   %s\n", op.Target)
+			fmt.Fprintf(out, "Status:   %s\n", op.Status)
+			fmt.Fprintf(out, "Created:  %s\n", op.CreatedAt.Local().Format("2006-01-02 15:04:05"))
+			if op.CompletedAt != nil {
+				fmt.Fprintf(out, "Finished: %s\n", op.CompletedAt.Local().Format("2006-01-02 15:04:05"))
+			}
+			if op.Error != "" {
+				fmt.Fprintf(out, "Error:    %s\n", op.Error)
+			}
+			if op.Hint != "" {
+				fmt.Fprintf(out, "Hint:     %s\n", op.Hint)
+			}
+			return nil
+		},
+	}
+	cmd.Flags().BoolVar(&wait, "wait", false, "Poll until the operation finishes")
+	return cmd
+}
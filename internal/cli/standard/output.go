@@ -0,0 +1,187 @@
+// Copyright (c) 2025 HYPR. PTE. LTD.
+//
+// Business Source License 1.1
+// See LICENSE file in the project root for details.
+
+package standard
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+// Output formats selected with --output.
+const (
+	outputTable = "table"
+	outputJSON  = "json"
+	outputYAML  = "yaml"
+)
+
+// addOutputFlags registers --output and --quiet on the root command. Both
+// apply to every subcommand.
+func addOutputFlags(cmd *cobra.Command) {
+	cmd.PersistentFlags().StringP("output", "o", envOrDefault("VOLAR_OUTPUT", outputTable), "Output format: table, json or yaml")
+	cmd.PersistentFlags().BoolP("quiet", "q", false, "Print only resource names, and nothing for commands that change state")
+}
+
+// validateOutputFlags rejects unknown output formats before a command runs.
+func validateOutputFlags(cmd *cobra.Command) error {
+	switch format := outputFormat(cmd); format {
+	case outputTable, outputJSON, outputYAML:
+		return nil
+	default:
+		return fmt.Errorf("unsupported output format %q (want table, json or yaml)", format)
+	}
+}
+
+// outputFormat returns the format selected with --output.
+func outputFormat(cmd *cobra.Command) string {
+	format, err := cmd.Flags().GetString("output")
+	if err != nil {
+		return outputTable
+	}
+	return strings.ToLower(strings.TrimSpace(format))
+}
+
+// structuredOutput reports whether --output asks for JSON or YAML.
+func structuredOutput(cmd *cobra.Command) bool {
+	return outputFormat(cmd) != outputTable
+}
+
+// quiet reports whether --quiet is set.
+func quiet(cmd *cobra.Command) bool {
+	q, err := cmd.Flags().GetBool("quiet")
+	return err == nil && q
+}
+
+// render writes payload in the JSON or YAML format selected with --output
+// and reports whether it did. For table output it writes nothing and leaves
+// the rendering to the caller. Payloads are the API types, so structured
+// output carries the API's field names.
+func render(cmd *cobra.Command, payload any) (bool, error) {
+	switch outputFormat(cmd) {
+	case outputJSON:
+		return true, encodeAsJSON(cmd.OutOrStdout(), payload)
+	case outputYAML:
+		return true, encodeAsYAML(cmd.OutOrStdout(), payload)
+	default:
+		return false, nil
+	}
+}
+
+// renderStream writes one message of a stream in the JSON or YAML format
+// selected with --output and reports whether it did: JSON as one compact
+// line per message, YAML as one document per message.
+func renderStream(cmd *cobra.Command, payload any) (bool, error) {
+	out := cmd.OutOrStdout()
+	switch outputFormat(cmd) {
+	case outputJSON:
+		return true, json.NewEncoder(out).Encode(payload)
+	case outputYAML:
+		if _, err := fmt.Fprintln(out, "---"); err != nil {
+			return true, err
+		}
+		return true, encodeAsYAML(out, payload)
+	default:
+		return false, nil
+	}
+}
+
+// renderResult renders the result of a command that changes state: payload
+// in JSON or YAML, nothing with --quiet, and otherwise the message.
+func renderResult(cmd *cobra.Command, payload any, format string, args ...any) error {
+	if done, err := render(cmd, payload); done {
+		return err
+	}
+	infof(cmd, format, args...)
+	return nil
+}
+
+// renderNames handles --output and --quiet for list commands: the payload
+// in JSON or YAML, or one name per line with --quiet. It reports whether
+// the list was written.
+func renderNames(cmd *cobra.Command, payload any, names func() []string) (bool, error) {
+	if done, err := render(cmd, payload); done {
+		return true, err
+	}
+	if !quiet(cmd) {
+		return false, nil
+	}
+	for _, name := range names() {
+		fmt.Fprintln(cmd.OutOrStdout(), name)
+	}
+	return true, nil
+}
+
+// writeDocument writes payload, a document with no table view, to path or,
+// when path is empty, to standard output: as YAML with --output yaml and as
+// JSON otherwise.
+func writeDocument(cmd *cobra.Command, path string, payload any) error {
+	var buf bytes.Buffer
+	var err error
+	if outputFormat(cmd) == outputYAML {
+		err = encodeAsYAML(&buf, payload)
+	} else {
+		err = encodeAsJSON(&buf, payload)
+	}
+	if err != nil {
+		return err
+	}
+	if strings.TrimSpace(path) != "" {
+		return os.WriteFile(path, buf.Bytes(), 0o644)
+	}
+	_, err = cmd.OutOrStdout().Write(buf.Bytes())
+	return err
+}
+
+// infof prints a progress or confirmation line unless --quiet is set or a
+// structured format was selected, so scripts only see the payload.
+func infof(cmd *cobra.Command, format string, args ...any) {
+	if quiet(cmd) || structuredOutput(cmd) {
+		return
+	}
+	fmt.Fprintf(cmd.OutOrStdout(), format+"\n", args...)
+}
+
+func encodeAsJSON(out io.Writer, payload any) error {
+	enc := json.NewEncoder(out)
+	enc.SetIndent("", "  ")
+	return enc.Encode(payload)
+}
+
+// encodeAsYAML writes payload as YAML with the same field names and order as
+// its JSON encoding.
+func encodeAsYAML(out io.Writer, payload any) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	// JSON is valid YAML; decoding it into a node keeps the key order.
+	var node yaml.Node
+	if err := yaml.Unmarshal(data, &node); err != nil {
+		return err
+	}
+	clearStyle(&node)
+	enc := yaml.NewEncoder(out)
+	enc.SetIndent(2)
+	if err := enc.Encode(&node); err != nil {
+		return err
+	}
+	return enc.Close()
+}
+
+// clearStyle drops the flow and quoting styles taken from the JSON source so
+// the encoder writes block YAML.
+func clearStyle(node *yaml.Node) {
+	node.Style = 0
+	for _, child := range node.Content {
+		clearStyle(child)
+	}
+}
@@ -34,6 +34,8 @@ func newPluginsCmd() *cobra.Command {
 	// For now install/remove expect manifest JSON files.
 	cmd.AddCommand(newPluginsInstallCmd())
 	cmd.AddCommand(newPluginsRemoveCmd())
+	cmd.AddCommand(newPluginsVersionsCmd())
+	cmd.AddCommand(newPluginsUpgradeCmd())
 
 	return cmd
 }
@@ -54,6 +56,15 @@ func newPluginsListCmd() *cobra.Command {
 			if err != nil {
 				return err
 			}
+			if done, err := renderNames(cmd, plugins, func() []string {
+				names := make([]string, 0, len(plugins))
+				for _, plugin := range plugins {
+					names = append(names, plugin.Name)
+				}
+				return names
+			}); done {
+				return err
+			}
 			if len(plugins) == 0 {
 				fmt.Fprintln(cmd.OutOrStdout(), "No plugins installed")
 				return nil
@@ -68,7 +79,7 @@ func newPluginsListCmd() *cobra.Command {
 }
 
 func newPluginsShowCmd() *cobra.Command {
-	return &cobra.Command{
+	cmd := &cobra.Command{
 		Use:   "show <name>",
 		Short: "Show plugin manifest",
 		Args:  cobra.ExactArgs(1),
@@ -88,13 +99,15 @@ func newPluginsShowCmd() *cobra.Command {
 				fmt.Fprintf(cmd.OutOrStdout(), "Plugin %s not found\n", args[0])
 				return nil
 			}
-			return encodeAsJSON(cmd.OutOrStdout(), manifest)
+			return writeDocument(cmd, "", manifest)
 		},
 	}
+	cmd.ValidArgsFunction = completeArgs(completePluginNames)
+	return cmd
 }
 
 func newPluginsEnableCmd() *cobra.Command {
-	return &cobra.Command{
+	cmd := &cobra.Command{
 		Use:   "enable <name>",
 		Short: "Enable a plugin",
 		Args:  cobra.ExactArgs(1),
@@ -102,10 +115,12 @@ func newPluginsEnableCmd() *cobra.Command {
 			return togglePlugin(cmd, args[0], true)
 		},
 	}
+	cmd.ValidArgsFunction = completeArgs(completePluginNames)
+	return cmd
 }
 
 func newPluginsDisableCmd() *cobra.Command {
-	return &cobra.Command{
+	cmd := &cobra.Command{
 		Use:   "disable <name>",
 		Short: "Disable a plugin",
 		Args:  cobra.ExactArgs(1),
@@ -113,11 +128,14 @@ func newPluginsDisableCmd() *cobra.Command {
 			return togglePlugin(cmd, args[0], false)
 		},
 	}
+	cmd.ValidArgsFunction = completeArgs(completePluginNames)
+	return cmd
 }
 
 func newPluginsInstallCmd() *cobra.Command {
 	var manifestPath string
 	var manifestURL string
+	var stage bool
 
 	cmd := &cobra.Command{
 		Use:   "install [manifest]",
@@ -166,58 +184,8 @@ func newPluginsInstallCmd() *cobra.Command {
 			if err := json.Unmarshal(data, &manifest); err != nil {
 				return fmt.Errorf("decode manifest: %w", err)
 			}
-			rootfsPath := strings.TrimSpace(manifest.RootFS.URL)
-			if rootfsPath != "" && !strings.HasPrefix(rootfsPath, "http://") && !strings.HasPrefix(rootfsPath, "https://") && !strings.HasPrefix(rootfsPath, "file://") && !filepath.IsAbs(rootfsPath) {
-				resolved := filepath.Join(filepath.Dir(manifestPath), rootfsPath)
-				manifest.RootFS.URL = filepath.Clean(resolved)
-			}
-			initramfsPath := strings.TrimSpace(manifest.Initramfs.URL)
-			if initramfsPath != "" && !strings.HasPrefix(initramfsPath, "http://") && !strings.HasPrefix(initramfsPath, "https://") && !strings.HasPrefix(initramfsPath, "file://") && !filepath.IsAbs(initramfsPath) {
-				resolved := filepath.Join(filepath.Dir(manifestPath), initramfsPath)
-				manifest.Initramfs.URL = filepath.Clean(resolved)
-			}
-			for i := range manifest.Disks {
-				src := strings.TrimSpace(manifest.Disks[i].Source)
-				if src == "" {
-					continue
-				}
-				if strings.HasPrefix(src, "http://") || strings.HasPrefix(src, "https://") || strings.HasPrefix(src, "file://") || filepath.IsAbs(src) {
-					continue
-				}
-				resolved := filepath.Join(filepath.Dir(manifestPath), src)
-				manifest.Disks[i].Source = filepath.Clean(resolved)
-			}
-			if manifest.CloudInit != nil {
-				base := filepath.Dir(manifestPath)
-				process := func(doc pluginspec.CloudInitDoc) (pluginspec.CloudInitDoc, error) {
-					path := strings.TrimSpace(doc.Path)
-					if path == "" {
-						return doc, nil
-					}
-					if !filepath.IsAbs(path) {
-						path = filepath.Join(base, path)
-					}
-					data, err := os.ReadFile(path)
-					if err != nil {
-						return doc, err
-					}
-					doc.Path = ""
-					doc.Content = string(data)
-					doc.Inline = true
-					return doc, nil
-				}
-				var err error
-				if manifest.CloudInit != nil {
-					if manifest.CloudInit.UserData, err = process(manifest.CloudInit.UserData); err != nil {
-						return err
-					}
-					if manifest.CloudInit.MetaData, err = process(manifest.CloudInit.MetaData); err != nil {
-						return err
-					}
-					if manifest.CloudInit.NetworkCfg, err = process(manifest.CloudInit.NetworkCfg); err != nil {
-						return err
-					}
-				}
+			if err := resolveManifestPaths(&manifest, manifestPath); err != nil {
+				return err
 			}
 			manifest.Normalize()
 			if err := manifest.Validate(); err != nil {
@@ -231,17 +199,112 @@ func newPluginsInstallCmd() *cobra.Command {
 			ctx, cancel := context.WithTimeout(cmd.Context(), 15*time.Second)
 			defer cancel()
 
+			if stage {
+				return api.StagePluginVersion(ctx, manifest)
+			}
 			return api.InstallPlugin(ctx, manifest)
 		},
 	}
 
 	cmd.Flags().StringVar(&manifestPath, "manifest", "", "Path to plugin manifest JSON")
 	cmd.Flags().StringVar(&manifestURL, "url", "", "URL to plugin manifest JSON")
+	cmd.Flags().BoolVar(&stage, "stage", false, "Install as another version of an installed plugin without activating it")
+	return cmd
+}
+
+func newPluginsVersionsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "versions <name>",
+		Short: "List installed versions of a plugin and the VMs running each",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			api, err := clientFromCmd(cmd)
+			if err != nil {
+				return err
+			}
+			ctx, cancel := context.WithTimeout(cmd.Context(), 10*time.Second)
+			defer cancel()
+
+			versions, err := api.ListPluginVersions(ctx, args[0])
+			if err != nil {
+				return err
+			}
+			if done, err := renderNames(cmd, versions, func() []string {
+				names := make([]string, 0, len(versions.Versions))
+				for _, version := range versions.Versions {
+					names = append(names, version.Version)
+				}
+				return names
+			}); done {
+				return err
+			}
+			out := cmd.OutOrStdout()
+			fmt.Fprintf(out, "%-12s %-7s %s\n", "VERSION", "ACTIVE", "INSTALLED")
+			for _, version := range versions.Versions {
+				fmt.Fprintf(out, "%-12s %-7t %s\n", version.Version, version.Active, version.InstalledAt.Format(time.RFC3339))
+			}
+			if len(versions.VMs) == 0 {
+				return nil
+			}
+			fmt.Fprintln(out)
+			fmt.Fprintf(out, "%-24s %-20s %s\n", "VM", "DEPLOYMENT", "VERSION")
+			for _, vm := range versions.VMs {
+				fmt.Fprintf(out, "%-24s %-20s %s\n", vm.VM, vm.Deployment, vm.Version)
+			}
+			if len(versions.Drift) > 0 {
+				fmt.Fprintf(out, "\n%d VM(s) not on active version %s: %s\n", len(versions.Drift), versions.Active, strings.Join(versions.Drift, ", "))
+			}
+			return nil
+		},
+	}
+	cmd.ValidArgsFunction = completeArgs(completePluginNames)
+	return cmd
+}
+
+func newPluginsUpgradeCmd() *cobra.Command {
+	var roll bool
+
+	cmd := &cobra.Command{
+		Use:   "upgrade <name> <version>",
+		Short: "Switch a plugin to another installed version",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			api, err := clientFromCmd(cmd)
+			if err != nil {
+				return err
+			}
+			ctx, cancel := context.WithTimeout(cmd.Context(), 30*time.Second)
+			defer cancel()
+
+			upgrade, err := api.UpgradePlugin(ctx, args[0], args[1], roll)
+			if err != nil {
+				return err
+			}
+			if done, err := render(cmd, upgrade); done || quiet(cmd) {
+				return err
+			}
+			out := cmd.OutOrStdout()
+			fmt.Fprintf(out, "Plugin %s upgraded from %s to %s\n", upgrade.Plugin, upgrade.From, upgrade.To)
+			for _, name := range upgrade.Restarted {
+				fmt.Fprintf(out, "  rolling deployment %s\n", name)
+			}
+			for name, reason := range upgrade.Skipped {
+				fmt.Fprintf(out, "  skipped deployment %s: %s\n", name, reason)
+			}
+			if len(upgrade.Drift) > 0 {
+				fmt.Fprintf(out, "%d VM(s) still on other versions: %s\n", len(upgrade.Drift), strings.Join(upgrade.Drift, ", "))
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&roll, "roll", false, "Roll the plugin's deployments onto the new version")
+	cmd.ValidArgsFunction = completeArgs(completePluginNames)
 	return cmd
 }
 
 func newPluginsRemoveCmd() *cobra.Command {
-	return &cobra.Command{
+	cmd := &cobra.Command{
 		Use:   "remove <name>",
 		Short: "Remove an installed plugin",
 		Args:  cobra.ExactArgs(1),
@@ -256,6 +319,67 @@ func newPluginsRemoveCmd() *cobra.Command {
 			return api.RemovePlugin(ctx, args[0])
 		},
 	}
+	cmd.ValidArgsFunction = completeArgs(completePluginNames)
+	return cmd
+}
+
+// resolveManifestPaths makes boot media and disk paths relative to the
+// manifest file absolute and inlines cloud-init documents referenced by path.
+func resolveManifestPaths(manifest *pluginspec.Manifest, manifestPath string) error {
+	rootfsPath := strings.TrimSpace(manifest.RootFS.URL)
+	if rootfsPath != "" && !strings.HasPrefix(rootfsPath, "http://") && !strings.HasPrefix(rootfsPath, "https://") && !strings.HasPrefix(rootfsPath, "file://") && !filepath.IsAbs(rootfsPath) {
+		resolved := filepath.Join(filepath.Dir(manifestPath), rootfsPath)
+		manifest.RootFS.URL = filepath.Clean(resolved)
+	}
+	initramfsPath := strings.TrimSpace(manifest.Initramfs.URL)
+	if initramfsPath != "" && !strings.HasPrefix(initramfsPath, "http://") && !strings.HasPrefix(initramfsPath, "https://") && !strings.HasPrefix(initramfsPath, "file://") && !filepath.IsAbs(initramfsPath) {
+		resolved := filepath.Join(filepath.Dir(manifestPath), initramfsPath)
+		manifest.Initramfs.URL = filepath.Clean(resolved)
+	}
+	for i := range manifest.Disks {
+		src := strings.TrimSpace(manifest.Disks[i].Source)
+		if src == "" {
+			continue
+		}
+		if strings.HasPrefix(src, "http://") || strings.HasPrefix(src, "https://") || strings.HasPrefix(src, "file://") || filepath.IsAbs(src) {
+			continue
+		}
+		resolved := filepath.Join(filepath.Dir(manifestPath), src)
+		manifest.Disks[i].Source = filepath.Clean(resolved)
+	}
+	if manifest.CloudInit != nil {
+		base := filepath.Dir(manifestPath)
+		process := func(doc pluginspec.CloudInitDoc) (pluginspec.CloudInitDoc, error) {
+			path := strings.TrimSpace(doc.Path)
+			if path == "" {
+				return doc, nil
+			}
+			if !filepath.IsAbs(path) {
+				path = filepath.Join(base, path)
+			}
+			data, err := os.ReadFile(path)
+			if err != nil {
+				return doc, err
+			}
+			doc.Path = ""
+			doc.Content = string(data)
+			doc.Inline = true
+			return doc, nil
+		}
+		var err error
+		if manifest.CloudInit != nil {
+			if manifest.CloudInit.UserData, err = process(manifest.CloudInit.UserData); err != nil {
+				return err
+			}
+			if manifest.CloudInit.MetaData, err = process(manifest.CloudInit.MetaData); err != nil {
+				return err
+			}
+			if manifest.CloudInit.NetworkCfg, err = process(manifest.CloudInit.NetworkCfg); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
 }
 
 func fetchURL(ctx context.Context, raw string) ([]byte, error) {
@@ -311,6 +435,6 @@ func togglePlugin(cmd *cobra.Command, name string, enabled bool) error {
 	if enabled {
 		state = "enabled"
 	}
-	fmt.Fprintf(cmd.OutOrStdout(), "Plugin %s %s\n", name, state)
+	infof(cmd, "Plugin %s %s", name, state)
 	return nil
 }
@@ -0,0 +1,323 @@
+// Copyright (c) 2025 HYPR. PTE. LTD.
+//
+// Business Source License 1.1
+// See LICENSE file in the project root for details.
+
+package standard
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/volantvm/volant/pkg/volantclient"
+)
+
+func newRoutesCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "routes",
+		Aliases: []string{"route"},
+		Short:   "Manage the host port routes of the drift proxy",
+		Long: `Manage the host port routes of the drift proxy. VMs publish and retract
+routes for their expose rules as they start and stop; these commands show
+them and manage routes of your own.`,
+	}
+	cmd.AddCommand(newRoutesListCmd())
+	cmd.AddCommand(newRoutesSetCmd())
+	cmd.AddCommand(newRoutesDeleteCmd())
+	cmd.AddCommand(newRoutesBackendCmd())
+	cmd.AddCommand(newRoutesSyncCmd())
+	return cmd
+}
+
+func newRoutesListCmd() *cobra.Command {
+	var vm string
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List routes",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			api, err := clientFromCmd(cmd)
+			if err != nil {
+				return err
+			}
+			ctx, cancel := context.WithTimeout(cmd.Context(), 15*time.Second)
+			defer cancel()
+
+			items, err := api.ListRoutes(ctx, vm)
+			if err != nil {
+				return err
+			}
+			if done, err := renderNames(cmd, items, func() []string {
+				names := make([]string, 0, len(items))
+				for _, item := range items {
+					names = append(names, fmt.Sprintf("%s/%d", item.Protocol, item.HostPort))
+				}
+				return names
+			}); done {
+				return err
+			}
+			if len(items) == 0 {
+				fmt.Fprintln(cmd.OutOrStdout(), "No routes found")
+				return nil
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "%-10s %-28s %-24s %-9s\n", "ROUTE", "BACKEND", "VM", "PUBLISHED")
+			for _, item := range items {
+				owner := item.VM
+				if owner == "" {
+					owner = "-"
+				}
+				fmt.Fprintf(cmd.OutOrStdout(), "%-10s %-28s %-24s %-9t\n", fmt.Sprintf("%s/%d", item.Protocol, item.HostPort), routeBackend(item.Route), owner, item.Published)
+			}
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&vm, "vm", "", "Only show the routes of this VM")
+	return cmd
+}
+
+func newRoutesSetCmd() *cobra.Command {
+	var protocol, target, socket string
+	var vsock bool
+	cmd := &cobra.Command{
+		Use:   "set <host-port> --to <ip:port|cid:port>",
+		Short: "Create or replace a route",
+		Long: `Create or replace the route of a host port. Routes published for a running
+VM's expose rules cannot be changed here; change the VM's config instead.
+
+Examples:
+  volar routes set 8080 --to 10.1.0.12:80
+  volar routes set 5353 --protocol udp --to 10.1.0.12:53
+  volar routes set 9000 --vsock --to 42:9000
+  volar routes set 15432 --vsock --socket db.sock --to 42:5432`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			hostPort, err := parseRoutePort(args[0])
+			if err != nil {
+				return err
+			}
+			if target == "" {
+				return fmt.Errorf("--to is required")
+			}
+			host, portStr, err := net.SplitHostPort(target)
+			if err != nil {
+				return fmt.Errorf("invalid --to %q: %w", target, err)
+			}
+			port, err := parseRoutePort(portStr)
+			if err != nil {
+				return err
+			}
+			backend := volantclient.RouteBackend{Type: "bridge", IP: host, Port: port}
+			if socket != "" {
+				if !vsock {
+					return fmt.Errorf("--socket requires --vsock")
+				}
+				protocol = "unix"
+			}
+			if vsock {
+				cid, err := strconv.ParseUint(host, 10, 32)
+				if err != nil {
+					return fmt.Errorf("invalid vsock cid %q", host)
+				}
+				backend = volantclient.RouteBackend{Type: "vsock", CID: uint32(cid), Port: port}
+			}
+
+			api, err := clientFromCmd(cmd)
+			if err != nil {
+				return err
+			}
+			ctx, cancel := context.WithTimeout(cmd.Context(), 15*time.Second)
+			defer cancel()
+
+			route, err := api.UpsertRoute(ctx, volantclient.Route{HostPort: hostPort, Protocol: protocol, Backend: backend, Socket: socket})
+			if err != nil {
+				return err
+			}
+			return renderResult(cmd, route, "Route %s/%d set to %s", route.Protocol, route.HostPort, routeBackend(*route))
+		},
+	}
+	cmd.Flags().StringVar(&protocol, "protocol", "tcp", "Protocol: tcp or udp")
+	cmd.Flags().StringVar(&target, "to", "", "Backend address: ip:port, or cid:port with --vsock")
+	cmd.Flags().BoolVar(&vsock, "vsock", false, "Route to a vsock CID instead of a bridge address")
+	cmd.Flags().StringVar(&socket, "socket", "", "Listen on this unix socket in driftd's socket directory instead of the host port (with --vsock)")
+	return cmd
+}
+
+func newRoutesDeleteCmd() *cobra.Command {
+	var protocol string
+	cmd := &cobra.Command{
+		Use:   "delete <host-port>",
+		Short: "Delete a route",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			hostPort, err := parseRoutePort(args[0])
+			if err != nil {
+				return err
+			}
+			api, err := clientFromCmd(cmd)
+			if err != nil {
+				return err
+			}
+			ctx, cancel := context.WithTimeout(cmd.Context(), 15*time.Second)
+			defer cancel()
+
+			if err := api.DeleteRoute(ctx, protocol, hostPort); err != nil {
+				return err
+			}
+			infof(cmd, "Route %s/%d deleted", protocol, hostPort)
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&protocol, "protocol", "tcp", "Protocol: tcp, udp or unix")
+	return cmd
+}
+
+func newRoutesBackendCmd() *cobra.Command {
+	var (
+		protocol       string
+		weight         int
+		drain, undrain bool
+	)
+	cmd := &cobra.Command{
+		Use:   "backend <host-port> <ip:port>",
+		Short: "Drain or reweight a backend of an l7 route",
+		Long: `Change a backend of an l7 route in every rule that names it. A draining
+backend gets no new requests or connections while those in flight finish;
+driftd reports its active connections in the route's stats.
+
+Examples:
+  volar routes backend 80 10.1.0.12:8080 --drain
+  volar routes backend 80 10.1.0.13:8080 --weight 3 --undrain`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			hostPort, err := parseRoutePort(args[0])
+			if err != nil {
+				return err
+			}
+			ip, portStr, err := net.SplitHostPort(args[1])
+			if err != nil {
+				return fmt.Errorf("invalid backend %q: %w", args[1], err)
+			}
+			port, err := parseRoutePort(portStr)
+			if err != nil {
+				return err
+			}
+			if drain && undrain {
+				return fmt.Errorf("--drain and --undrain are mutually exclusive")
+			}
+			setWeight := cmd.Flags().Changed("weight")
+			if !drain && !undrain && !setWeight {
+				return fmt.Errorf("nothing to change: use --drain, --undrain or --weight")
+			}
+
+			api, err := clientFromCmd(cmd)
+			if err != nil {
+				return err
+			}
+			ctx, cancel := context.WithTimeout(cmd.Context(), 15*time.Second)
+			defer cancel()
+
+			items, err := api.ListRoutes(ctx, "")
+			if err != nil {
+				return err
+			}
+			var route *volantclient.Route
+			for i := range items {
+				if items[i].HostPort == hostPort && strings.EqualFold(items[i].Protocol, protocol) {
+					route = &items[i].Route
+					break
+				}
+			}
+			if route == nil {
+				return fmt.Errorf("route %s/%d not found", protocol, hostPort)
+			}
+			if route.L7 == nil {
+				return fmt.Errorf("route %s/%d is not an l7 route", protocol, hostPort)
+			}
+			found := false
+			for i := range route.L7.Rules {
+				for j := range route.L7.Rules[i].Backends {
+					target := &route.L7.Rules[i].Backends[j]
+					if target.IP != ip || target.Port != port {
+						continue
+					}
+					found = true
+					if drain || undrain {
+						target.Draining = drain
+					}
+					if setWeight {
+						target.Weight = weight
+					}
+				}
+			}
+			if !found {
+				return fmt.Errorf("route %s/%d has no backend %s", protocol, hostPort, args[1])
+			}
+
+			updated, err := api.UpsertRoute(ctx, *route)
+			if err != nil {
+				return err
+			}
+			return renderResult(cmd, updated, "Backend %s of route %s/%d updated", args[1], updated.Protocol, updated.HostPort)
+		},
+	}
+	cmd.Flags().StringVar(&protocol, "protocol", "tcp", "Protocol: tcp or udp")
+	cmd.Flags().IntVar(&weight, "weight", 1, "Relative share of new requests or connections")
+	cmd.Flags().BoolVar(&drain, "drain", false, "Stop new requests and connections to the backend")
+	cmd.Flags().BoolVar(&undrain, "undrain", false, "Send new requests and connections to the backend again")
+	return cmd
+}
+
+func newRoutesSyncCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "sync",
+		Short: "Publish the routes of running VMs again",
+		Long: `Publish the routes of every running VM's expose rules again, for when driftd
+restarted without them or was down when the VMs started.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			api, err := clientFromCmd(cmd)
+			if err != nil {
+				return err
+			}
+			ctx, cancel := context.WithTimeout(cmd.Context(), time.Minute)
+			defer cancel()
+
+			published, err := api.SyncRoutes(ctx)
+			if err != nil {
+				return err
+			}
+			return renderResult(cmd, published, "Published %d VM routes", len(published))
+		},
+	}
+	return cmd
+}
+
+func parseRoutePort(value string) (uint16, error) {
+	port, err := strconv.ParseUint(value, 10, 16)
+	if err != nil || port == 0 {
+		return 0, fmt.Errorf("invalid port %q", value)
+	}
+	return uint16(port), nil
+}
+
+func routeBackend(route volantclient.Route) string {
+	backend := route.Backend
+	switch backend.Type {
+	case "vsock":
+		if route.Socket != "" {
+			return fmt.Sprintf("vsock %d:%d from %s", backend.CID, backend.Port, route.Socket)
+		}
+		return fmt.Sprintf("vsock %d:%d", backend.CID, backend.Port)
+	case "l7":
+		if route.L7 != nil {
+			return fmt.Sprintf("l7 %s, rules: %d", route.L7.Mode, len(route.L7.Rules))
+		}
+		return "l7"
+	}
+	return net.JoinHostPort(backend.IP, strconv.Itoa(int(backend.Port)))
+}
@@ -0,0 +1,254 @@
+// Copyright (c) 2025 HYPR. PTE. LTD.
+//
+// Business Source License 1.1
+// See LICENSE file in the project root for details.
+
+package standard
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/volantvm/volant/pkg/volantclient"
+)
+
+func newSchedulesCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "schedules",
+		Short: "Manage scheduled VM lifecycle actions",
+	}
+	cmd.AddCommand(newSchedulesListCmd())
+	cmd.AddCommand(newSchedulesCreateCmd())
+	cmd.AddCommand(newSchedulesDeleteCmd())
+	cmd.AddCommand(newSchedulesToggleCmd("enable", "Resume a paused schedule", true))
+	cmd.AddCommand(newSchedulesToggleCmd("disable", "Pause a schedule without deleting it", false))
+	cmd.AddCommand(newSchedulesRunCmd())
+	cmd.AddCommand(newSchedulesHistoryCmd())
+	return cmd
+}
+
+func newSchedulesListCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List schedules",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			api, err := clientFromCmd(cmd)
+			if err != nil {
+				return err
+			}
+			ctx, cancel := context.WithTimeout(cmd.Context(), 15*time.Second)
+			defer cancel()
+
+			schedules, err := api.ListSchedules(ctx)
+			if err != nil {
+				return err
+			}
+			if done, err := renderNames(cmd, schedules, func() []string {
+				names := make([]string, 0, len(schedules))
+				for _, schedule := range schedules {
+					names = append(names, schedule.Name)
+				}
+				return names
+			}); done {
+				return err
+			}
+			if len(schedules) == 0 {
+				fmt.Fprintln(cmd.OutOrStdout(), "No schedules found")
+				return nil
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "%-20s %-16s %-10s %-28s %-8s %-20s\n", "NAME", "CRON", "ACTION", "TARGET", "ENABLED", "NEXT RUN")
+			for _, schedule := range schedules {
+				next := "-"
+				if schedule.NextRunAt != nil {
+					next = schedule.NextRunAt.Local().Format("2006-01-02 15:04")
+				}
+				target := schedule.TargetKind + "/" + schedule.Target
+				fmt.Fprintf(cmd.OutOrStdout(), "%-20s %-16s %-10s %-28s %-8t %-20s\n", schedule.Name, schedule.Cron, schedule.Action, target, schedule.Enabled, next)
+			}
+			return nil
+		},
+	}
+	return cmd
+}
+
+func newSchedulesCreateCmd() *cobra.Command {
+	var cronExpr string
+	var action string
+	var vmName, deploymentName, pluginName string
+	var replicas int
+	var disabled bool
+	cmd := &cobra.Command{
+		Use:   "create <name>",
+		Short: "Create a schedule",
+		Long: `Create a cron-style schedule that applies an action to a VM, a deployment or
+every VM of a plugin. Cron expressions use five fields (minute hour day month
+weekday) evaluated in the daemon's local time; @hourly, @daily and friends are
+also accepted.
+
+Examples:
+  volar schedules create nightly-stop --cron "0 22 * * *" --action stop --plugin browser
+  volar schedules create morning-start --cron "0 8 * * 1-5" --action start --deployment web
+  volar schedules create db-snapshot --cron @hourly --action snapshot --vm db
+  volar schedules create weekend-scale --cron "0 0 * * sat" --action scale --deployment web --replicas 1`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			req := volantclient.CreateScheduleRequest{
+				Name:     args[0],
+				Cron:     cronExpr,
+				Action:   action,
+				Disabled: disabled,
+			}
+			targets := 0
+			for kind, value := range map[string]string{"vm": vmName, "deployment": deploymentName, "plugin": pluginName} {
+				if value != "" {
+					req.TargetKind, req.Target = kind, value
+					targets++
+				}
+			}
+			if targets != 1 {
+				return fmt.Errorf("exactly one of --vm, --deployment or --plugin is required")
+			}
+			if cmd.Flags().Changed("replicas") {
+				req.Replicas = &replicas
+			}
+
+			api, err := clientFromCmd(cmd)
+			if err != nil {
+				return err
+			}
+			ctx, cancel := context.WithTimeout(cmd.Context(), 15*time.Second)
+			defer cancel()
+
+			schedule, err := api.CreateSchedule(ctx, req)
+			if err != nil {
+				return err
+			}
+			return renderResult(cmd, schedule, "Schedule %s created", schedule.Name)
+		},
+	}
+	cmd.Flags().StringVar(&cronExpr, "cron", "", "Cron expression (required)")
+	cmd.Flags().StringVar(&action, "action", "", "Action: start, stop, restart, snapshot or scale (required)")
+	cmd.Flags().StringVar(&vmName, "vm", "", "Target VM")
+	cmd.Flags().StringVar(&deploymentName, "deployment", "", "Target deployment")
+	cmd.Flags().StringVar(&pluginName, "plugin", "", "Target every VM of the plugin")
+	cmd.Flags().IntVar(&replicas, "replicas", 0, "Replica count for scale actions")
+	cmd.Flags().BoolVar(&disabled, "disabled", false, "Create the schedule paused")
+	_ = cmd.MarkFlagRequired("cron")
+	_ = cmd.MarkFlagRequired("action")
+	_ = cmd.RegisterFlagCompletionFunc("vm", completeFlag(completeVMNames))
+	_ = cmd.RegisterFlagCompletionFunc("deployment", completeFlag(completeDeploymentNames))
+	_ = cmd.RegisterFlagCompletionFunc("plugin", completeFlag(completePluginNames))
+	return cmd
+}
+
+func newSchedulesDeleteCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "delete <name>",
+		Short: "Delete a schedule and its history",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			api, err := clientFromCmd(cmd)
+			if err != nil {
+				return err
+			}
+			ctx, cancel := context.WithTimeout(cmd.Context(), 15*time.Second)
+			defer cancel()
+
+			if err := api.DeleteSchedule(ctx, args[0]); err != nil {
+				return err
+			}
+			infof(cmd, "Schedule %s deleted", args[0])
+			return nil
+		},
+	}
+	return cmd
+}
+
+func newSchedulesToggleCmd(use, short string, enabled bool) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   use + " <name>",
+		Short: short,
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			api, err := clientFromCmd(cmd)
+			if err != nil {
+				return err
+			}
+			ctx, cancel := context.WithTimeout(cmd.Context(), 15*time.Second)
+			defer cancel()
+
+			schedule, err := api.SetScheduleEnabled(ctx, args[0], enabled)
+			if err != nil {
+				return err
+			}
+			return renderResult(cmd, schedule, "Schedule %s %sd", args[0], use)
+		},
+	}
+	return cmd
+}
+
+func newSchedulesRunCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "run <name>",
+		Short: "Run a schedule immediately",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			api, err := clientFromCmd(cmd)
+			if err != nil {
+				return err
+			}
+			ctx, cancel := context.WithTimeout(cmd.Context(), 5*time.Minute)
+			defer cancel()
+
+			run, err := api.RunSchedule(ctx, args[0])
+			if err != nil {
+				return err
+			}
+			if run.Message != "" {
+				return renderResult(cmd, run, "Schedule %s %s: %s", args[0], run.Status, run.Message)
+			}
+			return renderResult(cmd, run, "Schedule %s %s", args[0], run.Status)
+		},
+	}
+	return cmd
+}
+
+func newSchedulesHistoryCmd() *cobra.Command {
+	var limit int
+	cmd := &cobra.Command{
+		Use:   "history <name>",
+		Short: "Show recent executions of a schedule",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			api, err := clientFromCmd(cmd)
+			if err != nil {
+				return err
+			}
+			ctx, cancel := context.WithTimeout(cmd.Context(), 15*time.Second)
+			defer cancel()
+
+			runs, err := api.ScheduleRuns(ctx, args[0], limit)
+			if err != nil {
+				return err
+			}
+			if done, err := render(cmd, runs); done {
+				return err
+			}
+			if len(runs) == 0 {
+				fmt.Fprintln(cmd.OutOrStdout(), "No runs recorded")
+				return nil
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "%-20s %-10s %-10s %s\n", "STARTED", "STATUS", "DURATION", "MESSAGE")
+			for _, run := range runs {
+				duration := run.FinishedAt.Sub(run.StartedAt).Round(time.Millisecond)
+				fmt.Fprintf(cmd.OutOrStdout(), "%-20s %-10s %-10s %s\n", run.StartedAt.Local().Format("2006-01-02 15:04:05"), run.Status, duration, run.Message)
+			}
+			return nil
+		},
+	}
+	cmd.Flags().IntVar(&limit, "limit", 20, "Number of runs to show")
+	return cmd
+}
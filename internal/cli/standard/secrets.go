@@ -0,0 +1,158 @@
+// Copyright (c) 2025 HYPR. PTE. LTD.
+//
+// Business Source License 1.1
+// See LICENSE file in the project root for details.
+
+package standard
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+func newSecretsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "secrets",
+		Short: "Manage secrets cloud-init templates and VMs use",
+		Long: `Manage secrets cloud-init templates reference and VMs mount.
+
+volantd seals each value under its host key before storing it. A cloud-init
+document with "template": true inserts one as {{ secret "name" }}, and a VM
+config or manifest "secrets" list has the agent write it to a file and,
+optionally, an environment variable. Values never reach stored configs, and
+every value opened for a VM is recorded in the audit log.`,
+	}
+	cmd.AddCommand(newSecretsListCmd())
+	cmd.AddCommand(newSecretsSetCmd())
+	cmd.AddCommand(newSecretsDeleteCmd())
+	return cmd
+}
+
+func newSecretsListCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List secrets (names only)",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			api, err := clientFromCmd(cmd)
+			if err != nil {
+				return err
+			}
+			ctx, cancel := context.WithTimeout(cmd.Context(), 15*time.Second)
+			defer cancel()
+
+			secrets, err := api.ListSecrets(ctx)
+			if err != nil {
+				return err
+			}
+			if done, err := renderNames(cmd, secrets, func() []string {
+				names := make([]string, 0, len(secrets))
+				for _, secret := range secrets {
+					names = append(names, secret.Name)
+				}
+				return names
+			}); done {
+				return err
+			}
+			if len(secrets) == 0 {
+				fmt.Fprintln(cmd.OutOrStdout(), "No secrets found")
+				return nil
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "%-32s %-20s\n", "NAME", "UPDATED")
+			for _, secret := range secrets {
+				fmt.Fprintf(cmd.OutOrStdout(), "%-32s %-20s\n", secret.Name, secret.UpdatedAt.Local().Format("2006-01-02 15:04:05"))
+			}
+			return nil
+		},
+	}
+	return cmd
+}
+
+func newSecretsSetCmd() *cobra.Command {
+	var value string
+	var fromFile string
+	var existing bool
+	cmd := &cobra.Command{
+		Use:   "set <name>",
+		Short: "Store a secret, replacing any existing value",
+		Long: `Store a secret, replacing any existing value. The value comes from
+--value, --from-file or, when neither is given, standard input. VMs pick up
+a new value the next time their seed image is rendered or their agent boots.
+
+Examples:
+  volar secrets set db-password --from-file ./password.txt
+  printf %s "$TOKEN" | volar secrets set registry-token`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if value != "" && fromFile != "" {
+				return fmt.Errorf("--value and --from-file are mutually exclusive")
+			}
+			if value == "" {
+				var data []byte
+				var err error
+				if fromFile != "" {
+					data, err = os.ReadFile(fromFile)
+				} else {
+					data, err = io.ReadAll(cmd.InOrStdin())
+				}
+				if err != nil {
+					return fmt.Errorf("read secret value: %w", err)
+				}
+				// Drop the newline editors and echo leave behind.
+				value = strings.TrimRight(string(data), "\r\n")
+			}
+
+			api, err := clientFromCmd(cmd)
+			if err != nil {
+				return err
+			}
+			ctx, cancel := context.WithTimeout(cmd.Context(), 15*time.Second)
+			defer cancel()
+
+			put := api.PutSecret
+			if existing {
+				put = api.UpdateSecret
+			}
+			secret, err := put(ctx, args[0], value)
+			if err != nil {
+				return err
+			}
+			return renderResult(cmd, secret, "Secret %s stored", secret.Name)
+		},
+	}
+	cmd.Flags().StringVar(&value, "value", "", "Secret value (visible in shell history; prefer --from-file or stdin)")
+	cmd.Flags().StringVar(&fromFile, "from-file", "", "Read the value from a file")
+	cmd.Flags().BoolVar(&existing, "existing", false, "Only replace an existing secret; fail when it does not exist")
+	return cmd
+}
+
+func newSecretsDeleteCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "delete <name>",
+		Short: "Delete a secret",
+		Long: `Delete a secret. VMs already rendered with it keep the value in their seed
+image until it is rebuilt; templates referencing it fail to render.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			api, err := clientFromCmd(cmd)
+			if err != nil {
+				return err
+			}
+			ctx, cancel := context.WithTimeout(cmd.Context(), 15*time.Second)
+			defer cancel()
+
+			if err := api.DeleteSecret(ctx, args[0]); err != nil {
+				return err
+			}
+			infof(cmd, "Secret %s deleted", args[0])
+			return nil
+		},
+	}
+	return cmd
+}
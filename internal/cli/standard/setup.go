@@ -8,7 +8,6 @@ package standard
 import (
 	"context"
 	"fmt"
-	"net"
 	"os"
 	"path/filepath"
 	"time"
@@ -22,6 +21,9 @@ func newSetupCmd() *cobra.Command {
 	var bridge string
 	var subnet string
 	var hostIP string
+	var subnet6 string
+	var hostIP6 string
+	var ndpProxy string
 	var dryRun bool
 	var runtimeDir string
 	var logDir string
@@ -44,11 +46,22 @@ func newSetupCmd() *cobra.Command {
 				hostIP = "192.168.127.1"
 			}
 
-			hostCIDR, err := hostCIDRFrom(subnet, hostIP)
+			hostCIDR, err := setup.HostCIDR(subnet, hostIP)
 			if err != nil {
 				return err
 			}
 
+			var hostCIDR6 string
+			if subnet6 != "" {
+				if hostIP6 == "" {
+					return fmt.Errorf("--host-ip6 is required with --subnet6")
+				}
+				hostCIDR6, err = setup.HostCIDR(subnet6, hostIP6)
+				if err != nil {
+					return err
+				}
+			}
+
 			exe, err := os.Executable()
 			if err != nil {
 				return fmt.Errorf("resolve executable: %w", err)
@@ -56,23 +69,31 @@ func newSetupCmd() *cobra.Command {
 			serverBinary := filepath.Join(filepath.Dir(exe), "volantd")
 
 			opts := setup.Options{
-				BridgeName:  bridge,
-				SubnetCIDR:  subnet,
-				HostCIDR:    hostCIDR,
-				DryRun:      dryRun,
-				RuntimeDir:  runtimeDir,
-				LogDir:      logDir,
-				ServicePath: serviceFile,
-				BinaryPath:  serverBinary,
-				WorkDir:     workDir,
-				BZImagePath: bzImage,
-				VMLinuxPath: vmlinux,
+				BridgeName:        bridge,
+				SubnetCIDR:        subnet,
+				HostCIDR:          hostCIDR,
+				Subnet6CIDR:       subnet6,
+				HostCIDR6:         hostCIDR6,
+				NDPProxyInterface: ndpProxy,
+				DryRun:            dryRun,
+				RuntimeDir:        runtimeDir,
+				LogDir:            logDir,
+				ServicePath:       serviceFile,
+				BinaryPath:        serverBinary,
+				WorkDir:           workDir,
+				BZImagePath:       bzImage,
+				VMLinuxPath:       vmlinux,
 			}
 
 			res, err := setup.Run(ctx, opts)
 			if err != nil {
 				return err
 			}
+			// setup configures this host rather than calling the API, so
+			// only --quiet applies to it.
+			if quiet(cmd) {
+				return nil
+			}
 			if res != nil && len(res.Commands) > 0 {
 				out := cmd.OutOrStdout()
 				fmt.Fprintln(out, "Commands executed:")
@@ -92,6 +113,9 @@ func newSetupCmd() *cobra.Command {
 	cmd.Flags().StringVar(&bridge, "bridge", envOrDefault("VOLANT_BRIDGE", "vbr0"), "Name of the Linux bridge to create")
 	cmd.Flags().StringVar(&subnet, "subnet", envOrDefault("VOLANT_SUBNET", "192.168.127.0/24"), "Managed subnet CIDR")
 	cmd.Flags().StringVar(&hostIP, "host-ip", envOrDefault("volant_HOST_IP", "192.168.127.1"), "Host IP address inside the bridge subnet")
+	cmd.Flags().StringVar(&subnet6, "subnet6", os.Getenv("VOLANT_SUBNET6"), "Optional IPv6 subnet CIDR for dual-stack guests")
+	cmd.Flags().StringVar(&hostIP6, "host-ip6", os.Getenv("VOLANT_HOST_IP6"), "Host IPv6 address inside the IPv6 subnet")
+	cmd.Flags().StringVar(&ndpProxy, "ndp-proxy-iface", os.Getenv("VOLANT_NDP_PROXY_IFACE"), "Uplink to publish guest IPv6 addresses on via NDP proxy instead of NAT")
 	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Print commands without executing them")
 	cmd.Flags().StringVar(&runtimeDir, "runtime-dir", envOrDefault("VOLANT_RUNTIME_DIR", "~/.volant/run"), "Runtime directory for VM sockets")
 	cmd.Flags().StringVar(&logDir, "log-dir", envOrDefault("VOLANT_LOG_DIR", "~/.volant/logs"), "Log directory for VM logs")
@@ -102,16 +126,3 @@ func newSetupCmd() *cobra.Command {
 
 	return cmd
 }
-
-func hostCIDRFrom(subnet, host string) (string, error) {
-	_, network, err := net.ParseCIDR(subnet)
-	if err != nil {
-		return "", fmt.Errorf("invalid subnet %s: %w", subnet, err)
-	}
-	ip := net.ParseIP(host)
-	if ip == nil {
-		return "", fmt.Errorf("invalid host ip %s", host)
-	}
-	mask, _ := network.Mask.Size()
-	return fmt.Sprintf("%s/%d", ip.String(), mask), nil
-}
@@ -0,0 +1,239 @@
+// Copyright (c) 2025 HYPR. PTE. LTD.
+//
+// Business Source License 1.1
+// See LICENSE file in the project root for details.
+
+package standard
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/volantvm/volant/internal/server/orchestrator/vmconfig"
+)
+
+// sshProbeTimeout bounds the check for whether a VM is reachable directly.
+const sshProbeTimeout = 2 * time.Second
+
+func newVMsSSHCmd() *cobra.Command {
+	var (
+		user     string
+		identity string
+		tunnel   bool
+	)
+	cmd := &cobra.Command{
+		Use:   "ssh <name> [-- ssh-args...]",
+		Short: "Open an SSH session to a microVM",
+		Long: `Open an SSH session to a microVM with the system ssh client.
+
+volar connects to the VM's address when it is reachable and otherwise
+tunnels through volantd over a WebSocket; --tunnel always tunnels. When the
+VM config sets ssh.generate_key, the private key volantd holds for it is
+fetched, used for the session and removed afterwards. Host keys are recorded
+under the alias volant-<name>; remove a stale one with
+"ssh-keygen -R volant-<name>" after recreating a VM.
+
+Arguments after -- are passed to ssh, so a command can be run remotely.
+
+Examples:
+  volar vms ssh web-1
+  volar vms ssh web-1 --user ubuntu -i ~/.ssh/id_ed25519
+  volar vms ssh web-1 --tunnel -- uptime`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			name := args[0]
+			sshPath, err := exec.LookPath("ssh")
+			if err != nil {
+				return fmt.Errorf("ssh client not found: %w", err)
+			}
+			api, err := clientFromCmd(cmd)
+			if err != nil {
+				return err
+			}
+			ctx, cancel := context.WithTimeout(cmd.Context(), 15*time.Second)
+			defer cancel()
+
+			access, err := api.GetVMSSH(ctx, name)
+			if err != nil {
+				return err
+			}
+			if user == "" {
+				user = access.User
+			}
+			sshArgs := []string{
+				"-o", "HostKeyAlias=volant-" + name,
+				"-o", "StrictHostKeyChecking=accept-new",
+				"-p", strconv.Itoa(access.Port),
+				"-l", user,
+			}
+
+			switch {
+			case identity != "":
+				sshArgs = append(sshArgs, "-i", identity)
+			case access.GeneratedKey:
+				privateKey, err := api.GetVMSSHKey(ctx, name)
+				if err != nil {
+					return err
+				}
+				dir, err := os.MkdirTemp("", "volar-ssh-")
+				if err != nil {
+					return err
+				}
+				defer os.RemoveAll(dir)
+				keyPath := filepath.Join(dir, "id_ed25519")
+				if err := os.WriteFile(keyPath, []byte(privateKey), 0o600); err != nil {
+					return fmt.Errorf("write ssh key: %w", err)
+				}
+				sshArgs = append(sshArgs, "-i", keyPath, "-o", "IdentitiesOnly=yes")
+			}
+
+			host := access.Host
+			if !tunnel && !reachable(host, access.Port) {
+				fmt.Fprintf(cmd.ErrOrStderr(), "volar: %s is not reachable directly; tunneling through volantd\n", name)
+				tunnel = true
+			}
+			if tunnel {
+				proxy, err := sshProxyCommand(cmd, name)
+				if err != nil {
+					return err
+				}
+				sshArgs = append(sshArgs, "-o", "ProxyCommand="+proxy)
+				host = "volant-" + name
+			}
+			sshArgs = append(sshArgs, host)
+			sshArgs = append(sshArgs, args[1:]...)
+
+			session := exec.CommandContext(cmd.Context(), sshPath, sshArgs...)
+			session.Stdin = cmd.InOrStdin()
+			session.Stdout = cmd.OutOrStdout()
+			session.Stderr = cmd.ErrOrStderr()
+			if err := session.Run(); err != nil {
+				var exitErr *exec.ExitError
+				if errors.As(err, &exitErr) {
+					cmd.SilenceErrors = true
+					cmd.SilenceUsage = true
+					return &ExitError{Code: exitErr.ExitCode()}
+				}
+				return err
+			}
+			return nil
+		},
+	}
+	cmd.Flags().StringVarP(&user, "user", "l", "", "Login user (defaults to the VM's ssh.user or root)")
+	cmd.Flags().StringVarP(&identity, "identity", "i", "", "Private key file to authenticate with instead of the generated key")
+	cmd.Flags().BoolVar(&tunnel, "tunnel", false, "Always tunnel through volantd instead of connecting to the VM address")
+	cmd.ValidArgsFunction = completeArgs(completeVMNames)
+	return cmd
+}
+
+// newVMsSSHProxyCmd relays standard input and output to a VM's SSH port
+// through volantd. `volar vms ssh --tunnel` runs it as ssh's ProxyCommand.
+func newVMsSSHProxyCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:    "ssh-proxy <name>",
+		Short:  "Relay stdin and stdout to a VM's SSH port through volantd",
+		Hidden: true,
+		Args:   cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			api, err := clientFromCmd(cmd)
+			if err != nil {
+				return err
+			}
+			conn, err := api.DialSSH(cmd.Context(), args[0])
+			if err != nil {
+				return err
+			}
+			defer conn.Close()
+
+			errCh := make(chan error, 2)
+			go func() {
+				_, err := io.Copy(conn, cmd.InOrStdin())
+				errCh <- err
+			}()
+			go func() {
+				_, err := io.Copy(cmd.OutOrStdout(), conn)
+				errCh <- err
+			}()
+			if err := <-errCh; err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, net.ErrClosed) {
+				return err
+			}
+			return nil
+		},
+	}
+	cmd.ValidArgsFunction = completeArgs(completeVMNames)
+	return cmd
+}
+
+// sshConfigFromFlags builds the SSH settings of a new VM from public key
+// files and --ssh-generate-key, or returns nil when neither is given.
+func sshConfigFromFlags(keyFiles []string, generate bool) (*vmconfig.SSH, error) {
+	if len(keyFiles) == 0 && !generate {
+		return nil, nil
+	}
+	cfg := &vmconfig.SSH{GenerateKey: generate}
+	for _, path := range keyFiles {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("read ssh key: %w", err)
+		}
+		for _, line := range strings.Split(string(data), "\n") {
+			if line = strings.TrimSpace(line); line != "" && !strings.HasPrefix(line, "#") {
+				cfg.AuthorizedKeys = append(cfg.AuthorizedKeys, line)
+			}
+		}
+	}
+	return cfg, nil
+}
+
+// reachable reports whether host:port accepts TCP connections from here.
+func reachable(host string, port int) bool {
+	if host == "" {
+		return false
+	}
+	conn, err := net.DialTimeout("tcp", net.JoinHostPort(host, strconv.Itoa(port)), sshProbeTimeout)
+	if err != nil {
+		return false
+	}
+	conn.Close()
+	return true
+}
+
+// sshProxyCommand returns the ProxyCommand running this volar binary's
+// ssh-proxy against the same API.
+func sshProxyCommand(cmd *cobra.Command, name string) (string, error) {
+	self, err := os.Executable()
+	if err != nil {
+		return "", fmt.Errorf("locate volar: %w", err)
+	}
+	parts := []string{self}
+	// Pass on the connection flags; the environment and the config file
+	// reach the proxy by themselves.
+	flags := cmd.Root().PersistentFlags()
+	if name, _ := flags.GetString("context"); name != "" {
+		parts = append(parts, "--context", name)
+	}
+	if base, _ := flags.GetString("api"); flags.Changed("api") {
+		parts = append(parts, "--api", base)
+	}
+	parts = append(parts, "vms", "ssh-proxy", name)
+	for i, part := range parts {
+		parts[i] = shellQuote(part)
+	}
+	return strings.Join(parts, " "), nil
+}
+
+// shellQuote quotes s for the shell ssh runs a ProxyCommand with.
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
@@ -0,0 +1,460 @@
+// Copyright (c) 2025 HYPR. PTE. LTD.
+//
+// Business Source License 1.1
+// See LICENSE file in the project root for details.
+
+package standard
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/volantvm/volant/pkg/volantclient"
+)
+
+func newSystemCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "system",
+		Short: "Back up the control plane database, inspect host capacity, prerequisites and cleanup, reload configuration, put the host in maintenance, and manage agent binaries",
+	}
+	cmd.AddCommand(newSystemBackupCmd())
+	cmd.AddCommand(newSystemBackupsCmd())
+	cmd.AddCommand(newSystemRestoreCmd())
+	cmd.AddCommand(newSystemGCCmd())
+	cmd.AddCommand(newSystemCapacityCmd())
+	cmd.AddCommand(newSystemDoctorCmd())
+	cmd.AddCommand(newSystemReloadCmd())
+	cmd.AddCommand(newSystemMaintenanceCmd())
+	cmd.AddCommand(newSystemAgentCmd())
+	return cmd
+}
+
+func newSystemReloadCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "reload",
+		Short: "Reload the daemon configuration without restarting",
+		Long: `Make volantd re-read its config file, as SIGHUP does. The API key,
+client CIDR allowlist, CORS origins and log level take effect immediately;
+other changed settings are listed and need a restart. Running VMs are not
+touched.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			api, err := clientFromCmd(cmd)
+			if err != nil {
+				return err
+			}
+			ctx, cancel := context.WithTimeout(cmd.Context(), 15*time.Second)
+			defer cancel()
+
+			result, err := api.ReloadConfig(ctx)
+			if err != nil {
+				return err
+			}
+			if done, err := render(cmd, result); done {
+				return err
+			}
+			out := cmd.OutOrStdout()
+			if len(result.Applied) == 0 && len(result.RestartRequired) == 0 {
+				fmt.Fprintln(out, "Configuration reloaded, nothing changed")
+				return nil
+			}
+			fmt.Fprintln(out, "Configuration reloaded")
+			if len(result.Applied) > 0 {
+				fmt.Fprintf(out, "Applied: %s\n", strings.Join(result.Applied, ", "))
+			}
+			if len(result.RestartRequired) > 0 {
+				fmt.Fprintf(out, "Restart required: %s\n", strings.Join(result.RestartRequired, ", "))
+			}
+			return nil
+		},
+	}
+	return cmd
+}
+
+func newSystemMaintenanceCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "maintenance",
+		Short: "Show, enter or exit host maintenance mode",
+		Long: `Host maintenance mode stops every running VM for host kernel upgrades or
+hardware work, refuses new VMs and holds deployment reconciles and schedules.
+Exiting restarts the VMs it stopped. The state survives a daemon restart, so
+the host can be rebooted in between.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			api, err := clientFromCmd(cmd)
+			if err != nil {
+				return err
+			}
+			ctx, cancel := context.WithTimeout(cmd.Context(), 15*time.Second)
+			defer cancel()
+
+			status, err := api.HostMaintenance(ctx)
+			if err != nil {
+				return err
+			}
+			return renderHostMaintenance(cmd, status)
+		},
+	}
+	cmd.AddCommand(newSystemMaintenanceEnterCmd())
+	cmd.AddCommand(newSystemMaintenanceExitCmd())
+	return cmd
+}
+
+func newSystemMaintenanceEnterCmd() *cobra.Command {
+	var (
+		reason      string
+		concurrency int
+	)
+	cmd := &cobra.Command{
+		Use:   "enter",
+		Short: "Stop all running VMs and refuse new ones",
+		Long: `Put the host in maintenance: new VMs are refused, deployment reconciles
+and schedules are held, and every running VM is stopped, running its
+pre-stop hooks, --concurrency at a time. Running it again while engaged
+stops VMs started since. Exits non-zero when a VM could not be stopped.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			api, err := clientFromCmd(cmd)
+			if err != nil {
+				return err
+			}
+			ctx, cancel := context.WithTimeout(cmd.Context(), 30*time.Minute)
+			defer cancel()
+
+			status, err := api.EnterHostMaintenance(ctx, reason, concurrency)
+			if err != nil {
+				return err
+			}
+			return renderHostMaintenance(cmd, status)
+		},
+	}
+	cmd.Flags().StringVar(&reason, "reason", "", "Why the host is going into maintenance")
+	cmd.Flags().IntVar(&concurrency, "concurrency", 0, "VMs to stop at once (default 4)")
+	return cmd
+}
+
+func newSystemMaintenanceExitCmd() *cobra.Command {
+	var concurrency int
+	cmd := &cobra.Command{
+		Use:   "exit",
+		Short: "Leave maintenance and restart the VMs it stopped",
+		Long: `End host maintenance: held reconciles and schedules replay, new VMs are
+accepted again and the VMs maintenance stopped are restarted, --concurrency
+at a time. Exits non-zero when a VM could not be restarted.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			api, err := clientFromCmd(cmd)
+			if err != nil {
+				return err
+			}
+			ctx, cancel := context.WithTimeout(cmd.Context(), 30*time.Minute)
+			defer cancel()
+
+			status, err := api.ExitHostMaintenance(ctx, concurrency)
+			if err != nil {
+				return err
+			}
+			return renderHostMaintenance(cmd, status)
+		},
+	}
+	cmd.Flags().IntVar(&concurrency, "concurrency", 0, "VMs to restart at once (default 4)")
+	return cmd
+}
+
+// renderHostMaintenance prints the maintenance state and fails the command
+// when a VM could not be stopped or restarted.
+func renderHostMaintenance(cmd *cobra.Command, status *volantclient.HostMaintenance) error {
+	failed := false
+	for _, vm := range status.VMs {
+		if vm.Error != "" {
+			failed = true
+		}
+	}
+	if done, err := render(cmd, status); !done {
+		out := cmd.OutOrStdout()
+		switch {
+		case status.Engaged:
+			fmt.Fprintf(out, "Host in maintenance since %s: %s\n", status.Since.Local().Format(time.RFC3339), status.Reason)
+		case status.EndedAt != nil:
+			fmt.Fprintf(out, "Host not in maintenance; last maintenance ended %s\n", status.EndedAt.Local().Format(time.RFC3339))
+		default:
+			fmt.Fprintln(out, "Host not in maintenance")
+		}
+		if len(status.VMs) > 0 {
+			fmt.Fprintf(out, "%-24s %-20s %s\n", "VM", "STOPPED", "STATUS")
+			for _, vm := range status.VMs {
+				state := vm.Outcome
+				switch {
+				case vm.Error != "":
+					state = "error: " + vm.Error
+				case state == "" && status.Engaged:
+					state = "stopped"
+				case state == "":
+					state = "restart pending"
+				}
+				fmt.Fprintf(out, "%-24s %-20s %s\n", vm.Name, vm.StoppedAt.Local().Format("2006-01-02 15:04:05"), state)
+			}
+		}
+	} else if err != nil {
+		return err
+	}
+	if failed {
+		cmd.SilenceErrors = true
+		cmd.SilenceUsage = true
+		return &ExitError{Code: 1}
+	}
+	return nil
+}
+
+func newSystemDoctorCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "doctor",
+		Short: "Check the host prerequisites of volantd",
+		Long: `Check KVM, the cloud-hypervisor binary, the bridge, /dev/net/tun, IP
+forwarding, vhost-vsock, the IOMMU and free disk space on the volantd host.
+Each check passes, warns (needed by some workloads only) or fails, with a
+hint on how to fix it. Exits non-zero when a check fails.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			api, err := clientFromCmd(cmd)
+			if err != nil {
+				return err
+			}
+			ctx, cancel := context.WithTimeout(cmd.Context(), 30*time.Second)
+			defer cancel()
+
+			report, err := api.Doctor(ctx)
+			if err != nil {
+				return err
+			}
+			if done, err := render(cmd, report); !done {
+				out := cmd.OutOrStdout()
+				fmt.Fprintf(out, "%-6s %-14s %s\n", "STATUS", "CHECK", "DETAIL")
+				for _, check := range report.Checks {
+					fmt.Fprintf(out, "%-6s %-14s %s\n", strings.ToUpper(check.Status), check.Name, check.Detail)
+					if check.Hint != "" {
+						fmt.Fprintf(out, "%-6s %-14s -> %s\n", "", "", check.Hint)
+					}
+				}
+			} else if err != nil {
+				return err
+			}
+			if !report.Healthy {
+				cmd.SilenceErrors = true
+				cmd.SilenceUsage = true
+				return &ExitError{Code: 1}
+			}
+			return nil
+		},
+	}
+	return cmd
+}
+
+func newSystemBackupCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "backup",
+		Short: "Back up the database now",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			api, err := clientFromCmd(cmd)
+			if err != nil {
+				return err
+			}
+			ctx, cancel := context.WithTimeout(cmd.Context(), 5*time.Minute)
+			defer cancel()
+
+			backup, err := api.CreateBackup(ctx)
+			if err != nil {
+				return err
+			}
+			return renderResult(cmd, backup, "Backup %s created (%s)", backup.Name, formatBytes(backup.Size))
+		},
+	}
+	return cmd
+}
+
+func newSystemBackupsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "backups",
+		Short: "List database backups",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			api, err := clientFromCmd(cmd)
+			if err != nil {
+				return err
+			}
+			ctx, cancel := context.WithTimeout(cmd.Context(), 15*time.Second)
+			defer cancel()
+
+			backups, err := api.ListBackups(ctx)
+			if err != nil {
+				return err
+			}
+			if done, err := renderNames(cmd, backups, func() []string {
+				names := make([]string, 0, len(backups))
+				for _, backup := range backups {
+					names = append(names, backup.Name)
+				}
+				return names
+			}); done {
+				return err
+			}
+			if len(backups) == 0 {
+				fmt.Fprintln(cmd.OutOrStdout(), "No backups found")
+				return nil
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "%-34s %-10s %-20s\n", "NAME", "SIZE", "CREATED")
+			for _, backup := range backups {
+				fmt.Fprintf(cmd.OutOrStdout(), "%-34s %-10s %-20s\n", backup.Name, formatBytes(backup.Size), backup.CreatedAt.Local().Format("2006-01-02 15:04:05"))
+			}
+			return nil
+		},
+	}
+	return cmd
+}
+
+func newSystemRestoreCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "restore <backup>",
+		Short: "Restore the database from a backup",
+		Long: `Restore the control plane database from a backup listed by
+"volar system backups". volantd backs up the current state first, so a
+mistaken restore can itself be undone. Restart volantd afterwards so VM and
+network state is reloaded from the restored database.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			api, err := clientFromCmd(cmd)
+			if err != nil {
+				return err
+			}
+			ctx, cancel := context.WithTimeout(cmd.Context(), 5*time.Minute)
+			defer cancel()
+
+			result, err := api.RestoreBackup(ctx, args[0])
+			if err != nil {
+				return err
+			}
+			if result.Message != "" {
+				return renderResult(cmd, result, "Restored %s (previous state saved as %s)\n%s", result.Restored.Name, result.Safety.Name, result.Message)
+			}
+			return renderResult(cmd, result, "Restored %s (previous state saved as %s)", result.Restored.Name, result.Safety.Name)
+		},
+	}
+	return cmd
+}
+
+func newSystemGCCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "gc",
+		Short: "Show what the last garbage collection pass reclaimed",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			api, err := clientFromCmd(cmd)
+			if err != nil {
+				return err
+			}
+			ctx, cancel := context.WithTimeout(cmd.Context(), 15*time.Second)
+			defer cancel()
+
+			status, err := api.GarbageCollection(ctx)
+			if err != nil {
+				return err
+			}
+			if done, err := render(cmd, status); done {
+				return err
+			}
+			out := cmd.OutOrStdout()
+			interval := time.Duration(status.IntervalSeconds) * time.Second
+			run := status.LastRun
+			if run == nil {
+				fmt.Fprintf(out, "No garbage collection pass yet (runs every %s)\n", interval)
+				return nil
+			}
+			fmt.Fprintf(out, "Last pass: %s (runs every %s)\n", run.FinishedAt.Local().Format("2006-01-02 15:04:05"), interval)
+			for _, group := range []struct {
+				label string
+				items []string
+			}{{"Taps", run.Taps}, {"Sockets", run.Sockets}, {"Seed images", run.SeedImages}} {
+				fmt.Fprintf(out, "%s reclaimed: %d\n", group.label, len(group.items))
+				for _, item := range group.items {
+					fmt.Fprintf(out, "  %s\n", item)
+				}
+			}
+			if run.Pending > 0 {
+				fmt.Fprintf(out, "Pending: %d (removed next pass if still unowned)\n", run.Pending)
+			}
+			for _, msg := range run.Errors {
+				fmt.Fprintf(out, "Error: %s\n", msg)
+			}
+			return nil
+		},
+	}
+	return cmd
+}
+
+func newSystemCapacityCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "capacity",
+		Short: "Show host CPU and memory committed to VMs",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			api, err := clientFromCmd(cmd)
+			if err != nil {
+				return err
+			}
+			ctx, cancel := context.WithTimeout(cmd.Context(), 15*time.Second)
+			defer cancel()
+
+			capacity, err := api.Capacity(ctx)
+			if err != nil {
+				return err
+			}
+			if done, err := render(cmd, capacity); done {
+				return err
+			}
+			out := cmd.OutOrStdout()
+			fmt.Fprintf(out, "VMs holding resources: %d\n", capacity.CommittedVMs)
+			fmt.Fprintf(out, "%-10s %-8s %-10s %-10s %-10s %-10s\n", "RESOURCE", "TOTAL", "THRESHOLD", "ALLOWED", "COMMITTED", "AVAILABLE")
+			for _, row := range []struct {
+				name               string
+				total, committed   int
+				threshold          int
+				allowed, available *int
+			}{
+				{"cpus", capacity.TotalCPUs, capacity.CommittedCPUs, capacity.CPUThresholdPercent, capacity.AllowedCPUs, capacity.AvailableCPUs},
+				{"memory_mb", capacity.TotalMemoryMB, capacity.CommittedMemoryMB, capacity.MemoryThresholdPercent, capacity.AllowedMemoryMB, capacity.AvailableMemoryMB},
+			} {
+				threshold, allowed, available := "-", "unlimited", "unlimited"
+				if row.threshold > 0 {
+					threshold = fmt.Sprintf("%d%%", row.threshold)
+				}
+				if row.allowed != nil {
+					allowed = fmt.Sprint(*row.allowed)
+				}
+				if row.available != nil {
+					available = fmt.Sprint(*row.available)
+				}
+				fmt.Fprintf(out, "%-10s %-8d %-10s %-10s %-10d %-10s\n", row.name, row.total, threshold, allowed, row.committed, available)
+			}
+			return nil
+		},
+	}
+	return cmd
+}
+
+func formatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
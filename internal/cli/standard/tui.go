@@ -0,0 +1,32 @@
+// Copyright (c) 2025 HYPR. PTE. LTD.
+//
+// Business Source License 1.1
+// See LICENSE file in the project root for details.
+
+package standard
+
+import (
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/volantvm/volant/internal/cli/tui"
+)
+
+func newTUICmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "tui",
+		Short: "Open the interactive terminal UI",
+		Long: `Open the interactive terminal UI: the VM list with live lifecycle events,
+per-VM log, console and plugin action panes, and deployment scaling. Plugin
+actions are forms generated from each VM's OpenAPI document.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			api, err := clientFromCmd(cmd)
+			if err != nil {
+				return err
+			}
+			return tui.Run(cmd.Context(), api, os.Stdin, os.Stdout)
+		},
+	}
+}
@@ -0,0 +1,146 @@
+// Copyright (c) 2025 HYPR. PTE. LTD.
+//
+// Business Source License 1.1
+// See LICENSE file in the project root for details.
+
+package standard
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/volantvm/volant/internal/pluginspec"
+	"github.com/volantvm/volant/internal/server/demo"
+	"github.com/volantvm/volant/pkg/volantclient"
+)
+
+const demoVMName = "demo-1"
+
+func newUpCmd() *cobra.Command {
+	var demoMode, realVMs bool
+	var manifestPath string
+	cmd := &cobra.Command{
+		Use:   "up",
+		Short: "Start a throwaway local control plane",
+		Long: `Start volantd inside this process with a temporary database, install a
+plugin and boot one VM. Nothing needs to be configured beforehand. Press
+Ctrl-C to tear everything down; all state is removed on exit.
+
+VMs are simulated unless --real is set and the host is prepared for
+cloud-hypervisor (root, kernel installed, bridge from 'volar setup'). The
+bundled sample plugin has no boot image, so real VMs need --manifest.
+
+Examples:
+  volar up --demo
+  sudo volar up --demo --real --manifest nginx.manifest.json`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if !demoMode {
+				return fmt.Errorf("only --demo is supported")
+			}
+			manifest := demo.SampleManifest()
+			if manifestPath != "" {
+				loaded, err := loadDemoManifest(manifestPath)
+				if err != nil {
+					return err
+				}
+				manifest = loaded
+			} else if realVMs {
+				return fmt.Errorf("--real requires --manifest: the sample plugin has no boot image")
+			}
+
+			ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+			defer stop()
+
+			out := cmd.OutOrStdout()
+			server, err := demo.Start(ctx, demo.Options{Real: realVMs})
+			if err != nil {
+				return err
+			}
+			defer func() {
+				fmt.Fprintln(out, "Tearing down demo...")
+				if err := server.Close(); err != nil {
+					fmt.Fprintf(cmd.ErrOrStderr(), "teardown: %v\n", err)
+				}
+			}()
+			launcher := "cloud-hypervisor"
+			if server.Mock {
+				launcher = "mock (VMs are simulated)"
+			}
+			fmt.Fprintf(out, "volantd (demo) listening on %s\n", server.BaseURL)
+			fmt.Fprintf(out, "Launcher: %s\n", launcher)
+			fmt.Fprintf(out, "State:    %s\n", server.Dir)
+
+			api, err := volantclient.New(server.BaseURL)
+			if err != nil {
+				return err
+			}
+			if err := runDemo(ctx, cmd, api, manifest); err != nil {
+				return err
+			}
+
+			fmt.Fprintf(out, "\nTry it from another terminal:\n")
+			fmt.Fprintf(out, "  volar --api %s vms list\n", server.BaseURL)
+			fmt.Fprintf(out, "  volar --api %s vms get %s\n", server.BaseURL, demoVMName)
+			fmt.Fprintf(out, "\nPress Ctrl-C to stop and remove all demo state.\n")
+			<-ctx.Done()
+
+			cleanupCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+			defer cancel()
+			if err := api.DeleteVM(cleanupCtx, demoVMName); err != nil {
+				fmt.Fprintf(cmd.ErrOrStderr(), "delete %s: %v\n", demoVMName, err)
+			}
+			return nil
+		},
+	}
+	cmd.Flags().BoolVar(&demoMode, "demo", false, "Run an embedded, disposable volantd with a sample VM")
+	cmd.Flags().BoolVar(&realVMs, "real", false, "Boot real microVMs when the host supports it")
+	cmd.Flags().StringVar(&manifestPath, "manifest", "", "Plugin manifest to install instead of the sample plugin")
+	return cmd
+}
+
+func runDemo(ctx context.Context, cmd *cobra.Command, api *volantclient.Client, manifest pluginspec.Manifest) error {
+	out := cmd.OutOrStdout()
+	stepCtx, cancel := context.WithTimeout(ctx, 5*time.Minute)
+	defer cancel()
+
+	if err := api.InstallPlugin(stepCtx, manifest); err != nil {
+		return fmt.Errorf("install plugin %s: %w", manifest.Name, err)
+	}
+	fmt.Fprintf(out, "Installed plugin %s %s\n", manifest.Name, manifest.Version)
+
+	vm, err := api.CreateVM(stepCtx, volantclient.CreateVMRequest{
+		Name:     demoVMName,
+		Plugin:   manifest.Name,
+		CPUCores: manifest.Resources.CPUCores,
+		MemoryMB: manifest.Resources.MemoryMB,
+	})
+	if err != nil {
+		return fmt.Errorf("create vm %s: %w", demoVMName, err)
+	}
+	fmt.Fprintf(out, "Booted VM %s (status=%s ip=%s)\n", vm.Name, vm.Status, vm.IPAddress)
+	return nil
+}
+
+func loadDemoManifest(path string) (pluginspec.Manifest, error) {
+	var manifest pluginspec.Manifest
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return manifest, fmt.Errorf("read manifest: %w", err)
+	}
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return manifest, fmt.Errorf("decode manifest: %w", err)
+	}
+	if err := resolveManifestPaths(&manifest, path); err != nil {
+		return manifest, err
+	}
+	manifest.Normalize()
+	manifest.Enabled = true
+	return manifest, manifest.Validate()
+}
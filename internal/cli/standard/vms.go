@@ -16,25 +16,21 @@ import (
 	"net/url"
 	"os"
 	"os/signal"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
 
 	"github.com/spf13/cobra"
 
-	"github.com/volantvm/volant/internal/cli/client"
 	"github.com/volantvm/volant/internal/cli/openapiutil"
 	"github.com/volantvm/volant/internal/pluginspec"
+	"github.com/volantvm/volant/internal/server/labels"
 	"github.com/volantvm/volant/internal/server/orchestrator/vmconfig"
+	"github.com/volantvm/volant/pkg/volantclient"
 	"golang.org/x/term"
 )
 
-func encodeAsJSON(out io.Writer, payload interface{}) error {
-	enc := json.NewEncoder(out)
-	enc.SetIndent("", "  ")
-	return enc.Encode(payload)
-}
-
 func newVMsCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "vms",
@@ -46,17 +42,34 @@ func newVMsCmd() *cobra.Command {
 	cmd.AddCommand(newVMsDeleteCmd())
 	cmd.AddCommand(newVMsGetCmd())
 	cmd.AddCommand(newVMsConsoleCmd())
+	cmd.AddCommand(newVMsConsoleLogCmd())
+	cmd.AddCommand(newVMsLogsCmd())
+	cmd.AddCommand(newVMsEventsCmd())
 	cmd.AddCommand(newVMsOperationsCmd())
 	cmd.AddCommand(newVMsCallCmd())
 	cmd.AddCommand(newVMsStartCmd())
 	cmd.AddCommand(newVMsStopCmd())
 	cmd.AddCommand(newVMsRestartCmd())
+	cmd.AddCommand(newVMsDuplicateCmd())
+	cmd.AddCommand(newVMsCloneCmd())
+	cmd.AddCommand(newVMsClaimCmd())
 	cmd.AddCommand(newVMsScaleCmd())
 	cmd.AddCommand(newVMsConfigCmd())
+	cmd.AddCommand(newVMsPortsCmd())
+	cmd.AddCommand(newVMsSysInfoCmd())
+	cmd.AddCommand(newVMsMetricsCmd())
+	cmd.AddCommand(newVMsPushCmd())
+	cmd.AddCommand(newVMsPullCmd())
+	cmd.AddCommand(newVMsExecCmd())
+	cmd.AddCommand(newVMsSSHCmd())
+	cmd.AddCommand(newVMsSSHProxyCmd())
+	cmd.AddCommand(newVMsLabelCmd())
+	cmd.AddCommand(newVMsBulkCmd())
+	cmd.AddCommand(newVMsWatchCmd())
 	return cmd
 }
 
-func resolveConsoleSocket(ctx context.Context, api *client.Client, vmName, socketOverride string, useConsole bool) (string, string, error) {
+func resolveConsoleSocket(ctx context.Context, api *volantclient.Client, vmName, socketOverride string, useConsole bool) (string, string, error) {
 	vm, err := api.GetVM(ctx, vmName)
 	if err != nil {
 		return "", "", err
@@ -79,6 +92,7 @@ func resolveConsoleSocket(ctx context.Context, api *client.Client, vmName, socke
 }
 
 func newVMsListCmd() *cobra.Command {
+	var selector string
 	cmd := &cobra.Command{
 		Use:   "list",
 		Short: "List microVMs",
@@ -90,10 +104,24 @@ func newVMsListCmd() *cobra.Command {
 			ctx, cancel := context.WithTimeout(cmd.Context(), 10*time.Second)
 			defer cancel()
 
-			vms, err := api.ListVMs(ctx)
+			var vms []volantclient.VM
+			if selector != "" {
+				vms, err = api.ListVMsBySelector(ctx, selector)
+			} else {
+				vms, err = api.ListVMs(ctx)
+			}
 			if err != nil {
 				return err
 			}
+			if done, err := renderNames(cmd, vms, func() []string {
+				names := make([]string, 0, len(vms))
+				for _, vm := range vms {
+					names = append(names, vm.Name)
+				}
+				return names
+			}); done {
+				return err
+			}
 			if len(vms) == 0 {
 				fmt.Fprintln(cmd.OutOrStdout(), "No VMs found")
 				return nil
@@ -105,6 +133,7 @@ func newVMsListCmd() *cobra.Command {
 			return nil
 		},
 	}
+	cmd.Flags().StringVarP(&selector, "selector", "l", "", "Label selector, e.g. env=prod,tier!=db")
 	return cmd
 }
 
@@ -125,6 +154,9 @@ func newVMsGetCmd() *cobra.Command {
 			if err != nil {
 				return err
 			}
+			if done, err := render(cmd, vm); done {
+				return err
+			}
 			fmt.Fprintf(cmd.OutOrStdout(), "Name: %s\nStatus: %s\nRuntime: %s\nIP: %s\nMAC: %s\nCPU: %d\nMemory: %d MB\n", vm.Name, vm.Status, vm.Runtime, vm.IPAddress, vm.MACAddress, vm.CPUCores, vm.MemoryMB)
 			if vm.PID != nil {
 				fmt.Fprintf(cmd.OutOrStdout(), "PID: %d\n", *vm.PID)
@@ -138,9 +170,16 @@ func newVMsGetCmd() *cobra.Command {
 			if vm.ConsoleSocket != "" {
 				fmt.Fprintf(cmd.OutOrStdout(), "Console Socket: %s\n", vm.ConsoleSocket)
 			}
+			if vm.ExpiresAt != nil {
+				fmt.Fprintf(cmd.OutOrStdout(), "Expires At: %s\n", vm.ExpiresAt.Local().Format(time.RFC3339))
+			}
+			for _, key := range labels.Keys(vm.Labels) {
+				fmt.Fprintf(cmd.OutOrStdout(), "Label: %s=%s\n", key, vm.Labels[key])
+			}
 			return nil
 		},
 	}
+	cmd.ValidArgsFunction = completeArgs(completeVMNames)
 	return cmd
 }
 
@@ -295,7 +334,47 @@ func newVMsCreateCmd() *cobra.Command {
 				return err
 			}
 
-			req := client.CreateVMRequest{
+			labelFlag, err := cmd.Flags().GetStringArray("label")
+			if err != nil {
+				return err
+			}
+			vmLabels, err := parseLabelFlags(labelFlag)
+			if err != nil {
+				return err
+			}
+			requestID, err := cmd.Flags().GetString("request-id")
+			if err != nil {
+				return err
+			}
+			sshKeyFlag, err := cmd.Flags().GetStringArray("ssh-key")
+			if err != nil {
+				return err
+			}
+			sshGenerateFlag, err := cmd.Flags().GetBool("ssh-generate-key")
+			if err != nil {
+				return err
+			}
+			sshCfg, err := sshConfigFromFlags(sshKeyFlag, sshGenerateFlag)
+			if err != nil {
+				return err
+			}
+			autostartFlag, err := cmd.Flags().GetBool("autostart")
+			if err != nil {
+				return err
+			}
+			ttlFlag, err := cmd.Flags().GetDuration("ttl")
+			if err != nil {
+				return err
+			}
+			ttlActionFlag, err := cmd.Flags().GetString("ttl-action")
+			if err != nil {
+				return err
+			}
+			if ttlFlag < 0 || (ttlFlag > 0 && ttlFlag < time.Second) {
+				return fmt.Errorf("--ttl must be at least 1s")
+			}
+
+			req := volantclient.CreateVMRequest{
 				Name:          args[0],
 				Plugin:        pluginName,
 				Runtime:       runtimeName,
@@ -304,12 +383,17 @@ func newVMsCreateCmd() *cobra.Command {
 				KernelCmdline: kernelExtra,
 				APIHost:       apiHost,
 				APIPort:       apiPort,
+				Labels:        vmLabels,
+				RequestID:     requestID,
+				TTLSeconds:    int(ttlFlag.Round(time.Second) / time.Second),
+				TTLAction:     strings.TrimSpace(ttlActionFlag),
 			}
 			if cfg != nil {
 				cfgClone := cfg.Clone()
 				cfgClone.Plugin = pluginName
 				cfgClone.Runtime = runtimeName
-				cfgClone.Resources = vmconfig.Resources{CPUCores: cpu, MemoryMB: mem}
+				cfgClone.Resources.CPUCores = cpu
+				cfgClone.Resources.MemoryMB = mem
 				cfgClone.KernelCmdline = kernelExtra
 				cfgClone.API = vmconfig.API{Host: apiHost, Port: apiPort}
 
@@ -342,7 +426,8 @@ func newVMsCreateCmd() *cobra.Command {
 			} else {
 				// Build a minimal config if CLI overrides are provided without a --config file.
 				needConfig := len(deviceFlag) > 0 || len(deviceAllowlistFlag) > 0 ||
-					strings.TrimSpace(kernelPathFlag) != "" || strings.TrimSpace(initramfsFlag) != ""
+					strings.TrimSpace(kernelPathFlag) != "" || strings.TrimSpace(initramfsFlag) != "" ||
+					sshCfg != nil || autostartFlag
 
 				if needConfig {
 					cfgClone := vmconfig.Config{
@@ -383,13 +468,30 @@ func newVMsCreateCmd() *cobra.Command {
 					req.Config = &cfgClone
 				}
 			}
+			if sshCfg != nil {
+				req.Config.SSH = sshCfg
+			}
+			if autostartFlag {
+				req.Config.Autostart = true
+			}
+
+			async, err := cmd.Flags().GetBool("async")
+			if err != nil {
+				return err
+			}
+			if async {
+				op, err := api.CreateVMAsync(ctx, req)
+				if err != nil {
+					return err
+				}
+				return renderResult(cmd, op, "VM %s creation accepted (operation %s)", req.Name, op.ID)
+			}
 
 			vm, err := api.CreateVM(ctx, req)
 			if err != nil {
 				return err
 			}
-			fmt.Fprintf(cmd.OutOrStdout(), "VM %s created with IP %s\n", vm.Name, vm.IPAddress)
-			return nil
+			return renderResult(cmd, vm, "VM %s created with IP %s", vm.Name, vm.IPAddress)
 		},
 	}
 	cmd.Flags().String("runtime", "", "Runtime type to launch (derived from plugin or config if omitted)")
@@ -400,11 +502,20 @@ func newVMsCreateCmd() *cobra.Command {
 	cmd.Flags().String("initramfs", "", "Override initramfs image path (.cpio.gz)")
 	cmd.Flags().String("initramfs-checksum", "", "Checksum for initramfs (e.g., sha256:deadbeef...) (optional)")
 	cmd.Flags().String("plugin", "", "Plugin name to use when creating the VM")
+	_ = cmd.RegisterFlagCompletionFunc("plugin", completeFlag(completePluginNames))
 	cmd.Flags().StringVar(&configPath, "config", "", "Path to a VM config JSON file")
 	cmd.Flags().String("api-host", "", "Override agent API host for the VM")
 	cmd.Flags().String("api-port", "", "Override agent API port for the VM")
 	cmd.Flags().StringSlice("device", nil, "PCI devices to pass through (e.g., 0000:01:00.0)")
 	cmd.Flags().StringSlice("device-allowlist", nil, "Device allowlist patterns (e.g., 10de:* for NVIDIA)")
+	cmd.Flags().StringArray("label", nil, "Label as KEY=VALUE (repeatable)")
+	cmd.Flags().String("request-id", "", "Idempotency key; rerunning with the same key returns the first result instead of creating again")
+	cmd.Flags().StringArray("ssh-key", nil, "Public key file (such as ~/.ssh/id_ed25519.pub) to authorize for SSH (repeatable)")
+	cmd.Flags().Bool("ssh-generate-key", false, "Have volantd generate an SSH keypair for the VM; 'volar vms ssh' uses it")
+	cmd.Flags().Bool("autostart", false, "Start the VM whenever volantd starts")
+	cmd.Flags().Duration("ttl", 0, "Stop the VM after it has run this long, e.g. 30m (default: plugin's ttl_seconds)")
+	cmd.Flags().String("ttl-action", "", "What to do when the TTL runs out: stop or delete (default stop)")
+	cmd.Flags().Bool("async", false, "Return once the request is accepted; follow progress with 'volar operations show'")
 	return cmd
 }
 
@@ -424,10 +535,11 @@ func newVMsDeleteCmd() *cobra.Command {
 			if err := api.DeleteVM(ctx, args[0]); err != nil {
 				return err
 			}
-			fmt.Fprintf(cmd.OutOrStdout(), "VM %s deleted\n", args[0])
+			infof(cmd, "VM %s deleted", args[0])
 			return nil
 		},
 	}
+	cmd.ValidArgsFunction = completeArgs(completeVMNames)
 	return cmd
 }
 
@@ -449,13 +561,12 @@ func newVMsStartCmd() *cobra.Command {
 				return err
 			}
 			if vm.PID != nil {
-				fmt.Fprintf(cmd.OutOrStdout(), "VM %s started (PID %d)\n", vm.Name, *vm.PID)
-			} else {
-				fmt.Fprintf(cmd.OutOrStdout(), "VM %s started\n", vm.Name)
+				return renderResult(cmd, vm, "VM %s started (PID %d)", vm.Name, *vm.PID)
 			}
-			return nil
+			return renderResult(cmd, vm, "VM %s started", vm.Name)
 		},
 	}
+	cmd.ValidArgsFunction = completeArgs(completeVMNames)
 	return cmd
 }
 
@@ -476,10 +587,10 @@ func newVMsStopCmd() *cobra.Command {
 			if err != nil {
 				return err
 			}
-			fmt.Fprintf(cmd.OutOrStdout(), "VM %s stopped\n", vm.Name)
-			return nil
+			return renderResult(cmd, vm, "VM %s stopped", vm.Name)
 		},
 	}
+	cmd.ValidArgsFunction = completeArgs(completeVMNames)
 	return cmd
 }
 
@@ -501,13 +612,72 @@ func newVMsRestartCmd() *cobra.Command {
 				return err
 			}
 			if vm.PID != nil {
-				fmt.Fprintf(cmd.OutOrStdout(), "VM %s restarted (PID %d)\n", vm.Name, *vm.PID)
-			} else {
-				fmt.Fprintf(cmd.OutOrStdout(), "VM %s restarted\n", vm.Name)
+				return renderResult(cmd, vm, "VM %s restarted (PID %d)", vm.Name, *vm.PID)
 			}
-			return nil
+			return renderResult(cmd, vm, "VM %s restarted", vm.Name)
+		},
+	}
+	cmd.ValidArgsFunction = completeArgs(completeVMNames)
+	return cmd
+}
+
+func newVMsDuplicateCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "duplicate <name> <new-name>",
+		Short: "Copy a microVM's config and disks into a new, separately addressed VM",
+		Long: `Create a new VM from an existing VM's configuration and disk state. The copy
+gets its own IP, MAC, vsock CID and hostname; host port mappings and
+statically addressed extra interfaces are not copied. A running source is
+paused briefly while its disks are copied.`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			api, err := clientFromCmd(cmd)
+			if err != nil {
+				return err
+			}
+			ctx, cancel := context.WithTimeout(cmd.Context(), 10*time.Minute)
+			defer cancel()
+
+			vm, err := api.DuplicateVM(ctx, args[0], args[1])
+			if err != nil {
+				return err
+			}
+			return renderResult(cmd, vm, "VM %s duplicated as %s (IP %s)", args[0], vm.Name, vm.IPAddress)
+		},
+	}
+	cmd.ValidArgsFunction = completeArgs(completeVMNames)
+	return cmd
+}
+
+func newVMsCloneCmd() *cobra.Command {
+	var scratch bool
+	cmd := &cobra.Command{
+		Use:   "clone <name> <new-name>",
+		Short: "Create a separately addressed microVM from another VM's config",
+		Long: `Create a new VM from an existing VM's configuration. The clone gets its own
+IP, MAC, vsock CID and hostname and boots a fresh root filesystem from its
+manifest; host port mappings and statically addressed extra interfaces are
+not copied. With --scratch the source's persistent scratch disk is copied
+too, as a reflink where the filesystem supports it. VMs with writable data
+disks cannot be cloned; use duplicate to copy their disks along.`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			api, err := clientFromCmd(cmd)
+			if err != nil {
+				return err
+			}
+			ctx, cancel := context.WithTimeout(cmd.Context(), 10*time.Minute)
+			defer cancel()
+
+			vm, err := api.CloneVM(ctx, args[0], args[1], scratch)
+			if err != nil {
+				return err
+			}
+			return renderResult(cmd, vm, "VM %s cloned as %s (IP %s)", args[0], vm.Name, vm.IPAddress)
 		},
 	}
+	cmd.Flags().BoolVar(&scratch, "scratch", false, "Copy the source's persistent scratch disk")
+	cmd.ValidArgsFunction = completeArgs(completeVMNames)
 	return cmd
 }
 
@@ -560,15 +730,19 @@ func newVMsScaleCmd() *cobra.Command {
 				if err != nil {
 					return err
 				}
-				fmt.Fprintf(cmd.OutOrStdout(), "VM %s updated: CPU=%d cores, Memory=%d MB (config version %d)\n",
-					args[0], updated.Config.Resources.CPUCores, updated.Config.Resources.MemoryMB, updated.Version)
 				if restart {
 					restartCtx, cancelRestart := context.WithTimeout(cmd.Context(), 60*time.Second)
 					defer cancelRestart()
 					if _, err := api.RestartVM(restartCtx, args[0]); err != nil {
 						return fmt.Errorf("config updated but restart failed: %w", err)
 					}
-					fmt.Fprintf(cmd.OutOrStdout(), "VM %s restarted to apply resource changes\n", args[0])
+				}
+				if err := renderResult(cmd, updated, "VM %s updated: CPU=%d cores, Memory=%d MB (config version %d)",
+					args[0], updated.Config.Resources.CPUCores, updated.Config.Resources.MemoryMB, updated.Version); err != nil {
+					return err
+				}
+				if restart {
+					infof(cmd, "VM %s restarted to apply resource changes", args[0])
 				}
 			}
 
@@ -577,7 +751,7 @@ func newVMsScaleCmd() *cobra.Command {
 				if err != nil {
 					return err
 				}
-				fmt.Fprintf(cmd.OutOrStdout(), "Deployment %s scaled to %d replicas (ready %d)\n", deployment.Name, deployment.DesiredReplicas, deployment.ReadyReplicas)
+				return renderResult(cmd, deployment, "Deployment %s scaled to %d replicas (ready %d)", deployment.Name, deployment.DesiredReplicas, deployment.ReadyReplicas)
 			}
 			return nil
 		},
@@ -586,6 +760,7 @@ func newVMsScaleCmd() *cobra.Command {
 	cmd.Flags().Int("memory", -1, "Target memory in MB")
 	cmd.Flags().Bool("restart", false, "Restart the VM after updating resources")
 	cmd.Flags().Int("replicas", -1, "Scale deployment replica count")
+	cmd.ValidArgsFunction = completeArgs(completeVMNames)
 	return cmd
 }
 
@@ -626,28 +801,28 @@ func newVMsConfigGetCmd() *cobra.Command {
 				payload = cfg.Config
 			}
 
-			data, err := json.MarshalIndent(payload, "", "  ")
-			if err != nil {
-				return err
-			}
-			if outputPath != "" {
-				return os.WriteFile(outputPath, data, 0o644)
-			}
-			fmt.Fprintln(cmd.OutOrStdout(), string(data))
-			return nil
+			return writeDocument(cmd, outputPath, payload)
 		},
 	}
-	cmd.Flags().StringVar(&outputPath, "output", "", "Write configuration to file instead of stdout")
+	cmd.Flags().StringVar(&outputPath, "output-file", "", "Write configuration to file instead of stdout")
 	cmd.Flags().BoolVar(&raw, "raw", false, "Include metadata such as version and timestamps")
+	cmd.ValidArgsFunction = completeArgs(completeVMNames)
 	return cmd
 }
 
 func newVMsConfigSetCmd() *cobra.Command {
 	var filePath string
+	var opts volantclient.ConfigUpdateOptions
 	cmd := &cobra.Command{
 		Use:   "set <name>",
 		Short: "Replace VM configuration from a file",
-		Args:  cobra.ExactArgs(1),
+		Long: `Replace VM configuration from a file.
+
+With --restart the VM is restarted onto the new configuration. --rollback also
+snapshots the running VM first and restores the snapshot if the restarted VM
+does not pass its health check within --health-window; the outcome is shown in
+"volar vms config history".`,
+		Args: cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			if strings.TrimSpace(filePath) == "" {
 				return fmt.Errorf("--file is required")
@@ -676,18 +851,37 @@ func newVMsConfigSetCmd() *cobra.Command {
 			if err != nil {
 				return err
 			}
-			ctx, cancel := context.WithTimeout(cmd.Context(), 30*time.Second)
+			if opts.Rollback {
+				opts.Restart = true
+			}
+			timeout := 30 * time.Second
+			if opts.Restart {
+				timeout = 2 * time.Minute
+			}
+			ctx, cancel := context.WithTimeout(cmd.Context(), timeout)
 			defer cancel()
 
-			updated, err := api.UpdateVMConfigRaw(ctx, args[0], payload)
+			updated, err := api.ApplyVMConfigRaw(ctx, args[0], payload, opts)
 			if err != nil {
 				return err
 			}
-			fmt.Fprintf(cmd.OutOrStdout(), "VM %s configuration updated (version %d)\n", args[0], updated.Version)
+			if err := renderResult(cmd, updated, "VM %s configuration updated (version %d)", args[0], updated.Version); err != nil {
+				return err
+			}
+			switch {
+			case opts.Rollback:
+				infof(cmd, "VM %s restarting; check \"volar vms config history %s\" for the health check outcome", args[0], args[0])
+			case opts.Restart:
+				infof(cmd, "VM %s restarted to apply configuration", args[0])
+			}
 			return nil
 		},
 	}
 	cmd.Flags().StringVar(&filePath, "file", "", "Path to JSON configuration file")
+	cmd.Flags().BoolVar(&opts.Restart, "restart", false, "Restart the VM onto the new configuration")
+	cmd.Flags().BoolVar(&opts.Rollback, "rollback", false, "Snapshot before restarting and roll back if the VM is not healthy in time (implies --restart)")
+	cmd.Flags().DurationVar(&opts.HealthWindow, "health-window", 0, "Time the restarted VM has to become healthy (default: plugin health check timeout or 2m)")
+	cmd.ValidArgsFunction = completeArgs(completeVMNames)
 	return cmd
 }
 
@@ -711,18 +905,208 @@ func newVMsConfigHistoryCmd() *cobra.Command {
 			if err != nil {
 				return err
 			}
+			if done, err := renderNames(cmd, history, func() []string {
+				versions := make([]string, 0, len(history))
+				for _, entry := range history {
+					versions = append(versions, strconv.Itoa(entry.Version))
+				}
+				return versions
+			}); done {
+				return err
+			}
 			for _, entry := range history {
-				fmt.Fprintf(cmd.OutOrStdout(), "Version %d	%s	CPU=%d	Memory=%d MB\n",
+				fmt.Fprintf(cmd.OutOrStdout(), "Version %d	%s	CPU=%d	Memory=%d MB",
 					entry.Version,
 					entry.UpdatedAt.UTC().Format(time.RFC3339),
 					entry.Config.Resources.CPUCores,
 					entry.Config.Resources.MemoryMB,
 				)
+				if entry.Outcome != "" {
+					fmt.Fprintf(cmd.OutOrStdout(), "	%s", entry.Outcome)
+					if entry.OutcomeMessage != "" {
+						fmt.Fprintf(cmd.OutOrStdout(), ": %s", entry.OutcomeMessage)
+					}
+				}
+				fmt.Fprintln(cmd.OutOrStdout())
 			}
 			return nil
 		},
 	}
 	cmd.Flags().Int("limit", 0, "Limit the number of history entries returned")
+	cmd.ValidArgsFunction = completeArgs(completeVMNames)
+	return cmd
+}
+
+func newVMsPortsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "ports <name>",
+		Short: "Show VM port mappings",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			api, err := clientFromCmd(cmd)
+			if err != nil {
+				return err
+			}
+			ctx, cancel := context.WithTimeout(cmd.Context(), 15*time.Second)
+			defer cancel()
+			ports, err := api.GetVMPorts(ctx, args[0])
+			if err != nil {
+				return err
+			}
+			if done, err := render(cmd, ports); done {
+				return err
+			}
+			if len(ports) == 0 {
+				fmt.Fprintln(cmd.OutOrStdout(), "No port mappings")
+				return nil
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "%-8s %-6s %-22s %-8s\n", "HOST", "PROTO", "GUEST", "ACTIVE")
+			for _, port := range ports {
+				guest := strconv.Itoa(port.Guest)
+				if port.GuestIP != "" {
+					guest = net.JoinHostPort(port.GuestIP, guest)
+				}
+				fmt.Fprintf(cmd.OutOrStdout(), "%-8d %-6s %-22s %-8t\n", port.Host, port.Proto, guest, port.Active)
+			}
+			return nil
+		},
+	}
+	cmd.ValidArgsFunction = completeArgs(completeVMNames)
+	return cmd
+}
+
+func newVMsWatchCmd() *cobra.Command {
+	var channels []string
+	cmd := &cobra.Command{
+		Use:   "watch <name>",
+		Short: "Follow a microVM's status, config versions, heartbeats and logs",
+		Long: `Follow one microVM over a single connection. Channels are status,
+config, heartbeat and logs; logs are off unless requested.
+
+Examples:
+  volar vms watch web-1
+  volar vms watch web-1 --channels status,logs`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			api, err := clientFromCmd(cmd)
+			if err != nil {
+				return err
+			}
+			ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt)
+			defer stop()
+
+			out := cmd.OutOrStdout()
+			return api.WatchVM(ctx, args[0], channels, func(msg volantclient.VMWatchMessage) {
+				if done, _ := renderStream(cmd, msg); done {
+					return
+				}
+				fmt.Fprintf(out, "%s %-9s %-17s %s\n", msg.Timestamp.Local().Format("15:04:05"), msg.Channel, msg.Type, watchSummary(msg))
+			})
+		},
+	}
+	cmd.Flags().StringSliceVar(&channels, "channels", nil, "Channels to follow: status, config, heartbeat, logs (default all but logs)")
+	cmd.ValidArgsFunction = completeArgs(completeVMNames)
+	return cmd
+}
+
+// watchSummary condenses a watch message to one line of text.
+func watchSummary(msg volantclient.VMWatchMessage) string {
+	var data struct {
+		Status        string `json:"status"`
+		Message       string `json:"message"`
+		Version       int    `json:"version"`
+		ConfigVersion int    `json:"config_version"`
+		Stream        string `json:"stream"`
+		Line          string `json:"line"`
+	}
+	if err := json.Unmarshal(msg.Data, &data); err != nil {
+		return string(msg.Data)
+	}
+	switch msg.Channel {
+	case "logs":
+		return data.Stream + ": " + data.Line
+	case "config":
+		return fmt.Sprintf("version %d", data.Version)
+	case "heartbeat":
+		return fmt.Sprintf("%s (config version %d)", data.Status, data.ConfigVersion)
+	}
+	if data.Message != "" {
+		return data.Status + ": " + data.Message
+	}
+	return data.Status
+}
+
+func newVMsSysInfoCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "sysinfo <name>",
+		Short: "Show what the guest booted with (kernel, disks, interfaces, modules, agent build)",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			api, err := clientFromCmd(cmd)
+			if err != nil {
+				return err
+			}
+			ctx, cancel := context.WithTimeout(cmd.Context(), 15*time.Second)
+			defer cancel()
+			info, err := api.GetVMSysInfo(ctx, args[0])
+			if err != nil {
+				return err
+			}
+			return writeDocument(cmd, "", info)
+		},
+	}
+	cmd.ValidArgsFunction = completeArgs(completeVMNames)
+	return cmd
+}
+
+func newVMsMetricsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "metrics <name>",
+		Short: "Show CPU, memory, disk and process usage inside the guest",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			api, err := clientFromCmd(cmd)
+			if err != nil {
+				return err
+			}
+			ctx, cancel := context.WithTimeout(cmd.Context(), 15*time.Second)
+			defer cancel()
+			metrics, err := api.GetVMGuestMetrics(ctx, args[0])
+			if err != nil {
+				return err
+			}
+			if done, err := render(cmd, metrics); done {
+				return err
+			}
+			out := cmd.OutOrStdout()
+			fmt.Fprintf(out, "CPU:       %.1f%% of %d CPUs (load %.2f %.2f %.2f)\n", metrics.CPU.Percent, metrics.CPU.Count, metrics.Load[0], metrics.Load[1], metrics.Load[2])
+			fmt.Fprintf(out, "Memory:    %d MiB used, %d MiB available of %d MiB\n", metrics.Memory.UsedBytes>>20, metrics.Memory.AvailableBytes>>20, metrics.Memory.TotalBytes>>20)
+			fmt.Fprintf(out, "Processes: %d (%d running)\n", metrics.Processes.Total, metrics.Processes.Running)
+			fmt.Fprintf(out, "Uptime:    %s\n", (time.Duration(metrics.UptimeSeconds) * time.Second).String())
+			if len(metrics.Disks) > 0 {
+				fmt.Fprintf(out, "\n%-24s %-16s %10s %10s\n", "MOUNT", "DEVICE", "SIZE MiB", "FREE MiB")
+				for _, disk := range metrics.Disks {
+					fmt.Fprintf(out, "%-24s %-16s %10d %10d\n", disk.MountPoint, disk.Device, disk.TotalBytes>>20, disk.FreeBytes>>20)
+				}
+			}
+			if len(metrics.Plugin) > 0 {
+				keys := make([]string, 0, len(metrics.Plugin))
+				for key := range metrics.Plugin {
+					keys = append(keys, key)
+				}
+				sort.Strings(keys)
+				fmt.Fprintln(out, "\nPlugin stats:")
+				for _, key := range keys {
+					fmt.Fprintf(out, "  %s: %g\n", key, metrics.Plugin[key])
+				}
+			}
+			if metrics.PluginError != "" {
+				fmt.Fprintf(out, "\nPlugin stats unavailable: %s\n", metrics.PluginError)
+			}
+			return nil
+		},
+	}
+	cmd.ValidArgsFunction = completeArgs(completeVMNames)
 	return cmd
 }
 
@@ -736,6 +1120,7 @@ func newDeploymentsCmd() *cobra.Command {
 	cmd.AddCommand(newDeploymentsGetCmd())
 	cmd.AddCommand(newDeploymentsDeleteCmd())
 	cmd.AddCommand(newDeploymentsScaleCmd())
+	cmd.AddCommand(newDeploymentsUpdateCmd())
 	return cmd
 }
 
@@ -755,13 +1140,25 @@ func newDeploymentsListCmd() *cobra.Command {
 			if err != nil {
 				return err
 			}
+			if done, err := renderNames(cmd, deployments, func() []string {
+				names := make([]string, 0, len(deployments))
+				for _, dep := range deployments {
+					names = append(names, dep.Name)
+				}
+				return names
+			}); done {
+				return err
+			}
 			if len(deployments) == 0 {
 				fmt.Fprintln(cmd.OutOrStdout(), "No deployments found")
 				return nil
 			}
-			fmt.Fprintf(cmd.OutOrStdout(), "%-20s %-10s %-10s\n", "NAME", "DESIRED", "READY")
+			fmt.Fprintf(cmd.OutOrStdout(), "%-20s %-10s %-10s %-12s %-12s\n", "NAME", "DESIRED", "READY", "CONDITION", "RECONCILE")
 			for _, dep := range deployments {
-				fmt.Fprintf(cmd.OutOrStdout(), "%-20s %-10d %-10d\n", dep.Name, dep.DesiredReplicas, dep.ReadyReplicas)
+				fmt.Fprintf(cmd.OutOrStdout(), "%-20s %-10d %-10d %-12s %-12s\n", dep.Name, dep.DesiredReplicas, dep.ReadyReplicas, dep.Condition, dep.Reconcile.State)
+				if dep.Reconcile.LastError != "" {
+					fmt.Fprintf(cmd.OutOrStdout(), "  last error: %s\n", dep.Reconcile.LastError)
+				}
 			}
 			return nil
 		},
@@ -772,27 +1169,57 @@ func newDeploymentsListCmd() *cobra.Command {
 func newDeploymentsCreateCmd() *cobra.Command {
 	var configPath string
 	var replicas int
+	var maxSurge int
+	var requestID string
 	cmd := &cobra.Command{
 		Use:   "create <name>",
 		Short: "Create a deployment",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			if strings.TrimSpace(configPath) == "" {
-				return fmt.Errorf("--config is required")
+			cfg, err := readDeploymentConfig(configPath)
+			if err != nil {
+				return err
 			}
-			data, err := os.ReadFile(configPath)
+
+			api, err := clientFromCmd(cmd)
 			if err != nil {
 				return err
 			}
-			var cfg vmconfig.Config
-			if err := json.Unmarshal(data, &cfg); err != nil {
-				var envelope struct {
-					Config vmconfig.Config `json:"config"`
-				}
-				if err2 := json.Unmarshal(data, &envelope); err2 != nil {
-					return fmt.Errorf("parse config file: %w", err)
-				}
-				cfg = envelope.Config
+			ctx, cancel := context.WithTimeout(cmd.Context(), 30*time.Second)
+			defer cancel()
+
+			deployment, err := api.CreateDeployment(ctx, volantclient.CreateDeploymentRequest{
+				Name:      args[0],
+				Replicas:  replicas,
+				MaxSurge:  maxSurge,
+				Config:    cfg,
+				RequestID: requestID,
+			})
+			if err != nil {
+				return err
+			}
+			return renderResult(cmd, deployment, "Deployment %s created with %d replicas", deployment.Name, deployment.DesiredReplicas)
+		},
+	}
+	cmd.Flags().StringVar(&configPath, "config", "", "Path to deployment config JSON file")
+	cmd.Flags().IntVar(&replicas, "replicas", 1, "Number of replicas to launch")
+	cmd.Flags().IntVar(&maxSurge, "max-surge", 0, "Maximum replicas booting at once; waits for readiness between batches (0 = unlimited)")
+	cmd.Flags().StringVar(&requestID, "request-id", "", "Idempotency key; rerunning with the same key returns the first result instead of creating again")
+	return cmd
+}
+
+func newDeploymentsUpdateCmd() *cobra.Command {
+	var configPath string
+	var maxSurge int
+	var maxUnavailable int
+	cmd := &cobra.Command{
+		Use:   "update <name>",
+		Short: "Roll a deployment onto a new config",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := readDeploymentConfig(configPath)
+			if err != nil {
+				return err
 			}
 
 			api, err := clientFromCmd(cmd)
@@ -802,23 +1229,47 @@ func newDeploymentsCreateCmd() *cobra.Command {
 			ctx, cancel := context.WithTimeout(cmd.Context(), 30*time.Second)
 			defer cancel()
 
-			deployment, err := api.CreateDeployment(ctx, client.CreateDeploymentRequest{
-				Name:     args[0],
-				Replicas: replicas,
-				Config:   cfg,
+			deployment, err := api.UpdateDeploymentConfig(ctx, args[0], volantclient.UpdateDeploymentConfigRequest{
+				Config:         cfg,
+				MaxSurge:       &maxSurge,
+				MaxUnavailable: &maxUnavailable,
 			})
 			if err != nil {
 				return err
 			}
-			fmt.Fprintf(cmd.OutOrStdout(), "Deployment %s created with %d replicas\n", deployment.Name, deployment.DesiredReplicas)
-			return nil
+			return renderResult(cmd, deployment, "Deployment %s rolling update started (%d replicas)", deployment.Name, deployment.DesiredReplicas)
 		},
 	}
 	cmd.Flags().StringVar(&configPath, "config", "", "Path to deployment config JSON file")
-	cmd.Flags().IntVar(&replicas, "replicas", 1, "Number of replicas to launch")
+	cmd.Flags().IntVar(&maxSurge, "max-surge", 1, "Extra replicas allowed above the desired count during the update")
+	cmd.Flags().IntVar(&maxUnavailable, "max-unavailable", 0, "Replicas that may be down before replacements are ready")
+	cmd.ValidArgsFunction = completeArgs(completeDeploymentNames)
 	return cmd
 }
 
+// readDeploymentConfig loads a vmconfig from a file holding either the bare
+// config or a {"config": ...} envelope.
+func readDeploymentConfig(configPath string) (vmconfig.Config, error) {
+	if strings.TrimSpace(configPath) == "" {
+		return vmconfig.Config{}, fmt.Errorf("--config is required")
+	}
+	data, err := os.ReadFile(configPath)
+	if err != nil {
+		return vmconfig.Config{}, err
+	}
+	var cfg vmconfig.Config
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		var envelope struct {
+			Config vmconfig.Config `json:"config"`
+		}
+		if err2 := json.Unmarshal(data, &envelope); err2 != nil {
+			return vmconfig.Config{}, fmt.Errorf("parse config file: %w", err)
+		}
+		cfg = envelope.Config
+	}
+	return cfg, nil
+}
+
 func newDeploymentsGetCmd() *cobra.Command {
 	var outputPath string
 	cmd := &cobra.Command{
@@ -837,19 +1288,11 @@ func newDeploymentsGetCmd() *cobra.Command {
 			if err != nil {
 				return err
 			}
-
-			data, err := json.MarshalIndent(deployment, "", "  ")
-			if err != nil {
-				return err
-			}
-			if strings.TrimSpace(outputPath) != "" {
-				return os.WriteFile(outputPath, data, 0o644)
-			}
-			fmt.Fprintln(cmd.OutOrStdout(), string(data))
-			return nil
+			return writeDocument(cmd, outputPath, deployment)
 		},
 	}
-	cmd.Flags().StringVar(&outputPath, "output", "", "Write deployment details to file")
+	cmd.Flags().StringVar(&outputPath, "output-file", "", "Write deployment details to file")
+	cmd.ValidArgsFunction = completeArgs(completeDeploymentNames)
 	return cmd
 }
 
@@ -869,10 +1312,11 @@ func newDeploymentsDeleteCmd() *cobra.Command {
 			if err := api.DeleteDeployment(ctx, args[0]); err != nil {
 				return err
 			}
-			fmt.Fprintf(cmd.OutOrStdout(), "Deployment %s deleted\n", args[0])
+			infof(cmd, "Deployment %s deleted", args[0])
 			return nil
 		},
 	}
+	cmd.ValidArgsFunction = completeArgs(completeDeploymentNames)
 	return cmd
 }
 
@@ -897,23 +1341,31 @@ func newDeploymentsScaleCmd() *cobra.Command {
 			if err != nil {
 				return err
 			}
-			fmt.Fprintf(cmd.OutOrStdout(), "Deployment %s scaled to %d replicas (ready %d)\n", deployment.Name, deployment.DesiredReplicas, deployment.ReadyReplicas)
-			return nil
+			return renderResult(cmd, deployment, "Deployment %s scaled to %d replicas (ready %d)", deployment.Name, deployment.DesiredReplicas, deployment.ReadyReplicas)
 		},
 	}
+	cmd.ValidArgsFunction = completeArgs(completeDeploymentNames)
 	return cmd
 }
 
 func newVMsConsoleCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "console <name>",
-		Short: "Attach to a VM serial socket",
+		Short: "Attach to a VM serial console",
+		Long:  "Attach to a VM serial console through the API. Recent output is replayed first; --socket attaches to a serial socket directly instead.",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			socketPath, err := cmd.Flags().GetString("socket")
 			if err != nil {
 				return err
 			}
+			replay, err := cmd.Flags().GetInt("replay")
+			if err != nil {
+				return err
+			}
+			if replay < 0 {
+				return fmt.Errorf("replay must be a non-negative integer")
+			}
 
 			api, err := clientFromCmd(cmd)
 			if err != nil {
@@ -922,16 +1374,155 @@ func newVMsConsoleCmd() *cobra.Command {
 			ctx, cancel := context.WithTimeout(cmd.Context(), 10*time.Second)
 			defer cancel()
 
-			socketPath, _, err = resolveConsoleSocket(ctx, api, args[0], socketPath, false)
+			if strings.TrimSpace(socketPath) != "" {
+				socketPath, _, err = resolveConsoleSocket(ctx, api, args[0], socketPath, false)
+				if err != nil {
+					return err
+				}
+				infof(cmd, "Connecting to serial socket: %s", socketPath)
+				return attachUnixSocket(cmd, socketPath)
+			}
+
+			conn, err := api.AttachConsole(ctx, args[0], replay)
 			if err != nil {
 				return err
 			}
+			infof(cmd, "Connected to %s serial console", args[0])
+			return attachConsole(cmd, conn)
+		},
+	}
+	cmd.Flags().String("socket", "", "Attach to this serial socket directly instead of through the API")
+	cmd.Flags().Int("replay", 100, "Recent output lines to replay on attach (0 disables)")
+	cmd.ValidArgsFunction = completeArgs(completeVMNames)
+	return cmd
+}
 
-			fmt.Fprintf(cmd.OutOrStdout(), "Connecting to serial socket: %s\n", socketPath)
-			return attachUnixSocket(cmd, socketPath)
+func newVMsLogsCmd() *cobra.Command {
+	var query volantclient.VMLogsQuery
+	cmd := &cobra.Command{
+		Use:   "logs <name>",
+		Short: "Show persisted hypervisor and guest agent logs of a microVM",
+		Long: `Show the logs volantd keeps for a microVM across VM and daemon restarts.
+
+Examples:
+  volar vms logs web-1 --since 1h
+  volar vms logs web-1 --stream stderr --source hypervisor --limit 50`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if query.Limit < 0 {
+				return fmt.Errorf("limit must be a non-negative integer")
+			}
+			api, err := clientFromCmd(cmd)
+			if err != nil {
+				return err
+			}
+			ctx, cancel := context.WithTimeout(cmd.Context(), 30*time.Second)
+			defer cancel()
+
+			entries, err := api.GetVMLogs(ctx, args[0], query)
+			if err != nil {
+				return err
+			}
+			if done, err := render(cmd, entries); done {
+				return err
+			}
+			out := cmd.OutOrStdout()
+			for _, entry := range entries {
+				fmt.Fprintf(out, "%s %-10s %-6s %s\n", entry.Timestamp.Local().Format(time.RFC3339), entry.Source, entry.Stream, entry.Line)
+			}
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&query.Since, "since", "", "Only entries since an RFC 3339 time or a duration ago (e.g. 30m)")
+	cmd.Flags().StringVar(&query.Stream, "stream", "", "Only this stream: stdout or stderr")
+	cmd.Flags().StringVar(&query.Source, "source", "", "Only this source: hypervisor or agent")
+	cmd.Flags().IntVar(&query.Limit, "limit", 0, "Show only the last N entries (0 shows all)")
+	cmd.ValidArgsFunction = completeArgs(completeVMNames)
+	return cmd
+}
+
+func newVMsEventsCmd() *cobra.Command {
+	var query volantclient.VMEventsQuery
+	cmd := &cobra.Command{
+		Use:   "events <name>",
+		Short: "Show the recorded lifecycle events of a microVM",
+		Long: `Show the lifecycle events volantd recorded for a microVM: starts, stops,
+crashes, launch failures, config updates and expiry. Events are kept for
+seven days, also after the VM is deleted.
+
+Examples:
+  volar vms events web-1 --since 24h
+  volar vms events web-1 --type VM_CRASHED,VM_FAILED`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if query.Limit < 0 {
+				return fmt.Errorf("limit must be a non-negative integer")
+			}
+			api, err := clientFromCmd(cmd)
+			if err != nil {
+				return err
+			}
+			ctx, cancel := context.WithTimeout(cmd.Context(), 30*time.Second)
+			defer cancel()
+
+			events, err := api.GetVMEvents(ctx, args[0], query)
+			if err != nil {
+				return err
+			}
+			if done, err := render(cmd, events); done {
+				return err
+			}
+			out := cmd.OutOrStdout()
+			if len(events) == 0 {
+				fmt.Fprintln(out, "No events recorded")
+				return nil
+			}
+			for _, event := range events {
+				fmt.Fprintf(out, "%s %-18s %-9s %s\n", event.Timestamp.Local().Format(time.RFC3339), event.Type, event.Status, event.Message)
+				if event.Hint != "" {
+					fmt.Fprintf(out, "  hint: %s\n", event.Hint)
+				}
+			}
+			return nil
 		},
 	}
-	cmd.Flags().String("socket", "", "Override socket path")
+	cmd.Flags().StringVar(&query.Since, "since", "", "Only events since an RFC 3339 time or a duration ago (e.g. 24h)")
+	cmd.Flags().StringSliceVar(&query.Types, "type", nil, "Only these event types, e.g. VM_CRASHED (repeatable or comma-separated)")
+	cmd.Flags().IntVar(&query.Limit, "limit", 0, "Show only the last N events (0 uses the server default of 100)")
+	cmd.ValidArgsFunction = completeArgs(completeVMNames)
+	return cmd
+}
+
+func newVMsConsoleLogCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "console-log <name>",
+		Short: "Print recent VM serial console output",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			tail, err := cmd.Flags().GetInt("tail")
+			if err != nil {
+				return err
+			}
+			if tail < 0 {
+				return fmt.Errorf("tail must be a non-negative integer")
+			}
+			api, err := clientFromCmd(cmd)
+			if err != nil {
+				return err
+			}
+			ctx, cancel := context.WithTimeout(cmd.Context(), 30*time.Second)
+			defer cancel()
+
+			output, err := api.ConsoleLog(ctx, args[0], tail)
+			if err != nil {
+				return err
+			}
+			_, err = cmd.OutOrStdout().Write(output)
+			return err
+		},
+	}
+	cmd.Flags().Int("tail", 1000, "Number of recent lines to print (0 prints everything kept)")
+	cmd.ValidArgsFunction = completeArgs(completeVMNames)
 	return cmd
 }
 
@@ -940,6 +1531,11 @@ func attachUnixSocket(cmd *cobra.Command, socketPath string) error {
 	if err != nil {
 		return fmt.Errorf("connect unix socket: %w", err)
 	}
+	return attachConsole(cmd, conn)
+}
+
+// attachConsole bridges the terminal to conn until either side hangs up.
+func attachConsole(cmd *cobra.Command, conn io.ReadWriteCloser) error {
 	defer conn.Close()
 
 	stdinFd := int(os.Stdin.Fd())
@@ -1005,29 +1601,20 @@ func uniqueStrings(values []string) []string {
 	return result
 }
 
-func preferredContentType(op *openapiutil.Operation) string {
-	if op == nil || op.RequestBody == nil {
-		return ""
-	}
-	body := op.RequestBody.Value
-	if body == nil || len(body.Content) == 0 {
-		return ""
-	}
-	if _, ok := body.Content["application/json"]; ok {
-		return "application/json"
-	}
-	for ct := range body.Content {
-		return ct
+func clientFromCmd(cmd *cobra.Command) (*volantclient.Client, error) {
+	conn, err := resolveConnection(cmd)
+	if err != nil {
+		return nil, err
 	}
-	return ""
+	return conn.client()
 }
 
-func clientFromCmd(cmd *cobra.Command) (*client.Client, error) {
-	base, err := cmd.Root().PersistentFlags().GetString("api")
-	if err != nil {
-		base = envOrDefault("VOLANT_API_BASE", "http://127.0.0.1:7777")
-	}
-	return client.New(base)
+// pluginOperation is the structured output of `vms operations`.
+type pluginOperation struct {
+	OperationID string `json:"operation_id"`
+	Method      string `json:"method"`
+	Path        string `json:"path"`
+	Summary     string `json:"summary,omitempty"`
 }
 
 // newVMsOperationsCmd lists all available operations from the VM's plugin OpenAPI spec.
@@ -1060,7 +1647,24 @@ func newVMsOperationsCmd() *cobra.Command {
 
 			// List operations
 			ops := openapiutil.ListOperations(doc)
-			if len(ops) == 0 {
+			listed := make([]pluginOperation, 0, len(ops))
+			for _, op := range ops {
+				operationID := op.OperationID
+				if operationID == "" {
+					operationID = fmt.Sprintf("%s:%s", op.Method, op.Path)
+				}
+				listed = append(listed, pluginOperation{OperationID: operationID, Method: op.Method, Path: op.Path, Summary: op.Summary})
+			}
+			if done, err := renderNames(cmd, listed, func() []string {
+				ids := make([]string, 0, len(listed))
+				for _, op := range listed {
+					ids = append(ids, op.OperationID)
+				}
+				return ids
+			}); done {
+				return err
+			}
+			if len(listed) == 0 {
 				fmt.Fprintln(cmd.OutOrStdout(), "No operations found")
 				return nil
 			}
@@ -1068,21 +1672,18 @@ func newVMsOperationsCmd() *cobra.Command {
 			// Display operations in a table
 			fmt.Fprintf(cmd.OutOrStdout(), "%-30s %-8s %-40s %s\n", "OPERATION ID", "METHOD", "PATH", "SUMMARY")
 			fmt.Fprintf(cmd.OutOrStdout(), "%s\n", strings.Repeat("-", 120))
-			for _, op := range ops {
-				operationID := op.OperationID
-				if operationID == "" {
-					operationID = fmt.Sprintf("%s:%s", op.Method, op.Path)
-				}
+			for _, op := range listed {
 				summary := op.Summary
 				if len(summary) > 50 {
 					summary = summary[:47] + "..."
 				}
-				fmt.Fprintf(cmd.OutOrStdout(), "%-30s %-8s %-40s %s\n", operationID, op.Method, op.Path, summary)
+				fmt.Fprintf(cmd.OutOrStdout(), "%-30s %-8s %-40s %s\n", op.OperationID, op.Method, op.Path, summary)
 			}
 
 			return nil
 		},
 	}
+	cmd.ValidArgsFunction = completeArgs(completeVMNames)
 	return cmd
 }
 
@@ -1158,7 +1759,7 @@ Examples:
 			}
 
 			// Determine content type
-			contentType := preferredContentType(op)
+			contentType := openapiutil.PreferredContentType(op)
 			headers := http.Header{}
 			if contentType != "" && len(body) > 0 {
 				headers.Set("Content-Type", contentType)
@@ -1208,5 +1809,6 @@ Examples:
 	cmd.Flags().StringVar(&bodyInline, "body", "", "Inline request body")
 	cmd.Flags().DurationVar(&timeout, "timeout", 60*time.Second, "Request timeout")
 
+	cmd.ValidArgsFunction = completeArgs(completeVMNames, completeOperations)
 	return cmd
 }
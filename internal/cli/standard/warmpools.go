@@ -0,0 +1,219 @@
+// Copyright (c) 2025 HYPR. PTE. LTD.
+//
+// Business Source License 1.1
+// See LICENSE file in the project root for details.
+
+package standard
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/volantvm/volant/internal/server/labels"
+	"github.com/volantvm/volant/pkg/volantclient"
+)
+
+func newWarmPoolsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "warm-pools",
+		Aliases: []string{"warm-pool", "pools"},
+		Short:   "Keep booted microVMs of a plugin ready to claim",
+	}
+	cmd.AddCommand(newWarmPoolsListCmd())
+	cmd.AddCommand(newWarmPoolsSetCmd())
+	cmd.AddCommand(newWarmPoolsShowCmd())
+	cmd.AddCommand(newWarmPoolsDeleteCmd())
+	return cmd
+}
+
+func newWarmPoolsListCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List warm pools",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			api, err := clientFromCmd(cmd)
+			if err != nil {
+				return err
+			}
+			ctx, cancel := context.WithTimeout(cmd.Context(), 15*time.Second)
+			defer cancel()
+
+			pools, err := api.ListWarmPools(ctx)
+			if err != nil {
+				return err
+			}
+			if done, err := renderNames(cmd, pools, func() []string {
+				names := make([]string, 0, len(pools))
+				for _, pool := range pools {
+					names = append(names, pool.Plugin)
+				}
+				return names
+			}); done {
+				return err
+			}
+			if len(pools) == 0 {
+				fmt.Fprintln(cmd.OutOrStdout(), "No warm pools found")
+				return nil
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "%-20s %-6s %-6s %-8s\n", "PLUGIN", "SIZE", "READY", "STARTING")
+			for _, pool := range pools {
+				fmt.Fprintf(cmd.OutOrStdout(), "%-20s %-6d %-6d %-8d\n", pool.Plugin, pool.Size, pool.Ready, pool.Starting)
+			}
+			return nil
+		},
+	}
+	return cmd
+}
+
+func newWarmPoolsSetCmd() *cobra.Command {
+	var configPath string
+	var size int
+	cmd := &cobra.Command{
+		Use:   "set <plugin>",
+		Short: "Create or resize the warm pool of a plugin",
+		Long: `Keep --size booted, unclaimed microVMs of a plugin ready. The pool is
+filled in the background and refilled after every claim. Without --config
+the VMs use the plugin's manifest and default resources; changing the config
+replaces the unclaimed VMs.
+
+Examples:
+  volar warm-pools set browser --size 5
+  volar warm-pools set browser --size 5 --config browser.json
+  volar warm-pools set browser --size 0`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var cfg *volantclient.VMConfig
+			if configPath != "" {
+				parsed, err := readDeploymentConfig(configPath)
+				if err != nil {
+					return err
+				}
+				cfg = &parsed
+			}
+			api, err := clientFromCmd(cmd)
+			if err != nil {
+				return err
+			}
+			ctx, cancel := context.WithTimeout(cmd.Context(), 30*time.Second)
+			defer cancel()
+
+			pool, err := api.SetWarmPool(ctx, args[0], volantclient.SetWarmPoolRequest{Size: size, Config: cfg})
+			if err != nil {
+				return err
+			}
+			return renderResult(cmd, pool, "Warm pool %s set to %d VMs (%d ready)", pool.Plugin, pool.Size, pool.Ready)
+		},
+	}
+	cmd.Flags().IntVar(&size, "size", 1, "Number of unclaimed VMs to keep ready")
+	cmd.Flags().StringVar(&configPath, "config", "", "Path to VM config JSON file (default: plugin defaults)")
+	cmd.ValidArgsFunction = completeArgs(completePluginNames)
+	return cmd
+}
+
+func newWarmPoolsShowCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "show <plugin>",
+		Short: "Show a warm pool",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			api, err := clientFromCmd(cmd)
+			if err != nil {
+				return err
+			}
+			ctx, cancel := context.WithTimeout(cmd.Context(), 15*time.Second)
+			defer cancel()
+
+			pool, err := api.GetWarmPool(ctx, args[0])
+			if err != nil {
+				return err
+			}
+			if done, err := render(cmd, pool); done {
+				return err
+			}
+			out := cmd.OutOrStdout()
+			fmt.Fprintf(out, "Plugin:    %s\n", pool.Plugin)
+			fmt.Fprintf(out, "Size:      %d\n", pool.Size)
+			fmt.Fprintf(out, "Ready:     %d\n", pool.Ready)
+			fmt.Fprintf(out, "Starting:  %d\n", pool.Starting)
+			fmt.Fprintf(out, "Resources: %d CPU, %d MB\n", pool.Config.Resources.CPUCores, pool.Config.Resources.MemoryMB)
+			return nil
+		},
+	}
+	cmd.ValidArgsFunction = completeArgs(completePluginNames)
+	return cmd
+}
+
+func newWarmPoolsDeleteCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "delete <plugin>",
+		Short: "Delete a warm pool and its unclaimed VMs",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			api, err := clientFromCmd(cmd)
+			if err != nil {
+				return err
+			}
+			ctx, cancel := context.WithTimeout(cmd.Context(), 60*time.Second)
+			defer cancel()
+
+			if err := api.DeleteWarmPool(ctx, args[0]); err != nil {
+				return err
+			}
+			infof(cmd, "Warm pool %s deleted", args[0])
+			return nil
+		},
+	}
+	cmd.ValidArgsFunction = completeArgs(completePluginNames)
+	return cmd
+}
+
+func newVMsClaimCmd() *cobra.Command {
+	var labelFlags []string
+	cmd := &cobra.Command{
+		Use:   "claim <plugin>",
+		Short: "Take a booted microVM from a plugin's warm pool",
+		Long: `Hand out a running, ready microVM from the plugin's warm pool without
+waiting for a boot. The VM keeps its generated name and gets the given
+labels; the pool is refilled in the background.
+
+Examples:
+  volar vms claim browser
+  volar vms claim browser --label session=4711`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			extra, err := parseLabelFlags(labelFlags)
+			if err != nil {
+				return err
+			}
+			api, err := clientFromCmd(cmd)
+			if err != nil {
+				return err
+			}
+			ctx, cancel := context.WithTimeout(cmd.Context(), 15*time.Second)
+			defer cancel()
+
+			vm, err := api.ClaimVM(ctx, args[0], extra)
+			if err != nil {
+				return err
+			}
+			if done, err := render(cmd, vm); done {
+				return err
+			}
+			if quiet(cmd) {
+				fmt.Fprintln(cmd.OutOrStdout(), vm.Name)
+				return nil
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "Claimed VM %s (%s)\n", vm.Name, vm.IPAddress)
+			for _, key := range labels.Keys(vm.Labels) {
+				fmt.Fprintf(cmd.OutOrStdout(), "  %s=%s\n", key, vm.Labels[key])
+			}
+			return nil
+		},
+	}
+	cmd.Flags().StringArrayVar(&labelFlags, "label", nil, "Label to set on the claimed VM as KEY=VALUE (repeatable)")
+	cmd.ValidArgsFunction = completeArgs(completePluginNames)
+	return cmd
+}
@@ -0,0 +1,221 @@
+// Copyright (c) 2025 HYPR. PTE. LTD.
+//
+// Business Source License 1.1
+// See LICENSE file in the project root for details.
+
+package standard
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/volantvm/volant/pkg/volantclient"
+)
+
+func newWebhooksCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "webhooks",
+		Short: "Manage webhooks that receive VM and deployment events",
+	}
+	cmd.AddCommand(newWebhooksListCmd())
+	cmd.AddCommand(newWebhooksCreateCmd())
+	cmd.AddCommand(newWebhooksDeleteCmd())
+	cmd.AddCommand(newWebhooksToggleCmd("enable", "Resume deliveries to a webhook", true))
+	cmd.AddCommand(newWebhooksToggleCmd("disable", "Pause deliveries without deleting the webhook", false))
+	cmd.AddCommand(newWebhooksDeliveriesCmd())
+	return cmd
+}
+
+func newWebhooksListCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List webhooks",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			api, err := clientFromCmd(cmd)
+			if err != nil {
+				return err
+			}
+			ctx, cancel := context.WithTimeout(cmd.Context(), 15*time.Second)
+			defer cancel()
+
+			webhooks, err := api.ListWebhooks(ctx)
+			if err != nil {
+				return err
+			}
+			if done, err := renderNames(cmd, webhooks, func() []string {
+				names := make([]string, 0, len(webhooks))
+				for _, webhook := range webhooks {
+					names = append(names, webhook.Name)
+				}
+				return names
+			}); done {
+				return err
+			}
+			if len(webhooks) == 0 {
+				fmt.Fprintln(cmd.OutOrStdout(), "No webhooks found")
+				return nil
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "%-20s %-40s %-8s %s\n", "NAME", "URL", "ENABLED", "EVENTS")
+			for _, webhook := range webhooks {
+				events := "*"
+				if len(webhook.Events) > 0 {
+					events = strings.Join(webhook.Events, ",")
+				}
+				fmt.Fprintf(cmd.OutOrStdout(), "%-20s %-40s %-8t %s\n", webhook.Name, webhook.URL, webhook.Enabled, events)
+			}
+			return nil
+		},
+	}
+	return cmd
+}
+
+func newWebhooksCreateCmd() *cobra.Command {
+	var endpoint string
+	var events []string
+	var secret string
+	var disabled bool
+	cmd := &cobra.Command{
+		Use:   "create <name>",
+		Short: "Register a webhook",
+		Long: `Register an endpoint that receives VM and deployment lifecycle events as
+signed JSON POSTs. Each request carries X-Volant-Signature, the hex
+HMAC-SHA256 of the body keyed by the webhook secret. A secret is generated
+when --secret is omitted and is only shown once, by this command.
+
+Examples:
+  volar webhooks create ops --url https://ops.example.com/volant
+  volar webhooks create crashes --url https://hooks.example.com/x --events VM_CRASHED,VM_FAILED`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			api, err := clientFromCmd(cmd)
+			if err != nil {
+				return err
+			}
+			ctx, cancel := context.WithTimeout(cmd.Context(), 15*time.Second)
+			defer cancel()
+
+			webhook, err := api.CreateWebhook(ctx, volantclient.CreateWebhookRequest{
+				Name:     args[0],
+				URL:      endpoint,
+				Events:   events,
+				Secret:   secret,
+				Disabled: disabled,
+			})
+			if err != nil {
+				return err
+			}
+			if done, err := render(cmd, webhook); done {
+				return err
+			}
+			if quiet(cmd) {
+				// The generated secret is only shown once.
+				if secret == "" {
+					fmt.Fprintln(cmd.OutOrStdout(), webhook.Secret)
+				}
+				return nil
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "Webhook %s created\n", webhook.Name)
+			if secret == "" {
+				fmt.Fprintf(cmd.OutOrStdout(), "Secret: %s\n", webhook.Secret)
+			}
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&endpoint, "url", "", "Endpoint URL (required)")
+	cmd.Flags().StringSliceVar(&events, "events", nil, "Event types to deliver (default all)")
+	cmd.Flags().StringVar(&secret, "secret", "", "Signing secret (generated when empty)")
+	cmd.Flags().BoolVar(&disabled, "disabled", false, "Create the webhook paused")
+	_ = cmd.MarkFlagRequired("url")
+	return cmd
+}
+
+func newWebhooksDeleteCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "delete <name>",
+		Short: "Delete a webhook and its delivery history",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			api, err := clientFromCmd(cmd)
+			if err != nil {
+				return err
+			}
+			ctx, cancel := context.WithTimeout(cmd.Context(), 15*time.Second)
+			defer cancel()
+
+			if err := api.DeleteWebhook(ctx, args[0]); err != nil {
+				return err
+			}
+			infof(cmd, "Webhook %s deleted", args[0])
+			return nil
+		},
+	}
+	return cmd
+}
+
+func newWebhooksToggleCmd(use, short string, enabled bool) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   use + " <name>",
+		Short: short,
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			api, err := clientFromCmd(cmd)
+			if err != nil {
+				return err
+			}
+			ctx, cancel := context.WithTimeout(cmd.Context(), 15*time.Second)
+			defer cancel()
+
+			webhook, err := api.SetWebhookEnabled(ctx, args[0], enabled)
+			if err != nil {
+				return err
+			}
+			return renderResult(cmd, webhook, "Webhook %s %sd", args[0], use)
+		},
+	}
+	return cmd
+}
+
+func newWebhooksDeliveriesCmd() *cobra.Command {
+	var limit int
+	cmd := &cobra.Command{
+		Use:   "deliveries <name>",
+		Short: "Show recent delivery attempts of a webhook",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			api, err := clientFromCmd(cmd)
+			if err != nil {
+				return err
+			}
+			ctx, cancel := context.WithTimeout(cmd.Context(), 15*time.Second)
+			defer cancel()
+
+			deliveries, err := api.WebhookDeliveries(ctx, args[0], limit)
+			if err != nil {
+				return err
+			}
+			if done, err := render(cmd, deliveries); done {
+				return err
+			}
+			if len(deliveries) == 0 {
+				fmt.Fprintln(cmd.OutOrStdout(), "No deliveries recorded")
+				return nil
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "%-20s %-22s %-8s %-8s %-10s %s\n", "TIME", "EVENT", "ATTEMPT", "STATUS", "DURATION", "ERROR")
+			for _, delivery := range deliveries {
+				status := "-"
+				if delivery.StatusCode != 0 {
+					status = fmt.Sprint(delivery.StatusCode)
+				}
+				duration := time.Duration(delivery.DurationMS) * time.Millisecond
+				fmt.Fprintf(cmd.OutOrStdout(), "%-20s %-22s %-8d %-8s %-10s %s\n", delivery.CreatedAt.Local().Format("2006-01-02 15:04:05"), delivery.EventType, delivery.Attempt, status, duration, delivery.Error)
+			}
+			return nil
+		},
+	}
+	cmd.Flags().IntVar(&limit, "limit", 20, "Number of attempts to show")
+	return cmd
+}
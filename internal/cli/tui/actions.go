@@ -0,0 +1,445 @@
+// Copyright (c) 2025 HYPR. PTE. LTD.
+//
+// Business Source License 1.1
+// See LICENSE file in the project root for details.
+
+package tui
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+
+	"github.com/volantvm/volant/internal/cli/openapiutil"
+)
+
+// maxResponseBytes caps how much of a plugin response is shown.
+const maxResponseBytes = 1 << 20
+
+// Where a form field's value goes in the request.
+const (
+	fieldPath   = "path"
+	fieldQuery  = "query"
+	fieldHeader = "header"
+	// fieldBody is one property of a JSON object body.
+	fieldBody = "body"
+	// fieldRaw is the whole body, sent as typed.
+	fieldRaw = "raw"
+)
+
+type formField struct {
+	name     string
+	in       string
+	kind     string
+	required bool
+	help     string
+	value    []rune
+}
+
+type actionResultMsg struct {
+	status string
+	lines  []string
+	err    error
+}
+
+// actionFormScreen is a form generated from one operation of a plugin's
+// OpenAPI document: a field per path, query and header parameter, and a
+// field per property of a JSON object body or one field for any other body.
+type actionFormScreen struct {
+	vm          string
+	op          openapiutil.Operation
+	contentType string
+	fields      []formField
+	// focus indexes fields; len(fields) is the Send button.
+	focus   int
+	sending bool
+}
+
+func newActionFormScreen(vm string, op openapiutil.Operation) *actionFormScreen {
+	s := &actionFormScreen{vm: vm, op: op}
+	s.contentType = openapiutil.PreferredContentType(&op)
+	s.fields = formFields(op, s.contentType)
+	return s
+}
+
+func (s *actionFormScreen) title() string {
+	if s.op.OperationID != "" {
+		return s.op.OperationID
+	}
+	return s.op.Method + " " + s.op.Path
+}
+
+func (s *actionFormScreen) help() string {
+	return "↑↓ tab move  type to edit  ctrl+u clear  enter next/send  ctrl+s send  esc back"
+}
+
+func (s *actionFormScreen) init(a *app) {}
+
+func (s *actionFormScreen) close() {}
+
+func (s *actionFormScreen) update(a *app, m msg) {
+	switch m := m.(type) {
+	case actionResultMsg:
+		s.sending = false
+		if m.err != nil {
+			a.fail(m.err)
+			return
+		}
+		a.push(newTextScreen("Response", m.status, m.lines))
+	case keyMsg:
+		s.handleKey(a, m)
+	}
+}
+
+func (s *actionFormScreen) handleKey(a *app, k keyMsg) {
+	switch k.key {
+	case "esc":
+		a.pop()
+	case "up", "shift+tab":
+		s.focus = (s.focus + len(s.fields)) % (len(s.fields) + 1)
+	case "down", "tab":
+		s.focus = (s.focus + 1) % (len(s.fields) + 1)
+	case "enter":
+		if s.focus == len(s.fields) {
+			s.send(a)
+		} else {
+			s.focus++
+		}
+	case "ctrl+s":
+		s.send(a)
+	case "backspace":
+		if f := s.focused(); f != nil && len(f.value) > 0 {
+			f.value = f.value[:len(f.value)-1]
+		}
+	case "ctrl+u":
+		if f := s.focused(); f != nil {
+			f.value = nil
+		}
+	default:
+		f := s.focused()
+		if f == nil || k.key == "" || len(k.raw) == 0 || k.raw[0] < 0x20 || k.raw[0] == 0x7f {
+			return
+		}
+		f.value = append(f.value, []rune(string(k.raw))...)
+	}
+}
+
+func (s *actionFormScreen) focused() *formField {
+	if s.focus < len(s.fields) {
+		return &s.fields[s.focus]
+	}
+	return nil
+}
+
+func (s *actionFormScreen) send(a *app) {
+	if s.sending {
+		return
+	}
+	path, query, headers, body, err := s.request()
+	if err != nil {
+		a.fail(err)
+		return
+	}
+	s.sending = true
+	a.flash("Sending %s %s…", s.op.Method, path)
+	a.do(s, func() msg {
+		ctx, cancel := context.WithTimeout(a.ctx, actionTimeout)
+		defer cancel()
+		resp, err := a.api.ProxyVM(ctx, s.vm, s.op.Method, path, query, body, headers)
+		if err != nil {
+			return actionResultMsg{err: err}
+		}
+		defer resp.Body.Close()
+		data, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseBytes))
+		if err != nil {
+			return actionResultMsg{err: fmt.Errorf("read response: %w", err)}
+		}
+		return actionResultMsg{status: fmt.Sprintf("%s %s → HTTP %d", s.op.Method, path, resp.StatusCode), lines: responseLines(resp.Header.Get("Content-Type"), data)}
+	})
+}
+
+// request assembles the plugin request from the form.
+func (s *actionFormScreen) request() (string, url.Values, http.Header, []byte, error) {
+	path := s.op.Path
+	query := url.Values{}
+	headers := http.Header{}
+	properties := map[string]any{}
+	var raw []byte
+	for _, f := range s.fields {
+		value := string(f.value)
+		if value == "" {
+			if f.required {
+				return "", nil, nil, nil, fmt.Errorf("%s is required", f.name)
+			}
+			continue
+		}
+		switch f.in {
+		case fieldPath:
+			path = strings.ReplaceAll(path, "{"+f.name+"}", url.PathEscape(value))
+		case fieldQuery:
+			query.Set(f.name, value)
+		case fieldHeader:
+			headers.Set(f.name, value)
+		case fieldBody:
+			converted, err := convertValue(f.kind, value)
+			if err != nil {
+				return "", nil, nil, nil, fmt.Errorf("%s: %w", f.name, err)
+			}
+			properties[f.name] = converted
+		case fieldRaw:
+			raw = []byte(value)
+		}
+	}
+	if len(properties) > 0 {
+		data, err := json.Marshal(properties)
+		if err != nil {
+			return "", nil, nil, nil, err
+		}
+		raw = data
+	}
+	if len(raw) > 0 && s.contentType != "" {
+		headers.Set("Content-Type", s.contentType)
+	}
+	return path, query, headers, raw, nil
+}
+
+func (s *actionFormScreen) view(a *app, width, height int) []string {
+	lines := []string{styleBold + fit(fmt.Sprintf("%s %s on %s", s.op.Method, s.op.Path, s.vm), width) + styleReset}
+	if summary := strings.TrimSpace(s.op.Summary); summary != "" {
+		lines = append(lines, fit(clean(summary), width))
+	}
+	if description := strings.TrimSpace(s.op.Description); description != "" {
+		lines = append(lines, styleDim+fit(clean(strings.Join(strings.Fields(description), " ")), width)+styleReset)
+	}
+	lines = append(lines, "")
+	if len(s.fields) == 0 {
+		lines = append(lines, fit("This operation takes no input.", width))
+	}
+
+	labelWidth := 12
+	for _, f := range s.fields {
+		if n := len(f.label()); n > labelWidth {
+			labelWidth = n
+		}
+	}
+	if labelWidth > width/3 {
+		labelWidth = width / 3
+	}
+	valueWidth := width - labelWidth - 4
+
+	rows := height - len(lines) - 2
+	start, end := window(s.focus, len(s.fields), rows)
+	for i := start; i < end; i++ {
+		f := s.fields[i]
+		value := []rune(string(f.value))
+		if i == s.focus {
+			value = append(value, '█')
+		}
+		if len(value) > valueWidth {
+			value = value[len(value)-valueWidth:]
+		}
+		shown := string(value)
+		if len(f.value) == 0 && i != s.focus && f.help != "" {
+			shown = styleDim + fit(clean(f.help), valueWidth) + styleReset
+		}
+		label := fit(f.label(), labelWidth)
+		if i == s.focus {
+			label = styleReverse + label + styleReset
+		}
+		lines = append(lines, "  "+label+"  "+shown)
+	}
+	lines = append(lines, "")
+	button := "[ Send ]"
+	if s.sending {
+		button = "[ Sending… ]"
+	}
+	if s.focus == len(s.fields) {
+		button = styleReverse + button + styleReset
+	}
+	lines = append(lines, "  "+button)
+	return lines
+}
+
+// label is the field name, marked when required, with where it goes.
+func (f formField) label() string {
+	label := f.name
+	if f.required {
+		label += "*"
+	}
+	switch f.in {
+	case fieldBody, fieldRaw:
+		return label
+	}
+	return label + " (" + f.in + ")"
+}
+
+// formFields generates the fields of op's form.
+func formFields(op openapiutil.Operation, contentType string) []formField {
+	var fields []formField
+	for _, param := range op.Parameters {
+		switch param.In {
+		case fieldPath, fieldQuery, fieldHeader:
+			fields = append(fields, formField{
+				name:     param.Name,
+				in:       param.In,
+				kind:     "string",
+				required: param.Required || param.In == fieldPath,
+				help:     param.Description,
+			})
+		}
+	}
+	if contentType == "" {
+		return fields
+	}
+	required := op.RequestBody != nil && op.RequestBody.Value != nil && op.RequestBody.Value.Required
+	schema := openapiutil.RequestSchema(&op, contentType)
+	if strings.Contains(contentType, "json") && schema != nil && len(schema.Properties) > 0 {
+		names := make([]string, 0, len(schema.Properties))
+		for name := range schema.Properties {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		requiredProps := make(map[string]bool, len(schema.Required))
+		for _, name := range schema.Required {
+			requiredProps[name] = true
+		}
+		for _, name := range names {
+			field := formField{name: name, in: fieldBody, required: requiredProps[name]}
+			if prop := schema.Properties[name].Value; prop != nil {
+				field.kind = schemaKind(prop)
+				field.help = prop.Description
+				field.value = []rune(initialValue(prop))
+			}
+			fields = append(fields, field)
+		}
+		return fields
+	}
+	body := formField{name: "body", in: fieldRaw, required: required, help: contentType}
+	if schema != nil {
+		body.value = []rune(initialValue(schema))
+	}
+	return append(fields, body)
+}
+
+// schemaKind is the JSON type a body property's text is converted to.
+func schemaKind(schema *openapi3.Schema) string {
+	for _, kind := range schema.Type.Slice() {
+		if kind != openapi3.TypeNull {
+			return kind
+		}
+	}
+	return ""
+}
+
+// initialValue prefills a field from the schema's default or example.
+func initialValue(schema *openapi3.Schema) string {
+	value := schema.Default
+	if value == nil {
+		value = schema.Example
+	}
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	default:
+		data, err := json.Marshal(v)
+		if err != nil {
+			return ""
+		}
+		return string(data)
+	}
+}
+
+// convertValue turns the text of a body property into its JSON type.
+// Untyped properties take JSON when the text parses as JSON and a string
+// otherwise.
+func convertValue(kind, value string) (any, error) {
+	switch kind {
+	case openapi3.TypeString:
+		return value, nil
+	case openapi3.TypeInteger:
+		return strconv.ParseInt(value, 10, 64)
+	case openapi3.TypeNumber:
+		return strconv.ParseFloat(value, 64)
+	case openapi3.TypeBoolean:
+		return strconv.ParseBool(value)
+	case openapi3.TypeArray, openapi3.TypeObject:
+		var decoded any
+		if err := json.Unmarshal([]byte(value), &decoded); err != nil {
+			return nil, fmt.Errorf("expected JSON %s: %w", kind, err)
+		}
+		return decoded, nil
+	default:
+		var decoded any
+		if err := json.Unmarshal([]byte(value), &decoded); err == nil {
+			return decoded, nil
+		}
+		return value, nil
+	}
+}
+
+// responseLines formats a plugin response body for display, indenting JSON.
+func responseLines(contentType string, data []byte) []string {
+	if strings.Contains(contentType, "json") {
+		var indented bytes.Buffer
+		if json.Indent(&indented, data, "", "  ") == nil {
+			data = indented.Bytes()
+		}
+	}
+	text := strings.TrimRight(string(data), "\n")
+	if text == "" {
+		return []string{"(empty response)"}
+	}
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		lines[i] = clean(line)
+	}
+	return lines
+}
+
+// textScreen shows scrollable text such as a plugin response.
+type textScreen struct {
+	name    string
+	heading string
+	pane    textPane
+}
+
+func newTextScreen(name, heading string, lines []string) *textScreen {
+	s := &textScreen{name: name, heading: heading}
+	s.pane.set(lines)
+	s.pane.offset = len(lines)
+	return s
+}
+
+func (s *textScreen) title() string { return s.name }
+
+func (s *textScreen) help() string { return "↑↓ pgup pgdn scroll  esc back" }
+
+func (s *textScreen) init(a *app) {}
+
+func (s *textScreen) close() {}
+
+func (s *textScreen) update(a *app, m msg) {
+	if k, ok := m.(keyMsg); ok {
+		if k.key == "esc" || k.key == "q" {
+			a.pop()
+			return
+		}
+		s.pane.scroll(k.key)
+	}
+}
+
+func (s *textScreen) view(a *app, width, height int) []string {
+	lines := []string{styleBold + fit(s.heading, width) + styleReset, ""}
+	return append(lines, s.pane.view(width, height-len(lines))...)
+}
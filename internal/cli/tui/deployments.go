@@ -0,0 +1,174 @@
+// Copyright (c) 2025 HYPR. PTE. LTD.
+//
+// Business Source License 1.1
+// See LICENSE file in the project root for details.
+
+package tui
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/volantvm/volant/pkg/volantclient"
+)
+
+// deploymentsRefresh is how many ticks pass between reloads, so ready counts
+// follow a scale in progress.
+const deploymentsRefresh = 3
+
+type deploymentsLoadedMsg struct {
+	deployments []volantclient.Deployment
+	err         error
+}
+
+// deploymentsScreen lists deployments and scales them: + and - adjust the
+// replica count and Enter applies it.
+type deploymentsScreen struct {
+	deployments []volantclient.Deployment
+	loaded      bool
+	selected    int
+	// pending holds replica counts adjusted but not yet applied.
+	pending map[string]int
+	ticks   int
+}
+
+func newDeploymentsScreen() *deploymentsScreen {
+	return &deploymentsScreen{pending: make(map[string]int)}
+}
+
+func (s *deploymentsScreen) title() string { return "Deployments" }
+
+func (s *deploymentsScreen) help() string {
+	return "↑↓ select  +/- replicas  enter apply  u undo  r refresh  esc back"
+}
+
+func (s *deploymentsScreen) init(a *app) {
+	s.load(a)
+}
+
+func (s *deploymentsScreen) close() {}
+
+func (s *deploymentsScreen) load(a *app) {
+	a.do(s, func() msg {
+		ctx, cancel := context.WithTimeout(a.ctx, requestTimeout)
+		defer cancel()
+		deployments, err := a.api.ListDeployments(ctx)
+		return deploymentsLoadedMsg{deployments: deployments, err: err}
+	})
+}
+
+func (s *deploymentsScreen) current() *volantclient.Deployment {
+	if s.selected < 0 || s.selected >= len(s.deployments) {
+		return nil
+	}
+	return &s.deployments[s.selected]
+}
+
+func (s *deploymentsScreen) update(a *app, m msg) {
+	switch m := m.(type) {
+	case deploymentsLoadedMsg:
+		if m.err != nil {
+			a.fail(m.err)
+			return
+		}
+		sort.Slice(m.deployments, func(i, j int) bool { return m.deployments[i].Name < m.deployments[j].Name })
+		s.deployments = m.deployments
+		s.loaded = true
+		s.selected = moveSelection("", s.selected, len(s.deployments), 0)
+	case actionDoneMsg:
+		if m.err != nil {
+			a.fail(m.err)
+		} else {
+			a.flash("%s", m.text)
+		}
+		s.load(a)
+	case tickMsg:
+		if s.ticks++; s.ticks%deploymentsRefresh == 0 {
+			s.load(a)
+		}
+	case keyMsg:
+		s.handleKey(a, m)
+	}
+}
+
+func (s *deploymentsScreen) handleKey(a *app, k keyMsg) {
+	dep := s.current()
+	switch k.key {
+	case "+", "=", "-":
+		if dep == nil {
+			return
+		}
+		replicas, ok := s.pending[dep.Name]
+		if !ok {
+			replicas = dep.DesiredReplicas
+		}
+		if k.key == "-" {
+			replicas--
+		} else {
+			replicas++
+		}
+		if replicas < 0 {
+			replicas = 0
+		}
+		if replicas == dep.DesiredReplicas {
+			delete(s.pending, dep.Name)
+		} else {
+			s.pending[dep.Name] = replicas
+		}
+	case "u":
+		if dep != nil {
+			delete(s.pending, dep.Name)
+		}
+	case "enter":
+		if dep == nil {
+			return
+		}
+		replicas, ok := s.pending[dep.Name]
+		if !ok {
+			return
+		}
+		delete(s.pending, dep.Name)
+		s.scale(a, dep.Name, replicas)
+	case "r":
+		s.load(a)
+	case "esc", "q":
+		a.pop()
+	default:
+		s.selected = moveSelection(k.key, s.selected, len(s.deployments), 10)
+	}
+}
+
+func (s *deploymentsScreen) scale(a *app, name string, replicas int) {
+	a.flash("Scaling %s to %d replicas…", name, replicas)
+	a.do(s, func() msg {
+		ctx, cancel := context.WithTimeout(a.ctx, actionTimeout)
+		defer cancel()
+		dep, err := a.api.ScaleDeployment(ctx, name, replicas)
+		if err != nil {
+			return actionDoneMsg{err: fmt.Errorf("%s: %w", name, err)}
+		}
+		return actionDoneMsg{text: fmt.Sprintf("Deployment %s scaled to %d replicas (ready %d)", dep.Name, dep.DesiredReplicas, dep.ReadyReplicas)}
+	})
+}
+
+func (s *deploymentsScreen) view(a *app, width, height int) []string {
+	lines := []string{styleBold + fit(fmt.Sprintf("%-24s %-16s %-12s %-8s %s", "NAME", "PLUGIN", "DESIRED", "READY", "UPDATED"), width) + styleReset}
+	switch {
+	case !s.loaded:
+		lines = append(lines, "Loading deployments…")
+	case len(s.deployments) == 0:
+		lines = append(lines, "No deployments found. Create one with \"volar deployments create\".")
+	}
+	start, end := window(s.selected, len(s.deployments), height-1)
+	for i := start; i < end; i++ {
+		dep := s.deployments[i]
+		desired := fmt.Sprint(dep.DesiredReplicas)
+		if replicas, ok := s.pending[dep.Name]; ok {
+			desired = fmt.Sprintf("%d → %d", dep.DesiredReplicas, replicas)
+		}
+		row := fmt.Sprintf("%-24s %-16s %-12s %-8d %s", dep.Name, dep.Config.Plugin, desired, dep.ReadyReplicas, dep.UpdatedAt.Local().Format("2006-01-02 15:04"))
+		lines = append(lines, selectRow(row, width, i == s.selected))
+	}
+	return lines
+}
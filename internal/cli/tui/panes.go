@@ -0,0 +1,191 @@
+// Copyright (c) 2025 HYPR. PTE. LTD.
+//
+// Business Source License 1.1
+// See LICENSE file in the project root for details.
+
+package tui
+
+import (
+	"unicode/utf8"
+)
+
+const (
+	maxPaneLines    = 2000
+	maxConsoleLines = 1000
+)
+
+// textPane is a scrollable list of lines that follows the newest line until
+// the user scrolls up.
+type textPane struct {
+	lines []string
+	// offset is how many lines the view is scrolled up from the bottom.
+	offset int
+	height int
+}
+
+func (p *textPane) append(line string) {
+	p.lines = appendBounded(p.lines, line, maxPaneLines)
+	if p.offset > 0 {
+		// Keep the view still while the user reads older lines.
+		p.offset++
+	}
+	p.clamp()
+}
+
+func (p *textPane) set(lines []string) {
+	p.lines = lines
+	p.offset = 0
+}
+
+// scroll applies the scrolling keys and reports whether key was one.
+func (p *textPane) scroll(key string) bool {
+	page := p.height - 1
+	if page < 1 {
+		page = 1
+	}
+	switch key {
+	case "up", "k":
+		p.offset++
+	case "down", "j":
+		p.offset--
+	case "pgup":
+		p.offset += page
+	case "pgdown":
+		p.offset -= page
+	case "home", "g":
+		p.offset = len(p.lines)
+	case "end", "G":
+		p.offset = 0
+	default:
+		return false
+	}
+	p.clamp()
+	return true
+}
+
+func (p *textPane) clamp() {
+	if top := len(p.lines) - p.height; p.offset > top {
+		p.offset = top
+	}
+	if p.offset < 0 {
+		p.offset = 0
+	}
+}
+
+func (p *textPane) view(width, height int) []string {
+	p.height = height
+	p.clamp()
+	end := len(p.lines) - p.offset
+	start := end - height
+	if start < 0 {
+		start = 0
+	}
+	out := make([]string, 0, height)
+	for _, line := range p.lines[start:end] {
+		out = append(out, fit(line, width))
+	}
+	return out
+}
+
+// consolePane keeps the recent output of a serial console as lines. It
+// understands carriage returns, backspaces and tabs, and drops other escape
+// sequences since the pane is not a terminal emulator.
+type consolePane struct {
+	lines   []string
+	cur     []rune
+	col     int
+	esc     int
+	partial []byte
+}
+
+// Escape sequence states of consolePane.
+const (
+	escNone = iota
+	escStart
+	escCSI
+	escOSC
+)
+
+func (p *consolePane) write(data []byte) {
+	data = append(p.partial, data...)
+	p.partial = nil
+	for len(data) > 0 {
+		if !utf8.FullRune(data) {
+			p.partial = append([]byte(nil), data...)
+			return
+		}
+		r, size := utf8.DecodeRune(data)
+		data = data[size:]
+		switch p.esc {
+		case escStart:
+			switch r {
+			case '[':
+				p.esc = escCSI
+			case ']':
+				p.esc = escOSC
+			default:
+				p.esc = escNone
+			}
+			continue
+		case escCSI:
+			if r >= 0x40 && r <= 0x7e {
+				p.esc = escNone
+			}
+			continue
+		case escOSC:
+			// OSC ends with BEL or ESC \.
+			if r == 0x07 {
+				p.esc = escNone
+			} else if r == 0x1b {
+				p.esc = escStart
+			}
+			continue
+		}
+		switch r {
+		case 0x1b:
+			p.esc = escStart
+		case '\n':
+			p.lines = appendBounded(p.lines, string(p.cur), maxConsoleLines)
+			p.cur = nil
+			p.col = 0
+		case '\r':
+			p.col = 0
+		case '\b':
+			if p.col > 0 {
+				p.col--
+			}
+		case '\t':
+			for {
+				p.put(' ')
+				if p.col%8 == 0 {
+					break
+				}
+			}
+		default:
+			if r >= 0x20 && r != 0x7f {
+				p.put(r)
+			}
+		}
+	}
+}
+
+func (p *consolePane) put(r rune) {
+	if p.col < len(p.cur) {
+		p.cur[p.col] = r
+	} else {
+		p.cur = append(p.cur, r)
+	}
+	p.col++
+}
+
+func (p *consolePane) view(width, height int) []string {
+	lines := append(append([]string(nil), p.lines...), string(p.cur))
+	if len(lines) > height {
+		lines = lines[len(lines)-height:]
+	}
+	out := make([]string, 0, len(lines))
+	for _, line := range lines {
+		out = append(out, fit(line, width))
+	}
+	return out
+}
@@ -0,0 +1,432 @@
+// Copyright (c) 2025 HYPR. PTE. LTD.
+//
+// Business Source License 1.1
+// See LICENSE file in the project root for details.
+
+// Package tui implements volar's interactive terminal UI: a VM list with live
+// lifecycle events, per-VM log, console and plugin action panes, and
+// deployment scaling. Screens follow an update/view loop and are drawn with
+// plain ANSI sequences on a raw-mode terminal.
+package tui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"golang.org/x/term"
+
+	"github.com/volantvm/volant/pkg/volantclient"
+)
+
+const (
+	// requestTimeout bounds API calls made while a screen loads or refreshes.
+	requestTimeout = 15 * time.Second
+	// actionTimeout bounds lifecycle actions and plugin operations.
+	actionTimeout = 60 * time.Second
+	// statusTTL is how long a footer message replaces the key help.
+	statusTTL = 5 * time.Second
+	// reconnectDelay spaces out attempts to reopen a dropped stream.
+	reconnectDelay = 3 * time.Second
+)
+
+const (
+	enterAltScreen = "\x1b[?1049h\x1b[?25l"
+	leaveAltScreen = "\x1b[?25h\x1b[?1049l"
+	styleReset     = "\x1b[0m"
+	styleBold      = "\x1b[1m"
+	styleReverse   = "\x1b[7m"
+	styleDim       = "\x1b[2m"
+	styleRed       = "\x1b[31m"
+)
+
+type msg any
+
+// keyMsg is one key press. key names special keys ("up", "enter", "esc",
+// "ctrl+c", ...) and is the character itself otherwise; raw holds the bytes
+// read, which the console pane forwards to the guest unchanged.
+type keyMsg struct {
+	key string
+	raw []byte
+}
+
+// tickMsg is delivered to the top screen once a second so it can refresh.
+type tickMsg time.Time
+
+// screenMsg carries the result of background work to the screen that
+// started it, which may no longer be on top.
+type screenMsg struct {
+	to  screen
+	msg msg
+}
+
+// screen is one view of the UI. Screens are stacked: Enter pushes a detail
+// screen and Esc pops back to the one below.
+type screen interface {
+	// title names the screen in the header breadcrumbs.
+	title() string
+	// help is the key summary shown in the footer.
+	help() string
+	// init starts the screen's loads and streams when it is pushed.
+	init(a *app)
+	update(a *app, m msg)
+	// view renders the body, at most height lines of width columns.
+	view(a *app, width, height int) []string
+	// close stops the screen's streams when it is popped.
+	close()
+}
+
+// keyCapturer is implemented by screens that take every key, including
+// Ctrl-C, while in a mode such as an attached console.
+type keyCapturer interface {
+	capturesKeys() bool
+}
+
+type app struct {
+	ctx       context.Context
+	api       *volantclient.Client
+	msgs      chan screenMsg
+	stack     []screen
+	status    string
+	statusErr bool
+	statusAt  time.Time
+}
+
+// Run shows the UI on the terminal behind in and out until the user quits
+// or ctx ends.
+func Run(ctx context.Context, api *volantclient.Client, in *os.File, out io.Writer) error {
+	fd := int(in.Fd())
+	if !term.IsTerminal(fd) {
+		return errors.New("tui: standard input is not a terminal")
+	}
+	state, err := term.MakeRaw(fd)
+	if err != nil {
+		return fmt.Errorf("tui: raw mode: %w", err)
+	}
+	defer term.Restore(fd, state)
+	io.WriteString(out, enterAltScreen)
+	defer io.WriteString(out, leaveAltScreen)
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	a := &app{ctx: ctx, api: api, msgs: make(chan screenMsg, 64)}
+	defer func() {
+		for len(a.stack) > 0 {
+			a.pop()
+		}
+	}()
+
+	keys := make(chan keyMsg, 16)
+	go readKeys(in, keys)
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+
+	a.push(newVMListScreen())
+	for len(a.stack) > 0 {
+		a.draw(out, fd)
+		select {
+		case <-ctx.Done():
+			return nil
+		case k, ok := <-keys:
+			if !ok {
+				return nil
+			}
+			a.handleKey(k)
+		case m := <-a.msgs:
+			a.dispatch(m)
+		case t := <-ticker.C:
+			a.top().update(a, tickMsg(t))
+		}
+	}
+	return nil
+}
+
+func (a *app) top() screen {
+	return a.stack[len(a.stack)-1]
+}
+
+func (a *app) push(s screen) {
+	a.stack = append(a.stack, s)
+	s.init(a)
+}
+
+func (a *app) pop() {
+	a.top().close()
+	a.stack = a.stack[:len(a.stack)-1]
+}
+
+// quit pops every screen, which ends Run.
+func (a *app) quit() {
+	for len(a.stack) > 0 {
+		a.pop()
+	}
+}
+
+func (a *app) handleKey(k keyMsg) {
+	top := a.top()
+	if c, ok := top.(keyCapturer); ok && c.capturesKeys() {
+		top.update(a, k)
+		return
+	}
+	if k.key == "ctrl+c" {
+		a.quit()
+		return
+	}
+	top.update(a, k)
+}
+
+// dispatch hands m to its screen if that screen is still open.
+func (a *app) dispatch(m screenMsg) {
+	for _, s := range a.stack {
+		if s == m.to {
+			s.update(a, m.msg)
+			return
+		}
+	}
+}
+
+// do runs fn in the background and delivers its result to s.
+func (a *app) do(s screen, fn func() msg) {
+	go func() {
+		a.send(s, fn())
+	}()
+}
+
+// send delivers m to s from a background goroutine.
+func (a *app) send(s screen, m msg) {
+	select {
+	case a.msgs <- screenMsg{to: s, msg: m}:
+	case <-a.ctx.Done():
+	}
+}
+
+// flash shows a message in the footer for a few seconds.
+func (a *app) flash(format string, args ...any) {
+	a.status = fmt.Sprintf(format, args...)
+	a.statusErr = false
+	a.statusAt = time.Now()
+}
+
+// fail shows err in the footer.
+func (a *app) fail(err error) {
+	a.status = "error: " + err.Error()
+	a.statusErr = true
+	a.statusAt = time.Now()
+}
+
+func (a *app) draw(out io.Writer, fd int) {
+	width, height, err := term.GetSize(fd)
+	if err != nil || width < 20 || height < 5 {
+		width, height = 80, 24
+	}
+	titles := make([]string, 0, len(a.stack))
+	for _, s := range a.stack {
+		titles = append(titles, s.title())
+	}
+	header := " volar › " + strings.Join(titles, " › ")
+	if base := a.api.BaseURL(); base != nil {
+		header = padRight(header, width-len(base.String())-1) + base.String()
+	}
+
+	lines := make([]string, 0, height)
+	lines = append(lines, styleBold+styleReverse+fit(header, width)+styleReset)
+	body := a.top().view(a, width, height-2)
+	for i := 0; i < height-2; i++ {
+		if i < len(body) {
+			lines = append(lines, body[i])
+		} else {
+			lines = append(lines, "")
+		}
+	}
+	footer := styleDim + fit(" "+a.top().help(), width) + styleReset
+	if a.status != "" && time.Since(a.statusAt) < statusTTL {
+		footer = fit(" "+a.status, width)
+		if a.statusErr {
+			footer = styleRed + footer + styleReset
+		}
+	}
+	lines = append(lines, footer)
+
+	var b strings.Builder
+	b.WriteString("\x1b[H")
+	b.WriteString(strings.Join(lines, "\x1b[K\r\n"))
+	b.WriteString("\x1b[K\x1b[J")
+	io.WriteString(out, b.String())
+}
+
+// csiKeys names the escape sequences of special keys by what follows
+// "ESC [" or "ESC O".
+var csiKeys = map[string]string{
+	"A":  "up",
+	"B":  "down",
+	"C":  "right",
+	"D":  "left",
+	"H":  "home",
+	"F":  "end",
+	"Z":  "shift+tab",
+	"1~": "home",
+	"3~": "delete",
+	"4~": "end",
+	"5~": "pgup",
+	"6~": "pgdown",
+}
+
+func readKeys(r io.Reader, keys chan<- keyMsg) {
+	defer close(keys)
+	buf := make([]byte, 256)
+	for {
+		n, err := r.Read(buf)
+		data := buf[:n]
+		for len(data) > 0 {
+			key, size := parseKey(data)
+			keys <- keyMsg{key: key, raw: append([]byte(nil), data[:size]...)}
+			data = data[size:]
+		}
+		if err != nil {
+			return
+		}
+	}
+}
+
+// parseKey decodes the key at the start of data and returns its name and
+// length. Unknown sequences are consumed with an empty name.
+func parseKey(data []byte) (string, int) {
+	switch b := data[0]; {
+	case b == 0x1b:
+		if len(data) == 1 || (data[1] != '[' && data[1] != 'O') {
+			return "esc", 1
+		}
+		// Parameters follow until a final byte in 0x40-0x7e.
+		for i := 2; i < len(data); i++ {
+			if data[i] >= 0x40 && data[i] <= 0x7e {
+				return csiKeys[string(data[2:i+1])], i + 1
+			}
+		}
+		return "", len(data)
+	case b == '\r' || b == '\n':
+		return "enter", 1
+	case b == '\t':
+		return "tab", 1
+	case b == 0x7f || b == 0x08:
+		return "backspace", 1
+	case b == 0x1d:
+		return "ctrl+]", 1
+	case b >= 0x01 && b <= 0x1a:
+		return "ctrl+" + string(rune('a'+b-1)), 1
+	case b < 0x20:
+		return "", 1
+	}
+	r, size := utf8.DecodeRune(data)
+	return string(r), size
+}
+
+// fit truncates or pads s to exactly width columns.
+func fit(s string, width int) string {
+	if width <= 0 {
+		return ""
+	}
+	runes := []rune(s)
+	if len(runes) > width {
+		if width == 1 {
+			return "…"
+		}
+		return string(runes[:width-1]) + "…"
+	}
+	return s + strings.Repeat(" ", width-len(runes))
+}
+
+// padRight pads s to at least width columns.
+func padRight(s string, width int) string {
+	if n := utf8.RuneCountInString(s); n < width {
+		return s + strings.Repeat(" ", width-n)
+	}
+	return s + " "
+}
+
+// clean makes guest text safe to draw: tabs become spaces, and escape
+// sequences and other control characters are dropped.
+func clean(s string) string {
+	var b strings.Builder
+	esc := false
+	for _, r := range s {
+		switch {
+		case esc:
+			if r >= 0x40 && r <= 0x7e && r != '[' {
+				esc = false
+			}
+		case r == 0x1b:
+			esc = true
+		case r == '\t':
+			b.WriteString("    ")
+		case r < 0x20 || r == 0x7f:
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// window returns the range of a list of total rows to show in height rows
+// so that selected stays visible.
+func window(selected, total, height int) (int, int) {
+	if height <= 0 || total == 0 {
+		return 0, 0
+	}
+	start := 0
+	if selected >= height {
+		start = selected - height + 1
+	}
+	end := start + height
+	if end > total {
+		end = total
+	}
+	return start, end
+}
+
+// moveSelection applies the list navigation keys to selected.
+func moveSelection(key string, selected, total, page int) int {
+	switch key {
+	case "up", "k":
+		selected--
+	case "down", "j":
+		selected++
+	case "pgup":
+		selected -= page
+	case "pgdown":
+		selected += page
+	case "home", "g":
+		selected = 0
+	case "end", "G":
+		selected = total - 1
+	}
+	if selected >= total {
+		selected = total - 1
+	}
+	if selected < 0 {
+		selected = 0
+	}
+	return selected
+}
+
+// selectRow renders a list row, highlighted when it is selected.
+func selectRow(text string, width int, selected bool) string {
+	line := fit(text, width)
+	if selected {
+		return styleReverse + line + styleReset
+	}
+	return line
+}
+
+// appendBounded appends line and drops the oldest lines beyond limit.
+func appendBounded(lines []string, line string, limit int) []string {
+	lines = append(lines, line)
+	if len(lines) > limit {
+		lines = append(lines[:0], lines[len(lines)-limit:]...)
+	}
+	return lines
+}
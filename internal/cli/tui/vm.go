@@ -0,0 +1,382 @@
+// Copyright (c) 2025 HYPR. PTE. LTD.
+//
+// Business Source License 1.1
+// See LICENSE file in the project root for details.
+
+package tui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/volantvm/volant/internal/cli/openapiutil"
+	"github.com/volantvm/volant/pkg/volantclient"
+)
+
+const (
+	// logHistory is how many persisted log lines the logs tab starts with.
+	logHistory = 500
+	// consoleReplay is how many recent console lines are replayed on attach.
+	consoleReplay = 200
+	// vmRefresh is how many ticks pass between reloads of the VM summary.
+	vmRefresh = 5
+)
+
+// Tabs of vmScreen.
+const (
+	tabLogs = iota
+	tabConsole
+	tabActions
+)
+
+var tabNames = []string{"Logs", "Console", "Actions"}
+
+type vmLoadedMsg struct {
+	vm  *volantclient.VM
+	err error
+}
+
+type logsLoadedMsg struct {
+	entries []volantclient.VMLogEntry
+	err     error
+}
+
+type logLineMsg volantclient.VMLogEvent
+
+type consoleAttachedMsg struct {
+	conn io.ReadWriteCloser
+	err  error
+}
+
+type consoleOutputMsg []byte
+
+type consoleClosedMsg struct {
+	conn io.ReadWriteCloser
+	err  error
+}
+
+type operationsLoadedMsg struct {
+	ops []openapiutil.Operation
+	err error
+}
+
+// vmScreen shows one VM: its persisted and live logs, its serial console,
+// and the operations its plugin's OpenAPI document offers.
+type vmScreen struct {
+	name   string
+	vm     *volantclient.VM
+	tab    int
+	ctx    context.Context
+	cancel context.CancelFunc
+	ticks  int
+
+	logs textPane
+
+	console   consolePane
+	conn      io.ReadWriteCloser
+	attaching bool
+
+	ops          []openapiutil.Operation
+	opsRequested bool
+	opsLoaded    bool
+	opsErr       error
+	opSelected   int
+}
+
+func newVMScreen(name string) *vmScreen {
+	return &vmScreen{name: name}
+}
+
+func (s *vmScreen) title() string { return s.name }
+
+func (s *vmScreen) help() string {
+	switch {
+	case s.conn != nil:
+		return "Console attached: keys go to the guest  ctrl+] detach"
+	case s.tab == tabConsole:
+		return "tab switch pane  enter attach  esc back"
+	case s.tab == tabActions:
+		return "tab switch pane  ↑↓ select  enter open form  r reload  esc back"
+	default:
+		return "tab switch pane  ↑↓ pgup pgdn scroll  end follow  esc back"
+	}
+}
+
+func (s *vmScreen) init(a *app) {
+	s.ctx, s.cancel = context.WithCancel(a.ctx)
+	s.loadVM(a)
+	a.do(s, func() msg {
+		ctx, cancel := context.WithTimeout(s.ctx, requestTimeout)
+		defer cancel()
+		entries, err := a.api.GetVMLogs(ctx, s.name, volantclient.VMLogsQuery{Limit: logHistory})
+		return logsLoadedMsg{entries: entries, err: err}
+	})
+}
+
+func (s *vmScreen) close() {
+	s.detach()
+	s.cancel()
+}
+
+func (s *vmScreen) capturesKeys() bool {
+	return s.conn != nil
+}
+
+func (s *vmScreen) loadVM(a *app) {
+	a.do(s, func() msg {
+		ctx, cancel := context.WithTimeout(s.ctx, requestTimeout)
+		defer cancel()
+		vm, err := a.api.GetVM(ctx, s.name)
+		return vmLoadedMsg{vm: vm, err: err}
+	})
+}
+
+// followLogs streams live log lines once the history is loaded, reconnecting
+// when the stream drops.
+func (s *vmScreen) followLogs(a *app) {
+	for {
+		err := a.api.WatchVMLogs(s.ctx, s.name, func(event volantclient.VMLogEvent) {
+			a.send(s, logLineMsg(event))
+		})
+		if s.ctx.Err() != nil {
+			return
+		}
+		if err != nil {
+			a.send(s, streamEndedMsg{err: err})
+		}
+		select {
+		case <-s.ctx.Done():
+			return
+		case <-time.After(reconnectDelay):
+		}
+	}
+}
+
+func (s *vmScreen) attach(a *app) {
+	if s.conn != nil || s.attaching {
+		return
+	}
+	s.attaching = true
+	a.flash("Attaching to the %s console…", s.name)
+	a.do(s, func() msg {
+		conn, err := a.api.AttachConsole(s.ctx, s.name, consoleReplay)
+		return consoleAttachedMsg{conn: conn, err: err}
+	})
+}
+
+// readConsole forwards console output until the connection closes.
+func (s *vmScreen) readConsole(a *app, conn io.ReadWriteCloser) {
+	buf := make([]byte, 4096)
+	for {
+		n, err := conn.Read(buf)
+		if n > 0 {
+			a.send(s, consoleOutputMsg(append([]byte(nil), buf[:n]...)))
+		}
+		if err != nil {
+			a.send(s, consoleClosedMsg{conn: conn, err: err})
+			return
+		}
+	}
+}
+
+func (s *vmScreen) detach() {
+	if s.conn != nil {
+		s.conn.Close()
+		s.conn = nil
+	}
+}
+
+func (s *vmScreen) loadOperations(a *app) {
+	s.opsRequested = true
+	s.opsLoaded = false
+	a.do(s, func() msg {
+		ctx, cancel := context.WithTimeout(s.ctx, requestTimeout)
+		defer cancel()
+		data, _, err := a.api.GetVMOpenAPISpec(ctx, s.name)
+		if err != nil {
+			return operationsLoadedMsg{err: err}
+		}
+		doc, err := openapiutil.ParseDocument(data)
+		if err != nil {
+			return operationsLoadedMsg{err: err}
+		}
+		return operationsLoadedMsg{ops: openapiutil.ListOperations(doc)}
+	})
+}
+
+func (s *vmScreen) update(a *app, m msg) {
+	switch m := m.(type) {
+	case vmLoadedMsg:
+		if m.err != nil {
+			a.fail(m.err)
+			return
+		}
+		s.vm = m.vm
+	case logsLoadedMsg:
+		if m.err != nil {
+			a.fail(m.err)
+		}
+		lines := make([]string, 0, len(m.entries))
+		for _, entry := range m.entries {
+			lines = append(lines, formatLogLine(entry.Timestamp, entry.Source+"/"+entry.Stream, entry.Line))
+		}
+		s.logs.set(lines)
+		go s.followLogs(a)
+	case logLineMsg:
+		s.logs.append(formatLogLine(m.Timestamp, m.Stream, m.Line))
+	case streamEndedMsg:
+		a.fail(fmt.Errorf("log stream: %w (reconnecting)", m.err))
+	case consoleAttachedMsg:
+		s.attaching = false
+		if m.err != nil {
+			a.fail(m.err)
+			return
+		}
+		s.conn = m.conn
+		s.console = consolePane{}
+		go s.readConsole(a, m.conn)
+	case consoleOutputMsg:
+		s.console.write(m)
+	case consoleClosedMsg:
+		if s.conn == m.conn {
+			s.conn = nil
+			if m.err != nil && !errors.Is(m.err, io.EOF) {
+				a.fail(fmt.Errorf("console closed: %w", m.err))
+			} else {
+				a.flash("Console closed")
+			}
+		}
+	case operationsLoadedMsg:
+		s.opsLoaded = true
+		s.ops, s.opsErr = m.ops, m.err
+		s.opSelected = moveSelection("", s.opSelected, len(s.ops), 0)
+	case tickMsg:
+		if s.ticks++; s.ticks%vmRefresh == 0 {
+			s.loadVM(a)
+		}
+	case keyMsg:
+		s.handleKey(a, m)
+	}
+}
+
+func (s *vmScreen) handleKey(a *app, k keyMsg) {
+	if s.conn != nil {
+		if k.key == "ctrl+]" {
+			s.detach()
+			a.flash("Detached from the %s console", s.name)
+			return
+		}
+		if _, err := s.conn.Write(k.raw); err != nil {
+			a.fail(err)
+		}
+		return
+	}
+	switch k.key {
+	case "tab", "right":
+		s.switchTab(a, (s.tab+1)%len(tabNames))
+		return
+	case "shift+tab", "left":
+		s.switchTab(a, (s.tab+len(tabNames)-1)%len(tabNames))
+		return
+	case "1", "2", "3":
+		s.switchTab(a, int(k.key[0]-'1'))
+		return
+	case "esc", "q":
+		a.pop()
+		return
+	}
+	switch s.tab {
+	case tabLogs:
+		s.logs.scroll(k.key)
+	case tabConsole:
+		if k.key == "enter" {
+			s.attach(a)
+		}
+	case tabActions:
+		switch k.key {
+		case "enter":
+			if s.opSelected < len(s.ops) {
+				a.push(newActionFormScreen(s.name, s.ops[s.opSelected]))
+			}
+		case "r":
+			s.loadOperations(a)
+		default:
+			s.opSelected = moveSelection(k.key, s.opSelected, len(s.ops), 10)
+		}
+	}
+}
+
+func (s *vmScreen) switchTab(a *app, tab int) {
+	s.tab = tab
+	if tab == tabActions && !s.opsRequested {
+		s.loadOperations(a)
+	}
+}
+
+func (s *vmScreen) view(a *app, width, height int) []string {
+	var lines []string
+	if vm := s.vm; vm != nil {
+		summary := fmt.Sprintf("%s  %s  %s  %d CPU  %d MB  %s", vm.Name, vm.Status, vm.IPAddress, vm.CPUCores, vm.MemoryMB, vm.Runtime)
+		if vm.PID != nil {
+			summary += fmt.Sprintf("  pid %d", *vm.PID)
+		}
+		lines = append(lines, styleBold+fit(summary, width)+styleReset)
+	} else {
+		lines = append(lines, fit("Loading "+s.name+"…", width))
+	}
+
+	var tabs strings.Builder
+	for i, name := range tabNames {
+		label := fmt.Sprintf(" %d %s ", i+1, name)
+		if i == s.tab {
+			label = styleReverse + label + styleReset
+		}
+		tabs.WriteString(label)
+		tabs.WriteString(" ")
+	}
+	lines = append(lines, tabs.String(), "")
+
+	rows := height - len(lines)
+	switch s.tab {
+	case tabLogs:
+		if len(s.logs.lines) == 0 {
+			lines = append(lines, styleDim+fit("No log lines yet", width)+styleReset)
+		}
+		lines = append(lines, s.logs.view(width, rows)...)
+	case tabConsole:
+		if s.conn == nil && len(s.console.lines) == 0 && len(s.console.cur) == 0 {
+			lines = append(lines, fit("Press Enter to attach to the serial console; Ctrl-] detaches.", width))
+		}
+		lines = append(lines, s.console.view(width, rows)...)
+	case tabActions:
+		lines = append(lines, s.operationsView(width, rows)...)
+	}
+	return lines
+}
+
+func (s *vmScreen) operationsView(width, rows int) []string {
+	switch {
+	case s.opsErr != nil:
+		return []string{fit("Plugin operations unavailable: "+s.opsErr.Error(), width)}
+	case !s.opsLoaded:
+		return []string{fit("Loading the plugin's OpenAPI document…", width)}
+	case len(s.ops) == 0:
+		return []string{fit("The plugin's OpenAPI document has no operations", width)}
+	}
+	lines := []string{styleBold + fit(fmt.Sprintf("%-7s %-32s %-28s %s", "METHOD", "PATH", "OPERATION", "SUMMARY"), width) + styleReset}
+	start, end := window(s.opSelected, len(s.ops), rows-1)
+	for i := start; i < end; i++ {
+		op := s.ops[i]
+		row := fmt.Sprintf("%-7s %-32s %-28s %s", op.Method, op.Path, op.OperationID, op.Summary)
+		lines = append(lines, selectRow(row, width, i == s.opSelected))
+	}
+	return lines
+}
+
+func formatLogLine(ts time.Time, stream, line string) string {
+	return fmt.Sprintf("%s %-17s %s", ts.Local().Format("15:04:05"), stream, clean(line))
+}
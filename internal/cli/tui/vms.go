@@ -0,0 +1,273 @@
+// Copyright (c) 2025 HYPR. PTE. LTD.
+//
+// Business Source License 1.1
+// See LICENSE file in the project root for details.
+
+package tui
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/volantvm/volant/internal/server/orchestrator/events"
+	"github.com/volantvm/volant/pkg/volantclient"
+)
+
+const (
+	maxEvents = 200
+	// vmListRefresh is how many ticks pass between full reloads of the list;
+	// lifecycle events keep it current in between.
+	vmListRefresh = 15
+)
+
+type vmsLoadedMsg struct {
+	vms []volantclient.VM
+	err error
+}
+
+type vmEventMsg volantclient.VMEvent
+
+type streamEndedMsg struct {
+	err error
+}
+
+// actionDoneMsg reports a lifecycle action started from a screen.
+type actionDoneMsg struct {
+	text string
+	err  error
+}
+
+// vmListScreen lists the VMs with their status, kept current by the
+// lifecycle event stream shown below the list.
+type vmListScreen struct {
+	cancel   context.CancelFunc
+	vms      []volantclient.VM
+	loaded   bool
+	selected int
+	events   []string
+	ticks    int
+}
+
+func newVMListScreen() *vmListScreen {
+	return &vmListScreen{}
+}
+
+func (s *vmListScreen) title() string { return "VMs" }
+
+func (s *vmListScreen) help() string {
+	return "↑↓ select  enter open  s start  x stop  t restart  d deployments  r refresh  q quit"
+}
+
+func (s *vmListScreen) init(a *app) {
+	ctx, cancel := context.WithCancel(a.ctx)
+	s.cancel = cancel
+	s.load(a)
+	go s.watch(ctx, a)
+}
+
+func (s *vmListScreen) close() {
+	s.cancel()
+}
+
+func (s *vmListScreen) load(a *app) {
+	a.do(s, func() msg {
+		ctx, cancel := context.WithTimeout(a.ctx, requestTimeout)
+		defer cancel()
+		vms, err := a.api.ListVMs(ctx)
+		return vmsLoadedMsg{vms: vms, err: err}
+	})
+}
+
+// watch follows the lifecycle event stream until ctx ends, reconnecting
+// when it drops.
+func (s *vmListScreen) watch(ctx context.Context, a *app) {
+	for {
+		err := a.api.WatchVMEvents(ctx, func(event volantclient.VMEvent) {
+			if event.Type != events.TypeVMLog {
+				a.send(s, vmEventMsg(event))
+			}
+		})
+		if ctx.Err() != nil {
+			return
+		}
+		a.send(s, streamEndedMsg{err: err})
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(reconnectDelay):
+		}
+	}
+}
+
+func (s *vmListScreen) current() *volantclient.VM {
+	if s.selected < 0 || s.selected >= len(s.vms) {
+		return nil
+	}
+	return &s.vms[s.selected]
+}
+
+func (s *vmListScreen) update(a *app, m msg) {
+	switch m := m.(type) {
+	case vmsLoadedMsg:
+		if m.err != nil {
+			a.fail(m.err)
+			return
+		}
+		s.setVMs(m.vms)
+	case vmEventMsg:
+		s.applyEvent(a, volantclient.VMEvent(m))
+	case streamEndedMsg:
+		if m.err != nil {
+			a.fail(fmt.Errorf("event stream: %w (reconnecting)", m.err))
+		}
+	case actionDoneMsg:
+		if m.err != nil {
+			a.fail(m.err)
+		} else {
+			a.flash("%s", m.text)
+		}
+		s.load(a)
+	case tickMsg:
+		if s.ticks++; s.ticks%vmListRefresh == 0 {
+			s.load(a)
+		}
+	case keyMsg:
+		s.handleKey(a, m)
+	}
+}
+
+func (s *vmListScreen) handleKey(a *app, k keyMsg) {
+	switch k.key {
+	case "enter":
+		if vm := s.current(); vm != nil {
+			a.push(newVMScreen(vm.Name))
+		}
+	case "s", "x", "t":
+		if vm := s.current(); vm != nil {
+			s.act(a, k.key, vm.Name)
+		}
+	case "d":
+		a.push(newDeploymentsScreen())
+	case "r":
+		s.load(a)
+	case "q":
+		a.pop()
+	default:
+		s.selected = moveSelection(k.key, s.selected, len(s.vms), 10)
+	}
+}
+
+// act starts, stops or restarts a VM in the background.
+func (s *vmListScreen) act(a *app, key, name string) {
+	actions := map[string]struct {
+		verb, done string
+		call       func(context.Context, string) (*volantclient.VM, error)
+	}{
+		"s": {"Starting", "started", a.api.StartVM},
+		"x": {"Stopping", "stopped", a.api.StopVM},
+		"t": {"Restarting", "restarted", a.api.RestartVM},
+	}
+	action := actions[key]
+	a.flash("%s %s…", action.verb, name)
+	a.do(s, func() msg {
+		ctx, cancel := context.WithTimeout(a.ctx, actionTimeout)
+		defer cancel()
+		if _, err := action.call(ctx, name); err != nil {
+			return actionDoneMsg{err: fmt.Errorf("%s: %w", name, err)}
+		}
+		return actionDoneMsg{text: fmt.Sprintf("VM %s %s", name, action.done)}
+	})
+}
+
+func (s *vmListScreen) setVMs(vms []volantclient.VM) {
+	var selectedName string
+	if vm := s.current(); vm != nil {
+		selectedName = vm.Name
+	}
+	sort.Slice(vms, func(i, j int) bool { return vms[i].Name < vms[j].Name })
+	s.vms = vms
+	s.loaded = true
+	for i := range vms {
+		if vms[i].Name == selectedName {
+			s.selected = i
+		}
+	}
+	s.selected = moveSelection("", s.selected, len(s.vms), 0)
+}
+
+// applyEvent records event and updates the VM it concerns in place; VMs
+// that appear or disappear reload the list.
+func (s *vmListScreen) applyEvent(a *app, event volantclient.VMEvent) {
+	line := fmt.Sprintf("%s %-18s %-20s %s", event.Timestamp.Local().Format("15:04:05"), event.Type, event.Name, event.Status)
+	if event.Message != "" {
+		line += "  " + event.Message
+	}
+	if event.Hint != "" {
+		line += " (" + event.Hint + ")"
+	}
+	s.events = appendBounded(s.events, clean(line), maxEvents)
+
+	switch event.Type {
+	case events.TypeVMCreated, events.TypeVMDeleted:
+		s.load(a)
+		return
+	}
+	for i := range s.vms {
+		vm := &s.vms[i]
+		if vm.Name != event.Name {
+			continue
+		}
+		if event.Status != "" {
+			vm.Status = string(event.Status)
+		}
+		if event.IPAddress != "" {
+			vm.IPAddress = event.IPAddress
+		}
+		if event.PID != nil || event.Type == events.TypeVMStopped {
+			vm.PID = event.PID
+		}
+		return
+	}
+	s.load(a)
+}
+
+func (s *vmListScreen) view(a *app, width, height int) []string {
+	eventRows := height / 3
+	if eventRows > 10 {
+		eventRows = 10
+	}
+	listRows := height - eventRows - 2
+
+	lines := []string{styleBold + fit(fmt.Sprintf("%-24s %-10s %-16s %4s %8s  %s", "NAME", "STATUS", "IP", "CPU", "MEMORY", "RUNTIME"), width) + styleReset}
+	switch {
+	case !s.loaded:
+		lines = append(lines, "Loading VMs…")
+	case len(s.vms) == 0:
+		lines = append(lines, "No VMs found. Create one with \"volar vms create\".")
+	}
+	start, end := window(s.selected, len(s.vms), listRows)
+	for i := start; i < end; i++ {
+		vm := s.vms[i]
+		row := fmt.Sprintf("%-24s %-10s %-16s %4d %6dMB  %s", vm.Name, vm.Status, vm.IPAddress, vm.CPUCores, vm.MemoryMB, vm.Runtime)
+		lines = append(lines, selectRow(row, width, i == s.selected))
+	}
+	for len(lines) < listRows+1 {
+		lines = append(lines, "")
+	}
+
+	lines = append(lines, styleDim+fit("── Events "+strings.Repeat("─", width), width)+styleReset)
+	recent := s.events
+	if len(recent) > eventRows {
+		recent = recent[len(recent)-eventRows:]
+	}
+	if len(recent) == 0 {
+		lines = append(lines, styleDim+fit("Waiting for lifecycle events…", width)+styleReset)
+	}
+	for _, line := range recent {
+		lines = append(lines, fit(line, width))
+	}
+	return lines
+}
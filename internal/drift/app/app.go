@@ -12,14 +12,17 @@ import (
 
 // Daemon coordinates HTTP serving and graceful shutdown for Drift.
 type Daemon struct {
-	cfg    config.Config
-	logger *slog.Logger
-	http   *http.Server
+	cfg     config.Config
+	logger  *slog.Logger
+	http    *http.Server
+	metrics *http.Server
 }
 
-// New constructs a Daemon with the provided configuration and handler.
-func New(cfg config.Config, logger *slog.Logger, handler http.Handler) *Daemon {
-	return &Daemon{
+// New constructs a Daemon with the provided configuration and handlers.
+// metrics is served on the metrics listener when it differs from the API
+// listener, whose handler is expected to serve metrics as well.
+func New(cfg config.Config, logger *slog.Logger, handler, metrics http.Handler) *Daemon {
+	d := &Daemon{
 		cfg:    cfg,
 		logger: logger,
 		http: &http.Server{
@@ -27,28 +30,42 @@ func New(cfg config.Config, logger *slog.Logger, handler http.Handler) *Daemon {
 			Handler: handler,
 		},
 	}
+	if metrics != nil && cfg.MetricsListen != "" && cfg.MetricsListen != cfg.HTTPListen {
+		d.metrics = &http.Server{
+			Addr:    cfg.MetricsListen,
+			Handler: metrics,
+		}
+	}
+	return d
 }
 
 // Run starts the HTTP server and blocks until the context is canceled.
 func (d *Daemon) Run(ctx context.Context) error {
-	serverErr := make(chan error, 1)
-	go func() {
-		d.logger.Info("http server starting", "addr", d.cfg.HTTPListen)
-		if err := d.http.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
-			serverErr <- err
-		}
-		close(serverErr)
-	}()
+	servers := []*http.Server{d.http}
+	if d.metrics != nil {
+		servers = append(servers, d.metrics)
+	}
+	serverErr := make(chan error, len(servers))
+	for _, srv := range servers {
+		go func(srv *http.Server) {
+			d.logger.Info("http server starting", "addr", srv.Addr)
+			if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				serverErr <- err
+			}
+		}(srv)
+	}
 
+	var runErr error
 	select {
 	case <-ctx.Done():
-		shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-		defer cancel()
-		if err := d.http.Shutdown(shutdownCtx); err != nil {
-			return err
+	case runErr = <-serverErr:
+	}
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	for _, srv := range servers {
+		if err := srv.Shutdown(shutdownCtx); err != nil && runErr == nil {
+			runErr = err
 		}
-		return nil
-	case err := <-serverErr:
-		return err
 	}
+	return runErr
 }
@@ -29,6 +29,33 @@ struct {
 	__type(value, struct portmap_value);
 } portmap SEC(".maps");
 
+struct route_stats {
+	__u64 packets;
+	__u64 bytes;
+};
+
+struct {
+	__uint(type, BPF_MAP_TYPE_PERCPU_HASH);
+	__uint(max_entries, 4096);
+	__type(key, struct portmap_key);
+	__type(value, struct route_stats);
+} routestats SEC(".maps");
+
+static __always_inline void count_packet(struct __sk_buff *skb, struct portmap_key *key)
+{
+	struct route_stats *stats = bpf_map_lookup_elem(&routestats, key);
+	if (stats) {
+		stats->packets++;
+		stats->bytes += skb->len;
+		return;
+	}
+	struct route_stats initial = {
+		.packets = 1,
+		.bytes = skb->len,
+	};
+	bpf_map_update_elem(&routestats, key, &initial, BPF_NOEXIST);
+}
+
 static __always_inline int rewrite_tcp(struct __sk_buff *skb, struct iphdr *iph, struct tcphdr *tcph, __be32 new_ip, __be16 new_port, __u32 l3_off, __u32 l4_off)
 {
 	__be16 old_port = tcph->dest;
@@ -114,6 +141,7 @@ int drift_l4_ingress(struct __sk_buff *skb)
 		struct portmap_value *value = bpf_map_lookup_elem(&portmap, &key);
 		if (!value)
 			return TC_ACT_OK;
+		count_packet(skb, &key);
 		return rewrite_tcp(skb, iph, tcph, value->dst_ip, value->dst_port, l3_off, l4_off);
 	}
 
@@ -128,6 +156,7 @@ int drift_l4_ingress(struct __sk_buff *skb)
 		struct portmap_value *value = bpf_map_lookup_elem(&portmap, &key);
 		if (!value)
 			return TC_ACT_OK;
+		count_packet(skb, &key);
 		return rewrite_udp(skb, iph, udph, value->dst_ip, value->dst_port, l3_off, l4_off);
 	}
 
@@ -15,6 +15,13 @@ const (
 	defaultBPFObject     = "drift_l4.bpf.o"
 )
 
+// Route store backends selectable through DRIFT_ROUTE_STORE.
+const (
+	RouteStoreFile   = "file"
+	RouteStoreSQLite = "sqlite"
+	RouteStoreEtcd   = "etcd"
+)
+
 // Config captures runtime settings for the Drift control daemon.
 type Config struct {
 	HTTPListen    string
@@ -24,6 +31,22 @@ type Config struct {
 	RoutesPath    string
 	BPFObjectPath string
 	APIKey        string
+	// SocketDir holds the unix sockets of vsock routes.
+	SocketDir string
+
+	// RouteStore selects where routes persist: a JSON file (the default),
+	// a SQLite database at RoutesPath, or etcd.
+	RouteStore string
+	Etcd       EtcdConfig
+}
+
+// EtcdConfig locates the etcd cluster of the etcd route store.
+type EtcdConfig struct {
+	Endpoints []string
+	Prefix    string
+	CAFile    string
+	CertFile  string
+	KeyFile   string
 }
 
 // FromEnv loads configuration using environment variables with defaults.
@@ -36,6 +59,15 @@ func FromEnv() (Config, error) {
 		RoutesPath:    expandPath(getenv("DRIFT_ROUTES_PATH", "")),
 		BPFObjectPath: expandPath(getenv("DRIFT_BPF_OBJECT", defaultBPFObject)),
 		APIKey:        strings.TrimSpace(os.Getenv("DRIFT_API_KEY")),
+		SocketDir:     expandPath(getenv("DRIFT_SOCKET_DIR", "")),
+		RouteStore:    strings.ToLower(getenv("DRIFT_ROUTE_STORE", RouteStoreFile)),
+		Etcd: EtcdConfig{
+			Endpoints: splitList(os.Getenv("DRIFT_ETCD_ENDPOINTS")),
+			Prefix:    strings.TrimSpace(os.Getenv("DRIFT_ETCD_PREFIX")),
+			CAFile:    expandPath(os.Getenv("DRIFT_ETCD_CA")),
+			CertFile:  expandPath(os.Getenv("DRIFT_ETCD_CERT")),
+			KeyFile:   expandPath(os.Getenv("DRIFT_ETCD_KEY")),
+		},
 	}
 
 	if cfg.HTTPListen = strings.TrimSpace(cfg.HTTPListen); cfg.HTTPListen == "" {
@@ -49,9 +81,25 @@ func FromEnv() (Config, error) {
 	if cfg.StateDir == "" {
 		return Config{}, fmt.Errorf("state directory required")
 	}
+	if cfg.SocketDir == "" {
+		cfg.SocketDir = filepath.Join(cfg.StateDir, "sockets")
+	}
 
-	if cfg.RoutesPath == "" {
-		cfg.RoutesPath = filepath.Join(cfg.StateDir, "routes.json")
+	switch cfg.RouteStore {
+	case RouteStoreFile:
+		if cfg.RoutesPath == "" {
+			cfg.RoutesPath = filepath.Join(cfg.StateDir, "routes.json")
+		}
+	case RouteStoreSQLite:
+		if cfg.RoutesPath == "" {
+			cfg.RoutesPath = filepath.Join(cfg.StateDir, "routes.db")
+		}
+	case RouteStoreEtcd:
+		if len(cfg.Etcd.Endpoints) == 0 {
+			return Config{}, fmt.Errorf("DRIFT_ETCD_ENDPOINTS required for the etcd route store")
+		}
+	default:
+		return Config{}, fmt.Errorf("unknown route store %q (want file, sqlite or etcd)", cfg.RouteStore)
 	}
 
 	if cfg.BPFObjectPath == "" {
@@ -70,6 +118,16 @@ func getenv(key, fallback string) string {
 	return fallback
 }
 
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
 func expandPath(path string) string {
 	path = strings.TrimSpace(path)
 	if path == "" {
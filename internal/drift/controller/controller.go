@@ -4,10 +4,16 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"log/slog"
 	"net"
+	"path/filepath"
+	"reflect"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/volantvm/volant/internal/drift/dataplane"
+	"github.com/volantvm/volant/internal/drift/l7proxy"
 	"github.com/volantvm/volant/internal/drift/routes"
 	"github.com/volantvm/volant/internal/drift/vsockproxy"
 )
@@ -17,11 +23,18 @@ type Controller struct {
 	store routes.Store
 	dp    dataplane.Interface
 	vsock vsockproxy.Manager
+	l7    l7proxy.Manager
+
+	// mu guards applied, the routes programmed into the runtime managers,
+	// and the reload counters.
+	mu      sync.Mutex
+	applied map[string]routes.Route
+	reloads ReloadStats
 }
 
 // New constructs a Controller.
-func New(store routes.Store, dp dataplane.Interface, vsock vsockproxy.Manager) *Controller {
-	return &Controller{store: store, dp: dp, vsock: vsock}
+func New(store routes.Store, dp dataplane.Interface, vsock vsockproxy.Manager, l7 l7proxy.Manager) *Controller {
+	return &Controller{store: store, dp: dp, vsock: vsock, l7: l7, applied: make(map[string]routes.Route)}
 }
 
 // ValidationError marks input validation failures.
@@ -47,12 +60,22 @@ func (c *Controller) Upsert(ctx context.Context, route routes.Route) (routes.Rou
 		return routes.Route{}, ValidationError{Err: err}
 	}
 
-	if err := c.applyRuntime(ctx, normalized); err != nil {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	key := routes.Key(normalized.HostPort, normalized.Protocol)
+	previous, hadPrevious := c.applied[key]
+	if err := c.replaceRuntime(ctx, previous, hadPrevious, normalized); err != nil {
 		return routes.Route{}, err
 	}
 
 	if err := c.store.Upsert(ctx, normalized); err != nil {
 		_ = c.removeRuntime(ctx, normalized)
+		if hadPrevious && c.applyRuntime(ctx, previous) == nil {
+			c.applied[key] = previous
+		} else {
+			delete(c.applied, key)
+		}
 		return routes.Route{}, err
 	}
 
@@ -66,6 +89,9 @@ func (c *Controller) Delete(ctx context.Context, hostPort uint16, protocol strin
 		return fmt.Errorf("protocol %q not supported", protocol)
 	}
 
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
 	route, err := c.store.Get(ctx, hostPort, protocol)
 	if err != nil {
 		return err
@@ -74,25 +100,93 @@ func (c *Controller) Delete(ctx context.Context, hostPort uint16, protocol strin
 	if err := c.removeRuntime(ctx, *route); err != nil {
 		return err
 	}
+	delete(c.applied, routes.Key(hostPort, protocol))
 
 	return c.store.Delete(ctx, hostPort, protocol)
 }
 
 // Restore replays persisted routes into runtime managers.
 func (c *Controller) Restore(ctx context.Context) error {
+	return c.Sync(ctx)
+}
+
+// Sync reconciles the runtime managers with the store: stored routes are
+// applied, changed ones reprogrammed, and routes no longer stored removed.
+// It picks up the writes other driftd instances make to a shared store.
+func (c *Controller) Sync(ctx context.Context) (err error) {
+	started := time.Now()
+	defer func() {
+		c.mu.Lock()
+		c.reloads.record(started, err)
+		c.mu.Unlock()
+	}()
+
 	items, err := c.store.List(ctx)
 	if err != nil {
 		return err
 	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var errs []error
+	stored := make(map[string]struct{}, len(items))
 	for _, route := range items {
-		if err := c.applyRuntime(ctx, route); err != nil {
+		key := routes.Key(route.HostPort, route.Protocol)
+		stored[key] = struct{}{}
+		previous, hadPrevious := c.applied[key]
+		if hadPrevious && reflect.DeepEqual(previous, route) {
+			continue
+		}
+		if err := c.replaceRuntime(ctx, previous, hadPrevious, route); err != nil {
 			var unavailable RuntimeUnavailableError
 			if errors.As(err, &unavailable) {
 				continue
 			}
-			return fmt.Errorf("restore route %d/%s: %w", route.HostPort, route.Protocol, err)
+			errs = append(errs, fmt.Errorf("restore route %d/%s: %w", route.HostPort, route.Protocol, err))
+		}
+	}
+	for key, route := range c.applied {
+		if _, ok := stored[key]; ok {
+			continue
 		}
+		if err := c.removeRuntime(ctx, route); err != nil {
+			errs = append(errs, fmt.Errorf("remove route %d/%s: %w", route.HostPort, route.Protocol, err))
+			continue
+		}
+		delete(c.applied, key)
+	}
+	return errors.Join(errs...)
+}
+
+// Watch syncs the runtime managers whenever another driftd instance
+// changes a shared store, until ctx is done. Stores that cannot be shared
+// return immediately.
+func (c *Controller) Watch(ctx context.Context, logger *slog.Logger) error {
+	watcher, ok := c.store.(routes.Watcher)
+	if !ok {
+		return nil
 	}
+	return watcher.Watch(ctx, func() {
+		if err := c.Sync(ctx); err != nil && ctx.Err() == nil {
+			logger.Warn("sync routes", "error", err)
+		}
+	})
+}
+
+// replaceRuntime programs route in place of previous, removing previous
+// first when its backend type changed, and records route as applied.
+func (c *Controller) replaceRuntime(ctx context.Context, previous routes.Route, hadPrevious bool, route routes.Route) error {
+	if hadPrevious && previous.Backend.Type != route.Backend.Type {
+		if err := c.removeRuntime(ctx, previous); err != nil {
+			return err
+		}
+		delete(c.applied, routes.Key(previous.HostPort, previous.Protocol))
+	}
+	if err := c.applyRuntime(ctx, route); err != nil {
+		return err
+	}
+	c.applied[routes.Key(route.HostPort, route.Protocol)] = route
 	return nil
 }
 
@@ -108,6 +202,35 @@ func normalize(route routes.Route) (routes.Route, error) {
 	}
 
 	normalized.Backend.Type = routes.BackendType(strings.ToLower(strings.TrimSpace(string(route.Backend.Type))))
+	normalized.Socket = strings.TrimSpace(route.Socket)
+	if normalized.Protocol == "unix" {
+		if normalized.Backend.Type != routes.BackendVsock {
+			return routes.Route{}, fmt.Errorf("unix routes require a vsock backend")
+		}
+		if normalized.Socket == "" {
+			normalized.Socket = fmt.Sprintf("vsock-%d.sock", route.HostPort)
+		}
+		if normalized.Socket != filepath.Base(normalized.Socket) || normalized.Socket == "." || normalized.Socket == ".." {
+			return routes.Route{}, fmt.Errorf("socket must be a file name, not a path")
+		}
+	} else if normalized.Socket != "" {
+		return routes.Route{}, fmt.Errorf("socket requires the unix protocol")
+	}
+	if normalized.Backend.Type == routes.BackendL7 {
+		if normalized.Protocol != "tcp" {
+			return routes.Route{}, fmt.Errorf("l7 routes require tcp protocol")
+		}
+		l7, err := normalizeL7(route.L7)
+		if err != nil {
+			return routes.Route{}, err
+		}
+		normalized.Backend = routes.Backend{Type: routes.BackendL7}
+		normalized.L7 = l7
+		return normalized, nil
+	}
+	if route.L7 != nil {
+		return routes.Route{}, fmt.Errorf("l7 settings require backend type l7")
+	}
 	if normalized.Backend.Port == 0 {
 		return routes.Route{}, fmt.Errorf("backend.port must be > 0")
 	}
@@ -124,8 +247,8 @@ func normalize(route routes.Route) (routes.Route, error) {
 		if route.Backend.CID == 0 {
 			return routes.Route{}, fmt.Errorf("backend.cid must be > 0 for vsock routes")
 		}
-		if normalized.Protocol != "tcp" {
-			return routes.Route{}, fmt.Errorf("vsock routes require tcp protocol")
+		if normalized.Protocol == "udp" {
+			return routes.Route{}, fmt.Errorf("vsock routes require tcp or unix protocol")
 		}
 		normalized.Backend.IP = ""
 	default:
@@ -135,9 +258,80 @@ func normalize(route routes.Route) (routes.Route, error) {
 	return normalized, nil
 }
 
+// maxWeight bounds backend weights, keeping the weighted round robin's
+// running sums far from overflow.
+const maxWeight = 10000
+
+func normalizeL7(cfg *routes.L7) (*routes.L7, error) {
+	if cfg == nil {
+		return nil, fmt.Errorf("l7 settings required for l7 routes")
+	}
+	normalized := routes.L7{Mode: strings.ToLower(strings.TrimSpace(cfg.Mode))}
+	switch normalized.Mode {
+	case routes.L7ModeHTTP, routes.L7ModeTLS, routes.L7ModeTCP:
+	default:
+		return nil, fmt.Errorf("l7.mode %q not supported (want http, tls or tcp)", cfg.Mode)
+	}
+	if len(cfg.Rules) == 0 {
+		return nil, fmt.Errorf("l7.rules must not be empty")
+	}
+	if normalized.Mode == routes.L7ModeTCP && len(cfg.Rules) > 1 {
+		return nil, fmt.Errorf("l7.rules: tcp routes take a single rule")
+	}
+	for i, rule := range cfg.Rules {
+		out := routes.L7Rule{
+			Host:       strings.ToLower(strings.TrimSpace(rule.Host)),
+			PathPrefix: strings.TrimSpace(rule.PathPrefix),
+		}
+		if normalized.Mode == routes.L7ModeTCP && (out.Host != "" || out.PathPrefix != "") {
+			return nil, fmt.Errorf("l7.rules[%d]: tcp routes match no host or path", i)
+		}
+		if strings.Contains(strings.TrimPrefix(out.Host, "*."), "*") {
+			return nil, fmt.Errorf("l7.rules[%d].host: only a leading \"*.\" wildcard is supported", i)
+		}
+		if out.PathPrefix != "" {
+			if normalized.Mode == routes.L7ModeTLS {
+				return nil, fmt.Errorf("l7.rules[%d].path_prefix: tls routes match on host only", i)
+			}
+			if !strings.HasPrefix(out.PathPrefix, "/") {
+				return nil, fmt.Errorf("l7.rules[%d].path_prefix must start with /", i)
+			}
+		}
+		if len(rule.Backends) == 0 {
+			return nil, fmt.Errorf("l7.rules[%d].backends must not be empty", i)
+		}
+		for j, target := range rule.Backends {
+			ip := net.ParseIP(strings.TrimSpace(target.IP))
+			if ip == nil {
+				return nil, fmt.Errorf("l7.rules[%d].backends[%d].ip must be a valid ip", i, j)
+			}
+			if target.Port == 0 {
+				return nil, fmt.Errorf("l7.rules[%d].backends[%d].port must be > 0", i, j)
+			}
+			if target.Weight < 0 || target.Weight > maxWeight {
+				return nil, fmt.Errorf("l7.rules[%d].backends[%d].weight must be between 0 and %d", i, j, maxWeight)
+			}
+			out.Backends = append(out.Backends, routes.L7Target{IP: ip.String(), Port: target.Port, Weight: target.Weight, Draining: target.Draining})
+		}
+		normalized.Rules = append(normalized.Rules, out)
+	}
+	if hc := cfg.HealthCheck; hc != nil {
+		if hc.IntervalSeconds < 0 || hc.TimeoutSeconds < 0 {
+			return nil, fmt.Errorf("l7.health_check interval and timeout must not be negative")
+		}
+		check := *hc
+		check.Path = strings.TrimSpace(check.Path)
+		if check.Path != "" && !strings.HasPrefix(check.Path, "/") {
+			return nil, fmt.Errorf("l7.health_check.path must start with /")
+		}
+		normalized.HealthCheck = &check
+	}
+	return &normalized, nil
+}
+
 func validProtocol(proto string) bool {
 	switch proto {
-	case "tcp", "udp":
+	case "tcp", "udp", "unix":
 		return true
 	default:
 		return false
@@ -169,9 +363,23 @@ func (c *Controller) applyRuntime(ctx context.Context, route routes.Route) error
 		if c.vsock == nil {
 			return RuntimeUnavailableError{Component: "vsock proxy"}
 		}
-		if err := c.vsock.Upsert(ctx, route.Protocol, route.HostPort, route.Backend.CID, route.Backend.Port); err != nil {
+		mapping := vsockproxy.Mapping{
+			Protocol:  route.Protocol,
+			HostPort:  route.HostPort,
+			Socket:    route.Socket,
+			CID:       route.Backend.CID,
+			GuestPort: route.Backend.Port,
+		}
+		if err := c.vsock.Upsert(ctx, mapping); err != nil {
 			return fmt.Errorf("apply vsock proxy: %w", err)
 		}
+	case routes.BackendL7:
+		if c.l7 == nil {
+			return RuntimeUnavailableError{Component: "l7 proxy"}
+		}
+		if err := c.l7.Upsert(ctx, route.HostPort, *route.L7); err != nil {
+			return fmt.Errorf("apply l7 proxy: %w", err)
+		}
 	}
 	return nil
 }
@@ -192,6 +400,13 @@ func (c *Controller) removeRuntime(ctx context.Context, route routes.Route) erro
 		if err := c.vsock.Remove(ctx, route.Protocol, route.HostPort); err != nil {
 			return fmt.Errorf("remove vsock proxy: %w", err)
 		}
+	case routes.BackendL7:
+		if c.l7 == nil {
+			return nil
+		}
+		if err := c.l7.Remove(ctx, route.HostPort); err != nil {
+			return fmt.Errorf("remove l7 proxy: %w", err)
+		}
 	}
 	return nil
 }
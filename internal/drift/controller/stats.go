@@ -0,0 +1,135 @@
+package controller
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/volantvm/volant/internal/drift/dataplane"
+	"github.com/volantvm/volant/internal/drift/l7proxy"
+	"github.com/volantvm/volant/internal/drift/routes"
+	"github.com/volantvm/volant/internal/drift/vsockproxy"
+)
+
+// RouteStats is the traffic a route's backend received since the route was
+// applied. Bridge routes count the packets the dataplane redirected; vsock
+// routes count the connections their proxy forwarded, and l7 routes the
+// requests or connections their proxy routed.
+type RouteStats struct {
+	routes.Route
+	// Applied is false for stored routes the runtime managers do not hold.
+	Applied bool              `json:"applied"`
+	Packets uint64            `json:"packets"`
+	Bytes   uint64            `json:"bytes"`
+	Vsock   *vsockproxy.Stats `json:"vsock,omitempty"`
+	L7Proxy *l7proxy.Stats    `json:"l7_proxy,omitempty"`
+}
+
+// ReloadStats count the times the controller reprogrammed the runtime
+// managers from the store, at startup and on changes by other instances.
+type ReloadStats struct {
+	Reloads      uint64        `json:"reloads"`
+	Failures     uint64        `json:"failures"`
+	LastReload   time.Time     `json:"last_reload"`
+	LastDuration time.Duration `json:"last_duration"`
+}
+
+func (r *ReloadStats) record(started time.Time, err error) {
+	r.Reloads++
+	if err != nil {
+		r.Failures++
+	}
+	r.LastReload = started
+	r.LastDuration = time.Since(started)
+}
+
+// RouteStats returns the traffic of the stored route of hostPort and protocol.
+func (c *Controller) RouteStats(ctx context.Context, hostPort uint16, protocol string) (RouteStats, error) {
+	route, err := c.store.Get(ctx, hostPort, strings.ToLower(strings.TrimSpace(protocol)))
+	if err != nil {
+		return RouteStats{}, err
+	}
+	c.mu.Lock()
+	applied, ok := c.applied[routes.Key(route.HostPort, route.Protocol)]
+	c.mu.Unlock()
+	if !ok || !reflect.DeepEqual(applied, *route) {
+		return RouteStats{Route: *route}, nil
+	}
+	return c.routeStats(ctx, applied)
+}
+
+// AppliedRouteStats returns the traffic of every route the runtime
+// managers hold, ordered by host port and protocol.
+func (c *Controller) AppliedRouteStats(ctx context.Context) ([]RouteStats, error) {
+	c.mu.Lock()
+	applied := make([]routes.Route, 0, len(c.applied))
+	for _, route := range c.applied {
+		applied = append(applied, route)
+	}
+	c.mu.Unlock()
+	sort.Slice(applied, func(i, j int) bool {
+		if applied[i].HostPort != applied[j].HostPort {
+			return applied[i].HostPort < applied[j].HostPort
+		}
+		return applied[i].Protocol < applied[j].Protocol
+	})
+
+	result := make([]RouteStats, 0, len(applied))
+	for _, route := range applied {
+		stats, err := c.routeStats(ctx, route)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, stats)
+	}
+	return result, nil
+}
+
+// ReloadStats returns the reload counters.
+func (c *Controller) ReloadStats() ReloadStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.reloads
+}
+
+// DataplaneStats returns the map operation counters of the bridge
+// dataplane, and false when it is unavailable.
+func (c *Controller) DataplaneStats() (dataplane.Stats, bool) {
+	if c.dp == nil {
+		return dataplane.Stats{}, false
+	}
+	return c.dp.Stats(), true
+}
+
+func (c *Controller) routeStats(ctx context.Context, route routes.Route) (RouteStats, error) {
+	stats := RouteStats{Route: route, Applied: true}
+	switch route.Backend.Type {
+	case routes.BackendBridge:
+		if c.dp == nil {
+			return stats, nil
+		}
+		counters, err := c.dp.Counters(ctx, protocolNumber(route.Protocol), route.HostPort)
+		if err != nil {
+			return RouteStats{}, err
+		}
+		stats.Packets, stats.Bytes = counters.Packets, counters.Bytes
+	case routes.BackendVsock:
+		if c.vsock == nil {
+			return stats, nil
+		}
+		if proxy, ok := c.vsock.Stats(route.Protocol, route.HostPort); ok {
+			stats.Vsock = &proxy
+			stats.Bytes = proxy.BytesIn + proxy.BytesOut
+		}
+	case routes.BackendL7:
+		if c.l7 == nil {
+			return stats, nil
+		}
+		if proxy, ok := c.l7.Stats(route.HostPort); ok {
+			stats.L7Proxy = &proxy
+		}
+	}
+	return stats, nil
+}
@@ -19,11 +19,13 @@ type manager struct {
 	logger   *slog.Logger
 	program  *ebpf.Program
 	portmap  *ebpf.Map
+	stats    *ebpf.Map
 	link     link.Link
 	iface    string
 	mu       sync.Mutex
 	closed   bool
 	programs *ebpf.Collection
+	counts   Stats
 }
 
 func newManager(opts Options) (Interface, error) {
@@ -59,6 +61,13 @@ func newManager(opts Options) (Interface, error) {
 		return nil, errors.New("dataplane: portmap not found")
 	}
 
+	// Objects built before per-route counters lack the map; routes still
+	// work, their counters just stay at zero.
+	stats := coll.Maps["routestats"]
+	if stats == nil {
+		opts.Logger.Warn("dataplane: routestats map not found, per-route counters disabled", "object", opts.ObjectPath)
+	}
+
 	iface, err := net.InterfaceByName(opts.Interface)
 	if err != nil {
 		coll.Close()
@@ -79,6 +88,7 @@ func newManager(opts Options) (Interface, error) {
 		logger:   opts.Logger.With("component", "dataplane"),
 		program:  prog,
 		portmap:  portmap,
+		stats:    stats,
 		link:     l,
 		iface:    opts.Interface,
 		programs: coll,
@@ -108,8 +118,10 @@ func (m *manager) ApplyBridge(_ context.Context, proto uint8, hostPort uint16, d
 	}
 
 	if err := m.portmap.Put(&key, &value); err != nil {
+		m.counts.Failures++
 		return fmt.Errorf("dataplane: portmap update: %w", err)
 	}
+	m.counts.Applies++
 
 	m.logger.Info("route applied", "proto", protoName(proto), "host_port", hostPort, "dest_ip", destIP.String(), "dest_port", destPort)
 	return nil
@@ -128,13 +140,59 @@ func (m *manager) Remove(_ context.Context, proto uint8, hostPort uint16) error
 	}
 
 	if err := m.portmap.Delete(&key); err != nil && !errors.Is(err, ebpf.ErrKeyNotExist) {
+		m.counts.Failures++
 		return fmt.Errorf("dataplane: portmap delete: %w", err)
 	}
+	m.counts.Removes++
+	if m.stats != nil {
+		if err := m.stats.Delete(&key); err != nil && !errors.Is(err, ebpf.ErrKeyNotExist) {
+			m.logger.Warn("reset route counters", "proto", protoName(proto), "host_port", hostPort, "error", err)
+		}
+	}
 
 	m.logger.Info("route removed", "proto", protoName(proto), "host_port", hostPort)
 	return nil
 }
 
+func (m *manager) Counters(_ context.Context, proto uint8, hostPort uint16) (Counters, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.closed {
+		return Counters{}, errors.New("dataplane: manager closed")
+	}
+	if m.stats == nil {
+		return Counters{}, nil
+	}
+
+	key := portmapKey{
+		Proto: proto,
+		Port:  htons(hostPort),
+	}
+
+	// routestats is a per-CPU map: one value per possible CPU.
+	var perCPU []routeStats
+	if err := m.stats.Lookup(&key, &perCPU); err != nil {
+		if errors.Is(err, ebpf.ErrKeyNotExist) {
+			return Counters{}, nil
+		}
+		return Counters{}, fmt.Errorf("dataplane: routestats lookup: %w", err)
+	}
+	var total Counters
+	for _, value := range perCPU {
+		total.Packets += value.Packets
+		total.Bytes += value.Bytes
+	}
+	return total, nil
+}
+
+func (m *manager) Stats() Stats {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	stats := m.counts
+	stats.CountersLoaded = m.stats != nil
+	return stats
+}
+
 func (m *manager) Close() error {
 	m.mu.Lock()
 	defer m.mu.Unlock()
@@ -163,6 +221,11 @@ type portmapValue struct {
 	_        uint16
 }
 
+type routeStats struct {
+	Packets uint64
+	Bytes   uint64
+}
+
 func htons(value uint16) uint16 {
 	return value<<8 | value>>8
 }
@@ -15,4 +15,6 @@ type noopManager struct{}
 
 func (noopManager) ApplyBridge(context.Context, uint8, uint16, net.IP, uint16) error { return nil }
 func (noopManager) Remove(context.Context, uint8, uint16) error                      { return nil }
+func (noopManager) Counters(context.Context, uint8, uint16) (Counters, error)        { return Counters{}, nil }
+func (noopManager) Stats() Stats                                                     { return Stats{} }
 func (noopManager) Close() error                                                     { return nil }
@@ -17,9 +17,28 @@ type Options struct {
 type Interface interface {
 	ApplyBridge(ctx context.Context, proto uint8, hostPort uint16, destIP net.IP, destPort uint16) error
 	Remove(ctx context.Context, proto uint8, hostPort uint16) error
+	// Counters returns the traffic the dataplane redirected for a host port.
+	Counters(ctx context.Context, proto uint8, hostPort uint16) (Counters, error)
+	// Stats returns the map operations the manager performed.
+	Stats() Stats
 	Close() error
 }
 
+// Counters are the packets and bytes redirected for a host port since its
+// route was applied.
+type Counters struct {
+	Packets uint64 `json:"packets"`
+	Bytes   uint64 `json:"bytes"`
+}
+
+// Stats count the map operations of a dataplane manager.
+type Stats struct {
+	Applies        uint64 `json:"applies"`
+	Removes        uint64 `json:"removes"`
+	Failures       uint64 `json:"failures"`
+	CountersLoaded bool   `json:"counters_loaded"`
+}
+
 // ErrUnsupported indicates the dataplane is not available on the current platform.
 var ErrUnsupported = errorUnsupported{}
 
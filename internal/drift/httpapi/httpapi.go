@@ -28,15 +28,30 @@ func New(ctrl *controller.Controller) http.Handler {
 	r.Use(middleware.Recoverer)
 
 	r.Get("/healthz", h.handleHealth)
-	r.Group(func(r chi.Router) {
+	r.Get("/metrics", h.handleMetrics)
+	// Route IDs are "<protocol>/<port>", e.g. /v1/routes/tcp/8080/stats.
+	routesAPI := func(r chi.Router) {
 		r.Get("/routes", h.handleListRoutes)
 		r.Post("/routes", h.handleUpsertRoute)
 		r.Delete("/routes/{protocol}/{port}", h.handleDeleteRoute)
-	})
+		r.Get("/routes/{protocol}/{port}/stats", h.handleRouteStats)
+	}
+	r.Group(routesAPI)
+	r.Route("/v1", routesAPI)
 
 	return r
 }
 
+// Metrics returns a handler serving only the Prometheus metrics, for the
+// separate metrics listener.
+func Metrics(ctrl *controller.Controller) http.Handler {
+	h := &Handler{controller: ctrl}
+	r := chi.NewRouter()
+	r.Use(middleware.Recoverer)
+	r.Get("/metrics", h.handleMetrics)
+	return r
+}
+
 func (h *Handler) handleHealth(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusOK)
 	_, _ = w.Write([]byte("ok"))
@@ -79,18 +94,11 @@ func (h *Handler) handleUpsertRoute(w http.ResponseWriter, r *http.Request) {
 
 func (h *Handler) handleDeleteRoute(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
-	protocol := chi.URLParam(r, "protocol")
-	portStr := chi.URLParam(r, "port")
-	if protocol == "" || portStr == "" {
-		writeError(w, http.StatusBadRequest, "missing protocol or port")
-		return
-	}
-	port64, err := strconv.ParseUint(portStr, 10, 16)
-	if err != nil {
-		writeError(w, http.StatusBadRequest, "invalid port")
+	protocol, port, ok := routeParams(w, r)
+	if !ok {
 		return
 	}
-	if err := h.controller.Delete(ctx, uint16(port64), protocol); err != nil {
+	if err := h.controller.Delete(ctx, port, protocol); err != nil {
 		status := http.StatusBadRequest
 		if errors.Is(err, routes.ErrNotFound) {
 			status = http.StatusNotFound
@@ -101,6 +109,39 @@ func (h *Handler) handleDeleteRoute(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusNoContent)
 }
 
+// handleRouteStats reports the traffic a route's backend received.
+func (h *Handler) handleRouteStats(w http.ResponseWriter, r *http.Request) {
+	protocol, port, ok := routeParams(w, r)
+	if !ok {
+		return
+	}
+	stats, err := h.controller.RouteStats(r.Context(), port, protocol)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, routes.ErrNotFound) {
+			status = http.StatusNotFound
+		}
+		writeError(w, status, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, stats)
+}
+
+func routeParams(w http.ResponseWriter, r *http.Request) (string, uint16, bool) {
+	protocol := chi.URLParam(r, "protocol")
+	portStr := chi.URLParam(r, "port")
+	if protocol == "" || portStr == "" {
+		writeError(w, http.StatusBadRequest, "missing protocol or port")
+		return "", 0, false
+	}
+	port64, err := strconv.ParseUint(portStr, 10, 16)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid port")
+		return "", 0, false
+	}
+	return protocol, uint16(port64), true
+}
+
 func writeJSON(w http.ResponseWriter, status int, payload any) {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(status)
@@ -0,0 +1,144 @@
+package httpapi
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/volantvm/volant/internal/drift/controller"
+	"github.com/volantvm/volant/internal/drift/l7proxy"
+	"github.com/volantvm/volant/internal/drift/routes"
+)
+
+// handleMetrics renders route traffic, vsock proxy connections and
+// dataplane reloads in the Prometheus text format.
+func (h *Handler) handleMetrics(w http.ResponseWriter, r *http.Request) {
+	stats, err := h.controller.AppliedRouteStats(r.Context())
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	var b strings.Builder
+	header := func(name, kind, help string) {
+		fmt.Fprintf(&b, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, kind)
+	}
+	perRoute := func(name, kind, help string, value func(s controller.RouteStats) (float64, bool)) {
+		header(name, kind, help)
+		for _, s := range stats {
+			if v, ok := value(s); ok {
+				writeSample(&b, name, routeLabels(s), v)
+			}
+		}
+	}
+	perVsock := func(name, kind, help string, value func(s controller.RouteStats) float64) {
+		perRoute(name, kind, help, func(s controller.RouteStats) (float64, bool) {
+			if s.Vsock == nil {
+				return 0, false
+			}
+			return value(s), true
+		})
+	}
+
+	perRoute("drift_route_packets_total", "counter", "Packets the dataplane redirected for a bridge route.", func(s controller.RouteStats) (float64, bool) {
+		return float64(s.Packets), s.Backend.Type == routes.BackendBridge
+	})
+	perRoute("drift_route_bytes_total", "counter", "Bytes a route's backend received; both directions for vsock routes.", func(s controller.RouteStats) (float64, bool) {
+		return float64(s.Bytes), s.Backend.Type != routes.BackendL7
+	})
+	perVsock("drift_vsock_connections_total", "counter", "Connections accepted by a vsock proxy.", func(s controller.RouteStats) float64 {
+		return float64(s.Vsock.Connections)
+	})
+	perVsock("drift_vsock_active_connections", "gauge", "Connections a vsock proxy is forwarding.", func(s controller.RouteStats) float64 {
+		return float64(s.Vsock.ActiveConnections)
+	})
+	perVsock("drift_vsock_dial_failures_total", "counter", "Connections a vsock proxy could not forward to the guest.", func(s controller.RouteStats) float64 {
+		return float64(s.Vsock.DialFailures)
+	})
+	perL7 := func(name, help string, value func(s controller.RouteStats) uint64) {
+		perRoute(name, "counter", help, func(s controller.RouteStats) (float64, bool) {
+			if s.L7Proxy == nil {
+				return 0, false
+			}
+			return float64(value(s)), true
+		})
+	}
+	perL7("drift_l7_requests_total", "Requests or connections an l7 proxy routed to a backend.", func(s controller.RouteStats) uint64 { return s.L7Proxy.Requests })
+	perL7("drift_l7_unrouted_total", "Requests or connections matching no rule or no healthy backend.", func(s controller.RouteStats) uint64 { return s.L7Proxy.Unrouted })
+	perL7("drift_l7_backend_errors_total", "Requests or connections whose backend could not be reached.", func(s controller.RouteStats) uint64 { return s.L7Proxy.Errors })
+	perBackend := func(name, help string, value func(backend l7proxy.BackendStatus) float64) {
+		header(name, "gauge", help)
+		for _, s := range stats {
+			if s.L7Proxy == nil {
+				continue
+			}
+			for _, backend := range s.L7Proxy.Backends {
+				writeSample(&b, name, routeLabels(s)+fmt.Sprintf(`,target=%q`, backend.Address), value(backend))
+			}
+		}
+	}
+	perBackend("drift_l7_backend_up", "Whether an l7 backend passes its health checks.", func(backend l7proxy.BackendStatus) float64 {
+		return boolValue(backend.Healthy)
+	})
+	perBackend("drift_l7_backend_draining", "Whether an l7 backend is draining.", func(backend l7proxy.BackendStatus) float64 {
+		return boolValue(backend.Draining)
+	})
+	perBackend("drift_l7_backend_active_connections", "Requests or connections an l7 backend is serving.", func(backend l7proxy.BackendStatus) float64 {
+		return float64(backend.ActiveConnections)
+	})
+	header("drift_routes", "gauge", "Routes programmed into the runtime managers.")
+	writeSample(&b, "drift_routes", "", float64(len(stats)))
+
+	reloads := h.controller.ReloadStats()
+	header("drift_dataplane_reloads_total", "counter", "Times the routes were reprogrammed from the store.")
+	writeSample(&b, "drift_dataplane_reloads_total", "", float64(reloads.Reloads))
+	header("drift_dataplane_reload_failures_total", "counter", "Reloads that failed to program some route.")
+	writeSample(&b, "drift_dataplane_reload_failures_total", "", float64(reloads.Failures))
+	if !reloads.LastReload.IsZero() {
+		header("drift_dataplane_last_reload_timestamp_seconds", "gauge", "Time the last reload started.")
+		writeSample(&b, "drift_dataplane_last_reload_timestamp_seconds", "", float64(reloads.LastReload.UnixMilli())/1000)
+		header("drift_dataplane_last_reload_duration_seconds", "gauge", "Duration of the last reload.")
+		writeSample(&b, "drift_dataplane_last_reload_duration_seconds", "", reloads.LastDuration.Seconds())
+	}
+	if dp, ok := h.controller.DataplaneStats(); ok {
+		header("drift_dataplane_map_operations_total", "counter", "Route map updates performed by the bridge dataplane.")
+		writeSample(&b, "drift_dataplane_map_operations_total", `op="apply"`, float64(dp.Applies))
+		writeSample(&b, "drift_dataplane_map_operations_total", `op="remove"`, float64(dp.Removes))
+		header("drift_dataplane_map_failures_total", "counter", "Route map updates the bridge dataplane failed.")
+		writeSample(&b, "drift_dataplane_map_failures_total", "", float64(dp.Failures))
+	}
+
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write([]byte(b.String()))
+}
+
+func routeLabels(s controller.RouteStats) string {
+	var backend string
+	switch s.Backend.Type {
+	case routes.BackendVsock:
+		backend = strconv.FormatUint(uint64(s.Backend.CID), 10) + ":" + strconv.Itoa(int(s.Backend.Port))
+	case routes.BackendL7:
+		if s.L7 != nil {
+			backend = s.L7.Mode
+		}
+	default:
+		backend = s.Backend.IP + ":" + strconv.Itoa(int(s.Backend.Port))
+	}
+	return fmt.Sprintf(`protocol=%q,host_port="%d",backend_type=%q,backend=%q`, s.Protocol, s.HostPort, s.Backend.Type, backend)
+}
+
+func boolValue(v bool) float64 {
+	if v {
+		return 1
+	}
+	return 0
+}
+
+func writeSample(b *strings.Builder, name, labels string, value float64) {
+	if labels != "" {
+		name += "{" + labels + "}"
+	}
+	fmt.Fprintf(b, "%s %s\n", name, strconv.FormatFloat(value, 'g', -1, 64))
+}
@@ -0,0 +1,525 @@
+package l7proxy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/volantvm/volant/internal/drift/routes"
+)
+
+const (
+	defaultHealthInterval = 10 * time.Second
+	defaultHealthTimeout  = 2 * time.Second
+	dialTimeout           = 5 * time.Second
+	helloTimeout          = 10 * time.Second
+)
+
+type manager struct {
+	logger   *slog.Logger
+	bindAddr string
+	mu       sync.Mutex
+	proxies  map[uint16]*proxy
+	closed   bool
+}
+
+// proxy serves one host port. Its table of rules is swapped atomically on
+// upserts, so in-flight requests finish against the rules they started with.
+type proxy struct {
+	hostPort  uint16
+	mode      string
+	listener  net.Listener
+	server    *http.Server
+	transport *http.Transport
+	table     atomic.Pointer[table]
+	done      chan struct{}
+	logger    *slog.Logger
+
+	requests atomic.Uint64
+	unrouted atomic.Uint64
+	errors   atomic.Uint64
+}
+
+type table struct {
+	rules    []*rule
+	backends []*backend
+	stop     context.CancelFunc
+}
+
+type rule struct {
+	host       string
+	pathPrefix string
+
+	// mu guards the current weights of the smooth weighted round robin.
+	mu      sync.Mutex
+	targets []*target
+}
+
+type target struct {
+	backend *backend
+	weight  int
+	current int
+}
+
+// backend is shared by the rules naming its address and carried over to
+// the next table on upserts, so health, draining and in-flight counts
+// survive rule changes.
+type backend struct {
+	addr     string
+	healthy  atomic.Bool
+	draining atomic.Bool
+	requests atomic.Uint64
+	active   atomic.Int64
+}
+
+func newManager(opts Options) Manager {
+	logger := opts.Logger
+	if logger == nil {
+		logger = slog.Default()
+	}
+	bind := opts.BindAddress
+	if bind == "" {
+		bind = "0.0.0.0"
+	}
+	return &manager{
+		logger:   logger.With("component", "l7proxy"),
+		bindAddr: bind,
+		proxies:  make(map[uint16]*proxy),
+	}
+}
+
+func (m *manager) Upsert(_ context.Context, hostPort uint16, cfg routes.L7) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.closed {
+		return errors.New("l7 proxy: manager closed")
+	}
+
+	if existing, ok := m.proxies[hostPort]; ok {
+		if existing.mode == cfg.Mode {
+			existing.swap(newTable(cfg, existing.table.Load(), existing.logger))
+			existing.logger.Info("l7 proxy rules updated", "rules", len(cfg.Rules))
+			return nil
+		}
+		existing.stop()
+		delete(m.proxies, hostPort)
+	}
+
+	addr := net.JoinHostPort(m.bindAddr, fmt.Sprint(hostPort))
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return fmt.Errorf("l7 proxy: listen %s: %w", addr, err)
+	}
+	px := &proxy{
+		hostPort: hostPort,
+		mode:     cfg.Mode,
+		listener: listener,
+		done:     make(chan struct{}),
+		logger:   m.logger.With("host_port", hostPort, "mode", cfg.Mode),
+	}
+	px.swap(newTable(cfg, nil, px.logger))
+	px.start()
+	m.proxies[hostPort] = px
+	return nil
+}
+
+func (m *manager) Remove(_ context.Context, hostPort uint16) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	px, ok := m.proxies[hostPort]
+	if !ok {
+		return nil
+	}
+	px.stop()
+	delete(m.proxies, hostPort)
+	return nil
+}
+
+func (m *manager) Stats(hostPort uint16) (Stats, bool) {
+	m.mu.Lock()
+	px, ok := m.proxies[hostPort]
+	m.mu.Unlock()
+	if !ok {
+		return Stats{}, false
+	}
+	stats := Stats{
+		Requests: px.requests.Load(),
+		Unrouted: px.unrouted.Load(),
+		Errors:   px.errors.Load(),
+	}
+	for _, b := range px.table.Load().backends {
+		active := b.active.Load()
+		if active < 0 {
+			active = 0
+		}
+		stats.Backends = append(stats.Backends, BackendStatus{
+			Address:           b.addr,
+			Healthy:           b.healthy.Load(),
+			Draining:          b.draining.Load(),
+			Requests:          b.requests.Load(),
+			ActiveConnections: uint64(active),
+		})
+	}
+	return stats, true
+}
+
+func (m *manager) Close() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.closed {
+		return nil
+	}
+	m.closed = true
+	for hostPort, px := range m.proxies {
+		px.stop()
+		delete(m.proxies, hostPort)
+	}
+	return nil
+}
+
+// newTable builds the rules of cfg and starts health checking their
+// backends when cfg asks for it. Backends of previous are reused; new ones
+// start out healthy.
+func newTable(cfg routes.L7, previous *table, logger *slog.Logger) *table {
+	known := make(map[string]*backend)
+	if previous != nil {
+		for _, b := range previous.backends {
+			known[b.addr] = b
+		}
+	}
+
+	t := &table{}
+	byAddr := make(map[string]*backend)
+	draining := make(map[string]bool)
+	for _, spec := range cfg.Rules {
+		r := &rule{host: spec.Host, pathPrefix: spec.PathPrefix}
+		for _, ts := range spec.Backends {
+			addr := net.JoinHostPort(ts.IP, fmt.Sprint(ts.Port))
+			b, ok := byAddr[addr]
+			if !ok {
+				if b, ok = known[addr]; !ok {
+					b = &backend{addr: addr}
+					b.healthy.Store(true)
+				}
+				byAddr[addr] = b
+				t.backends = append(t.backends, b)
+			}
+			draining[addr] = draining[addr] || ts.Draining
+			weight := ts.Weight
+			if weight <= 0 {
+				weight = 1
+			}
+			r.targets = append(r.targets, &target{backend: b, weight: weight})
+		}
+		t.rules = append(t.rules, r)
+	}
+	for addr, b := range byAddr {
+		b.draining.Store(draining[addr])
+	}
+	sort.Slice(t.backends, func(i, j int) bool { return t.backends[i].addr < t.backends[j].addr })
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.stop = cancel
+	if cfg.HealthCheck != nil && len(t.backends) > 0 {
+		go t.check(ctx, cfg.Mode, *cfg.HealthCheck, logger)
+	}
+	return t
+}
+
+// match returns the rule for host and path: exact hosts win over wildcard
+// hosts, which win over rules for any host, and then the longest path
+// prefix wins.
+func (t *table) match(host, path string) *rule {
+	var (
+		best      *rule
+		bestScore int
+	)
+	for _, r := range t.rules {
+		score := hostScore(r.host, host)
+		if score == 0 || !strings.HasPrefix(path, r.pathPrefix) {
+			continue
+		}
+		score = score<<16 + len(r.pathPrefix)
+		if best == nil || score > bestScore {
+			best, bestScore = r, score
+		}
+	}
+	return best
+}
+
+func hostScore(pattern, host string) int {
+	switch {
+	case pattern == "":
+		return 1
+	case pattern == host:
+		return 3
+	case strings.HasPrefix(pattern, "*.") && strings.HasSuffix(host, pattern[1:]):
+		return 2
+	default:
+		return 0
+	}
+}
+
+// pick returns the next healthy backend of r that is not draining, by
+// smooth weighted round robin, or nil.
+func (r *rule) pick() *backend {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var (
+		best  *target
+		total int
+	)
+	for _, t := range r.targets {
+		if t.backend.draining.Load() || !t.backend.healthy.Load() {
+			continue
+		}
+		t.current += t.weight
+		total += t.weight
+		if best == nil || t.current > best.current {
+			best = t
+		}
+	}
+	if best == nil {
+		return nil
+	}
+	best.current -= total
+	return best.backend
+}
+
+func (t *table) check(ctx context.Context, mode string, hc routes.HealthCheck, logger *slog.Logger) {
+	interval := time.Duration(hc.IntervalSeconds) * time.Second
+	if interval <= 0 {
+		interval = defaultHealthInterval
+	}
+	timeout := time.Duration(hc.TimeoutSeconds) * time.Second
+	if timeout <= 0 {
+		timeout = defaultHealthTimeout
+	}
+	client := &http.Client{
+		Timeout: timeout,
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		for _, b := range t.backends {
+			var err error
+			if mode == routes.L7ModeHTTP && hc.Path != "" {
+				err = probeHTTP(ctx, client, b.addr, hc.Path)
+			} else {
+				err = probeTCP(ctx, b.addr, timeout)
+			}
+			healthy := err == nil
+			if b.healthy.Swap(healthy) != healthy {
+				if healthy {
+					logger.Info("l7 backend healthy", "backend", b.addr)
+				} else {
+					logger.Warn("l7 backend unhealthy", "backend", b.addr, "error", err)
+				}
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func probeHTTP(ctx context.Context, client *http.Client, addr, path string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "http://"+addr+path, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 4096))
+	resp.Body.Close()
+	if resp.StatusCode >= http.StatusInternalServerError {
+		return fmt.Errorf("health check returned %s", resp.Status)
+	}
+	return nil
+}
+
+func probeTCP(ctx context.Context, addr string, timeout time.Duration) error {
+	dialer := net.Dialer{Timeout: timeout}
+	conn, err := dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return err
+	}
+	return conn.Close()
+}
+
+func (p *proxy) swap(t *table) {
+	if old := p.table.Swap(t); old != nil {
+		old.stop()
+	}
+}
+
+func (p *proxy) start() {
+	p.logger.Info("l7 proxy started")
+	if p.mode != routes.L7ModeHTTP {
+		go p.serveStreams()
+		return
+	}
+	p.transport = http.DefaultTransport.(*http.Transport).Clone()
+	p.server = &http.Server{
+		Handler:           http.HandlerFunc(p.serveHTTP),
+		ReadHeaderTimeout: 30 * time.Second,
+	}
+	go func() {
+		defer close(p.done)
+		if err := p.server.Serve(p.listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			p.logger.Error("serve", "error", err)
+		}
+	}()
+}
+
+func (p *proxy) stop() {
+	if p.server != nil {
+		_ = p.server.Close()
+		p.transport.CloseIdleConnections()
+	} else {
+		_ = p.listener.Close()
+	}
+	<-p.done
+	p.table.Load().stop()
+	p.logger.Info("l7 proxy stopped")
+}
+
+// serveHTTP routes a request by its Host header and path.
+func (p *proxy) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	host := strings.ToLower(r.Host)
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	matched := p.table.Load().match(host, r.URL.Path)
+	if matched == nil {
+		p.unrouted.Add(1)
+		http.Error(w, "no route for host and path", http.StatusNotFound)
+		return
+	}
+	b := matched.pick()
+	if b == nil {
+		p.unrouted.Add(1)
+		http.Error(w, "no healthy backend", http.StatusServiceUnavailable)
+		return
+	}
+	p.requests.Add(1)
+	b.requests.Add(1)
+	b.active.Add(1)
+	defer b.active.Add(-1)
+
+	rp := &httputil.ReverseProxy{
+		Transport: p.transport,
+		Rewrite: func(pr *httputil.ProxyRequest) {
+			pr.SetURL(&url.URL{Scheme: "http", Host: b.addr})
+			pr.SetXForwarded()
+			pr.Out.Host = pr.In.Host
+		},
+		ErrorHandler: func(w http.ResponseWriter, r *http.Request, err error) {
+			p.errors.Add(1)
+			p.logger.Warn("proxy request", "backend", b.addr, "error", err)
+			w.WriteHeader(http.StatusBadGateway)
+		},
+	}
+	rp.ServeHTTP(w, r)
+}
+
+// serveStreams accepts the connections of tls and tcp mode proxies.
+func (p *proxy) serveStreams() {
+	defer close(p.done)
+	for {
+		conn, err := p.listener.Accept()
+		if err != nil {
+			if errors.Is(err, net.ErrClosed) {
+				return
+			}
+			var nErr net.Error
+			if errors.As(err, &nErr) && nErr.Timeout() {
+				continue
+			}
+			p.logger.Error("accept error", "error", err)
+			return
+		}
+		go p.handleStream(conn)
+	}
+}
+
+// handleStream passes a connection through to a backend untouched. In tls
+// mode the backend is chosen by the SNI of the ClientHello.
+func (p *proxy) handleStream(conn net.Conn) {
+	defer conn.Close()
+	var (
+		serverName string
+		hello      io.Reader = conn
+	)
+	if p.mode == routes.L7ModeTLS {
+		_ = conn.SetReadDeadline(time.Now().Add(helloTimeout))
+		var err error
+		serverName, hello, err = peekServerName(conn)
+		if err != nil {
+			p.unrouted.Add(1)
+			p.logger.Debug("read client hello", "remote", conn.RemoteAddr().String(), "error", err)
+			return
+		}
+		_ = conn.SetReadDeadline(time.Time{})
+	}
+
+	matched := p.table.Load().match(strings.ToLower(serverName), "")
+	var b *backend
+	if matched != nil {
+		b = matched.pick()
+	}
+	if b == nil {
+		p.unrouted.Add(1)
+		return
+	}
+	p.requests.Add(1)
+	b.requests.Add(1)
+
+	upstream, err := net.DialTimeout("tcp", b.addr, dialTimeout)
+	if err != nil {
+		p.errors.Add(1)
+		p.logger.Warn("dial backend", "backend", b.addr, "error", err)
+		return
+	}
+	defer upstream.Close()
+	b.active.Add(1)
+	defer b.active.Add(-1)
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		_, _ = io.Copy(upstream, hello)
+		closeWrite(upstream)
+	}()
+	go func() {
+		defer wg.Done()
+		_, _ = io.Copy(conn, upstream)
+		closeWrite(conn)
+	}()
+	wg.Wait()
+}
+
+func closeWrite(conn net.Conn) {
+	if tcp, ok := conn.(*net.TCPConn); ok {
+		_ = tcp.CloseWrite()
+	}
+}
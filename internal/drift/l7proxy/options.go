@@ -0,0 +1,54 @@
+package l7proxy
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/volantvm/volant/internal/drift/routes"
+)
+
+// Options configure the L7 proxy manager.
+type Options struct {
+	BindAddress string
+	Logger      *slog.Logger
+}
+
+// Manager manages host listeners that route HTTP requests by Host header
+// and path, TLS connections by SNI, or plain TCP connections to the
+// backends of a route's rules.
+type Manager interface {
+	// Upsert starts the proxy of a host port, or swaps the rules of a
+	// running one without dropping its listener.
+	Upsert(ctx context.Context, hostPort uint16, cfg routes.L7) error
+	Remove(ctx context.Context, hostPort uint16) error
+	// Stats returns the traffic of the proxy of a host port, and false when
+	// there is none.
+	Stats(hostPort uint16) (Stats, bool)
+	Close() error
+}
+
+// Stats count the requests (http mode) or connections (tls and tcp modes)
+// a proxy routed since its route was applied.
+type Stats struct {
+	Requests uint64 `json:"requests"`
+	// Unrouted matched no rule, or a rule without healthy backends.
+	Unrouted uint64 `json:"unrouted"`
+	// Errors failed to reach the chosen backend.
+	Errors   uint64          `json:"errors"`
+	Backends []BackendStatus `json:"backends"`
+}
+
+// BackendStatus is the health and traffic of one backend address. A
+// draining backend is drained once ActiveConnections reaches zero.
+type BackendStatus struct {
+	Address           string `json:"address"`
+	Healthy           bool   `json:"healthy"`
+	Draining          bool   `json:"draining"`
+	Requests          uint64 `json:"requests"`
+	ActiveConnections uint64 `json:"active_connections"`
+}
+
+// New constructs a manager.
+func New(opts Options) Manager {
+	return newManager(opts)
+}
@@ -0,0 +1,49 @@
+package l7proxy
+
+import (
+	"bytes"
+	"crypto/tls"
+	"errors"
+	"io"
+	"net"
+	"time"
+)
+
+// errHelloRead stops the handshake once the ClientHello has been parsed.
+var errHelloRead = errors.New("client hello read")
+
+// peekServerName reads the ClientHello of a TLS connection and returns its
+// server name along with a reader that replays the bytes consumed, so the
+// connection can be passed through unchanged. The name is empty when the
+// client sent no SNI.
+func peekServerName(conn net.Conn) (string, io.Reader, error) {
+	var (
+		consumed   bytes.Buffer
+		serverName string
+	)
+	err := tls.Server(helloConn{r: io.TeeReader(conn, &consumed)}, &tls.Config{
+		GetConfigForClient: func(hello *tls.ClientHelloInfo) (*tls.Config, error) {
+			serverName = hello.ServerName
+			return nil, errHelloRead
+		},
+	}).Handshake()
+	if !errors.Is(err, errHelloRead) {
+		return "", nil, err
+	}
+	return serverName, io.MultiReader(&consumed, conn), nil
+}
+
+// helloConn feeds the handshake reads from r and discards its writes, so
+// the alert crypto/tls sends on aborting never reaches the client.
+type helloConn struct {
+	r io.Reader
+}
+
+func (c helloConn) Read(p []byte) (int, error)     { return c.r.Read(p) }
+func (helloConn) Write(p []byte) (int, error)      { return len(p), nil }
+func (helloConn) Close() error                     { return nil }
+func (helloConn) LocalAddr() net.Addr              { return nil }
+func (helloConn) RemoteAddr() net.Addr             { return nil }
+func (helloConn) SetDeadline(time.Time) error      { return nil }
+func (helloConn) SetReadDeadline(time.Time) error  { return nil }
+func (helloConn) SetWriteDeadline(time.Time) error { return nil }
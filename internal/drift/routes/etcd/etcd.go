@@ -0,0 +1,489 @@
+// Package etcd stores drift routes in etcd, so driftd instances on several
+// hosts share one routing table. It talks to etcd's v3 JSON gateway over
+// HTTP rather than gRPC.
+package etcd
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/volantvm/volant/internal/drift/routes"
+)
+
+// DefaultPrefix is the key prefix routes are stored under.
+const DefaultPrefix = "/volant/drift/routes/"
+
+const (
+	requestTimeout = 10 * time.Second
+	maxBackoff     = 30 * time.Second
+)
+
+// Options configures a Store.
+type Options struct {
+	// Endpoints are etcd client URLs, tried in order.
+	Endpoints []string
+	// Prefix is the key prefix routes are stored under; DefaultPrefix when empty.
+	Prefix string
+	// CAFile, CertFile and KeyFile configure TLS towards https endpoints.
+	CAFile   string
+	CertFile string
+	KeyFile  string
+}
+
+// Store persists routes as one etcd key per route. Each write is a single
+// atomic put or delete; Watch follows the prefix from the revision last
+// seen so no change is missed across reconnects.
+type Store struct {
+	endpoints []string
+	prefix    string
+	client    *http.Client
+	stream    *http.Client
+
+	mu      sync.Mutex
+	written map[int64]struct{}
+}
+
+var (
+	_ routes.Store   = (*Store)(nil)
+	_ routes.Watcher = (*Store)(nil)
+)
+
+// NewStore constructs a Store for opts and checks that etcd is reachable.
+func NewStore(ctx context.Context, opts Options) (*Store, error) {
+	var endpoints []string
+	for _, endpoint := range opts.Endpoints {
+		if endpoint = strings.TrimRight(strings.TrimSpace(endpoint), "/"); endpoint != "" {
+			endpoints = append(endpoints, endpoint)
+		}
+	}
+	if len(endpoints) == 0 {
+		return nil, fmt.Errorf("routes: etcd endpoints required")
+	}
+	prefix := opts.Prefix
+	if prefix == "" {
+		prefix = DefaultPrefix
+	}
+	if !strings.HasSuffix(prefix, "/") {
+		prefix += "/"
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if opts.CAFile != "" || opts.CertFile != "" {
+		tlsConfig, err := loadTLS(opts)
+		if err != nil {
+			return nil, err
+		}
+		transport.TLSClientConfig = tlsConfig
+	}
+
+	store := &Store{
+		endpoints: endpoints,
+		prefix:    prefix,
+		client:    &http.Client{Transport: transport, Timeout: requestTimeout},
+		stream:    &http.Client{Transport: transport},
+		written:   make(map[int64]struct{}),
+	}
+	if _, _, err := store.list(ctx); err != nil {
+		return nil, err
+	}
+	return store, nil
+}
+
+func loadTLS(opts Options) (*tls.Config, error) {
+	cfg := &tls.Config{MinVersion: tls.VersionTLS12}
+	if opts.CAFile != "" {
+		pem, err := os.ReadFile(opts.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("routes: read etcd ca: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("routes: etcd ca %s holds no certificates", opts.CAFile)
+		}
+		cfg.RootCAs = pool
+	}
+	if opts.CertFile != "" || opts.KeyFile != "" {
+		cert, err := tls.LoadX509KeyPair(opts.CertFile, opts.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("routes: load etcd client certificate: %w", err)
+		}
+		cfg.Certificates = []tls.Certificate{cert}
+	}
+	return cfg, nil
+}
+
+// List returns all stored routes.
+func (s *Store) List(ctx context.Context) ([]routes.Route, error) {
+	items, _, err := s.list(ctx)
+	return items, err
+}
+
+// Get fetches a route by host port and protocol.
+func (s *Store) Get(ctx context.Context, hostPort uint16, protocol string) (*routes.Route, error) {
+	var resp rangeResponse
+	if err := s.call(ctx, "/v3/kv/range", rangeRequest{Key: encode(s.key(hostPort, protocol))}, &resp); err != nil {
+		return nil, err
+	}
+	if len(resp.KVs) == 0 {
+		return nil, fmt.Errorf("%w: %s", routes.ErrNotFound, routes.Key(hostPort, protocol))
+	}
+	route, err := decodeRoute(resp.KVs[0])
+	if err != nil {
+		return nil, err
+	}
+	return &route, nil
+}
+
+// Upsert stores or replaces a route.
+func (s *Store) Upsert(ctx context.Context, route routes.Route) error {
+	value, err := json.Marshal(route)
+	if err != nil {
+		return fmt.Errorf("routes: encode route: %w", err)
+	}
+	var resp putResponse
+	if err := s.call(ctx, "/v3/kv/put", putRequest{Key: encode(s.key(route.HostPort, route.Protocol)), Value: base64.StdEncoding.EncodeToString(value)}, &resp); err != nil {
+		return err
+	}
+	s.remember(int64(resp.Header.Revision))
+	return nil
+}
+
+// Delete removes a route by host port and protocol.
+func (s *Store) Delete(ctx context.Context, hostPort uint16, protocol string) error {
+	var resp deleteResponse
+	if err := s.call(ctx, "/v3/kv/deleterange", rangeRequest{Key: encode(s.key(hostPort, protocol))}, &resp); err != nil {
+		return err
+	}
+	if resp.Deleted == 0 {
+		return fmt.Errorf("%w: %s", routes.ErrNotFound, routes.Key(hostPort, protocol))
+	}
+	s.remember(int64(resp.Header.Revision))
+	return nil
+}
+
+// Watch follows changes under the prefix until ctx is done, calling
+// changed for writes made by other instances. Broken streams are resumed
+// from the last revision seen; when etcd compacted that revision away,
+// changed is called since events may have been missed.
+func (s *Store) Watch(ctx context.Context, changed func()) error {
+	_, revision, err := s.list(ctx)
+	if err != nil {
+		return err
+	}
+	backoff := time.Second
+	for {
+		next, err := s.watchFrom(ctx, revision+1, changed)
+		if next > revision {
+			revision = next
+			backoff = time.Second
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		var compacted compactedError
+		if errors.As(err, &compacted) {
+			changed()
+			if _, current, err := s.list(ctx); err == nil {
+				revision = current
+			} else {
+				revision = compacted.revision - 1
+			}
+			continue
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(backoff):
+		}
+		if backoff *= 2; backoff > maxBackoff {
+			backoff = maxBackoff
+		}
+	}
+}
+
+// compactedError reports that the watch start revision was compacted.
+type compactedError struct{ revision int64 }
+
+func (e compactedError) Error() string {
+	return fmt.Sprintf("routes: etcd revision compacted at %d", e.revision)
+}
+
+// watchFrom streams events from start and returns the last revision seen.
+func (s *Store) watchFrom(ctx context.Context, start int64, changed func()) (int64, error) {
+	begin, end := s.prefixRange()
+	body, err := json.Marshal(watchRequest{Create: &watchCreate{Key: begin, RangeEnd: end, StartRevision: start}})
+	if err != nil {
+		return 0, err
+	}
+
+	var lastErr error
+	for _, endpoint := range s.endpoints {
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint+"/v3/watch", bytes.NewReader(body))
+		if err != nil {
+			return 0, err
+		}
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := s.stream.Do(req)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		last, err := s.consume(resp, changed)
+		resp.Body.Close()
+		return last, err
+	}
+	return 0, fmt.Errorf("routes: etcd watch: %w", lastErr)
+}
+
+func (s *Store) consume(resp *http.Response, changed func()) (int64, error) {
+	if resp.StatusCode != http.StatusOK {
+		return 0, responseError(resp)
+	}
+	var last int64
+	decoder := json.NewDecoder(bufio.NewReader(resp.Body))
+	for {
+		var msg watchMessage
+		if err := decoder.Decode(&msg); err != nil {
+			if errors.Is(err, io.EOF) {
+				return last, io.ErrUnexpectedEOF
+			}
+			return last, err
+		}
+		if msg.Error != nil {
+			return last, fmt.Errorf("routes: etcd watch: %s", msg.Error.Message)
+		}
+		result := msg.Result
+		if result.CompactRevision > 0 {
+			return last, compactedError{revision: int64(result.CompactRevision)}
+		}
+		if result.Canceled {
+			return last, fmt.Errorf("routes: etcd watch canceled: %s", result.CancelReason)
+		}
+		foreign := false
+		for _, event := range result.Events {
+			revision := int64(event.KV.ModRevision)
+			if revision > last {
+				last = revision
+			}
+			if !s.forget(revision) {
+				foreign = true
+			}
+		}
+		if foreign {
+			changed()
+		}
+	}
+}
+
+func (s *Store) list(ctx context.Context) ([]routes.Route, int64, error) {
+	begin, end := s.prefixRange()
+	var resp rangeResponse
+	if err := s.call(ctx, "/v3/kv/range", rangeRequest{Key: begin, RangeEnd: end}, &resp); err != nil {
+		return nil, 0, err
+	}
+	result := make([]routes.Route, 0, len(resp.KVs))
+	for _, kv := range resp.KVs {
+		route, err := decodeRoute(kv)
+		if err != nil {
+			return nil, 0, err
+		}
+		result = append(result, route)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].HostPort != result[j].HostPort {
+			return result[i].HostPort < result[j].HostPort
+		}
+		return result[i].Protocol < result[j].Protocol
+	})
+	return result, int64(resp.Header.Revision), nil
+}
+
+// call posts req to path on the first endpoint that answers.
+func (s *Store) call(ctx context.Context, path string, req, out any) error {
+	body, err := json.Marshal(req)
+	if err != nil {
+		return err
+	}
+	var lastErr error
+	for _, endpoint := range s.endpoints {
+		httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint+path, bytes.NewReader(body))
+		if err != nil {
+			return err
+		}
+		httpReq.Header.Set("Content-Type", "application/json")
+		resp, err := s.client.Do(httpReq)
+		if err != nil {
+			lastErr = err
+			if ctx.Err() != nil {
+				break
+			}
+			continue
+		}
+		err = func() error {
+			defer resp.Body.Close()
+			if resp.StatusCode != http.StatusOK {
+				return responseError(resp)
+			}
+			if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+				return fmt.Errorf("routes: decode etcd response: %w", err)
+			}
+			return nil
+		}()
+		return err
+	}
+	return fmt.Errorf("routes: etcd %s: %w", path, lastErr)
+}
+
+func responseError(resp *http.Response) error {
+	data, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+	var status struct {
+		Message string `json:"message"`
+		Error   string `json:"error"`
+	}
+	if json.Unmarshal(data, &status) == nil {
+		if status.Message != "" {
+			return fmt.Errorf("routes: etcd: %s", status.Message)
+		}
+		if status.Error != "" {
+			return fmt.Errorf("routes: etcd: %s", status.Error)
+		}
+	}
+	return fmt.Errorf("routes: etcd: unexpected status %s", resp.Status)
+}
+
+func (s *Store) remember(revision int64) {
+	s.mu.Lock()
+	s.written[revision] = struct{}{}
+	s.mu.Unlock()
+}
+
+// forget reports whether revision was written by this store.
+func (s *Store) forget(revision int64) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.written[revision]; ok {
+		delete(s.written, revision)
+		return true
+	}
+	return false
+}
+
+func (s *Store) key(hostPort uint16, protocol string) string {
+	return s.prefix + routes.Key(hostPort, protocol)
+}
+
+// prefixRange returns the encoded key range covering the prefix.
+func (s *Store) prefixRange() (string, string) {
+	end := []byte(s.prefix)
+	end[len(end)-1]++
+	return encode(s.prefix), base64.StdEncoding.EncodeToString(end)
+}
+
+func encode(value string) string {
+	return base64.StdEncoding.EncodeToString([]byte(value))
+}
+
+func decodeRoute(kv keyValue) (routes.Route, error) {
+	var route routes.Route
+	value, err := base64.StdEncoding.DecodeString(kv.Value)
+	if err == nil {
+		err = json.Unmarshal(value, &route)
+	}
+	if err != nil {
+		key, _ := base64.StdEncoding.DecodeString(kv.Key)
+		return routes.Route{}, fmt.Errorf("routes: decode %s: %w", key, err)
+	}
+	return route, nil
+}
+
+// int64String decodes the int64 fields the gateway encodes as strings.
+type int64String int64
+
+func (v *int64String) UnmarshalJSON(data []byte) error {
+	unquoted := strings.Trim(string(data), `"`)
+	if unquoted == "" || unquoted == "null" {
+		*v = 0
+		return nil
+	}
+	n, err := strconv.ParseInt(unquoted, 10, 64)
+	if err != nil {
+		return err
+	}
+	*v = int64String(n)
+	return nil
+}
+
+type responseHeader struct {
+	Revision int64String `json:"revision"`
+}
+
+type keyValue struct {
+	Key         string      `json:"key"`
+	Value       string      `json:"value"`
+	ModRevision int64String `json:"mod_revision"`
+}
+
+type rangeRequest struct {
+	Key      string `json:"key"`
+	RangeEnd string `json:"range_end,omitempty"`
+}
+
+type rangeResponse struct {
+	Header responseHeader `json:"header"`
+	KVs    []keyValue     `json:"kvs"`
+}
+
+type putRequest struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+type putResponse struct {
+	Header responseHeader `json:"header"`
+}
+
+type deleteResponse struct {
+	Header  responseHeader `json:"header"`
+	Deleted int64String    `json:"deleted"`
+}
+
+type watchCreate struct {
+	Key           string `json:"key"`
+	RangeEnd      string `json:"range_end"`
+	StartRevision int64  `json:"start_revision,string"`
+}
+
+type watchRequest struct {
+	Create *watchCreate `json:"create_request"`
+}
+
+type watchMessage struct {
+	Result struct {
+		Header          responseHeader `json:"header"`
+		Canceled        bool           `json:"canceled"`
+		CancelReason    string         `json:"cancel_reason"`
+		CompactRevision int64String    `json:"compact_revision"`
+		Events          []struct {
+			Type string   `json:"type"`
+			KV   keyValue `json:"kv"`
+		} `json:"events"`
+	} `json:"result"`
+	Error *struct {
+		Message string `json:"message"`
+	} `json:"error"`
+}
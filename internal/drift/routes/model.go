@@ -6,13 +6,27 @@ type BackendType string
 const (
 	BackendBridge BackendType = "bridge"
 	BackendVsock  BackendType = "vsock"
+	// BackendL7 routes through driftd's userspace proxy, which picks a
+	// backend per HTTP request or TLS connection from the route's L7 rules.
+	BackendL7 BackendType = "l7"
+)
+
+// L7 proxy modes.
+const (
+	// L7ModeHTTP routes plain HTTP requests by Host header and path.
+	L7ModeHTTP = "http"
+	// L7ModeTLS routes TLS connections by SNI without terminating them.
+	L7ModeTLS = "tls"
+	// L7ModeTCP balances plain TCP connections over the backends of a
+	// single rule.
+	L7ModeTCP = "tcp"
 )
 
 // Backend describes the routing destination for a host port.
 type Backend struct {
 	Type BackendType `json:"type"`
 	IP   string      `json:"ip,omitempty"`
-	Port uint16      `json:"port"`
+	Port uint16      `json:"port,omitempty"`
 	CID  uint32      `json:"cid,omitempty"`
 }
 
@@ -21,4 +35,52 @@ type Route struct {
 	HostPort uint16  `json:"host_port"`
 	Protocol string  `json:"protocol"`
 	Backend  Backend `json:"backend"`
+	// Socket names the unix socket that vsock routes of the unix protocol
+	// listen on, in driftd's socket directory; their host port only
+	// identifies the route.
+	Socket string `json:"socket,omitempty"`
+	// L7 configures the proxy of routes whose backend type is l7.
+	L7 *L7 `json:"l7,omitempty"`
+}
+
+// L7 configures userspace routing of a host port.
+type L7 struct {
+	// Mode is http, tls or tcp.
+	Mode string `json:"mode"`
+	// Rules are matched by host, then by the longest path prefix.
+	Rules       []L7Rule     `json:"rules"`
+	HealthCheck *HealthCheck `json:"health_check,omitempty"`
+}
+
+// L7Rule sends the requests or connections for a host and path prefix to
+// a set of backends, balanced by weight over the healthy ones.
+type L7Rule struct {
+	// Host matches the Host header or SNI exactly, or by suffix when it
+	// starts with "*."; empty matches any host.
+	Host string `json:"host,omitempty"`
+	// PathPrefix narrows HTTP rules to request paths under it.
+	PathPrefix string     `json:"path_prefix,omitempty"`
+	Backends   []L7Target `json:"backends"`
+}
+
+// L7Target is a backend address of an L7 rule.
+type L7Target struct {
+	IP   string `json:"ip"`
+	Port uint16 `json:"port"`
+	// Weight is the share of new requests or connections the backend gets
+	// relative to the others of the rule; 0 counts as 1.
+	Weight int `json:"weight,omitempty"`
+	// Draining stops new requests and connections to the backend, in every
+	// rule, while those in flight finish.
+	Draining bool `json:"draining,omitempty"`
+}
+
+// HealthCheck probes the backends of L7 rules; unhealthy backends receive
+// no traffic until they pass again.
+type HealthCheck struct {
+	// Path is requested with GET in http mode, expecting a status below
+	// 500; without it, and in tls mode, backends must accept a connection.
+	Path            string `json:"path,omitempty"`
+	IntervalSeconds int    `json:"interval_seconds,omitempty"`
+	TimeoutSeconds  int    `json:"timeout_seconds,omitempty"`
 }
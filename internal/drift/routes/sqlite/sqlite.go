@@ -0,0 +1,282 @@
+// Package sqlite stores drift routes in a SQLite database, which driftd
+// instances on one host can share and which survives interrupted writes.
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+
+	"github.com/volantvm/volant/internal/drift/routes"
+)
+
+// pollInterval is how often Watch checks for writes by other instances.
+const pollInterval = time.Second
+
+const schema = `
+CREATE TABLE IF NOT EXISTS routes (
+	host_port INTEGER NOT NULL,
+	protocol TEXT NOT NULL,
+	backend TEXT NOT NULL,
+	l7 TEXT,
+	socket TEXT NOT NULL DEFAULT '',
+	PRIMARY KEY (host_port, protocol)
+);
+CREATE TABLE IF NOT EXISTS meta (
+	id INTEGER PRIMARY KEY CHECK (id = 1),
+	generation INTEGER NOT NULL
+);
+INSERT OR IGNORE INTO meta (id, generation) VALUES (1, 0);
+`
+
+// addedColumns are route columns newer than the first schema, added to
+// databases created before them.
+var addedColumns = []struct{ name, definition string }{
+	{name: "l7", definition: "l7 TEXT"},
+	{name: "socket", definition: "socket TEXT NOT NULL DEFAULT ''"},
+}
+
+// Store persists routes in a SQLite database. Every write bumps a
+// generation counter in the same transaction, which Watch polls to notice
+// writes by other instances.
+type Store struct {
+	db *sql.DB
+
+	mu      sync.Mutex
+	written map[int64]struct{}
+}
+
+var (
+	_ routes.Store   = (*Store)(nil)
+	_ routes.Watcher = (*Store)(nil)
+)
+
+// NewStore opens or creates the route database at path.
+func NewStore(path string) (*Store, error) {
+	if strings.TrimSpace(path) == "" {
+		return nil, fmt.Errorf("routes: database path required")
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, fmt.Errorf("routes: ensure directory: %w", err)
+	}
+	dsn := fmt.Sprintf("file:%s?_busy_timeout=5000&_journal_mode=WAL", path)
+	db, err := sql.Open("sqlite3", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("routes: open sqlite: %w", err)
+	}
+	if _, err := db.Exec(schema); err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("routes: init schema: %w", err)
+	}
+	if err := migrate(db); err != nil {
+		_ = db.Close()
+		return nil, err
+	}
+	return &Store{db: db, written: make(map[int64]struct{})}, nil
+}
+
+// Close releases the database.
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+// List returns all stored routes.
+func (s *Store) List(ctx context.Context) ([]routes.Route, error) {
+	rows, err := s.db.QueryContext(ctx, `SELECT host_port, protocol, backend, l7, socket FROM routes ORDER BY host_port, protocol`)
+	if err != nil {
+		return nil, fmt.Errorf("routes: list: %w", err)
+	}
+	defer rows.Close()
+
+	var result []routes.Route
+	for rows.Next() {
+		route, err := scanRoute(rows)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, route)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("routes: list: %w", err)
+	}
+	return result, nil
+}
+
+// Get fetches a route by host port and protocol.
+func (s *Store) Get(ctx context.Context, hostPort uint16, protocol string) (*routes.Route, error) {
+	row := s.db.QueryRowContext(ctx, `SELECT host_port, protocol, backend, l7, socket FROM routes WHERE host_port = ? AND protocol = ?`, hostPort, protocol)
+	route, err := scanRoute(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("%w: %s", routes.ErrNotFound, routes.Key(hostPort, protocol))
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &route, nil
+}
+
+// Upsert stores or replaces a route.
+func (s *Store) Upsert(ctx context.Context, route routes.Route) error {
+	backend, err := json.Marshal(route.Backend)
+	if err != nil {
+		return fmt.Errorf("routes: encode backend: %w", err)
+	}
+	var l7 sql.NullString
+	if route.L7 != nil {
+		data, err := json.Marshal(route.L7)
+		if err != nil {
+			return fmt.Errorf("routes: encode l7: %w", err)
+		}
+		l7 = sql.NullString{String: string(data), Valid: true}
+	}
+	return s.write(ctx, func(tx *sql.Tx) error {
+		_, err := tx.ExecContext(ctx, `INSERT INTO routes (host_port, protocol, backend, l7, socket) VALUES (?, ?, ?, ?, ?)
+ON CONFLICT (host_port, protocol) DO UPDATE SET backend = excluded.backend, l7 = excluded.l7, socket = excluded.socket`, route.HostPort, route.Protocol, string(backend), l7, route.Socket)
+		return err
+	})
+}
+
+// Delete removes a route by host port and protocol.
+func (s *Store) Delete(ctx context.Context, hostPort uint16, protocol string) error {
+	return s.write(ctx, func(tx *sql.Tx) error {
+		result, err := tx.ExecContext(ctx, `DELETE FROM routes WHERE host_port = ? AND protocol = ?`, hostPort, protocol)
+		if err != nil {
+			return err
+		}
+		if n, err := result.RowsAffected(); err == nil && n == 0 {
+			return fmt.Errorf("%w: %s", routes.ErrNotFound, routes.Key(hostPort, protocol))
+		}
+		return nil
+	})
+}
+
+// Watch polls the generation counter until ctx is done, calling changed
+// when another instance wrote to the database.
+func (s *Store) Watch(ctx context.Context, changed func()) error {
+	last, err := s.generation(ctx)
+	if err != nil {
+		return err
+	}
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+		current, err := s.generation(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			continue
+		}
+		if current == last {
+			continue
+		}
+		foreign := false
+		s.mu.Lock()
+		for gen := last + 1; gen <= current; gen++ {
+			if _, ok := s.written[gen]; ok {
+				delete(s.written, gen)
+				continue
+			}
+			foreign = true
+		}
+		s.mu.Unlock()
+		last = current
+		if foreign {
+			changed()
+		}
+	}
+}
+
+// write runs fn and bumps the generation in one transaction.
+func (s *Store) write(ctx context.Context, fn func(tx *sql.Tx) error) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("routes: begin: %w", err)
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	if err := fn(tx); err != nil {
+		if errors.Is(err, routes.ErrNotFound) {
+			return err
+		}
+		return fmt.Errorf("routes: write: %w", err)
+	}
+	var gen int64
+	if err := tx.QueryRowContext(ctx, `UPDATE meta SET generation = generation + 1 WHERE id = 1 RETURNING generation`).Scan(&gen); err != nil {
+		return fmt.Errorf("routes: bump generation: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("routes: commit: %w", err)
+	}
+	s.mu.Lock()
+	s.written[gen] = struct{}{}
+	s.mu.Unlock()
+	return nil
+}
+
+// migrate adds the columns a database created by an older driftd lacks.
+func migrate(db *sql.DB) error {
+	for _, column := range addedColumns {
+		var n int
+		if err := db.QueryRow(`SELECT COUNT(*) FROM pragma_table_info('routes') WHERE name = ?`, column.name).Scan(&n); err != nil {
+			return fmt.Errorf("routes: inspect schema: %w", err)
+		}
+		if n > 0 {
+			continue
+		}
+		if _, err := db.Exec(`ALTER TABLE routes ADD COLUMN ` + column.definition); err != nil {
+			return fmt.Errorf("routes: add column %s: %w", column.name, err)
+		}
+	}
+	return nil
+}
+
+func (s *Store) generation(ctx context.Context) (int64, error) {
+	var gen int64
+	if err := s.db.QueryRowContext(ctx, `SELECT generation FROM meta WHERE id = 1`).Scan(&gen); err != nil {
+		return 0, fmt.Errorf("routes: read generation: %w", err)
+	}
+	return gen, nil
+}
+
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+func scanRoute(row scanner) (routes.Route, error) {
+	var (
+		route   routes.Route
+		backend string
+		l7      sql.NullString
+	)
+	if err := row.Scan(&route.HostPort, &route.Protocol, &backend, &l7, &route.Socket); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return routes.Route{}, err
+		}
+		return routes.Route{}, fmt.Errorf("routes: scan: %w", err)
+	}
+	if err := json.Unmarshal([]byte(backend), &route.Backend); err != nil {
+		return routes.Route{}, fmt.Errorf("routes: decode backend %s: %w", routes.Key(route.HostPort, route.Protocol), err)
+	}
+	if l7.Valid {
+		route.L7 = &routes.L7{}
+		if err := json.Unmarshal([]byte(l7.String), route.L7); err != nil {
+			return routes.Route{}, fmt.Errorf("routes: decode l7 %s: %w", routes.Key(route.HostPort, route.Protocol), err)
+		}
+	}
+	return route, nil
+}
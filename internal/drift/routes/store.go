@@ -15,3 +15,15 @@ type Store interface {
 	Upsert(ctx context.Context, route Route) error
 	Delete(ctx context.Context, hostPort uint16, protocol string) error
 }
+
+// Watcher is implemented by stores several driftd instances can share.
+// Watch blocks until ctx is done, calling changed whenever another writer
+// modifies the stored routes.
+type Watcher interface {
+	Watch(ctx context.Context, changed func()) error
+}
+
+// Key identifies a route within a store.
+func Key(hostPort uint16, protocol string) string {
+	return storageKey(hostPort, protocol)
+}
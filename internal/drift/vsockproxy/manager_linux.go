@@ -9,28 +9,44 @@ import (
 	"io"
 	"log/slog"
 	"net"
+	"os"
+	"path/filepath"
 	"sync"
+	"sync/atomic"
 
 	"github.com/mdlayher/vsock"
 )
 
 type manager struct {
-	logger   *slog.Logger
-	bindAddr string
-	mu       sync.Mutex
-	proxies  map[string]*proxy
-	closed   bool
+	logger    *slog.Logger
+	bindAddr  string
+	socketDir string
+	mu        sync.Mutex
+	proxies   map[string]*proxy
+	closed    bool
 }
 
 type proxy struct {
 	proto     string
 	hostPort  uint16
+	socket    string
 	cid       uint32
 	guestPort uint16
 	listener  net.Listener
 	cancel    context.CancelFunc
 	done      chan struct{}
 	logger    *slog.Logger
+	counters  *counters
+}
+
+// counters outlive the proxy of a host port across upserts, which replace
+// its listener.
+type counters struct {
+	connections  atomic.Uint64
+	active       atomic.Int64
+	dialFailures atomic.Uint64
+	bytesIn      atomic.Uint64
+	bytesOut     atomic.Uint64
 }
 
 func newManager(opts Options) (Manager, error) {
@@ -44,9 +60,10 @@ func newManager(opts Options) (Manager, error) {
 	}
 
 	return &manager{
-		logger:   logger.With("component", "vsockproxy"),
-		bindAddr: bind,
-		proxies:  make(map[string]*proxy),
+		logger:    logger.With("component", "vsockproxy"),
+		bindAddr:  bind,
+		socketDir: opts.SocketDir,
+		proxies:   make(map[string]*proxy),
 	}, nil
 }
 
@@ -54,8 +71,9 @@ func (m *manager) key(proto string, port uint16) string {
 	return fmt.Sprintf("%s/%d", proto, port)
 }
 
-func (m *manager) Upsert(ctx context.Context, proto string, hostPort uint16, cid uint32, guestPort uint16) error {
-	if proto != "tcp" {
+func (m *manager) Upsert(ctx context.Context, mapping Mapping) error {
+	proto := mapping.Protocol
+	if proto != "tcp" && proto != "unix" {
 		return fmt.Errorf("vsock proxy: protocol %q not supported", proto)
 	}
 
@@ -65,33 +83,96 @@ func (m *manager) Upsert(ctx context.Context, proto string, hostPort uint16, cid
 		return errors.New("vsock proxy: manager closed")
 	}
 
-	key := m.key(proto, hostPort)
+	key := m.key(proto, mapping.HostPort)
+	var socket string
+	if proto == "unix" {
+		var err error
+		if socket, err = m.socketPath(key, mapping.Socket); err != nil {
+			return err
+		}
+	}
+	stats := &counters{}
 	if existing, ok := m.proxies[key]; ok {
 		existing.stop()
+		stats = existing.counters
 	}
 
-	addr := fmt.Sprintf("%s:%d", m.bindAddr, hostPort)
-	listener, err := net.Listen("tcp", addr)
+	listener, err := m.listen(mapping.HostPort, socket)
 	if err != nil {
-		return fmt.Errorf("vsock proxy: listen %s: %w", addr, err)
+		delete(m.proxies, key)
+		return err
 	}
 
 	childCtx, cancel := context.WithCancel(context.Background())
+	logger := m.logger.With("host_port", mapping.HostPort, "cid", mapping.CID, "guest_port", mapping.GuestPort)
+	if socket != "" {
+		logger = logger.With("socket", socket)
+	}
 	px := &proxy{
 		proto:     proto,
-		hostPort:  hostPort,
-		cid:       cid,
-		guestPort: guestPort,
+		hostPort:  mapping.HostPort,
+		socket:    socket,
+		cid:       mapping.CID,
+		guestPort: mapping.GuestPort,
 		listener:  listener,
 		cancel:    cancel,
 		done:      make(chan struct{}),
-		logger:    m.logger.With("host_port", hostPort, "cid", cid, "guest_port", guestPort),
+		logger:    logger,
+		counters:  stats,
 	}
 	px.start(childCtx)
 	m.proxies[key] = px
 	return nil
 }
 
+// socketPath resolves the socket of a unix mapping inside the socket
+// directory, refusing names that would escape it or that another mapping
+// listens on.
+func (m *manager) socketPath(key, name string) (string, error) {
+	if m.socketDir == "" {
+		return "", errors.New("vsock proxy: no socket directory configured")
+	}
+	if name == "" || name != filepath.Base(name) || name == "." || name == ".." {
+		return "", fmt.Errorf("vsock proxy: invalid socket name %q", name)
+	}
+	path := filepath.Join(m.socketDir, name)
+	for other, proxy := range m.proxies {
+		if other != key && proxy.socket == path {
+			return "", fmt.Errorf("vsock proxy: socket %s already used by %s", name, other)
+		}
+	}
+	return path, nil
+}
+
+// listen opens the TCP listener of a host port, or the unix socket at
+// socket when it is set, replacing a socket file a previous driftd left.
+func (m *manager) listen(hostPort uint16, socket string) (net.Listener, error) {
+	if socket == "" {
+		addr := fmt.Sprintf("%s:%d", m.bindAddr, hostPort)
+		listener, err := net.Listen("tcp", addr)
+		if err != nil {
+			return nil, fmt.Errorf("vsock proxy: listen %s: %w", addr, err)
+		}
+		return listener, nil
+	}
+	if err := os.MkdirAll(m.socketDir, 0o755); err != nil {
+		return nil, fmt.Errorf("vsock proxy: ensure socket directory: %w", err)
+	}
+	if info, err := os.Lstat(socket); err == nil {
+		if info.Mode()&os.ModeSocket == 0 {
+			return nil, fmt.Errorf("vsock proxy: %s exists and is not a socket", socket)
+		}
+		if err := os.Remove(socket); err != nil {
+			return nil, fmt.Errorf("vsock proxy: remove stale socket: %w", err)
+		}
+	}
+	listener, err := net.Listen("unix", socket)
+	if err != nil {
+		return nil, fmt.Errorf("vsock proxy: listen %s: %w", socket, err)
+	}
+	return listener, nil
+}
+
 func (m *manager) Remove(ctx context.Context, proto string, hostPort uint16) error {
 	m.mu.Lock()
 	defer m.mu.Unlock()
@@ -105,6 +186,27 @@ func (m *manager) Remove(ctx context.Context, proto string, hostPort uint16) err
 	return nil
 }
 
+func (m *manager) Stats(proto string, hostPort uint16) (Stats, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	proxy, ok := m.proxies[m.key(proto, hostPort)]
+	if !ok {
+		return Stats{}, false
+	}
+	c := proxy.counters
+	active := c.active.Load()
+	if active < 0 {
+		active = 0
+	}
+	return Stats{
+		Connections:       c.connections.Load(),
+		ActiveConnections: uint64(active),
+		DialFailures:      c.dialFailures.Load(),
+		BytesIn:           c.bytesIn.Load(),
+		BytesOut:          c.bytesOut.Load(),
+	}, true
+}
+
 func (m *manager) Close() error {
 	m.mu.Lock()
 	defer m.mu.Unlock()
@@ -146,26 +248,43 @@ func (p *proxy) start(ctx context.Context) {
 
 func (p *proxy) handleConnection(ctx context.Context, conn net.Conn) {
 	defer conn.Close()
+	p.counters.connections.Add(1)
 	vsockConn, err := dialVsock(ctx, p.cid, uint32(p.guestPort))
 	if err != nil {
+		p.counters.dialFailures.Add(1)
 		p.logger.Error("vsock dial failed", "error", err)
 		return
 	}
 	defer vsockConn.Close()
+	p.counters.active.Add(1)
+	defer p.counters.active.Add(-1)
 
 	var wg sync.WaitGroup
-	copyStream := func(dst io.Writer, src io.Reader) {
+	copyStream := func(dst io.Writer, src io.Reader, total *atomic.Uint64) {
 		defer wg.Done()
-		if _, err := io.Copy(dst, src); err != nil {
+		if _, err := io.Copy(countingWriter{w: dst, total: total}, src); err != nil {
 			p.logger.Warn("copy stream", "error", err)
 		}
 	}
 	wg.Add(2)
-	go copyStream(vsockConn, conn)
-	go copyStream(conn, vsockConn)
+	go copyStream(vsockConn, conn, &p.counters.bytesIn)
+	go copyStream(conn, vsockConn, &p.counters.bytesOut)
 	wg.Wait()
 }
 
+// countingWriter adds the bytes written to total as they flow, so long-lived
+// connections show up in the counters before they close.
+type countingWriter struct {
+	w     io.Writer
+	total *atomic.Uint64
+}
+
+func (c countingWriter) Write(b []byte) (int, error) {
+	n, err := c.w.Write(b)
+	c.total.Add(uint64(n))
+	return n, err
+}
+
 func (p *proxy) stop() {
 	p.cancel()
 	_ = p.listener.Close()
@@ -10,6 +10,7 @@ func newManager(Options) (Manager, error) {
 	return nil, ErrUnsupported
 }
 
-func (noopManager) Upsert(context.Context, string, uint16, uint32, uint16) error { return nil }
-func (noopManager) Remove(context.Context, string, uint16) error                 { return nil }
-func (noopManager) Close() error                                                 { return nil }
+func (noopManager) Upsert(context.Context, Mapping) error        { return nil }
+func (noopManager) Remove(context.Context, string, uint16) error { return nil }
+func (noopManager) Stats(string, uint16) (Stats, bool)           { return Stats{}, false }
+func (noopManager) Close() error                                 { return nil }
@@ -8,16 +8,44 @@ import (
 // Options configure the vsock proxy manager.
 type Options struct {
 	BindAddress string
-	Logger      *slog.Logger
+	// SocketDir holds the unix sockets of unix mappings.
+	SocketDir string
+	Logger    *slog.Logger
 }
 
-// Manager manages host TCP listeners that forward to vsock endpoints.
+// Mapping forwards a host listener to a guest vsock port. The listener is a
+// TCP port on the bind address for the tcp protocol, and the unix socket
+// Socket within the socket directory for the unix protocol, where HostPort
+// only identifies the mapping.
+type Mapping struct {
+	Protocol  string
+	HostPort  uint16
+	Socket    string
+	CID       uint32
+	GuestPort uint16
+}
+
+// Manager manages host TCP and unix socket listeners that forward to vsock
+// endpoints.
 type Manager interface {
-	Upsert(ctx context.Context, proto string, hostPort uint16, cid uint32, guestPort uint16) error
+	Upsert(ctx context.Context, mapping Mapping) error
 	Remove(ctx context.Context, proto string, hostPort uint16) error
+	// Stats returns the traffic of the proxy of a host port, and false when
+	// there is none.
+	Stats(proto string, hostPort uint16) (Stats, bool)
 	Close() error
 }
 
+// Stats count the connections a proxy forwarded since its route was
+// applied. BytesIn flows from clients to the guest, BytesOut back.
+type Stats struct {
+	Connections       uint64 `json:"connections"`
+	ActiveConnections uint64 `json:"active_connections"`
+	DialFailures      uint64 `json:"dial_failures"`
+	BytesIn           uint64 `json:"bytes_in"`
+	BytesOut          uint64 `json:"bytes_out"`
+}
+
 // ErrUnsupported indicates vsock forwarding isn't available on this platform.
 var ErrUnsupported = errorUnsupported{}
 
@@ -0,0 +1,73 @@
+// Copyright (c) 2025 HYPR. PTE. LTD.
+//
+// Business Source License 1.1
+// See LICENSE file in the project root for details.
+
+package pluginspec
+
+import (
+	"encoding/hex"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// AgentArchitectures lists the guest architectures volantd serves agent
+// binaries for, named as GOARCH.
+var AgentArchitectures = []string{"amd64", "arm64"}
+
+var agentVersionPattern = regexp.MustCompile(`^[0-9A-Za-z][0-9A-Za-z._+-]*$`)
+
+// Agent pins the guest agent a plugin's VMs run. Guests booting an older
+// agent fetch this version from volantd's agent artifacts, verify it and
+// hand over to it, so agent upgrades need no rootfs rebuild.
+type Agent struct {
+	Version string `json:"version"`
+	// Checksums pins the SHA-256 of the binary per architecture, as hex
+	// with an optional "sha256:" prefix. Without a pin the guest only checks
+	// the download against the checksum volantd serves.
+	Checksums map[string]string `json:"checksums,omitempty"`
+}
+
+// ValidAgentVersion reports whether version can name an agent release.
+func ValidAgentVersion(version string) bool {
+	return agentVersionPattern.MatchString(version) && !strings.Contains(version, "..")
+}
+
+// IsAgentArchitecture reports whether arch is one of AgentArchitectures.
+func IsAgentArchitecture(arch string) bool {
+	for _, known := range AgentArchitectures {
+		if arch == known {
+			return true
+		}
+	}
+	return false
+}
+
+// Normalize trims the version and checksums.
+func (a *Agent) Normalize() {
+	if a == nil {
+		return
+	}
+	a.Version = strings.TrimSpace(a.Version)
+	for arch, sum := range a.Checksums {
+		a.Checksums[arch] = strings.ToLower(strings.TrimPrefix(strings.TrimSpace(sum), "sha256:"))
+	}
+}
+
+// Validate checks the version and that checksums are SHA-256 digests of
+// known architectures.
+func (a Agent) Validate() error {
+	if !ValidAgentVersion(a.Version) {
+		return fmt.Errorf("agent: invalid version %q", a.Version)
+	}
+	for arch, sum := range a.Checksums {
+		if !IsAgentArchitecture(arch) {
+			return fmt.Errorf("agent: unknown architecture %q (known: %s)", arch, strings.Join(AgentArchitectures, ", "))
+		}
+		if decoded, err := hex.DecodeString(strings.TrimPrefix(sum, "sha256:")); err != nil || len(decoded) != 32 {
+			return fmt.Errorf("agent: checksum for %s must be a hex SHA-256", arch)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,51 @@
+// Copyright (c) 2025 HYPR. PTE. LTD.
+//
+// Business Source License 1.1
+// See LICENSE file in the project root for details.
+
+package pluginspec
+
+import "fmt"
+
+// Boot modes select how the hypervisor starts the guest.
+const (
+	// BootModeKernel boots the daemon's or the plugin's kernel directly and
+	// runs the volant agent from the root filesystem or initramfs. It is the
+	// default.
+	BootModeKernel = "kernel"
+	// BootModeFirmware boots the root disk through firmware, so bootable
+	// cloud images run unmodified with their own kernel and init. The guest
+	// is configured by cloud-init rather than the agent.
+	BootModeFirmware = "firmware"
+)
+
+// FirmwareBoot reports whether the manifest boots its root disk through
+// firmware.
+func (m *Manifest) FirmwareBoot() bool {
+	return m != nil && m.BootMode == BootModeFirmware
+}
+
+// validateBootMode checks that the boot media fit the boot mode. Firmware
+// boot needs a writable root disk and cannot use a kernel or initramfs.
+func (m Manifest) validateBootMode() error {
+	switch m.BootMode {
+	case "", BootModeKernel:
+		return nil
+	case BootModeFirmware:
+	default:
+		return fmt.Errorf("plugin manifest: boot_mode %q not supported (use %s or %s)", m.BootMode, BootModeKernel, BootModeFirmware)
+	}
+	if m.RootFS.URL == "" {
+		return fmt.Errorf("plugin manifest: firmware boot requires rootfs.url")
+	}
+	if m.RootFS.ReadOnly {
+		return fmt.Errorf("plugin manifest: firmware boot cannot use a read-only rootfs")
+	}
+	if m.Initramfs.URL != "" {
+		return fmt.Errorf("plugin manifest: firmware boot cannot use an initramfs")
+	}
+	if m.Kernel != nil {
+		return fmt.Errorf("plugin manifest: firmware boot cannot use a kernel; the disk boots its own")
+	}
+	return nil
+}
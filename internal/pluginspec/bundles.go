@@ -0,0 +1,120 @@
+// Copyright (c) 2025 HYPR. PTE. LTD.
+//
+// Business Source License 1.1
+// See LICENSE file in the project root for details.
+
+package pluginspec
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// BundleDir is where the agent writes each bundle's data keys, one file per
+// key, under BundleDir/<bundle>/<key>.
+const BundleDir = "/run/volant/bundles"
+
+// DefaultBundleFileMode applies to bundle files without an explicit mode.
+const DefaultBundleFileMode = "0600"
+
+var (
+	bundleNamePattern = regexp.MustCompile(`^[a-z0-9]([a-z0-9-]*[a-z0-9])?$`)
+	bundleKeyPattern  = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+)
+
+// Bundle is a named set of key/value pairs and files delivered to the guest.
+// Data keys are exported to the workload environment and written under
+// BundleDir; files are written to their absolute guest paths.
+type Bundle struct {
+	Name    string            `json:"name"`
+	Version int               `json:"version,omitempty"`
+	Data    map[string]string `json:"data,omitempty"`
+	Files   []BundleFile      `json:"files,omitempty"`
+}
+
+// BundleFile is a file written into the guest.
+type BundleFile struct {
+	Path string `json:"path"`
+	// Content is the file body; base64 when Encoding is "base64".
+	Content  string `json:"content"`
+	Encoding string `json:"encoding,omitempty"`
+	// Mode is an octal permission string such as "0644".
+	Mode string `json:"mode,omitempty"`
+}
+
+// Normalize trims names and paths and fills default modes.
+func (b *Bundle) Normalize() {
+	b.Name = strings.TrimSpace(b.Name)
+	for i := range b.Files {
+		file := &b.Files[i]
+		file.Path = strings.TrimSpace(file.Path)
+		file.Encoding = strings.ToLower(strings.TrimSpace(file.Encoding))
+		file.Mode = strings.TrimSpace(file.Mode)
+		if file.Mode == "" {
+			file.Mode = DefaultBundleFileMode
+		}
+	}
+}
+
+// Validate checks the bundle name, data keys and file entries.
+func (b Bundle) Validate() error {
+	if !bundleNamePattern.MatchString(b.Name) || len(b.Name) > 63 {
+		return errors.New("bundle name must be 1-63 lowercase letters, digits or '-'")
+	}
+	for key := range b.Data {
+		if !bundleKeyPattern.MatchString(key) {
+			return fmt.Errorf("bundle key %q must be a valid environment variable name", key)
+		}
+	}
+	seen := make(map[string]bool, len(b.Files))
+	for _, file := range b.Files {
+		if !path.IsAbs(file.Path) || path.Clean(file.Path) != file.Path || file.Path == "/" {
+			return fmt.Errorf("bundle file path %q must be absolute and clean", file.Path)
+		}
+		if seen[file.Path] {
+			return fmt.Errorf("bundle file path %q listed twice", file.Path)
+		}
+		seen[file.Path] = true
+		if _, err := file.Bytes(); err != nil {
+			return err
+		}
+		if _, err := file.FileMode(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Bytes returns the decoded file body.
+func (f BundleFile) Bytes() ([]byte, error) {
+	switch f.Encoding {
+	case "":
+		return []byte(f.Content), nil
+	case "base64":
+		data, err := base64.StdEncoding.DecodeString(f.Content)
+		if err != nil {
+			return nil, fmt.Errorf("bundle file %s: decode base64: %w", f.Path, err)
+		}
+		return data, nil
+	default:
+		return nil, fmt.Errorf("bundle file %s: unknown encoding %q", f.Path, f.Encoding)
+	}
+}
+
+// FileMode parses Mode, defaulting to DefaultBundleFileMode.
+func (f BundleFile) FileMode() (uint32, error) {
+	raw := f.Mode
+	if raw == "" {
+		raw = DefaultBundleFileMode
+	}
+	mode, err := strconv.ParseUint(raw, 8, 32)
+	if err != nil || mode > 0o777 {
+		return 0, fmt.Errorf("bundle file %s: mode %q must be octal permissions", f.Path, f.Mode)
+	}
+	return uint32(mode), nil
+}
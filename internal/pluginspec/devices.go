@@ -0,0 +1,172 @@
+// Copyright (c) 2025 HYPR. PTE. LTD.
+//
+// Business Source License 1.1
+// See LICENSE file in the project root for details.
+
+package pluginspec
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Device classes a request may name instead of a PCI class code prefix.
+const (
+	DeviceClassGPU         = "gpu"
+	DeviceClassNetwork     = "network"
+	DeviceClassStorage     = "storage"
+	DeviceClassAccelerator = "accelerator"
+)
+
+// deviceClassCodes maps named classes to their PCI base class.
+var deviceClassCodes = map[string]string{
+	DeviceClassGPU:         "03",
+	DeviceClassNetwork:     "02",
+	DeviceClassStorage:     "01",
+	DeviceClassAccelerator: "12",
+}
+
+// DeviceRequest asks for Count host PCI devices matching every set field.
+type DeviceRequest struct {
+	// Vendor and Device are 4-digit hex PCI IDs, e.g. "10de".
+	Vendor string `json:"vendor,omitempty"`
+	Device string `json:"device,omitempty"`
+	// Class is gpu, network, storage, accelerator or a hex PCI class code
+	// prefix such as "0302".
+	Class string `json:"class,omitempty"`
+	// Count defaults to 1.
+	Count int `json:"count,omitempty"`
+}
+
+// Wanted returns how many devices the request asks for.
+func (r DeviceRequest) Wanted() int {
+	if r.Count < 1 {
+		return 1
+	}
+	return r.Count
+}
+
+// UnmarshalJSON also accepts a bare list of requests, so manifests can write
+// "devices": [{"vendor": "10de", "class": "gpu", "count": 1}].
+func (d *DeviceConfig) UnmarshalJSON(data []byte) error {
+	if trimmed := strings.TrimSpace(string(data)); strings.HasPrefix(trimmed, "[") {
+		var requests []DeviceRequest
+		if err := json.Unmarshal(data, &requests); err != nil {
+			return err
+		}
+		*d = DeviceConfig{Requests: requests}
+		return nil
+	}
+	type plain DeviceConfig
+	var cfg plain
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return err
+	}
+	*d = DeviceConfig(cfg)
+	return nil
+}
+
+// Normalize trims and lowercases the requests and defaults Count to 1.
+func (d *DeviceConfig) Normalize() {
+	if d == nil {
+		return
+	}
+	for i := range d.Requests {
+		req := &d.Requests[i]
+		req.Vendor = normalizeHexID(req.Vendor)
+		req.Device = normalizeHexID(req.Device)
+		req.Class = normalizeHexID(req.Class)
+		if req.Count == 0 {
+			req.Count = 1
+		}
+	}
+}
+
+// Validate checks that every request names a known class and well-formed IDs.
+func (d *DeviceConfig) Validate() error {
+	if d == nil {
+		return nil
+	}
+	for i, req := range d.Requests {
+		if req.Vendor == "" && req.Device == "" && req.Class == "" {
+			return fmt.Errorf("devices: request %d must set vendor, device or class", i)
+		}
+		if req.Count < 0 {
+			return fmt.Errorf("devices: request %d count must not be negative", i)
+		}
+		if req.Vendor != "" && !isHex(req.Vendor, 4) {
+			return fmt.Errorf("devices: request %d vendor %q must be a 4-digit hex id", i, req.Vendor)
+		}
+		if req.Device != "" && !isHex(req.Device, 4) {
+			return fmt.Errorf("devices: request %d device %q must be a 4-digit hex id", i, req.Device)
+		}
+		if req.Class != "" && req.classPrefix() == "" {
+			return fmt.Errorf("devices: request %d unknown class %q (use gpu, network, storage, accelerator or a hex class code)", i, req.Class)
+		}
+	}
+	return nil
+}
+
+// Matches reports whether a device with the given hex vendor, device and
+// class code (as read from sysfs, e.g. "030000") satisfies the request.
+func (r DeviceRequest) Matches(vendor, device, class string) bool {
+	if want := normalizeHexID(r.Vendor); want != "" && want != normalizeHexID(vendor) {
+		return false
+	}
+	if want := normalizeHexID(r.Device); want != "" && want != normalizeHexID(device) {
+		return false
+	}
+	if r.Class != "" {
+		prefix := r.classPrefix()
+		if prefix == "" || !strings.HasPrefix(normalizeHexID(class), prefix) {
+			return false
+		}
+	}
+	return true
+}
+
+// String formats the request for logs and errors, e.g. "1x 10de gpu".
+func (r DeviceRequest) String() string {
+	parts := []string{fmt.Sprintf("%dx", r.Wanted())}
+	if r.Vendor != "" {
+		id := r.Vendor
+		if r.Device != "" {
+			id += ":" + r.Device
+		}
+		parts = append(parts, id)
+	} else if r.Device != "" {
+		parts = append(parts, "*:"+r.Device)
+	}
+	if r.Class != "" {
+		parts = append(parts, r.Class)
+	}
+	return strings.Join(parts, " ")
+}
+
+func (r DeviceRequest) classPrefix() string {
+	class := normalizeHexID(r.Class)
+	if code, ok := deviceClassCodes[class]; ok {
+		return code
+	}
+	if len(class)%2 == 0 && len(class) <= 6 && isHex(class, len(class)) {
+		return class
+	}
+	return ""
+}
+
+func normalizeHexID(value string) string {
+	return strings.TrimPrefix(strings.ToLower(strings.TrimSpace(value)), "0x")
+}
+
+func isHex(value string, length int) bool {
+	if length == 0 || len(value) != length {
+		return false
+	}
+	for _, c := range value {
+		if (c < '0' || c > '9') && (c < 'a' || c > 'f') {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,168 @@
+// Copyright (c) 2025 HYPR. PTE. LTD.
+//
+// Business Source License 1.1
+// See LICENSE file in the project root for details.
+
+package pluginspec
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	// DefaultExecTimeoutMs bounds commands that do not ask for a timeout.
+	DefaultExecTimeoutMs = 60_000
+	// MaxExecOutputBytes caps the stdout and stderr kept by a buffered exec;
+	// streaming sessions are not capped.
+	MaxExecOutputBytes = 1 << 20
+)
+
+// Request env names that change how the dynamic loader, libc or an
+// interpreter starts the command are refused: they would load code of the
+// caller's choosing into an allowed binary.
+var (
+	deniedExecEnv = map[string]bool{
+		"GCONV_PATH":        true,
+		"HOSTALIASES":       true,
+		"LOCALDOMAIN":       true,
+		"RES_OPTIONS":       true,
+		"BASH_ENV":          true,
+		"ENV":               true,
+		"SHELLOPTS":         true,
+		"PS4":               true,
+		"NODE_OPTIONS":      true,
+		"NODE_PATH":         true,
+		"PERL5OPT":          true,
+		"PERL5LIB":          true,
+		"PERLLIB":           true,
+		"RUBYOPT":           true,
+		"RUBYLIB":           true,
+		"JAVA_TOOL_OPTIONS": true,
+		"_JAVA_OPTIONS":     true,
+		"JDK_JAVA_OPTIONS":  true,
+	}
+	deniedExecEnvPrefixes = []string{"LD_", "DYLD_", "MALLOC_", "PYTHON", "BASH_FUNC_"}
+)
+
+// Exec streams: the first byte of each binary message of a streaming exec
+// session names the stream its payload belongs to. A stdin message without
+// payload closes stdin.
+const (
+	ExecStreamStdin  byte = 0
+	ExecStreamStdout byte = 1
+	ExecStreamStderr byte = 2
+)
+
+// Exec is the policy for running commands in the guest through the exec API.
+// Without one, exec is refused.
+type Exec struct {
+	// Allow lists the binaries callers may run: absolute paths, names looked
+	// up in the guest PATH, or path patterns such as "/usr/bin/*". "*" allows
+	// any binary.
+	Allow []string `json:"allow"`
+	// MaxTimeoutMs caps the timeout callers may ask for; 0 leaves it uncapped.
+	MaxTimeoutMs int64 `json:"max_timeout_ms,omitempty"`
+}
+
+// ExecRequest runs Command in the guest. The first element is the binary,
+// checked against the manifest's exec allowlist.
+type ExecRequest struct {
+	Command []string          `json:"command"`
+	Env     map[string]string `json:"env,omitempty"`
+	// WorkDir defaults to the workload's working directory.
+	WorkDir string `json:"workdir,omitempty"`
+	// Stdin is written to the command before its stdin is closed. Streaming
+	// sessions write it first and keep stdin open.
+	Stdin string `json:"stdin,omitempty"`
+	// TimeoutMs kills the command once it has run this long; 0 applies
+	// DefaultExecTimeoutMs.
+	TimeoutMs int64 `json:"timeout_ms,omitempty"`
+}
+
+// ExecResult reports how a command ended. Streaming sessions send it as their
+// last, text, message, without output.
+type ExecResult struct {
+	// ExitCode is -1 when the command was killed or never started.
+	ExitCode  int    `json:"exit_code"`
+	Stdout    string `json:"stdout,omitempty"`
+	Stderr    string `json:"stderr,omitempty"`
+	Truncated bool   `json:"truncated,omitempty"`
+	TimedOut  bool   `json:"timed_out,omitempty"`
+	// Error and Code explain a command that could not be run or was killed.
+	Error      string `json:"error,omitempty"`
+	Code       string `json:"code,omitempty"`
+	DurationMs int64  `json:"duration_ms"`
+}
+
+// Timeout returns the request timeout in milliseconds, applying the default.
+func (r ExecRequest) Timeout() int64 {
+	if r.TimeoutMs > 0 {
+		return r.TimeoutMs
+	}
+	return DefaultExecTimeoutMs
+}
+
+// Validate checks the request on its own; the allowlist is applied in the
+// guest, where binaries are resolved.
+func (r ExecRequest) Validate() error {
+	if len(r.Command) == 0 || strings.TrimSpace(r.Command[0]) == "" {
+		return fmt.Errorf("exec: command required")
+	}
+	if r.TimeoutMs < 0 {
+		return fmt.Errorf("exec: timeout_ms must be >= 0")
+	}
+	for key := range r.Env {
+		if key == "" || strings.ContainsAny(key, "=\x00") {
+			return fmt.Errorf("exec: invalid env name %q", key)
+		}
+		if execEnvDenied(key) {
+			return fmt.Errorf("exec: env %s is not allowed", key)
+		}
+	}
+	return nil
+}
+
+// execEnvDenied reports whether an exec request may not set the env name.
+func execEnvDenied(key string) bool {
+	if deniedExecEnv[key] {
+		return true
+	}
+	for _, prefix := range deniedExecEnvPrefixes {
+		if strings.HasPrefix(key, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// Normalize trims allowlist entries and drops empty ones.
+func (e *Exec) Normalize() {
+	if e == nil {
+		return
+	}
+	allow := make([]string, 0, len(e.Allow))
+	for _, entry := range e.Allow {
+		if value := strings.TrimSpace(entry); value != "" {
+			allow = append(allow, value)
+		}
+	}
+	e.Allow = allow
+}
+
+// Validate rejects allowlist entries that are neither "*", a bare name nor
+// an absolute path or pattern.
+func (e Exec) Validate() error {
+	if len(e.Allow) == 0 {
+		return fmt.Errorf("exec: allow must list at least one binary")
+	}
+	for _, entry := range e.Allow {
+		if entry != "*" && strings.Contains(entry, "/") && !strings.HasPrefix(entry, "/") {
+			return fmt.Errorf("exec: allow entry %q must be a name or an absolute path", entry)
+		}
+	}
+	if e.MaxTimeoutMs < 0 {
+		return fmt.Errorf("exec: max_timeout_ms must be >= 0")
+	}
+	return nil
+}
@@ -0,0 +1,135 @@
+// Copyright (c) 2025 HYPR. PTE. LTD.
+//
+// Business Source License 1.1
+// See LICENSE file in the project root for details.
+
+package pluginspec
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DefaultHookTimeoutMs bounds a hook that does not set timeout_ms.
+const DefaultHookTimeoutMs = 10000
+
+// Hooks lists work the guest agent runs at lifecycle transitions.
+type Hooks struct {
+	// PreStop runs in order before the hypervisor is terminated, e.g. to
+	// flush session data or deregister from an external service.
+	PreStop []Hook `json:"pre_stop,omitempty"`
+	// PostUpgrade runs in order the first time a VM becomes ready after a
+	// plugin upgrade rolled it onto a new version, e.g. to migrate data or
+	// config left by the previous version. Command hooks see the versions in
+	// VOLANT_UPGRADE_FROM and VOLANT_UPGRADE_TO.
+	PostUpgrade []Hook `json:"post_upgrade,omitempty"`
+}
+
+// Hook is either a command run inside the guest or a request sent to the
+// workload's base_url. Exactly one of Command or Path must be set.
+type Hook struct {
+	Name      string   `json:"name"`
+	Command   []string `json:"command,omitempty"`
+	Method    string   `json:"method,omitempty"`
+	Path      string   `json:"path,omitempty"`
+	TimeoutMs int64    `json:"timeout_ms,omitempty"`
+}
+
+// Hook result statuses.
+const (
+	HookStatusOK      = "ok"
+	HookStatusFailed  = "failed"
+	HookStatusTimeout = "timeout"
+)
+
+// HookResult reports the outcome of one hook.
+type HookResult struct {
+	Name       string `json:"name"`
+	Status     string `json:"status"`
+	ExitCode   *int   `json:"exit_code,omitempty"`
+	HTTPStatus int    `json:"http_status,omitempty"`
+	Output     string `json:"output,omitempty"`
+	Error      string `json:"error,omitempty"`
+	DurationMs int64  `json:"duration_ms"`
+}
+
+// Timeout returns the hook timeout in milliseconds, applying the default.
+func (h Hook) Timeout() int64 {
+	if h.TimeoutMs > 0 {
+		return h.TimeoutMs
+	}
+	return DefaultHookTimeoutMs
+}
+
+// Normalize trims whitespace and defaults the request method.
+func (h *Hook) Normalize() {
+	h.Name = strings.TrimSpace(h.Name)
+	h.Method = strings.ToUpper(strings.TrimSpace(h.Method))
+	h.Path = strings.TrimSpace(h.Path)
+	if h.Path != "" && h.Method == "" {
+		h.Method = "POST"
+	}
+	if len(h.Command) > 0 {
+		trimmed := make([]string, 0, len(h.Command))
+		for _, arg := range h.Command {
+			if value := strings.TrimSpace(arg); value != "" {
+				trimmed = append(trimmed, value)
+			}
+		}
+		h.Command = trimmed
+	}
+}
+
+// Validate ensures the hook has a name and exactly one target.
+func (h Hook) Validate() error {
+	if h.Name == "" {
+		return fmt.Errorf("hook name required")
+	}
+	hasCommand := len(h.Command) > 0
+	hasPath := h.Path != ""
+	if hasCommand == hasPath {
+		return fmt.Errorf("hook %s: exactly one of command or path must be set", h.Name)
+	}
+	if hasPath && !strings.HasPrefix(h.Path, "/") {
+		return fmt.Errorf("hook %s: path must start with /", h.Name)
+	}
+	if h.TimeoutMs < 0 {
+		return fmt.Errorf("hook %s: timeout_ms must be >= 0", h.Name)
+	}
+	return nil
+}
+
+// Normalize normalizes every hook.
+func (h *Hooks) Normalize() {
+	if h == nil {
+		return
+	}
+	for i := range h.PreStop {
+		h.PreStop[i].Normalize()
+	}
+	for i := range h.PostUpgrade {
+		h.PostUpgrade[i].Normalize()
+	}
+}
+
+// Validate checks every hook and rejects duplicate names within a stage.
+func (h Hooks) Validate() error {
+	if err := validateHookStage("pre_stop", h.PreStop); err != nil {
+		return err
+	}
+	return validateHookStage("post_upgrade", h.PostUpgrade)
+}
+
+func validateHookStage(stage string, hooks []Hook) error {
+	seen := make(map[string]struct{}, len(hooks))
+	for _, hook := range hooks {
+		if err := hook.Validate(); err != nil {
+			return fmt.Errorf("%s %w", stage, err)
+		}
+		if _, dup := seen[hook.Name]; dup {
+			return fmt.Errorf("%s hook %s declared twice", stage, hook.Name)
+		}
+		seen[hook.Name] = struct{}{}
+	}
+	return nil
+}
@@ -0,0 +1,66 @@
+// Copyright (c) 2025 HYPR. PTE. LTD.
+//
+// Business Source License 1.1
+// See LICENSE file in the project root for details.
+
+package pluginspec
+
+import "strings"
+
+// Host kernel features a manifest may require in host_features.
+const (
+	HostFeatureVhostVsock = "vhost-vsock"
+	HostFeatureTun        = "tun"
+	HostFeatureIOUring    = "io_uring"
+	HostFeatureVirtiofs   = "virtiofs"
+	HostFeatureHugepages  = "hugepages"
+)
+
+// KnownHostFeatures lists every feature volantd probes at startup.
+var KnownHostFeatures = []string{
+	HostFeatureVhostVsock,
+	HostFeatureTun,
+	HostFeatureIOUring,
+	HostFeatureVirtiofs,
+	HostFeatureHugepages,
+}
+
+// IsKnownHostFeature reports whether name is a probed host feature.
+func IsKnownHostFeature(name string) bool {
+	for _, known := range KnownHostFeatures {
+		if name == known {
+			return true
+		}
+	}
+	return false
+}
+
+func normalizeHostFeatures(features []string) []string {
+	if len(features) == 0 {
+		return nil
+	}
+	seen := make(map[string]bool, len(features))
+	out := make([]string, 0, len(features))
+	for _, feature := range features {
+		feature = strings.ToLower(strings.TrimSpace(feature))
+		if feature == "" || seen[feature] {
+			continue
+		}
+		seen[feature] = true
+		out = append(out, feature)
+	}
+	return out
+}
+
+// RequiredHostFeatures returns the host features m needs: those it declares
+// plus any implied by its configuration, such as virtiofs for shares.
+func (m *Manifest) RequiredHostFeatures() []string {
+	if m == nil {
+		return nil
+	}
+	required := append([]string(nil), m.HostFeatures...)
+	if len(m.Shares) > 0 {
+		required = append(required, HostFeatureVirtiofs)
+	}
+	return normalizeHostFeatures(required)
+}
@@ -0,0 +1,55 @@
+// Copyright (c) 2025 HYPR. PTE. LTD.
+//
+// Business Source License 1.1
+// See LICENSE file in the project root for details.
+
+package pluginspec
+
+import "fmt"
+
+// Constraints reported when a request exceeds a plugin's limits.
+const (
+	LimitMaxCPUCores = "max_cpu_cores"
+	LimitMaxMemoryMB = "max_memory_mb"
+	LimitMaxVMs      = "max_vms"
+)
+
+// Limits caps what the VMs of a plugin may use, so a misbehaving client
+// cannot exhaust the host. Zero fields are unlimited.
+type Limits struct {
+	// MaxCPUCores and MaxMemoryMB cap the resources of each VM.
+	MaxCPUCores int `json:"max_cpu_cores,omitempty"`
+	MaxMemoryMB int `json:"max_memory_mb,omitempty"`
+	// MaxVMs caps the VMs of the plugin on the host, counting standalone VMs
+	// and the desired replicas of its deployments.
+	MaxVMs int `json:"max_vms,omitempty"`
+}
+
+// Validate rejects negative limits and default resources above the caps.
+func (l *Limits) Validate(defaults ResourceSpec) error {
+	if l == nil {
+		return nil
+	}
+	if l.MaxCPUCores < 0 || l.MaxMemoryMB < 0 || l.MaxVMs < 0 {
+		return fmt.Errorf("limits must be >= 0")
+	}
+	if constraint, limit, requested := l.Exceeded(defaults.CPUCores, defaults.MemoryMB); constraint != "" {
+		return fmt.Errorf("default resources exceed limits: %s is %d, resources ask for %d", constraint, limit, requested)
+	}
+	return nil
+}
+
+// Exceeded returns the first per-VM cap that cpuCores or memoryMB exceeds,
+// with the cap and the requested value, or "" when both fit.
+func (l *Limits) Exceeded(cpuCores, memoryMB int) (constraint string, limit, requested int) {
+	if l == nil {
+		return "", 0, 0
+	}
+	if l.MaxCPUCores > 0 && cpuCores > l.MaxCPUCores {
+		return LimitMaxCPUCores, l.MaxCPUCores, cpuCores
+	}
+	if l.MaxMemoryMB > 0 && memoryMB > l.MaxMemoryMB {
+		return LimitMaxMemoryMB, l.MaxMemoryMB, memoryMB
+	}
+	return "", 0, 0
+}
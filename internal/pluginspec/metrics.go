@@ -0,0 +1,105 @@
+// Copyright (c) 2025 HYPR. PTE. LTD.
+//
+// Business Source License 1.1
+// See LICENSE file in the project root for details.
+
+package pluginspec
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// DefaultMetricsTimeoutMs bounds the request for plugin stats.
+const DefaultMetricsTimeoutMs = 2000
+
+// Metrics points the agent at a workload endpoint reporting plugin-specific
+// stats, collected alongside the guest's own metrics.
+type Metrics struct {
+	// Endpoint is a path on the workload's base_url answering GET with a
+	// JSON object; its numeric fields become stats, anything else is ignored.
+	Endpoint  string `json:"endpoint"`
+	TimeoutMs int64  `json:"timeout_ms,omitempty"`
+}
+
+// Normalize trims the endpoint.
+func (m *Metrics) Normalize() {
+	if m == nil {
+		return
+	}
+	m.Endpoint = strings.TrimSpace(m.Endpoint)
+}
+
+// Validate requires an endpoint path.
+func (m Metrics) Validate() error {
+	if !strings.HasPrefix(m.Endpoint, "/") {
+		return fmt.Errorf("metrics: endpoint must be a path starting with /")
+	}
+	if m.TimeoutMs < 0 {
+		return fmt.Errorf("metrics: timeout_ms must be >= 0")
+	}
+	return nil
+}
+
+// Timeout returns the stats request timeout, applying the default.
+func (m Metrics) Timeout() time.Duration {
+	if m.TimeoutMs > 0 {
+		return time.Duration(m.TimeoutMs) * time.Millisecond
+	}
+	return DefaultMetricsTimeoutMs * time.Millisecond
+}
+
+// GuestMetrics is a sample of resource usage as seen inside the guest.
+type GuestMetrics struct {
+	Timestamp     time.Time      `json:"timestamp"`
+	UptimeSeconds float64        `json:"uptime_seconds"`
+	CPU           GuestCPU       `json:"cpu"`
+	Memory        GuestMemory    `json:"memory"`
+	Disks         []GuestDisk    `json:"disks"`
+	Processes     GuestProcesses `json:"processes"`
+	// Load holds the 1, 5 and 15 minute load averages.
+	Load [3]float64 `json:"load"`
+	// Plugin holds the stats reported by the manifest's metrics endpoint;
+	// PluginError says why they are missing when the endpoint failed.
+	Plugin      map[string]float64 `json:"plugin,omitempty"`
+	PluginError string             `json:"plugin_error,omitempty"`
+}
+
+// GuestCPU reports CPU time spent since boot, summed over all CPUs, and the
+// share of CPU capacity used since the previous sample.
+type GuestCPU struct {
+	Count         int     `json:"count"`
+	Percent       float64 `json:"percent"`
+	UserSeconds   float64 `json:"user_seconds"`
+	SystemSeconds float64 `json:"system_seconds"`
+	IdleSeconds   float64 `json:"idle_seconds"`
+	IOWaitSeconds float64 `json:"iowait_seconds"`
+	StealSeconds  float64 `json:"steal_seconds"`
+}
+
+// GuestMemory reports memory as the guest kernel sees it.
+type GuestMemory struct {
+	TotalBytes     uint64 `json:"total_bytes"`
+	AvailableBytes uint64 `json:"available_bytes"`
+	UsedBytes      uint64 `json:"used_bytes"`
+	CachedBytes    uint64 `json:"cached_bytes"`
+	SwapTotalBytes uint64 `json:"swap_total_bytes"`
+	SwapFreeBytes  uint64 `json:"swap_free_bytes"`
+}
+
+// GuestDisk reports usage of a mounted block device or shared folder.
+type GuestDisk struct {
+	Device     string `json:"device"`
+	MountPoint string `json:"mount_point"`
+	FSType     string `json:"fs_type"`
+	TotalBytes uint64 `json:"total_bytes"`
+	FreeBytes  uint64 `json:"free_bytes"`
+}
+
+// GuestProcesses counts processes in the guest; Running counts those on a
+// CPU or waiting for one.
+type GuestProcesses struct {
+	Total   int `json:"total"`
+	Running int `json:"running"`
+}
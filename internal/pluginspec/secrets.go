@@ -0,0 +1,146 @@
+// Copyright (c) 2025 HYPR. PTE. LTD.
+//
+// Business Source License 1.1
+// See LICENSE file in the project root for details.
+
+package pluginspec
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+const (
+	// SecretDir is where the agent writes secrets mounted without a path, as
+	// SecretDir/<name>.
+	SecretDir = "/run/volant/secrets"
+	// DefaultSecretFileMode applies to secret files without an explicit mode.
+	DefaultSecretFileMode = "0400"
+	// MaxSecretMounts bounds the secrets a VM may mount.
+	MaxSecretMounts = 32
+	// ReservedSecretPrefix starts the names of secrets volantd generates for
+	// itself, such as "ssh.<vm>" holding a VM's SSH private key. They cannot
+	// be mounted, read from templates or changed through the API.
+	ReservedSecretPrefix = "ssh."
+)
+
+var (
+	secretNamePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]{0,127}$`)
+	secretEnvPattern  = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+)
+
+// ValidSecretName reports whether name can name a stored secret.
+func ValidSecretName(name string) bool {
+	return secretNamePattern.MatchString(name)
+}
+
+// ReservedSecretName reports whether name is reserved for a secret volantd
+// generates.
+func ReservedSecretName(name string) bool {
+	return strings.HasPrefix(strings.TrimSpace(name), ReservedSecretPrefix)
+}
+
+// SecretMount asks the agent to place a stored secret in the guest: as a
+// file at Path (SecretDir/<name> by default) and, when Env is set, in the
+// workload environment.
+type SecretMount struct {
+	Name string `json:"name"`
+	Path string `json:"path,omitempty"`
+	// Mode is an octal permission string such as "0400".
+	Mode string `json:"mode,omitempty"`
+	Env  string `json:"env,omitempty"`
+}
+
+// MountedSecret is a secret mount with its value, as served to the agent.
+type MountedSecret struct {
+	SecretMount
+	Value string `json:"value"`
+}
+
+// Normalize trims the fields and cleans the path.
+func (s *SecretMount) Normalize() {
+	s.Name = strings.TrimSpace(s.Name)
+	s.Env = strings.TrimSpace(s.Env)
+	s.Mode = strings.TrimSpace(s.Mode)
+	if s.Path = strings.TrimSpace(s.Path); s.Path != "" {
+		s.Path = path.Clean(s.Path)
+	}
+}
+
+// Target returns the guest path of the secret file.
+func (s SecretMount) Target() string {
+	if s.Path != "" {
+		return s.Path
+	}
+	return SecretDir + "/" + s.Name
+}
+
+// FileMode parses Mode, defaulting to DefaultSecretFileMode.
+func (s SecretMount) FileMode() (uint32, error) {
+	raw := s.Mode
+	if raw == "" {
+		raw = DefaultSecretFileMode
+	}
+	mode, err := strconv.ParseUint(raw, 8, 32)
+	if err != nil || mode > 0o777 {
+		return 0, fmt.Errorf("secret %s: mode %q must be octal permissions", s.Name, s.Mode)
+	}
+	return uint32(mode), nil
+}
+
+// Validate checks the name, path, mode and environment variable of a mount.
+func (s SecretMount) Validate() error {
+	if !ValidSecretName(s.Name) {
+		return fmt.Errorf("secret %q: name must be 1-128 letters, digits, '.', '_' or '-', starting with a letter or digit", s.Name)
+	}
+	if ReservedSecretName(s.Name) {
+		return fmt.Errorf("secret %s: names starting with %q are reserved for keys volantd generates", s.Name, ReservedSecretPrefix)
+	}
+	if s.Path != "" && (!path.IsAbs(s.Path) || s.Path == "/") {
+		return fmt.Errorf("secret %s: path %q must be an absolute file path", s.Name, s.Path)
+	}
+	if _, err := s.FileMode(); err != nil {
+		return err
+	}
+	if s.Env != "" && !secretEnvPattern.MatchString(s.Env) {
+		return fmt.Errorf("secret %s: env %q must be a valid environment variable name", s.Name, s.Env)
+	}
+	return nil
+}
+
+// ValidateSecretMounts validates each mount and checks that targets and
+// environment variables are unique.
+func ValidateSecretMounts(mounts []SecretMount) error {
+	if len(mounts) > MaxSecretMounts {
+		return fmt.Errorf("at most %d secrets can be mounted", MaxSecretMounts)
+	}
+	targets := make(map[string]string, len(mounts))
+	envs := make(map[string]string, len(mounts))
+	for _, mount := range mounts {
+		if err := mount.Validate(); err != nil {
+			return err
+		}
+		if other, ok := targets[mount.Target()]; ok {
+			return fmt.Errorf("secret %s: path %s already used by secret %s", mount.Name, mount.Target(), other)
+		}
+		targets[mount.Target()] = mount.Name
+		if mount.Env == "" {
+			continue
+		}
+		if other, ok := envs[mount.Env]; ok {
+			return fmt.Errorf("secret %s: env %s already set by secret %s", mount.Name, mount.Env, other)
+		}
+		envs[mount.Env] = mount.Name
+	}
+	return nil
+}
+
+// NormalizeSecretMounts normalizes every mount in place.
+func NormalizeSecretMounts(mounts []SecretMount) {
+	for i := range mounts {
+		mounts[i].Normalize()
+	}
+}
@@ -0,0 +1,147 @@
+// Copyright (c) 2025 HYPR. PTE. LTD.
+//
+// Business Source License 1.1
+// See LICENSE file in the project root for details.
+
+package pluginspec
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	// MaxShares bounds the virtio-fs shares a VM may declare.
+	MaxShares = 8
+	// MaxShareTagLength is the virtio-fs limit on mount tags.
+	MaxShareTagLength = 36
+	// ShareKeyPrefix, suffixed with the share index (volant.share1,
+	// volant.share2, ...), carries "tag,mount_path[,ro]" so the agent can
+	// mount each share.
+	ShareKeyPrefix = "volant.share"
+	// DefaultShareMountRoot is where shares without a mount_path are mounted
+	// in the guest, under their tag.
+	DefaultShareMountRoot = "/mnt"
+)
+
+// Share exposes a host directory to the guest over virtio-fs, so large
+// datasets need not be baked into the root filesystem image.
+type Share struct {
+	// Tag names the share to the guest.
+	Tag string `json:"tag"`
+	// HostPath is the absolute host directory to share.
+	HostPath string `json:"host_path"`
+	// MountPath is where the agent mounts the share; defaults to /mnt/<tag>.
+	MountPath string `json:"mount_path,omitempty"`
+	ReadOnly  bool   `json:"readonly,omitempty"`
+}
+
+// Normalize trims whitespace and cleans the paths.
+func (s *Share) Normalize() {
+	if s == nil {
+		return
+	}
+	s.Tag = strings.TrimSpace(s.Tag)
+	if s.HostPath = strings.TrimSpace(s.HostPath); s.HostPath != "" {
+		s.HostPath = filepath.Clean(s.HostPath)
+	}
+	if s.MountPath = strings.TrimSpace(s.MountPath); s.MountPath != "" {
+		s.MountPath = filepath.Clean(s.MountPath)
+	}
+}
+
+// Target returns the guest mount point of the share.
+func (s Share) Target() string {
+	if s.MountPath != "" {
+		return s.MountPath
+	}
+	return DefaultShareMountRoot + "/" + s.Tag
+}
+
+// Validate checks the tag and paths of a single share.
+func (s Share) Validate() error {
+	if s.Tag == "" {
+		return fmt.Errorf("share tag required")
+	}
+	if len(s.Tag) > MaxShareTagLength {
+		return fmt.Errorf("share %s: tag longer than %d characters", s.Tag, MaxShareTagLength)
+	}
+	for _, c := range s.Tag {
+		if (c < 'a' || c > 'z') && (c < 'A' || c > 'Z') && (c < '0' || c > '9') && c != '-' && c != '_' && c != '.' {
+			return fmt.Errorf("share %s: tag may only contain letters, digits, '-', '_' and '.'", s.Tag)
+		}
+	}
+	if s.HostPath == "" {
+		return fmt.Errorf("share %s: host_path required", s.Tag)
+	}
+	if !filepath.IsAbs(s.HostPath) {
+		return fmt.Errorf("share %s: host_path %q must be absolute", s.Tag, s.HostPath)
+	}
+	if s.MountPath != "" && !strings.HasPrefix(s.MountPath, "/") {
+		return fmt.Errorf("share %s: mount_path %q must be absolute", s.Tag, s.MountPath)
+	}
+	if strings.ContainsAny(s.MountPath, ", ") {
+		return fmt.Errorf("share %s: mount_path %q must not contain commas or spaces", s.Tag, s.MountPath)
+	}
+	if s.Target() == "/" {
+		return fmt.Errorf("share %s: cannot be mounted over the root filesystem", s.Tag)
+	}
+	return nil
+}
+
+// ValidateShares validates each share and checks that tags and mount points
+// are unique.
+func ValidateShares(shares []Share) error {
+	if len(shares) > MaxShares {
+		return fmt.Errorf("at most %d shares are supported", MaxShares)
+	}
+	tags := make(map[string]struct{}, len(shares))
+	targets := make(map[string]string, len(shares))
+	for _, share := range shares {
+		if err := share.Validate(); err != nil {
+			return err
+		}
+		if _, ok := tags[share.Tag]; ok {
+			return fmt.Errorf("share %s: duplicate tag", share.Tag)
+		}
+		tags[share.Tag] = struct{}{}
+		if other, ok := targets[share.Target()]; ok {
+			return fmt.Errorf("share %s: mount path %s already used by share %s", share.Tag, share.Target(), other)
+		}
+		targets[share.Target()] = share.Tag
+	}
+	return nil
+}
+
+// NormalizeShares normalizes every share in place.
+func NormalizeShares(shares []Share) {
+	for i := range shares {
+		shares[i].Normalize()
+	}
+}
+
+// ShareArg formats the volant.share<N> value for share.
+func ShareArg(share Share) string {
+	value := share.Tag + "," + share.Target()
+	if share.ReadOnly {
+		value += ",ro"
+	}
+	return value
+}
+
+// ParseShareArg reverses ShareArg, returning the tag, mount point and
+// whether the share is read-only.
+func ParseShareArg(value string) (tag, target string, readOnly bool, err error) {
+	parts := strings.Split(strings.TrimSpace(value), ",")
+	if len(parts) < 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", false, fmt.Errorf("share %q: expected tag,mount_path[,ro]", value)
+	}
+	for _, opt := range parts[2:] {
+		if opt != "ro" {
+			return "", "", false, fmt.Errorf("share %q: unknown option %q", value, opt)
+		}
+		readOnly = true
+	}
+	return parts[0], parts[1], readOnly, nil
+}
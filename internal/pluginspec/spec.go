@@ -12,6 +12,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"net"
 	"net/url"
 	"sort"
 	"strings"
@@ -36,8 +37,29 @@ const (
 	RootFSDeviceKey = "volant.rootfs_device"
 	// RootFSFSTypeKey indicates the filesystem type for the root filesystem device.
 	RootFSFSTypeKey = "volant.rootfs_fstype"
+	// RootFSOverlayKey mounts the root filesystem read-only beneath an
+	// overlay. The value is "tmpfs" or the block device holding the upper layer.
+	RootFSOverlayKey = "volant.rootfs_overlay"
 	// BootModeKey controls the agent boot strategy: auto|initramfs|rootfs
 	BootModeKey = "volant.boot"
+	// IPv6AddressKey carries the guest IPv6 address in CIDR form. The kernel's
+	// ip= autoconfiguration is IPv4-only, so the agent applies it to eth0.
+	IPv6AddressKey = "volant.ip6"
+	// IPv6GatewayKey carries the IPv6 default gateway for the guest.
+	IPv6GatewayKey = "volant.gw6"
+	// VMNameKey carries the VM name when config bundles or secrets are
+	// attached, so the agent can fetch them from /api/v1/vms/<name>/bundles
+	// and /api/v1/vms/<name>/secrets.
+	VMNameKey = "volant.vm"
+	// SecretMountsKey carries the number of secrets mounted into the VM.
+	SecretMountsKey = "volant.secrets"
+	// InterfaceKeyPrefix, suffixed with the interface index (volant.if1,
+	// volant.if2, ...), carries "mac,cidr[,gateway]" for each additional NIC
+	// with a static address and just "mac" for dhcp interfaces.
+	InterfaceKeyPrefix = "volant.if"
+	// ScratchDiskKey carries "device,mount_path" for the VM's scratch disk,
+	// which the agent mounts as ext4.
+	ScratchDiskKey = "volant.scratch"
 )
 
 // Manifest captures the metadata required to register and boot a runtime plugin.
@@ -48,12 +70,21 @@ type Manifest struct {
 	Runtime       string            `json:"runtime"`
 	RootFS        RootFS            `json:"rootfs"`
 	Initramfs     Initramfs         `json:"initramfs"`
+	Kernel        *Kernel           `json:"kernel,omitempty"`
+	BootMode      string            `json:"boot_mode,omitempty"`
 	Disks         []Disk            `json:"disks,omitempty"`
+	Shares        []Share           `json:"shares,omitempty"`
+	Secrets       []SecretMount     `json:"secrets,omitempty"`
 	Image         string            `json:"image,omitempty"`
 	ImageDigest   string            `json:"image_digest,omitempty"`
 	Resources     ResourceSpec      `json:"resources"`
+	Limits        *Limits           `json:"limits,omitempty"`
 	Actions       map[string]Action `json:"actions,omitempty"`
 	HealthCheck   HealthCheck       `json:"health_check"`
+	Hooks         *Hooks            `json:"hooks,omitempty"`
+	Exec          *Exec             `json:"exec,omitempty"`
+	Metrics       *Metrics          `json:"metrics,omitempty"`
+	Agent         *Agent            `json:"agent,omitempty"`
 	Workload      Workload          `json:"workload"`
 	CloudInit     *CloudInit        `json:"cloud_init,omitempty"`
 	Network       *NetworkConfig    `json:"network,omitempty"`
@@ -61,18 +92,59 @@ type Manifest struct {
 	Enabled       bool              `json:"enabled"`
 	OpenAPI       string            `json:"openapi,omitempty"`
 	Labels        map[string]string `json:"labels,omitempty"`
+	// HostFeatures lists host kernel features the plugin needs (see
+	// KnownHostFeatures). Installs and VM creation fail fast on hosts that
+	// lack them.
+	HostFeatures []string `json:"host_features,omitempty"`
+	// TTLSeconds is the default lifetime of the plugin's VMs, counted from
+	// each start; 0 keeps them until they are stopped. TTLAction is what
+	// happens when it runs out: stop (the default) or delete.
+	TTLSeconds int    `json:"ttl_seconds,omitempty"`
+	TTLAction  string `json:"ttl_action,omitempty"`
 }
 
 // DeviceConfig holds device passthrough configuration
 type DeviceConfig struct {
 	PCIPassthrough []string `json:"pci_passthrough,omitempty"` // PCI addresses like "0000:01:00.0"
 	Allowlist      []string `json:"allowlist,omitempty"`       // Optional vendor:device patterns like "10de:*"
+	// Requests asks for devices by kind instead of address; the orchestrator
+	// picks matching devices from the host passthrough pool at launch.
+	Requests []DeviceRequest `json:"requests,omitempty"`
 }
 
 type RootFS struct {
 	URL      string `json:"url"`
 	Checksum string `json:"checksum,omitempty"`
 	Format   string `json:"format,omitempty"`
+	// ReadOnly boots the image read-only beneath a writable overlay so the
+	// base image is never modified and can be shared between VMs.
+	ReadOnly bool `json:"readonly,omitempty"`
+	// Overlay selects the upper layer for a read-only root: "tmpfs"
+	// (default; discarded on every boot) or "disk" (kept across restarts).
+	Overlay       string `json:"overlay,omitempty"`
+	OverlaySizeMB int    `json:"overlay_size_mb,omitempty"`
+	// KeepClone keeps the per-VM copy-on-write clone of a writable qcow2
+	// image when the VM is destroyed; a VM later created under the same name
+	// boots from it again.
+	KeepClone bool `json:"keep_clone,omitempty"`
+}
+
+// Root filesystem overlay kinds.
+const (
+	RootFSOverlayTmpfs = "tmpfs"
+	RootFSOverlayDisk  = "disk"
+)
+
+// OverlayKind returns the normalized overlay kind of a read-only root, or ""
+// when the root is writable.
+func (r RootFS) OverlayKind() string {
+	if !r.ReadOnly {
+		return ""
+	}
+	if kind := strings.ToLower(strings.TrimSpace(r.Overlay)); kind != "" {
+		return kind
+	}
+	return RootFSOverlayTmpfs
 }
 
 type Initramfs struct {
@@ -80,6 +152,14 @@ type Initramfs struct {
 	Checksum string `json:"checksum,omitempty"`
 }
 
+// Kernel is a guest kernel shipped with a plugin, booted instead of the
+// daemon's default kernel. Remote kernels are downloaded once per host and
+// verified against Checksum.
+type Kernel struct {
+	URL      string `json:"url"`
+	Checksum string `json:"checksum,omitempty"`
+}
+
 type Disk struct {
 	Name     string `json:"name"`
 	Source   string `json:"source"`
@@ -101,6 +181,10 @@ type CloudInitDoc struct {
 	Inline  bool   `json:"inline,omitempty"`
 	Content string `json:"content,omitempty"`
 	Path    string `json:"path,omitempty"`
+	// Template renders Content as a Go template when the seed image is
+	// built, with the VM's name, addresses and volantd endpoint, and
+	// `{{ secret "name" }}` for secrets stored in volantd.
+	Template bool `json:"template,omitempty"`
 }
 
 var allowedDiskFormats = map[string]struct{}{
@@ -191,6 +275,9 @@ func (d CloudInitDoc) Validate(field string) error {
 	if d.Path != "" && d.Inline {
 		return fmt.Errorf("cloud_init.%s: inline must be false when path provided", field)
 	}
+	if d.Template && d.Content == "" {
+		return fmt.Errorf("cloud_init.%s: template requires inline content", field)
+	}
 	return nil
 }
 
@@ -205,7 +292,26 @@ type Action struct {
 	Description string `json:"description"`
 	Method      string `json:"method"`
 	Path        string `json:"path"`
-	TimeoutMs   int64  `json:"timeout_ms"`
+	// TimeoutMs bounds each attempt at the action, replacing the default
+	// agent timeouts when set.
+	TimeoutMs int64 `json:"timeout_ms"`
+	// Stream relays the workload's response to the caller as it is written,
+	// for chunked or SSE replies. Streaming actions are bounded only by
+	// TimeoutMs, not by the default request timeouts.
+	Stream bool         `json:"stream,omitempty"`
+	Retry  *RetryPolicy `json:"retry,omitempty"`
+}
+
+// MaxActionAttempts caps RetryPolicy.MaxAttempts.
+const MaxActionAttempts = 10
+
+// RetryPolicy retries an action whose agent cannot be reached, times out or
+// answers 502, 503 or 504.
+type RetryPolicy struct {
+	// MaxAttempts counts the first try; 0 and 1 disable retries.
+	MaxAttempts int `json:"max_attempts"`
+	// BackoffMs is the delay before the first retry, doubled for each one after.
+	BackoffMs int64 `json:"backoff_ms,omitempty"`
 }
 
 // HealthCheck defines a basic probe configuration.
@@ -243,6 +349,12 @@ func (m Manifest) Validate() error {
 	if normalized.Resources.MemoryMB <= 0 {
 		return fmt.Errorf("plugin manifest: memory_mb must be > 0")
 	}
+	if err := normalized.Limits.Validate(normalized.Resources); err != nil {
+		return fmt.Errorf("plugin manifest: %w", err)
+	}
+	if err := ValidateTTL(normalized.TTLSeconds, normalized.TTLAction); err != nil {
+		return fmt.Errorf("plugin manifest: %w", err)
+	}
 	for name, action := range normalized.Actions {
 		if strings.TrimSpace(action.Method) == "" {
 			return fmt.Errorf("plugin manifest: action %s missing method", name)
@@ -250,6 +362,17 @@ func (m Manifest) Validate() error {
 		if strings.TrimSpace(action.Path) == "" {
 			return fmt.Errorf("plugin manifest: action %s missing path", name)
 		}
+		if action.TimeoutMs < 0 {
+			return fmt.Errorf("plugin manifest: action %s timeout_ms must be >= 0", name)
+		}
+		if retry := action.Retry; retry != nil {
+			if retry.MaxAttempts < 0 || retry.MaxAttempts > MaxActionAttempts {
+				return fmt.Errorf("plugin manifest: action %s retry.max_attempts must be between 0 and %d", name, MaxActionAttempts)
+			}
+			if retry.BackoffMs < 0 {
+				return fmt.Errorf("plugin manifest: action %s retry.backoff_ms must be >= 0", name)
+			}
+		}
 	}
 	if err := normalized.Workload.Validate(); err != nil {
 		return err
@@ -261,16 +384,28 @@ func (m Manifest) Validate() error {
 	if err := normalized.Initramfs.Validate(); err != nil {
 		return err
 	}
+	if err := normalized.Kernel.Validate(); err != nil {
+		return err
+	}
 	rootfsSet := strings.TrimSpace(normalized.RootFS.URL) != ""
 	initramfsSet := strings.TrimSpace(normalized.Initramfs.URL) != ""
 	if !rootfsSet && !initramfsSet {
 		return fmt.Errorf("plugin manifest: at least one of rootfs.url or initramfs.url must be set")
 	}
+	if err := normalized.validateBootMode(); err != nil {
+		return err
+	}
 	for _, disk := range normalized.Disks {
 		if err := disk.Validate(); err != nil {
 			return fmt.Errorf("plugin manifest: %w", err)
 		}
 	}
+	if err := ValidateShares(normalized.Shares); err != nil {
+		return fmt.Errorf("plugin manifest: %w", err)
+	}
+	if err := ValidateSecretMounts(normalized.Secrets); err != nil {
+		return fmt.Errorf("plugin manifest: %w", err)
+	}
 	if normalized.CloudInit != nil {
 		if err := normalized.CloudInit.Validate(); err != nil {
 			return fmt.Errorf("plugin manifest: %w", err)
@@ -281,6 +416,36 @@ func (m Manifest) Validate() error {
 			return fmt.Errorf("plugin manifest: %w", err)
 		}
 	}
+	if normalized.Hooks != nil {
+		if err := normalized.Hooks.Validate(); err != nil {
+			return fmt.Errorf("plugin manifest: %w", err)
+		}
+	}
+	if normalized.Exec != nil {
+		if err := normalized.Exec.Validate(); err != nil {
+			return fmt.Errorf("plugin manifest: %w", err)
+		}
+	}
+	if normalized.Metrics != nil {
+		if err := normalized.Metrics.Validate(); err != nil {
+			return fmt.Errorf("plugin manifest: %w", err)
+		}
+	}
+	if normalized.Agent != nil {
+		if err := normalized.Agent.Validate(); err != nil {
+			return fmt.Errorf("plugin manifest: %w", err)
+		}
+	}
+	if normalized.Devices != nil {
+		if err := normalized.Devices.Validate(); err != nil {
+			return fmt.Errorf("plugin manifest: %w", err)
+		}
+	}
+	for _, feature := range normalized.HostFeatures {
+		if !IsKnownHostFeature(feature) {
+			return fmt.Errorf("plugin manifest: unknown host feature %q (known: %s)", feature, strings.Join(KnownHostFeatures, ", "))
+		}
+	}
 	return nil
 }
 
@@ -302,16 +467,24 @@ func (m *Manifest) Normalize() {
 	m.RootFS.URL = strings.TrimSpace(m.RootFS.URL)
 	m.RootFS.Checksum = strings.TrimSpace(m.RootFS.Checksum)
 	m.RootFS.Format = normalizeFormat(m.RootFS.Format)
+	m.RootFS.Overlay = strings.ToLower(strings.TrimSpace(m.RootFS.Overlay))
 	if m.RootFS.Format == "" {
 		m.RootFS.Format = "raw"
 	}
+	m.BootMode = strings.ToLower(strings.TrimSpace(m.BootMode))
 	m.Initramfs.URL = strings.TrimSpace(m.Initramfs.URL)
 	m.Initramfs.Checksum = strings.TrimSpace(m.Initramfs.Checksum)
+	if m.Kernel != nil {
+		m.Kernel.URL = strings.TrimSpace(m.Kernel.URL)
+		m.Kernel.Checksum = strings.TrimSpace(m.Kernel.Checksum)
+	}
 	if len(m.Disks) > 0 {
 		for i := range m.Disks {
 			m.Disks[i].Normalize()
 		}
 	}
+	NormalizeShares(m.Shares)
+	NormalizeSecretMounts(m.Secrets)
 	if m.CloudInit != nil {
 		m.CloudInit.Normalize()
 		if strings.TrimSpace(m.CloudInit.Datasource) == "" {
@@ -325,6 +498,15 @@ func (m *Manifest) Normalize() {
 	if m.Network != nil {
 		m.Network.Normalize()
 	}
+	m.Hooks.Normalize()
+	m.Exec.Normalize()
+	m.Metrics.Normalize()
+	m.Agent.Normalize()
+	m.Devices.Normalize()
+	m.HostFeatures = normalizeHostFeatures(m.HostFeatures)
+	if m.TTLAction != "" {
+		m.TTLAction = NormalizeTTLAction(m.TTLAction)
+	}
 
 	m.Workload.Type = strings.TrimSpace(m.Workload.Type)
 	m.Workload.BaseURL = strings.TrimSpace(m.Workload.BaseURL)
@@ -427,7 +609,7 @@ func (w Workload) Validate() error {
 func (r RootFS) Validate() error {
 	url := strings.TrimSpace(r.URL)
 	if url == "" {
-		return nil
+		return r.validateOverlay()
 	}
 	if !strings.HasPrefix(url, "http://") && !strings.HasPrefix(url, "https://") && !strings.HasPrefix(url, "file://") && !strings.HasPrefix(url, "/") {
 		return fmt.Errorf("plugin manifest: rootfs url must be http(s), file://, or absolute path")
@@ -443,9 +625,40 @@ func (r RootFS) Validate() error {
 	if _, ok := allowedDiskFormats[format]; !ok {
 		return fmt.Errorf("plugin manifest: rootfs format %q not supported", r.Format)
 	}
+	if r.KeepClone && (format != "qcow2" || r.ReadOnly) {
+		return fmt.Errorf("plugin manifest: rootfs keep_clone requires a writable qcow2 image")
+	}
+	return r.validateOverlay()
+}
+
+func (r RootFS) validateOverlay() error {
+	if !r.ReadOnly {
+		if strings.TrimSpace(r.Overlay) != "" || r.OverlaySizeMB != 0 {
+			return fmt.Errorf("plugin manifest: rootfs overlay requires readonly")
+		}
+		return nil
+	}
+	switch r.OverlayKind() {
+	case RootFSOverlayTmpfs:
+		if r.OverlaySizeMB != 0 {
+			return fmt.Errorf("plugin manifest: rootfs overlay_size_mb only applies to disk overlays")
+		}
+	case RootFSOverlayDisk:
+		if r.OverlaySizeMB < 0 {
+			return fmt.Errorf("plugin manifest: rootfs overlay_size_mb must be positive")
+		}
+	default:
+		return fmt.Errorf("plugin manifest: rootfs overlay %q not supported (use tmpfs or disk)", r.Overlay)
+	}
 	return nil
 }
 
+// Cloned reports whether the root boots from a per-VM copy-on-write clone of
+// the image, which is the case for writable qcow2 images.
+func (r RootFS) Cloned() bool {
+	return strings.TrimSpace(r.URL) != "" && !r.ReadOnly && normalizeFormat(r.Format) == "qcow2"
+}
+
 func (i Initramfs) Validate() error {
 	url := strings.TrimSpace(i.URL)
 	if url == "" {
@@ -461,6 +674,26 @@ func (i Initramfs) Validate() error {
 	return nil
 }
 
+// Validate checks the kernel location and checksum. A nil kernel uses the
+// daemon default.
+func (k *Kernel) Validate() error {
+	if k == nil {
+		return nil
+	}
+	url := strings.TrimSpace(k.URL)
+	if url == "" {
+		return fmt.Errorf("plugin manifest: kernel url required")
+	}
+	if !strings.HasPrefix(url, "http://") && !strings.HasPrefix(url, "https://") && !strings.HasPrefix(url, "file://") && !strings.HasPrefix(url, "/") {
+		return fmt.Errorf("plugin manifest: kernel url must be http(s), file://, or absolute path")
+	}
+	checksum := strings.TrimSpace(k.Checksum)
+	if checksum != "" && !strings.Contains(checksum, ":") && len(checksum) < 32 {
+		return fmt.Errorf("plugin manifest: kernel checksum should include algorithm prefix or be a sha256")
+	}
+	return nil
+}
+
 // Encode encodes the manifest as JSON, base64url encoded, for kernel cmdline transport.
 func Encode(m Manifest) (string, error) {
 	data, err := json.Marshal(m)
@@ -520,10 +753,54 @@ const (
 
 // NetworkConfig defines plugin-level network configuration defaults.
 type NetworkConfig struct {
-	Mode       NetworkMode `json:"mode"`
-	Subnet     string      `json:"subnet,omitempty"`      // For bridged mode: CIDR (e.g., "10.1.0.0/24")
-	Gateway    string      `json:"gateway,omitempty"`     // For bridged mode: gateway IP
-	AutoAssign bool        `json:"auto_assign,omitempty"` // For bridged mode: auto-allocate IPs from subnet
+	Mode NetworkMode `json:"mode"`
+	// Name attaches the primary NIC to a user-defined network (bridged mode)
+	// instead of the daemon's default bridge.
+	Name       string `json:"name,omitempty"`
+	Subnet     string `json:"subnet,omitempty"`      // For bridged mode: CIDR (e.g., "10.1.0.0/24")
+	Gateway    string `json:"gateway,omitempty"`     // For bridged mode: gateway IP
+	AutoAssign bool   `json:"auto_assign,omitempty"` // For bridged mode: auto-allocate IPs from subnet
+	// Egress restricts traffic the VM may send off the host.
+	Egress *EgressPolicy `json:"egress,omitempty"`
+	// Interfaces attaches additional NICs after the primary interface.
+	Interfaces []NetworkInterface `json:"interfaces,omitempty"`
+}
+
+// MaxNetworkInterfaces bounds the additional NICs a VM may declare.
+const MaxNetworkInterfaces = 8
+
+// NetworkInterface is an additional NIC attached to an existing host bridge.
+// Bridged interfaces take a static address; dhcp interfaces rely on a DHCP
+// server on that bridge and a client in the guest.
+type NetworkInterface struct {
+	Bridge    string      `json:"bridge"`
+	Mode      NetworkMode `json:"mode,omitempty"`       // bridged (default) or dhcp
+	IPAddress string      `json:"ip_address,omitempty"` // CIDR, required for bridged
+	Gateway   string      `json:"gateway,omitempty"`
+	MAC       string      `json:"mac_address,omitempty"` // derived from the VM name when empty
+}
+
+// Egress verdicts.
+const (
+	EgressAllow = "allow"
+	EgressDeny  = "deny"
+)
+
+// EgressPolicy is an ordered list of egress rules; the first matching rule
+// wins and Default applies to everything else.
+type EgressPolicy struct {
+	Default string       `json:"default,omitempty"` // allow (default) or deny
+	Rules   []EgressRule `json:"rules,omitempty"`
+}
+
+// EgressRule matches outbound traffic by destination and port.
+type EgressRule struct {
+	Action string   `json:"action"`
+	CIDRs  []string `json:"cidrs,omitempty"`
+	// Hosts are DNS names resolved by volantd when the policy is installed.
+	Hosts []string `json:"hosts,omitempty"`
+	Ports []int    `json:"ports,omitempty"`
+	Proto string   `json:"proto,omitempty"` // tcp or udp; empty matches both
 }
 
 // Normalize trims and normalizes network configuration fields.
@@ -532,8 +809,124 @@ func (n *NetworkConfig) Normalize() {
 		return
 	}
 	n.Mode = NetworkMode(strings.ToLower(strings.TrimSpace(string(n.Mode))))
+	n.Name = strings.TrimSpace(n.Name)
 	n.Subnet = strings.TrimSpace(n.Subnet)
 	n.Gateway = strings.TrimSpace(n.Gateway)
+	if n.Egress != nil {
+		n.Egress.Normalize()
+	}
+	for i := range n.Interfaces {
+		iface := &n.Interfaces[i]
+		iface.Bridge = strings.TrimSpace(iface.Bridge)
+		iface.Mode = NetworkMode(strings.ToLower(strings.TrimSpace(string(iface.Mode))))
+		if iface.Mode == "" {
+			iface.Mode = NetworkModeBridged
+		}
+		iface.IPAddress = strings.TrimSpace(iface.IPAddress)
+		iface.Gateway = strings.TrimSpace(iface.Gateway)
+		iface.MAC = strings.ToLower(strings.TrimSpace(iface.MAC))
+	}
+}
+
+// Validate checks the bridge, addressing and MAC of an additional NIC.
+func (i NetworkInterface) Validate() error {
+	bridge := strings.TrimSpace(i.Bridge)
+	if bridge == "" || len(bridge) > 15 || strings.ContainsAny(bridge, "/ ") {
+		return fmt.Errorf("interface: invalid bridge name %q", i.Bridge)
+	}
+	switch NetworkMode(strings.ToLower(strings.TrimSpace(string(i.Mode)))) {
+	case "", NetworkModeBridged:
+		if _, _, err := net.ParseCIDR(strings.TrimSpace(i.IPAddress)); err != nil {
+			return fmt.Errorf("interface on %s: bridged mode requires ip_address in CIDR form", bridge)
+		}
+	case NetworkModeDHCP:
+		if strings.TrimSpace(i.IPAddress) != "" {
+			return fmt.Errorf("interface on %s: ip_address is not allowed in dhcp mode", bridge)
+		}
+	default:
+		return fmt.Errorf("interface on %s: unsupported mode %q (must be bridged or dhcp)", bridge, i.Mode)
+	}
+	if gw := strings.TrimSpace(i.Gateway); gw != "" && net.ParseIP(gw) == nil {
+		return fmt.Errorf("interface on %s: invalid gateway %q", bridge, i.Gateway)
+	}
+	if mac := strings.TrimSpace(i.MAC); mac != "" {
+		if _, err := net.ParseMAC(mac); err != nil {
+			return fmt.Errorf("interface on %s: invalid mac_address %q", bridge, i.MAC)
+		}
+	}
+	return nil
+}
+
+// Normalize lowercases verdicts and protocols and trims destinations.
+func (p *EgressPolicy) Normalize() {
+	if p == nil {
+		return
+	}
+	p.Default = strings.ToLower(strings.TrimSpace(p.Default))
+	if p.Default == "" {
+		p.Default = EgressAllow
+	}
+	for i := range p.Rules {
+		rule := &p.Rules[i]
+		rule.Action = strings.ToLower(strings.TrimSpace(rule.Action))
+		rule.Proto = strings.ToLower(strings.TrimSpace(rule.Proto))
+		rule.CIDRs = trimNonEmpty(rule.CIDRs)
+		rule.Hosts = trimNonEmpty(rule.Hosts)
+	}
+}
+
+// Validate checks verdicts, destinations and ports.
+func (p EgressPolicy) Validate() error {
+	switch strings.ToLower(strings.TrimSpace(p.Default)) {
+	case "", EgressAllow, EgressDeny:
+	default:
+		return fmt.Errorf("egress: default %q must be allow or deny", p.Default)
+	}
+	for i, rule := range p.Rules {
+		switch strings.ToLower(strings.TrimSpace(rule.Action)) {
+		case EgressAllow, EgressDeny:
+		default:
+			return fmt.Errorf("egress: rule %d: action %q must be allow or deny", i, rule.Action)
+		}
+		if len(rule.CIDRs) == 0 && len(rule.Hosts) == 0 && len(rule.Ports) == 0 {
+			return fmt.Errorf("egress: rule %d: cidrs, hosts or ports required", i)
+		}
+		for _, cidr := range rule.CIDRs {
+			cidr = strings.TrimSpace(cidr)
+			if net.ParseIP(cidr) != nil {
+				continue
+			}
+			if _, _, err := net.ParseCIDR(cidr); err != nil {
+				return fmt.Errorf("egress: rule %d: invalid cidr %q", i, cidr)
+			}
+		}
+		for _, host := range rule.Hosts {
+			if strings.TrimSpace(host) == "" || strings.ContainsAny(host, "/ ") {
+				return fmt.Errorf("egress: rule %d: invalid host %q", i, host)
+			}
+		}
+		for _, port := range rule.Ports {
+			if port <= 0 || port > 65535 {
+				return fmt.Errorf("egress: rule %d: port %d out of range", i, port)
+			}
+		}
+		switch strings.ToLower(strings.TrimSpace(rule.Proto)) {
+		case "", "tcp", "udp":
+		default:
+			return fmt.Errorf("egress: rule %d: proto %q must be tcp or udp", i, rule.Proto)
+		}
+	}
+	return nil
+}
+
+func trimNonEmpty(values []string) []string {
+	out := make([]string, 0, len(values))
+	for _, value := range values {
+		if value = strings.TrimSpace(value); value != "" {
+			out = append(out, value)
+		}
+	}
+	return out
 }
 
 // Validate checks network configuration for semantic correctness.
@@ -552,5 +945,29 @@ func (n NetworkConfig) Validate() error {
 	default:
 		return fmt.Errorf("network: unsupported mode %q (must be vsock, bridged, or dhcp)", n.Mode)
 	}
+	if strings.TrimSpace(n.Name) != "" {
+		if NetworkMode(mode) != NetworkModeBridged {
+			return fmt.Errorf("network: name requires bridged mode")
+		}
+		if n.Subnet != "" || n.Gateway != "" {
+			return fmt.Errorf("network: subnet and gateway come from network %s and cannot be set", n.Name)
+		}
+	}
+	if len(n.Interfaces) > MaxNetworkInterfaces {
+		return fmt.Errorf("network: at most %d additional interfaces are supported", MaxNetworkInterfaces)
+	}
+	for _, iface := range n.Interfaces {
+		if err := iface.Validate(); err != nil {
+			return fmt.Errorf("network: %w", err)
+		}
+	}
+	if n.Egress != nil {
+		if NetworkMode(mode) == NetworkModeVsock {
+			return fmt.Errorf("network: egress policy requires bridged or dhcp mode")
+		}
+		if err := n.Egress.Validate(); err != nil {
+			return fmt.Errorf("network: %w", err)
+		}
+	}
 	return nil
 }
@@ -0,0 +1,39 @@
+// Copyright (c) 2025 HYPR. PTE. LTD.
+//
+// Business Source License 1.1
+// See LICENSE file in the project root for details.
+
+package pluginspec
+
+import (
+	"fmt"
+	"strings"
+)
+
+// What the orchestrator does with a VM once its ttl_seconds have passed.
+const (
+	TTLActionStop   = "stop"
+	TTLActionDelete = "delete"
+)
+
+// NormalizeTTLAction lowercases action and defaults it to TTLActionStop.
+func NormalizeTTLAction(action string) string {
+	action = strings.ToLower(strings.TrimSpace(action))
+	if action == "" {
+		return TTLActionStop
+	}
+	return action
+}
+
+// ValidateTTL rejects negative TTLs and unknown expiry actions.
+func ValidateTTL(seconds int, action string) error {
+	if seconds < 0 {
+		return fmt.Errorf("ttl_seconds must be >= 0")
+	}
+	switch NormalizeTTLAction(action) {
+	case TTLActionStop, TTLActionDelete:
+		return nil
+	default:
+		return fmt.Errorf("ttl_action %q not supported (use %s or %s)", action, TTLActionStop, TTLActionDelete)
+	}
+}
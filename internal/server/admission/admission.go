@@ -0,0 +1,308 @@
+// Copyright (c) 2025 HYPR. PTE. LTD.
+//
+// Business Source License 1.1
+// See LICENSE file in the project root for details.
+
+// Package admission calls external validating and mutating webhooks before
+// volantd accepts a change, so policy engines such as OPA can allow, reject
+// or rewrite requests.
+package admission
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+// EnvConfig names the environment variable holding the webhook config path.
+const EnvConfig = "VOLANT_ADMISSION_CONFIG"
+
+// Operation identifies the change being admitted.
+type Operation string
+
+const (
+	OpVMCreate         Operation = "vm.create"
+	OpVMConfigUpdate   Operation = "vm.config.update"
+	OpPluginInstall    Operation = "plugin.install"
+	OpDeploymentCreate Operation = "deployment.create"
+	OpDeploymentScale  Operation = "deployment.scale"
+	OpDeploymentUpdate Operation = "deployment.update"
+	OpDeploymentDelete Operation = "deployment.delete"
+)
+
+var knownOperations = map[Operation]bool{
+	OpVMCreate:         true,
+	OpVMConfigUpdate:   true,
+	OpPluginInstall:    true,
+	OpDeploymentCreate: true,
+	OpDeploymentScale:  true,
+	OpDeploymentUpdate: true,
+	OpDeploymentDelete: true,
+}
+
+// Webhook types.
+const (
+	TypeValidating = "validating"
+	TypeMutating   = "mutating"
+)
+
+// Failure policies decide what happens when a webhook cannot be reached or
+// returns garbage.
+const (
+	FailurePolicyFail   = "fail"
+	FailurePolicyIgnore = "ignore"
+)
+
+const (
+	defaultTimeout = 5 * time.Second
+	maxTimeout     = 30 * time.Second
+	maxResponse    = 1 << 20
+)
+
+var (
+	// ErrDenied is returned when a webhook rejects the request.
+	ErrDenied = errors.New("admission denied")
+	// ErrWebhookFailed is returned when a webhook with the fail policy errors.
+	ErrWebhookFailed = errors.New("admission webhook failed")
+)
+
+// Config is the on-disk webhook configuration.
+type Config struct {
+	Webhooks []Webhook `json:"webhooks"`
+}
+
+// Webhook is one registered endpoint.
+type Webhook struct {
+	Name string `json:"name"`
+	URL  string `json:"url"`
+	// Type is "validating" (default) or "mutating".
+	Type string `json:"type,omitempty"`
+	// Operations limits the webhook to these operations; empty means all.
+	Operations    []Operation `json:"operations,omitempty"`
+	TimeoutMs     int         `json:"timeout_ms,omitempty"`
+	FailurePolicy string      `json:"failure_policy,omitempty"`
+}
+
+// Request is the body POSTed to webhooks.
+type Request struct {
+	UID       string          `json:"uid"`
+	Operation Operation       `json:"operation"`
+	Name      string          `json:"name,omitempty"`
+	Object    json.RawMessage `json:"object,omitempty"`
+}
+
+// Response is the body webhooks must return.
+type Response struct {
+	Allowed bool   `json:"allowed"`
+	Reason  string `json:"reason,omitempty"`
+	// Object replaces the request object. Only honoured for mutating hooks.
+	Object json.RawMessage `json:"object,omitempty"`
+}
+
+// Controller runs the configured webhooks. A nil controller admits everything.
+type Controller struct {
+	logger   *slog.Logger
+	client   *http.Client
+	webhooks []Webhook
+}
+
+// New validates cfg and returns a controller, or nil when no webhooks are
+// configured.
+func New(cfg Config, logger *slog.Logger) (*Controller, error) {
+	if len(cfg.Webhooks) == 0 {
+		return nil, nil
+	}
+	if logger == nil {
+		logger = slog.Default()
+	}
+	hooks := make([]Webhook, 0, len(cfg.Webhooks))
+	for i, hook := range cfg.Webhooks {
+		if err := hook.normalize(); err != nil {
+			return nil, fmt.Errorf("admission: webhook %d: %w", i, err)
+		}
+		hooks = append(hooks, hook)
+	}
+	return &Controller{
+		logger:   logger.With("component", "admission"),
+		client:   &http.Client{},
+		webhooks: hooks,
+	}, nil
+}
+
+// FromEnv loads the config file named by VOLANT_ADMISSION_CONFIG. It returns
+// nil when the variable is unset.
+func FromEnv(logger *slog.Logger) (*Controller, error) {
+	path := strings.TrimSpace(os.Getenv(EnvConfig))
+	if path == "" {
+		return nil, nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("admission: read config: %w", err)
+	}
+	var cfg Config
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("admission: decode config: %w", err)
+	}
+	return New(cfg, logger)
+}
+
+func (w *Webhook) normalize() error {
+	w.Name = strings.TrimSpace(w.Name)
+	if w.Name == "" {
+		return errors.New("name is required")
+	}
+	parsed, err := url.Parse(strings.TrimSpace(w.URL))
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return fmt.Errorf("%s: url must be an absolute http(s) URL", w.Name)
+	}
+	w.URL = parsed.String()
+	w.Type = strings.ToLower(strings.TrimSpace(w.Type))
+	switch w.Type {
+	case "":
+		w.Type = TypeValidating
+	case TypeValidating, TypeMutating:
+	default:
+		return fmt.Errorf("%s: unknown type %q", w.Name, w.Type)
+	}
+	w.FailurePolicy = strings.ToLower(strings.TrimSpace(w.FailurePolicy))
+	switch w.FailurePolicy {
+	case "":
+		w.FailurePolicy = FailurePolicyFail
+	case FailurePolicyFail, FailurePolicyIgnore:
+	default:
+		return fmt.Errorf("%s: unknown failure_policy %q", w.Name, w.FailurePolicy)
+	}
+	for _, op := range w.Operations {
+		if !knownOperations[op] {
+			return fmt.Errorf("%s: unknown operation %q", w.Name, op)
+		}
+	}
+	if w.TimeoutMs < 0 {
+		return fmt.Errorf("%s: timeout_ms must not be negative", w.Name)
+	}
+	return nil
+}
+
+func (w Webhook) timeout() time.Duration {
+	if w.TimeoutMs == 0 {
+		return defaultTimeout
+	}
+	timeout := time.Duration(w.TimeoutMs) * time.Millisecond
+	if timeout > maxTimeout {
+		return maxTimeout
+	}
+	return timeout
+}
+
+func (w Webhook) matches(op Operation) bool {
+	if len(w.Operations) == 0 {
+		return true
+	}
+	for _, candidate := range w.Operations {
+		if candidate == op {
+			return true
+		}
+	}
+	return false
+}
+
+// Admit sends obj to every webhook registered for op. Mutating webhooks run
+// first, in config order, and may replace obj (which must be a pointer);
+// validating webhooks then see the final object. The first denial wins.
+func (c *Controller) Admit(ctx context.Context, op Operation, name string, obj any) error {
+	if c == nil {
+		return nil
+	}
+	for _, pass := range []string{TypeMutating, TypeValidating} {
+		for _, hook := range c.webhooks {
+			if hook.Type != pass || !hook.matches(op) {
+				continue
+			}
+			if err := c.call(ctx, hook, op, name, obj); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (c *Controller) call(ctx context.Context, hook Webhook, op Operation, name string, obj any) error {
+	req := Request{UID: newUID(), Operation: op, Name: name}
+	if obj != nil {
+		raw, err := json.Marshal(obj)
+		if err != nil {
+			return fmt.Errorf("admission: encode object: %w", err)
+		}
+		req.Object = raw
+	}
+	resp, err := c.post(ctx, hook, req)
+	if err != nil {
+		if hook.FailurePolicy == FailurePolicyIgnore {
+			c.logger.Warn("admission webhook failed, ignoring", "webhook", hook.Name, "operation", op, "error", err)
+			return nil
+		}
+		return fmt.Errorf("%w: %s: %v", ErrWebhookFailed, hook.Name, err)
+	}
+	if !resp.Allowed {
+		reason := strings.TrimSpace(resp.Reason)
+		if reason == "" {
+			reason = "no reason given"
+		}
+		return fmt.Errorf("%w by %s: %s", ErrDenied, hook.Name, reason)
+	}
+	if hook.Type == TypeMutating && len(resp.Object) > 0 && obj != nil {
+		if err := json.Unmarshal(resp.Object, obj); err != nil {
+			return fmt.Errorf("%w: %s: decode mutated object: %v", ErrWebhookFailed, hook.Name, err)
+		}
+	}
+	return nil
+}
+
+func (c *Controller) post(ctx context.Context, hook Webhook, body Request) (Response, error) {
+	var out Response
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return out, err
+	}
+	ctx, cancel := context.WithTimeout(ctx, hook.timeout())
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, hook.URL, bytes.NewReader(payload))
+	if err != nil {
+		return out, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return out, err
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxResponse))
+	if err != nil {
+		return out, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return out, fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	if err := json.Unmarshal(data, &out); err != nil {
+		return out, fmt.Errorf("decode response: %w", err)
+	}
+	return out, nil
+}
+
+func newUID() string {
+	var buf [16]byte
+	_, _ = rand.Read(buf[:])
+	return hex.EncodeToString(buf[:])
+}
@@ -0,0 +1,107 @@
+// Copyright (c) 2025 HYPR. PTE. LTD.
+//
+// Business Source License 1.1
+// See LICENSE file in the project root for details.
+
+package admission
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+type object struct {
+	Name     string `json:"name"`
+	Replicas int    `json:"replicas"`
+}
+
+func hookServer(t *testing.T, handle func(Request) Response) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req Request
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("decode request: %v", err)
+		}
+		_ = json.NewEncoder(w).Encode(handle(req))
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestAdmitMutatesThenValidates(t *testing.T) {
+	mutator := hookServer(t, func(req Request) Response {
+		var obj object
+		_ = json.Unmarshal(req.Object, &obj)
+		obj.Replicas = 3
+		raw, _ := json.Marshal(obj)
+		return Response{Allowed: true, Object: raw}
+	})
+	var seen int
+	validator := hookServer(t, func(req Request) Response {
+		var obj object
+		_ = json.Unmarshal(req.Object, &obj)
+		seen = obj.Replicas
+		return Response{Allowed: obj.Replicas <= 3, Reason: "too many replicas"}
+	})
+
+	controller, err := New(Config{Webhooks: []Webhook{
+		{Name: "limit", URL: validator.URL, Operations: []Operation{OpDeploymentScale}},
+		{Name: "default", URL: mutator.URL, Type: TypeMutating},
+	}}, nil)
+	if err != nil {
+		t.Fatalf("new: %v", err)
+	}
+
+	obj := object{Name: "web", Replicas: 10}
+	if err := controller.Admit(context.Background(), OpDeploymentScale, "web", &obj); err != nil {
+		t.Fatalf("admit: %v", err)
+	}
+	if obj.Replicas != 3 || seen != 3 {
+		t.Fatalf("expected mutated replicas 3, got obj=%d validator saw=%d", obj.Replicas, seen)
+	}
+}
+
+func TestAdmitDeniesAndFailurePolicy(t *testing.T) {
+	deny := hookServer(t, func(Request) Response { return Response{Allowed: false, Reason: "nope"} })
+	broken := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	t.Cleanup(broken.Close)
+
+	controller, err := New(Config{Webhooks: []Webhook{{Name: "deny", URL: deny.URL}}}, nil)
+	if err != nil {
+		t.Fatalf("new: %v", err)
+	}
+	if err := controller.Admit(context.Background(), OpVMCreate, "vm", &object{}); !errors.Is(err, ErrDenied) {
+		t.Fatalf("expected ErrDenied, got %v", err)
+	}
+
+	failing, err := New(Config{Webhooks: []Webhook{{Name: "broken", URL: broken.URL}}}, nil)
+	if err != nil {
+		t.Fatalf("new: %v", err)
+	}
+	if err := failing.Admit(context.Background(), OpVMCreate, "vm", nil); !errors.Is(err, ErrWebhookFailed) {
+		t.Fatalf("expected ErrWebhookFailed, got %v", err)
+	}
+
+	ignoring, err := New(Config{Webhooks: []Webhook{{Name: "broken", URL: broken.URL, FailurePolicy: FailurePolicyIgnore}}}, nil)
+	if err != nil {
+		t.Fatalf("new: %v", err)
+	}
+	if err := ignoring.Admit(context.Background(), OpVMCreate, "vm", nil); err != nil {
+		t.Fatalf("expected ignore policy to admit, got %v", err)
+	}
+
+	// Operations that no webhook subscribes to are admitted without a call.
+	scoped, err := New(Config{Webhooks: []Webhook{{Name: "deny", URL: deny.URL, Operations: []Operation{OpPluginInstall}}}}, nil)
+	if err != nil {
+		t.Fatalf("new: %v", err)
+	}
+	if err := scoped.Admit(context.Background(), OpVMCreate, "vm", nil); err != nil {
+		t.Fatalf("expected unrelated operation to be admitted, got %v", err)
+	}
+}
@@ -0,0 +1,134 @@
+// Copyright (c) 2025 HYPR. PTE. LTD.
+//
+// Business Source License 1.1
+// See LICENSE file in the project root for details.
+
+// Package apiauth holds the API key check and client CIDR allowlist shared by
+// the REST and gRPC APIs.
+package apiauth
+
+import (
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+	"sync/atomic"
+)
+
+const (
+	// EnvKey names the environment variable holding the API key.
+	EnvKey = "VOLANT_API_KEY"
+	// Header carries the key on REST requests.
+	Header = "X-Volant-API-Key"
+	// QueryParam carries the key when headers cannot be set (websockets).
+	QueryParam = "api_key"
+	// MetadataKey carries the key on gRPC calls.
+	MetadataKey = "x-volant-api-key"
+)
+
+var (
+	// ErrInvalidKey is returned when the provided key does not match.
+	ErrInvalidKey = errors.New("invalid api key")
+	// ErrClientDenied is returned for clients outside the CIDR allowlist.
+	ErrClientDenied = errors.New("access denied")
+)
+
+// Checker validates API keys.
+type Checker struct {
+	expected []byte
+}
+
+// New returns a checker for key, or nil when key is empty (auth disabled).
+func New(key string) *Checker {
+	if key == "" {
+		return nil
+	}
+	return &Checker{expected: []byte(key)}
+}
+
+// FromEnv returns the checker configured by VOLANT_API_KEY, or nil.
+func FromEnv() *Checker {
+	return New(strings.TrimSpace(os.Getenv(EnvKey)))
+}
+
+// current and currentNetworks are the checker and allowlist of the running
+// daemon, which a configuration reload replaces.
+var (
+	current         atomic.Pointer[Checker]
+	currentNetworks atomic.Pointer[Networks]
+)
+
+// Set makes c the checker Current returns.
+func Set(c *Checker) {
+	current.Store(c)
+}
+
+// Current returns the checker last passed to Set, or nil (auth disabled).
+func Current() *Checker {
+	return current.Load()
+}
+
+// Check reports whether provided matches the configured key. A nil checker
+// accepts everything.
+func (c *Checker) Check(provided string) error {
+	if c == nil {
+		return nil
+	}
+	if subtle.ConstantTimeCompare([]byte(provided), c.expected) != 1 {
+		return ErrInvalidKey
+	}
+	return nil
+}
+
+// Networks is the client CIDR allowlist (VOLANT_API_ALLOW_CIDR). An empty
+// allowlist admits every client.
+type Networks []*net.IPNet
+
+// ParseNetworks parses cidrs. Invalid entries are skipped and reported in the
+// returned error alongside the valid networks.
+func ParseNetworks(cidrs []string) (Networks, error) {
+	var networks Networks
+	var errs []error
+	for _, raw := range cidrs {
+		_, network, err := net.ParseCIDR(strings.TrimSpace(raw))
+		if err != nil {
+			errs = append(errs, fmt.Errorf("invalid CIDR %q: %w", raw, err))
+			continue
+		}
+		networks = append(networks, network)
+	}
+	return networks, errors.Join(errs...)
+}
+
+// Allows reports whether a client at ip may use the API. A nil ip is only
+// admitted when the allowlist is empty.
+func (n Networks) Allows(ip net.IP) bool {
+	if len(n) == 0 {
+		return true
+	}
+	if ip == nil {
+		return false
+	}
+	for _, network := range n {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// SetNetworks makes n the allowlist CurrentNetworks returns.
+func SetNetworks(n Networks) {
+	currentNetworks.Store(&n)
+}
+
+// CurrentNetworks returns the allowlist last passed to SetNetworks, or an
+// empty one.
+func CurrentNetworks() Networks {
+	if n := currentNetworks.Load(); n != nil {
+		return *n
+	}
+	return nil
+}
@@ -9,14 +9,19 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"net"
 	"net/http"
 	"time"
 
+	"github.com/volantvm/volant/internal/server/backup"
 	"github.com/volantvm/volant/internal/server/config"
 	"github.com/volantvm/volant/internal/server/db"
+	"github.com/volantvm/volant/internal/server/dhcp"
 	"github.com/volantvm/volant/internal/server/eventbus"
 	"github.com/volantvm/volant/internal/server/orchestrator"
 	"github.com/volantvm/volant/internal/server/plugins"
+	"github.com/volantvm/volant/internal/server/scheduler"
+	"github.com/volantvm/volant/internal/server/webhooks"
 )
 
 // App wires the config, persistence, orchestrator, and HTTP transport.
@@ -27,13 +32,16 @@ type App struct {
 	engine          orchestrator.Engine
 	events          eventbus.Bus
 	runtimeRegistry *plugins.Registry
+	scheduler       *scheduler.Scheduler
+	backups         *backup.Manager
+	webhooks        *webhooks.Manager
 	httpServer      *http.Server
 	shutdownWait    time.Duration
 }
 
 // New constructs the daemon application. Dependencies that are not yet
 // implemented should be passed as nil until their concrete types land.
-func New(cfg config.ServerConfig, logger *slog.Logger, store db.Store, engine orchestrator.Engine, events eventbus.Bus, registry *plugins.Registry, mux http.Handler) (*App, error) {
+func New(cfg config.ServerConfig, logger *slog.Logger, store db.Store, engine orchestrator.Engine, events eventbus.Bus, registry *plugins.Registry, sched *scheduler.Scheduler, backups *backup.Manager, hooks *webhooks.Manager, mux http.Handler) (*App, error) {
 	if logger == nil {
 		return nil, fmt.Errorf("logger must not be nil")
 	}
@@ -56,6 +64,9 @@ func New(cfg config.ServerConfig, logger *slog.Logger, store db.Store, engine or
 		engine:          engine,
 		events:          events,
 		runtimeRegistry: registry,
+		scheduler:       sched,
+		backups:         backups,
+		webhooks:        hooks,
 		httpServer:      httpServer,
 		shutdownWait:    15 * time.Second,
 	}, nil
@@ -69,14 +80,34 @@ func (a *App) Run(ctx context.Context) error {
 	if err := a.engine.Start(ctx); err != nil {
 		return fmt.Errorf("start orchestrator: %w", err)
 	}
-
-	errCh := make(chan error, 1)
-	go func() {
-		a.logger.Info("api server listening", "addr", a.httpServer.Addr)
-		if err := a.httpServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-			errCh <- err
+	if a.scheduler != nil {
+		go a.scheduler.Run(ctx)
+	}
+	if a.backups != nil {
+		go a.backups.Run(ctx)
+	}
+	if a.webhooks != nil {
+		go a.webhooks.Run(ctx)
+	}
+	if a.cfg.DHCPEnabled {
+		if err := a.startDHCP(ctx); err != nil {
+			return err
 		}
-	}()
+	}
+
+	listeners, err := a.listen()
+	if err != nil {
+		return fmt.Errorf("api listen: %w", err)
+	}
+	errCh := make(chan error, len(listeners))
+	for _, listener := range listeners {
+		go func(listener net.Listener) {
+			a.logger.Info("api server listening", "network", listener.Addr().Network(), "addr", listener.Addr().String())
+			if err := a.httpServer.Serve(listener); err != nil && err != http.ErrServerClosed {
+				errCh <- err
+			}
+		}(listener)
+	}
 
 	select {
 	case <-ctx.Done():
@@ -99,6 +130,35 @@ func (a *App) Run(ctx context.Context) error {
 	}
 }
 
+// startDHCP serves the bridge subnet once the orchestrator has brought the
+// bridge up.
+func (a *App) startDHCP(ctx context.Context) error {
+	_, subnet, err := net.ParseCIDR(a.cfg.SubnetCIDR)
+	if err != nil {
+		return fmt.Errorf("dhcp subnet: %w", err)
+	}
+	var dns []net.IP
+	for _, entry := range a.cfg.DHCPDNS {
+		dns = append(dns, net.ParseIP(entry))
+	}
+	server, err := dhcp.New(dhcp.Config{
+		Interface: a.cfg.BridgeName,
+		ServerIP:  net.ParseIP(a.cfg.HostIP),
+		Subnet:    subnet,
+		DNS:       dns,
+		LeaseTime: a.cfg.DHCPLeaseTime,
+	}, &dhcp.StoreLeaser{Store: a.Store(), Subnet: subnet}, a.logger)
+	if err != nil {
+		return err
+	}
+	go func() {
+		if err := server.Serve(ctx); err != nil {
+			a.logger.Error("dhcp server stopped", "error", err)
+		}
+	}()
+	return nil
+}
+
 func (a *App) Store() db.Store {
 	if a.engine != nil && a.engine.Store() != nil {
 		return a.engine.Store()
@@ -0,0 +1,144 @@
+// Copyright (c) 2025 HYPR. PTE. LTD.
+//
+// Business Source License 1.1
+// See LICENSE file in the project root for details.
+
+package app
+
+import (
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+)
+
+// systemdListenFDsStart is the first file descriptor passed by systemd socket
+// activation (SD_LISTEN_FDS_START).
+const systemdListenFDsStart = 3
+
+// apiSocketMode restricts the API socket to root and the socket's group.
+const apiSocketMode fs.FileMode = 0o660
+
+// listen opens the API listeners: the plain ones and, when configured, the
+// HTTPS listener.
+func (a *App) listen() ([]net.Listener, error) {
+	listeners, err := a.listenPlain()
+	if err != nil || a.cfg.TLSListenAddr == "" {
+		return listeners, err
+	}
+	listener, err := a.listenTLS()
+	if err != nil {
+		for _, l := range listeners {
+			_ = l.Close()
+		}
+		return nil, err
+	}
+	return append(listeners, listener), nil
+}
+
+// listenPlain opens the plain HTTP listeners. Sockets passed by systemd take
+// precedence over the configured address.
+func (a *App) listenPlain() ([]net.Listener, error) {
+	listeners, err := systemdListeners()
+	if err != nil {
+		return nil, err
+	}
+	if len(listeners) > 0 {
+		return listeners, nil
+	}
+
+	if socketPath, ok := a.cfg.UnixSocketPath(); ok {
+		listener, err := listenUnix(socketPath)
+		if err != nil {
+			return nil, err
+		}
+		return []net.Listener{listener}, nil
+	}
+
+	listener, err := net.Listen("tcp", a.cfg.APIListenAddr)
+	if err != nil {
+		return nil, err
+	}
+	return []net.Listener{listener}, nil
+}
+
+// listenTLS binds the HTTPS listener. Guests keep using the plain listener,
+// since their agents speak plain HTTP to the advertised address.
+func (a *App) listenTLS() (net.Listener, error) {
+	tlsConfig, err := a.cfg.TLSConfig()
+	if err != nil {
+		return nil, err
+	}
+	listener, err := net.Listen("tcp", a.cfg.TLSListenAddr)
+	if err != nil {
+		return nil, err
+	}
+	return tls.NewListener(listener, tlsConfig), nil
+}
+
+// systemdListeners returns the sockets handed over by systemd socket
+// activation, if any, and clears the activation environment so child
+// processes do not inherit it.
+func systemdListeners() ([]net.Listener, error) {
+	pid, err := strconv.Atoi(os.Getenv("LISTEN_PID"))
+	if err != nil || pid != os.Getpid() {
+		return nil, nil
+	}
+	count, err := strconv.Atoi(os.Getenv("LISTEN_FDS"))
+	if err != nil || count <= 0 {
+		return nil, nil
+	}
+	_ = os.Unsetenv("LISTEN_PID")
+	_ = os.Unsetenv("LISTEN_FDS")
+	_ = os.Unsetenv("LISTEN_FDNAMES")
+
+	listeners := make([]net.Listener, 0, count)
+	for fd := systemdListenFDsStart; fd < systemdListenFDsStart+count; fd++ {
+		file := os.NewFile(uintptr(fd), "systemd-socket-"+strconv.Itoa(fd))
+		listener, err := net.FileListener(file)
+		_ = file.Close()
+		if err != nil {
+			for _, l := range listeners {
+				_ = l.Close()
+			}
+			return nil, fmt.Errorf("systemd socket fd %d: %w", fd, err)
+		}
+		listeners = append(listeners, listener)
+	}
+	return listeners, nil
+}
+
+// listenUnix binds a Unix domain socket, replacing a stale socket left by a
+// previous run.
+func listenUnix(path string) (net.Listener, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, fmt.Errorf("create socket directory: %w", err)
+	}
+	if info, err := os.Lstat(path); err == nil {
+		if info.Mode()&fs.ModeSocket == 0 {
+			return nil, fmt.Errorf("api socket %s exists and is not a socket", path)
+		}
+		if conn, dialErr := net.Dial("unix", path); dialErr == nil {
+			_ = conn.Close()
+			return nil, fmt.Errorf("api socket %s is in use by another process", path)
+		}
+		if err := os.Remove(path); err != nil {
+			return nil, fmt.Errorf("remove stale api socket: %w", err)
+		}
+	} else if !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+	listener, err := net.Listen("unix", path)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.Chmod(path, apiSocketMode); err != nil {
+		_ = listener.Close()
+		return nil, fmt.Errorf("chmod api socket: %w", err)
+	}
+	return listener, nil
+}
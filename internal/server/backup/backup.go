@@ -0,0 +1,263 @@
+// Copyright (c) 2025 HYPR. PTE. LTD.
+//
+// Business Source License 1.1
+// See LICENSE file in the project root for details.
+
+// Package backup takes scheduled and on-demand snapshots of the control plane
+// database and restores them after host failures.
+package backup
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/volantvm/volant/internal/server/db"
+)
+
+const (
+	namePrefix = "volant-"
+	nameSuffix = ".db"
+	timeLayout = "20060102T150405.000Z"
+)
+
+var (
+	// ErrUnsupported indicates the configured store cannot be backed up by
+	// volantd, e.g. PostgreSQL, which has its own tooling.
+	ErrUnsupported = errors.New("backup: storage backend does not support backups")
+	// ErrNotFound indicates the named backup does not exist.
+	ErrNotFound = errors.New("backup: backup not found")
+	// ErrInvalidName indicates a backup name that volantd did not produce.
+	ErrInvalidName = errors.New("backup: invalid backup name")
+)
+
+// Options configures a Manager.
+type Options struct {
+	// Dir holds the backup files.
+	Dir string
+	// Interval between scheduled backups; zero disables them.
+	Interval time.Duration
+	// Retain is how many backups to keep; older ones are pruned after each
+	// new backup.
+	Retain int
+}
+
+// Info describes one backup file.
+type Info struct {
+	Name      string    `json:"name"`
+	Size      int64     `json:"size"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// RestoreResult reports a completed restore.
+type RestoreResult struct {
+	Restored Info `json:"restored"`
+	// Safety is the backup of the state that was replaced.
+	Safety Info `json:"safety"`
+}
+
+// Manager creates, lists, prunes and restores database backups.
+type Manager struct {
+	store  db.Store
+	opts   Options
+	logger *slog.Logger
+	now    func() time.Time
+
+	mu sync.Mutex
+}
+
+// New constructs a manager for store.
+func New(store db.Store, opts Options, logger *slog.Logger) *Manager {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	if opts.Retain < 1 {
+		opts.Retain = 1
+	}
+	return &Manager{
+		store:  store,
+		opts:   opts,
+		logger: logger.With("component", "backup"),
+		now:    time.Now,
+	}
+}
+
+// Run takes a backup every Interval until ctx is cancelled. The first one is
+// due an interval after the newest existing backup, so restarts do not skip
+// or double up on backups.
+func (m *Manager) Run(ctx context.Context) {
+	if m.opts.Interval <= 0 {
+		return
+	}
+	if _, ok := m.store.(db.Backupper); !ok {
+		m.logger.Info("scheduled backups disabled: storage backend does not support them")
+		return
+	}
+
+	wait := m.opts.Interval
+	if backups, err := m.List(); err == nil && len(backups) > 0 {
+		wait = time.Until(backups[0].CreatedAt.Add(m.opts.Interval))
+		if wait < 0 {
+			wait = 0
+		}
+	}
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-timer.C:
+		}
+		if info, err := m.Create(ctx); err != nil {
+			m.logger.Error("scheduled backup", "error", err)
+		} else {
+			m.logger.Info("scheduled backup", "name", info.Name, "size", info.Size)
+		}
+		timer.Reset(m.opts.Interval)
+	}
+}
+
+// Create takes a backup now and prunes old ones beyond the retention count.
+func (m *Manager) Create(ctx context.Context) (Info, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	info, err := m.create(ctx)
+	if err != nil {
+		return Info{}, err
+	}
+	if err := m.prune(); err != nil {
+		m.logger.Warn("prune backups", "error", err)
+	}
+	return info, nil
+}
+
+// List returns existing backups, newest first.
+func (m *Manager) List() ([]Info, error) {
+	entries, err := os.ReadDir(m.opts.Dir)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("list backups: %w", err)
+	}
+	backups := make([]Info, 0, len(entries))
+	for _, entry := range entries {
+		created, ok := parseName(entry.Name())
+		if !ok || !entry.Type().IsRegular() {
+			continue
+		}
+		fi, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		backups = append(backups, Info{Name: entry.Name(), Size: fi.Size(), CreatedAt: created})
+	}
+	sort.Slice(backups, func(i, j int) bool { return backups[i].CreatedAt.After(backups[j].CreatedAt) })
+	return backups, nil
+}
+
+// Restore replaces the database with the named backup. The current state is
+// backed up first so a mistaken restore can itself be undone. volantd should
+// be restarted afterwards so in-memory state is rebuilt from the database.
+func (m *Manager) Restore(ctx context.Context, name string) (RestoreResult, error) {
+	backupper, ok := m.store.(db.Backupper)
+	if !ok {
+		return RestoreResult{}, ErrUnsupported
+	}
+	if _, ok := parseName(name); !ok {
+		return RestoreResult{}, fmt.Errorf("%w: %q", ErrInvalidName, name)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	path := filepath.Join(m.opts.Dir, name)
+	fi, err := os.Stat(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return RestoreResult{}, fmt.Errorf("%w: %s", ErrNotFound, name)
+		}
+		return RestoreResult{}, fmt.Errorf("stat backup: %w", err)
+	}
+	created, _ := parseName(name)
+	restored := Info{Name: name, Size: fi.Size(), CreatedAt: created}
+
+	// The safety backup is not pruned here: with a full retention window the
+	// backup being restored could be the one dropped.
+	safety, err := m.create(ctx)
+	if err != nil {
+		return RestoreResult{}, fmt.Errorf("back up current state: %w", err)
+	}
+	if err := backupper.Restore(ctx, path); err != nil {
+		return RestoreResult{}, fmt.Errorf("restore %s: %w", name, err)
+	}
+	m.logger.Warn("database restored from backup", "name", name, "safety_backup", safety.Name)
+	return RestoreResult{Restored: restored, Safety: safety}, nil
+}
+
+func (m *Manager) create(ctx context.Context) (Info, error) {
+	backupper, ok := m.store.(db.Backupper)
+	if !ok {
+		return Info{}, ErrUnsupported
+	}
+	if err := os.MkdirAll(m.opts.Dir, 0o700); err != nil {
+		return Info{}, fmt.Errorf("ensure backup directory: %w", err)
+	}
+	created := m.now().UTC().Truncate(time.Millisecond)
+	name := namePrefix + created.Format(timeLayout) + nameSuffix
+	path := filepath.Join(m.opts.Dir, name)
+	if err := backupper.Backup(ctx, path); err != nil {
+		return Info{}, err
+	}
+	fi, err := os.Stat(path)
+	if err != nil {
+		return Info{}, fmt.Errorf("stat backup: %w", err)
+	}
+	return Info{Name: name, Size: fi.Size(), CreatedAt: created}, nil
+}
+
+func (m *Manager) prune() error {
+	backups, err := m.List()
+	if err != nil {
+		return err
+	}
+	var errs []error
+	for _, info := range backups[min(len(backups), m.opts.Retain):] {
+		if err := os.Remove(filepath.Join(m.opts.Dir, info.Name)); err != nil && !errors.Is(err, os.ErrNotExist) {
+			errs = append(errs, err)
+			continue
+		}
+		m.logger.Info("pruned backup", "name", info.Name)
+	}
+	return errors.Join(errs...)
+}
+
+// parseName reports whether name is a backup file produced by create and
+// returns its timestamp. Anything else, including paths, is rejected.
+func parseName(name string) (time.Time, bool) {
+	if strings.ContainsAny(name, `/\`) {
+		return time.Time{}, false
+	}
+	stamp, ok := strings.CutPrefix(name, namePrefix)
+	if !ok {
+		return time.Time{}, false
+	}
+	stamp, ok = strings.CutSuffix(stamp, nameSuffix)
+	if !ok {
+		return time.Time{}, false
+	}
+	created, err := time.Parse(timeLayout, stamp)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return created, true
+}
@@ -0,0 +1,99 @@
+// Copyright (c) 2025 HYPR. PTE. LTD.
+//
+// Business Source License 1.1
+// See LICENSE file in the project root for details.
+
+package backup
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/volantvm/volant/internal/server/db"
+	"github.com/volantvm/volant/internal/server/db/sqlite"
+)
+
+func TestCreatePrunesBeyondRetention(t *testing.T) {
+	ctx := context.Background()
+	m := newTestManager(t, 2)
+
+	var names []string
+	for i := 0; i < 3; i++ {
+		info, err := m.Create(ctx)
+		if err != nil {
+			t.Fatalf("create backup %d: %v", i, err)
+		}
+		names = append(names, info.Name)
+	}
+
+	backups, err := m.List()
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	if len(backups) != 2 || backups[0].Name != names[2] || backups[1].Name != names[1] {
+		t.Fatalf("expected the two newest backups, got %+v", backups)
+	}
+}
+
+func TestRestoreKeepsSafetyBackup(t *testing.T) {
+	ctx := context.Background()
+	m := newTestManager(t, 1)
+
+	if err := m.store.Queries().Plugins().Upsert(ctx, db.Plugin{Name: "nginx", Version: "1.0.0", Enabled: true, Metadata: []byte(`{}`)}); err != nil {
+		t.Fatalf("upsert plugin: %v", err)
+	}
+	info, err := m.Create(ctx)
+	if err != nil {
+		t.Fatalf("create backup: %v", err)
+	}
+	if err := m.store.Queries().Plugins().Delete(ctx, "nginx"); err != nil {
+		t.Fatalf("delete plugin: %v", err)
+	}
+
+	result, err := m.Restore(ctx, info.Name)
+	if err != nil {
+		t.Fatalf("restore: %v", err)
+	}
+	if result.Safety.Name == "" || result.Safety.Name == info.Name {
+		t.Fatalf("unexpected safety backup %+v", result.Safety)
+	}
+	if plugin, err := m.store.Queries().Plugins().GetByName(ctx, "nginx"); err != nil || plugin == nil {
+		t.Fatalf("expected restored plugin, got %+v %v", plugin, err)
+	}
+	if backups, _ := m.List(); len(backups) != 2 {
+		t.Fatalf("expected restored and safety backups to remain, got %+v", backups)
+	}
+}
+
+func TestRestoreRejectsUnknownNames(t *testing.T) {
+	ctx := context.Background()
+	m := newTestManager(t, 1)
+
+	if _, err := m.Restore(ctx, "../state.db"); !errors.Is(err, ErrInvalidName) {
+		t.Fatalf("expected ErrInvalidName, got %v", err)
+	}
+	if _, err := m.Restore(ctx, "volant-20250101T000000.000Z.db"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+}
+
+func newTestManager(t *testing.T, retain int) *Manager {
+	t.Helper()
+	dir := t.TempDir()
+	store, err := sqlite.Open(context.Background(), filepath.Join(dir, "state.db"))
+	if err != nil {
+		t.Fatalf("open sqlite store: %v", err)
+	}
+	t.Cleanup(func() { _ = store.Close(context.Background()) })
+
+	m := New(store, Options{Dir: filepath.Join(dir, "backups"), Retain: retain}, nil)
+	clock := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	m.now = func() time.Time {
+		clock = clock.Add(time.Hour)
+		return clock
+	}
+	return m
+}
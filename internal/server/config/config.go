@@ -6,12 +6,20 @@
 package config
 
 import (
+	"crypto/tls"
+	"crypto/x509"
 	"fmt"
 	"net"
 	"net/url"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
+	"time"
+
+	"github.com/volantvm/volant/internal/server/devicemanager"
+	"github.com/volantvm/volant/internal/server/logship"
+	"github.com/volantvm/volant/internal/server/tracing"
 )
 
 const (
@@ -25,12 +33,29 @@ const (
 	defaultLogDir        = "~/.volant/logs"
 	defaultBZImagePath   = "/var/lib/volant/kernel/bzImage"
 	defaultVMLinuxPath   = "/var/lib/volant/kernel/vmlinux"
+	defaultFirmwarePath  = "/var/lib/volant/firmware/hypervisor-fw"
 	defaultDriftEndpoint = ""
+	defaultIPAMBackend   = "internal"
+	defaultBackupDir     = "~/.volant/backups"
+	defaultBackupEvery   = 24 * time.Hour
+	defaultBackupRetain  = 7
+	defaultSecretsKey    = "~/.volant/secrets.key"
+	unixListenPrefix     = "unix://"
+
+	defaultVMLogMaxSizeMB = 10
+	defaultVMLogMaxFiles  = 5
+	defaultVMLogRetention = 7 * 24 * time.Hour
+
+	defaultAutostartConcurrency = 4
+	defaultSeedCacheSize        = 32
 )
 
 // ServerConfig captures the runtime configuration required by the daemon.
 type ServerConfig struct {
-	DatabasePath     string
+	DatabasePath string
+	// DatabaseURL selects the PostgreSQL store instead of the SQLite file at
+	// DatabasePath, so several daemons can share state.
+	DatabaseURL      string
 	APIListenAddr    string
 	APIAdvertiseAddr string
 	BridgeName       string
@@ -39,27 +64,159 @@ type ServerConfig struct {
 	VMLinuxPath      string
 	HypervisorBinary string
 	HostIP           string
-	RuntimeDir       string
-	LogDir           string
-	DriftEndpoint    string
-	DriftAPIKey      string
+	// SubnetCIDR6 and HostIP6 enable dual-stack guests when set.
+	SubnetCIDR6 string
+	HostIP6     string
+	// NDPProxyInterface is the uplink on which guest IPv6 addresses are
+	// published when the IPv6 subnet is routed rather than NATed.
+	NDPProxyInterface string
+	RuntimeDir        string
+	LogDir            string
+	DriftEndpoint     string
+	DriftAPIKey       string
+	IPAMBackend       string
+	IPAMURL           string
+	IPAMToken         string
+	IPAMPool          string
+	IPAMApp           string
+	// DHCPEnabled runs the embedded DHCP server on the bridge so dhcp-mode
+	// VMs get addresses from the subnet.
+	DHCPEnabled   bool
+	DHCPLeaseTime time.Duration
+	DHCPDNS       []string
+	// BackupDir holds SQLite snapshots. BackupInterval schedules them (zero
+	// disables) and BackupRetain caps how many scheduled ones are kept.
+	BackupDir      string
+	BackupInterval time.Duration
+	BackupRetain   int
+	// SecretsKey, when set, is the hex key sealing stored secrets.
+	// Otherwise SecretsKeyCommand prints it, or SecretsKeyFile holds it; the
+	// file is generated on first start. Daemons sharing a database must
+	// share the key.
+	SecretsKey        string
+	SecretsKeyCommand string
+	SecretsKeyFile    string
+	// GRPCListenAddr enables the gRPC API on host:port when set. It needs a
+	// volantd built with the grpc tag.
+	GRPCListenAddr string
+	// TLSListenAddr serves the API over HTTPS on host:port, next to the
+	// plain listener guests use, with the certificate and key in TLSCertFile
+	// and TLSKeyFile. With TLSClientCAFile set, clients must present a
+	// certificate it signed.
+	TLSListenAddr   string
+	TLSCertFile     string
+	TLSKeyFile      string
+	TLSClientCAFile string
+	// PassthroughDevices lists the PCI addresses volantd may hand to VMs
+	// whose manifests request devices by vendor or class.
+	PassthroughDevices []string
+	// FirmwarePath boots plugins with boot_mode firmware. It is only checked
+	// when such a VM starts.
+	FirmwarePath string
+	// ArtifactPeers are the base URLs of other volantd hosts whose cached
+	// kernels, initramfs and root images are pulled before their origin is
+	// tried, with ArtifactPeerKey as the API key.
+	ArtifactPeers   []string
+	ArtifactPeerKey string
+	// CapacityCPUPercent and CapacityMemoryPercent cap the vCPUs and memory
+	// committed to VMs as a percentage of the host totals; zero disables
+	// admission control for that resource.
+	CapacityCPUPercent    int
+	CapacityMemoryPercent int
+	// VMLogMaxSizeMB rotates a VM's persisted log once it reaches this size,
+	// VMLogMaxFiles caps the rotated files kept per VM and VMLogRetention
+	// removes logs older than it (zero keeps them until rotation drops them).
+	VMLogMaxSizeMB int
+	VMLogMaxFiles  int
+	VMLogRetention time.Duration
+	// LogExport forwards VM logs to external log systems; plugins and VMs
+	// opt in or out with the volant.logs.export labels.
+	LogExport logship.Endpoints
+	// TracingEndpoint exports spans to this OTLP/HTTP collector; empty
+	// disables tracing. TracingSampleRatio is the fraction of new traces
+	// recorded.
+	TracingEndpoint    string
+	TracingSampleRatio float64
+	// AutostartConcurrency caps how many VMs flagged autostart are started
+	// at once when volantd starts.
+	AutostartConcurrency int
+	// SeedCacheSize caps the cloud-init seed images kept for reuse; zero
+	// disables the cache.
+	SeedCacheSize int
+	// TapPoolSize is how many spare tap devices are kept on the bridge for
+	// new VMs; zero keeps none.
+	TapPoolSize int
+}
+
+// DatabaseFromEnv loads only the storage settings, for tools such as
+// `volantd migrate` that must run without kernels or network configured.
+func DatabaseFromEnv() ServerConfig {
+	return ServerConfig{
+		DatabasePath: getenv("VOLANT_DB_PATH", defaultDBPath),
+		DatabaseURL:  strings.TrimSpace(os.Getenv("VOLANT_DATABASE_URL")),
+	}
+}
+
+// HostFromEnv loads only the network, path and hypervisor settings, without
+// validating them, for `volantd setup`, which prepares a host before its
+// kernels are installed.
+func HostFromEnv() ServerConfig {
+	return ServerConfig{
+		BridgeName:        getenv("VOLANT_BRIDGE", defaultBridgeName),
+		SubnetCIDR:        getenv("VOLANT_SUBNET", defaultSubnetCIDR),
+		HostIP:            getenv("VOLANT_HOST_IP", defaultHostIP),
+		SubnetCIDR6:       strings.TrimSpace(os.Getenv("VOLANT_SUBNET6")),
+		HostIP6:           strings.TrimSpace(os.Getenv("VOLANT_HOST_IP6")),
+		NDPProxyInterface: strings.TrimSpace(os.Getenv("VOLANT_NDP_PROXY_IFACE")),
+		HypervisorBinary:  getenv("VOLANT_HYPERVISOR", "cloud-hypervisor"),
+		RuntimeDir:        getenv("VOLANT_RUNTIME_DIR", defaultRuntimeDir),
+		LogDir:            getenv("VOLANT_LOG_DIR", defaultLogDir),
+		BZImagePath:       expandPath(getenv("VOLANT_KERNEL_BZIMAGE", defaultBZImagePath)),
+		VMLinuxPath:       expandPath(getenv("VOLANT_KERNEL_VMLINUX", defaultVMLinuxPath)),
+	}
 }
 
 // FromEnv loads server configuration from environment variables, applying
 // opinionated defaults when unset.
 func FromEnv() (ServerConfig, error) {
 	cfg := ServerConfig{
-		DatabasePath:     getenv("VOLANT_DB_PATH", defaultDBPath),
-		APIListenAddr:    getenv("VOLANT_API_LISTEN", defaultAPIListenAddr),
-		APIAdvertiseAddr: getenv("VOLANT_API_ADVERTISE", ""),
-		BridgeName:       getenv("VOLANT_BRIDGE", defaultBridgeName),
-		SubnetCIDR:       getenv("VOLANT_SUBNET", defaultSubnetCIDR),
-		HostIP:           getenv("VOLANT_HOST_IP", defaultHostIP),
-		HypervisorBinary: getenv("VOLANT_HYPERVISOR", "cloud-hypervisor"),
-		RuntimeDir:       getenv("VOLANT_RUNTIME_DIR", defaultRuntimeDir),
-		LogDir:           getenv("VOLANT_LOG_DIR", defaultLogDir),
-		DriftEndpoint:    strings.TrimSpace(os.Getenv("VOLANT_DRIFT_ENDPOINT")),
-		DriftAPIKey:      strings.TrimSpace(os.Getenv("VOLANT_DRIFT_API_KEY")),
+		DatabasePath:       getenv("VOLANT_DB_PATH", defaultDBPath),
+		DatabaseURL:        strings.TrimSpace(os.Getenv("VOLANT_DATABASE_URL")),
+		APIListenAddr:      getenv("VOLANT_API_LISTEN", defaultAPIListenAddr),
+		APIAdvertiseAddr:   getenv("VOLANT_API_ADVERTISE", ""),
+		BridgeName:         getenv("VOLANT_BRIDGE", defaultBridgeName),
+		SubnetCIDR:         getenv("VOLANT_SUBNET", defaultSubnetCIDR),
+		HostIP:             getenv("VOLANT_HOST_IP", defaultHostIP),
+		SubnetCIDR6:        strings.TrimSpace(os.Getenv("VOLANT_SUBNET6")),
+		HostIP6:            strings.TrimSpace(os.Getenv("VOLANT_HOST_IP6")),
+		NDPProxyInterface:  strings.TrimSpace(os.Getenv("VOLANT_NDP_PROXY_IFACE")),
+		HypervisorBinary:   getenv("VOLANT_HYPERVISOR", "cloud-hypervisor"),
+		RuntimeDir:         getenv("VOLANT_RUNTIME_DIR", defaultRuntimeDir),
+		LogDir:             getenv("VOLANT_LOG_DIR", defaultLogDir),
+		DriftEndpoint:      strings.TrimSpace(os.Getenv("VOLANT_DRIFT_ENDPOINT")),
+		DriftAPIKey:        strings.TrimSpace(os.Getenv("VOLANT_DRIFT_API_KEY")),
+		IPAMBackend:        strings.ToLower(strings.TrimSpace(getenv("VOLANT_IPAM_BACKEND", defaultIPAMBackend))),
+		IPAMURL:            strings.TrimSpace(os.Getenv("VOLANT_IPAM_URL")),
+		IPAMToken:          strings.TrimSpace(os.Getenv("VOLANT_IPAM_TOKEN")),
+		IPAMPool:           strings.TrimSpace(os.Getenv("VOLANT_IPAM_POOL")),
+		IPAMApp:            strings.TrimSpace(os.Getenv("VOLANT_IPAM_APP")),
+		ArtifactPeerKey:    strings.TrimSpace(os.Getenv("VOLANT_ARTIFACT_PEER_KEY")),
+		GRPCListenAddr:     strings.TrimSpace(os.Getenv("VOLANT_GRPC_LISTEN")),
+		TLSListenAddr:      strings.TrimSpace(os.Getenv("VOLANT_TLS_LISTEN")),
+		TLSCertFile:        expandPath(os.Getenv("VOLANT_TLS_CERT")),
+		TLSKeyFile:         expandPath(os.Getenv("VOLANT_TLS_KEY")),
+		TLSClientCAFile:    expandPath(os.Getenv("VOLANT_TLS_CLIENT_CA")),
+		BackupDir:          expandPath(getenv("VOLANT_BACKUP_DIR", defaultBackupDir)),
+		BackupInterval:     defaultBackupEvery,
+		BackupRetain:       defaultBackupRetain,
+		SecretsKey:         os.Getenv("VOLANT_SECRETS_KEY"),
+		SecretsKeyCommand:  os.Getenv("VOLANT_SECRETS_KEY_COMMAND"),
+		SecretsKeyFile:     expandPath(getenv("VOLANT_SECRETS_KEY_FILE", defaultSecretsKey)),
+		VMLogMaxSizeMB:     defaultVMLogMaxSizeMB,
+		VMLogMaxFiles:      defaultVMLogMaxFiles,
+		VMLogRetention:     defaultVMLogRetention,
+		TracingEndpoint:    strings.TrimSpace(os.Getenv("VOLANT_TRACING_OTLP")),
+		TracingSampleRatio: 1,
 	}
 
 	if cfg.DriftEndpoint == "" {
@@ -70,6 +227,176 @@ func FromEnv() (ServerConfig, error) {
 		}
 	}
 
+	if raw := strings.TrimSpace(os.Getenv("VOLANT_DHCP")); raw != "" {
+		enabled, err := strconv.ParseBool(raw)
+		if err != nil {
+			return ServerConfig{}, fmt.Errorf("invalid VOLANT_DHCP %q: %w", raw, err)
+		}
+		cfg.DHCPEnabled = enabled
+	}
+	if raw := strings.TrimSpace(os.Getenv("VOLANT_DHCP_LEASE_TIME")); raw != "" {
+		lease, err := time.ParseDuration(raw)
+		if err != nil || lease < time.Minute {
+			return ServerConfig{}, fmt.Errorf("invalid dhcp lease time %q: must be at least 1m", raw)
+		}
+		cfg.DHCPLeaseTime = lease
+	}
+	if raw := strings.TrimSpace(os.Getenv("VOLANT_BACKUP_INTERVAL")); raw != "" {
+		interval, err := time.ParseDuration(raw)
+		if err != nil || (interval != 0 && interval < time.Minute) {
+			return ServerConfig{}, fmt.Errorf("invalid backup interval %q: must be 0 or at least 1m", raw)
+		}
+		cfg.BackupInterval = interval
+	}
+	if raw := strings.TrimSpace(os.Getenv("VOLANT_BACKUP_RETAIN")); raw != "" {
+		retain, err := strconv.Atoi(raw)
+		if err != nil || retain < 1 {
+			return ServerConfig{}, fmt.Errorf("invalid backup retention %q: must be at least 1", raw)
+		}
+		cfg.BackupRetain = retain
+	}
+	if raw := strings.TrimSpace(os.Getenv("VOLANT_CAPACITY_CPU_PERCENT")); raw != "" {
+		percent, err := strconv.Atoi(raw)
+		if err != nil || percent < 0 {
+			return ServerConfig{}, fmt.Errorf("invalid cpu capacity threshold %q: must be a percentage >= 0", raw)
+		}
+		cfg.CapacityCPUPercent = percent
+	}
+	if raw := strings.TrimSpace(os.Getenv("VOLANT_CAPACITY_MEMORY_PERCENT")); raw != "" {
+		percent, err := strconv.Atoi(raw)
+		if err != nil || percent < 0 {
+			return ServerConfig{}, fmt.Errorf("invalid memory capacity threshold %q: must be a percentage >= 0", raw)
+		}
+		cfg.CapacityMemoryPercent = percent
+	}
+	if raw := strings.TrimSpace(os.Getenv("VOLANT_VM_LOG_MAX_SIZE_MB")); raw != "" {
+		size, err := strconv.Atoi(raw)
+		if err != nil || size < 1 {
+			return ServerConfig{}, fmt.Errorf("invalid vm log max size %q: must be at least 1", raw)
+		}
+		cfg.VMLogMaxSizeMB = size
+	}
+	if raw := strings.TrimSpace(os.Getenv("VOLANT_VM_LOG_MAX_FILES")); raw != "" {
+		files, err := strconv.Atoi(raw)
+		if err != nil || files < 0 {
+			return ServerConfig{}, fmt.Errorf("invalid vm log max files %q: must be >= 0", raw)
+		}
+		cfg.VMLogMaxFiles = files
+	}
+	if raw := strings.TrimSpace(os.Getenv("VOLANT_VM_LOG_RETENTION")); raw != "" {
+		retention, err := time.ParseDuration(raw)
+		if err != nil || retention < 0 {
+			return ServerConfig{}, fmt.Errorf("invalid vm log retention %q: must be a duration >= 0", raw)
+		}
+		cfg.VMLogRetention = retention
+	}
+	cfg.AutostartConcurrency = defaultAutostartConcurrency
+	if raw := strings.TrimSpace(os.Getenv("VOLANT_AUTOSTART_CONCURRENCY")); raw != "" {
+		concurrency, err := strconv.Atoi(raw)
+		if err != nil || concurrency < 1 {
+			return ServerConfig{}, fmt.Errorf("invalid autostart concurrency %q: must be at least 1", raw)
+		}
+		cfg.AutostartConcurrency = concurrency
+	}
+	cfg.SeedCacheSize = defaultSeedCacheSize
+	if raw := strings.TrimSpace(os.Getenv("VOLANT_SEED_CACHE_SIZE")); raw != "" {
+		size, err := strconv.Atoi(raw)
+		if err != nil || size < 0 {
+			return ServerConfig{}, fmt.Errorf("invalid seed cache size %q: must be >= 0", raw)
+		}
+		cfg.SeedCacheSize = size
+	}
+	if raw := strings.TrimSpace(os.Getenv("VOLANT_TAP_POOL_SIZE")); raw != "" {
+		size, err := strconv.Atoi(raw)
+		if err != nil || size < 0 {
+			return ServerConfig{}, fmt.Errorf("invalid tap pool size %q: must be >= 0", raw)
+		}
+		cfg.TapPoolSize = size
+	}
+	cfg.LogExport = logship.Endpoints{
+		Syslog:  strings.TrimSpace(os.Getenv("VOLANT_LOG_EXPORT_SYSLOG")),
+		Loki:    strings.TrimSpace(os.Getenv("VOLANT_LOG_EXPORT_LOKI")),
+		OTLP:    strings.TrimSpace(os.Getenv("VOLANT_LOG_EXPORT_OTLP")),
+		Default: true,
+	}
+	if raw := strings.TrimSpace(os.Getenv("VOLANT_LOG_EXPORT_DEFAULT")); raw != "" {
+		enabled, err := strconv.ParseBool(raw)
+		if err != nil {
+			return ServerConfig{}, fmt.Errorf("invalid log export default %q: must be a boolean", raw)
+		}
+		cfg.LogExport.Default = enabled
+	}
+	if err := cfg.LogExport.Validate(); err != nil {
+		return ServerConfig{}, err
+	}
+	if cfg.TracingEndpoint != "" {
+		if err := tracing.ValidateEndpoint(cfg.TracingEndpoint); err != nil {
+			return ServerConfig{}, err
+		}
+	}
+	if raw := strings.TrimSpace(os.Getenv("VOLANT_TRACING_SAMPLE_RATIO")); raw != "" {
+		ratio, err := strconv.ParseFloat(raw, 64)
+		if err != nil || ratio < 0 || ratio > 1 {
+			return ServerConfig{}, fmt.Errorf("invalid tracing sample ratio %q: must be between 0 and 1", raw)
+		}
+		cfg.TracingSampleRatio = ratio
+	}
+	for _, entry := range strings.Split(os.Getenv("VOLANT_DHCP_DNS"), ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		if ip := net.ParseIP(entry); ip == nil || ip.To4() == nil {
+			return ServerConfig{}, fmt.Errorf("invalid dhcp dns server %q", entry)
+		}
+		cfg.DHCPDNS = append(cfg.DHCPDNS, entry)
+	}
+	for _, entry := range strings.Split(os.Getenv("VOLANT_PASSTHROUGH_DEVICES"), ",") {
+		entry = strings.ToLower(strings.TrimSpace(entry))
+		if entry == "" {
+			continue
+		}
+		if !devicemanager.IsValidPCIAddress(entry) {
+			return ServerConfig{}, fmt.Errorf("invalid passthrough device %q: expected a pci address like 0000:01:00.0", entry)
+		}
+		cfg.PassthroughDevices = append(cfg.PassthroughDevices, entry)
+	}
+	for _, entry := range strings.Split(os.Getenv("VOLANT_ARTIFACT_PEERS"), ",") {
+		entry = strings.TrimRight(strings.TrimSpace(entry), "/")
+		if entry == "" {
+			continue
+		}
+		if err := checkPeerURL(entry); err != nil {
+			return ServerConfig{}, err
+		}
+		cfg.ArtifactPeers = append(cfg.ArtifactPeers, entry)
+	}
+
+	if cfg.GRPCListenAddr != "" {
+		if _, _, err := net.SplitHostPort(cfg.GRPCListenAddr); err != nil {
+			return ServerConfig{}, fmt.Errorf("invalid grpc listen address %q: %w", cfg.GRPCListenAddr, err)
+		}
+	}
+
+	if cfg.TLSListenAddr != "" || cfg.TLSCertFile != "" || cfg.TLSKeyFile != "" || cfg.TLSClientCAFile != "" {
+		if _, _, err := net.SplitHostPort(cfg.TLSListenAddr); err != nil {
+			return ServerConfig{}, fmt.Errorf("invalid tls listen address %q: %w", cfg.TLSListenAddr, err)
+		}
+		if _, err := cfg.TLSConfig(); err != nil {
+			return ServerConfig{}, err
+		}
+	}
+
+	switch cfg.IPAMBackend {
+	case "internal":
+	case "webhook", "netbox", "phpipam":
+		if _, err := url.ParseRequestURI(cfg.IPAMURL); err != nil {
+			return ServerConfig{}, fmt.Errorf("invalid ipam url %q: %w", cfg.IPAMURL, err)
+		}
+	default:
+		return ServerConfig{}, fmt.Errorf("unknown ipam backend %q", cfg.IPAMBackend)
+	}
+
 	// New dual-kernel config
 	bz := strings.TrimSpace(os.Getenv("VOLANT_KERNEL_BZIMAGE"))
 	vm := strings.TrimSpace(os.Getenv("VOLANT_KERNEL_VMLINUX"))
@@ -89,6 +416,7 @@ func FromEnv() (ServerConfig, error) {
 	}
 	cfg.BZImagePath = bz
 	cfg.VMLinuxPath = vm
+	cfg.FirmwarePath = expandPath(getenv("VOLANT_FIRMWARE", defaultFirmwarePath))
 
 	if _, _, err := net.ParseCIDR(cfg.SubnetCIDR); err != nil {
 		return ServerConfig{}, fmt.Errorf("invalid subnet cidr %q: %w", cfg.SubnetCIDR, err)
@@ -98,10 +426,32 @@ func FromEnv() (ServerConfig, error) {
 		return ServerConfig{}, fmt.Errorf("invalid host ip %q", cfg.HostIP)
 	}
 
+	if cfg.SubnetCIDR6 != "" || cfg.HostIP6 != "" {
+		_, subnet6, err := net.ParseCIDR(cfg.SubnetCIDR6)
+		if err != nil || subnet6.IP.To4() != nil {
+			return ServerConfig{}, fmt.Errorf("invalid ipv6 subnet cidr %q", cfg.SubnetCIDR6)
+		}
+		hostIP6 := net.ParseIP(cfg.HostIP6)
+		if hostIP6 == nil || !subnet6.Contains(hostIP6) {
+			return ServerConfig{}, fmt.Errorf("invalid ipv6 host ip %q for subnet %s", cfg.HostIP6, cfg.SubnetCIDR6)
+		}
+	}
+
 	listenAddr := strings.TrimSpace(cfg.APIListenAddr)
 	if listenAddr == "" {
 		return ServerConfig{}, fmt.Errorf("api listen address required")
 	}
+	if socketPath, ok := cfg.UnixSocketPath(); ok {
+		if !filepath.IsAbs(socketPath) {
+			return ServerConfig{}, fmt.Errorf("invalid api listen address %q: socket path must be absolute", listenAddr)
+		}
+		// Guests cannot reach a Unix socket; they keep using the bridge
+		// address unless an advertise address is configured explicitly.
+		if strings.TrimSpace(cfg.APIAdvertiseAddr) == "" {
+			cfg.APIAdvertiseAddr = net.JoinHostPort(cfg.HostIP, defaultAPIPort)
+		}
+		return cfg, nil
+	}
 	listenHost, listenPort, err := net.SplitHostPort(listenAddr)
 	if err != nil {
 		return ServerConfig{}, fmt.Errorf("invalid api listen address %q: %w", listenAddr, err)
@@ -121,6 +471,45 @@ func FromEnv() (ServerConfig, error) {
 	return cfg, nil
 }
 
+// UnixSocketPath returns the socket path when the API listens on a Unix
+// domain socket (VOLANT_API_LISTEN=unix:///run/volant.sock).
+func (c ServerConfig) UnixSocketPath() (string, bool) {
+	path, ok := strings.CutPrefix(strings.TrimSpace(c.APIListenAddr), unixListenPrefix)
+	if !ok {
+		return "", false
+	}
+	return filepath.Clean(path), true
+}
+
+// TLSConfig loads the certificates of the HTTPS listener.
+func (c ServerConfig) TLSConfig() (*tls.Config, error) {
+	if c.TLSCertFile == "" || c.TLSKeyFile == "" {
+		return nil, fmt.Errorf("tls needs both VOLANT_TLS_CERT and VOLANT_TLS_KEY")
+	}
+	cert, err := tls.LoadX509KeyPair(c.TLSCertFile, c.TLSKeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("load tls certificate: %w", err)
+	}
+	tlsConfig := &tls.Config{
+		MinVersion:   tls.VersionTLS12,
+		Certificates: []tls.Certificate{cert},
+		NextProtos:   []string{"http/1.1"},
+	}
+	if c.TLSClientCAFile != "" {
+		pem, err := os.ReadFile(c.TLSClientCAFile)
+		if err != nil {
+			return nil, fmt.Errorf("read tls client ca: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("tls client ca %s holds no PEM certificates", c.TLSClientCAFile)
+		}
+		tlsConfig.ClientCAs = pool
+		tlsConfig.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+	return tlsConfig, nil
+}
+
 func isRoutableAdvertiseHost(host string) bool {
 	if host == "" {
 		return false
@@ -0,0 +1,494 @@
+// Copyright (c) 2025 HYPR. PTE. LTD.
+//
+// Business Source License 1.1
+// See LICENSE file in the project root for details.
+
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pelletier/go-toml/v2"
+	"gopkg.in/yaml.v3"
+
+	"github.com/volantvm/volant/internal/shared/logging"
+)
+
+type valueKind int
+
+const (
+	kindString valueKind = iota
+	kindBool
+	kindInt
+	kindFloat
+	kindDuration
+	kindList
+)
+
+// fileSetting maps a dotted key of a YAML or TOML config file onto the
+// environment variable FromEnv and the other readers use.
+type fileSetting struct {
+	key   string
+	env   string
+	kind  valueKind
+	check func(string) error
+}
+
+// fileSettings lists every setting a config file may hold.
+var fileSettings = []fileSetting{
+	{key: "api.listen", env: "VOLANT_API_LISTEN"},
+	{key: "api.advertise", env: "VOLANT_API_ADVERTISE"},
+	{key: "api.key", env: "VOLANT_API_KEY"},
+	{key: "api.allow_cidr", env: "VOLANT_API_ALLOW_CIDR", kind: kindList, check: checkCIDR},
+	{key: "api.cors_origins", env: "VOLANT_CORS_ORIGINS", kind: kindList},
+	{key: "api.grpc_listen", env: "VOLANT_GRPC_LISTEN"},
+	{key: "api.tls.listen", env: "VOLANT_TLS_LISTEN"},
+	{key: "api.tls.cert", env: "VOLANT_TLS_CERT"},
+	{key: "api.tls.key", env: "VOLANT_TLS_KEY"},
+	{key: "api.tls.client_ca", env: "VOLANT_TLS_CLIENT_CA"},
+	{key: "api.admission_config", env: "VOLANT_ADMISSION_CONFIG"},
+
+	{key: "database.path", env: "VOLANT_DB_PATH"},
+	{key: "database.url", env: "VOLANT_DATABASE_URL"},
+
+	{key: "network.bridge", env: "VOLANT_BRIDGE"},
+	{key: "network.subnet", env: "VOLANT_SUBNET", check: checkCIDR},
+	{key: "network.host_ip", env: "VOLANT_HOST_IP", check: checkIP},
+	{key: "network.subnet6", env: "VOLANT_SUBNET6", check: checkCIDR},
+	{key: "network.host_ip6", env: "VOLANT_HOST_IP6", check: checkIP},
+	{key: "network.ndp_proxy_interface", env: "VOLANT_NDP_PROXY_IFACE"},
+	{key: "network.tap_pool_size", env: "VOLANT_TAP_POOL_SIZE", kind: kindInt},
+
+	{key: "dhcp.enabled", env: "VOLANT_DHCP", kind: kindBool},
+	{key: "dhcp.lease_time", env: "VOLANT_DHCP_LEASE_TIME", kind: kindDuration},
+	{key: "dhcp.dns", env: "VOLANT_DHCP_DNS", kind: kindList, check: checkIP},
+
+	{key: "ipam.backend", env: "VOLANT_IPAM_BACKEND"},
+	{key: "ipam.url", env: "VOLANT_IPAM_URL"},
+	{key: "ipam.token", env: "VOLANT_IPAM_TOKEN"},
+	{key: "ipam.pool", env: "VOLANT_IPAM_POOL"},
+	{key: "ipam.app", env: "VOLANT_IPAM_APP"},
+
+	{key: "hypervisor.binary", env: "VOLANT_HYPERVISOR"},
+	{key: "hypervisor.bzimage", env: "VOLANT_KERNEL_BZIMAGE"},
+	{key: "hypervisor.vmlinux", env: "VOLANT_KERNEL_VMLINUX"},
+	{key: "hypervisor.firmware", env: "VOLANT_FIRMWARE"},
+	{key: "hypervisor.passthrough_devices", env: "VOLANT_PASSTHROUGH_DEVICES", kind: kindList},
+	{key: "hypervisor.artifact_peers", env: "VOLANT_ARTIFACT_PEERS", kind: kindList, check: checkPeerURL},
+	{key: "hypervisor.artifact_peer_key", env: "VOLANT_ARTIFACT_PEER_KEY"},
+
+	{key: "paths.runtime_dir", env: "VOLANT_RUNTIME_DIR"},
+	{key: "paths.log_dir", env: "VOLANT_LOG_DIR"},
+	{key: "paths.agent_dir", env: "VOLANT_AGENT_DIR"},
+
+	{key: "limits.cpu_percent", env: "VOLANT_CAPACITY_CPU_PERCENT", kind: kindInt},
+	{key: "limits.memory_percent", env: "VOLANT_CAPACITY_MEMORY_PERCENT", kind: kindInt},
+	{key: "limits.file_max_size_mb", env: "VOLANT_FILE_MAX_SIZE_MB", kind: kindInt},
+	{key: "limits.guest_metrics_interval", env: "VOLANT_GUEST_METRICS_INTERVAL", kind: kindDuration},
+	{key: "limits.autostart_concurrency", env: "VOLANT_AUTOSTART_CONCURRENCY", kind: kindInt},
+	{key: "limits.seed_cache_size", env: "VOLANT_SEED_CACHE_SIZE", kind: kindInt},
+
+	{key: "backups.dir", env: "VOLANT_BACKUP_DIR"},
+	{key: "backups.interval", env: "VOLANT_BACKUP_INTERVAL", kind: kindDuration},
+	{key: "backups.retain", env: "VOLANT_BACKUP_RETAIN", kind: kindInt},
+
+	{key: "secrets.key", env: "VOLANT_SECRETS_KEY"},
+	{key: "secrets.key_command", env: "VOLANT_SECRETS_KEY_COMMAND"},
+	{key: "secrets.key_file", env: "VOLANT_SECRETS_KEY_FILE"},
+
+	{key: "console.disabled", env: "VOLANT_CONSOLE_DISABLED", kind: kindBool},
+	{key: "console.keys", env: "VOLANT_CONSOLE_KEYS", kind: kindList, check: checkConsoleKey},
+
+	{key: "logs.level", env: "VOLANT_LOG_LEVEL", check: checkLevel},
+	{key: "logs.vm_max_size_mb", env: "VOLANT_VM_LOG_MAX_SIZE_MB", kind: kindInt},
+	{key: "logs.vm_max_files", env: "VOLANT_VM_LOG_MAX_FILES", kind: kindInt},
+	{key: "logs.vm_retention", env: "VOLANT_VM_LOG_RETENTION", kind: kindDuration},
+	{key: "logs.export.syslog", env: "VOLANT_LOG_EXPORT_SYSLOG"},
+	{key: "logs.export.loki", env: "VOLANT_LOG_EXPORT_LOKI"},
+	{key: "logs.export.otlp", env: "VOLANT_LOG_EXPORT_OTLP"},
+	{key: "logs.export.default", env: "VOLANT_LOG_EXPORT_DEFAULT", kind: kindBool},
+
+	{key: "tracing.otlp", env: "VOLANT_TRACING_OTLP"},
+	{key: "tracing.sample_ratio", env: "VOLANT_TRACING_SAMPLE_RATIO", kind: kindFloat},
+
+	{key: "drift.endpoint", env: "VOLANT_DRIFT_ENDPOINT"},
+	{key: "drift.api_key", env: "VOLANT_DRIFT_API_KEY"},
+}
+
+// FileError lists every problem found in a config file, so all of them can
+// be fixed in one pass.
+type FileError struct {
+	Path     string
+	Problems []string
+}
+
+func (e *FileError) Error() string {
+	return fmt.Sprintf("config: %s: %s", e.Path, strings.Join(e.Problems, "; "))
+}
+
+// ReadFile reads a config file into environment settings. Files ending in
+// .yaml, .yml or .toml hold the structured settings of fileSettings and are
+// validated strictly; any other file holds KEY=VALUE lines.
+func ReadFile(path string) (map[string]string, error) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		return readStructuredFile(path, decodeYAML)
+	case ".toml":
+		return readStructuredFile(path, decodeTOML)
+	default:
+		return readEnvFile(path)
+	}
+}
+
+// fileEntry is a setting found in a config file, or a malformed section
+// when err is set. line is zero when the format does not report positions.
+type fileEntry struct {
+	key   string
+	line  int
+	value any
+	err   string
+}
+
+func readStructuredFile(path string, decode func([]byte, *[]string) []fileEntry) (map[string]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("config: read %s: %w", path, err)
+	}
+	var problems []string
+	entries := decode(data, &problems)
+
+	settings := make(map[string]fileSetting, len(fileSettings))
+	for _, setting := range fileSettings {
+		settings[setting.key] = setting
+	}
+	values := make(map[string]string, len(entries))
+	for _, entry := range entries {
+		if entry.err != "" {
+			problems = append(problems, entry.problem(entry.err))
+			continue
+		}
+		setting, ok := settings[entry.key]
+		if !ok {
+			problems = append(problems, entry.problem(unknownSetting(entry.key)))
+			continue
+		}
+		value, err := setting.envValue(entry.value)
+		if err != nil {
+			problems = append(problems, entry.problem(err.Error()))
+			continue
+		}
+		values[setting.env] = value
+	}
+	if len(problems) > 0 {
+		return nil, &FileError{Path: path, Problems: problems}
+	}
+	return values, nil
+}
+
+func (e fileEntry) problem(msg string) string {
+	if e.line > 0 {
+		return fmt.Sprintf("line %d: %s: %s", e.line, e.key, msg)
+	}
+	return fmt.Sprintf("%s: %s", e.key, msg)
+}
+
+func decodeYAML(data []byte, problems *[]string) []fileEntry {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		*problems = append(*problems, strings.TrimPrefix(err.Error(), "yaml: "))
+		return nil
+	}
+	if len(doc.Content) == 0 {
+		return nil
+	}
+	var entries []fileEntry
+	flattenYAML(doc.Content[0], "", &entries)
+	return entries
+}
+
+func flattenYAML(node *yaml.Node, prefix string, entries *[]fileEntry) {
+	if node.Kind != yaml.MappingNode {
+		*entries = append(*entries, fileEntry{key: sectionName(prefix), line: node.Line, err: "want a mapping of settings"})
+		return
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		keyNode, valueNode := node.Content[i], node.Content[i+1]
+		key := joinKey(prefix, keyNode.Value)
+		if isSection(key) {
+			flattenYAML(valueNode, key, entries)
+			continue
+		}
+		entry := fileEntry{key: key, line: keyNode.Line}
+		if err := valueNode.Decode(&entry.value); err != nil {
+			entry.err = err.Error()
+		}
+		*entries = append(*entries, entry)
+	}
+}
+
+func decodeTOML(data []byte, problems *[]string) []fileEntry {
+	var doc map[string]any
+	if err := toml.Unmarshal(data, &doc); err != nil {
+		var decodeErr *toml.DecodeError
+		if errors.As(err, &decodeErr) {
+			row, _ := decodeErr.Position()
+			*problems = append(*problems, fmt.Sprintf("line %d: %s", row, strings.TrimPrefix(decodeErr.Error(), "toml: ")))
+		} else {
+			*problems = append(*problems, err.Error())
+		}
+		return nil
+	}
+	var entries []fileEntry
+	flattenTOML(doc, "", &entries)
+	return entries
+}
+
+func flattenTOML(table map[string]any, prefix string, entries *[]fileEntry) {
+	keys := make([]string, 0, len(table))
+	for key := range table {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, name := range keys {
+		key := joinKey(prefix, name)
+		value := table[name]
+		if !isSection(key) {
+			*entries = append(*entries, fileEntry{key: key, value: value})
+			continue
+		}
+		section, ok := value.(map[string]any)
+		if !ok {
+			*entries = append(*entries, fileEntry{key: key, err: "want a table of settings"})
+			continue
+		}
+		flattenTOML(section, key, entries)
+	}
+}
+
+// envValue checks a file value against the kind of the setting and renders
+// it the way the environment variable is written.
+func (s fileSetting) envValue(value any) (string, error) {
+	var out string
+	switch s.kind {
+	case kindString:
+		str, ok := value.(string)
+		if !ok {
+			switch value.(type) {
+			case bool, int, int64, uint64, float64:
+				return "", fmt.Errorf("want a string, got %s (quote it)", describe(value))
+			}
+			return "", fmt.Errorf("want a string, got %s", describe(value))
+		}
+		out = str
+	case kindBool:
+		b, ok := value.(bool)
+		if !ok {
+			return "", fmt.Errorf("want true or false, got %s", describe(value))
+		}
+		out = strconv.FormatBool(b)
+	case kindInt:
+		n, ok := asInt(value)
+		if !ok {
+			return "", fmt.Errorf("want a whole number, got %s", describe(value))
+		}
+		out = strconv.FormatInt(n, 10)
+	case kindFloat:
+		if n, ok := asInt(value); ok {
+			out = strconv.FormatInt(n, 10)
+		} else if f, ok := value.(float64); ok {
+			out = strconv.FormatFloat(f, 'g', -1, 64)
+		} else {
+			return "", fmt.Errorf("want a number, got %s", describe(value))
+		}
+	case kindDuration:
+		if n, ok := asInt(value); ok && n == 0 {
+			return "0", nil
+		}
+		str, ok := value.(string)
+		if !ok {
+			return "", fmt.Errorf("want a duration such as \"30s\" or \"24h\", got %s", describe(value))
+		}
+		if _, err := time.ParseDuration(strings.TrimSpace(str)); err != nil {
+			return "", fmt.Errorf("invalid duration %q (want a duration such as \"30s\" or \"24h\")", str)
+		}
+		out = str
+	case kindList:
+		items, ok := value.([]any)
+		if !ok {
+			return "", fmt.Errorf("want a list of strings, got %s", describe(value))
+		}
+		parts := make([]string, 0, len(items))
+		for i, item := range items {
+			str, ok := item.(string)
+			if !ok {
+				return "", fmt.Errorf("item %d: want a string, got %s", i+1, describe(item))
+			}
+			if strings.Contains(str, ",") {
+				return "", fmt.Errorf("item %d: %q must not contain a comma", i+1, str)
+			}
+			if s.check != nil {
+				if err := s.check(strings.TrimSpace(str)); err != nil {
+					return "", fmt.Errorf("item %d: %w", i+1, err)
+				}
+			}
+			parts = append(parts, str)
+		}
+		return strings.Join(parts, ","), nil
+	}
+	if s.check != nil {
+		if err := s.check(strings.TrimSpace(out)); err != nil {
+			return "", err
+		}
+	}
+	return out, nil
+}
+
+func asInt(value any) (int64, bool) {
+	switch n := value.(type) {
+	case int:
+		return int64(n), true
+	case int64:
+		return n, true
+	case uint64:
+		if n <= 1<<63-1 {
+			return int64(n), true
+		}
+	}
+	return 0, false
+}
+
+func describe(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return "no value"
+	case string:
+		return fmt.Sprintf("%q", v)
+	case bool:
+		return strconv.FormatBool(v)
+	case int, int64, uint64, float64:
+		return fmt.Sprintf("the number %v", v)
+	case []any:
+		return "a list"
+	case map[string]any:
+		return "a mapping"
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}
+
+// unknownSetting explains an unknown key, suggesting the setting that was
+// most likely meant.
+func unknownSetting(key string) string {
+	for _, setting := range fileSettings {
+		if strings.EqualFold(key, setting.env) || strings.HasSuffix(key, "."+setting.env) {
+			return fmt.Sprintf("unknown setting (environment variable %s is written as %s)", setting.env, setting.key)
+		}
+	}
+	best, bestDistance := "", 3
+	leaf := key[strings.LastIndex(key, ".")+1:]
+	for _, setting := range fileSettings {
+		if d := editDistance(key, setting.key); d < bestDistance {
+			best, bestDistance = setting.key, d
+		}
+	}
+	if best == "" {
+		for _, setting := range fileSettings {
+			if strings.HasSuffix(setting.key, "."+leaf) {
+				best = setting.key
+				break
+			}
+		}
+	}
+	if best != "" {
+		return fmt.Sprintf("unknown setting (did you mean %s?)", best)
+	}
+	return "unknown setting"
+}
+
+// isSection reports whether key is a group of settings rather than one.
+func isSection(key string) bool {
+	for _, setting := range fileSettings {
+		if strings.HasPrefix(setting.key, key+".") {
+			return true
+		}
+	}
+	return false
+}
+
+func joinKey(prefix, name string) string {
+	if prefix == "" {
+		return name
+	}
+	return prefix + "." + name
+}
+
+func sectionName(prefix string) string {
+	if prefix == "" {
+		return "top level"
+	}
+	return prefix
+}
+
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur := make([]int, len(b)+1)
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev = cur
+	}
+	return prev[len(b)]
+}
+
+func checkCIDR(value string) error {
+	if _, _, err := net.ParseCIDR(value); err != nil {
+		return fmt.Errorf("invalid CIDR %q (want a prefix such as 10.0.0.0/8)", value)
+	}
+	return nil
+}
+
+func checkPeerURL(value string) error {
+	u, err := url.Parse(value)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("invalid artifact peer %q (want a volantd base URL such as http://10.0.0.2:8080)", value)
+	}
+	return nil
+}
+
+func checkIP(value string) error {
+	if net.ParseIP(value) == nil {
+		return fmt.Errorf("invalid IP address %q", value)
+	}
+	return nil
+}
+
+func checkConsoleKey(value string) error {
+	user, key, ok := strings.Cut(value, ":")
+	if !ok || strings.TrimSpace(user) == "" || strings.TrimSpace(key) == "" {
+		return fmt.Errorf("want user:key, got %q", value)
+	}
+	return nil
+}
+
+func checkLevel(value string) error {
+	_, err := logging.ParseLevel(value)
+	return err
+}
@@ -0,0 +1,264 @@
+// Copyright (c) 2025 HYPR. PTE. LTD.
+//
+// Business Source License 1.1
+// See LICENSE file in the project root for details.
+
+package config
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"log/slog"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/volantvm/volant/internal/shared/logging"
+)
+
+// EnvFileKey names the variable pointing at an optional config file, read
+// at startup and again on every reload (see ReadFile for its formats). Its
+// values take precedence over the environment volantd was started with.
+const EnvFileKey = "VOLANT_CONFIG_FILE"
+
+// reloadableKeys are the settings a reload applies to the running daemon;
+// every other setting is only read at startup.
+var reloadableKeys = []string{
+	"VOLANT_API_KEY",
+	"VOLANT_API_ALLOW_CIDR",
+	"VOLANT_CORS_ORIGINS",
+	"VOLANT_LOG_LEVEL",
+}
+
+// Reloadable holds the settings the running daemon picks up on reload.
+type Reloadable struct {
+	APIKey      string
+	AllowCIDRs  []string
+	CORSOrigins []string
+	LogLevel    slog.Level
+}
+
+// ReloadableFromEnv reads the reloadable settings from the environment. An
+// invalid log level is reported, but the other settings are still filled
+// in and the level is left at info.
+func ReloadableFromEnv() (Reloadable, error) {
+	settings := Reloadable{
+		APIKey:      strings.TrimSpace(os.Getenv("VOLANT_API_KEY")),
+		AllowCIDRs:  splitList(os.Getenv("VOLANT_API_ALLOW_CIDR")),
+		CORSOrigins: splitList(os.Getenv("VOLANT_CORS_ORIGINS")),
+		LogLevel:    slog.LevelInfo,
+	}
+	level, err := logging.ParseLevel(os.Getenv("VOLANT_LOG_LEVEL"))
+	if err != nil {
+		return settings, fmt.Errorf("VOLANT_LOG_LEVEL: %w", err)
+	}
+	settings.LogLevel = level
+	return settings, nil
+}
+
+// ReloadResult reports what a reload changed. Only setting names are
+// listed, never values, since some hold credentials.
+type ReloadResult struct {
+	ReloadedAt time.Time `json:"reloaded_at"`
+	File       string    `json:"file,omitempty"`
+	// Applied lists the changed settings the daemon now uses.
+	Applied []string `json:"applied"`
+	// RestartRequired lists the changed settings that only take effect
+	// when volantd restarts.
+	RestartRequired []string `json:"restart_required"`
+}
+
+// Reloader applies the config file to the environment and hands the
+// reloadable settings to its subscribers, at startup and on Reload.
+type Reloader struct {
+	path string
+
+	mu        sync.Mutex
+	inherited map[string]string
+	fileKeys  []string
+	current   Reloadable
+	subs      []func(Reloadable)
+}
+
+// NewReloader loads the config file at path, or the one named by
+// VOLANT_CONFIG_FILE when path is empty, into the environment. Call it
+// before reading any other configuration.
+func NewReloader(path string) (*Reloader, error) {
+	if strings.TrimSpace(path) == "" {
+		path = os.Getenv(EnvFileKey)
+	}
+	r := &Reloader{
+		path:      expandPath(path),
+		inherited: environ(),
+	}
+	if _, err := r.load(); err != nil {
+		return nil, err
+	}
+	current, err := ReloadableFromEnv()
+	if err != nil {
+		return nil, err
+	}
+	r.current = current
+	return r, nil
+}
+
+// Subscribe calls fn with the current settings and again after every
+// reload.
+func (r *Reloader) Subscribe(fn func(Reloadable)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.subs = append(r.subs, fn)
+	fn(r.current)
+}
+
+// Reload reads the config file again and applies the reloadable settings.
+// An invalid file leaves the environment and the running settings as they
+// were.
+func (r *Reloader) Reload() (ReloadResult, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	before := environ()
+	previousKeys := r.fileKeys
+	changed, err := r.load()
+	if err != nil {
+		return ReloadResult{}, err
+	}
+	current, err := ReloadableFromEnv()
+	if err != nil {
+		r.fileKeys = previousKeys
+		restoreEnv(before, changed)
+		return ReloadResult{}, err
+	}
+
+	result := ReloadResult{ReloadedAt: time.Now().UTC(), File: r.path, Applied: []string{}, RestartRequired: []string{}}
+	for _, key := range changed {
+		if slices.Contains(reloadableKeys, key) {
+			result.Applied = append(result.Applied, key)
+		} else {
+			result.RestartRequired = append(result.RestartRequired, key)
+		}
+	}
+	r.current = current
+	for _, fn := range r.subs {
+		fn(current)
+	}
+	return result, nil
+}
+
+// load overlays the config file on the inherited environment, restores
+// inherited values of keys the file no longer sets, and returns the keys
+// whose values changed.
+func (r *Reloader) load() ([]string, error) {
+	if r.path == "" {
+		return nil, nil
+	}
+	values, err := ReadFile(r.path)
+	if err != nil {
+		return nil, err
+	}
+
+	desired := make(map[string]*string)
+	for _, key := range r.fileKeys {
+		if value, ok := r.inherited[key]; ok {
+			desired[key] = &value
+		} else {
+			desired[key] = nil
+		}
+	}
+	keys := make([]string, 0, len(values))
+	for key, value := range values {
+		desired[key] = &value
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var changed []string
+	for key, value := range desired {
+		old, had := os.LookupEnv(key)
+		switch {
+		case value == nil && had:
+			if err := os.Unsetenv(key); err != nil {
+				return nil, fmt.Errorf("config: unset %s: %w", key, err)
+			}
+		case value != nil && (!had || old != *value):
+			if err := os.Setenv(key, *value); err != nil {
+				return nil, fmt.Errorf("config: set %s: %w", key, err)
+			}
+		default:
+			continue
+		}
+		changed = append(changed, key)
+	}
+	sort.Strings(changed)
+	r.fileKeys = keys
+	return changed, nil
+}
+
+// readEnvFile parses KEY=VALUE lines. Blank lines and lines starting with #
+// are skipped, an "export " prefix is allowed and values may be quoted.
+func readEnvFile(path string) (map[string]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("config: read %s: %w", path, err)
+	}
+	values := make(map[string]string)
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		line = strings.TrimPrefix(line, "export ")
+		key, value, ok := strings.Cut(line, "=")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" || strings.ContainsAny(key, " \t") {
+			return nil, fmt.Errorf("config: %s line %d: want KEY=VALUE", path, n)
+		}
+		value = strings.TrimSpace(value)
+		if len(value) >= 2 && (value[0] == '"' || value[0] == '\'') && value[len(value)-1] == value[0] {
+			value = value[1 : len(value)-1]
+		}
+		values[key] = value
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("config: read %s: %w", path, err)
+	}
+	return values, nil
+}
+
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+func environ() map[string]string {
+	env := make(map[string]string)
+	for _, entry := range os.Environ() {
+		if key, value, ok := strings.Cut(entry, "="); ok {
+			env[key] = value
+		}
+	}
+	return env
+}
+
+// restoreEnv puts back the snapshot values of the keys a failed reload
+// changed.
+func restoreEnv(snapshot map[string]string, keys []string) {
+	for _, key := range keys {
+		if value, ok := snapshot[key]; ok {
+			_ = os.Setenv(key, value)
+		} else {
+			_ = os.Unsetenv(key)
+		}
+	}
+}
@@ -0,0 +1,297 @@
+// Copyright (c) 2025 HYPR. PTE. LTD.
+//
+// Business Source License 1.1
+// See LICENSE file in the project root for details.
+
+// Package migrate applies and rolls back the versioned SQL migrations shared
+// by the SQLite and PostgreSQL stores.
+//
+// Migrations are embedded files named NNNN_name.sql (up) with an optional
+// NNNN_name.down.sql that reverses them. Applied versions are recorded in the
+// schema_migrations table.
+package migrate
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const downSuffix = ".down.sql"
+
+var (
+	// ErrSchemaTooNew indicates the database has migrations applied that this
+	// build does not know about, typically after a downgrade.
+	ErrSchemaTooNew = errors.New("migrate: database schema is newer than this build")
+	// ErrNoDown indicates a migration cannot be rolled back.
+	ErrNoDown = errors.New("migrate: migration has no down script")
+)
+
+// Migration is one versioned schema change.
+type Migration struct {
+	Version int
+	Name    string
+	Up      string
+	Down    string
+}
+
+// Status reports whether a migration has been applied.
+type Status struct {
+	Migration
+	Applied   bool
+	AppliedAt time.Time
+}
+
+// Dialect adapts the migrator to a database engine.
+type Dialect struct {
+	// Placeholder returns the bind parameter for the n-th (1-based) argument.
+	Placeholder func(n int) string
+	// CreateTable creates schema_migrations if it does not exist.
+	CreateTable string
+	// Acquire prepares the migration connection, e.g. takes a lock, and
+	// returns a function that undoes it once migrations finish.
+	Acquire func(ctx context.Context, conn *sql.Conn) (release func(context.Context) error, err error)
+}
+
+// Load reads migrations from dir in fsys, ordered by version.
+func Load(fsys fs.FS, dir string) ([]Migration, error) {
+	entries, err := fs.Glob(fsys, path.Join(dir, "*.sql"))
+	if err != nil {
+		return nil, fmt.Errorf("list migrations: %w", err)
+	}
+
+	byVersion := make(map[int]*Migration)
+	for _, file := range entries {
+		base := path.Base(file)
+		down := strings.HasSuffix(base, downSuffix)
+		stem := strings.TrimSuffix(strings.TrimSuffix(base, downSuffix), ".sql")
+		prefix, name, ok := strings.Cut(stem, "_")
+		if !ok {
+			return nil, fmt.Errorf("invalid migration filename: %s", base)
+		}
+		version, err := strconv.Atoi(prefix)
+		if err != nil {
+			return nil, fmt.Errorf("parse version for %s: %w", base, err)
+		}
+		content, err := fs.ReadFile(fsys, file)
+		if err != nil {
+			return nil, fmt.Errorf("read migration %s: %w", file, err)
+		}
+
+		m := byVersion[version]
+		if m == nil {
+			m = &Migration{Version: version, Name: name}
+			byVersion[version] = m
+		} else if m.Name != name {
+			return nil, fmt.Errorf("migration %d has conflicting names %q and %q", version, m.Name, name)
+		}
+		if down {
+			m.Down = string(content)
+		} else {
+			m.Up = string(content)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if m.Up == "" {
+			return nil, fmt.Errorf("migration %d (%s) has a down script but no up script", m.Version, m.Name)
+		}
+		migrations = append(migrations, *m)
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+	return migrations, nil
+}
+
+// Migrator applies migrations to one database.
+type Migrator struct {
+	db         *sql.DB
+	dialect    Dialect
+	migrations []Migration
+}
+
+// New returns a migrator for db. migrations must be ordered by version, as
+// returned by Load.
+func New(db *sql.DB, dialect Dialect, migrations []Migration) *Migrator {
+	return &Migrator{db: db, dialect: dialect, migrations: migrations}
+}
+
+// Latest returns the highest version known to this build.
+func (m *Migrator) Latest() int {
+	if len(m.migrations) == 0 {
+		return 0
+	}
+	return m.migrations[len(m.migrations)-1].Version
+}
+
+// Status lists every known migration and whether it has been applied.
+func (m *Migrator) Status(ctx context.Context) ([]Status, error) {
+	var result []Status
+	err := m.withConn(ctx, func(conn *sql.Conn) error {
+		applied, err := m.applied(ctx, conn)
+		if err != nil {
+			return err
+		}
+		result = make([]Status, 0, len(m.migrations))
+		for _, mig := range m.migrations {
+			at, ok := applied[mig.Version]
+			result = append(result, Status{Migration: mig, Applied: ok, AppliedAt: at})
+		}
+		return nil
+	})
+	return result, err
+}
+
+// Up applies pending migrations up to and including target, or all of them
+// when target is zero. It returns the migrations it applied.
+func (m *Migrator) Up(ctx context.Context, target int) ([]Migration, error) {
+	if target == 0 {
+		target = m.Latest()
+	}
+	var done []Migration
+	err := m.withConn(ctx, func(conn *sql.Conn) error {
+		applied, err := m.applied(ctx, conn)
+		if err != nil {
+			return err
+		}
+		if err := m.checkKnown(applied); err != nil {
+			return err
+		}
+		for _, mig := range m.migrations {
+			if mig.Version > target {
+				break
+			}
+			if _, ok := applied[mig.Version]; ok {
+				continue
+			}
+			if err := m.run(ctx, conn, mig.Version, mig.Up, `INSERT INTO schema_migrations(version, name, applied_at) VALUES(`+m.dialect.Placeholder(1)+`, `+m.dialect.Placeholder(2)+`, `+m.dialect.Placeholder(3)+`);`, mig.Version, mig.Name, time.Now().UTC()); err != nil {
+				return fmt.Errorf("apply migration %d (%s): %w", mig.Version, mig.Name, err)
+			}
+			done = append(done, mig)
+		}
+		return nil
+	})
+	return done, err
+}
+
+// Down rolls back applied migrations newer than target, newest first, and
+// returns the migrations it reverted. A target of zero reverts everything.
+func (m *Migrator) Down(ctx context.Context, target int) ([]Migration, error) {
+	var done []Migration
+	err := m.withConn(ctx, func(conn *sql.Conn) error {
+		applied, err := m.applied(ctx, conn)
+		if err != nil {
+			return err
+		}
+		if err := m.checkKnown(applied); err != nil {
+			return err
+		}
+		for i := len(m.migrations) - 1; i >= 0; i-- {
+			mig := m.migrations[i]
+			if mig.Version <= target {
+				break
+			}
+			if _, ok := applied[mig.Version]; !ok {
+				continue
+			}
+			if strings.TrimSpace(mig.Down) == "" {
+				return fmt.Errorf("%w: %d (%s)", ErrNoDown, mig.Version, mig.Name)
+			}
+			if err := m.run(ctx, conn, mig.Version, mig.Down, `DELETE FROM schema_migrations WHERE version = `+m.dialect.Placeholder(1)+`;`, mig.Version); err != nil {
+				return fmt.Errorf("revert migration %d (%s): %w", mig.Version, mig.Name, err)
+			}
+			done = append(done, mig)
+		}
+		return nil
+	})
+	return done, err
+}
+
+// withConn pins a single connection for the duration of fn so session state
+// set by the dialect (locks, pragmas) applies to every statement.
+func (m *Migrator) withConn(ctx context.Context, fn func(*sql.Conn) error) (err error) {
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return fmt.Errorf("acquire migration connection: %w", err)
+	}
+	defer conn.Close()
+
+	if m.dialect.Acquire != nil {
+		release, err := m.dialect.Acquire(ctx, conn)
+		if err != nil {
+			return err
+		}
+		defer func() {
+			if relErr := release(context.WithoutCancel(ctx)); relErr != nil && err == nil {
+				err = relErr
+			}
+		}()
+	}
+
+	if _, err := conn.ExecContext(ctx, m.dialect.CreateTable); err != nil {
+		return fmt.Errorf("ensure schema_migrations: %w", err)
+	}
+	return fn(conn)
+}
+
+func (m *Migrator) applied(ctx context.Context, conn *sql.Conn) (map[int]time.Time, error) {
+	rows, err := conn.QueryContext(ctx, `SELECT version, applied_at FROM schema_migrations`)
+	if err != nil {
+		return nil, fmt.Errorf("select applied migrations: %w", err)
+	}
+	defer rows.Close()
+
+	applied := make(map[int]time.Time)
+	for rows.Next() {
+		var (
+			version int
+			at      sql.NullTime
+		)
+		if err := rows.Scan(&version, &at); err != nil {
+			return nil, fmt.Errorf("scan migration version: %w", err)
+		}
+		applied[version] = at.Time
+	}
+	return applied, rows.Err()
+}
+
+// checkKnown refuses to touch a schema carrying versions this build lacks;
+// rolling those back needs the newer build's down scripts.
+func (m *Migrator) checkKnown(applied map[int]time.Time) error {
+	newest := 0
+	for version := range applied {
+		if version > newest {
+			newest = version
+		}
+	}
+	if latest := m.Latest(); newest > latest {
+		return fmt.Errorf("%w: database at version %d, build supports up to %d; run `volantd migrate down --to %d` with the newer build first", ErrSchemaTooNew, newest, latest, latest)
+	}
+	return nil
+}
+
+func (m *Migrator) run(ctx context.Context, conn *sql.Conn, version int, script, record string, args ...any) error {
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin migration %d: %w", version, err)
+	}
+	if _, err := tx.ExecContext(ctx, script); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, record, args...); err != nil {
+		_ = tx.Rollback()
+		return fmt.Errorf("record migration %d: %w", version, err)
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit migration %d: %w", version, err)
+	}
+	return nil
+}
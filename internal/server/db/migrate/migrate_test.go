@@ -0,0 +1,51 @@
+// Copyright (c) 2025 HYPR. PTE. LTD.
+//
+// Business Source License 1.1
+// See LICENSE file in the project root for details.
+
+package migrate
+
+import (
+	"testing"
+	"testing/fstest"
+)
+
+func TestLoadPairsUpAndDown(t *testing.T) {
+	fsys := fstest.MapFS{
+		"m/0002_add_b.sql":     {Data: []byte("CREATE TABLE b (id INTEGER);")},
+		"m/0001_init.sql":      {Data: []byte("CREATE TABLE a (id INTEGER);")},
+		"m/0001_init.down.sql": {Data: []byte("DROP TABLE a;")},
+		"m/README.md":          {Data: []byte("ignored")},
+		"other/0003_skip.sql":  {Data: []byte("SELECT 1;")},
+	}
+	migrations, err := Load(fsys, "m")
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if len(migrations) != 2 {
+		t.Fatalf("expected 2 migrations, got %+v", migrations)
+	}
+	if migrations[0].Version != 1 || migrations[0].Name != "init" || migrations[0].Down != "DROP TABLE a;" {
+		t.Fatalf("unexpected first migration %+v", migrations[0])
+	}
+	if migrations[1].Version != 2 || migrations[1].Name != "add_b" || migrations[1].Down != "" {
+		t.Fatalf("unexpected second migration %+v", migrations[1])
+	}
+}
+
+func TestLoadRejectsInvalidSets(t *testing.T) {
+	cases := map[string]fstest.MapFS{
+		"down without up": {"m/0001_init.down.sql": {Data: []byte("DROP TABLE a;")}},
+		"bad version":     {"m/abc_init.sql": {Data: []byte("SELECT 1;")}},
+		"no name":         {"m/0001.sql": {Data: []byte("SELECT 1;")}},
+		"name mismatch": {
+			"m/0001_init.sql":       {Data: []byte("SELECT 1;")},
+			"m/0001_other.down.sql": {Data: []byte("SELECT 1;")},
+		},
+	}
+	for name, fsys := range cases {
+		if _, err := Load(fsys, "m"); err == nil {
+			t.Fatalf("%s: expected error", name)
+		}
+	}
+}
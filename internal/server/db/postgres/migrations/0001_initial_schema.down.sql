@@ -0,0 +1,20 @@
+DROP TABLE IF EXISTS operations;
+DROP TABLE IF EXISTS idempotency_keys;
+DROP TABLE IF EXISTS config_bundles;
+DROP TABLE IF EXISTS dhcp_leases;
+DROP TABLE IF EXISTS network_leases;
+DROP TABLE IF EXISTS networks;
+DROP TABLE IF EXISTS maintenance_intents;
+DROP TABLE IF EXISTS maintenance_windows;
+DROP TABLE IF EXISTS audit_events;
+DROP TABLE IF EXISTS schedule_runs;
+DROP TABLE IF EXISTS schedules;
+DROP TABLE IF EXISTS vm_cloudinit;
+DROP TABLE IF EXISTS vm_config_history;
+DROP TABLE IF EXISTS vm_configs;
+DROP TABLE IF EXISTS plugin_artifacts;
+DROP TABLE IF EXISTS plugins;
+DROP TABLE IF EXISTS ip_allocations;
+DROP TABLE IF EXISTS vm_labels;
+DROP TABLE IF EXISTS vms;
+DROP TABLE IF EXISTS vm_groups;
@@ -0,0 +1,244 @@
+-- Baseline schema matching SQLite migrations 0001-0021. Later changes get
+-- their own numbered files alongside the SQLite ones.
+
+CREATE TABLE IF NOT EXISTS vm_groups (
+    id BIGSERIAL PRIMARY KEY,
+    name TEXT NOT NULL UNIQUE,
+    config_json TEXT NOT NULL,
+    replicas INTEGER NOT NULL CHECK (replicas >= 0),
+    max_surge INTEGER NOT NULL DEFAULT 0 CHECK (max_surge >= 0),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS vms (
+    id BIGSERIAL PRIMARY KEY,
+    name TEXT NOT NULL UNIQUE,
+    status TEXT NOT NULL,
+    runtime TEXT NOT NULL DEFAULT 'browser',
+    pid BIGINT,
+    ip_address TEXT NOT NULL UNIQUE,
+    ipv6_address TEXT,
+    mac_address TEXT NOT NULL UNIQUE,
+    vsock_cid BIGINT NOT NULL DEFAULT 0,
+    cpu_cores INTEGER NOT NULL CHECK (cpu_cores > 0),
+    memory_mb INTEGER NOT NULL CHECK (memory_mb > 0),
+    kernel_cmdline TEXT,
+    serial_socket TEXT,
+    console_socket TEXT,
+    group_id BIGINT REFERENCES vm_groups(id) ON DELETE SET NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_vms_status ON vms(status);
+CREATE INDEX IF NOT EXISTS idx_vms_runtime ON vms(lower(runtime));
+CREATE INDEX IF NOT EXISTS idx_vms_group_id ON vms(group_id);
+CREATE INDEX IF NOT EXISTS idx_vms_vsock_cid ON vms(vsock_cid);
+CREATE INDEX IF NOT EXISTS idx_vms_created_at ON vms(created_at);
+CREATE INDEX IF NOT EXISTS idx_vms_updated_at ON vms(updated_at);
+
+CREATE TABLE IF NOT EXISTS vm_labels (
+    vm_id BIGINT NOT NULL REFERENCES vms(id) ON DELETE CASCADE,
+    key TEXT NOT NULL,
+    value TEXT NOT NULL DEFAULT '',
+    PRIMARY KEY (vm_id, key)
+);
+
+CREATE INDEX IF NOT EXISTS idx_vm_labels_key_value ON vm_labels(key, value);
+
+CREATE TABLE IF NOT EXISTS ip_allocations (
+    ip_address TEXT PRIMARY KEY,
+    vm_id BIGINT REFERENCES vms(id) ON DELETE SET NULL,
+    status TEXT NOT NULL,
+    leased_at TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_ip_allocations_status ON ip_allocations(status);
+
+CREATE TABLE IF NOT EXISTS plugins (
+    id BIGSERIAL PRIMARY KEY,
+    name TEXT NOT NULL UNIQUE,
+    version TEXT NOT NULL,
+    enabled BOOLEAN NOT NULL DEFAULT TRUE,
+    metadata TEXT,
+    installed_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS plugin_artifacts (
+    id BIGSERIAL PRIMARY KEY,
+    plugin_name TEXT NOT NULL,
+    version TEXT NOT NULL,
+    artifact_name TEXT NOT NULL,
+    kind TEXT NOT NULL,
+    source_url TEXT,
+    checksum TEXT,
+    format TEXT NOT NULL,
+    local_path TEXT NOT NULL,
+    size_bytes BIGINT NOT NULL DEFAULT 0,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (plugin_name, version, artifact_name)
+);
+
+CREATE INDEX IF NOT EXISTS idx_plugin_artifacts_plugin_kind ON plugin_artifacts(plugin_name, version, kind);
+
+CREATE TABLE IF NOT EXISTS vm_configs (
+    vm_id BIGINT PRIMARY KEY REFERENCES vms(id) ON DELETE CASCADE,
+    config_json TEXT NOT NULL,
+    version INTEGER NOT NULL DEFAULT 1,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS vm_config_history (
+    id BIGSERIAL PRIMARY KEY,
+    vm_id BIGINT NOT NULL REFERENCES vms(id) ON DELETE CASCADE,
+    version INTEGER NOT NULL,
+    config_json TEXT NOT NULL,
+    outcome TEXT,
+    outcome_message TEXT,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_vm_config_history_vm ON vm_config_history(vm_id, version DESC);
+
+CREATE TABLE IF NOT EXISTS vm_cloudinit (
+    vm_id BIGINT PRIMARY KEY REFERENCES vms(id) ON DELETE CASCADE,
+    user_data TEXT,
+    meta_data TEXT,
+    network_config TEXT,
+    seed_path TEXT,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS schedules (
+    id BIGSERIAL PRIMARY KEY,
+    name TEXT NOT NULL UNIQUE,
+    cron TEXT NOT NULL,
+    action TEXT NOT NULL,
+    target_kind TEXT NOT NULL,
+    target TEXT NOT NULL,
+    replicas INTEGER CHECK (replicas IS NULL OR replicas >= 0),
+    enabled BOOLEAN NOT NULL DEFAULT TRUE,
+    last_run_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS schedule_runs (
+    id BIGSERIAL PRIMARY KEY,
+    schedule_id BIGINT NOT NULL REFERENCES schedules(id) ON DELETE CASCADE,
+    status TEXT NOT NULL,
+    message TEXT,
+    started_at TIMESTAMPTZ NOT NULL,
+    finished_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_schedule_runs_schedule ON schedule_runs(schedule_id, id DESC);
+
+CREATE TABLE IF NOT EXISTS audit_events (
+    id BIGSERIAL PRIMARY KEY,
+    action TEXT NOT NULL,
+    target TEXT NOT NULL,
+    actor TEXT NOT NULL,
+    client_ip TEXT,
+    message TEXT,
+    duration_ms BIGINT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_audit_events_target ON audit_events(target, id DESC);
+
+CREATE TABLE IF NOT EXISTS maintenance_windows (
+    id BIGSERIAL PRIMARY KEY,
+    name TEXT NOT NULL UNIQUE,
+    scope TEXT NOT NULL,
+    target TEXT,
+    reason TEXT,
+    starts_at TIMESTAMPTZ NOT NULL,
+    ends_at TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS maintenance_intents (
+    id BIGSERIAL PRIMARY KEY,
+    window_id BIGINT NOT NULL REFERENCES maintenance_windows(id) ON DELETE CASCADE,
+    source TEXT NOT NULL,
+    target TEXT NOT NULL,
+    action TEXT NOT NULL,
+    count INTEGER NOT NULL DEFAULT 1,
+    recorded_at TIMESTAMPTZ NOT NULL,
+    replayed_at TIMESTAMPTZ,
+    outcome TEXT
+);
+
+CREATE INDEX IF NOT EXISTS idx_maintenance_intents_pending ON maintenance_intents(source, replayed_at);
+CREATE INDEX IF NOT EXISTS idx_maintenance_intents_window ON maintenance_intents(window_id, id);
+
+CREATE TABLE IF NOT EXISTS networks (
+    id BIGSERIAL PRIMARY KEY,
+    name TEXT NOT NULL UNIQUE,
+    bridge TEXT NOT NULL UNIQUE,
+    subnet TEXT NOT NULL,
+    gateway TEXT NOT NULL,
+    internal BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS network_leases (
+    network_id BIGINT NOT NULL REFERENCES networks(id) ON DELETE CASCADE,
+    ip_address TEXT NOT NULL,
+    vm_id BIGINT NOT NULL UNIQUE REFERENCES vms(id) ON DELETE CASCADE,
+    leased_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (network_id, ip_address)
+);
+
+CREATE TABLE IF NOT EXISTS dhcp_leases (
+    mac_address TEXT PRIMARY KEY,
+    ip_address TEXT NOT NULL,
+    vm_id BIGINT NOT NULL REFERENCES vms(id) ON DELETE CASCADE,
+    hostname TEXT,
+    expires_at TIMESTAMPTZ NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_dhcp_leases_vm ON dhcp_leases(vm_id);
+
+CREATE TABLE IF NOT EXISTS config_bundles (
+    id BIGSERIAL PRIMARY KEY,
+    name TEXT NOT NULL UNIQUE,
+    data_json TEXT NOT NULL DEFAULT '{}',
+    files_json TEXT NOT NULL DEFAULT '[]',
+    version INTEGER NOT NULL DEFAULT 1,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS idempotency_keys (
+    scope TEXT NOT NULL,
+    key TEXT NOT NULL,
+    request_hash TEXT NOT NULL,
+    status_code INTEGER NOT NULL DEFAULT 0,
+    response BYTEA,
+    created_at TIMESTAMPTZ NOT NULL,
+    expires_at TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (scope, key)
+);
+
+CREATE INDEX IF NOT EXISTS idx_idempotency_keys_expires_at ON idempotency_keys(expires_at);
+
+CREATE TABLE IF NOT EXISTS operations (
+    id TEXT PRIMARY KEY,
+    kind TEXT NOT NULL,
+    target TEXT NOT NULL,
+    status TEXT NOT NULL,
+    error TEXT,
+    code TEXT,
+    hint TEXT,
+    created_at TIMESTAMPTZ NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL,
+    completed_at TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_operations_status ON operations(status);
@@ -0,0 +1,8 @@
+CREATE TABLE IF NOT EXISTS device_assignments (
+    pci_address TEXT PRIMARY KEY,
+    vm_id BIGINT NOT NULL REFERENCES vms(id) ON DELETE CASCADE,
+    iommu_group TEXT,
+    assigned_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_device_assignments_vm ON device_assignments(vm_id);
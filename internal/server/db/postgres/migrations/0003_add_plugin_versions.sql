@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS plugin_versions (
+    id BIGSERIAL PRIMARY KEY,
+    plugin_name TEXT NOT NULL,
+    version TEXT NOT NULL,
+    metadata TEXT,
+    installed_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (plugin_name, version)
+);
+
+INSERT INTO plugin_versions (plugin_name, version, metadata, installed_at)
+SELECT name, version, metadata, installed_at FROM plugins
+ON CONFLICT (plugin_name, version) DO NOTHING;
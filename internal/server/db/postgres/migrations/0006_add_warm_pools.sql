@@ -0,0 +1,8 @@
+CREATE TABLE IF NOT EXISTS warm_pools (
+    id BIGSERIAL PRIMARY KEY,
+    plugin TEXT NOT NULL UNIQUE,
+    size INTEGER NOT NULL CHECK (size >= 0),
+    config_json TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
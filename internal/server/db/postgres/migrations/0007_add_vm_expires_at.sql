@@ -0,0 +1,2 @@
+ALTER TABLE vms ADD COLUMN IF NOT EXISTS expires_at TIMESTAMPTZ;
+CREATE INDEX IF NOT EXISTS idx_vms_expires_at ON vms(expires_at);
@@ -0,0 +1,155 @@
+// Copyright (c) 2025 HYPR. PTE. LTD.
+//
+// Business Source License 1.1
+// See LICENSE file in the project root for details.
+
+// Package postgres implements db.Store on PostgreSQL so several volantd
+// instances can share state instead of each owning a SQLite file.
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"fmt"
+	"time"
+
+	_ "github.com/lib/pq"
+
+	"github.com/volantvm/volant/internal/server/db"
+	"github.com/volantvm/volant/internal/server/db/migrate"
+)
+
+//go:embed migrations/*.sql
+var migrationsFS embed.FS
+
+// migrationLockID keys the advisory lock that serialises migrations when
+// several daemons start against the same database.
+const migrationLockID = 0x766f6c616e74 // "volant"
+
+// Store wraps a PostgreSQL connection pool with migration metadata.
+type Store struct {
+	db *sql.DB
+}
+
+// Open connects to PostgreSQL using a libpq-style DSN or postgres:// URL and
+// applies pending migrations.
+func Open(ctx context.Context, dsn string) (*Store, error) {
+	store, err := OpenForMigration(ctx, dsn)
+	if err != nil {
+		return nil, err
+	}
+	if err := store.applyMigrations(ctx); err != nil {
+		_ = store.db.Close()
+		return nil, err
+	}
+	return store, nil
+}
+
+// OpenForMigration connects without applying migrations, for managing the
+// schema through Migrator.
+func OpenForMigration(ctx context.Context, dsn string) (*Store, error) {
+	db, err := sql.Open("postgres", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("open postgres: %w", err)
+	}
+
+	configurePool(db)
+
+	if err := db.PingContext(ctx); err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("ping postgres: %w", err)
+	}
+
+	return &Store{db: db}, nil
+}
+
+// Close shuts down the underlying connection pool.
+func (s *Store) Close(ctx context.Context) error {
+	closeCh := make(chan error, 1)
+	go func() { closeCh <- s.db.Close() }()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case err := <-closeCh:
+		return err
+	}
+}
+
+// Queries returns repository accessors bound to the root connection.
+func (s *Store) Queries() db.Queries {
+	return &queries{exec: s.db}
+}
+
+// WithTx executes fn within a SQL transaction, rolling back on error.
+func (s *Store) WithTx(ctx context.Context, fn func(db.Queries) error) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+
+	q := &queries{exec: tx}
+	if err := fn(q); err != nil {
+		if rbErr := tx.Rollback(); rbErr != nil {
+			return fmt.Errorf("rollback tx after error %v: %w", err, rbErr)
+		}
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit tx: %w", err)
+	}
+	return nil
+}
+
+func configurePool(db *sql.DB) {
+	db.SetMaxOpenConns(16)
+	db.SetMaxIdleConns(4)
+	db.SetConnMaxLifetime(30 * time.Minute)
+}
+
+// dialect serialises migrations across daemons sharing the database with a
+// session advisory lock held on the migration connection.
+var dialect = migrate.Dialect{
+	Placeholder: func(n int) string { return fmt.Sprintf("$%d", n) },
+	CreateTable: `CREATE TABLE IF NOT EXISTS schema_migrations (
+        version INTEGER PRIMARY KEY,
+        name TEXT NOT NULL,
+        applied_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+    );`,
+	Acquire: func(ctx context.Context, conn *sql.Conn) (func(context.Context) error, error) {
+		if _, err := conn.ExecContext(ctx, `SELECT pg_advisory_lock($1);`, migrationLockID); err != nil {
+			return nil, fmt.Errorf("acquire migration lock: %w", err)
+		}
+		return func(ctx context.Context) error {
+			if _, err := conn.ExecContext(ctx, `SELECT pg_advisory_unlock($1);`, migrationLockID); err != nil {
+				return fmt.Errorf("release migration lock: %w", err)
+			}
+			return nil
+		}, nil
+	},
+}
+
+// Migrations returns the embedded PostgreSQL migrations in version order.
+func Migrations() ([]migrate.Migration, error) {
+	return migrate.Load(migrationsFS, "migrations")
+}
+
+// Migrator returns a migrator bound to the store's database.
+func (s *Store) Migrator() (*migrate.Migrator, error) {
+	migrations, err := Migrations()
+	if err != nil {
+		return nil, err
+	}
+	return migrate.New(s.db, dialect, migrations), nil
+}
+
+func (s *Store) applyMigrations(ctx context.Context) error {
+	m, err := s.Migrator()
+	if err != nil {
+		return err
+	}
+	_, err = m.Up(ctx, 0)
+	return err
+}
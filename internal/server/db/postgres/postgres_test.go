@@ -0,0 +1,241 @@
+// Copyright (c) 2025 HYPR. PTE. LTD.
+//
+// Business Source License 1.1
+// See LICENSE file in the project root for details.
+
+package postgres
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/volantvm/volant/internal/server/db"
+	"github.com/volantvm/volant/internal/server/labels"
+)
+
+// testDSNEnv names a scratch database the tests may create schemas in.
+const testDSNEnv = "VOLANT_TEST_DATABASE_URL"
+
+func TestVMRepositoryListPage(t *testing.T) {
+	ctx := context.Background()
+	store := openTestStore(t)
+
+	groupID, err := store.Queries().VMGroups().Create(ctx, &db.VMGroup{Name: "web", ConfigJSON: []byte(`{}`), Replicas: 2})
+	if err != nil {
+		t.Fatalf("create group: %v", err)
+	}
+	vmRepo := store.Queries().VirtualMachines()
+	specs := []struct {
+		name    string
+		status  db.VMStatus
+		runtime string
+		group   *int64
+		plugin  string
+		labels  map[string]string
+	}{
+		{"web-1", db.VMStatusRunning, "nginx", &groupID, "nginx", map[string]string{"env": "prod", "tier": "web"}},
+		{"web-2", db.VMStatusStopped, "nginx", &groupID, "nginx", map[string]string{"env": "staging", "tier": "web", "canary": ""}},
+		{"db_1", db.VMStatusRunning, "Postgres", nil, "postgres", map[string]string{"env": "prod"}},
+		{"cache", db.VMStatusCrashed, "redis", nil, "", nil},
+	}
+	for i, spec := range specs {
+		id, err := vmRepo.Create(ctx, &db.VM{
+			Name:       spec.name,
+			Status:     spec.status,
+			Runtime:    spec.runtime,
+			IPAddress:  fmt.Sprintf("192.168.127.%d", i+2),
+			MACAddress: fmt.Sprintf("02:00:00:00:00:%02d", i+1),
+			CPUCores:   1,
+			MemoryMB:   512,
+			GroupID:    spec.group,
+			Labels:     spec.labels,
+		})
+		if err != nil {
+			t.Fatalf("create vm %s: %v", spec.name, err)
+		}
+		if spec.plugin != "" {
+			if _, err := store.Queries().VMConfigs().Upsert(ctx, id, []byte(`{"plugin":"`+spec.plugin+`"}`)); err != nil {
+				t.Fatalf("upsert config: %v", err)
+			}
+		}
+	}
+
+	names := func(vms []db.VM) []string {
+		out := make([]string, 0, len(vms))
+		for _, vm := range vms {
+			out = append(out, vm.Name)
+		}
+		return out
+	}
+	cases := []struct {
+		name  string
+		opts  db.VMListOptions
+		want  []string
+		total int
+	}{
+		{"all", db.VMListOptions{Limit: -1}, []string{"web-1", "web-2", "db_1", "cache"}, 4},
+		{"status", db.VMListOptions{Statuses: []db.VMStatus{db.VMStatusRunning}, Limit: -1}, []string{"web-1", "db_1"}, 2},
+		{"runtime folds case", db.VMListOptions{Runtime: "postgres", Limit: -1}, []string{"db_1"}, 1},
+		{"group", db.VMListOptions{Group: "web", SortBy: db.VMSortName, Descending: true, Limit: -1}, []string{"web-2", "web-1"}, 2},
+		{"plugin", db.VMListOptions{Plugin: "NGINX", Limit: -1}, []string{"web-1", "web-2"}, 2},
+		{"query escapes wildcards", db.VMListOptions{Query: "_", Limit: -1}, []string{"db_1"}, 1},
+		{"page", db.VMListOptions{SortBy: db.VMSortName, Limit: 2, Offset: 1}, []string{"db_1", "web-1"}, 4},
+		{"selector equals", db.VMListOptions{Selector: mustSelector(t, "env=prod"), Limit: -1}, []string{"web-1", "db_1"}, 2},
+		{"selector not equals", db.VMListOptions{Selector: mustSelector(t, "env!=prod"), Limit: -1}, []string{"web-2", "cache"}, 2},
+		{"selector exists", db.VMListOptions{Selector: mustSelector(t, "tier,!canary"), Limit: -1}, []string{"web-1"}, 1},
+	}
+	for _, tc := range cases {
+		vms, total, err := vmRepo.ListPage(ctx, tc.opts)
+		if err != nil {
+			t.Fatalf("%s: list page: %v", tc.name, err)
+		}
+		if got := names(vms); fmt.Sprint(got) != fmt.Sprint(tc.want) || total != tc.total {
+			t.Fatalf("%s: got %v (total %d), want %v (total %d)", tc.name, got, total, tc.want, tc.total)
+		}
+	}
+
+	vm, err := vmRepo.GetByName(ctx, "web-2")
+	if err != nil {
+		t.Fatalf("get vm: %v", err)
+	}
+	if len(vm.Labels) != 3 || vm.Labels["env"] != "staging" {
+		t.Fatalf("unexpected labels %v", vm.Labels)
+	}
+}
+
+func TestIPRepositoryLeaseByFamily(t *testing.T) {
+	ctx := context.Background()
+	store := openTestStore(t)
+
+	ipRepo := store.Queries().IPAllocations()
+	pool := []string{"192.168.127.10", "fd00::10", "192.168.127.10"}
+	if err := ipRepo.EnsurePool(ctx, pool); err != nil {
+		t.Fatalf("ensure pool: %v", err)
+	}
+
+	v6, err := ipRepo.LeaseNextAvailable(ctx, db.IPFamilyV6)
+	if err != nil || v6.IPAddress != "fd00::10" || v6.Status != db.IPStatusLeased {
+		t.Fatalf("lease v6: %+v %v", v6, err)
+	}
+	v4, err := ipRepo.LeaseNextAvailable(ctx, db.IPFamilyV4)
+	if err != nil || v4.IPAddress != "192.168.127.10" || v4.LeasedAt == nil {
+		t.Fatalf("lease v4: %+v %v", v4, err)
+	}
+	if _, err := ipRepo.LeaseNextAvailable(ctx, db.IPFamilyV4); err != db.ErrNoAvailableIPs {
+		t.Fatalf("expected exhausted pool, got %v", err)
+	}
+}
+
+func TestIdempotencyKeyRepository(t *testing.T) {
+	ctx := context.Background()
+	store := openTestStore(t)
+
+	repo := store.Queries().IdempotencyKeys()
+	now := time.Now().UTC()
+	key := db.IdempotencyKey{Scope: "POST /api/v1/vms", Key: "abc", RequestHash: "h1", CreatedAt: now, ExpiresAt: now.Add(time.Hour)}
+
+	if existing, ok, err := repo.Reserve(ctx, key); err != nil || !ok || existing != nil {
+		t.Fatalf("reserve: %+v %v %v", existing, ok, err)
+	}
+	if err := repo.Complete(ctx, key.Scope, key.Key, 201, []byte(`{"name":"vm-1"}`)); err != nil {
+		t.Fatalf("complete: %v", err)
+	}
+	existing, ok, err := repo.Reserve(ctx, key)
+	if err != nil || ok || existing == nil || existing.StatusCode != 201 || string(existing.Response) != `{"name":"vm-1"}` {
+		t.Fatalf("expected cached response, got %+v %v %v", existing, ok, err)
+	}
+
+	removed, err := repo.DeleteExpired(ctx, now.Add(2*time.Hour))
+	if err != nil || removed != 1 {
+		t.Fatalf("delete expired: %d %v", removed, err)
+	}
+}
+
+func TestPluginAndScheduleBooleans(t *testing.T) {
+	ctx := context.Background()
+	store := openTestStore(t)
+
+	plugins := store.Queries().Plugins()
+	if err := plugins.Upsert(ctx, db.Plugin{Name: "nginx", Version: "1.0.0", Enabled: true, Metadata: []byte(`{"name":"nginx"}`)}); err != nil {
+		t.Fatalf("upsert plugin: %v", err)
+	}
+	if err := plugins.SetEnabled(ctx, "nginx", false); err != nil {
+		t.Fatalf("disable plugin: %v", err)
+	}
+	plugin, err := plugins.GetByName(ctx, "nginx")
+	if err != nil || plugin == nil || plugin.Enabled || string(plugin.Metadata) != `{"name":"nginx"}` {
+		t.Fatalf("unexpected plugin %+v %v", plugin, err)
+	}
+
+	schedules := store.Queries().Schedules()
+	id, err := schedules.Create(ctx, &db.Schedule{Name: "nightly", Cron: "0 2 * * *", Action: "restart", TargetKind: "vm", Target: "web-1", Enabled: true})
+	if err != nil {
+		t.Fatalf("create schedule: %v", err)
+	}
+	if _, err := schedules.RecordRun(ctx, db.ScheduleRun{ScheduleID: id, Status: "succeeded", StartedAt: time.Now(), FinishedAt: time.Now()}); err != nil {
+		t.Fatalf("record run: %v", err)
+	}
+	schedule, err := schedules.GetByName(ctx, "nightly")
+	if err != nil || schedule == nil || !schedule.Enabled || schedule.LastRunAt == nil {
+		t.Fatalf("unexpected schedule %+v %v", schedule, err)
+	}
+}
+
+func mustSelector(t *testing.T, expr string) labels.Selector {
+	t.Helper()
+	selector, err := labels.Parse(expr)
+	if err != nil {
+		t.Fatalf("parse selector %q: %v", expr, err)
+	}
+	return selector
+}
+
+// openTestStore opens a store in a throwaway schema of the database named by
+// VOLANT_TEST_DATABASE_URL, skipping the test when it is unset.
+func openTestStore(t *testing.T) *Store {
+	t.Helper()
+	dsn := os.Getenv(testDSNEnv)
+	if dsn == "" {
+		t.Skipf("%s not set", testDSNEnv)
+	}
+	ctx := context.Background()
+
+	var buf [6]byte
+	_, _ = rand.Read(buf[:])
+	schema := "volant_test_" + hex.EncodeToString(buf[:])
+
+	admin, err := sql.Open("postgres", dsn)
+	if err != nil {
+		t.Fatalf("open admin connection: %v", err)
+	}
+	t.Cleanup(func() { _ = admin.Close() })
+	if _, err := admin.ExecContext(ctx, `CREATE SCHEMA `+schema); err != nil {
+		t.Fatalf("create schema: %v", err)
+	}
+	t.Cleanup(func() { _, _ = admin.ExecContext(ctx, `DROP SCHEMA `+schema+` CASCADE`) })
+
+	store, err := Open(ctx, withSearchPath(dsn, schema))
+	if err != nil {
+		t.Fatalf("open postgres store: %v", err)
+	}
+	t.Cleanup(func() { _ = store.Close(ctx) })
+	return store
+}
+
+func withSearchPath(dsn, schema string) string {
+	if strings.Contains(dsn, "://") {
+		sep := "?"
+		if strings.Contains(dsn, "?") {
+			sep = "&"
+		}
+		return dsn + sep + "search_path=" + schema
+	}
+	return dsn + " search_path=" + schema
+}
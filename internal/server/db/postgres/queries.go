@@ -0,0 +1,2816 @@
+// Copyright (c) 2025 HYPR. PTE. LTD.
+//
+// Business Source License 1.1
+// See LICENSE file in the project root for details.
+
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+
+	"github.com/volantvm/volant/internal/server/db"
+	"github.com/volantvm/volant/internal/server/labels"
+)
+
+var timestampLayouts = []string{
+	"2006-01-02 15:04:05",
+	time.RFC3339,
+	time.RFC3339Nano,
+}
+
+// executor abstracts *sql.DB and *sql.Tx for shared query logic.
+type executor interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+type queries struct {
+	exec executor
+}
+
+var _ db.Queries = (*queries)(nil)
+
+func (q *queries) VirtualMachines() db.VMRepository {
+	return &vmRepository{exec: q.exec}
+}
+
+func (q *queries) IPAllocations() db.IPRepository {
+	return &ipRepository{exec: q.exec}
+}
+
+func (q *queries) Plugins() db.PluginRepository {
+	return &pluginRepository{exec: q.exec}
+}
+
+func (q *queries) VMConfigs() db.VMConfigRepository {
+	return &vmConfigRepository{exec: q.exec}
+}
+
+func (q *queries) VMGroups() db.VMGroupRepository {
+	return &vmGroupRepository{exec: q.exec}
+}
+
+func (q *queries) PluginArtifacts() db.PluginArtifactRepository {
+	return &pluginArtifactRepository{exec: q.exec}
+}
+
+func (q *queries) VMCloudInit() db.VMCloudInitRepository {
+	return &vmCloudInitRepository{exec: q.exec}
+}
+
+func (q *queries) Schedules() db.ScheduleRepository {
+	return &scheduleRepository{exec: q.exec}
+}
+
+func (q *queries) AuditEvents() db.AuditRepository {
+	return &auditRepository{exec: q.exec}
+}
+
+func (q *queries) Maintenance() db.MaintenanceRepository {
+	return &maintenanceRepository{exec: q.exec}
+}
+
+func (q *queries) Networks() db.NetworkRepository {
+	return &networkRepository{exec: q.exec}
+}
+
+func (q *queries) ConfigBundles() db.ConfigBundleRepository {
+	return &configBundleRepository{exec: q.exec}
+}
+
+func (q *queries) IdempotencyKeys() db.IdempotencyKeyRepository {
+	return &idempotencyKeyRepository{exec: q.exec}
+}
+
+func (q *queries) Operations() db.OperationRepository {
+	return &operationRepository{exec: q.exec}
+}
+
+func (q *queries) DHCPLeases() db.DHCPLeaseRepository {
+	return &dhcpLeaseRepository{exec: q.exec}
+}
+
+func (q *queries) DeviceAssignments() db.DeviceAssignmentRepository {
+	return &deviceAssignmentRepository{exec: q.exec}
+}
+
+func (q *queries) PluginVersions() db.PluginVersionRepository {
+	return &pluginVersionRepository{exec: q.exec}
+}
+
+func (q *queries) Webhooks() db.WebhookRepository {
+	return &webhookRepository{exec: q.exec}
+}
+
+func (q *queries) Secrets() db.SecretRepository {
+	return &secretRepository{exec: q.exec}
+}
+
+func (q *queries) WarmPools() db.WarmPoolRepository {
+	return &warmPoolRepository{exec: q.exec}
+}
+
+func (q *queries) VMEvents() db.VMEventRepository {
+	return &vmEventRepository{exec: q.exec}
+}
+
+type vmRepository struct {
+	exec executor
+}
+
+var _ db.VMRepository = (*vmRepository)(nil)
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func (r *vmRepository) Create(ctx context.Context, vm *db.VM) (int64, error) {
+	pidVal := nullableInt64(vm.PID)
+	cmdlineVal := nullableString(vm.KernelCmdline)
+	serialVal := nullableString(vm.SerialSocket)
+	groupVal := nullableInt64(vm.GroupID)
+
+	var id int64
+	err := r.exec.QueryRowContext(
+		ctx,
+		`INSERT INTO vms (name, status, runtime, pid, ip_address, ipv6_address, mac_address, vsock_cid, cpu_cores, memory_mb, kernel_cmdline, serial_socket, group_id)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13) RETURNING id;`,
+		vm.Name,
+		string(vm.Status),
+		vm.Runtime,
+		pidVal,
+		vm.IPAddress,
+		nullableString(vm.IPv6Address),
+		vm.MACAddress,
+		vm.VsockCID,
+		vm.CPUCores,
+		vm.MemoryMB,
+		cmdlineVal,
+		serialVal,
+		groupVal,
+	).Scan(&id)
+	if err != nil {
+		return 0, fmt.Errorf("insert vm: %w", err)
+	}
+
+	if len(vm.Labels) > 0 {
+		if err := r.SetLabels(ctx, id, vm.Labels); err != nil {
+			return 0, err
+		}
+	}
+	return id, nil
+}
+
+func (r *vmRepository) GetByName(ctx context.Context, name string) (*db.VM, error) {
+	row := r.exec.QueryRowContext(ctx, `SELECT id, name, status, runtime, pid, ip_address, ipv6_address, mac_address, vsock_cid, cpu_cores, memory_mb, kernel_cmdline, serial_socket, group_id, expires_at, created_at, updated_at FROM vms WHERE name = $1;`, name)
+	vm, err := scanVM(row)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	vms := []db.VM{vm}
+	if err := r.attachLabels(ctx, vms); err != nil {
+		return nil, err
+	}
+	return &vms[0], nil
+}
+
+func (r *vmRepository) GetByMAC(ctx context.Context, mac string) (*db.VM, error) {
+	row := r.exec.QueryRowContext(ctx, `SELECT id, name, status, runtime, pid, ip_address, ipv6_address, mac_address, vsock_cid, cpu_cores, memory_mb, kernel_cmdline, serial_socket, group_id, expires_at, created_at, updated_at FROM vms WHERE lower(mac_address) = lower($1);`, mac)
+	vm, err := scanVM(row)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	vms := []db.VM{vm}
+	if err := r.attachLabels(ctx, vms); err != nil {
+		return nil, err
+	}
+	return &vms[0], nil
+}
+
+func (r *vmRepository) List(ctx context.Context) ([]db.VM, error) {
+	rows, err := r.exec.QueryContext(ctx, `SELECT id, name, status, runtime, pid, ip_address, ipv6_address, mac_address, vsock_cid, cpu_cores, memory_mb, kernel_cmdline, serial_socket, group_id, expires_at, created_at, updated_at FROM vms ORDER BY created_at ASC;`)
+	if err != nil {
+		return nil, fmt.Errorf("query vms: %w", err)
+	}
+	defer rows.Close()
+
+	var result []db.VM
+	for rows.Next() {
+		vm, err := scanVM(rows)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, vm)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate vms: %w", err)
+	}
+	if err := r.attachLabels(ctx, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+var vmSortColumns = map[string]string{
+	db.VMSortCreatedAt: "created_at",
+	db.VMSortUpdatedAt: "updated_at",
+	db.VMSortName:      "lower(name)",
+	db.VMSortStatus:    "status",
+	db.VMSortRuntime:   "lower(runtime)",
+}
+
+func (r *vmRepository) ListPage(ctx context.Context, opts db.VMListOptions) ([]db.VM, int, error) {
+	var (
+		clauses []string
+		args    []any
+	)
+	// arg binds v and returns its positional placeholder.
+	arg := func(v any) string {
+		args = append(args, v)
+		return fmt.Sprintf("$%d", len(args))
+	}
+	if len(opts.Statuses) > 0 {
+		placeholders := make([]string, len(opts.Statuses))
+		for i, status := range opts.Statuses {
+			placeholders[i] = arg(strings.ToLower(string(status)))
+		}
+		clauses = append(clauses, "status IN ("+strings.Join(placeholders, ", ")+")")
+	}
+	if opts.Runtime != "" {
+		clauses = append(clauses, "lower(runtime) = lower("+arg(opts.Runtime)+")")
+	}
+	if opts.Group != "" {
+		clauses = append(clauses, "group_id IN (SELECT id FROM vm_groups WHERE name = "+arg(opts.Group)+")")
+	}
+	if opts.Plugin != "" {
+		clauses = append(clauses, `id IN (SELECT vm_id FROM vm_configs WHERE lower(COALESCE(NULLIF(TRIM(config_json::jsonb ->> 'plugin'), ''), TRIM(config_json::jsonb #>> '{manifest,name}'))) = lower(`+arg(opts.Plugin)+`))`)
+	}
+	for _, req := range opts.Selector {
+		switch req.Operator {
+		case labels.Equals:
+			clauses = append(clauses, "id IN (SELECT vm_id FROM vm_labels WHERE key = "+arg(req.Key)+" AND value = "+arg(req.Value)+")")
+		case labels.NotEquals:
+			clauses = append(clauses, "id NOT IN (SELECT vm_id FROM vm_labels WHERE key = "+arg(req.Key)+" AND value = "+arg(req.Value)+")")
+		case labels.Exists:
+			clauses = append(clauses, "id IN (SELECT vm_id FROM vm_labels WHERE key = "+arg(req.Key)+")")
+		case labels.DoesNotExist:
+			clauses = append(clauses, "id NOT IN (SELECT vm_id FROM vm_labels WHERE key = "+arg(req.Key)+")")
+		}
+	}
+	if opts.Query != "" {
+		// ILIKE keeps search case-insensitive, matching the SQLite store.
+		pattern := arg("%" + likeEscaper.Replace(opts.Query) + "%")
+		clauses = append(clauses, `(name ILIKE `+pattern+` ESCAPE '\' OR ip_address ILIKE `+pattern+` ESCAPE '\' OR runtime ILIKE `+pattern+` ESCAPE '\')`)
+	}
+	where := ""
+	if len(clauses) > 0 {
+		where = " WHERE " + strings.Join(clauses, " AND ")
+	}
+
+	var total int
+	if err := r.exec.QueryRowContext(ctx, `SELECT COUNT(*) FROM vms`+where+`;`, args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("count vms: %w", err)
+	}
+
+	column, ok := vmSortColumns[opts.SortBy]
+	if !ok {
+		column = vmSortColumns[db.VMSortCreatedAt]
+	}
+	direction := "ASC"
+	if opts.Descending {
+		direction = "DESC"
+	}
+	var limit any = opts.Limit
+	if opts.Limit < 0 {
+		limit = nil // LIMIT NULL is unbounded.
+	}
+	offset := opts.Offset
+	if offset < 0 {
+		offset = 0
+	}
+	query := `SELECT id, name, status, runtime, pid, ip_address, ipv6_address, mac_address, vsock_cid, cpu_cores, memory_mb, kernel_cmdline, serial_socket, group_id, expires_at, created_at, updated_at FROM vms` +
+		where + ` ORDER BY ` + column + ` ` + direction + `, id ` + direction + ` LIMIT ` + arg(limit) + ` OFFSET ` + arg(offset) + `;`
+	rows, err := r.exec.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("query vms: %w", err)
+	}
+	defer rows.Close()
+
+	var result []db.VM
+	for rows.Next() {
+		vm, err := scanVM(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		result = append(result, vm)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("iterate vms: %w", err)
+	}
+	if err := r.attachLabels(ctx, result); err != nil {
+		return nil, 0, err
+	}
+	return result, total, nil
+}
+
+func (r *vmRepository) SetLabels(ctx context.Context, id int64, set map[string]string) error {
+	if _, err := r.exec.ExecContext(ctx, `DELETE FROM vm_labels WHERE vm_id = $1;`, id); err != nil {
+		return fmt.Errorf("clear vm labels: %w", err)
+	}
+	for key, value := range set {
+		if _, err := r.exec.ExecContext(ctx, `INSERT INTO vm_labels (vm_id, key, value) VALUES ($1, $2, $3);`, id, key, value); err != nil {
+			return fmt.Errorf("insert vm label: %w", err)
+		}
+	}
+	return nil
+}
+
+// attachLabels fills in the labels of each VM in place.
+func (r *vmRepository) attachLabels(ctx context.Context, vms []db.VM) error {
+	if len(vms) == 0 {
+		return nil
+	}
+	index := make(map[int64]int, len(vms))
+	ids := make([]int64, 0, len(vms))
+	for i := range vms {
+		index[vms[i].ID] = i
+		ids = append(ids, vms[i].ID)
+	}
+	rows, err := r.exec.QueryContext(ctx, `SELECT vm_id, key, value FROM vm_labels WHERE vm_id = ANY($1);`, pq.Array(ids))
+	if err != nil {
+		return fmt.Errorf("query vm labels: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			vmID       int64
+			key, value string
+		)
+		if err := rows.Scan(&vmID, &key, &value); err != nil {
+			return fmt.Errorf("scan vm label: %w", err)
+		}
+		vm := &vms[index[vmID]]
+		if vm.Labels == nil {
+			vm.Labels = make(map[string]string)
+		}
+		vm.Labels[key] = value
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("iterate vm labels: %w", err)
+	}
+	return nil
+}
+
+// likeEscaper escapes LIKE wildcards so user input matches literally.
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+func (r *vmRepository) ListByGroupID(ctx context.Context, groupID int64) ([]db.VM, error) {
+	rows, err := r.exec.QueryContext(ctx, `SELECT id, name, status, runtime, pid, ip_address, ipv6_address, mac_address, vsock_cid, cpu_cores, memory_mb, kernel_cmdline, serial_socket, group_id, expires_at, created_at, updated_at FROM vms WHERE group_id = $1 ORDER BY name ASC;`, groupID)
+	if err != nil {
+		return nil, fmt.Errorf("query vms by group: %w", err)
+	}
+	defer rows.Close()
+
+	var result []db.VM
+	for rows.Next() {
+		vm, err := scanVM(rows)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, vm)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate vms by group: %w", err)
+	}
+	if err := r.attachLabels(ctx, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (r *vmRepository) VsockCIDs(ctx context.Context) ([]uint32, error) {
+	rows, err := r.exec.QueryContext(ctx, `SELECT vsock_cid FROM vms WHERE vsock_cid > 0 ORDER BY vsock_cid ASC;`)
+	if err != nil {
+		return nil, fmt.Errorf("query vsock cids: %w", err)
+	}
+	defer rows.Close()
+
+	var cids []uint32
+	for rows.Next() {
+		var cid int64
+		if err := rows.Scan(&cid); err != nil {
+			return nil, fmt.Errorf("scan vsock cid: %w", err)
+		}
+		cids = append(cids, uint32(cid))
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate vsock cids: %w", err)
+	}
+	return cids, nil
+}
+
+func (r *vmRepository) UpdateRuntimeState(ctx context.Context, id int64, status db.VMStatus, pid *int64) error {
+	pidVal := nullableInt64(pid)
+	if _, err := r.exec.ExecContext(ctx, `UPDATE vms SET status = $1, pid = $2, updated_at = CURRENT_TIMESTAMP WHERE id = $3;`, string(status), pidVal, id); err != nil {
+		return fmt.Errorf("update vm runtime state: %w", err)
+	}
+	return nil
+}
+
+func (r *vmRepository) SetExpiresAt(ctx context.Context, id int64, expiresAt *time.Time) error {
+	var value any
+	if expiresAt != nil {
+		value = expiresAt.UTC()
+	}
+	if _, err := r.exec.ExecContext(ctx, `UPDATE vms SET expires_at = $1, updated_at = CURRENT_TIMESTAMP WHERE id = $2;`, value, id); err != nil {
+		return fmt.Errorf("update vm expiry: %w", err)
+	}
+	return nil
+}
+
+func (r *vmRepository) ListExpired(ctx context.Context, now time.Time) ([]db.VM, error) {
+	rows, err := r.exec.QueryContext(ctx, `SELECT id, name, status, runtime, pid, ip_address, ipv6_address, mac_address, vsock_cid, cpu_cores, memory_mb, kernel_cmdline, serial_socket, group_id, expires_at, created_at, updated_at FROM vms WHERE expires_at IS NOT NULL AND expires_at <= $1 ORDER BY expires_at ASC;`, now.UTC())
+	if err != nil {
+		return nil, fmt.Errorf("query expired vms: %w", err)
+	}
+	defer rows.Close()
+
+	var result []db.VM
+	for rows.Next() {
+		vm, err := scanVM(rows)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, vm)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate expired vms: %w", err)
+	}
+	if err := r.attachLabels(ctx, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (r *vmRepository) UpdateKernelCmdline(ctx context.Context, id int64, cmdline string) error {
+	if _, err := r.exec.ExecContext(ctx, `UPDATE vms SET kernel_cmdline = $1, updated_at = CURRENT_TIMESTAMP WHERE id = $2;`, nullableString(cmdline), id); err != nil {
+		return fmt.Errorf("update vm cmdline: %w", err)
+	}
+	return nil
+}
+
+func (r *vmRepository) UpdateSockets(ctx context.Context, id int64, serial string) error {
+	if _, err := r.exec.ExecContext(ctx, `UPDATE vms SET serial_socket = $1, console_socket = NULL, updated_at = CURRENT_TIMESTAMP WHERE id = $2;`, nullableString(serial), id); err != nil {
+		return fmt.Errorf("update vm sockets: %w", err)
+	}
+	return nil
+}
+
+func (r *vmRepository) UpdateSpec(ctx context.Context, id int64, runtime string, cpuCores, memoryMB int, kernelCmdline string) error {
+	if _, err := r.exec.ExecContext(ctx, `UPDATE vms SET runtime = $1, cpu_cores = $2, memory_mb = $3, kernel_cmdline = $4, updated_at = CURRENT_TIMESTAMP WHERE id = $5;`, runtime, cpuCores, memoryMB, nullableString(kernelCmdline), id); err != nil {
+		return fmt.Errorf("update vm spec: %w", err)
+	}
+	return nil
+}
+
+func (r *vmRepository) Delete(ctx context.Context, id int64) error {
+	if _, err := r.exec.ExecContext(ctx, `DELETE FROM vms WHERE id = $1;`, id); err != nil {
+		return fmt.Errorf("delete vm: %w", err)
+	}
+	return nil
+}
+
+type ipRepository struct {
+	exec executor
+}
+
+var _ db.IPRepository = (*ipRepository)(nil)
+
+func (r *ipRepository) EnsurePool(ctx context.Context, ips []string) error {
+	for _, ip := range ips {
+		if _, err := r.exec.ExecContext(ctx, `INSERT INTO ip_allocations (ip_address, status) VALUES ($1, $2) ON CONFLICT (ip_address) DO NOTHING;`, ip, string(db.IPStatusAvailable)); err != nil {
+			return fmt.Errorf("ensure pool entry %s: %w", ip, err)
+		}
+	}
+	return nil
+}
+
+func (r *ipRepository) LeaseNextAvailable(ctx context.Context, family int) (*db.IPAllocation, error) {
+	// IPv6 addresses are the only ones containing a colon. Selecting and
+	// marking in one statement with SKIP LOCKED keeps concurrent daemons from
+	// handing out the same address.
+	isIPv6 := family == db.IPFamilyV6
+	row := r.exec.QueryRowContext(ctx, `UPDATE ip_allocations SET status = $1, vm_id = NULL, leased_at = CURRENT_TIMESTAMP
+		WHERE ip_address = (
+			SELECT ip_address FROM ip_allocations WHERE status = $2 AND (strpos(ip_address, ':') > 0) = $3
+			ORDER BY ip_address ASC LIMIT 1 FOR UPDATE SKIP LOCKED
+		) RETURNING ip_address;`, string(db.IPStatusLeased), string(db.IPStatusAvailable), isIPv6)
+	var ip string
+	if err := row.Scan(&ip); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, db.ErrNoAvailableIPs
+		}
+		return nil, fmt.Errorf("lease next available ip: %w", err)
+	}
+
+	return r.Lookup(ctx, ip)
+}
+
+func (r *ipRepository) LeaseSpecific(ctx context.Context, ip string) (*db.IPAllocation, error) {
+	res, err := r.exec.ExecContext(ctx, `UPDATE ip_allocations SET status = $1, vm_id = NULL, leased_at = CURRENT_TIMESTAMP WHERE ip_address = $2 AND status = $3;`, string(db.IPStatusLeased), ip, string(db.IPStatusAvailable))
+	if err != nil {
+		return nil, fmt.Errorf("lease specific ip: %w", err)
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return nil, fmt.Errorf("lease specific rows affected: %w", err)
+	}
+	if affected == 0 {
+		return nil, db.ErrNoAvailableIPs
+	}
+	return r.Lookup(ctx, ip)
+}
+
+func (r *ipRepository) Assign(ctx context.Context, ip string, vmID int64) error {
+	res, err := r.exec.ExecContext(ctx, `UPDATE ip_allocations SET vm_id = $1 WHERE ip_address = $2 AND status = $3;`, vmID, ip, string(db.IPStatusLeased))
+	if err != nil {
+		return fmt.Errorf("assign ip: %w", err)
+	}
+	if rows, err := res.RowsAffected(); err == nil && rows == 0 {
+		return fmt.Errorf("assign ip: no rows affected")
+	} else if err != nil {
+		return fmt.Errorf("assign ip rows affected: %w", err)
+	}
+	return nil
+}
+
+func (r *ipRepository) Release(ctx context.Context, ip string) error {
+	if _, err := r.exec.ExecContext(ctx, `UPDATE ip_allocations SET status = $1, vm_id = NULL, leased_at = NULL WHERE ip_address = $2;`, string(db.IPStatusAvailable), ip); err != nil {
+		return fmt.Errorf("release ip: %w", err)
+	}
+	return nil
+}
+
+func (r *ipRepository) Lookup(ctx context.Context, ip string) (*db.IPAllocation, error) {
+	row := r.exec.QueryRowContext(ctx, `SELECT ip_address, vm_id, status, leased_at FROM ip_allocations WHERE ip_address = $1;`, ip)
+	alloc, err := scanIP(row)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &alloc, nil
+}
+
+type pluginRepository struct {
+	exec executor
+}
+
+var _ db.PluginRepository = (*pluginRepository)(nil)
+
+type vmGroupRepository struct {
+	exec executor
+}
+
+var _ db.VMGroupRepository = (*vmGroupRepository)(nil)
+
+type pluginArtifactRepository struct {
+	exec executor
+}
+
+var _ db.PluginArtifactRepository = (*pluginArtifactRepository)(nil)
+
+type pluginVersionRepository struct {
+	exec executor
+}
+
+var _ db.PluginVersionRepository = (*pluginVersionRepository)(nil)
+
+type vmCloudInitRepository struct {
+	exec executor
+}
+
+var _ db.VMCloudInitRepository = (*vmCloudInitRepository)(nil)
+
+type vmConfigRepository struct {
+	exec executor
+}
+
+var _ db.VMConfigRepository = (*vmConfigRepository)(nil)
+
+type scheduleRepository struct {
+	exec executor
+}
+
+var _ db.ScheduleRepository = (*scheduleRepository)(nil)
+
+type webhookRepository struct {
+	exec executor
+}
+
+var _ db.WebhookRepository = (*webhookRepository)(nil)
+
+type secretRepository struct {
+	exec executor
+}
+
+var _ db.SecretRepository = (*secretRepository)(nil)
+
+type warmPoolRepository struct {
+	exec executor
+}
+
+var _ db.WarmPoolRepository = (*warmPoolRepository)(nil)
+
+type auditRepository struct {
+	exec executor
+}
+
+var _ db.AuditRepository = (*auditRepository)(nil)
+
+type vmEventRepository struct {
+	exec executor
+}
+
+var _ db.VMEventRepository = (*vmEventRepository)(nil)
+
+type maintenanceRepository struct {
+	exec executor
+}
+
+var _ db.MaintenanceRepository = (*maintenanceRepository)(nil)
+
+type dhcpLeaseRepository struct {
+	exec executor
+}
+
+var _ db.DHCPLeaseRepository = (*dhcpLeaseRepository)(nil)
+
+type deviceAssignmentRepository struct {
+	exec executor
+}
+
+var _ db.DeviceAssignmentRepository = (*deviceAssignmentRepository)(nil)
+
+type networkRepository struct {
+	exec executor
+}
+
+var _ db.NetworkRepository = (*networkRepository)(nil)
+
+type configBundleRepository struct {
+	exec executor
+}
+
+var _ db.ConfigBundleRepository = (*configBundleRepository)(nil)
+
+type idempotencyKeyRepository struct {
+	exec executor
+}
+
+var _ db.IdempotencyKeyRepository = (*idempotencyKeyRepository)(nil)
+
+type operationRepository struct {
+	exec executor
+}
+
+var _ db.OperationRepository = (*operationRepository)(nil)
+
+func (r *pluginRepository) Upsert(ctx context.Context, plugin db.Plugin) error {
+	meta := string(plugin.Metadata)
+	_, err := r.exec.ExecContext(ctx, `INSERT INTO plugins (name, version, enabled, metadata, installed_at, updated_at)
+		VALUES ($1, $2, $3, $4, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP)
+		ON CONFLICT(name) DO UPDATE SET version = excluded.version, enabled = excluded.enabled, metadata = excluded.metadata, updated_at = CURRENT_TIMESTAMP;`,
+		plugin.Name, plugin.Version, plugin.Enabled, meta,
+	)
+	if err != nil {
+		return fmt.Errorf("upsert plugin: %w", err)
+	}
+	return nil
+}
+
+func (r *pluginRepository) List(ctx context.Context) ([]db.Plugin, error) {
+	rows, err := r.exec.QueryContext(ctx, `SELECT id, name, version, enabled, metadata, installed_at, updated_at FROM plugins ORDER BY name ASC;`)
+	if err != nil {
+		return nil, fmt.Errorf("list plugins: %w", err)
+	}
+	defer rows.Close()
+
+	var result []db.Plugin
+	for rows.Next() {
+		plugin, err := scanPlugin(rows)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, plugin)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate plugins: %w", err)
+	}
+	return result, nil
+}
+
+func (r *pluginRepository) GetByName(ctx context.Context, name string) (*db.Plugin, error) {
+	row := r.exec.QueryRowContext(ctx, `SELECT id, name, version, enabled, metadata, installed_at, updated_at FROM plugins WHERE name = $1;`, name)
+	plugin, err := scanPlugin(row)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &plugin, nil
+}
+
+func (r *pluginRepository) SetEnabled(ctx context.Context, name string, enabled bool) error {
+	res, err := r.exec.ExecContext(ctx, `UPDATE plugins SET enabled = $1, updated_at = CURRENT_TIMESTAMP WHERE name = $2;`, enabled, name)
+	if err != nil {
+		return fmt.Errorf("set plugin enabled: %w", err)
+	}
+	if rows, err := res.RowsAffected(); err == nil && rows == 0 {
+		return sql.ErrNoRows
+	} else if err != nil {
+		return fmt.Errorf("set plugin enabled rows: %w", err)
+	}
+	return nil
+}
+
+func (r *pluginRepository) Delete(ctx context.Context, name string) error {
+	res, err := r.exec.ExecContext(ctx, `DELETE FROM plugins WHERE name = $1;`, name)
+	if err != nil {
+		return fmt.Errorf("delete plugin: %w", err)
+	}
+	if rows, err := res.RowsAffected(); err == nil && rows == 0 {
+		return sql.ErrNoRows
+	} else if err != nil {
+		return fmt.Errorf("delete plugin rows: %w", err)
+	}
+	return nil
+}
+
+func (r *vmGroupRepository) Create(ctx context.Context, group *db.VMGroup) (int64, error) {
+	var id int64
+	if err := r.exec.QueryRowContext(ctx, `INSERT INTO vm_groups (name, config_json, replicas, max_surge) VALUES ($1, $2, $3, $4) RETURNING id;`, group.Name, string(group.ConfigJSON), group.Replicas, group.MaxSurge).Scan(&id); err != nil {
+		return 0, fmt.Errorf("insert vm group: %w", err)
+	}
+	return id, nil
+}
+
+func (r *vmGroupRepository) Update(ctx context.Context, id int64, configJSON []byte, replicas int) error {
+	if _, err := r.exec.ExecContext(ctx, `UPDATE vm_groups SET config_json = $1, replicas = $2, updated_at = CURRENT_TIMESTAMP WHERE id = $3;`, string(configJSON), replicas, id); err != nil {
+		return fmt.Errorf("update vm group: %w", err)
+	}
+	return nil
+}
+
+func (r *vmGroupRepository) UpdateReplicas(ctx context.Context, id int64, replicas int) error {
+	if _, err := r.exec.ExecContext(ctx, `UPDATE vm_groups SET replicas = $1, updated_at = CURRENT_TIMESTAMP WHERE id = $2;`, replicas, id); err != nil {
+		return fmt.Errorf("update vm group replicas: %w", err)
+	}
+	return nil
+}
+
+func (r *vmGroupRepository) Delete(ctx context.Context, id int64) error {
+	if _, err := r.exec.ExecContext(ctx, `DELETE FROM vm_groups WHERE id = $1;`, id); err != nil {
+		return fmt.Errorf("delete vm group: %w", err)
+	}
+	return nil
+}
+
+func (r *vmGroupRepository) GetByName(ctx context.Context, name string) (*db.VMGroup, error) {
+	row := r.exec.QueryRowContext(ctx, `SELECT id, name, config_json, replicas, max_surge, created_at, updated_at FROM vm_groups WHERE name = $1;`, name)
+	group, err := scanVMGroup(row)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &group, nil
+}
+
+func (r *vmGroupRepository) GetByID(ctx context.Context, id int64) (*db.VMGroup, error) {
+	row := r.exec.QueryRowContext(ctx, `SELECT id, name, config_json, replicas, max_surge, created_at, updated_at FROM vm_groups WHERE id = $1;`, id)
+	group, err := scanVMGroup(row)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &group, nil
+}
+
+func (r *vmGroupRepository) List(ctx context.Context) ([]db.VMGroup, error) {
+	rows, err := r.exec.QueryContext(ctx, `SELECT id, name, config_json, replicas, max_surge, created_at, updated_at FROM vm_groups ORDER BY name ASC;`)
+	if err != nil {
+		return nil, fmt.Errorf("list vm groups: %w", err)
+	}
+	defer rows.Close()
+
+	var result []db.VMGroup
+	for rows.Next() {
+		group, err := scanVMGroup(rows)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, group)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate vm groups: %w", err)
+	}
+	return result, nil
+}
+
+func (r *pluginArtifactRepository) Upsert(ctx context.Context, artifact db.PluginArtifact) error {
+	if _, err := r.exec.ExecContext(ctx, `INSERT INTO plugin_artifacts (plugin_name, version, artifact_name, kind, source_url, checksum, format, local_path, size_bytes)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		ON CONFLICT(plugin_name, version, artifact_name) DO UPDATE SET kind = excluded.kind, source_url = excluded.source_url, checksum = excluded.checksum, format = excluded.format, local_path = excluded.local_path, size_bytes = excluded.size_bytes, updated_at = CURRENT_TIMESTAMP;`,
+		artifact.PluginName, artifact.Version, artifact.ArtifactName, artifact.Kind, artifact.SourceURL, artifact.Checksum, artifact.Format, artifact.LocalPath, artifact.SizeBytes); err != nil {
+		return fmt.Errorf("upsert plugin artifact: %w", err)
+	}
+	return nil
+}
+
+func (r *pluginArtifactRepository) ListByPlugin(ctx context.Context, plugin string) ([]db.PluginArtifact, error) {
+	rows, err := r.exec.QueryContext(ctx, `SELECT id, plugin_name, version, artifact_name, kind, source_url, checksum, format, local_path, size_bytes, created_at, updated_at FROM plugin_artifacts WHERE plugin_name = $1 ORDER BY version DESC, artifact_name ASC;`, plugin)
+	if err != nil {
+		return nil, fmt.Errorf("list plugin artifacts: %w", err)
+	}
+	defer rows.Close()
+
+	var result []db.PluginArtifact
+	for rows.Next() {
+		artifact, err := scanPluginArtifact(rows)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, artifact)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate plugin artifacts: %w", err)
+	}
+	return result, nil
+}
+
+func (r *pluginArtifactRepository) ListByPluginVersion(ctx context.Context, plugin, version string) ([]db.PluginArtifact, error) {
+	rows, err := r.exec.QueryContext(ctx, `SELECT id, plugin_name, version, artifact_name, kind, source_url, checksum, format, local_path, size_bytes, created_at, updated_at FROM plugin_artifacts WHERE plugin_name = $1 AND version = $2 ORDER BY artifact_name ASC;`, plugin, version)
+	if err != nil {
+		return nil, fmt.Errorf("list plugin artifacts by version: %w", err)
+	}
+	defer rows.Close()
+
+	var result []db.PluginArtifact
+	for rows.Next() {
+		artifact, err := scanPluginArtifact(rows)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, artifact)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate plugin artifacts by version: %w", err)
+	}
+	return result, nil
+}
+
+func (r *pluginArtifactRepository) Get(ctx context.Context, plugin, version, artifactName string) (*db.PluginArtifact, error) {
+	row := r.exec.QueryRowContext(ctx, `SELECT id, plugin_name, version, artifact_name, kind, source_url, checksum, format, local_path, size_bytes, created_at, updated_at FROM plugin_artifacts WHERE plugin_name = $1 AND version = $2 AND artifact_name = $3;`, plugin, version, artifactName)
+	artifact, err := scanPluginArtifact(row)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &artifact, nil
+}
+
+func (r *pluginArtifactRepository) DeleteByPluginVersion(ctx context.Context, plugin, version string) error {
+	if _, err := r.exec.ExecContext(ctx, `DELETE FROM plugin_artifacts WHERE plugin_name = $1 AND version = $2;`, plugin, version); err != nil {
+		return fmt.Errorf("delete plugin artifacts by version: %w", err)
+	}
+	return nil
+}
+
+func (r *pluginArtifactRepository) DeleteByPlugin(ctx context.Context, plugin string) error {
+	if _, err := r.exec.ExecContext(ctx, `DELETE FROM plugin_artifacts WHERE plugin_name = $1;`, plugin); err != nil {
+		return fmt.Errorf("delete plugin artifacts: %w", err)
+	}
+	return nil
+}
+
+func (r *pluginVersionRepository) Upsert(ctx context.Context, version db.PluginVersion) error {
+	meta := string(version.Metadata)
+	if _, err := r.exec.ExecContext(ctx, `INSERT INTO plugin_versions (plugin_name, version, metadata)
+		VALUES ($1, $2, $3)
+		ON CONFLICT(plugin_name, version) DO UPDATE SET metadata = excluded.metadata;`,
+		version.PluginName, version.Version, meta); err != nil {
+		return fmt.Errorf("upsert plugin version: %w", err)
+	}
+	return nil
+}
+
+func (r *pluginVersionRepository) ListByPlugin(ctx context.Context, plugin string) ([]db.PluginVersion, error) {
+	rows, err := r.exec.QueryContext(ctx, `SELECT id, plugin_name, version, metadata, installed_at FROM plugin_versions WHERE plugin_name = $1 ORDER BY installed_at DESC, id DESC;`, plugin)
+	if err != nil {
+		return nil, fmt.Errorf("list plugin versions: %w", err)
+	}
+	defer rows.Close()
+
+	var result []db.PluginVersion
+	for rows.Next() {
+		version, err := scanPluginVersion(rows)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, version)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate plugin versions: %w", err)
+	}
+	return result, nil
+}
+
+func (r *pluginVersionRepository) Get(ctx context.Context, plugin, version string) (*db.PluginVersion, error) {
+	row := r.exec.QueryRowContext(ctx, `SELECT id, plugin_name, version, metadata, installed_at FROM plugin_versions WHERE plugin_name = $1 AND version = $2;`, plugin, version)
+	record, err := scanPluginVersion(row)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &record, nil
+}
+
+func (r *pluginVersionRepository) Delete(ctx context.Context, plugin, version string) error {
+	if _, err := r.exec.ExecContext(ctx, `DELETE FROM plugin_versions WHERE plugin_name = $1 AND version = $2;`, plugin, version); err != nil {
+		return fmt.Errorf("delete plugin version: %w", err)
+	}
+	return nil
+}
+
+func (r *pluginVersionRepository) DeleteByPlugin(ctx context.Context, plugin string) error {
+	if _, err := r.exec.ExecContext(ctx, `DELETE FROM plugin_versions WHERE plugin_name = $1;`, plugin); err != nil {
+		return fmt.Errorf("delete plugin versions: %w", err)
+	}
+	return nil
+}
+
+func (r *vmCloudInitRepository) Upsert(ctx context.Context, record db.VMCloudInit) error {
+	if _, err := r.exec.ExecContext(ctx, `INSERT INTO vm_cloudinit (vm_id, user_data, meta_data, network_config, seed_path)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT(vm_id) DO UPDATE SET user_data = excluded.user_data, meta_data = excluded.meta_data, network_config = excluded.network_config, seed_path = excluded.seed_path, updated_at = CURRENT_TIMESTAMP;`,
+		record.VMID, record.UserData, record.MetaData, record.NetworkConfig, record.SeedPath); err != nil {
+		return fmt.Errorf("upsert vm cloudinit: %w", err)
+	}
+	return nil
+}
+
+func (r *vmCloudInitRepository) Get(ctx context.Context, vmID int64) (*db.VMCloudInit, error) {
+	row := r.exec.QueryRowContext(ctx, `SELECT vm_id, user_data, meta_data, network_config, seed_path, updated_at FROM vm_cloudinit WHERE vm_id = $1;`, vmID)
+	record, err := scanVMCloudInit(row)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &record, nil
+}
+
+func (r *vmCloudInitRepository) Delete(ctx context.Context, vmID int64) error {
+	if _, err := r.exec.ExecContext(ctx, `DELETE FROM vm_cloudinit WHERE vm_id = $1;`, vmID); err != nil {
+		return fmt.Errorf("delete vm cloudinit: %w", err)
+	}
+	return nil
+}
+
+func (r *vmConfigRepository) GetCurrent(ctx context.Context, vmID int64) (*db.VMConfig, error) {
+	row := r.exec.QueryRowContext(ctx, `SELECT vm_id, version, config_json, updated_at FROM vm_configs WHERE vm_id = $1;`, vmID)
+	cfg, err := scanVMConfig(row)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &cfg, nil
+}
+
+func (r *vmConfigRepository) Upsert(ctx context.Context, vmID int64, payload []byte) (*db.VMConfig, error) {
+	var currentVersion int
+	row := r.exec.QueryRowContext(ctx, `SELECT version FROM vm_configs WHERE vm_id = $1;`, vmID)
+	switch err := row.Scan(&currentVersion); err {
+	case nil:
+	case sql.ErrNoRows:
+		currentVersion = 0
+	default:
+		return nil, fmt.Errorf("select vm config version: %w", err)
+	}
+
+	nextVersion := currentVersion + 1
+	configText := string(payload)
+
+	if _, err := r.exec.ExecContext(ctx, `INSERT INTO vm_configs (vm_id, config_json, version, updated_at)
+	VALUES ($1, $2, $3, CURRENT_TIMESTAMP)
+	ON CONFLICT(vm_id) DO UPDATE SET config_json = excluded.config_json, version = excluded.version, updated_at = CURRENT_TIMESTAMP;`, vmID, configText, nextVersion); err != nil {
+		return nil, fmt.Errorf("upsert vm config: %w", err)
+	}
+
+	if _, err := r.exec.ExecContext(ctx, `INSERT INTO vm_config_history (vm_id, version, config_json, updated_at) VALUES ($1, $2, $3, CURRENT_TIMESTAMP);`, vmID, nextVersion, configText); err != nil {
+		return nil, fmt.Errorf("insert vm config history: %w", err)
+	}
+
+	row = r.exec.QueryRowContext(ctx, `SELECT vm_id, version, config_json, updated_at FROM vm_configs WHERE vm_id = $1;`, vmID)
+	cfg, err := scanVMConfig(row)
+	if err != nil {
+		return nil, err
+	}
+	return &cfg, nil
+}
+
+func (r *vmConfigRepository) History(ctx context.Context, vmID int64, limit int) ([]db.VMConfigHistoryEntry, error) {
+	baseQuery := `SELECT id, vm_id, version, config_json, updated_at, outcome, outcome_message FROM vm_config_history WHERE vm_id = $1 ORDER BY version DESC`
+	var (
+		rows *sql.Rows
+		err  error
+	)
+	if limit > 0 {
+		rows, err = r.exec.QueryContext(ctx, baseQuery+" LIMIT $2", vmID, limit)
+	} else {
+		rows, err = r.exec.QueryContext(ctx, baseQuery, vmID)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("query vm config history: %w", err)
+	}
+	defer rows.Close()
+
+	var entries []db.VMConfigHistoryEntry
+	for rows.Next() {
+		record, scanErr := scanVMConfigHistory(rows)
+		if scanErr != nil {
+			return nil, scanErr
+		}
+		entries = append(entries, record)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate vm config history: %w", err)
+	}
+	return entries, nil
+}
+
+func (r *vmConfigRepository) SetOutcome(ctx context.Context, vmID int64, version int, outcome, message string) error {
+	res, err := r.exec.ExecContext(ctx, `UPDATE vm_config_history SET outcome = $1, outcome_message = $2 WHERE vm_id = $3 AND version = $4;`, nullableString(outcome), nullableString(message), vmID, version)
+	if err != nil {
+		return fmt.Errorf("set vm config outcome: %w", err)
+	}
+	if affected, err := res.RowsAffected(); err == nil && affected == 0 {
+		return fmt.Errorf("set vm config outcome: version %d not found", version)
+	}
+	return nil
+}
+
+const scheduleColumns = `id, name, cron, action, target_kind, target, replicas, enabled, last_run_at, created_at, updated_at`
+
+func (r *scheduleRepository) Create(ctx context.Context, schedule *db.Schedule) (int64, error) {
+	var replicas any
+	if schedule.Replicas != nil {
+		replicas = *schedule.Replicas
+	}
+	var id int64
+	if err := r.exec.QueryRowContext(ctx, `INSERT INTO schedules (name, cron, action, target_kind, target, replicas, enabled) VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING id;`,
+		schedule.Name, schedule.Cron, schedule.Action, schedule.TargetKind, schedule.Target, replicas, schedule.Enabled).Scan(&id); err != nil {
+		return 0, fmt.Errorf("insert schedule: %w", err)
+	}
+	return id, nil
+}
+
+func (r *scheduleRepository) GetByName(ctx context.Context, name string) (*db.Schedule, error) {
+	row := r.exec.QueryRowContext(ctx, `SELECT `+scheduleColumns+` FROM schedules WHERE name = $1;`, name)
+	schedule, err := scanSchedule(row)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &schedule, nil
+}
+
+func (r *scheduleRepository) List(ctx context.Context) ([]db.Schedule, error) {
+	rows, err := r.exec.QueryContext(ctx, `SELECT `+scheduleColumns+` FROM schedules ORDER BY name ASC;`)
+	if err != nil {
+		return nil, fmt.Errorf("list schedules: %w", err)
+	}
+	defer rows.Close()
+
+	var result []db.Schedule
+	for rows.Next() {
+		schedule, err := scanSchedule(rows)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, schedule)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate schedules: %w", err)
+	}
+	return result, nil
+}
+
+func (r *scheduleRepository) SetEnabled(ctx context.Context, id int64, enabled bool) error {
+	if _, err := r.exec.ExecContext(ctx, `UPDATE schedules SET enabled = $1, updated_at = CURRENT_TIMESTAMP WHERE id = $2;`, enabled, id); err != nil {
+		return fmt.Errorf("update schedule enabled: %w", err)
+	}
+	return nil
+}
+
+func (r *scheduleRepository) Delete(ctx context.Context, id int64) error {
+	if _, err := r.exec.ExecContext(ctx, `DELETE FROM schedules WHERE id = $1;`, id); err != nil {
+		return fmt.Errorf("delete schedule: %w", err)
+	}
+	return nil
+}
+
+// RecordRun appends a history entry and stamps the schedule's last run time.
+func (r *scheduleRepository) RecordRun(ctx context.Context, run db.ScheduleRun) (int64, error) {
+	started := run.StartedAt.UTC()
+	finished := run.FinishedAt.UTC()
+	var id int64
+	if err := r.exec.QueryRowContext(ctx, `INSERT INTO schedule_runs (schedule_id, status, message, started_at, finished_at) VALUES ($1, $2, $3, $4, $5) RETURNING id;`,
+		run.ScheduleID, run.Status, nullableString(run.Message), started, finished).Scan(&id); err != nil {
+		return 0, fmt.Errorf("insert schedule run: %w", err)
+	}
+	if _, err := r.exec.ExecContext(ctx, `UPDATE schedules SET last_run_at = $1 WHERE id = $2;`, started, run.ScheduleID); err != nil {
+		return 0, fmt.Errorf("update schedule last run: %w", err)
+	}
+	return id, nil
+}
+
+func (r *scheduleRepository) Runs(ctx context.Context, scheduleID int64, limit int) ([]db.ScheduleRun, error) {
+	if limit <= 0 {
+		limit = 50
+	}
+	rows, err := r.exec.QueryContext(ctx, `SELECT id, schedule_id, status, message, started_at, finished_at FROM schedule_runs WHERE schedule_id = $1 ORDER BY id DESC LIMIT $2;`, scheduleID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("list schedule runs: %w", err)
+	}
+	defer rows.Close()
+
+	var result []db.ScheduleRun
+	for rows.Next() {
+		run, err := scanScheduleRun(rows)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, run)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate schedule runs: %w", err)
+	}
+	return result, nil
+}
+
+const secretColumns = `id, name, sealed, created_at, updated_at`
+
+func (r *secretRepository) Create(ctx context.Context, secret *db.Secret) (int64, error) {
+	var id int64
+	if err := r.exec.QueryRowContext(ctx, `INSERT INTO secrets (name, sealed) VALUES ($1, $2) RETURNING id;`, secret.Name, secret.Sealed).Scan(&id); err != nil {
+		return 0, fmt.Errorf("insert secret: %w", err)
+	}
+	return id, nil
+}
+
+func (r *secretRepository) GetByName(ctx context.Context, name string) (*db.Secret, error) {
+	row := r.exec.QueryRowContext(ctx, `SELECT `+secretColumns+` FROM secrets WHERE name = $1;`, name)
+	secret, err := scanSecret(row)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &secret, nil
+}
+
+func (r *secretRepository) List(ctx context.Context) ([]db.Secret, error) {
+	rows, err := r.exec.QueryContext(ctx, `SELECT `+secretColumns+` FROM secrets ORDER BY name ASC;`)
+	if err != nil {
+		return nil, fmt.Errorf("list secrets: %w", err)
+	}
+	defer rows.Close()
+
+	var result []db.Secret
+	for rows.Next() {
+		secret, err := scanSecret(rows)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, secret)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate secrets: %w", err)
+	}
+	return result, nil
+}
+
+func (r *secretRepository) Update(ctx context.Context, id int64, sealed []byte) error {
+	if _, err := r.exec.ExecContext(ctx, `UPDATE secrets SET sealed = $1, updated_at = CURRENT_TIMESTAMP WHERE id = $2;`, sealed, id); err != nil {
+		return fmt.Errorf("update secret: %w", err)
+	}
+	return nil
+}
+
+func (r *secretRepository) Delete(ctx context.Context, id int64) error {
+	if _, err := r.exec.ExecContext(ctx, `DELETE FROM secrets WHERE id = $1;`, id); err != nil {
+		return fmt.Errorf("delete secret: %w", err)
+	}
+	return nil
+}
+
+func scanSecret(row rowScanner) (db.Secret, error) {
+	var (
+		secret     db.Secret
+		sealed     []byte
+		createdRaw any
+		updatedRaw any
+	)
+	if err := row.Scan(&secret.ID, &secret.Name, &sealed, &createdRaw, &updatedRaw); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return db.Secret{}, err
+		}
+		return db.Secret{}, fmt.Errorf("scan secret: %w", err)
+	}
+	secret.Sealed = append([]byte(nil), sealed...)
+	created, err := parseTimestamp(createdRaw)
+	if err != nil {
+		return db.Secret{}, fmt.Errorf("parse secret created: %w", err)
+	}
+	updated, err := parseTimestamp(updatedRaw)
+	if err != nil {
+		return db.Secret{}, fmt.Errorf("parse secret updated: %w", err)
+	}
+	secret.CreatedAt = created
+	secret.UpdatedAt = updated
+	return secret, nil
+}
+
+const warmPoolColumns = `id, plugin, size, config_json, created_at, updated_at`
+
+func (r *warmPoolRepository) Upsert(ctx context.Context, pool *db.WarmPool) error {
+	if _, err := r.exec.ExecContext(ctx, `INSERT INTO warm_pools (plugin, size, config_json) VALUES ($1, $2, $3) ON CONFLICT(plugin) DO UPDATE SET size = excluded.size, config_json = excluded.config_json, updated_at = CURRENT_TIMESTAMP;`, pool.Plugin, pool.Size, string(pool.ConfigJSON)); err != nil {
+		return fmt.Errorf("upsert warm pool: %w", err)
+	}
+	return nil
+}
+
+func (r *warmPoolRepository) GetByPlugin(ctx context.Context, plugin string) (*db.WarmPool, error) {
+	row := r.exec.QueryRowContext(ctx, `SELECT `+warmPoolColumns+` FROM warm_pools WHERE plugin = $1;`, plugin)
+	pool, err := scanWarmPool(row)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &pool, nil
+}
+
+func (r *warmPoolRepository) List(ctx context.Context) ([]db.WarmPool, error) {
+	rows, err := r.exec.QueryContext(ctx, `SELECT `+warmPoolColumns+` FROM warm_pools ORDER BY plugin ASC;`)
+	if err != nil {
+		return nil, fmt.Errorf("list warm pools: %w", err)
+	}
+	defer rows.Close()
+
+	var result []db.WarmPool
+	for rows.Next() {
+		pool, err := scanWarmPool(rows)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, pool)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate warm pools: %w", err)
+	}
+	return result, nil
+}
+
+func (r *warmPoolRepository) Delete(ctx context.Context, id int64) error {
+	if _, err := r.exec.ExecContext(ctx, `DELETE FROM warm_pools WHERE id = $1;`, id); err != nil {
+		return fmt.Errorf("delete warm pool: %w", err)
+	}
+	return nil
+}
+
+func scanWarmPool(row rowScanner) (db.WarmPool, error) {
+	var (
+		pool       db.WarmPool
+		configText string
+		createdRaw any
+		updatedRaw any
+	)
+	if err := row.Scan(&pool.ID, &pool.Plugin, &pool.Size, &configText, &createdRaw, &updatedRaw); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return db.WarmPool{}, err
+		}
+		return db.WarmPool{}, fmt.Errorf("scan warm pool: %w", err)
+	}
+	pool.ConfigJSON = []byte(configText)
+	created, err := parseTimestamp(createdRaw)
+	if err != nil {
+		return db.WarmPool{}, fmt.Errorf("parse warm pool created: %w", err)
+	}
+	updated, err := parseTimestamp(updatedRaw)
+	if err != nil {
+		return db.WarmPool{}, fmt.Errorf("parse warm pool updated: %w", err)
+	}
+	pool.CreatedAt = created
+	pool.UpdatedAt = updated
+	return pool, nil
+}
+
+const webhookColumns = `id, name, url, events, secret, enabled, created_at, updated_at`
+
+func (r *webhookRepository) Create(ctx context.Context, webhook *db.Webhook) (int64, error) {
+	var id int64
+	if err := r.exec.QueryRowContext(ctx, `INSERT INTO webhooks (name, url, events, secret, enabled) VALUES ($1, $2, $3, $4, $5) RETURNING id;`,
+		webhook.Name, webhook.URL, strings.Join(webhook.Events, ","), webhook.Secret, webhook.Enabled).Scan(&id); err != nil {
+		return 0, fmt.Errorf("insert webhook: %w", err)
+	}
+	return id, nil
+}
+
+func (r *webhookRepository) GetByName(ctx context.Context, name string) (*db.Webhook, error) {
+	row := r.exec.QueryRowContext(ctx, `SELECT `+webhookColumns+` FROM webhooks WHERE name = $1;`, name)
+	webhook, err := scanWebhook(row)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &webhook, nil
+}
+
+func (r *webhookRepository) List(ctx context.Context) ([]db.Webhook, error) {
+	rows, err := r.exec.QueryContext(ctx, `SELECT `+webhookColumns+` FROM webhooks ORDER BY name ASC;`)
+	if err != nil {
+		return nil, fmt.Errorf("list webhooks: %w", err)
+	}
+	defer rows.Close()
+
+	var result []db.Webhook
+	for rows.Next() {
+		webhook, err := scanWebhook(rows)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, webhook)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate webhooks: %w", err)
+	}
+	return result, nil
+}
+
+func (r *webhookRepository) SetEnabled(ctx context.Context, id int64, enabled bool) error {
+	if _, err := r.exec.ExecContext(ctx, `UPDATE webhooks SET enabled = $1, updated_at = CURRENT_TIMESTAMP WHERE id = $2;`, enabled, id); err != nil {
+		return fmt.Errorf("update webhook enabled: %w", err)
+	}
+	return nil
+}
+
+func (r *webhookRepository) Delete(ctx context.Context, id int64) error {
+	if _, err := r.exec.ExecContext(ctx, `DELETE FROM webhooks WHERE id = $1;`, id); err != nil {
+		return fmt.Errorf("delete webhook: %w", err)
+	}
+	return nil
+}
+
+func (r *webhookRepository) RecordDelivery(ctx context.Context, delivery db.WebhookDelivery) (int64, error) {
+	var id int64
+	if err := r.exec.QueryRowContext(ctx, `INSERT INTO webhook_deliveries (webhook_id, delivery_id, event_type, attempt, status_code, error, succeeded, payload, duration_ms, created_at) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10) RETURNING id;`,
+		delivery.WebhookID, delivery.DeliveryID, delivery.EventType, delivery.Attempt, delivery.StatusCode, nullableString(delivery.Error),
+		delivery.Succeeded, delivery.Payload, delivery.Duration.Milliseconds(), delivery.CreatedAt.UTC()).Scan(&id); err != nil {
+		return 0, fmt.Errorf("insert webhook delivery: %w", err)
+	}
+	return id, nil
+}
+
+func (r *webhookRepository) Deliveries(ctx context.Context, webhookID int64, limit int) ([]db.WebhookDelivery, error) {
+	if limit <= 0 {
+		limit = 50
+	}
+	rows, err := r.exec.QueryContext(ctx, `SELECT id, webhook_id, delivery_id, event_type, attempt, status_code, error, succeeded, payload, duration_ms, created_at FROM webhook_deliveries WHERE webhook_id = $1 ORDER BY id DESC LIMIT $2;`, webhookID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("list webhook deliveries: %w", err)
+	}
+	defer rows.Close()
+
+	var result []db.WebhookDelivery
+	for rows.Next() {
+		delivery, err := scanWebhookDelivery(rows)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, delivery)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate webhook deliveries: %w", err)
+	}
+	return result, nil
+}
+
+func (r *webhookRepository) DeleteDeliveriesBefore(ctx context.Context, cutoff time.Time) (int64, error) {
+	res, err := r.exec.ExecContext(ctx, `DELETE FROM webhook_deliveries WHERE created_at < $1;`, cutoff.UTC())
+	if err != nil {
+		return 0, fmt.Errorf("delete webhook deliveries: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("webhook deliveries rows affected: %w", err)
+	}
+	return n, nil
+}
+
+func (r *auditRepository) Record(ctx context.Context, event db.AuditEvent) (int64, error) {
+	var duration any
+	if event.Duration > 0 {
+		duration = event.Duration.Milliseconds()
+	}
+	var id int64
+	if err := r.exec.QueryRowContext(ctx, `INSERT INTO audit_events (action, target, actor, client_ip, message, duration_ms) VALUES ($1, $2, $3, $4, $5, $6) RETURNING id;`,
+		event.Action, event.Target, event.Actor, nullableString(event.ClientIP), nullableString(event.Message), duration).Scan(&id); err != nil {
+		return 0, fmt.Errorf("insert audit event: %w", err)
+	}
+	return id, nil
+}
+
+func (r *auditRepository) List(ctx context.Context, target string, limit int) ([]db.AuditEvent, error) {
+	if limit <= 0 {
+		limit = 50
+	}
+	rows, err := r.exec.QueryContext(ctx, `SELECT id, action, target, actor, client_ip, message, duration_ms, created_at FROM audit_events WHERE ($1 = '' OR target = $2) ORDER BY id DESC LIMIT $3;`, target, target, limit)
+	if err != nil {
+		return nil, fmt.Errorf("list audit events: %w", err)
+	}
+	defer rows.Close()
+
+	var result []db.AuditEvent
+	for rows.Next() {
+		event, err := scanAuditEvent(rows)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, event)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate audit events: %w", err)
+	}
+	return result, nil
+}
+
+const vmEventColumns = `id, vm_name, type, status, message, code, hint, created_at`
+
+func (r *vmEventRepository) Record(ctx context.Context, event db.VMEvent) (int64, error) {
+	var id int64
+	if err := r.exec.QueryRowContext(ctx, `INSERT INTO vm_events (vm_name, type, status, message, code, hint, created_at) VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING id;`,
+		event.VMName, event.Type, nullableString(event.Status), nullableString(event.Message), nullableString(event.Code), nullableString(event.Hint), event.CreatedAt.UTC()).Scan(&id); err != nil {
+		return 0, fmt.Errorf("insert vm event: %w", err)
+	}
+	return id, nil
+}
+
+func (r *vmEventRepository) List(ctx context.Context, query db.VMEventQuery) ([]db.VMEvent, error) {
+	var (
+		clauses []string
+		args    []any
+	)
+	// arg binds v and returns its positional placeholder.
+	arg := func(v any) string {
+		args = append(args, v)
+		return fmt.Sprintf("$%d", len(args))
+	}
+	if query.VMName != "" {
+		clauses = append(clauses, "vm_name = "+arg(query.VMName))
+	}
+	if !query.Since.IsZero() {
+		clauses = append(clauses, "created_at >= "+arg(query.Since.UTC()))
+	}
+	if len(query.Types) > 0 {
+		placeholders := make([]string, len(query.Types))
+		for i, typ := range query.Types {
+			placeholders[i] = arg(typ)
+		}
+		clauses = append(clauses, "type IN ("+strings.Join(placeholders, ", ")+")")
+	}
+	where := ""
+	if len(clauses) > 0 {
+		where = " WHERE " + strings.Join(clauses, " AND ")
+	}
+	limit := query.Limit
+	if limit <= 0 {
+		limit = 100
+	}
+	rows, err := r.exec.QueryContext(ctx, `SELECT `+vmEventColumns+` FROM vm_events`+where+` ORDER BY id DESC LIMIT `+arg(limit)+`;`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("list vm events: %w", err)
+	}
+	defer rows.Close()
+
+	var result []db.VMEvent
+	for rows.Next() {
+		event, err := scanVMEvent(rows)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, event)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate vm events: %w", err)
+	}
+	slices.Reverse(result)
+	return result, nil
+}
+
+func (r *vmEventRepository) DeleteBefore(ctx context.Context, cutoff time.Time) (int64, error) {
+	res, err := r.exec.ExecContext(ctx, `DELETE FROM vm_events WHERE created_at < $1;`, cutoff.UTC())
+	if err != nil {
+		return 0, fmt.Errorf("delete vm events: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("vm events rows affected: %w", err)
+	}
+	return n, nil
+}
+
+const networkColumns = `id, name, bridge, subnet, gateway, internal, created_at`
+
+func (r *networkRepository) Create(ctx context.Context, network *db.Network) (int64, error) {
+	var id int64
+	if err := r.exec.QueryRowContext(ctx, `INSERT INTO networks (name, bridge, subnet, gateway, internal) VALUES ($1, $2, $3, $4, $5) RETURNING id;`,
+		network.Name, network.Bridge, network.Subnet, network.Gateway, network.Internal).Scan(&id); err != nil {
+		return 0, fmt.Errorf("insert network: %w", err)
+	}
+	return id, nil
+}
+
+func (r *networkRepository) GetByName(ctx context.Context, name string) (*db.Network, error) {
+	row := r.exec.QueryRowContext(ctx, `SELECT `+networkColumns+` FROM networks WHERE name = $1;`, name)
+	network, err := scanNetwork(row)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &network, nil
+}
+
+func (r *networkRepository) List(ctx context.Context) ([]db.Network, error) {
+	rows, err := r.exec.QueryContext(ctx, `SELECT `+networkColumns+` FROM networks ORDER BY name ASC;`)
+	if err != nil {
+		return nil, fmt.Errorf("list networks: %w", err)
+	}
+	defer rows.Close()
+
+	var result []db.Network
+	for rows.Next() {
+		network, err := scanNetwork(rows)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, network)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate networks: %w", err)
+	}
+	return result, nil
+}
+
+func (r *networkRepository) Delete(ctx context.Context, id int64) error {
+	if _, err := r.exec.ExecContext(ctx, `DELETE FROM networks WHERE id = $1;`, id); err != nil {
+		return fmt.Errorf("delete network: %w", err)
+	}
+	return nil
+}
+
+func (r *networkRepository) Leases(ctx context.Context, networkID int64) ([]db.NetworkLease, error) {
+	rows, err := r.exec.QueryContext(ctx, `SELECT l.network_id, l.ip_address, l.vm_id, v.name, l.leased_at
+		FROM network_leases l JOIN vms v ON v.id = l.vm_id WHERE l.network_id = $1 ORDER BY l.ip_address ASC;`, networkID)
+	if err != nil {
+		return nil, fmt.Errorf("list network leases: %w", err)
+	}
+	defer rows.Close()
+
+	var result []db.NetworkLease
+	for rows.Next() {
+		lease, err := scanNetworkLease(rows)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, lease)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate network leases: %w", err)
+	}
+	return result, nil
+}
+
+func (r *networkRepository) Lease(ctx context.Context, networkID int64, ip string, vmID int64) error {
+	if _, err := r.exec.ExecContext(ctx, `INSERT INTO network_leases (network_id, ip_address, vm_id) VALUES ($1, $2, $3);`, networkID, ip, vmID); err != nil {
+		return fmt.Errorf("insert network lease: %w", err)
+	}
+	return nil
+}
+
+func (r *networkRepository) LeaseForVM(ctx context.Context, vmID int64) (*db.NetworkLease, error) {
+	row := r.exec.QueryRowContext(ctx, `SELECT l.network_id, l.ip_address, l.vm_id, v.name, l.leased_at
+		FROM network_leases l JOIN vms v ON v.id = l.vm_id WHERE l.vm_id = $1;`, vmID)
+	lease, err := scanNetworkLease(row)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &lease, nil
+}
+
+const configBundleColumns = `id, name, data_json, files_json, version, created_at, updated_at`
+
+func (r *configBundleRepository) Create(ctx context.Context, bundle *db.ConfigBundle) (int64, error) {
+	var id int64
+	if err := r.exec.QueryRowContext(ctx, `INSERT INTO config_bundles (name, data_json, files_json) VALUES ($1, $2, $3) RETURNING id;`,
+		bundle.Name, string(bundle.DataJSON), string(bundle.FilesJSON)).Scan(&id); err != nil {
+		return 0, fmt.Errorf("insert config bundle: %w", err)
+	}
+	return id, nil
+}
+
+func (r *configBundleRepository) GetByName(ctx context.Context, name string) (*db.ConfigBundle, error) {
+	row := r.exec.QueryRowContext(ctx, `SELECT `+configBundleColumns+` FROM config_bundles WHERE name = $1;`, name)
+	bundle, err := scanConfigBundle(row)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &bundle, nil
+}
+
+func (r *configBundleRepository) List(ctx context.Context) ([]db.ConfigBundle, error) {
+	rows, err := r.exec.QueryContext(ctx, `SELECT `+configBundleColumns+` FROM config_bundles ORDER BY name ASC;`)
+	if err != nil {
+		return nil, fmt.Errorf("list config bundles: %w", err)
+	}
+	defer rows.Close()
+
+	var result []db.ConfigBundle
+	for rows.Next() {
+		bundle, err := scanConfigBundle(rows)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, bundle)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate config bundles: %w", err)
+	}
+	return result, nil
+}
+
+func (r *configBundleRepository) Update(ctx context.Context, id int64, dataJSON, filesJSON []byte) error {
+	if _, err := r.exec.ExecContext(ctx, `UPDATE config_bundles SET data_json = $1, files_json = $2, version = version + 1, updated_at = CURRENT_TIMESTAMP WHERE id = $3;`,
+		string(dataJSON), string(filesJSON), id); err != nil {
+		return fmt.Errorf("update config bundle: %w", err)
+	}
+	return nil
+}
+
+func (r *configBundleRepository) Delete(ctx context.Context, id int64) error {
+	if _, err := r.exec.ExecContext(ctx, `DELETE FROM config_bundles WHERE id = $1;`, id); err != nil {
+		return fmt.Errorf("delete config bundle: %w", err)
+	}
+	return nil
+}
+
+const idempotencyKeyColumns = `scope, key, request_hash, status_code, response, created_at, expires_at`
+
+func (r *idempotencyKeyRepository) Reserve(ctx context.Context, key db.IdempotencyKey) (*db.IdempotencyKey, bool, error) {
+	now := key.CreatedAt
+	if now.IsZero() {
+		now = time.Now()
+	}
+	if _, err := r.exec.ExecContext(ctx, `DELETE FROM idempotency_keys WHERE scope = $1 AND key = $2 AND expires_at <= $3;`,
+		key.Scope, key.Key, now.UTC()); err != nil {
+		return nil, false, fmt.Errorf("delete expired idempotency key: %w", err)
+	}
+	res, err := r.exec.ExecContext(ctx, `INSERT INTO idempotency_keys (scope, key, request_hash, created_at, expires_at)
+		VALUES ($1, $2, $3, $4, $5) ON CONFLICT(scope, key) DO NOTHING;`,
+		key.Scope, key.Key, key.RequestHash, now.UTC(), key.ExpiresAt.UTC())
+	if err != nil {
+		return nil, false, fmt.Errorf("insert idempotency key: %w", err)
+	}
+	if n, err := res.RowsAffected(); err != nil {
+		return nil, false, fmt.Errorf("idempotency key rows affected: %w", err)
+	} else if n == 1 {
+		return nil, true, nil
+	}
+	row := r.exec.QueryRowContext(ctx, `SELECT `+idempotencyKeyColumns+` FROM idempotency_keys WHERE scope = $1 AND key = $2;`, key.Scope, key.Key)
+	existing, err := scanIdempotencyKey(row)
+	if err != nil {
+		return nil, false, err
+	}
+	return &existing, false, nil
+}
+
+func (r *idempotencyKeyRepository) Complete(ctx context.Context, scope, key string, statusCode int, response []byte) error {
+	if _, err := r.exec.ExecContext(ctx, `UPDATE idempotency_keys SET status_code = $1, response = $2 WHERE scope = $3 AND key = $4;`,
+		statusCode, response, scope, key); err != nil {
+		return fmt.Errorf("complete idempotency key: %w", err)
+	}
+	return nil
+}
+
+func (r *idempotencyKeyRepository) Delete(ctx context.Context, scope, key string) error {
+	if _, err := r.exec.ExecContext(ctx, `DELETE FROM idempotency_keys WHERE scope = $1 AND key = $2;`, scope, key); err != nil {
+		return fmt.Errorf("delete idempotency key: %w", err)
+	}
+	return nil
+}
+
+func (r *idempotencyKeyRepository) DeleteExpired(ctx context.Context, now time.Time) (int64, error) {
+	res, err := r.exec.ExecContext(ctx, `DELETE FROM idempotency_keys WHERE expires_at <= $1;`, now.UTC())
+	if err != nil {
+		return 0, fmt.Errorf("delete expired idempotency keys: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("idempotency keys rows affected: %w", err)
+	}
+	return n, nil
+}
+
+const operationColumns = `id, kind, target, status, error, code, hint, created_at, updated_at, completed_at`
+
+func (r *operationRepository) Create(ctx context.Context, op db.Operation) error {
+	now := op.CreatedAt
+	if now.IsZero() {
+		now = time.Now()
+	}
+	if _, err := r.exec.ExecContext(ctx, `INSERT INTO operations (id, kind, target, status, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6);`,
+		op.ID, op.Kind, op.Target, string(op.Status), now.UTC(), now.UTC()); err != nil {
+		return fmt.Errorf("insert operation: %w", err)
+	}
+	return nil
+}
+
+func (r *operationRepository) Get(ctx context.Context, id string) (*db.Operation, error) {
+	row := r.exec.QueryRowContext(ctx, `SELECT `+operationColumns+` FROM operations WHERE id = $1;`, id)
+	op, err := scanOperation(row)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &op, nil
+}
+
+func (r *operationRepository) Update(ctx context.Context, op db.Operation) error {
+	now := op.UpdatedAt
+	if now.IsZero() {
+		now = time.Now()
+	}
+	var completed any
+	if op.Status == db.OperationStatusSucceeded || op.Status == db.OperationStatusFailed {
+		completed = now.UTC()
+	}
+	if _, err := r.exec.ExecContext(ctx, `UPDATE operations SET status = $1, error = $2, code = $3, hint = $4, updated_at = $5, completed_at = $6 WHERE id = $7;`,
+		string(op.Status), nullableString(op.Error), nullableString(op.Code), nullableString(op.Hint), now.UTC(), completed, op.ID); err != nil {
+		return fmt.Errorf("update operation: %w", err)
+	}
+	return nil
+}
+
+func (r *operationRepository) FailUnfinished(ctx context.Context, message string, at time.Time) (int64, error) {
+	ts := at.UTC()
+	res, err := r.exec.ExecContext(ctx, `UPDATE operations SET status = $1, error = $2, updated_at = $3, completed_at = $4 WHERE status IN ($5, $6);`,
+		string(db.OperationStatusFailed), message, ts, ts, string(db.OperationStatusPending), string(db.OperationStatusRunning))
+	if err != nil {
+		return 0, fmt.Errorf("fail unfinished operations: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("operations rows affected: %w", err)
+	}
+	return n, nil
+}
+
+func (r *dhcpLeaseRepository) Upsert(ctx context.Context, lease db.DHCPLease) error {
+	_, err := r.exec.ExecContext(ctx, `INSERT INTO dhcp_leases (mac_address, ip_address, vm_id, hostname, expires_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT(mac_address) DO UPDATE SET ip_address = excluded.ip_address, vm_id = excluded.vm_id,
+			hostname = excluded.hostname, expires_at = excluded.expires_at, updated_at = excluded.updated_at;`,
+		strings.ToLower(lease.MACAddress), lease.IPAddress, lease.VMID, nullableString(lease.Hostname),
+		lease.ExpiresAt.UTC(), time.Now().UTC())
+	if err != nil {
+		return fmt.Errorf("upsert dhcp lease: %w", err)
+	}
+	return nil
+}
+
+func (r *dhcpLeaseRepository) Delete(ctx context.Context, mac string) error {
+	if _, err := r.exec.ExecContext(ctx, `DELETE FROM dhcp_leases WHERE mac_address = $1;`, strings.ToLower(mac)); err != nil {
+		return fmt.Errorf("delete dhcp lease: %w", err)
+	}
+	return nil
+}
+
+func (r *dhcpLeaseRepository) List(ctx context.Context) ([]db.DHCPLease, error) {
+	rows, err := r.exec.QueryContext(ctx, `SELECT l.mac_address, l.ip_address, l.vm_id, v.name, l.hostname, l.expires_at, l.updated_at
+		FROM dhcp_leases l JOIN vms v ON v.id = l.vm_id ORDER BY l.ip_address ASC;`)
+	if err != nil {
+		return nil, fmt.Errorf("list dhcp leases: %w", err)
+	}
+	defer rows.Close()
+
+	var result []db.DHCPLease
+	for rows.Next() {
+		lease, err := scanDHCPLease(rows)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, lease)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate dhcp leases: %w", err)
+	}
+	return result, nil
+}
+
+func (r *deviceAssignmentRepository) Assign(ctx context.Context, assignment db.DeviceAssignment) error {
+	res, err := r.exec.ExecContext(ctx, `INSERT INTO device_assignments (pci_address, vm_id, iommu_group, assigned_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT(pci_address) DO UPDATE SET vm_id = excluded.vm_id, iommu_group = excluded.iommu_group,
+			assigned_at = excluded.assigned_at
+		WHERE device_assignments.vm_id = excluded.vm_id;`,
+		strings.ToLower(assignment.PCIAddress), assignment.VMID, nullableString(assignment.IOMMUGroup), time.Now().UTC())
+	if err != nil {
+		return fmt.Errorf("assign device: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("device assignment rows affected: %w", err)
+	}
+	if n == 0 {
+		return fmt.Errorf("%w: %s", db.ErrDeviceAssigned, assignment.PCIAddress)
+	}
+	return nil
+}
+
+func (r *deviceAssignmentRepository) ReleaseVM(ctx context.Context, vmID int64) error {
+	if _, err := r.exec.ExecContext(ctx, `DELETE FROM device_assignments WHERE vm_id = $1;`, vmID); err != nil {
+		return fmt.Errorf("release device assignments: %w", err)
+	}
+	return nil
+}
+
+func (r *deviceAssignmentRepository) List(ctx context.Context) ([]db.DeviceAssignment, error) {
+	rows, err := r.exec.QueryContext(ctx, `SELECT a.pci_address, a.vm_id, v.name, a.iommu_group, a.assigned_at
+		FROM device_assignments a JOIN vms v ON v.id = a.vm_id ORDER BY a.pci_address ASC;`)
+	if err != nil {
+		return nil, fmt.Errorf("list device assignments: %w", err)
+	}
+	defer rows.Close()
+
+	var result []db.DeviceAssignment
+	for rows.Next() {
+		assignment, err := scanDeviceAssignment(rows)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, assignment)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate device assignments: %w", err)
+	}
+	return result, nil
+}
+
+const maintenanceWindowColumns = `id, name, scope, target, reason, starts_at, ends_at, created_at`
+
+const maintenanceIntentColumns = `id, window_id, source, target, action, count, recorded_at, replayed_at, outcome`
+
+func (r *maintenanceRepository) Create(ctx context.Context, window *db.MaintenanceWindow) (int64, error) {
+	var id int64
+	if err := r.exec.QueryRowContext(ctx, `INSERT INTO maintenance_windows (name, scope, target, reason, starts_at, ends_at) VALUES ($1, $2, $3, $4, $5, $6) RETURNING id;`,
+		window.Name, window.Scope, nullableString(window.Target), nullableString(window.Reason),
+		window.StartsAt.UTC(), window.EndsAt.UTC()).Scan(&id); err != nil {
+		return 0, fmt.Errorf("insert maintenance window: %w", err)
+	}
+	return id, nil
+}
+
+func (r *maintenanceRepository) GetByName(ctx context.Context, name string) (*db.MaintenanceWindow, error) {
+	row := r.exec.QueryRowContext(ctx, `SELECT `+maintenanceWindowColumns+` FROM maintenance_windows WHERE name = $1;`, name)
+	window, err := scanMaintenanceWindow(row)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &window, nil
+}
+
+func (r *maintenanceRepository) List(ctx context.Context) ([]db.MaintenanceWindow, error) {
+	rows, err := r.exec.QueryContext(ctx, `SELECT `+maintenanceWindowColumns+` FROM maintenance_windows ORDER BY starts_at ASC, name ASC;`)
+	if err != nil {
+		return nil, fmt.Errorf("list maintenance windows: %w", err)
+	}
+	defer rows.Close()
+
+	var result []db.MaintenanceWindow
+	for rows.Next() {
+		window, err := scanMaintenanceWindow(rows)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, window)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate maintenance windows: %w", err)
+	}
+	return result, nil
+}
+
+func (r *maintenanceRepository) SetEndsAt(ctx context.Context, id int64, endsAt time.Time) error {
+	if _, err := r.exec.ExecContext(ctx, `UPDATE maintenance_windows SET ends_at = $1 WHERE id = $2;`, endsAt.UTC(), id); err != nil {
+		return fmt.Errorf("update maintenance window end: %w", err)
+	}
+	return nil
+}
+
+func (r *maintenanceRepository) Delete(ctx context.Context, id int64) error {
+	if _, err := r.exec.ExecContext(ctx, `DELETE FROM maintenance_windows WHERE id = $1;`, id); err != nil {
+		return fmt.Errorf("delete maintenance window: %w", err)
+	}
+	return nil
+}
+
+func (r *maintenanceRepository) RecordIntent(ctx context.Context, intent db.MaintenanceIntent) error {
+	recorded := intent.RecordedAt.UTC()
+	res, err := r.exec.ExecContext(ctx, `UPDATE maintenance_intents SET count = count + 1, recorded_at = $1
+		WHERE window_id = $2 AND source = $3 AND target = $4 AND action = $5 AND replayed_at IS NULL;`,
+		recorded, intent.WindowID, intent.Source, intent.Target, intent.Action)
+	if err != nil {
+		return fmt.Errorf("update maintenance intent: %w", err)
+	}
+	if rows, err := res.RowsAffected(); err != nil {
+		return fmt.Errorf("update maintenance intent rows: %w", err)
+	} else if rows > 0 {
+		return nil
+	}
+	if _, err := r.exec.ExecContext(ctx, `INSERT INTO maintenance_intents (window_id, source, target, action, recorded_at) VALUES ($1, $2, $3, $4, $5);`,
+		intent.WindowID, intent.Source, intent.Target, intent.Action, recorded); err != nil {
+		return fmt.Errorf("insert maintenance intent: %w", err)
+	}
+	return nil
+}
+
+func (r *maintenanceRepository) Intents(ctx context.Context, windowID int64) ([]db.MaintenanceIntent, error) {
+	return r.queryIntents(ctx, `SELECT `+maintenanceIntentColumns+` FROM maintenance_intents WHERE window_id = $1 ORDER BY id ASC;`, windowID)
+}
+
+func (r *maintenanceRepository) PendingIntents(ctx context.Context, source string) ([]db.MaintenanceIntent, error) {
+	return r.queryIntents(ctx, `SELECT `+maintenanceIntentColumns+` FROM maintenance_intents WHERE source = $1 AND replayed_at IS NULL ORDER BY id ASC;`, source)
+}
+
+func (r *maintenanceRepository) queryIntents(ctx context.Context, query string, args ...any) ([]db.MaintenanceIntent, error) {
+	rows, err := r.exec.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("list maintenance intents: %w", err)
+	}
+	defer rows.Close()
+
+	var result []db.MaintenanceIntent
+	for rows.Next() {
+		intent, err := scanMaintenanceIntent(rows)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, intent)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate maintenance intents: %w", err)
+	}
+	return result, nil
+}
+
+func (r *maintenanceRepository) MarkReplayed(ctx context.Context, id int64, outcome string, at time.Time) error {
+	if _, err := r.exec.ExecContext(ctx, `UPDATE maintenance_intents SET replayed_at = $1, outcome = $2 WHERE id = $3;`,
+		at.UTC(), nullableString(outcome), id); err != nil {
+		return fmt.Errorf("mark maintenance intent replayed: %w", err)
+	}
+	return nil
+}
+
+func scanVM(row rowScanner) (db.VM, error) {
+	var (
+		vm         db.VM
+		status     string
+		runtime    sql.NullString
+		pid        sql.NullInt64
+		ipv6       sql.NullString
+		cmdline    sql.NullString
+		serial     sql.NullString
+		groupID    sql.NullInt64
+		expiresRaw any
+		createdRaw any
+		updatedRaw any
+	)
+
+	if err := row.Scan(
+		&vm.ID,
+		&vm.Name,
+		&status,
+		&runtime,
+		&pid,
+		&vm.IPAddress,
+		&ipv6,
+		&vm.MACAddress,
+		&vm.VsockCID,
+		&vm.CPUCores,
+		&vm.MemoryMB,
+		&cmdline,
+		&serial,
+		&groupID,
+		&expiresRaw,
+		&createdRaw,
+		&updatedRaw,
+	); err != nil {
+		if err == sql.ErrNoRows {
+			return db.VM{}, err
+		}
+		return db.VM{}, fmt.Errorf("scan vm: %w", err)
+	}
+
+	vm.Status = db.VMStatus(status)
+	if runtime.Valid {
+		vm.Runtime = runtime.String
+	}
+	if pid.Valid {
+		val := pid.Int64
+		vm.PID = &val
+	}
+	if ipv6.Valid {
+		vm.IPv6Address = ipv6.String
+	}
+	if cmdline.Valid {
+		vm.KernelCmdline = cmdline.String
+	}
+	if serial.Valid {
+		vm.SerialSocket = serial.String
+	}
+	if groupID.Valid {
+		gid := groupID.Int64
+		vm.GroupID = &gid
+	}
+	if expiresRaw != nil {
+		expires, err := parseTimestamp(expiresRaw)
+		if err != nil {
+			return db.VM{}, fmt.Errorf("parse vm expiry: %w", err)
+		}
+		vm.ExpiresAt = &expires
+	}
+
+	created, err := parseTimestamp(createdRaw)
+	if err != nil {
+		return db.VM{}, fmt.Errorf("parse vm created: %w", err)
+	}
+	updated, err := parseTimestamp(updatedRaw)
+	if err != nil {
+		return db.VM{}, fmt.Errorf("parse vm updated: %w", err)
+	}
+
+	vm.CreatedAt = created
+	vm.UpdatedAt = updated
+	return vm, nil
+}
+
+func scanIP(row rowScanner) (db.IPAllocation, error) {
+	var (
+		ip     db.IPAllocation
+		status string
+		vmID   sql.NullInt64
+		leased any
+	)
+
+	if err := row.Scan(&ip.IPAddress, &vmID, &status, &leased); err != nil {
+		if err == sql.ErrNoRows {
+			return db.IPAllocation{}, err
+		}
+		return db.IPAllocation{}, fmt.Errorf("scan ip allocation: %w", err)
+	}
+	ip.Status = db.IPStatus(status)
+	if vmID.Valid {
+		value := vmID.Int64
+		ip.VMID = &value
+	}
+	if leased != nil {
+		ts, err := coerceTime(leased)
+		if err != nil {
+			return db.IPAllocation{}, fmt.Errorf("parse leased_at: %w", err)
+		}
+		ip.LeasedAt = &ts
+	}
+	return ip, nil
+}
+
+func scanPlugin(row rowScanner) (db.Plugin, error) {
+	var (
+		plugin      db.Plugin
+		metadataRaw []byte
+		installed   any
+		updated     any
+	)
+
+	if err := row.Scan(
+		&plugin.ID,
+		&plugin.Name,
+		&plugin.Version,
+		&plugin.Enabled,
+		&metadataRaw,
+		&installed,
+		&updated,
+	); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return db.Plugin{}, sql.ErrNoRows
+		}
+		return db.Plugin{}, fmt.Errorf("scan plugin: %w", err)
+	}
+
+	plugin.Metadata = append([]byte(nil), metadataRaw...)
+	plugin.InstalledAt, _ = parseTimeField(installed)
+	plugin.UpdatedAt, _ = parseTimeField(updated)
+	return plugin, nil
+}
+
+func scanPluginVersion(row rowScanner) (db.PluginVersion, error) {
+	var (
+		version     db.PluginVersion
+		metadataRaw []byte
+		installed   any
+	)
+
+	if err := row.Scan(&version.ID, &version.PluginName, &version.Version, &metadataRaw, &installed); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return db.PluginVersion{}, sql.ErrNoRows
+		}
+		return db.PluginVersion{}, fmt.Errorf("scan plugin version: %w", err)
+	}
+	version.Metadata = append([]byte(nil), metadataRaw...)
+	version.InstalledAt, _ = parseTimeField(installed)
+	return version, nil
+}
+
+func scanPluginArtifact(row rowScanner) (db.PluginArtifact, error) {
+	var (
+		artifact db.PluginArtifact
+		created  any
+		updated  any
+	)
+
+	if err := row.Scan(&artifact.ID, &artifact.PluginName, &artifact.Version, &artifact.ArtifactName, &artifact.Kind, &artifact.SourceURL, &artifact.Checksum, &artifact.Format, &artifact.LocalPath, &artifact.SizeBytes, &created, &updated); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return db.PluginArtifact{}, err
+		}
+		return db.PluginArtifact{}, fmt.Errorf("scan plugin artifact: %w", err)
+	}
+	createdAt, err := parseTimestamp(created)
+	if err != nil {
+		return db.PluginArtifact{}, fmt.Errorf("parse artifact created_at: %w", err)
+	}
+	updatedAt, err := parseTimestamp(updated)
+	if err != nil {
+		return db.PluginArtifact{}, fmt.Errorf("parse artifact updated_at: %w", err)
+	}
+	artifact.CreatedAt = createdAt
+	artifact.UpdatedAt = updatedAt
+	return artifact, nil
+}
+
+func scanVMCloudInit(row rowScanner) (db.VMCloudInit, error) {
+	var (
+		record  db.VMCloudInit
+		updated any
+	)
+
+	if err := row.Scan(&record.VMID, &record.UserData, &record.MetaData, &record.NetworkConfig, &record.SeedPath, &updated); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return db.VMCloudInit{}, err
+		}
+		return db.VMCloudInit{}, fmt.Errorf("scan vm cloudinit: %w", err)
+	}
+	if updatedTime, err := parseTimestamp(updated); err == nil {
+		record.UpdatedAt = updatedTime
+	}
+	return record, nil
+}
+
+func scanVMGroup(row rowScanner) (db.VMGroup, error) {
+	var (
+		group      db.VMGroup
+		configText string
+		createdRaw any
+		updatedRaw any
+	)
+
+	if err := row.Scan(&group.ID, &group.Name, &configText, &group.Replicas, &group.MaxSurge, &createdRaw, &updatedRaw); err != nil {
+		return db.VMGroup{}, err
+	}
+	group.ConfigJSON = []byte(configText)
+	created, err := parseTimestamp(createdRaw)
+	if err != nil {
+		return db.VMGroup{}, fmt.Errorf("parse vm group created: %w", err)
+	}
+	updated, err := parseTimestamp(updatedRaw)
+	if err != nil {
+		return db.VMGroup{}, fmt.Errorf("parse vm group updated: %w", err)
+	}
+	group.CreatedAt = created
+	group.UpdatedAt = updated
+	return group, nil
+}
+
+func scanSchedule(row rowScanner) (db.Schedule, error) {
+	var (
+		schedule   db.Schedule
+		replicas   sql.NullInt64
+		lastRun    any
+		createdRaw any
+		updatedRaw any
+	)
+
+	if err := row.Scan(&schedule.ID, &schedule.Name, &schedule.Cron, &schedule.Action, &schedule.TargetKind, &schedule.Target, &replicas, &schedule.Enabled, &lastRun, &createdRaw, &updatedRaw); err != nil {
+		if err == sql.ErrNoRows {
+			return db.Schedule{}, err
+		}
+		return db.Schedule{}, fmt.Errorf("scan schedule: %w", err)
+	}
+	if replicas.Valid {
+		value := int(replicas.Int64)
+		schedule.Replicas = &value
+	}
+	if lastRun != nil {
+		ts, err := coerceTime(lastRun)
+		if err != nil {
+			return db.Schedule{}, fmt.Errorf("parse schedule last_run_at: %w", err)
+		}
+		schedule.LastRunAt = &ts
+	}
+	created, err := parseTimestamp(createdRaw)
+	if err != nil {
+		return db.Schedule{}, fmt.Errorf("parse schedule created: %w", err)
+	}
+	updated, err := parseTimestamp(updatedRaw)
+	if err != nil {
+		return db.Schedule{}, fmt.Errorf("parse schedule updated: %w", err)
+	}
+	schedule.CreatedAt = created
+	schedule.UpdatedAt = updated
+	return schedule, nil
+}
+
+func scanAuditEvent(row rowScanner) (db.AuditEvent, error) {
+	var (
+		event      db.AuditEvent
+		clientIP   sql.NullString
+		message    sql.NullString
+		durationMS sql.NullInt64
+		createdRaw any
+	)
+
+	if err := row.Scan(&event.ID, &event.Action, &event.Target, &event.Actor, &clientIP, &message, &durationMS, &createdRaw); err != nil {
+		return db.AuditEvent{}, fmt.Errorf("scan audit event: %w", err)
+	}
+	event.ClientIP = clientIP.String
+	event.Message = message.String
+	if durationMS.Valid {
+		event.Duration = time.Duration(durationMS.Int64) * time.Millisecond
+	}
+	created, err := parseTimestamp(createdRaw)
+	if err != nil {
+		return db.AuditEvent{}, fmt.Errorf("parse audit event created: %w", err)
+	}
+	event.CreatedAt = created
+	return event, nil
+}
+
+func scanVMEvent(row rowScanner) (db.VMEvent, error) {
+	var (
+		event      db.VMEvent
+		status     sql.NullString
+		message    sql.NullString
+		code       sql.NullString
+		hint       sql.NullString
+		createdRaw any
+	)
+
+	if err := row.Scan(&event.ID, &event.VMName, &event.Type, &status, &message, &code, &hint, &createdRaw); err != nil {
+		return db.VMEvent{}, fmt.Errorf("scan vm event: %w", err)
+	}
+	event.Status = status.String
+	event.Message = message.String
+	event.Code = code.String
+	event.Hint = hint.String
+	created, err := coerceTime(createdRaw)
+	if err != nil {
+		return db.VMEvent{}, fmt.Errorf("parse vm event created: %w", err)
+	}
+	event.CreatedAt = created
+	return event, nil
+}
+
+func scanNetwork(row rowScanner) (db.Network, error) {
+	var (
+		network    db.Network
+		createdRaw any
+	)
+
+	if err := row.Scan(&network.ID, &network.Name, &network.Bridge, &network.Subnet, &network.Gateway, &network.Internal, &createdRaw); err != nil {
+		if err == sql.ErrNoRows {
+			return db.Network{}, err
+		}
+		return db.Network{}, fmt.Errorf("scan network: %w", err)
+	}
+	created, err := parseTimestamp(createdRaw)
+	if err != nil {
+		return db.Network{}, fmt.Errorf("parse network created: %w", err)
+	}
+	network.CreatedAt = created
+	return network, nil
+}
+
+func scanConfigBundle(row rowScanner) (db.ConfigBundle, error) {
+	var (
+		bundle     db.ConfigBundle
+		data       string
+		files      string
+		createdRaw any
+		updatedRaw any
+	)
+
+	if err := row.Scan(&bundle.ID, &bundle.Name, &data, &files, &bundle.Version, &createdRaw, &updatedRaw); err != nil {
+		if err == sql.ErrNoRows {
+			return db.ConfigBundle{}, err
+		}
+		return db.ConfigBundle{}, fmt.Errorf("scan config bundle: %w", err)
+	}
+	bundle.DataJSON = []byte(data)
+	bundle.FilesJSON = []byte(files)
+	created, err := parseTimestamp(createdRaw)
+	if err != nil {
+		return db.ConfigBundle{}, fmt.Errorf("parse config bundle created: %w", err)
+	}
+	updated, err := parseTimestamp(updatedRaw)
+	if err != nil {
+		return db.ConfigBundle{}, fmt.Errorf("parse config bundle updated: %w", err)
+	}
+	bundle.CreatedAt = created
+	bundle.UpdatedAt = updated
+	return bundle, nil
+}
+
+func scanNetworkLease(row rowScanner) (db.NetworkLease, error) {
+	var (
+		lease     db.NetworkLease
+		leasedRaw any
+	)
+
+	if err := row.Scan(&lease.NetworkID, &lease.IPAddress, &lease.VMID, &lease.VMName, &leasedRaw); err != nil {
+		if err == sql.ErrNoRows {
+			return db.NetworkLease{}, err
+		}
+		return db.NetworkLease{}, fmt.Errorf("scan network lease: %w", err)
+	}
+	leased, err := parseTimestamp(leasedRaw)
+	if err != nil {
+		return db.NetworkLease{}, fmt.Errorf("parse network lease time: %w", err)
+	}
+	lease.LeasedAt = leased
+	return lease, nil
+}
+
+func scanDHCPLease(row rowScanner) (db.DHCPLease, error) {
+	var (
+		lease      db.DHCPLease
+		hostname   sql.NullString
+		expiresRaw any
+		updatedRaw any
+	)
+
+	if err := row.Scan(&lease.MACAddress, &lease.IPAddress, &lease.VMID, &lease.VMName, &hostname, &expiresRaw, &updatedRaw); err != nil {
+		return db.DHCPLease{}, fmt.Errorf("scan dhcp lease: %w", err)
+	}
+	lease.Hostname = hostname.String
+	expires, err := parseTimestamp(expiresRaw)
+	if err != nil {
+		return db.DHCPLease{}, fmt.Errorf("parse dhcp lease expiry: %w", err)
+	}
+	updated, err := parseTimestamp(updatedRaw)
+	if err != nil {
+		return db.DHCPLease{}, fmt.Errorf("parse dhcp lease update time: %w", err)
+	}
+	lease.ExpiresAt = expires
+	lease.UpdatedAt = updated
+	return lease, nil
+}
+
+func scanDeviceAssignment(row rowScanner) (db.DeviceAssignment, error) {
+	var (
+		assignment  db.DeviceAssignment
+		group       sql.NullString
+		assignedRaw any
+	)
+
+	if err := row.Scan(&assignment.PCIAddress, &assignment.VMID, &assignment.VMName, &group, &assignedRaw); err != nil {
+		return db.DeviceAssignment{}, fmt.Errorf("scan device assignment: %w", err)
+	}
+	assignment.IOMMUGroup = group.String
+	assigned, err := parseTimestamp(assignedRaw)
+	if err != nil {
+		return db.DeviceAssignment{}, fmt.Errorf("parse device assignment time: %w", err)
+	}
+	assignment.AssignedAt = assigned
+	return assignment, nil
+}
+
+func scanIdempotencyKey(row rowScanner) (db.IdempotencyKey, error) {
+	var (
+		key        db.IdempotencyKey
+		createdRaw any
+		expiresRaw any
+	)
+
+	if err := row.Scan(&key.Scope, &key.Key, &key.RequestHash, &key.StatusCode, &key.Response, &createdRaw, &expiresRaw); err != nil {
+		return db.IdempotencyKey{}, fmt.Errorf("scan idempotency key: %w", err)
+	}
+	created, err := parseTimestamp(createdRaw)
+	if err != nil {
+		return db.IdempotencyKey{}, fmt.Errorf("parse idempotency key creation time: %w", err)
+	}
+	expires, err := parseTimestamp(expiresRaw)
+	if err != nil {
+		return db.IdempotencyKey{}, fmt.Errorf("parse idempotency key expiry: %w", err)
+	}
+	key.CreatedAt = created
+	key.ExpiresAt = expires
+	return key, nil
+}
+
+func scanOperation(row rowScanner) (db.Operation, error) {
+	var (
+		op           db.Operation
+		status       string
+		errMsg       sql.NullString
+		code         sql.NullString
+		hint         sql.NullString
+		createdRaw   any
+		updatedRaw   any
+		completedRaw any
+	)
+
+	if err := row.Scan(&op.ID, &op.Kind, &op.Target, &status, &errMsg, &code, &hint, &createdRaw, &updatedRaw, &completedRaw); err != nil {
+		if err == sql.ErrNoRows {
+			return db.Operation{}, err
+		}
+		return db.Operation{}, fmt.Errorf("scan operation: %w", err)
+	}
+	op.Status = db.OperationStatus(status)
+	op.Error = errMsg.String
+	op.Code = code.String
+	op.Hint = hint.String
+	created, err := coerceTime(createdRaw)
+	if err != nil {
+		return db.Operation{}, fmt.Errorf("parse operation created: %w", err)
+	}
+	op.CreatedAt = created
+	updated, err := coerceTime(updatedRaw)
+	if err != nil {
+		return db.Operation{}, fmt.Errorf("parse operation updated: %w", err)
+	}
+	op.UpdatedAt = updated
+	if completedRaw != nil {
+		completed, err := coerceTime(completedRaw)
+		if err != nil {
+			return db.Operation{}, fmt.Errorf("parse operation completed: %w", err)
+		}
+		op.CompletedAt = &completed
+	}
+	return op, nil
+}
+
+func scanMaintenanceWindow(row rowScanner) (db.MaintenanceWindow, error) {
+	var (
+		window     db.MaintenanceWindow
+		target     sql.NullString
+		reason     sql.NullString
+		startsRaw  any
+		endsRaw    any
+		createdRaw any
+	)
+
+	if err := row.Scan(&window.ID, &window.Name, &window.Scope, &target, &reason, &startsRaw, &endsRaw, &createdRaw); err != nil {
+		if err == sql.ErrNoRows {
+			return db.MaintenanceWindow{}, err
+		}
+		return db.MaintenanceWindow{}, fmt.Errorf("scan maintenance window: %w", err)
+	}
+	window.Target = target.String
+	window.Reason = reason.String
+	starts, err := coerceTime(startsRaw)
+	if err != nil {
+		return db.MaintenanceWindow{}, fmt.Errorf("parse maintenance window start: %w", err)
+	}
+	ends, err := coerceTime(endsRaw)
+	if err != nil {
+		return db.MaintenanceWindow{}, fmt.Errorf("parse maintenance window end: %w", err)
+	}
+	created, err := parseTimestamp(createdRaw)
+	if err != nil {
+		return db.MaintenanceWindow{}, fmt.Errorf("parse maintenance window created: %w", err)
+	}
+	window.StartsAt = starts
+	window.EndsAt = ends
+	window.CreatedAt = created
+	return window, nil
+}
+
+func scanMaintenanceIntent(row rowScanner) (db.MaintenanceIntent, error) {
+	var (
+		intent      db.MaintenanceIntent
+		outcome     sql.NullString
+		recordedRaw any
+		replayedRaw any
+	)
+
+	if err := row.Scan(&intent.ID, &intent.WindowID, &intent.Source, &intent.Target, &intent.Action, &intent.Count, &recordedRaw, &replayedRaw, &outcome); err != nil {
+		return db.MaintenanceIntent{}, fmt.Errorf("scan maintenance intent: %w", err)
+	}
+	intent.Outcome = outcome.String
+	recorded, err := coerceTime(recordedRaw)
+	if err != nil {
+		return db.MaintenanceIntent{}, fmt.Errorf("parse maintenance intent recorded: %w", err)
+	}
+	intent.RecordedAt = recorded
+	if replayedRaw != nil {
+		replayed, err := coerceTime(replayedRaw)
+		if err != nil {
+			return db.MaintenanceIntent{}, fmt.Errorf("parse maintenance intent replayed: %w", err)
+		}
+		intent.ReplayedAt = &replayed
+	}
+	return intent, nil
+}
+
+func scanWebhook(row rowScanner) (db.Webhook, error) {
+	var (
+		webhook    db.Webhook
+		events     string
+		createdRaw any
+		updatedRaw any
+	)
+
+	if err := row.Scan(&webhook.ID, &webhook.Name, &webhook.URL, &events, &webhook.Secret, &webhook.Enabled, &createdRaw, &updatedRaw); err != nil {
+		if err == sql.ErrNoRows {
+			return db.Webhook{}, err
+		}
+		return db.Webhook{}, fmt.Errorf("scan webhook: %w", err)
+	}
+	if events != "" {
+		webhook.Events = strings.Split(events, ",")
+	}
+	created, err := parseTimestamp(createdRaw)
+	if err != nil {
+		return db.Webhook{}, fmt.Errorf("parse webhook created: %w", err)
+	}
+	updated, err := parseTimestamp(updatedRaw)
+	if err != nil {
+		return db.Webhook{}, fmt.Errorf("parse webhook updated: %w", err)
+	}
+	webhook.CreatedAt = created
+	webhook.UpdatedAt = updated
+	return webhook, nil
+}
+
+func scanWebhookDelivery(row rowScanner) (db.WebhookDelivery, error) {
+	var (
+		delivery   db.WebhookDelivery
+		errText    sql.NullString
+		payload    []byte
+		durationMS int64
+		createdRaw any
+	)
+
+	if err := row.Scan(&delivery.ID, &delivery.WebhookID, &delivery.DeliveryID, &delivery.EventType, &delivery.Attempt, &delivery.StatusCode, &errText, &delivery.Succeeded, &payload, &durationMS, &createdRaw); err != nil {
+		return db.WebhookDelivery{}, fmt.Errorf("scan webhook delivery: %w", err)
+	}
+	delivery.Error = errText.String
+	delivery.Payload = append([]byte(nil), payload...)
+	delivery.Duration = time.Duration(durationMS) * time.Millisecond
+	created, err := coerceTime(createdRaw)
+	if err != nil {
+		return db.WebhookDelivery{}, fmt.Errorf("parse webhook delivery created: %w", err)
+	}
+	delivery.CreatedAt = created
+	return delivery, nil
+}
+
+func scanScheduleRun(row rowScanner) (db.ScheduleRun, error) {
+	var (
+		run         db.ScheduleRun
+		message     sql.NullString
+		startedRaw  any
+		finishedRaw any
+	)
+
+	if err := row.Scan(&run.ID, &run.ScheduleID, &run.Status, &message, &startedRaw, &finishedRaw); err != nil {
+		return db.ScheduleRun{}, fmt.Errorf("scan schedule run: %w", err)
+	}
+	run.Message = message.String
+	started, err := coerceTime(startedRaw)
+	if err != nil {
+		return db.ScheduleRun{}, fmt.Errorf("parse schedule run started: %w", err)
+	}
+	finished, err := coerceTime(finishedRaw)
+	if err != nil {
+		return db.ScheduleRun{}, fmt.Errorf("parse schedule run finished: %w", err)
+	}
+	run.StartedAt = started
+	run.FinishedAt = finished
+	return run, nil
+}
+
+func scanVMConfig(row rowScanner) (db.VMConfig, error) {
+	var (
+		cfg     db.VMConfig
+		payload []byte
+		updated any
+	)
+
+	if err := row.Scan(&cfg.VMID, &cfg.Version, &payload, &updated); err != nil {
+		if err == sql.ErrNoRows {
+			return db.VMConfig{}, err
+		}
+		return db.VMConfig{}, fmt.Errorf("scan vm config: %w", err)
+	}
+
+	cfg.ConfigJSON = append([]byte(nil), payload...)
+	ts, err := parseTimestamp(updated)
+	if err != nil {
+		return db.VMConfig{}, fmt.Errorf("parse vm config updated: %w", err)
+	}
+	cfg.UpdatedAt = ts
+	return cfg, nil
+}
+
+func scanVMConfigHistory(row rowScanner) (db.VMConfigHistoryEntry, error) {
+	var (
+		record  db.VMConfigHistoryEntry
+		payload []byte
+		updated any
+		outcome sql.NullString
+		message sql.NullString
+	)
+
+	if err := row.Scan(&record.ID, &record.VMID, &record.Version, &payload, &updated, &outcome, &message); err != nil {
+		return db.VMConfigHistoryEntry{}, fmt.Errorf("scan vm config history: %w", err)
+	}
+	record.ConfigJSON = append([]byte(nil), payload...)
+	record.Outcome = outcome.String
+	record.OutcomeMessage = message.String
+	ts, err := parseTimestamp(updated)
+	if err != nil {
+		return db.VMConfigHistoryEntry{}, fmt.Errorf("parse vm config history updated: %w", err)
+	}
+	record.UpdatedAt = ts
+	return record, nil
+}
+
+func parseTimeField(value any) (time.Time, error) {
+	if value == nil {
+		return time.Time{}, fmt.Errorf("time field nil")
+	}
+	switch v := value.(type) {
+	case time.Time:
+		return v, nil
+	case string:
+		for _, layout := range timestampLayouts {
+			if t, err := time.Parse(layout, v); err == nil {
+				return t, nil
+			}
+		}
+	case []byte:
+		str := string(v)
+		for _, layout := range timestampLayouts {
+			if t, err := time.Parse(layout, str); err == nil {
+				return t, nil
+			}
+		}
+	}
+	return time.Time{}, fmt.Errorf("unsupported time value %T", value)
+}
+
+func nullableInt64(v *int64) any {
+	if v == nil {
+		return nil
+	}
+	return *v
+}
+
+func nullableString(v string) any {
+	if v == "" {
+		return nil
+	}
+	return v
+}
+
+func coerceTime(value any) (time.Time, error) {
+	switch v := value.(type) {
+	case time.Time:
+		return v.UTC(), nil
+	case string:
+		for _, layout := range timestampLayouts {
+			if t, err := time.ParseInLocation(layout, v, time.UTC); err == nil {
+				return t.UTC(), nil
+			}
+		}
+		return time.Time{}, fmt.Errorf("unrecognised time format: %q", v)
+	case []byte:
+		s := string(v)
+		for _, layout := range timestampLayouts {
+			if t, err := time.ParseInLocation(layout, s, time.UTC); err == nil {
+				return t.UTC(), nil
+			}
+		}
+		return time.Time{}, fmt.Errorf("unrecognised time format bytes: %q", s)
+	case nil:
+		return time.Time{}, nil
+	default:
+		return time.Time{}, fmt.Errorf("unsupported time type %T", value)
+	}
+}
+
+func parseTimestamp(value any) (time.Time, error) {
+	switch t := value.(type) {
+	case time.Time:
+		return t, nil
+	case string:
+		for _, layout := range timestampLayouts {
+			parsed, err := time.Parse(layout, t)
+			if err == nil {
+				return parsed, nil
+			}
+		}
+	case []byte:
+		for _, layout := range timestampLayouts {
+			parsed, err := time.Parse(layout, string(t))
+			if err == nil {
+				return parsed, nil
+			}
+		}
+	case int64:
+		return time.Unix(t, 0), nil
+	case float64:
+		return time.Unix(int64(t), 0), nil
+	}
+	return time.Time{}, fmt.Errorf("unsupported timestamp type %T", value)
+}
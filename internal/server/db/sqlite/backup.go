@@ -0,0 +1,98 @@
+// Copyright (c) 2025 HYPR. PTE. LTD.
+//
+// Business Source License 1.1
+// See LICENSE file in the project root for details.
+
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+
+	"github.com/volantvm/volant/internal/server/db/migrate"
+)
+
+// Backup writes a consistent snapshot of the database to path with VACUUM
+// INTO, which runs online and produces a compacted copy. The snapshot is
+// written beside path and renamed into place so readers never see a partial
+// file.
+func (s *Store) Backup(ctx context.Context, path string) error {
+	if _, err := os.Stat(path); err == nil {
+		return fmt.Errorf("backup target %s already exists", path)
+	}
+	tmp := path + ".partial"
+	_ = os.Remove(tmp)
+	if _, err := s.db.ExecContext(ctx, `VACUUM INTO ?;`, tmp); err != nil {
+		_ = os.Remove(tmp)
+		return fmt.Errorf("vacuum into %s: %w", tmp, err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		_ = os.Remove(tmp)
+		return fmt.Errorf("finalize backup: %w", err)
+	}
+	return nil
+}
+
+// Restore copies the snapshot at path over the live database using SQLite's
+// online backup API, then applies any migrations newer than the snapshot.
+// The snapshot is checked for integrity and for a schema this build can run
+// before anything is overwritten.
+func (s *Store) Restore(ctx context.Context, path string) error {
+	if _, err := os.Stat(path); err != nil {
+		return fmt.Errorf("stat backup: %w", err)
+	}
+	src, err := sql.Open("sqlite3", fmt.Sprintf("file:%s?mode=ro", path))
+	if err != nil {
+		return fmt.Errorf("open backup: %w", err)
+	}
+	defer src.Close()
+	src.SetMaxOpenConns(1)
+
+	if err := s.checkSnapshot(ctx, src); err != nil {
+		return err
+	}
+
+	srcConn, err := src.Conn(ctx)
+	if err != nil {
+		return fmt.Errorf("open backup connection: %w", err)
+	}
+	defer srcConn.Close()
+	dstConn, err := s.db.Conn(ctx)
+	if err != nil {
+		return fmt.Errorf("acquire database connection: %w", err)
+	}
+	defer dstConn.Close()
+
+	err = copyDatabase(dstConn, srcConn)
+	if err != nil {
+		return err
+	}
+	// Release the pinned connection before migrating; the pool holds one.
+	_ = dstConn.Close()
+	return s.applyMigrations(ctx)
+}
+
+func (s *Store) checkSnapshot(ctx context.Context, src *sql.DB) error {
+	var result string
+	if err := src.QueryRowContext(ctx, `PRAGMA integrity_check;`).Scan(&result); err != nil {
+		return fmt.Errorf("check backup integrity: %w", err)
+	}
+	if result != "ok" {
+		return fmt.Errorf("backup failed integrity check: %s", result)
+	}
+
+	var version sql.NullInt64
+	if err := src.QueryRowContext(ctx, `SELECT MAX(version) FROM schema_migrations;`).Scan(&version); err != nil {
+		return fmt.Errorf("read backup schema version: %w", err)
+	}
+	m, err := s.Migrator()
+	if err != nil {
+		return err
+	}
+	if latest := m.Latest(); int(version.Int64) > latest {
+		return fmt.Errorf("%w: backup at version %d, build supports up to %d", migrate.ErrSchemaTooNew, version.Int64, latest)
+	}
+	return nil
+}
@@ -0,0 +1,42 @@
+// Copyright (c) 2025 HYPR. PTE. LTD.
+//
+// Business Source License 1.1
+// See LICENSE file in the project root for details.
+
+//go:build cgo
+
+package sqlite
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+
+	sqlite3 "github.com/mattn/go-sqlite3"
+)
+
+// copyDatabase copies every page of src's main database over dst's with the
+// online backup API.
+func copyDatabase(dst, src *sql.Conn) error {
+	return dst.Raw(func(dstDriver any) error {
+		return src.Raw(func(srcDriver any) error {
+			dstConn, ok := dstDriver.(*sqlite3.SQLiteConn)
+			if !ok {
+				return errors.New("unexpected sqlite driver connection")
+			}
+			srcConn, ok := srcDriver.(*sqlite3.SQLiteConn)
+			if !ok {
+				return errors.New("unexpected sqlite driver connection")
+			}
+			backup, err := dstConn.Backup("main", srcConn, "main")
+			if err != nil {
+				return fmt.Errorf("start restore: %w", err)
+			}
+			if _, err := backup.Step(-1); err != nil {
+				_ = backup.Finish()
+				return fmt.Errorf("copy backup pages: %w", err)
+			}
+			return backup.Finish()
+		})
+	})
+}
@@ -0,0 +1,19 @@
+// Copyright (c) 2025 HYPR. PTE. LTD.
+//
+// Business Source License 1.1
+// See LICENSE file in the project root for details.
+
+//go:build !cgo
+
+package sqlite
+
+import (
+	"database/sql"
+	"errors"
+)
+
+// copyDatabase needs the cgo SQLite driver; without it there is no database
+// to restore into.
+func copyDatabase(dst, src *sql.Conn) error {
+	return errors.New("sqlite: restore requires a cgo build")
+}
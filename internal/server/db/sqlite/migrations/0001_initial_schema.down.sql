@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS plugins;
+DROP TABLE IF EXISTS workloads;
+DROP TABLE IF EXISTS ip_allocations;
+DROP TABLE IF EXISTS vms;
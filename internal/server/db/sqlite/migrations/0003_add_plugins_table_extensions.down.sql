@@ -0,0 +1,2 @@
+ALTER TABLE plugins DROP COLUMN updated_at;
+ALTER TABLE plugins DROP COLUMN enabled;
@@ -0,0 +1,2 @@
+ALTER TABLE vms DROP COLUMN console_socket;
+ALTER TABLE vms DROP COLUMN serial_socket;
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS vm_config_history;
+DROP TABLE IF EXISTS vm_configs;
@@ -0,0 +1,31 @@
+-- SQLite cannot drop a foreign key column, so rebuild vms without group_id.
+-- The migrator runs this with foreign keys disabled.
+DROP INDEX IF EXISTS idx_vms_group_id;
+
+CREATE TABLE vms_tmp (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name TEXT NOT NULL UNIQUE,
+    status TEXT NOT NULL,
+    pid INTEGER,
+    ip_address TEXT NOT NULL UNIQUE,
+    mac_address TEXT NOT NULL UNIQUE,
+    cpu_cores INTEGER NOT NULL CHECK (cpu_cores > 0),
+    memory_mb INTEGER NOT NULL CHECK (memory_mb > 0),
+    kernel_cmdline TEXT,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    runtime TEXT NOT NULL DEFAULT 'browser',
+    serial_socket TEXT,
+    console_socket TEXT
+);
+
+INSERT INTO vms_tmp (id, name, status, pid, ip_address, mac_address, cpu_cores, memory_mb, kernel_cmdline, created_at, updated_at, runtime, serial_socket, console_socket)
+SELECT id, name, status, pid, ip_address, mac_address, cpu_cores, memory_mb, kernel_cmdline, created_at, updated_at, runtime, serial_socket, console_socket
+FROM vms;
+
+DROP TABLE vms;
+ALTER TABLE vms_tmp RENAME TO vms;
+
+CREATE INDEX IF NOT EXISTS idx_vms_status ON vms(status);
+
+DROP TABLE IF EXISTS vm_groups;
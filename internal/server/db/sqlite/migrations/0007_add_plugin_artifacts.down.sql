@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS vm_cloudinit;
+DROP TABLE IF EXISTS plugin_artifacts;
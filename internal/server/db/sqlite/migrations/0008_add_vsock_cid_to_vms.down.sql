@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_vms_vsock_cid;
+ALTER TABLE vms DROP COLUMN vsock_cid;
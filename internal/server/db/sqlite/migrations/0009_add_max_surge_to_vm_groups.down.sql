@@ -0,0 +1 @@
+ALTER TABLE vm_groups DROP COLUMN max_surge;
@@ -0,0 +1,3 @@
+-- Limit how many deployment replicas may boot concurrently during scale-up.
+-- Zero keeps the previous behaviour of launching all missing replicas at once.
+ALTER TABLE vm_groups ADD COLUMN max_surge INTEGER NOT NULL DEFAULT 0 CHECK (max_surge >= 0);
@@ -0,0 +1,2 @@
+ALTER TABLE vm_config_history DROP COLUMN outcome_message;
+ALTER TABLE vm_config_history DROP COLUMN outcome;
@@ -0,0 +1,4 @@
+-- Record how a configuration version fared once applied, e.g. whether a
+-- restart under the new config passed health checks or was rolled back.
+ALTER TABLE vm_config_history ADD COLUMN outcome TEXT;
+ALTER TABLE vm_config_history ADD COLUMN outcome_message TEXT;
@@ -0,0 +1 @@
+ALTER TABLE vms DROP COLUMN ipv6_address;
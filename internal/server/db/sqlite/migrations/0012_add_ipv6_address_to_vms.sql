@@ -0,0 +1,2 @@
+-- Secondary IPv6 address for dual-stack VMs; the primary address stays in ip_address.
+ALTER TABLE vms ADD COLUMN ipv6_address TEXT;
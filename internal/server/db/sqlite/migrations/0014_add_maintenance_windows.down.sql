@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS maintenance_intents;
+DROP TABLE IF EXISTS maintenance_windows;
@@ -0,0 +1,25 @@
+CREATE TABLE IF NOT EXISTS maintenance_windows (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name TEXT NOT NULL UNIQUE,
+    scope TEXT NOT NULL,
+    target TEXT,
+    reason TEXT,
+    starts_at TIMESTAMP NOT NULL,
+    ends_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS maintenance_intents (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    window_id INTEGER NOT NULL REFERENCES maintenance_windows(id) ON DELETE CASCADE,
+    source TEXT NOT NULL,
+    target TEXT NOT NULL,
+    action TEXT NOT NULL,
+    count INTEGER NOT NULL DEFAULT 1,
+    recorded_at TIMESTAMP NOT NULL,
+    replayed_at TIMESTAMP,
+    outcome TEXT
+);
+
+CREATE INDEX IF NOT EXISTS idx_maintenance_intents_pending ON maintenance_intents(source, replayed_at);
+CREATE INDEX IF NOT EXISTS idx_maintenance_intents_window ON maintenance_intents(window_id, id);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS network_leases;
+DROP TABLE IF EXISTS networks;
@@ -0,0 +1,17 @@
+CREATE TABLE IF NOT EXISTS networks (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name TEXT NOT NULL UNIQUE,
+    bridge TEXT NOT NULL UNIQUE,
+    subnet TEXT NOT NULL,
+    gateway TEXT NOT NULL,
+    internal INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS network_leases (
+    network_id INTEGER NOT NULL REFERENCES networks(id) ON DELETE CASCADE,
+    ip_address TEXT NOT NULL,
+    vm_id INTEGER NOT NULL UNIQUE REFERENCES vms(id) ON DELETE CASCADE,
+    leased_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (network_id, ip_address)
+);
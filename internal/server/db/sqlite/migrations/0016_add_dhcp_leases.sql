@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS dhcp_leases (
+    mac_address TEXT PRIMARY KEY,
+    ip_address TEXT NOT NULL,
+    vm_id INTEGER NOT NULL REFERENCES vms(id) ON DELETE CASCADE,
+    hostname TEXT,
+    expires_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_dhcp_leases_vm ON dhcp_leases(vm_id);
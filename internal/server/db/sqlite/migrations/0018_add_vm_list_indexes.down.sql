@@ -0,0 +1,4 @@
+-- idx_vms_status predates this migration (0001) and stays.
+DROP INDEX IF EXISTS idx_vms_runtime;
+DROP INDEX IF EXISTS idx_vms_created_at;
+DROP INDEX IF EXISTS idx_vms_updated_at;
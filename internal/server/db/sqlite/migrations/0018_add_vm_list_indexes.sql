@@ -0,0 +1,4 @@
+CREATE INDEX IF NOT EXISTS idx_vms_status ON vms(status);
+CREATE INDEX IF NOT EXISTS idx_vms_runtime ON vms(runtime COLLATE NOCASE);
+CREATE INDEX IF NOT EXISTS idx_vms_created_at ON vms(created_at);
+CREATE INDEX IF NOT EXISTS idx_vms_updated_at ON vms(updated_at);
@@ -0,0 +1,8 @@
+CREATE TABLE IF NOT EXISTS vm_labels (
+    vm_id INTEGER NOT NULL REFERENCES vms(id) ON DELETE CASCADE,
+    key TEXT NOT NULL,
+    value TEXT NOT NULL DEFAULT '',
+    PRIMARY KEY (vm_id, key)
+);
+
+CREATE INDEX IF NOT EXISTS idx_vm_labels_key_value ON vm_labels(key, value);
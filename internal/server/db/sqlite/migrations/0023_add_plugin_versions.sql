@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS plugin_versions (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    plugin_name TEXT NOT NULL,
+    version TEXT NOT NULL,
+    metadata TEXT,
+    installed_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE(plugin_name, version)
+);
+
+INSERT OR IGNORE INTO plugin_versions (plugin_name, version, metadata, installed_at)
+SELECT name, version, metadata, installed_at FROM plugins;
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_vms_expires_at;
+ALTER TABLE vms DROP COLUMN expires_at;
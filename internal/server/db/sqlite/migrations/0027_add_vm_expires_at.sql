@@ -0,0 +1,3 @@
+-- Time a VM's TTL runs out; NULL when it has none.
+ALTER TABLE vms ADD COLUMN expires_at TIMESTAMP;
+CREATE INDEX IF NOT EXISTS idx_vms_expires_at ON vms(expires_at);
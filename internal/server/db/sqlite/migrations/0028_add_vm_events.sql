@@ -0,0 +1,14 @@
+-- Lifecycle events of VMs, kept for postmortems after the VM is gone.
+CREATE TABLE IF NOT EXISTS vm_events (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    vm_name TEXT NOT NULL,
+    type TEXT NOT NULL,
+    status TEXT,
+    message TEXT,
+    code TEXT,
+    hint TEXT,
+    created_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_vm_events_vm_name ON vm_events(vm_name, id);
+CREATE INDEX IF NOT EXISTS idx_vm_events_created_at ON vm_events(created_at);
@@ -10,16 +10,14 @@ import (
 	"database/sql"
 	"embed"
 	"fmt"
-	"io/fs"
 	"os"
 	"path/filepath"
-	"sort"
 	"strings"
-	"time"
 
 	_ "github.com/mattn/go-sqlite3"
 
 	"github.com/volantvm/volant/internal/server/db"
+	"github.com/volantvm/volant/internal/server/db/migrate"
 )
 
 //go:embed migrations/*.sql
@@ -33,6 +31,20 @@ type Store struct {
 // Open establishes a SQLite connection, applies migrations, and enables
 // recommended pragmas for the orchestrator workload.
 func Open(ctx context.Context, path string) (*Store, error) {
+	store, err := OpenForMigration(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+	if err := store.applyMigrations(ctx); err != nil {
+		_ = store.db.Close()
+		return nil, err
+	}
+	return store, nil
+}
+
+// OpenForMigration opens the database without applying migrations, for
+// managing the schema through Migrator.
+func OpenForMigration(ctx context.Context, path string) (*Store, error) {
 	expanded, err := expandPath(path)
 	if err != nil {
 		return nil, fmt.Errorf("expand path: %w", err)
@@ -53,9 +65,9 @@ func Open(ctx context.Context, path string) (*Store, error) {
 		return nil, err
 	}
 
-	if err := applyMigrations(ctx, db); err != nil {
+	if err := db.PingContext(ctx); err != nil {
 		_ = db.Close()
-		return nil, err
+		return nil, fmt.Errorf("ping sqlite: %w", err)
 	}
 
 	return &Store{db: db}, nil
@@ -106,117 +118,59 @@ func configurePool(db *sql.DB) error {
 	return nil
 }
 
-func applyMigrations(ctx context.Context, db *sql.DB) error {
-	if _, err := db.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS schema_migrations (
+// dialect runs migrations with foreign keys disabled so down scripts can
+// rebuild tables without cascading deletes, as SQLite's ALTER TABLE docs
+// recommend, and verifies the keys before re-enabling them.
+var dialect = migrate.Dialect{
+	Placeholder: func(int) string { return "?" },
+	CreateTable: `CREATE TABLE IF NOT EXISTS schema_migrations (
         version INTEGER PRIMARY KEY,
         name TEXT NOT NULL,
         applied_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
-    );`); err != nil {
-		return fmt.Errorf("ensure schema_migrations: %w", err)
-	}
-
-	applied, err := loadApplied(ctx, db)
-	if err != nil {
-		return err
-	}
-
-	migrations, err := loadMigrations()
-	if err != nil {
-		return err
-	}
-
-	for _, m := range migrations {
-		if applied[m.version] {
-			continue
-		}
-		if err := executeMigration(ctx, db, m); err != nil {
-			return err
+    );`,
+	Acquire: func(ctx context.Context, conn *sql.Conn) (func(context.Context) error, error) {
+		if _, err := conn.ExecContext(ctx, `PRAGMA foreign_keys = OFF;`); err != nil {
+			return nil, fmt.Errorf("disable foreign keys: %w", err)
 		}
-	}
-
-	return nil
+		return func(ctx context.Context) error {
+			rows, err := conn.QueryContext(ctx, `PRAGMA foreign_key_check;`)
+			if err != nil {
+				return fmt.Errorf("check foreign keys: %w", err)
+			}
+			violated := rows.Next()
+			rows.Close()
+			if _, err := conn.ExecContext(ctx, `PRAGMA foreign_keys = ON;`); err != nil {
+				return fmt.Errorf("enable foreign keys: %w", err)
+			}
+			if violated {
+				return fmt.Errorf("migration left foreign key violations")
+			}
+			return nil
+		}, nil
+	},
 }
 
-func loadApplied(ctx context.Context, db *sql.DB) (map[int]bool, error) {
-	rows, err := db.QueryContext(ctx, `SELECT version FROM schema_migrations`)
-	if err != nil {
-		return nil, fmt.Errorf("select applied migrations: %w", err)
-	}
-	defer rows.Close()
-
-	applied := make(map[int]bool)
-	for rows.Next() {
-		var v int
-		if err := rows.Scan(&v); err != nil {
-			return nil, fmt.Errorf("scan migration version: %w", err)
-		}
-		applied[v] = true
-	}
-	return applied, rows.Err()
-}
-
-type migration struct {
-	version int
-	name    string
-	sql     string
+// Migrations returns the embedded SQLite migrations in version order.
+func Migrations() ([]migrate.Migration, error) {
+	return migrate.Load(migrationsFS, "migrations")
 }
 
-func loadMigrations() ([]migration, error) {
-	entries, err := fs.Glob(migrationsFS, "migrations/*.sql")
+// Migrator returns a migrator bound to the store's database.
+func (s *Store) Migrator() (*migrate.Migrator, error) {
+	migrations, err := Migrations()
 	if err != nil {
-		return nil, fmt.Errorf("list migrations: %w", err)
-	}
-
-	sort.Strings(entries)
-	migrations := make([]migration, 0, len(entries))
-	for _, path := range entries {
-		content, err := fs.ReadFile(migrationsFS, path)
-		if err != nil {
-			return nil, fmt.Errorf("read migration %s: %w", path, err)
-		}
-		base := filepath.Base(path)
-		parts := strings.SplitN(base, "_", 2)
-		if len(parts) != 2 {
-			return nil, fmt.Errorf("invalid migration filename: %s", base)
-		}
-		version, err := parseVersion(parts[0])
-		if err != nil {
-			return nil, fmt.Errorf("parse version for %s: %w", base, err)
-		}
-		name := strings.TrimSuffix(parts[1], filepath.Ext(parts[1]))
-		migrations = append(migrations, migration{version: version, name: name, sql: string(content)})
-	}
-	return migrations, nil
-}
-
-func parseVersion(prefix string) (int, error) {
-	var v int
-	if _, err := fmt.Sscanf(prefix, "%d", &v); err != nil {
-		return 0, err
+		return nil, err
 	}
-	return v, nil
+	return migrate.New(s.db, dialect, migrations), nil
 }
 
-func executeMigration(ctx context.Context, db *sql.DB, m migration) error {
-	tx, err := db.BeginTx(ctx, nil)
+func (s *Store) applyMigrations(ctx context.Context) error {
+	m, err := s.Migrator()
 	if err != nil {
-		return fmt.Errorf("begin migration %d: %w", m.version, err)
-	}
-
-	if _, err := tx.ExecContext(ctx, m.sql); err != nil {
-		_ = tx.Rollback()
-		return fmt.Errorf("apply migration %d: %w", m.version, err)
-	}
-
-	if _, err := tx.ExecContext(ctx, `INSERT INTO schema_migrations(version, name, applied_at) VALUES(?, ?, ?);`, m.version, m.name, time.Now().UTC()); err != nil {
-		_ = tx.Rollback()
-		return fmt.Errorf("record migration %d: %w", m.version, err)
-	}
-
-	if err := tx.Commit(); err != nil {
-		return fmt.Errorf("commit migration %d: %w", m.version, err)
+		return err
 	}
-	return nil
+	_, err = m.Up(ctx, 0)
+	return err
 }
 
 func expandPath(path string) (string, error) {
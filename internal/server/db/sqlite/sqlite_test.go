@@ -6,8 +6,10 @@
 package sqlite
 
 import (
+	"bytes"
 	"context"
 	"errors"
+	"fmt"
 	"path/filepath"
 	"testing"
 	"time"
@@ -15,6 +17,8 @@ import (
 	"database/sql"
 
 	"github.com/volantvm/volant/internal/server/db"
+	"github.com/volantvm/volant/internal/server/db/migrate"
+	"github.com/volantvm/volant/internal/server/labels"
 )
 
 func TestVMRepositoryCRUD(t *testing.T) {
@@ -37,6 +41,7 @@ func TestVMRepositoryCRUD(t *testing.T) {
 		MACAddress: "02:00:00:00:00:01",
 		CPUCores:   2,
 		MemoryMB:   2048,
+		VsockCID:   7,
 	}
 
 	id, err := vmRepo.Create(ctx, vm)
@@ -57,6 +62,37 @@ func TestVMRepositoryCRUD(t *testing.T) {
 	if fetched.CreatedAt.IsZero() || fetched.UpdatedAt.IsZero() {
 		t.Fatalf("timestamps not populated: %+v", fetched)
 	}
+	if _, err := vmRepo.Create(ctx, &db.VM{Name: "vm-2", Status: db.VMStatusPending, Runtime: "browser", MACAddress: "02:00:00:00:00:02", CPUCores: 1, MemoryMB: 512, VsockCID: 3}); err != nil {
+		t.Fatalf("create second vm: %v", err)
+	}
+	cids, err := vmRepo.VsockCIDs(ctx)
+	if err != nil {
+		t.Fatalf("vsock cids: %v", err)
+	}
+	if len(cids) != 2 || cids[0] != 3 || cids[1] != 7 {
+		t.Fatalf("expected vsock cids [3 7], got %v", cids)
+	}
+
+	expiresAt := time.Now().Add(time.Minute).UTC()
+	if err := vmRepo.SetExpiresAt(ctx, id, &expiresAt); err != nil {
+		t.Fatalf("set expires at: %v", err)
+	}
+	if expired, err := vmRepo.ListExpired(ctx, time.Now()); err != nil || len(expired) != 0 {
+		t.Fatalf("expected nothing expired yet, got %v (%v)", expired, err)
+	}
+	expired, err := vmRepo.ListExpired(ctx, expiresAt.Add(time.Second))
+	if err != nil {
+		t.Fatalf("list expired: %v", err)
+	}
+	if len(expired) != 1 || expired[0].ID != id || expired[0].ExpiresAt == nil || !expired[0].ExpiresAt.Equal(expiresAt) {
+		t.Fatalf("expected vm-1 expired at %v, got %+v", expiresAt, expired)
+	}
+	if err := vmRepo.SetExpiresAt(ctx, id, nil); err != nil {
+		t.Fatalf("clear expires at: %v", err)
+	}
+	if fetched, err := vmRepo.GetByName(ctx, "vm-1"); err != nil || fetched.ExpiresAt != nil {
+		t.Fatalf("expected expiry cleared, got %+v (%v)", fetched, err)
+	}
 
 	pid := int64(4321)
 	if err := vmRepo.UpdateRuntimeState(ctx, id, db.VMStatusRunning, &pid); err != nil {
@@ -111,7 +147,7 @@ func TestIPRepositoryLeaseAndAssign(t *testing.T) {
 
 	var leasedIP string
 	err := store.WithTx(ctx, func(q db.Queries) error {
-		allocation, err := q.IPAllocations().LeaseNextAvailable(ctx)
+		allocation, err := q.IPAllocations().LeaseNextAvailable(ctx, db.IPFamilyV4)
 		if err != nil {
 			return err
 		}
@@ -158,6 +194,113 @@ func TestIPRepositoryLeaseAndAssign(t *testing.T) {
 	}
 }
 
+func TestVMRepositoryListPage(t *testing.T) {
+	ctx := context.Background()
+	store := openTestStore(t)
+	t.Cleanup(func() { _ = store.Close(ctx) })
+
+	groupID, err := store.Queries().VMGroups().Create(ctx, &db.VMGroup{Name: "web", ConfigJSON: []byte(`{}`), Replicas: 2})
+	if err != nil {
+		t.Fatalf("create group: %v", err)
+	}
+	vmRepo := store.Queries().VirtualMachines()
+	specs := []struct {
+		name    string
+		status  db.VMStatus
+		runtime string
+		group   *int64
+		plugin  string
+		labels  map[string]string
+	}{
+		{"web-1", db.VMStatusRunning, "nginx", &groupID, "nginx", map[string]string{"env": "prod", "tier": "web"}},
+		{"web-2", db.VMStatusStopped, "nginx", &groupID, "nginx", map[string]string{"env": "staging", "tier": "web", "canary": ""}},
+		{"db_1", db.VMStatusRunning, "Postgres", nil, "postgres", map[string]string{"env": "prod"}},
+		{"cache", db.VMStatusCrashed, "redis", nil, "", nil},
+	}
+	for i, spec := range specs {
+		id, err := vmRepo.Create(ctx, &db.VM{
+			Name:       spec.name,
+			Status:     spec.status,
+			Runtime:    spec.runtime,
+			IPAddress:  fmt.Sprintf("192.168.127.%d", i+2),
+			MACAddress: fmt.Sprintf("02:00:00:00:00:%02d", i+1),
+			CPUCores:   1,
+			MemoryMB:   512,
+			GroupID:    spec.group,
+			Labels:     spec.labels,
+		})
+		if err != nil {
+			t.Fatalf("create vm %s: %v", spec.name, err)
+		}
+		if spec.plugin != "" {
+			if _, err := store.Queries().VMConfigs().Upsert(ctx, id, []byte(`{"plugin":"`+spec.plugin+`"}`)); err != nil {
+				t.Fatalf("upsert config: %v", err)
+			}
+		}
+	}
+
+	names := func(vms []db.VM) []string {
+		out := make([]string, 0, len(vms))
+		for _, vm := range vms {
+			out = append(out, vm.Name)
+		}
+		return out
+	}
+	cases := []struct {
+		name  string
+		opts  db.VMListOptions
+		want  []string
+		total int
+	}{
+		{"all", db.VMListOptions{Limit: -1}, []string{"web-1", "web-2", "db_1", "cache"}, 4},
+		{"status", db.VMListOptions{Statuses: []db.VMStatus{db.VMStatusRunning}, Limit: -1}, []string{"web-1", "db_1"}, 2},
+		{"runtime folds case", db.VMListOptions{Runtime: "postgres", Limit: -1}, []string{"db_1"}, 1},
+		{"group", db.VMListOptions{Group: "web", SortBy: db.VMSortName, Descending: true, Limit: -1}, []string{"web-2", "web-1"}, 2},
+		{"plugin", db.VMListOptions{Plugin: "NGINX", Limit: -1}, []string{"web-1", "web-2"}, 2},
+		{"query escapes wildcards", db.VMListOptions{Query: "_", Limit: -1}, []string{"db_1"}, 1},
+		{"page", db.VMListOptions{SortBy: db.VMSortName, Limit: 2, Offset: 1}, []string{"db_1", "web-1"}, 4},
+		{"selector equals", db.VMListOptions{Selector: mustSelector(t, "env=prod"), Limit: -1}, []string{"web-1", "db_1"}, 2},
+		{"selector not equals", db.VMListOptions{Selector: mustSelector(t, "env!=prod"), Limit: -1}, []string{"web-2", "cache"}, 2},
+		{"selector exists", db.VMListOptions{Selector: mustSelector(t, "tier,!canary"), Limit: -1}, []string{"web-1"}, 1},
+	}
+	for _, tc := range cases {
+		vms, total, err := vmRepo.ListPage(ctx, tc.opts)
+		if err != nil {
+			t.Fatalf("%s: list page: %v", tc.name, err)
+		}
+		if got := names(vms); fmt.Sprint(got) != fmt.Sprint(tc.want) || total != tc.total {
+			t.Fatalf("%s: got %v (total %d), want %v (total %d)", tc.name, got, total, tc.want, tc.total)
+		}
+	}
+
+	vm, err := vmRepo.GetByName(ctx, "web-2")
+	if err != nil {
+		t.Fatalf("get vm: %v", err)
+	}
+	if len(vm.Labels) != 3 || vm.Labels["env"] != "staging" {
+		t.Fatalf("unexpected labels %v", vm.Labels)
+	}
+	if err := vmRepo.SetLabels(ctx, vm.ID, map[string]string{"env": "prod"}); err != nil {
+		t.Fatalf("set labels: %v", err)
+	}
+	vm, err = vmRepo.GetByName(ctx, "web-2")
+	if err != nil {
+		t.Fatalf("get vm: %v", err)
+	}
+	if fmt.Sprint(vm.Labels) != "map[env:prod]" {
+		t.Fatalf("labels not replaced: %v", vm.Labels)
+	}
+}
+
+func mustSelector(t *testing.T, expr string) labels.Selector {
+	t.Helper()
+	selector, err := labels.Parse(expr)
+	if err != nil {
+		t.Fatalf("parse selector %q: %v", expr, err)
+	}
+	return selector
+}
+
 func openTestStore(t *testing.T) *Store {
 	t.Helper()
 	path := filepath.Join(t.TempDir(), "state.db")
@@ -216,7 +359,7 @@ func TestLeaseNextAvailableEmptyPool(t *testing.T) {
 	store := openTestStore(t)
 	t.Cleanup(func() { _ = store.Close(ctx) })
 
-	_, err := store.Queries().IPAllocations().LeaseNextAvailable(ctx)
+	_, err := store.Queries().IPAllocations().LeaseNextAvailable(ctx, db.IPFamilyV4)
 	if err != db.ErrNoAvailableIPs {
 		t.Fatalf("expected ErrNoAvailableIPs, got %v", err)
 	}
@@ -312,3 +455,599 @@ func TestPluginRepository(t *testing.T) {
 		t.Fatalf("expected nil after delete, got %+v", missing)
 	}
 }
+
+func TestAuditRepository(t *testing.T) {
+	ctx := context.Background()
+	store := openTestStore(t)
+	t.Cleanup(func() { _ = store.Close(ctx) })
+
+	repo := store.Queries().AuditEvents()
+	events := []db.AuditEvent{
+		{Action: "console.open", Target: "vm-1", Actor: "alice", ClientIP: "10.0.0.5"},
+		{Action: "console.close", Target: "vm-1", Actor: "alice", ClientIP: "10.0.0.5", Duration: 90 * time.Second},
+		{Action: "console.denied", Target: "vm-2", Actor: "anonymous", Message: "console permission required"},
+	}
+	for _, event := range events {
+		if _, err := repo.Record(ctx, event); err != nil {
+			t.Fatalf("record audit event: %v", err)
+		}
+	}
+
+	all, err := repo.List(ctx, "", 0)
+	if err != nil {
+		t.Fatalf("list audit events: %v", err)
+	}
+	if len(all) != 3 || all[0].Action != "console.denied" {
+		t.Fatalf("expected newest-first events, got %+v", all)
+	}
+
+	vm1, err := repo.List(ctx, "vm-1", 10)
+	if err != nil {
+		t.Fatalf("list vm-1 audit events: %v", err)
+	}
+	if len(vm1) != 2 {
+		t.Fatalf("expected 2 vm-1 events, got %d", len(vm1))
+	}
+	if vm1[0].Duration != 90*time.Second || vm1[0].Actor != "alice" {
+		t.Fatalf("unexpected close event: %+v", vm1[0])
+	}
+	if vm1[1].Duration != 0 {
+		t.Fatalf("expected no duration on open event, got %s", vm1[1].Duration)
+	}
+}
+
+func TestVMEventRepository(t *testing.T) {
+	ctx := context.Background()
+	store := openTestStore(t)
+	t.Cleanup(func() { _ = store.Close(ctx) })
+
+	repo := store.Queries().VMEvents()
+	base := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	events := []db.VMEvent{
+		{VMName: "vm-1", Type: "VM_RUNNING", Status: "running", CreatedAt: base},
+		{VMName: "vm-2", Type: "VM_RUNNING", Status: "running", CreatedAt: base.Add(time.Minute)},
+		{VMName: "vm-1", Type: "VM_CRASHED", Status: "crashed", Message: "exit status 137", CreatedAt: base.Add(2 * time.Minute)},
+		{VMName: "vm-1", Type: "VM_FAILED", Status: "crashed", Code: "kernel_missing", Hint: "install the kernel", CreatedAt: base.Add(3 * time.Minute)},
+	}
+	for _, event := range events {
+		if _, err := repo.Record(ctx, event); err != nil {
+			t.Fatalf("record vm event: %v", err)
+		}
+	}
+
+	vm1, err := repo.List(ctx, db.VMEventQuery{VMName: "vm-1"})
+	if err != nil {
+		t.Fatalf("list vm events: %v", err)
+	}
+	if len(vm1) != 3 || vm1[0].Type != "VM_RUNNING" || vm1[2].Type != "VM_FAILED" {
+		t.Fatalf("expected vm-1 events oldest first, got %+v", vm1)
+	}
+	if vm1[1].Message != "exit status 137" || vm1[2].Code != "kernel_missing" || vm1[2].Hint != "install the kernel" || !vm1[2].CreatedAt.Equal(base.Add(3*time.Minute)) {
+		t.Fatalf("unexpected event fields: %+v", vm1)
+	}
+
+	filtered, err := repo.List(ctx, db.VMEventQuery{VMName: "vm-1", Since: base.Add(time.Minute), Types: []string{"VM_CRASHED", "VM_RUNNING"}})
+	if err != nil {
+		t.Fatalf("list filtered vm events: %v", err)
+	}
+	if len(filtered) != 1 || filtered[0].Type != "VM_CRASHED" {
+		t.Fatalf("expected only the crash, got %+v", filtered)
+	}
+
+	last, err := repo.List(ctx, db.VMEventQuery{VMName: "vm-1", Limit: 2})
+	if err != nil {
+		t.Fatalf("list limited vm events: %v", err)
+	}
+	if len(last) != 2 || last[0].Type != "VM_CRASHED" || last[1].Type != "VM_FAILED" {
+		t.Fatalf("expected the last two events, got %+v", last)
+	}
+
+	removed, err := repo.DeleteBefore(ctx, base.Add(90*time.Second))
+	if err != nil {
+		t.Fatalf("delete vm events: %v", err)
+	}
+	if removed != 2 {
+		t.Fatalf("expected 2 events removed, got %d", removed)
+	}
+	if vm2, err := repo.List(ctx, db.VMEventQuery{VMName: "vm-2"}); err != nil || len(vm2) != 0 {
+		t.Fatalf("expected vm-2 history pruned, got %+v (%v)", vm2, err)
+	}
+}
+
+func TestMaintenanceIntentsCoalesce(t *testing.T) {
+	ctx := context.Background()
+	store := openTestStore(t)
+	t.Cleanup(func() { _ = store.Close(ctx) })
+
+	repo := store.Queries().Maintenance()
+	start := time.Date(2025, 3, 1, 2, 0, 0, 0, time.UTC)
+	window := db.MaintenanceWindow{Name: "kernel-upgrade", Scope: "deployment", Target: "web", StartsAt: start, EndsAt: start.Add(time.Hour)}
+	windowID, err := repo.Create(ctx, &window)
+	if err != nil {
+		t.Fatalf("create window: %v", err)
+	}
+
+	intent := db.MaintenanceIntent{WindowID: windowID, Source: "deployment", Target: "web", Action: "reconcile", RecordedAt: start.Add(time.Minute)}
+	for i := 0; i < 3; i++ {
+		if err := repo.RecordIntent(ctx, intent); err != nil {
+			t.Fatalf("record intent: %v", err)
+		}
+	}
+	pending, err := repo.PendingIntents(ctx, "deployment")
+	if err != nil {
+		t.Fatalf("pending intents: %v", err)
+	}
+	if len(pending) != 1 || pending[0].Count != 3 {
+		t.Fatalf("expected one coalesced intent, got %+v", pending)
+	}
+
+	if err := repo.MarkReplayed(ctx, pending[0].ID, "replayed", start.Add(2*time.Hour)); err != nil {
+		t.Fatalf("mark replayed: %v", err)
+	}
+	if err := repo.RecordIntent(ctx, intent); err != nil {
+		t.Fatalf("record intent after replay: %v", err)
+	}
+	all, err := repo.Intents(ctx, windowID)
+	if err != nil {
+		t.Fatalf("list intents: %v", err)
+	}
+	if len(all) != 2 || all[0].ReplayedAt == nil || all[1].ReplayedAt != nil {
+		t.Fatalf("expected replayed intent plus a fresh pending one, got %+v", all)
+	}
+
+	stored, err := repo.GetByName(ctx, "kernel-upgrade")
+	if err != nil || stored == nil {
+		t.Fatalf("get window: %v", err)
+	}
+	if !stored.EndsAt.Equal(start.Add(time.Hour)) || stored.Target != "web" {
+		t.Fatalf("unexpected window: %+v", stored)
+	}
+}
+
+func TestConfigBundleRepositoryVersioning(t *testing.T) {
+	ctx := context.Background()
+	store := openTestStore(t)
+	t.Cleanup(func() { _ = store.Close(ctx) })
+
+	repo := store.Queries().ConfigBundles()
+	id, err := repo.Create(ctx, &db.ConfigBundle{Name: "db-creds", DataJSON: []byte(`{"DB_USER":"app"}`), FilesJSON: []byte(`[]`)})
+	if err != nil {
+		t.Fatalf("create bundle: %v", err)
+	}
+	bundle, err := repo.GetByName(ctx, "db-creds")
+	if err != nil || bundle == nil {
+		t.Fatalf("get bundle: %v %+v", err, bundle)
+	}
+	if bundle.ID != id || bundle.Version != 1 {
+		t.Fatalf("unexpected bundle: %+v", bundle)
+	}
+
+	if err := repo.Update(ctx, id, []byte(`{"DB_USER":"svc"}`), []byte(`[]`)); err != nil {
+		t.Fatalf("update bundle: %v", err)
+	}
+	bundle, err = repo.GetByName(ctx, "db-creds")
+	if err != nil {
+		t.Fatalf("get bundle: %v", err)
+	}
+	if bundle.Version != 2 || string(bundle.DataJSON) != `{"DB_USER":"svc"}` {
+		t.Fatalf("expected version 2 with new data, got %+v", bundle)
+	}
+
+	if err := repo.Delete(ctx, id); err != nil {
+		t.Fatalf("delete bundle: %v", err)
+	}
+	if bundle, err := repo.GetByName(ctx, "db-creds"); err != nil || bundle != nil {
+		t.Fatalf("expected bundle gone, got %+v %v", bundle, err)
+	}
+}
+
+func TestIdempotencyKeyRepository(t *testing.T) {
+	ctx := context.Background()
+	store := openTestStore(t)
+	t.Cleanup(func() { _ = store.Close(ctx) })
+
+	repo := store.Queries().IdempotencyKeys()
+	now := time.Now().UTC()
+	key := db.IdempotencyKey{Scope: "POST /api/v1/vms", Key: "abc", RequestHash: "h1", CreatedAt: now, ExpiresAt: now.Add(time.Hour)}
+
+	if existing, ok, err := repo.Reserve(ctx, key); err != nil || !ok || existing != nil {
+		t.Fatalf("reserve: %+v %v %v", existing, ok, err)
+	}
+	existing, ok, err := repo.Reserve(ctx, key)
+	if err != nil || ok || existing == nil {
+		t.Fatalf("expected key held, got %+v %v %v", existing, ok, err)
+	}
+	if existing.StatusCode != 0 || existing.RequestHash != "h1" {
+		t.Fatalf("expected in-progress record, got %+v", existing)
+	}
+	// The same key on another route is independent.
+	other := key
+	other.Scope = "POST /api/v1/deployments"
+	if _, ok, err := repo.Reserve(ctx, other); err != nil || !ok {
+		t.Fatalf("reserve other scope: %v %v", ok, err)
+	}
+
+	if err := repo.Complete(ctx, key.Scope, key.Key, 201, []byte(`{"name":"vm-1"}`)); err != nil {
+		t.Fatalf("complete: %v", err)
+	}
+	existing, _, err = repo.Reserve(ctx, key)
+	if err != nil || existing == nil || existing.StatusCode != 201 || string(existing.Response) != `{"name":"vm-1"}` {
+		t.Fatalf("expected cached response, got %+v %v", existing, err)
+	}
+
+	// Once expired the key can be reserved again.
+	later := key
+	later.RequestHash = "h2"
+	later.CreatedAt = now.Add(2 * time.Hour)
+	later.ExpiresAt = now.Add(3 * time.Hour)
+	if _, ok, err := repo.Reserve(ctx, later); err != nil || !ok {
+		t.Fatalf("reserve after expiry: %v %v", ok, err)
+	}
+
+	removed, err := repo.DeleteExpired(ctx, now.Add(2*time.Hour))
+	if err != nil || removed != 1 {
+		t.Fatalf("delete expired: %d %v", removed, err)
+	}
+	if err := repo.Delete(ctx, later.Scope, later.Key); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if _, ok, err := repo.Reserve(ctx, key); err != nil || !ok {
+		t.Fatalf("reserve after delete: %v %v", ok, err)
+	}
+}
+
+func TestOperationRepository(t *testing.T) {
+	ctx := context.Background()
+	store := openTestStore(t)
+	t.Cleanup(func() { _ = store.Close(ctx) })
+
+	repo := store.Queries().Operations()
+	now := time.Now().UTC()
+	for _, id := range []string{"op-1", "op-2"} {
+		if err := repo.Create(ctx, db.Operation{ID: id, Kind: "vm.create", Target: "vm-" + id, Status: db.OperationStatusPending, CreatedAt: now}); err != nil {
+			t.Fatalf("create %s: %v", id, err)
+		}
+	}
+
+	if op, err := repo.Get(ctx, "missing"); err != nil || op != nil {
+		t.Fatalf("expected missing operation, got %+v %v", op, err)
+	}
+
+	if err := repo.Update(ctx, db.Operation{ID: "op-1", Status: db.OperationStatusRunning, UpdatedAt: now.Add(time.Second)}); err != nil {
+		t.Fatalf("update running: %v", err)
+	}
+	op, err := repo.Get(ctx, "op-1")
+	if err != nil || op == nil || op.Status != db.OperationStatusRunning || op.CompletedAt != nil {
+		t.Fatalf("expected running operation, got %+v %v", op, err)
+	}
+
+	if err := repo.Update(ctx, db.Operation{ID: "op-1", Status: db.OperationStatusFailed, Error: "boom", Code: "kernel_not_found", Hint: "install a kernel"}); err != nil {
+		t.Fatalf("update failed: %v", err)
+	}
+	op, err = repo.Get(ctx, "op-1")
+	if err != nil || op == nil || op.Status != db.OperationStatusFailed || op.CompletedAt == nil {
+		t.Fatalf("expected failed operation, got %+v %v", op, err)
+	}
+	if op.Error != "boom" || op.Code != "kernel_not_found" || op.Hint != "install a kernel" || op.Target != "vm-op-1" {
+		t.Fatalf("unexpected failure details: %+v", op)
+	}
+
+	n, err := repo.FailUnfinished(ctx, "interrupted", now.Add(time.Minute))
+	if err != nil || n != 1 {
+		t.Fatalf("fail unfinished: %d %v", n, err)
+	}
+	op, err = repo.Get(ctx, "op-2")
+	if err != nil || op == nil || op.Status != db.OperationStatusFailed || op.Error != "interrupted" || op.CompletedAt == nil {
+		t.Fatalf("expected interrupted operation, got %+v %v", op, err)
+	}
+}
+
+func TestDeviceAssignmentRepository(t *testing.T) {
+	ctx := context.Background()
+	store := openTestStore(t)
+	t.Cleanup(func() { _ = store.Close(ctx) })
+
+	vms := store.Queries().VirtualMachines()
+	gpuVM, err := vms.Create(ctx, &db.VM{Name: "gpu", Status: db.VMStatusRunning, Runtime: "llm", IPAddress: "10.0.0.2", MACAddress: "02:00:00:00:00:02", CPUCores: 1, MemoryMB: 512})
+	if err != nil {
+		t.Fatalf("create vm: %v", err)
+	}
+	otherVM, err := vms.Create(ctx, &db.VM{Name: "other", Status: db.VMStatusRunning, Runtime: "llm", IPAddress: "10.0.0.3", MACAddress: "02:00:00:00:00:03", CPUCores: 1, MemoryMB: 512})
+	if err != nil {
+		t.Fatalf("create vm: %v", err)
+	}
+
+	repo := store.Queries().DeviceAssignments()
+	for _, assignment := range []db.DeviceAssignment{
+		{PCIAddress: "0000:01:00.1", VMID: gpuVM, IOMMUGroup: "12"},
+		{PCIAddress: "0000:01:00.0", VMID: gpuVM, IOMMUGroup: "12"},
+		{PCIAddress: "0000:02:00.0", VMID: otherVM},
+	} {
+		if err := repo.Assign(ctx, assignment); err != nil {
+			t.Fatalf("assign %s: %v", assignment.PCIAddress, err)
+		}
+	}
+
+	if err := repo.Assign(ctx, db.DeviceAssignment{PCIAddress: "0000:02:00.0", VMID: gpuVM}); !errors.Is(err, db.ErrDeviceAssigned) {
+		t.Fatalf("expected held device refused, got %v", err)
+	}
+	if err := repo.Assign(ctx, db.DeviceAssignment{PCIAddress: "0000:02:00.0", VMID: otherVM}); err != nil {
+		t.Fatalf("reassign to holder: %v", err)
+	}
+
+	list, err := repo.List(ctx)
+	if err != nil || len(list) != 3 {
+		t.Fatalf("expected 3 assignments, got %+v %v", list, err)
+	}
+	if list[0].PCIAddress != "0000:01:00.0" || list[0].VMName != "gpu" || list[0].IOMMUGroup != "12" || list[0].AssignedAt.IsZero() {
+		t.Fatalf("unexpected first assignment: %+v", list[0])
+	}
+	if list[2].IOMMUGroup != "" || list[2].VMName != "other" {
+		t.Fatalf("unexpected ungrouped assignment: %+v", list[2])
+	}
+
+	if err := repo.ReleaseVM(ctx, gpuVM); err != nil {
+		t.Fatalf("release: %v", err)
+	}
+	if list, err := repo.List(ctx); err != nil || len(list) != 1 || list[0].VMID != otherVM {
+		t.Fatalf("expected only other vm's device left, got %+v %v", list, err)
+	}
+
+	if err := vms.Delete(ctx, otherVM); err != nil {
+		t.Fatalf("delete vm: %v", err)
+	}
+	if list, err := repo.List(ctx); err != nil || len(list) != 0 {
+		t.Fatalf("expected assignments removed with the vm, got %+v %v", list, err)
+	}
+}
+
+func TestPluginVersionRepository(t *testing.T) {
+	ctx := context.Background()
+	store := openTestStore(t)
+	t.Cleanup(func() { _ = store.Close(ctx) })
+
+	repo := store.Queries().PluginVersions()
+	for _, version := range []db.PluginVersion{
+		{PluginName: "caddy", Version: "1.0.0", Metadata: []byte(`{"version":"1.0.0"}`)},
+		{PluginName: "caddy", Version: "1.1.0", Metadata: []byte(`{"version":"1.1.0"}`)},
+		{PluginName: "redis", Version: "7.0.0"},
+	} {
+		if err := repo.Upsert(ctx, version); err != nil {
+			t.Fatalf("upsert %s %s: %v", version.PluginName, version.Version, err)
+		}
+	}
+	if err := repo.Upsert(ctx, db.PluginVersion{PluginName: "caddy", Version: "1.0.0", Metadata: []byte(`{"version":"1.0.0","labels":{"a":"b"}}`)}); err != nil {
+		t.Fatalf("reinstall: %v", err)
+	}
+
+	list, err := repo.ListByPlugin(ctx, "caddy")
+	if err != nil || len(list) != 2 {
+		t.Fatalf("expected 2 caddy versions, got %+v %v", list, err)
+	}
+	if list[0].Version != "1.1.0" || list[1].Version != "1.0.0" || list[1].InstalledAt.IsZero() {
+		t.Fatalf("expected newest version first, got %+v", list)
+	}
+
+	got, err := repo.Get(ctx, "caddy", "1.0.0")
+	if err != nil || got == nil || string(got.Metadata) != `{"version":"1.0.0","labels":{"a":"b"}}` {
+		t.Fatalf("expected reinstalled manifest, got %+v %v", got, err)
+	}
+	if missing, err := repo.Get(ctx, "caddy", "2.0.0"); err != nil || missing != nil {
+		t.Fatalf("expected missing version to be nil, got %+v %v", missing, err)
+	}
+
+	if err := repo.Delete(ctx, "caddy", "1.1.0"); err != nil {
+		t.Fatalf("delete version: %v", err)
+	}
+	if err := repo.DeleteByPlugin(ctx, "redis"); err != nil {
+		t.Fatalf("delete plugin versions: %v", err)
+	}
+	if list, _ := repo.ListByPlugin(ctx, "caddy"); len(list) != 1 || list[0].Version != "1.0.0" {
+		t.Fatalf("expected only caddy 1.0.0 left, got %+v", list)
+	}
+	if list, _ := repo.ListByPlugin(ctx, "redis"); len(list) != 0 {
+		t.Fatalf("expected redis versions gone, got %+v", list)
+	}
+}
+
+func TestWebhookRepository(t *testing.T) {
+	ctx := context.Background()
+	store := openTestStore(t)
+	t.Cleanup(func() { _ = store.Close(ctx) })
+
+	repo := store.Queries().Webhooks()
+	id, err := repo.Create(ctx, &db.Webhook{Name: "ops", URL: "https://hooks.example/volant", Events: []string{"VM_CRASHED", "VM_FAILED"}, Secret: "s3cret", Enabled: true})
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	if _, err := repo.Create(ctx, &db.Webhook{Name: "all", URL: "https://hooks.example/all", Secret: "x"}); err != nil {
+		t.Fatalf("create all: %v", err)
+	}
+	hook, err := repo.GetByName(ctx, "ops")
+	if err != nil || hook == nil || hook.ID != id || !hook.Enabled || hook.Secret != "s3cret" || len(hook.Events) != 2 || hook.Events[1] != "VM_FAILED" {
+		t.Fatalf("unexpected webhook %+v %v", hook, err)
+	}
+	if list, err := repo.List(ctx); err != nil || len(list) != 2 || list[0].Name != "all" || list[0].Events != nil || list[0].Enabled {
+		t.Fatalf("unexpected list %+v %v", list, err)
+	}
+
+	old := time.Now().Add(-48 * time.Hour)
+	for attempt, created := range []time.Time{old, time.Now()} {
+		if _, err := repo.RecordDelivery(ctx, db.WebhookDelivery{WebhookID: id, DeliveryID: "d-1", EventType: "VM_CRASHED", Attempt: attempt + 1, StatusCode: 500 - attempt*300, Error: "boom", Succeeded: attempt == 1, Payload: []byte(`{}`), Duration: 1500 * time.Millisecond, CreatedAt: created}); err != nil {
+			t.Fatalf("record delivery: %v", err)
+		}
+	}
+	deliveries, err := repo.Deliveries(ctx, id, 0)
+	if err != nil || len(deliveries) != 2 {
+		t.Fatalf("deliveries: %+v %v", deliveries, err)
+	}
+	if latest := deliveries[0]; latest.Attempt != 2 || !latest.Succeeded || latest.StatusCode != 200 || latest.Duration != 1500*time.Millisecond || string(latest.Payload) != `{}` {
+		t.Fatalf("unexpected latest delivery %+v", latest)
+	}
+	if n, err := repo.DeleteDeliveriesBefore(ctx, time.Now().Add(-24*time.Hour)); err != nil || n != 1 {
+		t.Fatalf("prune deliveries: %d %v", n, err)
+	}
+
+	if err := repo.SetEnabled(ctx, id, false); err != nil {
+		t.Fatalf("disable: %v", err)
+	}
+	if hook, _ := repo.GetByName(ctx, "ops"); hook == nil || hook.Enabled {
+		t.Fatalf("expected disabled webhook, got %+v", hook)
+	}
+	if err := repo.Delete(ctx, id); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if deliveries, _ := repo.Deliveries(ctx, id, 0); len(deliveries) != 0 {
+		t.Fatalf("expected deliveries removed with webhook, got %+v", deliveries)
+	}
+}
+
+func TestSecretRepository(t *testing.T) {
+	ctx := context.Background()
+	store := openTestStore(t)
+	t.Cleanup(func() { _ = store.Close(ctx) })
+
+	repo := store.Queries().Secrets()
+	id, err := repo.Create(ctx, &db.Secret{Name: "db-password", Sealed: []byte{0x00, 0x01, 0xff}})
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	if _, err := repo.Create(ctx, &db.Secret{Name: "db-password", Sealed: []byte{0x02}}); err == nil {
+		t.Fatalf("expected duplicate name to fail")
+	}
+	secret, err := repo.GetByName(ctx, "db-password")
+	if err != nil || secret == nil || secret.ID != id || !bytes.Equal(secret.Sealed, []byte{0x00, 0x01, 0xff}) {
+		t.Fatalf("unexpected secret %+v %v", secret, err)
+	}
+
+	if err := repo.Update(ctx, id, []byte{0x03}); err != nil {
+		t.Fatalf("update: %v", err)
+	}
+	if list, err := repo.List(ctx); err != nil || len(list) != 1 || !bytes.Equal(list[0].Sealed, []byte{0x03}) {
+		t.Fatalf("unexpected list %+v %v", list, err)
+	}
+
+	if err := repo.Delete(ctx, id); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if secret, err := repo.GetByName(ctx, "db-password"); err != nil || secret != nil {
+		t.Fatalf("expected secret gone, got %+v %v", secret, err)
+	}
+}
+
+func TestMigrationsRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	store := openTestStore(t)
+	t.Cleanup(func() { _ = store.Close(ctx) })
+
+	if _, err := store.Queries().VMGroups().Create(ctx, &db.VMGroup{Name: "web", ConfigJSON: []byte(`{}`), Replicas: 1}); err != nil {
+		t.Fatalf("create group: %v", err)
+	}
+	if _, err := store.Queries().VirtualMachines().Create(ctx, &db.VM{Name: "vm-1", Status: db.VMStatusStopped, Runtime: "nginx", IPAddress: "192.168.127.2", MACAddress: "02:00:00:00:00:01", CPUCores: 1, MemoryMB: 256}); err != nil {
+		t.Fatalf("create vm: %v", err)
+	}
+
+	m, err := store.Migrator()
+	if err != nil {
+		t.Fatalf("migrator: %v", err)
+	}
+	latest := m.Latest()
+
+	reverted, err := m.Down(ctx, 5)
+	if err != nil {
+		t.Fatalf("down to 5: %v", err)
+	}
+	if len(reverted) != latest-5 || reverted[0].Version != latest {
+		t.Fatalf("expected newest-first rollback of %d migrations, got %d", latest-5, len(reverted))
+	}
+	// Rows survive rebuilding vms without the deployment column.
+	var name string
+	if err := store.db.QueryRowContext(ctx, `SELECT name FROM vms;`).Scan(&name); err != nil || name != "vm-1" {
+		t.Fatalf("vm lost in rollback: %q %v", name, err)
+	}
+
+	if _, err := m.Down(ctx, 0); err != nil {
+		t.Fatalf("down to 0: %v", err)
+	}
+	applied, err := m.Up(ctx, 0)
+	if err != nil {
+		t.Fatalf("up: %v", err)
+	}
+	if len(applied) != latest {
+		t.Fatalf("expected %d migrations re-applied, got %d", latest, len(applied))
+	}
+	status, err := m.Status(ctx)
+	if err != nil {
+		t.Fatalf("status: %v", err)
+	}
+	for _, s := range status {
+		if !s.Applied || s.AppliedAt.IsZero() {
+			t.Fatalf("migration %d not applied: %+v", s.Version, s)
+		}
+	}
+}
+
+func TestMigrationsRefuseNewerSchema(t *testing.T) {
+	ctx := context.Background()
+	store := openTestStore(t)
+	t.Cleanup(func() { _ = store.Close(ctx) })
+
+	if _, err := store.db.ExecContext(ctx, `INSERT INTO schema_migrations(version, name) VALUES (9999, 'from_the_future');`); err != nil {
+		t.Fatalf("insert future migration: %v", err)
+	}
+	m, err := store.Migrator()
+	if err != nil {
+		t.Fatalf("migrator: %v", err)
+	}
+	if _, err := m.Up(ctx, 0); !errors.Is(err, migrate.ErrSchemaTooNew) {
+		t.Fatalf("expected ErrSchemaTooNew, got %v", err)
+	}
+}
+
+func TestBackupAndRestore(t *testing.T) {
+	ctx := context.Background()
+	store := openTestStore(t)
+	t.Cleanup(func() { _ = store.Close(ctx) })
+
+	plugins := store.Queries().Plugins()
+	if err := plugins.Upsert(ctx, db.Plugin{Name: "nginx", Version: "1.0.0", Enabled: true, Metadata: []byte(`{}`)}); err != nil {
+		t.Fatalf("upsert plugin: %v", err)
+	}
+	path := filepath.Join(t.TempDir(), "backup.db")
+	if err := store.Backup(ctx, path); err != nil {
+		t.Fatalf("backup: %v", err)
+	}
+	if err := store.Backup(ctx, path); err == nil {
+		t.Fatalf("expected backup over an existing file to fail")
+	}
+
+	if err := plugins.Delete(ctx, "nginx"); err != nil {
+		t.Fatalf("delete plugin: %v", err)
+	}
+	if err := store.Restore(ctx, path); err != nil {
+		t.Fatalf("restore: %v", err)
+	}
+	plugin, err := store.Queries().Plugins().GetByName(ctx, "nginx")
+	if err != nil || plugin == nil {
+		t.Fatalf("expected restored plugin, got %+v %v", plugin, err)
+	}
+}
+
+func TestRestoreRefusesNewerSchema(t *testing.T) {
+	ctx := context.Background()
+	source := openTestStore(t)
+	t.Cleanup(func() { _ = source.Close(ctx) })
+	if _, err := source.db.ExecContext(ctx, `INSERT INTO schema_migrations(version, name) VALUES (9999, 'from_the_future');`); err != nil {
+		t.Fatalf("insert future migration: %v", err)
+	}
+	path := filepath.Join(t.TempDir(), "backup.db")
+	if err := source.Backup(ctx, path); err != nil {
+		t.Fatalf("backup: %v", err)
+	}
+
+	store := openTestStore(t)
+	t.Cleanup(func() { _ = store.Close(ctx) })
+	if err := store.Restore(ctx, path); !errors.Is(err, migrate.ErrSchemaTooNew) {
+		t.Fatalf("expected ErrSchemaTooNew, got %v", err)
+	}
+}
@@ -9,6 +9,8 @@ import (
 	"context"
 	"errors"
 	"time"
+
+	"github.com/volantvm/volant/internal/server/labels"
 )
 
 // VMStatus enumerates the lifecycle phases tracked for microVMs.
@@ -30,6 +32,7 @@ type VM struct {
 	Runtime       string
 	PID           *int64
 	IPAddress     string
+	IPv6Address   string // Secondary address on dual-stack hosts
 	MACAddress    string
 	VsockCID      uint32 // Vsock Context ID for vsock communication
 	CPUCores      int
@@ -37,8 +40,39 @@ type VM struct {
 	KernelCmdline string
 	SerialSocket  string
 	GroupID       *int64
-	CreatedAt     time.Time
-	UpdatedAt     time.Time
+	Labels        map[string]string
+	// ExpiresAt is when the VM's TTL runs out; nil when it has none.
+	ExpiresAt *time.Time
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// VM sort keys accepted by VMListOptions.SortBy.
+const (
+	VMSortCreatedAt = "created_at"
+	VMSortUpdatedAt = "updated_at"
+	VMSortName      = "name"
+	VMSortStatus    = "status"
+	VMSortRuntime   = "runtime"
+)
+
+// VMListOptions filters, orders and pages a VM listing. Empty fields match
+// every VM.
+type VMListOptions struct {
+	Statuses []VMStatus
+	Runtime  string
+	// Group is a deployment name.
+	Group string
+	// Plugin matches the plugin named in the VM config or its inline manifest.
+	Plugin string
+	// Query is a case-insensitive substring of the name, IP address or runtime.
+	Query      string
+	Selector   labels.Selector
+	SortBy     string
+	Descending bool
+	// Limit caps the page size; negative means no limit.
+	Limit  int
+	Offset int
 }
 
 // VMGroup represents a deployment/group of VMs managed together.
@@ -47,6 +81,7 @@ type VMGroup struct {
 	Name       string
 	ConfigJSON []byte
 	Replicas   int
+	MaxSurge   int
 	CreatedAt  time.Time
 	UpdatedAt  time.Time
 }
@@ -75,6 +110,247 @@ type VMCloudInit struct {
 	UpdatedAt     time.Time
 }
 
+// Schedule is a cron-style rule that applies a lifecycle action to a target.
+type Schedule struct {
+	ID         int64
+	Name       string
+	Cron       string
+	Action     string
+	TargetKind string
+	Target     string
+	Replicas   *int
+	Enabled    bool
+	LastRunAt  *time.Time
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
+
+// ScheduleRun records the outcome of a single schedule execution.
+type ScheduleRun struct {
+	ID         int64
+	ScheduleID int64
+	Status     string
+	Message    string
+	StartedAt  time.Time
+	FinishedAt time.Time
+}
+
+// Webhook delivers lifecycle events to an external URL.
+type Webhook struct {
+	ID   int64
+	Name string
+	URL  string
+	// Events lists the event types delivered; empty delivers every type.
+	Events []string
+	// Secret keys the HMAC signature sent with each delivery.
+	Secret    string
+	Enabled   bool
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// WebhookDelivery records one attempt to deliver an event to a webhook.
+type WebhookDelivery struct {
+	ID        int64
+	WebhookID int64
+	// DeliveryID is shared by every attempt to deliver the same event.
+	DeliveryID string
+	EventType  string
+	Attempt    int
+	// StatusCode is zero when no response was received.
+	StatusCode int
+	Error      string
+	Succeeded  bool
+	Payload    []byte
+	Duration   time.Duration
+	CreatedAt  time.Time
+}
+
+// Secret is a named value cloud-init templates can reference. Sealed holds
+// the value encrypted by the secrets manager; the store never sees it in
+// plain text.
+type Secret struct {
+	ID        int64
+	Name      string
+	Sealed    []byte
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// WarmPool keeps Size booted, unclaimed VMs of a plugin ready to hand out.
+// ConfigJSON is the VM config the pool's VMs are created from.
+type WarmPool struct {
+	ID         int64
+	Plugin     string
+	Size       int
+	ConfigJSON []byte
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
+
+// Network is a user-defined bridge with its own subnet and address pool.
+type Network struct {
+	ID      int64
+	Name    string
+	Bridge  string
+	Subnet  string
+	Gateway string
+	// Internal networks get no outbound NAT.
+	Internal  bool
+	CreatedAt time.Time
+}
+
+// NetworkLease records the address a VM holds on a user-defined network.
+type NetworkLease struct {
+	NetworkID int64
+	IPAddress string
+	VMID      int64
+	VMName    string
+	LeasedAt  time.Time
+}
+
+// ConfigBundle is a named set of key/value pairs and files injected into every
+// replica of the deployments that reference it.
+type ConfigBundle struct {
+	ID        int64
+	Name      string
+	DataJSON  []byte
+	FilesJSON []byte
+	// Version increases on every update so rotations can be told apart.
+	Version   int
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// DHCPLease records an address the embedded DHCP server bound to a VM's MAC.
+type DHCPLease struct {
+	MACAddress string
+	IPAddress  string
+	VMID       int64
+	VMName     string
+	Hostname   string
+	ExpiresAt  time.Time
+	UpdatedAt  time.Time
+}
+
+// DeviceAssignment records a PCI device passed through to a running VM.
+type DeviceAssignment struct {
+	PCIAddress string
+	VMID       int64
+	VMName     string
+	// IOMMUGroup is empty when the host reported no group for the device.
+	IOMMUGroup string
+	AssignedAt time.Time
+}
+
+// IdempotencyKey records a create request made with a client request token
+// and, once it has finished, the response replayed to retries.
+type IdempotencyKey struct {
+	// Scope is the method and route the key was used on; keys are unique per
+	// scope.
+	Scope       string
+	Key         string
+	RequestHash string
+	// StatusCode is zero while the original request is still running.
+	StatusCode int
+	Response   []byte
+	CreatedAt  time.Time
+	ExpiresAt  time.Time
+}
+
+// OperationStatus is the lifecycle stage of an asynchronous operation.
+type OperationStatus string
+
+const (
+	OperationStatusPending   OperationStatus = "pending"
+	OperationStatusRunning   OperationStatus = "running"
+	OperationStatusSucceeded OperationStatus = "succeeded"
+	OperationStatusFailed    OperationStatus = "failed"
+)
+
+// Operation tracks a long-running request that was accepted before it
+// finished, such as an async VM create.
+type Operation struct {
+	ID     string
+	Kind   string
+	Target string
+	Status OperationStatus
+	// Error, Code and Hint describe why a failed operation failed.
+	Error       string
+	Code        string
+	Hint        string
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+	CompletedAt *time.Time
+}
+
+// MaintenanceWindow suspends automatic reconciliation for a scope between
+// StartsAt and EndsAt.
+type MaintenanceWindow struct {
+	ID        int64
+	Name      string
+	Scope     string
+	Target    string
+	Reason    string
+	StartsAt  time.Time
+	EndsAt    time.Time
+	CreatedAt time.Time
+}
+
+// MaintenanceIntent is an automatic action held back by a maintenance window.
+// Repeats of the same pending action are coalesced into Count.
+type MaintenanceIntent struct {
+	ID         int64
+	WindowID   int64
+	Source     string
+	Target     string
+	Action     string
+	Count      int
+	RecordedAt time.Time
+	ReplayedAt *time.Time
+	Outcome    string
+}
+
+// AuditEvent records a security-relevant action taken against a target, such
+// as a console session on a VM.
+type AuditEvent struct {
+	ID       int64
+	Action   string
+	Target   string
+	Actor    string
+	ClientIP string
+	Message  string
+	// Duration is set for actions that span time, like a closed console
+	// session; zero means not applicable.
+	Duration  time.Duration
+	CreatedAt time.Time
+}
+
+// VMEvent is a recorded lifecycle event of a VM. Events are keyed by VM
+// name and outlive the VM until retention removes them.
+type VMEvent struct {
+	ID      int64
+	VMName  string
+	Type    string
+	Status  string
+	Message string
+	// Code and Hint classify a launch failure.
+	Code      string
+	Hint      string
+	CreatedAt time.Time
+}
+
+// VMEventQuery filters a VM's recorded events. Empty fields match every
+// event.
+type VMEventQuery struct {
+	VMName string
+	// Since drops events recorded before it.
+	Since time.Time
+	Types []string
+	// Limit keeps the last Limit matching events; zero means 100.
+	Limit int
+}
+
 // VMConfig captures the serialized configuration stored for a VM.
 type VMConfig struct {
 	VMID       int64
@@ -90,6 +366,10 @@ type VMConfigHistoryEntry struct {
 	Version    int
 	ConfigJSON []byte
 	UpdatedAt  time.Time
+	// Outcome records how the version fared after being applied; empty when
+	// the update did not request health-checked rollback.
+	Outcome        string
+	OutcomeMessage string
 }
 
 // IPStatus communicates whether an address is available or leased.
@@ -100,6 +380,12 @@ const (
 	IPStatusLeased    IPStatus = "leased"
 )
 
+// Address families accepted by IPRepository.LeaseNextAvailable.
+const (
+	IPFamilyV4 = 4
+	IPFamilyV6 = 6
+)
+
 // IPAllocation captures pool state for deterministic static IP assignment.
 type IPAllocation struct {
 	IPAddress string
@@ -111,6 +397,10 @@ type IPAllocation struct {
 // ErrNoAvailableIPs is returned when the allocator cannot find a free address.
 var ErrNoAvailableIPs = errors.New("db: no available ip addresses")
 
+// ErrDeviceAssigned is returned when a PCI device is already assigned to
+// another VM.
+var ErrDeviceAssigned = errors.New("db: device assigned to another vm")
+
 // Store describes the persistence surface consumed by the orchestrator.
 type Store interface {
 	Close(ctx context.Context) error
@@ -118,6 +408,17 @@ type Store interface {
 	WithTx(ctx context.Context, fn func(Queries) error) error
 }
 
+// Backupper is implemented by stores that can snapshot their database to a
+// local file and replace their contents from one.
+type Backupper interface {
+	// Backup writes a consistent copy of the database to path, which must not
+	// exist yet.
+	Backup(ctx context.Context, path string) error
+	// Restore replaces the live database with the snapshot at path and brings
+	// its schema up to date.
+	Restore(ctx context.Context, path string) error
+}
+
 type Plugin struct {
 	ID          int64
 	Name        string
@@ -128,6 +429,16 @@ type Plugin struct {
 	UpdatedAt   time.Time
 }
 
+// PluginVersion is one installed version of a plugin. The active version is
+// the one held in the plugins table.
+type PluginVersion struct {
+	ID          int64
+	PluginName  string
+	Version     string
+	Metadata    []byte
+	InstalledAt time.Time
+}
+
 type PluginRepository interface {
 	Upsert(ctx context.Context, plugin Plugin) error
 	List(ctx context.Context) ([]Plugin, error)
@@ -146,15 +457,43 @@ type Queries interface {
 	VMGroups() VMGroupRepository
 	PluginArtifacts() PluginArtifactRepository
 	VMCloudInit() VMCloudInitRepository
+	Schedules() ScheduleRepository
+	AuditEvents() AuditRepository
+	Maintenance() MaintenanceRepository
+	Networks() NetworkRepository
+	DHCPLeases() DHCPLeaseRepository
+	ConfigBundles() ConfigBundleRepository
+	IdempotencyKeys() IdempotencyKeyRepository
+	Operations() OperationRepository
+	DeviceAssignments() DeviceAssignmentRepository
+	PluginVersions() PluginVersionRepository
+	Webhooks() WebhookRepository
+	Secrets() SecretRepository
+	WarmPools() WarmPoolRepository
+	VMEvents() VMEventRepository
 }
 
 // VMRepository manages CRUD and lifecycle updates for VMs.
 type VMRepository interface {
 	Create(ctx context.Context, vm *VM) (int64, error)
 	GetByName(ctx context.Context, name string) (*VM, error)
+	// GetByMAC returns nil when no VM uses mac.
+	GetByMAC(ctx context.Context, mac string) (*VM, error)
 	List(ctx context.Context) ([]VM, error)
+	// ListPage returns the VMs matching opts and the total number of matches
+	// before Limit and Offset are applied.
+	ListPage(ctx context.Context, opts VMListOptions) ([]VM, int, error)
 	ListByGroupID(ctx context.Context, groupID int64) ([]VM, error)
+	// VsockCIDs returns the vsock CIDs assigned to VMs in ascending order.
+	VsockCIDs(ctx context.Context) ([]uint32, error)
+	// SetLabels replaces the labels of a VM.
+	SetLabels(ctx context.Context, id int64, labels map[string]string) error
 	UpdateRuntimeState(ctx context.Context, id int64, status VMStatus, pid *int64) error
+	// SetExpiresAt sets or, with nil, clears the time the VM's TTL runs out.
+	SetExpiresAt(ctx context.Context, id int64, expiresAt *time.Time) error
+	// ListExpired returns the VMs whose TTL ran out at or before now,
+	// soonest first.
+	ListExpired(ctx context.Context, now time.Time) ([]VM, error)
 	UpdateKernelCmdline(ctx context.Context, id int64, cmdline string) error
 	UpdateSockets(ctx context.Context, id int64, serial string) error
 	UpdateSpec(ctx context.Context, id int64, runtime string, cpuCores, memoryMB int, kernelCmdline string) error
@@ -166,6 +505,7 @@ type VMConfigRepository interface {
 	GetCurrent(ctx context.Context, vmID int64) (*VMConfig, error)
 	Upsert(ctx context.Context, vmID int64, payload []byte) (*VMConfig, error)
 	History(ctx context.Context, vmID int64, limit int) ([]VMConfigHistoryEntry, error)
+	SetOutcome(ctx context.Context, vmID int64, version int, outcome, message string) error
 }
 
 // VMGroupRepository manages VM deployment groups.
@@ -188,16 +528,173 @@ type PluginArtifactRepository interface {
 	DeleteByPlugin(ctx context.Context, plugin string) error
 }
 
+// PluginVersionRepository keeps every installed version of each plugin.
+type PluginVersionRepository interface {
+	// Upsert records a version, replacing the manifest stored for it when the
+	// version is already installed.
+	Upsert(ctx context.Context, version PluginVersion) error
+	// ListByPlugin returns the versions of plugin, most recently installed
+	// first.
+	ListByPlugin(ctx context.Context, plugin string) ([]PluginVersion, error)
+	Get(ctx context.Context, plugin, version string) (*PluginVersion, error)
+	Delete(ctx context.Context, plugin, version string) error
+	DeleteByPlugin(ctx context.Context, plugin string) error
+}
+
 type VMCloudInitRepository interface {
 	Upsert(ctx context.Context, record VMCloudInit) error
 	Get(ctx context.Context, vmID int64) (*VMCloudInit, error)
 	Delete(ctx context.Context, vmID int64) error
 }
 
+// ScheduleRepository manages scheduled lifecycle rules and their run history.
+type ScheduleRepository interface {
+	Create(ctx context.Context, schedule *Schedule) (int64, error)
+	GetByName(ctx context.Context, name string) (*Schedule, error)
+	List(ctx context.Context) ([]Schedule, error)
+	SetEnabled(ctx context.Context, id int64, enabled bool) error
+	Delete(ctx context.Context, id int64) error
+	RecordRun(ctx context.Context, run ScheduleRun) (int64, error)
+	Runs(ctx context.Context, scheduleID int64, limit int) ([]ScheduleRun, error)
+}
+
+// WebhookRepository manages webhook registrations and their delivery history.
+type WebhookRepository interface {
+	Create(ctx context.Context, webhook *Webhook) (int64, error)
+	GetByName(ctx context.Context, name string) (*Webhook, error)
+	List(ctx context.Context) ([]Webhook, error)
+	SetEnabled(ctx context.Context, id int64, enabled bool) error
+	Delete(ctx context.Context, id int64) error
+	RecordDelivery(ctx context.Context, delivery WebhookDelivery) (int64, error)
+	// Deliveries returns the most recent attempts for the webhook, newest
+	// first.
+	Deliveries(ctx context.Context, webhookID int64, limit int) ([]WebhookDelivery, error)
+	// DeleteDeliveriesBefore removes attempts recorded before cutoff.
+	DeleteDeliveriesBefore(ctx context.Context, cutoff time.Time) (int64, error)
+}
+
+// SecretRepository stores sealed secrets.
+type SecretRepository interface {
+	Create(ctx context.Context, secret *Secret) (int64, error)
+	GetByName(ctx context.Context, name string) (*Secret, error)
+	List(ctx context.Context) ([]Secret, error)
+	// Update replaces the sealed value.
+	Update(ctx context.Context, id int64, sealed []byte) error
+	Delete(ctx context.Context, id int64) error
+}
+
+// WarmPoolRepository manages warm pool definitions, one per plugin.
+type WarmPoolRepository interface {
+	// Upsert creates the pool of pool.Plugin or replaces its size and config.
+	Upsert(ctx context.Context, pool *WarmPool) error
+	GetByPlugin(ctx context.Context, plugin string) (*WarmPool, error)
+	List(ctx context.Context) ([]WarmPool, error)
+	Delete(ctx context.Context, id int64) error
+}
+
+// NetworkRepository manages user-defined networks and their address leases.
+type NetworkRepository interface {
+	Create(ctx context.Context, network *Network) (int64, error)
+	GetByName(ctx context.Context, name string) (*Network, error)
+	List(ctx context.Context) ([]Network, error)
+	Delete(ctx context.Context, id int64) error
+	Leases(ctx context.Context, networkID int64) ([]NetworkLease, error)
+	// Lease records ip on the network for the VM; leases are dropped when the
+	// VM row is deleted.
+	Lease(ctx context.Context, networkID int64, ip string, vmID int64) error
+	LeaseForVM(ctx context.Context, vmID int64) (*NetworkLease, error)
+}
+
+// ConfigBundleRepository manages deployment config bundles.
+type ConfigBundleRepository interface {
+	Create(ctx context.Context, bundle *ConfigBundle) (int64, error)
+	GetByName(ctx context.Context, name string) (*ConfigBundle, error)
+	List(ctx context.Context) ([]ConfigBundle, error)
+	// Update replaces the bundle contents and bumps its version.
+	Update(ctx context.Context, id int64, dataJSON, filesJSON []byte) error
+	Delete(ctx context.Context, id int64) error
+}
+
+// IdempotencyKeyRepository tracks client request tokens and the responses
+// cached for them.
+type IdempotencyKeyRepository interface {
+	// Reserve claims key.Scope/key.Key for a new request. When the key is
+	// already held and unexpired it returns the existing record and false.
+	Reserve(ctx context.Context, key IdempotencyKey) (*IdempotencyKey, bool, error)
+	// Complete stores the response for a reserved key.
+	Complete(ctx context.Context, scope, key string, statusCode int, response []byte) error
+	Delete(ctx context.Context, scope, key string) error
+	// DeleteExpired removes keys that expired at or before now.
+	DeleteExpired(ctx context.Context, now time.Time) (int64, error)
+}
+
+// OperationRepository records asynchronous operations and their outcome.
+type OperationRepository interface {
+	Create(ctx context.Context, op Operation) error
+	Get(ctx context.Context, id string) (*Operation, error)
+	// Update stores the status and failure details of op.ID; terminal
+	// statuses also set CompletedAt.
+	Update(ctx context.Context, op Operation) error
+	// FailUnfinished marks every pending or running operation failed with
+	// message, for operations cut short by a daemon restart.
+	FailUnfinished(ctx context.Context, message string, at time.Time) (int64, error)
+}
+
+// DHCPLeaseRepository tracks leases handed out by the embedded DHCP server.
+type DHCPLeaseRepository interface {
+	// Upsert records or renews the lease for lease.MACAddress.
+	Upsert(ctx context.Context, lease DHCPLease) error
+	Delete(ctx context.Context, mac string) error
+	List(ctx context.Context) ([]DHCPLease, error)
+}
+
+// DeviceAssignmentRepository tracks which VM holds each passthrough device.
+type DeviceAssignmentRepository interface {
+	// Assign records that assignment.VMID holds assignment.PCIAddress. It
+	// returns ErrDeviceAssigned when another VM already holds the address.
+	Assign(ctx context.Context, assignment DeviceAssignment) error
+	// ReleaseVM drops every assignment held by vmID.
+	ReleaseVM(ctx context.Context, vmID int64) error
+	List(ctx context.Context) ([]DeviceAssignment, error)
+}
+
+// MaintenanceRepository manages maintenance windows and the intents they hold.
+type MaintenanceRepository interface {
+	Create(ctx context.Context, window *MaintenanceWindow) (int64, error)
+	GetByName(ctx context.Context, name string) (*MaintenanceWindow, error)
+	List(ctx context.Context) ([]MaintenanceWindow, error)
+	SetEndsAt(ctx context.Context, id int64, endsAt time.Time) error
+	Delete(ctx context.Context, id int64) error
+	// RecordIntent stores a held action, folding it into an identical pending
+	// intent of the same window when one exists.
+	RecordIntent(ctx context.Context, intent MaintenanceIntent) error
+	Intents(ctx context.Context, windowID int64) ([]MaintenanceIntent, error)
+	// PendingIntents returns intents from source that have not been replayed.
+	PendingIntents(ctx context.Context, source string) ([]MaintenanceIntent, error)
+	MarkReplayed(ctx context.Context, id int64, outcome string, at time.Time) error
+}
+
+// AuditRepository appends to and reads the audit trail.
+type AuditRepository interface {
+	Record(ctx context.Context, event AuditEvent) (int64, error)
+	// List returns the most recent events, newest first. An empty target
+	// matches every target.
+	List(ctx context.Context, target string, limit int) ([]AuditEvent, error)
+}
+
+// VMEventRepository records and reads the lifecycle event history of VMs.
+type VMEventRepository interface {
+	Record(ctx context.Context, event VMEvent) (int64, error)
+	// List returns the events matching query, oldest first.
+	List(ctx context.Context, query VMEventQuery) ([]VMEvent, error)
+	// DeleteBefore removes events recorded before cutoff.
+	DeleteBefore(ctx context.Context, cutoff time.Time) (int64, error)
+}
+
 // IPRepository manages deterministic IP allocation.
 type IPRepository interface {
 	EnsurePool(ctx context.Context, ips []string) error
-	LeaseNextAvailable(ctx context.Context) (*IPAllocation, error)
+	LeaseNextAvailable(ctx context.Context, family int) (*IPAllocation, error)
 	LeaseSpecific(ctx context.Context, ip string) (*IPAllocation, error)
 	Assign(ctx context.Context, ip string, vmID int64) error
 	Release(ctx context.Context, ip string) error
@@ -0,0 +1,272 @@
+// Copyright (c) 2025 HYPR. PTE. LTD.
+//
+// Business Source License 1.1
+// See LICENSE file in the project root for details.
+
+// Package demo runs a throwaway volantd inside the calling process for
+// `volar up --demo`. All state lives in one temporary directory that is
+// removed on Close.
+package demo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"time"
+
+	"github.com/volantvm/volant/internal/pluginspec"
+	"github.com/volantvm/volant/internal/server/app"
+	"github.com/volantvm/volant/internal/server/config"
+	"github.com/volantvm/volant/internal/server/db/sqlite"
+	"github.com/volantvm/volant/internal/server/eventbus/memory"
+	"github.com/volantvm/volant/internal/server/httpapi"
+	"github.com/volantvm/volant/internal/server/orchestrator"
+	"github.com/volantvm/volant/internal/server/orchestrator/cloudhypervisor"
+	"github.com/volantvm/volant/internal/server/orchestrator/network"
+	"github.com/volantvm/volant/internal/server/orchestrator/runtime"
+	"github.com/volantvm/volant/internal/server/plugins"
+	"github.com/volantvm/volant/internal/server/scheduler"
+	"github.com/volantvm/volant/internal/server/webhooks"
+)
+
+const (
+	subnetCIDR = "192.168.127.0/24"
+	hostIP     = "192.168.127.1"
+	// Real VMs join the bridge prepared by `volar setup`.
+	realBridge     = "vbr0"
+	realHypervisor = "cloud-hypervisor"
+	realBZImage    = "/var/lib/volant/kernel/bzImage"
+	realVMLinux    = "/var/lib/volant/kernel/vmlinux"
+	startTimeout   = 10 * time.Second
+	stopTimeout    = 20 * time.Second
+)
+
+// Options control how the demo daemon boots VMs.
+type Options struct {
+	// Real uses cloud-hypervisor when the host is prepared for it (root,
+	// hypervisor on PATH, kernel installed, bridge present). Otherwise, or
+	// when false, VMs are mocked.
+	Real bool
+}
+
+// Server is a running in-process daemon.
+type Server struct {
+	// BaseURL is the API address for the CLI client.
+	BaseURL string
+	// Dir holds the database, sockets, runtime files and logs.
+	Dir string
+	// Mock reports whether VMs are simulated rather than booted.
+	Mock bool
+
+	cancel context.CancelFunc
+	done   chan struct{}
+	runErr error
+	logs   *os.File
+}
+
+// Start boots the daemon and waits until its API answers.
+func Start(ctx context.Context, opts Options) (*Server, error) {
+	dir, err := os.MkdirTemp("", "volant-demo-")
+	if err != nil {
+		return nil, fmt.Errorf("demo: create state dir: %w", err)
+	}
+	srv, err := start(ctx, dir, opts)
+	if err != nil {
+		_ = os.RemoveAll(dir)
+		return nil, err
+	}
+	return srv, nil
+}
+
+func start(ctx context.Context, dir string, opts Options) (*Server, error) {
+	logs, err := os.Create(filepath.Join(dir, "volantd.log"))
+	if err != nil {
+		return nil, fmt.Errorf("demo: create log file: %w", err)
+	}
+	logger := slog.New(slog.NewTextHandler(logs, nil)).With("component", "volantd-demo")
+
+	socketPath := filepath.Join(dir, "api.sock")
+	runtimeDir := filepath.Join(dir, "run")
+	logDir := filepath.Join(dir, "logs")
+	cfg := config.ServerConfig{
+		DatabasePath:     filepath.Join(dir, "state.db"),
+		APIListenAddr:    "unix://" + socketPath,
+		APIAdvertiseAddr: net.JoinHostPort(hostIP, "7777"),
+		BridgeName:       realBridge,
+		SubnetCIDR:       subnetCIDR,
+		HostIP:           hostIP,
+		RuntimeDir:       runtimeDir,
+		LogDir:           logDir,
+		IPAMBackend:      "internal",
+	}
+	_, subnet, _ := net.ParseCIDR(subnetCIDR)
+
+	bootCtx, cancel := context.WithCancel(context.Background())
+	store, err := sqlite.Open(bootCtx, cfg.DatabasePath)
+	if err != nil {
+		cancel()
+		_ = logs.Close()
+		return nil, fmt.Errorf("demo: open database: %w", err)
+	}
+
+	params := orchestrator.Params{
+		Store:            store,
+		Logger:           logger,
+		Subnet:           subnet,
+		HostIP:           net.ParseIP(hostIP),
+		APIListenAddr:    cfg.APIListenAddr,
+		APIAdvertiseAddr: cfg.APIAdvertiseAddr,
+		RuntimeDir:       runtimeDir,
+	}
+	mock := !opts.Real || !realLauncherAvailable()
+	var launcher runtime.Launcher
+	if mock {
+		launcher = &mockLauncher{}
+		params.Network = network.NewNoop()
+		params.Readiness = alwaysReady{}
+	} else {
+		cfg.BZImagePath = realBZImage
+		cfg.VMLinuxPath = realVMLinux
+		launcher = cloudhypervisor.New(realHypervisor, realBZImage, realVMLinux, runtimeDir, logDir)
+		params.Network = network.NewBridgeManager(realBridge, "")
+	}
+	params.Launcher = launcher
+
+	events := memory.New()
+	params.Bus = events
+	engine, err := orchestrator.New(params)
+	if err != nil {
+		cancel()
+		_ = store.Close(context.Background())
+		_ = logs.Close()
+		return nil, fmt.Errorf("demo: init orchestrator: %w", err)
+	}
+	registry := plugins.NewRegistry(store.Queries().Plugins())
+	sched := scheduler.New(engine, events, logger)
+	hooks := webhooks.New(store, events, logger)
+	handler, err := httpapi.New(logger, engine, events, registry, nil, sched, nil, hooks, nil, nil, nil)
+	if err != nil {
+		cancel()
+		_ = store.Close(context.Background())
+		_ = logs.Close()
+		return nil, fmt.Errorf("demo: init http api: %w", err)
+	}
+	daemon, err := app.New(cfg, logger, store, engine, events, registry, sched, nil, hooks, handler)
+	if err != nil {
+		cancel()
+		_ = store.Close(context.Background())
+		_ = logs.Close()
+		return nil, fmt.Errorf("demo: init app: %w", err)
+	}
+
+	srv := &Server{
+		BaseURL: "unix://" + socketPath,
+		Dir:     dir,
+		Mock:    mock,
+		cancel:  cancel,
+		done:    make(chan struct{}),
+		logs:    logs,
+	}
+	go func() {
+		defer close(srv.done)
+		srv.runErr = daemon.Run(bootCtx)
+	}()
+
+	if err := waitForSocket(ctx, socketPath, srv); err != nil {
+		_ = srv.Close()
+		return nil, err
+	}
+	return srv, nil
+}
+
+// Close stops the daemon and deletes all of its state. VMs still running are
+// stopped with it.
+func (s *Server) Close() error {
+	s.cancel()
+	var runErr error
+	select {
+	case <-s.done:
+		if s.runErr != nil && !errors.Is(s.runErr, context.Canceled) {
+			runErr = s.runErr
+		}
+	case <-time.After(stopTimeout):
+		runErr = errors.New("demo: daemon did not stop in time")
+	}
+	_ = s.logs.Close()
+	if err := os.RemoveAll(s.Dir); err != nil {
+		return fmt.Errorf("demo: remove state dir: %w", err)
+	}
+	return runErr
+}
+
+// SampleManifest is the plugin installed by default. Its boot media is never
+// fetched by the mock launcher.
+func SampleManifest() pluginspec.Manifest {
+	return pluginspec.Manifest{
+		SchemaVersion: "1.0",
+		Name:          "demo",
+		Version:       "0.1.0",
+		Runtime:       "demo",
+		Enabled:       true,
+		Initramfs:     pluginspec.Initramfs{URL: "file:///var/lib/volant/demo/demo.cpio.gz"},
+		Resources:     pluginspec.ResourceSpec{CPUCores: 1, MemoryMB: 256},
+		Workload: pluginspec.Workload{
+			Type:       "http",
+			BaseURL:    "http://127.0.0.1:8080",
+			Entrypoint: []string{"/usr/bin/demo-server"},
+		},
+		HealthCheck: pluginspec.HealthCheck{Endpoint: "/", Timeout: 5000},
+		Labels:      map[string]string{"volant.demo": "true"},
+	}
+}
+
+// realLauncherAvailable reports whether the host can boot real microVMs.
+func realLauncherAvailable() bool {
+	if os.Geteuid() != 0 {
+		return false
+	}
+	if _, err := exec.LookPath(realHypervisor); err != nil {
+		return false
+	}
+	if !fileExists(realBZImage) && !fileExists(realVMLinux) {
+		return false
+	}
+	_, err := net.InterfaceByName(realBridge)
+	return err == nil
+}
+
+func fileExists(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && !info.IsDir()
+}
+
+// waitForSocket polls until the API socket accepts connections, the daemon
+// exits, or the start timeout elapses.
+func waitForSocket(ctx context.Context, path string, srv *Server) error {
+	ctx, cancel := context.WithTimeout(ctx, startTimeout)
+	defer cancel()
+	ticker := time.NewTicker(50 * time.Millisecond)
+	defer ticker.Stop()
+	for {
+		if conn, err := net.Dial("unix", path); err == nil {
+			_ = conn.Close()
+			return nil
+		}
+		select {
+		case <-srv.done:
+			err := srv.runErr
+			if err == nil {
+				err = errors.New("exited")
+			}
+			return fmt.Errorf("demo: daemon failed to start: %w", err)
+		case <-ctx.Done():
+			return fmt.Errorf("demo: daemon did not start: %w", ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
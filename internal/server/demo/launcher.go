@@ -0,0 +1,54 @@
+// Copyright (c) 2025 HYPR. PTE. LTD.
+//
+// Business Source License 1.1
+// See LICENSE file in the project root for details.
+
+package demo
+
+import (
+	"context"
+	"os"
+	"sync"
+
+	"github.com/volantvm/volant/internal/server/db"
+	"github.com/volantvm/volant/internal/server/orchestrator/runtime"
+)
+
+// mockLauncher "boots" VMs without a hypervisor. Instances stay running until
+// stopped so the full API lifecycle can be exercised on any host.
+type mockLauncher struct{}
+
+func (l *mockLauncher) Launch(ctx context.Context, spec runtime.LaunchSpec) (runtime.Instance, error) {
+	return &mockInstance{
+		name: spec.Name,
+		// The mock runs inside the demo process, so report its pid.
+		pid:  os.Getpid(),
+		done: make(chan error, 1),
+	}, nil
+}
+
+type mockInstance struct {
+	name string
+	pid  int
+	done chan error
+	once sync.Once
+}
+
+func (i *mockInstance) Name() string          { return i.name }
+func (i *mockInstance) PID() int              { return i.pid }
+func (i *mockInstance) APISocketPath() string { return "" }
+func (i *mockInstance) Wait() <-chan error    { return i.done }
+
+func (i *mockInstance) Stop(ctx context.Context) error {
+	i.once.Do(func() {
+		i.done <- nil
+		close(i.done)
+	})
+	return nil
+}
+
+// alwaysReady treats mock VMs as ready as soon as they are running; there is
+// no guest agent to probe.
+type alwaysReady struct{}
+
+func (alwaysReady) Ready(context.Context, db.VM) bool { return true }
@@ -0,0 +1,19 @@
+// Copyright (c) 2025 HYPR PTE. LTD.
+//
+// Business Source License 1.1
+// See LICENSE file in the project root for details.
+
+//go:build linux
+// +build linux
+
+package devicemanager
+
+import "log/slog"
+
+// NewVFIOManager creates a new VFIO manager with real filesystem operations
+func NewVFIOManager(logger *slog.Logger) VFIOManager {
+	return &vfioManager{
+		logger:     logger.With("component", "vfio-manager"),
+		fileSystem: &realFileSystem{},
+	}
+}
@@ -6,12 +6,14 @@
 package devicemanager
 
 import (
+	"errors"
 	"fmt"
 	"log/slog"
 	"os"
 	"path/filepath"
 	"regexp"
 	"strings"
+	"sync"
 )
 
 const (
@@ -27,6 +29,9 @@ const (
 var (
 	// Regular expression to validate PCI address format: 0000:01:00.0
 	pciAddressRegex = regexp.MustCompile(`^[0-9a-fA-F]{4}:[0-9a-fA-F]{2}:[0-9a-fA-F]{2}\.[0-7]$`)
+
+	// ErrVFIOUnsupported is returned for device operations on hosts without VFIO
+	ErrVFIOUnsupported = errors.New("devicemanager: vfio passthrough requires linux")
 )
 
 // VFIOManager manages VFIO device passthrough operations
@@ -64,12 +69,20 @@ type PCIDevice struct {
 	Driver     string
 	IOMMUGroup string
 	NumaNode   string
+	// Class is the PCI class code without the 0x prefix, e.g. "030000" for a
+	// VGA controller.
+	Class string
 }
 
 // vfioManager implements VFIOManager interface
 type vfioManager struct {
 	logger     *slog.Logger
 	fileSystem FileSystem
+
+	// hostDrivers remembers the driver each device used before BindDevices
+	// moved it to vfio-pci, so UnbindDevices can hand it back.
+	mu          sync.Mutex
+	hostDrivers map[string]string
 }
 
 // FileSystem interface for testability (allows mocking filesystem operations)
@@ -105,14 +118,6 @@ func (fs *realFileSystem) Readlink(path string) (string, error) {
 	return os.Readlink(path)
 }
 
-// NewVFIOManager creates a new VFIO manager with real filesystem operations
-func NewVFIOManager(logger *slog.Logger) VFIOManager {
-	return &vfioManager{
-		logger:     logger.With("component", "vfio-manager"),
-		fileSystem: &realFileSystem{},
-	}
-}
-
 // newVFIOManagerWithFS creates a VFIO manager with custom filesystem (for testing)
 func newVFIOManagerWithFS(logger *slog.Logger, fs FileSystem) VFIOManager {
 	return &vfioManager{
@@ -131,7 +136,7 @@ func (m *vfioManager) ValidateDevices(pciAddrs []string, allowlist []string) err
 
 	for _, addr := range pciAddrs {
 		// Validate PCI address format
-		if !isValidPCIAddress(addr) {
+		if !IsValidPCIAddress(addr) {
 			return fmt.Errorf("invalid PCI address format: %s (expected format: 0000:01:00.0)", addr)
 		}
 
@@ -217,6 +222,12 @@ func (m *vfioManager) BindDevices(pciAddrs []string) error {
 				return fmt.Errorf("failed to unbind %s from %s: %w", addr, currentDriver, err)
 			}
 			m.logger.Debug("unbound from current driver", "address", addr, "driver", currentDriver)
+			m.mu.Lock()
+			if m.hostDrivers == nil {
+				m.hostDrivers = make(map[string]string)
+			}
+			m.hostDrivers[addr] = currentDriver
+			m.mu.Unlock()
 		}
 
 		// Get device vendor:device ID for driver override
@@ -252,7 +263,8 @@ func (m *vfioManager) BindDevices(pciAddrs []string) error {
 	return nil
 }
 
-// UnbindDevices unbinds devices from vfio-pci driver
+// UnbindDevices unbinds devices from vfio-pci driver and hands them back to
+// the driver they used before BindDevices, or lets the kernel probe for one.
 func (m *vfioManager) UnbindDevices(pciAddrs []string) error {
 	m.logger.Info("unbinding devices from vfio-pci", "count", len(pciAddrs))
 
@@ -267,9 +279,10 @@ func (m *vfioManager) UnbindDevices(pciAddrs []string) error {
 			if err := m.unbindDriver(addr, vfioPCIDriver); err != nil {
 				m.logger.Warn("failed to unbind device", "address", addr, "error", err)
 				// Continue with other devices
-			} else {
-				m.logger.Debug("unbound device from vfio-pci", "address", addr)
+				continue
 			}
+			m.logger.Debug("unbound device from vfio-pci", "address", addr)
+			m.rebindHostDriver(addr)
 		}
 	}
 
@@ -313,7 +326,7 @@ func (m *vfioManager) GetVFIOGroupPaths(pciAddrs []string) ([]string, error) {
 
 // GetDeviceInfo returns detailed information about a PCI device
 func (m *vfioManager) GetDeviceInfo(pciAddr string) (*PCIDevice, error) {
-	if !isValidPCIAddress(pciAddr) {
+	if !IsValidPCIAddress(pciAddr) {
 		return nil, fmt.Errorf("invalid PCI address: %s", pciAddr)
 	}
 
@@ -338,13 +351,14 @@ func (m *vfioManager) GetDeviceInfo(pciAddr string) (*PCIDevice, error) {
 		Driver:     driver,
 		IOMMUGroup: iommuGroup,
 		NumaNode:   numaNode,
+		Class:      m.getClass(pciAddr),
 	}, nil
 }
 
 // Helper functions
 
-// isValidPCIAddress validates PCI address format (e.g., 0000:01:00.0)
-func isValidPCIAddress(addr string) bool {
+// IsValidPCIAddress reports whether addr is a full PCI address (e.g., 0000:01:00.0)
+func IsValidPCIAddress(addr string) bool {
 	return pciAddressRegex.MatchString(addr)
 }
 
@@ -443,6 +457,45 @@ func (m *vfioManager) getNumaNode(pciAddr string) string {
 	return strings.TrimSpace(string(data))
 }
 
+// getClass returns the PCI class code for a device
+func (m *vfioManager) getClass(pciAddr string) string {
+	data, err := m.fileSystem.ReadFile(filepath.Join(pciDevicesPath, pciAddr, "class"))
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(string(data)), "0x"))
+}
+
+// rebindHostDriver returns a device released from vfio-pci to its host
+// driver. The device ID is dropped from vfio-pci first so the kernel does
+// not hand the device straight back to it.
+func (m *vfioManager) rebindHostDriver(pciAddr string) {
+	if vendor, device, err := m.getDeviceID(pciAddr); err == nil {
+		removeIDPath := "/sys/bus/pci/drivers/vfio-pci/remove_id"
+		if err := m.fileSystem.WriteFile(removeIDPath, []byte(fmt.Sprintf("%s %s", vendor, device))); err != nil {
+			m.logger.Debug("failed to remove device ID from vfio-pci", "address", pciAddr, "error", err)
+		}
+	}
+
+	m.mu.Lock()
+	driver := m.hostDrivers[pciAddr]
+	delete(m.hostDrivers, pciAddr)
+	m.mu.Unlock()
+
+	if driver != "" {
+		bindPath := filepath.Join("/sys/bus/pci/drivers", driver, "bind")
+		err := m.fileSystem.WriteFile(bindPath, []byte(pciAddr))
+		if err == nil {
+			m.logger.Info("rebound device to host driver", "address", pciAddr, "driver", driver)
+			return
+		}
+		m.logger.Warn("failed to rebind device to host driver", "address", pciAddr, "driver", driver, "error", err)
+	}
+	if err := m.fileSystem.WriteFile("/sys/bus/pci/drivers_probe", []byte(pciAddr)); err != nil {
+		m.logger.Warn("failed to probe host driver for device", "address", pciAddr, "error", err)
+	}
+}
+
 // unbindDriver unbinds a device from its current driver
 func (m *vfioManager) unbindDriver(pciAddr, driver string) error {
 	unbindPath := filepath.Join("/sys/bus/pci/drivers", driver, "unbind")
@@ -0,0 +1,52 @@
+// Copyright (c) 2025 HYPR PTE. LTD.
+//
+// Business Source License 1.1
+// See LICENSE file in the project root for details.
+
+//go:build !linux
+
+package devicemanager
+
+import "log/slog"
+
+// NewVFIOManager returns a manager that rejects passthrough on non-Linux
+// hosts, which have no sysfs PCI tree or vfio-pci driver.
+func NewVFIOManager(logger *slog.Logger) VFIOManager {
+	_ = logger
+	return unsupportedVFIOManager{}
+}
+
+// unsupportedVFIOManager accepts empty device lists so VMs without
+// passthrough are unaffected.
+type unsupportedVFIOManager struct{}
+
+func (unsupportedVFIOManager) ValidateDevices(pciAddrs []string, _ []string) error {
+	return unsupportedUnlessEmpty(pciAddrs)
+}
+
+func (unsupportedVFIOManager) CheckIOMMUGroups(pciAddrs []string) ([]IOMMUGroup, error) {
+	return nil, unsupportedUnlessEmpty(pciAddrs)
+}
+
+func (unsupportedVFIOManager) BindDevices(pciAddrs []string) error {
+	return unsupportedUnlessEmpty(pciAddrs)
+}
+
+func (unsupportedVFIOManager) UnbindDevices(pciAddrs []string) error {
+	return unsupportedUnlessEmpty(pciAddrs)
+}
+
+func (unsupportedVFIOManager) GetVFIOGroupPaths(pciAddrs []string) ([]string, error) {
+	return nil, unsupportedUnlessEmpty(pciAddrs)
+}
+
+func (unsupportedVFIOManager) GetDeviceInfo(string) (*PCIDevice, error) {
+	return nil, ErrVFIOUnsupported
+}
+
+func unsupportedUnlessEmpty(pciAddrs []string) error {
+	if len(pciAddrs) == 0 {
+		return nil
+	}
+	return ErrVFIOUnsupported
+}
@@ -0,0 +1,18 @@
+// Copyright (c) 2025 HYPR. PTE. LTD.
+//
+// Business Source License 1.1
+// See LICENSE file in the project root for details.
+//go:build !linux
+// +build !linux
+
+package dhcp
+
+import (
+	"context"
+	"errors"
+	"net"
+)
+
+func listen(ctx context.Context, iface string) (net.PacketConn, error) {
+	return nil, errors.New("dhcp: the embedded server requires linux")
+}
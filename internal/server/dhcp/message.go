@@ -0,0 +1,165 @@
+// Copyright (c) 2025 HYPR. PTE. LTD.
+//
+// Business Source License 1.1
+// See LICENSE file in the project root for details.
+
+package dhcp
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net"
+	"time"
+)
+
+// DHCP message types (RFC 2132, option 53).
+const (
+	MessageDiscover byte = 1
+	MessageOffer    byte = 2
+	MessageRequest  byte = 3
+	MessageDecline  byte = 4
+	MessageAck      byte = 5
+	MessageNak      byte = 6
+	MessageRelease  byte = 7
+	MessageInform   byte = 8
+)
+
+// Option codes used by the server.
+const (
+	optSubnetMask    byte = 1
+	optRouter        byte = 3
+	optDNS           byte = 6
+	optHostname      byte = 12
+	optRequestedIP   byte = 50
+	optLeaseTime     byte = 51
+	optMessageType   byte = 53
+	optServerID      byte = 54
+	optRenewalTime   byte = 58
+	optRebindingTime byte = 59
+	optPad           byte = 0
+	optEnd           byte = 255
+)
+
+const (
+	opRequest      byte = 1
+	opReply        byte = 2
+	headerLen           = 236
+	flagBroadcast       = 0x8000
+	htypeEthernet  byte = 1
+	minMessageSize      = headerLen + 4
+)
+
+var magicCookie = []byte{99, 130, 83, 99}
+
+// Message is a decoded BOOTP/DHCP packet. Only the fields the server uses are
+// kept; options are stored raw by code.
+type Message struct {
+	Op      byte
+	XID     uint32
+	Flags   uint16
+	CIAddr  net.IP
+	YIAddr  net.IP
+	SIAddr  net.IP
+	GIAddr  net.IP
+	CHAddr  net.HardwareAddr
+	Options map[byte][]byte
+}
+
+// Type returns the DHCP message type, or 0 for plain BOOTP.
+func (m *Message) Type() byte {
+	if v := m.Options[optMessageType]; len(v) == 1 {
+		return v[0]
+	}
+	return 0
+}
+
+func (m *Message) ipOption(code byte) net.IP {
+	if v := m.Options[code]; len(v) == net.IPv4len {
+		return net.IP(v)
+	}
+	return nil
+}
+
+// Parse decodes a DHCP packet.
+func Parse(data []byte) (*Message, error) {
+	if len(data) < minMessageSize {
+		return nil, errors.New("dhcp: packet too short")
+	}
+	if string(data[headerLen:headerLen+4]) != string(magicCookie) {
+		return nil, errors.New("dhcp: missing magic cookie")
+	}
+	hlen := int(data[2])
+	if data[1] != htypeEthernet || hlen != 6 {
+		return nil, fmt.Errorf("dhcp: unsupported hardware type %d/%d", data[1], hlen)
+	}
+	m := &Message{
+		Op:      data[0],
+		XID:     binary.BigEndian.Uint32(data[4:8]),
+		Flags:   binary.BigEndian.Uint16(data[10:12]),
+		CIAddr:  net.IP(append([]byte(nil), data[12:16]...)),
+		YIAddr:  net.IP(append([]byte(nil), data[16:20]...)),
+		SIAddr:  net.IP(append([]byte(nil), data[20:24]...)),
+		GIAddr:  net.IP(append([]byte(nil), data[24:28]...)),
+		CHAddr:  net.HardwareAddr(append([]byte(nil), data[28:28+hlen]...)),
+		Options: make(map[byte][]byte),
+	}
+	opts := data[minMessageSize:]
+	for i := 0; i < len(opts); {
+		code := opts[i]
+		if code == optEnd {
+			break
+		}
+		if code == optPad {
+			i++
+			continue
+		}
+		if i+1 >= len(opts) {
+			return nil, errors.New("dhcp: truncated option")
+		}
+		length := int(opts[i+1])
+		if i+2+length > len(opts) {
+			return nil, errors.New("dhcp: truncated option")
+		}
+		m.Options[code] = append(m.Options[code], opts[i+2:i+2+length]...)
+		i += 2 + length
+	}
+	return m, nil
+}
+
+// Marshal encodes the message. Options are written in ascending code order
+// so the output is deterministic.
+func (m *Message) Marshal() []byte {
+	out := make([]byte, minMessageSize, 300)
+	out[0] = m.Op
+	out[1] = htypeEthernet
+	out[2] = 6
+	binary.BigEndian.PutUint32(out[4:8], m.XID)
+	binary.BigEndian.PutUint16(out[10:12], m.Flags)
+	copy(out[12:16], m.CIAddr.To4())
+	copy(out[16:20], m.YIAddr.To4())
+	copy(out[20:24], m.SIAddr.To4())
+	copy(out[24:28], m.GIAddr.To4())
+	copy(out[28:44], m.CHAddr)
+	copy(out[headerLen:], magicCookie)
+	for code := 1; code < int(optEnd); code++ {
+		value, ok := m.Options[byte(code)]
+		if !ok {
+			continue
+		}
+		for len(value) > 255 {
+			out = append(out, byte(code), 255)
+			out = append(out, value[:255]...)
+			value = value[255:]
+		}
+		out = append(out, byte(code), byte(len(value)))
+		out = append(out, value...)
+	}
+	return append(out, optEnd)
+}
+
+func seconds(d time.Duration) []byte {
+	b := make([]byte, 4)
+	binary.BigEndian.PutUint32(b, uint32(d/time.Second))
+	return b
+}
@@ -0,0 +1,243 @@
+// Copyright (c) 2025 HYPR. PTE. LTD.
+//
+// Business Source License 1.1
+// See LICENSE file in the project root for details.
+
+// Package dhcp implements the small DHCPv4 server volantd runs on its bridge
+// for VMs in dhcp network mode. It only answers for MACs that belong to known
+// VMs and hands each the address the orchestrator reserved for it.
+package dhcp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/volantvm/volant/internal/server/db"
+)
+
+// DefaultLeaseTime is used when Config.LeaseTime is zero.
+const DefaultLeaseTime = time.Hour
+
+const (
+	serverPort = 67
+	clientPort = 68
+)
+
+// Config describes the network the server hands out addresses on.
+type Config struct {
+	Interface string
+	ServerIP  net.IP
+	Subnet    *net.IPNet
+	// Router defaults to ServerIP.
+	Router    net.IP
+	DNS       []net.IP
+	LeaseTime time.Duration
+}
+
+// Binding is the address reserved for a client.
+type Binding struct {
+	IP       net.IP
+	Hostname string
+	VMID     int64
+}
+
+// Leaser resolves clients to their reserved address and records leases.
+type Leaser interface {
+	Lookup(ctx context.Context, mac net.HardwareAddr) (*Binding, error)
+	Commit(ctx context.Context, mac net.HardwareAddr, binding Binding, expires time.Time) error
+	Release(ctx context.Context, mac net.HardwareAddr) error
+}
+
+// Server answers DHCP requests on a single interface.
+type Server struct {
+	cfg    Config
+	leaser Leaser
+	logger *slog.Logger
+	now    func() time.Time
+}
+
+// New validates cfg and constructs a server.
+func New(cfg Config, leaser Leaser, logger *slog.Logger) (*Server, error) {
+	if cfg.Subnet == nil || cfg.Subnet.IP.To4() == nil {
+		return nil, errors.New("dhcp: an IPv4 subnet is required")
+	}
+	if cfg.ServerIP.To4() == nil || !cfg.Subnet.Contains(cfg.ServerIP) {
+		return nil, fmt.Errorf("dhcp: server ip %s is not in %s", cfg.ServerIP, cfg.Subnet)
+	}
+	if strings.TrimSpace(cfg.Interface) == "" {
+		return nil, errors.New("dhcp: interface is required")
+	}
+	if leaser == nil {
+		return nil, errors.New("dhcp: leaser is required")
+	}
+	if cfg.Router == nil {
+		cfg.Router = cfg.ServerIP
+	}
+	if cfg.LeaseTime <= 0 {
+		cfg.LeaseTime = DefaultLeaseTime
+	}
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &Server{cfg: cfg, leaser: leaser, logger: logger.With("component", "dhcp"), now: time.Now}, nil
+}
+
+// Serve listens on the configured interface until ctx is cancelled.
+func (s *Server) Serve(ctx context.Context) error {
+	conn, err := listen(ctx, s.cfg.Interface)
+	if err != nil {
+		return err
+	}
+	go func() {
+		<-ctx.Done()
+		_ = conn.Close()
+	}()
+	s.logger.Info("dhcp server listening", "interface", s.cfg.Interface, "subnet", s.cfg.Subnet)
+
+	buf := make([]byte, 1500)
+	for {
+		n, _, err := conn.ReadFrom(buf)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return fmt.Errorf("dhcp: read: %w", err)
+		}
+		req, err := Parse(buf[:n])
+		if err != nil || req.Op != opRequest {
+			continue
+		}
+		reply, err := s.Handle(ctx, req)
+		if err != nil {
+			s.logger.Warn("handle dhcp request", "mac", req.CHAddr, "error", err)
+			continue
+		}
+		if reply == nil {
+			continue
+		}
+		dst := &net.UDPAddr{IP: net.IPv4bcast, Port: clientPort}
+		if !req.CIAddr.IsUnspecified() && req.Flags&flagBroadcast == 0 {
+			dst.IP = req.CIAddr
+		}
+		if _, err := conn.WriteTo(reply.Marshal(), dst); err != nil {
+			s.logger.Warn("send dhcp reply", "mac", req.CHAddr, "error", err)
+		}
+	}
+}
+
+// Handle computes the reply to req, or nil when the server stays silent.
+func (s *Server) Handle(ctx context.Context, req *Message) (*Message, error) {
+	switch req.Type() {
+	case MessageDiscover:
+		binding, err := s.leaser.Lookup(ctx, req.CHAddr)
+		if err != nil || binding == nil {
+			return nil, err
+		}
+		return s.reply(req, MessageOffer, binding), nil
+
+	case MessageRequest:
+		if serverID := req.ipOption(optServerID); serverID != nil && !serverID.Equal(s.cfg.ServerIP) {
+			// The client picked another server's offer.
+			return nil, nil
+		}
+		binding, err := s.leaser.Lookup(ctx, req.CHAddr)
+		if err != nil || binding == nil {
+			return nil, err
+		}
+		requested := req.ipOption(optRequestedIP)
+		if requested == nil {
+			requested = req.CIAddr
+		}
+		if !requested.Equal(binding.IP) {
+			return s.reply(req, MessageNak, nil), nil
+		}
+		if err := s.leaser.Commit(ctx, req.CHAddr, *binding, s.now().Add(s.cfg.LeaseTime)); err != nil {
+			return nil, err
+		}
+		return s.reply(req, MessageAck, binding), nil
+
+	case MessageRelease:
+		return nil, s.leaser.Release(ctx, req.CHAddr)
+
+	case MessageDecline:
+		s.logger.Warn("client declined address", "mac", req.CHAddr, "ip", req.ipOption(optRequestedIP))
+		return nil, nil
+	}
+	return nil, nil
+}
+
+func (s *Server) reply(req *Message, msgType byte, binding *Binding) *Message {
+	reply := &Message{
+		Op:     opReply,
+		XID:    req.XID,
+		Flags:  req.Flags,
+		CIAddr: net.IPv4zero,
+		YIAddr: net.IPv4zero,
+		SIAddr: net.IPv4zero,
+		GIAddr: req.GIAddr,
+		CHAddr: req.CHAddr,
+		Options: map[byte][]byte{
+			optMessageType: {msgType},
+			optServerID:    s.cfg.ServerIP.To4(),
+		},
+	}
+	if binding == nil {
+		return reply
+	}
+	reply.YIAddr = binding.IP.To4()
+	reply.SIAddr = s.cfg.ServerIP.To4()
+	reply.Options[optSubnetMask] = []byte(s.cfg.Subnet.Mask)
+	reply.Options[optRouter] = s.cfg.Router.To4()
+	reply.Options[optLeaseTime] = seconds(s.cfg.LeaseTime)
+	reply.Options[optRenewalTime] = seconds(s.cfg.LeaseTime / 2)
+	reply.Options[optRebindingTime] = seconds(s.cfg.LeaseTime * 7 / 8)
+	if len(s.cfg.DNS) > 0 {
+		var dns []byte
+		for _, ip := range s.cfg.DNS {
+			dns = append(dns, ip.To4()...)
+		}
+		reply.Options[optDNS] = dns
+	}
+	if binding.Hostname != "" {
+		reply.Options[optHostname] = []byte(binding.Hostname)
+	}
+	return reply
+}
+
+// StoreLeaser serves the addresses recorded on VM rows and tracks leases in
+// the dhcp_leases table.
+type StoreLeaser struct {
+	Store  db.Store
+	Subnet *net.IPNet
+}
+
+func (l *StoreLeaser) Lookup(ctx context.Context, mac net.HardwareAddr) (*Binding, error) {
+	vm, err := l.Store.Queries().VirtualMachines().GetByMAC(ctx, mac.String())
+	if err != nil || vm == nil {
+		return nil, err
+	}
+	ip := net.ParseIP(vm.IPAddress).To4()
+	if ip == nil || !l.Subnet.Contains(ip) {
+		return nil, nil
+	}
+	return &Binding{IP: ip, Hostname: vm.Name, VMID: vm.ID}, nil
+}
+
+func (l *StoreLeaser) Commit(ctx context.Context, mac net.HardwareAddr, binding Binding, expires time.Time) error {
+	return l.Store.Queries().DHCPLeases().Upsert(ctx, db.DHCPLease{
+		MACAddress: mac.String(),
+		IPAddress:  binding.IP.String(),
+		VMID:       binding.VMID,
+		Hostname:   binding.Hostname,
+		ExpiresAt:  expires,
+	})
+}
+
+func (l *StoreLeaser) Release(ctx context.Context, mac net.HardwareAddr) error {
+	return l.Store.Queries().DHCPLeases().Delete(ctx, mac.String())
+}
@@ -0,0 +1,98 @@
+// Copyright (c) 2025 HYPR. PTE. LTD.
+//
+// Business Source License 1.1
+// See LICENSE file in the project root for details.
+
+package dhcp
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+)
+
+type fakeLeaser struct {
+	bindings  map[string]Binding
+	committed map[string]time.Time
+}
+
+func (f *fakeLeaser) Lookup(ctx context.Context, mac net.HardwareAddr) (*Binding, error) {
+	if b, ok := f.bindings[mac.String()]; ok {
+		return &b, nil
+	}
+	return nil, nil
+}
+
+func (f *fakeLeaser) Commit(ctx context.Context, mac net.HardwareAddr, binding Binding, expires time.Time) error {
+	f.committed[mac.String()] = expires
+	return nil
+}
+
+func (f *fakeLeaser) Release(ctx context.Context, mac net.HardwareAddr) error {
+	delete(f.committed, mac.String())
+	return nil
+}
+
+func TestServerLeasesReservedAddress(t *testing.T) {
+	_, subnet, _ := net.ParseCIDR("192.168.127.0/24")
+	known, _ := net.ParseMAC("02:00:00:00:00:01")
+	unknown, _ := net.ParseMAC("02:00:00:00:00:02")
+	leaser := &fakeLeaser{
+		bindings:  map[string]Binding{known.String(): {IP: net.ParseIP("192.168.127.10").To4(), Hostname: "web", VMID: 1}},
+		committed: map[string]time.Time{},
+	}
+	server, err := New(Config{Interface: "vbr0", ServerIP: net.ParseIP("192.168.127.1"), Subnet: subnet}, leaser, nil)
+	if err != nil {
+		t.Fatalf("new server: %v", err)
+	}
+	ctx := context.Background()
+
+	request := func(mac net.HardwareAddr, msgType byte, requested string) *Message {
+		msg := &Message{Op: opRequest, XID: 42, CHAddr: mac, Options: map[byte][]byte{optMessageType: {msgType}}}
+		if requested != "" {
+			msg.Options[optRequestedIP] = net.ParseIP(requested).To4()
+		}
+		// Every message goes over the wire format to exercise the codec.
+		parsed, err := Parse(msg.Marshal())
+		if err != nil {
+			t.Fatalf("parse: %v", err)
+		}
+		return parsed
+	}
+
+	offer, err := server.Handle(ctx, request(known, MessageDiscover, ""))
+	if err != nil || offer == nil {
+		t.Fatalf("discover: reply=%v err=%v", offer, err)
+	}
+	if offer.Type() != MessageOffer || !offer.YIAddr.Equal(net.ParseIP("192.168.127.10")) || offer.XID != 42 {
+		t.Fatalf("unexpected offer: %+v", offer)
+	}
+	if got := net.IP(offer.Options[optRouter]); !got.Equal(net.ParseIP("192.168.127.1")) {
+		t.Fatalf("router = %s", got)
+	}
+
+	if reply, _ := server.Handle(ctx, request(unknown, MessageDiscover, "")); reply != nil {
+		t.Fatalf("expected no reply for unknown mac, got %+v", reply)
+	}
+
+	nak, _ := server.Handle(ctx, request(known, MessageRequest, "192.168.127.99"))
+	if nak == nil || nak.Type() != MessageNak {
+		t.Fatalf("expected nak for wrong address, got %+v", nak)
+	}
+
+	ack, err := server.Handle(ctx, request(known, MessageRequest, "192.168.127.10"))
+	if err != nil || ack == nil || ack.Type() != MessageAck {
+		t.Fatalf("expected ack, got %+v (%v)", ack, err)
+	}
+	if _, ok := leaser.committed[known.String()]; !ok {
+		t.Fatalf("lease not committed")
+	}
+
+	if _, err := server.Handle(ctx, request(known, MessageRelease, "")); err != nil {
+		t.Fatalf("release: %v", err)
+	}
+	if _, ok := leaser.committed[known.String()]; ok {
+		t.Fatalf("lease not released")
+	}
+}
@@ -0,0 +1,504 @@
+// Copyright (c) 2025 HYPR. PTE. LTD.
+//
+// Business Source License 1.1
+// See LICENSE file in the project root for details.
+
+//go:build grpc
+// +build grpc
+
+// Package grpcapi serves the orchestrator engine over gRPC. It is compiled
+// only with the grpc build tag. The volantv1 stubs are generated from
+// volantv1/volant.proto by `make proto` and committed.
+package grpcapi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/volantvm/volant/internal/server/admission"
+	"github.com/volantvm/volant/internal/server/apiauth"
+	"github.com/volantvm/volant/internal/server/db"
+	"github.com/volantvm/volant/internal/server/eventbus"
+	"github.com/volantvm/volant/internal/server/grpcapi/volantv1"
+	"github.com/volantvm/volant/internal/server/orchestrator"
+	orchestratorevents "github.com/volantvm/volant/internal/server/orchestrator/events"
+	"github.com/volantvm/volant/internal/server/orchestrator/vmconfig"
+	"github.com/volantvm/volant/internal/server/plugins"
+)
+
+// New builds a gRPC server exposing engine. Calls are subject to the same
+// client CIDR allowlist and VOLANT_API_KEY as the REST API (apiauth.Current
+// and CurrentNetworks, so reloads apply), with the key sent as
+// x-volant-api-key metadata, and pass through the same admission webhooks.
+func New(logger *slog.Logger, engine orchestrator.Engine, bus eventbus.Bus, registry *plugins.Registry, admit *admission.Controller) *grpc.Server {
+	logger = logger.With("component", "grpcapi")
+	server := grpc.NewServer(
+		grpc.UnaryInterceptor(func(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+			if err := authorize(ctx, logger, apiauth.CurrentNetworks(), apiauth.Current()); err != nil {
+				return nil, err
+			}
+			return handler(ctx, req)
+		}),
+		grpc.StreamInterceptor(func(srv any, ss grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+			if err := authorize(ss.Context(), logger, apiauth.CurrentNetworks(), apiauth.Current()); err != nil {
+				return err
+			}
+			return handler(srv, ss)
+		}),
+	)
+	volantv1.RegisterVolantServer(server, &service{
+		logger:  logger,
+		engine:  engine,
+		bus:     bus,
+		plugins: registry,
+		admit:   admit,
+	})
+	return server
+}
+
+func authorize(ctx context.Context, logger *slog.Logger, networks apiauth.Networks, checker *apiauth.Checker) error {
+	if len(networks) > 0 {
+		var ip net.IP
+		if p, ok := peer.FromContext(ctx); ok {
+			if addr, ok := p.Addr.(*net.TCPAddr); ok {
+				ip = addr.IP
+			}
+		}
+		if !networks.Allows(ip) {
+			logger.Warn("call blocked by CIDR filter", "ip", ip.String())
+			return status.Error(codes.PermissionDenied, apiauth.ErrClientDenied.Error())
+		}
+	}
+	if checker == nil {
+		return nil
+	}
+	var provided string
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get(apiauth.MetadataKey); len(values) > 0 {
+			provided = values[0]
+		}
+	}
+	if err := checker.Check(provided); err != nil {
+		return status.Error(codes.Unauthenticated, err.Error())
+	}
+	return nil
+}
+
+type service struct {
+	volantv1.UnimplementedVolantServer
+
+	logger  *slog.Logger
+	engine  orchestrator.Engine
+	bus     eventbus.Bus
+	plugins *plugins.Registry
+	admit   *admission.Controller
+}
+
+// createVMObject mirrors the REST create body so webhooks see one shape.
+type createVMObject struct {
+	Name          string           `json:"name"`
+	Plugin        string           `json:"plugin"`
+	Runtime       string           `json:"runtime"`
+	CPUCores      int              `json:"cpu_cores"`
+	MemoryMB      int              `json:"memory_mb"`
+	KernelCmdline string           `json:"kernel_cmdline"`
+	Config        *vmconfig.Config `json:"config,omitempty"`
+}
+
+// deploymentObject mirrors the REST deployment create and scale bodies.
+type deploymentObject struct {
+	Name     string           `json:"name,omitempty"`
+	Replicas int              `json:"replicas"`
+	MaxSurge int              `json:"max_surge,omitempty"`
+	Config   *vmconfig.Config `json:"config,omitempty"`
+}
+
+func (s *service) ListVMs(ctx context.Context, _ *volantv1.ListVMsRequest) (*volantv1.ListVMsResponse, error) {
+	vms, err := s.engine.ListVMs(ctx)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	resp := &volantv1.ListVMsResponse{Vms: make([]*volantv1.VM, 0, len(vms))}
+	for i := range vms {
+		resp.Vms = append(resp.Vms, vmToProto(&vms[i]))
+	}
+	return resp, nil
+}
+
+func (s *service) GetVM(ctx context.Context, req *volantv1.GetVMRequest) (*volantv1.VM, error) {
+	vm, err := s.engine.GetVM(ctx, req.GetName())
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	if vm == nil {
+		return nil, status.Errorf(codes.NotFound, "vm %s not found", req.GetName())
+	}
+	return vmToProto(vm), nil
+}
+
+func (s *service) CreateVM(ctx context.Context, req *volantv1.CreateVMRequest) (*volantv1.VM, error) {
+	var cfg *vmconfig.Config
+	if len(req.GetConfigJson()) > 0 {
+		cfg = &vmconfig.Config{}
+		if err := json.Unmarshal(req.GetConfigJson(), cfg); err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "decode config: %v", err)
+		}
+	}
+	obj := createVMObject{
+		Name:          req.GetName(),
+		Plugin:        req.GetPlugin(),
+		Runtime:       req.GetRuntime(),
+		CPUCores:      int(req.GetCpuCores()),
+		MemoryMB:      int(req.GetMemoryMb()),
+		KernelCmdline: req.GetKernelCmdline(),
+		Config:        cfg,
+	}
+	if err := s.admit.Admit(ctx, admission.OpVMCreate, obj.Name, &obj); err != nil {
+		return nil, toStatus(err)
+	}
+	cfg = obj.Config
+	pluginName := strings.TrimSpace(obj.Plugin)
+	if cfg != nil && strings.TrimSpace(cfg.Plugin) != "" {
+		if pluginName != "" && !strings.EqualFold(pluginName, strings.TrimSpace(cfg.Plugin)) {
+			return nil, status.Error(codes.InvalidArgument, "plugin mismatch between request and config")
+		}
+		pluginName = strings.TrimSpace(cfg.Plugin)
+	}
+	if pluginName == "" {
+		return nil, status.Error(codes.InvalidArgument, "plugin is required")
+	}
+	manifest, ok := s.plugins.Get(pluginName)
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "plugin %s not found", pluginName)
+	}
+	if !manifest.Enabled {
+		return nil, status.Errorf(codes.FailedPrecondition, "plugin %s disabled", pluginName)
+	}
+	manifest.Normalize()
+
+	runtimeName := strings.TrimSpace(obj.Runtime)
+	cpu := obj.CPUCores
+	mem := obj.MemoryMB
+	kernelExtra := strings.TrimSpace(obj.KernelCmdline)
+	if cfg != nil {
+		if v := strings.TrimSpace(cfg.Runtime); v != "" {
+			runtimeName = v
+		}
+		if cfg.Resources.CPUCores > 0 {
+			cpu = cfg.Resources.CPUCores
+		}
+		if cfg.Resources.MemoryMB > 0 {
+			mem = cfg.Resources.MemoryMB
+		}
+		if v := strings.TrimSpace(cfg.KernelCmdline); v != "" {
+			kernelExtra = v
+		}
+	}
+	if runtimeName == "" {
+		runtimeName = manifest.Runtime
+	}
+	if cpu <= 0 {
+		cpu = 2
+	}
+	if mem <= 0 {
+		mem = 2048
+	}
+	if cfg != nil {
+		cfg.Plugin = pluginName
+		cfg.Runtime = runtimeName
+		cfg.Resources.CPUCores = cpu
+		cfg.Resources.MemoryMB = mem
+		cfg.KernelCmdline = kernelExtra
+		if cfg.Manifest == nil {
+			manifestForConfig := manifest
+			cfg.Manifest = &manifestForConfig
+		} else {
+			cfg.Manifest.Normalize()
+		}
+	}
+
+	vm, err := s.engine.CreateVM(ctx, orchestrator.CreateVMRequest{
+		Name:              obj.Name,
+		Plugin:            pluginName,
+		Runtime:           runtimeName,
+		CPUCores:          cpu,
+		MemoryMB:          mem,
+		KernelCmdlineHint: kernelExtra,
+		Manifest:          &manifest,
+		Config:            cfg,
+	})
+	if err != nil {
+		s.logger.Error("create vm", "vm", obj.Name, "error", err)
+		return nil, toStatus(err)
+	}
+	return vmToProto(vm), nil
+}
+
+func (s *service) DeleteVM(ctx context.Context, req *volantv1.DeleteVMRequest) (*volantv1.DeleteVMResponse, error) {
+	if err := s.engine.DestroyVM(ctx, req.GetName()); err != nil {
+		return nil, toStatus(err)
+	}
+	return &volantv1.DeleteVMResponse{}, nil
+}
+
+func (s *service) StartVM(ctx context.Context, req *volantv1.VMActionRequest) (*volantv1.VM, error) {
+	return s.vmAction(ctx, req.GetName(), s.engine.StartVM)
+}
+
+func (s *service) StopVM(ctx context.Context, req *volantv1.VMActionRequest) (*volantv1.VM, error) {
+	return s.vmAction(ctx, req.GetName(), s.engine.StopVM)
+}
+
+func (s *service) RestartVM(ctx context.Context, req *volantv1.VMActionRequest) (*volantv1.VM, error) {
+	return s.vmAction(ctx, req.GetName(), s.engine.RestartVM)
+}
+
+func (s *service) vmAction(ctx context.Context, name string, action func(context.Context, string) (*db.VM, error)) (*volantv1.VM, error) {
+	vm, err := action(ctx, name)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return vmToProto(vm), nil
+}
+
+func (s *service) GetVMConfig(ctx context.Context, req *volantv1.GetVMConfigRequest) (*volantv1.VMConfig, error) {
+	versioned, err := s.engine.GetVMConfig(ctx, req.GetName())
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	if versioned == nil {
+		return nil, status.Errorf(codes.NotFound, "vm config %s not found", req.GetName())
+	}
+	return configToProto(versioned)
+}
+
+func (s *service) UpdateVMConfig(ctx context.Context, req *volantv1.UpdateVMConfigRequest) (*volantv1.VMConfig, error) {
+	var patch vmconfig.Patch
+	if err := json.Unmarshal(req.GetPatchJson(), &patch); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "decode patch: %v", err)
+	}
+	if err := s.admit.Admit(ctx, admission.OpVMConfigUpdate, req.GetName(), &patch); err != nil {
+		return nil, toStatus(err)
+	}
+	versioned, err := s.engine.UpdateVMConfig(ctx, req.GetName(), patch)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return configToProto(versioned)
+}
+
+func (s *service) ListDeployments(ctx context.Context, _ *volantv1.ListDeploymentsRequest) (*volantv1.ListDeploymentsResponse, error) {
+	deployments, err := s.engine.ListDeployments(ctx)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	resp := &volantv1.ListDeploymentsResponse{Deployments: make([]*volantv1.Deployment, 0, len(deployments))}
+	for i := range deployments {
+		out, err := deploymentToProto(&deployments[i])
+		if err != nil {
+			return nil, err
+		}
+		resp.Deployments = append(resp.Deployments, out)
+	}
+	return resp, nil
+}
+
+func (s *service) GetDeployment(ctx context.Context, req *volantv1.GetDeploymentRequest) (*volantv1.Deployment, error) {
+	deployment, err := s.engine.GetDeployment(ctx, req.GetName())
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return deploymentToProto(deployment)
+}
+
+func (s *service) CreateDeployment(ctx context.Context, req *volantv1.CreateDeploymentRequest) (*volantv1.Deployment, error) {
+	var cfg vmconfig.Config
+	if err := json.Unmarshal(req.GetConfigJson(), &cfg); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "decode config: %v", err)
+	}
+	obj := deploymentObject{
+		Name:     req.GetName(),
+		Replicas: int(req.GetReplicas()),
+		MaxSurge: int(req.GetMaxSurge()),
+		Config:   &cfg,
+	}
+	if err := s.admit.Admit(ctx, admission.OpDeploymentCreate, obj.Name, &obj); err != nil {
+		return nil, toStatus(err)
+	}
+	if obj.Config == nil {
+		return nil, status.Error(codes.InvalidArgument, "config is required")
+	}
+	deployment, err := s.engine.CreateDeployment(ctx, orchestrator.CreateDeploymentRequest{
+		Name:     obj.Name,
+		Replicas: obj.Replicas,
+		MaxSurge: obj.MaxSurge,
+		Config:   *obj.Config,
+	})
+	if err != nil {
+		s.logger.Error("create deployment", "deployment", obj.Name, "error", err)
+		return nil, toStatus(err)
+	}
+	return deploymentToProto(deployment)
+}
+
+func (s *service) ScaleDeployment(ctx context.Context, req *volantv1.ScaleDeploymentRequest) (*volantv1.Deployment, error) {
+	obj := deploymentObject{Replicas: int(req.GetReplicas())}
+	if err := s.admit.Admit(ctx, admission.OpDeploymentScale, req.GetName(), &obj); err != nil {
+		return nil, toStatus(err)
+	}
+	deployment, err := s.engine.ScaleDeployment(ctx, req.GetName(), obj.Replicas)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return deploymentToProto(deployment)
+}
+
+func (s *service) DeleteDeployment(ctx context.Context, req *volantv1.DeleteDeploymentRequest) (*volantv1.DeleteDeploymentResponse, error) {
+	if err := s.admit.Admit(ctx, admission.OpDeploymentDelete, req.GetName(), nil); err != nil {
+		return nil, toStatus(err)
+	}
+	if err := s.engine.DeleteDeployment(ctx, req.GetName()); err != nil {
+		return nil, toStatus(err)
+	}
+	return &volantv1.DeleteDeploymentResponse{}, nil
+}
+
+func (s *service) WatchVMEvents(req *volantv1.WatchVMEventsRequest, stream volantv1.Volant_WatchVMEventsServer) error {
+	events := make(chan any, 16)
+	unsubscribe, err := s.bus.Subscribe(orchestratorevents.TopicVMEvents, events)
+	if err != nil {
+		return status.Error(codes.Unavailable, "failed to subscribe")
+	}
+	defer unsubscribe()
+
+	ctx := stream.Context()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case payload := <-events:
+			event, ok := payload.(orchestratorevents.VMEvent)
+			if !ok {
+				continue
+			}
+			if name := req.GetName(); name != "" && event.Name != name {
+				continue
+			}
+			if err := stream.Send(eventToProto(event)); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func vmToProto(vm *db.VM) *volantv1.VM {
+	if vm == nil {
+		return nil
+	}
+	out := &volantv1.VM{
+		Id:            vm.ID,
+		Name:          vm.Name,
+		Status:        string(vm.Status),
+		Runtime:       vm.Runtime,
+		IpAddress:     vm.IPAddress,
+		Ipv6Address:   vm.IPv6Address,
+		MacAddress:    vm.MACAddress,
+		VsockCid:      vm.VsockCID,
+		CpuCores:      int32(vm.CPUCores),
+		MemoryMb:      int32(vm.MemoryMB),
+		KernelCmdline: vm.KernelCmdline,
+	}
+	if vm.PID != nil {
+		out.Pid = *vm.PID
+	}
+	return out
+}
+
+func configToProto(versioned *vmconfig.Versioned) (*volantv1.VMConfig, error) {
+	data, err := json.Marshal(versioned.Config)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "encode config: %v", err)
+	}
+	return &volantv1.VMConfig{
+		Version:    int32(versioned.Version),
+		ConfigJson: data,
+		UpdatedAt:  timestamppb.New(versioned.UpdatedAt),
+	}, nil
+}
+
+func deploymentToProto(deployment *orchestrator.Deployment) (*volantv1.Deployment, error) {
+	data, err := json.Marshal(deployment.Config)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "encode config: %v", err)
+	}
+	return &volantv1.Deployment{
+		Name:            deployment.Name,
+		DesiredReplicas: int32(deployment.DesiredReplicas),
+		ReadyReplicas:   int32(deployment.ReadyReplicas),
+		MaxSurge:        int32(deployment.MaxSurge),
+		ConfigJson:      data,
+		CreatedAt:       timestamppb.New(deployment.CreatedAt),
+		UpdatedAt:       timestamppb.New(deployment.UpdatedAt),
+	}, nil
+}
+
+func eventToProto(event orchestratorevents.VMEvent) *volantv1.VMEvent {
+	out := &volantv1.VMEvent{
+		Type:       event.Type,
+		Name:       event.Name,
+		Status:     string(event.Status),
+		IpAddress:  event.IPAddress,
+		MacAddress: event.MAC,
+		Timestamp:  timestamppb.New(event.Timestamp),
+		Message:    event.Message,
+		Code:       event.Code,
+		Hint:       event.Hint,
+	}
+	if event.PID != nil {
+		out.Pid = *event.PID
+	}
+	return out
+}
+
+// toStatus maps engine errors onto gRPC codes, following the REST API's
+// status mapping.
+func toStatus(err error) error {
+	switch {
+	case errors.Is(err, admission.ErrDenied):
+		return status.Error(codes.PermissionDenied, err.Error())
+	case errors.Is(err, admission.ErrWebhookFailed):
+		return status.Error(codes.Unavailable, err.Error())
+	case errors.Is(err, orchestrator.ErrVMNotFound),
+		errors.Is(err, orchestrator.ErrDeploymentNotFound),
+		errors.Is(err, orchestrator.ErrNetworkNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, orchestrator.ErrVMExists),
+		errors.Is(err, orchestrator.ErrDeploymentExists),
+		errors.Is(err, orchestrator.ErrNetworkExists):
+		return status.Error(codes.AlreadyExists, err.Error())
+	case errors.Is(err, orchestrator.ErrVMNotRunning),
+		errors.Is(err, orchestrator.ErrRolloutInProgress),
+		errors.Is(err, orchestrator.ErrConfigUpdateInProgress),
+		errors.Is(err, orchestrator.ErrHostPortInUse),
+		errors.Is(err, orchestrator.ErrDevicesUnavailable),
+		errors.Is(err, orchestrator.ErrDeviceClaimed),
+		errors.Is(err, orchestrator.ErrHostFeaturesMissing),
+		errors.Is(err, orchestrator.ErrPlacementUnsatisfiable):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case errors.Is(err, orchestrator.ErrInvalidLabels):
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	return status.Error(codes.Internal, fmt.Sprint(err))
+}
@@ -0,0 +1,142 @@
+// Copyright (c) 2025 HYPR. PTE. LTD.
+//
+// Business Source License 1.1
+// See LICENSE file in the project root for details.
+
+//go:build grpc
+
+package grpcapi
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net"
+	"sync"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/volantvm/volant/internal/pluginspec"
+	"github.com/volantvm/volant/internal/server/apiauth"
+	"github.com/volantvm/volant/internal/server/db"
+	"github.com/volantvm/volant/internal/server/grpcapi/volantv1"
+	"github.com/volantvm/volant/internal/server/orchestrator"
+	"github.com/volantvm/volant/internal/server/plugins"
+)
+
+// testEngine records the VMs it is asked to create. Calls to anything else
+// panic through the nil embedded Engine.
+type testEngine struct {
+	orchestrator.Engine
+
+	mu      sync.Mutex
+	created []orchestrator.CreateVMRequest
+}
+
+func (e *testEngine) CreateVM(_ context.Context, req orchestrator.CreateVMRequest) (*db.VM, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.created = append(e.created, req)
+	return &db.VM{ID: int64(len(e.created)), Name: req.Name, Status: db.VMStatusRunning, Runtime: req.Runtime, CPUCores: req.CPUCores, MemoryMB: req.MemoryMB}, nil
+}
+
+func (e *testEngine) ListVMs(context.Context) ([]db.VM, error) {
+	return nil, nil
+}
+
+func startTestServer(t *testing.T, engine orchestrator.Engine, registry *plugins.Registry) volantv1.VolantClient {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	server := New(slog.New(slog.NewTextHandler(io.Discard, nil)), engine, nil, registry, nil)
+	go func() { _ = server.Serve(listener) }()
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient(listener.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+	return volantv1.NewVolantClient(conn)
+}
+
+func TestAuthorization(t *testing.T) {
+	t.Cleanup(func() {
+		apiauth.Set(nil)
+		apiauth.SetNetworks(nil)
+	})
+	client := startTestServer(t, &testEngine{}, plugins.NewRegistry(nil))
+	ctx := context.Background()
+	withKey := func(key string) context.Context {
+		return metadata.AppendToOutgoingContext(ctx, apiauth.MetadataKey, key)
+	}
+
+	apiauth.Set(apiauth.New("secret"))
+	if _, err := client.ListVMs(ctx, &volantv1.ListVMsRequest{}); status.Code(err) != codes.Unauthenticated {
+		t.Fatalf("expected a call without a key refused, got %v", err)
+	}
+	if _, err := client.ListVMs(withKey("wrong"), &volantv1.ListVMsRequest{}); status.Code(err) != codes.Unauthenticated {
+		t.Fatalf("expected a call with the wrong key refused, got %v", err)
+	}
+	if _, err := client.ListVMs(withKey("secret"), &volantv1.ListVMsRequest{}); err != nil {
+		t.Fatalf("expected a call with the key accepted: %v", err)
+	}
+
+	outside, _ := apiauth.ParseNetworks([]string{"10.0.0.0/8"})
+	apiauth.SetNetworks(outside)
+	if _, err := client.ListVMs(withKey("secret"), &volantv1.ListVMsRequest{}); status.Code(err) != codes.PermissionDenied {
+		t.Fatalf("expected a client outside the allowlist refused, got %v", err)
+	}
+	stream, err := client.WatchVMEvents(withKey("secret"), &volantv1.WatchVMEventsRequest{})
+	if err == nil {
+		_, err = stream.Recv()
+	}
+	if status.Code(err) != codes.PermissionDenied {
+		t.Fatalf("expected a stream from outside the allowlist refused, got %v", err)
+	}
+
+	loopback, _ := apiauth.ParseNetworks([]string{"127.0.0.0/8"})
+	apiauth.SetNetworks(loopback)
+	if _, err := client.ListVMs(withKey("secret"), &volantv1.ListVMsRequest{}); err != nil {
+		t.Fatalf("expected a client inside the allowlist accepted: %v", err)
+	}
+}
+
+func TestCreateVM(t *testing.T) {
+	engine := &testEngine{}
+	registry := plugins.NewRegistry(nil)
+	registry.Register(pluginspec.Manifest{Name: "browser", Runtime: "browser", Enabled: true})
+	client := startTestServer(t, engine, registry)
+	ctx := context.Background()
+
+	vm, err := client.CreateVM(ctx, &volantv1.CreateVMRequest{
+		Name:       "web-1",
+		Plugin:     "browser",
+		CpuCores:   1,
+		ConfigJson: []byte(`{"resources":{"memory_mb":512}}`),
+	})
+	if err != nil {
+		t.Fatalf("create vm: %v", err)
+	}
+	if vm.GetName() != "web-1" || vm.GetRuntime() != "browser" || vm.GetCpuCores() != 1 || vm.GetMemoryMb() != 512 {
+		t.Fatalf("unexpected vm %+v", vm)
+	}
+	if len(engine.created) != 1 {
+		t.Fatalf("expected one create, got %d", len(engine.created))
+	}
+	req := engine.created[0]
+	if req.Plugin != "browser" || req.Config == nil || req.Config.Manifest == nil || req.Config.Manifest.Name != "browser" {
+		t.Fatalf("unexpected create request %+v", req)
+	}
+
+	if _, err := client.CreateVM(ctx, &volantv1.CreateVMRequest{Name: "web-2", Plugin: "missing"}); status.Code(err) != codes.NotFound {
+		t.Fatalf("expected an unknown plugin reported as not found, got %v", err)
+	}
+}
@@ -0,0 +1,672 @@
+// Copyright (c) 2025 HYPR. PTE. LTD.
+//
+// Business Source License 1.1
+// See LICENSE file in the project root for details.
+
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: internal/server/grpcapi/volantv1/volant.proto
+
+package volantv1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	Volant_ListVMs_FullMethodName          = "/volant.v1.Volant/ListVMs"
+	Volant_GetVM_FullMethodName            = "/volant.v1.Volant/GetVM"
+	Volant_CreateVM_FullMethodName         = "/volant.v1.Volant/CreateVM"
+	Volant_DeleteVM_FullMethodName         = "/volant.v1.Volant/DeleteVM"
+	Volant_StartVM_FullMethodName          = "/volant.v1.Volant/StartVM"
+	Volant_StopVM_FullMethodName           = "/volant.v1.Volant/StopVM"
+	Volant_RestartVM_FullMethodName        = "/volant.v1.Volant/RestartVM"
+	Volant_GetVMConfig_FullMethodName      = "/volant.v1.Volant/GetVMConfig"
+	Volant_UpdateVMConfig_FullMethodName   = "/volant.v1.Volant/UpdateVMConfig"
+	Volant_ListDeployments_FullMethodName  = "/volant.v1.Volant/ListDeployments"
+	Volant_GetDeployment_FullMethodName    = "/volant.v1.Volant/GetDeployment"
+	Volant_CreateDeployment_FullMethodName = "/volant.v1.Volant/CreateDeployment"
+	Volant_ScaleDeployment_FullMethodName  = "/volant.v1.Volant/ScaleDeployment"
+	Volant_DeleteDeployment_FullMethodName = "/volant.v1.Volant/DeleteDeployment"
+	Volant_WatchVMEvents_FullMethodName    = "/volant.v1.Volant/WatchVMEvents"
+)
+
+// VolantClient is the client API for Volant service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// Volant exposes the orchestrator engine. It mirrors the REST API under
+// /api/v1; VM and deployment configs travel as the same JSON documents the
+// REST API accepts, so both APIs share one schema.
+type VolantClient interface {
+	ListVMs(ctx context.Context, in *ListVMsRequest, opts ...grpc.CallOption) (*ListVMsResponse, error)
+	GetVM(ctx context.Context, in *GetVMRequest, opts ...grpc.CallOption) (*VM, error)
+	CreateVM(ctx context.Context, in *CreateVMRequest, opts ...grpc.CallOption) (*VM, error)
+	DeleteVM(ctx context.Context, in *DeleteVMRequest, opts ...grpc.CallOption) (*DeleteVMResponse, error)
+	StartVM(ctx context.Context, in *VMActionRequest, opts ...grpc.CallOption) (*VM, error)
+	StopVM(ctx context.Context, in *VMActionRequest, opts ...grpc.CallOption) (*VM, error)
+	RestartVM(ctx context.Context, in *VMActionRequest, opts ...grpc.CallOption) (*VM, error)
+	GetVMConfig(ctx context.Context, in *GetVMConfigRequest, opts ...grpc.CallOption) (*VMConfig, error)
+	UpdateVMConfig(ctx context.Context, in *UpdateVMConfigRequest, opts ...grpc.CallOption) (*VMConfig, error)
+	ListDeployments(ctx context.Context, in *ListDeploymentsRequest, opts ...grpc.CallOption) (*ListDeploymentsResponse, error)
+	GetDeployment(ctx context.Context, in *GetDeploymentRequest, opts ...grpc.CallOption) (*Deployment, error)
+	CreateDeployment(ctx context.Context, in *CreateDeploymentRequest, opts ...grpc.CallOption) (*Deployment, error)
+	ScaleDeployment(ctx context.Context, in *ScaleDeploymentRequest, opts ...grpc.CallOption) (*Deployment, error)
+	DeleteDeployment(ctx context.Context, in *DeleteDeploymentRequest, opts ...grpc.CallOption) (*DeleteDeploymentResponse, error)
+	// WatchVMEvents streams lifecycle events until the client cancels.
+	WatchVMEvents(ctx context.Context, in *WatchVMEventsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[VMEvent], error)
+}
+
+type volantClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewVolantClient(cc grpc.ClientConnInterface) VolantClient {
+	return &volantClient{cc}
+}
+
+func (c *volantClient) ListVMs(ctx context.Context, in *ListVMsRequest, opts ...grpc.CallOption) (*ListVMsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListVMsResponse)
+	err := c.cc.Invoke(ctx, Volant_ListVMs_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *volantClient) GetVM(ctx context.Context, in *GetVMRequest, opts ...grpc.CallOption) (*VM, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(VM)
+	err := c.cc.Invoke(ctx, Volant_GetVM_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *volantClient) CreateVM(ctx context.Context, in *CreateVMRequest, opts ...grpc.CallOption) (*VM, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(VM)
+	err := c.cc.Invoke(ctx, Volant_CreateVM_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *volantClient) DeleteVM(ctx context.Context, in *DeleteVMRequest, opts ...grpc.CallOption) (*DeleteVMResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeleteVMResponse)
+	err := c.cc.Invoke(ctx, Volant_DeleteVM_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *volantClient) StartVM(ctx context.Context, in *VMActionRequest, opts ...grpc.CallOption) (*VM, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(VM)
+	err := c.cc.Invoke(ctx, Volant_StartVM_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *volantClient) StopVM(ctx context.Context, in *VMActionRequest, opts ...grpc.CallOption) (*VM, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(VM)
+	err := c.cc.Invoke(ctx, Volant_StopVM_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *volantClient) RestartVM(ctx context.Context, in *VMActionRequest, opts ...grpc.CallOption) (*VM, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(VM)
+	err := c.cc.Invoke(ctx, Volant_RestartVM_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *volantClient) GetVMConfig(ctx context.Context, in *GetVMConfigRequest, opts ...grpc.CallOption) (*VMConfig, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(VMConfig)
+	err := c.cc.Invoke(ctx, Volant_GetVMConfig_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *volantClient) UpdateVMConfig(ctx context.Context, in *UpdateVMConfigRequest, opts ...grpc.CallOption) (*VMConfig, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(VMConfig)
+	err := c.cc.Invoke(ctx, Volant_UpdateVMConfig_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *volantClient) ListDeployments(ctx context.Context, in *ListDeploymentsRequest, opts ...grpc.CallOption) (*ListDeploymentsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListDeploymentsResponse)
+	err := c.cc.Invoke(ctx, Volant_ListDeployments_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *volantClient) GetDeployment(ctx context.Context, in *GetDeploymentRequest, opts ...grpc.CallOption) (*Deployment, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Deployment)
+	err := c.cc.Invoke(ctx, Volant_GetDeployment_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *volantClient) CreateDeployment(ctx context.Context, in *CreateDeploymentRequest, opts ...grpc.CallOption) (*Deployment, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Deployment)
+	err := c.cc.Invoke(ctx, Volant_CreateDeployment_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *volantClient) ScaleDeployment(ctx context.Context, in *ScaleDeploymentRequest, opts ...grpc.CallOption) (*Deployment, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Deployment)
+	err := c.cc.Invoke(ctx, Volant_ScaleDeployment_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *volantClient) DeleteDeployment(ctx context.Context, in *DeleteDeploymentRequest, opts ...grpc.CallOption) (*DeleteDeploymentResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeleteDeploymentResponse)
+	err := c.cc.Invoke(ctx, Volant_DeleteDeployment_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *volantClient) WatchVMEvents(ctx context.Context, in *WatchVMEventsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[VMEvent], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &Volant_ServiceDesc.Streams[0], Volant_WatchVMEvents_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[WatchVMEventsRequest, VMEvent]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Volant_WatchVMEventsClient = grpc.ServerStreamingClient[VMEvent]
+
+// VolantServer is the server API for Volant service.
+// All implementations must embed UnimplementedVolantServer
+// for forward compatibility.
+//
+// Volant exposes the orchestrator engine. It mirrors the REST API under
+// /api/v1; VM and deployment configs travel as the same JSON documents the
+// REST API accepts, so both APIs share one schema.
+type VolantServer interface {
+	ListVMs(context.Context, *ListVMsRequest) (*ListVMsResponse, error)
+	GetVM(context.Context, *GetVMRequest) (*VM, error)
+	CreateVM(context.Context, *CreateVMRequest) (*VM, error)
+	DeleteVM(context.Context, *DeleteVMRequest) (*DeleteVMResponse, error)
+	StartVM(context.Context, *VMActionRequest) (*VM, error)
+	StopVM(context.Context, *VMActionRequest) (*VM, error)
+	RestartVM(context.Context, *VMActionRequest) (*VM, error)
+	GetVMConfig(context.Context, *GetVMConfigRequest) (*VMConfig, error)
+	UpdateVMConfig(context.Context, *UpdateVMConfigRequest) (*VMConfig, error)
+	ListDeployments(context.Context, *ListDeploymentsRequest) (*ListDeploymentsResponse, error)
+	GetDeployment(context.Context, *GetDeploymentRequest) (*Deployment, error)
+	CreateDeployment(context.Context, *CreateDeploymentRequest) (*Deployment, error)
+	ScaleDeployment(context.Context, *ScaleDeploymentRequest) (*Deployment, error)
+	DeleteDeployment(context.Context, *DeleteDeploymentRequest) (*DeleteDeploymentResponse, error)
+	// WatchVMEvents streams lifecycle events until the client cancels.
+	WatchVMEvents(*WatchVMEventsRequest, grpc.ServerStreamingServer[VMEvent]) error
+	mustEmbedUnimplementedVolantServer()
+}
+
+// UnimplementedVolantServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedVolantServer struct{}
+
+func (UnimplementedVolantServer) ListVMs(context.Context, *ListVMsRequest) (*ListVMsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListVMs not implemented")
+}
+func (UnimplementedVolantServer) GetVM(context.Context, *GetVMRequest) (*VM, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetVM not implemented")
+}
+func (UnimplementedVolantServer) CreateVM(context.Context, *CreateVMRequest) (*VM, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateVM not implemented")
+}
+func (UnimplementedVolantServer) DeleteVM(context.Context, *DeleteVMRequest) (*DeleteVMResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteVM not implemented")
+}
+func (UnimplementedVolantServer) StartVM(context.Context, *VMActionRequest) (*VM, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method StartVM not implemented")
+}
+func (UnimplementedVolantServer) StopVM(context.Context, *VMActionRequest) (*VM, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method StopVM not implemented")
+}
+func (UnimplementedVolantServer) RestartVM(context.Context, *VMActionRequest) (*VM, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RestartVM not implemented")
+}
+func (UnimplementedVolantServer) GetVMConfig(context.Context, *GetVMConfigRequest) (*VMConfig, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetVMConfig not implemented")
+}
+func (UnimplementedVolantServer) UpdateVMConfig(context.Context, *UpdateVMConfigRequest) (*VMConfig, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateVMConfig not implemented")
+}
+func (UnimplementedVolantServer) ListDeployments(context.Context, *ListDeploymentsRequest) (*ListDeploymentsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListDeployments not implemented")
+}
+func (UnimplementedVolantServer) GetDeployment(context.Context, *GetDeploymentRequest) (*Deployment, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetDeployment not implemented")
+}
+func (UnimplementedVolantServer) CreateDeployment(context.Context, *CreateDeploymentRequest) (*Deployment, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateDeployment not implemented")
+}
+func (UnimplementedVolantServer) ScaleDeployment(context.Context, *ScaleDeploymentRequest) (*Deployment, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ScaleDeployment not implemented")
+}
+func (UnimplementedVolantServer) DeleteDeployment(context.Context, *DeleteDeploymentRequest) (*DeleteDeploymentResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteDeployment not implemented")
+}
+func (UnimplementedVolantServer) WatchVMEvents(*WatchVMEventsRequest, grpc.ServerStreamingServer[VMEvent]) error {
+	return status.Errorf(codes.Unimplemented, "method WatchVMEvents not implemented")
+}
+func (UnimplementedVolantServer) mustEmbedUnimplementedVolantServer() {}
+func (UnimplementedVolantServer) testEmbeddedByValue()                {}
+
+// UnsafeVolantServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to VolantServer will
+// result in compilation errors.
+type UnsafeVolantServer interface {
+	mustEmbedUnimplementedVolantServer()
+}
+
+func RegisterVolantServer(s grpc.ServiceRegistrar, srv VolantServer) {
+	// If the following call pancis, it indicates UnimplementedVolantServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&Volant_ServiceDesc, srv)
+}
+
+func _Volant_ListVMs_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListVMsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(VolantServer).ListVMs(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Volant_ListVMs_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(VolantServer).ListVMs(ctx, req.(*ListVMsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Volant_GetVM_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetVMRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(VolantServer).GetVM(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Volant_GetVM_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(VolantServer).GetVM(ctx, req.(*GetVMRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Volant_CreateVM_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateVMRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(VolantServer).CreateVM(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Volant_CreateVM_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(VolantServer).CreateVM(ctx, req.(*CreateVMRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Volant_DeleteVM_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteVMRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(VolantServer).DeleteVM(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Volant_DeleteVM_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(VolantServer).DeleteVM(ctx, req.(*DeleteVMRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Volant_StartVM_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(VMActionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(VolantServer).StartVM(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Volant_StartVM_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(VolantServer).StartVM(ctx, req.(*VMActionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Volant_StopVM_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(VMActionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(VolantServer).StopVM(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Volant_StopVM_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(VolantServer).StopVM(ctx, req.(*VMActionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Volant_RestartVM_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(VMActionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(VolantServer).RestartVM(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Volant_RestartVM_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(VolantServer).RestartVM(ctx, req.(*VMActionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Volant_GetVMConfig_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetVMConfigRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(VolantServer).GetVMConfig(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Volant_GetVMConfig_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(VolantServer).GetVMConfig(ctx, req.(*GetVMConfigRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Volant_UpdateVMConfig_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateVMConfigRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(VolantServer).UpdateVMConfig(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Volant_UpdateVMConfig_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(VolantServer).UpdateVMConfig(ctx, req.(*UpdateVMConfigRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Volant_ListDeployments_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListDeploymentsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(VolantServer).ListDeployments(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Volant_ListDeployments_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(VolantServer).ListDeployments(ctx, req.(*ListDeploymentsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Volant_GetDeployment_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetDeploymentRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(VolantServer).GetDeployment(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Volant_GetDeployment_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(VolantServer).GetDeployment(ctx, req.(*GetDeploymentRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Volant_CreateDeployment_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateDeploymentRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(VolantServer).CreateDeployment(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Volant_CreateDeployment_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(VolantServer).CreateDeployment(ctx, req.(*CreateDeploymentRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Volant_ScaleDeployment_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ScaleDeploymentRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(VolantServer).ScaleDeployment(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Volant_ScaleDeployment_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(VolantServer).ScaleDeployment(ctx, req.(*ScaleDeploymentRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Volant_DeleteDeployment_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteDeploymentRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(VolantServer).DeleteDeployment(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Volant_DeleteDeployment_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(VolantServer).DeleteDeployment(ctx, req.(*DeleteDeploymentRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Volant_WatchVMEvents_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchVMEventsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(VolantServer).WatchVMEvents(m, &grpc.GenericServerStream[WatchVMEventsRequest, VMEvent]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Volant_WatchVMEventsServer = grpc.ServerStreamingServer[VMEvent]
+
+// Volant_ServiceDesc is the grpc.ServiceDesc for Volant service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Volant_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "volant.v1.Volant",
+	HandlerType: (*VolantServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ListVMs",
+			Handler:    _Volant_ListVMs_Handler,
+		},
+		{
+			MethodName: "GetVM",
+			Handler:    _Volant_GetVM_Handler,
+		},
+		{
+			MethodName: "CreateVM",
+			Handler:    _Volant_CreateVM_Handler,
+		},
+		{
+			MethodName: "DeleteVM",
+			Handler:    _Volant_DeleteVM_Handler,
+		},
+		{
+			MethodName: "StartVM",
+			Handler:    _Volant_StartVM_Handler,
+		},
+		{
+			MethodName: "StopVM",
+			Handler:    _Volant_StopVM_Handler,
+		},
+		{
+			MethodName: "RestartVM",
+			Handler:    _Volant_RestartVM_Handler,
+		},
+		{
+			MethodName: "GetVMConfig",
+			Handler:    _Volant_GetVMConfig_Handler,
+		},
+		{
+			MethodName: "UpdateVMConfig",
+			Handler:    _Volant_UpdateVMConfig_Handler,
+		},
+		{
+			MethodName: "ListDeployments",
+			Handler:    _Volant_ListDeployments_Handler,
+		},
+		{
+			MethodName: "GetDeployment",
+			Handler:    _Volant_GetDeployment_Handler,
+		},
+		{
+			MethodName: "CreateDeployment",
+			Handler:    _Volant_CreateDeployment_Handler,
+		},
+		{
+			MethodName: "ScaleDeployment",
+			Handler:    _Volant_ScaleDeployment_Handler,
+		},
+		{
+			MethodName: "DeleteDeployment",
+			Handler:    _Volant_DeleteDeployment_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "WatchVMEvents",
+			Handler:       _Volant_WatchVMEvents_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "internal/server/grpcapi/volantv1/volant.proto",
+}
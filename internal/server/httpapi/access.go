@@ -0,0 +1,145 @@
+// Copyright (c) 2025 HYPR. PTE. LTD.
+//
+// Business Source License 1.1
+// See LICENSE file in the project root for details.
+
+package httpapi
+
+import (
+	"log/slog"
+	"net"
+	"net/http"
+	"strings"
+	"sync/atomic"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/volantvm/volant/internal/server/apiauth"
+	"github.com/volantvm/volant/internal/server/config"
+)
+
+// accessPolicy is what the API enforces before routing a request: CORS for
+// browser-based clients, the client CIDR allowlist and the API key. A
+// configuration reload replaces it.
+type accessPolicy struct {
+	// cors is nil when VOLANT_CORS_ORIGINS is unset.
+	cors *corsPolicy
+	// networks is empty when no valid CIDR is configured, allowing every
+	// client.
+	networks apiauth.Networks
+	checker  *apiauth.Checker
+}
+
+// corsPolicy lists the allowed origins; methods and headers use sane
+// defaults.
+type corsPolicy struct {
+	origins  []string
+	allowAll bool
+}
+
+func newAccessPolicy(logger *slog.Logger, settings config.Reloadable) *accessPolicy {
+	policy := &accessPolicy{checker: apiauth.New(settings.APIKey)}
+	if len(settings.CORSOrigins) > 0 {
+		policy.cors = &corsPolicy{}
+		for _, origin := range settings.CORSOrigins {
+			if origin == "*" {
+				policy.cors.allowAll = true
+			}
+			policy.cors.origins = append(policy.cors.origins, origin)
+		}
+	}
+	networks, err := apiauth.ParseNetworks(settings.AllowCIDRs)
+	if err != nil {
+		logger.Warn("client allowlist", "error", err)
+	}
+	policy.networks = networks
+	return policy
+}
+
+func accessMiddleware(logger *slog.Logger, current *atomic.Pointer[accessPolicy]) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		policy := current.Load()
+		if policy.cors != nil {
+			policy.cors.setHeaders(c)
+			if c.Request.Method == http.MethodOptions {
+				c.Status(http.StatusNoContent)
+				c.Abort()
+				return
+			}
+		}
+		if len(policy.networks) > 0 && !policy.allowsClient(logger, c) {
+			return
+		}
+		if policy.checker != nil {
+			provided := c.GetHeader(apiauth.Header)
+			if provided == "" {
+				provided = c.Query(apiauth.QueryParam)
+			}
+			if err := policy.checker.Check(provided); err != nil {
+				abortError(c, http.StatusUnauthorized, CodeUnauthorized, err.Error())
+				return
+			}
+		}
+		c.Next()
+	}
+}
+
+// setHeaders answers an allowed origin with the CORS headers.
+func (p *corsPolicy) setHeaders(c *gin.Context) {
+	origin := c.GetHeader("Origin")
+	if origin == "" {
+		return
+	}
+	allowedOrigin := ""
+	if p.allowAll {
+		allowedOrigin = "*"
+	} else {
+		for _, o := range p.origins {
+			if strings.EqualFold(o, origin) {
+				allowedOrigin = origin
+				break
+			}
+		}
+	}
+	if allowedOrigin == "" {
+		return
+	}
+	c.Header("Access-Control-Allow-Origin", allowedOrigin)
+	c.Header("Vary", "Origin")
+	c.Header("Access-Control-Allow-Credentials", "true")
+	c.Header("Access-Control-Allow-Methods", "GET, POST, PATCH, DELETE, OPTIONS")
+	c.Header("Access-Control-Allow-Headers", "Content-Type, Authorization, X-Requested-With, X-Volant-API-Key, X-Volant-Console-Key")
+	c.Header("Access-Control-Expose-Headers", "Content-Type, X-Total-Count, Location")
+}
+
+// allowsClient aborts requests from clients outside the allowlist.
+func (p *accessPolicy) allowsClient(logger *slog.Logger, c *gin.Context) bool {
+	ip := net.ParseIP(c.ClientIP())
+	if ip == nil {
+		abortError(c, http.StatusForbidden, CodeForbidden, "invalid client IP")
+		return false
+	}
+	if p.networks.Allows(ip) {
+		return true
+	}
+	logger.Warn("request blocked by CIDR filter", "ip", ip.String())
+	abortError(c, http.StatusForbidden, CodeForbidden, apiauth.ErrClientDenied.Error())
+	return false
+}
+
+// reloadConfig re-reads the configuration file and applies the settings
+// that can change without a restart, as SIGHUP does.
+func (api *apiServer) reloadConfig(c *gin.Context) {
+	if api.reloader == nil {
+		respondError(c, http.StatusNotImplemented, CodeNotImplemented, "configuration reload is not available")
+		return
+	}
+	result, err := api.reloader.Reload()
+	if err != nil {
+		api.logger.Error("reload config", "error", err)
+		respondError(c, http.StatusUnprocessableEntity, CodeUnprocessable, err.Error())
+		return
+	}
+	api.logger.Info("config reloaded", "applied", result.Applied, "restart_required", result.RestartRequired)
+	c.JSON(http.StatusOK, result)
+}
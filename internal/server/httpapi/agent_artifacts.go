@@ -0,0 +1,253 @@
+// Copyright (c) 2025 HYPR. PTE. LTD.
+//
+// Business Source License 1.1
+// See LICENSE file in the project root for details.
+
+package httpapi
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/volantvm/volant/internal/pluginspec"
+	"github.com/volantvm/volant/internal/server/db"
+)
+
+// defaultAgentDir holds the agent binaries guests update to unless
+// VOLANT_AGENT_DIR says otherwise. Each lives at <version>/kestrel-<arch>
+// beside a .sha256 file holding its hex digest.
+const defaultAgentDir = "/var/lib/volant/agent"
+
+// agentArtifact describes an agent binary volantd serves to guests.
+type agentArtifact struct {
+	Version    string    `json:"version"`
+	Arch       string    `json:"arch"`
+	SizeBytes  int64     `json:"size_bytes"`
+	SHA256     string    `json:"sha256"`
+	ModifiedAt time.Time `json:"modified_at"`
+}
+
+func agentDirFromEnv() string {
+	if dir := strings.TrimSpace(os.Getenv("VOLANT_AGENT_DIR")); dir != "" {
+		return dir
+	}
+	return defaultAgentDir
+}
+
+// agentBinaryPath returns where the agent binary of version and arch lives,
+// or false after answering 400 when either is invalid.
+func (api *apiServer) agentBinaryPath(c *gin.Context) (string, bool) {
+	version, arch := c.Param("version"), c.Param("arch")
+	if !pluginspec.ValidAgentVersion(version) {
+		respondError(c, http.StatusBadRequest, CodeInvalidRequest, fmt.Sprintf("invalid agent version %q", version))
+		return "", false
+	}
+	if !pluginspec.IsAgentArchitecture(arch) {
+		respondError(c, http.StatusBadRequest, CodeInvalidRequest, fmt.Sprintf("unknown architecture %q (known: %s)", arch, strings.Join(pluginspec.AgentArchitectures, ", ")))
+		return "", false
+	}
+	return filepath.Join(api.agentDir, version, "kestrel-"+arch), true
+}
+
+// agentBinarySum returns the digest recorded for the binary at path,
+// hashing it (and recording the result) when an operator placed the binary
+// there by hand.
+func agentBinarySum(path string) (string, error) {
+	if data, err := os.ReadFile(path + ".sha256"); err == nil {
+		if sum := strings.TrimSpace(string(data)); len(sum) == sha256.Size*2 {
+			return sum, nil
+		}
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	hash := sha256.New()
+	if _, err := io.Copy(hash, f); err != nil {
+		return "", err
+	}
+	sum := hex.EncodeToString(hash.Sum(nil))
+	_ = os.WriteFile(path+".sha256", []byte(sum+"\n"), 0o644)
+	return sum, nil
+}
+
+// listAgentArtifacts lists the agent binaries available to guests.
+func (api *apiServer) listAgentArtifacts(c *gin.Context) {
+	artifacts := []agentArtifact{}
+	versions, err := os.ReadDir(api.agentDir)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		api.logger.Error("list agent artifacts", "dir", api.agentDir, "error", err)
+		respondError(c, http.StatusInternalServerError, CodeInternal, "failed to list agent artifacts")
+		return
+	}
+	for _, version := range versions {
+		if !version.IsDir() || !pluginspec.ValidAgentVersion(version.Name()) {
+			continue
+		}
+		for _, arch := range pluginspec.AgentArchitectures {
+			path := filepath.Join(api.agentDir, version.Name(), "kestrel-"+arch)
+			info, err := os.Stat(path)
+			if err != nil || !info.Mode().IsRegular() {
+				continue
+			}
+			sum, err := agentBinarySum(path)
+			if err != nil {
+				api.logger.Warn("hash agent artifact", "path", path, "error", err)
+				continue
+			}
+			artifacts = append(artifacts, agentArtifact{Version: version.Name(), Arch: arch, SizeBytes: info.Size(), SHA256: sum, ModifiedAt: info.ModTime().UTC()})
+		}
+	}
+	sort.SliceStable(artifacts, func(i, j int) bool {
+		if artifacts[i].Version != artifacts[j].Version {
+			return artifacts[i].Version < artifacts[j].Version
+		}
+		return artifacts[i].Arch < artifacts[j].Arch
+	})
+	c.JSON(http.StatusOK, artifacts)
+}
+
+// putAgentArtifact stores an agent binary for guests to update to. The
+// upload only replaces an existing binary once complete and, when the
+// caller sent X-Volant-Checksum-Sha256, verified.
+func (api *apiServer) putAgentArtifact(c *gin.Context) {
+	path, ok := api.agentBinaryPath(c)
+	if !ok {
+		return
+	}
+	if c.Request.ContentLength > api.fileLimit {
+		respondError(c, http.StatusRequestEntityTooLarge, CodeFileTooLarge, fmt.Sprintf("file exceeds the limit of %d bytes", api.fileLimit))
+		return
+	}
+	want := strings.ToLower(strings.TrimPrefix(strings.TrimSpace(c.GetHeader(checksumHeader)), "sha256:"))
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		api.logger.Error("store agent artifact", "path", path, "error", err)
+		respondError(c, http.StatusInternalServerError, CodeInternal, "failed to store agent artifact")
+		return
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".kestrel-*")
+	if err != nil {
+		api.logger.Error("store agent artifact", "path", path, "error", err)
+		respondError(c, http.StatusInternalServerError, CodeInternal, "failed to store agent artifact")
+		return
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+
+	clearDeadlines(c)
+	hash := sha256.New()
+	size, err := io.Copy(io.MultiWriter(tmp, hash), http.MaxBytesReader(c.Writer, c.Request.Body, api.fileLimit))
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			respondError(c, http.StatusRequestEntityTooLarge, CodeFileTooLarge, fmt.Sprintf("file exceeds the limit of %d bytes", api.fileLimit))
+			return
+		}
+		respondError(c, http.StatusBadRequest, CodeInvalidRequest, "read upload: "+err.Error())
+		return
+	}
+	if size == 0 {
+		respondError(c, http.StatusBadRequest, CodeInvalidRequest, "empty agent binary")
+		return
+	}
+	sum := hex.EncodeToString(hash.Sum(nil))
+	if want != "" && want != sum {
+		respondError(c, http.StatusUnprocessableEntity, CodeChecksumMismatch, fmt.Sprintf("upload has sha256 %s, expected %s", sum, want))
+		return
+	}
+	if err := tmp.Chmod(0o755); err == nil {
+		err = tmp.Close()
+	}
+	if err == nil {
+		err = os.WriteFile(path+".sha256", []byte(sum+"\n"), 0o644)
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), path)
+	}
+	if err != nil {
+		api.logger.Error("store agent artifact", "path", path, "error", err)
+		respondError(c, http.StatusInternalServerError, CodeInternal, "failed to store agent artifact")
+		return
+	}
+
+	version, arch := c.Param("version"), c.Param("arch")
+	api.recordAudit(c.Request.Context(), db.AuditEvent{
+		Action:   auditAgentUpload,
+		Target:   version + "/" + arch,
+		Actor:    anonymousConsoleUser,
+		ClientIP: c.ClientIP(),
+		Message:  "sha256 " + sum,
+	})
+	c.JSON(http.StatusCreated, agentArtifact{Version: version, Arch: arch, SizeBytes: size, SHA256: sum, ModifiedAt: time.Now().UTC()})
+}
+
+// getAgentArtifact serves an agent binary with its SHA-256 in
+// X-Volant-Checksum-Sha256. Guests fetch it on boot when their manifest
+// pins a newer agent.
+func (api *apiServer) getAgentArtifact(c *gin.Context) {
+	path, ok := api.agentBinaryPath(c)
+	if !ok {
+		return
+	}
+	f, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		respondError(c, http.StatusNotFound, CodeNotFound, "agent artifact not found")
+		return
+	}
+	if err != nil {
+		api.logger.Error("open agent artifact", "path", path, "error", err)
+		respondError(c, http.StatusInternalServerError, CodeInternal, "failed to open agent artifact")
+		return
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil || !info.Mode().IsRegular() {
+		respondError(c, http.StatusNotFound, CodeNotFound, "agent artifact not found")
+		return
+	}
+	sum, err := agentBinarySum(path)
+	if err != nil {
+		api.logger.Error("hash agent artifact", "path", path, "error", err)
+		respondError(c, http.StatusInternalServerError, CodeInternal, "failed to hash agent artifact")
+		return
+	}
+	clearDeadlines(c)
+	c.Header(checksumHeader, sum)
+	c.Header("Content-Type", "application/octet-stream")
+	http.ServeContent(c.Writer, c.Request, "", info.ModTime(), f)
+}
+
+// deleteAgentArtifact removes an agent binary. Guests pinned to it keep
+// running their current agent.
+func (api *apiServer) deleteAgentArtifact(c *gin.Context) {
+	path, ok := api.agentBinaryPath(c)
+	if !ok {
+		return
+	}
+	if err := os.Remove(path); err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			respondError(c, http.StatusNotFound, CodeNotFound, "agent artifact not found")
+			return
+		}
+		api.logger.Error("delete agent artifact", "path", path, "error", err)
+		respondError(c, http.StatusInternalServerError, CodeInternal, "failed to delete agent artifact")
+		return
+	}
+	_ = os.Remove(path + ".sha256")
+	// Drop the version directory once its last binary is gone.
+	_ = os.Remove(filepath.Dir(path))
+	c.Status(http.StatusNoContent)
+}
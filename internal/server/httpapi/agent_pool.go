@@ -0,0 +1,297 @@
+// Copyright (c) 2025 HYPR. PTE. LTD.
+//
+// Business Source License 1.1
+// See LICENSE file in the project root for details.
+
+package httpapi
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// agentMaxConnsPerVM caps the connections open to one guest agent;
+	// further calls wait for one to free up.
+	agentMaxConnsPerVM     = 16
+	agentMaxIdleConnsPerVM = 4
+
+	// agentBreakerThreshold consecutive failures to reach an agent open its
+	// breaker for agentBreakerCooldown, doubled after each failed probe up
+	// to agentBreakerMaxCooldown.
+	agentBreakerThreshold   = 5
+	agentBreakerCooldown    = 5 * time.Second
+	agentBreakerMaxCooldown = time.Minute
+	// agentBreakerIdle is how long an agent with a closed breaker and no
+	// calls is remembered.
+	agentBreakerIdle = 10 * time.Minute
+)
+
+// Breaker states reported in agent stats.
+const (
+	breakerClosed   = "closed"
+	breakerOpen     = "open"
+	breakerHalfOpen = "half_open"
+)
+
+// agentUnavailableError is returned for calls to an agent whose breaker is
+// open, without contacting it.
+type agentUnavailableError struct {
+	host       string
+	retryAfter time.Duration
+}
+
+func (e *agentUnavailableError) Error() string {
+	return fmt.Sprintf("agent %s is unavailable after repeated failures; retry in %s", e.host, e.retryAfter.Round(time.Second))
+}
+
+// callerBodyError is a failure reading a request body relayed from the
+// caller to an agent.
+type callerBodyError struct {
+	err error
+}
+
+func (e *callerBodyError) Error() string { return e.err.Error() }
+
+func (e *callerBodyError) Unwrap() error { return e.err }
+
+// callerBody marks read errors of a relayed request body as the caller's.
+type callerBody struct {
+	r io.Reader
+}
+
+func (b callerBody) Read(p []byte) (int, error) {
+	n, err := b.r.Read(p)
+	if err != nil && !errors.Is(err, io.EOF) {
+		err = &callerBodyError{err: err}
+	}
+	return n, err
+}
+
+// agentStats reports the calls to one guest agent.
+type agentStats struct {
+	VM                  string     `json:"vm,omitempty"`
+	Host                string     `json:"host"`
+	State               string     `json:"state"`
+	InFlight            int        `json:"in_flight"`
+	Requests            uint64     `json:"requests"`
+	Failures            uint64     `json:"failures"`
+	Rejected            uint64     `json:"rejected"`
+	ConsecutiveFailures int        `json:"consecutive_failures"`
+	RetryAfterSeconds   int        `json:"retry_after_seconds,omitempty"`
+	LastError           string     `json:"last_error,omitempty"`
+	LastFailureAt       *time.Time `json:"last_failure_at,omitempty"`
+}
+
+type agentStatsResponse struct {
+	Agents []agentStats `json:"agents"`
+}
+
+// agentBreaker is the circuit breaker and counters of one agent host.
+type agentBreaker struct {
+	stats     agentStats
+	cooldown  time.Duration
+	openUntil time.Time
+	probing   bool
+	lastUsed  time.Time
+}
+
+// agentBreakers wraps the agent transport with a circuit breaker per agent
+// host, which is one per VM. Only failures to reach the agent count: errors
+// it answers with are the workload's.
+type agentBreakers struct {
+	next      http.RoundTripper
+	now       func() time.Time
+	mu        sync.Mutex
+	hosts     map[string]*agentBreaker
+	lastPrune time.Time
+}
+
+func newAgentBreakers(next http.RoundTripper) *agentBreakers {
+	return &agentBreakers{next: next, now: time.Now, hosts: make(map[string]*agentBreaker)}
+}
+
+func (b *agentBreakers) RoundTrip(req *http.Request) (*http.Response, error) {
+	host := req.URL.Host
+	probe, err := b.acquire(host)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := b.next.RoundTrip(req)
+	// Calls the caller gave up on, or whose body it failed to send, say
+	// nothing about the agent.
+	var bodyErr *callerBodyError
+	failed := err != nil && req.Context().Err() == nil && !errors.As(err, &bodyErr)
+	b.record(host, probe, failed, err)
+	if err != nil {
+		b.done(host)
+		return nil, err
+	}
+	resp.Body = &agentBody{ReadCloser: resp.Body, done: func() { b.done(host) }}
+	return resp, nil
+}
+
+// acquire admits a call unless the host's breaker is open. Once the cooldown
+// has passed, one call is let through as a probe.
+func (b *agentBreakers) acquire(host string) (bool, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	now := b.now()
+	b.pruneLocked(now)
+	breaker, ok := b.hosts[host]
+	if !ok {
+		breaker = &agentBreaker{stats: agentStats{Host: host, State: breakerClosed}}
+		b.hosts[host] = breaker
+	}
+	breaker.lastUsed = now
+
+	probe := false
+	switch breaker.stats.State {
+	case breakerOpen:
+		if now.Before(breaker.openUntil) {
+			breaker.stats.Rejected++
+			return false, &agentUnavailableError{host: host, retryAfter: breaker.openUntil.Sub(now)}
+		}
+		breaker.stats.State = breakerHalfOpen
+		breaker.probing = true
+		probe = true
+	case breakerHalfOpen:
+		if breaker.probing {
+			breaker.stats.Rejected++
+			return false, &agentUnavailableError{host: host, retryAfter: time.Second}
+		}
+		breaker.probing = true
+		probe = true
+	}
+	breaker.stats.Requests++
+	breaker.stats.InFlight++
+	return probe, nil
+}
+
+func (b *agentBreakers) record(host string, probe, failed bool, err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	breaker, ok := b.hosts[host]
+	if !ok {
+		return
+	}
+	if probe {
+		breaker.probing = false
+	}
+	if !failed {
+		if err == nil {
+			breaker.stats.State = breakerClosed
+			breaker.stats.ConsecutiveFailures = 0
+			breaker.cooldown = 0
+		}
+		return
+	}
+
+	now := b.now()
+	breaker.stats.Failures++
+	breaker.stats.ConsecutiveFailures++
+	breaker.stats.LastError = err.Error()
+	breaker.stats.LastFailureAt = &now
+	if probe || breaker.stats.ConsecutiveFailures >= agentBreakerThreshold {
+		switch {
+		case breaker.cooldown == 0:
+			breaker.cooldown = agentBreakerCooldown
+		case probe:
+			breaker.cooldown = min(2*breaker.cooldown, agentBreakerMaxCooldown)
+		}
+		breaker.stats.State = breakerOpen
+		breaker.openUntil = now.Add(breaker.cooldown)
+	}
+}
+
+func (b *agentBreakers) done(host string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if breaker, ok := b.hosts[host]; ok && breaker.stats.InFlight > 0 {
+		breaker.stats.InFlight--
+	}
+}
+
+// pruneLocked forgets healthy agents that have not been called for a while,
+// such as those of deleted VMs.
+func (b *agentBreakers) pruneLocked(now time.Time) {
+	if now.Sub(b.lastPrune) < agentBreakerIdle/2 {
+		return
+	}
+	b.lastPrune = now
+	for host, breaker := range b.hosts {
+		if breaker.stats.State == breakerClosed && breaker.stats.InFlight == 0 && now.Sub(breaker.lastUsed) > agentBreakerIdle {
+			delete(b.hosts, host)
+		}
+	}
+}
+
+// snapshot returns the stats of every known agent host, by host.
+func (b *agentBreakers) snapshot() map[string]agentStats {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	now := b.now()
+	out := make(map[string]agentStats, len(b.hosts))
+	for host, breaker := range b.hosts {
+		stats := breaker.stats
+		if stats.State == breakerOpen && now.Before(breaker.openUntil) {
+			stats.RetryAfterSeconds = int(math.Ceil(breaker.openUntil.Sub(now).Seconds()))
+		}
+		out[host] = stats
+	}
+	return out
+}
+
+// agentBody releases the call's slot once the response has been read.
+type agentBody struct {
+	io.ReadCloser
+	once sync.Once
+	done func()
+}
+
+func (b *agentBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.once.Do(b.done)
+	return err
+}
+
+// getAgentStats reports connection and circuit breaker state per VM agent.
+func (api *apiServer) getAgentStats(c *gin.Context) {
+	resp := agentStatsResponse{Agents: []agentStats{}}
+	if api.breakers == nil {
+		c.JSON(http.StatusOK, resp)
+		return
+	}
+	byHost := api.breakers.snapshot()
+	vms, err := api.engine.ListVMs(c.Request.Context())
+	if err != nil {
+		api.logger.Error("list vms", "error", err)
+		respondError(c, http.StatusInternalServerError, CodeInternal, "failed to list vms")
+		return
+	}
+	filter := queryValueSet(c, "vm", false)
+	for _, vm := range vms {
+		if filter != nil && !filter[vm.Name] {
+			continue
+		}
+		if !agentReachable(&vm) {
+			continue
+		}
+		stats, ok := byHost[agentHost(&vm, api.agentPort)]
+		if !ok {
+			continue
+		}
+		stats.VM = vm.Name
+		resp.Agents = append(resp.Agents, stats)
+	}
+	sort.Slice(resp.Agents, func(i, j int) bool { return resp.Agents[i].VM < resp.Agents[j].VM })
+	c.JSON(http.StatusOK, resp)
+}
@@ -0,0 +1,148 @@
+// Copyright (c) 2025 HYPR. PTE. LTD.
+//
+// Business Source License 1.1
+// See LICENSE file in the project root for details.
+
+package httpapi
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+// roundTripFunc answers agent calls in breaker tests.
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) { return f(req) }
+
+// breakerStep is one call through the breaker. call is ok, fail (the agent
+// cannot be reached), cancelled (the caller gave up), body (the caller's
+// body failed) or hold, which admits a call and leaves it in flight.
+type breakerStep struct {
+	advance  time.Duration
+	call     string
+	rejected bool
+}
+
+func repeatStep(n int, step breakerStep) []breakerStep {
+	steps := make([]breakerStep, n)
+	for i := range steps {
+		steps[i] = step
+	}
+	return steps
+}
+
+func TestAgentBreakers(t *testing.T) {
+	fail := breakerStep{call: "fail"}
+	open := repeatStep(agentBreakerThreshold, fail)
+	for _, tc := range []struct {
+		name         string
+		steps        []breakerStep
+		wantState    string
+		wantCooldown time.Duration
+	}{
+		{
+			name:      "stays closed below the threshold",
+			steps:     repeatStep(agentBreakerThreshold-1, fail),
+			wantState: breakerClosed,
+		},
+		{
+			name:         "opens after the threshold",
+			steps:        open,
+			wantState:    breakerOpen,
+			wantCooldown: agentBreakerCooldown,
+		},
+		{
+			name:      "success resets the failure count",
+			steps:     append(append(repeatStep(agentBreakerThreshold-1, fail), breakerStep{call: "ok"}), repeatStep(agentBreakerThreshold-1, fail)...),
+			wantState: breakerClosed,
+		},
+		{
+			name:         "rejects while open",
+			steps:        append(open, breakerStep{advance: agentBreakerCooldown - time.Second, call: "ok", rejected: true}),
+			wantState:    breakerOpen,
+			wantCooldown: agentBreakerCooldown,
+		},
+		{
+			name:         "lets exactly one probe through when half open",
+			steps:        append(open, breakerStep{advance: agentBreakerCooldown, call: "hold"}, breakerStep{call: "ok", rejected: true}),
+			wantState:    breakerHalfOpen,
+			wantCooldown: agentBreakerCooldown,
+		},
+		{
+			name:      "closes after a successful probe",
+			steps:     append(open, breakerStep{advance: agentBreakerCooldown, call: "ok"}, breakerStep{call: "ok"}),
+			wantState: breakerClosed,
+		},
+		{
+			name: "doubles the cooldown on a failed probe",
+			steps: append(open,
+				breakerStep{advance: agentBreakerCooldown, call: "fail"},
+				breakerStep{advance: 2*agentBreakerCooldown - time.Second, call: "ok", rejected: true},
+				breakerStep{advance: time.Second, call: "fail"},
+			),
+			wantState:    breakerOpen,
+			wantCooldown: 4 * agentBreakerCooldown,
+		},
+		{
+			name:      "ignores calls the caller cancelled",
+			steps:     repeatStep(2*agentBreakerThreshold, breakerStep{call: "cancelled"}),
+			wantState: breakerClosed,
+		},
+		{
+			name:      "ignores failures of the caller's body",
+			steps:     repeatStep(2*agentBreakerThreshold, breakerStep{call: "body"}),
+			wantState: breakerClosed,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			now := time.Unix(1700000000, 0)
+			breakers := newAgentBreakers(roundTripFunc(func(req *http.Request) (*http.Response, error) {
+				switch req.Header.Get("X-Step") {
+				case "fail":
+					return nil, errors.New("dial tcp: connection refused")
+				case "cancelled":
+					return nil, req.Context().Err()
+				case "body":
+					return nil, &callerBodyError{err: errors.New("unexpected EOF")}
+				}
+				return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader("ok"))}, nil
+			}))
+			breakers.now = func() time.Time { return now }
+
+			for i, step := range tc.steps {
+				now = now.Add(step.advance)
+				var err error
+				if step.call == "hold" {
+					_, err = breakers.acquire("10.0.0.2:8080")
+				} else {
+					ctx, cancel := context.WithCancel(context.Background())
+					if step.call == "cancelled" {
+						cancel()
+					}
+					req, _ := http.NewRequestWithContext(ctx, http.MethodGet, "http://10.0.0.2:8080/api/v1/health", nil)
+					req.Header.Set("X-Step", step.call)
+					var resp *http.Response
+					if resp, err = breakers.RoundTrip(req); err == nil {
+						_ = resp.Body.Close()
+					}
+					cancel()
+				}
+				var unavailable *agentUnavailableError
+				if rejected := errors.As(err, &unavailable); rejected != step.rejected {
+					t.Fatalf("step %d (%s): rejected %v, want %v (err %v)", i, step.call, rejected, step.rejected, err)
+				}
+			}
+
+			breaker := breakers.hosts["10.0.0.2:8080"]
+			if breaker.stats.State != tc.wantState || breaker.cooldown != tc.wantCooldown {
+				t.Fatalf("state %s cooldown %s, want %s %s", breaker.stats.State, breaker.cooldown, tc.wantState, tc.wantCooldown)
+			}
+		})
+	}
+}
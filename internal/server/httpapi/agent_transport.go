@@ -0,0 +1,220 @@
+// Copyright (c) 2025 HYPR. PTE. LTD.
+//
+// Business Source License 1.1
+// See LICENSE file in the project root for details.
+
+package httpapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mdlayher/vsock"
+
+	"github.com/volantvm/volant/internal/pluginspec"
+	"github.com/volantvm/volant/internal/server/db"
+	"github.com/volantvm/volant/internal/server/tracing"
+)
+
+// vsockHostSuffix marks synthetic agent hosts of the form "<cid>.vsock",
+// which dialAgent connects to over vsock instead of TCP.
+const vsockHostSuffix = ".vsock"
+
+// agentReachable reports whether the API has a path to the VM's agent: its IP
+// or, for vsock-mode VMs without one, its vsock context ID.
+func agentReachable(vm *db.VM) bool {
+	return strings.TrimSpace(vm.IPAddress) != "" || vm.VsockCID != 0
+}
+
+// agentHost returns the host:port used to reach a guest port.
+func agentHost(vm *db.VM, port int) string {
+	host := strings.TrimSpace(vm.IPAddress)
+	if host == "" && vm.VsockCID != 0 {
+		host = strconv.FormatUint(uint64(vm.VsockCID), 10) + vsockHostSuffix
+	}
+	return net.JoinHostPort(host, strconv.Itoa(port))
+}
+
+// dialAgent dials guest addresses, routing "<cid>.vsock" hosts over vsock to
+// the same port number.
+func dialAgent(ctx context.Context, network, addr string) (net.Conn, error) {
+	host, portStr, err := net.SplitHostPort(addr)
+	if err != nil {
+		return nil, err
+	}
+	cidStr, isVsock := strings.CutSuffix(host, vsockHostSuffix)
+	if !isVsock {
+		var dialer net.Dialer
+		return dialer.DialContext(ctx, network, addr)
+	}
+	cid, err := strconv.ParseUint(cidStr, 10, 32)
+	if err != nil {
+		return nil, fmt.Errorf("invalid vsock cid %q", cidStr)
+	}
+	port, err := strconv.ParseUint(portStr, 10, 32)
+	if err != nil {
+		return nil, fmt.Errorf("invalid vsock port %q", portStr)
+	}
+	conn, err := vsock.Dial(uint32(cid), uint32(port), nil)
+	if err != nil {
+		return nil, fmt.Errorf("vsock dial cid %d port %d: %w", cid, port, err)
+	}
+	return conn, nil
+}
+
+// agentProxy applies the environment's proxy settings to TCP agents only.
+func agentProxy(req *http.Request) (*url.URL, error) {
+	if strings.HasSuffix(req.URL.Hostname(), vsockHostSuffix) {
+		return nil, nil
+	}
+	return http.ProxyFromEnvironment(req)
+}
+
+// agentRequestTimeout bounds agent calls that have no timeout of their own.
+const agentRequestTimeout = 120 * time.Second
+
+// newAgentTransport returns the transport shared by agent clients. Each
+// agent host is a VM, so the per-host limits pool connections per VM.
+func newAgentTransport() *http.Transport {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.DialContext = dialAgent
+	transport.Proxy = agentProxy
+	transport.MaxConnsPerHost = agentMaxConnsPerVM
+	transport.MaxIdleConnsPerHost = agentMaxIdleConnsPerVM
+	return transport
+}
+
+func newAgentClient(transport http.RoundTripper) *http.Client {
+	return &http.Client{Timeout: agentRequestTimeout, Transport: tracing.Transport(transport)}
+}
+
+// newAgentLongClient returns a client for calls bounded by their context:
+// actions with their own timeout and streaming actions.
+func newAgentLongClient(transport http.RoundTripper) *http.Client {
+	return &http.Client{Transport: tracing.Transport(transport)}
+}
+
+// agentCallPolicy is how a plugin action's manifest bounds calls to an agent.
+// The zero policy is one attempt under agentRequestTimeout.
+type agentCallPolicy struct {
+	// timeout bounds each attempt; zero keeps the default.
+	timeout  time.Duration
+	attempts int
+	backoff  time.Duration
+}
+
+func actionCallPolicy(action pluginspec.Action) agentCallPolicy {
+	policy := agentCallPolicy{timeout: time.Duration(action.TimeoutMs) * time.Millisecond}
+	if action.Retry != nil {
+		policy.attempts = action.Retry.MaxAttempts
+		policy.backoff = time.Duration(action.Retry.BackoffMs) * time.Millisecond
+	}
+	return policy
+}
+
+// retryableAgentStatus reports whether an agent response is worth retrying:
+// the agent or workload could not be reached or timed out.
+func retryableAgentStatus(status int) bool {
+	switch status {
+	case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// doAgent sends the request built by newRequest under policy, rebuilding it
+// for each retry. Closing the returned body releases the attempt's deadline.
+// untimed drops the client timeout even without a policy timeout, for
+// streams that may run for as long as the caller stays.
+func (api *apiServer) doAgent(ctx context.Context, policy agentCallPolicy, untimed bool, newRequest func(context.Context) (*http.Request, error)) (*http.Response, error) {
+	client := api.agentClient
+	if policy.timeout > 0 || untimed {
+		client = api.agentLong
+	}
+	backoff := policy.backoff
+	for attempt := 1; ; attempt++ {
+		attemptCtx, cancel := ctx, context.CancelFunc(func() {})
+		if policy.timeout > 0 {
+			attemptCtx, cancel = context.WithTimeout(ctx, policy.timeout)
+		}
+		req, err := newRequest(attemptCtx)
+		if err != nil {
+			cancel()
+			return nil, err
+		}
+		resp, err := client.Do(req)
+		// An open breaker answers for the agent until its cooldown ends.
+		var unavailable *agentUnavailableError
+		if (err == nil && !retryableAgentStatus(resp.StatusCode)) || errors.As(err, &unavailable) || attempt >= policy.attempts || ctx.Err() != nil {
+			if err != nil {
+				cancel()
+				return nil, err
+			}
+			resp.Body = cancelOnClose{ReadCloser: resp.Body, cancel: cancel}
+			return resp, nil
+		}
+		if resp != nil {
+			_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+			_ = resp.Body.Close()
+			api.logger.Warn("agent call failed, retrying", "url", req.URL.String(), "attempt", attempt, "status", resp.StatusCode)
+		} else {
+			api.logger.Warn("agent call failed, retrying", "url", req.URL.String(), "attempt", attempt, "error", err)
+		}
+		cancel()
+		timer := time.NewTimer(backoff)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+		backoff *= 2
+	}
+}
+
+// cancelOnClose releases a context once the response body is done with.
+type cancelOnClose struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b cancelOnClose) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+	return err
+}
+
+// respondAgentCallError answers a failed agent call: 503 with Retry-After
+// while the agent's breaker is open, 504 when it timed out, 502 otherwise.
+func respondAgentCallError(c *gin.Context, err error) {
+	var unavailable *agentUnavailableError
+	if errors.As(err, &unavailable) {
+		c.Header("Retry-After", strconv.Itoa(int(math.Ceil(unavailable.retryAfter.Seconds()))))
+		respondError(c, http.StatusServiceUnavailable, CodeAgentUnavailable, unavailable.Error())
+		return
+	}
+	var netErr net.Error
+	if errors.Is(err, context.DeadlineExceeded) || (errors.As(err, &netErr) && netErr.Timeout()) {
+		respondError(c, http.StatusGatewayTimeout, CodeUpstreamTimeout, err.Error())
+		return
+	}
+	respondError(c, http.StatusBadGateway, CodeUpstreamError, err.Error())
+}
+
+// clearDeadlines lifts the server's read and write timeouts, which are sized
+// for ordinary requests, from a response bounded by a policy or the caller.
+func clearDeadlines(c *gin.Context) {
+	rc := http.NewResponseController(c.Writer)
+	_ = rc.SetReadDeadline(time.Time{})
+	_ = rc.SetWriteDeadline(time.Time{})
+}
@@ -0,0 +1,233 @@
+// Copyright (c) 2025 HYPR. PTE. LTD.
+//
+// Business Source License 1.1
+// See LICENSE file in the project root for details.
+
+package httpapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/volantvm/volant/internal/server/eventbus"
+	orchestratorevents "github.com/volantvm/volant/internal/server/orchestrator/events"
+)
+
+const (
+	// aguiBufferSize is how many recent events streams can resume from.
+	aguiBufferSize   = 512
+	aguiMaxBatch     = 256
+	aguiMaxBodyBytes = 1 << 20
+	aguiKeepAlive    = 15 * time.Second
+)
+
+// aguiHub numbers forwarded AG-UI events and keeps the most recent ones, so
+// a stream reconnecting with Last-Event-ID misses nothing still buffered.
+type aguiHub struct {
+	bus    eventbus.Bus
+	mu     sync.Mutex
+	seq    uint64
+	recent []orchestratorevents.AGUIEvent
+}
+
+// publish numbers events and publishes them in order.
+func (h *aguiHub) publish(c *gin.Context, vm string, events []json.RawMessage, types []string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for i, raw := range events {
+		h.seq++
+		event := orchestratorevents.AGUIEvent{Seq: h.seq, VM: vm, Type: types[i], Event: raw}
+		h.recent = append(h.recent, event)
+		if h.bus != nil {
+			_ = h.bus.Publish(c.Request.Context(), orchestratorevents.TopicAGUIEvents, event)
+		}
+	}
+	if len(h.recent) > aguiBufferSize {
+		h.recent = append([]orchestratorevents.AGUIEvent(nil), h.recent[len(h.recent)-aguiBufferSize:]...)
+	}
+}
+
+// since returns the buffered events numbered after seq, oldest first.
+func (h *aguiHub) since(seq uint64) []orchestratorevents.AGUIEvent {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	var out []orchestratorevents.AGUIEvent
+	for _, event := range h.recent {
+		if event.Seq > seq {
+			out = append(out, event)
+		}
+	}
+	return out
+}
+
+// postVMAGUIEvents accepts AG-UI events a VM's guest forwards, as one JSON
+// object or an array of them.
+func (api *apiServer) postVMAGUIEvents(c *gin.Context) {
+	name := c.Param("name")
+	vm, err := api.engine.GetVM(c.Request.Context(), name)
+	if err != nil {
+		api.logger.Error("resolve vm", "vm", name, "error", err)
+		respondError(c, http.StatusInternalServerError, CodeInternal, "failed to resolve vm")
+		return
+	}
+	if vm == nil {
+		respondError(c, http.StatusNotFound, CodeVMNotFound, "vm not found")
+		return
+	}
+	body, err := io.ReadAll(io.LimitReader(c.Request.Body, aguiMaxBodyBytes+1))
+	if err != nil {
+		respondError(c, http.StatusBadRequest, CodeInvalidRequest, err.Error())
+		return
+	}
+	if len(body) > aguiMaxBodyBytes {
+		respondError(c, http.StatusRequestEntityTooLarge, CodeInvalidRequest, fmt.Sprintf("body exceeds %d bytes", aguiMaxBodyBytes))
+		return
+	}
+
+	var objects []map[string]json.RawMessage
+	if trimmed := bytes.TrimSpace(body); len(trimmed) > 0 && trimmed[0] == '[' {
+		err = json.Unmarshal(trimmed, &objects)
+	} else {
+		var object map[string]json.RawMessage
+		err = json.Unmarshal(body, &object)
+		objects = append(objects, object)
+	}
+	if err != nil {
+		respondError(c, http.StatusBadRequest, CodeInvalidRequest, "events must be a JSON object or array of objects: "+err.Error())
+		return
+	}
+	if len(objects) > aguiMaxBatch {
+		respondError(c, http.StatusBadRequest, CodeInvalidRequest, fmt.Sprintf("at most %d events per request", aguiMaxBatch))
+		return
+	}
+
+	vmName, _ := json.Marshal(vm.Name)
+	now, _ := json.Marshal(time.Now().UnixMilli())
+	events := make([]json.RawMessage, 0, len(objects))
+	types := make([]string, 0, len(objects))
+	for i, object := range objects {
+		var eventType string
+		if object != nil {
+			_ = json.Unmarshal(object["type"], &eventType)
+		}
+		if strings.TrimSpace(eventType) == "" {
+			respondError(c, http.StatusBadRequest, CodeInvalidRequest, fmt.Sprintf("event %d: type is required", i))
+			return
+		}
+		// vm tells multiplexed streams apart; AG-UI timestamps are epoch ms.
+		object["vm"] = vmName
+		if _, ok := object["timestamp"]; !ok {
+			object["timestamp"] = now
+		}
+		data, err := json.Marshal(object)
+		if err != nil {
+			respondError(c, http.StatusBadRequest, CodeInvalidRequest, err.Error())
+			return
+		}
+		events = append(events, data)
+		types = append(types, eventType)
+	}
+	api.agui.publish(c, vm.Name, events, types)
+	c.JSON(http.StatusAccepted, gin.H{"accepted": len(events)})
+}
+
+// streamAGUIEvents multiplexes the AG-UI events of every VM, or those picked
+// with ?vm=, over SSE. Each data line is an AG-UI event carrying vm.
+func (api *apiServer) streamAGUIEvents(c *gin.Context) {
+	if api.bus == nil {
+		respondError(c, http.StatusServiceUnavailable, CodeUnavailable, "event streaming not available")
+		return
+	}
+	flusher, ok := c.Writer.(http.Flusher)
+	if !ok {
+		respondError(c, http.StatusInternalServerError, CodeInternal, "streaming unsupported")
+		return
+	}
+
+	vms := queryValueSet(c, "vm", false)
+	types := queryValueSet(c, "type", true)
+	lastID := c.GetHeader("Last-Event-ID")
+	if lastID == "" {
+		lastID = c.Query("last_event_id")
+	}
+	var last uint64
+	resume := false
+	if lastID != "" {
+		n, err := strconv.ParseUint(strings.TrimSpace(lastID), 10, 64)
+		if err != nil {
+			respondError(c, http.StatusBadRequest, CodeInvalidRequest, "invalid Last-Event-ID")
+			return
+		}
+		last, resume = n, true
+	}
+
+	ctx := c.Request.Context()
+	eventsCh := make(chan any, 256)
+	unsubscribe, err := api.bus.Subscribe(orchestratorevents.TopicAGUIEvents, eventsCh)
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, CodeInternal, "failed to subscribe")
+		return
+	}
+	defer unsubscribe()
+
+	c.Writer.Header().Set("Content-Type", "text/event-stream")
+	c.Writer.Header().Set("Cache-Control", "no-cache")
+	c.Writer.Header().Set("Connection", "keep-alive")
+	clearDeadlines(c)
+	c.Writer.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	send := func(event orchestratorevents.AGUIEvent) bool {
+		if event.Seq <= last {
+			return true
+		}
+		last = event.Seq
+		if (vms != nil && !vms[event.VM]) || (types != nil && !types[strings.ToUpper(event.Type)]) {
+			return true
+		}
+		if _, err := fmt.Fprintf(c.Writer, "id: %d\ndata: %s\n\n", event.Seq, event.Event); err != nil {
+			return false
+		}
+		flusher.Flush()
+		return true
+	}
+
+	if resume {
+		for _, event := range api.agui.since(last) {
+			if !send(event) {
+				return
+			}
+		}
+	}
+
+	keepAlive := time.NewTicker(aguiKeepAlive)
+	defer keepAlive.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-keepAlive.C:
+			if _, err := c.Writer.Write([]byte(": keepalive\n\n")); err != nil {
+				return
+			}
+			flusher.Flush()
+		case payload := <-eventsCh:
+			event, ok := payload.(orchestratorevents.AGUIEvent)
+			if !ok {
+				continue
+			}
+			if !send(event) {
+				return
+			}
+		}
+	}
+}
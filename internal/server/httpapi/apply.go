@@ -0,0 +1,653 @@
+// Copyright (c) 2025 HYPR. PTE. LTD.
+//
+// Business Source License 1.1
+// See LICENSE file in the project root for details.
+
+package httpapi
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/volantvm/volant/internal/pluginspec"
+	"github.com/volantvm/volant/internal/server/admission"
+	"github.com/volantvm/volant/internal/server/orchestrator"
+	orchestratorevents "github.com/volantvm/volant/internal/server/orchestrator/events"
+	"github.com/volantvm/volant/internal/server/orchestrator/vmconfig"
+)
+
+// maxApplyResources bounds one apply request.
+const maxApplyResources = 64
+
+// Resource kinds accepted by apply, in the order they are applied: plugins
+// come first so VMs and deployments in the same request can use them.
+const (
+	applyKindPlugin     = "plugin"
+	applyKindVM         = "vm"
+	applyKindDeployment = "deployment"
+)
+
+// What apply does, or would do, to a resource.
+const (
+	applyActionCreate    = "create"
+	applyActionUpdate    = "update"
+	applyActionUnchanged = "unchanged"
+)
+
+// Resource outcomes.
+const (
+	applyPlanned = "planned"
+	applyApplied = "applied"
+	applyFailed  = "failed"
+	applySkipped = "skipped"
+)
+
+// immutableVMFields are config fields an existing VM cannot change; the VM
+// has to be deleted and created again.
+var immutableVMFields = map[string]bool{
+	"plugin":        true,
+	"bundles":       true,
+	"spread":        true,
+	"upgraded_from": true,
+}
+
+type applyRequest struct {
+	Resources []applyResource `json:"resources" binding:"required"`
+	// DryRun reports what would change without changing anything.
+	DryRun bool `json:"dry_run,omitempty"`
+}
+
+// applyResource is one declared resource. Spec is a plugin manifest for
+// plugins, and {config, labels, restart} for VMs and {replicas, max_surge,
+// max_unavailable, config} for deployments.
+type applyResource struct {
+	Kind string          `json:"kind" binding:"required"`
+	Name string          `json:"name" binding:"required"`
+	Spec json.RawMessage `json:"spec" binding:"required"`
+}
+
+type applyChange struct {
+	Field string `json:"field"`
+	From  any    `json:"from,omitempty"`
+	To    any    `json:"to,omitempty"`
+}
+
+type applyResourceResult struct {
+	Index   int           `json:"index"`
+	Kind    string        `json:"kind"`
+	Name    string        `json:"name"`
+	Action  string        `json:"action,omitempty"`
+	Changes []applyChange `json:"changes,omitempty"`
+	Status  string        `json:"status"`
+	Error   string        `json:"error,omitempty"`
+	Code    string        `json:"code,omitempty"`
+}
+
+type applyResponse struct {
+	DryRun    bool                  `json:"dry_run"`
+	Resources []applyResourceResult `json:"resources"`
+}
+
+type applyVMSpec struct {
+	Config vmconfig.Config `json:"config"`
+	// Labels are merged into the VM's labels; labels not listed are kept.
+	Labels map[string]string `json:"labels,omitempty"`
+	// Restart restarts a running VM so a config change takes effect.
+	Restart bool `json:"restart,omitempty"`
+}
+
+type applyDeploymentSpec struct {
+	Replicas       *int            `json:"replicas,omitempty"`
+	MaxSurge       *int            `json:"max_surge,omitempty"`
+	MaxUnavailable *int            `json:"max_unavailable,omitempty"`
+	Config         vmconfig.Config `json:"config"`
+}
+
+// applyPlan is what applying one resource takes. apply is nil when the
+// resource is unchanged.
+type applyPlan struct {
+	action  string
+	changes []applyChange
+	apply   func(ctx context.Context) error
+}
+
+// applyResources makes the server match the declared resources: each is
+// looked up by kind and name, created when missing and updated when its
+// declared fields differ. Fields a document leaves out keep their current
+// values, so applying the same documents again changes nothing.
+//
+// Every resource is planned before anything is changed; an invalid document
+// fails the request untouched. Resources are then applied in order, stopping
+// at the first failure. Unlike a transaction nothing is rolled back: applying
+// the documents again resumes where the failure left off.
+func (api *apiServer) applyResources(c *gin.Context) {
+	var req applyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, http.StatusBadRequest, CodeInvalidRequest, err.Error())
+		return
+	}
+	if len(req.Resources) == 0 {
+		respondError(c, http.StatusBadRequest, CodeInvalidRequest, "no resources to apply")
+		return
+	}
+	if len(req.Resources) > maxApplyResources {
+		respondError(c, http.StatusBadRequest, CodeInvalidRequest, fmt.Sprintf("%d resources given; at most %d allowed", len(req.Resources), maxApplyResources))
+		return
+	}
+	seen := make(map[string]int, len(req.Resources))
+	for i := range req.Resources {
+		res := &req.Resources[i]
+		res.Kind = strings.ToLower(strings.TrimSpace(res.Kind))
+		res.Name = strings.TrimSpace(res.Name)
+		key := res.Kind + "/" + res.Name
+		if first, ok := seen[key]; ok {
+			respondError(c, http.StatusBadRequest, CodeInvalidRequest, fmt.Sprintf("resources %d and %d both declare %s %s", first, i, res.Kind, res.Name))
+			return
+		}
+		seen[key] = i
+	}
+
+	api.txMu.Lock()
+	defer api.txMu.Unlock()
+
+	ctx := c.Request.Context()
+	order := applyOrder(req.Resources)
+	results := make([]applyResourceResult, len(req.Resources))
+	plans := make([]applyPlan, len(req.Resources))
+	var failure error
+	for _, i := range order {
+		res := req.Resources[i]
+		results[i] = applyResourceResult{Index: i, Kind: res.Kind, Name: res.Name, Status: applyPlanned}
+		plan, err := api.planResource(ctx, res)
+		if err != nil {
+			results[i].Status = applyFailed
+			results[i].Error = err.Error()
+			results[i].Code = codeFromError(err)
+			if failure == nil {
+				failure = err
+			}
+			continue
+		}
+		plans[i] = plan
+		results[i].Action = plan.action
+		results[i].Changes = plan.changes
+	}
+	if failure != nil || req.DryRun {
+		status := http.StatusOK
+		if failure != nil {
+			status = statusFromError(failure)
+		}
+		if failure != nil && !req.DryRun {
+			for i := range results {
+				if results[i].Status == applyPlanned {
+					results[i].Status = applySkipped
+				}
+			}
+		}
+		c.JSON(status, applyResponse{DryRun: req.DryRun, Resources: results})
+		return
+	}
+
+	for _, i := range order {
+		if failure != nil {
+			results[i].Status = applySkipped
+			continue
+		}
+		if plans[i].apply == nil {
+			results[i].Status = applyApplied
+			continue
+		}
+		if err := plans[i].apply(ctx); err != nil {
+			api.logger.Warn("apply resource failed", "kind", results[i].Kind, "name", results[i].Name, "error", err)
+			results[i].Status = applyFailed
+			results[i].Error = err.Error()
+			results[i].Code = codeFromError(err)
+			failure = err
+			continue
+		}
+		results[i].Status = applyApplied
+	}
+	status := http.StatusOK
+	if failure != nil {
+		status = statusFromError(failure)
+	}
+	c.JSON(status, applyResponse{Resources: results})
+}
+
+// applyOrder returns the indexes of resources with plugins first, otherwise
+// keeping the order of the request.
+func applyOrder(resources []applyResource) []int {
+	order := make([]int, len(resources))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		return resources[order[a]].Kind == applyKindPlugin && resources[order[b]].Kind != applyKindPlugin
+	})
+	return order
+}
+
+func (api *apiServer) planResource(ctx context.Context, res applyResource) (applyPlan, error) {
+	if res.Name == "" {
+		return applyPlan{}, invalidApply("name is required")
+	}
+	switch res.Kind {
+	case applyKindPlugin:
+		return api.planPlugin(res)
+	case applyKindVM:
+		return api.planVM(ctx, res)
+	case applyKindDeployment:
+		return api.planDeployment(ctx, res)
+	case "":
+		return applyPlan{}, invalidApply("kind is required")
+	default:
+		return applyPlan{}, invalidApply(fmt.Sprintf("unsupported kind %q; expected %s, %s or %s", res.Kind, applyKindPlugin, applyKindVM, applyKindDeployment))
+	}
+}
+
+func (api *apiServer) planPlugin(res applyResource) (applyPlan, error) {
+	if api.plugins == nil {
+		return applyPlan{}, errors.New("plugin registry unavailable")
+	}
+	// Manifests are decoded as the install endpoint decodes them, so a
+	// manifest file with its $schema applies unchanged.
+	var manifest pluginspec.Manifest
+	if err := json.Unmarshal(res.Spec, &manifest); err != nil {
+		return applyPlan{}, invalidApply("spec: " + err.Error())
+	}
+	if manifest.Name == "" {
+		manifest.Name = res.Name
+	} else if manifest.Name != res.Name {
+		return applyPlan{}, invalidApply(fmt.Sprintf("spec names plugin %q", manifest.Name))
+	}
+	manifest.Normalize()
+	if err := manifest.Validate(); err != nil {
+		return applyPlan{}, invalidApply(err.Error())
+	}
+	// Installing enables the plugin, as the install endpoint does.
+	install := manifest
+	install.Enabled = true
+	apply := func(ctx context.Context) error {
+		_, _, err := api.txInstallPlugin(ctx, install)
+		return err
+	}
+
+	current, ok := api.plugins.Get(manifest.Name)
+	if !ok {
+		return applyPlan{action: applyActionCreate, apply: apply}, nil
+	}
+	// Whether the plugin is enabled is not part of what a manifest declares.
+	manifest.Enabled = current.Enabled
+	desiredValue, err := jsonValue(manifest)
+	if err != nil {
+		return applyPlan{}, err
+	}
+	currentValue, err := jsonValue(current)
+	if err != nil {
+		return applyPlan{}, err
+	}
+	changes := diffValues("", desiredValue, currentValue, nil)
+	if len(changes) == 0 {
+		return applyPlan{action: applyActionUnchanged}, nil
+	}
+	return applyPlan{action: applyActionUpdate, changes: changes, apply: apply}, nil
+}
+
+func (api *apiServer) planVM(ctx context.Context, res applyResource) (applyPlan, error) {
+	var spec applyVMSpec
+	if err := decodeApplySpec(res.Spec, &spec); err != nil {
+		return applyPlan{}, err
+	}
+	rawConfig, err := rawConfigFields(res.Spec)
+	if err != nil {
+		return applyPlan{}, err
+	}
+	vm, err := api.engine.GetVM(ctx, res.Name)
+	if err != nil {
+		return applyPlan{}, err
+	}
+
+	if vm == nil {
+		if strings.TrimSpace(spec.Config.Plugin) == "" {
+			return applyPlan{}, invalidApply("config.plugin is required to create a VM")
+		}
+		createReq := createVMRequest{Name: res.Name, Config: &spec.Config, Labels: spec.Labels}
+		return applyPlan{action: applyActionCreate, apply: func(ctx context.Context) error {
+			if err := api.checkAdmission(ctx, admission.OpVMCreate, res.Name, &createReq); err != nil {
+				return err
+			}
+			prepared, err := api.prepareCreateVM(createReq)
+			if err != nil {
+				return err
+			}
+			created, err := api.engine.CreateVM(ctx, prepared)
+			if err != nil {
+				return err
+			}
+			api.bus.Publish(ctx, orchestratorevents.TopicVMEvents, orchestratorevents.VMEvent{
+				Type:      orchestratorevents.TypeVMCreated,
+				Name:      created.Name,
+				Timestamp: time.Now().UTC(),
+				Message:   "VM created",
+			})
+			return nil
+		}}, nil
+	}
+
+	current, err := api.engine.GetVMConfig(ctx, res.Name)
+	if err != nil {
+		return applyPlan{}, err
+	}
+	if current == nil {
+		return applyPlan{}, fmt.Errorf("vm %s has no config", res.Name)
+	}
+	desired, err := overlayConfig(current.Config, rawConfig)
+	if err != nil {
+		return applyPlan{}, err
+	}
+	changes, err := diffConfigs(desired, current.Config)
+	if err != nil {
+		return applyPlan{}, err
+	}
+	// Only declared fields can differ, so the patch carries them as written.
+	patchFields := make(map[string]json.RawMessage)
+	for _, change := range changes {
+		field, _, _ := strings.Cut(strings.TrimPrefix(change.Field, "config."), ".")
+		if immutableVMFields[field] {
+			return applyPlan{}, invalidApply(fmt.Sprintf("config.%s of an existing VM cannot be changed; delete the VM and apply again", field))
+		}
+		patchFields[field] = rawConfig[field]
+	}
+	var patch vmconfig.Patch
+	if len(patchFields) > 0 {
+		data, err := json.Marshal(patchFields)
+		if err != nil {
+			return applyPlan{}, err
+		}
+		if err := json.Unmarshal(data, &patch); err != nil {
+			return applyPlan{}, err
+		}
+	}
+
+	labelPatch := make(map[string]*string)
+	for _, key := range sortedKeys(spec.Labels) {
+		value := spec.Labels[key]
+		if currentValue, ok := vm.Labels[key]; ok && currentValue == value {
+			continue
+		}
+		labelPatch[key] = &value
+		change := applyChange{Field: "labels." + key, To: value}
+		if currentValue, ok := vm.Labels[key]; ok {
+			change.From = currentValue
+		}
+		changes = append(changes, change)
+	}
+
+	if len(changes) == 0 {
+		return applyPlan{action: applyActionUnchanged}, nil
+	}
+	return applyPlan{action: applyActionUpdate, changes: changes, apply: func(ctx context.Context) error {
+		if len(patchFields) > 0 {
+			if err := api.checkAdmission(ctx, admission.OpVMConfigUpdate, res.Name, &patch); err != nil {
+				return err
+			}
+			if _, err := api.engine.ApplyVMConfig(ctx, res.Name, patch, orchestrator.VMConfigUpdateOptions{Restart: spec.Restart}); err != nil {
+				return err
+			}
+		}
+		if len(labelPatch) > 0 {
+			if _, err := api.engine.UpdateVMLabels(ctx, res.Name, labelPatch); err != nil {
+				return err
+			}
+		}
+		return nil
+	}}, nil
+}
+
+func (api *apiServer) planDeployment(ctx context.Context, res applyResource) (applyPlan, error) {
+	var spec applyDeploymentSpec
+	if err := decodeApplySpec(res.Spec, &spec); err != nil {
+		return applyPlan{}, err
+	}
+	rawConfig, err := rawConfigFields(res.Spec)
+	if err != nil {
+		return applyPlan{}, err
+	}
+	current, err := api.engine.GetDeployment(ctx, res.Name)
+	if err != nil && !errors.Is(err, orchestrator.ErrDeploymentNotFound) {
+		return applyPlan{}, err
+	}
+
+	if current == nil {
+		createReq := createDeploymentRequest{Name: res.Name, Replicas: 1, Config: spec.Config}
+		if spec.Replicas != nil {
+			createReq.Replicas = *spec.Replicas
+		}
+		if spec.MaxSurge != nil {
+			createReq.MaxSurge = *spec.MaxSurge
+		}
+		return applyPlan{action: applyActionCreate, apply: func(ctx context.Context) error {
+			if err := api.checkAdmission(ctx, admission.OpDeploymentCreate, res.Name, &createReq); err != nil {
+				return err
+			}
+			_, err := api.engine.CreateDeployment(ctx, orchestrator.CreateDeploymentRequest{
+				Name:     createReq.Name,
+				Replicas: createReq.Replicas,
+				MaxSurge: createReq.MaxSurge,
+				Config:   createReq.Config,
+			})
+			return err
+		}}, nil
+	}
+
+	var changes []applyChange
+	scale := spec.Replicas != nil && *spec.Replicas != current.DesiredReplicas
+	if scale {
+		changes = append(changes, applyChange{Field: "replicas", From: current.DesiredReplicas, To: *spec.Replicas})
+	}
+	desired, err := overlayConfig(current.Config, rawConfig)
+	if err != nil {
+		return applyPlan{}, err
+	}
+	configChanges, err := diffConfigs(desired, current.Config)
+	if err != nil {
+		return applyPlan{}, err
+	}
+	changes = append(changes, configChanges...)
+	if len(changes) == 0 {
+		return applyPlan{action: applyActionUnchanged}, nil
+	}
+
+	update := orchestrator.UpdateDeploymentConfigRequest{Config: desired, MaxSurge: 1}
+	if spec.MaxSurge != nil {
+		update.MaxSurge = *spec.MaxSurge
+	}
+	if spec.MaxUnavailable != nil {
+		update.MaxUnavailable = *spec.MaxUnavailable
+	}
+	return applyPlan{action: applyActionUpdate, changes: changes, apply: func(ctx context.Context) error {
+		if scale {
+			patch := patchDeploymentRequest{Replicas: spec.Replicas}
+			if err := api.checkAdmission(ctx, admission.OpDeploymentScale, res.Name, &patch); err != nil {
+				return err
+			}
+			if _, err := api.engine.ScaleDeployment(ctx, res.Name, *spec.Replicas); err != nil {
+				return err
+			}
+		}
+		if len(configChanges) > 0 {
+			body := updateDeploymentConfigRequest{Config: update.Config, MaxSurge: &update.MaxSurge, MaxUnavailable: &update.MaxUnavailable}
+			if err := api.checkAdmission(ctx, admission.OpDeploymentUpdate, res.Name, &body); err != nil {
+				return err
+			}
+			if _, err := api.engine.UpdateDeploymentConfig(ctx, res.Name, update); err != nil {
+				return err
+			}
+		}
+		return nil
+	}}, nil
+}
+
+func invalidApply(message string) error {
+	return newRequestError(http.StatusBadRequest, CodeInvalidRequest, message)
+}
+
+// decodeApplySpec decodes spec into v, rejecting unknown fields: a misspelt
+// field would otherwise be ignored on every apply without a word.
+func decodeApplySpec(spec json.RawMessage, v any) error {
+	decoder := json.NewDecoder(bytes.NewReader(spec))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(v); err != nil {
+		return invalidApply("spec: " + err.Error())
+	}
+	return nil
+}
+
+// rawConfigFields returns the config fields declared in a VM or deployment
+// spec, as written.
+func rawConfigFields(spec json.RawMessage) (map[string]json.RawMessage, error) {
+	var fields struct {
+		Config map[string]json.RawMessage `json:"config"`
+	}
+	if err := json.Unmarshal(spec, &fields); err != nil {
+		return nil, invalidApply("spec: " + err.Error())
+	}
+	return fields.Config, nil
+}
+
+// overlayConfig returns current with the declared fields replaced. Resources
+// and api are merged field by field, as a config patch merges them; every
+// other declared field replaces the current value whole.
+func overlayConfig(current vmconfig.Config, declared map[string]json.RawMessage) (vmconfig.Config, error) {
+	data, err := json.Marshal(current)
+	if err != nil {
+		return vmconfig.Config{}, err
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return vmconfig.Config{}, err
+	}
+	for key, value := range declared {
+		if key != "resources" && key != "api" {
+			fields[key] = value
+		}
+	}
+	if data, err = json.Marshal(fields); err != nil {
+		return vmconfig.Config{}, err
+	}
+	var merged vmconfig.Config
+	if err := json.Unmarshal(data, &merged); err != nil {
+		return vmconfig.Config{}, invalidApply("config: " + err.Error())
+	}
+	if value, ok := declared["resources"]; ok {
+		if err := json.Unmarshal(value, &merged.Resources); err != nil {
+			return vmconfig.Config{}, invalidApply("config.resources: " + err.Error())
+		}
+	}
+	if value, ok := declared["api"]; ok {
+		if err := json.Unmarshal(value, &merged.API); err != nil {
+			return vmconfig.Config{}, invalidApply("config.api: " + err.Error())
+		}
+	}
+	return merged, nil
+}
+
+func diffConfigs(desired, current vmconfig.Config) ([]applyChange, error) {
+	desiredValue, err := jsonValue(desired)
+	if err != nil {
+		return nil, err
+	}
+	currentValue, err := jsonValue(current)
+	if err != nil {
+		return nil, err
+	}
+	return diffValues("config", desiredValue, currentValue, nil), nil
+}
+
+// diffValues appends a change for every leaf where desired and current
+// differ, descending into objects. Empty values are equal, since an omitted
+// field and its zero value mean the same.
+func diffValues(field string, desired, current any, changes []applyChange) []applyChange {
+	desiredMap, desiredIsMap := desired.(map[string]any)
+	currentMap, currentIsMap := current.(map[string]any)
+	if desiredIsMap && currentIsMap {
+		keys := sortedKeys(desiredMap)
+		for _, key := range sortedKeys(currentMap) {
+			if _, ok := desiredMap[key]; !ok {
+				keys = append(keys, key)
+			}
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			changes = diffValues(joinField(field, key), desiredMap[key], currentMap[key], changes)
+		}
+		return changes
+	}
+	if (isEmptyValue(desired) && isEmptyValue(current)) || reflect.DeepEqual(desired, current) {
+		return changes
+	}
+	return append(changes, applyChange{Field: field, From: current, To: desired})
+}
+
+func joinField(prefix, key string) string {
+	if prefix == "" {
+		return key
+	}
+	return prefix + "." + key
+}
+
+func isEmptyValue(v any) bool {
+	switch v := v.(type) {
+	case nil:
+		return true
+	case string:
+		return v == ""
+	case bool:
+		return !v
+	case float64:
+		return v == 0
+	case []any:
+		return len(v) == 0
+	case map[string]any:
+		for _, value := range v {
+			if !isEmptyValue(value) {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
+
+// jsonValue returns v as decoded JSON, so values of different Go types can
+// be compared field by field.
+func jsonValue(v any) (any, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var value any
+	if err := json.Unmarshal(data, &value); err != nil {
+		return nil, err
+	}
+	return value, nil
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,192 @@
+// Copyright (c) 2025 HYPR. PTE. LTD.
+//
+// Business Source License 1.1
+// See LICENSE file in the project root for details.
+
+package httpapi
+
+import (
+	"context"
+	"crypto/subtle"
+	"log/slog"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/volantvm/volant/internal/server/db"
+)
+
+// Audit actions recorded for console sessions, history downloads, commands
+// run in guests, agent binary uploads, secret changes and SSH tunnels.
+const (
+	auditConsoleOpen   = "console.open"
+	auditConsoleClose  = "console.close"
+	auditConsoleDenied = "console.denied"
+	auditConsoleLog    = "console.log"
+	auditExec          = "exec"
+	auditAgentUpload   = "agent.upload"
+	auditSecretPut     = "secret.put"
+	auditSecretDelete  = "secret.delete"
+	auditSSHOpen       = "ssh.open"
+	auditSSHClose      = "ssh.close"
+)
+
+// anonymousConsoleUser identifies console callers when no console keys are
+// configured, and exec, agent upload and secret callers, which hold no named
+// key.
+const anonymousConsoleUser = "anonymous"
+
+type consoleKey struct {
+	user string
+	key  string
+}
+
+// consoleAccess is the daemon-wide console policy. Console keys grant the
+// console permission separately from VOLANT_API_KEY and name the user that
+// holds them, so sessions can be attributed in the audit trail.
+type consoleAccess struct {
+	disabled bool
+	keys     []consoleKey
+}
+
+// consoleAccessFromEnv reads VOLANT_CONSOLE_DISABLED and VOLANT_CONSOLE_KEYS
+// (comma-separated user:key pairs).
+func consoleAccessFromEnv(logger *slog.Logger) consoleAccess {
+	var access consoleAccess
+	if raw := strings.TrimSpace(os.Getenv("VOLANT_CONSOLE_DISABLED")); raw != "" {
+		disabled, err := strconv.ParseBool(raw)
+		if err != nil {
+			// Fail closed on a malformed compliance switch.
+			logger.Warn("invalid VOLANT_CONSOLE_DISABLED, disabling console access", "value", raw)
+			disabled = true
+		}
+		access.disabled = disabled
+	}
+	for _, entry := range strings.Split(os.Getenv("VOLANT_CONSOLE_KEYS"), ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		user, key, ok := strings.Cut(entry, ":")
+		user, key = strings.TrimSpace(user), strings.TrimSpace(key)
+		if !ok || user == "" || key == "" {
+			logger.Warn("ignoring malformed console key entry; expected user:key")
+			continue
+		}
+		access.keys = append(access.keys, consoleKey{user: user, key: key})
+	}
+	return access
+}
+
+// userForKey returns the user holding key.
+func (a consoleAccess) userForKey(key string) (string, bool) {
+	if key == "" {
+		return "", false
+	}
+	for _, entry := range a.keys {
+		if subtle.ConstantTimeCompare([]byte(entry.key), []byte(key)) == 1 {
+			return entry.user, true
+		}
+	}
+	return "", false
+}
+
+// authorizeConsole applies the daemon and per-VM console policy and returns
+// the caller's console identity. Denials are answered and audited here.
+func (api *apiServer) authorizeConsole(c *gin.Context, vm *db.VM) (string, bool) {
+	deny := func(status int, actor, reason string) (string, bool) {
+		api.recordAudit(c.Request.Context(), db.AuditEvent{
+			Action:   auditConsoleDenied,
+			Target:   vm.Name,
+			Actor:    actor,
+			ClientIP: c.ClientIP(),
+			Message:  reason,
+		})
+		respondError(c, status, codeForStatus(status), reason)
+		return "", false
+	}
+
+	if api.console.disabled {
+		return deny(http.StatusForbidden, anonymousConsoleUser, "console access disabled")
+	}
+
+	user := anonymousConsoleUser
+	if len(api.console.keys) > 0 {
+		provided := c.GetHeader("X-Volant-Console-Key")
+		if provided == "" {
+			provided = c.Query("console_key")
+		}
+		matched, ok := api.console.userForKey(provided)
+		if !ok {
+			return deny(http.StatusForbidden, anonymousConsoleUser, "console permission required")
+		}
+		user = matched
+	}
+
+	cfg, err := api.engine.GetVMConfig(c.Request.Context(), vm.Name)
+	if err != nil {
+		api.logger.Error("console vm config", "vm", vm.Name, "error", err)
+		c.JSON(statusFromError(err), errorResponse(err))
+		return "", false
+	}
+	if !cfg.Config.Console.AllowsUser(user) {
+		return deny(http.StatusForbidden, user, "console access denied for vm")
+	}
+	return user, true
+}
+
+// recordAudit appends to the audit trail; failures are logged, never surfaced
+// to the caller.
+func (api *apiServer) recordAudit(ctx context.Context, event db.AuditEvent) {
+	store := api.engine.Store()
+	if store == nil {
+		return
+	}
+	if _, err := store.Queries().AuditEvents().Record(ctx, event); err != nil {
+		api.logger.Error("record audit event", "action", event.Action, "target", event.Target, "error", err)
+	}
+}
+
+type auditEventResponse struct {
+	ID         int64     `json:"id"`
+	Action     string    `json:"action"`
+	Target     string    `json:"target"`
+	Actor      string    `json:"actor"`
+	ClientIP   string    `json:"client_ip,omitempty"`
+	Message    string    `json:"message,omitempty"`
+	DurationMS int64     `json:"duration_ms,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+func auditEventToResponse(event db.AuditEvent) auditEventResponse {
+	return auditEventResponse{
+		ID:         event.ID,
+		Action:     event.Action,
+		Target:     event.Target,
+		Actor:      event.Actor,
+		ClientIP:   event.ClientIP,
+		Message:    event.Message,
+		DurationMS: event.Duration.Milliseconds(),
+		CreatedAt:  event.CreatedAt,
+	}
+}
+
+func (api *apiServer) listAuditEvents(c *gin.Context) {
+	limit := queryCount(c, "limit")
+	target := strings.TrimSpace(c.Query("target"))
+	events, err := api.engine.Store().Queries().AuditEvents().List(c.Request.Context(), target, limit)
+	if err != nil {
+		api.logger.Error("list audit events", "error", err)
+		c.JSON(statusFromError(err), errorResponse(err))
+		return
+	}
+	resp := make([]auditEventResponse, 0, len(events))
+	for _, event := range events {
+		resp = append(resp, auditEventToResponse(event))
+	}
+	c.JSON(http.StatusOK, resp)
+}
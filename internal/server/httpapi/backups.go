@@ -0,0 +1,89 @@
+// Copyright (c) 2025 HYPR. PTE. LTD.
+//
+// Business Source License 1.1
+// See LICENSE file in the project root for details.
+
+package httpapi
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type backupResponse struct {
+	Name      string    `json:"name"`
+	Size      int64     `json:"size"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type restoreBackupRequest struct {
+	Backup string `json:"backup" binding:"required"`
+}
+
+type restoreBackupResponse struct {
+	Restored backupResponse `json:"restored"`
+	Safety   backupResponse `json:"safety"`
+	Message  string         `json:"message"`
+}
+
+func (api *apiServer) ensureBackupsAvailable(c *gin.Context) bool {
+	if api.backups == nil {
+		respondError(c, http.StatusServiceUnavailable, CodeUnavailable, "backups unavailable")
+		return false
+	}
+	return true
+}
+
+func (api *apiServer) listBackups(c *gin.Context) {
+	if !api.ensureBackupsAvailable(c) {
+		return
+	}
+	backups, err := api.backups.List()
+	if err != nil {
+		api.logger.Error("list backups", "error", err)
+		c.JSON(statusFromError(err), errorResponse(err))
+		return
+	}
+	resp := make([]backupResponse, 0, len(backups))
+	for _, info := range backups {
+		resp = append(resp, backupResponse(info))
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+func (api *apiServer) createBackup(c *gin.Context) {
+	if !api.ensureBackupsAvailable(c) {
+		return
+	}
+	info, err := api.backups.Create(c.Request.Context())
+	if err != nil {
+		api.logger.Error("create backup", "error", err)
+		c.JSON(statusFromError(err), errorResponse(err))
+		return
+	}
+	c.JSON(http.StatusCreated, backupResponse(info))
+}
+
+func (api *apiServer) restoreBackup(c *gin.Context) {
+	if !api.ensureBackupsAvailable(c) {
+		return
+	}
+	var req restoreBackupRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, http.StatusBadRequest, CodeInvalidRequest, err.Error())
+		return
+	}
+	result, err := api.backups.Restore(c.Request.Context(), req.Backup)
+	if err != nil {
+		api.logger.Error("restore backup", "backup", req.Backup, "error", err)
+		c.JSON(statusFromError(err), errorResponse(err))
+		return
+	}
+	c.JSON(http.StatusOK, restoreBackupResponse{
+		Restored: backupResponse(result.Restored),
+		Safety:   backupResponse(result.Safety),
+		Message:  "database restored; restart volantd to reload VM and network state",
+	})
+}
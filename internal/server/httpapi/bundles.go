@@ -0,0 +1,156 @@
+// Copyright (c) 2025 HYPR. PTE. LTD.
+//
+// Business Source License 1.1
+// See LICENSE file in the project root for details.
+
+package httpapi
+
+import (
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/volantvm/volant/internal/pluginspec"
+	"github.com/volantvm/volant/internal/server/orchestrator"
+)
+
+type configBundleRequest struct {
+	Data  map[string]string       `json:"data,omitempty"`
+	Files []pluginspec.BundleFile `json:"files,omitempty"`
+}
+
+type createConfigBundleRequest struct {
+	Name string `json:"name" binding:"required"`
+	configBundleRequest
+}
+
+// configBundleResponse describes a bundle without its values; secrets are only
+// handed to the VMs they are attached to.
+type configBundleResponse struct {
+	Name      string    `json:"name"`
+	Version   int       `json:"version"`
+	Keys      []string  `json:"keys"`
+	Files     []string  `json:"files"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type configBundleRotationResponse struct {
+	configBundleResponse
+	Restarted []string          `json:"restarted,omitempty"`
+	Skipped   map[string]string `json:"skipped,omitempty"`
+}
+
+func configBundleToResponse(bundle orchestrator.ConfigBundle) configBundleResponse {
+	resp := configBundleResponse{
+		Name:      bundle.Name,
+		Version:   bundle.Version,
+		Keys:      make([]string, 0, len(bundle.Data)),
+		Files:     make([]string, 0, len(bundle.Files)),
+		CreatedAt: bundle.CreatedAt,
+		UpdatedAt: bundle.UpdatedAt,
+	}
+	for key := range bundle.Data {
+		resp.Keys = append(resp.Keys, key)
+	}
+	sort.Strings(resp.Keys)
+	for _, file := range bundle.Files {
+		resp.Files = append(resp.Files, file.Path)
+	}
+	return resp
+}
+
+func (api *apiServer) listConfigBundles(c *gin.Context) {
+	bundles, err := api.engine.ListConfigBundles(c.Request.Context())
+	if err != nil {
+		api.logger.Error("list config bundles", "error", err)
+		c.JSON(statusFromError(err), errorResponse(err))
+		return
+	}
+	resp := make([]configBundleResponse, 0, len(bundles))
+	for _, bundle := range bundles {
+		resp = append(resp, configBundleToResponse(bundle))
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+func (api *apiServer) createConfigBundle(c *gin.Context) {
+	var req createConfigBundleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, http.StatusBadRequest, CodeInvalidRequest, err.Error())
+		return
+	}
+	bundle, err := api.engine.CreateConfigBundle(c.Request.Context(), pluginspec.Bundle{
+		Name:  req.Name,
+		Data:  req.Data,
+		Files: req.Files,
+	})
+	if err != nil {
+		api.logger.Error("create config bundle", "bundle", req.Name, "error", err)
+		c.JSON(statusFromError(err), errorResponse(err))
+		return
+	}
+	c.JSON(http.StatusCreated, configBundleToResponse(*bundle))
+}
+
+func (api *apiServer) getConfigBundle(c *gin.Context) {
+	bundle, err := api.engine.GetConfigBundle(c.Request.Context(), c.Param("name"))
+	if err != nil {
+		c.JSON(statusFromError(err), errorResponse(err))
+		return
+	}
+	c.JSON(http.StatusOK, configBundleToResponse(*bundle))
+}
+
+// updateConfigBundle replaces the bundle contents and rolls the deployments
+// using it.
+func (api *apiServer) updateConfigBundle(c *gin.Context) {
+	name := c.Param("name")
+	var req configBundleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, http.StatusBadRequest, CodeInvalidRequest, err.Error())
+		return
+	}
+	rotation, err := api.engine.UpdateConfigBundle(c.Request.Context(), pluginspec.Bundle{
+		Name:  name,
+		Data:  req.Data,
+		Files: req.Files,
+	})
+	if err != nil {
+		api.logger.Error("update config bundle", "bundle", name, "error", err)
+		c.JSON(statusFromError(err), errorResponse(err))
+		return
+	}
+	c.JSON(http.StatusAccepted, configBundleRotationResponse{
+		configBundleResponse: configBundleToResponse(rotation.Bundle),
+		Restarted:            rotation.Restarted,
+		Skipped:              rotation.Skipped,
+	})
+}
+
+func (api *apiServer) deleteConfigBundle(c *gin.Context) {
+	name := c.Param("name")
+	if err := api.engine.DeleteConfigBundle(c.Request.Context(), name); err != nil {
+		api.logger.Error("delete config bundle", "bundle", name, "error", err)
+		c.JSON(statusFromError(err), errorResponse(err))
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// getVMBundles serves the resolved bundles, values included, to the VM's
+// agent at boot.
+func (api *apiServer) getVMBundles(c *gin.Context) {
+	name := c.Param("name")
+	bundles, err := api.engine.VMBundles(c.Request.Context(), name)
+	if err != nil {
+		c.JSON(statusFromError(err), errorResponse(err))
+		return
+	}
+	if bundles == nil {
+		bundles = []pluginspec.Bundle{}
+	}
+	c.JSON(http.StatusOK, bundles)
+}
@@ -0,0 +1,298 @@
+// Copyright (c) 2025 HYPR. PTE. LTD.
+//
+// Business Source License 1.1
+// See LICENSE file in the project root for details.
+
+package httpapi
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"os"
+	goruntime "runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/volantvm/volant/internal/server/db"
+	"github.com/volantvm/volant/internal/server/eventbus"
+	orchestratorevents "github.com/volantvm/volant/internal/server/orchestrator/events"
+)
+
+const (
+	// dashboardMaxAge is how long clients may reuse a dashboard payload.
+	dashboardMaxAge = 5 * time.Second
+	// recentEventCapacity bounds the lifecycle events kept for the dashboard.
+	recentEventCapacity = 50
+)
+
+// Plugin health states reported on the dashboard.
+const (
+	pluginHealthy  = "healthy"
+	pluginDegraded = "degraded"
+	pluginDisabled = "disabled"
+)
+
+type dashboardResponse struct {
+	GeneratedAt  time.Time                    `json:"generated_at"`
+	VMs          dashboardVMs                 `json:"vms"`
+	Deployments  []dashboardDeployment        `json:"deployments"`
+	RecentEvents []orchestratorevents.VMEvent `json:"recent_events"`
+	Plugins      []dashboardPlugin            `json:"plugins"`
+	Host         hostUtilization              `json:"host"`
+}
+
+type dashboardVMs struct {
+	Total    int            `json:"total"`
+	ByStatus map[string]int `json:"by_status"`
+	Items    []dashboardVM  `json:"items"`
+}
+
+type dashboardVM struct {
+	Name      string `json:"name"`
+	Status    string `json:"status"`
+	Runtime   string `json:"runtime"`
+	IPAddress string `json:"ip_address,omitempty"`
+	CPUCores  int    `json:"cpu_cores"`
+	MemoryMB  int    `json:"memory_mb"`
+}
+
+type dashboardDeployment struct {
+	Name            string `json:"name"`
+	DesiredReplicas int    `json:"desired_replicas"`
+	ReadyReplicas   int    `json:"ready_replicas"`
+	// State is "ready" when every desired replica is ready and "progressing"
+	// otherwise.
+	State string `json:"state"`
+}
+
+type dashboardPlugin struct {
+	Name       string `json:"name"`
+	Version    string `json:"version"`
+	Enabled    bool   `json:"enabled"`
+	Health     string `json:"health"`
+	VMs        int    `json:"vms"`
+	RunningVMs int    `json:"running_vms"`
+	CrashedVMs int    `json:"crashed_vms"`
+}
+
+type hostUtilization struct {
+	CPUs              int       `json:"cpus"`
+	LoadAverage       []float64 `json:"load_average,omitempty"`
+	MemoryTotalMB     int       `json:"memory_total_mb,omitempty"`
+	MemoryAvailableMB int       `json:"memory_available_mb,omitempty"`
+	// AllocatedCPUs and AllocatedMemoryMB sum the resources of running VMs.
+	AllocatedCPUs     int `json:"allocated_cpus"`
+	AllocatedMemoryMB int `json:"allocated_memory_mb"`
+}
+
+// recentEvents keeps the latest VM lifecycle events for the dashboard.
+type recentEvents struct {
+	mu     sync.Mutex
+	events []orchestratorevents.VMEvent
+}
+
+// watchRecentEvents records lifecycle events published on bus for the life of
+// the process.
+func watchRecentEvents(bus eventbus.Bus) *recentEvents {
+	recent := &recentEvents{}
+	if bus == nil {
+		return recent
+	}
+	ch := make(chan any, 64)
+	if _, err := bus.Subscribe(orchestratorevents.TopicVMEvents, ch); err != nil {
+		return recent
+	}
+	go func() {
+		for payload := range ch {
+			if event, ok := payload.(orchestratorevents.VMEvent); ok && event.Type != orchestratorevents.TypeVMLog {
+				recent.add(event)
+			}
+		}
+	}()
+	return recent
+}
+
+func (r *recentEvents) add(event orchestratorevents.VMEvent) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = append(r.events, event)
+	if len(r.events) > recentEventCapacity {
+		r.events = r.events[len(r.events)-recentEventCapacity:]
+	}
+}
+
+// list returns the events newest first.
+func (r *recentEvents) list() []orchestratorevents.VMEvent {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	out := make([]orchestratorevents.VMEvent, 0, len(r.events))
+	for i := len(r.events) - 1; i >= 0; i-- {
+		out = append(out, r.events[i])
+	}
+	return out
+}
+
+// getDashboard returns everything a front page needs in one payload. The body
+// is tagged with an ETag so polling clients get 304s while nothing changes.
+func (api *apiServer) getDashboard(c *gin.Context) {
+	ctx := c.Request.Context()
+	vms, err := api.engine.ListVMs(ctx)
+	if err != nil {
+		api.logger.Error("dashboard list vms", "error", err)
+		respondError(c, http.StatusInternalServerError, CodeInternal, "failed to list vms")
+		return
+	}
+	deployments, err := api.engine.ListDeployments(ctx)
+	if err != nil {
+		api.logger.Error("dashboard list deployments", "error", err)
+		respondError(c, http.StatusInternalServerError, CodeInternal, "failed to list deployments")
+		return
+	}
+
+	resp := dashboardResponse{
+		VMs:          summarizeVMs(vms),
+		Deployments:  make([]dashboardDeployment, 0, len(deployments)),
+		RecentEvents: api.events.list(),
+		Plugins:      api.pluginHealth(vms),
+		Host:         readHostUtilization(vms),
+	}
+	for _, dep := range deployments {
+		state := "ready"
+		if dep.ReadyReplicas < dep.DesiredReplicas {
+			state = "progressing"
+		}
+		resp.Deployments = append(resp.Deployments, dashboardDeployment{
+			Name:            dep.Name,
+			DesiredReplicas: dep.DesiredReplicas,
+			ReadyReplicas:   dep.ReadyReplicas,
+			State:           state,
+		})
+	}
+
+	// Tag the payload before stamping it so unchanged state keeps its ETag.
+	body, err := json.Marshal(resp)
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, CodeInternal, "failed to encode dashboard")
+		return
+	}
+	sum := sha256.Sum256(body)
+	etag := `"` + hex.EncodeToString(sum[:16]) + `"`
+	c.Header("Cache-Control", "private, max-age="+strconv.Itoa(int(dashboardMaxAge.Seconds())))
+	c.Header("ETag", etag)
+	if match := c.GetHeader("If-None-Match"); match != "" && match == etag {
+		c.Status(http.StatusNotModified)
+		return
+	}
+	resp.GeneratedAt = time.Now().UTC()
+	c.JSON(http.StatusOK, resp)
+}
+
+func summarizeVMs(vms []db.VM) dashboardVMs {
+	summary := dashboardVMs{
+		Total:    len(vms),
+		ByStatus: map[string]int{},
+		Items:    make([]dashboardVM, 0, len(vms)),
+	}
+	for _, vm := range vms {
+		summary.ByStatus[strings.ToLower(string(vm.Status))]++
+		summary.Items = append(summary.Items, dashboardVM{
+			Name:      vm.Name,
+			Status:    string(vm.Status),
+			Runtime:   vm.Runtime,
+			IPAddress: vm.IPAddress,
+			CPUCores:  vm.CPUCores,
+			MemoryMB:  vm.MemoryMB,
+		})
+	}
+	sort.Slice(summary.Items, func(i, j int) bool { return summary.Items[i].Name < summary.Items[j].Name })
+	return summary
+}
+
+// pluginHealth reports each installed plugin with the state of its VMs. A
+// plugin is degraded while any of its VMs has crashed.
+func (api *apiServer) pluginHealth(vms []db.VM) []dashboardPlugin {
+	if api.plugins == nil {
+		return []dashboardPlugin{}
+	}
+	names := api.plugins.List()
+	sort.Strings(names)
+	out := make([]dashboardPlugin, 0, len(names))
+	for _, name := range names {
+		manifest, ok := api.plugins.Get(name)
+		if !ok {
+			continue
+		}
+		entry := dashboardPlugin{Name: manifest.Name, Version: manifest.Version, Enabled: manifest.Enabled}
+		for _, vm := range vms {
+			if !strings.EqualFold(vm.Runtime, manifest.Name) {
+				continue
+			}
+			entry.VMs++
+			switch vm.Status {
+			case db.VMStatusRunning:
+				entry.RunningVMs++
+			case db.VMStatusCrashed:
+				entry.CrashedVMs++
+			}
+		}
+		switch {
+		case !manifest.Enabled:
+			entry.Health = pluginDisabled
+		case entry.CrashedVMs > 0:
+			entry.Health = pluginDegraded
+		default:
+			entry.Health = pluginHealthy
+		}
+		out = append(out, entry)
+	}
+	return out
+}
+
+// readHostUtilization samples /proc where available; on other platforms only
+// the CPU count and VM allocations are reported.
+func readHostUtilization(vms []db.VM) hostUtilization {
+	host := hostUtilization{CPUs: goruntime.NumCPU()}
+	for _, vm := range vms {
+		if vm.Status == db.VMStatusRunning {
+			host.AllocatedCPUs += vm.CPUCores
+			host.AllocatedMemoryMB += vm.MemoryMB
+		}
+	}
+	if raw, err := os.ReadFile("/proc/loadavg"); err == nil {
+		fields := strings.Fields(string(raw))
+		for i := 0; i < 3 && i < len(fields); i++ {
+			if value, err := strconv.ParseFloat(fields[i], 64); err == nil {
+				host.LoadAverage = append(host.LoadAverage, value)
+			}
+		}
+	}
+	if f, err := os.Open("/proc/meminfo"); err == nil {
+		defer f.Close()
+		scanner := bufio.NewScanner(f)
+		for scanner.Scan() {
+			fields := strings.Fields(scanner.Text())
+			if len(fields) < 2 {
+				continue
+			}
+			kb, err := strconv.Atoi(fields[1])
+			if err != nil {
+				continue
+			}
+			switch fields[0] {
+			case "MemTotal:":
+				host.MemoryTotalMB = kb / 1024
+			case "MemAvailable:":
+				host.MemoryAvailableMB = kb / 1024
+			}
+		}
+	}
+	return host
+}
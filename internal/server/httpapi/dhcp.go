@@ -0,0 +1,49 @@
+// Copyright (c) 2025 HYPR. PTE. LTD.
+//
+// Business Source License 1.1
+// See LICENSE file in the project root for details.
+
+package httpapi
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/volantvm/volant/internal/server/db"
+)
+
+type dhcpLeaseResponse struct {
+	MACAddress string    `json:"mac_address"`
+	IPAddress  string    `json:"ip_address"`
+	VM         string    `json:"vm"`
+	Hostname   string    `json:"hostname,omitempty"`
+	ExpiresAt  time.Time `json:"expires_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+func dhcpLeaseToResponse(lease db.DHCPLease) dhcpLeaseResponse {
+	return dhcpLeaseResponse{
+		MACAddress: lease.MACAddress,
+		IPAddress:  lease.IPAddress,
+		VM:         lease.VMName,
+		Hostname:   lease.Hostname,
+		ExpiresAt:  lease.ExpiresAt,
+		UpdatedAt:  lease.UpdatedAt,
+	}
+}
+
+func (api *apiServer) listDHCPLeases(c *gin.Context) {
+	leases, err := api.engine.Store().Queries().DHCPLeases().List(c.Request.Context())
+	if err != nil {
+		api.logger.Error("list dhcp leases", "error", err)
+		c.JSON(statusFromError(err), errorResponse(err))
+		return
+	}
+	resp := make([]dhcpLeaseResponse, 0, len(leases))
+	for _, lease := range leases {
+		resp = append(resp, dhcpLeaseToResponse(lease))
+	}
+	c.JSON(http.StatusOK, resp)
+}
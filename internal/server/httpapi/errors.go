@@ -0,0 +1,389 @@
+// Copyright (c) 2025 HYPR. PTE. LTD.
+//
+// Business Source License 1.1
+// See LICENSE file in the project root for details.
+
+package httpapi
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/volantvm/volant/internal/server/admission"
+	"github.com/volantvm/volant/internal/server/apiauth"
+	"github.com/volantvm/volant/internal/server/backup"
+	"github.com/volantvm/volant/internal/server/db"
+	"github.com/volantvm/volant/internal/server/db/migrate"
+	"github.com/volantvm/volant/internal/server/orchestrator"
+	"github.com/volantvm/volant/internal/server/orchestrator/runtime"
+	"github.com/volantvm/volant/internal/server/plugins"
+	"github.com/volantvm/volant/internal/server/scheduler"
+	"github.com/volantvm/volant/internal/server/secrets"
+	"github.com/volantvm/volant/internal/server/webhooks"
+)
+
+// Codes returned in the code field of error responses. Codes are stable:
+// clients branch on them, while messages may change between releases.
+const (
+	// Generic codes, used when no specific code applies.
+	CodeInvalidRequest  = "INVALID_REQUEST"
+	CodeUnauthorized    = "UNAUTHORIZED"
+	CodeForbidden       = "FORBIDDEN"
+	CodeNotFound        = "NOT_FOUND"
+	CodeConflict        = "CONFLICT"
+	CodeUnprocessable   = "UNPROCESSABLE"
+	CodeInternal        = "INTERNAL"
+	CodeNotImplemented  = "NOT_IMPLEMENTED"
+	CodeUpstreamError   = "UPSTREAM_ERROR"
+	CodeUpstreamTimeout = "UPSTREAM_TIMEOUT"
+	CodeUnavailable     = "UNAVAILABLE"
+
+	CodeValidationFailed          = "VALIDATION_FAILED"
+	CodeAdmissionDenied           = "ADMISSION_DENIED"
+	CodeAdmissionWebhookFailed    = "ADMISSION_WEBHOOK_FAILED"
+	CodeVMNotFound                = "VM_NOT_FOUND"
+	CodeVMExists                  = "VM_EXISTS"
+	CodeVMNotRunning              = "VM_NOT_RUNNING"
+	CodeVMNotReady                = "VM_NOT_READY"
+	CodeVMNotDuplicable           = "VM_NOT_DUPLICABLE"
+	CodeDependencyUnavailable     = "DEPENDENCY_UNAVAILABLE"
+	CodeAgentUnavailable          = "AGENT_UNAVAILABLE"
+	CodeDeploymentNotFound        = "DEPLOYMENT_NOT_FOUND"
+	CodeDeploymentExists          = "DEPLOYMENT_EXISTS"
+	CodeWarmPoolNotFound          = "WARM_POOL_NOT_FOUND"
+	CodeWarmPoolEmpty             = "WARM_POOL_EMPTY"
+	CodeInvalidWarmPool           = "INVALID_WARM_POOL"
+	CodeRolloutInProgress         = "ROLLOUT_IN_PROGRESS"
+	CodeConfigUpdateInProgress    = "CONFIG_UPDATE_IN_PROGRESS"
+	CodeHostPortInUse             = "HOST_PORT_IN_USE"
+	CodeRouteManaged              = "ROUTE_MANAGED"
+	CodeDevicesUnavailable        = "DEVICES_UNAVAILABLE"
+	CodeDeviceClaimed             = "DEVICE_CLAIMED"
+	CodeMaintenanceWindowNotFound = "MAINTENANCE_WINDOW_NOT_FOUND"
+	CodeMaintenanceWindowExists   = "MAINTENANCE_WINDOW_EXISTS"
+	CodeMaintenanceWindowActive   = "MAINTENANCE_WINDOW_ACTIVE"
+	CodeHostMaintenance           = "HOST_MAINTENANCE"
+	CodeInvalidMaintenanceWindow  = "INVALID_MAINTENANCE_WINDOW"
+	CodeNetworkNotFound           = "NETWORK_NOT_FOUND"
+	CodeNetworkExists             = "NETWORK_EXISTS"
+	CodeNetworkInUse              = "NETWORK_IN_USE"
+	CodeInvalidNetwork            = "INVALID_NETWORK"
+	CodeIPPoolExhausted           = "IP_POOL_EXHAUSTED"
+	CodeInvalidLabels             = "INVALID_LABELS"
+	CodeNodeNotFound              = "NODE_NOT_FOUND"
+	CodeArtifactNotFound          = "ARTIFACT_NOT_FOUND"
+	CodeOperationNotFound         = "OPERATION_NOT_FOUND"
+	CodeHostFeaturesMissing       = "HOST_FEATURES_MISSING"
+	CodePlacementUnsatisfiable    = "PLACEMENT_UNSATISFIABLE"
+	CodePluginQuotaExceeded       = "PLUGIN_QUOTA_EXCEEDED"
+	CodeInsufficientCapacity      = "INSUFFICIENT_CAPACITY"
+	CodeConfigBundleNotFound      = "CONFIG_BUNDLE_NOT_FOUND"
+	CodeConfigBundleExists        = "CONFIG_BUNDLE_EXISTS"
+	CodeConfigBundleInUse         = "CONFIG_BUNDLE_IN_USE"
+	CodeInvalidConfigBundle       = "INVALID_CONFIG_BUNDLE"
+	CodePluginNotFound            = "PLUGIN_NOT_FOUND"
+	CodePluginDisabled            = "PLUGIN_DISABLED"
+	CodePluginVersionNotFound     = "PLUGIN_VERSION_NOT_FOUND"
+	CodeActionNotFound            = "ACTION_NOT_FOUND"
+	CodeScheduleNotFound          = "SCHEDULE_NOT_FOUND"
+	CodeScheduleExists            = "SCHEDULE_EXISTS"
+	CodeInvalidSchedule           = "INVALID_SCHEDULE"
+	CodeWebhookNotFound           = "WEBHOOK_NOT_FOUND"
+	CodeWebhookExists             = "WEBHOOK_EXISTS"
+	CodeInvalidWebhook            = "INVALID_WEBHOOK"
+	CodeBackupNotFound            = "BACKUP_NOT_FOUND"
+	CodeInvalidBackupName         = "INVALID_BACKUP_NAME"
+	CodeBackupsUnsupported        = "BACKUPS_UNSUPPORTED"
+	CodeSchemaTooNew              = "SCHEMA_TOO_NEW"
+	CodeIdempotencyKeyReused      = "IDEMPOTENCY_KEY_REUSED"
+	CodeIdempotencyKeyInProgress  = "IDEMPOTENCY_KEY_IN_PROGRESS"
+	CodeFileTooLarge              = "FILE_TOO_LARGE"
+	CodeChecksumMismatch          = "CHECKSUM_MISMATCH"
+	CodeExecNotAllowed            = "EXEC_NOT_ALLOWED"
+	CodeSecretNotFound            = "SECRET_NOT_FOUND"
+	CodeInvalidSecret             = "INVALID_SECRET"
+	CodeSecretAccessDenied        = "SECRET_ACCESS_DENIED"
+	CodeSSHKeyNotFound            = "SSH_KEY_NOT_FOUND"
+)
+
+// errorCode documents one code in the OpenAPI spec.
+type errorCode struct {
+	code        string
+	status      int
+	description string
+}
+
+// sentinelCodes maps the sentinel errors of the engine and its collaborators
+// to a status and code. The first match wins.
+var sentinelCodes = []struct {
+	err         error
+	status      int
+	code        string
+	description string
+}{
+	{admission.ErrDenied, http.StatusForbidden, CodeAdmissionDenied, "An admission webhook rejected the request."},
+	{admission.ErrWebhookFailed, http.StatusServiceUnavailable, CodeAdmissionWebhookFailed, "An admission webhook could not be reached or answered invalidly."},
+	{apiauth.ErrInvalidKey, http.StatusUnauthorized, CodeUnauthorized, "The API key is missing or invalid."},
+	{orchestrator.ErrVMNotFound, http.StatusNotFound, CodeVMNotFound, "The VM does not exist."},
+	{orchestrator.ErrVMExists, http.StatusConflict, CodeVMExists, "A VM with the name already exists."},
+	{orchestrator.ErrDeploymentNotFound, http.StatusNotFound, CodeDeploymentNotFound, "The deployment does not exist."},
+	{orchestrator.ErrDeploymentExists, http.StatusConflict, CodeDeploymentExists, "A deployment with the name already exists."},
+	{orchestrator.ErrWarmPoolNotFound, http.StatusNotFound, CodeWarmPoolNotFound, "The plugin has no warm pool."},
+	{orchestrator.ErrWarmPoolEmpty, http.StatusServiceUnavailable, CodeWarmPoolEmpty, "The warm pool has no ready VM; it is being replenished."},
+	{orchestrator.ErrInvalidWarmPool, http.StatusBadRequest, CodeInvalidWarmPool, "The warm pool definition failed validation."},
+	{orchestrator.ErrRolloutInProgress, http.StatusConflict, CodeRolloutInProgress, "The deployment is already rolling out a change."},
+	{orchestrator.ErrVMNotRunning, http.StatusConflict, CodeVMNotRunning, "The operation needs a running VM."},
+	{orchestrator.ErrVMNotDuplicable, http.StatusConflict, CodeVMNotDuplicable, "The VM holds passthrough devices or writable disks its copy cannot share."},
+	{orchestrator.ErrDependencyUnavailable, http.StatusConflict, CodeDependencyUnavailable, "A VM named in depends_on does not exist, failed to start or did not become ready in time."},
+	{orchestrator.ErrConfigUpdateInProgress, http.StatusConflict, CodeConfigUpdateInProgress, "Another config update of the VM is being applied."},
+	{orchestrator.ErrHostPortInUse, http.StatusConflict, CodeHostPortInUse, "A requested host port is already forwarded to another VM."},
+	{orchestrator.ErrRouteManaged, http.StatusConflict, CodeRouteManaged, "The drift route is published for a running VM's expose rules; change the VM's config instead."},
+	{orchestrator.ErrDevicesUnavailable, http.StatusConflict, CodeDevicesUnavailable, "No free passthrough devices match the device request."},
+	{orchestrator.ErrDeviceClaimed, http.StatusConflict, CodeDeviceClaimed, "A passthrough device, or a device in its IOMMU group, belongs to another VM."},
+	{db.ErrDeviceAssigned, http.StatusConflict, CodeDeviceClaimed, "A passthrough device, or a device in its IOMMU group, belongs to another VM."},
+	{orchestrator.ErrMaintenanceWindowNotFound, http.StatusNotFound, CodeMaintenanceWindowNotFound, "The maintenance window does not exist."},
+	{orchestrator.ErrMaintenanceWindowExists, http.StatusConflict, CodeMaintenanceWindowExists, "A maintenance window with the name already exists."},
+	{orchestrator.ErrMaintenanceWindowActive, http.StatusConflict, CodeMaintenanceWindowActive, "The operation is blocked while the maintenance window is active."},
+	{orchestrator.ErrHostMaintenance, http.StatusServiceUnavailable, CodeHostMaintenance, "The host is in maintenance mode and refuses new VMs until it is exited."},
+	{orchestrator.ErrInvalidMaintenanceWindow, http.StatusBadRequest, CodeInvalidMaintenanceWindow, "The maintenance window failed validation."},
+	{orchestrator.ErrNetworkNotFound, http.StatusNotFound, CodeNetworkNotFound, "The network does not exist."},
+	{orchestrator.ErrNetworkExists, http.StatusConflict, CodeNetworkExists, "A network with the name or subnet already exists."},
+	{orchestrator.ErrNetworkInUse, http.StatusConflict, CodeNetworkInUse, "VMs are still attached to the network."},
+	{orchestrator.ErrInvalidNetwork, http.StatusBadRequest, CodeInvalidNetwork, "The network failed validation."},
+	{orchestrator.ErrIPPoolExhausted, http.StatusConflict, CodeIPPoolExhausted, "The network has no free address for the VM."},
+	{db.ErrNoAvailableIPs, http.StatusConflict, CodeIPPoolExhausted, "The network has no free address for the VM."},
+	{orchestrator.ErrInvalidLabels, http.StatusBadRequest, CodeInvalidLabels, "A label key or value failed validation."},
+	{orchestrator.ErrNodeNotFound, http.StatusNotFound, CodeNodeNotFound, "The node is not this host."},
+	{orchestrator.ErrArtifactNotFound, http.StatusNotFound, CodeArtifactNotFound, "The node has no cached artifact by that name."},
+	{orchestrator.ErrOperationNotFound, http.StatusNotFound, CodeOperationNotFound, "The operation does not exist."},
+	{orchestrator.ErrHostFeaturesMissing, http.StatusUnprocessableEntity, CodeHostFeaturesMissing, "The host lacks kernel features the plugin requires; details.missing_features lists them."},
+	{orchestrator.ErrPlacementUnsatisfiable, http.StatusUnprocessableEntity, CodePlacementUnsatisfiable, "The host cannot provide the requested CPU pinning, NUMA or huge page placement."},
+	{orchestrator.ErrPluginQuotaExceeded, http.StatusUnprocessableEntity, CodePluginQuotaExceeded, "The request exceeds a plugin limit; details name the constraint, limit and requested value."},
+	{orchestrator.ErrInsufficientCapacity, http.StatusConflict, CodeInsufficientCapacity, "The host capacity thresholds would be exceeded; details name the resource, allowed and requested amounts."},
+	{orchestrator.ErrConfigBundleNotFound, http.StatusNotFound, CodeConfigBundleNotFound, "The config bundle does not exist."},
+	{orchestrator.ErrConfigBundleExists, http.StatusConflict, CodeConfigBundleExists, "A config bundle with the name already exists."},
+	{orchestrator.ErrConfigBundleInUse, http.StatusConflict, CodeConfigBundleInUse, "Deployments still reference the config bundle."},
+	{orchestrator.ErrInvalidConfigBundle, http.StatusBadRequest, CodeInvalidConfigBundle, "The config bundle failed validation."},
+	{plugins.ErrPluginNotFound, http.StatusNotFound, CodePluginNotFound, "The plugin is not installed."},
+	{plugins.ErrActionNotFound, http.StatusNotFound, CodeActionNotFound, "The plugin declares no such action."},
+	{scheduler.ErrScheduleNotFound, http.StatusNotFound, CodeScheduleNotFound, "The schedule does not exist."},
+	{scheduler.ErrScheduleExists, http.StatusConflict, CodeScheduleExists, "A schedule with the name already exists."},
+	{scheduler.ErrInvalidSchedule, http.StatusBadRequest, CodeInvalidSchedule, "The schedule failed validation."},
+	{webhooks.ErrWebhookNotFound, http.StatusNotFound, CodeWebhookNotFound, "The webhook does not exist."},
+	{webhooks.ErrWebhookExists, http.StatusConflict, CodeWebhookExists, "A webhook with the name already exists."},
+	{webhooks.ErrInvalidWebhook, http.StatusBadRequest, CodeInvalidWebhook, "The webhook failed validation."},
+	{secrets.ErrSecretNotFound, http.StatusNotFound, CodeSecretNotFound, "The secret does not exist."},
+	{secrets.ErrInvalidSecret, http.StatusBadRequest, CodeInvalidSecret, "The secret name or value failed validation."},
+	{orchestrator.ErrSecretAccessDenied, http.StatusForbidden, CodeSecretAccessDenied, "A VM's secrets can only be read by the VM itself."},
+	{orchestrator.ErrSSHKeyNotFound, http.StatusNotFound, CodeSSHKeyNotFound, "volantd holds no SSH key for the VM; set ssh.generate_key and start it."},
+	{backup.ErrNotFound, http.StatusNotFound, CodeBackupNotFound, "The backup does not exist."},
+	{backup.ErrInvalidName, http.StatusBadRequest, CodeInvalidBackupName, "The backup name is not a plain file name."},
+	{backup.ErrUnsupported, http.StatusNotImplemented, CodeBackupsUnsupported, "The storage backend does not support backups."},
+	{migrate.ErrSchemaTooNew, http.StatusConflict, CodeSchemaTooNew, "The database schema is newer than this build of volantd."},
+}
+
+// apiError is the body of every error response.
+type apiError struct {
+	Code    string         `json:"code"`
+	Message string         `json:"message"`
+	Details map[string]any `json:"details,omitempty"`
+	// Error repeats Message for clients that predate codes.
+	Error string `json:"error"`
+}
+
+func newAPIError(code, message string) apiError {
+	return apiError{Code: code, Message: message, Error: message}
+}
+
+// respondError answers the request with an error envelope.
+func respondError(c *gin.Context, status int, code, message string) {
+	c.JSON(status, newAPIError(code, message))
+}
+
+// abortError aborts the handler chain with an error envelope.
+func abortError(c *gin.Context, status int, code, message string) {
+	c.AbortWithStatusJSON(status, newAPIError(code, message))
+}
+
+// errorResponse builds the envelope for err, carrying the fields of typed
+// engine errors as details.
+func errorResponse(err error) apiError {
+	body := newAPIError(codeFromError(err), err.Error())
+	var featureErr *orchestrator.HostFeatureError
+	if errors.As(err, &featureErr) {
+		body.Details = map[string]any{"missing_features": featureErr.Missing}
+	}
+	var quotaErr *orchestrator.QuotaError
+	if errors.As(err, &quotaErr) {
+		body.Details = map[string]any{
+			"constraint": quotaErr.Constraint,
+			"limit":      quotaErr.Limit,
+			"requested":  quotaErr.Requested,
+		}
+	}
+	var capacityErr *orchestrator.CapacityError
+	if errors.As(err, &capacityErr) {
+		body.Details = map[string]any{
+			"resource":  capacityErr.Resource,
+			"allowed":   capacityErr.Allowed,
+			"requested": capacityErr.Requested,
+		}
+	}
+	if launchErr, ok := runtime.AsLaunchError(err); ok {
+		body.Details = map[string]any{"hint": launchErr.Hint}
+	}
+	return body
+}
+
+// requestError is a failure the handler detects itself, carrying the status
+// and code to answer with.
+type requestError struct {
+	status  int
+	code    string
+	message string
+}
+
+func newRequestError(status int, code, message string) *requestError {
+	return &requestError{status: status, code: code, message: message}
+}
+
+func (e *requestError) Error() string { return e.message }
+
+func statusFromError(err error) int {
+	var reqErr *requestError
+	if errors.As(err, &reqErr) {
+		return reqErr.status
+	}
+	if launchErr, ok := runtime.AsLaunchError(err); ok {
+		return launchStatus(launchErr.Code)
+	}
+	for _, sentinel := range sentinelCodes {
+		if errors.Is(err, sentinel.err) {
+			return sentinel.status
+		}
+	}
+	return http.StatusInternalServerError
+}
+
+// launchStatus is the status of a classified launch failure: bad inputs are
+// the caller's to fix, everything else is the host's.
+func launchStatus(code runtime.ErrorCode) int {
+	switch code {
+	case runtime.ErrorKernelNotFound, runtime.ErrorChecksumMismatch:
+		return http.StatusUnprocessableEntity
+	default:
+		return http.StatusServiceUnavailable
+	}
+}
+
+func codeFromError(err error) string {
+	var reqErr *requestError
+	if errors.As(err, &reqErr) {
+		return reqErr.code
+	}
+	if launchErr, ok := runtime.AsLaunchError(err); ok {
+		return string(launchErr.Code)
+	}
+	for _, sentinel := range sentinelCodes {
+		if errors.Is(err, sentinel.err) {
+			return sentinel.code
+		}
+	}
+	return CodeInternal
+}
+
+// codeForStatus returns the generic code of an HTTP status, for errors
+// relayed from agents and other upstreams.
+func codeForStatus(status int) string {
+	switch status {
+	case http.StatusBadRequest:
+		return CodeInvalidRequest
+	case http.StatusUnauthorized:
+		return CodeUnauthorized
+	case http.StatusForbidden:
+		return CodeForbidden
+	case http.StatusNotFound:
+		return CodeNotFound
+	case http.StatusConflict:
+		return CodeConflict
+	case http.StatusUnprocessableEntity:
+		return CodeUnprocessable
+	case http.StatusNotImplemented:
+		return CodeNotImplemented
+	case http.StatusBadGateway:
+		return CodeUpstreamError
+	case http.StatusGatewayTimeout:
+		return CodeUpstreamTimeout
+	case http.StatusServiceUnavailable:
+		return CodeUnavailable
+	}
+	if status >= 400 && status < 500 {
+		return CodeInvalidRequest
+	}
+	return CodeInternal
+}
+
+// isErrorCode reports whether code has the shape of an error code, so codes
+// relayed from agents are passed through only when well formed.
+func isErrorCode(code string) bool {
+	if code == "" || len(code) > 64 {
+		return false
+	}
+	return strings.Trim(code, "ABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789_") == ""
+}
+
+// errorCodes lists every code for the OpenAPI spec.
+func errorCodes() []errorCode {
+	codes := []errorCode{
+		{CodeInvalidRequest, http.StatusBadRequest, "The request is malformed or failed validation."},
+		{CodeForbidden, http.StatusForbidden, "The client or caller is not allowed to do this."},
+		{CodeNotFound, http.StatusNotFound, "The resource does not exist."},
+		{CodeConflict, http.StatusConflict, "The request conflicts with the resource's current state."},
+		{CodeUnprocessable, http.StatusUnprocessableEntity, "The request is well formed but cannot be carried out."},
+		{CodeValidationFailed, http.StatusUnprocessableEntity, "The request does not match the API schema; details.errors lists each field."},
+		{CodeInternal, http.StatusInternalServerError, "An unexpected server error."},
+		{CodeNotImplemented, http.StatusNotImplemented, "The operation is not supported by this server."},
+		{CodeUpstreamError, http.StatusBadGateway, "A guest agent or other upstream failed."},
+		{CodeUpstreamTimeout, http.StatusGatewayTimeout, "A guest agent or other upstream did not answer in time, after any retries the plugin action allows."},
+		{CodeUnavailable, http.StatusServiceUnavailable, "A subsystem the request needs is not configured or not running."},
+		{CodeVMNotReady, http.StatusConflict, "The VM's agent is not reachable yet."},
+		{CodeAgentUnavailable, http.StatusServiceUnavailable, "No agent address is known for the VM, or its circuit breaker is open after repeated failures to reach it; Retry-After says when to try again."},
+		{CodePluginDisabled, http.StatusConflict, "The plugin is installed but disabled."},
+		{CodePluginVersionNotFound, http.StatusNotFound, "The plugin version is not installed."},
+		{CodeIdempotencyKeyReused, http.StatusUnprocessableEntity, "The Idempotency-Key was used with a different request."},
+		{CodeIdempotencyKeyInProgress, http.StatusConflict, "A request with the Idempotency-Key is still running."},
+		{CodeFileTooLarge, http.StatusRequestEntityTooLarge, "The file exceeds VOLANT_FILE_MAX_SIZE_MB or the agent's limit."},
+		{CodeChecksumMismatch, http.StatusUnprocessableEntity, "The uploaded file does not match its X-Volant-Checksum-Sha256; the guest file is left unchanged."},
+		{CodeExecNotAllowed, http.StatusForbidden, "The plugin manifest does not enable exec, or the command is not in its exec allowlist."},
+	}
+	for _, sentinel := range sentinelCodes {
+		codes = append(codes, errorCode{sentinel.code, sentinel.status, sentinel.description})
+	}
+	for _, code := range runtime.ErrorCodes() {
+		codes = append(codes, errorCode{string(code), launchStatus(code), "VM launch failed: " + runtime.Hint(code)})
+	}
+	// Codes backed by several sentinels are listed once.
+	seen := make(map[string]bool, len(codes))
+	unique := codes[:0]
+	for _, code := range codes {
+		if !seen[code.code] {
+			seen[code.code] = true
+			unique = append(unique, code)
+		}
+	}
+	return unique
+}
+
+// errorCodesDescription renders the code table for the OpenAPI spec.
+func errorCodesDescription() string {
+	var b strings.Builder
+	b.WriteString("Stable machine-readable error code.\n\n| Code | Status | Meaning |\n| --- | --- | --- |\n")
+	for _, code := range errorCodes() {
+		fmt.Fprintf(&b, "| %s | %d | %s |\n", code.code, code.status, code.description)
+	}
+	return b.String()
+}
@@ -0,0 +1,196 @@
+// Copyright (c) 2025 HYPR. PTE. LTD.
+//
+// Business Source License 1.1
+// See LICENSE file in the project root for details.
+
+package httpapi
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+
+	orchestratorevents "github.com/volantvm/volant/internal/server/orchestrator/events"
+	"github.com/volantvm/volant/internal/server/scheduler"
+)
+
+const (
+	// eventsPingInterval keeps idle connections alive through proxies.
+	eventsPingInterval = 30 * time.Second
+	// eventsPongWait drops clients that stop answering pings.
+	eventsPongWait  = 2 * eventsPingInterval
+	eventsWriteWait = 10 * time.Second
+)
+
+// Envelope topics on /ws/v1/events.
+const (
+	eventTopicVM         = "vm"
+	eventTopicDeployment = "deployment"
+	eventTopicPlugin     = "plugin"
+	eventTopicSchedule   = "schedule"
+	eventTopicOperation  = "operation"
+	eventTopicSystem     = "system"
+)
+
+var eventBusTopics = []string{
+	orchestratorevents.TopicVMEvents,
+	orchestratorevents.TopicDeploymentEvents,
+	orchestratorevents.TopicPluginEvents,
+	scheduler.TopicScheduleEvents,
+	orchestratorevents.TopicOperationEvents,
+	orchestratorevents.TopicSystemEvents,
+}
+
+// eventEnvelope wraps every message sent on /ws/v1/events.
+type eventEnvelope struct {
+	Topic     string    `json:"topic"`
+	Type      string    `json:"type"`
+	Name      string    `json:"name,omitempty"`
+	Timestamp time.Time `json:"timestamp"`
+	Data      any       `json:"data"`
+}
+
+func envelopeFor(payload any) (eventEnvelope, bool) {
+	switch event := payload.(type) {
+	case orchestratorevents.VMEvent:
+		return eventEnvelope{Topic: eventTopicVM, Type: event.Type, Name: event.Name, Timestamp: event.Timestamp, Data: event}, true
+	case orchestratorevents.DeploymentEvent:
+		return eventEnvelope{Topic: eventTopicDeployment, Type: event.Type, Name: event.Name, Timestamp: event.Timestamp, Data: event}, true
+	case orchestratorevents.PluginEvent:
+		return eventEnvelope{Topic: eventTopicPlugin, Type: event.Type, Name: event.Name, Timestamp: event.Timestamp, Data: event}, true
+	case scheduler.Event:
+		return eventEnvelope{Topic: eventTopicSchedule, Type: event.Type, Name: event.Schedule, Timestamp: event.Timestamp, Data: event}, true
+	case orchestratorevents.OperationEvent:
+		return eventEnvelope{Topic: eventTopicOperation, Type: event.Type, Name: event.ID, Timestamp: event.Timestamp, Data: event}, true
+	case orchestratorevents.GCEvent:
+		return eventEnvelope{Topic: eventTopicSystem, Type: event.Type, Timestamp: event.Timestamp, Data: event}, true
+	}
+	return eventEnvelope{}, false
+}
+
+// eventFilter holds the subscription filters from the query string. Each
+// parameter may be repeated or comma-separated; empty sets match everything.
+type eventFilter struct {
+	vms    map[string]bool
+	types  map[string]bool
+	topics map[string]bool
+}
+
+func parseEventFilter(c *gin.Context) eventFilter {
+	return eventFilter{
+		vms:    queryValueSet(c, "vm", false),
+		types:  queryValueSet(c, "type", true),
+		topics: queryValueSet(c, "topic", true),
+	}
+}
+
+func queryValueSet(c *gin.Context, key string, fold bool) map[string]bool {
+	var set map[string]bool
+	for _, raw := range c.QueryArray(key) {
+		for _, value := range strings.Split(raw, ",") {
+			value = strings.TrimSpace(value)
+			if value == "" {
+				continue
+			}
+			if fold {
+				value = strings.ToUpper(value)
+			}
+			if set == nil {
+				set = make(map[string]bool)
+			}
+			set[value] = true
+		}
+	}
+	return set
+}
+
+func (f eventFilter) match(env eventEnvelope) bool {
+	if f.topics != nil && !f.topics[strings.ToUpper(env.Topic)] {
+		return false
+	}
+	if f.types != nil {
+		if !f.types[strings.ToUpper(env.Type)] {
+			return false
+		}
+	} else if env.Type == orchestratorevents.TypeVMLog {
+		// Log lines are opt-in; they would drown out lifecycle events.
+		return false
+	}
+	if f.vms != nil {
+		// A VM filter narrows the stream to events about those VMs.
+		return env.Topic == eventTopicVM && f.vms[env.Name]
+	}
+	return true
+}
+
+// /ws/v1/events -> stream all event bus topics as JSON envelopes
+func (api *apiServer) eventsWebSocket(c *gin.Context) {
+	if api.bus == nil {
+		respondError(c, http.StatusServiceUnavailable, CodeUnavailable, "event streaming not available")
+		return
+	}
+	filter := parseEventFilter(c)
+
+	upgrader := websocket.Upgrader{CheckOrigin: func(r *http.Request) bool { return true }}
+	conn, err := upgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		api.logger.Error("events websocket upgrade", "error", err)
+		return
+	}
+	defer conn.Close()
+
+	eventsCh := make(chan any, 64)
+	for _, topic := range eventBusTopics {
+		unsubscribe, err := api.bus.Subscribe(topic, eventsCh)
+		if err != nil {
+			writeWebSocketClose(conn, websocket.CloseInternalServerErr, "failed to subscribe")
+			return
+		}
+		defer unsubscribe()
+	}
+
+	ctx, cancel := context.WithCancel(c.Request.Context())
+	defer cancel()
+
+	// The read loop only services control frames; it ends the stream when the
+	// client closes the connection or stops answering pings.
+	_ = conn.SetReadDeadline(time.Now().Add(eventsPongWait))
+	conn.SetPongHandler(func(string) error {
+		return conn.SetReadDeadline(time.Now().Add(eventsPongWait))
+	})
+	go func() {
+		defer cancel()
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}()
+
+	ticker := time.NewTicker(eventsPingInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			writeWebSocketClose(conn, websocket.CloseNormalClosure, "stream closed")
+			return
+		case <-ticker.C:
+			if err := conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(eventsWriteWait)); err != nil {
+				return
+			}
+		case payload := <-eventsCh:
+			env, ok := envelopeFor(payload)
+			if !ok || !filter.match(env) {
+				continue
+			}
+			_ = conn.SetWriteDeadline(time.Now().Add(eventsWriteWait))
+			if err := conn.WriteJSON(env); err != nil {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,199 @@
+// Copyright (c) 2025 HYPR. PTE. LTD.
+//
+// Business Source License 1.1
+// See LICENSE file in the project root for details.
+
+package httpapi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+
+	"github.com/volantvm/volant/internal/pluginspec"
+	"github.com/volantvm/volant/internal/server/db"
+	"github.com/volantvm/volant/internal/server/tracing"
+)
+
+const (
+	// execCallGrace is how much longer than the command's timeout volantd
+	// waits for the agent to report it.
+	execCallGrace = 10 * time.Second
+	// execStartTimeout bounds the wait for a session's exec request.
+	execStartTimeout = 30 * time.Second
+)
+
+// postVMExec runs a command in the guest through the agent and returns its
+// exit code and output. The plugin manifest's exec allowlist decides which
+// binaries may run.
+func (api *apiServer) postVMExec(c *gin.Context) {
+	vm, ok := api.resolveVM(c)
+	if !ok {
+		return
+	}
+	var req pluginspec.ExecRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, http.StatusBadRequest, CodeInvalidRequest, err.Error())
+		return
+	}
+	if err := req.Validate(); err != nil {
+		respondError(c, http.StatusBadRequest, CodeInvalidRequest, err.Error())
+		return
+	}
+
+	started := time.Now()
+	var result pluginspec.ExecResult
+	// The agent may lower a missing timeout to the manifest's cap, never raise it.
+	policy := agentCallPolicy{timeout: time.Duration(req.Timeout())*time.Millisecond + execCallGrace}
+	if err := api.agentAction(c, vm, http.MethodPost, "/v1/exec", req, &result, policy); err != nil {
+		return
+	}
+	api.auditExec(c, vm, req.Command, &result, time.Since(started))
+	c.JSON(http.StatusOK, result)
+}
+
+// vmExecWebSocket runs a command in the guest over a WebSocket relayed to the
+// agent. The first client message is the JSON exec request; binary messages
+// then carry stdin, stdout and stderr behind a stream byte, and the last
+// message is the JSON exec result.
+func (api *apiServer) vmExecWebSocket(c *gin.Context) {
+	vm, ok := api.resolveVM(c)
+	if !ok {
+		return
+	}
+	ctx := c.Request.Context()
+	target := "ws://" + agentHost(vm, api.agentPort) + "/v1/exec/stream"
+	dialer := websocket.Dialer{
+		Proxy:            agentProxy,
+		NetDialContext:   dialAgent,
+		HandshakeTimeout: 30 * time.Second,
+	}
+	header := http.Header{}
+	tracing.Inject(ctx, header)
+	agentConn, resp, err := dialer.DialContext(ctx, target, header)
+	if resp != nil {
+		resp.Body.Close()
+	}
+	if err != nil {
+		api.logger.Error("exec ws dial", "vm", vm.Name, "error", err)
+		respondError(c, http.StatusBadGateway, CodeUpstreamError, "failed to connect to agent")
+		return
+	}
+	defer agentConn.Close()
+
+	upgrader := websocket.Upgrader{CheckOrigin: func(r *http.Request) bool { return true }}
+	clientConn, err := upgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		api.logger.Error("exec ws upgrade", "vm", vm.Name, "error", err)
+		return
+	}
+	defer clientConn.Close()
+
+	// Read the request here so the session is audited with its command.
+	_ = clientConn.SetReadDeadline(time.Now().Add(execStartTimeout))
+	messageType, first, err := clientConn.ReadMessage()
+	_ = clientConn.SetReadDeadline(time.Time{})
+	var req pluginspec.ExecRequest
+	if err == nil && messageType != websocket.TextMessage {
+		err = errors.New("first message must be the JSON exec request")
+	}
+	if err == nil {
+		err = json.Unmarshal(first, &req)
+	}
+	if err == nil {
+		err = req.Validate()
+	}
+	if err != nil {
+		data, _ := json.Marshal(pluginspec.ExecResult{ExitCode: -1, Error: err.Error(), Code: CodeInvalidRequest})
+		_ = clientConn.WriteMessage(websocket.TextMessage, data)
+		writeWebSocketClose(clientConn, websocket.ClosePolicyViolation, "invalid exec request")
+		writeWebSocketClose(agentConn, websocket.CloseNormalClosure, "")
+		return
+	}
+	if err := agentConn.WriteMessage(websocket.TextMessage, first); err != nil {
+		writeWebSocketClose(clientConn, websocket.CloseTryAgainLater, "agent closed")
+		return
+	}
+
+	started := time.Now()
+	var result *pluginspec.ExecResult
+	errCh := make(chan error, 2)
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go pumpWebSocket(ctx, api.logger, "client->agent", clientConn, agentConn, &wg, errCh)
+	go func() {
+		defer wg.Done()
+		for {
+			messageType, payload, err := agentConn.ReadMessage()
+			if err != nil {
+				// Relay how the agent ended the session, e.g. a refused command.
+				var closed *websocket.CloseError
+				if errors.As(err, &closed) && closed.Code != websocket.CloseNoStatusReceived {
+					writeWebSocketClose(clientConn, closed.Code, closed.Text)
+				}
+				errCh <- fmt.Errorf("agent->client read: %w", err)
+				return
+			}
+			if messageType == websocket.TextMessage {
+				var final pluginspec.ExecResult
+				if json.Unmarshal(payload, &final) == nil {
+					result = &final
+				}
+			}
+			if err := clientConn.WriteMessage(messageType, payload); err != nil {
+				errCh <- fmt.Errorf("agent->client write: %w", err)
+				return
+			}
+		}
+	}()
+
+	var proxyErr error
+	select {
+	case <-ctx.Done():
+		proxyErr = ctx.Err()
+	case proxyErr = <-errCh:
+	}
+	writeWebSocketClose(agentConn, websocket.CloseNormalClosure, "")
+	writeWebSocketClose(clientConn, websocket.CloseNormalClosure, "")
+	// Closing both ends unblocks whichever pump is still reading.
+	_ = agentConn.Close()
+	_ = clientConn.Close()
+	wg.Wait()
+
+	if proxyErr != nil && !errors.Is(proxyErr, context.Canceled) && !errors.Is(proxyErr, net.ErrClosed) && !websocket.IsCloseError(proxyErr, websocket.CloseNormalClosure, websocket.CloseGoingAway) {
+		api.logger.Debug("exec proxy closed", "vm", vm.Name, "error", proxyErr)
+	}
+	api.auditExec(c, vm, req.Command, result, time.Since(started))
+}
+
+// auditExec records a command run in vm; result is nil when the session
+// ended before the agent reported one.
+func (api *apiServer) auditExec(c *gin.Context, vm *db.VM, command []string, result *pluginspec.ExecResult, elapsed time.Duration) {
+	message := strings.Join(command, " ")
+	switch {
+	case result == nil:
+		message += " (disconnected)"
+	case result.TimedOut:
+		message += " (timed out)"
+	default:
+		message += fmt.Sprintf(" (exit %d)", result.ExitCode)
+	}
+	// The request context is already cancelled when a client hangs up.
+	api.recordAudit(context.Background(), db.AuditEvent{
+		Action:   auditExec,
+		Target:   vm.Name,
+		Actor:    anonymousConsoleUser,
+		ClientIP: c.ClientIP(),
+		Message:  message,
+		Duration: elapsed,
+	})
+}
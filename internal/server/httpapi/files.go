@@ -0,0 +1,203 @@
+// Copyright (c) 2025 HYPR. PTE. LTD.
+//
+// Business Source License 1.1
+// See LICENSE file in the project root for details.
+
+package httpapi
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// defaultFileLimitMB caps files transferred to or from guests unless
+	// VOLANT_FILE_MAX_SIZE_MB says otherwise.
+	defaultFileLimitMB = 1024
+
+	checksumHeader = "X-Volant-Checksum-Sha256"
+	fileModeHeader = "X-Volant-File-Mode"
+)
+
+// fileTransferResponse describes a file written to a guest.
+type fileTransferResponse struct {
+	Path   string `json:"path"`
+	Size   int64  `json:"size"`
+	SHA256 string `json:"sha256"`
+}
+
+// fileLimitFromEnv reads VOLANT_FILE_MAX_SIZE_MB, the largest file moved
+// through the files API, in bytes.
+func fileLimitFromEnv(logger *slog.Logger) int64 {
+	raw := strings.TrimSpace(os.Getenv("VOLANT_FILE_MAX_SIZE_MB"))
+	if raw == "" {
+		return defaultFileLimitMB << 20
+	}
+	size, err := strconv.ParseInt(raw, 10, 64)
+	if err != nil || size < 1 || size > 1<<30 {
+		logger.Warn("invalid VOLANT_FILE_MAX_SIZE_MB, using default", "value", raw, "default", defaultFileLimitMB)
+		return defaultFileLimitMB << 20
+	}
+	return size << 20
+}
+
+// agentFilePath returns the agent path of a guest file, escaped so that any
+// file name survives the trip.
+func agentFilePath(guestPath string) string {
+	return (&url.URL{Path: "/v1/files/" + strings.TrimPrefix(guestPath, "/")}).EscapedPath()
+}
+
+// guestFilePath returns the guest path of a files route, or false after
+// answering 400 when it names none.
+func guestFilePath(c *gin.Context) (string, bool) {
+	path := c.Param("path")
+	if strings.Trim(path, "/") == "" {
+		respondError(c, http.StatusBadRequest, CodeInvalidRequest, "file path required")
+		return "", false
+	}
+	return path, true
+}
+
+// putVMFile streams the request body to a file in the guest. The file only
+// replaces what was there once it has been fully written and its SHA-256
+// matches the X-Volant-Checksum-Sha256 header, when the caller sent one.
+func (api *apiServer) putVMFile(c *gin.Context) {
+	vm, ok := api.resolveVMByName(c, c.Param("name"))
+	if !ok {
+		return
+	}
+	path, ok := guestFilePath(c)
+	if !ok {
+		return
+	}
+	if c.Request.ContentLength > api.fileLimit {
+		respondError(c, http.StatusRequestEntityTooLarge, CodeFileTooLarge, fmt.Sprintf("file exceeds the limit of %d bytes", api.fileLimit))
+		return
+	}
+	want := strings.ToLower(strings.TrimSpace(c.GetHeader(checksumHeader)))
+	if want != "" {
+		if decoded, err := hex.DecodeString(want); err != nil || len(decoded) != sha256.Size {
+			respondError(c, http.StatusBadRequest, CodeInvalidRequest, "invalid "+checksumHeader+": expected a hex SHA-256")
+			return
+		}
+	}
+
+	clearDeadlines(c)
+	hash := sha256.New()
+	body := callerBody{r: io.TeeReader(http.MaxBytesReader(c.Writer, c.Request.Body, api.fileLimit), hash)}
+	target := api.agentURL(vm, agentFilePath(path))
+	resp, err := api.doAgent(c.Request.Context(), agentCallPolicy{}, true, func(ctx context.Context) (*http.Request, error) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodPut, target, body)
+		if err != nil {
+			return nil, err
+		}
+		req.ContentLength = c.Request.ContentLength
+		req.Header.Set("Content-Type", "application/octet-stream")
+		if want != "" {
+			req.Header.Set(checksumHeader, want)
+		}
+		if mode := c.GetHeader(fileModeHeader); mode != "" {
+			req.Header.Set(fileModeHeader, mode)
+		}
+		return req, nil
+	})
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			respondError(c, http.StatusRequestEntityTooLarge, CodeFileTooLarge, fmt.Sprintf("file exceeds the limit of %d bytes", api.fileLimit))
+			return
+		}
+		var bodyErr *callerBodyError
+		if errors.As(err, &bodyErr) {
+			respondError(c, http.StatusBadRequest, CodeInvalidRequest, "read upload: "+bodyErr.Error())
+			return
+		}
+		api.logger.Error("agent file upload", "vm", vm.Name, "path", path, "error", err)
+		respondAgentCallError(c, err)
+		return
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		_ = respondAgentError(c, resp)
+		return
+	}
+
+	var written fileTransferResponse
+	if err := json.NewDecoder(resp.Body).Decode(&written); err != nil {
+		respondError(c, http.StatusBadGateway, CodeUpstreamError, "failed to decode agent response")
+		return
+	}
+	// The agent hashed what it wrote; this checks nothing was lost between.
+	if sum := hex.EncodeToString(hash.Sum(nil)); written.SHA256 != sum {
+		api.logger.Error("agent file upload checksum", "vm", vm.Name, "path", path, "sent", sum, "written", written.SHA256)
+		respondError(c, http.StatusBadGateway, CodeUpstreamError, fmt.Sprintf("agent wrote sha256 %s, sent %s", written.SHA256, sum))
+		return
+	}
+	c.JSON(resp.StatusCode, written)
+}
+
+// getVMFile streams a file from the guest. The X-Volant-Checksum-Sha256
+// header carries the SHA-256 of the whole file, also for Range requests.
+func (api *apiServer) getVMFile(c *gin.Context) {
+	vm, ok := api.resolveVMByName(c, c.Param("name"))
+	if !ok {
+		return
+	}
+	path, ok := guestFilePath(c)
+	if !ok {
+		return
+	}
+
+	clearDeadlines(c)
+	target := api.agentURL(vm, agentFilePath(path))
+	resp, err := api.doAgent(c.Request.Context(), agentCallPolicy{}, true, func(ctx context.Context) (*http.Request, error) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, target, nil)
+		if err != nil {
+			return nil, err
+		}
+		for _, key := range []string{"Range", "If-Range", "If-Modified-Since", "If-None-Match"} {
+			if value := c.GetHeader(key); value != "" {
+				req.Header.Set(key, value)
+			}
+		}
+		return req, nil
+	})
+	if err != nil {
+		api.logger.Error("agent file download", "vm", vm.Name, "path", path, "error", err)
+		respondAgentCallError(c, err)
+		return
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 400 {
+		_ = respondAgentError(c, resp)
+		return
+	}
+	if resp.StatusCode == http.StatusOK && resp.ContentLength > api.fileLimit {
+		respondError(c, http.StatusRequestEntityTooLarge, CodeFileTooLarge, fmt.Sprintf("file exceeds the limit of %d bytes", api.fileLimit))
+		return
+	}
+
+	for _, key := range []string{"Content-Type", "Content-Length", "Content-Range", "Accept-Ranges", "Last-Modified", checksumHeader, fileModeHeader} {
+		if value := resp.Header.Get(key); value != "" {
+			c.Header(key, value)
+		}
+	}
+	c.Status(resp.StatusCode)
+	if _, err := io.Copy(c.Writer, io.LimitReader(resp.Body, api.fileLimit)); err != nil && c.Request.Context().Err() == nil {
+		// Headers are out, so the caller only sees a short body.
+		api.logger.Warn("agent file download", "vm", vm.Name, "path", path, "error", err)
+	}
+}
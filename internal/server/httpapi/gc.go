@@ -0,0 +1,53 @@
+// Copyright (c) 2025 HYPR. PTE. LTD.
+//
+// Business Source License 1.1
+// See LICENSE file in the project root for details.
+
+package httpapi
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/volantvm/volant/internal/server/orchestrator"
+)
+
+type gcRunResponse struct {
+	StartedAt  time.Time `json:"started_at"`
+	FinishedAt time.Time `json:"finished_at"`
+	Taps       []string  `json:"taps"`
+	Sockets    []string  `json:"sockets"`
+	SeedImages []string  `json:"seed_images"`
+	Pending    int       `json:"pending"`
+	Errors     []string  `json:"errors,omitempty"`
+}
+
+type gcStatusResponse struct {
+	IntervalSeconds int            `json:"interval_seconds"`
+	LastRun         *gcRunResponse `json:"last_run"`
+}
+
+func (api *apiServer) getGarbageCollection(c *gin.Context) {
+	resp := gcStatusResponse{IntervalSeconds: int(orchestrator.GCInterval / time.Second)}
+	if report := api.engine.LastGC(); report != nil {
+		resp.LastRun = &gcRunResponse{
+			StartedAt:  report.StartedAt,
+			FinishedAt: report.FinishedAt,
+			Taps:       nonNilStrings(report.Taps),
+			Sockets:    nonNilStrings(report.Sockets),
+			SeedImages: nonNilStrings(report.SeedImages),
+			Pending:    report.Pending,
+			Errors:     report.Errors,
+		}
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+func nonNilStrings(values []string) []string {
+	if values == nil {
+		return []string{}
+	}
+	return values
+}
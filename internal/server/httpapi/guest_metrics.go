@@ -0,0 +1,273 @@
+// Copyright (c) 2025 HYPR. PTE. LTD.
+//
+// Business Source License 1.1
+// See LICENSE file in the project root for details.
+
+package httpapi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/volantvm/volant/internal/pluginspec"
+	"github.com/volantvm/volant/internal/server/db"
+)
+
+const (
+	// defaultGuestMetricsInterval is how often running VMs are sampled
+	// unless VOLANT_GUEST_METRICS_INTERVAL says otherwise.
+	defaultGuestMetricsInterval = 30 * time.Second
+	// guestMetricsTimeout bounds each agent's answer.
+	guestMetricsTimeout = 10 * time.Second
+	// guestMetricsWorkers caps the agents polled at once.
+	guestMetricsWorkers = 8
+)
+
+// guestSample is the latest metrics reported by a VM's agent.
+type guestSample struct {
+	plugin  string
+	metrics pluginspec.GuestMetrics
+}
+
+// guestMetricsCache keeps the latest sample of each running VM for the
+// Prometheus endpoint.
+type guestMetricsCache struct {
+	mu      sync.Mutex
+	samples map[string]guestSample
+}
+
+func newGuestMetricsCache() *guestMetricsCache {
+	return &guestMetricsCache{samples: make(map[string]guestSample)}
+}
+
+func (g *guestMetricsCache) store(vm *db.VM, metrics pluginspec.GuestMetrics) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.samples[vm.Name] = guestSample{plugin: vm.Runtime, metrics: metrics}
+}
+
+func (g *guestMetricsCache) drop(name string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	delete(g.samples, name)
+}
+
+// retain drops the samples of VMs not in running.
+func (g *guestMetricsCache) retain(running map[string]struct{}) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	for name := range g.samples {
+		if _, ok := running[name]; !ok {
+			delete(g.samples, name)
+		}
+	}
+}
+
+// snapshot returns the samples ordered by VM name.
+func (g *guestMetricsCache) snapshot() ([]string, map[string]guestSample) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	names := make([]string, 0, len(g.samples))
+	samples := make(map[string]guestSample, len(g.samples))
+	for name, sample := range g.samples {
+		names = append(names, name)
+		samples[name] = sample
+	}
+	sort.Strings(names)
+	return names, samples
+}
+
+// guestMetricsIntervalFromEnv reads VOLANT_GUEST_METRICS_INTERVAL; 0
+// disables background polling.
+func guestMetricsIntervalFromEnv(logger *slog.Logger) time.Duration {
+	raw := strings.TrimSpace(os.Getenv("VOLANT_GUEST_METRICS_INTERVAL"))
+	if raw == "" {
+		return defaultGuestMetricsInterval
+	}
+	if raw == "0" {
+		return 0
+	}
+	interval, err := time.ParseDuration(raw)
+	if err != nil || interval < time.Second {
+		logger.Warn("invalid VOLANT_GUEST_METRICS_INTERVAL, using default", "value", raw, "default", defaultGuestMetricsInterval)
+		return defaultGuestMetricsInterval
+	}
+	return interval
+}
+
+// pollGuestMetrics samples every running VM each interval.
+func (api *apiServer) pollGuestMetrics(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			api.collectGuestMetrics(ctx)
+		}
+	}
+}
+
+// collectGuestMetrics samples the running VMs. A VM whose agent does not
+// answer keeps no sample, so its series go stale rather than report old
+// values.
+func (api *apiServer) collectGuestMetrics(ctx context.Context) {
+	vms, err := api.engine.ListVMs(ctx)
+	if err != nil {
+		api.logger.Warn("guest metrics: list vms", "error", err)
+		return
+	}
+	running := make(map[string]struct{}, len(vms))
+	sem := make(chan struct{}, guestMetricsWorkers)
+	var wg sync.WaitGroup
+	for i := range vms {
+		vm := &vms[i]
+		if vm.Status != db.VMStatusRunning || !agentReachable(vm) {
+			continue
+		}
+		running[vm.Name] = struct{}{}
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+			metrics, err := api.fetchGuestMetrics(ctx, vm)
+			if err != nil {
+				api.logger.Debug("guest metrics", "vm", vm.Name, "error", err)
+				api.guests.drop(vm.Name)
+				return
+			}
+			api.guests.store(vm, *metrics)
+		}()
+	}
+	wg.Wait()
+	api.guests.retain(running)
+}
+
+// fetchGuestMetrics asks vm's agent for a sample.
+func (api *apiServer) fetchGuestMetrics(ctx context.Context, vm *db.VM) (*pluginspec.GuestMetrics, error) {
+	target := api.agentURL(vm, "/v1/metrics")
+	resp, err := api.doAgent(ctx, agentCallPolicy{timeout: guestMetricsTimeout}, false, func(ctx context.Context) (*http.Request, error) {
+		return http.NewRequestWithContext(ctx, http.MethodGet, target, nil)
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+		return nil, fmt.Errorf("agent returned status %d", resp.StatusCode)
+	}
+	var metrics pluginspec.GuestMetrics
+	if err := json.NewDecoder(resp.Body).Decode(&metrics); err != nil {
+		return nil, fmt.Errorf("decode guest metrics: %w", err)
+	}
+	return &metrics, nil
+}
+
+// getVMGuestMetrics samples the guest's CPU, memory, disk and process usage
+// through its agent, as opposed to the host's view of the VM process.
+func (api *apiServer) getVMGuestMetrics(c *gin.Context) {
+	vm, ok := api.resolveVM(c)
+	if !ok {
+		return
+	}
+	var metrics pluginspec.GuestMetrics
+	if err := api.agentAction(c, vm, http.MethodGet, "/v1/metrics", nil, &metrics, agentCallPolicy{timeout: guestMetricsTimeout}); err != nil {
+		return
+	}
+	api.guests.store(vm, metrics)
+	c.JSON(http.StatusOK, metrics)
+}
+
+// getMetrics renders the latest guest samples in the Prometheus text format.
+func (api *apiServer) getMetrics(c *gin.Context) {
+	names, samples := api.guests.snapshot()
+	var b strings.Builder
+	family := func(name, kind, help string, each func(labels string, sample guestSample)) {
+		fmt.Fprintf(&b, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, kind)
+		for _, vm := range names {
+			sample := samples[vm]
+			each(`vm="`+promEscape(vm)+`",plugin="`+promEscape(sample.plugin)+`"`, sample)
+		}
+	}
+	gauge := func(name, help string, value func(m *pluginspec.GuestMetrics) float64) {
+		family(name, "gauge", help, func(labels string, sample guestSample) {
+			writePromSample(&b, name, labels, value(&sample.metrics))
+		})
+	}
+
+	gauge("volant_guest_sample_timestamp_seconds", "Time the guest metrics were sampled.", func(m *pluginspec.GuestMetrics) float64 {
+		return float64(m.Timestamp.UnixMilli()) / 1000
+	})
+	gauge("volant_guest_uptime_seconds", "Time since the guest booted.", func(m *pluginspec.GuestMetrics) float64 { return m.UptimeSeconds })
+	gauge("volant_guest_cpus", "CPUs seen by the guest.", func(m *pluginspec.GuestMetrics) float64 { return float64(m.CPU.Count) })
+	gauge("volant_guest_cpu_usage_percent", "Share of guest CPU capacity used since the previous sample.", func(m *pluginspec.GuestMetrics) float64 { return m.CPU.Percent })
+	family("volant_guest_cpu_seconds_total", "counter", "CPU time spent by the guest, summed over CPUs.", func(labels string, sample guestSample) {
+		cpu := sample.metrics.CPU
+		for _, mode := range []struct {
+			name  string
+			value float64
+		}{{"user", cpu.UserSeconds}, {"system", cpu.SystemSeconds}, {"idle", cpu.IdleSeconds}, {"iowait", cpu.IOWaitSeconds}, {"steal", cpu.StealSeconds}} {
+			writePromSample(&b, "volant_guest_cpu_seconds_total", labels+`,mode="`+mode.name+`"`, mode.value)
+		}
+	})
+	gauge("volant_guest_memory_total_bytes", "Memory seen by the guest kernel.", func(m *pluginspec.GuestMetrics) float64 { return float64(m.Memory.TotalBytes) })
+	gauge("volant_guest_memory_available_bytes", "Guest memory available without swapping.", func(m *pluginspec.GuestMetrics) float64 { return float64(m.Memory.AvailableBytes) })
+	gauge("volant_guest_memory_used_bytes", "Guest memory in use, excluding reclaimable caches.", func(m *pluginspec.GuestMetrics) float64 { return float64(m.Memory.UsedBytes) })
+	gauge("volant_guest_memory_cached_bytes", "Guest page cache.", func(m *pluginspec.GuestMetrics) float64 { return float64(m.Memory.CachedBytes) })
+	gauge("volant_guest_swap_total_bytes", "Guest swap space.", func(m *pluginspec.GuestMetrics) float64 { return float64(m.Memory.SwapTotalBytes) })
+	gauge("volant_guest_swap_free_bytes", "Unused guest swap space.", func(m *pluginspec.GuestMetrics) float64 { return float64(m.Memory.SwapFreeBytes) })
+	for _, fs := range []struct {
+		name, help string
+		value      func(d pluginspec.GuestDisk) uint64
+	}{
+		{"volant_guest_filesystem_size_bytes", "Size of a guest filesystem.", func(d pluginspec.GuestDisk) uint64 { return d.TotalBytes }},
+		{"volant_guest_filesystem_free_bytes", "Free space on a guest filesystem available to unprivileged users.", func(d pluginspec.GuestDisk) uint64 { return d.FreeBytes }},
+	} {
+		family(fs.name, "gauge", fs.help, func(labels string, sample guestSample) {
+			for _, disk := range sample.metrics.Disks {
+				writePromSample(&b, fs.name, labels+`,device="`+promEscape(disk.Device)+`",mountpoint="`+promEscape(disk.MountPoint)+`",fstype="`+promEscape(disk.FSType)+`"`, float64(fs.value(disk)))
+			}
+		})
+	}
+	gauge("volant_guest_processes", "Processes in the guest.", func(m *pluginspec.GuestMetrics) float64 { return float64(m.Processes.Total) })
+	gauge("volant_guest_processes_running", "Guest processes on a CPU or waiting for one.", func(m *pluginspec.GuestMetrics) float64 { return float64(m.Processes.Running) })
+	for i, window := range []string{"1", "5", "15"} {
+		gauge("volant_guest_load"+window, "Guest "+window+"-minute load average.", func(m *pluginspec.GuestMetrics) float64 { return m.Load[i] })
+	}
+	family("volant_guest_plugin_stat", "gauge", "Stats reported by the plugin's metrics endpoint in the guest.", func(labels string, sample guestSample) {
+		keys := make([]string, 0, len(sample.metrics.Plugin))
+		for key := range sample.metrics.Plugin {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			writePromSample(&b, "volant_guest_plugin_stat", labels+`,stat="`+promEscape(key)+`"`, sample.metrics.Plugin[key])
+		}
+	})
+
+	c.Data(http.StatusOK, "text/plain; version=0.0.4; charset=utf-8", []byte(b.String()))
+}
+
+func writePromSample(b *strings.Builder, name, labels string, value float64) {
+	fmt.Fprintf(b, "%s{%s} %s\n", name, labels, strconv.FormatFloat(value, 'g', -1, 64))
+}
+
+// promEscape escapes a Prometheus label value.
+func promEscape(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(value)
+}
@@ -21,27 +21,36 @@ import (
 	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/gorilla/websocket"
 
-	"github.com/volantvm/volant/internal/drift/routes"
 	"github.com/volantvm/volant/internal/pluginspec"
+	"github.com/volantvm/volant/internal/server/admission"
+	"github.com/volantvm/volant/internal/server/backup"
+	"github.com/volantvm/volant/internal/server/config"
 	"github.com/volantvm/volant/internal/server/db"
 	"github.com/volantvm/volant/internal/server/devicemanager"
 	"github.com/volantvm/volant/internal/server/driftclient"
 	"github.com/volantvm/volant/internal/server/eventbus"
+	"github.com/volantvm/volant/internal/server/labels"
+	"github.com/volantvm/volant/internal/server/mcp"
 	"github.com/volantvm/volant/internal/server/orchestrator"
+	"github.com/volantvm/volant/internal/server/orchestrator/doctor"
 	orchestratorevents "github.com/volantvm/volant/internal/server/orchestrator/events"
+	"github.com/volantvm/volant/internal/server/orchestrator/hostfeatures"
 	"github.com/volantvm/volant/internal/server/orchestrator/vmconfig"
+	"github.com/volantvm/volant/internal/server/orchestrator/vmlogs"
 	"github.com/volantvm/volant/internal/server/plugins"
+	"github.com/volantvm/volant/internal/server/scheduler"
+	"github.com/volantvm/volant/internal/server/secrets"
+	"github.com/volantvm/volant/internal/server/tracing"
+	"github.com/volantvm/volant/internal/server/webhooks"
 )
 
-const (
-	agentDefaultPort         = 8080
-	agentDevToolsDefaultPort = 9222
-)
+const agentDevToolsDefaultPort = 9222
 
 var hopHeaders = map[string]struct{}{
 	"connection":          {},
@@ -55,83 +64,235 @@ var hopHeaders = map[string]struct{}{
 	"upgrade":             {},
 }
 
-func New(logger *slog.Logger, engine orchestrator.Engine, bus eventbus.Bus, plugins *plugins.Registry, drift *driftclient.Client) http.Handler {
+func New(logger *slog.Logger, engine orchestrator.Engine, bus eventbus.Bus, plugins *plugins.Registry, drift *driftclient.Client, sched *scheduler.Scheduler, backups *backup.Manager, hooks *webhooks.Manager, secretStore *secrets.Manager, reloader *config.Reloader, checker *doctor.Checker) (http.Handler, error) {
+	// The spec is generated from code, so a validator that cannot be built
+	// is a bug; serving without it would skip the parameter checks handlers
+	// rely on.
+	validator, err := newRequestValidator()
+	if err != nil {
+		return nil, fmt.Errorf("httpapi: build request validator from openapi spec: %w", err)
+	}
+
 	gin.SetMode(gin.ReleaseMode)
 	r := gin.New()
 	r.Use(gin.Recovery())
+	r.Use(tracingMiddleware())
 	r.Use(requestLogger(logger))
 
-	// CORS (optional, for browser-based UI)
-	if raw := os.Getenv("VOLANT_CORS_ORIGINS"); raw != "" {
-		origins := strings.Split(raw, ",")
-		r.Use(corsMiddleware(logger, origins))
-	}
-
-	if cidr := os.Getenv("VOLANT_API_ALLOW_CIDR"); cidr != "" {
-		allowList := strings.Split(cidr, ",")
-		r.Use(ipFilterMiddleware(logger, allowList))
-	}
-
-	if apiKey := os.Getenv("VOLANT_API_KEY"); apiKey != "" {
-		r.Use(apiKeyMiddleware(apiKey))
+	// CORS origins, the client CIDR allowlist and the API key can change at
+	// runtime, so one middleware enforces whichever policy is current.
+	access := &atomic.Pointer[accessPolicy]{}
+	if reloader != nil {
+		reloader.Subscribe(func(settings config.Reloadable) {
+			access.Store(newAccessPolicy(logger, settings))
+		})
+	} else {
+		settings, err := config.ReloadableFromEnv()
+		if err != nil {
+			logger.Warn("load access settings", "error", err)
+		}
+		access.Store(newAccessPolicy(logger, settings))
 	}
+	r.Use(accessMiddleware(logger, access))
 
 	if err := loadStoredPlugins(engine, logger, plugins); err != nil {
 		logger.Warn("load stored plugins", "error", err)
 	}
 
-	api := &apiServer{
-		logger:      logger,
-		engine:      engine,
-		bus:         bus,
-		agentPort:   agentDefaultPort,
-		agentClient: &http.Client{Timeout: 120 * time.Second},
-		plugins:     plugins,
-		drift:       drift,
+	admit, err := admission.FromEnv(logger)
+	if err != nil {
+		// Fail closed: a broken policy config must not admit everything.
+		logger.Error("load admission webhooks, rejecting admitted operations", "error", err)
 	}
 
+	breakers := newAgentBreakers(newAgentTransport())
+	api := &apiServer{
+		logger:       logger,
+		engine:       engine,
+		bus:          bus,
+		agentPort:    orchestrator.AgentPort,
+		agentClient:  newAgentClient(breakers),
+		agentLong:    newAgentLongClient(breakers),
+		breakers:     breakers,
+		fileLimit:    fileLimitFromEnv(logger),
+		agentDir:     agentDirFromEnv(),
+		plugins:      plugins,
+		drift:        drift,
+		scheduler:    sched,
+		backups:      backups,
+		webhooks:     hooks,
+		secrets:      secretStore,
+		console:      consoleAccessFromEnv(logger),
+		events:       watchRecentEvents(bus),
+		agui:         &aguiHub{bus: bus},
+		guests:       newGuestMetricsCache(),
+		admission:    admit,
+		admissionErr: err,
+		reloader:     reloader,
+		doctor:       checker,
+	}
+	if interval := guestMetricsIntervalFromEnv(logger); interval > 0 && engine != nil {
+		go api.pollGuestMetrics(context.Background(), interval)
+	}
+	// MCP tool calls are served by this router, so they pass through the
+	// same middleware as direct API requests.
+	api.mcp = mcp.New(mcp.Handler(r), mcp.Options{}, logger)
+
 	r.GET("/healthz", func(c *gin.Context) {
 		c.JSON(http.StatusOK, gin.H{"status": "ok"})
 	})
 
-	// Serve OpenAPI spec at /openapi (JSON)
+	// Guest metrics in the Prometheus text format.
+	r.GET("/metrics", api.getMetrics)
+
+	// Serve OpenAPI spec at /openapi (JSON, or YAML with ?format=yaml)
 	r.GET("/openapi", func(c *gin.Context) {
 		api.serveOpenAPI(c.Writer, c.Request)
 	})
 
 	v1 := r.Group("/api/v1")
+	v1.Use(validator.middleware())
 	{
 		v1.GET("/system/status", api.systemStatus)
 		v1.GET("/system/info", api.systemInfo)
 		v1.GET("/system/summary", api.systemSummary)
+		v1.GET("/system/capacity", api.systemCapacity)
+		v1.GET("/system/doctor", api.systemDoctor)
+		v1.GET("/system/backups", api.listBackups)
+		v1.POST("/system/backups", api.createBackup)
+		v1.POST("/system/restore", api.restoreBackup)
+		v1.GET("/system/gc", api.getGarbageCollection)
+		v1.GET("/system/agents", api.getAgentStats)
+		v1.POST("/system/reload", api.reloadConfig)
+		v1.GET("/system/maintenance", api.getHostMaintenance)
+		v1.POST("/system/maintenance", api.enterHostMaintenance)
+		v1.POST("/system/maintenance/exit", api.exitHostMaintenance)
+		v1.GET("/agent/artifacts", api.listAgentArtifacts)
+		v1.PUT("/agent/artifacts/:version/:arch", api.putAgentArtifact)
+		v1.GET("/agent/artifacts/:version/:arch", api.getAgentArtifact)
+		v1.DELETE("/agent/artifacts/:version/:arch", api.deleteAgentArtifact)
+		v1.GET("/dashboard", api.getDashboard)
 		v1.POST("/mcp", api.handleMCP)
+		v1.GET("/mcp", api.handleMCP)
+		v1.POST("/transactions", api.applyTransaction)
+		v1.POST("/apply", api.applyResources)
+		v1.POST("/bulk/vms/:action", api.bulkVMAction)
+
+		v1.GET("/operations/:id", api.getOperation)
 
 		vms := v1.Group("/vms")
 		{
 			vms.GET("", api.listVMs)
-			vms.POST("", api.createVM)
+			vms.POST("", api.idempotent(api.createVM))
+			vms.POST("claim", api.claimVM)
 			vms.GET(":name", api.getVM)
 			vms.GET(":name/config", api.getVMConfig)
 			vms.GET(":name/config/history", api.getVMConfigHistory)
+			vms.GET(":name/ports", api.getVMPorts)
+			vms.GET(":name/bundles", api.getVMBundles)
+			vms.GET(":name/secrets", api.getVMSecrets)
+			vms.GET(":name/ssh", api.getVMSSH)
+			vms.GET(":name/ssh/key", api.getVMSSHKey)
+			vms.GET(":name/console/log", api.vmConsoleLog)
+			vms.GET(":name/logs", api.getVMLogs)
+			vms.GET(":name/events", api.getVMEvents)
 			vms.PATCH(":name/config", api.updateVMConfig)
+			vms.PATCH(":name/labels", api.updateVMLabels)
 			vms.DELETE(":name", api.deleteVM)
 			vms.POST(":name/start", api.startVM)
 			vms.POST(":name/stop", api.stopVM)
 			vms.POST(":name/restart", api.restartVM)
+			vms.POST(":name/duplicate", api.duplicateVM)
+			vms.POST(":name/clone", api.cloneVM)
 			vms.GET(":name/openapi", api.getVMOpenAPI)
+			vms.GET(":name/sysinfo", api.getVMSysInfo)
+			vms.GET(":name/guest-metrics", api.getVMGuestMetrics)
 			vms.Any(":name/agent/*path", api.proxyAgent)
 			vms.POST(":name/actions/:plugin/:action", api.postVMPluginAction)
+			vms.POST(":name/agui/events", api.postVMAGUIEvents)
+			vms.PUT(":name/files/*path", api.putVMFile)
+			vms.GET(":name/files/*path", api.getVMFile)
+			vms.POST(":name/exec", api.postVMExec)
 		}
 
 		deployments := v1.Group("/deployments")
 		{
 			deployments.GET("", api.listDeployments)
-			deployments.POST("", api.createDeployment)
+			deployments.POST("", api.idempotent(api.createDeployment))
 			deployments.GET(":name", api.getDeployment)
 			deployments.PATCH(":name", api.patchDeployment)
+			deployments.PUT(":name/config", api.updateDeploymentConfig)
 			deployments.DELETE(":name", api.deleteDeployment)
 		}
 
+		warmPools := v1.Group("/warm-pools")
+		{
+			warmPools.GET("", api.listWarmPools)
+			warmPools.GET(":plugin", api.getWarmPool)
+			warmPools.PUT(":plugin", api.putWarmPool)
+			warmPools.DELETE(":plugin", api.deleteWarmPool)
+		}
+
+		schedules := v1.Group("/schedules")
+		{
+			schedules.GET("", api.listSchedules)
+			schedules.POST("", api.createSchedule)
+			schedules.GET(":name", api.getSchedule)
+			schedules.DELETE(":name", api.deleteSchedule)
+			schedules.POST(":name/enabled", api.setScheduleEnabled)
+			schedules.POST(":name/run", api.runSchedule)
+			schedules.GET(":name/runs", api.getScheduleRuns)
+		}
+
+		webhookRoutes := v1.Group("/webhooks")
+		{
+			webhookRoutes.GET("", api.listWebhooks)
+			webhookRoutes.POST("", api.createWebhook)
+			webhookRoutes.GET(":name", api.getWebhook)
+			webhookRoutes.DELETE(":name", api.deleteWebhook)
+			webhookRoutes.POST(":name/enabled", api.setWebhookEnabled)
+			webhookRoutes.GET(":name/deliveries", api.getWebhookDeliveries)
+		}
+
+		secretRoutes := v1.Group("/secrets")
+		{
+			secretRoutes.GET("", api.listSecrets)
+			secretRoutes.POST("", api.putSecret)
+			secretRoutes.GET(":name", api.getSecret)
+			secretRoutes.PUT(":name", api.updateSecret)
+			secretRoutes.DELETE(":name", api.deleteSecret)
+		}
+
+		maintenance := v1.Group("/maintenance")
+		{
+			maintenance.GET("", api.listMaintenanceWindows)
+			maintenance.POST("", api.createMaintenanceWindow)
+			maintenance.GET(":name", api.getMaintenanceWindow)
+			maintenance.DELETE(":name", api.deleteMaintenanceWindow)
+			maintenance.POST(":name/end", api.endMaintenanceWindow)
+		}
+
+		networks := v1.Group("/networks")
+		{
+			networks.GET("", api.listNetworks)
+			networks.POST("", api.createNetwork)
+			networks.GET(":name", api.getNetwork)
+			networks.DELETE(":name", api.deleteNetwork)
+		}
+
+		bundles := v1.Group("/config-bundles")
+		{
+			bundles.GET("", api.listConfigBundles)
+			bundles.POST("", api.createConfigBundle)
+			bundles.GET(":name", api.getConfigBundle)
+			bundles.PUT(":name", api.updateConfigBundle)
+			bundles.DELETE(":name", api.deleteConfigBundle)
+		}
+
+		v1.GET("/dhcp/leases", api.listDHCPLeases)
+		v1.GET("/nodes/:node/artifacts", api.listNodeArtifacts)
+		v1.GET("/nodes/:node/artifacts/*name", api.getNodeArtifact)
+
 		pluginsGroup := v1.Group("/plugins")
 		{
 			pluginsGroup.GET("", api.listPlugins)
@@ -140,6 +301,8 @@ func New(logger *slog.Logger, engine orchestrator.Engine, bus eventbus.Bus, plug
 			pluginsGroup.GET(":plugin/manifest", api.getPluginManifest)
 			pluginsGroup.DELETE(":plugin", api.removePlugin)
 			pluginsGroup.POST(":plugin/enabled", api.setPluginEnabled)
+			pluginsGroup.GET(":plugin/versions", api.listPluginVersions)
+			pluginsGroup.POST(":plugin/upgrade", api.upgradePlugin)
 			pluginsGroup.POST(":plugin/actions/:action", api.postPluginAction)
 
 			// Plugin artifacts API
@@ -149,10 +312,13 @@ func New(logger *slog.Logger, engine orchestrator.Engine, bus eventbus.Bus, plug
 			pluginsGroup.GET(":plugin/artifacts/:artifact", api.getPluginArtifact)
 		}
 
+		v1.GET("/audit", api.listAuditEvents)
+
 		events := v1.Group("/events")
 		{
 			events.GET("/vms", api.streamVMEvents)
 		}
+		v1.GET("/agui/stream", api.streamAGUIEvents)
 
 		vfio := v1.Group("/vfio")
 		{
@@ -162,21 +328,35 @@ func New(logger *slog.Logger, engine orchestrator.Engine, bus eventbus.Bus, plug
 			vfio.POST("/devices/bind", api.bindVFIODevices)
 			vfio.POST("/devices/unbind", api.unbindVFIODevices)
 			vfio.POST("/devices/group-paths", api.getVFIOGroupPaths)
+			vfio.GET("/assignments", api.listVFIOAssignments)
 		}
 
+		routesGroup := v1.Group("/routes")
+		{
+			routesGroup.GET("", api.listRoutes)
+			routesGroup.POST("", api.upsertRoute)
+			routesGroup.POST("/sync", api.syncRoutes)
+			routesGroup.DELETE(":protocol/:port", api.deleteRoute)
+		}
+
+		// /drift/routes predates /routes and is kept for older clients.
 		driftRoutes := v1.Group("/drift/routes")
 		{
-			driftRoutes.GET("", api.listDriftRoutes)
-			driftRoutes.POST("", api.upsertDriftRoute)
-			driftRoutes.DELETE(":protocol/:port", api.deleteDriftRoute)
+			driftRoutes.GET("", api.listRoutes)
+			driftRoutes.POST("", api.upsertRoute)
+			driftRoutes.DELETE(":protocol/:port", api.deleteRoute)
 		}
 	}
 
 	r.GET("/ws/v1/vms/:name/devtools/*path", api.vmDevToolsWebSocket)
 	r.GET("/ws/v1/vms/:name/console", api.vmConsoleWebSocket)
+	r.GET("/ws/v1/vms/:name/exec", api.vmExecWebSocket)
+	r.GET("/ws/v1/vms/:name/ssh", api.vmSSHWebSocket)
 	r.GET("/ws/v1/vms/:name/logs", api.vmLogsWebSocket)
+	r.GET("/ws/v1/vms/:name/watch", api.vmWatchWebSocket)
+	r.GET("/ws/v1/events", api.eventsWebSocket)
 
-	return r
+	return r, nil
 }
 
 func loadStoredPlugins(engine orchestrator.Engine, logger *slog.Logger, registry *plugins.Registry) error {
@@ -222,6 +402,9 @@ func requestLogger(logger *slog.Logger) gin.HandlerFunc {
 			slog.String("latency", latency.String()),
 			slog.String("client_ip", c.ClientIP()),
 		}
+		if span := tracing.SpanFromContext(c.Request.Context()); span != nil {
+			args = append(args, slog.String("trace_id", span.Context().TraceID.String()))
+		}
 		if len(c.Errors) > 0 {
 			args = append(args, slog.String("error", c.Errors.String()))
 			logger.Error("http request", args...)
@@ -231,101 +414,36 @@ func requestLogger(logger *slog.Logger) gin.HandlerFunc {
 	}
 }
 
-func ipFilterMiddleware(logger *slog.Logger, cidrs []string) gin.HandlerFunc {
-	var networks []*net.IPNet
-	for _, raw := range cidrs {
-		raw = strings.TrimSpace(raw)
-		if raw == "" {
-			continue
-		}
-		_, network, err := net.ParseCIDR(raw)
-		if err != nil {
-			logger.Warn("invalid CIDR", "cidr", raw, "error", err)
-			continue
-		}
-		networks = append(networks, network)
-	}
-	if len(networks) == 0 {
-		return func(c *gin.Context) { c.Next() }
-	}
-
-	return func(c *gin.Context) {
-		ip := net.ParseIP(c.ClientIP())
-		if ip == nil {
-			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "invalid client IP"})
-			return
-		}
-		for _, network := range networks {
-			if network.Contains(ip) {
-				c.Next()
-				return
-			}
-		}
-		logger.Warn("request blocked by CIDR filter", "ip", ip.String())
-		c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "access denied"})
-	}
-}
-
-func apiKeyMiddleware(expected string) gin.HandlerFunc {
+// tracingMiddleware records a server span per request, continuing the
+// caller's trace when it sends a traceparent header.
+func tracingMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		provided := c.GetHeader("X-Volant-API-Key")
-		if provided == "" {
-			provided = c.Query("api_key")
-		}
-		if provided != expected {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "invalid api key"})
+		route := c.FullPath()
+		name := c.Request.Method
+		if route != "" {
+			name += " " + route
+		}
+		ctx, span := tracing.StartServer(c.Request.Context(), c.Request.Header, name,
+			tracing.String("http.request.method", c.Request.Method),
+			tracing.String("http.route", route),
+			tracing.String("url.path", c.Request.URL.Path),
+		)
+		if span == nil {
+			c.Next()
 			return
 		}
+		c.Request = c.Request.WithContext(ctx)
 		c.Next()
-	}
-}
-
-// corsMiddleware enables configurable CORS for browser-based clients.
-// Allowed origins are provided via configuration; methods/headers use sane defaults.
-func corsMiddleware(logger *slog.Logger, allowed []string) gin.HandlerFunc {
-	normalized := make([]string, 0, len(allowed))
-	allowAll := false
-	for _, o := range allowed {
-		v := strings.TrimSpace(o)
-		if v == "" {
-			continue
-		}
-		if v == "*" {
-			allowAll = true
-		}
-		normalized = append(normalized, v)
-	}
-	return func(c *gin.Context) {
-		origin := c.GetHeader("Origin")
-		// Set CORS headers if origin is allowed
-		if origin != "" {
-			allowedOrigin := ""
-			if allowAll {
-				allowedOrigin = "*"
-			} else {
-				lo := strings.ToLower(origin)
-				for _, o := range normalized {
-					if strings.EqualFold(o, origin) || strings.EqualFold(o, lo) {
-						allowedOrigin = origin
-						break
-					}
-				}
-			}
-			if allowedOrigin != "" {
-				c.Header("Access-Control-Allow-Origin", allowedOrigin)
-				c.Header("Vary", "Origin")
-				c.Header("Access-Control-Allow-Credentials", "true")
-				c.Header("Access-Control-Allow-Methods", "GET, POST, PATCH, DELETE, OPTIONS")
-				c.Header("Access-Control-Allow-Headers", "Content-Type, Authorization, X-Requested-With, X-Volant-API-Key")
-				c.Header("Access-Control-Expose-Headers", "Content-Type, X-Total-Count")
+		status := c.Writer.Status()
+		span.SetAttributes(tracing.Int("http.response.status_code", status))
+		var err error
+		if status >= http.StatusInternalServerError {
+			err = fmt.Errorf("status %d", status)
+			if last := c.Errors.Last(); last != nil {
+				err = last.Err
 			}
 		}
-		if c.Request.Method == http.MethodOptions {
-			c.Status(http.StatusNoContent)
-			c.Abort()
-			return
-		}
-		c.Next()
+		span.End(err)
 	}
 }
 
@@ -336,7 +454,31 @@ type apiServer struct {
 	plugins     *plugins.Registry
 	agentPort   int
 	agentClient *http.Client
+	agentLong   *http.Client
+	breakers    *agentBreakers
+	fileLimit   int64
+	agentDir    string
 	drift       *driftclient.Client
+	scheduler   *scheduler.Scheduler
+	backups     *backup.Manager
+	webhooks    *webhooks.Manager
+	secrets     *secrets.Manager
+	mcp         *mcp.Server
+	agui        *aguiHub
+	guests      *guestMetricsCache
+	console     consoleAccess
+	events      *recentEvents
+	admission   *admission.Controller
+	// admissionErr is set when the webhook config failed to load; every
+	// admitted operation is then rejected.
+	admissionErr error
+	reloader     *config.Reloader
+	doctor       *doctor.Checker
+	// txMu serializes transactions so their rollbacks cannot interleave.
+	txMu sync.Mutex
+
+	idempotencyMu      sync.Mutex
+	idempotencySweptAt time.Time
 }
 
 type navigateActionRequest struct {
@@ -372,32 +514,69 @@ type graphqlActionRequest struct {
 type createDeploymentRequest struct {
 	Name     string          `json:"name" binding:"required"`
 	Replicas int             `json:"replicas"`
+	MaxSurge int             `json:"max_surge,omitempty"`
 	Config   vmconfig.Config `json:"config" binding:"required"`
+	// RequestID is an idempotency key for clients that cannot set the
+	// Idempotency-Key header.
+	RequestID string `json:"request_id,omitempty"`
 }
 
 type patchDeploymentRequest struct {
 	Replicas *int `json:"replicas" binding:"required"`
 }
 
+type updateDeploymentConfigRequest struct {
+	Config         vmconfig.Config `json:"config" binding:"required"`
+	MaxSurge       *int            `json:"max_surge,omitempty"`
+	MaxUnavailable *int            `json:"max_unavailable,omitempty"`
+}
+
+// deploymentResponse reports a deployment; Condition is available,
+// progressing or failed.
 type deploymentResponse struct {
-	Name            string          `json:"name"`
-	DesiredReplicas int             `json:"desired_replicas"`
-	ReadyReplicas   int             `json:"ready_replicas"`
-	Config          vmconfig.Config `json:"config"`
-	CreatedAt       time.Time       `json:"created_at"`
-	UpdatedAt       time.Time       `json:"updated_at"`
+	Name            string                  `json:"name"`
+	DesiredReplicas int                     `json:"desired_replicas"`
+	ReadyReplicas   int                     `json:"ready_replicas"`
+	MaxSurge        int                     `json:"max_surge,omitempty"`
+	Config          vmconfig.Config         `json:"config"`
+	Condition       string                  `json:"condition"`
+	Reconcile       reconcileStatusResponse `json:"reconcile"`
+	CreatedAt       time.Time               `json:"created_at"`
+	UpdatedAt       time.Time               `json:"updated_at"`
+}
+
+// reconcileStatusResponse reports the deployment reconciler's progress.
+// State is synced, pending, reconciling, retrying or failed.
+type reconcileStatusResponse struct {
+	State            string     `json:"state"`
+	LastReconciledAt *time.Time `json:"last_reconciled_at,omitempty"`
+	LastError        string     `json:"last_error,omitempty"`
+	Failures         int        `json:"failures,omitempty"`
+	NextRetryAt      *time.Time `json:"next_retry_at,omitempty"`
+	FailedAt         *time.Time `json:"failed_at,omitempty"`
 }
 
 type createVMRequest struct {
-	Name          string           `json:"name" binding:"required"`
-	Plugin        string           `json:"plugin"`
-	Runtime       string           `json:"runtime"`
-	CPUCores      int              `json:"cpu_cores"`
-	MemoryMB      int              `json:"memory_mb"`
-	KernelCmdline string           `json:"kernel_cmdline"`
-	APIHost       string           `json:"api_host"`
-	APIPort       string           `json:"api_port"`
-	Config        *vmconfig.Config `json:"config,omitempty"`
+	Name          string            `json:"name" binding:"required"`
+	Plugin        string            `json:"plugin"`
+	Runtime       string            `json:"runtime"`
+	CPUCores      int               `json:"cpu_cores"`
+	MemoryMB      int               `json:"memory_mb"`
+	KernelCmdline string            `json:"kernel_cmdline"`
+	APIHost       string            `json:"api_host"`
+	APIPort       string            `json:"api_port"`
+	Config        *vmconfig.Config  `json:"config,omitempty"`
+	Labels        map[string]string `json:"labels,omitempty"`
+	// Async returns 202 with an operation instead of waiting for the boot;
+	// equivalent to ?async=true or "Prefer: respond-async".
+	Async bool `json:"async,omitempty"`
+	// RequestID is an idempotency key for clients that cannot set the
+	// Idempotency-Key header.
+	RequestID string `json:"request_id,omitempty"`
+	// TTLSeconds and TTLAction stop or delete the VM after it has run that
+	// long; config.ttl_seconds and config.ttl_action take precedence.
+	TTLSeconds int    `json:"ttl_seconds,omitempty"`
+	TTLAction  string `json:"ttl_action,omitempty"`
 }
 
 type vfioDeviceInfoRequest struct {
@@ -458,19 +637,23 @@ type vfioGroupPathsResponse struct {
 }
 
 type vmResponse struct {
-	ID            int64      `json:"id"`
-	Name          string     `json:"name"`
-	Status        string     `json:"status"`
-	Runtime       string     `json:"runtime"`
-	PID           *int64     `json:"pid,omitempty"`
-	IPAddress     string     `json:"ip_address"`
-	MACAddress    string     `json:"mac_address"`
-	CPUCores      int        `json:"cpu_cores"`
-	MemoryMB      int        `json:"memory_mb"`
-	KernelCmdline string     `json:"kernel_cmdline"`
-	SerialSocket  string     `json:"serial_socket"`
-	CreatedAt     *time.Time `json:"created_at,omitempty"`
-	UpdatedAt     *time.Time `json:"updated_at,omitempty"`
+	ID            int64             `json:"id"`
+	Name          string            `json:"name"`
+	Status        string            `json:"status"`
+	Runtime       string            `json:"runtime"`
+	PID           *int64            `json:"pid,omitempty"`
+	IPAddress     string            `json:"ip_address"`
+	IPv6Address   string            `json:"ipv6_address,omitempty"`
+	MACAddress    string            `json:"mac_address"`
+	CPUCores      int               `json:"cpu_cores"`
+	MemoryMB      int               `json:"memory_mb"`
+	KernelCmdline string            `json:"kernel_cmdline"`
+	SerialSocket  string            `json:"serial_socket"`
+	Labels        map[string]string `json:"labels,omitempty"`
+	// ExpiresAt is when the VM's TTL runs out.
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+	CreatedAt *time.Time `json:"created_at,omitempty"`
+	UpdatedAt *time.Time `json:"updated_at,omitempty"`
 }
 
 func vmToResponse(vm *db.VM) vmResponse {
@@ -484,11 +667,14 @@ func vmToResponse(vm *db.VM) vmResponse {
 		Runtime:       vm.Runtime,
 		PID:           vm.PID,
 		IPAddress:     vm.IPAddress,
+		IPv6Address:   vm.IPv6Address,
 		MACAddress:    vm.MACAddress,
 		CPUCores:      vm.CPUCores,
 		MemoryMB:      vm.MemoryMB,
 		KernelCmdline: vm.KernelCmdline,
 		SerialSocket:  vm.SerialSocket,
+		Labels:        vm.Labels,
+		ExpiresAt:     vm.ExpiresAt,
 	}
 	if !vm.CreatedAt.IsZero() {
 		t := vm.CreatedAt
@@ -506,141 +692,58 @@ func deploymentToResponse(dep orchestrator.Deployment) deploymentResponse {
 		Name:            dep.Name,
 		DesiredReplicas: dep.DesiredReplicas,
 		ReadyReplicas:   dep.ReadyReplicas,
+		MaxSurge:        dep.MaxSurge,
 		Config:          dep.Config,
-		CreatedAt:       dep.CreatedAt,
-		UpdatedAt:       dep.UpdatedAt,
+		Condition:       dep.Condition,
+		Reconcile: reconcileStatusResponse{
+			State:            dep.Reconcile.State,
+			LastReconciledAt: dep.Reconcile.LastReconciledAt,
+			LastError:        dep.Reconcile.LastError,
+			Failures:         dep.Reconcile.Failures,
+			NextRetryAt:      dep.Reconcile.NextRetryAt,
+			FailedAt:         dep.Reconcile.FailedAt,
+		},
+		CreatedAt: dep.CreatedAt,
+		UpdatedAt: dep.UpdatedAt,
 	}
 }
 
 func (api *apiServer) listVMs(c *gin.Context) {
-	// Parse filters
-	statuses := make(map[string]bool)
-	if arr := c.QueryArray("status"); len(arr) > 0 {
-		for _, s := range arr {
-			for _, part := range strings.Split(s, ",") {
-				v := strings.TrimSpace(strings.ToLower(part))
-				if v != "" {
-					statuses[v] = true
-				}
+	opts := db.VMListOptions{
+		Runtime: strings.TrimSpace(c.Query("runtime")),
+		Group:   strings.TrimSpace(c.Query("group")),
+		Plugin:  strings.TrimSpace(c.Query("plugin")),
+		Query:   strings.TrimSpace(c.Query("q")),
+		Limit:   -1,
+	}
+	for _, s := range c.QueryArray("status") {
+		for _, part := range strings.Split(s, ",") {
+			if v := strings.TrimSpace(strings.ToLower(part)); v != "" {
+				opts.Statuses = append(opts.Statuses, db.VMStatus(v))
 			}
 		}
 	}
-	runtimeFilter := strings.TrimSpace(c.Query("runtime"))
-	pluginFilter := strings.TrimSpace(c.Query("plugin"))
-	q := strings.ToLower(strings.TrimSpace(c.Query("q")))
-	limit := -1
-	offset := 0
-	if raw := strings.TrimSpace(c.Query("limit")); raw != "" {
-		if n, err := strconv.Atoi(raw); err == nil && n >= 0 {
-			limit = n
-		} else {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid limit"})
-			return
-		}
-	}
-	if raw := strings.TrimSpace(c.Query("offset")); raw != "" {
-		if n, err := strconv.Atoi(raw); err == nil && n >= 0 {
-			offset = n
-		} else {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid offset"})
+	// limit and offset are checked against the OpenAPI spec.
+	opts.Limit = queryCount(c, "limit")
+	opts.Offset = queryCount(c, "offset")
+	if raw := strings.TrimSpace(c.Query("selector")); raw != "" {
+		selector, err := labels.Parse(raw)
+		if err != nil {
+			respondError(c, http.StatusBadRequest, CodeInvalidRequest, err.Error())
 			return
 		}
+		opts.Selector = selector
 	}
-	sortField := strings.ToLower(strings.TrimSpace(c.Query("sort")))
-	if sortField == "" {
-		sortField = "created_at"
-	}
-	order := strings.ToLower(strings.TrimSpace(c.Query("order")))
-	if order != "desc" {
-		order = "asc"
-	}
+	opts.SortBy = strings.ToLower(strings.TrimSpace(c.Query("sort")))
+	opts.Descending = strings.EqualFold(strings.TrimSpace(c.Query("order")), "desc")
 
-	// Load all VMs then apply filters in-memory
-	vms, err := api.engine.ListVMs(c.Request.Context())
+	page, total, err := api.engine.ListVMsPage(c.Request.Context(), opts)
 	if err != nil {
 		api.logger.Error("list vms", "error", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to list vms"})
+		respondError(c, http.StatusInternalServerError, CodeInternal, "failed to list vms")
 		return
 	}
 
-	// Optional plugin filter requires reading VM config
-
-	// Filter
-	filtered := make([]db.VM, 0, len(vms))
-	for i := range vms {
-		vm := vms[i]
-		if len(statuses) > 0 && !statuses[strings.ToLower(string(vm.Status))] {
-			continue
-		}
-		if runtimeFilter != "" && !strings.EqualFold(vm.Runtime, runtimeFilter) {
-			continue
-		}
-		if q != "" {
-			if !strings.Contains(strings.ToLower(vm.Name), q) && !strings.Contains(strings.ToLower(vm.IPAddress), q) && !strings.Contains(strings.ToLower(vm.Runtime), q) {
-				continue
-			}
-		}
-		// plugin filter via config lookup
-		if pluginFilter != "" {
-			versioned, cfgErr := api.engine.GetVMConfig(c.Request.Context(), vm.Name)
-			if cfgErr != nil || versioned == nil {
-				continue
-			}
-			effPlugin := strings.TrimSpace(versioned.Config.Plugin)
-			if effPlugin == "" && versioned.Config.Manifest != nil {
-				effPlugin = strings.TrimSpace(versioned.Config.Manifest.Name)
-			}
-			if !strings.EqualFold(effPlugin, pluginFilter) {
-				continue
-			}
-		}
-		filtered = append(filtered, vm)
-	}
-
-	// Sort
-	less := func(i, j int) bool { return true }
-	switch sortField {
-	case "name":
-		less = func(i, j int) bool { return strings.ToLower(filtered[i].Name) < strings.ToLower(filtered[j].Name) }
-	case "status":
-		less = func(i, j int) bool { return string(filtered[i].Status) < string(filtered[j].Status) }
-	case "runtime":
-		less = func(i, j int) bool {
-			return strings.ToLower(filtered[i].Runtime) < strings.ToLower(filtered[j].Runtime)
-		}
-	case "updated_at":
-		less = func(i, j int) bool { return filtered[i].UpdatedAt.Before(filtered[j].UpdatedAt) }
-	default: // created_at
-		less = func(i, j int) bool { return filtered[i].CreatedAt.Before(filtered[j].CreatedAt) }
-	}
-	// Simple stable sort
-	for i := 0; i < len(filtered); i++ {
-		for j := i + 1; j < len(filtered); j++ {
-			if less(j, i) {
-				filtered[i], filtered[j] = filtered[j], filtered[i]
-			}
-		}
-	}
-	if order == "desc" {
-		for i, j := 0, len(filtered)-1; i < j; i, j = i+1, j-1 {
-			filtered[i], filtered[j] = filtered[j], filtered[i]
-		}
-	}
-
-	total := len(filtered)
-	// Paginate
-	start := offset
-	if start > total {
-		start = total
-	}
-	end := total
-	if limit >= 0 {
-		if start+limit < end {
-			end = start + limit
-		}
-	}
-	page := filtered[start:end]
-
 	// Build response and include X-Total-Count
 	resp := make([]vmResponse, 0, len(page))
 	for i := range page {
@@ -659,6 +762,8 @@ type systemSummaryResponse struct {
 	TotalPlugins  int             `json:"total_plugins"`
 	EnabledPlugin int             `json:"enabled_plugins"`
 	Plugins       []pluginSummary `json:"plugins"`
+	// Scratch totals the scratch disks on the host.
+	Scratch orchestrator.ScratchUsage `json:"scratch"`
 }
 
 type pluginSummary struct {
@@ -671,7 +776,7 @@ func (api *apiServer) systemSummary(c *gin.Context) {
 	vms, err := api.engine.ListVMs(c.Request.Context())
 	if err != nil {
 		api.logger.Error("system summary list vms", "error", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to list vms"})
+		respondError(c, http.StatusInternalServerError, CodeInternal, "failed to list vms")
 		return
 	}
 	byStatus := map[string]int{}
@@ -705,6 +810,7 @@ func (api *apiServer) systemSummary(c *gin.Context) {
 		TotalPlugins:  totalPlugins,
 		EnabledPlugin: enabled,
 		Plugins:       pluginsList,
+		Scratch:       api.engine.ScratchUsage(),
 	}
 	c.JSON(http.StatusOK, resp)
 }
@@ -713,7 +819,7 @@ func (api *apiServer) listDeployments(c *gin.Context) {
 	deployments, err := api.engine.ListDeployments(c.Request.Context())
 	if err != nil {
 		api.logger.Error("list deployments", "error", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to list deployments"})
+		respondError(c, http.StatusInternalServerError, CodeInternal, "failed to list deployments")
 		return
 	}
 	resp := make([]deploymentResponse, 0, len(deployments))
@@ -728,11 +834,11 @@ func (api *apiServer) getVM(c *gin.Context) {
 	vm, err := api.engine.GetVM(c.Request.Context(), name)
 	if err != nil {
 		api.logger.Error("get vm", "vm", name, "error", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch vm"})
+		respondError(c, http.StatusInternalServerError, CodeInternal, "failed to fetch vm")
 		return
 	}
 	if vm == nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "vm not found"})
+		respondError(c, http.StatusNotFound, CodeVMNotFound, "vm not found")
 		return
 	}
 	c.JSON(http.StatusOK, vmToResponse(vm))
@@ -741,30 +847,65 @@ func (api *apiServer) getVM(c *gin.Context) {
 func (api *apiServer) createVM(c *gin.Context) {
 	var req createVMRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(c, http.StatusBadRequest, CodeInvalidRequest, err.Error())
+		return
+	}
+	if !api.admit(c, admission.OpVMCreate, req.Name, &req) {
+		return
+	}
+	createReq, err := api.prepareCreateVM(req)
+	if err != nil {
+		c.JSON(statusFromError(err), errorResponse(err))
+		return
+	}
+	if wantsAsync(c, req.Async) {
+		op, err := api.engine.CreateVMAsync(c.Request.Context(), createReq)
+		if err != nil {
+			api.logger.Error("create vm async", "vm", req.Name, "error", err)
+			c.JSON(statusFromError(err), errorResponse(err))
+			return
+		}
+		c.Header("Location", operationLocation(op.ID))
+		c.JSON(http.StatusAccepted, operationToResponse(*op))
 		return
 	}
+
+	vm, err := api.engine.CreateVM(c.Request.Context(), createReq)
+	if err != nil {
+		api.logger.Error("create vm", "vm", req.Name, "error", err)
+		c.JSON(statusFromError(err), errorResponse(err))
+		return
+	}
+	// Emit event for async notification
+	api.bus.Publish(c.Request.Context(), orchestratorevents.TopicVMEvents, orchestratorevents.VMEvent{
+		Type:      orchestratorevents.TypeVMCreated,
+		Name:      vm.Name,
+		Timestamp: time.Now().UTC(),
+		Message:   "VM created",
+	})
+	c.JSON(http.StatusCreated, vmToResponse(vm))
+}
+
+// prepareCreateVM resolves the plugin, runtime and defaults of a create
+// request into the request passed to the engine.
+func (api *apiServer) prepareCreateVM(req createVMRequest) (orchestrator.CreateVMRequest, error) {
 	pluginName := strings.TrimSpace(req.Plugin)
 	if req.Config != nil && strings.TrimSpace(req.Config.Plugin) != "" {
 		configPlugin := strings.TrimSpace(req.Config.Plugin)
 		if pluginName != "" && !strings.EqualFold(pluginName, configPlugin) {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "plugin mismatch between request and config"})
-			return
+			return orchestrator.CreateVMRequest{}, newRequestError(http.StatusBadRequest, CodeInvalidRequest, "plugin mismatch between request and config")
 		}
 		pluginName = configPlugin
 	}
 	if pluginName == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "plugin is required"})
-		return
+		return orchestrator.CreateVMRequest{}, newRequestError(http.StatusBadRequest, CodeInvalidRequest, "plugin is required")
 	}
 	manifest, ok := api.plugins.Get(pluginName)
 	if !ok {
-		c.JSON(http.StatusNotFound, gin.H{"error": fmt.Sprintf("plugin %s not found", pluginName)})
-		return
+		return orchestrator.CreateVMRequest{}, newRequestError(http.StatusNotFound, CodePluginNotFound, fmt.Sprintf("plugin %s not found", pluginName))
 	}
 	if !manifest.Enabled {
-		c.JSON(http.StatusConflict, gin.H{"error": fmt.Sprintf("plugin %s disabled", pluginName)})
-		return
+		return orchestrator.CreateVMRequest{}, newRequestError(http.StatusConflict, CodePluginDisabled, fmt.Sprintf("plugin %s disabled", pluginName))
 	}
 	labels := cloneLabelMap(manifest.Labels)
 	manifestCopy := manifest
@@ -782,8 +923,7 @@ func (api *apiServer) createVM(c *gin.Context) {
 		runtimeName = manifestCopy.Name
 	}
 	if runtimeName == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "runtime not specified and plugin manifest missing runtime"})
-		return
+		return orchestrator.CreateVMRequest{}, newRequestError(http.StatusBadRequest, CodeInvalidRequest, "runtime not specified and plugin manifest missing runtime")
 	}
 
 	cpu := req.CPUCores
@@ -817,12 +957,25 @@ func (api *apiServer) createVM(c *gin.Context) {
 		}
 	}
 
+	if req.Config == nil && (req.TTLSeconds != 0 || req.TTLAction != "") {
+		req.Config = &vmconfig.Config{}
+	}
 	var configClone *vmconfig.Config
 	if req.Config != nil {
 		clone := req.Config.Clone()
+		if clone.TTLSeconds == 0 {
+			clone.TTLSeconds = req.TTLSeconds
+		}
+		if strings.TrimSpace(clone.TTLAction) == "" {
+			clone.TTLAction = req.TTLAction
+		}
+		if err := pluginspec.ValidateTTL(clone.TTLSeconds, clone.TTLAction); err != nil {
+			return orchestrator.CreateVMRequest{}, newRequestError(http.StatusBadRequest, CodeInvalidRequest, err.Error())
+		}
 		clone.Plugin = pluginName
 		clone.Runtime = runtimeName
-		clone.Resources = vmconfig.Resources{CPUCores: cpu, MemoryMB: mem}
+		clone.Resources.CPUCores = cpu
+		clone.Resources.MemoryMB = mem
 		clone.KernelCmdline = kernelExtra
 		clone.API = vmconfig.API{Host: apiHost, Port: apiPort}
 		if clone.Manifest == nil {
@@ -836,7 +989,7 @@ func (api *apiServer) createVM(c *gin.Context) {
 		configClone = &clone
 	}
 
-	vm, err := api.engine.CreateVM(c.Request.Context(), orchestrator.CreateVMRequest{
+	return orchestrator.CreateVMRequest{
 		Name:              req.Name,
 		Plugin:            pluginName,
 		Runtime:           runtimeName,
@@ -847,36 +1000,28 @@ func (api *apiServer) createVM(c *gin.Context) {
 		KernelCmdlineHint: kernelExtra,
 		Manifest:          &manifestCopy,
 		Config:            configClone,
-	})
-	if err != nil {
-		api.logger.Error("create vm", "vm", req.Name, "error", err)
-		c.JSON(statusFromError(err), gin.H{"error": err.Error()})
-		return
-	}
-	// Emit event for async notification
-	api.bus.Publish(c.Request.Context(), orchestratorevents.TopicVMEvents, orchestratorevents.VMEvent{
-		Type:      orchestratorevents.TypeVMCreated,
-		Name:      vm.Name,
-		Timestamp: time.Now().UTC(),
-		Message:   "VM created",
-	})
-	c.JSON(http.StatusCreated, vmToResponse(vm))
+		Labels:            req.Labels,
+	}, nil
 }
 
 func (api *apiServer) createDeployment(c *gin.Context) {
 	var req createDeploymentRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(c, http.StatusBadRequest, CodeInvalidRequest, err.Error())
+		return
+	}
+	if !api.admit(c, admission.OpDeploymentCreate, req.Name, &req) {
 		return
 	}
 	deployment, err := api.engine.CreateDeployment(c.Request.Context(), orchestrator.CreateDeploymentRequest{
 		Name:     req.Name,
 		Replicas: req.Replicas,
+		MaxSurge: req.MaxSurge,
 		Config:   req.Config,
 	})
 	if err != nil {
 		api.logger.Error("create deployment", "deployment", req.Name, "error", err)
-		c.JSON(statusFromError(err), gin.H{"error": err.Error()})
+		c.JSON(statusFromError(err), errorResponse(err))
 		return
 	}
 	c.JSON(http.StatusCreated, deploymentToResponse(*deployment))
@@ -887,11 +1032,11 @@ func (api *apiServer) getVMConfig(c *gin.Context) {
 	config, err := api.engine.GetVMConfig(c.Request.Context(), name)
 	if err != nil {
 		api.logger.Error("get vm config", "vm", name, "error", err)
-		c.JSON(statusFromError(err), gin.H{"error": err.Error()})
+		c.JSON(statusFromError(err), errorResponse(err))
 		return
 	}
 	if config == nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "vm config not found"})
+		respondError(c, http.StatusNotFound, CodeNotFound, "vm config not found")
 		return
 	}
 	c.JSON(http.StatusOK, config)
@@ -901,55 +1046,141 @@ func (api *apiServer) updateVMConfig(c *gin.Context) {
 	name := c.Param("name")
 	var patch vmconfig.Patch
 	if err := c.ShouldBindJSON(&patch); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(c, http.StatusBadRequest, CodeInvalidRequest, err.Error())
+		return
+	}
+	if !api.admit(c, admission.OpVMConfigUpdate, name, &patch) {
+		return
+	}
+	opts, err := parseVMConfigUpdateOptions(c)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, CodeInvalidRequest, err.Error())
 		return
 	}
-	config, err := api.engine.UpdateVMConfig(c.Request.Context(), name, patch)
+	config, err := api.engine.ApplyVMConfig(c.Request.Context(), name, patch, opts)
 	if err != nil {
 		api.logger.Error("update vm config", "vm", name, "error", err)
-		c.JSON(statusFromError(err), gin.H{"error": err.Error()})
+		c.JSON(statusFromError(err), errorResponse(err))
+		return
+	}
+	if opts.Rollback {
+		// The restart and health check continue in the background; the
+		// outcome is reported in the config history.
+		c.JSON(http.StatusAccepted, config)
 		return
 	}
 	c.JSON(http.StatusOK, config)
 }
 
-func (api *apiServer) getVMConfigHistory(c *gin.Context) {
-	name := c.Param("name")
-	limit := 0
-	if raw := strings.TrimSpace(c.Query("limit")); raw != "" {
-		val, err := strconv.Atoi(raw)
-		if err != nil || val < 0 {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid limit"})
-			return
+func parseVMConfigUpdateOptions(c *gin.Context) (orchestrator.VMConfigUpdateOptions, error) {
+	var opts orchestrator.VMConfigUpdateOptions
+	for key, target := range map[string]*bool{"restart": &opts.Restart, "rollback": &opts.Rollback} {
+		raw := strings.TrimSpace(c.Query(key))
+		if raw == "" {
+			continue
+		}
+		val, err := strconv.ParseBool(raw)
+		if err != nil {
+			return opts, fmt.Errorf("invalid %s", key)
 		}
-		limit = val
+		*target = val
 	}
+	if opts.Rollback {
+		opts.Restart = true
+	}
+	if raw := strings.TrimSpace(c.Query("health_window")); raw != "" {
+		window, err := time.ParseDuration(raw)
+		if err != nil || window < 0 {
+			return opts, fmt.Errorf("invalid health_window")
+		}
+		opts.HealthWindow = window
+	}
+	return opts, nil
+}
+
+func (api *apiServer) getVMConfigHistory(c *gin.Context) {
+	name := c.Param("name")
+	limit := queryCount(c, "limit")
 	entries, err := api.engine.GetVMConfigHistory(c.Request.Context(), name, limit)
 	if err != nil {
 		api.logger.Error("vm config history", "vm", name, "error", err)
-		c.JSON(statusFromError(err), gin.H{"error": err.Error()})
+		c.JSON(statusFromError(err), errorResponse(err))
 		return
 	}
 	c.JSON(http.StatusOK, entries)
 }
 
+func (api *apiServer) getVMPorts(c *gin.Context) {
+	name := c.Param("name")
+	ports, err := api.engine.VMPorts(c.Request.Context(), name)
+	if err != nil {
+		api.logger.Error("vm ports", "vm", name, "error", err)
+		c.JSON(statusFromError(err), errorResponse(err))
+		return
+	}
+	c.JSON(http.StatusOK, ports)
+}
+
 func (api *apiServer) startVM(c *gin.Context) {
 	name := c.Param("name")
 	vm, err := api.engine.StartVM(c.Request.Context(), name)
 	if err != nil {
 		api.logger.Error("start vm", "vm", name, "error", err)
-		c.JSON(statusFromError(err), gin.H{"error": err.Error()})
+		c.JSON(statusFromError(err), errorResponse(err))
 		return
 	}
 	c.JSON(http.StatusOK, vmToResponse(vm))
 }
 
+type duplicateVMRequest struct {
+	Name string `json:"name" binding:"required"`
+}
+
+func (api *apiServer) duplicateVM(c *gin.Context) {
+	source := c.Param("name")
+	var req duplicateVMRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, http.StatusBadRequest, CodeInvalidRequest, err.Error())
+		return
+	}
+	vm, err := api.engine.DuplicateVM(c.Request.Context(), source, orchestrator.DuplicateVMRequest{Name: req.Name})
+	if err != nil {
+		api.logger.Error("duplicate vm", "vm", source, "duplicate", req.Name, "error", err)
+		c.JSON(statusFromError(err), errorResponse(err))
+		return
+	}
+	c.JSON(http.StatusCreated, vmToResponse(vm))
+}
+
+type cloneVMRequest struct {
+	Name string `json:"name" binding:"required"`
+	// Scratch copies the source's persistent scratch disk; otherwise the
+	// clone starts with an empty one.
+	Scratch bool `json:"scratch,omitempty"`
+}
+
+func (api *apiServer) cloneVM(c *gin.Context) {
+	source := c.Param("name")
+	var req cloneVMRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, http.StatusBadRequest, CodeInvalidRequest, err.Error())
+		return
+	}
+	vm, err := api.engine.DuplicateVM(c.Request.Context(), source, orchestrator.DuplicateVMRequest{Name: req.Name, ConfigOnly: true, Scratch: req.Scratch})
+	if err != nil {
+		api.logger.Error("clone vm", "vm", source, "clone", req.Name, "error", err)
+		c.JSON(statusFromError(err), errorResponse(err))
+		return
+	}
+	c.JSON(http.StatusCreated, vmToResponse(vm))
+}
+
 func (api *apiServer) stopVM(c *gin.Context) {
 	name := c.Param("name")
 	vm, err := api.engine.StopVM(c.Request.Context(), name)
 	if err != nil {
 		api.logger.Error("stop vm", "vm", name, "error", err)
-		c.JSON(statusFromError(err), gin.H{"error": err.Error()})
+		c.JSON(statusFromError(err), errorResponse(err))
 		return
 	}
 	c.JSON(http.StatusOK, vmToResponse(vm))
@@ -960,7 +1191,7 @@ func (api *apiServer) restartVM(c *gin.Context) {
 	vm, err := api.engine.RestartVM(c.Request.Context(), name)
 	if err != nil {
 		api.logger.Error("restart vm", "vm", name, "error", err)
-		c.JSON(statusFromError(err), gin.H{"error": err.Error()})
+		c.JSON(statusFromError(err), errorResponse(err))
 		return
 	}
 	c.JSON(http.StatusOK, vmToResponse(vm))
@@ -970,7 +1201,7 @@ func (api *apiServer) deleteVM(c *gin.Context) {
 	name := c.Param("name")
 	if err := api.engine.DestroyVM(c.Request.Context(), name); err != nil {
 		api.logger.Error("destroy vm", "vm", name, "error", err)
-		c.JSON(statusFromError(err), gin.H{"error": err.Error()})
+		c.JSON(statusFromError(err), errorResponse(err))
 		return
 	}
 	c.Status(http.StatusNoContent)
@@ -981,7 +1212,7 @@ func (api *apiServer) getDeployment(c *gin.Context) {
 	deployment, err := api.engine.GetDeployment(c.Request.Context(), name)
 	if err != nil {
 		api.logger.Error("get deployment", "deployment", name, "error", err)
-		c.JSON(statusFromError(err), gin.H{"error": err.Error()})
+		c.JSON(statusFromError(err), errorResponse(err))
 		return
 	}
 	c.JSON(http.StatusOK, deploymentToResponse(*deployment))
@@ -991,27 +1222,63 @@ func (api *apiServer) patchDeployment(c *gin.Context) {
 	name := c.Param("name")
 	var req patchDeploymentRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(c, http.StatusBadRequest, CodeInvalidRequest, err.Error())
+		return
+	}
+	if !api.admit(c, admission.OpDeploymentScale, name, &req) {
 		return
 	}
 	if req.Replicas == nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "replicas field required"})
+		respondError(c, http.StatusBadRequest, CodeInvalidRequest, "replicas field required")
 		return
 	}
 	deployment, err := api.engine.ScaleDeployment(c.Request.Context(), name, *req.Replicas)
 	if err != nil {
 		api.logger.Error("scale deployment", "deployment", name, "error", err)
-		c.JSON(statusFromError(err), gin.H{"error": err.Error()})
+		c.JSON(statusFromError(err), errorResponse(err))
 		return
 	}
 	c.JSON(http.StatusOK, deploymentToResponse(*deployment))
 }
 
+func (api *apiServer) updateDeploymentConfig(c *gin.Context) {
+	name := c.Param("name")
+	var req updateDeploymentConfigRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, http.StatusBadRequest, CodeInvalidRequest, err.Error())
+		return
+	}
+	if !api.admit(c, admission.OpDeploymentUpdate, name, &req) {
+		return
+	}
+	update := orchestrator.UpdateDeploymentConfigRequest{
+		Config:         req.Config,
+		MaxSurge:       1,
+		MaxUnavailable: 0,
+	}
+	if req.MaxSurge != nil {
+		update.MaxSurge = *req.MaxSurge
+	}
+	if req.MaxUnavailable != nil {
+		update.MaxUnavailable = *req.MaxUnavailable
+	}
+	deployment, err := api.engine.UpdateDeploymentConfig(c.Request.Context(), name, update)
+	if err != nil {
+		api.logger.Error("update deployment config", "deployment", name, "error", err)
+		c.JSON(statusFromError(err), errorResponse(err))
+		return
+	}
+	c.JSON(http.StatusAccepted, deploymentToResponse(*deployment))
+}
+
 func (api *apiServer) deleteDeployment(c *gin.Context) {
 	name := c.Param("name")
+	if !api.admit(c, admission.OpDeploymentDelete, name, nil) {
+		return
+	}
 	if err := api.engine.DeleteDeployment(c.Request.Context(), name); err != nil {
 		api.logger.Error("delete deployment", "deployment", name, "error", err)
-		c.JSON(statusFromError(err), gin.H{"error": err.Error()})
+		c.JSON(statusFromError(err), errorResponse(err))
 		return
 	}
 	c.Status(http.StatusNoContent)
@@ -1019,13 +1286,13 @@ func (api *apiServer) deleteDeployment(c *gin.Context) {
 
 func (api *apiServer) streamVMEvents(c *gin.Context) {
 	if api.bus == nil {
-		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "event streaming not available"})
+		respondError(c, http.StatusServiceUnavailable, CodeUnavailable, "event streaming not available")
 		return
 	}
 
 	flusher, ok := c.Writer.(http.Flusher)
 	if !ok {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "streaming unsupported"})
+		respondError(c, http.StatusInternalServerError, CodeInternal, "streaming unsupported")
 		return
 	}
 
@@ -1033,7 +1300,7 @@ func (api *apiServer) streamVMEvents(c *gin.Context) {
 	eventsCh := make(chan any, 16)
 	unsubscribe, err := api.bus.Subscribe(orchestratorevents.TopicVMEvents, eventsCh)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to subscribe"})
+		respondError(c, http.StatusInternalServerError, CodeInternal, "failed to subscribe")
 		return
 	}
 	defer unsubscribe()
@@ -1041,6 +1308,11 @@ func (api *apiServer) streamVMEvents(c *gin.Context) {
 	c.Writer.Header().Set("Content-Type", "text/event-stream")
 	c.Writer.Header().Set("Cache-Control", "no-cache")
 	c.Writer.Header().Set("Connection", "keep-alive")
+	// Send the headers now, so clients see the stream open before the
+	// first event.
+	clearDeadlines(c)
+	c.Writer.WriteHeader(http.StatusOK)
+	flusher.Flush()
 
 	for {
 		select {
@@ -1078,10 +1350,12 @@ func (api *apiServer) systemInfo(c *gin.Context) {
 	listenAddr := ""
 	advertiseAddr := ""
 	hostIP := ""
+	var hostFeatures *hostfeatures.Report
 	if api.engine != nil {
 		listenAddr = api.engine.ControlPlaneListenAddr()
 		advertiseAddr = api.engine.ControlPlaneAdvertiseAddr()
 		hostIP = api.engine.HostIP().String()
+		hostFeatures = api.engine.HostFeatures()
 	}
 
 	c.JSON(http.StatusOK, gin.H{
@@ -1089,130 +1363,40 @@ func (api *apiServer) systemInfo(c *gin.Context) {
 		"api_listen_addr":    listenAddr,
 		"api_advertise_addr": advertiseAddr,
 		"host_ip":            hostIP,
+		"host_features":      hostFeatures,
 	})
 }
 
-type SystemStatusResponse struct {
-	VMCount int     `json:"vm_count"`
-	CPU     float64 `json:"cpu_percent"`
-	MEM     float64 `json:"mem_percent"`
+// GET /api/v1/system/capacity
+func (api *apiServer) systemCapacity(c *gin.Context) {
+	report, err := api.engine.Capacity(c.Request.Context())
+	if err != nil {
+		api.logger.Error("system capacity", "error", err)
+		respondError(c, http.StatusInternalServerError, CodeInternal, "failed to compute capacity")
+		return
+	}
+	c.JSON(http.StatusOK, report)
 }
 
-type MCPRequest struct {
-	Command string                 `json:"command"`
-	Params  map[string]interface{} `json:"params"`
+// GET /api/v1/system/doctor
+func (api *apiServer) systemDoctor(c *gin.Context) {
+	if api.doctor == nil {
+		respondError(c, http.StatusNotImplemented, CodeNotImplemented, "host checks are not available")
+		return
+	}
+	c.JSON(http.StatusOK, api.doctor.Run(c.Request.Context()))
 }
 
-type MCPResponse struct {
-	Result interface{} `json:"result"`
-	Error  string      `json:"error,omitempty"`
+type SystemStatusResponse struct {
+	VMCount int     `json:"vm_count"`
+	CPU     float64 `json:"cpu_percent"`
+	MEM     float64 `json:"mem_percent"`
 }
 
+// handleMCP serves the Model Context Protocol over the Streamable HTTP
+// transport; tool calls come back through the router as API requests.
 func (api *apiServer) handleMCP(c *gin.Context) {
-	var req MCPRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, MCPResponse{Error: err.Error()})
-		return
-	}
-
-	ctx := c.Request.Context()
-	var result interface{}
-	var err error
-
-	switch req.Command {
-	case "volant.vms.list":
-		vms, e := api.engine.ListVMs(ctx)
-		if e != nil {
-			err = e
-		} else {
-			vmList := make([]map[string]interface{}, len(vms))
-			for i, vm := range vms {
-				vmList[i] = map[string]interface{}{
-					"id":         vm.ID,
-					"name":       vm.Name,
-					"status":     vm.Status,
-					"ip_address": vm.IPAddress,
-					"cpu_cores":  vm.CPUCores,
-					"memory_mb":  vm.MemoryMB,
-				}
-			}
-			result = vmList
-		}
-	case "volant.vms.create":
-		name, ok := req.Params["name"].(string)
-		if !ok {
-			err = fmt.Errorf("name param required")
-		} else {
-			runtime := "browser"
-			if raw, exists := req.Params["runtime"].(string); exists {
-				runtime = strings.TrimSpace(raw)
-			}
-			manifest, ok := api.plugins.Get(runtime)
-			if !ok || !manifest.Enabled {
-				err = fmt.Errorf("runtime %s unavailable", runtime)
-				break
-			}
-			manifestCopy := manifest
-			hostIP := api.engine.HostIP().String()
-			_, portStr, _ := net.SplitHostPort(api.engine.ControlPlaneAdvertiseAddr())
-			vm, e := api.engine.CreateVM(ctx, orchestrator.CreateVMRequest{
-				Name:     name,
-				Plugin:   runtime,
-				Runtime:  runtime,
-				CPUCores: manifest.Resources.CPUCores,
-				MemoryMB: manifest.Resources.MemoryMB,
-				Manifest: &manifestCopy,
-				APIHost:  hostIP,
-				APIPort:  portStr,
-			})
-			if e != nil {
-				err = e
-			} else {
-				result = map[string]interface{}{
-					"id":         vm.ID,
-					"name":       vm.Name,
-					"status":     vm.Status,
-					"ip_address": vm.IPAddress,
-					"cpu_cores":  vm.CPUCores,
-					"memory_mb":  vm.MemoryMB,
-				}
-				// Emit event for async notification
-				api.bus.Publish(ctx, orchestratorevents.TopicVMEvents, orchestratorevents.VMEvent{
-					Type:      orchestratorevents.TypeVMCreated,
-					Name:      vm.Name,
-					Timestamp: time.Now().UTC(),
-					Message:   "VM created via MCP",
-				})
-			}
-		}
-	case "volant.system.get_capabilities":
-		result = map[string]interface{}{
-			"capabilities": []map[string]interface{}{
-				{
-					"name":        "volant.vms.create",
-					"description": "Create a new microVM",
-					"params": map[string]interface{}{
-						"name":      "string (required)",
-						"cpu_cores": "int (default 2)",
-						"memory_mb": "int (default 2048)",
-					},
-				},
-				{
-					"name":        "volant.vms.list",
-					"description": "List all microVMs",
-					"params":      map[string]interface{}{},
-				},
-			},
-		}
-	default:
-		err = fmt.Errorf("unknown command: %s", req.Command)
-	}
-
-	resp := MCPResponse{Result: result}
-	if err != nil {
-		resp.Error = err.Error()
-	}
-	c.JSON(http.StatusOK, resp)
+	api.mcp.ServeHTTP(c.Writer, c.Request)
 }
 
 // getVMOpenAPI serves the VM plugin's OpenAPI document.
@@ -1220,7 +1404,7 @@ func (api *apiServer) handleMCP(c *gin.Context) {
 func (api *apiServer) getVMOpenAPI(c *gin.Context) {
 	name := c.Param("name")
 	if strings.TrimSpace(name) == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "vm name required"})
+		respondError(c, http.StatusBadRequest, CodeInvalidRequest, "vm name required")
 		return
 	}
 
@@ -1228,7 +1412,7 @@ func (api *apiServer) getVMOpenAPI(c *gin.Context) {
 	vm, err := api.engine.GetVM(c.Request.Context(), name)
 	if err != nil {
 		api.logger.Error("get vm openapi", "vm", name, "error", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to resolve vm"})
+		respondError(c, http.StatusInternalServerError, CodeInternal, "failed to resolve vm")
 		return
 	}
 
@@ -1253,7 +1437,7 @@ func (api *apiServer) getVMOpenAPI(c *gin.Context) {
 		_, _ = c.Writer.Write(data)
 	}
 
-	if vm != nil && vm.Status == db.VMStatusRunning && strings.TrimSpace(vm.IPAddress) != "" {
+	if vm != nil && vm.Status == db.VMStatusRunning && agentReachable(vm) {
 		req, err := http.NewRequestWithContext(c.Request.Context(), http.MethodGet, api.agentURL(vm, "/v1/openapi"), nil)
 		if err == nil {
 			resp, err := api.agentClient.Do(req)
@@ -1272,16 +1456,16 @@ func (api *apiServer) getVMOpenAPI(c *gin.Context) {
 	versioned, err := api.engine.GetVMConfig(c.Request.Context(), name)
 	if err != nil {
 		api.logger.Error("get vm config for openapi", "vm", name, "error", err)
-		c.JSON(statusFromError(err), gin.H{"error": err.Error()})
+		c.JSON(statusFromError(err), errorResponse(err))
 		return
 	}
 	if versioned == nil || versioned.Config.Manifest == nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "openapi spec unavailable"})
+		respondError(c, http.StatusNotFound, CodeNotFound, "openapi spec unavailable")
 		return
 	}
 	ref := strings.TrimSpace(versioned.Config.Manifest.OpenAPI)
 	if ref == "" {
-		c.JSON(http.StatusNotFound, gin.H{"error": "openapi spec unavailable"})
+		respondError(c, http.StatusNotFound, CodeNotFound, "openapi spec unavailable")
 		return
 	}
 
@@ -1289,22 +1473,22 @@ func (api *apiServer) getVMOpenAPI(c *gin.Context) {
 	if strings.HasPrefix(ref, "http://") || strings.HasPrefix(ref, "https://") {
 		req, err := http.NewRequestWithContext(c.Request.Context(), http.MethodGet, ref, nil)
 		if err != nil {
-			c.JSON(http.StatusBadGateway, gin.H{"error": "failed to fetch manifest openapi"})
+			respondError(c, http.StatusBadGateway, CodeUpstreamError, "failed to fetch manifest openapi")
 			return
 		}
 		resp, err := api.agentClient.Do(req)
 		if err != nil {
-			c.JSON(http.StatusBadGateway, gin.H{"error": "failed to fetch manifest openapi"})
+			respondError(c, http.StatusBadGateway, CodeUpstreamError, "failed to fetch manifest openapi")
 			return
 		}
 		defer resp.Body.Close()
 		if resp.StatusCode != http.StatusOK {
-			c.JSON(http.StatusBadGateway, gin.H{"error": fmt.Sprintf("manifest openapi returned %d", resp.StatusCode)})
+			respondError(c, http.StatusBadGateway, CodeUpstreamError, fmt.Sprintf("manifest openapi returned %d", resp.StatusCode))
 			return
 		}
 		data, err := io.ReadAll(resp.Body)
 		if err != nil {
-			c.JSON(http.StatusBadGateway, gin.H{"error": "failed to read manifest openapi"})
+			respondError(c, http.StatusBadGateway, CodeUpstreamError, "failed to read manifest openapi")
 			return
 		}
 		ct := resp.Header.Get("Content-Type")
@@ -1330,12 +1514,12 @@ func (api *apiServer) getVMOpenAPI(c *gin.Context) {
 	}
 	if !strings.HasPrefix(path, "/") {
 		// not a supported scheme/path
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid openapi reference in manifest"})
+		respondError(c, http.StatusBadRequest, CodeInvalidRequest, "invalid openapi reference in manifest")
 		return
 	}
 	data, err := os.ReadFile(path)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "openapi file not found"})
+		respondError(c, http.StatusNotFound, CodeNotFound, "openapi file not found")
 		return
 	}
 	ct := "application/json"
@@ -1368,9 +1552,23 @@ type vmLogPayload struct {
 	Timestamp time.Time `json:"timestamp"`
 }
 
+// getVMSysInfo relays the agent's view of the booted guest: kernel, mounts,
+// interfaces, loaded modules and agent build.
+func (api *apiServer) getVMSysInfo(c *gin.Context) {
+	vm, ok := api.resolveVM(c)
+	if !ok {
+		return
+	}
+	var info json.RawMessage
+	if err := api.agentAction(c, vm, http.MethodGet, "/v1/sysinfo", nil, &info, agentCallPolicy{}); err != nil {
+		return
+	}
+	c.JSON(http.StatusOK, info)
+}
+
 func (api *apiServer) proxyAgent(c *gin.Context) {
 	if api.agentClient == nil {
-		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "agent proxy unavailable"})
+		respondError(c, http.StatusServiceUnavailable, CodeAgentUnavailable, "agent proxy unavailable")
 		return
 	}
 
@@ -1378,23 +1576,23 @@ func (api *apiServer) proxyAgent(c *gin.Context) {
 	vm, err := api.engine.GetVM(c.Request.Context(), name)
 	if err != nil {
 		api.logger.Error("proxy agent get vm", "vm", name, "error", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to resolve vm"})
+		respondError(c, http.StatusInternalServerError, CodeInternal, "failed to resolve vm")
 		return
 	}
 	if vm == nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "vm not found"})
+		respondError(c, http.StatusNotFound, CodeVMNotFound, "vm not found")
 		return
 	}
 	if vm.Status != db.VMStatusRunning {
-		c.JSON(http.StatusConflict, gin.H{"error": "vm not running"})
+		respondError(c, http.StatusConflict, CodeVMNotRunning, "vm not running")
 		return
 	}
-	if vm.IPAddress == "" {
-		c.JSON(http.StatusBadGateway, gin.H{"error": "vm ip address unavailable"})
+	if !agentReachable(vm) {
+		respondError(c, http.StatusBadGateway, CodeAgentUnavailable, "vm agent address unavailable")
 		return
 	}
 	if strings.EqualFold(c.GetHeader("Upgrade"), "websocket") {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "websocket upgrade not supported"})
+		respondError(c, http.StatusBadRequest, CodeInvalidRequest, "websocket upgrade not supported")
 		return
 	}
 
@@ -1407,36 +1605,47 @@ func (api *apiServer) proxyAgent(c *gin.Context) {
 		target = target + "?" + raw
 	}
 
-	var bodyReader io.Reader = http.NoBody
+	var bodyBytes []byte
 	if c.Request.Body != nil {
-		bodyBytes, err := io.ReadAll(c.Request.Body)
+		var err error
+		bodyBytes, err = io.ReadAll(c.Request.Body)
 		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+			respondError(c, http.StatusBadRequest, CodeInvalidRequest, "invalid request body")
 			return
 		}
 		if err := c.Request.Body.Close(); err != nil {
 			api.logger.Debug("proxy agent body close", "vm", vm.Name, "error", err)
 		}
+	}
+
+	// Requests for a plugin action follow its manifest's timeout and retries.
+	var policy agentCallPolicy
+	if api.plugins != nil {
+		if action, ok := api.plugins.MatchAction(vm.Runtime, c.Request.Method, proxyPath); ok {
+			policy = actionCallPolicy(action)
+		}
+	}
+	if policy.timeout > 0 {
+		clearDeadlines(c)
+	}
+	resp, err := api.doAgent(c.Request.Context(), policy, false, func(ctx context.Context) (*http.Request, error) {
+		var bodyReader io.Reader = http.NoBody
 		if len(bodyBytes) > 0 {
 			bodyReader = bytes.NewReader(bodyBytes)
 		}
-	}
-
-	req, err := http.NewRequestWithContext(c.Request.Context(), c.Request.Method, target, bodyReader)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to create proxy request"})
-		return
-	}
-
-	req.Header = make(http.Header)
-	copyHeaders(req.Header, c.Request.Header)
-	req.Header.Del("Accept-Encoding")
-	req.Host = fmt.Sprintf("%s:%d", vm.IPAddress, api.agentPort)
-
-	resp, err := api.agentClient.Do(req)
+		req, err := http.NewRequestWithContext(ctx, c.Request.Method, target, bodyReader)
+		if err != nil {
+			return nil, err
+		}
+		req.Header = make(http.Header)
+		copyHeaders(req.Header, c.Request.Header)
+		req.Header.Del("Accept-Encoding")
+		req.Host = agentHost(vm, api.agentPort)
+		return req, nil
+	})
 	if err != nil {
 		api.logger.Error("proxy agent request", "vm", vm.Name, "error", err)
-		c.JSON(http.StatusBadGateway, gin.H{"error": err.Error()})
+		respondAgentCallError(c, err)
 		return
 	}
 	defer resp.Body.Close()
@@ -1495,7 +1704,7 @@ func (api *apiServer) fetchDevToolsInfo(ctx context.Context, vm *db.VM) (*devToo
 
 func (api *apiServer) vmDevToolsWebSocket(c *gin.Context) {
 	if api.agentClient == nil {
-		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "agent proxy unavailable"})
+		respondError(c, http.StatusServiceUnavailable, CodeAgentUnavailable, "agent proxy unavailable")
 		return
 	}
 
@@ -1505,22 +1714,22 @@ func (api *apiServer) vmDevToolsWebSocket(c *gin.Context) {
 	vm, err := api.engine.GetVM(ctx, name)
 	if err != nil {
 		api.logger.Error("devtools ws get vm", "vm", name, "error", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to resolve vm"})
+		respondError(c, http.StatusInternalServerError, CodeInternal, "failed to resolve vm")
 		return
 	}
 	if vm == nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "vm not found"})
+		respondError(c, http.StatusNotFound, CodeVMNotFound, "vm not found")
 		return
 	}
-	if vm.Status != db.VMStatusRunning || vm.IPAddress == "" {
-		c.JSON(http.StatusConflict, gin.H{"error": "vm not ready"})
+	if vm.Status != db.VMStatusRunning || !agentReachable(vm) {
+		respondError(c, http.StatusConflict, CodeVMNotReady, "vm not ready")
 		return
 	}
 
 	info, err := api.fetchDevToolsInfo(ctx, vm)
 	if err != nil {
 		api.logger.Error("devtools info", "vm", vm.Name, "error", err)
-		c.JSON(http.StatusBadGateway, gin.H{"error": "devtools metadata unavailable"})
+		respondError(c, http.StatusBadGateway, CodeUpstreamError, "devtools metadata unavailable")
 		return
 	}
 
@@ -1539,7 +1748,7 @@ func (api *apiServer) vmDevToolsWebSocket(c *gin.Context) {
 	if err != nil || targetURL.Host == "" {
 		targetURL = &url.URL{
 			Scheme: "ws",
-			Host:   net.JoinHostPort(vm.IPAddress, strconv.Itoa(info.Port)),
+			Host:   agentHost(vm, info.Port),
 		}
 	}
 	if targetURL.Scheme == "" {
@@ -1556,7 +1765,8 @@ func (api *apiServer) vmDevToolsWebSocket(c *gin.Context) {
 	targetURL.RawQuery = c.Request.URL.RawQuery
 
 	dialer := websocket.Dialer{
-		Proxy:            http.ProxyFromEnvironment,
+		Proxy:            agentProxy,
+		NetDialContext:   dialAgent,
 		HandshakeTimeout: 30 * time.Second,
 	}
 	agentConn, resp, err := dialer.DialContext(ctx, targetURL.String(), nil)
@@ -1565,7 +1775,9 @@ func (api *apiServer) vmDevToolsWebSocket(c *gin.Context) {
 	}
 	if err != nil {
 		api.logger.Error("devtools ws dial", "vm", vm.Name, "target", targetURL.String(), "error", err)
-		c.JSON(http.StatusBadGateway, gin.H{"error": "failed to connect devtools", "target": targetURL.String()})
+		body := newAPIError(CodeUpstreamError, "failed to connect devtools")
+		body.Details = map[string]any{"target": targetURL.String()}
+		c.JSON(http.StatusBadGateway, body)
 		return
 	}
 	defer agentConn.Close()
@@ -1636,7 +1848,7 @@ func proxyWebSocket(src, dst *websocket.Conn) error {
 
 func (api *apiServer) vmLogsWebSocket(c *gin.Context) {
 	if api.agentClient == nil {
-		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "agent proxy unavailable"})
+		respondError(c, http.StatusServiceUnavailable, CodeAgentUnavailable, "agent proxy unavailable")
 		return
 	}
 
@@ -1660,29 +1872,66 @@ func (api *apiServer) vmLogsWebSocket(c *gin.Context) {
 		writeWebSocketClose(conn, websocket.CloseNormalClosure, "vm not found")
 		return
 	}
-	if vm.Status != db.VMStatusRunning || vm.IPAddress == "" {
+	if vm.Status != db.VMStatusRunning || !agentReachable(vm) {
 		writeWebSocketClose(conn, websocket.CloseTryAgainLater, "vm not ready")
 		return
 	}
 
+	err = api.streamAgentLogs(ctx, vm, func(event vmLogPayload) bool {
+		if err := conn.WriteJSON(event); err != nil {
+			return false
+		}
+		if api.bus != nil {
+			e := orchestratorevents.VMEvent{
+				Type:      orchestratorevents.TypeVMLog,
+				Name:      vm.Name,
+				Status:    orchestratorevents.VMStatusRunning,
+				IPAddress: vm.IPAddress,
+				Timestamp: event.Timestamp,
+				Message:   event.Line,
+				Stream:    event.Stream,
+				Line:      event.Line,
+			}
+			if err := api.bus.Publish(ctx, orchestratorevents.TopicVMEvents, e); err != nil {
+				api.logger.Debug("publish vm log", "vm", vm.Name, "error", err)
+			}
+		}
+		return true
+	})
+	switch {
+	case errors.Is(err, errLogConsumerGone):
+		writeWebSocketClose(conn, websocket.CloseAbnormalClosure, "client closed")
+	case ctx.Err() != nil:
+		writeWebSocketClose(conn, websocket.CloseNormalClosure, ctx.Err().Error())
+	case err != nil:
+		writeWebSocketClose(conn, websocket.CloseTryAgainLater, err.Error())
+	default:
+		writeWebSocketClose(conn, websocket.CloseNormalClosure, "stream closed")
+	}
+}
+
+// errLogConsumerGone stops streamAgentLogs when emit reports the consumer
+// can no longer take lines.
+var errLogConsumerGone = errors.New("log consumer gone")
+
+// streamAgentLogs follows the agent's log SSE stream and hands each line to
+// emit until the stream ends, ctx is done or emit returns false.
+func (api *apiServer) streamAgentLogs(ctx context.Context, vm *db.VM, emit func(vmLogPayload) bool) error {
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, api.agentURL(vm, "/v1/logs/stream"), nil)
 	if err != nil {
-		writeWebSocketClose(conn, websocket.CloseInternalServerErr, "stream request failed")
-		return
+		return errors.New("stream request failed")
 	}
 	req.Header.Set("Accept", "text/event-stream")
 
 	resp, err := api.agentClient.Do(req)
 	if err != nil {
 		api.logger.Error("vm logs stream", "vm", vm.Name, "error", err)
-		writeWebSocketClose(conn, websocket.CloseTryAgainLater, "agent unreachable")
-		return
+		return errors.New("agent unreachable")
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		writeWebSocketClose(conn, websocket.CloseTryAgainLater, fmt.Sprintf("agent returned %d", resp.StatusCode))
-		return
+		return fmt.Errorf("agent returned %d", resp.StatusCode)
 	}
 
 	scanner := bufio.NewScanner(resp.Body)
@@ -1701,55 +1950,33 @@ func (api *apiServer) vmLogsWebSocket(c *gin.Context) {
 			api.logger.Debug("agent log decode", "vm", vm.Name, "error", err)
 			return true
 		}
-
-		event := vmLogPayload{
+		return emit(vmLogPayload{
 			Name:      vm.Name,
 			Stream:    raw.Stream,
 			Line:      raw.Line,
 			Timestamp: raw.Timestamp,
-		}
-		if err := conn.WriteJSON(event); err != nil {
-			return false
-		}
-
-		if api.bus != nil {
-			e := orchestratorevents.VMEvent{
-				Type:      orchestratorevents.TypeVMLog,
-				Name:      vm.Name,
-				Status:    orchestratorevents.VMStatusRunning,
-				IPAddress: vm.IPAddress,
-				Timestamp: raw.Timestamp,
-				Message:   raw.Line,
-				Stream:    raw.Stream,
-				Line:      raw.Line,
-			}
-			if err := api.bus.Publish(ctx, orchestratorevents.TopicVMEvents, e); err != nil {
-				api.logger.Debug("publish vm log", "vm", vm.Name, "error", err)
-			}
-		}
-		return true
+		})
 	}
 
 	for {
 		if err := ctx.Err(); err != nil {
-			writeWebSocketClose(conn, websocket.CloseNormalClosure, err.Error())
-			return
+			return err
 		}
 
 		if !scanner.Scan() {
-			_ = flush()
+			if !flush() {
+				return errLogConsumerGone
+			}
 			if err := scanner.Err(); err != nil && ctx.Err() == nil {
 				api.logger.Debug("vm log stream ended", "vm", vm.Name, "error", err)
 			}
-			writeWebSocketClose(conn, websocket.CloseNormalClosure, "stream closed")
-			return
+			return nil
 		}
 
 		line := scanner.Text()
 		if line == "" {
 			if !flush() {
-				writeWebSocketClose(conn, websocket.CloseAbnormalClosure, "client closed")
-				return
+				return errLogConsumerGone
 			}
 			continue
 		}
@@ -1771,7 +1998,7 @@ func (api *apiServer) agentURL(vm *db.VM, path string) string {
 	if !strings.HasPrefix(path, "/") {
 		path = "/" + path
 	}
-	return fmt.Sprintf("http://%s:%d%s", vm.IPAddress, api.agentPort, path)
+	return "http://" + agentHost(vm, api.agentPort) + path
 }
 
 func copyHeaders(dst, src http.Header) {
@@ -1795,7 +2022,7 @@ func writeWebSocketClose(conn *websocket.Conn, code int, message string) {
 	_ = conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(code, message), time.Now().Add(time.Second))
 }
 
-func (api *apiServer) agentAction(c *gin.Context, vm *db.VM, method, path string, body any, out any) error {
+func (api *apiServer) agentAction(c *gin.Context, vm *db.VM, method, path string, body any, out any, policy agentCallPolicy) error {
 	if method == "" {
 		method = http.MethodPost
 	}
@@ -1803,90 +2030,116 @@ func (api *apiServer) agentAction(c *gin.Context, vm *db.VM, method, path string
 	var buf bytes.Buffer
 	if body != nil {
 		if err := json.NewEncoder(&buf).Encode(body); err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to encode request"})
+			respondError(c, http.StatusInternalServerError, CodeInternal, "failed to encode request")
 			return err
 		}
 	}
 
-	req, err := http.NewRequestWithContext(c.Request.Context(), method, api.agentURL(vm, path), bytes.NewReader(buf.Bytes()))
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to create agent request"})
-		return err
-	}
-	if body != nil {
-		req.Header.Set("Content-Type", "application/json")
+	target := api.agentURL(vm, path)
+	if policy.timeout > 0 {
+		clearDeadlines(c)
 	}
-
-	resp, err := api.agentClient.Do(req)
+	resp, err := api.doAgent(c.Request.Context(), policy, false, func(ctx context.Context) (*http.Request, error) {
+		req, err := http.NewRequestWithContext(ctx, method, target, bytes.NewReader(buf.Bytes()))
+		if err != nil {
+			return nil, err
+		}
+		if body != nil {
+			req.Header.Set("Content-Type", "application/json")
+		}
+		return req, nil
+	})
 	if err != nil {
 		api.logger.Error("agent action", "vm", vm.Name, "path", path, "error", err)
-		c.JSON(http.StatusBadGateway, gin.H{"error": err.Error()})
+		respondAgentCallError(c, err)
 		return err
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode >= 300 {
-		var payload map[string]any
-		if err := json.NewDecoder(resp.Body).Decode(&payload); err != nil {
-			c.JSON(resp.StatusCode, gin.H{"error": http.StatusText(resp.StatusCode)})
-			return fmt.Errorf("agent returned %d", resp.StatusCode)
-		}
-		message, _ := payload["error"].(string)
-		if message == "" {
-			message = http.StatusText(resp.StatusCode)
-		}
-		c.JSON(resp.StatusCode, gin.H{"error": message})
-		return fmt.Errorf("agent returned %d", resp.StatusCode)
+		return respondAgentError(c, resp)
 	}
 
 	if out == nil {
 		return nil
 	}
 	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to decode agent response"})
+		respondError(c, http.StatusInternalServerError, CodeInternal, "failed to decode agent response")
 		return err
 	}
 	return nil
 }
 
+// respondAgentError relays an agent error response, keeping its code when it
+// is one of ours.
+func respondAgentError(c *gin.Context, resp *http.Response) error {
+	var payload map[string]any
+	if err := json.NewDecoder(resp.Body).Decode(&payload); err != nil {
+		respondError(c, resp.StatusCode, codeForStatus(resp.StatusCode), http.StatusText(resp.StatusCode))
+		return fmt.Errorf("agent returned %d", resp.StatusCode)
+	}
+	message, _ := payload["error"].(string)
+	if message == "" {
+		message = http.StatusText(resp.StatusCode)
+	}
+	code, _ := payload["code"].(string)
+	if !isErrorCode(code) {
+		code = codeForStatus(resp.StatusCode)
+	}
+	respondError(c, resp.StatusCode, code, message)
+	return fmt.Errorf("agent returned %d", resp.StatusCode)
+}
+
 func (api *apiServer) resolveVM(c *gin.Context) (*db.VM, bool) {
 	name := c.Param("name")
 	if name == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "vm name required"})
+		respondError(c, http.StatusBadRequest, CodeInvalidRequest, "vm name required")
 		return nil, false
 	}
 
 	vm, err := api.engine.GetVM(c.Request.Context(), name)
 	if err != nil {
 		api.logger.Error("resolve vm", "vm", name, "error", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to resolve vm"})
+		respondError(c, http.StatusInternalServerError, CodeInternal, "failed to resolve vm")
 		return nil, false
 	}
 	if vm == nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "vm not found"})
+		respondError(c, http.StatusNotFound, CodeVMNotFound, "vm not found")
 		return nil, false
 	}
-	if vm.Status != db.VMStatusRunning || vm.IPAddress == "" {
-		c.JSON(http.StatusConflict, gin.H{"error": "vm not ready"})
+	if vm.Status != db.VMStatusRunning || !agentReachable(vm) {
+		respondError(c, http.StatusConflict, CodeVMNotReady, "vm not ready")
 		return nil, false
 	}
 
 	return vm, true
 }
 
-func statusFromError(err error) int {
-	switch {
-	case errors.Is(err, orchestrator.ErrVMNotFound):
-		return http.StatusNotFound
-	case errors.Is(err, orchestrator.ErrVMExists):
-		return http.StatusConflict
-	case errors.Is(err, orchestrator.ErrDeploymentNotFound):
-		return http.StatusNotFound
-	case errors.Is(err, orchestrator.ErrDeploymentExists):
-		return http.StatusConflict
-	default:
-		return http.StatusInternalServerError
+// errorResponse renders err for API clients, adding the failure code and
+// remediation hint for classified launch failures.
+// admit runs the admission webhooks for op, writing the error response and
+// returning false when the request is rejected. Mutating webhooks may rewrite
+// obj in place.
+func (api *apiServer) admit(c *gin.Context, op admission.Operation, name string, obj any) bool {
+	if err := api.checkAdmission(c.Request.Context(), op, name, obj); err != nil {
+		c.JSON(statusFromError(err), errorResponse(err))
+		return false
+	}
+	return true
+}
+
+// checkAdmission runs the admission webhooks for op, possibly rewriting obj.
+func (api *apiServer) checkAdmission(ctx context.Context, op admission.Operation, name string, obj any) error {
+	err := api.admissionErr
+	if err != nil {
+		err = fmt.Errorf("%w: config unavailable: %v", admission.ErrWebhookFailed, err)
+	} else {
+		err = api.admission.Admit(ctx, op, name, obj)
 	}
+	if err != nil {
+		api.logger.Warn("admission rejected", "operation", op, "name", name, "error", err)
+	}
+	return err
 }
 
 func (api *apiServer) postVMPluginAction(c *gin.Context) {
@@ -1896,7 +2149,7 @@ func (api *apiServer) postVMPluginAction(c *gin.Context) {
 
 func (api *apiServer) dispatchPluginAction(c *gin.Context, vmName string) {
 	if api.plugins == nil {
-		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "plugin registry unavailable"})
+		respondError(c, http.StatusServiceUnavailable, CodeUnavailable, "plugin registry unavailable")
 		return
 	}
 
@@ -1905,13 +2158,13 @@ func (api *apiServer) dispatchPluginAction(c *gin.Context, vmName string) {
 	manifest, action, err := api.plugins.ResolveAction(pluginName, actionName)
 	if err != nil {
 		api.logger.Error("resolve plugin action", "plugin", pluginName, "action", actionName, "error", err)
-		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		c.JSON(statusFromError(err), errorResponse(err))
 		return
 	}
 
 	var payload map[string]any
 	if err := c.ShouldBindJSON(&payload); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(c, http.StatusBadRequest, CodeInvalidRequest, err.Error())
 		return
 	}
 
@@ -1923,7 +2176,7 @@ func (api *apiServer) dispatchPluginAction(c *gin.Context, vmName string) {
 			return
 		}
 		if manifest.Runtime != vm.Runtime {
-			c.JSON(http.StatusConflict, gin.H{"error": "vm runtime does not match plugin"})
+			respondError(c, http.StatusConflict, CodeConflict, "vm runtime does not match plugin")
 			return
 		}
 	}
@@ -1938,16 +2191,25 @@ func (api *apiServer) dispatchPluginAction(c *gin.Context, vmName string) {
 		method = http.MethodPost
 	}
 
+	if action.Stream {
+		if vm == nil {
+			respondError(c, http.StatusBadRequest, CodeInvalidRequest, "streaming actions run in a vm; use /api/v1/vms/{name}/actions")
+			return
+		}
+		api.streamAgentAction(c, vm, method, targetPath, payload, actionCallPolicy(action))
+		return
+	}
+
 	var respBody map[string]any
 	if vm != nil {
-		if err := api.agentAction(c, vm, method, targetPath, payload, &respBody); err != nil {
+		if err := api.agentAction(c, vm, method, targetPath, payload, &respBody, actionCallPolicy(action)); err != nil {
 			return
 		}
 	} else {
 		resp, err := api.forwardPluginAction(c.Request.Context(), manifest, method, targetPath, payload)
 		if err != nil {
 			api.logger.Error("plugin action forward", "plugin", pluginName, "action", actionName, "error", err)
-			c.JSON(http.StatusBadGateway, gin.H{"error": err.Error()})
+			respondError(c, http.StatusBadGateway, CodeUpstreamError, err.Error())
 			return
 		}
 		respBody = resp
@@ -1964,15 +2226,15 @@ func (api *apiServer) resolveVMByName(c *gin.Context, name string) (*db.VM, bool
 	vm, err := api.engine.GetVM(c.Request.Context(), name)
 	if err != nil {
 		api.logger.Error("resolve vm", "vm", name, "error", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to resolve vm"})
+		respondError(c, http.StatusInternalServerError, CodeInternal, "failed to resolve vm")
 		return nil, false
 	}
 	if vm == nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "vm not found"})
+		respondError(c, http.StatusNotFound, CodeVMNotFound, "vm not found")
 		return nil, false
 	}
-	if vm.Status != db.VMStatusRunning || vm.IPAddress == "" {
-		c.JSON(http.StatusConflict, gin.H{"error": "vm not ready"})
+	if vm.Status != db.VMStatusRunning || !agentReachable(vm) {
+		respondError(c, http.StatusConflict, CodeVMNotReady, "vm not ready")
 		return nil, false
 	}
 	return vm, true
@@ -1985,7 +2247,7 @@ func (api *apiServer) forwardPluginAction(ctx context.Context, manifest pluginsp
 
 func (api *apiServer) listPlugins(c *gin.Context) {
 	if api.plugins == nil {
-		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "plugin registry unavailable"})
+		respondError(c, http.StatusServiceUnavailable, CodeUnavailable, "plugin registry unavailable")
 		return
 	}
 
@@ -1995,14 +2257,14 @@ func (api *apiServer) listPlugins(c *gin.Context) {
 
 func (api *apiServer) describePlugin(c *gin.Context) {
 	if api.plugins == nil {
-		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "plugin registry unavailable"})
+		respondError(c, http.StatusServiceUnavailable, CodeUnavailable, "plugin registry unavailable")
 		return
 	}
 
 	pluginName := c.Param("plugin")
 	manifest, ok := api.plugins.Get(pluginName)
 	if !ok {
-		c.JSON(http.StatusNotFound, gin.H{"error": "plugin not found"})
+		respondError(c, http.StatusNotFound, CodePluginNotFound, "plugin not found")
 		return
 	}
 	c.JSON(http.StatusOK, manifest)
@@ -2014,51 +2276,83 @@ func (api *apiServer) postPluginAction(c *gin.Context) {
 
 func (api *apiServer) installPlugin(c *gin.Context) {
 	if api.plugins == nil {
-		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "plugin registry unavailable"})
+		respondError(c, http.StatusServiceUnavailable, CodeUnavailable, "plugin registry unavailable")
 		return
 	}
 
 	var manifest pluginspec.Manifest
 	if err := c.ShouldBindJSON(&manifest); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(c, http.StatusBadRequest, CodeInvalidRequest, err.Error())
+		return
+	}
+	if !api.admit(c, admission.OpPluginInstall, manifest.Name, &manifest) {
 		return
 	}
 
 	manifest.Normalize()
 	if err := manifest.Validate(); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(c, http.StatusBadRequest, CodeInvalidRequest, err.Error())
+		return
+	}
+	if api.engine != nil {
+		if err := api.engine.CheckHostFeatures(&manifest); err != nil {
+			c.JSON(statusFromError(err), errorResponse(err))
+			return
+		}
+	}
+
+	// activate=false stores another version of an installed plugin without
+	// switching to it; POST :plugin/upgrade activates it later.
+	activate := true
+	if raw := strings.TrimSpace(c.Query("activate")); raw != "" {
+		val, err := strconv.ParseBool(raw)
+		if err != nil {
+			respondError(c, http.StatusBadRequest, CodeInvalidRequest, "invalid activate")
+			return
+		}
+		activate = val
+	}
+	if active, ok := api.plugins.Get(manifest.Name); !activate && ok && active.Version != manifest.Version {
+		if err := api.stagePluginVersion(c.Request.Context(), manifest); err != nil {
+			api.logger.Error("stage plugin version", "plugin", manifest.Name, "version", manifest.Version, "error", err)
+			respondError(c, http.StatusInternalServerError, CodeInternal, err.Error())
+			return
+		}
+		c.Status(http.StatusCreated)
 		return
 	}
 
 	if err := api.persistPluginManifest(c.Request.Context(), manifest, true); err != nil {
 		api.logger.Error("install plugin", "plugin", manifest.Name, "error", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(c, http.StatusInternalServerError, CodeInternal, err.Error())
 		return
 	}
 
 	api.plugins.Register(manifest)
+	api.publishPluginEvent(c.Request.Context(), orchestratorevents.TypePluginInstalled, manifest.Name, manifest.Version, true)
 	c.Status(http.StatusCreated)
 }
 
 func (api *apiServer) removePlugin(c *gin.Context) {
 	name := c.Param("plugin")
 	if strings.TrimSpace(name) == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "plugin name required"})
+		respondError(c, http.StatusBadRequest, CodeInvalidRequest, "plugin name required")
 		return
 	}
 
 	if err := api.deletePluginManifest(c.Request.Context(), name); err != nil {
 		api.logger.Error("remove plugin", "plugin", name, "error", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(c, http.StatusInternalServerError, CodeInternal, err.Error())
 		return
 	}
 
+	api.publishPluginEvent(c.Request.Context(), orchestratorevents.TypePluginRemoved, name, "", false)
 	c.Status(http.StatusNoContent)
 }
 
 func (api *apiServer) setPluginEnabled(c *gin.Context) {
 	if api.plugins == nil {
-		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "plugin registry unavailable"})
+		respondError(c, http.StatusServiceUnavailable, CodeUnavailable, "plugin registry unavailable")
 		return
 	}
 
@@ -2067,19 +2361,44 @@ func (api *apiServer) setPluginEnabled(c *gin.Context) {
 		Enabled bool `json:"enabled"`
 	}
 	if err := c.ShouldBindJSON(&payload); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(c, http.StatusBadRequest, CodeInvalidRequest, err.Error())
 		return
 	}
 
 	if err := api.togglePlugin(c.Request.Context(), name, payload.Enabled); err != nil {
 		api.logger.Error("toggle plugin", "plugin", name, "error", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(c, http.StatusInternalServerError, CodeInternal, err.Error())
 		return
 	}
 
+	eventType := orchestratorevents.TypePluginDisabled
+	if payload.Enabled {
+		eventType = orchestratorevents.TypePluginEnabled
+	}
+	var version string
+	if manifest, ok := api.plugins.Get(name); ok {
+		version = manifest.Version
+	}
+	api.publishPluginEvent(c.Request.Context(), eventType, name, version, payload.Enabled)
 	c.Status(http.StatusOK)
 }
 
+func (api *apiServer) publishPluginEvent(ctx context.Context, typ, name, version string, enabled bool) {
+	if api.bus == nil {
+		return
+	}
+	event := orchestratorevents.PluginEvent{
+		Type:      typ,
+		Name:      name,
+		Version:   version,
+		Enabled:   enabled,
+		Timestamp: time.Now().UTC(),
+	}
+	if err := api.bus.Publish(ctx, orchestratorevents.TopicPluginEvents, event); err != nil {
+		api.logger.Error("publish plugin event", "type", typ, "plugin", name, "error", err)
+	}
+}
+
 func (api *apiServer) persistPluginManifest(ctx context.Context, manifest pluginspec.Manifest, enabled bool) error {
 	store := api.engine.Store()
 	if store == nil {
@@ -2091,6 +2410,13 @@ func (api *apiServer) persistPluginManifest(ctx context.Context, manifest plugin
 		if err != nil {
 			return err
 		}
+		if err := q.PluginVersions().Upsert(ctx, db.PluginVersion{
+			PluginName: manifest.Name,
+			Version:    manifest.Version,
+			Metadata:   data,
+		}); err != nil {
+			return err
+		}
 		return q.Plugins().Upsert(ctx, db.Plugin{
 			Name:     manifest.Name,
 			Version:  manifest.Version,
@@ -2100,6 +2426,25 @@ func (api *apiServer) persistPluginManifest(ctx context.Context, manifest plugin
 	})
 }
 
+// stagePluginVersion records manifest as an installed version of its plugin
+// without making it the active one.
+func (api *apiServer) stagePluginVersion(ctx context.Context, manifest pluginspec.Manifest) error {
+	store := api.engine.Store()
+	if store == nil {
+		return fmt.Errorf("store not configured")
+	}
+
+	data, err := json.Marshal(manifest)
+	if err != nil {
+		return err
+	}
+	return store.Queries().PluginVersions().Upsert(ctx, db.PluginVersion{
+		PluginName: manifest.Name,
+		Version:    manifest.Version,
+		Metadata:   data,
+	})
+}
+
 func (api *apiServer) deletePluginManifest(ctx context.Context, name string) error {
 	store := api.engine.Store()
 	if store == nil {
@@ -2107,6 +2452,9 @@ func (api *apiServer) deletePluginManifest(ctx context.Context, name string) err
 	}
 
 	return store.WithTx(ctx, func(q db.Queries) error {
+		if err := q.PluginVersions().DeleteByPlugin(ctx, name); err != nil {
+			return err
+		}
 		return q.Plugins().Delete(ctx, name)
 	})
 }
@@ -2161,14 +2509,15 @@ func (api *apiServer) mountManifestRoutes(router *gin.RouterGroup, vm *db.VM, ma
 		}
 		actionPath := path
 		actionMethod := method
+		policy := actionCallPolicy(action)
 
 		router.Handle(actionMethod, actionPath, func(c *gin.Context) {
 			var payload map[string]any
 			if err := c.ShouldBindJSON(&payload); err != nil && !errors.Is(err, io.EOF) {
-				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+				respondError(c, http.StatusBadRequest, CodeInvalidRequest, err.Error())
 				return
 			}
-			if err := api.agentAction(c, vm, actionMethod, actionPath, payload, nil); err != nil {
+			if err := api.agentAction(c, vm, actionMethod, actionPath, payload, nil, policy); err != nil {
 				return
 			}
 			c.Status(http.StatusAccepted)
@@ -2184,18 +2533,18 @@ func (api *apiServer) handleManifestAction(ctx context.Context, w http.ResponseW
 
 func (api *apiServer) getPluginManifest(c *gin.Context) {
 	if api.plugins == nil {
-		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "plugin registry unavailable"})
+		respondError(c, http.StatusServiceUnavailable, CodeUnavailable, "plugin registry unavailable")
 		return
 	}
 
 	pluginName := c.Param("plugin")
 	manifest, ok := api.plugins.Get(pluginName)
 	if !ok {
-		c.JSON(http.StatusNotFound, gin.H{"error": "plugin not found"})
+		respondError(c, http.StatusNotFound, CodePluginNotFound, "plugin not found")
 		return
 	}
 	if !manifest.Enabled {
-		c.JSON(http.StatusConflict, gin.H{"error": "plugin disabled"})
+		respondError(c, http.StatusConflict, CodePluginDisabled, "plugin disabled")
 		return
 	}
 	c.JSON(http.StatusOK, manifest)
@@ -2206,12 +2555,12 @@ func (api *apiServer) getPluginManifest(c *gin.Context) {
 func (api *apiServer) listPluginArtifacts(c *gin.Context) {
 	plugin := strings.TrimSpace(c.Param("plugin"))
 	if plugin == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "plugin name required"})
+		respondError(c, http.StatusBadRequest, CodeInvalidRequest, "plugin name required")
 		return
 	}
 	store := api.engine.Store()
 	if store == nil {
-		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "store not configured"})
+		respondError(c, http.StatusServiceUnavailable, CodeUnavailable, "store not configured")
 		return
 	}
 
@@ -2227,7 +2576,7 @@ func (api *apiServer) listPluginArtifacts(c *gin.Context) {
 	}
 	if err != nil {
 		api.logger.Error("list plugin artifacts", "plugin", plugin, "version", version, "error", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to list artifacts"})
+		respondError(c, http.StatusInternalServerError, CodeInternal, "failed to list artifacts")
 		return
 	}
 	c.JSON(http.StatusOK, result)
@@ -2239,26 +2588,26 @@ func (api *apiServer) getPluginArtifact(c *gin.Context) {
 	artifact := strings.TrimSpace(c.Param("artifact"))
 	version := strings.TrimSpace(c.Query("version"))
 	if plugin == "" || artifact == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "plugin and artifact required"})
+		respondError(c, http.StatusBadRequest, CodeInvalidRequest, "plugin and artifact required")
 		return
 	}
 	if version == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "version query required"})
+		respondError(c, http.StatusBadRequest, CodeInvalidRequest, "version query required")
 		return
 	}
 	store := api.engine.Store()
 	if store == nil {
-		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "store not configured"})
+		respondError(c, http.StatusServiceUnavailable, CodeUnavailable, "store not configured")
 		return
 	}
 	rec, err := store.Queries().PluginArtifacts().Get(c.Request.Context(), plugin, version, artifact)
 	if err != nil {
 		api.logger.Error("get plugin artifact", "plugin", plugin, "artifact", artifact, "version", version, "error", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to get artifact"})
+		respondError(c, http.StatusInternalServerError, CodeInternal, "failed to get artifact")
 		return
 	}
 	if rec == nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "artifact not found"})
+		respondError(c, http.StatusNotFound, CodeNotFound, "artifact not found")
 		return
 	}
 	c.JSON(http.StatusOK, rec)
@@ -2279,12 +2628,12 @@ type upsertArtifactRequest struct {
 func (api *apiServer) upsertPluginArtifact(c *gin.Context) {
 	plugin := strings.TrimSpace(c.Param("plugin"))
 	if plugin == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "plugin name required"})
+		respondError(c, http.StatusBadRequest, CodeInvalidRequest, "plugin name required")
 		return
 	}
 	var req upsertArtifactRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(c, http.StatusBadRequest, CodeInvalidRequest, err.Error())
 		return
 	}
 	art := db.PluginArtifact{
@@ -2299,19 +2648,19 @@ func (api *apiServer) upsertPluginArtifact(c *gin.Context) {
 		SizeBytes:    req.SizeBytes,
 	}
 	if art.Version == "" || art.ArtifactName == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "version and artifact_name required"})
+		respondError(c, http.StatusBadRequest, CodeInvalidRequest, "version and artifact_name required")
 		return
 	}
 	store := api.engine.Store()
 	if store == nil {
-		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "store not configured"})
+		respondError(c, http.StatusServiceUnavailable, CodeUnavailable, "store not configured")
 		return
 	}
 	if err := store.WithTx(c.Request.Context(), func(q db.Queries) error {
 		return q.PluginArtifacts().Upsert(c.Request.Context(), art)
 	}); err != nil {
 		api.logger.Error("upsert plugin artifact", "plugin", plugin, "artifact", art.ArtifactName, "version", art.Version, "error", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to upsert artifact"})
+		respondError(c, http.StatusInternalServerError, CodeInternal, "failed to upsert artifact")
 		return
 	}
 	c.Status(http.StatusCreated)
@@ -2321,13 +2670,13 @@ func (api *apiServer) upsertPluginArtifact(c *gin.Context) {
 func (api *apiServer) deletePluginArtifacts(c *gin.Context) {
 	plugin := strings.TrimSpace(c.Param("plugin"))
 	if plugin == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "plugin name required"})
+		respondError(c, http.StatusBadRequest, CodeInvalidRequest, "plugin name required")
 		return
 	}
 	version := strings.TrimSpace(c.Query("version"))
 	store := api.engine.Store()
 	if store == nil {
-		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "store not configured"})
+		respondError(c, http.StatusServiceUnavailable, CodeUnavailable, "store not configured")
 		return
 	}
 	var err error
@@ -2342,32 +2691,42 @@ func (api *apiServer) deletePluginArtifacts(c *gin.Context) {
 	}
 	if err != nil {
 		api.logger.Error("delete plugin artifacts", "plugin", plugin, "version", version, "error", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to delete artifacts"})
+		respondError(c, http.StatusInternalServerError, CodeInternal, "failed to delete artifacts")
 		return
 	}
 	c.Status(http.StatusNoContent)
 }
 
-// /ws/v1/vms/:name/console -> bridge to VM serial socket
+// defaultConsoleReplay is the number of recent output lines sent to a console
+// WebSocket when it attaches.
+const defaultConsoleReplay = 100
+
+// defaultConsoleTail is the number of lines console/log returns without tail.
+const defaultConsoleTail = 1000
+
+// /ws/v1/vms/:name/console -> bridge to the VM serial console. Recent output
+// is replayed first; ?replay=N sets how many lines (0 disables).
 func (api *apiServer) vmConsoleWebSocket(c *gin.Context) {
 	vm, ok := api.resolveVM(c)
 	if !ok {
 		return
 	}
-	serial := strings.TrimSpace(vm.SerialSocket)
-	if serial == "" {
-		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "serial socket unavailable"})
+	user, ok := api.authorizeConsole(c, vm)
+	if !ok {
+		return
+	}
+	replay, ok := queryLines(c, "replay", defaultConsoleReplay)
+	if !ok {
 		return
 	}
 
-	// Establish Unix domain socket connection to serial
-	unixConn, err := net.Dial("unix", serial)
+	session, err := api.engine.AttachConsole(vm.Name, replay)
 	if err != nil {
-		api.logger.Error("console dial unix", "vm", vm.Name, "socket", serial, "error", err)
-		c.JSON(http.StatusBadGateway, gin.H{"error": "serial socket unavailable"})
+		api.logger.Error("console attach", "vm", vm.Name, "error", err)
+		respondError(c, http.StatusServiceUnavailable, CodeUnavailable, "serial console unavailable")
 		return
 	}
-	defer unixConn.Close()
+	defer session.Close()
 
 	upgrader := websocket.Upgrader{CheckOrigin: func(r *http.Request) bool { return true }}
 	wsConn, err := upgrader.Upgrade(c.Writer, c.Request, nil)
@@ -2377,33 +2736,38 @@ func (api *apiServer) vmConsoleWebSocket(c *gin.Context) {
 	}
 	defer wsConn.Close()
 
+	audit := db.AuditEvent{Target: vm.Name, Actor: user, ClientIP: c.ClientIP()}
+	opened := time.Now()
+	audit.Action = auditConsoleOpen
+	api.recordAudit(c.Request.Context(), audit)
+	defer func() {
+		// The request context is already cancelled when the client hangs up.
+		audit.Action = auditConsoleClose
+		audit.Duration = time.Since(opened)
+		api.recordAudit(context.Background(), audit)
+	}()
+
 	ctx := c.Request.Context()
 	errCh := make(chan error, 2)
 	var wg sync.WaitGroup
 	wg.Add(2)
 
-	// Pipe: VM serial -> WS (binary frames)
+	// Pipe: replayed history, then VM serial -> WS (binary frames)
 	go func() {
 		defer wg.Done()
-		buf := make([]byte, 4096)
-		for {
-			n, readErr := unixConn.Read(buf)
-			if n > 0 {
-				if writeErr := wsConn.WriteMessage(websocket.BinaryMessage, buf[:n]); writeErr != nil {
-					errCh <- writeErr
-					return
-				}
-			}
-			if readErr != nil {
-				errCh <- readErr
+		if len(session.History) > 0 {
+			if writeErr := wsConn.WriteMessage(websocket.BinaryMessage, session.History); writeErr != nil {
+				errCh <- writeErr
 				return
 			}
-			select {
-			case <-ctx.Done():
+		}
+		for chunk := range session.Output() {
+			if writeErr := wsConn.WriteMessage(websocket.BinaryMessage, chunk); writeErr != nil {
+				errCh <- writeErr
 				return
-			default:
 			}
 		}
+		errCh <- io.EOF
 	}()
 
 	// Pipe: WS -> VM serial
@@ -2417,7 +2781,7 @@ func (api *apiServer) vmConsoleWebSocket(c *gin.Context) {
 			}
 			// Accept both text and binary frames
 			_ = msgType
-			if _, writeErr := unixConn.Write(payload); writeErr != nil {
+			if _, writeErr := session.Write(payload); writeErr != nil {
 				errCh <- writeErr
 				return
 			}
@@ -2437,6 +2801,8 @@ func (api *apiServer) vmConsoleWebSocket(c *gin.Context) {
 	}
 
 	_ = wsConn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""), time.Now().Add(time.Second))
+	session.Close()
+	_ = wsConn.Close()
 	wg.Wait()
 
 	if bridgeErr != nil && !errors.Is(bridgeErr, net.ErrClosed) && !errors.Is(bridgeErr, io.EOF) && !websocket.IsCloseError(bridgeErr, websocket.CloseNormalClosure, websocket.CloseGoingAway) {
@@ -2444,12 +2810,167 @@ func (api *apiServer) vmConsoleWebSocket(c *gin.Context) {
 	}
 }
 
+// GET /api/v1/vms/:name/console/log?tail=N returns recent serial output as
+// text; tail=0 returns everything kept.
+func (api *apiServer) vmConsoleLog(c *gin.Context) {
+	name := c.Param("name")
+	vm, err := api.engine.GetVM(c.Request.Context(), name)
+	if err != nil {
+		api.logger.Error("console log vm", "vm", name, "error", err)
+		respondError(c, http.StatusInternalServerError, CodeInternal, "failed to resolve vm")
+		return
+	}
+	if vm == nil {
+		respondError(c, http.StatusNotFound, CodeVMNotFound, "vm not found")
+		return
+	}
+	user, ok := api.authorizeConsole(c, vm)
+	if !ok {
+		return
+	}
+	tail, ok := queryLines(c, "tail", defaultConsoleTail)
+	if !ok {
+		return
+	}
+	output, err := api.engine.ConsoleLog(c.Request.Context(), vm.Name, tail)
+	if err != nil {
+		api.logger.Error("console log", "vm", vm.Name, "error", err)
+		c.JSON(statusFromError(err), errorResponse(err))
+		return
+	}
+	api.recordAudit(c.Request.Context(), db.AuditEvent{Action: auditConsoleLog, Target: vm.Name, Actor: user, ClientIP: c.ClientIP()})
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", vm.Name+"-console.log"))
+	c.Data(http.StatusOK, "text/plain; charset=utf-8", output)
+}
+
+type vmLogsResponse struct {
+	Name    string         `json:"name"`
+	Entries []vmlogs.Entry `json:"entries"`
+}
+
+// GET /api/v1/vms/:name/logs?since=...&stream=...&source=...&limit=N returns
+// persisted hypervisor and agent output. since is an RFC 3339 time or a
+// duration back from now.
+func (api *apiServer) getVMLogs(c *gin.Context) {
+	query := vmlogs.Query{
+		Stream: strings.TrimSpace(c.Query("stream")),
+		Source: strings.TrimSpace(c.Query("source")),
+	}
+	switch query.Stream {
+	case "", vmlogs.StreamStdout, vmlogs.StreamStderr:
+	default:
+		respondError(c, http.StatusBadRequest, CodeInvalidRequest, fmt.Sprintf("invalid stream %q: must be stdout or stderr", query.Stream))
+		return
+	}
+	switch query.Source {
+	case "", vmlogs.SourceHypervisor, vmlogs.SourceAgent:
+	default:
+		respondError(c, http.StatusBadRequest, CodeInvalidRequest, fmt.Sprintf("invalid source %q: must be hypervisor or agent", query.Source))
+		return
+	}
+	since, ok := querySince(c)
+	if !ok {
+		return
+	}
+	query.Since = since
+	limit, ok := queryLines(c, "limit", 0)
+	if !ok {
+		return
+	}
+	query.Limit = limit
+
+	name := c.Param("name")
+	entries, err := api.engine.VMLogs(c.Request.Context(), name, query)
+	if err != nil {
+		api.logger.Error("vm logs", "vm", name, "error", err)
+		c.JSON(statusFromError(err), errorResponse(err))
+		return
+	}
+	c.JSON(http.StatusOK, vmLogsResponse{Name: name, Entries: entries})
+}
+
+type vmEventResponse struct {
+	ID        int64     `json:"id"`
+	Type      string    `json:"type"`
+	Status    string    `json:"status,omitempty"`
+	Message   string    `json:"message,omitempty"`
+	Code      string    `json:"code,omitempty"`
+	Hint      string    `json:"hint,omitempty"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// GET /api/v1/vms/:name/events?since=...&type=...&limit=N returns the
+// recorded lifecycle events of a VM, oldest first. Events are kept for seven
+// days, also after the VM is deleted.
+func (api *apiServer) getVMEvents(c *gin.Context) {
+	since, ok := querySince(c)
+	if !ok {
+		return
+	}
+	query := db.VMEventQuery{Since: since, Limit: queryCount(c, "limit")}
+	for eventType := range queryValueSet(c, "type", true) {
+		query.Types = append(query.Types, eventType)
+	}
+
+	name := c.Param("name")
+	events, err := api.engine.VMEvents(c.Request.Context(), name, query)
+	if err != nil {
+		api.logger.Error("vm events", "vm", name, "error", err)
+		c.JSON(statusFromError(err), errorResponse(err))
+		return
+	}
+	resp := make([]vmEventResponse, 0, len(events))
+	for _, event := range events {
+		resp = append(resp, vmEventResponse{
+			ID:        event.ID,
+			Type:      event.Type,
+			Status:    event.Status,
+			Message:   event.Message,
+			Code:      event.Code,
+			Hint:      event.Hint,
+			Timestamp: event.CreatedAt,
+		})
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// querySince parses the since query parameter, an RFC 3339 time or a
+// duration back from now.
+func querySince(c *gin.Context) (time.Time, bool) {
+	raw := strings.TrimSpace(c.Query("since"))
+	if raw == "" {
+		return time.Time{}, true
+	}
+	if since, err := time.Parse(time.RFC3339, raw); err == nil {
+		return since, true
+	}
+	if ago, err := time.ParseDuration(raw); err == nil && ago >= 0 {
+		return time.Now().Add(-ago), true
+	}
+	respondError(c, http.StatusBadRequest, CodeInvalidRequest, fmt.Sprintf("invalid since %q: must be an RFC 3339 time or a duration", raw))
+	return time.Time{}, false
+}
+
+// queryLines parses a non-negative line count query parameter.
+func queryLines(c *gin.Context, key string, fallback int) (int, bool) {
+	raw := strings.TrimSpace(c.Query(key))
+	if raw == "" {
+		return fallback, true
+	}
+	lines, err := strconv.Atoi(raw)
+	if err != nil || lines < 0 {
+		respondError(c, http.StatusBadRequest, CodeInvalidRequest, fmt.Sprintf("invalid %s %q: must be a line count >= 0", key, raw))
+		return 0, false
+	}
+	return lines, true
+}
+
 // VFIO Device Management Handlers
 
 func (api *apiServer) getVFIODeviceInfo(c *gin.Context) {
 	var req vfioDeviceInfoRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(c, http.StatusBadRequest, CodeInvalidRequest, err.Error())
 		return
 	}
 
@@ -2459,7 +2980,7 @@ func (api *apiServer) getVFIODeviceInfo(c *gin.Context) {
 	deviceInfo, err := vfioMgr.GetDeviceInfo(req.PCIAddress)
 	if err != nil {
 		api.logger.Error("failed to get device info", "pci_address", req.PCIAddress, "error", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(c, http.StatusInternalServerError, CodeInternal, err.Error())
 		return
 	}
 
@@ -2478,7 +2999,7 @@ func (api *apiServer) getVFIODeviceInfo(c *gin.Context) {
 func (api *apiServer) validateVFIODevices(c *gin.Context) {
 	var req vfioValidateRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(c, http.StatusBadRequest, CodeInvalidRequest, err.Error())
 		return
 	}
 
@@ -2504,7 +3025,7 @@ func (api *apiServer) validateVFIODevices(c *gin.Context) {
 func (api *apiServer) checkVFIOIOMMUGroups(c *gin.Context) {
 	var req vfioValidateRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(c, http.StatusBadRequest, CodeInvalidRequest, err.Error())
 		return
 	}
 
@@ -2513,7 +3034,7 @@ func (api *apiServer) checkVFIOIOMMUGroups(c *gin.Context) {
 	groups, err := vfioMgr.CheckIOMMUGroups(req.PCIAddresses)
 	if err != nil {
 		api.logger.Error("failed to check IOMMU groups", "devices", req.PCIAddresses, "error", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(c, http.StatusInternalServerError, CodeInternal, err.Error())
 		return
 	}
 
@@ -2531,7 +3052,7 @@ func (api *apiServer) checkVFIOIOMMUGroups(c *gin.Context) {
 func (api *apiServer) bindVFIODevices(c *gin.Context) {
 	var req vfioBindRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(c, http.StatusBadRequest, CodeInvalidRequest, err.Error())
 		return
 	}
 
@@ -2558,13 +3079,23 @@ func (api *apiServer) bindVFIODevices(c *gin.Context) {
 func (api *apiServer) unbindVFIODevices(c *gin.Context) {
 	var req vfioUnbindRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(c, http.StatusBadRequest, CodeInvalidRequest, err.Error())
+		return
+	}
+
+	held, err := api.assignedVFIODevice(c, req.PCIAddresses)
+	if err != nil {
+		c.JSON(statusFromError(err), errorResponse(err))
+		return
+	}
+	if held != nil {
+		respondError(c, http.StatusConflict, CodeDeviceClaimed, fmt.Sprintf("device %s is assigned to vm %s", held.PCIAddress, held.VMName))
 		return
 	}
 
 	vfioMgr := devicemanager.NewVFIOManager(api.logger)
 
-	err := vfioMgr.UnbindDevices(req.PCIAddresses)
+	err = vfioMgr.UnbindDevices(req.PCIAddresses)
 	if err != nil {
 		api.logger.Error("failed to unbind devices", "devices", req.PCIAddresses, "error", err)
 		c.JSON(http.StatusInternalServerError, vfioUnbindResponse{
@@ -2585,7 +3116,7 @@ func (api *apiServer) unbindVFIODevices(c *gin.Context) {
 func (api *apiServer) getVFIOGroupPaths(c *gin.Context) {
 	var req vfioGroupPathsRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(c, http.StatusBadRequest, CodeInvalidRequest, err.Error())
 		return
 	}
 
@@ -2594,7 +3125,7 @@ func (api *apiServer) getVFIOGroupPaths(c *gin.Context) {
 	groupPaths, err := vfioMgr.GetVFIOGroupPaths(req.PCIAddresses)
 	if err != nil {
 		api.logger.Error("failed to get VFIO group paths", "devices", req.PCIAddresses, "error", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(c, http.StatusInternalServerError, CodeInternal, err.Error())
 		return
 	}
 
@@ -2603,56 +3134,9 @@ func (api *apiServer) getVFIOGroupPaths(c *gin.Context) {
 	})
 }
 
-func (api *apiServer) listDriftRoutes(c *gin.Context) {
-	if !api.ensureDriftAvailable(c) {
-		return
-	}
-	items, err := api.drift.ListRoutes(c.Request.Context())
-	if err != nil {
-		api.respondDriftError(c, err)
-		return
-	}
-	c.JSON(http.StatusOK, items)
-}
-
-func (api *apiServer) upsertDriftRoute(c *gin.Context) {
-	if !api.ensureDriftAvailable(c) {
-		return
-	}
-	var route routes.Route
-	if err := c.ShouldBindJSON(&route); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
-		return
-	}
-	updated, err := api.drift.UpsertRoute(c.Request.Context(), route)
-	if err != nil {
-		api.respondDriftError(c, err)
-		return
-	}
-	c.JSON(http.StatusCreated, updated)
-}
-
-func (api *apiServer) deleteDriftRoute(c *gin.Context) {
-	if !api.ensureDriftAvailable(c) {
-		return
-	}
-	protocol := c.Param("protocol")
-	portStr := c.Param("port")
-	port, err := strconv.ParseUint(portStr, 10, 16)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid port"})
-		return
-	}
-	if err := api.drift.DeleteRoute(c.Request.Context(), protocol, uint16(port)); err != nil {
-		api.respondDriftError(c, err)
-		return
-	}
-	c.Status(http.StatusNoContent)
-}
-
 func (api *apiServer) ensureDriftAvailable(c *gin.Context) bool {
 	if api.drift == nil || !api.drift.Enabled() {
-		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "drift not configured"})
+		respondError(c, http.StatusServiceUnavailable, CodeUnavailable, "drift not configured")
 		return false
 	}
 	return true
@@ -2660,13 +3144,13 @@ func (api *apiServer) ensureDriftAvailable(c *gin.Context) bool {
 
 func (api *apiServer) respondDriftError(c *gin.Context, err error) {
 	if errors.Is(err, driftclient.ErrDisabled) {
-		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "drift not configured"})
+		respondError(c, http.StatusServiceUnavailable, CodeUnavailable, "drift not configured")
 		return
 	}
 	var apiErr *driftclient.APIError
 	if errors.As(err, &apiErr) {
-		c.JSON(apiErr.Status, gin.H{"error": apiErr.Error()})
+		respondError(c, apiErr.Status, codeForStatus(apiErr.Status), apiErr.Error())
 		return
 	}
-	c.JSON(http.StatusBadGateway, gin.H{"error": err.Error()})
+	respondError(c, http.StatusBadGateway, CodeUpstreamError, err.Error())
 }
@@ -0,0 +1,172 @@
+// Copyright (c) 2025 HYPR. PTE. LTD.
+//
+// Business Source License 1.1
+// See LICENSE file in the project root for details.
+
+package httpapi
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/volantvm/volant/internal/server/db"
+)
+
+const (
+	// idempotencyKeyHeader carries the client request token; a request_id
+	// field in the JSON body is accepted when the header is absent.
+	idempotencyKeyHeader = "Idempotency-Key"
+	// idempotencyReplayedHeader marks responses served from the cache.
+	idempotencyReplayedHeader = "Idempotent-Replayed"
+	// idempotencyKeyTTL is how long a key and its response are kept.
+	idempotencyKeyTTL = 24 * time.Hour
+	// idempotencySweepInterval paces the removal of expired keys.
+	idempotencySweepInterval = time.Hour
+	maxIdempotencyKeyLength  = 255
+)
+
+// idempotencyWriter records the response so it can be replayed to retries.
+type idempotencyWriter struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *idempotencyWriter) Write(data []byte) (int, error) {
+	w.body.Write(data)
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *idempotencyWriter) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
+
+// idempotent lets clients retry a create after a timeout without creating
+// the resource twice. The first request with a given key runs the handler
+// and its response is cached; retries with the same key and body get that
+// response back, retries while it is still running get 409 and reusing the
+// key for a different body gets 422. Requests without a key run as before.
+func (api *apiServer) idempotent(handler gin.HandlerFunc) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		store := api.engine.Store()
+		if store == nil {
+			handler(c)
+			return
+		}
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			respondError(c, http.StatusBadRequest, CodeInvalidRequest, err.Error())
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		key := strings.TrimSpace(c.GetHeader(idempotencyKeyHeader))
+		if key == "" {
+			var token struct {
+				RequestID string `json:"request_id"`
+			}
+			if json.Unmarshal(body, &token) == nil {
+				key = strings.TrimSpace(token.RequestID)
+			}
+		}
+		if key == "" {
+			handler(c)
+			return
+		}
+		if len(key) > maxIdempotencyKeyLength {
+			respondError(c, http.StatusBadRequest, CodeInvalidRequest, "idempotency key must be at most 255 characters")
+			return
+		}
+
+		ctx := c.Request.Context()
+		now := time.Now().UTC()
+		api.sweepIdempotencyKeys(ctx, store, now)
+
+		scope := c.Request.Method + " " + c.FullPath()
+		sum := sha256.Sum256(body)
+		hash := hex.EncodeToString(sum[:])
+		existing, reserved, err := store.Queries().IdempotencyKeys().Reserve(ctx, db.IdempotencyKey{
+			Scope:       scope,
+			Key:         key,
+			RequestHash: hash,
+			CreatedAt:   now,
+			ExpiresAt:   now.Add(idempotencyKeyTTL),
+		})
+		if err != nil {
+			api.logger.Error("reserve idempotency key", "scope", scope, "error", err)
+			respondError(c, http.StatusInternalServerError, CodeInternal, "failed to record idempotency key")
+			return
+		}
+		if !reserved {
+			switch {
+			case existing.RequestHash != hash:
+				respondError(c, http.StatusUnprocessableEntity, CodeIdempotencyKeyReused, "idempotency key was already used with a different request")
+			case existing.StatusCode == 0:
+				respondError(c, http.StatusConflict, CodeIdempotencyKeyInProgress, "a request with this idempotency key is still in progress")
+			default:
+				c.Header(idempotencyReplayedHeader, "true")
+				c.Data(existing.StatusCode, "application/json; charset=utf-8", existing.Response)
+			}
+			return
+		}
+
+		// Keep going if the client gives up: the retry will find the
+		// finished response instead of a half-created resource.
+		writer := &idempotencyWriter{ResponseWriter: c.Writer}
+		c.Writer = writer
+		c.Request = c.Request.WithContext(context.WithoutCancel(ctx))
+		completed := false
+		defer func() {
+			if completed {
+				return
+			}
+			// Release the key after a server error or a panic so the
+			// retry runs the request again.
+			if err := store.Queries().IdempotencyKeys().Delete(context.WithoutCancel(ctx), scope, key); err != nil {
+				api.logger.Warn("release idempotency key", "scope", scope, "error", err)
+			}
+		}()
+
+		handler(c)
+
+		status := writer.Status()
+		if status >= http.StatusInternalServerError {
+			return
+		}
+		if err := store.Queries().IdempotencyKeys().Complete(context.WithoutCancel(ctx), scope, key, status, writer.body.Bytes()); err != nil {
+			api.logger.Warn("store idempotent response", "scope", scope, "error", err)
+			return
+		}
+		completed = true
+	}
+}
+
+// sweepIdempotencyKeys removes expired keys at most once per
+// idempotencySweepInterval.
+func (api *apiServer) sweepIdempotencyKeys(ctx context.Context, store db.Store, now time.Time) {
+	api.idempotencyMu.Lock()
+	if now.Sub(api.idempotencySweptAt) < idempotencySweepInterval {
+		api.idempotencyMu.Unlock()
+		return
+	}
+	api.idempotencySweptAt = now
+	api.idempotencyMu.Unlock()
+
+	removed, err := store.Queries().IdempotencyKeys().DeleteExpired(ctx, now)
+	if err != nil {
+		api.logger.Warn("delete expired idempotency keys", "error", err)
+		return
+	}
+	if removed > 0 {
+		api.logger.Debug("deleted expired idempotency keys", "count", removed)
+	}
+}
@@ -0,0 +1,130 @@
+// Copyright (c) 2025 HYPR. PTE. LTD.
+//
+// Business Source License 1.1
+// See LICENSE file in the project root for details.
+
+package httpapi
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/volantvm/volant/internal/server/db"
+	"github.com/volantvm/volant/internal/server/labels"
+)
+
+// Bulk VM actions.
+const (
+	bulkActionStart   = "start"
+	bulkActionStop    = "stop"
+	bulkActionRestart = "restart"
+	bulkActionDelete  = "delete"
+)
+
+type bulkVMRequest struct {
+	Selector string `json:"selector"`
+}
+
+type bulkVMResult struct {
+	Name  string `json:"name"`
+	Error string `json:"error,omitempty"`
+}
+
+type bulkVMResponse struct {
+	Action    string         `json:"action"`
+	Selector  string         `json:"selector"`
+	Matched   int            `json:"matched"`
+	Succeeded int            `json:"succeeded"`
+	Failed    int            `json:"failed"`
+	Results   []bulkVMResult `json:"results"`
+}
+
+// updateVMLabels applies a JSON merge patch to a VM's labels: string values
+// set keys and nulls remove them.
+func (api *apiServer) updateVMLabels(c *gin.Context) {
+	name := c.Param("name")
+	var patch map[string]*string
+	if err := c.ShouldBindJSON(&patch); err != nil {
+		respondError(c, http.StatusBadRequest, CodeInvalidRequest, err.Error())
+		return
+	}
+	vm, err := api.engine.UpdateVMLabels(c.Request.Context(), name, patch)
+	if err != nil {
+		api.logger.Error("update vm labels", "vm", name, "error", err)
+		c.JSON(statusFromError(err), errorResponse(err))
+		return
+	}
+	c.JSON(http.StatusOK, vmToResponse(vm))
+}
+
+// bulkVMAction runs a lifecycle action on every VM matching a label
+// selector. Each VM is handled independently; one failure does not stop the
+// rest.
+func (api *apiServer) bulkVMAction(c *gin.Context) {
+	action := strings.ToLower(strings.TrimSpace(c.Param("action")))
+	var run func(ctx context.Context, name string) error
+	switch action {
+	case bulkActionStart:
+		run = func(ctx context.Context, name string) error { _, err := api.engine.StartVM(ctx, name); return err }
+	case bulkActionStop:
+		run = func(ctx context.Context, name string) error { _, err := api.engine.StopVM(ctx, name); return err }
+	case bulkActionRestart:
+		run = func(ctx context.Context, name string) error { _, err := api.engine.RestartVM(ctx, name); return err }
+	case bulkActionDelete:
+		run = api.engine.DestroyVM
+	default:
+		respondError(c, http.StatusBadRequest, CodeInvalidRequest, "action must be one of start, stop, restart, delete")
+		return
+	}
+
+	expr := strings.TrimSpace(c.Query("selector"))
+	if expr == "" && c.Request.ContentLength != 0 {
+		var req bulkVMRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			respondError(c, http.StatusBadRequest, CodeInvalidRequest, err.Error())
+			return
+		}
+		expr = strings.TrimSpace(req.Selector)
+	}
+	selector, err := labels.Parse(expr)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, CodeInvalidRequest, err.Error())
+		return
+	}
+	// An empty selector matches everything; refuse it rather than stop or
+	// delete every VM on the host by accident.
+	if len(selector) == 0 {
+		respondError(c, http.StatusBadRequest, CodeInvalidRequest, "selector is required")
+		return
+	}
+
+	ctx := c.Request.Context()
+	vms, _, err := api.engine.ListVMsPage(ctx, db.VMListOptions{Selector: selector, SortBy: db.VMSortName, Limit: -1})
+	if err != nil {
+		api.logger.Error("bulk vm action", "action", action, "selector", expr, "error", err)
+		c.JSON(statusFromError(err), errorResponse(err))
+		return
+	}
+	resp := bulkVMResponse{
+		Action:   action,
+		Selector: selector.String(),
+		Matched:  len(vms),
+		Results:  make([]bulkVMResult, 0, len(vms)),
+	}
+	for _, vm := range vms {
+		result := bulkVMResult{Name: vm.Name}
+		if err := run(ctx, vm.Name); err != nil {
+			api.logger.Warn("bulk vm action failed", "action", action, "vm", vm.Name, "error", err)
+			result.Error = err.Error()
+			resp.Failed++
+		} else {
+			resp.Succeeded++
+		}
+		resp.Results = append(resp.Results, result)
+	}
+	api.logger.Info("bulk vm action", "action", action, "selector", resp.Selector, "matched", resp.Matched, "failed", resp.Failed)
+	c.JSON(http.StatusOK, resp)
+}
@@ -0,0 +1,235 @@
+// Copyright (c) 2025 HYPR. PTE. LTD.
+//
+// Business Source License 1.1
+// See LICENSE file in the project root for details.
+
+package httpapi
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/volantvm/volant/internal/server/db"
+	"github.com/volantvm/volant/internal/server/orchestrator"
+)
+
+type createMaintenanceWindowRequest struct {
+	Name   string `json:"name" binding:"required"`
+	Scope  string `json:"scope,omitempty"`
+	Target string `json:"target,omitempty"`
+	Reason string `json:"reason,omitempty"`
+	// StartsAt defaults to now. Either EndsAt or Duration (e.g. "2h") is required.
+	StartsAt *time.Time `json:"starts_at,omitempty"`
+	EndsAt   *time.Time `json:"ends_at,omitempty"`
+	Duration string     `json:"duration,omitempty"`
+}
+
+type maintenanceWindowResponse struct {
+	Name      string                      `json:"name"`
+	Scope     string                      `json:"scope"`
+	Target    string                      `json:"target,omitempty"`
+	Reason    string                      `json:"reason,omitempty"`
+	StartsAt  time.Time                   `json:"starts_at"`
+	EndsAt    time.Time                   `json:"ends_at"`
+	Active    bool                        `json:"active"`
+	CreatedAt time.Time                   `json:"created_at"`
+	Intents   []maintenanceIntentResponse `json:"intents,omitempty"`
+}
+
+type maintenanceIntentResponse struct {
+	Source     string     `json:"source"`
+	Target     string     `json:"target"`
+	Action     string     `json:"action"`
+	Count      int        `json:"count"`
+	RecordedAt time.Time  `json:"recorded_at"`
+	ReplayedAt *time.Time `json:"replayed_at,omitempty"`
+	Outcome    string     `json:"outcome,omitempty"`
+}
+
+func maintenanceWindowToResponse(window db.MaintenanceWindow) maintenanceWindowResponse {
+	return maintenanceWindowResponse{
+		Name:      window.Name,
+		Scope:     window.Scope,
+		Target:    window.Target,
+		Reason:    window.Reason,
+		StartsAt:  window.StartsAt,
+		EndsAt:    window.EndsAt,
+		Active:    orchestrator.MaintenanceWindowOpen(window, time.Now()),
+		CreatedAt: window.CreatedAt,
+	}
+}
+
+func maintenanceIntentToResponse(intent db.MaintenanceIntent) maintenanceIntentResponse {
+	return maintenanceIntentResponse{
+		Source:     intent.Source,
+		Target:     intent.Target,
+		Action:     intent.Action,
+		Count:      intent.Count,
+		RecordedAt: intent.RecordedAt,
+		ReplayedAt: intent.ReplayedAt,
+		Outcome:    intent.Outcome,
+	}
+}
+
+func (api *apiServer) listMaintenanceWindows(c *gin.Context) {
+	windows, err := api.engine.ListMaintenanceWindows(c.Request.Context())
+	if err != nil {
+		api.logger.Error("list maintenance windows", "error", err)
+		c.JSON(statusFromError(err), errorResponse(err))
+		return
+	}
+	resp := make([]maintenanceWindowResponse, 0, len(windows))
+	for _, window := range windows {
+		resp = append(resp, maintenanceWindowToResponse(window))
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+func (api *apiServer) createMaintenanceWindow(c *gin.Context) {
+	var req createMaintenanceWindowRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, http.StatusBadRequest, CodeInvalidRequest, err.Error())
+		return
+	}
+	start := time.Now().UTC()
+	if req.StartsAt != nil {
+		start = *req.StartsAt
+	}
+	var end time.Time
+	switch {
+	case req.EndsAt != nil && strings.TrimSpace(req.Duration) != "":
+		respondError(c, http.StatusBadRequest, CodeInvalidRequest, "set either ends_at or duration, not both")
+		return
+	case req.EndsAt != nil:
+		end = *req.EndsAt
+	case strings.TrimSpace(req.Duration) != "":
+		duration, err := time.ParseDuration(strings.TrimSpace(req.Duration))
+		if err != nil || duration <= 0 {
+			respondError(c, http.StatusBadRequest, CodeInvalidRequest, "invalid duration")
+			return
+		}
+		end = start.Add(duration)
+	default:
+		respondError(c, http.StatusBadRequest, CodeInvalidRequest, "ends_at or duration is required")
+		return
+	}
+
+	window, err := api.engine.CreateMaintenanceWindow(c.Request.Context(), orchestrator.CreateMaintenanceWindowRequest{
+		Name:     req.Name,
+		Scope:    req.Scope,
+		Target:   req.Target,
+		Reason:   req.Reason,
+		StartsAt: start,
+		EndsAt:   end,
+	})
+	if err != nil {
+		api.logger.Error("create maintenance window", "window", req.Name, "error", err)
+		c.JSON(statusFromError(err), errorResponse(err))
+		return
+	}
+	c.JSON(http.StatusCreated, maintenanceWindowToResponse(*window))
+}
+
+func (api *apiServer) getMaintenanceWindow(c *gin.Context) {
+	name := c.Param("name")
+	window, err := api.engine.GetMaintenanceWindow(c.Request.Context(), name)
+	if err != nil {
+		c.JSON(statusFromError(err), errorResponse(err))
+		return
+	}
+	intents, err := api.engine.MaintenanceIntents(c.Request.Context(), name)
+	if err != nil {
+		api.logger.Error("list maintenance intents", "window", name, "error", err)
+		c.JSON(statusFromError(err), errorResponse(err))
+		return
+	}
+	resp := maintenanceWindowToResponse(*window)
+	for _, intent := range intents {
+		resp.Intents = append(resp.Intents, maintenanceIntentToResponse(intent))
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+func (api *apiServer) endMaintenanceWindow(c *gin.Context) {
+	name := c.Param("name")
+	window, err := api.engine.EndMaintenanceWindow(c.Request.Context(), name)
+	if err != nil {
+		api.logger.Error("end maintenance window", "window", name, "error", err)
+		c.JSON(statusFromError(err), errorResponse(err))
+		return
+	}
+	c.JSON(http.StatusOK, maintenanceWindowToResponse(*window))
+}
+
+func (api *apiServer) deleteMaintenanceWindow(c *gin.Context) {
+	name := c.Param("name")
+	if err := api.engine.DeleteMaintenanceWindow(c.Request.Context(), name); err != nil {
+		api.logger.Error("delete maintenance window", "window", name, "error", err)
+		c.JSON(statusFromError(err), errorResponse(err))
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+type hostMaintenanceRequest struct {
+	Reason string `json:"reason,omitempty"`
+	// Concurrency caps how many VMs are stopped or restarted at once;
+	// defaults to 4.
+	Concurrency int `json:"concurrency,omitempty"`
+}
+
+// GET /api/v1/system/maintenance
+func (api *apiServer) getHostMaintenance(c *gin.Context) {
+	status, err := api.engine.HostMaintenance(c.Request.Context())
+	if err != nil {
+		api.logger.Error("get host maintenance", "error", err)
+		c.JSON(statusFromError(err), errorResponse(err))
+		return
+	}
+	c.JSON(http.StatusOK, status)
+}
+
+// POST /api/v1/system/maintenance
+func (api *apiServer) enterHostMaintenance(c *gin.Context) {
+	var req hostMaintenanceRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		respondError(c, http.StatusBadRequest, CodeInvalidRequest, err.Error())
+		return
+	}
+	if req.Concurrency < 0 {
+		respondError(c, http.StatusBadRequest, CodeInvalidRequest, "concurrency must be >= 0")
+		return
+	}
+	status, err := api.engine.EnterHostMaintenance(c.Request.Context(), orchestrator.HostMaintenanceRequest{Reason: req.Reason, Concurrency: req.Concurrency})
+	if err != nil {
+		api.logger.Error("enter host maintenance", "error", err)
+		c.JSON(statusFromError(err), errorResponse(err))
+		return
+	}
+	c.JSON(http.StatusOK, status)
+}
+
+// POST /api/v1/system/maintenance/exit
+func (api *apiServer) exitHostMaintenance(c *gin.Context) {
+	var req hostMaintenanceRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		respondError(c, http.StatusBadRequest, CodeInvalidRequest, err.Error())
+		return
+	}
+	if req.Concurrency < 0 {
+		respondError(c, http.StatusBadRequest, CodeInvalidRequest, "concurrency must be >= 0")
+		return
+	}
+	status, err := api.engine.ExitHostMaintenance(c.Request.Context(), req.Concurrency)
+	if err != nil {
+		api.logger.Error("exit host maintenance", "error", err)
+		c.JSON(statusFromError(err), errorResponse(err))
+		return
+	}
+	c.JSON(http.StatusOK, status)
+}
@@ -0,0 +1,123 @@
+// Copyright (c) 2025 HYPR. PTE. LTD.
+//
+// Business Source License 1.1
+// See LICENSE file in the project root for details.
+
+package httpapi
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/volantvm/volant/internal/server/db"
+	"github.com/volantvm/volant/internal/server/orchestrator"
+)
+
+type createNetworkRequest struct {
+	Name   string `json:"name" binding:"required"`
+	Subnet string `json:"subnet" binding:"required"`
+	// Gateway defaults to the first host address of the subnet.
+	Gateway string `json:"gateway,omitempty"`
+	// Bridge defaults to "vn-<name>".
+	Bridge string `json:"bridge,omitempty"`
+	// Internal networks are not masqueraded to the outside world.
+	Internal bool `json:"internal,omitempty"`
+}
+
+type networkResponse struct {
+	Name      string                 `json:"name"`
+	Bridge    string                 `json:"bridge"`
+	Subnet    string                 `json:"subnet"`
+	Gateway   string                 `json:"gateway"`
+	Internal  bool                   `json:"internal"`
+	CreatedAt time.Time              `json:"created_at"`
+	Leases    []networkLeaseResponse `json:"leases,omitempty"`
+}
+
+type networkLeaseResponse struct {
+	IPAddress string    `json:"ip_address"`
+	VM        string    `json:"vm"`
+	LeasedAt  time.Time `json:"leased_at"`
+}
+
+func networkToResponse(netw db.Network) networkResponse {
+	return networkResponse{
+		Name:      netw.Name,
+		Bridge:    netw.Bridge,
+		Subnet:    netw.Subnet,
+		Gateway:   netw.Gateway,
+		Internal:  netw.Internal,
+		CreatedAt: netw.CreatedAt,
+	}
+}
+
+func (api *apiServer) listNetworks(c *gin.Context) {
+	networks, err := api.engine.ListNetworks(c.Request.Context())
+	if err != nil {
+		api.logger.Error("list networks", "error", err)
+		c.JSON(statusFromError(err), errorResponse(err))
+		return
+	}
+	resp := make([]networkResponse, 0, len(networks))
+	for _, netw := range networks {
+		resp = append(resp, networkToResponse(netw))
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+func (api *apiServer) createNetwork(c *gin.Context) {
+	var req createNetworkRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, http.StatusBadRequest, CodeInvalidRequest, err.Error())
+		return
+	}
+	netw, err := api.engine.CreateNetwork(c.Request.Context(), orchestrator.CreateNetworkRequest{
+		Name:     req.Name,
+		Subnet:   req.Subnet,
+		Gateway:  req.Gateway,
+		Bridge:   req.Bridge,
+		Internal: req.Internal,
+	})
+	if err != nil {
+		api.logger.Error("create network", "network", req.Name, "error", err)
+		c.JSON(statusFromError(err), errorResponse(err))
+		return
+	}
+	c.JSON(http.StatusCreated, networkToResponse(*netw))
+}
+
+func (api *apiServer) getNetwork(c *gin.Context) {
+	name := c.Param("name")
+	netw, err := api.engine.GetNetwork(c.Request.Context(), name)
+	if err != nil {
+		c.JSON(statusFromError(err), errorResponse(err))
+		return
+	}
+	leases, err := api.engine.NetworkLeases(c.Request.Context(), name)
+	if err != nil {
+		api.logger.Error("list network leases", "network", name, "error", err)
+		c.JSON(statusFromError(err), errorResponse(err))
+		return
+	}
+	resp := networkToResponse(*netw)
+	for _, lease := range leases {
+		resp.Leases = append(resp.Leases, networkLeaseResponse{
+			IPAddress: lease.IPAddress,
+			VM:        lease.VMName,
+			LeasedAt:  lease.LeasedAt,
+		})
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+func (api *apiServer) deleteNetwork(c *gin.Context) {
+	name := c.Param("name")
+	if err := api.engine.DeleteNetwork(c.Request.Context(), name); err != nil {
+		api.logger.Error("delete network", "network", name, "error", err)
+		c.JSON(statusFromError(err), errorResponse(err))
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
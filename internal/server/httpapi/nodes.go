@@ -0,0 +1,77 @@
+// Copyright (c) 2025 HYPR. PTE. LTD.
+//
+// Business Source License 1.1
+// See LICENSE file in the project root for details.
+
+package httpapi
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/volantvm/volant/internal/server/orchestrator"
+)
+
+type nodeArtifactResponse struct {
+	Kind string `json:"kind"`
+	// Name is set for cached artifacts, which peers fetch from
+	// /api/v1/nodes/:node/artifacts/:name.
+	Name       string    `json:"name,omitempty"`
+	Path       string    `json:"path"`
+	SizeBytes  int64     `json:"size_bytes"`
+	ModifiedAt time.Time `json:"modified_at"`
+}
+
+type nodeArtifactsResponse struct {
+	Node      string                 `json:"node"`
+	Artifacts []nodeArtifactResponse `json:"artifacts"`
+}
+
+func (api *apiServer) listNodeArtifacts(c *gin.Context) {
+	node := c.Param("node")
+	artifacts, err := api.engine.NodeArtifacts(c.Request.Context(), node)
+	if err != nil {
+		api.logger.Error("list node artifacts", "node", node, "error", err)
+		c.JSON(statusFromError(err), errorResponse(err))
+		return
+	}
+	resp := nodeArtifactsResponse{Node: orchestrator.NodeName(), Artifacts: make([]nodeArtifactResponse, 0, len(artifacts))}
+	for _, artifact := range artifacts {
+		resp.Artifacts = append(resp.Artifacts, nodeArtifactResponse{
+			Kind:       artifact.Kind,
+			Name:       artifact.Name,
+			Path:       artifact.Path,
+			SizeBytes:  artifact.SizeBytes,
+			ModifiedAt: artifact.ModifiedAt,
+		})
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// getNodeArtifact serves a cached boot artifact so peer hosts can pull it
+// instead of downloading it from its origin.
+func (api *apiServer) getNodeArtifact(c *gin.Context) {
+	node := c.Param("node")
+	name := strings.TrimPrefix(c.Param("name"), "/")
+	f, err := api.engine.OpenNodeArtifact(c.Request.Context(), node, name)
+	if err != nil {
+		if statusFromError(err) >= http.StatusInternalServerError {
+			api.logger.Error("open node artifact", "node", node, "name", name, "error", err)
+		}
+		c.JSON(statusFromError(err), errorResponse(err))
+		return
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		api.logger.Error("stat node artifact", "node", node, "name", name, "error", err)
+		respondError(c, http.StatusInternalServerError, CodeInternal, "failed to open artifact")
+		return
+	}
+	clearDeadlines(c)
+	c.Header("Content-Type", "application/octet-stream")
+	http.ServeContent(c.Writer, c.Request, "", info.ModTime(), f)
+}
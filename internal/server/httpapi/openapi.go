@@ -6,19 +6,34 @@
 package httpapi
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"reflect"
+	"slices"
 	"strings"
+	"unicode"
 
 	openapi3 "github.com/getkin/kin-openapi/openapi3"
 	"github.com/getkin/kin-openapi/openapi3gen"
+	"gopkg.in/yaml.v3"
 
+	"github.com/volantvm/volant/internal/drift/routes"
+	"github.com/volantvm/volant/internal/pluginspec"
+	"github.com/volantvm/volant/internal/server/apiauth"
+	"github.com/volantvm/volant/internal/server/config"
 	"github.com/volantvm/volant/internal/server/db"
+	"github.com/volantvm/volant/internal/server/mcp"
+	"github.com/volantvm/volant/internal/server/orchestrator"
+	"github.com/volantvm/volant/internal/server/orchestrator/doctor"
 	orchestratorevents "github.com/volantvm/volant/internal/server/orchestrator/events"
+	"github.com/volantvm/volant/internal/server/orchestrator/vmconfig"
+	"github.com/volantvm/volant/internal/server/webhooks"
 )
 
-// serveOpenAPI returns an OpenAPI v3 JSON document generated from server types.
+// serveOpenAPI returns an OpenAPI v3 document generated from server types:
+// JSON by default, YAML for ?format=yaml or an Accept header asking for it.
 func (api *apiServer) serveOpenAPI(w http.ResponseWriter, r *http.Request) {
 	baseURL := ""
 	if r != nil && r.Host != "" {
@@ -34,16 +49,36 @@ func (api *apiServer) serveOpenAPI(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, fmt.Sprintf("failed to build openapi: %v", err), http.StatusInternalServerError)
 		return
 	}
-	data, err := json.Marshal(spec)
+	contentType := "application/json"
+	marshal := json.Marshal
+	if r != nil && (strings.EqualFold(r.URL.Query().Get("format"), "yaml") || strings.Contains(r.Header.Get("Accept"), "yaml")) {
+		contentType = "application/yaml"
+		marshal = marshalYAML
+	}
+	data, err := marshal(spec)
 	if err != nil {
 		http.Error(w, fmt.Sprintf("failed to marshal openapi: %v", err), http.StatusInternalServerError)
 		return
 	}
-	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Content-Type", contentType)
 	w.WriteHeader(http.StatusOK)
 	_, _ = w.Write(data)
 }
 
+// marshalYAML encodes v as YAML indented by two spaces.
+func marshalYAML(v any) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(v); err != nil {
+		return nil, err
+	}
+	if err := enc.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
 // BuildOpenAPISpec constructs the OpenAPI spec. If baseURL is non-empty, it will be set as the server URL.
 func BuildOpenAPISpec(baseURL string) (*openapi3.T, error) {
 	// Initialize spec
@@ -65,18 +100,22 @@ func BuildOpenAPISpec(baseURL string) (*openapi3.T, error) {
 	gen := openapi3gen.NewGenerator(
 		openapi3gen.CreateComponentSchemas(openapi3gen.ExportComponentSchemasOptions{
 			ExportComponentSchemas: true,
-			ExportTopLevelSchema:   false,
+			ExportTopLevelSchema:   true,
 			ExportGenerics:         true,
 		}),
+		openapi3gen.UseAllExportedFields(),
+		openapi3gen.CreateTypeNameGenerator(schemaName),
+		openapi3gen.SchemaCustomizer(customizeSchema),
 	)
 
 	// Register common schemas from our request/response types
 	// VM and deployment types
 	vmRespRef, _ := gen.NewSchemaRefForValue(&vmResponse{}, spec.Components.Schemas)
 	createVMReqRef, _ := gen.NewSchemaRefForValue(&createVMRequest{}, spec.Components.Schemas)
+	operationRespRef, _ := gen.NewSchemaRefForValue(&operationResponse{}, spec.Components.Schemas)
 	sysStatusRef, _ := gen.NewSchemaRefForValue(&SystemStatusResponse{}, spec.Components.Schemas)
-	mcpReqRef, _ := gen.NewSchemaRefForValue(&MCPRequest{}, spec.Components.Schemas)
-	mcpRespRef, _ := gen.NewSchemaRefForValue(&MCPResponse{}, spec.Components.Schemas)
+	mcpReqRef, _ := gen.NewSchemaRefForValue(&mcp.Request{}, spec.Components.Schemas)
+	mcpRespRef, _ := gen.NewSchemaRefForValue(&mcp.Response{}, spec.Components.Schemas)
 	// Phase 3 additions
 	sysSummaryRef, _ := gen.NewSchemaRefForValue(&systemSummaryResponse{}, spec.Components.Schemas)
 	pluginArtifactRef, _ := gen.NewSchemaRefForValue(&db.PluginArtifact{}, spec.Components.Schemas)
@@ -85,18 +124,48 @@ func BuildOpenAPISpec(baseURL string) (*openapi3.T, error) {
 	vmEventRef, _ := gen.NewSchemaRefForValue(&orchestratorevents.VMEvent{}, spec.Components.Schemas)
 
 	// Helper: standard error schema
+	codeSchema := openapi3.NewStringSchema()
+	codeSchema.Description = errorCodesDescription()
+	for _, code := range errorCodes() {
+		codeSchema.Enum = append(codeSchema.Enum, code.code)
+	}
+	messageSchema := openapi3.NewStringSchema()
+	messageSchema.Description = "Human-readable description; not stable across releases."
+	legacyErrorSchema := openapi3.NewStringSchema()
+	legacyErrorSchema.Description = "Same as message; kept for clients that predate codes."
+	legacyErrorSchema.Deprecated = true
 	errorSchema := openapi3.NewSchemaRef("", &openapi3.Schema{
-		Type: &openapi3.Types{openapi3.TypeObject},
+		Type:     &openapi3.Types{openapi3.TypeObject},
+		Required: []string{"code", "message"},
 		Properties: map[string]*openapi3.SchemaRef{
-			"error": openapi3.NewSchemaRef("", openapi3.NewStringSchema()),
+			"code":    openapi3.NewSchemaRef("", codeSchema),
+			"message": openapi3.NewSchemaRef("", messageSchema),
 			"details": openapi3.NewSchemaRef("", &openapi3.Schema{
-				Type:                 &openapi3.Types{openapi3.TypeObject},
-				AdditionalProperties: openapi3.AdditionalProperties{Schema: openapi3.NewSchemaRef("", openapi3.NewStringSchema())},
+				Type:        &openapi3.Types{openapi3.TypeObject},
+				Description: "Code-specific fields, such as missing_features for HOST_FEATURES_MISSING or hint for launch failures.",
 			}),
+			"error": openapi3.NewSchemaRef("", legacyErrorSchema),
 		},
 	})
 	spec.Components.Schemas["Error"] = errorSchema
 
+	// The API key is only checked when volantd has one configured.
+	// Websockets, which cannot set headers, pass it as a query parameter.
+	spec.Components.SecuritySchemes = openapi3.SecuritySchemes{
+		"apiKey": &openapi3.SecuritySchemeRef{Value: openapi3.NewSecurityScheme().WithType("apiKey").WithIn("header").WithName(apiauth.Header).
+			WithDescription("Required when volantd runs with " + apiauth.EnvKey + " set")},
+		"apiKeyQuery": &openapi3.SecuritySchemeRef{Value: openapi3.NewSecurityScheme().WithType("apiKey").WithIn("query").WithName(apiauth.QueryParam).
+			WithDescription("The API key for clients that cannot set headers, such as browser websockets")},
+	}
+	spec.Security = openapi3.SecurityRequirements{{"apiKey": []string{}}, {"apiKeyQuery": []string{}}, {}}
+
+	// Counts and page sizes in query parameters and bodies.
+	countSchema := openapi3.NewSchemaRef("", openapi3.NewIntegerSchema().WithMin(0))
+	limitParam := &openapi3.ParameterRef{Value: &openapi3.Parameter{Name: "limit", In: openapi3.ParameterInQuery, Description: "Max items to return", Schema: countSchema}}
+
+	idempotencyKeyParam := &openapi3.ParameterRef{Value: openapi3.NewHeaderParameter(idempotencyKeyHeader).WithSchema(openapi3.NewStringSchema()).
+		WithDescription("Client request token; retries with the same token and body replay the first response for 24h")}
+
 	// /healthz
 	spec.AddOperation("/healthz", http.MethodGet, func() *openapi3.Operation {
 		op := openapi3.NewOperation()
@@ -151,6 +220,25 @@ func BuildOpenAPISpec(baseURL string) (*openapi3.T, error) {
 		return op
 	}())
 
+	// /api/v1/dashboard
+	dashboardRef, _ := gen.NewSchemaRefForValue(&dashboardResponse{}, spec.Components.Schemas)
+	spec.AddOperation("/api/v1/dashboard", http.MethodGet, func() *openapi3.Operation {
+		op := openapi3.NewOperation()
+		op.Summary = "Composite dashboard payload"
+		op.Description = "VM summaries, deployment states, recent lifecycle events, plugin health and host utilization in one response. Sent with Cache-Control max-age and an ETag; conditional requests return 304 when unchanged."
+		op.OperationID = "getDashboard"
+		op.Tags = []string{"status"}
+		op.Parameters = openapi3.Parameters{&openapi3.ParameterRef{Value: &openapi3.Parameter{Name: "If-None-Match", In: openapi3.ParameterInHeader, Schema: openapi3.NewSchemaRef("", openapi3.NewStringSchema())}}}
+		op.Responses = openapi3.NewResponses()
+		{
+			resp := openapi3.NewResponse().WithDescription("Dashboard")
+			resp.Content = openapi3.NewContentWithJSONSchemaRef(dashboardRef)
+			op.Responses.Set("200", &openapi3.ResponseRef{Value: resp})
+		}
+		op.Responses.Set("304", &openapi3.ResponseRef{Value: openapi3.NewResponse().WithDescription("Not modified")})
+		return op
+	}())
+
 	// /api/v1/vms
 	spec.AddOperation("/api/v1/vms", http.MethodGet, func() *openapi3.Operation {
 		op := openapi3.NewOperation()
@@ -162,9 +250,11 @@ func BuildOpenAPISpec(baseURL string) (*openapi3.T, error) {
 			&openapi3.ParameterRef{Value: &openapi3.Parameter{Name: "status", In: openapi3.ParameterInQuery, Description: "Filter by status (repeatable or comma-separated)", Schema: openapi3.NewSchemaRef("", openapi3.NewStringSchema())}},
 			&openapi3.ParameterRef{Value: &openapi3.Parameter{Name: "runtime", In: openapi3.ParameterInQuery, Description: "Filter by runtime", Schema: openapi3.NewSchemaRef("", openapi3.NewStringSchema())}},
 			&openapi3.ParameterRef{Value: &openapi3.Parameter{Name: "plugin", In: openapi3.ParameterInQuery, Description: "Filter by plugin name", Schema: openapi3.NewSchemaRef("", openapi3.NewStringSchema())}},
+			&openapi3.ParameterRef{Value: &openapi3.Parameter{Name: "group", In: openapi3.ParameterInQuery, Description: "Filter by deployment name", Schema: openapi3.NewSchemaRef("", openapi3.NewStringSchema())}},
 			&openapi3.ParameterRef{Value: &openapi3.Parameter{Name: "q", In: openapi3.ParameterInQuery, Description: "Free text search (name, ip, runtime)", Schema: openapi3.NewSchemaRef("", openapi3.NewStringSchema())}},
-			&openapi3.ParameterRef{Value: &openapi3.Parameter{Name: "limit", In: openapi3.ParameterInQuery, Description: "Max items to return", Schema: openapi3.NewSchemaRef("", openapi3.NewIntegerSchema())}},
-			&openapi3.ParameterRef{Value: &openapi3.Parameter{Name: "offset", In: openapi3.ParameterInQuery, Description: "Items to skip (for pagination)", Schema: openapi3.NewSchemaRef("", openapi3.NewIntegerSchema())}},
+			&openapi3.ParameterRef{Value: &openapi3.Parameter{Name: "selector", In: openapi3.ParameterInQuery, Description: "Label selector, e.g. env=prod,tier!=db,canary,!legacy", Schema: openapi3.NewSchemaRef("", openapi3.NewStringSchema())}},
+			&openapi3.ParameterRef{Value: &openapi3.Parameter{Name: "limit", In: openapi3.ParameterInQuery, Description: "Max items to return", Schema: countSchema}},
+			&openapi3.ParameterRef{Value: &openapi3.Parameter{Name: "offset", In: openapi3.ParameterInQuery, Description: "Items to skip (for pagination)", Schema: countSchema}},
 			&openapi3.ParameterRef{Value: &openapi3.Parameter{Name: "sort", In: openapi3.ParameterInQuery, Description: "Sort field (name,status,runtime,created_at,updated_at)", Schema: openapi3.NewSchemaRef("", openapi3.NewStringSchema())}},
 			&openapi3.ParameterRef{Value: &openapi3.Parameter{Name: "order", In: openapi3.ParameterInQuery, Description: "Sort order (asc,desc)", Schema: openapi3.NewSchemaRef("", openapi3.NewStringSchema())}},
 		)
@@ -192,6 +282,11 @@ func BuildOpenAPISpec(baseURL string) (*openapi3.T, error) {
 		op.Summary = "Create VM"
 		op.OperationID = "createVM"
 		op.Tags = []string{"vm"}
+		op.Description = "Waits for the VM to boot unless async is requested with ?async=true, \"Prefer: respond-async\" or \"async\": true, in which case an operation is returned immediately."
+		op.Parameters = openapi3.Parameters{
+			idempotencyKeyParam,
+			&openapi3.ParameterRef{Value: openapi3.NewQueryParameter("async").WithSchema(openapi3.NewBoolSchema()).WithDescription("Return 202 with an operation instead of waiting for the boot")},
+		}
 		op.RequestBody = &openapi3.RequestBodyRef{Value: &openapi3.RequestBody{Required: true, Content: openapi3.NewContentWithJSONSchemaRef(createVMReqRef)}}
 		op.Responses = openapi3.NewResponses()
 		// 201
@@ -200,6 +295,13 @@ func BuildOpenAPISpec(baseURL string) (*openapi3.T, error) {
 			resp.Content = openapi3.NewContentWithJSONSchemaRef(vmRespRef)
 			op.Responses.Set("201", &openapi3.ResponseRef{Value: resp})
 		}
+		// 202
+		{
+			resp := openapi3.NewResponse().WithDescription("Creation accepted; poll the operation in the Location header")
+			resp.Content = openapi3.NewContentWithJSONSchemaRef(operationRespRef)
+			resp.Headers = openapi3.Headers{"Location": &openapi3.HeaderRef{Value: &openapi3.Header{Parameter: openapi3.Parameter{Schema: openapi3.NewSchemaRef("", openapi3.NewStringSchema())}}}}
+			op.Responses.Set("202", &openapi3.ResponseRef{Value: resp})
+		}
 		// 400
 		{
 			resp := openapi3.NewResponse().WithDescription("Bad request")
@@ -212,6 +314,12 @@ func BuildOpenAPISpec(baseURL string) (*openapi3.T, error) {
 			resp.Content = openapi3.NewContentWithJSONSchemaRef(errorSchema)
 			op.Responses.Set("409", &openapi3.ResponseRef{Value: resp})
 		}
+		// 422
+		{
+			resp := openapi3.NewResponse().WithDescription("Idempotency key reused with a different request")
+			resp.Content = openapi3.NewContentWithJSONSchemaRef(errorSchema)
+			op.Responses.Set("422", &openapi3.ResponseRef{Value: resp})
+		}
 		// 500
 		{
 			resp := openapi3.NewResponse().WithDescription("Internal error")
@@ -223,6 +331,23 @@ func BuildOpenAPISpec(baseURL string) (*openapi3.T, error) {
 
 	// /api/v1/vms/{name}
 	nameParam := &openapi3.ParameterRef{Value: &openapi3.Parameter{Name: "name", In: openapi3.ParameterInPath, Required: true, Schema: openapi3.NewSchemaRef("", openapi3.NewStringSchema())}}
+	spec.AddOperation("/api/v1/operations/{id}", http.MethodGet, func() *openapi3.Operation {
+		op := openapi3.NewOperation()
+		op.Summary = "Get operation"
+		op.Description = "Reports the status of an asynchronous request such as an async VM create."
+		op.OperationID = "getOperation"
+		op.Tags = []string{"operations"}
+		op.Parameters = openapi3.Parameters{&openapi3.ParameterRef{Value: &openapi3.Parameter{Name: "id", In: openapi3.ParameterInPath, Required: true, Schema: openapi3.NewSchemaRef("", openapi3.NewStringSchema())}}}
+		op.Responses = openapi3.NewResponses()
+		{
+			resp := openapi3.NewResponse().WithDescription("Operation")
+			resp.Content = openapi3.NewContentWithJSONSchemaRef(operationRespRef)
+			op.Responses.Set("200", &openapi3.ResponseRef{Value: resp})
+		}
+		op.Responses.Set("404", &openapi3.ResponseRef{Value: openapi3.NewResponse().WithDescription("Operation not found").WithContent(openapi3.NewContentWithJSONSchemaRef(errorSchema))})
+		return op
+	}())
+
 	spec.AddOperation("/api/v1/vms/{name}", http.MethodGet, func() *openapi3.Operation {
 		op := openapi3.NewOperation()
 		op.Summary = "Fetch VM by name"
@@ -284,6 +409,59 @@ func BuildOpenAPISpec(baseURL string) (*openapi3.T, error) {
 		return op
 	}())
 
+	// /api/v1/agui
+	aguiEventSchema := func() *openapi3.Schema {
+		s := openapi3.NewObjectSchema()
+		s.Properties = map[string]*openapi3.SchemaRef{
+			"type": openapi3.NewSchemaRef("", openapi3.NewStringSchema().WithMinLength(1)),
+		}
+		s.Required = []string{"type"}
+		return s
+	}()
+	spec.AddOperation("/api/v1/vms/{name}/agui/events", http.MethodPost, func() *openapi3.Operation {
+		op := openapi3.NewOperation()
+		op.Summary = "Forward AG-UI events from a VM"
+		op.Description = "Accepts AG-UI protocol events emitted by automation inside the VM, one object or an array of up to 256, and publishes them on /api/v1/agui/stream with vm added. Events without a timestamp get the receive time in epoch milliseconds."
+		op.OperationID = "postVMAGUIEvents"
+		op.Tags = []string{"events"}
+		op.Parameters = openapi3.Parameters{&openapi3.ParameterRef{Value: &openapi3.Parameter{Name: "name", In: openapi3.ParameterInPath, Required: true, Schema: openapi3.NewSchemaRef("", openapi3.NewStringSchema())}}}
+		body := &openapi3.Schema{OneOf: openapi3.SchemaRefs{
+			openapi3.NewSchemaRef("", aguiEventSchema),
+			openapi3.NewSchemaRef("", openapi3.NewArraySchema().WithItems(aguiEventSchema).WithMaxItems(aguiMaxBatch)),
+		}}
+		op.RequestBody = &openapi3.RequestBodyRef{Value: &openapi3.RequestBody{Required: true, Content: openapi3.NewContentWithJSONSchema(body)}}
+		op.Responses = openapi3.NewResponses()
+		{
+			accepted := openapi3.NewObjectSchema()
+			accepted.Properties = map[string]*openapi3.SchemaRef{"accepted": openapi3.NewSchemaRef("", openapi3.NewIntegerSchema())}
+			op.Responses.Set("202", &openapi3.ResponseRef{Value: openapi3.NewResponse().WithDescription("Events published").WithContent(openapi3.NewContentWithJSONSchema(accepted))})
+		}
+		op.Responses.Set("400", &openapi3.ResponseRef{Value: openapi3.NewResponse().WithDescription("Invalid events").WithContent(openapi3.NewContentWithJSONSchemaRef(errorSchema))})
+		op.Responses.Set("404", &openapi3.ResponseRef{Value: openapi3.NewResponse().WithDescription("VM not found").WithContent(openapi3.NewContentWithJSONSchemaRef(errorSchema))})
+		return op
+	}())
+
+	spec.AddOperation("/api/v1/agui/stream", http.MethodGet, func() *openapi3.Operation {
+		op := openapi3.NewOperation()
+		op.Summary = "Stream AG-UI events (SSE)"
+		op.Description = "Multiplexes the AG-UI events forwarded from every VM. Each data line is one AG-UI event with vm added, and each SSE id is a sequence number: reconnecting with Last-Event-ID replays the buffered events after it."
+		op.OperationID = "streamAGUIEvents"
+		op.Tags = []string{"events"}
+		op.Parameters = openapi3.Parameters{
+			&openapi3.ParameterRef{Value: &openapi3.Parameter{Name: "vm", In: openapi3.ParameterInQuery, Description: "Only events from these VMs (comma-separated or repeated)", Schema: openapi3.NewSchemaRef("", openapi3.NewStringSchema())}},
+			&openapi3.ParameterRef{Value: &openapi3.Parameter{Name: "type", In: openapi3.ParameterInQuery, Description: "Only these AG-UI event types, e.g. RUN_STARTED,TOOL_CALL_START", Schema: openapi3.NewSchemaRef("", openapi3.NewStringSchema())}},
+			&openapi3.ParameterRef{Value: &openapi3.Parameter{Name: "last_event_id", In: openapi3.ParameterInQuery, Description: "Resume after this id, for clients that cannot send Last-Event-ID", Schema: countSchema}},
+		}
+		op.Responses = openapi3.NewResponses()
+		{
+			desc := "SSE stream of AG-UI events"
+			resp := &openapi3.Response{Description: &desc, Content: openapi3.Content{"text/event-stream": {Schema: openapi3.NewSchemaRef("", aguiEventSchema)}}}
+			op.Responses.Set("200", &openapi3.ResponseRef{Value: resp})
+		}
+		op.Responses.Set("503", &openapi3.ResponseRef{Value: openapi3.NewResponse().WithDescription("Event streaming not available").WithContent(openapi3.NewContentWithJSONSchemaRef(errorSchema))})
+		return op
+	}())
+
 	// /api/v1/plugins
 	pluginListSchema := openapi3.NewSchemaRef("", func() *openapi3.Schema {
 		s := openapi3.NewObjectSchema()
@@ -315,25 +493,23 @@ func BuildOpenAPISpec(baseURL string) (*openapi3.T, error) {
 	spec.AddOperation("/api/v1/mcp", http.MethodPost, func() *openapi3.Operation {
 		op := openapi3.NewOperation()
 		op.Summary = "Model Context Protocol endpoint"
-		op.OperationID = "postMCPCommand"
+		op.Description = "Streamable HTTP transport for one JSON-RPC 2.0 message per request. Supports initialize, ping, tools/list and tools/call; tools are the operations of this API and the actions of enabled plugins. Calls that report progress answer with an SSE stream when the client accepts text/event-stream."
+		op.OperationID = "postMCPMessage"
 		op.Tags = []string{"mcp"}
 		op.RequestBody = &openapi3.RequestBodyRef{Value: &openapi3.RequestBody{Required: true, Content: openapi3.NewContentWithJSONSchemaRef(mcpReqRef)}}
 		op.Responses = openapi3.NewResponses()
 		{
-			resp := openapi3.NewResponse().WithDescription("MCP response")
+			resp := openapi3.NewResponse().WithDescription("JSON-RPC response")
 			resp.Content = openapi3.NewContentWithJSONSchemaRef(mcpRespRef)
+			resp.Content["text/event-stream"] = openapi3.NewMediaType().WithSchema(openapi3.NewStringSchema())
 			op.Responses.Set("200", &openapi3.ResponseRef{Value: resp})
 		}
+		op.Responses.Set("202", &openapi3.ResponseRef{Value: openapi3.NewResponse().WithDescription("Notification accepted")})
 		{
-			resp := openapi3.NewResponse().WithDescription("Bad request")
-			resp.Content = openapi3.NewContentWithJSONSchemaRef(errorSchema)
+			resp := openapi3.NewResponse().WithDescription("Malformed JSON-RPC message")
+			resp.Content = openapi3.NewContentWithJSONSchemaRef(mcpRespRef)
 			op.Responses.Set("400", &openapi3.ResponseRef{Value: resp})
 		}
-		{
-			resp := openapi3.NewResponse().WithDescription("Internal error")
-			resp.Content = openapi3.NewContentWithJSONSchemaRef(errorSchema)
-			op.Responses.Set("500", &openapi3.ResponseRef{Value: resp})
-		}
 		return op
 	}())
 
@@ -415,7 +591,49 @@ func BuildOpenAPISpec(baseURL string) (*openapi3.T, error) {
 		return op
 	}())
 
+	duplicateReqRef, _ := gen.NewSchemaRefForValue(&duplicateVMRequest{}, spec.Components.Schemas)
+	spec.AddOperation("/api/v1/vms/{name}/duplicate", http.MethodPost, func() *openapi3.Operation {
+		op := openapi3.NewOperation()
+		op.Summary = "Duplicate a VM"
+		op.Description = "Creates a new VM from the source's config and disk state with a fresh IP, MAC, vsock CID and hostname. Port mappings and statically addressed extra NICs are not copied. A running source is paused while its disks are copied, as reflinks where the filesystem supports them. Sources with passthrough devices answer 409 VM_NOT_DUPLICABLE."
+		op.OperationID = "duplicateVM"
+		op.Tags = []string{"vm"}
+		op.Parameters = openapi3.Parameters{nameParam}
+		op.RequestBody = &openapi3.RequestBodyRef{Value: &openapi3.RequestBody{Required: true, Content: openapi3.NewContentWithJSONSchemaRef(duplicateReqRef)}}
+		op.Responses = openapi3.NewResponses()
+		{
+			resp := openapi3.NewResponse().WithDescription("Duplicate created")
+			resp.Content = openapi3.NewContentWithJSONSchemaRef(vmRespRef)
+			op.Responses.Set("201", &openapi3.ResponseRef{Value: resp})
+		}
+		op.Responses.Set("404", &openapi3.ResponseRef{Value: openapi3.NewResponse().WithDescription("Source VM not found").WithContent(openapi3.NewContentWithJSONSchemaRef(errorSchema))})
+		op.Responses.Set("409", &openapi3.ResponseRef{Value: openapi3.NewResponse().WithDescription("A VM with the new name exists, or the source cannot be duplicated").WithContent(openapi3.NewContentWithJSONSchemaRef(errorSchema))})
+		return op
+	}())
+
+	cloneReqRef, _ := gen.NewSchemaRefForValue(&cloneVMRequest{}, spec.Components.Schemas)
+	spec.AddOperation("/api/v1/vms/{name}/clone", http.MethodPost, func() *openapi3.Operation {
+		op := openapi3.NewOperation()
+		op.Summary = "Clone a VM's config"
+		op.Description = "Creates a new VM from the source's config with a fresh IP, MAC, vsock CID and hostname; it boots a fresh root filesystem from its manifest. With scratch set, the source's persistent scratch disk is copied too, as a reflink where the filesystem supports it; a running source is paused while it is copied. Port mappings and statically addressed extra NICs are not copied. Sources with passthrough devices or writable data disks answer 409 VM_NOT_DUPLICABLE; use duplicate to copy data disks along."
+		op.OperationID = "cloneVM"
+		op.Tags = []string{"vm"}
+		op.Parameters = openapi3.Parameters{nameParam}
+		op.RequestBody = &openapi3.RequestBodyRef{Value: &openapi3.RequestBody{Required: true, Content: openapi3.NewContentWithJSONSchemaRef(cloneReqRef)}}
+		op.Responses = openapi3.NewResponses()
+		{
+			resp := openapi3.NewResponse().WithDescription("Clone created")
+			resp.Content = openapi3.NewContentWithJSONSchemaRef(vmRespRef)
+			op.Responses.Set("201", &openapi3.ResponseRef{Value: resp})
+		}
+		op.Responses.Set("404", &openapi3.ResponseRef{Value: openapi3.NewResponse().WithDescription("Source VM not found").WithContent(openapi3.NewContentWithJSONSchemaRef(errorSchema))})
+		op.Responses.Set("409", &openapi3.ResponseRef{Value: openapi3.NewResponse().WithDescription("A VM with the new name exists, or the source cannot be cloned").WithContent(openapi3.NewContentWithJSONSchemaRef(errorSchema))})
+		return op
+	}())
+
 	// /api/v1/vms/{name}/config
+	vmConfigRef, _ := gen.NewSchemaRefForValue(&vmconfig.Versioned{}, spec.Components.Schemas)
+	vmConfigPatchRef, _ := gen.NewSchemaRefForValue(&vmconfig.Patch{}, spec.Components.Schemas)
 	spec.AddOperation("/api/v1/vms/{name}/config", http.MethodGet, func() *openapi3.Operation {
 		op := openapi3.NewOperation()
 		op.Summary = "Get VM configuration"
@@ -425,8 +643,7 @@ func BuildOpenAPISpec(baseURL string) (*openapi3.T, error) {
 		op.Responses = openapi3.NewResponses()
 		{
 			resp := openapi3.NewResponse().WithDescription("VM configuration")
-			schema := openapi3.NewObjectSchema()
-			resp.Content = openapi3.NewContentWithJSONSchema(schema)
+			resp.Content = openapi3.NewContentWithJSONSchemaRef(vmConfigRef)
 			op.Responses.Set("200", &openapi3.ResponseRef{Value: resp})
 		}
 		{
@@ -442,15 +659,24 @@ func BuildOpenAPISpec(baseURL string) (*openapi3.T, error) {
 		op.Summary = "Update VM configuration"
 		op.OperationID = "updateVMConfig"
 		op.Tags = []string{"vm", "config"}
-		op.Parameters = openapi3.Parameters{nameParam}
-		schema := openapi3.NewObjectSchema()
-		op.RequestBody = &openapi3.RequestBodyRef{Value: &openapi3.RequestBody{Required: true, Content: openapi3.NewContentWithJSONSchema(schema)}}
+		op.Parameters = openapi3.Parameters{
+			nameParam,
+			&openapi3.ParameterRef{Value: openapi3.NewQueryParameter("restart").WithSchema(openapi3.NewBoolSchema()).WithDescription("Restart the VM onto the new configuration")},
+			&openapi3.ParameterRef{Value: openapi3.NewQueryParameter("rollback").WithSchema(openapi3.NewBoolSchema()).WithDescription("Snapshot before restarting and roll back if the VM is not healthy in time; implies restart")},
+			&openapi3.ParameterRef{Value: openapi3.NewQueryParameter("health_window").WithSchema(openapi3.NewStringSchema()).WithDescription("Duration the restarted VM has to become healthy, e.g. 90s")},
+		}
+		op.RequestBody = &openapi3.RequestBodyRef{Value: &openapi3.RequestBody{Required: true, Content: openapi3.NewContentWithJSONSchemaRef(vmConfigPatchRef)}}
 		op.Responses = openapi3.NewResponses()
 		{
 			resp := openapi3.NewResponse().WithDescription("Configuration updated")
-			resp.Content = openapi3.NewContentWithJSONSchema(schema)
+			resp.Content = openapi3.NewContentWithJSONSchemaRef(vmConfigRef)
 			op.Responses.Set("200", &openapi3.ResponseRef{Value: resp})
 		}
+		{
+			resp := openapi3.NewResponse().WithDescription("Configuration stored; restart and health check in progress")
+			resp.Content = openapi3.NewContentWithJSONSchemaRef(vmConfigRef)
+			op.Responses.Set("202", &openapi3.ResponseRef{Value: resp})
+		}
 		{
 			resp := openapi3.NewResponse().WithDescription("Bad request")
 			resp.Content = openapi3.NewContentWithJSONSchemaRef(errorSchema)
@@ -458,6 +684,22 @@ func BuildOpenAPISpec(baseURL string) (*openapi3.T, error) {
 		}
 		return op
 	}())
+	spec.AddOperation("/api/v1/vms/{name}/labels", http.MethodPatch, func() *openapi3.Operation {
+		op := openapi3.NewOperation()
+		op.Summary = "Update VM labels"
+		op.Description = "JSON merge patch of the VM's labels: string values set keys, null removes them."
+		op.OperationID = "updateVMLabels"
+		op.Tags = []string{"vm"}
+		op.Parameters = openapi3.Parameters{nameParam}
+		schema := openapi3.NewObjectSchema()
+		schema.AdditionalProperties = openapi3.AdditionalProperties{Schema: openapi3.NewSchemaRef("", openapi3.NewStringSchema().WithNullable())}
+		op.RequestBody = &openapi3.RequestBodyRef{Value: &openapi3.RequestBody{Required: true, Content: openapi3.NewContentWithJSONSchema(schema)}}
+		op.Responses = openapi3.NewResponses()
+		op.Responses.Set("200", &openapi3.ResponseRef{Value: openapi3.NewResponse().WithDescription("VM").WithContent(openapi3.NewContentWithJSONSchemaRef(vmRespRef))})
+		op.Responses.Set("400", &openapi3.ResponseRef{Value: openapi3.NewResponse().WithDescription("Invalid label").WithContent(openapi3.NewContentWithJSONSchemaRef(errorSchema))})
+		op.Responses.Set("404", &openapi3.ResponseRef{Value: openapi3.NewResponse().WithDescription("Not found").WithContent(openapi3.NewContentWithJSONSchemaRef(errorSchema))})
+		return op
+	}())
 
 	// /api/v1/vms/{name}/config/history
 	spec.AddOperation("/api/v1/vms/{name}/config/history", http.MethodGet, func() *openapi3.Operation {
@@ -465,11 +707,187 @@ func BuildOpenAPISpec(baseURL string) (*openapi3.T, error) {
 		op.Summary = "Get VM configuration history"
 		op.OperationID = "getVMConfigHistory"
 		op.Tags = []string{"vm", "config"}
-		op.Parameters = openapi3.Parameters{nameParam}
+		op.Parameters = openapi3.Parameters{nameParam, limitParam}
 		op.Responses = openapi3.NewResponses()
 		{
 			resp := openapi3.NewResponse().WithDescription("Configuration history")
-			schema := &openapi3.Schema{Type: &openapi3.Types{openapi3.TypeArray}, Items: openapi3.NewSchemaRef("", openapi3.NewObjectSchema())}
+			historyRef, _ := gen.NewSchemaRefForValue(&[]vmconfig.HistoryEntry{}, spec.Components.Schemas)
+			resp.Content = openapi3.NewContentWithJSONSchemaRef(historyRef)
+			op.Responses.Set("200", &openapi3.ResponseRef{Value: resp})
+		}
+		return op
+	}())
+
+	// /api/v1/vms/{name}/events
+	spec.AddOperation("/api/v1/vms/{name}/events", http.MethodGet, func() *openapi3.Operation {
+		op := openapi3.NewOperation()
+		op.Summary = "VM event history"
+		op.Description = "Recorded lifecycle events of a VM (created, running, stopped, crashed, failed, config updated, expired, deleted), kept for seven days and also after the VM is deleted. Log lines are served by the VM log endpoints instead."
+		op.OperationID = "getVMEvents"
+		op.Tags = []string{"vm"}
+		op.Parameters = openapi3.Parameters{
+			nameParam,
+			&openapi3.ParameterRef{Value: &openapi3.Parameter{Name: "since", In: openapi3.ParameterInQuery, Description: "RFC 3339 time or a duration back from now, such as 1h", Schema: openapi3.NewSchemaRef("", openapi3.NewStringSchema())}},
+			&openapi3.ParameterRef{Value: &openapi3.Parameter{Name: "type", In: openapi3.ParameterInQuery, Description: "Event types to return, such as VM_CRASHED (comma-separated or repeated; default all)", Schema: openapi3.NewSchemaRef("", openapi3.NewStringSchema())}},
+			&openapi3.ParameterRef{Value: &openapi3.Parameter{Name: "limit", In: openapi3.ParameterInQuery, Description: "Keep the last N matching events (default 100)", Schema: countSchema}},
+		}
+		op.Responses = openapi3.NewResponses()
+		{
+			eventRef, _ := gen.NewSchemaRefForValue(&vmEventResponse{}, spec.Components.Schemas)
+			arr := &openapi3.Schema{Type: &openapi3.Types{openapi3.TypeArray}, Items: eventRef}
+			op.Responses.Set("200", &openapi3.ResponseRef{Value: openapi3.NewResponse().WithDescription("Events, oldest first").WithContent(openapi3.NewContentWithJSONSchema(arr))})
+		}
+		op.Responses.Set("400", &openapi3.ResponseRef{Value: openapi3.NewResponse().WithDescription("Invalid since").WithContent(openapi3.NewContentWithJSONSchemaRef(errorSchema))})
+		op.Responses.Set("404", &openapi3.ResponseRef{Value: openapi3.NewResponse().WithDescription("No such VM and no recorded events").WithContent(openapi3.NewContentWithJSONSchemaRef(errorSchema))})
+		return op
+	}())
+
+	// /api/v1/vms/{name}/sysinfo
+	spec.AddOperation("/api/v1/vms/{name}/sysinfo", http.MethodGet, func() *openapi3.Operation {
+		op := openapi3.NewOperation()
+		op.Summary = "Get guest system information"
+		op.Description = "Kernel, mounted disks, network interfaces, loaded modules and agent build info as reported by the in-guest agent."
+		op.OperationID = "getVMSysInfo"
+		op.Tags = []string{"vm"}
+		op.Parameters = openapi3.Parameters{nameParam}
+		op.Responses = openapi3.NewResponses()
+		{
+			resp := openapi3.NewResponse().WithDescription("Guest system information")
+			resp.Content = openapi3.NewContentWithJSONSchema(openapi3.NewObjectSchema())
+			op.Responses.Set("200", &openapi3.ResponseRef{Value: resp})
+		}
+		op.Responses.Set("409", &openapi3.ResponseRef{Value: openapi3.NewResponse().WithDescription("VM not running")})
+		op.Responses.Set("502", &openapi3.ResponseRef{Value: openapi3.NewResponse().WithDescription("Agent unreachable")})
+		return op
+	}())
+
+	// /api/v1/vms/{name}/guest-metrics
+	spec.AddOperation("/api/v1/vms/{name}/guest-metrics", http.MethodGet, func() *openapi3.Operation {
+		op := openapi3.NewOperation()
+		op.Summary = "Sample guest resource usage"
+		op.Description = "CPU, memory, disk and process usage as seen inside the guest, sampled by the agent, plus the stats of the plugin's metrics endpoint when the manifest declares one. CPU percent covers the time since the previous sample."
+		op.OperationID = "getVMGuestMetrics"
+		op.Tags = []string{"vm"}
+		op.Parameters = openapi3.Parameters{nameParam}
+		op.Responses = openapi3.NewResponses()
+		{
+			metrics, _ := gen.NewSchemaRefForValue(&pluginspec.GuestMetrics{}, spec.Components.Schemas)
+			op.Responses.Set("200", &openapi3.ResponseRef{Value: openapi3.NewResponse().WithDescription("Guest metrics sample").WithContent(openapi3.NewContentWithJSONSchemaRef(metrics))})
+		}
+		op.Responses.Set("409", &openapi3.ResponseRef{Value: openapi3.NewResponse().WithDescription("VM not running")})
+		op.Responses.Set("502", &openapi3.ResponseRef{Value: openapi3.NewResponse().WithDescription("Agent unreachable")})
+		return op
+	}())
+
+	// /api/v1/vms/{name}/files/{path}
+	filePathParam := &openapi3.ParameterRef{Value: &openapi3.Parameter{Name: "path", In: openapi3.ParameterInPath, Required: true, Description: "Absolute guest path; may contain slashes", Schema: openapi3.NewSchemaRef("", openapi3.NewStringSchema()),
+		Extensions: map[string]any{"x-slashes": true}}}
+	checksumParam := &openapi3.ParameterRef{Value: &openapi3.Parameter{Name: checksumHeader, In: openapi3.ParameterInHeader, Description: "Hex SHA-256 of the file; the upload is rejected with CHECKSUM_MISMATCH unless it matches", Schema: openapi3.NewSchemaRef("", openapi3.NewStringSchema().WithPattern("^[0-9a-fA-F]{64}$"))}}
+	fileModeParam := &openapi3.ParameterRef{Value: &openapi3.Parameter{Name: fileModeHeader, In: openapi3.ParameterInHeader, Description: "Octal permission bits of the written file (default 0644)", Schema: openapi3.NewSchemaRef("", openapi3.NewStringSchema().WithPattern("^[0-7]{1,4}$"))}}
+	binarySchema := openapi3.NewStringSchema().WithFormat("binary")
+	spec.AddOperation("/api/v1/vms/{name}/files/{path}", http.MethodPut, func() *openapi3.Operation {
+		op := openapi3.NewOperation()
+		op.Summary = "Upload a file to the guest"
+		op.Description = "Streams the body to the guest path through the agent, creating parent directories. The file replaces any existing one only once fully written. Files are limited to VOLANT_FILE_MAX_SIZE_MB (default 1024)."
+		op.OperationID = "putVMFile"
+		op.Tags = []string{"vm"}
+		op.Parameters = openapi3.Parameters{nameParam, filePathParam, checksumParam, fileModeParam}
+		op.RequestBody = &openapi3.RequestBodyRef{Value: &openapi3.RequestBody{Required: true, Content: openapi3.Content{"application/octet-stream": {Schema: openapi3.NewSchemaRef("", binarySchema)}}}}
+		op.Responses = openapi3.NewResponses()
+		{
+			written, _ := gen.NewSchemaRefForValue(&fileTransferResponse{}, spec.Components.Schemas)
+			op.Responses.Set("200", &openapi3.ResponseRef{Value: openapi3.NewResponse().WithDescription("File replaced").WithContent(openapi3.NewContentWithJSONSchemaRef(written))})
+			op.Responses.Set("201", &openapi3.ResponseRef{Value: openapi3.NewResponse().WithDescription("File created").WithContent(openapi3.NewContentWithJSONSchemaRef(written))})
+		}
+		op.Responses.Set("409", &openapi3.ResponseRef{Value: openapi3.NewResponse().WithDescription("VM not running, or the path is a directory").WithContent(openapi3.NewContentWithJSONSchemaRef(errorSchema))})
+		op.Responses.Set("413", &openapi3.ResponseRef{Value: openapi3.NewResponse().WithDescription("File too large").WithContent(openapi3.NewContentWithJSONSchemaRef(errorSchema))})
+		op.Responses.Set("422", &openapi3.ResponseRef{Value: openapi3.NewResponse().WithDescription("Checksum mismatch").WithContent(openapi3.NewContentWithJSONSchemaRef(errorSchema))})
+		return op
+	}())
+	spec.AddOperation("/api/v1/vms/{name}/files/{path}", http.MethodGet, func() *openapi3.Operation {
+		op := openapi3.NewOperation()
+		op.Summary = "Download a file from the guest"
+		op.Description = "Streams a regular file from the guest. X-Volant-Checksum-Sha256 carries the SHA-256 of the whole file and X-Volant-File-Mode its permission bits. Range requests are supported."
+		op.OperationID = "getVMFile"
+		op.Tags = []string{"vm"}
+		op.Parameters = openapi3.Parameters{nameParam, filePathParam}
+		op.Responses = openapi3.NewResponses()
+		for _, status := range []string{"200", "206"} {
+			desc := "File contents"
+			if status == "206" {
+				desc = "Requested range of the file"
+			}
+			resp := &openapi3.Response{Description: &desc, Content: openapi3.Content{"application/octet-stream": {Schema: openapi3.NewSchemaRef("", binarySchema)}}}
+			op.Responses.Set(status, &openapi3.ResponseRef{Value: resp})
+		}
+		op.Responses.Set("404", &openapi3.ResponseRef{Value: openapi3.NewResponse().WithDescription("VM or file not found").WithContent(openapi3.NewContentWithJSONSchemaRef(errorSchema))})
+		op.Responses.Set("413", &openapi3.ResponseRef{Value: openapi3.NewResponse().WithDescription("File too large").WithContent(openapi3.NewContentWithJSONSchemaRef(errorSchema))})
+		return op
+	}())
+
+	// /api/v1/vms/{name}/exec
+	spec.AddOperation("/api/v1/vms/{name}/exec", http.MethodPost, func() *openapi3.Operation {
+		op := openapi3.NewOperation()
+		op.Summary = "Run a command in the guest"
+		op.Description = "Runs a command through the agent and returns its exit code and up to 1 MiB each of stdout and stderr. The plugin manifest's exec allowlist decides which binaries may run. For interactive stdin and streamed output use the /ws/v1/vms/{name}/exec WebSocket."
+		op.OperationID = "postVMExec"
+		op.Tags = []string{"vm"}
+		op.Parameters = openapi3.Parameters{nameParam}
+		execReq, _ := gen.NewSchemaRefForValue(&pluginspec.ExecRequest{}, spec.Components.Schemas)
+		op.RequestBody = &openapi3.RequestBodyRef{Value: &openapi3.RequestBody{Required: true, Content: openapi3.NewContentWithJSONSchemaRef(execReq)}}
+		op.Responses = openapi3.NewResponses()
+		{
+			result, _ := gen.NewSchemaRefForValue(&pluginspec.ExecResult{}, spec.Components.Schemas)
+			op.Responses.Set("200", &openapi3.ResponseRef{Value: openapi3.NewResponse().WithDescription("Command finished, timed out or was killed").WithContent(openapi3.NewContentWithJSONSchemaRef(result))})
+		}
+		op.Responses.Set("403", &openapi3.ResponseRef{Value: openapi3.NewResponse().WithDescription("Binary not in the manifest's exec allowlist").WithContent(openapi3.NewContentWithJSONSchemaRef(errorSchema))})
+		op.Responses.Set("404", &openapi3.ResponseRef{Value: openapi3.NewResponse().WithDescription("VM or binary not found").WithContent(openapi3.NewContentWithJSONSchemaRef(errorSchema))})
+		op.Responses.Set("409", &openapi3.ResponseRef{Value: openapi3.NewResponse().WithDescription("VM not running").WithContent(openapi3.NewContentWithJSONSchemaRef(errorSchema))})
+		return op
+	}())
+
+	// /api/v1/vms/{name}/ssh
+	sshAccessRef, _ := gen.NewSchemaRefForValue(&orchestrator.SSHAccess{}, spec.Components.Schemas)
+	spec.AddOperation("/api/v1/vms/{name}/ssh", http.MethodGet, func() *openapi3.Operation {
+		op := openapi3.NewOperation()
+		op.Summary = "Describe SSH access to a VM"
+		op.Description = "Returns the login user, address and authorized keys of the VM. Sessions connect directly or through /ws/v1/vms/{name}/ssh, which relays binary frames to the VM's SSH port."
+		op.OperationID = "getVMSSH"
+		op.Tags = []string{"vm"}
+		op.Parameters = openapi3.Parameters{nameParam}
+		op.Responses = openapi3.NewResponses()
+		op.Responses.Set("200", &openapi3.ResponseRef{Value: openapi3.NewResponse().WithDescription("SSH access").WithContent(openapi3.NewContentWithJSONSchemaRef(sshAccessRef))})
+		op.Responses.Set("404", &openapi3.ResponseRef{Value: openapi3.NewResponse().WithDescription("VM not found").WithContent(openapi3.NewContentWithJSONSchemaRef(errorSchema))})
+		return op
+	}())
+
+	// /api/v1/vms/{name}/ssh/key
+	sshKeyRef, _ := gen.NewSchemaRefForValue(&sshKeyResponse{}, spec.Components.Schemas)
+	spec.AddOperation("/api/v1/vms/{name}/ssh/key", http.MethodGet, func() *openapi3.Operation {
+		op := openapi3.NewOperation()
+		op.Summary = "Fetch the SSH key generated for a VM"
+		op.Description = "Returns the OpenSSH private key volantd generated for a VM with ssh.generate_key. Each read is audited as secret.access."
+		op.OperationID = "getVMSSHKey"
+		op.Tags = []string{"vm", "secrets"}
+		op.Parameters = openapi3.Parameters{nameParam}
+		op.Responses = openapi3.NewResponses()
+		op.Responses.Set("200", &openapi3.ResponseRef{Value: openapi3.NewResponse().WithDescription("Private key").WithContent(openapi3.NewContentWithJSONSchemaRef(sshKeyRef))})
+		op.Responses.Set("404", &openapi3.ResponseRef{Value: openapi3.NewResponse().WithDescription("VM not found or no generated key").WithContent(openapi3.NewContentWithJSONSchemaRef(errorSchema))})
+		return op
+	}())
+
+	// /api/v1/vms/{name}/ports
+	portBindingRef, _ := gen.NewSchemaRefForValue(&orchestrator.PortBinding{}, spec.Components.Schemas)
+	spec.AddOperation("/api/v1/vms/{name}/ports", http.MethodGet, func() *openapi3.Operation {
+		op := openapi3.NewOperation()
+		op.Summary = "List VM port mappings"
+		op.OperationID = "getVMPorts"
+		op.Tags = []string{"vm", "network"}
+		op.Parameters = openapi3.Parameters{nameParam}
+		op.Responses = openapi3.NewResponses()
+		{
+			resp := openapi3.NewResponse().WithDescription("Declared port mappings and whether host rules are active")
+			schema := &openapi3.Schema{Type: &openapi3.Types{openapi3.TypeArray}, Items: portBindingRef}
 			resp.Content = openapi3.NewContentWithJSONSchema(schema)
 			op.Responses.Set("200", &openapi3.ResponseRef{Value: resp})
 		}
@@ -517,6 +935,7 @@ func BuildOpenAPISpec(baseURL string) (*openapi3.T, error) {
 		op.Summary = "Create deployment"
 		op.OperationID = "createDeployment"
 		op.Tags = []string{"deployment"}
+		op.Parameters = openapi3.Parameters{idempotencyKeyParam}
 		op.RequestBody = &openapi3.RequestBodyRef{Value: &openapi3.RequestBody{Required: true, Content: openapi3.NewContentWithJSONSchemaRef(deploymentReqRef)}}
 		op.Responses = openapi3.NewResponses()
 		{
@@ -550,8 +969,9 @@ func BuildOpenAPISpec(baseURL string) (*openapi3.T, error) {
 		op.Parameters = openapi3.Parameters{nameParam}
 		patchSchema := openapi3.NewObjectSchema()
 		patchSchema.Properties = map[string]*openapi3.SchemaRef{
-			"replicas": openapi3.NewSchemaRef("", openapi3.NewIntegerSchema()),
+			"replicas": countSchema,
 		}
+		patchSchema.Required = []string{"replicas"}
 		op.RequestBody = &openapi3.RequestBodyRef{Value: &openapi3.RequestBody{Required: true, Content: openapi3.NewContentWithJSONSchema(patchSchema)}}
 		op.Responses = openapi3.NewResponses()
 		{
@@ -562,6 +982,25 @@ func BuildOpenAPISpec(baseURL string) (*openapi3.T, error) {
 		return op
 	}())
 
+	deploymentConfigReqRef, _ := gen.NewSchemaRefForValue(&updateDeploymentConfigRequest{}, spec.Components.Schemas)
+	spec.AddOperation("/api/v1/deployments/{name}/config", http.MethodPut, func() *openapi3.Operation {
+		op := openapi3.NewOperation()
+		op.Summary = "Roll out a new deployment config"
+		op.Description = "Stores the config and replaces replicas in the background (defaults: max_surge=1, max_unavailable=0)."
+		op.OperationID = "updateDeploymentConfig"
+		op.Tags = []string{"deployment"}
+		op.Parameters = openapi3.Parameters{nameParam}
+		op.RequestBody = &openapi3.RequestBodyRef{Value: &openapi3.RequestBody{Required: true, Content: openapi3.NewContentWithJSONSchemaRef(deploymentConfigReqRef)}}
+		op.Responses = openapi3.NewResponses()
+		{
+			resp := openapi3.NewResponse().WithDescription("Rolling update started")
+			resp.Content = openapi3.NewContentWithJSONSchemaRef(deploymentRespRef)
+			op.Responses.Set("202", &openapi3.ResponseRef{Value: resp})
+		}
+		op.Responses.Set("409", &openapi3.ResponseRef{Value: openapi3.NewResponse().WithDescription("Rollout already in progress").WithContent(openapi3.NewContentWithJSONSchemaRef(errorSchema))})
+		return op
+	}())
+
 	spec.AddOperation("/api/v1/deployments/{name}", http.MethodDelete, func() *openapi3.Operation {
 		op := openapi3.NewOperation()
 		op.Summary = "Delete deployment"
@@ -573,96 +1012,1093 @@ func BuildOpenAPISpec(baseURL string) (*openapi3.T, error) {
 		return op
 	}())
 
-	// /api/v1/plugins
-	manifestSchema := openapi3.NewObjectSchema()
-	manifestSchema.Description = "Plugin manifest (see plugin-manifest-v1.json schema)"
-	spec.AddOperation("/api/v1/plugins", http.MethodGet, func() *openapi3.Operation {
+	// /api/v1/schedules
+	scheduleReqRef, _ := gen.NewSchemaRefForValue(&createScheduleRequest{}, spec.Components.Schemas)
+	scheduleRespRef, _ := gen.NewSchemaRefForValue(&scheduleResponse{}, spec.Components.Schemas)
+	scheduleRunRespRef, _ := gen.NewSchemaRefForValue(&scheduleRunResponse{}, spec.Components.Schemas)
+	spec.AddOperation("/api/v1/schedules", http.MethodGet, func() *openapi3.Operation {
 		op := openapi3.NewOperation()
-		op.Summary = "List plugins"
-		op.OperationID = "listPlugins"
-		op.Tags = []string{"plugins"}
+		op.Summary = "List schedules"
+		op.OperationID = "listSchedules"
+		op.Tags = []string{"schedules"}
 		op.Responses = openapi3.NewResponses()
 		{
-			resp := openapi3.NewResponse().WithDescription("List of plugin names")
-			listSchema := openapi3.NewObjectSchema()
-			listSchema.Properties = map[string]*openapi3.SchemaRef{
-				"plugins": openapi3.NewSchemaRef("", &openapi3.Schema{Type: &openapi3.Types{openapi3.TypeArray}, Items: openapi3.NewSchemaRef("", openapi3.NewStringSchema())}),
-			}
-			resp.Content = openapi3.NewContentWithJSONSchema(listSchema)
+			resp := openapi3.NewResponse().WithDescription("Array of schedules")
+			arr := &openapi3.Schema{Type: &openapi3.Types{openapi3.TypeArray}, Items: scheduleRespRef}
+			resp.Content = openapi3.NewContentWithJSONSchema(arr)
 			op.Responses.Set("200", &openapi3.ResponseRef{Value: resp})
 		}
 		return op
 	}())
 
-	spec.AddOperation("/api/v1/plugins", http.MethodPost, func() *openapi3.Operation {
+	spec.AddOperation("/api/v1/schedules", http.MethodPost, func() *openapi3.Operation {
 		op := openapi3.NewOperation()
-		op.Summary = "Install plugin"
-		op.OperationID = "installPlugin"
-		op.Tags = []string{"plugins"}
-		op.RequestBody = &openapi3.RequestBodyRef{Value: &openapi3.RequestBody{Required: true, Content: openapi3.NewContentWithJSONSchema(manifestSchema)}}
+		op.Summary = "Create schedule"
+		op.Description = "Cron-style rule applying start, stop, restart or snapshot to a vm, deployment or plugin target, or scale to a deployment."
+		op.OperationID = "createSchedule"
+		op.Tags = []string{"schedules"}
+		op.RequestBody = &openapi3.RequestBodyRef{Value: &openapi3.RequestBody{Required: true, Content: openapi3.NewContentWithJSONSchemaRef(scheduleReqRef)}}
 		op.Responses = openapi3.NewResponses()
-		op.Responses.Set("201", &openapi3.ResponseRef{Value: openapi3.NewResponse().WithDescription("Plugin installed")})
-		op.Responses.Set("400", &openapi3.ResponseRef{Value: openapi3.NewResponse().WithDescription("Bad request").WithContent(openapi3.NewContentWithJSONSchemaRef(errorSchema))})
+		{
+			resp := openapi3.NewResponse().WithDescription("Schedule created")
+			resp.Content = openapi3.NewContentWithJSONSchemaRef(scheduleRespRef)
+			op.Responses.Set("201", &openapi3.ResponseRef{Value: resp})
+		}
+		op.Responses.Set("400", &openapi3.ResponseRef{Value: openapi3.NewResponse().WithDescription("Invalid schedule").WithContent(openapi3.NewContentWithJSONSchemaRef(errorSchema))})
+		op.Responses.Set("409", &openapi3.ResponseRef{Value: openapi3.NewResponse().WithDescription("Schedule already exists").WithContent(openapi3.NewContentWithJSONSchemaRef(errorSchema))})
 		return op
 	}())
 
-	pluginParam := &openapi3.ParameterRef{Value: &openapi3.Parameter{Name: "plugin", In: openapi3.ParameterInPath, Required: true, Schema: openapi3.NewSchemaRef("", openapi3.NewStringSchema())}}
-	spec.AddOperation("/api/v1/plugins/{plugin}", http.MethodGet, func() *openapi3.Operation {
+	spec.AddOperation("/api/v1/schedules/{name}", http.MethodGet, func() *openapi3.Operation {
 		op := openapi3.NewOperation()
-		op.Summary = "Get plugin"
-		op.OperationID = "getPlugin"
-		op.Tags = []string{"plugins"}
-		op.Parameters = openapi3.Parameters{pluginParam}
+		op.Summary = "Get schedule"
+		op.OperationID = "getSchedule"
+		op.Tags = []string{"schedules"}
+		op.Parameters = openapi3.Parameters{nameParam}
 		op.Responses = openapi3.NewResponses()
 		{
-			resp := openapi3.NewResponse().WithDescription("Plugin manifest")
-			resp.Content = openapi3.NewContentWithJSONSchema(manifestSchema)
+			resp := openapi3.NewResponse().WithDescription("Schedule")
+			resp.Content = openapi3.NewContentWithJSONSchemaRef(scheduleRespRef)
 			op.Responses.Set("200", &openapi3.ResponseRef{Value: resp})
 		}
 		return op
 	}())
 
-	spec.AddOperation("/api/v1/plugins/{plugin}", http.MethodDelete, func() *openapi3.Operation {
+	spec.AddOperation("/api/v1/schedules/{name}", http.MethodDelete, func() *openapi3.Operation {
 		op := openapi3.NewOperation()
-		op.Summary = "Remove plugin"
-		op.OperationID = "removePlugin"
-		op.Tags = []string{"plugins"}
-		op.Parameters = openapi3.Parameters{pluginParam}
+		op.Summary = "Delete schedule"
+		op.OperationID = "deleteSchedule"
+		op.Tags = []string{"schedules"}
+		op.Parameters = openapi3.Parameters{nameParam}
 		op.Responses = openapi3.NewResponses()
-		op.Responses.Set("204", &openapi3.ResponseRef{Value: openapi3.NewResponse().WithDescription("Plugin removed")})
+		op.Responses.Set("204", &openapi3.ResponseRef{Value: openapi3.NewResponse().WithDescription("Deleted")})
 		return op
 	}())
 
-	spec.AddOperation("/api/v1/plugins/{plugin}/enabled", http.MethodPost, func() *openapi3.Operation {
+	spec.AddOperation("/api/v1/schedules/{name}/enabled", http.MethodPost, func() *openapi3.Operation {
 		op := openapi3.NewOperation()
-		op.Summary = "Set plugin enabled status"
-		op.OperationID = "setPluginEnabled"
-		op.Tags = []string{"plugins"}
-		op.Parameters = openapi3.Parameters{pluginParam}
-		enabledSchema := openapi3.NewObjectSchema()
-		enabledSchema.Properties = map[string]*openapi3.SchemaRef{
-			"enabled": openapi3.NewSchemaRef("", openapi3.NewBoolSchema()),
-		}
-		op.RequestBody = &openapi3.RequestBodyRef{Value: &openapi3.RequestBody{Required: true, Content: openapi3.NewContentWithJSONSchema(enabledSchema)}}
+		op.Summary = "Enable or disable schedule"
+		op.OperationID = "setScheduleEnabled"
+		op.Tags = []string{"schedules"}
+		op.Parameters = openapi3.Parameters{nameParam}
+		body := openapi3.NewObjectSchema()
+		body.Properties = map[string]*openapi3.SchemaRef{"enabled": openapi3.NewSchemaRef("", openapi3.NewBoolSchema())}
+		op.RequestBody = &openapi3.RequestBodyRef{Value: &openapi3.RequestBody{Required: true, Content: openapi3.NewContentWithJSONSchema(body)}}
 		op.Responses = openapi3.NewResponses()
-		op.Responses.Set("200", &openapi3.ResponseRef{Value: openapi3.NewResponse().WithDescription("Status updated")})
+		{
+			resp := openapi3.NewResponse().WithDescription("Schedule")
+			resp.Content = openapi3.NewContentWithJSONSchemaRef(scheduleRespRef)
+			op.Responses.Set("200", &openapi3.ResponseRef{Value: resp})
+		}
 		return op
 	}())
 
-	// Plugin artifacts
-	spec.AddOperation("/api/v1/plugins/{plugin}/artifacts", http.MethodGet, func() *openapi3.Operation {
+	spec.AddOperation("/api/v1/schedules/{name}/run", http.MethodPost, func() *openapi3.Operation {
 		op := openapi3.NewOperation()
-		op.Summary = "List plugin artifacts"
-		op.OperationID = "listPluginArtifacts"
-		op.Tags = []string{"plugins", "artifacts"}
-		op.Parameters = openapi3.Parameters{
-			pluginParam,
-			&openapi3.ParameterRef{Value: &openapi3.Parameter{Name: "version", In: openapi3.ParameterInQuery, Description: "Filter by version", Schema: openapi3.NewSchemaRef("", openapi3.NewStringSchema())}},
-		}
+		op.Summary = "Run schedule now"
+		op.OperationID = "runSchedule"
+		op.Tags = []string{"schedules"}
+		op.Parameters = openapi3.Parameters{nameParam}
 		op.Responses = openapi3.NewResponses()
 		{
-			resp := openapi3.NewResponse().WithDescription("Artifacts list")
-			arr := &openapi3.Schema{Type: &openapi3.Types{openapi3.TypeArray}, Items: pluginArtifactRef}
+			resp := openapi3.NewResponse().WithDescription("Execution result")
+			resp.Content = openapi3.NewContentWithJSONSchemaRef(scheduleRunRespRef)
+			op.Responses.Set("200", &openapi3.ResponseRef{Value: resp})
+		}
+		return op
+	}())
+
+	spec.AddOperation("/api/v1/schedules/{name}/runs", http.MethodGet, func() *openapi3.Operation {
+		op := openapi3.NewOperation()
+		op.Summary = "Schedule execution history"
+		op.OperationID = "getScheduleRuns"
+		op.Tags = []string{"schedules"}
+		op.Parameters = openapi3.Parameters{nameParam, limitParam}
+		op.Responses = openapi3.NewResponses()
+		{
+			resp := openapi3.NewResponse().WithDescription("Most recent runs first")
+			arr := &openapi3.Schema{Type: &openapi3.Types{openapi3.TypeArray}, Items: scheduleRunRespRef}
+			resp.Content = openapi3.NewContentWithJSONSchema(arr)
+			op.Responses.Set("200", &openapi3.ResponseRef{Value: resp})
+		}
+		return op
+	}())
+
+	// /api/v1/webhooks
+	webhookReqRef, _ := gen.NewSchemaRefForValue(&createWebhookRequest{}, spec.Components.Schemas)
+	webhookRespRef, _ := gen.NewSchemaRefForValue(&webhookResponse{}, spec.Components.Schemas)
+	webhookDeliveryRespRef, _ := gen.NewSchemaRefForValue(&webhookDeliveryResponse{}, spec.Components.Schemas)
+	spec.AddOperation("/api/v1/webhooks", http.MethodGet, func() *openapi3.Operation {
+		op := openapi3.NewOperation()
+		op.Summary = "List webhooks"
+		op.OperationID = "listWebhooks"
+		op.Tags = []string{"webhooks"}
+		op.Responses = openapi3.NewResponses()
+		{
+			resp := openapi3.NewResponse().WithDescription("Array of webhooks")
+			arr := &openapi3.Schema{Type: &openapi3.Types{openapi3.TypeArray}, Items: webhookRespRef}
+			resp.Content = openapi3.NewContentWithJSONSchema(arr)
+			op.Responses.Set("200", &openapi3.ResponseRef{Value: resp})
+		}
+		return op
+	}())
+
+	spec.AddOperation("/api/v1/webhooks", http.MethodPost, func() *openapi3.Operation {
+		op := openapi3.NewOperation()
+		op.Summary = "Register webhook"
+		op.Description = "Delivers VM and deployment lifecycle events to url as signed JSON POSTs, retrying failures with exponential backoff. The secret is returned only in this response; one is generated when omitted."
+		op.OperationID = "createWebhook"
+		op.Tags = []string{"webhooks"}
+		op.RequestBody = &openapi3.RequestBodyRef{Value: &openapi3.RequestBody{Required: true, Content: openapi3.NewContentWithJSONSchemaRef(webhookReqRef)}}
+		op.Responses = openapi3.NewResponses()
+		{
+			resp := openapi3.NewResponse().WithDescription("Webhook created")
+			resp.Content = openapi3.NewContentWithJSONSchemaRef(webhookRespRef)
+			op.Responses.Set("201", &openapi3.ResponseRef{Value: resp})
+		}
+		op.Responses.Set("400", &openapi3.ResponseRef{Value: openapi3.NewResponse().WithDescription("Invalid webhook").WithContent(openapi3.NewContentWithJSONSchemaRef(errorSchema))})
+		op.Responses.Set("409", &openapi3.ResponseRef{Value: openapi3.NewResponse().WithDescription("Webhook already exists").WithContent(openapi3.NewContentWithJSONSchemaRef(errorSchema))})
+		return op
+	}())
+
+	spec.AddOperation("/api/v1/webhooks/{name}", http.MethodGet, func() *openapi3.Operation {
+		op := openapi3.NewOperation()
+		op.Summary = "Get webhook"
+		op.OperationID = "getWebhook"
+		op.Tags = []string{"webhooks"}
+		op.Parameters = openapi3.Parameters{nameParam}
+		op.Responses = openapi3.NewResponses()
+		{
+			resp := openapi3.NewResponse().WithDescription("Webhook")
+			resp.Content = openapi3.NewContentWithJSONSchemaRef(webhookRespRef)
+			op.Responses.Set("200", &openapi3.ResponseRef{Value: resp})
+		}
+		return op
+	}())
+
+	spec.AddOperation("/api/v1/webhooks/{name}", http.MethodDelete, func() *openapi3.Operation {
+		op := openapi3.NewOperation()
+		op.Summary = "Delete webhook"
+		op.OperationID = "deleteWebhook"
+		op.Tags = []string{"webhooks"}
+		op.Parameters = openapi3.Parameters{nameParam}
+		op.Responses = openapi3.NewResponses()
+		op.Responses.Set("204", &openapi3.ResponseRef{Value: openapi3.NewResponse().WithDescription("Deleted")})
+		return op
+	}())
+
+	spec.AddOperation("/api/v1/webhooks/{name}/enabled", http.MethodPost, func() *openapi3.Operation {
+		op := openapi3.NewOperation()
+		op.Summary = "Enable or disable webhook"
+		op.OperationID = "setWebhookEnabled"
+		op.Tags = []string{"webhooks"}
+		op.Parameters = openapi3.Parameters{nameParam}
+		body := openapi3.NewObjectSchema()
+		body.Properties = map[string]*openapi3.SchemaRef{"enabled": openapi3.NewSchemaRef("", openapi3.NewBoolSchema())}
+		op.RequestBody = &openapi3.RequestBodyRef{Value: &openapi3.RequestBody{Required: true, Content: openapi3.NewContentWithJSONSchema(body)}}
+		op.Responses = openapi3.NewResponses()
+		{
+			resp := openapi3.NewResponse().WithDescription("Webhook")
+			resp.Content = openapi3.NewContentWithJSONSchemaRef(webhookRespRef)
+			op.Responses.Set("200", &openapi3.ResponseRef{Value: resp})
+		}
+		return op
+	}())
+
+	spec.AddOperation("/api/v1/webhooks/{name}/deliveries", http.MethodGet, func() *openapi3.Operation {
+		op := openapi3.NewOperation()
+		op.Summary = "Webhook delivery history"
+		op.Description = "One entry per attempt, kept for seven days. Retries of the same event share delivery_id."
+		op.OperationID = "getWebhookDeliveries"
+		op.Tags = []string{"webhooks"}
+		op.Parameters = openapi3.Parameters{nameParam, limitParam}
+		op.Responses = openapi3.NewResponses()
+		{
+			resp := openapi3.NewResponse().WithDescription("Most recent attempts first")
+			arr := &openapi3.Schema{Type: &openapi3.Types{openapi3.TypeArray}, Items: webhookDeliveryRespRef}
+			resp.Content = openapi3.NewContentWithJSONSchema(arr)
+			op.Responses.Set("200", &openapi3.ResponseRef{Value: resp})
+		}
+		return op
+	}())
+
+	// /api/v1/secrets
+	secretReqRef, _ := gen.NewSchemaRefForValue(&putSecretRequest{}, spec.Components.Schemas)
+	secretUpdateReqRef, _ := gen.NewSchemaRefForValue(&updateSecretRequest{}, spec.Components.Schemas)
+	secretRespRef, _ := gen.NewSchemaRefForValue(&secretResponse{}, spec.Components.Schemas)
+	spec.AddOperation("/api/v1/secrets", http.MethodGet, func() *openapi3.Operation {
+		op := openapi3.NewOperation()
+		op.Summary = "List secrets"
+		op.Description = "Names and timestamps only; values are never returned."
+		op.OperationID = "listSecrets"
+		op.Tags = []string{"secrets"}
+		op.Responses = openapi3.NewResponses()
+		{
+			resp := openapi3.NewResponse().WithDescription("Array of secrets")
+			arr := &openapi3.Schema{Type: &openapi3.Types{openapi3.TypeArray}, Items: secretRespRef}
+			resp.Content = openapi3.NewContentWithJSONSchema(arr)
+			op.Responses.Set("200", &openapi3.ResponseRef{Value: resp})
+		}
+		return op
+	}())
+
+	spec.AddOperation("/api/v1/secrets", http.MethodPost, func() *openapi3.Operation {
+		op := openapi3.NewOperation()
+		op.Summary = "Store secret"
+		op.Description = "Seals value under the host key and stores it, replacing the value of an existing secret. Cloud-init documents with template set reference it as {{ secret \"name\" }} and VM configs or manifests mount it with secrets; values only reach the guest."
+		op.OperationID = "putSecret"
+		op.Tags = []string{"secrets"}
+		op.RequestBody = &openapi3.RequestBodyRef{Value: &openapi3.RequestBody{Required: true, Content: openapi3.NewContentWithJSONSchemaRef(secretReqRef)}}
+		op.Responses = openapi3.NewResponses()
+		{
+			resp := openapi3.NewResponse().WithDescription("Secret replaced")
+			resp.Content = openapi3.NewContentWithJSONSchemaRef(secretRespRef)
+			op.Responses.Set("200", &openapi3.ResponseRef{Value: resp})
+		}
+		{
+			resp := openapi3.NewResponse().WithDescription("Secret created")
+			resp.Content = openapi3.NewContentWithJSONSchemaRef(secretRespRef)
+			op.Responses.Set("201", &openapi3.ResponseRef{Value: resp})
+		}
+		op.Responses.Set("400", &openapi3.ResponseRef{Value: openapi3.NewResponse().WithDescription("Invalid secret").WithContent(openapi3.NewContentWithJSONSchemaRef(errorSchema))})
+		return op
+	}())
+
+	spec.AddOperation("/api/v1/secrets/{name}", http.MethodGet, func() *openapi3.Operation {
+		op := openapi3.NewOperation()
+		op.Summary = "Get secret"
+		op.Description = "Name and timestamps only; the value is never returned."
+		op.OperationID = "getSecret"
+		op.Tags = []string{"secrets"}
+		op.Parameters = openapi3.Parameters{nameParam}
+		op.Responses = openapi3.NewResponses()
+		{
+			resp := openapi3.NewResponse().WithDescription("Secret")
+			resp.Content = openapi3.NewContentWithJSONSchemaRef(secretRespRef)
+			op.Responses.Set("200", &openapi3.ResponseRef{Value: resp})
+		}
+		op.Responses.Set("404", &openapi3.ResponseRef{Value: openapi3.NewResponse().WithDescription("Secret not found").WithContent(openapi3.NewContentWithJSONSchemaRef(errorSchema))})
+		return op
+	}())
+
+	spec.AddOperation("/api/v1/secrets/{name}", http.MethodPut, func() *openapi3.Operation {
+		op := openapi3.NewOperation()
+		op.Summary = "Update secret"
+		op.Description = "Replaces the value of an existing secret. VMs receive the new value the next time their seed image is built or their agent boots."
+		op.OperationID = "updateSecret"
+		op.Tags = []string{"secrets"}
+		op.Parameters = openapi3.Parameters{nameParam}
+		op.RequestBody = &openapi3.RequestBodyRef{Value: &openapi3.RequestBody{Required: true, Content: openapi3.NewContentWithJSONSchemaRef(secretUpdateReqRef)}}
+		op.Responses = openapi3.NewResponses()
+		{
+			resp := openapi3.NewResponse().WithDescription("Secret updated")
+			resp.Content = openapi3.NewContentWithJSONSchemaRef(secretRespRef)
+			op.Responses.Set("200", &openapi3.ResponseRef{Value: resp})
+		}
+		op.Responses.Set("400", &openapi3.ResponseRef{Value: openapi3.NewResponse().WithDescription("Invalid secret").WithContent(openapi3.NewContentWithJSONSchemaRef(errorSchema))})
+		op.Responses.Set("404", &openapi3.ResponseRef{Value: openapi3.NewResponse().WithDescription("Secret not found").WithContent(openapi3.NewContentWithJSONSchemaRef(errorSchema))})
+		return op
+	}())
+
+	spec.AddOperation("/api/v1/secrets/{name}", http.MethodDelete, func() *openapi3.Operation {
+		op := openapi3.NewOperation()
+		op.Summary = "Delete secret"
+		op.Description = "VMs already rendered with the secret keep its value in their seed image until it is rebuilt."
+		op.OperationID = "deleteSecret"
+		op.Tags = []string{"secrets"}
+		op.Parameters = openapi3.Parameters{nameParam}
+		op.Responses = openapi3.NewResponses()
+		op.Responses.Set("204", &openapi3.ResponseRef{Value: openapi3.NewResponse().WithDescription("Deleted")})
+		op.Responses.Set("404", &openapi3.ResponseRef{Value: openapi3.NewResponse().WithDescription("Secret not found").WithContent(openapi3.NewContentWithJSONSchemaRef(errorSchema))})
+		return op
+	}())
+
+	// /api/v1/system/backups
+	backupRespRef, _ := gen.NewSchemaRefForValue(&backupResponse{}, spec.Components.Schemas)
+	restoreReqRef, _ := gen.NewSchemaRefForValue(&restoreBackupRequest{}, spec.Components.Schemas)
+	restoreRespRef, _ := gen.NewSchemaRefForValue(&restoreBackupResponse{}, spec.Components.Schemas)
+	spec.AddOperation("/api/v1/system/backups", http.MethodGet, func() *openapi3.Operation {
+		op := openapi3.NewOperation()
+		op.Summary = "List database backups"
+		op.OperationID = "listBackups"
+		op.Tags = []string{"system"}
+		op.Responses = openapi3.NewResponses()
+		{
+			resp := openapi3.NewResponse().WithDescription("Backups, newest first")
+			arr := &openapi3.Schema{Type: &openapi3.Types{openapi3.TypeArray}, Items: backupRespRef}
+			resp.Content = openapi3.NewContentWithJSONSchema(arr)
+			op.Responses.Set("200", &openapi3.ResponseRef{Value: resp})
+		}
+		return op
+	}())
+
+	spec.AddOperation("/api/v1/system/backups", http.MethodPost, func() *openapi3.Operation {
+		op := openapi3.NewOperation()
+		op.Summary = "Back up the database now"
+		op.Description = "Writes an online snapshot of the SQLite database to the backup directory and prunes backups beyond the retention count."
+		op.OperationID = "createBackup"
+		op.Tags = []string{"system"}
+		op.Responses = openapi3.NewResponses()
+		{
+			resp := openapi3.NewResponse().WithDescription("Backup created")
+			resp.Content = openapi3.NewContentWithJSONSchemaRef(backupRespRef)
+			op.Responses.Set("201", &openapi3.ResponseRef{Value: resp})
+		}
+		op.Responses.Set("501", &openapi3.ResponseRef{Value: openapi3.NewResponse().WithDescription("Storage backend does not support backups").WithContent(openapi3.NewContentWithJSONSchemaRef(errorSchema))})
+		return op
+	}())
+
+	spec.AddOperation("/api/v1/system/restore", http.MethodPost, func() *openapi3.Operation {
+		op := openapi3.NewOperation()
+		op.Summary = "Restore the database from a backup"
+		op.Description = "Backs up the current state, then replaces the database with the named backup. Restart volantd afterwards so VM and network state is reloaded."
+		op.OperationID = "restoreBackup"
+		op.Tags = []string{"system"}
+		op.RequestBody = &openapi3.RequestBodyRef{Value: &openapi3.RequestBody{Required: true, Content: openapi3.NewContentWithJSONSchemaRef(restoreReqRef)}}
+		op.Responses = openapi3.NewResponses()
+		{
+			resp := openapi3.NewResponse().WithDescription("Restore result")
+			resp.Content = openapi3.NewContentWithJSONSchemaRef(restoreRespRef)
+			op.Responses.Set("200", &openapi3.ResponseRef{Value: resp})
+		}
+		op.Responses.Set("400", &openapi3.ResponseRef{Value: openapi3.NewResponse().WithDescription("Invalid backup name").WithContent(openapi3.NewContentWithJSONSchemaRef(errorSchema))})
+		op.Responses.Set("404", &openapi3.ResponseRef{Value: openapi3.NewResponse().WithDescription("Backup not found").WithContent(openapi3.NewContentWithJSONSchemaRef(errorSchema))})
+		op.Responses.Set("409", &openapi3.ResponseRef{Value: openapi3.NewResponse().WithDescription("Backup schema is newer than this build").WithContent(openapi3.NewContentWithJSONSchemaRef(errorSchema))})
+		return op
+	}())
+
+	// /api/v1/system/doctor
+	doctorRespRef, _ := gen.NewSchemaRefForValue(&doctor.Report{}, spec.Components.Schemas)
+	spec.AddOperation("/api/v1/system/doctor", http.MethodGet, func() *openapi3.Operation {
+		op := openapi3.NewOperation()
+		op.Summary = "Check host prerequisites"
+		op.Description = "Checks KVM, the cloud-hypervisor binary, the bridge and its host IP, /dev/net/tun, IP forwarding, vhost-vsock, the IOMMU and free space in the runtime directory on every call. Each check passes, warns (needed by some workloads only) or fails, with a hint on how to fix it; healthy is false when any check failed."
+		op.OperationID = "systemDoctor"
+		op.Tags = []string{"system"}
+		op.Responses = openapi3.NewResponses()
+		{
+			resp := openapi3.NewResponse().WithDescription("Check results")
+			resp.Content = openapi3.NewContentWithJSONSchemaRef(doctorRespRef)
+			op.Responses.Set("200", &openapi3.ResponseRef{Value: resp})
+		}
+		op.Responses.Set("501", &openapi3.ResponseRef{Value: openapi3.NewResponse().WithDescription("Host checks are not available in this server").WithContent(openapi3.NewContentWithJSONSchemaRef(errorSchema))})
+		return op
+	}())
+
+	// /api/v1/system/gc
+	gcRespRef, _ := gen.NewSchemaRefForValue(&gcStatusResponse{}, spec.Components.Schemas)
+	spec.AddOperation("/api/v1/system/gc", http.MethodGet, func() *openapi3.Operation {
+		op := openapi3.NewOperation()
+		op.Summary = "Show the last garbage collection pass"
+		op.Description = "Taps, sockets and cloud-init seed images that no VM owns are removed periodically once two consecutive passes find them unowned."
+		op.OperationID = "getGarbageCollection"
+		op.Tags = []string{"system"}
+		op.Responses = openapi3.NewResponses()
+		{
+			resp := openapi3.NewResponse().WithDescription("Garbage collection status; last_run is null before the first pass")
+			resp.Content = openapi3.NewContentWithJSONSchemaRef(gcRespRef)
+			op.Responses.Set("200", &openapi3.ResponseRef{Value: resp})
+		}
+		return op
+	}())
+
+	// /api/v1/system/agents
+	agentStatsRef, _ := gen.NewSchemaRefForValue(&agentStatsResponse{}, spec.Components.Schemas)
+	spec.AddOperation("/api/v1/system/agents", http.MethodGet, func() *openapi3.Operation {
+		op := openapi3.NewOperation()
+		op.Summary = "Show connection and circuit breaker state per VM agent"
+		op.Description = "Calls to a VM's agent share a pool of at most 16 connections. After 5 consecutive failures to reach the agent its breaker opens: calls fail fast with 503 AGENT_UNAVAILABLE and a Retry-After header until a probe after the cooldown succeeds. Agents not called recently are omitted."
+		op.OperationID = "getAgentStats"
+		op.Tags = []string{"system"}
+		op.Parameters = openapi3.Parameters{
+			&openapi3.ParameterRef{Value: &openapi3.Parameter{Name: "vm", In: openapi3.ParameterInQuery, Description: "Only these VMs (comma-separated or repeated)", Schema: openapi3.NewSchemaRef("", openapi3.NewStringSchema())}},
+		}
+		op.Responses = openapi3.NewResponses()
+		{
+			resp := openapi3.NewResponse().WithDescription("Agent call statistics, by VM name")
+			resp.Content = openapi3.NewContentWithJSONSchemaRef(agentStatsRef)
+			op.Responses.Set("200", &openapi3.ResponseRef{Value: resp})
+		}
+		return op
+	}())
+
+	// /api/v1/system/reload
+	reloadRespRef, _ := gen.NewSchemaRefForValue(&config.ReloadResult{}, spec.Components.Schemas)
+	spec.AddOperation("/api/v1/system/reload", http.MethodPost, func() *openapi3.Operation {
+		op := openapi3.NewOperation()
+		op.Summary = "Reload the daemon configuration"
+		op.Description = "Re-reads the config file (volantd --config or VOLANT_CONFIG_FILE), as SIGHUP does, and applies the API key, client CIDR allowlist, CORS origins and log level without a restart. Other changed settings are listed under restart_required. An invalid file changes nothing."
+		op.OperationID = "reloadConfig"
+		op.Tags = []string{"system"}
+		op.Responses = openapi3.NewResponses()
+		{
+			resp := openapi3.NewResponse().WithDescription("The settings the reload changed")
+			resp.Content = openapi3.NewContentWithJSONSchemaRef(reloadRespRef)
+			op.Responses.Set("200", &openapi3.ResponseRef{Value: resp})
+		}
+		op.Responses.Set("422", &openapi3.ResponseRef{Value: openapi3.NewResponse().WithDescription("The config file could not be read or holds an invalid setting").WithContent(openapi3.NewContentWithJSONSchemaRef(errorSchema))})
+		op.Responses.Set("501", &openapi3.ResponseRef{Value: openapi3.NewResponse().WithDescription("Reload is not available in this server").WithContent(openapi3.NewContentWithJSONSchemaRef(errorSchema))})
+		return op
+	}())
+
+	// /api/v1/system/maintenance
+	hostMaintReqRef, _ := gen.NewSchemaRefForValue(&hostMaintenanceRequest{}, spec.Components.Schemas)
+	hostMaintRespRef, _ := gen.NewSchemaRefForValue(&orchestrator.HostMaintenance{}, spec.Components.Schemas)
+	spec.AddOperation("/api/v1/system/maintenance", http.MethodGet, func() *openapi3.Operation {
+		op := openapi3.NewOperation()
+		op.Summary = "Show host maintenance mode"
+		op.Description = "Reports whether the host is in maintenance and the VMs maintenance stopped. After an exit, the last maintenance is reported with ended_at and the outcome of each restart."
+		op.OperationID = "getHostMaintenance"
+		op.Tags = []string{"system"}
+		op.Responses = openapi3.NewResponses()
+		{
+			resp := openapi3.NewResponse().WithDescription("Host maintenance state")
+			resp.Content = openapi3.NewContentWithJSONSchemaRef(hostMaintRespRef)
+			op.Responses.Set("200", &openapi3.ResponseRef{Value: resp})
+		}
+		return op
+	}())
+	spec.AddOperation("/api/v1/system/maintenance", http.MethodPost, func() *openapi3.Operation {
+		op := openapi3.NewOperation()
+		op.Summary = "Enter host maintenance mode"
+		op.Description = "Opens the maintenance window named host, which covers everything: deployment reconciles and schedules are held, and VM creation answers 503 HOST_MAINTENANCE. Every running VM is then stopped, running its pre-stop hooks, concurrency at a time; the response lists them once they are stopped. Posting again while engaged stops VMs started since."
+		op.OperationID = "enterHostMaintenance"
+		op.Tags = []string{"system"}
+		op.RequestBody = &openapi3.RequestBodyRef{Value: &openapi3.RequestBody{Content: openapi3.NewContentWithJSONSchemaRef(hostMaintReqRef)}}
+		op.Responses = openapi3.NewResponses()
+		{
+			resp := openapi3.NewResponse().WithDescription("Host maintenance state; vms[].error names VMs that failed to stop")
+			resp.Content = openapi3.NewContentWithJSONSchemaRef(hostMaintRespRef)
+			op.Responses.Set("200", &openapi3.ResponseRef{Value: resp})
+		}
+		op.Responses.Set("409", &openapi3.ResponseRef{Value: openapi3.NewResponse().WithDescription("Held actions of the previous maintenance are still waiting to replay").WithContent(openapi3.NewContentWithJSONSchemaRef(errorSchema))})
+		return op
+	}())
+	spec.AddOperation("/api/v1/system/maintenance/exit", http.MethodPost, func() *openapi3.Operation {
+		op := openapi3.NewOperation()
+		op.Summary = "Exit host maintenance mode"
+		op.Description = "Ends the host maintenance window, replays held actions and restarts the VMs maintenance stopped, concurrency at a time. Restarts cut short by a daemon restart are finished by the maintenance replay loop."
+		op.OperationID = "exitHostMaintenance"
+		op.Tags = []string{"system"}
+		op.RequestBody = &openapi3.RequestBodyRef{Value: &openapi3.RequestBody{Content: openapi3.NewContentWithJSONSchemaRef(hostMaintReqRef)}}
+		op.Responses = openapi3.NewResponses()
+		{
+			resp := openapi3.NewResponse().WithDescription("Host maintenance state; vms[].error names VMs that failed to restart")
+			resp.Content = openapi3.NewContentWithJSONSchemaRef(hostMaintRespRef)
+			op.Responses.Set("200", &openapi3.ResponseRef{Value: resp})
+		}
+		op.Responses.Set("404", &openapi3.ResponseRef{Value: openapi3.NewResponse().WithDescription("The host is not in maintenance").WithContent(openapi3.NewContentWithJSONSchemaRef(errorSchema))})
+		return op
+	}())
+
+	// /api/v1/agent/artifacts
+	agentArtifactRef, _ := gen.NewSchemaRefForValue(&agentArtifact{}, spec.Components.Schemas)
+	agentVersionParam := &openapi3.ParameterRef{Value: &openapi3.Parameter{Name: "version", In: openapi3.ParameterInPath, Required: true, Description: "Agent version, as pinned by a manifest's agent.version", Schema: openapi3.NewSchemaRef("", openapi3.NewStringSchema())}}
+	agentArchParam := &openapi3.ParameterRef{Value: &openapi3.Parameter{Name: "arch", In: openapi3.ParameterInPath, Required: true, Description: "Guest architecture", Schema: openapi3.NewSchemaRef("", openapi3.NewStringSchema().WithEnum("amd64", "arm64"))}}
+	spec.AddOperation("/api/v1/agent/artifacts", http.MethodGet, func() *openapi3.Operation {
+		op := openapi3.NewOperation()
+		op.Summary = "List agent binaries served to guests"
+		op.OperationID = "listAgentArtifacts"
+		op.Tags = []string{"system"}
+		op.Responses = openapi3.NewResponses()
+		{
+			resp := openapi3.NewResponse().WithDescription("Agent binaries by version and architecture")
+			arr := &openapi3.Schema{Type: &openapi3.Types{openapi3.TypeArray}, Items: agentArtifactRef}
+			resp.Content = openapi3.NewContentWithJSONSchema(arr)
+			op.Responses.Set("200", &openapi3.ResponseRef{Value: resp})
+		}
+		return op
+	}())
+	spec.AddOperation("/api/v1/agent/artifacts/{version}/{arch}", http.MethodPut, func() *openapi3.Operation {
+		op := openapi3.NewOperation()
+		op.Summary = "Upload an agent binary"
+		op.Description = "Stores the body as the agent binary guests fetch when their plugin manifest pins this agent version. It replaces any existing binary only once fully written. Binaries are kept under VOLANT_AGENT_DIR (default /var/lib/volant/agent) and limited to VOLANT_FILE_MAX_SIZE_MB (default 1024)."
+		op.OperationID = "putAgentArtifact"
+		op.Tags = []string{"system"}
+		op.Parameters = openapi3.Parameters{agentVersionParam, agentArchParam, checksumParam}
+		op.RequestBody = &openapi3.RequestBodyRef{Value: &openapi3.RequestBody{Required: true, Content: openapi3.Content{"application/octet-stream": {Schema: openapi3.NewSchemaRef("", binarySchema)}}}}
+		op.Responses = openapi3.NewResponses()
+		op.Responses.Set("201", &openapi3.ResponseRef{Value: openapi3.NewResponse().WithDescription("Binary stored").WithContent(openapi3.NewContentWithJSONSchemaRef(agentArtifactRef))})
+		op.Responses.Set("413", &openapi3.ResponseRef{Value: openapi3.NewResponse().WithDescription("File too large").WithContent(openapi3.NewContentWithJSONSchemaRef(errorSchema))})
+		op.Responses.Set("422", &openapi3.ResponseRef{Value: openapi3.NewResponse().WithDescription("Checksum mismatch").WithContent(openapi3.NewContentWithJSONSchemaRef(errorSchema))})
+		return op
+	}())
+	spec.AddOperation("/api/v1/agent/artifacts/{version}/{arch}", http.MethodGet, func() *openapi3.Operation {
+		op := openapi3.NewOperation()
+		op.Summary = "Download an agent binary"
+		op.Description = "Serves the agent binary with its SHA-256 in X-Volant-Checksum-Sha256. Guests verify the download against it, and against the manifest's pinned checksum when set, before replacing their agent."
+		op.OperationID = "getAgentArtifact"
+		op.Tags = []string{"system"}
+		op.Parameters = openapi3.Parameters{agentVersionParam, agentArchParam}
+		op.Responses = openapi3.NewResponses()
+		{
+			desc := "Agent binary"
+			resp := &openapi3.Response{Description: &desc, Content: openapi3.Content{"application/octet-stream": {Schema: openapi3.NewSchemaRef("", binarySchema)}}}
+			op.Responses.Set("200", &openapi3.ResponseRef{Value: resp})
+		}
+		op.Responses.Set("404", &openapi3.ResponseRef{Value: openapi3.NewResponse().WithDescription("No binary for this version and architecture").WithContent(openapi3.NewContentWithJSONSchemaRef(errorSchema))})
+		return op
+	}())
+	spec.AddOperation("/api/v1/agent/artifacts/{version}/{arch}", http.MethodDelete, func() *openapi3.Operation {
+		op := openapi3.NewOperation()
+		op.Summary = "Delete an agent binary"
+		op.Description = "Guests whose manifest pins the deleted version keep running their current agent."
+		op.OperationID = "deleteAgentArtifact"
+		op.Tags = []string{"system"}
+		op.Parameters = openapi3.Parameters{agentVersionParam, agentArchParam}
+		op.Responses = openapi3.NewResponses()
+		op.Responses.Set("204", &openapi3.ResponseRef{Value: openapi3.NewResponse().WithDescription("Deleted")})
+		op.Responses.Set("404", &openapi3.ResponseRef{Value: openapi3.NewResponse().WithDescription("No binary for this version and architecture").WithContent(openapi3.NewContentWithJSONSchemaRef(errorSchema))})
+		return op
+	}())
+
+	// /api/v1/maintenance
+	maintenanceReqRef, _ := gen.NewSchemaRefForValue(&createMaintenanceWindowRequest{}, spec.Components.Schemas)
+	maintenanceRespRef, _ := gen.NewSchemaRefForValue(&maintenanceWindowResponse{}, spec.Components.Schemas)
+	spec.AddOperation("/api/v1/maintenance", http.MethodGet, func() *openapi3.Operation {
+		op := openapi3.NewOperation()
+		op.Summary = "List maintenance windows"
+		op.OperationID = "listMaintenanceWindows"
+		op.Tags = []string{"maintenance"}
+		op.Responses = openapi3.NewResponses()
+		{
+			resp := openapi3.NewResponse().WithDescription("Array of maintenance windows")
+			arr := &openapi3.Schema{Type: &openapi3.Types{openapi3.TypeArray}, Items: maintenanceRespRef}
+			resp.Content = openapi3.NewContentWithJSONSchema(arr)
+			op.Responses.Set("200", &openapi3.ResponseRef{Value: resp})
+		}
+		return op
+	}())
+
+	spec.AddOperation("/api/v1/maintenance", http.MethodPost, func() *openapi3.Operation {
+		op := openapi3.NewOperation()
+		op.Summary = "Create maintenance window"
+		op.Description = "While the window is open, deployment reconciliation after replica exits or deletes and scheduled actions on covered targets are held and recorded; they replay once the window closes. Scope is all, vm, deployment or plugin."
+		op.OperationID = "createMaintenanceWindow"
+		op.Tags = []string{"maintenance"}
+		op.RequestBody = &openapi3.RequestBodyRef{Value: &openapi3.RequestBody{Required: true, Content: openapi3.NewContentWithJSONSchemaRef(maintenanceReqRef)}}
+		op.Responses = openapi3.NewResponses()
+		{
+			resp := openapi3.NewResponse().WithDescription("Maintenance window created")
+			resp.Content = openapi3.NewContentWithJSONSchemaRef(maintenanceRespRef)
+			op.Responses.Set("201", &openapi3.ResponseRef{Value: resp})
+		}
+		op.Responses.Set("400", &openapi3.ResponseRef{Value: openapi3.NewResponse().WithDescription("Invalid maintenance window").WithContent(openapi3.NewContentWithJSONSchemaRef(errorSchema))})
+		op.Responses.Set("409", &openapi3.ResponseRef{Value: openapi3.NewResponse().WithDescription("Maintenance window already exists").WithContent(openapi3.NewContentWithJSONSchemaRef(errorSchema))})
+		return op
+	}())
+
+	spec.AddOperation("/api/v1/maintenance/{name}", http.MethodGet, func() *openapi3.Operation {
+		op := openapi3.NewOperation()
+		op.Summary = "Get maintenance window"
+		op.Description = "Includes the intents the window held and how each was replayed."
+		op.OperationID = "getMaintenanceWindow"
+		op.Tags = []string{"maintenance"}
+		op.Parameters = openapi3.Parameters{nameParam}
+		op.Responses = openapi3.NewResponses()
+		{
+			resp := openapi3.NewResponse().WithDescription("Maintenance window")
+			resp.Content = openapi3.NewContentWithJSONSchemaRef(maintenanceRespRef)
+			op.Responses.Set("200", &openapi3.ResponseRef{Value: resp})
+		}
+		return op
+	}())
+
+	spec.AddOperation("/api/v1/maintenance/{name}", http.MethodDelete, func() *openapi3.Operation {
+		op := openapi3.NewOperation()
+		op.Summary = "Delete maintenance window"
+		op.OperationID = "deleteMaintenanceWindow"
+		op.Tags = []string{"maintenance"}
+		op.Parameters = openapi3.Parameters{nameParam}
+		op.Responses = openapi3.NewResponses()
+		op.Responses.Set("204", &openapi3.ResponseRef{Value: openapi3.NewResponse().WithDescription("Deleted")})
+		op.Responses.Set("409", &openapi3.ResponseRef{Value: openapi3.NewResponse().WithDescription("Window is open or has intents waiting to replay").WithContent(openapi3.NewContentWithJSONSchemaRef(errorSchema))})
+		return op
+	}())
+
+	spec.AddOperation("/api/v1/maintenance/{name}/end", http.MethodPost, func() *openapi3.Operation {
+		op := openapi3.NewOperation()
+		op.Summary = "End maintenance window now"
+		op.OperationID = "endMaintenanceWindow"
+		op.Tags = []string{"maintenance"}
+		op.Parameters = openapi3.Parameters{nameParam}
+		op.Responses = openapi3.NewResponses()
+		{
+			resp := openapi3.NewResponse().WithDescription("Maintenance window")
+			resp.Content = openapi3.NewContentWithJSONSchemaRef(maintenanceRespRef)
+			op.Responses.Set("200", &openapi3.ResponseRef{Value: resp})
+		}
+		return op
+	}())
+
+	// /api/v1/networks
+	networkReqRef, _ := gen.NewSchemaRefForValue(&createNetworkRequest{}, spec.Components.Schemas)
+	networkRespRef, _ := gen.NewSchemaRefForValue(&networkResponse{}, spec.Components.Schemas)
+	spec.AddOperation("/api/v1/networks", http.MethodGet, func() *openapi3.Operation {
+		op := openapi3.NewOperation()
+		op.Summary = "List networks"
+		op.OperationID = "listNetworks"
+		op.Tags = []string{"networks"}
+		op.Responses = openapi3.NewResponses()
+		{
+			resp := openapi3.NewResponse().WithDescription("Array of networks")
+			arr := &openapi3.Schema{Type: &openapi3.Types{openapi3.TypeArray}, Items: networkRespRef}
+			resp.Content = openapi3.NewContentWithJSONSchema(arr)
+			op.Responses.Set("200", &openapi3.ResponseRef{Value: resp})
+		}
+		return op
+	}())
+
+	spec.AddOperation("/api/v1/networks", http.MethodPost, func() *openapi3.Operation {
+		op := openapi3.NewOperation()
+		op.Summary = "Create network"
+		op.Description = "Creates a bridge with its own IPv4 subnet and address pool. VMs join it by setting network.name in their config."
+		op.OperationID = "createNetwork"
+		op.Tags = []string{"networks"}
+		op.RequestBody = &openapi3.RequestBodyRef{Value: &openapi3.RequestBody{Required: true, Content: openapi3.NewContentWithJSONSchemaRef(networkReqRef)}}
+		op.Responses = openapi3.NewResponses()
+		{
+			resp := openapi3.NewResponse().WithDescription("Network created")
+			resp.Content = openapi3.NewContentWithJSONSchemaRef(networkRespRef)
+			op.Responses.Set("201", &openapi3.ResponseRef{Value: resp})
+		}
+		op.Responses.Set("400", &openapi3.ResponseRef{Value: openapi3.NewResponse().WithDescription("Invalid network").WithContent(openapi3.NewContentWithJSONSchemaRef(errorSchema))})
+		op.Responses.Set("409", &openapi3.ResponseRef{Value: openapi3.NewResponse().WithDescription("Network already exists").WithContent(openapi3.NewContentWithJSONSchemaRef(errorSchema))})
+		return op
+	}())
+
+	spec.AddOperation("/api/v1/networks/{name}", http.MethodGet, func() *openapi3.Operation {
+		op := openapi3.NewOperation()
+		op.Summary = "Get network"
+		op.Description = "Includes the addresses leased to VMs."
+		op.OperationID = "getNetwork"
+		op.Tags = []string{"networks"}
+		op.Parameters = openapi3.Parameters{nameParam}
+		op.Responses = openapi3.NewResponses()
+		{
+			resp := openapi3.NewResponse().WithDescription("Network")
+			resp.Content = openapi3.NewContentWithJSONSchemaRef(networkRespRef)
+			op.Responses.Set("200", &openapi3.ResponseRef{Value: resp})
+		}
+		return op
+	}())
+
+	spec.AddOperation("/api/v1/networks/{name}", http.MethodDelete, func() *openapi3.Operation {
+		op := openapi3.NewOperation()
+		op.Summary = "Delete network"
+		op.OperationID = "deleteNetwork"
+		op.Tags = []string{"networks"}
+		op.Parameters = openapi3.Parameters{nameParam}
+		op.Responses = openapi3.NewResponses()
+		op.Responses.Set("204", &openapi3.ResponseRef{Value: openapi3.NewResponse().WithDescription("Deleted")})
+		op.Responses.Set("409", &openapi3.ResponseRef{Value: openapi3.NewResponse().WithDescription("VMs are still attached").WithContent(openapi3.NewContentWithJSONSchemaRef(errorSchema))})
+		return op
+	}())
+
+	// /api/v1/warm-pools
+	warmPoolReqRef, _ := gen.NewSchemaRefForValue(&setWarmPoolRequest{}, spec.Components.Schemas)
+	warmPoolRespRef, _ := gen.NewSchemaRefForValue(&warmPoolResponse{}, spec.Components.Schemas)
+	warmPoolParam := &openapi3.ParameterRef{Value: &openapi3.Parameter{Name: "plugin", In: openapi3.ParameterInPath, Required: true, Schema: openapi3.NewSchemaRef("", openapi3.NewStringSchema())}}
+	spec.AddOperation("/api/v1/warm-pools", http.MethodGet, func() *openapi3.Operation {
+		op := openapi3.NewOperation()
+		op.Summary = "List warm pools"
+		op.OperationID = "listWarmPools"
+		op.Tags = []string{"warm-pools"}
+		op.Responses = openapi3.NewResponses()
+		{
+			resp := openapi3.NewResponse().WithDescription("Array of warm pools")
+			arr := &openapi3.Schema{Type: &openapi3.Types{openapi3.TypeArray}, Items: warmPoolRespRef}
+			resp.Content = openapi3.NewContentWithJSONSchema(arr)
+			op.Responses.Set("200", &openapi3.ResponseRef{Value: resp})
+		}
+		return op
+	}())
+
+	spec.AddOperation("/api/v1/warm-pools/{plugin}", http.MethodGet, func() *openapi3.Operation {
+		op := openapi3.NewOperation()
+		op.Summary = "Get warm pool"
+		op.Description = "Reports how many of the pool's unclaimed VMs are ready and how many are still booting."
+		op.OperationID = "getWarmPool"
+		op.Tags = []string{"warm-pools"}
+		op.Parameters = openapi3.Parameters{warmPoolParam}
+		op.Responses = openapi3.NewResponses()
+		{
+			resp := openapi3.NewResponse().WithDescription("Warm pool")
+			resp.Content = openapi3.NewContentWithJSONSchemaRef(warmPoolRespRef)
+			op.Responses.Set("200", &openapi3.ResponseRef{Value: resp})
+		}
+		op.Responses.Set("404", &openapi3.ResponseRef{Value: openapi3.NewResponse().WithDescription("No warm pool for the plugin").WithContent(openapi3.NewContentWithJSONSchemaRef(errorSchema))})
+		return op
+	}())
+
+	spec.AddOperation("/api/v1/warm-pools/{plugin}", http.MethodPut, func() *openapi3.Operation {
+		op := openapi3.NewOperation()
+		op.Summary = "Set warm pool"
+		op.Description = "Creates or resizes the warm pool of a plugin. The pool keeps size booted, unclaimed VMs labeled volant.pool=<plugin> and is filled in the background. Changing the config replaces the unclaimed VMs."
+		op.OperationID = "setWarmPool"
+		op.Tags = []string{"warm-pools"}
+		op.Parameters = openapi3.Parameters{warmPoolParam}
+		op.RequestBody = &openapi3.RequestBodyRef{Value: &openapi3.RequestBody{Required: true, Content: openapi3.NewContentWithJSONSchemaRef(warmPoolReqRef)}}
+		op.Responses = openapi3.NewResponses()
+		{
+			resp := openapi3.NewResponse().WithDescription("Warm pool")
+			resp.Content = openapi3.NewContentWithJSONSchemaRef(warmPoolRespRef)
+			op.Responses.Set("200", &openapi3.ResponseRef{Value: resp})
+		}
+		op.Responses.Set("400", &openapi3.ResponseRef{Value: openapi3.NewResponse().WithDescription("Invalid warm pool").WithContent(openapi3.NewContentWithJSONSchemaRef(errorSchema))})
+		return op
+	}())
+
+	spec.AddOperation("/api/v1/warm-pools/{plugin}", http.MethodDelete, func() *openapi3.Operation {
+		op := openapi3.NewOperation()
+		op.Summary = "Delete warm pool"
+		op.Description = "Destroys the pool's unclaimed VMs. Claimed VMs are kept."
+		op.OperationID = "deleteWarmPool"
+		op.Tags = []string{"warm-pools"}
+		op.Parameters = openapi3.Parameters{warmPoolParam}
+		op.Responses = openapi3.NewResponses()
+		op.Responses.Set("204", &openapi3.ResponseRef{Value: openapi3.NewResponse().WithDescription("Deleted")})
+		op.Responses.Set("404", &openapi3.ResponseRef{Value: openapi3.NewResponse().WithDescription("No warm pool for the plugin").WithContent(openapi3.NewContentWithJSONSchemaRef(errorSchema))})
+		return op
+	}())
+
+	claimReqRef, _ := gen.NewSchemaRefForValue(&claimVMRequest{}, spec.Components.Schemas)
+	spec.AddOperation("/api/v1/vms/claim", http.MethodPost, func() *openapi3.Operation {
+		op := openapi3.NewOperation()
+		op.Summary = "Claim VM from warm pool"
+		op.Description = "Hands out a running, ready VM from the plugin's warm pool without waiting for a boot. The VM keeps its name; its volant.pool label is replaced by the given labels and the pool is replenished in the background."
+		op.OperationID = "claimVM"
+		op.Tags = []string{"vm"}
+		op.Parameters = openapi3.Parameters{
+			&openapi3.ParameterRef{Value: openapi3.NewQueryParameter("plugin").WithRequired(true).WithSchema(openapi3.NewStringSchema()).WithDescription("Plugin whose warm pool to claim from")},
+		}
+		op.RequestBody = &openapi3.RequestBodyRef{Value: &openapi3.RequestBody{Content: openapi3.NewContentWithJSONSchemaRef(claimReqRef)}}
+		op.Responses = openapi3.NewResponses()
+		{
+			resp := openapi3.NewResponse().WithDescription("Claimed VM")
+			resp.Content = openapi3.NewContentWithJSONSchemaRef(vmRespRef)
+			op.Responses.Set("200", &openapi3.ResponseRef{Value: resp})
+		}
+		op.Responses.Set("404", &openapi3.ResponseRef{Value: openapi3.NewResponse().WithDescription("No warm pool for the plugin").WithContent(openapi3.NewContentWithJSONSchemaRef(errorSchema))})
+		op.Responses.Set("503", &openapi3.ResponseRef{Value: openapi3.NewResponse().WithDescription("No ready VM in the pool").WithContent(openapi3.NewContentWithJSONSchemaRef(errorSchema))})
+		return op
+	}())
+
+	// /api/v1/routes
+	routeRef, _ := gen.NewSchemaRefForValue(&routes.Route{}, spec.Components.Schemas)
+	routeRespRef, _ := gen.NewSchemaRefForValue(&routeResponse{}, spec.Components.Schemas)
+	routeListSchema := &openapi3.Schema{Type: &openapi3.Types{openapi3.TypeArray}, Items: routeRespRef}
+	spec.AddOperation("/api/v1/routes", http.MethodGet, func() *openapi3.Operation {
+		op := openapi3.NewOperation()
+		op.Summary = "List routes"
+		op.Description = "Lists the host port routes of driftd, each with the VM whose expose rules publish it, followed by the routes of running VMs that driftd does not hold (published false)."
+		op.OperationID = "listRoutes"
+		op.Tags = []string{"routes"}
+		op.Parameters = openapi3.Parameters{
+			&openapi3.ParameterRef{Value: openapi3.NewQueryParameter("vm").WithSchema(openapi3.NewStringSchema()).WithDescription("Only the routes of this VM")},
+		}
+		op.Responses = openapi3.NewResponses()
+		op.Responses.Set("200", &openapi3.ResponseRef{Value: openapi3.NewResponse().WithDescription("Routes").WithContent(openapi3.NewContentWithJSONSchema(routeListSchema))})
+		op.Responses.Set("503", &openapi3.ResponseRef{Value: openapi3.NewResponse().WithDescription("Drift not configured").WithContent(openapi3.NewContentWithJSONSchemaRef(errorSchema))})
+		return op
+	}())
+
+	spec.AddOperation("/api/v1/routes", http.MethodPost, func() *openapi3.Operation {
+		op := openapi3.NewOperation()
+		op.Summary = "Create or replace a route"
+		op.Description = "Publishes a host port through driftd, replacing the route of the same port and protocol. Ports published for a running VM's expose rules are rejected with ROUTE_MANAGED."
+		op.OperationID = "upsertRoute"
+		op.Tags = []string{"routes"}
+		op.RequestBody = &openapi3.RequestBodyRef{Value: &openapi3.RequestBody{Required: true, Content: openapi3.NewContentWithJSONSchemaRef(routeRef)}}
+		op.Responses = openapi3.NewResponses()
+		op.Responses.Set("201", &openapi3.ResponseRef{Value: openapi3.NewResponse().WithDescription("Route stored").WithContent(openapi3.NewContentWithJSONSchemaRef(routeRef))})
+		op.Responses.Set("409", &openapi3.ResponseRef{Value: openapi3.NewResponse().WithDescription("The route belongs to a VM").WithContent(openapi3.NewContentWithJSONSchemaRef(errorSchema))})
+		op.Responses.Set("503", &openapi3.ResponseRef{Value: openapi3.NewResponse().WithDescription("Drift not configured").WithContent(openapi3.NewContentWithJSONSchemaRef(errorSchema))})
+		return op
+	}())
+
+	spec.AddOperation("/api/v1/routes/sync", http.MethodPost, func() *openapi3.Operation {
+		op := openapi3.NewOperation()
+		op.Summary = "Republish VM routes"
+		op.Description = "Publishes the routes of every running VM's expose rules again, for when driftd restarted without them. volantd also does this for the VMs it adopts on start."
+		op.OperationID = "syncRoutes"
+		op.Tags = []string{"routes"}
+		op.Responses = openapi3.NewResponses()
+		op.Responses.Set("200", &openapi3.ResponseRef{Value: openapi3.NewResponse().WithDescription("Routes published").WithContent(openapi3.NewContentWithJSONSchema(routeListSchema))})
+		op.Responses.Set("503", &openapi3.ResponseRef{Value: openapi3.NewResponse().WithDescription("Drift not configured").WithContent(openapi3.NewContentWithJSONSchemaRef(errorSchema))})
+		return op
+	}())
+
+	spec.AddOperation("/api/v1/routes/{protocol}/{port}", http.MethodDelete, func() *openapi3.Operation {
+		op := openapi3.NewOperation()
+		op.Summary = "Delete a route"
+		op.OperationID = "deleteRoute"
+		op.Tags = []string{"routes"}
+		op.Parameters = openapi3.Parameters{
+			&openapi3.ParameterRef{Value: openapi3.NewPathParameter("protocol").WithSchema(openapi3.NewStringSchema().WithEnum("tcp", "udp"))},
+			&openapi3.ParameterRef{Value: openapi3.NewPathParameter("port").WithSchema(openapi3.NewIntegerSchema().WithMin(1).WithMax(65535)).WithDescription("Host port")},
+		}
+		op.Responses = openapi3.NewResponses()
+		op.Responses.Set("204", &openapi3.ResponseRef{Value: openapi3.NewResponse().WithDescription("Deleted")})
+		op.Responses.Set("409", &openapi3.ResponseRef{Value: openapi3.NewResponse().WithDescription("The route belongs to a VM").WithContent(openapi3.NewContentWithJSONSchemaRef(errorSchema))})
+		op.Responses.Set("503", &openapi3.ResponseRef{Value: openapi3.NewResponse().WithDescription("Drift not configured").WithContent(openapi3.NewContentWithJSONSchemaRef(errorSchema))})
+		return op
+	}())
+
+	// /api/v1/config-bundles
+	bundleCreateReqRef, _ := gen.NewSchemaRefForValue(&createConfigBundleRequest{}, spec.Components.Schemas)
+	bundleUpdateReqRef, _ := gen.NewSchemaRefForValue(&configBundleRequest{}, spec.Components.Schemas)
+	bundleRespRef, _ := gen.NewSchemaRefForValue(&configBundleResponse{}, spec.Components.Schemas)
+	bundleRotationRespRef, _ := gen.NewSchemaRefForValue(&configBundleRotationResponse{}, spec.Components.Schemas)
+	spec.AddOperation("/api/v1/config-bundles", http.MethodGet, func() *openapi3.Operation {
+		op := openapi3.NewOperation()
+		op.Summary = "List config bundles"
+		op.Description = "Values are never returned; only key names and file paths."
+		op.OperationID = "listConfigBundles"
+		op.Tags = []string{"config-bundles"}
+		op.Responses = openapi3.NewResponses()
+		{
+			resp := openapi3.NewResponse().WithDescription("Array of config bundles")
+			arr := &openapi3.Schema{Type: &openapi3.Types{openapi3.TypeArray}, Items: bundleRespRef}
+			resp.Content = openapi3.NewContentWithJSONSchema(arr)
+			op.Responses.Set("200", &openapi3.ResponseRef{Value: resp})
+		}
+		return op
+	}())
+
+	spec.AddOperation("/api/v1/config-bundles", http.MethodPost, func() *openapi3.Operation {
+		op := openapi3.NewOperation()
+		op.Summary = "Create config bundle"
+		op.Description = "Creates a named set of key/value pairs and files. Deployments attach bundles by name in config.bundles."
+		op.OperationID = "createConfigBundle"
+		op.Tags = []string{"config-bundles"}
+		op.RequestBody = &openapi3.RequestBodyRef{Value: &openapi3.RequestBody{Required: true, Content: openapi3.NewContentWithJSONSchemaRef(bundleCreateReqRef)}}
+		op.Responses = openapi3.NewResponses()
+		{
+			resp := openapi3.NewResponse().WithDescription("Config bundle created")
+			resp.Content = openapi3.NewContentWithJSONSchemaRef(bundleRespRef)
+			op.Responses.Set("201", &openapi3.ResponseRef{Value: resp})
+		}
+		op.Responses.Set("400", &openapi3.ResponseRef{Value: openapi3.NewResponse().WithDescription("Invalid config bundle").WithContent(openapi3.NewContentWithJSONSchemaRef(errorSchema))})
+		op.Responses.Set("409", &openapi3.ResponseRef{Value: openapi3.NewResponse().WithDescription("Config bundle already exists").WithContent(openapi3.NewContentWithJSONSchemaRef(errorSchema))})
+		return op
+	}())
+
+	spec.AddOperation("/api/v1/config-bundles/{name}", http.MethodGet, func() *openapi3.Operation {
+		op := openapi3.NewOperation()
+		op.Summary = "Get config bundle"
+		op.OperationID = "getConfigBundle"
+		op.Tags = []string{"config-bundles"}
+		op.Parameters = openapi3.Parameters{nameParam}
+		op.Responses = openapi3.NewResponses()
+		{
+			resp := openapi3.NewResponse().WithDescription("Config bundle")
+			resp.Content = openapi3.NewContentWithJSONSchemaRef(bundleRespRef)
+			op.Responses.Set("200", &openapi3.ResponseRef{Value: resp})
+		}
+		return op
+	}())
+
+	spec.AddOperation("/api/v1/config-bundles/{name}", http.MethodPut, func() *openapi3.Operation {
+		op := openapi3.NewOperation()
+		op.Summary = "Update config bundle"
+		op.Description = "Replaces the bundle contents, bumps its version and rolls every deployment that uses it."
+		op.OperationID = "updateConfigBundle"
+		op.Tags = []string{"config-bundles"}
+		op.Parameters = openapi3.Parameters{nameParam}
+		op.RequestBody = &openapi3.RequestBodyRef{Value: &openapi3.RequestBody{Required: true, Content: openapi3.NewContentWithJSONSchemaRef(bundleUpdateReqRef)}}
+		op.Responses = openapi3.NewResponses()
+		{
+			resp := openapi3.NewResponse().WithDescription("Config bundle updated; deployments rolling")
+			resp.Content = openapi3.NewContentWithJSONSchemaRef(bundleRotationRespRef)
+			op.Responses.Set("202", &openapi3.ResponseRef{Value: resp})
+		}
+		return op
+	}())
+
+	spec.AddOperation("/api/v1/config-bundles/{name}", http.MethodDelete, func() *openapi3.Operation {
+		op := openapi3.NewOperation()
+		op.Summary = "Delete config bundle"
+		op.OperationID = "deleteConfigBundle"
+		op.Tags = []string{"config-bundles"}
+		op.Parameters = openapi3.Parameters{nameParam}
+		op.Responses = openapi3.NewResponses()
+		op.Responses.Set("204", &openapi3.ResponseRef{Value: openapi3.NewResponse().WithDescription("Deleted")})
+		op.Responses.Set("409", &openapi3.ResponseRef{Value: openapi3.NewResponse().WithDescription("Bundle is attached to a deployment").WithContent(openapi3.NewContentWithJSONSchemaRef(errorSchema))})
+		return op
+	}())
+
+	// /api/v1/dhcp/leases
+	dhcpLeaseRespRef, _ := gen.NewSchemaRefForValue(&dhcpLeaseResponse{}, spec.Components.Schemas)
+	spec.AddOperation("/api/v1/dhcp/leases", http.MethodGet, func() *openapi3.Operation {
+		op := openapi3.NewOperation()
+		op.Summary = "List DHCP leases"
+		op.Description = "Leases handed out by the embedded DHCP server to dhcp-mode VMs."
+		op.OperationID = "listDHCPLeases"
+		op.Tags = []string{"networks"}
+		op.Responses = openapi3.NewResponses()
+		{
+			resp := openapi3.NewResponse().WithDescription("Array of DHCP leases")
+			arr := &openapi3.Schema{Type: &openapi3.Types{openapi3.TypeArray}, Items: dhcpLeaseRespRef}
+			resp.Content = openapi3.NewContentWithJSONSchema(arr)
+			op.Responses.Set("200", &openapi3.ResponseRef{Value: resp})
+		}
+		return op
+	}())
+
+	// /api/v1/nodes/{node}/artifacts
+	nodeArtifactsRespRef, _ := gen.NewSchemaRefForValue(&nodeArtifactsResponse{}, spec.Components.Schemas)
+	spec.AddOperation("/api/v1/nodes/{node}/artifacts", http.MethodGet, func() *openapi3.Operation {
+		op := openapi3.NewOperation()
+		op.Summary = "List node artifacts"
+		op.Description = "Kernels and shared root images staged on the node. Use \"local\" or the host name."
+		op.OperationID = "listNodeArtifacts"
+		op.Tags = []string{"nodes"}
+		op.Parameters = openapi3.Parameters{{Value: &openapi3.Parameter{Name: "node", In: openapi3.ParameterInPath, Required: true, Schema: openapi3.NewSchemaRef("", openapi3.NewStringSchema())}}}
+		op.Responses = openapi3.NewResponses()
+		{
+			resp := openapi3.NewResponse().WithDescription("Node artifact inventory")
+			resp.Content = openapi3.NewContentWithJSONSchemaRef(nodeArtifactsRespRef)
+			op.Responses.Set("200", &openapi3.ResponseRef{Value: resp})
+		}
+		op.Responses.Set("404", &openapi3.ResponseRef{Value: openapi3.NewResponse().WithDescription("Unknown node").WithContent(openapi3.NewContentWithJSONSchemaRef(errorSchema))})
+		return op
+	}())
+	spec.AddOperation("/api/v1/nodes/{node}/artifacts/{name}", http.MethodGet, func() *openapi3.Operation {
+		op := openapi3.NewOperation()
+		op.Summary = "Download a node artifact"
+		op.Description = "Streams a cached kernel, initramfs or root image by the name the inventory reports. Hosts started with VOLANT_ARTIFACT_PEERS pull artifacts from their peers through it. Range requests are supported."
+		op.OperationID = "getNodeArtifact"
+		op.Tags = []string{"nodes"}
+		op.Parameters = openapi3.Parameters{
+			{Value: &openapi3.Parameter{Name: "node", In: openapi3.ParameterInPath, Required: true, Schema: openapi3.NewSchemaRef("", openapi3.NewStringSchema())}},
+			{Value: &openapi3.Parameter{Name: "name", In: openapi3.ParameterInPath, Required: true, Description: "Artifact name such as images/<sha256>.qcow2; contains a slash", Schema: openapi3.NewSchemaRef("", openapi3.NewStringSchema()),
+				Extensions: map[string]any{"x-slashes": true}}},
+		}
+		op.Responses = openapi3.NewResponses()
+		for _, status := range []string{"200", "206"} {
+			desc := "Artifact contents"
+			if status == "206" {
+				desc = "Requested range of the artifact"
+			}
+			resp := &openapi3.Response{Description: &desc, Content: openapi3.Content{"application/octet-stream": {Schema: openapi3.NewSchemaRef("", binarySchema)}}}
+			op.Responses.Set(status, &openapi3.ResponseRef{Value: resp})
+		}
+		op.Responses.Set("404", &openapi3.ResponseRef{Value: openapi3.NewResponse().WithDescription("Unknown node or artifact").WithContent(openapi3.NewContentWithJSONSchemaRef(errorSchema))})
+		return op
+	}())
+
+	// /api/v1/audit
+	auditRespRef, _ := gen.NewSchemaRefForValue(&auditEventResponse{}, spec.Components.Schemas)
+	spec.AddOperation("/api/v1/audit", http.MethodGet, func() *openapi3.Operation {
+		op := openapi3.NewOperation()
+		op.Summary = "List audit events"
+		op.Description = "Console session opens, closes (with duration) and denials, newest first."
+		op.OperationID = "listAuditEvents"
+		op.Tags = []string{"audit"}
+		op.Parameters = openapi3.Parameters{
+			&openapi3.ParameterRef{Value: &openapi3.Parameter{Name: "target", In: openapi3.ParameterInQuery, Description: "Only events for this VM", Schema: openapi3.NewSchemaRef("", openapi3.NewStringSchema())}},
+			limitParam,
+		}
+		op.Responses = openapi3.NewResponses()
+		{
+			resp := openapi3.NewResponse().WithDescription("Most recent events first")
+			arr := &openapi3.Schema{Type: &openapi3.Types{openapi3.TypeArray}, Items: auditRespRef}
+			resp.Content = openapi3.NewContentWithJSONSchema(arr)
+			op.Responses.Set("200", &openapi3.ResponseRef{Value: resp})
+		}
+		return op
+	}())
+
+	// /api/v1/plugins
+	manifestRef, _ := gen.NewSchemaRefForValue(&pluginspec.Manifest{}, spec.Components.Schemas)
+	spec.AddOperation("/api/v1/plugins", http.MethodGet, func() *openapi3.Operation {
+		op := openapi3.NewOperation()
+		op.Summary = "List plugins"
+		op.OperationID = "listPlugins"
+		op.Tags = []string{"plugins"}
+		op.Responses = openapi3.NewResponses()
+		{
+			resp := openapi3.NewResponse().WithDescription("List of plugin names")
+			listSchema := openapi3.NewObjectSchema()
+			listSchema.Properties = map[string]*openapi3.SchemaRef{
+				"plugins": openapi3.NewSchemaRef("", &openapi3.Schema{Type: &openapi3.Types{openapi3.TypeArray}, Items: openapi3.NewSchemaRef("", openapi3.NewStringSchema())}),
+			}
+			resp.Content = openapi3.NewContentWithJSONSchema(listSchema)
+			op.Responses.Set("200", &openapi3.ResponseRef{Value: resp})
+		}
+		return op
+	}())
+
+	spec.AddOperation("/api/v1/plugins", http.MethodPost, func() *openapi3.Operation {
+		op := openapi3.NewOperation()
+		op.Summary = "Install plugin"
+		op.OperationID = "installPlugin"
+		op.Tags = []string{"plugins"}
+		op.RequestBody = &openapi3.RequestBodyRef{Value: &openapi3.RequestBody{Required: true, Content: openapi3.NewContentWithJSONSchemaRef(manifestRef)}}
+		op.Responses = openapi3.NewResponses()
+		op.Responses.Set("201", &openapi3.ResponseRef{Value: openapi3.NewResponse().WithDescription("Plugin installed")})
+		op.Responses.Set("400", &openapi3.ResponseRef{Value: openapi3.NewResponse().WithDescription("Bad request").WithContent(openapi3.NewContentWithJSONSchemaRef(errorSchema))})
+		return op
+	}())
+
+	pluginParam := &openapi3.ParameterRef{Value: &openapi3.Parameter{Name: "plugin", In: openapi3.ParameterInPath, Required: true, Schema: openapi3.NewSchemaRef("", openapi3.NewStringSchema())}}
+	spec.AddOperation("/api/v1/plugins/{plugin}", http.MethodGet, func() *openapi3.Operation {
+		op := openapi3.NewOperation()
+		op.Summary = "Get plugin"
+		op.OperationID = "getPlugin"
+		op.Tags = []string{"plugins"}
+		op.Parameters = openapi3.Parameters{pluginParam}
+		op.Responses = openapi3.NewResponses()
+		{
+			resp := openapi3.NewResponse().WithDescription("Plugin manifest")
+			resp.Content = openapi3.NewContentWithJSONSchemaRef(manifestRef)
+			op.Responses.Set("200", &openapi3.ResponseRef{Value: resp})
+		}
+		return op
+	}())
+
+	spec.AddOperation("/api/v1/plugins/{plugin}", http.MethodDelete, func() *openapi3.Operation {
+		op := openapi3.NewOperation()
+		op.Summary = "Remove plugin"
+		op.OperationID = "removePlugin"
+		op.Tags = []string{"plugins"}
+		op.Parameters = openapi3.Parameters{pluginParam}
+		op.Responses = openapi3.NewResponses()
+		op.Responses.Set("204", &openapi3.ResponseRef{Value: openapi3.NewResponse().WithDescription("Plugin removed")})
+		return op
+	}())
+
+	spec.AddOperation("/api/v1/plugins/{plugin}/enabled", http.MethodPost, func() *openapi3.Operation {
+		op := openapi3.NewOperation()
+		op.Summary = "Set plugin enabled status"
+		op.OperationID = "setPluginEnabled"
+		op.Tags = []string{"plugins"}
+		op.Parameters = openapi3.Parameters{pluginParam}
+		enabledSchema := openapi3.NewObjectSchema()
+		enabledSchema.Properties = map[string]*openapi3.SchemaRef{
+			"enabled": openapi3.NewSchemaRef("", openapi3.NewBoolSchema()),
+		}
+		op.RequestBody = &openapi3.RequestBodyRef{Value: &openapi3.RequestBody{Required: true, Content: openapi3.NewContentWithJSONSchema(enabledSchema)}}
+		op.Responses = openapi3.NewResponses()
+		op.Responses.Set("200", &openapi3.ResponseRef{Value: openapi3.NewResponse().WithDescription("Status updated")})
+		return op
+	}())
+
+	// Plugin artifacts
+	spec.AddOperation("/api/v1/plugins/{plugin}/artifacts", http.MethodGet, func() *openapi3.Operation {
+		op := openapi3.NewOperation()
+		op.Summary = "List plugin artifacts"
+		op.OperationID = "listPluginArtifacts"
+		op.Tags = []string{"plugins", "artifacts"}
+		op.Parameters = openapi3.Parameters{
+			pluginParam,
+			&openapi3.ParameterRef{Value: &openapi3.Parameter{Name: "version", In: openapi3.ParameterInQuery, Description: "Filter by version", Schema: openapi3.NewSchemaRef("", openapi3.NewStringSchema())}},
+		}
+		op.Responses = openapi3.NewResponses()
+		{
+			resp := openapi3.NewResponse().WithDescription("Artifacts list")
+			arr := &openapi3.Schema{Type: &openapi3.Types{openapi3.TypeArray}, Items: pluginArtifactRef}
 			resp.Content = openapi3.NewContentWithJSONSchema(arr)
 			op.Responses.Set("200", &openapi3.ResponseRef{Value: resp})
 		}
@@ -721,13 +2157,18 @@ func BuildOpenAPISpec(baseURL string) (*openapi3.T, error) {
 	spec.AddOperation("/ws/v1/vms/{name}/console", http.MethodGet, func() *openapi3.Operation {
 		op := openapi3.NewOperation()
 		op.Summary = "WebSocket console for VM"
-		op.Description = "Upgrades to a WebSocket connection streaming the VM serial console"
+		op.Description = "Upgrades to a WebSocket connection streaming the VM serial console. When VOLANT_CONSOLE_KEYS is set a console key is required; sessions are recorded in the audit trail."
 		op.OperationID = "vmConsoleWebSocket"
 		op.Tags = []string{"vm", "console"}
-		op.Parameters = openapi3.Parameters{nameParam}
+		op.Parameters = openapi3.Parameters{
+			nameParam,
+			&openapi3.ParameterRef{Value: &openapi3.Parameter{Name: "X-Volant-Console-Key", In: openapi3.ParameterInHeader, Description: "Console key granting the console permission", Schema: openapi3.NewSchemaRef("", openapi3.NewStringSchema())}},
+			&openapi3.ParameterRef{Value: &openapi3.Parameter{Name: "console_key", In: openapi3.ParameterInQuery, Description: "Console key for clients that cannot set headers", Schema: openapi3.NewSchemaRef("", openapi3.NewStringSchema())}},
+		}
 		op.Responses = openapi3.NewResponses()
 		op.Responses.Set("101", &openapi3.ResponseRef{Value: openapi3.NewResponse().WithDescription("Switching Protocols (WebSocket)")})
 		op.Responses.Set("200", &openapi3.ResponseRef{Value: openapi3.NewResponse().WithDescription("OK (non-upgrade)")})
+		op.Responses.Set("403", &openapi3.ResponseRef{Value: openapi3.NewResponse().WithDescription("Console disabled or permission missing").WithContent(openapi3.NewContentWithJSONSchemaRef(errorSchema))})
 		return op
 	}())
 
@@ -747,6 +2188,68 @@ func BuildOpenAPISpec(baseURL string) (*openapi3.T, error) {
 		return op
 	}())
 
+	// /api/v1/transactions
+	txReqRef, _ := gen.NewSchemaRefForValue(&transactionRequest{}, spec.Components.Schemas)
+	txRespRef, _ := gen.NewSchemaRefForValue(&transactionResponse{}, spec.Components.Schemas)
+	spec.AddOperation("/api/v1/transactions", http.MethodPost, func() *openapi3.Operation {
+		op := openapi3.NewOperation()
+		op.Summary = "Apply a transaction"
+		op.Description = "Applies up to 16 dependent steps in order (plugin.install, network.create, config_bundle.create, deployment.create, route.upsert). Each spec is the body of that operation's own endpoint. If a step fails, the applied steps are rolled back in reverse order and the response carries the failing step's status code."
+		op.OperationID = "applyTransaction"
+		op.Tags = []string{"transactions"}
+		op.RequestBody = &openapi3.RequestBodyRef{Value: &openapi3.RequestBody{Required: true, Content: openapi3.NewContentWithJSONSchemaRef(txReqRef)}}
+		op.Responses = openapi3.NewResponses()
+		{
+			resp := openapi3.NewResponse().WithDescription("Transaction committed")
+			resp.Content = openapi3.NewContentWithJSONSchemaRef(txRespRef)
+			op.Responses.Set("200", &openapi3.ResponseRef{Value: resp})
+		}
+		op.Responses.Set("400", &openapi3.ResponseRef{Value: openapi3.NewResponse().WithDescription("Malformed step; nothing applied").WithContent(openapi3.NewContentWithJSONSchemaRef(errorSchema))})
+		op.Responses.Set("default", &openapi3.ResponseRef{Value: openapi3.NewResponse().WithDescription("Transaction rolled back").WithContent(openapi3.NewContentWithJSONSchemaRef(txRespRef))})
+		return op
+	}())
+
+	// /api/v1/apply
+	applyReqRef, _ := gen.NewSchemaRefForValue(&applyRequest{}, spec.Components.Schemas)
+	applyRespRef, _ := gen.NewSchemaRefForValue(&applyResponse{}, spec.Components.Schemas)
+	spec.AddOperation("/api/v1/apply", http.MethodPost, func() *openapi3.Operation {
+		op := openapi3.NewOperation()
+		op.Summary = "Apply declared resources"
+		op.Description = "Creates or updates up to 64 plugins, VMs and deployments, matched by kind and name. A plugin spec is its manifest; a VM spec is {config, labels, restart}; a deployment spec is {replicas, max_surge, max_unavailable, config}. Only the fields a spec declares are compared, and unchanged resources are left alone. Every resource is planned first; with dry_run the plan is returned without changing anything. Resources are applied in order, plugins first, stopping at the first failure without rolling back."
+		op.OperationID = "applyResources"
+		op.Tags = []string{"apply"}
+		op.RequestBody = &openapi3.RequestBodyRef{Value: &openapi3.RequestBody{Required: true, Content: openapi3.NewContentWithJSONSchemaRef(applyReqRef)}}
+		op.Responses = openapi3.NewResponses()
+		{
+			resp := openapi3.NewResponse().WithDescription("Resources applied, or planned with dry_run")
+			resp.Content = openapi3.NewContentWithJSONSchemaRef(applyRespRef)
+			op.Responses.Set("200", &openapi3.ResponseRef{Value: resp})
+		}
+		op.Responses.Set("400", &openapi3.ResponseRef{Value: openapi3.NewResponse().WithDescription("Malformed request; nothing applied").WithContent(openapi3.NewContentWithJSONSchemaRef(errorSchema))})
+		op.Responses.Set("default", &openapi3.ResponseRef{Value: openapi3.NewResponse().WithDescription("A resource is invalid or failed to apply; its result carries the error").WithContent(openapi3.NewContentWithJSONSchemaRef(applyRespRef))})
+		return op
+	}())
+
+	// /api/v1/bulk/vms/{action}
+	bulkReqRef, _ := gen.NewSchemaRefForValue(&bulkVMRequest{}, spec.Components.Schemas)
+	bulkRespRef, _ := gen.NewSchemaRefForValue(&bulkVMResponse{}, spec.Components.Schemas)
+	spec.AddOperation("/api/v1/bulk/vms/{action}", http.MethodPost, func() *openapi3.Operation {
+		op := openapi3.NewOperation()
+		op.Summary = "Run an action on VMs matching a label selector"
+		op.Description = "Starts, stops, restarts or deletes every VM matching the selector, given as a query parameter or in the body. An empty selector is rejected. Each VM is handled independently and reported in results."
+		op.OperationID = "bulkVMAction"
+		op.Tags = []string{"vm"}
+		op.Parameters = openapi3.Parameters{
+			&openapi3.ParameterRef{Value: &openapi3.Parameter{Name: "action", In: openapi3.ParameterInPath, Required: true, Schema: openapi3.NewSchemaRef("", openapi3.NewStringSchema().WithEnum("start", "stop", "restart", "delete"))}},
+			&openapi3.ParameterRef{Value: openapi3.NewQueryParameter("selector").WithSchema(openapi3.NewStringSchema()).WithDescription("Label selector")},
+		}
+		op.RequestBody = &openapi3.RequestBodyRef{Value: &openapi3.RequestBody{Content: openapi3.NewContentWithJSONSchemaRef(bulkReqRef)}}
+		op.Responses = openapi3.NewResponses()
+		op.Responses.Set("200", &openapi3.ResponseRef{Value: openapi3.NewResponse().WithDescription("Per-VM results").WithContent(openapi3.NewContentWithJSONSchemaRef(bulkRespRef))})
+		op.Responses.Set("400", &openapi3.ResponseRef{Value: openapi3.NewResponse().WithDescription("Invalid action or selector").WithContent(openapi3.NewContentWithJSONSchemaRef(errorSchema))})
+		return op
+	}())
+
 	// /api/v1/events/vms
 	spec.AddOperation("/api/v1/events/vms", http.MethodGet, func() *openapi3.Operation {
 		op := openapi3.NewOperation()
@@ -764,6 +2267,42 @@ func BuildOpenAPISpec(baseURL string) (*openapi3.T, error) {
 		return op
 	}())
 
+	// /ws/v1/events
+	spec.AddOperation("/ws/v1/events", http.MethodGet, func() *openapi3.Operation {
+		op := openapi3.NewOperation()
+		op.Summary = "Stream all events over WebSocket"
+		op.Description = "Upgrades to a WebSocket streaming VM lifecycle, deployment, plugin and schedule events as JSON envelopes {topic, type, name, timestamp, data}. The server pings every 30s and drops clients that stop answering. VM_LOG events are only sent when requested via type."
+		op.OperationID = "eventsWebSocket"
+		op.Tags = []string{"events"}
+		op.Parameters = openapi3.Parameters{
+			&openapi3.ParameterRef{Value: &openapi3.Parameter{Name: "vm", In: openapi3.ParameterInQuery, Description: "Only events for these VMs (comma-separated or repeated)", Schema: openapi3.NewSchemaRef("", openapi3.NewStringSchema())}},
+			&openapi3.ParameterRef{Value: &openapi3.Parameter{Name: "type", In: openapi3.ParameterInQuery, Description: "Only these event types, e.g. VM_RUNNING,DEPLOYMENT_RECONCILED", Schema: openapi3.NewSchemaRef("", openapi3.NewStringSchema())}},
+			&openapi3.ParameterRef{Value: &openapi3.Parameter{Name: "topic", In: openapi3.ParameterInQuery, Description: "Only these topics: vm, deployment, plugin, schedule", Schema: openapi3.NewSchemaRef("", openapi3.NewStringSchema())}},
+		}
+		op.Responses = openapi3.NewResponses()
+		op.Responses.Set("101", &openapi3.ResponseRef{Value: openapi3.NewResponse().WithDescription("Switching Protocols (WebSocket)")})
+		op.Responses.Set("503", &openapi3.ResponseRef{Value: openapi3.NewResponse().WithDescription("Event streaming not available").WithContent(openapi3.NewContentWithJSONSchemaRef(errorSchema))})
+		return op
+	}())
+
+	// /ws/v1/vms/{name}/watch
+	spec.AddOperation("/ws/v1/vms/{name}/watch", http.MethodGet, func() *openapi3.Operation {
+		op := openapi3.NewOperation()
+		op.Summary = "Watch one VM over WebSocket"
+		op.Description = "Upgrades to a WebSocket multiplexing one VM's channels as JSON messages {channel, type, name, timestamp, data}. status and config open with a SNAPSHOT, then carry VM events and VM_CONFIG_UPDATED with the new versioned config; heartbeat reports status and config version every 15s; logs follows the agent's log stream across restarts. The stream closes after VM_DELETED."
+		op.OperationID = "watchVMWebSocket"
+		op.Tags = []string{"events", "vm"}
+		op.Parameters = openapi3.Parameters{
+			nameParam,
+			&openapi3.ParameterRef{Value: &openapi3.Parameter{Name: "channels", In: openapi3.ParameterInQuery, Description: "Channels to stream: status, config, heartbeat, logs (comma-separated or repeated; default all but logs)", Schema: openapi3.NewSchemaRef("", openapi3.NewStringSchema())}},
+		}
+		op.Responses = openapi3.NewResponses()
+		op.Responses.Set("101", &openapi3.ResponseRef{Value: openapi3.NewResponse().WithDescription("Switching Protocols (WebSocket)")})
+		op.Responses.Set("400", &openapi3.ResponseRef{Value: openapi3.NewResponse().WithDescription("Unknown channel").WithContent(openapi3.NewContentWithJSONSchemaRef(errorSchema))})
+		op.Responses.Set("404", &openapi3.ResponseRef{Value: openapi3.NewResponse().WithDescription("Not found").WithContent(openapi3.NewContentWithJSONSchemaRef(errorSchema))})
+		return op
+	}())
+
 	// Register VFIO request/response schemas
 	vfioDeviceInfoReqRef, _ := gen.NewSchemaRefForValue(&vfioDeviceInfoRequest{}, spec.Components.Schemas)
 	vfioDeviceInfoRespRef, _ := gen.NewSchemaRefForValue(&vfioDeviceInfoResponse{}, spec.Components.Schemas)
@@ -900,6 +2439,7 @@ func BuildOpenAPISpec(baseURL string) (*openapi3.T, error) {
 			resp.Content = openapi3.NewContentWithJSONSchemaRef(errorSchema)
 			op.Responses.Set("400", &openapi3.ResponseRef{Value: resp})
 		}
+		op.Responses.Set("409", &openapi3.ResponseRef{Value: openapi3.NewResponse().WithDescription("Device is assigned to a VM").WithContent(openapi3.NewContentWithJSONSchemaRef(errorSchema))})
 		{
 			resp := openapi3.NewResponse().WithDescription("Internal error")
 			resp.Content = openapi3.NewContentWithJSONSchemaRef(errorSchema)
@@ -935,5 +2475,129 @@ func BuildOpenAPISpec(baseURL string) (*openapi3.T, error) {
 		return op
 	}())
 
+	// /api/v1/vfio/assignments
+	vfioAssignmentRespRef, _ := gen.NewSchemaRefForValue(&vfioAssignmentResponse{}, spec.Components.Schemas)
+	spec.AddOperation("/api/v1/vfio/assignments", http.MethodGet, func() *openapi3.Operation {
+		op := openapi3.NewOperation()
+		op.Summary = "List VFIO device assignments"
+		op.Description = "PCI devices currently passed through to VMs. A device, or another device in its IOMMU group, cannot be given to a second VM while assigned."
+		op.OperationID = "listVFIOAssignments"
+		op.Tags = []string{"vfio"}
+		op.Responses = openapi3.NewResponses()
+		{
+			resp := openapi3.NewResponse().WithDescription("Array of device assignments")
+			arr := &openapi3.Schema{Type: &openapi3.Types{openapi3.TypeArray}, Items: vfioAssignmentRespRef}
+			resp.Content = openapi3.NewContentWithJSONSchema(arr)
+			op.Responses.Set("200", &openapi3.ResponseRef{Value: resp})
+		}
+		return op
+	}())
+
+	// Requests with parameters or a body are validated against this spec.
+	for _, item := range spec.Paths.Map() {
+		for _, op := range item.Operations() {
+			if len(op.Parameters) == 0 && op.RequestBody == nil {
+				continue
+			}
+			if op.Responses.Value("422") == nil {
+				op.Responses.Set("422", &openapi3.ResponseRef{Value: openapi3.NewResponse().WithDescription("Request failed validation; details.errors lists each field").WithContent(openapi3.NewContentWithJSONSchemaRef(errorSchema))})
+			}
+		}
+	}
+
 	return spec, nil
 }
+
+// customizeSchema carries the request rules of Go types into their generated
+// schemas: fields tagged binding:"required" are required, and DeviceConfig
+// also accepts the bare list of requests its UnmarshalJSON allows.
+func customizeSchema(_ string, t reflect.Type, _ reflect.StructTag, schema *openapi3.Schema) error {
+	if t.Kind() != reflect.Struct {
+		return nil
+	}
+	if t == reflect.TypeOf(pluginspec.DeviceConfig{}) {
+		// Devices may also be given as a bare list of requests. The
+		// properties stay on the schema so it is still exported as a
+		// component; they only constrain the object form.
+		if requests := schema.Properties["requests"]; requests != nil {
+			schema.Type = nil
+			schema.OneOf = openapi3.SchemaRefs{openapi3.NewObjectSchema().NewRef(), requests}
+		}
+		return nil
+	}
+	if t == reflect.TypeOf(mcp.Request{}) || t == reflect.TypeOf(mcp.Response{}) {
+		// JSON-RPC ids are strings or numbers and params any object;
+		// malformed messages are answered with JSON-RPC errors, not 422.
+		for _, name := range []string{"id", "params"} {
+			if _, ok := schema.Properties[name]; ok {
+				schema.Properties[name] = openapi3.NewSchemaRef("", &openapi3.Schema{})
+			}
+		}
+	}
+	if t == reflect.TypeOf(createWebhookRequest{}) {
+		if events := schema.Properties["events"]; events != nil && events.Value != nil && events.Value.Items != nil {
+			for _, eventType := range webhooks.EventTypes() {
+				events.Value.Items.Value.Enum = append(events.Value.Items.Value.Enum, eventType)
+			}
+		}
+	}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !slices.Contains(strings.Split(field.Tag.Get("binding"), ","), "required") {
+			continue
+		}
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "" || name == "-" {
+			continue
+		}
+		schema.Required = append(schema.Required, name)
+		// binding:"required" also rejects empty strings.
+		if prop := schema.Properties[name]; prop != nil && prop.Value != nil && prop.Value.Type.Is(openapi3.TypeString) {
+			prop.Value.MinLength = 1
+		}
+	}
+	return nil
+}
+
+// schemaNames renames component schemas whose Go names are too generic
+// to stand on their own in generated clients.
+var schemaNames = map[reflect.Type]string{
+	reflect.TypeOf(vmconfig.Config{}):       "VMConfig",
+	reflect.TypeOf(vmconfig.Versioned{}):    "VersionedVMConfig",
+	reflect.TypeOf(vmconfig.HistoryEntry{}): "VMConfigHistoryEntry",
+	reflect.TypeOf(vmconfig.Patch{}):        "VMConfigPatch",
+	reflect.TypeOf(routes.Backend{}):        "RouteBackend",
+	reflect.TypeOf(routes.L7{}):             "RouteL7",
+	reflect.TypeOf(routes.L7Rule{}):         "RouteL7Rule",
+	reflect.TypeOf(routes.L7Target{}):       "RouteL7Target",
+	reflect.TypeOf(routes.HealthCheck{}):    "RouteHealthCheck",
+}
+
+// schemaInitialisms are the leading words of unexported type names that
+// schemaName upper-cases whole.
+var schemaInitialisms = map[string]bool{"api": true, "dhcp": true, "gc": true, "ssh": true, "vfio": true, "vm": true}
+
+// schemaName names the component schema of a Go type. Unexported request
+// and response types are exported (vmResponse becomes VMResponse) so
+// generated clients get usable type names, and the JSON-RPC types of the
+// mcp package are prefixed to tell them from other requests.
+func schemaName(t reflect.Type) string {
+	if name, ok := schemaNames[t]; ok {
+		return name
+	}
+	name := t.Name()
+	if strings.HasSuffix(t.PkgPath(), "/mcp") {
+		name = "MCP" + name
+	}
+	head := strings.IndexFunc(name, unicode.IsUpper)
+	switch {
+	case head == 0:
+		return name
+	case head < 0:
+		head = len(name)
+	}
+	if schemaInitialisms[name[:head]] {
+		return strings.ToUpper(name[:head]) + name[head:]
+	}
+	return strings.ToUpper(name[:1]) + name[1:]
+}
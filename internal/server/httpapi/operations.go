@@ -0,0 +1,80 @@
+// Copyright (c) 2025 HYPR. PTE. LTD.
+//
+// Business Source License 1.1
+// See LICENSE file in the project root for details.
+
+package httpapi
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/volantvm/volant/internal/server/db"
+)
+
+type operationResponse struct {
+	ID          string     `json:"id"`
+	Kind        string     `json:"kind"`
+	Target      string     `json:"target"`
+	Status      string     `json:"status"`
+	Done        bool       `json:"done"`
+	Error       string     `json:"error,omitempty"`
+	Code        string     `json:"code,omitempty"`
+	Hint        string     `json:"hint,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+}
+
+func operationToResponse(op db.Operation) operationResponse {
+	return operationResponse{
+		ID:          op.ID,
+		Kind:        op.Kind,
+		Target:      op.Target,
+		Status:      string(op.Status),
+		Done:        op.CompletedAt != nil,
+		Error:       op.Error,
+		Code:        op.Code,
+		Hint:        op.Hint,
+		CreatedAt:   op.CreatedAt,
+		UpdatedAt:   op.UpdatedAt,
+		CompletedAt: op.CompletedAt,
+	}
+}
+
+func operationLocation(id string) string {
+	return "/api/v1/operations/" + id
+}
+
+// wantsAsync reports whether the client asked for a 202 and an operation
+// instead of waiting: via the body flag, ?async=true, or the RFC 7240
+// "Prefer: respond-async" header.
+func wantsAsync(c *gin.Context, body bool) bool {
+	if body {
+		return true
+	}
+	if raw := strings.TrimSpace(c.Query("async")); raw != "" {
+		if async, err := strconv.ParseBool(raw); err == nil {
+			return async
+		}
+	}
+	for _, pref := range strings.Split(c.GetHeader("Prefer"), ",") {
+		if strings.EqualFold(strings.TrimSpace(pref), "respond-async") {
+			return true
+		}
+	}
+	return false
+}
+
+func (api *apiServer) getOperation(c *gin.Context) {
+	op, err := api.engine.GetOperation(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		c.JSON(statusFromError(err), errorResponse(err))
+		return
+	}
+	c.JSON(http.StatusOK, operationToResponse(*op))
+}
@@ -0,0 +1,91 @@
+// Copyright (c) 2025 HYPR. PTE. LTD.
+//
+// Business Source License 1.1
+// See LICENSE file in the project root for details.
+
+package httpapi
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/volantvm/volant/internal/server/db"
+)
+
+// streamChunkSize is the most a streaming action relays between flushes.
+const streamChunkSize = 32 << 10
+
+// streamAgentAction runs a streaming plugin action in vm's agent and relays
+// the response as it arrives, so chunked or SSE progress reaches the caller
+// while the action runs. A policy timeout bounds the whole action.
+func (api *apiServer) streamAgentAction(c *gin.Context, vm *db.VM, method, path string, body any, policy agentCallPolicy) {
+	var buf bytes.Buffer
+	if body != nil {
+		if err := json.NewEncoder(&buf).Encode(body); err != nil {
+			respondError(c, http.StatusInternalServerError, CodeInternal, "failed to encode request")
+			return
+		}
+	}
+
+	target := api.agentURL(vm, path)
+	clearDeadlines(c)
+	resp, err := api.doAgent(c.Request.Context(), policy, true, func(ctx context.Context) (*http.Request, error) {
+		req, err := http.NewRequestWithContext(ctx, method, target, bytes.NewReader(buf.Bytes()))
+		if err != nil {
+			return nil, err
+		}
+		if body != nil {
+			req.Header.Set("Content-Type", "application/json")
+		}
+		if accept := c.GetHeader("Accept"); accept != "" {
+			req.Header.Set("Accept", accept)
+		}
+		return req, nil
+	})
+	if err != nil {
+		api.logger.Error("agent stream action", "vm", vm.Name, "path", path, "error", err)
+		respondAgentCallError(c, err)
+		return
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		_ = respondAgentError(c, resp)
+		return
+	}
+
+	rc := http.NewResponseController(c.Writer)
+	for _, key := range []string{"Content-Type", "Cache-Control"} {
+		if value := resp.Header.Get(key); value != "" {
+			c.Header(key, value)
+		}
+	}
+	// Ask reverse proxies in front of volantd not to buffer the stream.
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(resp.StatusCode)
+	c.Writer.WriteHeaderNow()
+	_ = rc.Flush()
+
+	chunk := make([]byte, streamChunkSize)
+	for {
+		n, err := resp.Body.Read(chunk)
+		if n > 0 {
+			if _, werr := c.Writer.Write(chunk[:n]); werr != nil {
+				return
+			}
+			_ = rc.Flush()
+		}
+		if err != nil {
+			if !errors.Is(err, io.EOF) && c.Request.Context().Err() == nil {
+				// Headers are out, so the caller only sees the stream end.
+				api.logger.Warn("agent stream action", "vm", vm.Name, "path", path, "error", err)
+			}
+			return
+		}
+	}
+}
@@ -0,0 +1,215 @@
+// Copyright (c) 2025 HYPR. PTE. LTD.
+//
+// Business Source License 1.1
+// See LICENSE file in the project root for details.
+
+package httpapi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/volantvm/volant/internal/pluginspec"
+	"github.com/volantvm/volant/internal/server/orchestrator"
+	orchestratorevents "github.com/volantvm/volant/internal/server/orchestrator/events"
+)
+
+type pluginVersionResponse struct {
+	Version     string    `json:"version"`
+	Active      bool      `json:"active"`
+	InstalledAt time.Time `json:"installed_at"`
+}
+
+type pluginVMVersionResponse struct {
+	VM         string `json:"vm"`
+	Deployment string `json:"deployment,omitempty"`
+	Version    string `json:"version"`
+}
+
+type pluginVersionsResponse struct {
+	Plugin   string                    `json:"plugin"`
+	Active   string                    `json:"active"`
+	Versions []pluginVersionResponse   `json:"versions"`
+	VMs      []pluginVMVersionResponse `json:"vms"`
+	// Drift names the VMs still running a version other than the active one.
+	Drift []string `json:"drift"`
+}
+
+type upgradePluginRequest struct {
+	Version string `json:"version" binding:"required"`
+	// RollDeployments rolls every deployment of the plugin onto the new
+	// version; standalone VMs are left alone and reported as drift.
+	RollDeployments bool `json:"roll_deployments"`
+}
+
+type pluginUpgradeResponse struct {
+	Plugin    string            `json:"plugin"`
+	From      string            `json:"from"`
+	To        string            `json:"to"`
+	Restarted []string          `json:"restarted,omitempty"`
+	Skipped   map[string]string `json:"skipped,omitempty"`
+	Drift     []string          `json:"drift"`
+}
+
+// GET /api/v1/plugins/:plugin/versions
+func (api *apiServer) listPluginVersions(c *gin.Context) {
+	if api.plugins == nil {
+		respondError(c, http.StatusServiceUnavailable, CodeUnavailable, "plugin registry unavailable")
+		return
+	}
+	name := c.Param("plugin")
+	active, ok := api.plugins.Get(name)
+	if !ok {
+		respondError(c, http.StatusNotFound, CodePluginNotFound, "plugin not found")
+		return
+	}
+	store := api.engine.Store()
+	if store == nil {
+		respondError(c, http.StatusInternalServerError, CodeInternal, "store not configured")
+		return
+	}
+	records, err := store.Queries().PluginVersions().ListByPlugin(c.Request.Context(), name)
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, CodeInternal, err.Error())
+		return
+	}
+	vms, err := api.engine.PluginVMVersions(c.Request.Context(), name)
+	if err != nil {
+		c.JSON(statusFromError(err), errorResponse(err))
+		return
+	}
+
+	resp := pluginVersionsResponse{
+		Plugin:   name,
+		Active:   active.Version,
+		Versions: make([]pluginVersionResponse, 0, len(records)),
+		VMs:      make([]pluginVMVersionResponse, 0, len(vms)),
+		Drift:    pluginDrift(vms, active.Version),
+	}
+	for _, record := range records {
+		resp.Versions = append(resp.Versions, pluginVersionResponse{
+			Version:     record.Version,
+			Active:      record.Version == active.Version,
+			InstalledAt: record.InstalledAt,
+		})
+	}
+	for _, vm := range vms {
+		resp.VMs = append(resp.VMs, pluginVMVersionResponse{VM: vm.VM, Deployment: vm.Deployment, Version: vm.Version})
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// POST /api/v1/plugins/:plugin/upgrade
+func (api *apiServer) upgradePlugin(c *gin.Context) {
+	if api.plugins == nil {
+		respondError(c, http.StatusServiceUnavailable, CodeUnavailable, "plugin registry unavailable")
+		return
+	}
+	name := c.Param("plugin")
+	current, ok := api.plugins.Get(name)
+	if !ok {
+		respondError(c, http.StatusNotFound, CodePluginNotFound, "plugin not found")
+		return
+	}
+	var req upgradePluginRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, http.StatusBadRequest, CodeInvalidRequest, err.Error())
+		return
+	}
+
+	ctx := c.Request.Context()
+	target, err := api.pluginVersion(ctx, name, strings.TrimSpace(req.Version))
+	if err != nil {
+		api.logger.Error("load plugin version", "plugin", name, "version", req.Version, "error", err)
+		respondError(c, http.StatusInternalServerError, CodeInternal, err.Error())
+		return
+	}
+	if target == nil {
+		respondError(c, http.StatusNotFound, CodePluginVersionNotFound, fmt.Sprintf("plugin %s version %s not installed", name, req.Version))
+		return
+	}
+	if err := target.Validate(); err != nil {
+		respondError(c, http.StatusBadRequest, CodeInvalidRequest, err.Error())
+		return
+	}
+	if err := api.engine.CheckHostFeatures(target); err != nil {
+		c.JSON(statusFromError(err), errorResponse(err))
+		return
+	}
+
+	if err := api.persistPluginManifest(ctx, *target, current.Enabled); err != nil {
+		api.logger.Error("upgrade plugin", "plugin", name, "error", err)
+		respondError(c, http.StatusInternalServerError, CodeInternal, err.Error())
+		return
+	}
+	target.Enabled = current.Enabled
+	api.plugins.Register(*target)
+	api.publishPluginEvent(ctx, orchestratorevents.TypePluginUpgraded, name, target.Version, target.Enabled)
+
+	resp := pluginUpgradeResponse{Plugin: name, From: current.Version, To: target.Version}
+	status := http.StatusOK
+	if req.RollDeployments {
+		rollout, err := api.engine.RollPluginUpgrade(ctx, *target)
+		if err != nil {
+			api.logger.Error("roll plugin upgrade", "plugin", name, "error", err)
+			c.JSON(statusFromError(err), errorResponse(err))
+			return
+		}
+		resp.Restarted = rollout.Restarted
+		resp.Skipped = rollout.Skipped
+		status = http.StatusAccepted
+	}
+	vms, err := api.engine.PluginVMVersions(ctx, name)
+	if err != nil {
+		c.JSON(statusFromError(err), errorResponse(err))
+		return
+	}
+	resp.Drift = pluginDrift(vms, target.Version)
+	c.JSON(status, resp)
+}
+
+// pluginVersion returns the stored manifest of an installed plugin version,
+// or nil when that version is not installed.
+func (api *apiServer) pluginVersion(ctx context.Context, name, version string) (*pluginspec.Manifest, error) {
+	store := api.engine.Store()
+	if store == nil {
+		return nil, fmt.Errorf("store not configured")
+	}
+	record, err := store.Queries().PluginVersions().Get(ctx, name, version)
+	if err != nil || record == nil {
+		return nil, err
+	}
+	manifest := pluginspec.Manifest{Name: record.PluginName, Version: record.Version}
+	if len(record.Metadata) > 0 {
+		if err := json.Unmarshal(record.Metadata, &manifest); err != nil {
+			return nil, fmt.Errorf("decode plugin %s version %s: %w", name, version, err)
+		}
+	}
+	manifest.Normalize()
+	return &manifest, nil
+}
+
+func (api *apiServer) dropPluginVersion(ctx context.Context, name, version string) error {
+	store := api.engine.Store()
+	if store == nil {
+		return fmt.Errorf("store not configured")
+	}
+	return store.Queries().PluginVersions().Delete(ctx, name, version)
+}
+
+// pluginDrift names the VMs whose config runs a version other than active.
+func pluginDrift(vms []orchestrator.PluginVMVersion, active string) []string {
+	drift := []string{}
+	for _, vm := range vms {
+		if vm.Version != active {
+			drift = append(drift, vm.VM)
+		}
+	}
+	return drift
+}
@@ -0,0 +1,135 @@
+// Copyright (c) 2025 HYPR. PTE. LTD.
+//
+// Business Source License 1.1
+// See LICENSE file in the project root for details.
+
+package httpapi
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/volantvm/volant/internal/drift/routes"
+	"github.com/volantvm/volant/internal/server/orchestrator"
+)
+
+// routeResponse is a drift route and, when it is published for a running
+// VM's expose rules, that VM.
+type routeResponse struct {
+	routes.Route
+	VM string `json:"vm,omitempty"`
+	// Published is false for routes a running VM's expose rules call for
+	// but driftd does not hold; POST /api/v1/routes/sync publishes them.
+	Published bool `json:"published"`
+}
+
+func routeKey(protocol string, hostPort uint16) string {
+	return strings.ToLower(protocol) + "/" + strconv.FormatUint(uint64(hostPort), 10)
+}
+
+// listRoutes lists the routes driftd holds, marked with the VMs that own
+// them, followed by the VM routes it is missing.
+func (api *apiServer) listRoutes(c *gin.Context) {
+	if !api.ensureDriftAvailable(c) {
+		return
+	}
+	ctx := c.Request.Context()
+	items, err := api.drift.ListRoutes(ctx)
+	if err != nil {
+		api.respondDriftError(c, err)
+		return
+	}
+	owned, err := api.engine.VMRoutes(ctx)
+	if err != nil {
+		c.JSON(statusFromError(err), errorResponse(err))
+		return
+	}
+	owners := make(map[string]orchestrator.VMRoute, len(owned))
+	for _, route := range owned {
+		owners[routeKey(route.Route.Protocol, route.Route.HostPort)] = route
+	}
+
+	vm := strings.TrimSpace(c.Query("vm"))
+	resp := make([]routeResponse, 0, len(items))
+	for _, item := range items {
+		key := routeKey(item.Protocol, item.HostPort)
+		owner := owners[key].VM
+		delete(owners, key)
+		if vm != "" && owner != vm {
+			continue
+		}
+		resp = append(resp, routeResponse{Route: item, VM: owner, Published: true})
+	}
+	for _, route := range owned {
+		if _, missing := owners[routeKey(route.Route.Protocol, route.Route.HostPort)]; !missing || (vm != "" && route.VM != vm) {
+			continue
+		}
+		resp = append(resp, routeResponse{Route: route.Route, VM: route.VM})
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+func (api *apiServer) upsertRoute(c *gin.Context) {
+	if !api.ensureDriftAvailable(c) {
+		return
+	}
+	var route routes.Route
+	if err := c.ShouldBindJSON(&route); err != nil {
+		respondError(c, http.StatusBadRequest, CodeInvalidRequest, err.Error())
+		return
+	}
+	if err := api.engine.CheckRouteChange(c.Request.Context(), route.Protocol, route.HostPort); err != nil {
+		c.JSON(statusFromError(err), errorResponse(err))
+		return
+	}
+	updated, err := api.drift.UpsertRoute(c.Request.Context(), route)
+	if err != nil {
+		api.respondDriftError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, updated)
+}
+
+func (api *apiServer) deleteRoute(c *gin.Context) {
+	if !api.ensureDriftAvailable(c) {
+		return
+	}
+	protocol := c.Param("protocol")
+	port, err := strconv.ParseUint(c.Param("port"), 10, 16)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, CodeInvalidRequest, "invalid port")
+		return
+	}
+	if err := api.engine.CheckRouteChange(c.Request.Context(), protocol, uint16(port)); err != nil {
+		c.JSON(statusFromError(err), errorResponse(err))
+		return
+	}
+	if err := api.drift.DeleteRoute(c.Request.Context(), protocol, uint16(port)); err != nil {
+		api.respondDriftError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// syncRoutes publishes the routes of every running VM again, for when
+// driftd restarted without its routes or was down when a VM started.
+func (api *apiServer) syncRoutes(c *gin.Context) {
+	if !api.ensureDriftAvailable(c) {
+		return
+	}
+	published, err := api.engine.SyncRoutes(c.Request.Context())
+	if err != nil {
+		api.logger.Error("sync drift routes", "error", err)
+		api.respondDriftError(c, fmt.Errorf("sync routes: %w", err))
+		return
+	}
+	resp := make([]routeResponse, 0, len(published))
+	for _, route := range published {
+		resp = append(resp, routeResponse{Route: route.Route, VM: route.VM, Published: true})
+	}
+	c.JSON(http.StatusOK, resp)
+}
@@ -0,0 +1,205 @@
+// Copyright (c) 2025 HYPR. PTE. LTD.
+//
+// Business Source License 1.1
+// See LICENSE file in the project root for details.
+
+package httpapi
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/volantvm/volant/internal/server/db"
+	"github.com/volantvm/volant/internal/server/scheduler"
+)
+
+type createScheduleRequest struct {
+	Name       string `json:"name" binding:"required"`
+	Cron       string `json:"cron" binding:"required"`
+	Action     string `json:"action" binding:"required"`
+	TargetKind string `json:"target_kind" binding:"required"`
+	Target     string `json:"target" binding:"required"`
+	Replicas   *int   `json:"replicas,omitempty"`
+	Disabled   bool   `json:"disabled,omitempty"`
+}
+
+type scheduleResponse struct {
+	Name       string     `json:"name"`
+	Cron       string     `json:"cron"`
+	Action     string     `json:"action"`
+	TargetKind string     `json:"target_kind"`
+	Target     string     `json:"target"`
+	Replicas   *int       `json:"replicas,omitempty"`
+	Enabled    bool       `json:"enabled"`
+	LastRunAt  *time.Time `json:"last_run_at,omitempty"`
+	NextRunAt  *time.Time `json:"next_run_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+}
+
+type scheduleRunResponse struct {
+	Status     string    `json:"status"`
+	Message    string    `json:"message,omitempty"`
+	StartedAt  time.Time `json:"started_at"`
+	FinishedAt time.Time `json:"finished_at"`
+}
+
+func (api *apiServer) scheduleToResponse(schedule db.Schedule) scheduleResponse {
+	resp := scheduleResponse{
+		Name:       schedule.Name,
+		Cron:       schedule.Cron,
+		Action:     schedule.Action,
+		TargetKind: schedule.TargetKind,
+		Target:     schedule.Target,
+		Replicas:   schedule.Replicas,
+		Enabled:    schedule.Enabled,
+		LastRunAt:  schedule.LastRunAt,
+		CreatedAt:  schedule.CreatedAt,
+		UpdatedAt:  schedule.UpdatedAt,
+	}
+	if next := api.scheduler.NextRun(schedule); !next.IsZero() {
+		resp.NextRunAt = &next
+	}
+	return resp
+}
+
+func scheduleRunToResponse(run db.ScheduleRun) scheduleRunResponse {
+	return scheduleRunResponse{
+		Status:     run.Status,
+		Message:    run.Message,
+		StartedAt:  run.StartedAt,
+		FinishedAt: run.FinishedAt,
+	}
+}
+
+func (api *apiServer) ensureSchedulerAvailable(c *gin.Context) bool {
+	if api.scheduler == nil {
+		respondError(c, http.StatusServiceUnavailable, CodeUnavailable, "scheduler unavailable")
+		return false
+	}
+	return true
+}
+
+func (api *apiServer) listSchedules(c *gin.Context) {
+	if !api.ensureSchedulerAvailable(c) {
+		return
+	}
+	schedules, err := api.scheduler.List(c.Request.Context())
+	if err != nil {
+		api.logger.Error("list schedules", "error", err)
+		c.JSON(statusFromError(err), errorResponse(err))
+		return
+	}
+	resp := make([]scheduleResponse, 0, len(schedules))
+	for _, schedule := range schedules {
+		resp = append(resp, api.scheduleToResponse(schedule))
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+func (api *apiServer) createSchedule(c *gin.Context) {
+	if !api.ensureSchedulerAvailable(c) {
+		return
+	}
+	var req createScheduleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, http.StatusBadRequest, CodeInvalidRequest, err.Error())
+		return
+	}
+	schedule, err := api.scheduler.Create(c.Request.Context(), scheduler.CreateRequest{
+		Name:       req.Name,
+		Cron:       req.Cron,
+		Action:     req.Action,
+		TargetKind: req.TargetKind,
+		Target:     req.Target,
+		Replicas:   req.Replicas,
+		Disabled:   req.Disabled,
+	})
+	if err != nil {
+		api.logger.Error("create schedule", "schedule", req.Name, "error", err)
+		c.JSON(statusFromError(err), errorResponse(err))
+		return
+	}
+	c.JSON(http.StatusCreated, api.scheduleToResponse(*schedule))
+}
+
+func (api *apiServer) getSchedule(c *gin.Context) {
+	if !api.ensureSchedulerAvailable(c) {
+		return
+	}
+	name := c.Param("name")
+	schedule, err := api.scheduler.Get(c.Request.Context(), name)
+	if err != nil {
+		c.JSON(statusFromError(err), errorResponse(err))
+		return
+	}
+	c.JSON(http.StatusOK, api.scheduleToResponse(*schedule))
+}
+
+func (api *apiServer) deleteSchedule(c *gin.Context) {
+	if !api.ensureSchedulerAvailable(c) {
+		return
+	}
+	name := c.Param("name")
+	if err := api.scheduler.Delete(c.Request.Context(), name); err != nil {
+		api.logger.Error("delete schedule", "schedule", name, "error", err)
+		c.JSON(statusFromError(err), errorResponse(err))
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+func (api *apiServer) setScheduleEnabled(c *gin.Context) {
+	if !api.ensureSchedulerAvailable(c) {
+		return
+	}
+	name := c.Param("name")
+	var payload struct {
+		Enabled bool `json:"enabled"`
+	}
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		respondError(c, http.StatusBadRequest, CodeInvalidRequest, err.Error())
+		return
+	}
+	schedule, err := api.scheduler.SetEnabled(c.Request.Context(), name, payload.Enabled)
+	if err != nil {
+		api.logger.Error("toggle schedule", "schedule", name, "error", err)
+		c.JSON(statusFromError(err), errorResponse(err))
+		return
+	}
+	c.JSON(http.StatusOK, api.scheduleToResponse(*schedule))
+}
+
+func (api *apiServer) runSchedule(c *gin.Context) {
+	if !api.ensureSchedulerAvailable(c) {
+		return
+	}
+	name := c.Param("name")
+	run, err := api.scheduler.Trigger(c.Request.Context(), name)
+	if err != nil {
+		c.JSON(statusFromError(err), errorResponse(err))
+		return
+	}
+	c.JSON(http.StatusOK, scheduleRunToResponse(*run))
+}
+
+func (api *apiServer) getScheduleRuns(c *gin.Context) {
+	if !api.ensureSchedulerAvailable(c) {
+		return
+	}
+	name := c.Param("name")
+	limit := queryCount(c, "limit")
+	runs, err := api.scheduler.History(c.Request.Context(), name, limit)
+	if err != nil {
+		api.logger.Error("schedule history", "schedule", name, "error", err)
+		c.JSON(statusFromError(err), errorResponse(err))
+		return
+	}
+	resp := make([]scheduleRunResponse, 0, len(runs))
+	for _, run := range runs {
+		resp = append(resp, scheduleRunToResponse(run))
+	}
+	c.JSON(http.StatusOK, resp)
+}
@@ -0,0 +1,188 @@
+// Copyright (c) 2025 HYPR. PTE. LTD.
+//
+// Business Source License 1.1
+// See LICENSE file in the project root for details.
+
+package httpapi
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/volantvm/volant/internal/pluginspec"
+	"github.com/volantvm/volant/internal/server/db"
+	"github.com/volantvm/volant/internal/server/secrets"
+)
+
+type putSecretRequest struct {
+	Name  string `json:"name" binding:"required"`
+	Value string `json:"value" binding:"required"`
+}
+
+type updateSecretRequest struct {
+	Value string `json:"value" binding:"required"`
+}
+
+// secretResponse describes a stored secret. Values are never returned.
+type secretResponse struct {
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func secretToResponse(info secrets.Info) secretResponse {
+	return secretResponse{Name: info.Name, CreatedAt: info.CreatedAt, UpdatedAt: info.UpdatedAt}
+}
+
+func (api *apiServer) ensureSecretsAvailable(c *gin.Context) bool {
+	if api.secrets == nil {
+		respondError(c, http.StatusServiceUnavailable, CodeUnavailable, "secrets unavailable")
+		return false
+	}
+	return true
+}
+
+// rejectReservedSecret refuses writes to names reserved for keys volantd
+// generates, such as a VM's SSH private key.
+func rejectReservedSecret(c *gin.Context, name string) bool {
+	if !secrets.Reserved(name) {
+		return false
+	}
+	respondError(c, http.StatusBadRequest, CodeInvalidSecret, "secret names starting with \""+pluginspec.ReservedSecretPrefix+"\" are reserved for keys volantd generates")
+	return true
+}
+
+func (api *apiServer) listSecrets(c *gin.Context) {
+	if !api.ensureSecretsAvailable(c) {
+		return
+	}
+	infos, err := api.secrets.List(c.Request.Context())
+	if err != nil {
+		api.logger.Error("list secrets", "error", err)
+		c.JSON(statusFromError(err), errorResponse(err))
+		return
+	}
+	resp := make([]secretResponse, 0, len(infos))
+	for _, info := range infos {
+		resp = append(resp, secretToResponse(info))
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// putSecret stores a secret, replacing the value of an existing one.
+// Cloud-init templates rendered afterwards see the new value.
+func (api *apiServer) putSecret(c *gin.Context) {
+	if !api.ensureSecretsAvailable(c) {
+		return
+	}
+	var req putSecretRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, http.StatusBadRequest, CodeInvalidRequest, err.Error())
+		return
+	}
+	if rejectReservedSecret(c, req.Name) {
+		return
+	}
+	info, created, err := api.secrets.Put(c.Request.Context(), req.Name, req.Value)
+	if err != nil {
+		api.logger.Error("put secret", "secret", req.Name, "error", err)
+		c.JSON(statusFromError(err), errorResponse(err))
+		return
+	}
+	api.recordAudit(c.Request.Context(), db.AuditEvent{
+		Action:   auditSecretPut,
+		Target:   info.Name,
+		Actor:    anonymousConsoleUser,
+		ClientIP: c.ClientIP(),
+	})
+	status := http.StatusOK
+	if created {
+		status = http.StatusCreated
+	}
+	c.JSON(status, secretToResponse(*info))
+}
+
+func (api *apiServer) getSecret(c *gin.Context) {
+	if !api.ensureSecretsAvailable(c) {
+		return
+	}
+	info, err := api.secrets.Get(c.Request.Context(), c.Param("name"))
+	if err != nil {
+		c.JSON(statusFromError(err), errorResponse(err))
+		return
+	}
+	c.JSON(http.StatusOK, secretToResponse(*info))
+}
+
+// updateSecret replaces the value of an existing secret.
+func (api *apiServer) updateSecret(c *gin.Context) {
+	if !api.ensureSecretsAvailable(c) {
+		return
+	}
+	name := c.Param("name")
+	var req updateSecretRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, http.StatusBadRequest, CodeInvalidRequest, err.Error())
+		return
+	}
+	if rejectReservedSecret(c, name) {
+		return
+	}
+	info, err := api.secrets.Update(c.Request.Context(), name, req.Value)
+	if err != nil {
+		api.logger.Error("update secret", "secret", name, "error", err)
+		c.JSON(statusFromError(err), errorResponse(err))
+		return
+	}
+	api.recordAudit(c.Request.Context(), db.AuditEvent{
+		Action:   auditSecretPut,
+		Target:   info.Name,
+		Actor:    anonymousConsoleUser,
+		ClientIP: c.ClientIP(),
+	})
+	c.JSON(http.StatusOK, secretToResponse(*info))
+}
+
+func (api *apiServer) deleteSecret(c *gin.Context) {
+	if !api.ensureSecretsAvailable(c) {
+		return
+	}
+	name := c.Param("name")
+	if rejectReservedSecret(c, name) {
+		return
+	}
+	if err := api.secrets.Delete(c.Request.Context(), name); err != nil {
+		api.logger.Error("delete secret", "secret", name, "error", err)
+		c.JSON(statusFromError(err), errorResponse(err))
+		return
+	}
+	api.recordAudit(c.Request.Context(), db.AuditEvent{
+		Action:   auditSecretDelete,
+		Target:   name,
+		Actor:    anonymousConsoleUser,
+		ClientIP: c.ClientIP(),
+	})
+	c.Status(http.StatusNoContent)
+}
+
+// getVMSecrets serves the secrets mounted into a VM to its agent. The
+// engine refuses callers other than the VM and audits every value served.
+// The peer address is used rather than ClientIP, which forwarding headers
+// can spoof.
+func (api *apiServer) getVMSecrets(c *gin.Context) {
+	name := c.Param("name")
+	peer := c.RemoteIP()
+	mounted, err := api.engine.VMSecrets(c.Request.Context(), name, peer)
+	if err != nil {
+		api.logger.Warn("vm secrets", "vm", name, "client_ip", peer, "error", err)
+		c.JSON(statusFromError(err), errorResponse(err))
+		return
+	}
+	if mounted == nil {
+		mounted = []pluginspec.MountedSecret{}
+	}
+	c.Header("Cache-Control", "no-store")
+	c.JSON(http.StatusOK, mounted)
+}
@@ -0,0 +1,146 @@
+// Copyright (c) 2025 HYPR. PTE. LTD.
+//
+// Business Source License 1.1
+// See LICENSE file in the project root for details.
+
+package httpapi
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+
+	"github.com/volantvm/volant/internal/server/db"
+)
+
+type sshKeyResponse struct {
+	PrivateKey string `json:"private_key"`
+}
+
+// getVMSSH describes how to reach a VM over SSH.
+func (api *apiServer) getVMSSH(c *gin.Context) {
+	access, err := api.engine.VMSSH(c.Request.Context(), c.Param("name"))
+	if err != nil {
+		c.JSON(statusFromError(err), errorResponse(err))
+		return
+	}
+	c.JSON(http.StatusOK, access)
+}
+
+// getVMSSHKey serves the private key volantd generated for a VM. The engine
+// audits every read as a secret access, recording the peer address rather
+// than ClientIP, which forwarding headers can spoof.
+func (api *apiServer) getVMSSHKey(c *gin.Context) {
+	name := c.Param("name")
+	key, err := api.engine.VMSSHKey(c.Request.Context(), name, c.RemoteIP())
+	if err != nil {
+		api.logger.Warn("vm ssh key", "vm", name, "error", err)
+		c.JSON(statusFromError(err), errorResponse(err))
+		return
+	}
+	c.Header("Cache-Control", "no-store")
+	c.JSON(http.StatusOK, sshKeyResponse{PrivateKey: key})
+}
+
+// /ws/v1/vms/:name/ssh -> raw TCP tunnel to the VM's SSH port, for clients
+// that cannot reach the VM network. Binary frames carry the byte stream in
+// both directions; ssh runs over it as a ProxyCommand.
+func (api *apiServer) vmSSHWebSocket(c *gin.Context) {
+	vm, ok := api.resolveVM(c)
+	if !ok {
+		return
+	}
+	ctx := c.Request.Context()
+	conn, err := api.engine.DialSSH(ctx, vm.Name)
+	if err != nil {
+		api.logger.Error("ssh tunnel dial", "vm", vm.Name, "error", err)
+		if status := statusFromError(err); status != http.StatusInternalServerError {
+			c.JSON(status, errorResponse(err))
+			return
+		}
+		respondError(c, http.StatusBadGateway, CodeUpstreamError, "failed to connect to the vm ssh port")
+		return
+	}
+	defer conn.Close()
+
+	upgrader := websocket.Upgrader{CheckOrigin: func(r *http.Request) bool { return true }}
+	wsConn, err := upgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		api.logger.Error("ssh tunnel ws upgrade", "vm", vm.Name, "error", err)
+		return
+	}
+	defer wsConn.Close()
+
+	audit := db.AuditEvent{Target: vm.Name, Actor: anonymousConsoleUser, ClientIP: c.ClientIP()}
+	opened := time.Now()
+	audit.Action = auditSSHOpen
+	api.recordAudit(ctx, audit)
+	defer func() {
+		// The request context is already cancelled when the client hangs up.
+		audit.Action = auditSSHClose
+		audit.Duration = time.Since(opened)
+		api.recordAudit(context.Background(), audit)
+	}()
+
+	errCh := make(chan error, 2)
+	var wg sync.WaitGroup
+	wg.Add(2)
+
+	// Pipe: VM -> WS (binary frames)
+	go func() {
+		defer wg.Done()
+		buf := make([]byte, 32*1024)
+		for {
+			n, readErr := conn.Read(buf)
+			if n > 0 {
+				if writeErr := wsConn.WriteMessage(websocket.BinaryMessage, buf[:n]); writeErr != nil {
+					errCh <- writeErr
+					return
+				}
+			}
+			if readErr != nil {
+				errCh <- readErr
+				return
+			}
+		}
+	}()
+
+	// Pipe: WS -> VM
+	go func() {
+		defer wg.Done()
+		for {
+			_, payload, readErr := wsConn.ReadMessage()
+			if readErr != nil {
+				errCh <- readErr
+				return
+			}
+			if _, writeErr := conn.Write(payload); writeErr != nil {
+				errCh <- writeErr
+				return
+			}
+		}
+	}()
+
+	var bridgeErr error
+	select {
+	case <-ctx.Done():
+		bridgeErr = ctx.Err()
+	case bridgeErr = <-errCh:
+	}
+
+	_ = wsConn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""), time.Now().Add(time.Second))
+	_ = conn.Close()
+	_ = wsConn.Close()
+	wg.Wait()
+
+	if bridgeErr != nil && !errors.Is(bridgeErr, net.ErrClosed) && !errors.Is(bridgeErr, io.EOF) && !websocket.IsCloseError(bridgeErr, websocket.CloseNormalClosure, websocket.CloseGoingAway) {
+		api.logger.Debug("ssh tunnel closed", "vm", vm.Name, "error", bridgeErr)
+	}
+}
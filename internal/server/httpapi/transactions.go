@@ -0,0 +1,337 @@
+// Copyright (c) 2025 HYPR. PTE. LTD.
+//
+// Business Source License 1.1
+// See LICENSE file in the project root for details.
+
+package httpapi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/volantvm/volant/internal/drift/routes"
+	"github.com/volantvm/volant/internal/pluginspec"
+	"github.com/volantvm/volant/internal/server/admission"
+	"github.com/volantvm/volant/internal/server/orchestrator"
+	orchestratorevents "github.com/volantvm/volant/internal/server/orchestrator/events"
+)
+
+// maxTransactionSteps bounds a transaction; it is meant for a handful of
+// dependent resources, not bulk imports.
+const maxTransactionSteps = 16
+
+// Transaction step operations.
+const (
+	txOpPluginInstall      = "plugin.install"
+	txOpNetworkCreate      = "network.create"
+	txOpConfigBundleCreate = "config_bundle.create"
+	txOpDeploymentCreate   = "deployment.create"
+	txOpRouteUpsert        = "route.upsert"
+)
+
+// Transaction step outcomes.
+const (
+	txStepApplied        = "applied"
+	txStepFailed         = "failed"
+	txStepRolledBack     = "rolled_back"
+	txStepRollbackFailed = "rollback_failed"
+	txStepSkipped        = "skipped"
+)
+
+// Transaction outcomes.
+const (
+	txCommitted  = "committed"
+	txRolledBack = "rolled_back"
+)
+
+type transactionRequest struct {
+	Steps []transactionStep `json:"steps" binding:"required"`
+}
+
+// transactionStep carries one operation; Spec has the same shape as the body
+// of the operation's standalone endpoint.
+type transactionStep struct {
+	Op   string          `json:"op" binding:"required"`
+	Spec json.RawMessage `json:"spec" binding:"required"`
+}
+
+type transactionStepResult struct {
+	Index  int    `json:"index"`
+	Op     string `json:"op"`
+	Name   string `json:"name,omitempty"`
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+	Code   string `json:"code,omitempty"`
+	Result any    `json:"result,omitempty"`
+}
+
+type transactionResponse struct {
+	Status string                  `json:"status"`
+	Steps  []transactionStepResult `json:"steps"`
+}
+
+// txAction is a decoded step ready to apply. apply returns the step result
+// and an undo func that reverts it; undo may be nil when there is nothing to
+// revert.
+type txAction struct {
+	name  string
+	apply func(ctx context.Context) (any, func(context.Context) error, error)
+}
+
+// applyTransaction applies a batch of dependent operations in order. If any
+// step fails, the steps already applied are reverted in reverse order so no
+// half-configured environment is left behind.
+func (api *apiServer) applyTransaction(c *gin.Context) {
+	var req transactionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, http.StatusBadRequest, CodeInvalidRequest, err.Error())
+		return
+	}
+	if len(req.Steps) == 0 {
+		respondError(c, http.StatusBadRequest, CodeInvalidRequest, "transaction has no steps")
+		return
+	}
+	if len(req.Steps) > maxTransactionSteps {
+		respondError(c, http.StatusBadRequest, CodeInvalidRequest, fmt.Sprintf("transaction has %d steps; at most %d allowed", len(req.Steps), maxTransactionSteps))
+		return
+	}
+
+	// Decode every step before touching anything so a malformed step late in
+	// the batch costs nothing.
+	actions := make([]txAction, len(req.Steps))
+	for i, step := range req.Steps {
+		action, err := api.decodeTransactionStep(step)
+		if err != nil {
+			respondError(c, http.StatusBadRequest, CodeInvalidRequest, fmt.Sprintf("step %d (%s): %v", i, step.Op, err))
+			return
+		}
+		actions[i] = action
+	}
+
+	api.txMu.Lock()
+	defer api.txMu.Unlock()
+
+	ctx := c.Request.Context()
+	results := make([]transactionStepResult, len(actions))
+	for i, action := range actions {
+		results[i] = transactionStepResult{Index: i, Op: req.Steps[i].Op, Name: action.name, Status: txStepSkipped}
+	}
+	undos := make([]func(context.Context) error, len(actions))
+
+	var failure error
+	for i, action := range actions {
+		result, undo, err := action.apply(ctx)
+		if err != nil {
+			api.logger.Warn("transaction step failed", "step", i, "op", req.Steps[i].Op, "name", action.name, "error", err)
+			results[i].Status = txStepFailed
+			results[i].Error = err.Error()
+			results[i].Code = codeFromError(err)
+			failure = err
+			break
+		}
+		results[i].Status = txStepApplied
+		results[i].Result = result
+		undos[i] = undo
+	}
+
+	if failure == nil {
+		c.JSON(http.StatusOK, transactionResponse{Status: txCommitted, Steps: results})
+		return
+	}
+
+	// Roll back on a fresh context: the client may already have gone away,
+	// and abandoning the rollback would leave exactly the state it prevents.
+	rollbackCtx := context.WithoutCancel(ctx)
+	for i := len(actions) - 1; i >= 0; i-- {
+		if results[i].Status != txStepApplied {
+			continue
+		}
+		if undos[i] != nil {
+			if err := undos[i](rollbackCtx); err != nil {
+				api.logger.Error("transaction rollback failed", "step", i, "op", req.Steps[i].Op, "name", actions[i].name, "error", err)
+				results[i].Status = txStepRollbackFailed
+				results[i].Error = err.Error()
+				continue
+			}
+		}
+		results[i].Status = txStepRolledBack
+	}
+	c.JSON(statusFromError(failure), transactionResponse{Status: txRolledBack, Steps: results})
+}
+
+func (api *apiServer) decodeTransactionStep(step transactionStep) (txAction, error) {
+	switch strings.TrimSpace(step.Op) {
+	case txOpPluginInstall:
+		var manifest pluginspec.Manifest
+		if err := json.Unmarshal(step.Spec, &manifest); err != nil {
+			return txAction{}, err
+		}
+		return txAction{name: manifest.Name, apply: func(ctx context.Context) (any, func(context.Context) error, error) {
+			return api.txInstallPlugin(ctx, manifest)
+		}}, nil
+	case txOpNetworkCreate:
+		var req createNetworkRequest
+		if err := json.Unmarshal(step.Spec, &req); err != nil {
+			return txAction{}, err
+		}
+		return txAction{name: req.Name, apply: func(ctx context.Context) (any, func(context.Context) error, error) {
+			netw, err := api.engine.CreateNetwork(ctx, orchestrator.CreateNetworkRequest{
+				Name:     req.Name,
+				Subnet:   req.Subnet,
+				Gateway:  req.Gateway,
+				Bridge:   req.Bridge,
+				Internal: req.Internal,
+			})
+			if err != nil {
+				return nil, nil, err
+			}
+			undo := func(ctx context.Context) error { return api.engine.DeleteNetwork(ctx, netw.Name) }
+			return networkToResponse(*netw), undo, nil
+		}}, nil
+	case txOpConfigBundleCreate:
+		var req createConfigBundleRequest
+		if err := json.Unmarshal(step.Spec, &req); err != nil {
+			return txAction{}, err
+		}
+		return txAction{name: req.Name, apply: func(ctx context.Context) (any, func(context.Context) error, error) {
+			bundle, err := api.engine.CreateConfigBundle(ctx, pluginspec.Bundle{Name: req.Name, Data: req.Data, Files: req.Files})
+			if err != nil {
+				return nil, nil, err
+			}
+			undo := func(ctx context.Context) error { return api.engine.DeleteConfigBundle(ctx, bundle.Name) }
+			return configBundleToResponse(*bundle), undo, nil
+		}}, nil
+	case txOpDeploymentCreate:
+		var req createDeploymentRequest
+		if err := json.Unmarshal(step.Spec, &req); err != nil {
+			return txAction{}, err
+		}
+		if strings.TrimSpace(req.Name) == "" {
+			return txAction{}, errors.New("name is required")
+		}
+		return txAction{name: req.Name, apply: func(ctx context.Context) (any, func(context.Context) error, error) {
+			if err := api.checkAdmission(ctx, admission.OpDeploymentCreate, req.Name, &req); err != nil {
+				return nil, nil, err
+			}
+			deployment, err := api.engine.CreateDeployment(ctx, orchestrator.CreateDeploymentRequest{
+				Name:     req.Name,
+				Replicas: req.Replicas,
+				MaxSurge: req.MaxSurge,
+				Config:   req.Config,
+			})
+			if err != nil {
+				return nil, nil, err
+			}
+			undo := func(ctx context.Context) error { return api.engine.DeleteDeployment(ctx, deployment.Name) }
+			return deploymentToResponse(*deployment), undo, nil
+		}}, nil
+	case txOpRouteUpsert:
+		var route routes.Route
+		if err := json.Unmarshal(step.Spec, &route); err != nil {
+			return txAction{}, err
+		}
+		return txAction{name: fmt.Sprintf("%s/%d", route.Protocol, route.HostPort), apply: func(ctx context.Context) (any, func(context.Context) error, error) {
+			return api.txUpsertRoute(ctx, route)
+		}}, nil
+	case "":
+		return txAction{}, errors.New("op is required")
+	default:
+		return txAction{}, fmt.Errorf("unsupported op; expected one of %s, %s, %s, %s, %s",
+			txOpPluginInstall, txOpNetworkCreate, txOpConfigBundleCreate, txOpDeploymentCreate, txOpRouteUpsert)
+	}
+}
+
+// txInstallPlugin installs manifest; undo restores the manifest it replaced
+// or removes the plugin if it was new, and forgets the version if it was not
+// installed before.
+func (api *apiServer) txInstallPlugin(ctx context.Context, manifest pluginspec.Manifest) (any, func(context.Context) error, error) {
+	if api.plugins == nil {
+		return nil, nil, errors.New("plugin registry unavailable")
+	}
+	if err := api.checkAdmission(ctx, admission.OpPluginInstall, manifest.Name, &manifest); err != nil {
+		return nil, nil, err
+	}
+	manifest.Normalize()
+	if err := manifest.Validate(); err != nil {
+		return nil, nil, err
+	}
+	if err := api.engine.CheckHostFeatures(&manifest); err != nil {
+		return nil, nil, err
+	}
+
+	previous, existed := api.plugins.Get(manifest.Name)
+	staged, err := api.pluginVersion(ctx, manifest.Name, manifest.Version)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := api.persistPluginManifest(ctx, manifest, true); err != nil {
+		return nil, nil, err
+	}
+	api.plugins.Register(manifest)
+	api.publishPluginEvent(ctx, orchestratorevents.TypePluginInstalled, manifest.Name, manifest.Version, true)
+
+	undo := func(ctx context.Context) error {
+		if !existed {
+			if err := api.plugins.Remove(ctx, manifest.Name); err != nil {
+				return err
+			}
+			if err := api.dropPluginVersion(ctx, manifest.Name, manifest.Version); err != nil {
+				return err
+			}
+			api.publishPluginEvent(ctx, orchestratorevents.TypePluginRemoved, manifest.Name, "", false)
+			return nil
+		}
+		if err := api.persistPluginManifest(ctx, previous, previous.Enabled); err != nil {
+			return err
+		}
+		if staged == nil && manifest.Version != previous.Version {
+			if err := api.dropPluginVersion(ctx, manifest.Name, manifest.Version); err != nil {
+				return err
+			}
+		}
+		api.plugins.Register(previous)
+		api.publishPluginEvent(ctx, orchestratorevents.TypePluginInstalled, previous.Name, previous.Version, previous.Enabled)
+		return nil
+	}
+	return gin.H{"name": manifest.Name, "version": manifest.Version}, undo, nil
+}
+
+// txUpsertRoute upserts a drift route; undo restores the route it replaced or
+// deletes it if it was new.
+func (api *apiServer) txUpsertRoute(ctx context.Context, route routes.Route) (any, func(context.Context) error, error) {
+	if api.drift == nil || !api.drift.Enabled() {
+		return nil, nil, errors.New("drift not configured")
+	}
+	if err := api.engine.CheckRouteChange(ctx, route.Protocol, route.HostPort); err != nil {
+		return nil, nil, err
+	}
+	existing, err := api.drift.ListRoutes(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	var previous *routes.Route
+	for i := range existing {
+		if existing[i].HostPort == route.HostPort && strings.EqualFold(existing[i].Protocol, route.Protocol) {
+			previous = &existing[i]
+			break
+		}
+	}
+	updated, err := api.drift.UpsertRoute(ctx, route)
+	if err != nil {
+		return nil, nil, err
+	}
+	undo := func(ctx context.Context) error {
+		if previous != nil {
+			_, err := api.drift.UpsertRoute(ctx, *previous)
+			return err
+		}
+		return api.drift.DeleteRoute(ctx, updated.Protocol, updated.HostPort)
+	}
+	return updated, undo, nil
+}
@@ -0,0 +1,179 @@
+// Copyright (c) 2025 HYPR. PTE. LTD.
+//
+// Business Source License 1.1
+// See LICENSE file in the project root for details.
+
+package httpapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers"
+	"github.com/getkin/kin-openapi/routers/legacy"
+	"github.com/gin-gonic/gin"
+)
+
+// fieldError is one validation failure, reported in details.errors.
+type fieldError struct {
+	// In is where the field lives: body, path, query or header.
+	In string `json:"in"`
+	// Field is the parameter name, or a JSON pointer into the body.
+	Field   string `json:"field,omitempty"`
+	Message string `json:"message"`
+}
+
+// requestValidator checks requests against the OpenAPI spec before they reach
+// handlers, so the parameter types, enums and required fields the spec
+// declares are enforced in one place.
+type requestValidator struct {
+	router routers.Router
+}
+
+func newRequestValidator() (*requestValidator, error) {
+	spec, err := BuildOpenAPISpec("")
+	if err != nil {
+		return nil, err
+	}
+	// Round-trip through the loader so every $ref the generator emitted is
+	// resolved before the router validates the document.
+	data, err := json.Marshal(spec)
+	if err != nil {
+		return nil, err
+	}
+	doc, err := openapi3.NewLoader().LoadFromData(data)
+	if err != nil {
+		return nil, err
+	}
+	router, err := legacy.NewRouter(doc)
+	if err != nil {
+		return nil, err
+	}
+	return &requestValidator{router: router}, nil
+}
+
+// middleware answers 422 with field-level errors for requests that do not
+// match their operation. Routes the spec does not describe pass through.
+func (v *requestValidator) middleware() gin.HandlerFunc {
+	options := &openapi3filter.Options{
+		MultiError:         true,
+		AuthenticationFunc: openapi3filter.NoopAuthenticationFunc,
+	}
+	// Raw bodies such as file uploads are streamed by their handlers, not
+	// read into memory for a schema check.
+	rawOptions := *options
+	rawOptions.ExcludeRequestBody = true
+	return func(c *gin.Context) {
+		route, pathParams, err := v.router.FindRoute(c.Request)
+		if err != nil {
+			c.Next()
+			return
+		}
+		req := c.Request.Clone(c.Request.Context())
+		opts := options
+		if body := route.Operation.RequestBody; body != nil {
+			if body.Value.Content.Get("application/json") == nil {
+				opts = &rawOptions
+			} else if !strings.Contains(req.Header.Get("Content-Type"), "json") {
+				// Handlers decode bodies as JSON whatever the Content-Type
+				// says, so clients such as curl -d that send form content
+				// types keep working.
+				req.Header.Set("Content-Type", "application/json")
+			}
+		}
+		err = openapi3filter.ValidateRequest(req.Context(), &openapi3filter.RequestValidationInput{
+			Request:    req,
+			PathParams: pathParams,
+			Route:      route,
+			Options:    opts,
+		})
+		// ValidateRequest reads the body and leaves a fresh copy on req.
+		c.Request.Body = req.Body
+		if err == nil {
+			c.Next()
+			return
+		}
+		errs := collectFieldErrors(nil, err)
+		message := "request failed validation"
+		if len(errs) > 0 {
+			message += ": " + errs[0].String()
+		}
+		body := newAPIError(CodeValidationFailed, message)
+		body.Details = map[string]any{"errors": errs}
+		c.AbortWithStatusJSON(http.StatusUnprocessableEntity, body)
+	}
+}
+
+// queryCount returns a count query parameter the spec has already checked
+// to be a non-negative integer, or 0 when it is absent.
+func queryCount(c *gin.Context, key string) int {
+	n, _ := strconv.Atoi(strings.TrimSpace(c.Query(key)))
+	return n
+}
+
+func (e fieldError) String() string {
+	if e.Field == "" {
+		return e.Message
+	}
+	return fmt.Sprintf("%s %s: %s", e.In, e.Field, e.Message)
+}
+
+// collectFieldErrors flattens the errors of openapi3filter into fieldErrors.
+func collectFieldErrors(out []fieldError, err error) []fieldError {
+	switch e := err.(type) {
+	case openapi3.MultiError:
+		for _, inner := range e {
+			out = collectFieldErrors(out, inner)
+		}
+	case *openapi3filter.RequestError:
+		in, field := "body", ""
+		if e.Parameter != nil {
+			in, field = e.Parameter.In, e.Parameter.Name
+		}
+		out = collectSchemaErrors(out, in, field, e.Reason, e.Err)
+	default:
+		out = append(out, fieldError{Message: err.Error()})
+	}
+	return out
+}
+
+func collectSchemaErrors(out []fieldError, in, field, reason string, err error) []fieldError {
+	switch e := err.(type) {
+	case openapi3.MultiError:
+		for _, inner := range e {
+			out = collectSchemaErrors(out, in, field, reason, inner)
+		}
+	case *openapi3.SchemaError:
+		if in == "body" {
+			field = "/" + strings.Join(e.JSONPointer(), "/")
+		}
+		out = append(out, fieldError{In: in, Field: field, Message: e.Reason})
+	case *openapi3filter.ParseError:
+		if in == "body" && len(e.Path()) > 0 {
+			parts := make([]string, 0, len(e.Path()))
+			for _, part := range e.Path() {
+				parts = append(parts, fmt.Sprint(part))
+			}
+			field = "/" + strings.Join(parts, "/")
+		}
+		message := e.Reason
+		if message == "" {
+			message = e.Error()
+		}
+		out = append(out, fieldError{In: in, Field: field, Message: message})
+	case nil:
+		out = append(out, fieldError{In: in, Field: field, Message: reason})
+	default:
+		message := err.Error()
+		if reason != "" {
+			message = reason + ": " + message
+		}
+		out = append(out, fieldError{In: in, Field: field, Message: message})
+	}
+	return out
+}
@@ -0,0 +1,76 @@
+// Copyright (c) 2025 HYPR. PTE. LTD.
+//
+// Business Source License 1.1
+// See LICENSE file in the project root for details.
+
+package httpapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestRequestValidation(t *testing.T) {
+	validator, err := newRequestValidator()
+	if err != nil {
+		t.Fatalf("build validator: %v", err)
+	}
+	gin.SetMode(gin.ReleaseMode)
+	r := gin.New()
+	v1 := r.Group("/api/v1", validator.middleware())
+	v1.GET("/audit", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"limit": queryCount(c, "limit")})
+	})
+	v1.POST("/vms", func(c *gin.Context) {
+		c.Status(http.StatusCreated)
+	})
+
+	for _, tc := range []struct {
+		name   string
+		method string
+		target string
+		body   string
+		status int
+		field  string
+	}{
+		{name: "count", method: http.MethodGet, target: "/api/v1/audit?limit=5", status: http.StatusOK},
+		{name: "count not a number", method: http.MethodGet, target: "/api/v1/audit?limit=ten", status: http.StatusUnprocessableEntity, field: "limit"},
+		{name: "negative count", method: http.MethodGet, target: "/api/v1/audit?limit=-1", status: http.StatusUnprocessableEntity, field: "limit"},
+		{name: "body", method: http.MethodPost, target: "/api/v1/vms", body: `{"name":"web-1","plugin":"nginx"}`, status: http.StatusCreated},
+		{name: "body field of the wrong type", method: http.MethodPost, target: "/api/v1/vms", body: `{"name":"web-1","cpu_cores":"two"}`, status: http.StatusUnprocessableEntity, field: "/cpu_cores"},
+		{name: "body missing", method: http.MethodPost, target: "/api/v1/vms", status: http.StatusUnprocessableEntity},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(tc.method, tc.target, strings.NewReader(tc.body))
+			req.Header.Set("Content-Type", "application/json")
+			rec := httptest.NewRecorder()
+			r.ServeHTTP(rec, req)
+			if rec.Code != tc.status {
+				t.Fatalf("status %d, want %d: %s", rec.Code, tc.status, rec.Body)
+			}
+			if tc.status != http.StatusUnprocessableEntity {
+				return
+			}
+			var body struct {
+				Code    string `json:"code"`
+				Details struct {
+					Errors []fieldError `json:"errors"`
+				} `json:"details"`
+			}
+			if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+				t.Fatalf("decode error: %v", err)
+			}
+			if body.Code != CodeValidationFailed || len(body.Details.Errors) == 0 {
+				t.Fatalf("unexpected error body %s", rec.Body)
+			}
+			if tc.field != "" && body.Details.Errors[0].Field != tc.field {
+				t.Fatalf("field %q, want %q: %s", body.Details.Errors[0].Field, tc.field, rec.Body)
+			}
+		})
+	}
+}
@@ -0,0 +1,63 @@
+// Copyright (c) 2025 HYPR. PTE. LTD.
+//
+// Business Source License 1.1
+// See LICENSE file in the project root for details.
+
+package httpapi
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/volantvm/volant/internal/server/db"
+)
+
+type vfioAssignmentResponse struct {
+	PCIAddress string    `json:"pci_address"`
+	VM         string    `json:"vm"`
+	IOMMUGroup string    `json:"iommu_group,omitempty"`
+	AssignedAt time.Time `json:"assigned_at"`
+}
+
+func vfioAssignmentToResponse(assignment db.DeviceAssignment) vfioAssignmentResponse {
+	return vfioAssignmentResponse{
+		PCIAddress: assignment.PCIAddress,
+		VM:         assignment.VMName,
+		IOMMUGroup: assignment.IOMMUGroup,
+		AssignedAt: assignment.AssignedAt,
+	}
+}
+
+func (api *apiServer) listVFIOAssignments(c *gin.Context) {
+	assignments, err := api.engine.Store().Queries().DeviceAssignments().List(c.Request.Context())
+	if err != nil {
+		api.logger.Error("list vfio assignments", "error", err)
+		c.JSON(statusFromError(err), errorResponse(err))
+		return
+	}
+	resp := make([]vfioAssignmentResponse, 0, len(assignments))
+	for _, assignment := range assignments {
+		resp = append(resp, vfioAssignmentToResponse(assignment))
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// assignedVFIODevice returns the assignment of the first address in addrs
+// that a VM holds, so devices in use are not unbound under it.
+func (api *apiServer) assignedVFIODevice(c *gin.Context, addrs []string) (*db.DeviceAssignment, error) {
+	assignments, err := api.engine.Store().Queries().DeviceAssignments().List(c.Request.Context())
+	if err != nil {
+		return nil, err
+	}
+	for _, addr := range addrs {
+		for _, assignment := range assignments {
+			if strings.EqualFold(assignment.PCIAddress, strings.TrimSpace(addr)) {
+				return &assignment, nil
+			}
+		}
+	}
+	return nil, nil
+}
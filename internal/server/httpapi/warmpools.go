@@ -0,0 +1,133 @@
+// Copyright (c) 2025 HYPR. PTE. LTD.
+//
+// Business Source License 1.1
+// See LICENSE file in the project root for details.
+
+package httpapi
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/volantvm/volant/internal/server/orchestrator"
+	"github.com/volantvm/volant/internal/server/orchestrator/vmconfig"
+)
+
+type setWarmPoolRequest struct {
+	Size int `json:"size"`
+	// Config defaults to the plugin's manifest and default resources.
+	Config *vmconfig.Config `json:"config,omitempty"`
+}
+
+type warmPoolResponse struct {
+	Plugin    string          `json:"plugin"`
+	Size      int             `json:"size"`
+	Ready     int             `json:"ready"`
+	Starting  int             `json:"starting"`
+	Config    vmconfig.Config `json:"config"`
+	CreatedAt time.Time       `json:"created_at"`
+	UpdatedAt time.Time       `json:"updated_at"`
+}
+
+type claimVMRequest struct {
+	Labels map[string]string `json:"labels,omitempty"`
+}
+
+func warmPoolToResponse(pool orchestrator.WarmPool) warmPoolResponse {
+	return warmPoolResponse{
+		Plugin:    pool.Plugin,
+		Size:      pool.Size,
+		Ready:     pool.Ready,
+		Starting:  pool.Starting,
+		Config:    pool.Config,
+		CreatedAt: pool.CreatedAt,
+		UpdatedAt: pool.UpdatedAt,
+	}
+}
+
+func (api *apiServer) listWarmPools(c *gin.Context) {
+	pools, err := api.engine.WarmPools(c.Request.Context())
+	if err != nil {
+		api.logger.Error("list warm pools", "error", err)
+		c.JSON(statusFromError(err), errorResponse(err))
+		return
+	}
+	resp := make([]warmPoolResponse, 0, len(pools))
+	for _, pool := range pools {
+		resp = append(resp, warmPoolToResponse(pool))
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+func (api *apiServer) getWarmPool(c *gin.Context) {
+	plugin := c.Param("plugin")
+	pool, err := api.engine.GetWarmPool(c.Request.Context(), plugin)
+	if err != nil {
+		api.logger.Error("get warm pool", "plugin", plugin, "error", err)
+		c.JSON(statusFromError(err), errorResponse(err))
+		return
+	}
+	c.JSON(http.StatusOK, warmPoolToResponse(*pool))
+}
+
+// putWarmPool creates the warm pool of a plugin or changes its size and
+// config; the pool is filled in the background.
+func (api *apiServer) putWarmPool(c *gin.Context) {
+	plugin := c.Param("plugin")
+	var req setWarmPoolRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, http.StatusBadRequest, CodeInvalidRequest, err.Error())
+		return
+	}
+	pool, err := api.engine.SetWarmPool(c.Request.Context(), orchestrator.SetWarmPoolRequest{
+		Plugin: plugin,
+		Size:   req.Size,
+		Config: req.Config,
+	})
+	if err != nil {
+		api.logger.Error("set warm pool", "plugin", plugin, "error", err)
+		c.JSON(statusFromError(err), errorResponse(err))
+		return
+	}
+	c.JSON(http.StatusOK, warmPoolToResponse(*pool))
+}
+
+func (api *apiServer) deleteWarmPool(c *gin.Context) {
+	plugin := c.Param("plugin")
+	if err := api.engine.DeleteWarmPool(c.Request.Context(), plugin); err != nil {
+		api.logger.Error("delete warm pool", "plugin", plugin, "error", err)
+		c.JSON(statusFromError(err), errorResponse(err))
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// claimVM hands out a ready VM from the warm pool of the plugin query
+// parameter. The body is optional.
+func (api *apiServer) claimVM(c *gin.Context) {
+	plugin := strings.TrimSpace(c.Query("plugin"))
+	if plugin == "" {
+		respondError(c, http.StatusBadRequest, CodeInvalidRequest, "plugin query parameter required")
+		return
+	}
+	var req claimVMRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		respondError(c, http.StatusBadRequest, CodeInvalidRequest, err.Error())
+		return
+	}
+	vm, err := api.engine.ClaimVM(c.Request.Context(), orchestrator.ClaimVMRequest{
+		Plugin: plugin,
+		Labels: req.Labels,
+	})
+	if err != nil {
+		api.logger.Error("claim vm", "plugin", plugin, "error", err)
+		c.JSON(statusFromError(err), errorResponse(err))
+		return
+	}
+	c.JSON(http.StatusOK, vmToResponse(vm))
+}
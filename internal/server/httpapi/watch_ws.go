@@ -0,0 +1,264 @@
+// Copyright (c) 2025 HYPR. PTE. LTD.
+//
+// Business Source License 1.1
+// See LICENSE file in the project root for details.
+
+package httpapi
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+
+	"github.com/volantvm/volant/internal/server/db"
+	orchestratorevents "github.com/volantvm/volant/internal/server/orchestrator/events"
+)
+
+// Channels multiplexed on /ws/v1/vms/:name/watch.
+const (
+	watchChannelStatus    = "status"
+	watchChannelConfig    = "config"
+	watchChannelHeartbeat = "heartbeat"
+	watchChannelLogs      = "logs"
+)
+
+// Message types on the watch stream besides the VM event types.
+const (
+	watchTypeSnapshot  = "SNAPSHOT"
+	watchTypeHeartbeat = "HEARTBEAT"
+)
+
+const (
+	// watchHeartbeatInterval paces heartbeats, which carry the VM's current
+	// status so a client that missed an event converges anyway.
+	watchHeartbeatInterval = 15 * time.Second
+	// watchLogRetry is how long the log follower waits before reattaching
+	// after the VM stops or the agent stream ends.
+	watchLogRetry = 3 * time.Second
+)
+
+// defaultWatchChannels leaves logs out; they are opt-in as on /ws/v1/events.
+var defaultWatchChannels = map[string]bool{
+	watchChannelStatus:    true,
+	watchChannelConfig:    true,
+	watchChannelHeartbeat: true,
+}
+
+// watchMessage wraps every message sent on /ws/v1/vms/:name/watch.
+type watchMessage struct {
+	Channel   string    `json:"channel"`
+	Type      string    `json:"type"`
+	Name      string    `json:"name"`
+	Timestamp time.Time `json:"timestamp"`
+	Data      any       `json:"data,omitempty"`
+}
+
+type watchHeartbeat struct {
+	Status        string `json:"status"`
+	PID           *int64 `json:"pid,omitempty"`
+	ConfigVersion int    `json:"config_version,omitempty"`
+}
+
+func parseWatchChannels(c *gin.Context) (map[string]bool, error) {
+	requested := queryValueSet(c, "channels", false)
+	if requested == nil {
+		return defaultWatchChannels, nil
+	}
+	channels := make(map[string]bool, len(requested))
+	for channel := range requested {
+		channel = strings.ToLower(channel)
+		switch channel {
+		case watchChannelStatus, watchChannelConfig, watchChannelHeartbeat, watchChannelLogs:
+			channels[channel] = true
+		default:
+			return nil, fmt.Errorf("unknown channel %q; expected %s, %s, %s or %s", channel, watchChannelStatus, watchChannelConfig, watchChannelHeartbeat, watchChannelLogs)
+		}
+	}
+	return channels, nil
+}
+
+// /ws/v1/vms/:name/watch -> one stream per VM detail view: status
+// transitions, config versions, heartbeats and, on request, log lines.
+func (api *apiServer) vmWatchWebSocket(c *gin.Context) {
+	if api.bus == nil {
+		respondError(c, http.StatusServiceUnavailable, CodeUnavailable, "event streaming not available")
+		return
+	}
+	channels, err := parseWatchChannels(c)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, CodeInvalidRequest, err.Error())
+		return
+	}
+	name := c.Param("name")
+	vm, err := api.engine.GetVM(c.Request.Context(), name)
+	if err != nil {
+		c.JSON(statusFromError(err), errorResponse(err))
+		return
+	}
+	if vm == nil {
+		respondError(c, http.StatusNotFound, CodeVMNotFound, "vm not found")
+		return
+	}
+
+	upgrader := websocket.Upgrader{CheckOrigin: func(r *http.Request) bool { return true }}
+	conn, err := upgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		api.logger.Error("vm watch websocket upgrade", "vm", name, "error", err)
+		return
+	}
+	defer conn.Close()
+
+	// Subscribe before the snapshot so no transition falls between them.
+	eventsCh := make(chan any, 64)
+	unsubscribe, err := api.bus.Subscribe(orchestratorevents.TopicVMEvents, eventsCh)
+	if err != nil {
+		writeWebSocketClose(conn, websocket.CloseInternalServerErr, "failed to subscribe")
+		return
+	}
+	defer unsubscribe()
+
+	ctx, cancel := context.WithCancel(c.Request.Context())
+	defer cancel()
+
+	_ = conn.SetReadDeadline(time.Now().Add(eventsPongWait))
+	conn.SetPongHandler(func(string) error {
+		return conn.SetReadDeadline(time.Now().Add(eventsPongWait))
+	})
+	go func() {
+		defer cancel()
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}()
+
+	write := func(msg watchMessage) bool {
+		_ = conn.SetWriteDeadline(time.Now().Add(eventsWriteWait))
+		return conn.WriteJSON(msg) == nil
+	}
+
+	now := time.Now().UTC()
+	if channels[watchChannelStatus] {
+		if !write(watchMessage{Channel: watchChannelStatus, Type: watchTypeSnapshot, Name: name, Timestamp: now, Data: vmToResponse(vm)}) {
+			return
+		}
+	}
+	if channels[watchChannelConfig] {
+		if config, err := api.engine.GetVMConfig(ctx, name); err == nil && config != nil {
+			if !write(watchMessage{Channel: watchChannelConfig, Type: watchTypeSnapshot, Name: name, Timestamp: now, Data: config}) {
+				return
+			}
+		}
+	}
+
+	var logsCh chan vmLogPayload
+	if channels[watchChannelLogs] && api.agentClient != nil {
+		logsCh = make(chan vmLogPayload, 256)
+		go api.followVMLogs(ctx, name, logsCh)
+	}
+
+	pingTicker := time.NewTicker(eventsPingInterval)
+	defer pingTicker.Stop()
+	var heartbeat <-chan time.Time
+	if channels[watchChannelHeartbeat] {
+		heartbeatTicker := time.NewTicker(watchHeartbeatInterval)
+		defer heartbeatTicker.Stop()
+		heartbeat = heartbeatTicker.C
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			writeWebSocketClose(conn, websocket.CloseNormalClosure, "stream closed")
+			return
+		case <-pingTicker.C:
+			if err := conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(eventsWriteWait)); err != nil {
+				return
+			}
+		case <-heartbeat:
+			msg, ok := api.watchHeartbeat(ctx, name)
+			if !ok {
+				continue
+			}
+			if !write(msg) {
+				return
+			}
+		case line := <-logsCh:
+			if !write(watchMessage{Channel: watchChannelLogs, Type: orchestratorevents.TypeVMLog, Name: name, Timestamp: line.Timestamp, Data: line}) {
+				return
+			}
+		case payload := <-eventsCh:
+			event, ok := payload.(orchestratorevents.VMEvent)
+			// Log lines on the bus come from other log streams; this stream
+			// follows the agent itself when the logs channel is selected.
+			if !ok || event.Name != name || event.Type == orchestratorevents.TypeVMLog {
+				continue
+			}
+			msg := watchMessage{Channel: watchChannelStatus, Type: event.Type, Name: name, Timestamp: event.Timestamp, Data: event}
+			if event.Type == orchestratorevents.TypeVMConfigUpdated {
+				if !channels[watchChannelConfig] {
+					continue
+				}
+				msg.Channel = watchChannelConfig
+				if config, err := api.engine.GetVMConfig(ctx, name); err == nil && config != nil {
+					msg.Data = config
+				}
+			} else if !channels[watchChannelStatus] {
+				continue
+			}
+			if !write(msg) {
+				return
+			}
+			if event.Type == orchestratorevents.TypeVMDeleted {
+				writeWebSocketClose(conn, websocket.CloseNormalClosure, "vm deleted")
+				return
+			}
+		}
+	}
+}
+
+func (api *apiServer) watchHeartbeat(ctx context.Context, name string) (watchMessage, bool) {
+	vm, err := api.engine.GetVM(ctx, name)
+	if err != nil || vm == nil {
+		return watchMessage{}, false
+	}
+	beat := watchHeartbeat{Status: string(vm.Status), PID: vm.PID}
+	if config, err := api.engine.GetVMConfig(ctx, name); err == nil && config != nil {
+		beat.ConfigVersion = config.Version
+	}
+	return watchMessage{Channel: watchChannelHeartbeat, Type: watchTypeHeartbeat, Name: name, Timestamp: time.Now().UTC(), Data: beat}, true
+}
+
+// followVMLogs attaches to the agent's log stream whenever the VM is running
+// and reattaches after restarts, until ctx is done. Lines are dropped rather
+// than stalling the agent stream when the client falls behind.
+func (api *apiServer) followVMLogs(ctx context.Context, name string, out chan<- vmLogPayload) {
+	for {
+		vm, err := api.engine.GetVM(ctx, name)
+		if err == nil && vm != nil && vm.Status == db.VMStatusRunning && agentReachable(vm) {
+			err = api.streamAgentLogs(ctx, vm, func(line vmLogPayload) bool {
+				select {
+				case out <- line:
+				case <-ctx.Done():
+					return false
+				default:
+				}
+				return true
+			})
+			if err != nil && ctx.Err() == nil {
+				api.logger.Debug("vm watch log stream", "vm", name, "error", err)
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(watchLogRetry):
+		}
+	}
+}
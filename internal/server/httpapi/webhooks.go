@@ -0,0 +1,189 @@
+// Copyright (c) 2025 HYPR. PTE. LTD.
+//
+// Business Source License 1.1
+// See LICENSE file in the project root for details.
+
+package httpapi
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/volantvm/volant/internal/server/db"
+	"github.com/volantvm/volant/internal/server/webhooks"
+)
+
+type createWebhookRequest struct {
+	Name string `json:"name" binding:"required"`
+	URL  string `json:"url" binding:"required"`
+	// Events filters the event types delivered; empty delivers all of them.
+	Events []string `json:"events,omitempty"`
+	// Secret keys the X-Volant-Signature HMAC; one is generated when empty.
+	Secret   string `json:"secret,omitempty"`
+	Disabled bool   `json:"disabled,omitempty"`
+}
+
+type webhookResponse struct {
+	Name    string   `json:"name"`
+	URL     string   `json:"url"`
+	Events  []string `json:"events,omitempty"`
+	Enabled bool     `json:"enabled"`
+	// Secret is only returned when the webhook is created.
+	Secret    string    `json:"secret,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type webhookDeliveryResponse struct {
+	DeliveryID string    `json:"delivery_id"`
+	EventType  string    `json:"event_type"`
+	Attempt    int       `json:"attempt"`
+	StatusCode int       `json:"status_code,omitempty"`
+	Succeeded  bool      `json:"succeeded"`
+	Error      string    `json:"error,omitempty"`
+	DurationMS int64     `json:"duration_ms"`
+	Payload    string    `json:"payload,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+func webhookToResponse(webhook db.Webhook) webhookResponse {
+	return webhookResponse{
+		Name:      webhook.Name,
+		URL:       webhook.URL,
+		Events:    webhook.Events,
+		Enabled:   webhook.Enabled,
+		CreatedAt: webhook.CreatedAt,
+		UpdatedAt: webhook.UpdatedAt,
+	}
+}
+
+func webhookDeliveryToResponse(delivery db.WebhookDelivery) webhookDeliveryResponse {
+	return webhookDeliveryResponse{
+		DeliveryID: delivery.DeliveryID,
+		EventType:  delivery.EventType,
+		Attempt:    delivery.Attempt,
+		StatusCode: delivery.StatusCode,
+		Succeeded:  delivery.Succeeded,
+		Error:      delivery.Error,
+		DurationMS: delivery.Duration.Milliseconds(),
+		Payload:    string(delivery.Payload),
+		CreatedAt:  delivery.CreatedAt,
+	}
+}
+
+func (api *apiServer) ensureWebhooksAvailable(c *gin.Context) bool {
+	if api.webhooks == nil {
+		respondError(c, http.StatusServiceUnavailable, CodeUnavailable, "webhooks unavailable")
+		return false
+	}
+	return true
+}
+
+func (api *apiServer) listWebhooks(c *gin.Context) {
+	if !api.ensureWebhooksAvailable(c) {
+		return
+	}
+	hooks, err := api.webhooks.List(c.Request.Context())
+	if err != nil {
+		api.logger.Error("list webhooks", "error", err)
+		c.JSON(statusFromError(err), errorResponse(err))
+		return
+	}
+	resp := make([]webhookResponse, 0, len(hooks))
+	for _, webhook := range hooks {
+		resp = append(resp, webhookToResponse(webhook))
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+func (api *apiServer) createWebhook(c *gin.Context) {
+	if !api.ensureWebhooksAvailable(c) {
+		return
+	}
+	var req createWebhookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, http.StatusBadRequest, CodeInvalidRequest, err.Error())
+		return
+	}
+	webhook, err := api.webhooks.Create(c.Request.Context(), webhooks.CreateRequest{
+		Name:     req.Name,
+		URL:      req.URL,
+		Events:   req.Events,
+		Secret:   req.Secret,
+		Disabled: req.Disabled,
+	})
+	if err != nil {
+		api.logger.Error("create webhook", "webhook", req.Name, "error", err)
+		c.JSON(statusFromError(err), errorResponse(err))
+		return
+	}
+	resp := webhookToResponse(*webhook)
+	resp.Secret = webhook.Secret
+	c.JSON(http.StatusCreated, resp)
+}
+
+func (api *apiServer) getWebhook(c *gin.Context) {
+	if !api.ensureWebhooksAvailable(c) {
+		return
+	}
+	webhook, err := api.webhooks.Get(c.Request.Context(), c.Param("name"))
+	if err != nil {
+		c.JSON(statusFromError(err), errorResponse(err))
+		return
+	}
+	c.JSON(http.StatusOK, webhookToResponse(*webhook))
+}
+
+func (api *apiServer) deleteWebhook(c *gin.Context) {
+	if !api.ensureWebhooksAvailable(c) {
+		return
+	}
+	name := c.Param("name")
+	if err := api.webhooks.Delete(c.Request.Context(), name); err != nil {
+		api.logger.Error("delete webhook", "webhook", name, "error", err)
+		c.JSON(statusFromError(err), errorResponse(err))
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+func (api *apiServer) setWebhookEnabled(c *gin.Context) {
+	if !api.ensureWebhooksAvailable(c) {
+		return
+	}
+	name := c.Param("name")
+	var payload struct {
+		Enabled bool `json:"enabled"`
+	}
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		respondError(c, http.StatusBadRequest, CodeInvalidRequest, err.Error())
+		return
+	}
+	webhook, err := api.webhooks.SetEnabled(c.Request.Context(), name, payload.Enabled)
+	if err != nil {
+		api.logger.Error("toggle webhook", "webhook", name, "error", err)
+		c.JSON(statusFromError(err), errorResponse(err))
+		return
+	}
+	c.JSON(http.StatusOK, webhookToResponse(*webhook))
+}
+
+func (api *apiServer) getWebhookDeliveries(c *gin.Context) {
+	if !api.ensureWebhooksAvailable(c) {
+		return
+	}
+	name := c.Param("name")
+	deliveries, err := api.webhooks.Deliveries(c.Request.Context(), name, queryCount(c, "limit"))
+	if err != nil {
+		api.logger.Error("webhook deliveries", "webhook", name, "error", err)
+		c.JSON(statusFromError(err), errorResponse(err))
+		return
+	}
+	resp := make([]webhookDeliveryResponse, 0, len(deliveries))
+	for _, delivery := range deliveries {
+		resp = append(resp, webhookDeliveryToResponse(delivery))
+	}
+	c.JSON(http.StatusOK, resp)
+}
@@ -0,0 +1,174 @@
+// Copyright (c) 2025 HYPR. PTE. LTD.
+//
+// Business Source License 1.1
+// See LICENSE file in the project root for details.
+
+// Package labels validates VM labels and parses the selector expressions
+// used to match them, e.g. "env=prod,tier!=cache,gpu,!legacy".
+package labels
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+const maxLength = 63
+
+var (
+	keyPattern   = regexp.MustCompile(`^[A-Za-z0-9]([A-Za-z0-9._/-]*[A-Za-z0-9])?$`)
+	valuePattern = regexp.MustCompile(`^([A-Za-z0-9]([A-Za-z0-9._-]*[A-Za-z0-9])?)?$`)
+)
+
+// ValidateKey checks a label key: 1-63 letters, digits, '.', '_', '/' or '-',
+// starting and ending with a letter or digit.
+func ValidateKey(key string) error {
+	if len(key) > maxLength || !keyPattern.MatchString(key) {
+		return fmt.Errorf("label key %q must be 1-%d letters, digits, '.', '_', '/' or '-' and start and end alphanumeric", key, maxLength)
+	}
+	return nil
+}
+
+// ValidateValue checks a label value: empty, or up to 63 letters, digits,
+// '.', '_' or '-' starting and ending with a letter or digit.
+func ValidateValue(value string) error {
+	if len(value) > maxLength || !valuePattern.MatchString(value) {
+		return fmt.Errorf("label value %q must be at most %d letters, digits, '.', '_' or '-' and start and end alphanumeric", value, maxLength)
+	}
+	return nil
+}
+
+// Validate checks every key and value in set.
+func Validate(set map[string]string) error {
+	for key, value := range set {
+		if err := ValidateKey(key); err != nil {
+			return err
+		}
+		if err := ValidateValue(value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Operator is the comparison a Requirement applies.
+type Operator string
+
+const (
+	Equals       Operator = "="
+	NotEquals    Operator = "!="
+	Exists       Operator = "exists"
+	DoesNotExist Operator = "!exists"
+)
+
+// Requirement is one comma-separated term of a selector.
+type Requirement struct {
+	Key      string
+	Operator Operator
+	Value    string
+}
+
+// Selector matches label sets satisfying every requirement. The empty
+// selector matches everything.
+type Selector []Requirement
+
+// Parse parses a selector expression. Terms are comma-separated and take the
+// forms key=value (or key==value), key!=value, key and !key.
+func Parse(expr string) (Selector, error) {
+	var selector Selector
+	for _, term := range strings.Split(expr, ",") {
+		term = strings.TrimSpace(term)
+		if term == "" {
+			continue
+		}
+		var req Requirement
+		switch {
+		case strings.Contains(term, "!="):
+			key, value, _ := strings.Cut(term, "!=")
+			req = Requirement{Key: strings.TrimSpace(key), Operator: NotEquals, Value: strings.TrimSpace(value)}
+		case strings.Contains(term, "="):
+			key, value, _ := strings.Cut(term, "=")
+			value = strings.TrimPrefix(value, "=")
+			req = Requirement{Key: strings.TrimSpace(key), Operator: Equals, Value: strings.TrimSpace(value)}
+		case strings.HasPrefix(term, "!"):
+			req = Requirement{Key: strings.TrimSpace(term[1:]), Operator: DoesNotExist}
+		default:
+			req = Requirement{Key: term, Operator: Exists}
+		}
+		if err := ValidateKey(req.Key); err != nil {
+			return nil, fmt.Errorf("selector term %q: %w", term, err)
+		}
+		if err := ValidateValue(req.Value); err != nil {
+			return nil, fmt.Errorf("selector term %q: %w", term, err)
+		}
+		selector = append(selector, req)
+	}
+	return selector, nil
+}
+
+// Matches reports whether set satisfies every requirement.
+func (s Selector) Matches(set map[string]string) bool {
+	for _, req := range s {
+		value, ok := set[req.Key]
+		switch req.Operator {
+		case Equals:
+			if !ok || value != req.Value {
+				return false
+			}
+		case NotEquals:
+			if ok && value == req.Value {
+				return false
+			}
+		case Exists:
+			if !ok {
+				return false
+			}
+		case DoesNotExist:
+			if ok {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func (s Selector) String() string {
+	terms := make([]string, 0, len(s))
+	for _, req := range s {
+		switch req.Operator {
+		case Exists:
+			terms = append(terms, req.Key)
+		case DoesNotExist:
+			terms = append(terms, "!"+req.Key)
+		default:
+			terms = append(terms, req.Key+string(req.Operator)+req.Value)
+		}
+	}
+	return strings.Join(terms, ",")
+}
+
+// Merge returns base overlaid with overrides; neither input is modified.
+func Merge(base, overrides map[string]string) map[string]string {
+	if len(base) == 0 && len(overrides) == 0 {
+		return nil
+	}
+	out := make(map[string]string, len(base)+len(overrides))
+	for key, value := range base {
+		out[key] = value
+	}
+	for key, value := range overrides {
+		out[key] = value
+	}
+	return out
+}
+
+// Keys returns the keys of set in sorted order.
+func Keys(set map[string]string) []string {
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
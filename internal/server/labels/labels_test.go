@@ -0,0 +1,41 @@
+// Copyright (c) 2025 HYPR. PTE. LTD.
+//
+// Business Source License 1.1
+// See LICENSE file in the project root for details.
+
+package labels
+
+import "testing"
+
+func TestParseAndMatch(t *testing.T) {
+	selector, err := Parse("env=prod, tier!=cache,gpu,!legacy,volant.plugin==browser")
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if got := selector.String(); got != "env=prod,tier!=cache,gpu,!legacy,volant.plugin=browser" {
+		t.Fatalf("unexpected round trip: %s", got)
+	}
+
+	match := map[string]string{"env": "prod", "tier": "web", "gpu": "", "volant.plugin": "browser"}
+	if !selector.Matches(match) {
+		t.Fatalf("expected %v to match", match)
+	}
+	for _, set := range []map[string]string{
+		{"env": "dev", "gpu": "", "volant.plugin": "browser"},
+		{"env": "prod", "tier": "cache", "gpu": "", "volant.plugin": "browser"},
+		{"env": "prod", "volant.plugin": "browser"},
+		{"env": "prod", "gpu": "", "legacy": "true", "volant.plugin": "browser"},
+	} {
+		if selector.Matches(set) {
+			t.Fatalf("expected %v not to match", set)
+		}
+	}
+}
+
+func TestParseRejectsInvalidTerms(t *testing.T) {
+	for _, expr := range []string{"=prod", "env=pr od", "-env", "env=a,b c"} {
+		if _, err := Parse(expr); err == nil {
+			t.Fatalf("expected %q to be rejected", expr)
+		}
+	}
+}
@@ -0,0 +1,287 @@
+// Copyright (c) 2025 HYPR. PTE. LTD.
+//
+// Business Source License 1.1
+// See LICENSE file in the project root for details.
+
+// Package logship forwards VM hypervisor and agent logs to external log
+// systems (syslog, Grafana Loki, OTLP collectors) so fleet logs land in the
+// existing observability stack.
+package logship
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Exporter kinds.
+const (
+	KindSyslog = "syslog"
+	KindLoki   = "loki"
+	KindOTLP   = "otlp"
+)
+
+// Labels that override, per plugin or VM, which exporters receive its logs.
+// Values are true or false. LabelExportPrefix+kind decides for one exporter,
+// LabelExport for all of them; unset falls back to the daemon default.
+const (
+	LabelExport       = "volant.logs.export"
+	LabelExportPrefix = LabelExport + "."
+)
+
+const (
+	// queueSize is the number of records buffered per exporter; records
+	// arriving while it is full are dropped.
+	queueSize = 10000
+	// batchSize and flushInterval bound how long records wait before export.
+	batchSize     = 500
+	flushInterval = time.Second
+	exportTimeout = 10 * time.Second
+)
+
+// Record is one log line with the VM it came from.
+type Record struct {
+	Timestamp  time.Time
+	VM         string
+	Plugin     string
+	Deployment string
+	Source     string
+	Stream     string
+	Line       string
+}
+
+// Exporter sends batches of records to one log system.
+type Exporter interface {
+	Kind() string
+	Export(ctx context.Context, records []Record) error
+	Close() error
+}
+
+// Endpoints configures the exporters of a daemon; empty endpoints disable
+// their exporter.
+type Endpoints struct {
+	// Syslog is udp://host:port, tcp://host:port or unix:///path.
+	Syslog string
+	// Loki is the Loki base URL; /loki/api/v1/push is used when it has no
+	// path.
+	Loki string
+	// OTLP is the OTLP/HTTP collector base URL; /v1/logs is used when it has
+	// no path.
+	OTLP string
+	// Default decides whether VMs without export labels are shipped.
+	Default bool
+}
+
+// Enabled reports whether any exporter is configured.
+func (e Endpoints) Enabled() bool {
+	return e.Syslog != "" || e.Loki != "" || e.OTLP != ""
+}
+
+// Validate checks the endpoint URLs.
+func (e Endpoints) Validate() error {
+	for kind, endpoint := range map[string]string{KindSyslog: e.Syslog, KindLoki: e.Loki, KindOTLP: e.OTLP} {
+		if endpoint == "" {
+			continue
+		}
+		parsed, err := url.Parse(endpoint)
+		if err != nil {
+			return fmt.Errorf("logship: %s endpoint %q: %w", kind, endpoint, err)
+		}
+		var schemes []string
+		if kind == KindSyslog {
+			schemes = []string{"udp", "tcp", "unix"}
+		} else {
+			schemes = []string{"http", "https"}
+		}
+		if !contains(schemes, parsed.Scheme) {
+			return fmt.Errorf("logship: %s endpoint %q: scheme must be one of %s", kind, endpoint, strings.Join(schemes, ", "))
+		}
+	}
+	return nil
+}
+
+func contains(values []string, value string) bool {
+	for _, candidate := range values {
+		if candidate == value {
+			return true
+		}
+	}
+	return false
+}
+
+// Shipper queues records for each configured exporter and exports them in
+// batches from a background goroutine per exporter.
+type Shipper struct {
+	logger     *slog.Logger
+	defaultOn  bool
+	queues     map[string]*queue
+	kinds      []string
+	wg         sync.WaitGroup
+	closeOnce  sync.Once
+	shutdownCh chan struct{}
+}
+
+type queue struct {
+	exporter Exporter
+	records  chan Record
+	dropped  int64
+	mu       sync.Mutex
+}
+
+// New builds the exporters for endpoints and starts shipping. hostname
+// identifies this daemon in syslog headers and stream labels.
+func New(logger *slog.Logger, endpoints Endpoints, hostname string) (*Shipper, error) {
+	if err := endpoints.Validate(); err != nil {
+		return nil, err
+	}
+	var exporters []Exporter
+	if endpoints.Syslog != "" {
+		exporters = append(exporters, NewSyslog(endpoints.Syslog, hostname))
+	}
+	if endpoints.Loki != "" {
+		exporters = append(exporters, NewLoki(endpoints.Loki, hostname))
+	}
+	if endpoints.OTLP != "" {
+		exporters = append(exporters, NewOTLP(endpoints.OTLP, hostname))
+	}
+	return NewShipper(logger, endpoints.Default, exporters...), nil
+}
+
+// NewShipper starts shipping to exporters. defaultOn decides whether VMs
+// without export labels are shipped.
+func NewShipper(logger *slog.Logger, defaultOn bool, exporters ...Exporter) *Shipper {
+	s := &Shipper{
+		logger:     logger.With("component", "logship"),
+		defaultOn:  defaultOn,
+		queues:     make(map[string]*queue, len(exporters)),
+		shutdownCh: make(chan struct{}),
+	}
+	for _, exporter := range exporters {
+		q := &queue{exporter: exporter, records: make(chan Record, queueSize)}
+		s.queues[exporter.Kind()] = q
+		s.kinds = append(s.kinds, exporter.Kind())
+		s.wg.Add(1)
+		go s.run(q)
+	}
+	sort.Strings(s.kinds)
+	return s
+}
+
+// Kinds returns the kinds of the configured exporters.
+func (s *Shipper) Kinds() []string {
+	return append([]string(nil), s.kinds...)
+}
+
+// Targets resolves the exporters that receive the logs of a VM with labels.
+func (s *Shipper) Targets(labels map[string]string) []string {
+	all := s.defaultOn
+	if on, ok := parseBool(labels[LabelExport]); ok {
+		all = on
+	}
+	var targets []string
+	for _, kind := range s.kinds {
+		on := all
+		if override, ok := parseBool(labels[LabelExportPrefix+kind]); ok {
+			on = override
+		}
+		if on {
+			targets = append(targets, kind)
+		}
+	}
+	return targets
+}
+
+func parseBool(value string) (bool, bool) {
+	if value == "" {
+		return false, false
+	}
+	parsed, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, false
+	}
+	return parsed, true
+}
+
+// Ship queues records for the exporters named by targets without blocking.
+func (s *Shipper) Ship(targets []string, records ...Record) {
+	for _, kind := range targets {
+		q, ok := s.queues[kind]
+		if !ok {
+			continue
+		}
+		for _, record := range records {
+			select {
+			case q.records <- record:
+			default:
+				q.mu.Lock()
+				q.dropped++
+				q.mu.Unlock()
+			}
+		}
+	}
+}
+
+func (s *Shipper) run(q *queue) {
+	defer s.wg.Done()
+	ticker := time.NewTicker(flushInterval)
+	defer ticker.Stop()
+	batch := make([]Record, 0, batchSize)
+	flush := func() {
+		if len(batch) > 0 {
+			ctx, cancel := context.WithTimeout(context.Background(), exportTimeout)
+			if err := q.exporter.Export(ctx, batch); err != nil {
+				s.logger.Warn("export logs", "exporter", q.exporter.Kind(), "records", len(batch), "error", err)
+			}
+			cancel()
+			batch = batch[:0]
+		}
+		q.mu.Lock()
+		dropped := q.dropped
+		q.dropped = 0
+		q.mu.Unlock()
+		if dropped > 0 {
+			s.logger.Warn("log export queue full; records dropped", "exporter", q.exporter.Kind(), "dropped", dropped)
+		}
+	}
+	for {
+		select {
+		case record := <-q.records:
+			batch = append(batch, record)
+			if len(batch) >= batchSize {
+				flush()
+			}
+		case <-ticker.C:
+			flush()
+		case <-s.shutdownCh:
+			for {
+				select {
+				case record := <-q.records:
+					batch = append(batch, record)
+					if len(batch) >= batchSize {
+						flush()
+					}
+				default:
+					flush()
+					return
+				}
+			}
+		}
+	}
+}
+
+// Close exports what is queued and closes the exporters.
+func (s *Shipper) Close() {
+	s.closeOnce.Do(func() {
+		close(s.shutdownCh)
+		s.wg.Wait()
+		for _, q := range s.queues {
+			_ = q.exporter.Close()
+		}
+	})
+}
@@ -0,0 +1,188 @@
+// Copyright (c) 2025 HYPR. PTE. LTD.
+//
+// Business Source License 1.1
+// See LICENSE file in the project root for details.
+
+package logship
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+var testRecord = Record{
+	Timestamp:  time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC),
+	VM:         "web-1",
+	Plugin:     "nginx",
+	Deployment: "web",
+	Source:     "agent",
+	Stream:     "stderr",
+	Line:       "upstream timed out",
+}
+
+func TestTargetsResolveLabels(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	shipper := NewShipper(logger, true, &fakeExporter{kind: KindLoki}, &fakeExporter{kind: KindSyslog})
+	defer shipper.Close()
+
+	cases := []struct {
+		labels map[string]string
+		want   []string
+	}{
+		{nil, []string{KindLoki, KindSyslog}},
+		{map[string]string{LabelExport: "false"}, nil},
+		{map[string]string{LabelExport: "false", LabelExportPrefix + KindLoki: "true"}, []string{KindLoki}},
+		{map[string]string{LabelExportPrefix + KindSyslog: "false"}, []string{KindLoki}},
+		{map[string]string{LabelExport: "bogus"}, []string{KindLoki, KindSyslog}},
+	}
+	for _, tc := range cases {
+		if got := shipper.Targets(tc.labels); !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("Targets(%v) = %v, want %v", tc.labels, got, tc.want)
+		}
+	}
+
+	optIn := NewShipper(logger, false, &fakeExporter{kind: KindOTLP})
+	defer optIn.Close()
+	if got := optIn.Targets(nil); got != nil {
+		t.Errorf("opt-in Targets(nil) = %v, want none", got)
+	}
+	if got := optIn.Targets(map[string]string{LabelExport: "true"}); !reflect.DeepEqual(got, []string{KindOTLP}) {
+		t.Errorf("opt-in Targets(export=true) = %v", got)
+	}
+}
+
+func TestShipperFlushesOnClose(t *testing.T) {
+	exporter := &fakeExporter{kind: KindLoki}
+	shipper := NewShipper(slog.New(slog.NewTextHandler(io.Discard, nil)), true, exporter)
+	shipper.Ship([]string{KindLoki, KindOTLP}, testRecord, testRecord)
+	shipper.Close()
+	if got := exporter.count(); got != 2 {
+		t.Fatalf("exported %d records, want 2", got)
+	}
+}
+
+func TestEndpointsValidate(t *testing.T) {
+	if err := (Endpoints{Syslog: "udp://127.0.0.1:514", Loki: "http://loki:3100", OTLP: "https://otel:4318"}).Validate(); err != nil {
+		t.Fatalf("Validate: %v", err)
+	}
+	for _, bad := range []Endpoints{{Syslog: "http://host:514"}, {Loki: "udp://loki:3100"}, {OTLP: "otel:4318"}} {
+		if err := bad.Validate(); err == nil {
+			t.Errorf("Validate(%+v) succeeded", bad)
+		}
+	}
+}
+
+func TestLokiExport(t *testing.T) {
+	var body struct {
+		Streams []lokiStream `json:"streams"`
+	}
+	var path string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("decode push: %v", err)
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	if err := NewLoki(srv.URL, "host-a").Export(context.Background(), []Record{testRecord}); err != nil {
+		t.Fatalf("Export: %v", err)
+	}
+	if path != lokiPushPath {
+		t.Errorf("pushed to %q, want %q", path, lokiPushPath)
+	}
+	if len(body.Streams) != 1 || len(body.Streams[0].Values) != 1 {
+		t.Fatalf("unexpected push %+v", body)
+	}
+	stream := body.Streams[0]
+	if stream.Stream["vm"] != "web-1" || stream.Stream["plugin"] != "nginx" || stream.Stream["host"] != "host-a" {
+		t.Errorf("unexpected stream labels %v", stream.Stream)
+	}
+	if stream.Values[0][1] != testRecord.Line {
+		t.Errorf("line = %q", stream.Values[0][1])
+	}
+}
+
+func TestOTLPExport(t *testing.T) {
+	var body struct {
+		ResourceLogs []otlpResourceLogs `json:"resourceLogs"`
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != otlpLogsPath {
+			t.Errorf("posted to %q, want %q", r.URL.Path, otlpLogsPath)
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("decode logs: %v", err)
+		}
+	}))
+	defer srv.Close()
+
+	if err := NewOTLP(srv.URL, "host-a").Export(context.Background(), []Record{testRecord}); err != nil {
+		t.Fatalf("Export: %v", err)
+	}
+	if len(body.ResourceLogs) != 1 || len(body.ResourceLogs[0].ScopeLogs[0].LogRecords) != 1 {
+		t.Fatalf("unexpected logs %+v", body)
+	}
+	record := body.ResourceLogs[0].ScopeLogs[0].LogRecords[0]
+	if record.SeverityNumber != otlpSeverityError || record.Body.StringValue != testRecord.Line {
+		t.Errorf("unexpected record %+v", record)
+	}
+}
+
+func TestSyslogExport(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	defer conn.Close()
+
+	exporter := NewSyslog("udp://"+conn.LocalAddr().String(), "host-a")
+	defer exporter.Close()
+	if err := exporter.Export(context.Background(), []Record{testRecord}); err != nil {
+		t.Fatalf("Export: %v", err)
+	}
+	_ = conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	buf := make([]byte, 2048)
+	n, _, err := conn.ReadFrom(buf)
+	if err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	want := "<131>1 2025-01-02T03:04:05Z host-a volant web-1 agent.stderr - upstream timed out"
+	if got := string(buf[:n]); got != want {
+		t.Errorf("message = %q, want %q", got, want)
+	}
+}
+
+type fakeExporter struct {
+	kind string
+
+	mu      sync.Mutex
+	records []Record
+}
+
+func (f *fakeExporter) Kind() string { return f.kind }
+
+func (f *fakeExporter) Export(_ context.Context, records []Record) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.records = append(f.records, records...)
+	return nil
+}
+
+func (f *fakeExporter) Close() error { return nil }
+
+func (f *fakeExporter) count() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return len(f.records)
+}
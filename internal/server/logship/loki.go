@@ -0,0 +1,113 @@
+// Copyright (c) 2025 HYPR. PTE. LTD.
+//
+// Business Source License 1.1
+// See LICENSE file in the project root for details.
+
+package logship
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+const lokiPushPath = "/loki/api/v1/push"
+
+// lokiExporter pushes records to Grafana Loki, one stream per VM, source and
+// stream.
+type lokiExporter struct {
+	endpoint string
+	hostname string
+	client   *http.Client
+}
+
+// NewLoki returns an exporter pushing to the Loki at endpoint.
+func NewLoki(endpoint, hostname string) Exporter {
+	return &lokiExporter{endpoint: withDefaultPath(endpoint, lokiPushPath), hostname: hostname, client: &http.Client{}}
+}
+
+func (l *lokiExporter) Kind() string { return KindLoki }
+
+type lokiStream struct {
+	Stream map[string]string `json:"stream"`
+	Values [][2]string       `json:"values"`
+}
+
+func (l *lokiExporter) Export(ctx context.Context, records []Record) error {
+	streams := make(map[string]*lokiStream)
+	order := make([]string, 0)
+	for _, record := range records {
+		key := record.VM + "\x00" + record.Source + "\x00" + record.Stream
+		stream, ok := streams[key]
+		if !ok {
+			stream = &lokiStream{Stream: map[string]string{
+				"job":    "volant",
+				"host":   l.hostname,
+				"vm":     record.VM,
+				"source": record.Source,
+				"stream": record.Stream,
+			}}
+			if record.Plugin != "" {
+				stream.Stream["plugin"] = record.Plugin
+			}
+			if record.Deployment != "" {
+				stream.Stream["deployment"] = record.Deployment
+			}
+			streams[key] = stream
+			order = append(order, key)
+		}
+		stream.Values = append(stream.Values, [2]string{strconv.FormatInt(record.Timestamp.UnixNano(), 10), record.Line})
+	}
+	payload := struct {
+		Streams []*lokiStream `json:"streams"`
+	}{Streams: make([]*lokiStream, 0, len(order))}
+	for _, key := range order {
+		payload.Streams = append(payload.Streams, streams[key])
+	}
+	return postJSON(ctx, l.client, l.endpoint, payload)
+}
+
+func (l *lokiExporter) Close() error {
+	l.client.CloseIdleConnections()
+	return nil
+}
+
+// withDefaultPath appends path to endpoint when it has none.
+func withDefaultPath(endpoint, path string) string {
+	parsed, err := url.Parse(endpoint)
+	if err != nil || strings.Trim(parsed.Path, "/") != "" {
+		return endpoint
+	}
+	parsed.Path = path
+	return parsed.String()
+}
+
+// postJSON posts payload and expects a 2xx response.
+func postJSON(ctx context.Context, client *http.Client, endpoint string, payload any) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		detail, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("%s returned %d: %s", endpoint, resp.StatusCode, strings.TrimSpace(string(detail)))
+	}
+	_, _ = io.Copy(io.Discard, resp.Body)
+	return nil
+}
@@ -0,0 +1,124 @@
+// Copyright (c) 2025 HYPR. PTE. LTD.
+//
+// Business Source License 1.1
+// See LICENSE file in the project root for details.
+
+package logship
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+)
+
+const otlpLogsPath = "/v1/logs"
+
+// OTLP severity numbers.
+const (
+	otlpSeverityInfo  = 9
+	otlpSeverityError = 17
+)
+
+// otlpExporter sends records to an OTLP/HTTP collector using the JSON
+// encoding, one resource per VM.
+type otlpExporter struct {
+	endpoint string
+	hostname string
+	client   *http.Client
+}
+
+// NewOTLP returns an exporter sending to the OTLP/HTTP collector at
+// endpoint.
+func NewOTLP(endpoint, hostname string) Exporter {
+	return &otlpExporter{endpoint: withDefaultPath(endpoint, otlpLogsPath), hostname: hostname, client: &http.Client{}}
+}
+
+func (o *otlpExporter) Kind() string { return KindOTLP }
+
+type otlpValue struct {
+	StringValue string `json:"stringValue"`
+}
+
+type otlpAttribute struct {
+	Key   string    `json:"key"`
+	Value otlpValue `json:"value"`
+}
+
+type otlpLogRecord struct {
+	TimeUnixNano   string          `json:"timeUnixNano"`
+	SeverityNumber int             `json:"severityNumber"`
+	SeverityText   string          `json:"severityText"`
+	Body           otlpValue       `json:"body"`
+	Attributes     []otlpAttribute `json:"attributes"`
+}
+
+type otlpScopeLogs struct {
+	Scope struct {
+		Name string `json:"name"`
+	} `json:"scope"`
+	LogRecords []otlpLogRecord `json:"logRecords"`
+}
+
+type otlpResourceLogs struct {
+	Resource struct {
+		Attributes []otlpAttribute `json:"attributes"`
+	} `json:"resource"`
+	ScopeLogs []otlpScopeLogs `json:"scopeLogs"`
+}
+
+func attribute(key, value string) otlpAttribute {
+	return otlpAttribute{Key: key, Value: otlpValue{StringValue: value}}
+}
+
+func (o *otlpExporter) Export(ctx context.Context, records []Record) error {
+	resources := make(map[string]*otlpResourceLogs)
+	order := make([]string, 0)
+	for _, record := range records {
+		resource, ok := resources[record.VM]
+		if !ok {
+			resource = &otlpResourceLogs{}
+			resource.Resource.Attributes = []otlpAttribute{
+				attribute("service.name", "volant"),
+				attribute("host.name", o.hostname),
+				attribute("volant.vm", record.VM),
+			}
+			if record.Plugin != "" {
+				resource.Resource.Attributes = append(resource.Resource.Attributes, attribute("volant.plugin", record.Plugin))
+			}
+			if record.Deployment != "" {
+				resource.Resource.Attributes = append(resource.Resource.Attributes, attribute("volant.deployment", record.Deployment))
+			}
+			scope := otlpScopeLogs{}
+			scope.Scope.Name = "volant.vmlogs"
+			resource.ScopeLogs = []otlpScopeLogs{scope}
+			resources[record.VM] = resource
+			order = append(order, record.VM)
+		}
+		severity, text := otlpSeverityInfo, "INFO"
+		if record.Stream == "stderr" {
+			severity, text = otlpSeverityError, "ERROR"
+		}
+		resource.ScopeLogs[0].LogRecords = append(resource.ScopeLogs[0].LogRecords, otlpLogRecord{
+			TimeUnixNano:   strconv.FormatInt(record.Timestamp.UnixNano(), 10),
+			SeverityNumber: severity,
+			SeverityText:   text,
+			Body:           otlpValue{StringValue: record.Line},
+			Attributes: []otlpAttribute{
+				attribute("volant.source", record.Source),
+				attribute("log.iostream", record.Stream),
+			},
+		})
+	}
+	payload := struct {
+		ResourceLogs []*otlpResourceLogs `json:"resourceLogs"`
+	}{ResourceLogs: make([]*otlpResourceLogs, 0, len(order))}
+	for _, vm := range order {
+		payload.ResourceLogs = append(payload.ResourceLogs, resources[vm])
+	}
+	return postJSON(ctx, o.client, o.endpoint, payload)
+}
+
+func (o *otlpExporter) Close() error {
+	o.client.CloseIdleConnections()
+	return nil
+}
@@ -0,0 +1,116 @@
+// Copyright (c) 2025 HYPR. PTE. LTD.
+//
+// Business Source License 1.1
+// See LICENSE file in the project root for details.
+
+package logship
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// syslogFacility is local0.
+	syslogFacility     = 16
+	syslogSeverityInfo = 6
+	syslogSeverityErr  = 3
+	syslogAppName      = "volant"
+)
+
+// syslogExporter writes RFC 5424 messages to a syslog daemon, one datagram
+// per record over UDP and octet-counted frames over TCP and Unix sockets.
+type syslogExporter struct {
+	network  string
+	address  string
+	hostname string
+
+	mu   sync.Mutex
+	conn net.Conn
+}
+
+// NewSyslog returns an exporter for endpoint (udp://host:port,
+// tcp://host:port or unix:///path).
+func NewSyslog(endpoint, hostname string) Exporter {
+	parsed, _ := url.Parse(endpoint)
+	exporter := &syslogExporter{network: parsed.Scheme, address: parsed.Host, hostname: hostname}
+	if parsed.Scheme == "unix" {
+		exporter.network = "unixgram"
+		exporter.address = parsed.Path
+	}
+	return exporter
+}
+
+func (s *syslogExporter) Kind() string { return KindSyslog }
+
+func (s *syslogExporter) Export(ctx context.Context, records []Record) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.conn == nil {
+		conn, err := (&net.Dialer{}).DialContext(ctx, s.network, s.address)
+		if err != nil {
+			return err
+		}
+		s.conn = conn
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = s.conn.SetWriteDeadline(deadline)
+	}
+	for _, record := range records {
+		message := s.format(record)
+		if s.network == "tcp" {
+			message = fmt.Sprintf("%d %s", len(message), message)
+		}
+		if _, err := s.conn.Write([]byte(message)); err != nil {
+			// Redial on the next batch.
+			_ = s.conn.Close()
+			s.conn = nil
+			return err
+		}
+	}
+	return nil
+}
+
+// format renders record as an RFC 5424 message: the VM is the PROCID and
+// source.stream the MSGID.
+func (s *syslogExporter) format(record Record) string {
+	severity := syslogSeverityInfo
+	if record.Stream == "stderr" {
+		severity = syslogSeverityErr
+	}
+	return fmt.Sprintf("<%d>1 %s %s %s %s %s.%s - %s",
+		syslogFacility*8+severity,
+		record.Timestamp.UTC().Format(time.RFC3339Nano),
+		syslogField(s.hostname),
+		syslogAppName,
+		syslogField(record.VM),
+		record.Source,
+		record.Stream,
+		record.Line,
+	)
+}
+
+// syslogField renders a header field, which may not be empty or hold spaces.
+func syslogField(value string) string {
+	value = strings.Join(strings.Fields(value), "_")
+	if value == "" {
+		return "-"
+	}
+	return value
+}
+
+func (s *syslogExporter) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.conn == nil {
+		return nil
+	}
+	err := s.conn.Close()
+	s.conn = nil
+	return err
+}
@@ -0,0 +1,244 @@
+// Copyright (c) 2025 HYPR. PTE. LTD.
+//
+// Business Source License 1.1
+// See LICENSE file in the project root for details.
+
+// Package mcp serves the volantd API to Model Context Protocol clients. Tools
+// are derived from the OpenAPI spec and the actions of enabled plugins, and
+// every tool call is made against the REST API, so it passes the same
+// validation, admission and audit as any other client.
+package mcp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"runtime/debug"
+	"slices"
+	"sync"
+	"time"
+
+	openapi3 "github.com/getkin/kin-openapi/openapi3"
+)
+
+// LatestProtocolVersion is offered to clients asking for a protocol version
+// this server does not speak.
+const LatestProtocolVersion = "2025-06-18"
+
+var supportedProtocolVersions = []string{LatestProtocolVersion, "2025-03-26", "2024-11-05"}
+
+// JSON-RPC error codes.
+const (
+	CodeParseError     = -32700
+	CodeInvalidRequest = -32600
+	CodeMethodNotFound = -32601
+	CodeInvalidParams  = -32602
+	CodeInternalError  = -32603
+)
+
+const (
+	maxMessageBytes  = 4 << 20
+	maxResponseBytes = 8 << 20
+	pollInterval     = time.Second
+)
+
+// Request is a JSON-RPC 2.0 request, or a notification when ID is empty.
+type Request struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+}
+
+// Response is a JSON-RPC 2.0 response.
+type Response struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  any             `json:"result,omitempty"`
+	Error   *Error          `json:"error,omitempty"`
+}
+
+// Notification is a JSON-RPC 2.0 notification sent to the client.
+type Notification struct {
+	JSONRPC string `json:"jsonrpc"`
+	Method  string `json:"method"`
+	Params  any    `json:"params,omitempty"`
+}
+
+// Error is a JSON-RPC 2.0 error object.
+type Error struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+	Data    any    `json:"data,omitempty"`
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// Doer sends the API requests tool calls are made of. *http.Client satisfies
+// it; Handler adapts an in-process http.Handler.
+type Doer interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// Options configure how the server reaches the API.
+type Options struct {
+	// BaseURL is prepended to API paths. Leave it empty for Handler.
+	BaseURL string
+	// APIKey is sent with every API request when set.
+	APIKey string
+}
+
+// Server answers MCP requests. Use ServeHTTP or ServeStdio as transport.
+type Server struct {
+	api          Doer
+	opts         Options
+	logger       *slog.Logger
+	pollInterval time.Duration
+
+	mu   sync.Mutex
+	spec *openapi3.T
+}
+
+// New returns a server whose tools call the API through api.
+func New(api Doer, opts Options, logger *slog.Logger) *Server {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &Server{
+		api:          api,
+		opts:         opts,
+		logger:       logger.With("component", "mcp"),
+		pollInterval: pollInterval,
+	}
+}
+
+// decode parses one JSON-RPC message. The response is non-nil when the
+// message is not a valid request and should be answered with it.
+func decode(data []byte) (Request, *Response) {
+	var req Request
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '[' {
+		return req, errorResponse(nil, &Error{Code: CodeInvalidRequest, Message: "batch requests are not supported"})
+	}
+	if err := json.Unmarshal(data, &req); err != nil {
+		return req, errorResponse(nil, &Error{Code: CodeParseError, Message: err.Error()})
+	}
+	if req.JSONRPC != "2.0" || req.Method == "" {
+		return req, errorResponse(req.ID, &Error{Code: CodeInvalidRequest, Message: "expected a JSON-RPC 2.0 request"})
+	}
+	return req, nil
+}
+
+// Handle answers req, passing progress notifications to notify when it is
+// non-nil. It returns nil for notifications, which get no response.
+func (s *Server) Handle(ctx context.Context, req Request, notify func(Notification)) *Response {
+	result, err := s.dispatch(ctx, req, notify)
+	if len(req.ID) == 0 {
+		if err != nil {
+			s.logger.Debug("notification failed", "method", req.Method, "error", err)
+		}
+		return nil
+	}
+	if err != nil {
+		var rpcErr *Error
+		if !errors.As(err, &rpcErr) {
+			rpcErr = &Error{Code: CodeInternalError, Message: err.Error()}
+		}
+		return errorResponse(req.ID, rpcErr)
+	}
+	return &Response{JSONRPC: "2.0", ID: req.ID, Result: result}
+}
+
+func (s *Server) dispatch(ctx context.Context, req Request, notify func(Notification)) (any, error) {
+	switch req.Method {
+	case "initialize":
+		var params struct {
+			ProtocolVersion string `json:"protocolVersion"`
+		}
+		if err := unmarshalParams(req.Params, &params); err != nil {
+			return nil, err
+		}
+		version := params.ProtocolVersion
+		if !slices.Contains(supportedProtocolVersions, version) {
+			version = LatestProtocolVersion
+		}
+		return map[string]any{
+			"protocolVersion": version,
+			"capabilities": map[string]any{
+				"tools": map[string]any{"listChanged": false},
+			},
+			"serverInfo": map[string]any{
+				"name":    "volantd",
+				"version": serverVersion(),
+			},
+			"instructions": "Tools map to the volantd REST API (operation IDs from its OpenAPI spec) and to the actions of enabled plugins (plugin_<plugin>_<action>). Path and query parameters are top-level arguments; request bodies go in body.",
+		}, nil
+	case "ping":
+		return map[string]any{}, nil
+	case "tools/list":
+		tools, err := s.tools(ctx, true)
+		if err != nil {
+			return nil, err
+		}
+		list := make([]Tool, 0, len(tools))
+		for _, t := range tools {
+			list = append(list, t.Tool)
+		}
+		return map[string]any{"tools": list}, nil
+	case "tools/call":
+		var params struct {
+			Name      string         `json:"name"`
+			Arguments map[string]any `json:"arguments"`
+			Meta      struct {
+				ProgressToken json.RawMessage `json:"progressToken"`
+			} `json:"_meta"`
+		}
+		if err := unmarshalParams(req.Params, &params); err != nil {
+			return nil, err
+		}
+		var progress func(step float64, message string)
+		if len(params.Meta.ProgressToken) > 0 && notify != nil {
+			progress = func(step float64, message string) {
+				notify(Notification{JSONRPC: "2.0", Method: "notifications/progress", Params: map[string]any{
+					"progressToken": params.Meta.ProgressToken,
+					"progress":      step,
+					"message":       message,
+				}})
+			}
+		}
+		return s.call(ctx, params.Name, params.Arguments, progress)
+	default:
+		if len(req.ID) == 0 {
+			// Unknown notifications, including notifications/initialized and
+			// notifications/cancelled, need no action here.
+			return nil, nil
+		}
+		return nil, &Error{Code: CodeMethodNotFound, Message: fmt.Sprintf("method not found: %s", req.Method)}
+	}
+}
+
+func unmarshalParams(raw json.RawMessage, v any) error {
+	if len(raw) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(raw, v); err != nil {
+		return &Error{Code: CodeInvalidParams, Message: err.Error()}
+	}
+	return nil
+}
+
+func errorResponse(id json.RawMessage, err *Error) *Response {
+	return &Response{JSONRPC: "2.0", ID: id, Error: err}
+}
+
+func serverVersion() string {
+	if info, ok := debug.ReadBuildInfo(); ok && info.Main.Version != "" {
+		return info.Main.Version
+	}
+	return "dev"
+}
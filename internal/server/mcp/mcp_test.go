@@ -0,0 +1,322 @@
+// Copyright (c) 2025 HYPR. PTE. LTD.
+//
+// Business Source License 1.1
+// See LICENSE file in the project root for details.
+
+package mcp
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+const testSpec = `{
+  "openapi": "3.0.3",
+  "info": {"title": "volantd", "version": "test"},
+  "paths": {
+    "/api/v1/vms": {
+      "get": {"operationId": "listVMs", "summary": "List VMs",
+        "parameters": [{"name": "limit", "in": "query", "schema": {"type": "integer", "minimum": 0}}],
+        "responses": {"200": {"description": "ok"}}},
+      "post": {"operationId": "createVM", "summary": "Create VM",
+        "requestBody": {"required": true, "content": {"application/json": {"schema": {"$ref": "#/components/schemas/createVMRequest"}}}},
+        "responses": {"201": {"description": "created"}}}
+    },
+    "/api/v1/vms/{name}": {
+      "get": {"operationId": "getVM", "summary": "Get VM",
+        "parameters": [{"name": "name", "in": "path", "required": true, "schema": {"type": "string"}}],
+        "responses": {"200": {"description": "ok"}}},
+      "delete": {"operationId": "destroyVM", "summary": "Destroy VM",
+        "parameters": [{"name": "name", "in": "path", "required": true, "schema": {"type": "string"}}],
+        "responses": {"204": {"description": "deleted"}}}
+    },
+    "/api/v1/events/vms": {
+      "get": {"operationId": "streamVMEvents", "summary": "Stream events",
+        "responses": {"200": {"description": "stream", "content": {"text/event-stream": {"schema": {"type": "string"}}}}}}
+    },
+    "/api/v1/vms/{name}/files/{path}": {
+      "put": {"operationId": "putVMFile", "summary": "Upload a file",
+        "parameters": [{"name": "name", "in": "path", "required": true, "schema": {"type": "string"}},
+          {"name": "path", "in": "path", "required": true, "schema": {"type": "string"}}],
+        "requestBody": {"required": true, "content": {"application/octet-stream": {"schema": {"type": "string", "format": "binary"}}}},
+        "responses": {"201": {"description": "created"}}}
+    },
+    "/api/v1/mcp": {
+      "post": {"operationId": "postMCPMessage", "responses": {"200": {"description": "ok"}}}
+    }
+  },
+  "components": {"schemas": {
+    "createVMRequest": {"type": "object", "required": ["name"], "properties": {
+      "name": {"type": "string", "minLength": 1},
+      "labels": {"type": "object", "additionalProperties": {"type": "string"}}
+    }}
+  }}
+}`
+
+// fakeAPI records the requests tools make and answers like volantd.
+type fakeAPI struct {
+	mu       sync.Mutex
+	requests []string
+	bodies   []string
+	polls    int
+}
+
+func (f *fakeAPI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+	f.mu.Lock()
+	f.requests = append(f.requests, r.Method+" "+r.URL.RequestURI()+" key="+r.Header.Get("X-Volant-API-Key"))
+	f.bodies = append(f.bodies, string(body))
+	f.mu.Unlock()
+
+	w.Header().Set("Content-Type", "application/json")
+	switch {
+	case r.URL.Path == "/openapi":
+		io.WriteString(w, testSpec)
+	case r.URL.Path == "/api/v1/plugins":
+		io.WriteString(w, `{"plugins": ["browser", "off"]}`)
+	case r.URL.Path == "/api/v1/plugins/browser":
+		io.WriteString(w, `{"name": "browser", "enabled": true, "actions": {"navigate": {"description": "Open a URL", "method": "POST", "path": "/v1/browser/navigate"}}}`)
+	case r.URL.Path == "/api/v1/plugins/off":
+		io.WriteString(w, `{"name": "off", "enabled": false, "actions": {"noop": {"path": "/noop"}}}`)
+	case r.URL.Path == "/api/v1/vms" && r.Method == http.MethodPost:
+		w.Header().Set("Location", "/api/v1/operations/op-1")
+		w.WriteHeader(http.StatusAccepted)
+		io.WriteString(w, `{"id": "op-1", "status": "pending", "done": false}`)
+	case r.URL.Path == "/api/v1/operations/op-1":
+		f.mu.Lock()
+		f.polls++
+		polls := f.polls
+		f.mu.Unlock()
+		if polls < 2 {
+			io.WriteString(w, `{"id": "op-1", "status": "running", "done": false}`)
+			return
+		}
+		io.WriteString(w, `{"id": "op-1", "status": "succeeded", "done": true}`)
+	case r.URL.Path == "/api/v1/vms/missing":
+		w.WriteHeader(http.StatusNotFound)
+		io.WriteString(w, `{"code": "VM_NOT_FOUND", "message": "vm not found"}`)
+	default:
+		io.WriteString(w, `{"ok": true}`)
+	}
+}
+
+func (f *fakeAPI) seen() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]string(nil), f.requests...)
+}
+
+func newTestServer(api *fakeAPI) *Server {
+	s := New(Handler(api), Options{APIKey: "secret"}, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	s.pollInterval = time.Millisecond
+	return s
+}
+
+func call(t *testing.T, s *Server, method string, params any) *Response {
+	t.Helper()
+	raw, _ := json.Marshal(params)
+	resp := s.Handle(context.Background(), Request{JSONRPC: "2.0", ID: json.RawMessage(`1`), Method: method, Params: raw}, nil)
+	if resp == nil {
+		t.Fatalf("%s: no response", method)
+	}
+	return resp
+}
+
+func decodeResult(t *testing.T, resp *Response, v any) {
+	t.Helper()
+	if resp.Error != nil {
+		t.Fatalf("unexpected error %+v", resp.Error)
+	}
+	data, _ := json.Marshal(resp.Result)
+	if err := json.Unmarshal(data, v); err != nil {
+		t.Fatalf("decode result: %v", err)
+	}
+}
+
+func TestToolsFromSpecAndPlugins(t *testing.T) {
+	s := newTestServer(&fakeAPI{})
+
+	var init struct {
+		ProtocolVersion string `json:"protocolVersion"`
+	}
+	decodeResult(t, call(t, s, "initialize", map[string]any{"protocolVersion": "2024-11-05"}), &init)
+	if init.ProtocolVersion != "2024-11-05" {
+		t.Errorf("negotiated %q", init.ProtocolVersion)
+	}
+	decodeResult(t, call(t, s, "initialize", map[string]any{"protocolVersion": "1999-01-01"}), &init)
+	if init.ProtocolVersion != LatestProtocolVersion {
+		t.Errorf("unknown version answered with %q", init.ProtocolVersion)
+	}
+
+	var list struct {
+		Tools []Tool `json:"tools"`
+	}
+	decodeResult(t, call(t, s, "tools/list", nil), &list)
+	tools := map[string]Tool{}
+	var names []string
+	for _, tool := range list.Tools {
+		tools[tool.Name] = tool
+		names = append(names, tool.Name)
+	}
+	if want := "createVM destroyVM getVM listVMs plugin_browser_navigate"; strings.Join(names, " ") != want {
+		t.Fatalf("tools = %v, want %s", names, want)
+	}
+	if a := tools["listVMs"].Annotations; a == nil || !a.ReadOnlyHint {
+		t.Errorf("listVMs not read-only: %+v", a)
+	}
+	if a := tools["destroyVM"].Annotations; a == nil || a.DestructiveHint == nil || !*a.DestructiveHint {
+		t.Errorf("destroyVM not destructive: %+v", a)
+	}
+	schema, _ := json.Marshal(tools["createVM"].InputSchema)
+	for _, want := range []string{`"required":["body"]`, `"required":["name"]`, `"additionalProperties":{"type":"string"}`} {
+		if !strings.Contains(string(schema), want) {
+			t.Errorf("createVM schema %s lacks %s", schema, want)
+		}
+	}
+	if strings.Contains(string(schema), "$ref") {
+		t.Errorf("createVM schema has unresolved refs: %s", schema)
+	}
+}
+
+func TestCallBuildsAPIRequests(t *testing.T) {
+	api := &fakeAPI{}
+	s := newTestServer(api)
+
+	var result CallToolResult
+	decodeResult(t, call(t, s, "tools/call", map[string]any{"name": "getVM", "arguments": map[string]any{"name": "web 1"}}), &result)
+	if result.IsError || result.StructuredContent == nil {
+		t.Errorf("getVM result %+v", result)
+	}
+	decodeResult(t, call(t, s, "tools/call", map[string]any{"name": "listVMs", "arguments": map[string]any{"limit": 5}}), &result)
+	decodeResult(t, call(t, s, "tools/call", map[string]any{"name": "getVM", "arguments": map[string]any{"name": "missing"}}), &result)
+	if !result.IsError || !strings.Contains(result.Content[0].Text, "VM_NOT_FOUND") {
+		t.Errorf("missing VM result %+v", result)
+	}
+	decodeResult(t, call(t, s, "tools/call", map[string]any{"name": "plugin_browser_navigate", "arguments": map[string]any{"vm": "web-1", "body": map[string]any{"url": "https://example.com"}}}), &result)
+
+	seen := strings.Join(api.seen(), "\n")
+	for _, want := range []string{
+		"GET /api/v1/vms/web%201 key=secret",
+		"GET /api/v1/vms?limit=5 key=secret",
+		"POST /api/v1/vms/web-1/actions/browser/navigate key=secret",
+	} {
+		if !strings.Contains(seen, want) {
+			t.Errorf("requests lack %q:\n%s", want, seen)
+		}
+	}
+	if last := api.bodies[len(api.bodies)-1]; last != `{"url":"https://example.com"}` {
+		t.Errorf("action body %s", last)
+	}
+
+	if resp := call(t, s, "tools/call", map[string]any{"name": "getVM", "arguments": map[string]any{}}); resp.Error == nil || resp.Error.Code != CodeInvalidParams {
+		t.Errorf("missing path argument: %+v", resp.Error)
+	}
+	if resp := call(t, s, "tools/call", map[string]any{"name": "streamVMEvents"}); resp.Error == nil || resp.Error.Code != CodeInvalidParams {
+		t.Errorf("streaming operation callable: %+v", resp.Error)
+	}
+	if resp := call(t, s, "resources/list", nil); resp.Error == nil || resp.Error.Code != CodeMethodNotFound {
+		t.Errorf("unknown method: %+v", resp.Error)
+	}
+}
+
+func TestHTTPStreamsProgress(t *testing.T) {
+	srv := httptest.NewServer(newTestServer(&fakeAPI{}))
+	defer srv.Close()
+
+	post := func(accept, body string) *http.Response {
+		req, _ := http.NewRequest(http.MethodPost, srv.URL, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Accept", accept)
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("post: %v", err)
+		}
+		return resp
+	}
+
+	resp := post("application/json, text/event-stream", `{"jsonrpc":"2.0","id":7,"method":"tools/call","params":{"name":"createVM","arguments":{"body":{"name":"web"}},"_meta":{"progressToken":"tok"}}}`)
+	defer resp.Body.Close()
+	if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Fatalf("content type %q", ct)
+	}
+	var messages []map[string]any
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		if data, ok := strings.CutPrefix(scanner.Text(), "data: "); ok {
+			var message map[string]any
+			if err := json.Unmarshal([]byte(data), &message); err != nil {
+				t.Fatalf("decode %s: %v", data, err)
+			}
+			messages = append(messages, message)
+		}
+	}
+	if len(messages) != 4 {
+		t.Fatalf("messages %v", messages)
+	}
+	for i, status := range []string{"pending", "running", "succeeded"} {
+		params, _ := messages[i]["params"].(map[string]any)
+		if messages[i]["method"] != "notifications/progress" || params["progressToken"] != "tok" || !strings.HasSuffix(params["message"].(string), status) {
+			t.Errorf("message %d = %v", i, messages[i])
+		}
+	}
+	final, _ := messages[3]["result"].(map[string]any)
+	if messages[3]["id"] != float64(7) || final == nil || final["isError"] == true {
+		t.Errorf("final message %v", messages[3])
+	}
+
+	plain := post("application/json", `{"jsonrpc":"2.0","id":"a","method":"ping"}`)
+	defer plain.Body.Close()
+	if ct := plain.Header.Get("Content-Type"); ct != "application/json" {
+		t.Errorf("ping content type %q", ct)
+	}
+	notification := post("application/json", `{"jsonrpc":"2.0","method":"notifications/initialized"}`)
+	notification.Body.Close()
+	if notification.StatusCode != http.StatusAccepted {
+		t.Errorf("notification status %d", notification.StatusCode)
+	}
+	batch := post("application/json", `[{"jsonrpc":"2.0","id":1,"method":"ping"}]`)
+	batch.Body.Close()
+	if batch.StatusCode != http.StatusBadRequest {
+		t.Errorf("batch status %d", batch.StatusCode)
+	}
+}
+
+func TestServeStdio(t *testing.T) {
+	s := newTestServer(&fakeAPI{})
+	input := strings.Join([]string{
+		`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2025-06-18"}}`,
+		`{"jsonrpc":"2.0","method":"notifications/initialized"}`,
+		`not json`,
+		`{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"listVMs","arguments":{}}}`,
+	}, "\n")
+	var out strings.Builder
+	if err := s.ServeStdio(context.Background(), strings.NewReader(input), &out); err != nil {
+		t.Fatalf("ServeStdio: %v", err)
+	}
+	byID := map[string]Response{}
+	for _, line := range strings.Split(strings.TrimSpace(out.String()), "\n") {
+		var resp Response
+		if err := json.Unmarshal([]byte(line), &resp); err != nil {
+			t.Fatalf("decode %s: %v", line, err)
+		}
+		byID[string(resp.ID)] = resp
+	}
+	if len(byID) != 3 {
+		t.Fatalf("responses %v", out.String())
+	}
+	if byID["null"].Error == nil || byID["null"].Error.Code != CodeParseError {
+		t.Errorf("parse error response %+v", byID["null"])
+	}
+	if byID["1"].Error != nil || byID["2"].Error != nil {
+		t.Errorf("unexpected errors %+v %+v", byID["1"], byID["2"])
+	}
+}
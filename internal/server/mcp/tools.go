@@ -0,0 +1,570 @@
+// Copyright (c) 2025 HYPR. PTE. LTD.
+//
+// Business Source License 1.1
+// See LICENSE file in the project root for details.
+
+package mcp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	openapi3 "github.com/getkin/kin-openapi/openapi3"
+
+	"github.com/volantvm/volant/internal/pluginspec"
+	"github.com/volantvm/volant/internal/server/apiauth"
+	"github.com/volantvm/volant/internal/server/tracing"
+)
+
+// pluginToolPrefix names the tools that run plugin actions.
+const pluginToolPrefix = "plugin_"
+
+// maxSchemaDepth bounds how far nested schemas are inlined, which also stops
+// recursive types from expanding forever.
+const maxSchemaDepth = 12
+
+// Tool is an MCP tool definition.
+type Tool struct {
+	Name        string           `json:"name"`
+	Title       string           `json:"title,omitempty"`
+	Description string           `json:"description,omitempty"`
+	InputSchema map[string]any   `json:"inputSchema"`
+	Annotations *ToolAnnotations `json:"annotations,omitempty"`
+}
+
+// ToolAnnotations describe how a tool behaves.
+type ToolAnnotations struct {
+	ReadOnlyHint    bool  `json:"readOnlyHint,omitempty"`
+	DestructiveHint *bool `json:"destructiveHint,omitempty"`
+	IdempotentHint  bool  `json:"idempotentHint,omitempty"`
+}
+
+// Content is one item of a tool result.
+type Content struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+// CallToolResult is the result of tools/call.
+type CallToolResult struct {
+	Content           []Content `json:"content"`
+	StructuredContent any       `json:"structuredContent,omitempty"`
+	IsError           bool      `json:"isError,omitempty"`
+}
+
+type apiParam struct {
+	name     string
+	in       string
+	required bool
+}
+
+// tool binds a Tool to the API request that implements it.
+type tool struct {
+	Tool
+	method string
+	path   string
+	params []apiParam
+	body   bool
+	// plugin and action are set for plugin action tools.
+	plugin string
+	action string
+}
+
+var toolNameReplacer = regexp.MustCompile(`[^A-Za-z0-9_-]+`)
+
+func toolName(parts ...string) string {
+	name := toolNameReplacer.ReplaceAllString(strings.Join(parts, "_"), "_")
+	if len(name) > 64 {
+		name = name[:64]
+	}
+	return name
+}
+
+// tools returns the tools derived from the spec, plus plugin actions when
+// withPlugins is set, sorted by name.
+func (s *Server) tools(ctx context.Context, withPlugins bool) ([]tool, error) {
+	doc, err := s.loadSpec(ctx)
+	if err != nil {
+		return nil, err
+	}
+	tools := specTools(doc)
+	if withPlugins {
+		plugins, err := s.pluginTools(ctx)
+		if err != nil {
+			// The API tools still work without the registry.
+			s.logger.Warn("list plugin actions", "error", err)
+		}
+		tools = append(tools, plugins...)
+	}
+	sort.Slice(tools, func(i, j int) bool { return tools[i].Name < tools[j].Name })
+	return tools, nil
+}
+
+func (s *Server) loadSpec(ctx context.Context) (*openapi3.T, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.spec != nil {
+		return s.spec, nil
+	}
+	status, _, data, err := s.do(ctx, http.MethodGet, "/openapi", nil)
+	if err != nil {
+		return nil, fmt.Errorf("fetch openapi spec: %w", err)
+	}
+	if status != http.StatusOK {
+		return nil, fmt.Errorf("fetch openapi spec: status %d", status)
+	}
+	doc, err := openapi3.NewLoader().LoadFromData(data)
+	if err != nil {
+		return nil, fmt.Errorf("load openapi spec: %w", err)
+	}
+	s.spec = doc
+	return doc, nil
+}
+
+// specTools turns every /api/v1 operation into a tool. Streaming endpoints,
+// file transfers and the MCP endpoint itself are left out.
+func specTools(doc *openapi3.T) []tool {
+	var tools []tool
+	for path, item := range doc.Paths.Map() {
+		if !strings.HasPrefix(path, "/api/v1/") || path == "/api/v1/mcp" {
+			continue
+		}
+		for method, op := range item.Operations() {
+			if op.OperationID == "" || streams(op) || transfersFiles(op) {
+				continue
+			}
+			t := tool{method: method, path: path}
+			properties := map[string]any{}
+			var required []string
+			for _, ref := range append(append(openapi3.Parameters{}, item.Parameters...), op.Parameters...) {
+				p := ref.Value
+				if p == nil || (p.In != openapi3.ParameterInPath && p.In != openapi3.ParameterInQuery) {
+					continue
+				}
+				schema := inlineSchema(p.Schema, 0)
+				if p.Description != "" {
+					schema["description"] = p.Description
+				}
+				properties[p.Name] = schema
+				required = appendRequired(required, p.Name, p.Required)
+				t.params = append(t.params, apiParam{name: p.Name, in: p.In, required: p.Required})
+			}
+			if body := op.RequestBody; body != nil && body.Value != nil {
+				if media := body.Value.Content.Get("application/json"); media != nil {
+					schema := inlineSchema(media.Schema, 0)
+					if body.Value.Description != "" {
+						schema["description"] = body.Value.Description
+					}
+					properties["body"] = schema
+					required = appendRequired(required, "body", body.Value.Required)
+					t.body = true
+				}
+			}
+			input := map[string]any{"type": "object", "properties": properties}
+			if len(required) > 0 {
+				input["required"] = required
+			}
+
+			description := op.Summary
+			if op.Description != "" {
+				description = strings.TrimSpace(description + ". " + op.Description)
+			}
+			t.Tool = Tool{
+				Name:        toolName(op.OperationID),
+				Title:       op.Summary,
+				Description: fmt.Sprintf("%s (%s %s)", description, method, path),
+				InputSchema: input,
+				Annotations: annotations(method),
+			}
+			tools = append(tools, t)
+		}
+	}
+	return tools
+}
+
+func appendRequired(required []string, name string, ok bool) []string {
+	if ok {
+		return append(required, name)
+	}
+	return required
+}
+
+func streams(op *openapi3.Operation) bool {
+	if op.Responses == nil {
+		return false
+	}
+	for _, resp := range op.Responses.Map() {
+		if resp.Value != nil && resp.Value.Content.Get("text/event-stream") != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// transfersFiles reports whether an operation sends or returns raw bytes,
+// which tool arguments and results cannot carry.
+func transfersFiles(op *openapi3.Operation) bool {
+	if op.RequestBody != nil && op.RequestBody.Value != nil && op.RequestBody.Value.Content.Get("application/octet-stream") != nil {
+		return true
+	}
+	if op.Responses == nil {
+		return false
+	}
+	for _, resp := range op.Responses.Map() {
+		if resp.Value != nil && resp.Value.Content.Get("application/octet-stream") != nil {
+			return true
+		}
+	}
+	return false
+}
+
+func annotations(method string) *ToolAnnotations {
+	destructive := method == http.MethodDelete
+	switch method {
+	case http.MethodGet, http.MethodHead:
+		return &ToolAnnotations{ReadOnlyHint: true}
+	case http.MethodPut, http.MethodDelete:
+		return &ToolAnnotations{DestructiveHint: &destructive, IdempotentHint: true}
+	default:
+		return &ToolAnnotations{DestructiveHint: &destructive}
+	}
+}
+
+// inlineSchema renders ref as a self-contained JSON schema: MCP clients
+// cannot resolve references into the spec's components.
+func inlineSchema(ref *openapi3.SchemaRef, depth int) map[string]any {
+	out := map[string]any{}
+	if ref == nil || ref.Value == nil || depth > maxSchemaDepth {
+		return out
+	}
+	shallow := *ref.Value
+	shallow.Properties = nil
+	shallow.Items = nil
+	shallow.OneOf, shallow.AnyOf, shallow.AllOf, shallow.Not = nil, nil, nil, nil
+	shallow.AdditionalProperties = openapi3.AdditionalProperties{Has: ref.Value.AdditionalProperties.Has}
+	data, err := json.Marshal(&shallow)
+	if err != nil || json.Unmarshal(data, &out) != nil {
+		return map[string]any{}
+	}
+	// OpenAPI-only keywords mean nothing to JSON schema consumers.
+	delete(out, "nullable")
+	delete(out, "readOnly")
+	delete(out, "writeOnly")
+
+	schema := ref.Value
+	if len(schema.Properties) > 0 {
+		properties := make(map[string]any, len(schema.Properties))
+		for name, prop := range schema.Properties {
+			properties[name] = inlineSchema(prop, depth+1)
+		}
+		out["properties"] = properties
+	}
+	if schema.Items != nil {
+		out["items"] = inlineSchema(schema.Items, depth+1)
+	}
+	if extra := schema.AdditionalProperties.Schema; extra != nil {
+		out["additionalProperties"] = inlineSchema(extra, depth+1)
+	}
+	for key, refs := range map[string]openapi3.SchemaRefs{"oneOf": schema.OneOf, "anyOf": schema.AnyOf, "allOf": schema.AllOf} {
+		if len(refs) == 0 {
+			continue
+		}
+		list := make([]any, 0, len(refs))
+		for _, r := range refs {
+			list = append(list, inlineSchema(r, depth+1))
+		}
+		out[key] = list
+	}
+	return out
+}
+
+// pluginTools lists the actions of enabled plugins.
+func (s *Server) pluginTools(ctx context.Context) ([]tool, error) {
+	var listing struct {
+		Plugins []string `json:"plugins"`
+	}
+	if err := s.getJSON(ctx, "/api/v1/plugins", &listing); err != nil {
+		return nil, err
+	}
+	var tools []tool
+	for _, name := range listing.Plugins {
+		var manifest pluginspec.Manifest
+		if err := s.getJSON(ctx, "/api/v1/plugins/"+url.PathEscape(name), &manifest); err != nil {
+			s.logger.Warn("describe plugin", "plugin", name, "error", err)
+			continue
+		}
+		if !manifest.Enabled {
+			continue
+		}
+		for actionName, action := range manifest.Actions {
+			description := action.Description
+			if description == "" {
+				description = fmt.Sprintf("Run the %s action", actionName)
+			}
+			destructive := false
+			tools = append(tools, tool{
+				Tool: Tool{
+					Name:        toolName(pluginToolPrefix+name, actionName),
+					Title:       fmt.Sprintf("%s: %s", name, actionName),
+					Description: fmt.Sprintf("%s (plugin %s). Runs in the agent of vm when given, otherwise against the plugin's service.", description, name),
+					InputSchema: map[string]any{
+						"type": "object",
+						"properties": map[string]any{
+							"vm":   map[string]any{"type": "string", "description": "VM to run the action in"},
+							"body": map[string]any{"type": "object", "description": "JSON payload passed to the action"},
+						},
+					},
+					Annotations: &ToolAnnotations{DestructiveHint: &destructive},
+				},
+				method: http.MethodPost,
+				plugin: name,
+				action: actionName,
+			})
+		}
+	}
+	return tools, nil
+}
+
+func (s *Server) getJSON(ctx context.Context, path string, v any) error {
+	status, _, data, err := s.do(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return err
+	}
+	if status != http.StatusOK {
+		return fmt.Errorf("GET %s: status %d", path, status)
+	}
+	return json.Unmarshal(data, v)
+}
+
+// call runs the named tool. API failures are reported as tool errors so the
+// model can see and react to them; protocol errors are returned as errors.
+func (s *Server) call(ctx context.Context, name string, args map[string]any, progress func(float64, string)) (*CallToolResult, error) {
+	tools, err := s.tools(ctx, strings.HasPrefix(name, pluginToolPrefix))
+	if err != nil {
+		return nil, err
+	}
+	i := sort.Search(len(tools), func(i int) bool { return tools[i].Name >= name })
+	if i == len(tools) || tools[i].Name != name {
+		return nil, &Error{Code: CodeInvalidParams, Message: fmt.Sprintf("unknown tool: %s", name)}
+	}
+	t := tools[i]
+
+	path, body, err := t.request(args)
+	if err != nil {
+		return nil, &Error{Code: CodeInvalidParams, Message: err.Error()}
+	}
+	status, header, data, err := s.do(ctx, t.method, path, body)
+	if err != nil {
+		return nil, err
+	}
+	if location := header.Get("Location"); status == http.StatusAccepted && strings.HasPrefix(location, "/api/v1/operations/") {
+		status, data, err = s.follow(ctx, location, data, progress)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return toolResult(status, data), nil
+}
+
+// request builds the API path and body for a call with args.
+func (t tool) request(args map[string]any) (string, []byte, error) {
+	if t.plugin != "" {
+		path := "/api/v1/plugins/" + url.PathEscape(t.plugin) + "/actions/" + url.PathEscape(t.action)
+		if vm, _ := args["vm"].(string); vm != "" {
+			path = "/api/v1/vms/" + url.PathEscape(vm) + "/actions/" + url.PathEscape(t.plugin) + "/" + url.PathEscape(t.action)
+		}
+		payload, ok := args["body"]
+		if !ok || payload == nil {
+			payload = map[string]any{}
+		}
+		body, err := json.Marshal(payload)
+		return path, body, err
+	}
+
+	path := t.path
+	query := url.Values{}
+	for _, p := range t.params {
+		value, ok := args[p.name]
+		if !ok || value == nil {
+			if p.required {
+				return "", nil, fmt.Errorf("missing argument %q", p.name)
+			}
+			continue
+		}
+		if p.in == openapi3.ParameterInPath {
+			path = strings.ReplaceAll(path, "{"+p.name+"}", url.PathEscape(formatArg(value)))
+			continue
+		}
+		if list, ok := value.([]any); ok {
+			for _, item := range list {
+				query.Add(p.name, formatArg(item))
+			}
+			continue
+		}
+		query.Set(p.name, formatArg(value))
+	}
+	if len(query) > 0 {
+		path += "?" + query.Encode()
+	}
+	var body []byte
+	if payload, ok := args["body"]; ok && t.body {
+		data, err := json.Marshal(payload)
+		if err != nil {
+			return "", nil, err
+		}
+		body = data
+	}
+	return path, body, nil
+}
+
+func formatArg(value any) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case float64:
+		if v == math.Trunc(v) && math.Abs(v) < 1<<53 {
+			return strconv.FormatInt(int64(v), 10)
+		}
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// follow polls an asynchronous operation until it is done, reporting each
+// status change as progress, and returns the final operation.
+func (s *Server) follow(ctx context.Context, location string, accepted []byte, progress func(float64, string)) (int, []byte, error) {
+	var (
+		last   string
+		step   float64
+		status = http.StatusAccepted
+		data   = accepted
+	)
+	for {
+		var op struct {
+			ID     string `json:"id"`
+			Status string `json:"status"`
+			Done   bool   `json:"done"`
+			Error  string `json:"error"`
+		}
+		if err := json.Unmarshal(data, &op); err != nil || status >= http.StatusBadRequest {
+			return status, data, nil
+		}
+		if op.Status != last {
+			last = op.Status
+			step++
+			if progress != nil {
+				message := fmt.Sprintf("operation %s %s", op.ID, op.Status)
+				if op.Error != "" {
+					message += ": " + op.Error
+				}
+				progress(step, message)
+			}
+		}
+		if op.Done {
+			if op.Status == "failed" {
+				return http.StatusInternalServerError, data, nil
+			}
+			return http.StatusOK, data, nil
+		}
+		select {
+		case <-ctx.Done():
+			return 0, nil, ctx.Err()
+		case <-time.After(s.pollInterval):
+		}
+		var err error
+		status, _, data, err = s.do(ctx, http.MethodGet, location, nil)
+		if err != nil {
+			return 0, nil, err
+		}
+	}
+}
+
+func toolResult(status int, data []byte) *CallToolResult {
+	text := string(data)
+	if strings.TrimSpace(text) == "" {
+		text = fmt.Sprintf("%d %s", status, http.StatusText(status))
+	}
+	result := &CallToolResult{
+		Content: []Content{{Type: "text", Text: text}},
+		IsError: status >= http.StatusBadRequest,
+	}
+	var object map[string]any
+	if json.Unmarshal(data, &object) == nil && object != nil {
+		result.StructuredContent = object
+	}
+	return result
+}
+
+type callerKey struct{}
+
+// caller is the client of the MCP endpoint, whose credentials and address
+// in-process API requests carry so they are authorized and filtered as its
+// own requests would be.
+type caller struct {
+	remoteAddr string
+	header     http.Header
+}
+
+func withCaller(ctx context.Context, r *http.Request) context.Context {
+	header := http.Header{}
+	for _, key := range []string{apiauth.Header, "Authorization"} {
+		if values := r.Header.Values(key); len(values) > 0 {
+			header[key] = values
+		}
+	}
+	if header.Get(apiauth.Header) == "" {
+		if key := r.URL.Query().Get(apiauth.QueryParam); key != "" {
+			header.Set(apiauth.Header, key)
+		}
+	}
+	return context.WithValue(ctx, callerKey{}, caller{remoteAddr: r.RemoteAddr, header: header})
+}
+
+// do sends one API request and returns its status, headers and body.
+func (s *Server) do(ctx context.Context, method, path string, body []byte) (int, http.Header, []byte, error) {
+	var reader io.Reader
+	if body != nil {
+		reader = bytes.NewReader(body)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, s.opts.BaseURL+path, reader)
+	if err != nil {
+		return 0, nil, nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if s.opts.APIKey != "" {
+		req.Header.Set(apiauth.Header, s.opts.APIKey)
+	}
+	if c, ok := ctx.Value(callerKey{}).(caller); ok {
+		for key, values := range c.header {
+			req.Header[key] = values
+		}
+		req.RemoteAddr = c.remoteAddr
+	}
+	tracing.Inject(ctx, req.Header)
+
+	resp, err := s.api.Do(req)
+	if err != nil {
+		return 0, nil, nil, err
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseBytes))
+	if err != nil {
+		return 0, nil, nil, err
+	}
+	return resp.StatusCode, resp.Header, data, nil
+}
@@ -0,0 +1,222 @@
+// Copyright (c) 2025 HYPR. PTE. LTD.
+//
+// Business Source License 1.1
+// See LICENSE file in the project root for details.
+
+package mcp
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// Handler returns a Doer that serves API requests with h in-process.
+func Handler(h http.Handler) Doer {
+	return handlerDoer{handler: h}
+}
+
+type handlerDoer struct {
+	handler http.Handler
+}
+
+func (d handlerDoer) Do(req *http.Request) (*http.Response, error) {
+	// Handlers may rely on what http.Server guarantees for incoming requests.
+	if req.Body == nil {
+		req.Body = http.NoBody
+	}
+	if req.RequestURI == "" {
+		req.RequestURI = req.URL.RequestURI()
+	}
+	rec := &recorder{header: http.Header{}, status: http.StatusOK}
+	d.handler.ServeHTTP(rec, req)
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", rec.status, http.StatusText(rec.status)),
+		StatusCode:    rec.status,
+		Header:        rec.header,
+		Body:          io.NopCloser(&rec.body),
+		ContentLength: int64(rec.body.Len()),
+		Request:       req,
+	}, nil
+}
+
+// recorder buffers a response written by an in-process handler.
+type recorder struct {
+	header      http.Header
+	status      int
+	wroteHeader bool
+	body        bytes.Buffer
+}
+
+func (r *recorder) Header() http.Header {
+	return r.header
+}
+
+func (r *recorder) WriteHeader(status int) {
+	if r.wroteHeader {
+		return
+	}
+	r.status = status
+	r.wroteHeader = true
+}
+
+func (r *recorder) Write(p []byte) (int, error) {
+	r.WriteHeader(http.StatusOK)
+	return r.body.Write(p)
+}
+
+// Flush is a no-op: the whole response is returned once the handler is done.
+func (r *recorder) Flush() {}
+
+// ServeHTTP implements the Streamable HTTP transport without sessions. Each
+// POST carries one message. Requests are answered with JSON, or with an SSE
+// stream when the client accepts one and the call reports progress, in which
+// case the progress notifications precede the response on the stream.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		// No server-initiated stream is offered on GET.
+		w.Header().Set("Allow", http.MethodPost)
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	data, err := io.ReadAll(io.LimitReader(r.Body, maxMessageBytes))
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResponse(nil, &Error{Code: CodeParseError, Message: err.Error()}))
+		return
+	}
+	req, errResp := decode(data)
+	if errResp != nil {
+		writeJSON(w, http.StatusBadRequest, errResp)
+		return
+	}
+	ctx := withCaller(r.Context(), r)
+	if len(req.ID) == 0 {
+		s.Handle(ctx, req, nil)
+		w.WriteHeader(http.StatusAccepted)
+		return
+	}
+
+	var stream *sseStream
+	notify := func(Notification) {}
+	if flusher, ok := w.(http.Flusher); ok && strings.Contains(r.Header.Get("Accept"), "text/event-stream") {
+		stream = &sseStream{w: w, flusher: flusher}
+		notify = func(n Notification) { stream.send(n) }
+	}
+	resp := s.Handle(ctx, req, notify)
+	if stream != nil && stream.started {
+		stream.send(resp)
+		return
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// sseStream switches the response to an event stream on the first message.
+type sseStream struct {
+	w       http.ResponseWriter
+	flusher http.Flusher
+	started bool
+}
+
+func (s *sseStream) send(message any) {
+	data, err := json.Marshal(message)
+	if err != nil {
+		return
+	}
+	if !s.started {
+		s.started = true
+		s.w.Header().Set("Content-Type", "text/event-stream")
+		s.w.Header().Set("Cache-Control", "no-cache")
+		s.w.WriteHeader(http.StatusOK)
+	}
+	fmt.Fprintf(s.w, "event: message\ndata: %s\n\n", data)
+	s.flusher.Flush()
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// ServeStdio implements the stdio transport: newline-delimited messages are
+// read from r and responses and notifications written to w. Requests run
+// concurrently so notifications/cancelled can stop one in flight. It returns
+// once r is exhausted and every request has been answered.
+func (s *Server) ServeStdio(ctx context.Context, r io.Reader, w io.Writer) error {
+	var (
+		writeMu  sync.Mutex
+		mu       sync.Mutex
+		inflight = map[string]context.CancelFunc{}
+		wg       sync.WaitGroup
+	)
+	write := func(message any) {
+		data, err := json.Marshal(message)
+		if err != nil {
+			s.logger.Error("encode mcp message", "error", err)
+			return
+		}
+		writeMu.Lock()
+		defer writeMu.Unlock()
+		_, _ = w.Write(append(data, '\n'))
+	}
+	notify := func(n Notification) { write(n) }
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), maxMessageBytes)
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		req, errResp := decode(line)
+		if errResp != nil {
+			write(errResp)
+			continue
+		}
+		if len(req.ID) == 0 {
+			if req.Method == "notifications/cancelled" {
+				var params struct {
+					RequestID json.RawMessage `json:"requestId"`
+				}
+				if json.Unmarshal(req.Params, &params) == nil {
+					mu.Lock()
+					if cancel, ok := inflight[string(params.RequestID)]; ok {
+						cancel()
+					}
+					mu.Unlock()
+				}
+				continue
+			}
+			s.Handle(ctx, req, nil)
+			continue
+		}
+
+		key := string(req.ID)
+		reqCtx, cancel := context.WithCancel(ctx)
+		mu.Lock()
+		inflight[key] = cancel
+		mu.Unlock()
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			resp := s.Handle(reqCtx, req, notify)
+			cancelled := reqCtx.Err() != nil && ctx.Err() == nil
+			mu.Lock()
+			delete(inflight, key)
+			mu.Unlock()
+			cancel()
+			// Cancelled requests get no response.
+			if !cancelled {
+				write(resp)
+			}
+		}()
+	}
+	wg.Wait()
+	return scanner.Err()
+}
@@ -0,0 +1,124 @@
+// Copyright (c) 2025 HYPR. PTE. LTD.
+//
+// Business Source License 1.1
+// See LICENSE file in the project root for details.
+
+package orchestrator
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"time"
+
+	"github.com/volantvm/volant/internal/server/db"
+	"github.com/volantvm/volant/internal/server/orchestrator/vmconfig"
+)
+
+// DefaultAutostartConcurrency is how many VMs are started at once on daemon
+// boot when Params.AutostartConcurrency is unset.
+const DefaultAutostartConcurrency = 4
+
+const (
+	autostartAttempts   = 5
+	autostartBackoff    = 5 * time.Second
+	autostartMaxBackoff = time.Minute
+)
+
+// runAutostart starts the VMs whose config sets autostart once runtime
+// state has been reconciled, autostartConcurrency at a time. A VM that fails
+// to start is retried with exponential backoff. Dependencies are started
+// first by StartVM, so ordering follows depends_on. Nothing is started while
+// the host is in maintenance; exiting maintenance restores those VMs.
+func (e *engine) runAutostart(ctx context.Context) {
+	names, err := e.autostartCandidates(ctx)
+	if err != nil {
+		e.logger.Error("list vms to autostart", "error", err)
+		return
+	}
+	if len(names) == 0 {
+		return
+	}
+	if err := e.checkHostMaintenance(ctx); err != nil {
+		e.logger.Info("host in maintenance, vms not autostarted", "vms", len(names))
+		return
+	}
+	e.logger.Info("autostarting vms", "vms", len(names), "concurrency", e.autostartConcurrency)
+
+	var failed atomic.Int32
+	forEachLimited(names, e.autostartConcurrency, func(name string) {
+		if err := e.autostartVM(ctx, name); err != nil {
+			failed.Add(1)
+			e.logger.Error("autostart vm", "vm", name, "error", err)
+		}
+	})
+	e.logger.Info("autostart finished", "vms", len(names), "failed", failed.Load())
+}
+
+// autostartCandidates lists the stopped or crashed VMs flagged autostart.
+func (e *engine) autostartCandidates(ctx context.Context) ([]string, error) {
+	queries := e.store.Queries()
+	vms, err := queries.VirtualMachines().List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, vm := range vms {
+		if e.hasInstance(vm.Name) || (vm.Status != db.VMStatusStopped && vm.Status != db.VMStatusCrashed) {
+			continue
+		}
+		record, err := queries.VMConfigs().GetCurrent(ctx, vm.ID)
+		if err != nil || record == nil {
+			continue
+		}
+		versioned, err := vmconfig.FromDB(*record)
+		if err != nil {
+			e.logger.Warn("decode vm config for autostart", "vm", vm.Name, "error", err)
+			continue
+		}
+		if versioned.Config.Autostart {
+			names = append(names, vm.Name)
+		}
+	}
+	return names, nil
+}
+
+// autostartVM starts name, retrying failures with backoff. A VM that was
+// started in the meantime, for example as another VM's dependency, or was
+// deleted counts as done.
+func (e *engine) autostartVM(ctx context.Context, name string) error {
+	delay := autostartBackoff
+	for attempt := 1; ; attempt++ {
+		if e.hasInstance(name) {
+			return nil
+		}
+		vm, err := e.GetVM(ctx, name)
+		if err != nil {
+			return err
+		}
+		if vm == nil {
+			return nil
+		}
+		if err := e.checkHostMaintenance(ctx); err != nil {
+			return err
+		}
+		if vm.Status == db.VMStatusStarting {
+			err = e.waitForInstance(ctx, name, defaultReadinessTimeout)
+		} else {
+			_, err = e.StartVM(ctx, name)
+		}
+		if err == nil || e.hasInstance(name) {
+			return nil
+		}
+		if errors.Is(err, ErrVMNotFound) || attempt == autostartAttempts {
+			return err
+		}
+		e.logger.Warn("autostart vm failed, retrying", "vm", name, "attempt", attempt, "retry_in", delay.String(), "error", err)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(delay):
+		}
+		delay = min(delay*2, autostartMaxBackoff)
+	}
+}
@@ -0,0 +1,73 @@
+// Copyright (c) 2025 HYPR. PTE. LTD.
+//
+// Business Source License 1.1
+// See LICENSE file in the project root for details.
+
+package orchestrator
+
+import (
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/volantvm/volant/internal/pluginspec"
+	"github.com/volantvm/volant/internal/server/db"
+	"github.com/volantvm/volant/internal/server/orchestrator/runtime"
+)
+
+// applyFirmwareBoot switches spec to firmware boot for manifests whose root
+// disk carries its own bootloader and kernel. The kernel and initramfs,
+// including per-VM overrides, are dropped as the guest never loads them, and
+// the root disk must stay writable.
+func applyFirmwareBoot(manifest *pluginspec.Manifest, root pluginspec.RootFS, spec *runtime.LaunchSpec) error {
+	if !manifest.FirmwareBoot() {
+		return nil
+	}
+	if spec.RootFS == "" {
+		return fmt.Errorf("orchestrator: vm %s: firmware boot requires a root disk", spec.Name)
+	}
+	if root.OverlayKind() != "" {
+		return fmt.Errorf("orchestrator: vm %s: firmware boot cannot use a read-only rootfs", spec.Name)
+	}
+	spec.BootFirmware = true
+	spec.KernelOverride, spec.KernelChecksum = "", ""
+	spec.Initramfs, spec.InitramfsChecksum = "", ""
+	return nil
+}
+
+// firmwareNetworkConfig returns a cloud-init network-config (version 2) giving
+// a firmware-booted guest its static address, which kernel-booted guests get
+// from the ip= kernel argument instead. It returns "" for DHCP guests, which
+// cloud-init configures by default.
+func firmwareNetworkConfig(mac, ip, gateway, netmask string) string {
+	addr := net.ParseIP(strings.TrimSpace(ip)).To4()
+	mask := net.ParseIP(strings.TrimSpace(netmask)).To4()
+	if addr == nil || mask == nil || strings.TrimSpace(mac) == "" {
+		return ""
+	}
+	prefix, _ := net.IPMask(mask).Size()
+	var b strings.Builder
+	b.WriteString("version: 2\n")
+	b.WriteString("ethernets:\n")
+	b.WriteString("  eth0:\n")
+	b.WriteString("    match:\n")
+	fmt.Fprintf(&b, "      macaddress: %q\n", strings.ToLower(strings.TrimSpace(mac)))
+	b.WriteString("    set-name: eth0\n")
+	fmt.Fprintf(&b, "    addresses: [%s/%d]\n", addr, prefix)
+	if gw := net.ParseIP(strings.TrimSpace(gateway)).To4(); gw != nil {
+		b.WriteString("    routes:\n")
+		b.WriteString("      - to: default\n")
+		fmt.Fprintf(&b, "        via: %s\n", gw)
+	}
+	return b.String()
+}
+
+// firmwareNetwork returns the network-config seeded into a firmware-booted
+// VM, or "" for other VMs.
+func (e *engine) firmwareNetwork(manifest *pluginspec.Manifest, vm *db.VM, netCfg *pluginspec.NetworkConfig, netw *db.Network) string {
+	if !manifest.FirmwareBoot() || vm == nil || !needsTapDevice(netCfg) {
+		return ""
+	}
+	gateway, netmask := e.guestAddressing(netw)
+	return firmwareNetworkConfig(vm.MACAddress, staticAddress(netCfg, vm.IPAddress), gateway, netmask)
+}
@@ -0,0 +1,57 @@
+// Copyright (c) 2025 HYPR. PTE. LTD.
+//
+// Business Source License 1.1
+// See LICENSE file in the project root for details.
+
+package orchestrator
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/volantvm/volant/internal/pluginspec"
+	"github.com/volantvm/volant/internal/server/orchestrator/runtime"
+)
+
+func TestApplyFirmwareBoot(t *testing.T) {
+	manifest := &pluginspec.Manifest{BootMode: pluginspec.BootModeFirmware, RootFS: pluginspec.RootFS{URL: "https://example.com/jammy.qcow2"}}
+	spec := runtime.LaunchSpec{
+		Name:           "web",
+		RootFS:         manifest.RootFS.URL,
+		KernelOverride: "/boot/vmlinux",
+		Initramfs:      "/srv/initramfs",
+	}
+	if err := applyFirmwareBoot(manifest, manifest.RootFS, &spec); err != nil {
+		t.Fatalf("apply firmware boot: %v", err)
+	}
+	if !spec.BootFirmware || spec.KernelOverride != "" || spec.Initramfs != "" {
+		t.Fatalf("expected firmware boot without kernel or initramfs, got %+v", spec)
+	}
+
+	readonly := manifest.RootFS
+	readonly.ReadOnly = true
+	if err := applyFirmwareBoot(manifest, readonly, &runtime.LaunchSpec{Name: "web", RootFS: manifest.RootFS.URL}); err == nil {
+		t.Fatal("expected read-only root refused")
+	}
+
+	kernelSpec := runtime.LaunchSpec{Name: "api", KernelOverride: "/boot/vmlinux"}
+	if err := applyFirmwareBoot(&pluginspec.Manifest{}, pluginspec.RootFS{}, &kernelSpec); err != nil || kernelSpec.BootFirmware || kernelSpec.KernelOverride == "" {
+		t.Fatalf("expected kernel boot untouched, got %+v (%v)", kernelSpec, err)
+	}
+}
+
+func TestFirmwareNetworkConfig(t *testing.T) {
+	got := firmwareNetworkConfig("02:AB:CD:00:00:01", "192.168.127.10", "192.168.127.1", "255.255.255.0")
+	for _, want := range []string{
+		`macaddress: "02:ab:cd:00:00:01"`,
+		"addresses: [192.168.127.10/24]",
+		"via: 192.168.127.1",
+	} {
+		if !strings.Contains(got, want) {
+			t.Fatalf("network config missing %q:\n%s", want, got)
+		}
+	}
+	if got := firmwareNetworkConfig("02:ab:cd:00:00:01", "", "192.168.127.1", "255.255.255.0"); got != "" {
+		t.Fatalf("expected no config for dhcp guests, got %q", got)
+	}
+}
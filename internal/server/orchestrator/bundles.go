@@ -0,0 +1,275 @@
+// Copyright (c) 2025 HYPR. PTE. LTD.
+//
+// Business Source License 1.1
+// See LICENSE file in the project root for details.
+
+package orchestrator
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/volantvm/volant/internal/pluginspec"
+	"github.com/volantvm/volant/internal/server/db"
+	"github.com/volantvm/volant/internal/server/orchestrator/vmconfig"
+)
+
+// ConfigBundle is a stored bundle with its timestamps.
+type ConfigBundle struct {
+	pluginspec.Bundle
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// ConfigBundleRotation reports the deployments restarted after a bundle update.
+type ConfigBundleRotation struct {
+	Bundle ConfigBundle
+	// Restarted lists deployments whose replicas are being rolled.
+	Restarted []string
+	// Skipped maps deployments that could not be rolled to the reason.
+	Skipped map[string]string
+}
+
+func (e *engine) CreateConfigBundle(ctx context.Context, bundle pluginspec.Bundle) (*ConfigBundle, error) {
+	record, err := encodeConfigBundle(bundle)
+	if err != nil {
+		return nil, err
+	}
+	var created *db.ConfigBundle
+	err = e.store.WithTx(ctx, func(q db.Queries) error {
+		existing, err := q.ConfigBundles().GetByName(ctx, record.Name)
+		if err != nil {
+			return err
+		}
+		if existing != nil {
+			return fmt.Errorf("%w: %s", ErrConfigBundleExists, record.Name)
+		}
+		if _, err := q.ConfigBundles().Create(ctx, &record); err != nil {
+			return err
+		}
+		created, err = q.ConfigBundles().GetByName(ctx, record.Name)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return decodeConfigBundle(*created)
+}
+
+func (e *engine) ListConfigBundles(ctx context.Context) ([]ConfigBundle, error) {
+	records, err := e.store.Queries().ConfigBundles().List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]ConfigBundle, 0, len(records))
+	for _, record := range records {
+		bundle, err := decodeConfigBundle(record)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, *bundle)
+	}
+	return result, nil
+}
+
+func (e *engine) GetConfigBundle(ctx context.Context, name string) (*ConfigBundle, error) {
+	record, err := e.store.Queries().ConfigBundles().GetByName(ctx, strings.TrimSpace(name))
+	if err != nil {
+		return nil, err
+	}
+	if record == nil {
+		return nil, fmt.Errorf("%w: %s", ErrConfigBundleNotFound, name)
+	}
+	return decodeConfigBundle(*record)
+}
+
+// UpdateConfigBundle replaces a bundle's contents and rolls every deployment
+// that references it so replicas boot with the new version.
+func (e *engine) UpdateConfigBundle(ctx context.Context, bundle pluginspec.Bundle) (*ConfigBundleRotation, error) {
+	record, err := encodeConfigBundle(bundle)
+	if err != nil {
+		return nil, err
+	}
+	var updated *db.ConfigBundle
+	err = e.store.WithTx(ctx, func(q db.Queries) error {
+		existing, err := q.ConfigBundles().GetByName(ctx, record.Name)
+		if err != nil {
+			return err
+		}
+		if existing == nil {
+			return fmt.Errorf("%w: %s", ErrConfigBundleNotFound, record.Name)
+		}
+		if err := q.ConfigBundles().Update(ctx, existing.ID, record.DataJSON, record.FilesJSON); err != nil {
+			return err
+		}
+		updated, err = q.ConfigBundles().GetByName(ctx, record.Name)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	decoded, err := decodeConfigBundle(*updated)
+	if err != nil {
+		return nil, err
+	}
+
+	rotation := &ConfigBundleRotation{Bundle: *decoded}
+	groups, err := e.deploymentsUsingBundle(ctx, record.Name)
+	if err != nil {
+		return nil, err
+	}
+	for _, group := range groups {
+		config, err := vmconfig.Unmarshal(group.ConfigJSON)
+		if err != nil {
+			return nil, err
+		}
+		maxSurge := group.MaxSurge
+		if maxSurge <= 0 {
+			maxSurge = 1
+		}
+		_, err = e.UpdateDeploymentConfig(ctx, group.Name, UpdateDeploymentConfigRequest{
+			Config:   config,
+			MaxSurge: maxSurge,
+		})
+		if err != nil {
+			if rotation.Skipped == nil {
+				rotation.Skipped = make(map[string]string)
+			}
+			rotation.Skipped[group.Name] = err.Error()
+			e.logger.Warn("config bundle rotation", "bundle", record.Name, "deployment", group.Name, "error", err)
+			continue
+		}
+		rotation.Restarted = append(rotation.Restarted, group.Name)
+	}
+	e.logger.Info("config bundle updated", "bundle", record.Name, "version", decoded.Version, "restarted", len(rotation.Restarted))
+	return rotation, nil
+}
+
+func (e *engine) DeleteConfigBundle(ctx context.Context, name string) error {
+	name = strings.TrimSpace(name)
+	groups, err := e.deploymentsUsingBundle(ctx, name)
+	if err != nil {
+		return err
+	}
+	if len(groups) > 0 {
+		return fmt.Errorf("%w: %s is attached to deployment %s", ErrConfigBundleInUse, name, groups[0].Name)
+	}
+	return e.store.WithTx(ctx, func(q db.Queries) error {
+		record, err := q.ConfigBundles().GetByName(ctx, name)
+		if err != nil {
+			return err
+		}
+		if record == nil {
+			return fmt.Errorf("%w: %s", ErrConfigBundleNotFound, name)
+		}
+		return q.ConfigBundles().Delete(ctx, record.ID)
+	})
+}
+
+// VMBundles resolves the bundles attached to a VM's current config. Bundles
+// deleted since the VM was configured are skipped.
+func (e *engine) VMBundles(ctx context.Context, name string) ([]pluginspec.Bundle, error) {
+	versioned, err := e.GetVMConfig(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	if versioned == nil {
+		return nil, nil
+	}
+	bundles := make([]pluginspec.Bundle, 0, len(versioned.Config.Bundles))
+	for _, bundleName := range versioned.Config.Bundles {
+		bundle, err := e.GetConfigBundle(ctx, bundleName)
+		if err != nil {
+			if errors.Is(err, ErrConfigBundleNotFound) {
+				e.logger.Warn("vm references missing config bundle", "vm", name, "bundle", bundleName)
+				continue
+			}
+			return nil, err
+		}
+		bundles = append(bundles, bundle.Bundle)
+	}
+	return bundles, nil
+}
+
+// checkConfigBundles verifies every bundle named by a config exists.
+func (e *engine) checkConfigBundles(ctx context.Context, names []string) error {
+	repo := e.store.Queries().ConfigBundles()
+	for _, name := range names {
+		record, err := repo.GetByName(ctx, name)
+		if err != nil {
+			return err
+		}
+		if record == nil {
+			return fmt.Errorf("%w: %s", ErrConfigBundleNotFound, name)
+		}
+	}
+	return nil
+}
+
+func (e *engine) deploymentsUsingBundle(ctx context.Context, name string) ([]db.VMGroup, error) {
+	groups, err := e.store.Queries().VMGroups().List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var using []db.VMGroup
+	for _, group := range groups {
+		config, err := vmconfig.Unmarshal(group.ConfigJSON)
+		if err != nil {
+			return nil, err
+		}
+		for _, attached := range config.Bundles {
+			if attached == name {
+				using = append(using, group)
+				break
+			}
+		}
+	}
+	return using, nil
+}
+
+func encodeConfigBundle(bundle pluginspec.Bundle) (db.ConfigBundle, error) {
+	bundle.Normalize()
+	if err := bundle.Validate(); err != nil {
+		return db.ConfigBundle{}, fmt.Errorf("%w: %v", ErrInvalidConfigBundle, err)
+	}
+	data := bundle.Data
+	if data == nil {
+		data = map[string]string{}
+	}
+	files := bundle.Files
+	if files == nil {
+		files = []pluginspec.BundleFile{}
+	}
+	dataJSON, err := json.Marshal(data)
+	if err != nil {
+		return db.ConfigBundle{}, err
+	}
+	filesJSON, err := json.Marshal(files)
+	if err != nil {
+		return db.ConfigBundle{}, err
+	}
+	return db.ConfigBundle{Name: bundle.Name, DataJSON: dataJSON, FilesJSON: filesJSON}, nil
+}
+
+func decodeConfigBundle(record db.ConfigBundle) (*ConfigBundle, error) {
+	bundle := &ConfigBundle{
+		Bundle:    pluginspec.Bundle{Name: record.Name, Version: record.Version},
+		CreatedAt: record.CreatedAt,
+		UpdatedAt: record.UpdatedAt,
+	}
+	if len(record.DataJSON) > 0 {
+		if err := json.Unmarshal(record.DataJSON, &bundle.Data); err != nil {
+			return nil, fmt.Errorf("decode config bundle %s data: %w", record.Name, err)
+		}
+	}
+	if len(record.FilesJSON) > 0 {
+		if err := json.Unmarshal(record.FilesJSON, &bundle.Files); err != nil {
+			return nil, fmt.Errorf("decode config bundle %s files: %w", record.Name, err)
+		}
+	}
+	return bundle, nil
+}
@@ -0,0 +1,80 @@
+// Copyright (c) 2025 HYPR. PTE. LTD.
+//
+// Business Source License 1.1
+// See LICENSE file in the project root for details.
+
+package orchestrator
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/volantvm/volant/internal/server/db"
+	"github.com/volantvm/volant/internal/server/orchestrator/capacity"
+)
+
+// CapacityError reports a request that would commit more host CPU or memory
+// than the capacity thresholds allow. It wraps ErrInsufficientCapacity.
+type CapacityError struct {
+	// Resource is capacity.ResourceCPU or capacity.ResourceMemory.
+	Resource  string
+	Allowed   int
+	Requested int
+}
+
+func (e *CapacityError) Error() string {
+	unit := "cpus"
+	if e.Resource == capacity.ResourceMemory {
+		unit = "MB"
+	}
+	return fmt.Sprintf("host %s capacity exceeded: %d %s requested, %d %s allowed", e.Resource, e.Requested, unit, e.Allowed, unit)
+}
+
+func (e *CapacityError) Unwrap() error { return ErrInsufficientCapacity }
+
+// Capacity reports the host's CPU and memory against the resources committed
+// to VMs.
+func (e *engine) Capacity(ctx context.Context) (*capacity.Report, error) {
+	committed, err := committedUsage(ctx, e.store.Queries())
+	if err != nil {
+		return nil, err
+	}
+	report := e.capacity.Report(committed)
+	return &report, nil
+}
+
+// checkCapacity fails with a *CapacityError when committing cpus and
+// memoryMB more would take the host past its thresholds.
+func (e *engine) checkCapacity(ctx context.Context, q db.Queries, cpus, memoryMB int) error {
+	if cpus <= 0 && memoryMB <= 0 {
+		return nil
+	}
+	committed, err := committedUsage(ctx, q)
+	if err != nil {
+		return err
+	}
+	resource, allowed, requested := e.capacity.Check(committed, cpus, memoryMB)
+	if resource == "" {
+		return nil
+	}
+	return &CapacityError{Resource: resource, Allowed: allowed, Requested: requested}
+}
+
+// committedUsage sums the resources of VMs that hold or are about to hold
+// host resources; stopped and crashed VMs release theirs.
+func committedUsage(ctx context.Context, q db.Queries) (capacity.Usage, error) {
+	vms, err := q.VirtualMachines().List(ctx)
+	if err != nil {
+		return capacity.Usage{}, err
+	}
+	var usage capacity.Usage
+	for _, vm := range vms {
+		if vm.Status == db.VMStatusStopped || vm.Status == db.VMStatusCrashed {
+			continue
+		}
+		usage.VMs++
+		usage.CPUs += vm.CPUCores
+		usage.MemoryMB += vm.MemoryMB
+	}
+	return usage, nil
+}
@@ -0,0 +1,124 @@
+// Copyright (c) 2025 HYPR. PTE. LTD.
+//
+// Business Source License 1.1
+// See LICENSE file in the project root for details.
+
+// Package capacity tracks host CPU and memory against the resources committed
+// to VMs, so requests that would overload the host are refused up front.
+package capacity
+
+import goruntime "runtime"
+
+// Resource names reported by Check.
+const (
+	ResourceCPU    = "cpu"
+	ResourceMemory = "memory"
+)
+
+// Host is the CPU and memory the host offers to VMs.
+type Host struct {
+	CPUs int
+	// MemoryMB is zero when the platform does not report it.
+	MemoryMB int
+}
+
+// ReadHost reports the online CPUs and total memory of the running host.
+func ReadHost() Host {
+	return Host{CPUs: goruntime.NumCPU(), MemoryMB: readMemoryMB()}
+}
+
+// Thresholds cap the resources that may be committed to VMs, as a percentage
+// of the host totals. Values above 100 overcommit; zero disables admission
+// control for that resource.
+type Thresholds struct {
+	CPUPercent    int
+	MemoryPercent int
+}
+
+// Usage is the resources committed to VMs.
+type Usage struct {
+	VMs      int
+	CPUs     int
+	MemoryMB int
+}
+
+// Report is the host capacity as seen by admission control. Allowed and
+// Available are omitted for resources without a threshold.
+type Report struct {
+	TotalCPUs              int  `json:"total_cpus"`
+	TotalMemoryMB          int  `json:"total_memory_mb"`
+	CPUThresholdPercent    int  `json:"cpu_threshold_percent"`
+	MemoryThresholdPercent int  `json:"memory_threshold_percent"`
+	AllowedCPUs            *int `json:"allowed_cpus,omitempty"`
+	AllowedMemoryMB        *int `json:"allowed_memory_mb,omitempty"`
+	CommittedVMs           int  `json:"committed_vms"`
+	CommittedCPUs          int  `json:"committed_cpus"`
+	CommittedMemoryMB      int  `json:"committed_memory_mb"`
+	AvailableCPUs          *int `json:"available_cpus,omitempty"`
+	AvailableMemoryMB      *int `json:"available_memory_mb,omitempty"`
+}
+
+// Manager applies thresholds to a host.
+type Manager struct {
+	host       Host
+	thresholds Thresholds
+}
+
+// New returns a manager admitting VMs on host up to thresholds.
+func New(host Host, thresholds Thresholds) *Manager {
+	return &Manager{host: host, thresholds: thresholds}
+}
+
+// allowed returns the CPUs and memory that may be committed; false means the
+// resource is not capped. Memory is never capped when the host total is
+// unknown.
+func (m *Manager) allowed() (cpus int, cpuCapped bool, memoryMB int, memoryCapped bool) {
+	if m.thresholds.CPUPercent > 0 && m.host.CPUs > 0 {
+		cpus, cpuCapped = m.host.CPUs*m.thresholds.CPUPercent/100, true
+	}
+	if m.thresholds.MemoryPercent > 0 && m.host.MemoryMB > 0 {
+		memoryMB, memoryCapped = m.host.MemoryMB*m.thresholds.MemoryPercent/100, true
+	}
+	return cpus, cpuCapped, memoryMB, memoryCapped
+}
+
+// Report summarises the host against committed.
+func (m *Manager) Report(committed Usage) Report {
+	report := Report{
+		TotalCPUs:              m.host.CPUs,
+		TotalMemoryMB:          m.host.MemoryMB,
+		CPUThresholdPercent:    m.thresholds.CPUPercent,
+		MemoryThresholdPercent: m.thresholds.MemoryPercent,
+		CommittedVMs:           committed.VMs,
+		CommittedCPUs:          committed.CPUs,
+		CommittedMemoryMB:      committed.MemoryMB,
+	}
+	cpus, cpuCapped, memoryMB, memoryCapped := m.allowed()
+	if cpuCapped {
+		available := max(cpus-committed.CPUs, 0)
+		report.AllowedCPUs, report.AvailableCPUs = &cpus, &available
+	}
+	if memoryCapped {
+		available := max(memoryMB-committed.MemoryMB, 0)
+		report.AllowedMemoryMB, report.AvailableMemoryMB = &memoryMB, &available
+	}
+	return report
+}
+
+// Check reports the first resource that adding cpus and memoryMB to
+// committed would take past its threshold, with the allowed total and the
+// total that was requested. It returns an empty resource when the request
+// fits.
+func (m *Manager) Check(committed Usage, cpus, memoryMB int) (resource string, allowed, requested int) {
+	if m == nil {
+		return "", 0, 0
+	}
+	allowedCPUs, cpuCapped, allowedMemoryMB, memoryCapped := m.allowed()
+	if cpuCapped && cpus > 0 && committed.CPUs+cpus > allowedCPUs {
+		return ResourceCPU, allowedCPUs, committed.CPUs + cpus
+	}
+	if memoryCapped && memoryMB > 0 && committed.MemoryMB+memoryMB > allowedMemoryMB {
+		return ResourceMemory, allowedMemoryMB, committed.MemoryMB + memoryMB
+	}
+	return "", 0, 0
+}
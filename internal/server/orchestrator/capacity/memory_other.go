@@ -0,0 +1,12 @@
+// Copyright (c) 2025 HYPR. PTE. LTD.
+//
+// Business Source License 1.1
+// See LICENSE file in the project root for details.
+
+//go:build !linux
+
+package capacity
+
+func readMemoryMB() int {
+	return 0
+}
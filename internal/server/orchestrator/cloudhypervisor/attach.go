@@ -0,0 +1,236 @@
+// Copyright (c) 2025 HYPR. PTE. LTD.
+//
+// Business Source License 1.1
+// See LICENSE file in the project root for details.
+
+package cloudhypervisor
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/volantvm/volant/internal/server/orchestrator/runtime"
+)
+
+const (
+	stateSuffix       = ".state.json"
+	attachPingTimeout = 2 * time.Second
+	// adoptedPollInterval is how often an adopted process, which volantd
+	// cannot wait on, is checked for exit.
+	adoptedPollInterval = time.Second
+)
+
+// instanceState is written next to the API socket when an instance starts so
+// a restarted volantd can find and adopt it.
+type instanceState struct {
+	PID          int      `json:"pid"`
+	APISocket    string   `json:"api_socket"`
+	SerialSocket string   `json:"serial_socket,omitempty"`
+	Kernel       string   `json:"kernel,omitempty"`
+	Initramfs    string   `json:"initramfs,omitempty"`
+	RootFS       string   `json:"rootfs,omitempty"`
+	RootFSShared bool     `json:"rootfs_shared,omitempty"`
+	Disks        []string `json:"disks,omitempty"`
+	TapDevice    string   `json:"tap_device,omitempty"`
+	Interfaces   []string `json:"interfaces,omitempty"`
+	Devices      []string `json:"devices,omitempty"`
+	// Shares are the virtiofsd processes serving the instance's shares.
+	Shares []shareDaemon `json:"shares,omitempty"`
+}
+
+func (l *Launcher) statePath(name string) string {
+	return filepath.Join(l.RuntimeDir, name+stateSuffix)
+}
+
+// writeState records inst so it can be adopted later. Failing to write it
+// only costs adoption, so callers log rather than fail the launch.
+func (l *Launcher) writeState(inst *instance, spec runtime.LaunchSpec) error {
+	state := instanceState{
+		PID:          inst.process.Pid,
+		APISocket:    inst.apiSocket,
+		SerialSocket: inst.serialPath,
+		Kernel:       inst.kernelPath,
+		Initramfs:    inst.initramfsPath,
+		RootFS:       inst.rootfsPath,
+		RootFSShared: inst.rootfsShared,
+		Disks:        inst.disks,
+		TapDevice:    spec.TapDevice,
+		Shares:       inst.shares,
+	}
+	for _, iface := range spec.Interfaces {
+		state.Interfaces = append(state.Interfaces, iface.TapDevice)
+	}
+	for _, path := range spec.VFIODevicePaths {
+		state.Devices = append(state.Devices, filepath.Base(path))
+	}
+	data, err := json.Marshal(state)
+	if err != nil {
+		return err
+	}
+	path := l.statePath(inst.name)
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		_ = os.Remove(tmp)
+		return err
+	}
+	inst.statePath = path
+	return nil
+}
+
+func (l *Launcher) readState(name string) (*instanceState, error) {
+	data, err := os.ReadFile(l.statePath(name))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, runtime.ErrInstanceNotFound
+		}
+		return nil, err
+	}
+	var state instanceState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, fmt.Errorf("cloudhypervisor: decode state for %s: %w", name, err)
+	}
+	return &state, nil
+}
+
+// Attach adopts the Cloud Hypervisor process recorded for name. The process
+// must still be alive, be the one that owns the recorded API socket (guarding
+// against PID reuse) and answer on that socket.
+func (l *Launcher) Attach(ctx context.Context, name string) (*runtime.Attachment, error) {
+	state, err := l.readState(name)
+	if err != nil {
+		return nil, err
+	}
+	process, err := os.FindProcess(state.PID)
+	if err != nil || process.Signal(syscall.Signal(0)) != nil {
+		return nil, runtime.ErrInstanceNotFound
+	}
+	if !hasArg(state.PID, "path="+state.APISocket) {
+		return nil, runtime.ErrInstanceNotFound
+	}
+	if err := ping(ctx, state.APISocket); err != nil {
+		return nil, fmt.Errorf("%w: api socket not responding: %v", runtime.ErrInstanceNotFound, err)
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		ticker := time.NewTicker(adoptedPollInterval)
+		defer ticker.Stop()
+		for range ticker.C {
+			if process.Signal(syscall.Signal(0)) != nil {
+				break
+			}
+		}
+		stopShares(state.Shares)
+		// The exit status of a process volantd did not start is unknown.
+		done <- nil
+		close(done)
+	}()
+
+	inst := &instance{
+		name:          name,
+		process:       process,
+		apiSocket:     state.APISocket,
+		serialPath:    state.SerialSocket,
+		done:          done,
+		kernelPath:    state.Kernel,
+		initramfsPath: state.Initramfs,
+		rootfsPath:    state.RootFS,
+		rootfsShared:  state.RootFSShared,
+		disks:         state.Disks,
+		shares:        state.Shares,
+		statePath:     l.statePath(name),
+	}
+	return &runtime.Attachment{
+		Instance:     inst,
+		TapDevice:    state.TapDevice,
+		Interfaces:   state.Interfaces,
+		Devices:      state.Devices,
+		SerialSocket: state.SerialSocket,
+	}, nil
+}
+
+// Leftovers lists the instances that have state recorded in the runtime dir.
+func (l *Launcher) Leftovers() ([]string, error) {
+	matches, err := filepath.Glob(filepath.Join(l.RuntimeDir, "*"+stateSuffix))
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(matches))
+	for _, match := range matches {
+		names = append(names, strings.TrimSuffix(filepath.Base(match), stateSuffix))
+	}
+	return names, nil
+}
+
+// Discard removes the staged artifacts, sockets, share daemons and state of an
+// instance whose process is gone. Caller-owned disks are left alone.
+func (l *Launcher) Discard(name string) error {
+	state, err := l.readState(name)
+	if err != nil {
+		if errors.Is(err, runtime.ErrInstanceNotFound) {
+			return nil
+		}
+		// Unreadable state cannot be adopted either; drop it.
+		return removeIfExists(l.statePath(name))
+	}
+	inst := &instance{
+		apiSocket:     state.APISocket,
+		serialPath:    state.SerialSocket,
+		kernelPath:    state.Kernel,
+		initramfsPath: state.Initramfs,
+		rootfsPath:    state.RootFS,
+		rootfsShared:  state.RootFSShared,
+		statePath:     l.statePath(name),
+	}
+	_ = removeIfExists(inst.apiSocket)
+	stopShares(state.Shares)
+	inst.cleanupArtifacts()
+	return nil
+}
+
+// hasArg reports whether pid was started with want on its command line. It
+// reads /proc, so adoption is only possible on Linux.
+func hasArg(pid int, want string) bool {
+	cmdline, err := os.ReadFile(fmt.Sprintf("/proc/%d/cmdline", pid))
+	if err != nil {
+		return false
+	}
+	for _, arg := range bytes.Split(cmdline, []byte{0}) {
+		if string(arg) == want {
+			return true
+		}
+	}
+	return false
+}
+
+func ping(ctx context.Context, apiSocket string) error {
+	ctx, cancel := context.WithTimeout(ctx, attachPingTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "http://localhost/api/v1/vmm.ping", nil)
+	if err != nil {
+		return err
+	}
+	resp, err := apiClient(apiSocket).Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("vmm.ping: status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+var _ runtime.Attacher = (*Launcher)(nil)
@@ -0,0 +1,55 @@
+// Copyright (c) 2025 HYPR. PTE. LTD.
+//
+// Business Source License 1.1
+// See LICENSE file in the project root for details.
+
+package cloudhypervisor
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/volantvm/volant/internal/server/orchestrator/runtime"
+)
+
+// CloneDisks pauses the guest, copies its root filesystem and writable disks
+// into dir and resumes it.
+func (i *instance) CloneDisks(ctx context.Context, dir string) (*runtime.ClonedDisks, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("cloudhypervisor: create clone dir: %w", err)
+	}
+	client := apiClient(i.apiSocket)
+	if err := apiCall(ctx, client, "vm.pause", nil); err != nil {
+		return nil, fmt.Errorf("cloudhypervisor: pause: %w", err)
+	}
+	cloned, err := i.copyDisks(dir)
+	if resumeErr := apiCall(ctx, client, "vm.resume", nil); resumeErr != nil && err == nil {
+		err = fmt.Errorf("cloudhypervisor: resume: %w", resumeErr)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return cloned, nil
+}
+
+func (i *instance) copyDisks(dir string) (*runtime.ClonedDisks, error) {
+	cloned := &runtime.ClonedDisks{Disks: make(map[string]string, len(i.disks))}
+	// A shared read-only image never changes, so the copy boots from it too.
+	if i.rootfsPath != "" && !i.rootfsShared {
+		dst := filepath.Join(dir, "rootfs.img")
+		if err := runtime.CopyDisk(i.rootfsPath, dst); err != nil {
+			return nil, fmt.Errorf("cloudhypervisor: copy rootfs: %w", err)
+		}
+		cloned.RootFS = dst
+	}
+	for idx, path := range i.disks {
+		dst := filepath.Join(dir, fmt.Sprintf("%d-%s", idx, filepath.Base(path)))
+		if err := runtime.CopyDisk(path, dst); err != nil {
+			return nil, fmt.Errorf("cloudhypervisor: copy %s: %w", filepath.Base(path), err)
+		}
+		cloned.Disks[path] = dst
+	}
+	return cloned, nil
+}
@@ -11,6 +11,7 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"net"
 	"net/http"
 	"os"
 	"os/exec"
@@ -31,6 +32,18 @@ type Launcher struct {
 	RuntimeDir  string
 	LogDir      string
 	ConsoleDir  string
+	// Virtiofsd is the virtiofsd binary serving shares; empty looks it up on
+	// PATH and in the usual distribution locations.
+	Virtiofsd string
+	// Firmware is the hypervisor firmware (rust-hypervisor-fw or OVMF) that
+	// boots VMs whose spec sets BootFirmware.
+	Firmware string
+	// Peers are the base URLs of other volantd hosts. A kernel, initramfs
+	// or root image given by URL is pulled from the first peer that has it
+	// cached before its origin is tried.
+	Peers []string
+	// PeerAPIKey authenticates the requests to Peers.
+	PeerAPIKey string
 }
 
 // New returns a configured Launcher.
@@ -49,6 +62,9 @@ func (l *Launcher) Launch(ctx context.Context, spec runtime.LaunchSpec) (runtime
 	if l.Binary == "" {
 		return nil, fmt.Errorf("cloudhypervisor: binary path required")
 	}
+	if err := l.preflight(); err != nil {
+		return nil, err
+	}
 	if err := os.MkdirAll(l.RuntimeDir, 0o755); err != nil {
 		return nil, fmt.Errorf("cloudhypervisor: ensure runtime dir: %w", err)
 	}
@@ -62,61 +78,81 @@ func (l *Launcher) Launch(ctx context.Context, spec runtime.LaunchSpec) (runtime
 	apiSocket := filepath.Join(l.RuntimeDir, fmt.Sprintf("%s.sock", spec.Name))
 	_ = os.Remove(apiSocket)
 
-	// Select kernel: explicit override > default bzImage > fallback vmlinux
-	kernelSrc := strings.TrimSpace(spec.KernelOverride)
-	if kernelSrc == "" {
-		if l.BZImagePath != "" {
-			kernelSrc = l.BZImagePath
-		} else {
-			kernelSrc = l.VMLinuxPath
+	// kernelCopy is the per-VM kernel removed with the instance; firmware
+	// boots pass the shared firmware image instead.
+	var kernelCopy, bootImage string
+	if spec.BootFirmware {
+		firmware, err := l.firmwarePath()
+		if err != nil {
+			return nil, err
 		}
-	}
-	if strings.TrimSpace(kernelSrc) == "" {
-		return nil, fmt.Errorf("cloudhypervisor: kernel path required")
-	}
-
-	// Preserve extension for readability
-	ext := filepath.Ext(kernelSrc)
-	if ext == "" {
-		ext = ".vmlinux"
-	}
-	kernelCopy := filepath.Join(l.RuntimeDir, fmt.Sprintf("%s%s", spec.Name, ext))
-	if err := copyFile(kernelSrc, kernelCopy); err != nil {
-		return nil, fmt.Errorf("cloudhypervisor: stage kernel: %w", err)
+		bootImage = firmware
+	} else {
+		var err error
+		if kernelCopy, err = l.stageKernel(ctx, spec); err != nil {
+			return nil, err
+		}
+		bootImage = kernelCopy
 	}
 
 	var initramfsCopy string
 	if strings.TrimSpace(spec.Initramfs) != "" {
 		initramfsCopy = filepath.Join(l.RuntimeDir, fmt.Sprintf("%s.initramfs", spec.Name))
-		if err := streamFile(ctx, spec.Initramfs, initramfsCopy, spec.InitramfsChecksum); err != nil {
+		cached, err := l.stageCached(ctx, "initramfs", ".initramfs", spec.Initramfs, spec.InitramfsChecksum)
+		if err == nil {
+			err = copyFile(cached, initramfsCopy)
+		}
+		if err != nil {
 			_ = os.Remove(kernelCopy)
 			return nil, fmt.Errorf("cloudhypervisor: stage initramfs: %w", err)
 		}
 	}
 
-	var rootfsPath string
+	// rootfsCopy is the per-VM copy removed with the instance; a read-only
+	// root points rootfsPath at a shared image instead, and a cloned root at
+	// the caller's copy-on-write clone.
+	var rootfsPath, rootfsCopy string
+	cloned := false
 	if spec.RootFS != "" {
-		rootfsPath = filepath.Join(l.RuntimeDir, fmt.Sprintf("%s.rootfs", spec.Name))
-		if err := streamFile(ctx, spec.RootFS, rootfsPath, spec.RootFSChecksum); err != nil {
+		var err error
+		if spec.RootFSReadOnly {
+			rootfsPath, err = l.stageSharedRootFS(ctx, spec.RootFS, spec.RootFSChecksum)
+		} else if spec.RootFSClone != "" {
+			rootfsPath, err = l.stageRootFSClone(ctx, spec)
+			cloned = true
+		} else {
+			rootfsCopy = filepath.Join(l.RuntimeDir, fmt.Sprintf("%s.rootfs", spec.Name))
+			rootfsPath = rootfsCopy
+			err = streamFile(ctx, spec.RootFS, rootfsCopy, spec.RootFSChecksum)
+		}
+		if err != nil {
 			_ = os.Remove(kernelCopy)
 			if initramfsCopy != "" {
 				_ = os.Remove(initramfsCopy)
 			}
+			if rootfsCopy != "" {
+				_ = os.Remove(rootfsCopy)
+			}
 			return nil, fmt.Errorf("cloudhypervisor: fetch rootfs: %w", err)
 		}
 	}
 
-	logPath := filepath.Join(l.LogDir, fmt.Sprintf("%s.log", spec.Name))
-	logFile, err := os.OpenFile(logPath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	logFile, errLogFile, err := l.openLogs(spec)
 	if err != nil {
 		_ = os.Remove(kernelCopy)
 		if initramfsCopy != "" {
 			_ = os.Remove(initramfsCopy)
 		}
-		if rootfsPath != "" {
-			_ = os.Remove(rootfsPath)
+		if rootfsCopy != "" {
+			_ = os.Remove(rootfsCopy)
+		}
+		return nil, err
+	}
+	closeLogs := func() {
+		_ = logFile.Close()
+		if errLogFile != logFile {
+			_ = errLogFile.Close()
 		}
-		return nil, fmt.Errorf("cloudhypervisor: open log file: %w", err)
 	}
 
 	// Configure network based on whether tap device is provided
@@ -124,11 +160,13 @@ func (l *Launcher) Launch(ctx context.Context, spec runtime.LaunchSpec) (runtime
 	var netArg string
 	if spec.TapDevice != "" {
 		netArg = fmt.Sprintf("tap=%s,mac=%s", spec.TapDevice, spec.MACAddress)
-		if ip := strings.TrimSpace(spec.IPAddress); ip != "" {
+		// Cloud Hypervisor only accepts IPv4 host-side addressing; IPv6
+		// guests are configured from the kernel command line instead.
+		if ip := strings.TrimSpace(spec.IPAddress); isIPv4(ip) {
 			netArg = fmt.Sprintf("%s,ip=%s", netArg, ip)
-		}
-		if mask := strings.TrimSpace(spec.Netmask); mask != "" {
-			netArg = fmt.Sprintf("%s,mask=%s", netArg, mask)
+			if mask := strings.TrimSpace(spec.Netmask); mask != "" {
+				netArg = fmt.Sprintf("%s,mask=%s", netArg, mask)
+			}
 		}
 	}
 	if l.ConsoleDir == "" {
@@ -159,16 +197,27 @@ func (l *Launcher) Launch(ctx context.Context, spec runtime.LaunchSpec) (runtime
 
 	args := []string{
 		"--api-socket", fmt.Sprintf("path=%s", apiSocket),
-		"--cpus", fmt.Sprintf("boot=%d", spec.CPUCores),
-		"--memory", fmt.Sprintf("size=%dM", spec.MemoryMB),
-		"--kernel", kernelCopy,
+		"--cpus", cpusArg(spec),
+	}
+	args = append(args, memoryArgs(spec)...)
+	args = append(args,
+		"--kernel", bootImage,
 		"--serial", serialMode,
 		"--console", "off",
-	}
+	)
+	netArgs := make([]string, 0, 1+len(spec.Interfaces))
 	if netArg != "" {
-		// Bridged or DHCP mode: configure network interface
-		args = append(args, "--net", netArg)
-	} else {
+		netArgs = append(netArgs, netArg)
+	}
+	for _, iface := range spec.Interfaces {
+		netArgs = append(netArgs, fmt.Sprintf("tap=%s,mac=%s", iface.TapDevice, iface.MACAddress))
+	}
+	if len(netArgs) > 0 {
+		// Bridged or DHCP mode, plus any additional NICs
+		args = append(args, "--net")
+		args = append(args, netArgs...)
+	}
+	if netArg == "" {
 		// Vsock-only mode: configure vsock device for host-guest communication
 		// Use the allocated CID from the spec
 		vsockArg := fmt.Sprintf("cid=%d", spec.VsockCID)
@@ -178,7 +227,11 @@ func (l *Launcher) Launch(ctx context.Context, spec runtime.LaunchSpec) (runtime
 		args = append(args, "--initramfs", initramfsCopy)
 	}
 	if rootfsPath != "" {
-		args = append(args, "--disk", fmt.Sprintf("path=%s,readonly=false", rootfsPath))
+		rootDisk := fmt.Sprintf("path=%s,readonly=%t", rootfsPath, spec.RootFSReadOnly)
+		if cloned {
+			rootDisk += ",backing_files=on"
+		}
+		args = append(args, "--disk", rootDisk)
 	}
 	for _, disk := range spec.Disks {
 		path := strings.TrimSpace(disk.Path)
@@ -201,6 +254,11 @@ func (l *Launcher) Launch(ctx context.Context, spec runtime.LaunchSpec) (runtime
 			args = append(args, "--disk", fmt.Sprintf("path=%s,readonly=%s", seedPath, readonly))
 		}
 	}
+	if spec.OverlayDisk != nil && strings.TrimSpace(spec.OverlayDisk.Path) != "" {
+		args = append(args, "--disk", fmt.Sprintf("path=%s,readonly=false", spec.OverlayDisk.Path))
+	}
+
+	args = append(args, l.fsArgs(spec)...)
 
 	// Add VFIO GPU/device passthrough
 	for _, devicePath := range spec.VFIODevicePaths {
@@ -228,7 +286,7 @@ func (l *Launcher) Launch(ctx context.Context, spec runtime.LaunchSpec) (runtime
 			cmdline = strings.TrimSpace(cmdline + " " + strings.Join(appendix, " "))
 		}
 	}
-	if spec.IPAddress != "" && spec.Netmask != "" && spec.Gateway != "" {
+	if isIPv4(spec.IPAddress) && spec.Netmask != "" && spec.Gateway != "" {
 		hasIP := false
 		for _, field := range strings.Fields(cmdline) {
 			if strings.HasPrefix(field, "ip=") {
@@ -240,87 +298,201 @@ func (l *Launcher) Launch(ctx context.Context, spec runtime.LaunchSpec) (runtime
 			cmdline = strings.TrimSpace(cmdline + " " + fmt.Sprintf("ip=%s::%s:%s::eth0", spec.IPAddress, spec.Gateway, spec.Netmask))
 		}
 	}
-	args = append(args, "--cmdline", cmdline)
+	if !spec.BootFirmware {
+		args = append(args, "--cmdline", cmdline)
+	}
 
 	select {
 	case <-ctx.Done():
-		logFile.Close()
+		closeLogs()
 		_ = os.Remove(kernelCopy)
 		if initramfsCopy != "" {
 			_ = os.Remove(initramfsCopy)
 		}
-		if rootfsPath != "" {
-			_ = os.Remove(rootfsPath)
+		if rootfsCopy != "" {
+			_ = os.Remove(rootfsCopy)
 		}
 		return nil, fmt.Errorf("cloudhypervisor: launch cancelled: %w", ctx.Err())
 	default:
 	}
 
+	shares, err := l.startShares(ctx, spec, errLogFile)
+	if err != nil {
+		closeLogs()
+		_ = os.Remove(kernelCopy)
+		if initramfsCopy != "" {
+			_ = os.Remove(initramfsCopy)
+		}
+		if rootfsCopy != "" {
+			_ = os.Remove(rootfsCopy)
+		}
+		return nil, err
+	}
+
 	cmd := exec.CommandContext(ctx, l.Binary, args...)
 	cmd.Stdout = logFile
-	cmd.Stderr = logFile
+	cmd.Stderr = errLogFile
 
 	if err := cmd.Start(); err != nil {
-		_ = logFile.Close()
+		stopShares(shares)
+		closeLogs()
 		_ = os.Remove(kernelCopy)
 		if initramfsCopy != "" {
 			_ = os.Remove(initramfsCopy)
 		}
-		if rootfsPath != "" {
-			_ = os.Remove(rootfsPath)
+		if rootfsCopy != "" {
+			_ = os.Remove(rootfsCopy)
+		}
+		err = fmt.Errorf("cloudhypervisor: start: %w", err)
+		if errors.Is(err, exec.ErrNotFound) || errors.Is(err, os.ErrNotExist) {
+			return nil, runtime.NewLaunchError(runtime.ErrorBinaryMissing, err)
 		}
-		return nil, fmt.Errorf("cloudhypervisor: start: %w", err)
+		return nil, err
 	}
 
+	stateFile := l.statePath(spec.Name)
 	done := make(chan error, 1)
 	go func() {
 		err := cmd.Wait()
+		stopShares(shares)
+		_ = removeIfExists(stateFile)
 		done <- err
 		close(done)
 	}()
 
-	return &instance{
+	inst := &instance{
 		name:          spec.Name,
-		cmd:           cmd,
+		process:       cmd.Process,
 		apiSocket:     apiSocket,
 		serialPath:    serialPath,
 		consolePath:   "", // Removed consolePath
 		logFile:       logFile,
+		errLogFile:    errLogFile,
 		done:          done,
 		kernelPath:    kernelCopy,
 		initramfsPath: initramfsCopy,
 		rootfsPath:    rootfsPath,
-	}, nil
+		rootfsShared:  spec.RootFSReadOnly,
+		disks:         writableDisks(spec),
+		shares:        shares,
+	}
+	if cloned {
+		// The clone belongs to the caller; it is tracked with the writable
+		// disks so the instance copies it into snapshots but never removes it.
+		inst.rootfsPath = ""
+	}
+	if err := l.writeState(inst, spec); err != nil {
+		fmt.Fprintf(errLogFile, "volant: record instance state: %v\n", err)
+	}
+	return inst, nil
+}
+
+// stageKernel copies the kernel of spec into the runtime directory: the
+// spec's override, else the default bzImage, else the default vmlinux.
+func (l *Launcher) stageKernel(ctx context.Context, spec runtime.LaunchSpec) (string, error) {
+	kernelSrc := strings.TrimSpace(spec.KernelOverride)
+	if kernelSrc == "" {
+		if l.BZImagePath != "" {
+			kernelSrc = l.BZImagePath
+		} else {
+			kernelSrc = l.VMLinuxPath
+		}
+	}
+	if strings.TrimSpace(kernelSrc) == "" {
+		return "", runtime.NewLaunchError(runtime.ErrorKernelNotFound, fmt.Errorf("cloudhypervisor: kernel path required"))
+	}
+	// Plugin kernels are fetched and verified once, then copied per VM like
+	// the defaults.
+	if spec.KernelOverride != "" && cachedKernel(kernelSrc, spec.KernelChecksum) {
+		cached, err := l.stageCached(ctx, "kernels", ".kernel", kernelSrc, spec.KernelChecksum)
+		if err != nil {
+			err = fmt.Errorf("cloudhypervisor: fetch kernel: %w", err)
+			if _, ok := runtime.AsLaunchError(err); !ok {
+				err = runtime.NewLaunchError(runtime.ErrorKernelNotFound, err)
+			}
+			return "", err
+		}
+		kernelSrc = cached
+	}
+
+	// Preserve extension for readability
+	ext := filepath.Ext(kernelSrc)
+	if ext == "" {
+		ext = ".vmlinux"
+	}
+	kernelCopy := filepath.Join(l.RuntimeDir, fmt.Sprintf("%s%s", spec.Name, ext))
+	if err := copyFile(kernelSrc, kernelCopy); err != nil {
+		err = fmt.Errorf("cloudhypervisor: stage kernel: %w", err)
+		if errors.Is(err, os.ErrNotExist) {
+			return "", runtime.NewLaunchError(runtime.ErrorKernelNotFound, err)
+		}
+		return "", err
+	}
+	return kernelCopy, nil
+}
+
+// firmwarePath returns the firmware image booting firmware-mode VMs. It is
+// shared between VMs and never modified, so it is not copied.
+func (l *Launcher) firmwarePath() (string, error) {
+	if strings.TrimSpace(l.Firmware) == "" {
+		return "", runtime.NewLaunchError(runtime.ErrorFirmwareNotFound, fmt.Errorf("cloudhypervisor: firmware path required"))
+	}
+	info, err := os.Stat(l.Firmware)
+	if err != nil {
+		err = fmt.Errorf("cloudhypervisor: firmware: %w", err)
+		if errors.Is(err, os.ErrNotExist) {
+			return "", runtime.NewLaunchError(runtime.ErrorFirmwareNotFound, err)
+		}
+		return "", err
+	}
+	if !info.Mode().IsRegular() {
+		return "", runtime.NewLaunchError(runtime.ErrorFirmwareNotFound, fmt.Errorf("cloudhypervisor: firmware %s is not a file", l.Firmware))
+	}
+	return l.Firmware, nil
 }
 
 type instance struct {
-	name          string
-	cmd           *exec.Cmd
-	apiSocket     string
-	serialPath    string
-	consolePath   string
-	logFile       *os.File
+	name        string
+	process     *os.Process
+	apiSocket   string
+	serialPath  string
+	consolePath string
+	logFile     *os.File
+	// errLogFile receives stderr; it is logFile when both streams share a
+	// file.
+	errLogFile    *os.File
 	done          <-chan error
 	kernelPath    string
 	initramfsPath string
 	rootfsPath    string
+	// rootfsShared marks rootfsPath as a shared read-only image that
+	// outlives the instance.
+	rootfsShared bool
+	// disks are caller-owned writable disks; they are copied into snapshots
+	// but never removed by the instance.
+	disks []string
+	// shares are the virtiofsd processes serving the instance's shares; they
+	// are stopped once the hypervisor exits.
+	shares []shareDaemon
+	// statePath records the instance for adoption after a volantd restart.
+	statePath string
 }
 
 func (i *instance) Name() string          { return i.name }
-func (i *instance) PID() int              { return i.cmd.Process.Pid }
+func (i *instance) PID() int              { return i.process.Pid }
 func (i *instance) APISocketPath() string { return i.apiSocket }
 func (i *instance) Wait() <-chan error    { return i.done }
 
 func (i *instance) Stop(ctx context.Context) error {
-	defer i.logFile.Close()
+	defer i.closeLogs()
 	stopCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
 	defer cancel()
 
-	if i.cmd.Process == nil {
+	if i.process == nil {
 		return nil
 	}
 
-	if err := i.cmd.Process.Signal(syscall.SIGTERM); err != nil {
+	if err := i.process.Signal(syscall.SIGTERM); err != nil {
 		return fmt.Errorf("cloudhypervisor: signal term: %w", err)
 	}
 
@@ -331,7 +503,7 @@ func (i *instance) Stop(ctx context.Context) error {
 			return fmt.Errorf("cloudhypervisor: wait: %w", err)
 		}
 	case <-stopCtx.Done():
-		_ = i.cmd.Process.Signal(syscall.SIGKILL)
+		_ = i.process.Signal(syscall.SIGKILL)
 		if err, ok := <-i.done; ok && err != nil {
 			_ = os.Remove(i.apiSocket)
 			return fmt.Errorf("cloudhypervisor: wait after kill: %w", err)
@@ -343,6 +515,15 @@ func (i *instance) Stop(ctx context.Context) error {
 	return nil
 }
 
+func (i *instance) closeLogs() {
+	if i.logFile != nil {
+		_ = i.logFile.Close()
+	}
+	if i.errLogFile != nil && i.errLogFile != i.logFile {
+		_ = i.errLogFile.Close()
+	}
+}
+
 func (i *instance) cleanupArtifacts() {
 	if i.kernelPath != "" {
 		_ = os.Remove(i.kernelPath)
@@ -350,7 +531,7 @@ func (i *instance) cleanupArtifacts() {
 	if i.initramfsPath != "" {
 		_ = os.Remove(i.initramfsPath)
 	}
-	if i.rootfsPath != "" {
+	if i.rootfsPath != "" && !i.rootfsShared {
 		_ = os.Remove(i.rootfsPath)
 	}
 	if i.serialPath != "" {
@@ -359,6 +540,30 @@ func (i *instance) cleanupArtifacts() {
 	if i.consolePath != "" {
 		_ = os.Remove(i.consolePath)
 	}
+	if i.statePath != "" {
+		_ = os.Remove(i.statePath)
+	}
+}
+
+func writableDisks(spec runtime.LaunchSpec) []string {
+	var disks []runtime.Disk
+	if spec.RootFS != "" && !spec.RootFSReadOnly && spec.RootFSClone != "" {
+		disks = append(disks, runtime.Disk{Path: spec.RootFSClone})
+	}
+	disks = append(disks, spec.Disks...)
+	if spec.SeedDisk != nil {
+		disks = append(disks, *spec.SeedDisk)
+	}
+	if spec.OverlayDisk != nil {
+		disks = append(disks, *spec.OverlayDisk)
+	}
+	var paths []string
+	for _, disk := range disks {
+		if path := strings.TrimSpace(disk.Path); path != "" && !disk.Readonly {
+			paths = append(paths, path)
+		}
+	}
+	return paths
 }
 
 func removeIfExists(path string) error {
@@ -405,34 +610,49 @@ func copyFile(src, dst string) error {
 }
 
 func streamFile(ctx context.Context, src, dst, checksum string) error {
-	out, err := os.Create(dst)
-	if err != nil {
-		return err
-	}
-	defer out.Close()
-
 	var reader io.ReadCloser
-	if strings.HasPrefix(src, "http://") || strings.HasPrefix(src, "https://") {
-		req, err := http.NewRequestWithContext(ctx, http.MethodGet, src, nil)
-		if err != nil {
-			return err
-		}
-		resp, err := http.DefaultClient.Do(req)
-		if err != nil {
-			return err
-		}
-		if resp.StatusCode >= 300 {
-			resp.Body.Close()
-			return fmt.Errorf("download %s: status %s", src, resp.Status)
-		}
-		reader = resp.Body
+	var err error
+	if isRemote(src) {
+		reader, err = download(ctx, src, nil)
 	} else {
 		reader, err = os.Open(src)
-		if err != nil {
-			return err
-		}
+	}
+	if err != nil {
+		return err
 	}
 	defer reader.Close()
+	return writeVerified(reader, src, dst, checksum)
+}
+
+// download opens the body of a GET of url, sending header with the request.
+func download(ctx context.Context, url string, header http.Header) (io.ReadCloser, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	for key, values := range header {
+		req.Header[key] = values
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode >= 300 {
+		resp.Body.Close()
+		return nil, fmt.Errorf("download %s: status %s", url, resp.Status)
+	}
+	return resp.Body, nil
+}
+
+// writeVerified copies reader to dst and checks the SHA-256 of what was
+// written against checksum, when one is given. src names the source in
+// errors.
+func writeVerified(reader io.Reader, src, dst, checksum string) error {
+	out, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
 
 	hasher := sha256.New()
 	if _, err := io.Copy(io.MultiWriter(out, hasher), reader); err != nil {
@@ -443,11 +663,38 @@ func streamFile(ctx context.Context, src, dst, checksum string) error {
 		expected := strings.TrimPrefix(strings.TrimSpace(checksum), "sha256:")
 		actual := fmt.Sprintf("%x", hasher.Sum(nil))
 		if !strings.EqualFold(expected, actual) {
-			return fmt.Errorf("checksum mismatch: expected %s got %s", expected, actual)
+			return runtime.NewLaunchError(runtime.ErrorChecksumMismatch, fmt.Errorf("checksum mismatch for %s: expected %s got %s", src, expected, actual))
 		}
 	}
 	return nil
 }
 
+// preflight reports missing host prerequisites as classified errors before
+// any artifacts are staged.
+func (l *Launcher) preflight() error {
+	if _, err := exec.LookPath(l.Binary); err != nil {
+		return runtime.NewLaunchError(runtime.ErrorBinaryMissing, fmt.Errorf("cloudhypervisor: binary %s: %w", l.Binary, err))
+	}
+	kvm, err := os.OpenFile(kvmDevice, os.O_RDWR, 0)
+	switch {
+	case err == nil:
+		return kvm.Close()
+	case errors.Is(err, os.ErrNotExist):
+		return runtime.NewLaunchError(runtime.ErrorKVMUnavailable, fmt.Errorf("cloudhypervisor: %w", err))
+	case errors.Is(err, os.ErrPermission):
+		return runtime.NewLaunchError(runtime.ErrorKVMPermission, fmt.Errorf("cloudhypervisor: %w", err))
+	default:
+		return fmt.Errorf("cloudhypervisor: open %s: %w", kvmDevice, err)
+	}
+}
+
+// kvmDevice is the device cloud-hypervisor opens to create guests.
+const kvmDevice = "/dev/kvm"
+
 var _ runtime.Launcher = (*Launcher)(nil)
 var _ runtime.Instance = (*instance)(nil)
+
+func isIPv4(addr string) bool {
+	ip := net.ParseIP(strings.TrimSpace(addr))
+	return ip != nil && ip.To4() != nil
+}
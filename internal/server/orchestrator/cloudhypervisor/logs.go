@@ -0,0 +1,52 @@
+// Copyright (c) 2025 HYPR. PTE. LTD.
+//
+// Business Source License 1.1
+// See LICENSE file in the project root for details.
+
+package cloudhypervisor
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/volantvm/volant/internal/server/orchestrator/runtime"
+)
+
+// openLogs opens the files receiving the hypervisor's stdout and stderr:
+// spec.StdoutLog and spec.StderrLog when set, else <name>.log in the log
+// directory for both. The files are opened for appending so whoever ingests
+// them may truncate them while the hypervisor runs.
+func (l *Launcher) openLogs(spec runtime.LaunchSpec) (*os.File, *os.File, error) {
+	if l.LogDir == "" {
+		l.LogDir = l.RuntimeDir
+	}
+	shared := filepath.Join(l.LogDir, fmt.Sprintf("%s.log", spec.Name))
+	stdoutPath, stderrPath := spec.StdoutLog, spec.StderrLog
+	if stdoutPath == "" {
+		stdoutPath = shared
+	}
+	if stderrPath == "" {
+		stderrPath = shared
+	}
+	stdout, err := openLog(stdoutPath)
+	if err != nil {
+		return nil, nil, fmt.Errorf("cloudhypervisor: open log file: %w", err)
+	}
+	if stderrPath == stdoutPath {
+		return stdout, stdout, nil
+	}
+	stderr, err := openLog(stderrPath)
+	if err != nil {
+		_ = stdout.Close()
+		return nil, nil, fmt.Errorf("cloudhypervisor: open log file: %w", err)
+	}
+	return stdout, stderr, nil
+}
+
+func openLog(path string) (*os.File, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, err
+	}
+	return os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+}
@@ -0,0 +1,48 @@
+// Copyright (c) 2025 HYPR. PTE. LTD.
+//
+// Business Source License 1.1
+// See LICENSE file in the project root for details.
+
+package cloudhypervisor
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/volantvm/volant/internal/server/orchestrator/runtime"
+)
+
+// cpusArg builds --cpus, pinning every vCPU to the whole CPUSet when one is
+// given, e.g. boot=2,affinity=[0@[2-3],1@[2-3]].
+func cpusArg(spec runtime.LaunchSpec) string {
+	arg := fmt.Sprintf("boot=%d", spec.CPUCores)
+	cpuset := strings.TrimSpace(spec.CPUSet)
+	if cpuset == "" {
+		return arg
+	}
+	vcpus := make([]string, 0, spec.CPUCores)
+	for i := 0; i < spec.CPUCores; i++ {
+		vcpus = append(vcpus, fmt.Sprintf("%d@[%s]", i, cpuset))
+	}
+	return fmt.Sprintf("%s,affinity=[%s]", arg, strings.Join(vcpus, ","))
+}
+
+// memoryArgs builds the memory flags. Memory bound to a NUMA node lives in a
+// memory zone, since only zones take host_numa_node; the top-level size is
+// then zero. Shares need guest memory mapped shared so virtiofsd can reach it.
+func memoryArgs(spec runtime.LaunchSpec) []string {
+	var opts string
+	if spec.HugepageSizeKB > 0 {
+		opts = fmt.Sprintf(",hugepages=on,hugepage_size=%dK", spec.HugepageSizeKB)
+	}
+	if len(spec.Shares) > 0 {
+		opts += ",shared=on"
+	}
+	if spec.NUMANode == nil {
+		return []string{"--memory", fmt.Sprintf("size=%dM%s", spec.MemoryMB, opts)}
+	}
+	return []string{
+		"--memory", "size=0",
+		"--memory-zone", fmt.Sprintf("id=mem0,size=%dM,host_numa_node=%d%s", spec.MemoryMB, *spec.NUMANode, opts),
+	}
+}
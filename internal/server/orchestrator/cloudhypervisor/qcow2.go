@@ -0,0 +1,101 @@
+// Copyright (c) 2025 HYPR. PTE. LTD.
+//
+// Business Source License 1.1
+// See LICENSE file in the project root for details.
+
+package cloudhypervisor
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/volantvm/volant/internal/server/orchestrator/runtime"
+)
+
+// qcow2Magic opens every qcow2 image.
+var qcow2Magic = []byte{'Q', 'F', 'I', 0xfb}
+
+// stageRootFSClone stages the qcow2 image of spec once per host and returns
+// spec.RootFSClone, creating it as a copy-on-write overlay of that image when
+// missing. An existing clone keeps the backing image it was created from, so
+// the VM keeps its changes across restarts.
+func (l *Launcher) stageRootFSClone(ctx context.Context, spec runtime.LaunchSpec) (string, error) {
+	base, err := l.stageCached(ctx, "images", ".qcow2", spec.RootFS, spec.RootFSChecksum)
+	if err != nil {
+		return "", err
+	}
+	clone := spec.RootFSClone
+	if _, err := os.Stat(clone); err == nil {
+		backing, err := qcow2BackingFile(clone)
+		if err != nil {
+			return "", fmt.Errorf("clone %s: %w", clone, err)
+		}
+		if backing != "" {
+			if _, err := os.Stat(backing); err != nil {
+				return "", fmt.Errorf("clone %s: backing image: %w", clone, err)
+			}
+		}
+		return clone, nil
+	}
+	if _, err := qcow2BackingFile(base); err != nil {
+		return "", fmt.Errorf("image %s: %w", spec.RootFS, err)
+	}
+	qemuImg, err := exec.LookPath("qemu-img")
+	if err != nil {
+		return "", runtime.NewLaunchError(runtime.ErrorQemuImgMissing, fmt.Errorf("qemu-img: %w", err))
+	}
+	if err := os.MkdirAll(filepath.Dir(clone), 0o755); err != nil {
+		return "", err
+	}
+	tmp := clone + ".tmp"
+	_ = os.Remove(tmp)
+	if out, err := exec.CommandContext(ctx, qemuImg, "create", "-q", "-f", "qcow2", "-F", "qcow2", "-b", base, tmp).CombinedOutput(); err != nil {
+		_ = os.Remove(tmp)
+		return "", fmt.Errorf("qemu-img create: %w: %s", err, strings.TrimSpace(string(out)))
+	}
+	if err := os.Rename(tmp, clone); err != nil {
+		_ = os.Remove(tmp)
+		return "", err
+	}
+	return clone, nil
+}
+
+// qcow2BackingFile returns the backing file named in the header of the qcow2
+// image at path, or "" for a standalone image. Relative names are resolved
+// against the image's directory.
+func qcow2BackingFile(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+	// magic, version, backing_file_offset (u64), backing_file_size (u32)
+	header := make([]byte, 20)
+	if _, err := io.ReadFull(file, header); err != nil || !bytes.Equal(header[:4], qcow2Magic) {
+		return "", fmt.Errorf("not a qcow2 image")
+	}
+	offset := binary.BigEndian.Uint64(header[8:16])
+	size := binary.BigEndian.Uint32(header[16:20])
+	if offset == 0 || size == 0 {
+		return "", nil
+	}
+	if size > 1023 {
+		return "", fmt.Errorf("backing file name too long")
+	}
+	name := make([]byte, size)
+	if _, err := file.ReadAt(name, int64(offset)); err != nil {
+		return "", fmt.Errorf("read backing file name: %w", err)
+	}
+	backing := string(name)
+	if !filepath.IsAbs(backing) {
+		backing = filepath.Join(filepath.Dir(path), backing)
+	}
+	return backing, nil
+}
@@ -0,0 +1,178 @@
+// Copyright (c) 2025 HYPR. PTE. LTD.
+//
+// Business Source License 1.1
+// See LICENSE file in the project root for details.
+
+package cloudhypervisor
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/volantvm/volant/internal/server/apiauth"
+	"github.com/volantvm/volant/internal/server/orchestrator/runtime"
+)
+
+// Artifacts lists the default kernels, the plugin kernels and initramfs
+// images cached for VMs, and the shared read-only root images and qcow2 clone
+// bases staged on this host.
+func (l *Launcher) Artifacts() ([]runtime.Artifact, error) {
+	var artifacts []runtime.Artifact
+	for _, path := range []string{l.BZImagePath, l.VMLinuxPath} {
+		if strings.TrimSpace(path) == "" {
+			continue
+		}
+		if info, err := os.Stat(path); err == nil && info.Mode().IsRegular() {
+			artifacts = append(artifacts, runtime.Artifact{Kind: runtime.ArtifactKernel, Path: path, SizeBytes: info.Size(), ModifiedAt: info.ModTime()})
+		}
+	}
+	for _, cache := range []struct {
+		kind, pattern string
+	}{
+		{runtime.ArtifactKernel, filepath.Join("kernels", "*.kernel")},
+		{runtime.ArtifactInitramfs, filepath.Join("initramfs", "*.initramfs")},
+		{runtime.ArtifactRootFS, filepath.Join("images", "*.rootfs")},
+		{runtime.ArtifactRootFS, filepath.Join("images", "*.qcow2")},
+	} {
+		matches, err := filepath.Glob(filepath.Join(l.RuntimeDir, cache.pattern))
+		if err != nil {
+			return nil, err
+		}
+		for _, path := range matches {
+			if info, err := os.Stat(path); err == nil {
+				name := filepath.Base(filepath.Dir(path)) + "/" + filepath.Base(path)
+				artifacts = append(artifacts, runtime.Artifact{Kind: cache.kind, Name: name, Path: path, SizeBytes: info.Size(), ModifiedAt: info.ModTime()})
+			}
+		}
+	}
+	return artifacts, nil
+}
+
+// cacheName matches the names Artifacts reports for cached artifacts.
+var cacheName = regexp.MustCompile(`^(kernels/[0-9a-f]{64}\.kernel|initramfs/[0-9a-f]{64}\.initramfs|images/[0-9a-f]{64}\.(rootfs|qcow2))$`)
+
+// OpenArtifact opens a cached artifact by the name Artifacts reports. Peer
+// hosts pull artifacts through it instead of downloading them from their
+// origin.
+func (l *Launcher) OpenArtifact(name string) (*os.File, error) {
+	if !cacheName.MatchString(name) {
+		return nil, fmt.Errorf("cloudhypervisor: artifact %q: %w", name, os.ErrNotExist)
+	}
+	return os.Open(filepath.Join(l.RuntimeDir, filepath.FromSlash(name)))
+}
+
+// StageArtifacts fetches sources into the caches Launch reads them from.
+// Kernels given as plain local paths are not cached and are skipped.
+func (l *Launcher) StageArtifacts(ctx context.Context, sources []runtime.ArtifactSource) error {
+	for _, source := range sources {
+		var err error
+		switch {
+		case source.Kind == runtime.ArtifactKernel && cachedKernel(source.URL, source.Checksum):
+			_, err = l.stageCached(ctx, "kernels", ".kernel", source.URL, source.Checksum)
+		case source.Kind == runtime.ArtifactInitramfs:
+			_, err = l.stageCached(ctx, "initramfs", ".initramfs", source.URL, source.Checksum)
+		case source.Kind == runtime.ArtifactRootFS && source.Clone:
+			_, err = l.stageCached(ctx, "images", ".qcow2", source.URL, source.Checksum)
+		case source.Kind == runtime.ArtifactRootFS:
+			_, err = l.stageSharedRootFS(ctx, source.URL, source.Checksum)
+		}
+		if err != nil {
+			return fmt.Errorf("cloudhypervisor: stage %s %s: %w", source.Kind, source.URL, err)
+		}
+	}
+	return nil
+}
+
+// stageSharedRootFS stages a read-only root image once under
+// RuntimeDir/images and returns its path. VMs booting the same image share
+// the file, so it is never removed with an instance.
+func (l *Launcher) stageSharedRootFS(ctx context.Context, src, checksum string) (string, error) {
+	return l.stageCached(ctx, "images", ".rootfs", src, checksum)
+}
+
+// stageCached downloads or copies src once into RuntimeDir/<dir>, verifying
+// checksum, and returns the cached path. Local files are keyed by size and
+// modification time as well, so replacing one on disk stages a fresh copy.
+// Remote sources are cached under the same name on every host, so they are
+// pulled from a peer that already has them before the origin is tried.
+func (l *Launcher) stageCached(ctx context.Context, dir, ext, src, checksum string) (string, error) {
+	src = strings.TrimPrefix(src, "file://")
+	key := src + "\x00" + strings.TrimSpace(checksum)
+	if !isRemote(src) {
+		info, err := os.Stat(src)
+		if err != nil {
+			return "", err
+		}
+		key = fmt.Sprintf("%s\x00%d\x00%d", key, info.Size(), info.ModTime().UnixNano())
+	}
+	name := fmt.Sprintf("%s/%x%s", dir, sha256.Sum256([]byte(key)), ext)
+	dir = filepath.Join(l.RuntimeDir, dir)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", err
+	}
+	path := filepath.Join(l.RuntimeDir, filepath.FromSlash(name))
+	if _, err := os.Stat(path); err == nil {
+		return path, nil
+	}
+
+	tmp, err := os.CreateTemp(dir, ".staging-*")
+	if err != nil {
+		return "", err
+	}
+	tmpPath := tmp.Name()
+	_ = tmp.Close()
+	if !isRemote(src) || !l.pullFromPeers(ctx, name, tmpPath, checksum) {
+		if err := streamFile(ctx, src, tmpPath, checksum); err != nil {
+			_ = os.Remove(tmpPath)
+			return "", err
+		}
+	}
+	if err := os.Chmod(tmpPath, 0o444); err != nil {
+		_ = os.Remove(tmpPath)
+		return "", err
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		_ = os.Remove(tmpPath)
+		return "", err
+	}
+	return path, nil
+}
+
+// pullFromPeers copies the cached artifact name from the first peer that
+// serves it into dst and reports whether one did. A copy that fails the
+// checksum is discarded like any other miss.
+func (l *Launcher) pullFromPeers(ctx context.Context, name, dst, checksum string) bool {
+	header := http.Header{}
+	if l.PeerAPIKey != "" {
+		header.Set(apiauth.Header, l.PeerAPIKey)
+	}
+	for _, peer := range l.Peers {
+		url := strings.TrimRight(peer, "/") + "/api/v1/nodes/local/artifacts/" + name
+		body, err := download(ctx, url, header)
+		if err != nil {
+			continue
+		}
+		err = writeVerified(body, url, dst, checksum)
+		body.Close()
+		if err == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// cachedKernel reports whether a kernel source is fetched into the kernel
+// cache rather than copied straight from a local path.
+func cachedKernel(src, checksum string) bool {
+	return isRemote(src) || strings.HasPrefix(src, "file://") || checksum != ""
+}
+
+func isRemote(src string) bool {
+	return strings.HasPrefix(src, "http://") || strings.HasPrefix(src, "https://")
+}
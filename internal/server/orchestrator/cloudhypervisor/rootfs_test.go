@@ -0,0 +1,115 @@
+// Copyright (c) 2025 HYPR. PTE. LTD.
+//
+// Business Source License 1.1
+// See LICENSE file in the project root for details.
+
+package cloudhypervisor
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/volantvm/volant/internal/server/apiauth"
+	"github.com/volantvm/volant/internal/server/orchestrator/runtime"
+)
+
+func TestStagePullsFromPeers(t *testing.T) {
+	image := []byte("root image")
+	checksum := fmt.Sprintf("sha256:%x", sha256.Sum256(image))
+
+	var originHits atomic.Int32
+	origin := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		originHits.Add(1)
+		_, _ = w.Write(image)
+	}))
+	defer origin.Close()
+
+	// A peer is a host that already staged the image.
+	seeded := New("", "", "", t.TempDir(), "")
+	source := runtime.ArtifactSource{Kind: runtime.ArtifactRootFS, URL: origin.URL + "/base.qcow2", Checksum: checksum, Clone: true}
+	if err := seeded.StageArtifacts(context.Background(), []runtime.ArtifactSource{source}); err != nil {
+		t.Fatalf("seed peer: %v", err)
+	}
+	artifacts, err := seeded.Artifacts()
+	if err != nil || len(artifacts) != 1 || !strings.HasPrefix(artifacts[0].Name, "images/") {
+		t.Fatalf("expected the staged image listed by name: %+v %v", artifacts, err)
+	}
+	name := artifacts[0].Name
+
+	var peerBody atomic.Value
+	peerBody.Store(image)
+	peer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get(apiauth.Header) != "peer-key" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		f, err := seeded.OpenArtifact(strings.TrimPrefix(r.URL.Path, "/api/v1/nodes/local/artifacts/"))
+		if err != nil {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		defer f.Close()
+		if body := peerBody.Load().([]byte); string(body) != string(image) {
+			_, _ = w.Write(body)
+			return
+		}
+		_, _ = io.Copy(w, f)
+	}))
+	defer peer.Close()
+
+	for _, tc := range []struct {
+		name       string
+		peers      []string
+		key        string
+		body       []byte
+		originHits int32
+	}{
+		{name: "peer has it", peers: []string{peer.URL}, key: "peer-key", body: image},
+		{name: "unreachable peer first", peers: []string{"http://127.0.0.1:1", peer.URL + "/"}, key: "peer-key", body: image},
+		{name: "wrong key", peers: []string{peer.URL}, key: "other", body: image, originHits: 1},
+		{name: "corrupt copy", peers: []string{peer.URL}, key: "peer-key", body: []byte("tampered"), originHits: 1},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			originHits.Store(0)
+			peerBody.Store(tc.body)
+			l := New("", "", "", t.TempDir(), "")
+			l.Peers = tc.peers
+			l.PeerAPIKey = tc.key
+			path, err := l.stageCached(context.Background(), "images", ".qcow2", source.URL, checksum)
+			if err != nil {
+				t.Fatalf("stage: %v", err)
+			}
+			if got, _ := os.ReadFile(path); string(got) != string(image) {
+				t.Fatalf("staged %q, want %q", got, image)
+			}
+			if !strings.HasSuffix(path, name) {
+				t.Fatalf("expected the peer's cache name %s, got %s", name, path)
+			}
+			if hits := originHits.Load(); hits != tc.originHits {
+				t.Fatalf("origin fetched %d times, want %d", hits, tc.originHits)
+			}
+		})
+	}
+}
+
+func TestOpenArtifactRejectsOtherPaths(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(dir+"/secret", []byte("x"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	l := New("", "", "", dir+"/runtime", "")
+	for _, name := range []string{"../secret", "images/../../secret", "images/base.qcow2", "kernels/" + strings.Repeat("a", 64) + ".qcow2"} {
+		if _, err := l.OpenArtifact(name); !errors.Is(err, os.ErrNotExist) {
+			t.Errorf("open %q: expected not exist, got %v", name, err)
+		}
+	}
+}
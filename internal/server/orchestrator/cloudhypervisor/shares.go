@@ -0,0 +1,135 @@
+// Copyright (c) 2025 HYPR. PTE. LTD.
+//
+// Business Source License 1.1
+// See LICENSE file in the project root for details.
+
+package cloudhypervisor
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"syscall"
+	"time"
+
+	"github.com/volantvm/volant/internal/server/orchestrator/runtime"
+)
+
+const (
+	// shareSocketInfix separates the VM name from the share tag in virtiofsd
+	// socket names, e.g. web.virtiofs-data.sock.
+	shareSocketInfix = ".virtiofs-"
+	shareStopTimeout = 5 * time.Second
+)
+
+// virtiofsdPaths are checked when virtiofsd is not on PATH; distributions
+// install it outside the default search path.
+var virtiofsdPaths = []string{
+	"/usr/libexec/virtiofsd",
+	"/usr/lib/qemu/virtiofsd",
+	"/usr/lib/virtiofsd",
+}
+
+// shareDaemon is a virtiofsd process serving one share of an instance.
+type shareDaemon struct {
+	Tag    string `json:"tag"`
+	PID    int    `json:"pid"`
+	Socket string `json:"socket"`
+}
+
+func (l *Launcher) shareSocketPath(name, tag string) string {
+	return filepath.Join(l.RuntimeDir, name+shareSocketInfix+tag+".sock")
+}
+
+// fsArgs builds one --fs flag per share of spec.
+func (l *Launcher) fsArgs(spec runtime.LaunchSpec) []string {
+	var args []string
+	for _, share := range spec.Shares {
+		args = append(args, "--fs", fmt.Sprintf("tag=%s,socket=%s,num_queues=1,queue_size=1024", share.Tag, l.shareSocketPath(spec.Name, share.Tag)))
+	}
+	return args
+}
+
+func (l *Launcher) virtiofsdBinary() (string, error) {
+	if l.Virtiofsd != "" {
+		return exec.LookPath(l.Virtiofsd)
+	}
+	if path, err := exec.LookPath("virtiofsd"); err == nil {
+		return path, nil
+	}
+	for _, path := range virtiofsdPaths {
+		if info, err := os.Stat(path); err == nil && info.Mode().IsRegular() && info.Mode()&0o111 != 0 {
+			return path, nil
+		}
+	}
+	return "", exec.ErrNotFound
+}
+
+// startShares runs one virtiofsd per share of spec and waits until each
+// listens on its vhost-user socket. On failure the daemons already started
+// are stopped again.
+func (l *Launcher) startShares(ctx context.Context, spec runtime.LaunchSpec, logFile *os.File) ([]shareDaemon, error) {
+	if len(spec.Shares) == 0 {
+		return nil, nil
+	}
+	binary, err := l.virtiofsdBinary()
+	if err != nil {
+		return nil, runtime.NewLaunchError(runtime.ErrorVirtiofsdMissing, fmt.Errorf("cloudhypervisor: virtiofsd: %w", err))
+	}
+	daemons := make([]shareDaemon, 0, len(spec.Shares))
+	for _, share := range spec.Shares {
+		if info, err := os.Stat(share.HostPath); err != nil || !info.IsDir() {
+			stopShares(daemons)
+			return nil, fmt.Errorf("cloudhypervisor: share %s: %s is not a directory", share.Tag, share.HostPath)
+		}
+		socket := l.shareSocketPath(spec.Name, share.Tag)
+		if err := removeIfExists(socket); err != nil {
+			stopShares(daemons)
+			return nil, fmt.Errorf("cloudhypervisor: share %s: prepare socket: %w", share.Tag, err)
+		}
+		args := []string{"--socket-path=" + socket, "--shared-dir=" + share.HostPath, "--cache=auto"}
+		if share.ReadOnly {
+			args = append(args, "--readonly")
+		}
+		cmd := exec.CommandContext(ctx, binary, args...)
+		cmd.Stdout = logFile
+		cmd.Stderr = logFile
+		if err := cmd.Start(); err != nil {
+			stopShares(daemons)
+			return nil, fmt.Errorf("cloudhypervisor: share %s: start virtiofsd: %w", share.Tag, err)
+		}
+		exited := make(chan error, 1)
+		go func() {
+			exited <- cmd.Wait()
+			close(exited)
+		}()
+		daemons = append(daemons, shareDaemon{Tag: share.Tag, PID: cmd.Process.Pid, Socket: socket})
+		if err := waitForSocket(ctx, socket, exited); err != nil {
+			stopShares(daemons)
+			return nil, fmt.Errorf("cloudhypervisor: share %s: virtiofsd: %w", share.Tag, err)
+		}
+	}
+	return daemons, nil
+}
+
+// stopShares terminates the virtiofsd processes of an instance and removes
+// their sockets. Processes no longer serving their recorded socket, such as a
+// reused PID, are left alone.
+func stopShares(daemons []shareDaemon) {
+	for _, daemon := range daemons {
+		process, err := os.FindProcess(daemon.PID)
+		if err == nil && process.Signal(syscall.Signal(0)) == nil && hasArg(daemon.PID, "--socket-path="+daemon.Socket) {
+			_ = process.Signal(syscall.SIGTERM)
+			deadline := time.Now().Add(shareStopTimeout)
+			for process.Signal(syscall.Signal(0)) == nil && time.Now().Before(deadline) {
+				time.Sleep(50 * time.Millisecond)
+			}
+			if process.Signal(syscall.Signal(0)) == nil {
+				_ = process.Signal(syscall.SIGKILL)
+			}
+		}
+		_ = removeIfExists(daemon.Socket)
+	}
+}
@@ -0,0 +1,230 @@
+// Copyright (c) 2025 HYPR. PTE. LTD.
+//
+// Business Source License 1.1
+// See LICENSE file in the project root for details.
+
+package cloudhypervisor
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"time"
+
+	"github.com/volantvm/volant/internal/server/orchestrator/runtime"
+)
+
+const (
+	snapshotArtifactsDir  = "artifacts"
+	snapshotManifestFile  = "volant-artifacts.json"
+	restoreSocketTimeout  = 10 * time.Second
+	restoreSocketInterval = 100 * time.Millisecond
+)
+
+// snapshotArtifacts lists the host files a snapshot depends on. Cloud
+// Hypervisor only captures memory and device state, so writable disks and the
+// staged boot artifacts are copied alongside it and put back on restore.
+type snapshotArtifacts struct {
+	Kernel    string   `json:"kernel"`
+	Initramfs string   `json:"initramfs,omitempty"`
+	RootFS    string   `json:"rootfs,omitempty"`
+	Disks     []string `json:"disks,omitempty"`
+	// SharedRootFS is a read-only image shared with other VMs; it is never
+	// modified, so it is referenced rather than copied.
+	SharedRootFS string `json:"shared_rootfs,omitempty"`
+}
+
+func (a snapshotArtifacts) paths() []string {
+	paths := make([]string, 0, len(a.Disks)+3)
+	for _, path := range append([]string{a.Kernel, a.Initramfs, a.RootFS}, a.Disks...) {
+		if path != "" {
+			paths = append(paths, path)
+		}
+	}
+	return paths
+}
+
+func artifactCopyPath(dir string, index int, path string) string {
+	return filepath.Join(dir, snapshotArtifactsDir, fmt.Sprintf("%d-%s", index, filepath.Base(path)))
+}
+
+// Snapshot pauses the guest, writes its state and disk copies to dir and
+// resumes it.
+func (i *instance) Snapshot(ctx context.Context, dir string) error {
+	client := apiClient(i.apiSocket)
+	if err := apiCall(ctx, client, "vm.pause", nil); err != nil {
+		return fmt.Errorf("cloudhypervisor: pause: %w", err)
+	}
+	err := i.writeSnapshot(ctx, client, dir)
+	if resumeErr := apiCall(ctx, client, "vm.resume", nil); resumeErr != nil && err == nil {
+		err = fmt.Errorf("cloudhypervisor: resume: %w", resumeErr)
+	}
+	return err
+}
+
+func (i *instance) writeSnapshot(ctx context.Context, client *http.Client, dir string) error {
+	if err := apiCall(ctx, client, "vm.snapshot", map[string]string{"destination_url": "file://" + dir}); err != nil {
+		return fmt.Errorf("cloudhypervisor: snapshot: %w", err)
+	}
+	artifacts := snapshotArtifacts{
+		Kernel:    i.kernelPath,
+		Initramfs: i.initramfsPath,
+		Disks:     i.disks,
+	}
+	if i.rootfsShared {
+		artifacts.SharedRootFS = i.rootfsPath
+	} else {
+		artifacts.RootFS = i.rootfsPath
+	}
+	if err := os.MkdirAll(filepath.Join(dir, snapshotArtifactsDir), 0o755); err != nil {
+		return fmt.Errorf("cloudhypervisor: create artifacts dir: %w", err)
+	}
+	for idx, path := range artifacts.paths() {
+		if err := copyFile(path, artifactCopyPath(dir, idx, path)); err != nil {
+			return fmt.Errorf("cloudhypervisor: copy %s: %w", filepath.Base(path), err)
+		}
+	}
+	data, err := json.MarshalIndent(artifacts, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(filepath.Join(dir, snapshotManifestFile), data, 0o644); err != nil {
+		return fmt.Errorf("cloudhypervisor: write snapshot manifest: %w", err)
+	}
+	return nil
+}
+
+// Restore starts Cloud Hypervisor from a snapshot written by Snapshot and
+// resumes the guest.
+func (l *Launcher) Restore(ctx context.Context, spec runtime.LaunchSpec, dir string) (runtime.Instance, error) {
+	if l.Binary == "" {
+		return nil, fmt.Errorf("cloudhypervisor: binary path required")
+	}
+	if err := l.preflight(); err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(filepath.Join(dir, snapshotManifestFile))
+	if err != nil {
+		return nil, fmt.Errorf("cloudhypervisor: read snapshot manifest: %w", err)
+	}
+	var artifacts snapshotArtifacts
+	if err := json.Unmarshal(data, &artifacts); err != nil {
+		return nil, fmt.Errorf("cloudhypervisor: decode snapshot manifest: %w", err)
+	}
+	for idx, path := range artifacts.paths() {
+		if err := copyFile(artifactCopyPath(dir, idx, path), path); err != nil {
+			return nil, fmt.Errorf("cloudhypervisor: restore %s: %w", filepath.Base(path), err)
+		}
+	}
+
+	logFile, errLogFile, err := l.openLogs(spec)
+	if err != nil {
+		return nil, err
+	}
+	closeLogs := func() {
+		_ = logFile.Close()
+		if errLogFile != logFile {
+			_ = errLogFile.Close()
+		}
+	}
+
+	apiSocket := filepath.Join(l.RuntimeDir, fmt.Sprintf("%s.sock", spec.Name))
+	_ = os.Remove(apiSocket)
+	if err := removeIfExists(spec.SerialSocket); err != nil {
+		closeLogs()
+		return nil, fmt.Errorf("cloudhypervisor: prepare serial socket: %w", err)
+	}
+
+	// The restored guest reconnects to its shares at the sockets it was
+	// launched with, so their daemons must be listening first.
+	shares, err := l.startShares(ctx, spec, errLogFile)
+	if err != nil {
+		closeLogs()
+		return nil, err
+	}
+
+	cmd := exec.CommandContext(ctx, l.Binary,
+		"--api-socket", fmt.Sprintf("path=%s", apiSocket),
+		"--restore", fmt.Sprintf("source_url=file://%s", dir),
+	)
+	cmd.Stdout = logFile
+	cmd.Stderr = errLogFile
+	if err := cmd.Start(); err != nil {
+		stopShares(shares)
+		closeLogs()
+		return nil, fmt.Errorf("cloudhypervisor: start restore: %w", err)
+	}
+
+	stateFile := l.statePath(spec.Name)
+	done := make(chan error, 1)
+	go func() {
+		err := cmd.Wait()
+		stopShares(shares)
+		_ = removeIfExists(stateFile)
+		done <- err
+		close(done)
+	}()
+
+	inst := &instance{
+		name:          spec.Name,
+		process:       cmd.Process,
+		apiSocket:     apiSocket,
+		serialPath:    spec.SerialSocket,
+		logFile:       logFile,
+		errLogFile:    errLogFile,
+		done:          done,
+		kernelPath:    artifacts.Kernel,
+		initramfsPath: artifacts.Initramfs,
+		rootfsPath:    artifacts.RootFS,
+		disks:         artifacts.Disks,
+		shares:        shares,
+	}
+	if artifacts.SharedRootFS != "" {
+		inst.rootfsPath = artifacts.SharedRootFS
+		inst.rootfsShared = true
+	}
+	err = waitForSocket(ctx, apiSocket, done)
+	if err == nil {
+		err = apiCall(ctx, apiClient(apiSocket), "vm.resume", nil)
+	}
+	if err != nil {
+		_ = inst.Stop(ctx)
+		inst.cleanupArtifacts()
+		return nil, fmt.Errorf("cloudhypervisor: resume restored vm: %w", err)
+	}
+	if err := l.writeState(inst, spec); err != nil {
+		fmt.Fprintf(errLogFile, "volant: record instance state: %v\n", err)
+	}
+	return inst, nil
+}
+
+func waitForSocket(ctx context.Context, path string, done <-chan error) error {
+	deadline := time.NewTimer(restoreSocketTimeout)
+	defer deadline.Stop()
+	ticker := time.NewTicker(restoreSocketInterval)
+	defer ticker.Stop()
+	for {
+		if _, err := os.Stat(path); err == nil {
+			return nil
+		} else if !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-done:
+			return fmt.Errorf("process exited before socket %s appeared", path)
+		case <-deadline.C:
+			return fmt.Errorf("socket %s not ready after %s", path, restoreSocketTimeout)
+		case <-ticker.C:
+		}
+	}
+}
+
+var _ runtime.Restorer = (*Launcher)(nil)
+var _ runtime.Snapshotter = (*instance)(nil)
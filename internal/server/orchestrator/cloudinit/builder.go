@@ -7,6 +7,7 @@ package cloudinit
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"io"
 	"os"
@@ -28,6 +29,9 @@ type SeedInput struct {
 	UserData      string
 	MetaData      string
 	NetworkConfig string
+	// PublicKeys are SSH authorized keys added to meta-data as public-keys,
+	// which cloud-init installs for the image's default user.
+	PublicKeys []string
 }
 
 // Build creates a cloud-init seed image at dest using either cloud-localds or genisoimage/mkisofs.
@@ -39,20 +43,26 @@ func Build(ctx context.Context, input SeedInput, dest string) error {
 		return fmt.Errorf("cloudinit: ensure destination directory: %w", err)
 	}
 
-	tmpDir, err := os.MkdirTemp("", "cloudinit-seed-")
+	docs, err := seedDocuments(input)
 	if err != nil {
-		return fmt.Errorf("cloudinit: temp dir: %w", err)
+		return err
 	}
-	defer os.RemoveAll(tmpDir)
+	return buildSeed(ctx, docs, dest)
+}
 
+// documents are the files of a seed image.
+type documents struct {
+	userData      string
+	metaData      string
+	networkConfig string
+}
+
+// seedDocuments renders input into the files written to the seed image.
+func seedDocuments(input SeedInput) (documents, error) {
 	userData := strings.TrimSpace(input.UserData)
 	if userData == "" {
 		userData = "#cloud-config\n"
 	}
-	if err := os.WriteFile(filepath.Join(tmpDir, "user-data"), []byte(userData), 0o644); err != nil {
-		return fmt.Errorf("cloudinit: write user-data: %w", err)
-	}
-
 	metaData := strings.TrimSpace(input.MetaData)
 	if metaData == "" {
 		instID := strings.TrimSpace(input.InstanceID)
@@ -65,14 +75,34 @@ func Build(ctx context.Context, input SeedInput, dest string) error {
 		}
 		metaData = fmt.Sprintf("instance-id: %s\nlocal-hostname: %s\n", instID, hostname)
 	}
-	if err := os.WriteFile(filepath.Join(tmpDir, "meta-data"), []byte(metaData), 0o644); err != nil {
-		return fmt.Errorf("cloudinit: write meta-data: %w", err)
+	metaData, err := withPublicKeys(metaData, input.PublicKeys)
+	if err != nil {
+		return documents{}, err
+	}
+	networkConfig := ""
+	if strings.TrimSpace(input.NetworkConfig) != "" {
+		networkConfig = input.NetworkConfig
+	}
+	return documents{userData: userData, metaData: metaData, networkConfig: networkConfig}, nil
+}
+
+func buildSeed(ctx context.Context, docs documents, dest string) error {
+	tmpDir, err := os.MkdirTemp("", "cloudinit-seed-")
+	if err != nil {
+		return fmt.Errorf("cloudinit: temp dir: %w", err)
 	}
+	defer os.RemoveAll(tmpDir)
 
+	if err := os.WriteFile(filepath.Join(tmpDir, "user-data"), []byte(docs.userData), 0o644); err != nil {
+		return fmt.Errorf("cloudinit: write user-data: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(tmpDir, "meta-data"), []byte(docs.metaData), 0o644); err != nil {
+		return fmt.Errorf("cloudinit: write meta-data: %w", err)
+	}
 	networkPath := ""
-	if strings.TrimSpace(input.NetworkConfig) != "" {
+	if docs.networkConfig != "" {
 		networkPath = filepath.Join(tmpDir, "network-config")
-		if err := os.WriteFile(networkPath, []byte(input.NetworkConfig), 0o644); err != nil {
+		if err := os.WriteFile(networkPath, []byte(docs.networkConfig), 0o644); err != nil {
 			return fmt.Errorf("cloudinit: write network-config: %w", err)
 		}
 	}
@@ -83,15 +113,41 @@ func Build(ctx context.Context, input SeedInput, dest string) error {
 		}
 	}
 	files := map[string][]byte{
-		"user-data": []byte(userData),
-		"meta-data": []byte(metaData),
+		"user-data": []byte(docs.userData),
+		"meta-data": []byte(docs.metaData),
 	}
-	if strings.TrimSpace(input.NetworkConfig) != "" {
-		files["network-config"] = []byte(input.NetworkConfig)
+	if docs.networkConfig != "" {
+		files["network-config"] = []byte(docs.networkConfig)
 	}
 	return buildVFAT(dest, files)
 }
 
+// withPublicKeys appends keys to metaData as a public-keys list. Keys are
+// written as JSON strings, which YAML reads as double-quoted scalars.
+func withPublicKeys(metaData string, keys []string) (string, error) {
+	if len(keys) == 0 {
+		return metaData, nil
+	}
+	for _, line := range strings.Split(metaData, "\n") {
+		if strings.HasPrefix(line, "public-keys:") {
+			return "", fmt.Errorf("cloudinit: meta-data already sets public-keys; add the keys there or drop the ssh keys")
+		}
+	}
+	var b strings.Builder
+	b.WriteString(strings.TrimRight(metaData, "\n"))
+	b.WriteString("\npublic-keys:\n")
+	for _, key := range keys {
+		quoted, err := json.Marshal(key)
+		if err != nil {
+			return "", fmt.Errorf("cloudinit: encode public key: %w", err)
+		}
+		b.WriteString("  - ")
+		b.Write(quoted)
+		b.WriteString("\n")
+	}
+	return b.String(), nil
+}
+
 func runCloudLocalDS(ctx context.Context, dest, tmpDir, networkPath string) error {
 	args := []string{}
 	if networkPath != "" {
@@ -0,0 +1,122 @@
+// Copyright (c) 2025 HYPR. PTE. LTD.
+//
+// Business Source License 1.1
+// See LICENSE file in the project root for details.
+
+package cloudinit
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+const cacheSuffix = ".img"
+
+// Cache keeps seed images by the documents they were built from, so a VM
+// started again with unchanged cloud-init inputs reuses its image instead
+// of rebuilding it. Seeds are attached read-only, which lets a cached image
+// be hard-linked to every destination that needs it.
+type Cache struct {
+	dir string
+	max int
+}
+
+// NewCache returns a cache in dir holding at most max images; the least
+// recently used are removed first. A nil cache, or one with max < 1, always
+// builds.
+func NewCache(dir string, max int) *Cache {
+	return &Cache{dir: dir, max: max}
+}
+
+// Build places the seed image for input at dest, linking a cached image when
+// one was built from the same documents and building and caching it
+// otherwise. Failing to use the cache never fails the build.
+func (c *Cache) Build(ctx context.Context, input SeedInput, dest string) error {
+	if c == nil || c.max < 1 {
+		return Build(ctx, input, dest)
+	}
+	if strings.TrimSpace(dest) == "" {
+		return fmt.Errorf("cloudinit: destination path required")
+	}
+	if err := os.MkdirAll(filepath.Dir(dest), 0o755); err != nil {
+		return fmt.Errorf("cloudinit: ensure destination directory: %w", err)
+	}
+	docs, err := seedDocuments(input)
+	if err != nil {
+		return err
+	}
+	// dest may be a link to a cached image; never write through it.
+	if err := os.Remove(dest); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("cloudinit: remove previous seed: %w", err)
+	}
+
+	entry := filepath.Join(c.dir, docs.key()+cacheSuffix)
+	if err := os.Link(entry, dest); err == nil {
+		now := time.Now()
+		_ = os.Chtimes(entry, now, now)
+		return nil
+	}
+	if err := buildSeed(ctx, docs, dest); err != nil {
+		return err
+	}
+	c.store(dest, entry)
+	return nil
+}
+
+// store links the image built at path into the cache as entry, then trims
+// the cache to its size.
+func (c *Cache) store(path, entry string) {
+	// Rendered documents can hold secrets.
+	if err := os.MkdirAll(c.dir, 0o700); err != nil {
+		return
+	}
+	tmp := fmt.Sprintf("%s.%d.tmp", entry, time.Now().UnixNano())
+	if err := os.Link(path, tmp); err != nil {
+		return
+	}
+	if err := os.Rename(tmp, entry); err != nil {
+		_ = os.Remove(tmp)
+		return
+	}
+	c.prune()
+}
+
+// prune removes the least recently used images beyond the cache size.
+func (c *Cache) prune() {
+	matches, err := filepath.Glob(filepath.Join(c.dir, "*"+cacheSuffix))
+	if err != nil || len(matches) <= c.max {
+		return
+	}
+	type cached struct {
+		path string
+		used time.Time
+	}
+	entries := make([]cached, 0, len(matches))
+	for _, path := range matches {
+		info, err := os.Stat(path)
+		if err != nil {
+			continue
+		}
+		entries = append(entries, cached{path: path, used: info.ModTime()})
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].used.After(entries[j].used) })
+	for _, entry := range entries[min(c.max, len(entries)):] {
+		_ = os.Remove(entry.path)
+	}
+}
+
+// key identifies the image built from docs.
+func (d documents) key() string {
+	sum := sha256.New()
+	for _, doc := range []string{d.userData, d.metaData, d.networkConfig} {
+		fmt.Fprintf(sum, "%d:%s", len(doc), doc)
+	}
+	return hex.EncodeToString(sum.Sum(nil))
+}
@@ -0,0 +1,55 @@
+// Copyright (c) 2025 HYPR. PTE. LTD.
+//
+// Business Source License 1.1
+// See LICENSE file in the project root for details.
+
+package cloudinit
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"text/template"
+)
+
+// TemplateData holds the values cloud-init templates can reference, as
+// {{ .Name }}, {{ .IP }} and so on.
+type TemplateData struct {
+	Name       string
+	Hostname   string
+	InstanceID string
+	Plugin     string
+	IP         string
+	IPv6       string
+	MAC        string
+	// APIHost and APIPort are the volantd endpoint the guest reaches.
+	APIHost string
+	APIPort string
+	Labels  map[string]string
+}
+
+// SecretFunc returns the value of the named secret.
+type SecretFunc func(name string) (string, error)
+
+// Render executes doc as a Go template with data. `{{ secret "name" }}`
+// inserts a secret's value through secret; referencing a missing field or
+// secret fails instead of rendering an empty string.
+func Render(field, doc string, data TemplateData, secret SecretFunc) (string, error) {
+	funcs := template.FuncMap{
+		"secret": func(name string) (string, error) {
+			if secret == nil {
+				return "", errors.New("secrets unavailable")
+			}
+			return secret(name)
+		},
+	}
+	tmpl, err := template.New(field).Funcs(funcs).Option("missingkey=error").Parse(doc)
+	if err != nil {
+		return "", fmt.Errorf("cloudinit: parse %s template: %w", field, err)
+	}
+	var out strings.Builder
+	if err := tmpl.Execute(&out, data); err != nil {
+		return "", fmt.Errorf("cloudinit: render %s template: %w", field, err)
+	}
+	return out.String(), nil
+}
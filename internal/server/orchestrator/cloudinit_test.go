@@ -0,0 +1,169 @@
+// Copyright (c) 2025 HYPR. PTE. LTD.
+//
+// Business Source License 1.1
+// See LICENSE file in the project root for details.
+
+package orchestrator
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/volantvm/volant/internal/pluginspec"
+	"github.com/volantvm/volant/internal/server/db"
+	"github.com/volantvm/volant/internal/server/orchestrator/cloudinit"
+	"github.com/volantvm/volant/internal/server/orchestrator/vmconfig"
+	"github.com/volantvm/volant/internal/server/secrets"
+)
+
+type fakeSecrets map[string]string
+
+func (f fakeSecrets) Open(_ context.Context, name string, _ secrets.Access) (string, error) {
+	if value, ok := f[name]; ok {
+		return value, nil
+	}
+	return "", fmt.Errorf("%w: %s", secrets.ErrSecretNotFound, name)
+}
+
+func (f fakeSecrets) PutReserved(_ context.Context, name, value string) (*secrets.Info, bool, error) {
+	_, exists := f[name]
+	f[name] = value
+	return &secrets.Info{Name: name}, !exists, nil
+}
+
+func (f fakeSecrets) DeleteReserved(_ context.Context, name string) error {
+	if _, ok := f[name]; !ok {
+		return fmt.Errorf("%w: %s", secrets.ErrSecretNotFound, name)
+	}
+	delete(f, name)
+	return nil
+}
+
+func TestRenderCloudInit(t *testing.T) {
+	e := &engine{secrets: fakeSecrets{"db-password": "hunter2"}}
+	vm := &db.VM{Name: "web-1", Runtime: "nginx", IPAddress: "192.168.127.10", MACAddress: "02:00:00:00:00:0a"}
+	merged := &pluginspec.CloudInit{
+		UserData: pluginspec.CloudInitDoc{Inline: true, Template: true, Content: "#cloud-config\nhostname: {{ .Name }}\npassword: {{ secret \"db-password\" }}\napi: http://{{ .APIHost }}:{{ .APIPort }}"},
+		MetaData: pluginspec.CloudInitDoc{Inline: true, Content: "instance-id: {{ literal }}"},
+	}
+	input := cloudinit.SeedInput{InstanceID: "volant-1", UserData: merged.UserData.Content, MetaData: merged.MetaData.Content}
+
+	seed, err := e.renderCloudInit(context.Background(), vm, nil, merged, input, vmconfig.API{Host: "192.168.127.1", Port: "7777"})
+	if err != nil {
+		t.Fatalf("render: %v", err)
+	}
+	for _, want := range []string{"hostname: web-1", "password: hunter2", "api: http://192.168.127.1:7777"} {
+		if !strings.Contains(seed.UserData, want) {
+			t.Errorf("user-data missing %q:\n%s", want, seed.UserData)
+		}
+	}
+	if seed.MetaData != input.MetaData {
+		t.Errorf("expected non-template meta-data untouched, got %q", seed.MetaData)
+	}
+	if !strings.Contains(input.UserData, `secret "db-password"`) {
+		t.Errorf("expected the caller's input to keep the template")
+	}
+
+	merged.UserData.Content = `password: {{ secret "missing" }}`
+	input.UserData = merged.UserData.Content
+	if _, err := e.renderCloudInit(context.Background(), vm, nil, merged, input, vmconfig.API{}); err == nil {
+		t.Fatal("expected a missing secret to fail rendering")
+	}
+	merged.UserData.Content = `host: {{ .Nmae }}`
+	input.UserData = merged.UserData.Content
+	if _, err := e.renderCloudInit(context.Background(), vm, nil, merged, input, vmconfig.API{}); err == nil {
+		t.Fatal("expected an unknown field to fail rendering")
+	}
+	if _, err := (&engine{}).renderCloudInit(context.Background(), vm, nil, &pluginspec.CloudInit{UserData: pluginspec.CloudInitDoc{Inline: true, Template: true, Content: `{{ secret "db-password" }}`}}, cloudinit.SeedInput{UserData: `{{ secret "db-password" }}`}, vmconfig.API{}); err == nil {
+		t.Fatal("expected secrets to be unavailable without a resolver")
+	}
+}
+
+func TestSeedCacheReusesImage(t *testing.T) {
+	ctx := context.Background()
+	store := openTestStore(t)
+	defer func() { _ = store.Close(ctx) }()
+
+	subnet, host := testSubnet(t)
+	runtimeDir := t.TempDir()
+	engine, err := New(Params{
+		Store:            store,
+		Logger:           slog.New(slog.NewTextHandler(io.Discard, nil)),
+		Subnet:           subnet,
+		HostIP:           host,
+		APIListenAddr:    "127.0.0.1:7777",
+		APIAdvertiseAddr: "127.0.0.1:7777",
+		RuntimeDir:       runtimeDir,
+		Launcher:         &testLauncher{},
+		Network:          &testNetworkManager{},
+		SeedCacheSize:    4,
+	})
+	if err != nil {
+		t.Fatalf("new engine: %v", err)
+	}
+	if err := engine.Start(ctx); err != nil {
+		t.Fatalf("engine start: %v", err)
+	}
+	defer func() { _ = engine.Stop(ctx) }()
+
+	manifest := &pluginspec.Manifest{Name: "browser", Runtime: "browser", CloudInit: &pluginspec.CloudInit{
+		UserData: pluginspec.CloudInitDoc{Inline: true, Content: "#cloud-config\nruncmd: [true]"},
+	}}
+	if _, err := engine.CreateVM(ctx, CreateVMRequest{Name: "web", Plugin: "browser", Runtime: "browser", CPUCores: 1, MemoryMB: 512, Manifest: manifest}); err != nil {
+		t.Fatalf("create vm: %v", err)
+	}
+	seedPath := filepath.Join(runtimeDir, "cloudinit", "web"+seedImageSuffix)
+	built, err := os.Stat(seedPath)
+	if err != nil {
+		t.Fatalf("stat seed: %v", err)
+	}
+	cached, _ := filepath.Glob(filepath.Join(runtimeDir, "cloudinit", "cache", "*.img"))
+	if len(cached) != 1 {
+		t.Fatalf("expected the seed cached once, got %v", cached)
+	}
+
+	// Starting again with unchanged inputs links the cached image instead
+	// of building a new one.
+	if _, err := engine.StopVM(ctx, "web"); err != nil {
+		t.Fatalf("stop vm: %v", err)
+	}
+	if _, err := engine.StartVM(ctx, "web"); err != nil {
+		t.Fatalf("start vm: %v", err)
+	}
+	restarted, err := os.Stat(seedPath)
+	if err != nil {
+		t.Fatalf("stat seed after restart: %v", err)
+	}
+	if !os.SameFile(built, restarted) {
+		t.Fatal("expected the restarted vm to reuse the cached seed image")
+	}
+
+	// Changed inputs build and cache a new image.
+	userData := "#cloud-config\nruncmd: [false]"
+	if _, err := engine.StopVM(ctx, "web"); err != nil {
+		t.Fatalf("stop vm: %v", err)
+	}
+	if _, err := engine.UpdateVMConfig(ctx, "web", vmconfig.Patch{CloudInit: &pluginspec.CloudInit{Datasource: "NoCloud", UserData: pluginspec.CloudInitDoc{Inline: true, Content: userData}}}); err != nil {
+		t.Fatalf("update config: %v", err)
+	}
+	if _, err := engine.StartVM(ctx, "web"); err != nil {
+		t.Fatalf("start vm: %v", err)
+	}
+	rebuilt, err := os.Stat(seedPath)
+	if err != nil {
+		t.Fatalf("stat rebuilt seed: %v", err)
+	}
+	if os.SameFile(built, rebuilt) {
+		t.Fatal("expected changed cloud-init inputs to build a new seed image")
+	}
+	cached, _ = filepath.Glob(filepath.Join(runtimeDir, "cloudinit", "cache", "*.img"))
+	if len(cached) != 2 {
+		t.Fatalf("expected both seeds cached, got %v", cached)
+	}
+}
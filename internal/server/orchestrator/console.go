@@ -0,0 +1,260 @@
+// Copyright (c) 2025 HYPR. PTE. LTD.
+//
+// Business Source License 1.1
+// See LICENSE file in the project root for details.
+
+package orchestrator
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+)
+
+const (
+	// consoleHistoryBytes caps the serial output kept for each VM.
+	consoleHistoryBytes = 256 << 10
+	// consoleDialTimeout bounds how long the tee waits for the hypervisor to
+	// create the serial socket after launch.
+	consoleDialTimeout = 30 * time.Second
+	// consoleSessionBuffer is the number of output chunks a session may fall
+	// behind before it is disconnected.
+	consoleSessionBuffer = 256
+)
+
+// consoleLog holds the recent serial output of a VM and the connection to
+// its serial socket while the VM runs. volantd is the socket's only client;
+// console sessions attach through it.
+type consoleLog struct {
+	mu       sync.Mutex
+	history  []byte
+	conn     net.Conn
+	sessions map[*ConsoleSession]struct{}
+}
+
+// ConsoleSession is a client attached to a VM serial console.
+type ConsoleSession struct {
+	// History is the output captured before the session attached.
+	History []byte
+	output  chan []byte
+	log     *consoleLog
+	closed  bool
+}
+
+// Output delivers serial output as it arrives. It is closed when the VM's
+// serial connection ends, the session falls too far behind, or Close is
+// called.
+func (s *ConsoleSession) Output() <-chan []byte {
+	return s.output
+}
+
+// Write sends input to the VM serial console.
+func (s *ConsoleSession) Write(p []byte) (int, error) {
+	s.log.mu.Lock()
+	conn := s.log.conn
+	s.log.mu.Unlock()
+	if conn == nil {
+		return 0, fmt.Errorf("%w: serial console disconnected", ErrVMNotRunning)
+	}
+	return conn.Write(p)
+}
+
+// Close detaches the session.
+func (s *ConsoleSession) Close() {
+	s.log.mu.Lock()
+	defer s.log.mu.Unlock()
+	s.log.dropLocked(s)
+}
+
+func (l *consoleLog) dropLocked(session *ConsoleSession) {
+	if session.closed {
+		return
+	}
+	session.closed = true
+	delete(l.sessions, session)
+	close(session.output)
+}
+
+func (l *consoleLog) append(chunk []byte) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.history = append(l.history, chunk...)
+	if over := len(l.history) - consoleHistoryBytes; over > 0 {
+		l.history = append(l.history[:0], l.history[over:]...)
+	}
+	for session := range l.sessions {
+		select {
+		case session.output <- append([]byte(nil), chunk...):
+		default:
+			l.dropLocked(session)
+		}
+	}
+}
+
+// console returns the log of name, creating it when missing.
+func (e *engine) console(name string) *consoleLog {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.consoles == nil {
+		e.consoles = make(map[string]*consoleLog)
+	}
+	log, ok := e.consoles[name]
+	if !ok {
+		log = &consoleLog{sessions: make(map[*ConsoleSession]struct{})}
+		e.consoles[name] = log
+	}
+	return log
+}
+
+// dropConsole discards the console history of a deleted VM.
+func (e *engine) dropConsole(name string) {
+	e.mu.Lock()
+	log := e.consoles[name]
+	delete(e.consoles, name)
+	e.mu.Unlock()
+	if log == nil {
+		return
+	}
+	log.mu.Lock()
+	defer log.mu.Unlock()
+	for session := range log.sessions {
+		log.dropLocked(session)
+	}
+}
+
+// teeConsole connects to the serial socket of a launched VM and records its
+// output until the connection ends. exited is closed when the instance exits,
+// ending the wait for the socket to appear. History from earlier boots of the
+// VM is kept.
+func (e *engine) teeConsole(name, serial string, exited <-chan struct{}) {
+	if serial == "" {
+		return
+	}
+	conn, err := dialConsole(serial, exited)
+	if err != nil {
+		e.logger.Debug("console tee unavailable", "vm", name, "socket", serial, "error", err)
+		return
+	}
+	defer conn.Close()
+
+	log := e.console(name)
+	log.mu.Lock()
+	if log.conn != nil {
+		_ = log.conn.Close()
+	}
+	log.conn = conn
+	log.mu.Unlock()
+
+	buf := make([]byte, 4096)
+	for {
+		n, readErr := conn.Read(buf)
+		if n > 0 {
+			log.append(buf[:n])
+		}
+		if readErr != nil {
+			break
+		}
+	}
+
+	log.mu.Lock()
+	defer log.mu.Unlock()
+	if log.conn == conn {
+		log.conn = nil
+		for session := range log.sessions {
+			log.dropLocked(session)
+		}
+	}
+}
+
+func dialConsole(serial string, exited <-chan struct{}) (net.Conn, error) {
+	deadline := time.Now().Add(consoleDialTimeout)
+	for {
+		conn, err := net.Dial("unix", serial)
+		if err == nil {
+			return conn, nil
+		}
+		if time.Now().After(deadline) {
+			return nil, err
+		}
+		select {
+		case <-exited:
+			return nil, errors.New("instance exited")
+		case <-time.After(100 * time.Millisecond):
+		}
+	}
+}
+
+// AttachConsole attaches a session to the serial console of a running VM.
+// The session's History holds the last replayLines lines of output; zero
+// replays nothing.
+func (e *engine) AttachConsole(name string, replayLines int) (*ConsoleSession, error) {
+	e.mu.Lock()
+	log := e.consoles[name]
+	e.mu.Unlock()
+	if log == nil {
+		return nil, fmt.Errorf("%w: %s", ErrVMNotRunning, name)
+	}
+	log.mu.Lock()
+	defer log.mu.Unlock()
+	if log.conn == nil {
+		return nil, fmt.Errorf("%w: %s", ErrVMNotRunning, name)
+	}
+	session := &ConsoleSession{
+		output: make(chan []byte, consoleSessionBuffer),
+		log:    log,
+	}
+	if replayLines > 0 {
+		session.History = append([]byte(nil), tailLines(log.history, replayLines)...)
+	}
+	log.sessions[session] = struct{}{}
+	return session, nil
+}
+
+// ConsoleLog returns the last tail lines of serial output recorded for a VM
+// since volantd started, or all of it when tail is not positive. Output is
+// kept after the VM stops or crashes and discarded when it is deleted.
+func (e *engine) ConsoleLog(ctx context.Context, name string, tail int) ([]byte, error) {
+	vm, err := e.store.Queries().VirtualMachines().GetByName(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	if vm == nil {
+		return nil, fmt.Errorf("%w: %s", ErrVMNotFound, name)
+	}
+	e.mu.Lock()
+	log := e.consoles[name]
+	e.mu.Unlock()
+	if log == nil {
+		return []byte{}, nil
+	}
+	log.mu.Lock()
+	defer log.mu.Unlock()
+	if tail <= 0 {
+		return append([]byte(nil), log.history...), nil
+	}
+	return append([]byte(nil), tailLines(log.history, tail)...), nil
+}
+
+// tailLines returns the suffix of data holding its last n lines; a trailing
+// partial line counts as one.
+func tailLines(data []byte, n int) []byte {
+	end := len(data)
+	if end > 0 && data[end-1] == '\n' {
+		end--
+	}
+	for i := 0; i < n; i++ {
+		idx := bytes.LastIndexByte(data[:end], '\n')
+		if idx < 0 {
+			return data
+		}
+		if i == n-1 {
+			return data[idx+1:]
+		}
+		end = idx
+	}
+	return data
+}
@@ -0,0 +1,145 @@
+// Copyright (c) 2025 HYPR. PTE. LTD.
+//
+// Business Source License 1.1
+// See LICENSE file in the project root for details.
+
+package orchestrator
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/volantvm/volant/internal/pluginspec"
+)
+
+func TestConsoleHistory(t *testing.T) {
+	ctx := context.Background()
+	store := openTestStore(t)
+	defer func() { _ = store.Close(ctx) }()
+
+	subnet, host := testSubnet(t)
+	runtimeDir := t.TempDir()
+	engine, err := New(Params{
+		Store:            store,
+		Logger:           slog.New(slog.NewTextHandler(io.Discard, nil)),
+		Subnet:           subnet,
+		HostIP:           host,
+		APIListenAddr:    "127.0.0.1:7777",
+		APIAdvertiseAddr: "127.0.0.1:7777",
+		RuntimeDir:       runtimeDir,
+		Launcher:         &testLauncher{},
+		Network:          &testNetworkManager{},
+	})
+	if err != nil {
+		t.Fatalf("new engine: %v", err)
+	}
+	if err := engine.Start(ctx); err != nil {
+		t.Fatalf("engine start: %v", err)
+	}
+	defer func() { _ = engine.Stop(ctx) }()
+
+	// Stand in for the hypervisor's serial socket.
+	listener, err := net.Listen("unix", filepath.Join(runtimeDir, "demo.serial"))
+	if err != nil {
+		t.Fatalf("listen serial: %v", err)
+	}
+	defer listener.Close()
+
+	if _, err := engine.CreateVM(ctx, CreateVMRequest{
+		Name:     "demo",
+		CPUCores: 1,
+		MemoryMB: 512,
+		Manifest: &pluginspec.Manifest{Name: "demo", Runtime: "demo"},
+	}); err != nil {
+		t.Fatalf("create vm: %v", err)
+	}
+
+	_ = listener.(*net.UnixListener).SetDeadline(time.Now().Add(5 * time.Second))
+	serial, err := listener.Accept()
+	if err != nil {
+		t.Fatalf("accept serial: %v", err)
+	}
+	defer serial.Close()
+	if _, err := serial.Write([]byte("boot 1\nboot 2\nboot 3\n")); err != nil {
+		t.Fatalf("write serial: %v", err)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		output, err := engine.ConsoleLog(ctx, "demo", 0)
+		if err != nil {
+			t.Fatalf("console log: %v", err)
+		}
+		if string(output) == "boot 1\nboot 2\nboot 3\n" {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("console log = %q", output)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if output, _ := engine.ConsoleLog(ctx, "demo", 1); string(output) != "boot 3\n" {
+		t.Fatalf("tail 1 = %q", output)
+	}
+
+	session, err := engine.AttachConsole("demo", 2)
+	if err != nil {
+		t.Fatalf("attach console: %v", err)
+	}
+	defer session.Close()
+	if string(session.History) != "boot 2\nboot 3\n" {
+		t.Fatalf("session history = %q", session.History)
+	}
+
+	if _, err := session.Write([]byte("ls\n")); err != nil {
+		t.Fatalf("session write: %v", err)
+	}
+	_ = serial.SetReadDeadline(time.Now().Add(5 * time.Second))
+	buf := make([]byte, 16)
+	n, err := serial.Read(buf)
+	if err != nil || string(buf[:n]) != "ls\n" {
+		t.Fatalf("serial read = %q, %v", buf[:n], err)
+	}
+
+	if _, err := serial.Write([]byte("login:")); err != nil {
+		t.Fatalf("write serial: %v", err)
+	}
+	select {
+	case chunk := <-session.Output():
+		if string(chunk) != "login:" {
+			t.Fatalf("session output = %q", chunk)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("no live output")
+	}
+
+	// Output survives the serial connection going away.
+	_ = serial.Close()
+	select {
+	case _, ok := <-session.Output():
+		if ok {
+			t.Fatal("expected session closed")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("session not closed on disconnect")
+	}
+	if _, err := engine.AttachConsole("demo", 0); !errors.Is(err, ErrVMNotRunning) {
+		t.Fatalf("expected ErrVMNotRunning, got %v", err)
+	}
+	if output, _ := engine.ConsoleLog(ctx, "demo", 0); string(output) != "boot 1\nboot 2\nboot 3\nlogin:" {
+		t.Fatalf("console log after disconnect = %q", output)
+	}
+
+	if err := engine.DestroyVM(ctx, "demo"); err != nil {
+		t.Fatalf("destroy vm: %v", err)
+	}
+	if _, err := engine.ConsoleLog(ctx, "demo", 0); !errors.Is(err, ErrVMNotFound) {
+		t.Fatalf("expected ErrVMNotFound, got %v", err)
+	}
+}
@@ -0,0 +1,159 @@
+// Copyright (c) 2025 HYPR. PTE. LTD.
+//
+// Business Source License 1.1
+// See LICENSE file in the project root for details.
+
+package orchestrator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/volantvm/volant/internal/server/db"
+	"github.com/volantvm/volant/internal/server/orchestrator/vmconfig"
+)
+
+// ErrDependencyUnavailable indicates a VM named in depends_on does not exist,
+// could not be started or did not meet its condition in time.
+var ErrDependencyUnavailable = errors.New("orchestrator: vm dependency unavailable")
+
+type dependencyChainKey struct{}
+
+// startDependencies starts the VMs name depends on that are not running, in
+// the order listed, and waits for each to meet its condition. Every start
+// path runs it before launching, so creating, starting, restoring or
+// scaling a VM brings its dependencies up first. The dependencies' own
+// dependencies are started the same way; a cycle fails the start.
+func (e *engine) startDependencies(ctx context.Context, name string, deps []vmconfig.Dependency) error {
+	if len(deps) == 0 {
+		return nil
+	}
+	chain, _ := ctx.Value(dependencyChainKey{}).([]string)
+	chain = append(slices.Clone(chain), name)
+	ctx = context.WithValue(ctx, dependencyChainKey{}, chain)
+
+	for _, dep := range deps {
+		if slices.Contains(chain, dep.VM) {
+			return fmt.Errorf("%w: dependency cycle %s -> %s", ErrDependencyUnavailable, strings.Join(chain, " -> "), dep.VM)
+		}
+		if err := e.startDependency(ctx, dep); err != nil {
+			return fmt.Errorf("%w: vm %s depends on %s: %v", ErrDependencyUnavailable, name, dep.VM, err)
+		}
+	}
+	return nil
+}
+
+func (e *engine) startDependency(ctx context.Context, dep vmconfig.Dependency) error {
+	vm, err := e.store.Queries().VirtualMachines().GetByName(ctx, dep.VM)
+	if err != nil {
+		return err
+	}
+	if vm == nil {
+		return fmt.Errorf("vm does not exist")
+	}
+	if !e.hasInstance(dep.VM) {
+		var startErr error
+		if vm.Status != db.VMStatusStarting {
+			_, startErr = e.StartVM(ctx, dep.VM)
+		}
+		// When another caller is starting it, let that start finish.
+		if err := e.waitForInstance(ctx, dep.VM, defaultReadinessTimeout); err != nil {
+			if startErr != nil {
+				return startErr
+			}
+			return err
+		}
+	}
+	if dep.WaitsForReady() {
+		if err := e.waitForReady(ctx, []string{dep.VM}, defaultReadinessTimeout); err != nil {
+			return err
+		}
+	}
+	if !e.hasInstance(dep.VM) {
+		return fmt.Errorf("vm exited before meeting condition %s", dependencyCondition(dep))
+	}
+	return nil
+}
+
+// waitForInstance waits for a VM another caller is starting to launch or to
+// give up.
+func (e *engine) waitForInstance(ctx context.Context, name string, timeout time.Duration) error {
+	waitCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	ticker := time.NewTicker(readinessPollInterval)
+	defer ticker.Stop()
+	for {
+		if e.hasInstance(name) {
+			return nil
+		}
+		vm, err := e.store.Queries().VirtualMachines().GetByName(waitCtx, name)
+		if err != nil {
+			return err
+		}
+		if vm == nil || vm.Status != db.VMStatusStarting {
+			return fmt.Errorf("vm did not start")
+		}
+		select {
+		case <-waitCtx.Done():
+			return fmt.Errorf("vm still starting after %s", timeout)
+		case <-ticker.C:
+		}
+	}
+}
+
+// stopDependents stops, in the background, the running VMs that depend on
+// name with stop_with set. It is called once name stopped, exited or was
+// deleted; stopping a dependent cascades to its own dependents.
+func (e *engine) stopDependents(name string) {
+	ctx := e.launchContext()
+	go func() {
+		for _, dependent := range e.dependents(ctx, name) {
+			e.logger.Info("stopping vm with its dependency", "vm", dependent, "dependency", name)
+			if _, err := e.StopVM(ctx, dependent); err != nil {
+				e.logger.Error("stop vm with its dependency", "vm", dependent, "dependency", name, "error", err)
+			}
+		}
+	}()
+}
+
+// dependents lists the running VMs declaring a stop_with dependency on name.
+func (e *engine) dependents(ctx context.Context, name string) []string {
+	queries := e.store.Queries()
+	vms, err := queries.VirtualMachines().List(ctx)
+	if err != nil {
+		e.logger.Error("list vms for dependents", "vm", name, "error", err)
+		return nil
+	}
+	var names []string
+	for _, vm := range vms {
+		if vm.Name == name || !e.hasInstance(vm.Name) {
+			continue
+		}
+		record, err := queries.VMConfigs().GetCurrent(ctx, vm.ID)
+		if err != nil || record == nil {
+			continue
+		}
+		versioned, err := vmconfig.FromDB(*record)
+		if err != nil {
+			continue
+		}
+		for _, dep := range versioned.Config.DependsOn {
+			if dep.VM == name && dep.StopWith {
+				names = append(names, vm.Name)
+				break
+			}
+		}
+	}
+	return names
+}
+
+func dependencyCondition(dep vmconfig.Dependency) string {
+	if dep.WaitsForReady() {
+		return vmconfig.DependencyReady
+	}
+	return vmconfig.DependencyStarted
+}
@@ -0,0 +1,198 @@
+// Copyright (c) 2025 HYPR. PTE. LTD.
+//
+// Business Source License 1.1
+// See LICENSE file in the project root for details.
+
+package orchestrator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/volantvm/volant/internal/pluginspec"
+	"github.com/volantvm/volant/internal/server/db"
+)
+
+// allocateDevices returns the PCI addresses to pass through to vmName: the
+// fixed pci_passthrough entries of devCfg followed by devices picked from the
+// host passthrough pool for each of its requests. Pool devices held by other
+// VMs, or sharing an IOMMU group with a device they hold, are skipped, and a
+// VM pinned to a NUMA node only gets devices on that node. Picked devices stay
+// recorded against vmName until releaseDevices.
+func (e *engine) allocateDevices(ctx context.Context, vmName string, devCfg *pluginspec.DeviceConfig, numaNode *int) ([]string, error) {
+	if devCfg == nil {
+		return nil, nil
+	}
+	addrs := append([]string(nil), devCfg.PCIPassthrough...)
+	if len(devCfg.Requests) == 0 {
+		return addrs, nil
+	}
+	if len(e.devicePool) == 0 {
+		return nil, fmt.Errorf("%w: vm %s requests devices but no passthrough pool is configured", ErrDevicesUnavailable, vmName)
+	}
+	assigned, err := e.store.Queries().DeviceAssignments().List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list device assignments: %w", err)
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	taken := make(map[string]struct{})
+	takenGroups := make(map[string]struct{})
+	for _, held := range assigned {
+		if held.VMName == vmName {
+			continue
+		}
+		taken[held.PCIAddress] = struct{}{}
+		if held.IOMMUGroup != "" {
+			takenGroups[held.IOMMUGroup] = struct{}{}
+		}
+	}
+	for owner, held := range e.devices {
+		if owner == vmName {
+			continue
+		}
+		for _, addr := range held {
+			taken[addr] = struct{}{}
+		}
+	}
+	for _, addr := range addrs {
+		taken[addr] = struct{}{}
+	}
+
+	var allocated []string
+	for _, req := range devCfg.Requests {
+		want := req.Wanted()
+		for _, addr := range e.devicePool {
+			if want == 0 {
+				break
+			}
+			if _, ok := taken[addr]; ok {
+				continue
+			}
+			info, err := e.vfioMgr.GetDeviceInfo(addr)
+			if err != nil || info == nil {
+				e.logger.Debug("skip passthrough pool device", "device", addr, "error", err)
+				continue
+			}
+			if !req.Matches(info.Vendor, info.Device, info.Class) {
+				continue
+			}
+			if _, ok := takenGroups[info.IOMMUGroup]; ok && info.IOMMUGroup != "" {
+				continue
+			}
+			if numaNode != nil && !deviceOnNode(info.NumaNode, *numaNode) {
+				continue
+			}
+			taken[addr] = struct{}{}
+			allocated = append(allocated, addr)
+			want--
+		}
+		if want > 0 {
+			return nil, fmt.Errorf("%w: no free device in the passthrough pool for %s (%d missing)", ErrDevicesUnavailable, req, want)
+		}
+	}
+
+	e.devices[vmName] = allocated
+	e.logger.Info("allocated passthrough devices", "vm", vmName, "devices", allocated)
+	return append(addrs, allocated...), nil
+}
+
+// claimDevices records pciAddrs as assigned to vm so no other VM is launched
+// with them. A device whose IOMMU group holds a device assigned to another VM
+// is refused too, since a group can only be passed through as a whole.
+// Conflicts wrap ErrDeviceClaimed.
+func (e *engine) claimDevices(ctx context.Context, vm *db.VM, pciAddrs []string) error {
+	if len(pciAddrs) == 0 {
+		return nil
+	}
+	groups := make(map[string]string, len(pciAddrs))
+	for _, addr := range pciAddrs {
+		if info, err := e.vfioMgr.GetDeviceInfo(addr); err == nil && info != nil {
+			groups[addr] = info.IOMMUGroup
+		}
+	}
+	return e.store.WithTx(ctx, func(q db.Queries) error {
+		repo := q.DeviceAssignments()
+		assigned, err := repo.List(ctx)
+		if err != nil {
+			return err
+		}
+		for _, addr := range pciAddrs {
+			group := groups[addr]
+			for _, held := range assigned {
+				if held.VMID == vm.ID {
+					continue
+				}
+				if held.PCIAddress == addr {
+					return fmt.Errorf("%w: %s is assigned to vm %s", ErrDeviceClaimed, addr, held.VMName)
+				}
+				if group != "" && held.IOMMUGroup == group {
+					return fmt.Errorf("%w: %s shares iommu group %s with %s assigned to vm %s", ErrDeviceClaimed, addr, group, held.PCIAddress, held.VMName)
+				}
+			}
+		}
+		for _, addr := range pciAddrs {
+			err := repo.Assign(ctx, db.DeviceAssignment{PCIAddress: addr, VMID: vm.ID, IOMMUGroup: groups[addr]})
+			if errors.Is(err, db.ErrDeviceAssigned) {
+				return fmt.Errorf("%w: %s is assigned to another vm", ErrDeviceClaimed, addr)
+			}
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// releaseDevices drops the device assignments of vmName, forgets the pool
+// devices allocated to it and hands those back to their host drivers.
+func (e *engine) releaseDevices(ctx context.Context, vmName string) {
+	if vm, err := e.store.Queries().VirtualMachines().GetByName(ctx, vmName); err == nil && vm != nil {
+		if err := e.store.Queries().DeviceAssignments().ReleaseVM(ctx, vm.ID); err != nil {
+			e.logger.Warn("release device assignments", "vm", vmName, "error", err)
+		}
+	}
+
+	e.mu.Lock()
+	addrs := e.devices[vmName]
+	delete(e.devices, vmName)
+	e.mu.Unlock()
+	if len(addrs) == 0 {
+		return
+	}
+	if err := e.vfioMgr.UnbindDevices(addrs); err != nil {
+		e.logger.Warn("release passthrough devices", "vm", vmName, "devices", addrs, "error", err)
+		return
+	}
+	e.logger.Info("released passthrough devices", "vm", vmName, "devices", addrs)
+}
+
+// pooledDevices returns the addresses in addrs that belong to the host
+// passthrough pool, so an adopted VM keeps the pool devices it was given.
+func (e *engine) pooledDevices(addrs []string) []string {
+	var pooled []string
+	for _, addr := range addrs {
+		for _, candidate := range e.devicePool {
+			if addr == candidate {
+				pooled = append(pooled, addr)
+				break
+			}
+		}
+	}
+	return pooled
+}
+
+// deviceOnNode reports whether a device's sysfs numa_node matches node.
+// Devices without NUMA affinity (-1) fit any node.
+func deviceOnNode(deviceNode string, node int) bool {
+	id, err := strconv.Atoi(strings.TrimSpace(deviceNode))
+	if err != nil || id < 0 {
+		return true
+	}
+	return id == node
+}
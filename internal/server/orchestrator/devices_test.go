@@ -0,0 +1,155 @@
+// Copyright (c) 2025 HYPR. PTE. LTD.
+//
+// Business Source License 1.1
+// See LICENSE file in the project root for details.
+
+package orchestrator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"reflect"
+	"testing"
+
+	"github.com/volantvm/volant/internal/pluginspec"
+	"github.com/volantvm/volant/internal/server/db"
+	"github.com/volantvm/volant/internal/server/devicemanager"
+)
+
+func TestAllocateDevicesFromPool(t *testing.T) {
+	ctx := context.Background()
+	store := openTestStore(t)
+	defer func() { _ = store.Close(ctx) }()
+
+	vfio := &poolDevices{devices: map[string]devicemanager.PCIDevice{
+		"0000:01:00.0": {Vendor: "10de", Device: "2204", Class: "030000", NumaNode: "0"},
+		"0000:02:00.0": {Vendor: "15b3", Device: "1017", Class: "020000", NumaNode: "0"},
+		"0000:81:00.0": {Vendor: "10de", Device: "2204", Class: "030000", NumaNode: "1"},
+	}}
+	e := &engine{
+		store:      store,
+		logger:     slog.New(slog.NewTextHandler(io.Discard, nil)),
+		vfioMgr:    vfio,
+		devicePool: []string{"0000:01:00.0", "0000:02:00.0", "0000:81:00.0"},
+		devices:    make(map[string][]string),
+	}
+	gpu := &pluginspec.DeviceConfig{Requests: []pluginspec.DeviceRequest{{Vendor: "10de", Class: "gpu", Count: 1}}}
+
+	first, err := e.allocateDevices(ctx, "a", gpu, nil)
+	if err != nil || !reflect.DeepEqual(first, []string{"0000:01:00.0"}) {
+		t.Fatalf("expected first gpu, got %v %v", first, err)
+	}
+	second, err := e.allocateDevices(ctx, "b", gpu, nil)
+	if err != nil || !reflect.DeepEqual(second, []string{"0000:81:00.0"}) {
+		t.Fatalf("expected second gpu, got %v %v", second, err)
+	}
+	if _, err := e.allocateDevices(ctx, "c", gpu, nil); !errors.Is(err, ErrDevicesUnavailable) {
+		t.Fatalf("expected pool exhausted, got %v", err)
+	}
+
+	e.releaseDevices(ctx, "a")
+	if !reflect.DeepEqual(vfio.unbound, []string{"0000:01:00.0"}) {
+		t.Fatalf("expected released gpu rebound to host, got %v", vfio.unbound)
+	}
+	node := 1
+	if _, err := e.allocateDevices(ctx, "c", gpu, &node); !errors.Is(err, ErrDevicesUnavailable) {
+		t.Fatalf("expected no free gpu on node 1, got %v", err)
+	}
+
+	mixed := &pluginspec.DeviceConfig{
+		PCIPassthrough: []string{"0000:03:00.0"},
+		Requests:       []pluginspec.DeviceRequest{{Class: "gpu"}, {Class: "02"}},
+	}
+	addrs, err := e.allocateDevices(ctx, "c", mixed, nil)
+	if err != nil || !reflect.DeepEqual(addrs, []string{"0000:03:00.0", "0000:01:00.0", "0000:02:00.0"}) {
+		t.Fatalf("expected fixed address followed by pool devices, got %v %v", addrs, err)
+	}
+	if !reflect.DeepEqual(e.devices["c"], []string{"0000:01:00.0", "0000:02:00.0"}) {
+		t.Fatalf("expected only pool devices recorded, got %v", e.devices["c"])
+	}
+
+	empty := &engine{devices: make(map[string][]string)}
+	if _, err := empty.allocateDevices(ctx, "d", gpu, nil); !errors.Is(err, ErrDevicesUnavailable) {
+		t.Fatalf("expected requests refused without a pool, got %v", err)
+	}
+}
+
+func TestClaimDevicesRefusesAssignedDevices(t *testing.T) {
+	ctx := context.Background()
+	store := openTestStore(t)
+	defer func() { _ = store.Close(ctx) }()
+
+	vms := store.Queries().VirtualMachines()
+	var records []*db.VM
+	for i, name := range []string{"gpu-a", "gpu-b"} {
+		vm := &db.VM{Name: name, Status: db.VMStatusStarting, Runtime: "llm", IPAddress: fmt.Sprintf("192.168.127.%d", i+2), MACAddress: fmt.Sprintf("02:00:00:00:00:%02x", i+2), CPUCores: 1, MemoryMB: 512}
+		id, err := vms.Create(ctx, vm)
+		if err != nil {
+			t.Fatalf("create vm %s: %v", name, err)
+		}
+		vm.ID = id
+		records = append(records, vm)
+	}
+	e := &engine{
+		store:  store,
+		logger: slog.New(slog.NewTextHandler(io.Discard, nil)),
+		vfioMgr: &poolDevices{devices: map[string]devicemanager.PCIDevice{
+			"0000:01:00.0": {Vendor: "10de", IOMMUGroup: "12"},
+			"0000:01:00.1": {Vendor: "10de", IOMMUGroup: "12"},
+			"0000:02:00.0": {Vendor: "10de", IOMMUGroup: "13"},
+		}},
+		devices: make(map[string][]string),
+	}
+
+	if err := e.claimDevices(ctx, records[0], []string{"0000:01:00.0"}); err != nil {
+		t.Fatalf("claim: %v", err)
+	}
+	if err := e.claimDevices(ctx, records[0], []string{"0000:01:00.0"}); err != nil {
+		t.Fatalf("expected reclaim by the holder to succeed: %v", err)
+	}
+	if err := e.claimDevices(ctx, records[1], []string{"0000:01:00.0"}); !errors.Is(err, ErrDeviceClaimed) {
+		t.Fatalf("expected same device refused, got %v", err)
+	}
+	if err := e.claimDevices(ctx, records[1], []string{"0000:02:00.0", "0000:01:00.1"}); !errors.Is(err, ErrDeviceClaimed) {
+		t.Fatalf("expected shared iommu group refused, got %v", err)
+	}
+	assignments, err := store.Queries().DeviceAssignments().List(ctx)
+	if err != nil || len(assignments) != 1 || assignments[0].VMName != "gpu-a" || assignments[0].IOMMUGroup != "12" {
+		t.Fatalf("expected only gpu-a's claim recorded, got %+v %v", assignments, err)
+	}
+
+	e.releaseDevices(ctx, "gpu-a")
+	if err := e.claimDevices(ctx, records[1], []string{"0000:01:00.1"}); err != nil {
+		t.Fatalf("expected group free after release: %v", err)
+	}
+}
+
+// poolDevices serves fixed device info and records unbinds.
+type poolDevices struct {
+	devices map[string]devicemanager.PCIDevice
+	unbound []string
+}
+
+func (p *poolDevices) GetDeviceInfo(addr string) (*devicemanager.PCIDevice, error) {
+	info, ok := p.devices[addr]
+	if !ok {
+		return nil, errors.New("device not found")
+	}
+	info.Address = addr
+	return &info, nil
+}
+
+func (p *poolDevices) UnbindDevices(addrs []string) error {
+	p.unbound = append(p.unbound, addrs...)
+	return nil
+}
+
+func (*poolDevices) ValidateDevices([]string, []string) error { return nil }
+func (*poolDevices) CheckIOMMUGroups([]string) ([]devicemanager.IOMMUGroup, error) {
+	return nil, nil
+}
+func (*poolDevices) BindDevices([]string) error                   { return nil }
+func (*poolDevices) GetVFIOGroupPaths([]string) ([]string, error) { return nil, nil }
@@ -0,0 +1,200 @@
+// Copyright (c) 2025 HYPR. PTE. LTD.
+//
+// Business Source License 1.1
+// See LICENSE file in the project root for details.
+
+//go:build linux
+// +build linux
+
+package doctor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/sys/unix"
+
+	"github.com/volantvm/volant/internal/pluginspec"
+	"github.com/volantvm/volant/internal/server/orchestrator/hostfeatures"
+)
+
+// kvmGetAPIVersion is the KVM_GET_API_VERSION ioctl.
+const kvmGetAPIVersion = 0xAE00
+
+func (c *Checker) checks(ctx context.Context) []Check {
+	features := hostfeatures.Probe()
+	return []Check{
+		checkKVM(),
+		c.checkHypervisor(ctx),
+		c.checkBridge(),
+		featureCheck(features, "tun", pluginspec.HostFeatureTun, StatusFail),
+		checkIPForward(),
+		featureCheck(features, "vsock", pluginspec.HostFeatureVhostVsock, StatusWarn),
+		c.checkIOMMU(),
+		c.checkDiskSpace(),
+	}
+}
+
+func checkKVM() Check {
+	check := Check{
+		Name: "kvm",
+		Hint: "Enable virtualization (VT-x/AMD-V) in the firmware, load kvm_intel or kvm_amd, and run volantd as a user that can open /dev/kvm.",
+	}
+	f, err := os.OpenFile("/dev/kvm", os.O_RDWR, 0)
+	if err != nil {
+		return fail(check, err.Error())
+	}
+	defer f.Close()
+	version, _, errno := unix.Syscall(unix.SYS_IOCTL, f.Fd(), kvmGetAPIVersion, 0)
+	if errno != 0 {
+		return fail(check, fmt.Sprintf("KVM_GET_API_VERSION: %v", errno))
+	}
+	return pass(check, fmt.Sprintf("/dev/kvm, API version %d", version))
+}
+
+func (c *Checker) checkHypervisor(ctx context.Context) Check {
+	check := Check{
+		Name: "hypervisor",
+		Hint: "Install cloud-hypervisor (https://github.com/cloud-hypervisor/cloud-hypervisor/releases) or point VOLANT_HYPERVISOR at its binary.",
+	}
+	binary := c.opts.HypervisorBinary
+	if binary == "" {
+		binary = "cloud-hypervisor"
+	}
+	path, err := exec.LookPath(binary)
+	if err != nil {
+		return fail(check, err.Error())
+	}
+	ctx, cancel := context.WithTimeout(ctx, hypervisorTimeout)
+	defer cancel()
+	out, err := exec.CommandContext(ctx, path, "--version").Output()
+	if err != nil {
+		return fail(check, fmt.Sprintf("%s --version: %v", path, err))
+	}
+	version := strings.TrimSpace(string(out))
+	if version == "" {
+		return fail(check, path+" --version printed nothing")
+	}
+	return pass(check, fmt.Sprintf("%s (%s)", version, path))
+}
+
+func (c *Checker) checkBridge() Check {
+	check := Check{
+		Name: "bridge",
+		Hint: fmt.Sprintf("Create the bridge with `sudo volar setup`, or set VOLANT_BRIDGE to an existing bridge (currently %q).", c.opts.BridgeName),
+	}
+	iface, err := net.InterfaceByName(c.opts.BridgeName)
+	if err != nil {
+		return fail(check, fmt.Sprintf("%s does not exist (%v)", c.opts.BridgeName, err))
+	}
+	if _, err := os.Stat(filepath.Join("/sys/class/net", iface.Name, "bridge")); err != nil {
+		return fail(check, iface.Name+" is not a bridge")
+	}
+	addrs, _ := iface.Addrs()
+	var listed []string
+	hasHostIP := false
+	for _, addr := range addrs {
+		listed = append(listed, addr.String())
+		if ipNet, ok := addr.(*net.IPNet); ok && ipNet.IP.String() == c.opts.HostIP {
+			hasHostIP = true
+		}
+	}
+	detail := fmt.Sprintf("%s [%s]", iface.Name, strings.Join(listed, ", "))
+	if iface.Flags&net.FlagUp == 0 {
+		check.Hint = fmt.Sprintf("volantd brings the bridge up when a VM starts; bring it up now with `ip link set %s up`.", iface.Name)
+		return warn(check, detail+" is down")
+	}
+	if c.opts.HostIP != "" && !hasHostIP {
+		check.Hint = fmt.Sprintf("Guests use %s as their gateway and API address; add it with `ip addr add %s/<prefix> dev %s` or rerun `sudo volar setup`.", c.opts.HostIP, c.opts.HostIP, iface.Name)
+		return fail(check, fmt.Sprintf("%s does not hold host ip %s", detail, c.opts.HostIP))
+	}
+	return pass(check, detail)
+}
+
+func featureCheck(report *hostfeatures.Report, name, feature string, missing Status) Check {
+	check := Check{Name: name}
+	probed, ok := report.Lookup(feature)
+	if !ok {
+		check.Status = missing
+		check.Detail = "not probed"
+		return check
+	}
+	check.Detail = probed.Detail
+	check.Hint = probed.Hint
+	if probed.Available {
+		check.Status = StatusPass
+	} else {
+		check.Status = missing
+	}
+	return check
+}
+
+func checkIPForward() Check {
+	check := Check{
+		Name: "ip_forwarding",
+		Hint: "Guests cannot reach other networks without it: sysctl -w net.ipv4.ip_forward=1 (persist it in /etc/sysctl.d), or rerun `sudo volar setup`.",
+	}
+	data, err := os.ReadFile("/proc/sys/net/ipv4/ip_forward")
+	if err != nil {
+		return warn(check, err.Error())
+	}
+	if strings.TrimSpace(string(data)) != "1" {
+		return warn(check, "net.ipv4.ip_forward is 0")
+	}
+	return pass(check, "net.ipv4.ip_forward is 1")
+}
+
+func (c *Checker) checkIOMMU() Check {
+	check := Check{
+		Name: "iommu",
+		Hint: "PCI passthrough needs an IOMMU: enable VT-d or AMD-Vi in the firmware and boot with intel_iommu=on (or amd_iommu=on) iommu=pt.",
+	}
+	missing := warn
+	if len(c.opts.PassthroughDevices) > 0 {
+		missing = fail
+	}
+	groups, err := os.ReadDir("/sys/kernel/iommu_groups")
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return missing(check, err.Error())
+	}
+	if len(groups) == 0 {
+		return missing(check, "no IOMMU groups")
+	}
+	return pass(check, fmt.Sprintf("%d IOMMU groups", len(groups)))
+}
+
+func (c *Checker) checkDiskSpace() Check {
+	check := Check{
+		Name: "disk_space",
+		Hint: fmt.Sprintf("Free up space under %s, or move VOLANT_RUNTIME_DIR to a larger filesystem.", c.opts.RuntimeDir),
+	}
+	// The directory is created on the first VM start; measure the
+	// filesystem that will hold it.
+	dir := c.opts.RuntimeDir
+	for {
+		if _, err := os.Stat(dir); err == nil || dir == filepath.Dir(dir) {
+			break
+		}
+		dir = filepath.Dir(dir)
+	}
+	var stat unix.Statfs_t
+	if err := unix.Statfs(dir, &stat); err != nil {
+		return fail(check, fmt.Sprintf("statfs %s: %v", dir, err))
+	}
+	free := stat.Bavail * uint64(stat.Bsize)
+	total := stat.Blocks * uint64(stat.Bsize)
+	detail := fmt.Sprintf("%s free of %s in %s", formatBytes(free), formatBytes(total), c.opts.RuntimeDir)
+	switch {
+	case free < minFreeBytes:
+		return fail(check, detail)
+	case free < warnFreeBytes:
+		return warn(check, detail)
+	}
+	return pass(check, detail)
+}
@@ -0,0 +1,22 @@
+// Copyright (c) 2025 HYPR. PTE. LTD.
+//
+// Business Source License 1.1
+// See LICENSE file in the project root for details.
+
+//go:build !linux
+
+package doctor
+
+import (
+	"context"
+	"runtime"
+)
+
+func (c *Checker) checks(ctx context.Context) []Check {
+	return []Check{{
+		Name:   "kvm",
+		Status: StatusFail,
+		Detail: "not supported on " + runtime.GOOS,
+		Hint:   "volantd runs VMs on Linux hosts with KVM only.",
+	}}
+}
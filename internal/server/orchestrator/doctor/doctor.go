@@ -0,0 +1,115 @@
+// Copyright (c) 2025 HYPR. PTE. LTD.
+//
+// Business Source License 1.1
+// See LICENSE file in the project root for details.
+
+// Package doctor checks the host prerequisites volantd needs to run VMs,
+// explaining how to fix each one that is missing.
+package doctor
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Status is the outcome of a check. Warn marks a prerequisite only some
+// workloads need.
+type Status string
+
+const (
+	StatusPass Status = "pass"
+	StatusWarn Status = "warn"
+	StatusFail Status = "fail"
+)
+
+// Check is the result of one prerequisite check. Hint says how to fix it
+// and is empty when the check passed.
+type Check struct {
+	Name   string `json:"name"`
+	Status Status `json:"status"`
+	Detail string `json:"detail,omitempty"`
+	Hint   string `json:"hint,omitempty"`
+}
+
+// Report holds the results of one run. Healthy is false when any check
+// failed.
+type Report struct {
+	CheckedAt time.Time `json:"checked_at"`
+	Healthy   bool      `json:"healthy"`
+	Checks    []Check   `json:"checks"`
+}
+
+// Options describe the host setup volantd was configured with.
+type Options struct {
+	HypervisorBinary string
+	BridgeName       string
+	// HostIP is expected on the bridge.
+	HostIP string
+	// RuntimeDir holds VM disks and sockets; its free space is checked.
+	RuntimeDir string
+	// PassthroughDevices makes a missing IOMMU a failure rather than a
+	// warning.
+	PassthroughDevices []string
+}
+
+// Free space thresholds of the runtime directory.
+const (
+	minFreeBytes  = 1 << 30
+	warnFreeBytes = 5 << 30
+)
+
+// hypervisorTimeout bounds `cloud-hypervisor --version`.
+const hypervisorTimeout = 5 * time.Second
+
+// Checker runs the checks against the live host on every call.
+type Checker struct {
+	opts Options
+}
+
+// New constructs a checker.
+func New(opts Options) *Checker {
+	return &Checker{opts: opts}
+}
+
+// Run checks every prerequisite.
+func (c *Checker) Run(ctx context.Context) *Report {
+	report := &Report{CheckedAt: time.Now().UTC(), Healthy: true, Checks: c.checks(ctx)}
+	for i, check := range report.Checks {
+		if check.Status == StatusPass {
+			report.Checks[i].Hint = ""
+		}
+		if check.Status == StatusFail {
+			report.Healthy = false
+		}
+	}
+	return report
+}
+
+func pass(check Check, detail string) Check {
+	check.Status, check.Detail = StatusPass, detail
+	return check
+}
+
+func warn(check Check, detail string) Check {
+	check.Status, check.Detail = StatusWarn, detail
+	return check
+}
+
+func fail(check Check, detail string) Check {
+	check.Status, check.Detail = StatusFail, detail
+	return check
+}
+
+func formatBytes(n uint64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := uint64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
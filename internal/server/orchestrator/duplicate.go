@@ -0,0 +1,291 @@
+// Copyright (c) 2025 HYPR. PTE. LTD.
+//
+// Business Source License 1.1
+// See LICENSE file in the project root for details.
+
+package orchestrator
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/volantvm/volant/internal/pluginspec"
+	"github.com/volantvm/volant/internal/server/db"
+	"github.com/volantvm/volant/internal/server/orchestrator/runtime"
+	"github.com/volantvm/volant/internal/server/orchestrator/vmconfig"
+)
+
+// DuplicateVMRequest names the copy of a VM and chooses how much of the
+// source's disk state it takes.
+type DuplicateVMRequest struct {
+	Name string
+	// ConfigOnly copies the config alone: the copy boots a fresh root
+	// filesystem from its manifest. Sources with writable data disks cannot
+	// be copied this way, since the copy would write to the same files.
+	ConfigOnly bool
+	// Scratch also gives a config-only copy the source's persistent scratch
+	// disk; a full duplicate always takes it.
+	Scratch bool
+}
+
+// DuplicateVM creates a new VM from source's current config and, unless
+// req.ConfigOnly is set, its disk state. The copy gets its own IP, MAC, vsock
+// CID and hostname; host port mappings and statically addressed extra NICs
+// are dropped so it never takes over traffic meant for the source. A running
+// source is paused briefly while its disks are copied.
+func (e *engine) DuplicateVM(ctx context.Context, source string, req DuplicateVMRequest) (*db.VM, error) {
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return nil, fmt.Errorf("orchestrator: duplicate name required")
+	}
+	if name == source {
+		return nil, fmt.Errorf("orchestrator: duplicate of %s needs a different name", source)
+	}
+	vm, err := e.GetVM(ctx, source)
+	if err != nil {
+		return nil, err
+	}
+	if vm == nil {
+		return nil, fmt.Errorf("%w: %s", ErrVMNotFound, source)
+	}
+	if existing, err := e.GetVM(ctx, name); err != nil {
+		return nil, err
+	} else if existing != nil {
+		return nil, fmt.Errorf("%w: %s", ErrVMExists, name)
+	}
+	current, err := e.GetVMConfig(ctx, source)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg := current.Config.Clone()
+	if devCfg := effectiveDevices(cfg); devCfg != nil && len(devCfg.PCIPassthrough) > 0 {
+		return nil, fmt.Errorf("%w: vm %s passes through PCI devices", ErrVMNotDuplicable, source)
+	}
+	if req.ConfigOnly {
+		if disk := writableDisk(cfg.Manifest); disk != "" {
+			return nil, fmt.Errorf("%w: vm %s has writable disk %s; copy it with its disks instead", ErrVMNotDuplicable, source, disk)
+		}
+	}
+	reidentify(&cfg, name)
+
+	dir := cloneDir(e.runtimeDir, name)
+	layers := []func(runtimeDir, name string) string{overlayDiskPath, rootFSClonePath, scratchDiskPath}
+	cloned := &runtime.ClonedDisks{Disks: map[string]string{}}
+	switch {
+	case !req.ConfigOnly:
+		cloned, err = e.cloneDisks(ctx, source, cfg.Manifest, dir)
+	case req.Scratch:
+		layers = layers[2:]
+		cloned, err = e.cloneDisks(ctx, source, cfg.Manifest, dir)
+	default:
+		layers = nil
+	}
+	if err != nil {
+		_ = os.RemoveAll(dir)
+		return nil, err
+	}
+	if err := e.cloneLayers(source, name, cloned, layers); err != nil {
+		_ = os.RemoveAll(dir)
+		return nil, err
+	}
+	if req.ConfigOnly {
+		// A running source's runtime copied its root filesystem too; the
+		// copy boots its own.
+		_ = os.RemoveAll(dir)
+		cloned = &runtime.ClonedDisks{}
+	}
+	if cloned.RootFS != "" {
+		cfg.RootFS = &pluginspec.RootFS{URL: cloned.RootFS}
+		if cfg.Manifest != nil {
+			cfg.RootFS.Format = cfg.Manifest.RootFS.Format
+		}
+	}
+	if cfg.Manifest != nil {
+		for i, disk := range cfg.Manifest.Disks {
+			if copyPath, ok := cloned.Disks[strings.TrimSpace(disk.Source)]; ok {
+				cfg.Manifest.Disks[i].Source = copyPath
+				cfg.Manifest.Disks[i].Checksum = ""
+			}
+		}
+	}
+
+	created, err := e.CreateVM(ctx, CreateVMRequest{
+		Name:              name,
+		Plugin:            cfg.Plugin,
+		Runtime:           cfg.Runtime,
+		CPUCores:          cfg.Resources.CPUCores,
+		MemoryMB:          cfg.Resources.MemoryMB,
+		KernelCmdlineHint: cfg.KernelCmdline,
+		Manifest:          cfg.Manifest,
+		APIHost:           cfg.API.Host,
+		APIPort:           cfg.API.Port,
+		Config:            &cfg,
+		Labels:            duplicateLabels(vm.Labels),
+	})
+	if err != nil {
+		_ = os.RemoveAll(dir)
+		_ = os.Remove(overlayDiskPath(e.runtimeDir, name))
+		_ = os.Remove(rootFSClonePath(e.runtimeDir, name))
+		_ = os.Remove(scratchDiskPath(e.runtimeDir, name))
+		return nil, err
+	}
+	e.logger.Info("vm duplicated", "source", source, "vm", created.Name, "ip", created.IPAddress, "config_only", req.ConfigOnly)
+	return created, nil
+}
+
+// cloneDisks copies the source's disk state into dir. Running VMs are copied
+// through the runtime so the image is consistent; a stopped VM re-stages its
+// root filesystem on boot, so only its writable disks are copied.
+func (e *engine) cloneDisks(ctx context.Context, source string, manifest *pluginspec.Manifest, dir string) (*runtime.ClonedDisks, error) {
+	e.mu.Lock()
+	handle, running := e.instances[source]
+	e.mu.Unlock()
+	if running {
+		cloner, ok := handle.instance.(runtime.DiskCloner)
+		if !ok {
+			return nil, fmt.Errorf("orchestrator: runtime for vm %s cannot copy disks of a running guest", source)
+		}
+		cloned, err := cloner.CloneDisks(ctx, dir)
+		if err != nil {
+			return nil, fmt.Errorf("orchestrator: copy disks of vm %s: %w", source, err)
+		}
+		return cloned, nil
+	}
+
+	cloned := &runtime.ClonedDisks{Disks: map[string]string{}}
+	for idx, disk := range buildAdditionalDisks(manifest) {
+		if disk.Readonly {
+			continue
+		}
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return nil, fmt.Errorf("orchestrator: create clone dir: %w", err)
+		}
+		dst := filepath.Join(dir, fmt.Sprintf("%d-%s", idx, filepath.Base(disk.Path)))
+		if err := runtime.CopyDisk(disk.Path, dst); err != nil {
+			return nil, fmt.Errorf("orchestrator: copy disk %s of vm %s: %w", disk.Name, source, err)
+		}
+		cloned.Disks[disk.Path] = dst
+	}
+	return cloned, nil
+}
+
+// cloneLayers gives the copy the source's disks that live under the runtime
+// directory: the writable layer over a shared base image (the overlay disk of
+// a read-only root or the clone of a qcow2 root), so it starts from the same
+// changes, and the scratch disk.
+func (e *engine) cloneLayers(source, name string, cloned *runtime.ClonedDisks, layers []func(runtimeDir, name string) string) error {
+	for _, layerPath := range layers {
+		src := layerPath(e.runtimeDir, source)
+		dst := layerPath(e.runtimeDir, name)
+		copyPath, copied := cloned.Disks[src]
+		if !copied {
+			if _, err := os.Stat(src); err != nil {
+				continue
+			}
+		}
+		if err := os.MkdirAll(filepath.Dir(dst), 0o755); err != nil {
+			return fmt.Errorf("orchestrator: create disk dir: %w", err)
+		}
+		if copied {
+			delete(cloned.Disks, src)
+			if err := os.Rename(copyPath, dst); err != nil {
+				return fmt.Errorf("orchestrator: move disk copy of vm %s: %w", source, err)
+			}
+			continue
+		}
+		if err := runtime.CopyDisk(src, dst); err != nil {
+			return fmt.Errorf("orchestrator: copy %s of vm %s: %w", filepath.Base(filepath.Dir(src)), source, err)
+		}
+	}
+	return nil
+}
+
+// reidentify strips the parts of a copied config that would make the clone
+// collide with its source on the host or the network.
+func reidentify(cfg *vmconfig.Config, name string) {
+	cfg.Ports = nil
+	for _, netCfg := range []*pluginspec.NetworkConfig{cfg.Network, manifestNetwork(cfg.Manifest)} {
+		if netCfg == nil {
+			continue
+		}
+		kept := netCfg.Interfaces[:0]
+		for _, iface := range netCfg.Interfaces {
+			if iface.Mode != pluginspec.NetworkModeDHCP && strings.TrimSpace(iface.IPAddress) != "" {
+				continue
+			}
+			iface.MAC = ""
+			kept = append(kept, iface)
+		}
+		netCfg.Interfaces = kept
+	}
+	if cfg.CloudInit != nil {
+		cfg.CloudInit.MetaData.Content = rehostMetaData(cfg.CloudInit.MetaData.Content, name)
+	}
+	if cfg.Manifest != nil && cfg.Manifest.CloudInit != nil {
+		cfg.Manifest.CloudInit.MetaData.Content = rehostMetaData(cfg.Manifest.CloudInit.MetaData.Content, name)
+	}
+}
+
+// rehostMetaData rewrites instance-id and hostname in user-supplied
+// cloud-init meta-data so the guest picks up its new identity on boot. Empty
+// meta-data is left alone; the seed builder then generates it from the VM
+// name.
+func rehostMetaData(content, name string) string {
+	if strings.TrimSpace(content) == "" {
+		return content
+	}
+	lines := []string{
+		"instance-id: volant-" + name,
+		"local-hostname: " + sanitizeHostname(name),
+	}
+	for _, line := range strings.Split(content, "\n") {
+		key := strings.TrimSpace(strings.SplitN(line, ":", 2)[0])
+		switch key {
+		case "instance-id", "local-hostname", "hostname":
+			continue
+		}
+		lines = append(lines, line)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// writableDisk returns the name of the first data disk the guest may write
+// to, or "" when there is none.
+func writableDisk(manifest *pluginspec.Manifest) string {
+	for _, disk := range buildAdditionalDisks(manifest) {
+		if !disk.Readonly {
+			if disk.Name != "" {
+				return disk.Name
+			}
+			return disk.Path
+		}
+	}
+	return ""
+}
+
+func manifestNetwork(manifest *pluginspec.Manifest) *pluginspec.NetworkConfig {
+	if manifest == nil {
+		return nil
+	}
+	return manifest.Network
+}
+
+func effectiveDevices(cfg vmconfig.Config) *pluginspec.DeviceConfig {
+	if cfg.Devices != nil {
+		return cfg.Devices
+	}
+	if cfg.Manifest != nil {
+		return cfg.Manifest.Devices
+	}
+	return nil
+}
+
+// cloneDir holds the disk copies a duplicated VM boots from; it is removed
+// with the VM.
+func cloneDir(runtimeDir, name string) string {
+	return filepath.Join(runtimeDir, "clones", name)
+}
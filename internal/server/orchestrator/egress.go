@@ -0,0 +1,81 @@
+// Copyright (c) 2025 HYPR. PTE. LTD.
+//
+// Business Source License 1.1
+// See LICENSE file in the project root for details.
+
+package orchestrator
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/volantvm/volant/internal/pluginspec"
+	"github.com/volantvm/volant/internal/server/orchestrator/network"
+)
+
+// applyEgressPolicy installs the VM's egress policy on its tap before the
+// guest boots. Policies fail closed: a policy that cannot be installed stops
+// the launch rather than leaving the workload unrestricted.
+func (e *engine) applyEgressPolicy(ctx context.Context, vmName, tap string, netCfg *pluginspec.NetworkConfig) error {
+	if tap == "" || netCfg == nil || netCfg.Egress == nil {
+		return nil
+	}
+	firewall, ok := e.network.(network.EgressFirewall)
+	if !ok {
+		return fmt.Errorf("orchestrator: vm %s declares an egress policy but the network manager cannot enforce it", vmName)
+	}
+	rules, err := resolveEgressRules(ctx, netCfg.Egress.Rules)
+	if err != nil {
+		return fmt.Errorf("orchestrator: vm %s egress policy: %w", vmName, err)
+	}
+	defaultDeny := strings.EqualFold(strings.TrimSpace(netCfg.Egress.Default), pluginspec.EgressDeny)
+	return firewall.ApplyEgressPolicy(ctx, tap, rules, defaultDeny)
+}
+
+// resolveEgressRules turns CIDRs and DNS names into concrete networks. Names
+// are resolved once, when the policy is installed.
+func resolveEgressRules(ctx context.Context, specs []pluginspec.EgressRule) ([]network.EgressRule, error) {
+	rules := make([]network.EgressRule, 0, len(specs))
+	for _, spec := range specs {
+		rule := network.EgressRule{
+			Allow: strings.EqualFold(strings.TrimSpace(spec.Action), pluginspec.EgressAllow),
+			Ports: spec.Ports,
+			Proto: strings.ToLower(strings.TrimSpace(spec.Proto)),
+		}
+		for _, raw := range spec.CIDRs {
+			raw = strings.TrimSpace(raw)
+			if ip := net.ParseIP(raw); ip != nil {
+				rule.Networks = append(rule.Networks, hostNetwork(ip))
+				continue
+			}
+			_, cidr, err := net.ParseCIDR(raw)
+			if err != nil {
+				return nil, fmt.Errorf("invalid cidr %q", raw)
+			}
+			rule.Networks = append(rule.Networks, cidr)
+		}
+		for _, host := range spec.Hosts {
+			addrs, err := net.DefaultResolver.LookupIPAddr(ctx, strings.TrimSpace(host))
+			if err != nil {
+				return nil, fmt.Errorf("resolve %s: %w", host, err)
+			}
+			for _, addr := range addrs {
+				rule.Networks = append(rule.Networks, hostNetwork(addr.IP))
+			}
+		}
+		if len(rule.Networks) == 0 && (len(spec.CIDRs) > 0 || len(spec.Hosts) > 0) {
+			return nil, fmt.Errorf("no addresses for %s", strings.Join(spec.Hosts, ", "))
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+func hostNetwork(ip net.IP) *net.IPNet {
+	if v4 := ip.To4(); v4 != nil {
+		return &net.IPNet{IP: v4, Mask: net.CIDRMask(32, 32)}
+	}
+	return &net.IPNet{IP: ip, Mask: net.CIDRMask(128, 128)}
+}
@@ -0,0 +1,123 @@
+// Copyright (c) 2025 HYPR. PTE. LTD.
+//
+// Business Source License 1.1
+// See LICENSE file in the project root for details.
+
+package orchestrator
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/volantvm/volant/internal/server/db"
+	orchestratorevents "github.com/volantvm/volant/internal/server/orchestrator/events"
+)
+
+const (
+	// vmEventRetention is how long recorded VM events are kept.
+	vmEventRetention = 7 * 24 * time.Hour
+	// vmEventPruneInterval is how often expired VM events are removed.
+	vmEventPruneInterval = time.Hour
+)
+
+// startEventHistory subscribes to VM events and records them until ctx is
+// done. It subscribes before returning so events published right after Start
+// are not missed.
+func (e *engine) startEventHistory(ctx context.Context) {
+	if e.bus == nil {
+		return
+	}
+	events := make(chan any, 256)
+	unsubscribe, err := e.bus.Subscribe(orchestratorevents.TopicVMEvents, events)
+	if err != nil {
+		e.logger.Error("subscribe to vm events", "error", err)
+		return
+	}
+	go func() {
+		defer unsubscribe()
+		e.pruneVMEvents(ctx)
+		ticker := time.NewTicker(vmEventPruneInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case event := <-events:
+				if vmEvent, ok := event.(orchestratorevents.VMEvent); ok {
+					e.recordVMEvent(ctx, vmEvent)
+				}
+			case <-ticker.C:
+				e.pruneVMEvents(ctx)
+			}
+		}
+	}()
+}
+
+// recordVMEvent stores a lifecycle event. Log lines are not recorded; the VM
+// log store keeps them.
+func (e *engine) recordVMEvent(ctx context.Context, event orchestratorevents.VMEvent) {
+	if event.Type == orchestratorevents.TypeVMLog || event.Name == "" {
+		return
+	}
+	message := event.Message
+	if message == "" && event.Type == orchestratorevents.TypeVMConfigUpdated {
+		message = fmt.Sprintf("config version %d", event.ConfigVersion)
+	}
+	createdAt := event.Timestamp
+	if createdAt.IsZero() {
+		createdAt = time.Now()
+	}
+	if _, err := e.store.Queries().VMEvents().Record(ctx, db.VMEvent{
+		VMName:    event.Name,
+		Type:      event.Type,
+		Status:    string(event.Status),
+		Message:   message,
+		Code:      event.Code,
+		Hint:      event.Hint,
+		CreatedAt: createdAt,
+	}); err != nil && ctx.Err() == nil {
+		e.logger.Error("record vm event", "type", event.Type, "vm", event.Name, "error", err)
+	}
+}
+
+func (e *engine) pruneVMEvents(ctx context.Context) {
+	n, err := e.store.Queries().VMEvents().DeleteBefore(ctx, time.Now().Add(-vmEventRetention))
+	if err != nil {
+		if ctx.Err() == nil {
+			e.logger.Error("prune vm events", "error", err)
+		}
+		return
+	}
+	if n > 0 {
+		e.logger.Debug("pruned vm events", "count", n)
+	}
+}
+
+// VMEvents returns the recorded lifecycle events of a VM matching query,
+// oldest first. History outlives the VM until retention removes it, so a
+// deleted VM is only unknown once none of its events are left.
+func (e *engine) VMEvents(ctx context.Context, name string, query db.VMEventQuery) ([]db.VMEvent, error) {
+	query.VMName = name
+	events, err := e.store.Queries().VMEvents().List(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	if len(events) > 0 {
+		return events, nil
+	}
+	vm, err := e.store.Queries().VirtualMachines().GetByName(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	if vm == nil {
+		recorded, err := e.store.Queries().VMEvents().List(ctx, db.VMEventQuery{VMName: name, Limit: 1})
+		if err != nil {
+			return nil, err
+		}
+		if len(recorded) == 0 {
+			return nil, fmt.Errorf("%w: %s", ErrVMNotFound, name)
+		}
+	}
+	return []db.VMEvent{}, nil
+}
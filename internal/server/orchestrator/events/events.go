@@ -5,7 +5,12 @@
 
 package events
 
-import "time"
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/volantvm/volant/internal/pluginspec"
+)
 
 // VMStatus represents the lifecycle stage for event payloads.
 type VMStatus string
@@ -29,8 +34,16 @@ type VMEvent struct {
 	PID       *int64    `json:"pid,omitempty"`
 	Timestamp time.Time `json:"timestamp"`
 	Message   string    `json:"message,omitempty"`
-	Stream    string    `json:"stream,omitempty"`
-	Line      string    `json:"line,omitempty"`
+	// Code and Hint classify a VM_FAILED launch failure and suggest a fix.
+	Code   string `json:"code,omitempty"`
+	Hint   string `json:"hint,omitempty"`
+	Stream string `json:"stream,omitempty"`
+	Line   string `json:"line,omitempty"`
+	// Hooks holds the outcome of the plugin's pre-stop hooks on VM_STOPPED
+	// and VM_DELETED events.
+	Hooks []pluginspec.HookResult `json:"hooks,omitempty"`
+	// ConfigVersion is the new configuration version on VM_CONFIG_UPDATED.
+	ConfigVersion int `json:"config_version,omitempty"`
 }
 
 const (
@@ -39,7 +52,14 @@ const (
 	TypeVMStopped = "VM_STOPPED"
 	TypeVMCrashed = "VM_CRASHED"
 	TypeVMDeleted = "VM_DELETED"
+	TypeVMFailed  = "VM_FAILED"
 	TypeVMLog     = "VM_LOG"
+	// TypeVMConfigUpdated reports a new configuration version; the VM's
+	// status is unchanged.
+	TypeVMConfigUpdated = "VM_CONFIG_UPDATED"
+	// TypeVMExpired reports that a VM's TTL ran out. It is followed by
+	// VM_STOPPED or VM_DELETED, depending on the TTL action.
+	TypeVMExpired = "VM_EXPIRED"
 )
 
 // Canonical stream identifiers used when VMEvent.Type is TypeVMLog.
@@ -53,3 +73,96 @@ const TopicVMEvents = "orchestrator.vm.events"
 
 // TopicVMLogs is the default event bus topic for VM log streaming.
 const TopicVMLogs = "orchestrator.vm.logs"
+
+// DeploymentEvent reports the outcome of a deployment reconcile.
+type DeploymentEvent struct {
+	Type            string    `json:"type"`
+	Name            string    `json:"name"`
+	DesiredReplicas int       `json:"desired_replicas"`
+	ReadyReplicas   int       `json:"ready_replicas"`
+	Timestamp       time.Time `json:"timestamp"`
+	Message         string    `json:"message,omitempty"`
+}
+
+const (
+	TypeDeploymentReconciled = "DEPLOYMENT_RECONCILED"
+	TypeDeploymentDeleted    = "DEPLOYMENT_DELETED"
+	// TypeDeploymentFailed reports that a deployment used up its reconcile
+	// retry budget; Message holds the last error.
+	TypeDeploymentFailed = "DEPLOYMENT_FAILED"
+)
+
+// TopicDeploymentEvents is the event bus topic for deployment reconciles.
+const TopicDeploymentEvents = "orchestrator.deployment.events"
+
+// PluginEvent reports a plugin being installed, removed, enabled, disabled or
+// upgraded to another installed version.
+type PluginEvent struct {
+	Type      string    `json:"type"`
+	Name      string    `json:"name"`
+	Version   string    `json:"version,omitempty"`
+	Enabled   bool      `json:"enabled"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+const (
+	TypePluginInstalled = "PLUGIN_INSTALLED"
+	TypePluginRemoved   = "PLUGIN_REMOVED"
+	TypePluginEnabled   = "PLUGIN_ENABLED"
+	TypePluginDisabled  = "PLUGIN_DISABLED"
+	TypePluginUpgraded  = "PLUGIN_UPGRADED"
+)
+
+// TopicPluginEvents is the event bus topic for plugin registry changes.
+const TopicPluginEvents = "plugins.events"
+
+// OperationEvent reports progress of an asynchronous operation.
+type OperationEvent struct {
+	Type      string    `json:"type"`
+	ID        string    `json:"id"`
+	Kind      string    `json:"kind"`
+	Target    string    `json:"target"`
+	Status    string    `json:"status"`
+	Timestamp time.Time `json:"timestamp"`
+	Message   string    `json:"message,omitempty"`
+	Code      string    `json:"code,omitempty"`
+	Hint      string    `json:"hint,omitempty"`
+}
+
+const (
+	TypeOperationStarted   = "OPERATION_STARTED"
+	TypeOperationSucceeded = "OPERATION_SUCCEEDED"
+	TypeOperationFailed    = "OPERATION_FAILED"
+)
+
+// TopicOperationEvents is the event bus topic for asynchronous operations.
+const TopicOperationEvents = "orchestrator.operation.events"
+
+// GCEvent reports host resources reclaimed by a garbage collection pass.
+type GCEvent struct {
+	Type       string    `json:"type"`
+	Taps       []string  `json:"taps,omitempty"`
+	Sockets    []string  `json:"sockets,omitempty"`
+	SeedImages []string  `json:"seed_images,omitempty"`
+	Timestamp  time.Time `json:"timestamp"`
+}
+
+const TypeGCCompleted = "GC_COMPLETED"
+
+// TopicSystemEvents is the event bus topic for host maintenance performed by
+// volantd itself.
+const TopicSystemEvents = "orchestrator.system.events"
+
+// AGUIEvent is an AG-UI protocol event emitted by automation inside a VM,
+// such as a browser agent, and forwarded to volantd by its guest.
+type AGUIEvent struct {
+	// Seq orders events across all VMs; streams use it as the SSE id.
+	Seq  uint64 `json:"seq"`
+	VM   string `json:"vm"`
+	Type string `json:"type"`
+	// Event is the AG-UI event object with vm added.
+	Event json.RawMessage `json:"event"`
+}
+
+// TopicAGUIEvents is the event bus topic for forwarded AG-UI events.
+const TopicAGUIEvents = "agui.events"
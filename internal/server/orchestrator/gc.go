@@ -0,0 +1,228 @@
+// Copyright (c) 2025 HYPR. PTE. LTD.
+//
+// Business Source License 1.1
+// See LICENSE file in the project root for details.
+
+package orchestrator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/volantvm/volant/internal/server/db"
+	orchestratorevents "github.com/volantvm/volant/internal/server/orchestrator/events"
+	"github.com/volantvm/volant/internal/server/orchestrator/network"
+)
+
+// GCInterval is how often volantd looks for taps, sockets and seed images
+// left behind by VMs it no longer runs.
+const GCInterval = 5 * time.Minute
+
+// GCReport describes one garbage collection pass.
+type GCReport struct {
+	StartedAt  time.Time
+	FinishedAt time.Time
+	// Taps, Sockets and SeedImages list what the pass removed.
+	Taps       []string
+	Sockets    []string
+	SeedImages []string
+	// Pending counts leftovers seen for the first time; they are removed on
+	// the next pass if nothing has claimed them by then.
+	Pending int
+	Errors  []string
+}
+
+// Reclaimed returns the number of resources the pass removed.
+func (r GCReport) Reclaimed() int {
+	return len(r.Taps) + len(r.Sockets) + len(r.SeedImages)
+}
+
+type gcKind int
+
+const (
+	gcTap gcKind = iota
+	gcSocket
+	gcSeedImage
+)
+
+type gcCandidate struct {
+	kind   gcKind
+	target string
+}
+
+// LastGC returns the most recent garbage collection pass, or nil before the
+// first one has run.
+func (e *engine) LastGC() *GCReport {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.lastGC == nil {
+		return nil
+	}
+	report := *e.lastGC
+	return &report
+}
+
+// runGarbageCollection periodically removes host resources that no VM owns
+// and VM logs past their retention.
+func (e *engine) runGarbageCollection(ctx context.Context) {
+	e.collectGarbage(ctx, time.Now())
+	ticker := time.NewTicker(GCInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			e.collectGarbage(ctx, now)
+			if e.logs != nil {
+				e.logs.Prune(now)
+			}
+		}
+	}
+}
+
+// collectGarbage removes taps on the bridge, API and serial sockets and
+// cloud-init seed images that belong to no running or launching VM. A
+// leftover is only removed once two consecutive passes have found it
+// unowned, so resources a launch creates before its process exists survive.
+func (e *engine) collectGarbage(ctx context.Context, now time.Time) GCReport {
+	report := GCReport{StartedAt: now.UTC()}
+
+	vms, err := e.store.Queries().VirtualMachines().List(ctx)
+	if err != nil {
+		e.logger.Warn("garbage collection: list vms", "error", err)
+		return report
+	}
+
+	owned := make(map[string]struct{})
+	keepTaps := make(map[string]struct{})
+	e.mu.Lock()
+	for name, handle := range e.instances {
+		owned[name] = struct{}{}
+		if handle.tapName != "" {
+			keepTaps[handle.tapName] = struct{}{}
+		}
+	}
+	for _, taps := range e.nics {
+		for _, tap := range taps {
+			keepTaps[tap] = struct{}{}
+		}
+	}
+	e.mu.Unlock()
+
+	launching := false
+	for _, vm := range vms {
+		if vm.Status == db.VMStatusPending || vm.Status == db.VMStatusStarting {
+			owned[vm.Name] = struct{}{}
+			launching = true
+		}
+	}
+
+	var candidates []gcCandidate
+	// Tap names cannot be traced back to a VM, so taps are left alone while
+	// any VM is launching.
+	if lister, ok := e.network.(network.TapLister); ok && !launching {
+		taps, err := lister.ListTaps(ctx)
+		if err != nil {
+			report.Errors = append(report.Errors, "list taps: "+err.Error())
+		}
+		for _, tap := range taps {
+			if _, ok := keepTaps[tap]; !ok {
+				candidates = append(candidates, gcCandidate{kind: gcTap, target: tap})
+			}
+		}
+	}
+	for _, pattern := range []string{"*.sock", "*.serial"} {
+		matches, _ := filepath.Glob(filepath.Join(e.runtimeDir, pattern))
+		for _, path := range matches {
+			name := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+			// virtiofsd sockets are named <vm>.virtiofs-<tag>.sock.
+			name, _, _ = strings.Cut(name, ".virtiofs-")
+			if _, ok := owned[name]; !ok {
+				candidates = append(candidates, gcCandidate{kind: gcSocket, target: path})
+			}
+		}
+	}
+	seeds, _ := filepath.Glob(filepath.Join(e.runtimeDir, "cloudinit", "*"+seedImageSuffix))
+	for _, path := range seeds {
+		name := strings.TrimSuffix(filepath.Base(path), seedImageSuffix)
+		if _, ok := owned[name]; !ok {
+			candidates = append(candidates, gcCandidate{kind: gcSeedImage, target: path})
+		}
+	}
+
+	e.mu.Lock()
+	previous := e.gcSuspects
+	e.mu.Unlock()
+
+	suspects := make(map[gcCandidate]struct{})
+	for _, candidate := range candidates {
+		if _, ok := previous[candidate]; !ok {
+			suspects[candidate] = struct{}{}
+			report.Pending++
+			continue
+		}
+		if err := e.reclaim(ctx, candidate); err != nil {
+			suspects[candidate] = struct{}{}
+			report.Errors = append(report.Errors, err.Error())
+			continue
+		}
+		switch candidate.kind {
+		case gcTap:
+			report.Taps = append(report.Taps, candidate.target)
+		case gcSocket:
+			report.Sockets = append(report.Sockets, candidate.target)
+		case gcSeedImage:
+			report.SeedImages = append(report.SeedImages, candidate.target)
+		}
+	}
+	report.FinishedAt = time.Now().UTC()
+
+	e.mu.Lock()
+	e.gcSuspects = suspects
+	e.lastGC = &report
+	e.mu.Unlock()
+
+	for _, msg := range report.Errors {
+		e.logger.Warn("garbage collection", "error", msg)
+	}
+	if report.Reclaimed() > 0 {
+		e.logger.Info("garbage collection reclaimed resources", "taps", report.Taps, "sockets", report.Sockets, "seed_images", report.SeedImages)
+		e.publishGCEvent(ctx, report)
+	}
+	return report
+}
+
+func (e *engine) reclaim(ctx context.Context, candidate gcCandidate) error {
+	if candidate.kind == gcTap {
+		if err := e.network.CleanupTap(ctx, candidate.target); err != nil {
+			return fmt.Errorf("remove tap %s: %w", candidate.target, err)
+		}
+		return nil
+	}
+	if err := os.Remove(candidate.target); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+func (e *engine) publishGCEvent(ctx context.Context, report GCReport) {
+	if e.bus == nil {
+		return
+	}
+	event := orchestratorevents.GCEvent{
+		Type:       orchestratorevents.TypeGCCompleted,
+		Taps:       report.Taps,
+		Sockets:    report.Sockets,
+		SeedImages: report.SeedImages,
+		Timestamp:  report.FinishedAt,
+	}
+	if err := e.bus.Publish(ctx, orchestratorevents.TopicSystemEvents, event); err != nil {
+		e.logger.Error("publish gc event", "error", err)
+	}
+}
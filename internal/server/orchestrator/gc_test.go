@@ -0,0 +1,137 @@
+// Copyright (c) 2025 HYPR. PTE. LTD.
+//
+// Business Source License 1.1
+// See LICENSE file in the project root for details.
+
+package orchestrator
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/volantvm/volant/internal/server/db"
+)
+
+func TestCollectGarbageReclaimsUnownedResources(t *testing.T) {
+	ctx := context.Background()
+	store := openTestStore(t)
+	defer func() { _ = store.Close(ctx) }()
+	subnet, host := testSubnet(t)
+	runtimeDir := t.TempDir()
+	netw := &recoveryNetworkManager{taps: []string{"tap-live", "tap-ghost"}}
+
+	created, err := New(Params{
+		Store:            store,
+		Logger:           slog.New(slog.NewTextHandler(io.Discard, nil)),
+		Subnet:           subnet,
+		HostIP:           host,
+		APIListenAddr:    "127.0.0.1:7777",
+		APIAdvertiseAddr: "127.0.0.1:7777",
+		RuntimeDir:       runtimeDir,
+		Launcher:         &testLauncher{},
+		Network:          netw,
+	})
+	if err != nil {
+		t.Fatalf("new engine: %v", err)
+	}
+	e := created.(*engine)
+	e.instances["live"] = processHandle{tapName: "tap-live"}
+
+	if err := os.MkdirAll(filepath.Join(runtimeDir, "cloudinit"), 0o755); err != nil {
+		t.Fatalf("mkdir seeds: %v", err)
+	}
+	files := []string{
+		"live.sock", "live.serial", "cloudinit/live" + seedImageSuffix,
+		"ghost.sock", "ghost.serial", "cloudinit/ghost" + seedImageSuffix,
+	}
+	for _, file := range files {
+		if err := os.WriteFile(filepath.Join(runtimeDir, file), nil, 0o644); err != nil {
+			t.Fatalf("write %s: %v", file, err)
+		}
+	}
+
+	// The first sighting only marks leftovers.
+	first := e.collectGarbage(ctx, time.Now())
+	if first.Reclaimed() != 0 || first.Pending != 4 {
+		t.Fatalf("expected 4 pending and nothing reclaimed, got %+v", first)
+	}
+
+	second := e.collectGarbage(ctx, time.Now())
+	sort.Strings(second.Sockets)
+	if len(second.Taps) != 1 || second.Taps[0] != "tap-ghost" {
+		t.Fatalf("expected ghost tap reclaimed, got %v", second.Taps)
+	}
+	if len(second.Sockets) != 2 || filepath.Base(second.Sockets[0]) != "ghost.serial" || filepath.Base(second.Sockets[1]) != "ghost.sock" {
+		t.Fatalf("expected ghost sockets reclaimed, got %v", second.Sockets)
+	}
+	if len(second.SeedImages) != 1 || filepath.Base(second.SeedImages[0]) != "ghost"+seedImageSuffix {
+		t.Fatalf("expected ghost seed reclaimed, got %v", second.SeedImages)
+	}
+	for _, file := range files[:3] {
+		if _, err := os.Stat(filepath.Join(runtimeDir, file)); err != nil {
+			t.Fatalf("expected %s kept: %v", file, err)
+		}
+	}
+	if last := e.LastGC(); last == nil || last.Reclaimed() != 4 {
+		t.Fatalf("expected last report to record the pass, got %+v", last)
+	}
+}
+
+func TestCollectGarbageSparesLaunchingVMs(t *testing.T) {
+	ctx := context.Background()
+	store := openTestStore(t)
+	defer func() { _ = store.Close(ctx) }()
+	subnet, host := testSubnet(t)
+	runtimeDir := t.TempDir()
+	netw := &recoveryNetworkManager{taps: []string{"tap-booting"}}
+
+	created, err := New(Params{
+		Store:            store,
+		Logger:           slog.New(slog.NewTextHandler(io.Discard, nil)),
+		Subnet:           subnet,
+		HostIP:           host,
+		APIListenAddr:    "127.0.0.1:7777",
+		APIAdvertiseAddr: "127.0.0.1:7777",
+		RuntimeDir:       runtimeDir,
+		Launcher:         &testLauncher{},
+		Network:          netw,
+	})
+	if err != nil {
+		t.Fatalf("new engine: %v", err)
+	}
+	e := created.(*engine)
+
+	if _, err := store.Queries().VirtualMachines().Create(ctx, &db.VM{
+		Name:       "booting",
+		Status:     db.VMStatusStarting,
+		Runtime:    "browser",
+		IPAddress:  "192.168.127.50",
+		MACAddress: "02:00:00:00:00:50",
+		CPUCores:   1,
+		MemoryMB:   512,
+	}); err != nil {
+		t.Fatalf("create vm: %v", err)
+	}
+	socket := filepath.Join(runtimeDir, "booting.sock")
+	if err := os.WriteFile(socket, nil, 0o644); err != nil {
+		t.Fatalf("write socket: %v", err)
+	}
+
+	for i := 0; i < 2; i++ {
+		if report := e.collectGarbage(ctx, time.Now()); report.Reclaimed() != 0 || report.Pending != 0 {
+			t.Fatalf("pass %d: expected launching vm untouched, got %+v", i, report)
+		}
+	}
+	if _, err := os.Stat(socket); err != nil {
+		t.Fatalf("expected socket kept: %v", err)
+	}
+	if len(netw.removed) != 0 {
+		t.Fatalf("expected taps kept while a vm is launching, got %v", netw.removed)
+	}
+}
@@ -0,0 +1,185 @@
+// Copyright (c) 2025 HYPR. PTE. LTD.
+//
+// Business Source License 1.1
+// See LICENSE file in the project root for details.
+
+package orchestrator
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mdlayher/vsock"
+
+	"github.com/volantvm/volant/internal/pluginspec"
+	"github.com/volantvm/volant/internal/server/db"
+	"github.com/volantvm/volant/internal/server/orchestrator/vmconfig"
+	"github.com/volantvm/volant/internal/server/tracing"
+)
+
+const (
+	preStopHookPath     = "/v1/hooks/pre-stop"
+	postUpgradeHookPath = "/v1/hooks/post-upgrade"
+	// preStopGrace covers the round trip on top of the hook timeouts.
+	preStopGrace = 5 * time.Second
+	// maxPreStopWait bounds how long a stop waits for hooks overall.
+	maxPreStopWait = 5 * time.Minute
+)
+
+// preStopHooks returns the pre-stop hooks declared by the VM's manifest.
+func preStopHooks(cfg vmconfig.Config) []pluginspec.Hook {
+	if cfg.Manifest == nil || cfg.Manifest.Hooks == nil {
+		return nil
+	}
+	return append([]pluginspec.Hook(nil), cfg.Manifest.Hooks.PreStop...)
+}
+
+// runPreStopHooks asks the guest agent to run hooks before the hypervisor is
+// terminated. Failures are reported in the results and never block the stop.
+func (e *engine) runPreStopHooks(ctx context.Context, vm db.VM, hooks []pluginspec.Hook) []pluginspec.HookResult {
+	if len(hooks) == 0 {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(ctx, hookBudget(hooks))
+	defer cancel()
+
+	results, err := callAgentHooks(ctx, vm, preStopHookPath, map[string]any{"hooks": hooks})
+	if err != nil {
+		e.logger.Warn("pre-stop hooks", "vm", vm.Name, "error", err)
+		results = make([]pluginspec.HookResult, 0, len(hooks))
+		for _, hook := range hooks {
+			results = append(results, pluginspec.HookResult{
+				Name:   hook.Name,
+				Status: pluginspec.HookStatusFailed,
+				Error:  err.Error(),
+			})
+		}
+		return results
+	}
+	for _, result := range results {
+		if result.Status != pluginspec.HookStatusOK {
+			e.logger.Warn("pre-stop hook failed", "vm", vm.Name, "hook", result.Name, "status", result.Status, "error", result.Error)
+		}
+	}
+	return results
+}
+
+// postUpgradeHooks returns the post-upgrade hooks due on a VM a plugin
+// upgrade rolled onto a new version.
+func postUpgradeHooks(cfg vmconfig.Config) []pluginspec.Hook {
+	if cfg.UpgradedFrom == "" || cfg.Manifest == nil || cfg.Manifest.Hooks == nil {
+		return nil
+	}
+	return append([]pluginspec.Hook(nil), cfg.Manifest.Hooks.PostUpgrade...)
+}
+
+// runPostUpgradeHooks asks the guest agent to run the post-upgrade hooks of a
+// VM rolled onto a new plugin version, then clears the VM's UpgradedFrom so
+// they run once. Failures are logged and never hold the VM back.
+func (e *engine) runPostUpgradeHooks(ctx context.Context, vm db.VM) {
+	versioned, err := e.GetVMConfig(ctx, vm.Name)
+	if err != nil || versioned == nil || versioned.Config.UpgradedFrom == "" {
+		return
+	}
+	cfg := versioned.Config
+	if hooks := postUpgradeHooks(cfg); len(hooks) > 0 {
+		hookCtx, cancel := context.WithTimeout(ctx, hookBudget(hooks))
+		results, err := callAgentHooks(hookCtx, vm, postUpgradeHookPath, map[string]any{
+			"hooks":        hooks,
+			"from_version": cfg.UpgradedFrom,
+			"to_version":   cfg.Manifest.Version,
+		})
+		cancel()
+		if err != nil {
+			e.logger.Warn("post-upgrade hooks", "vm", vm.Name, "error", err)
+		}
+		for _, result := range results {
+			if result.Status != pluginspec.HookStatusOK {
+				e.logger.Warn("post-upgrade hook failed", "vm", vm.Name, "hook", result.Name, "status", result.Status, "error", result.Error)
+			}
+		}
+	}
+	if _, err := e.writeVMConfig(ctx, vm.Name, func(current vmconfig.Config) (vmconfig.Config, error) {
+		current.UpgradedFrom = ""
+		return current, nil
+	}); err != nil {
+		e.logger.Warn("clear plugin upgrade marker", "vm", vm.Name, "error", err)
+		return
+	}
+	e.logger.Info("plugin upgrade applied", "vm", vm.Name, "from", cfg.UpgradedFrom, "to", cfg.Manifest.Version)
+}
+
+// hookBudget bounds a hook run by the hook timeouts plus the round trip.
+func hookBudget(hooks []pluginspec.Hook) time.Duration {
+	budget := preStopGrace
+	for _, hook := range hooks {
+		budget += time.Duration(hook.Timeout()) * time.Millisecond
+	}
+	if budget > maxPreStopWait {
+		budget = maxPreStopWait
+	}
+	return budget
+}
+
+// callAgentHooks posts payload to the guest agent's hook endpoint at path and
+// returns the per-hook results.
+func callAgentHooks(ctx context.Context, vm db.VM, path string, payload map[string]any) ([]pluginspec.HookResult, error) {
+	transport, base, err := agentTransport(vm)
+	if err != nil {
+		return nil, err
+	}
+	defer transport.CloseIdleConnections()
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, base+path, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := (&http.Client{Transport: tracing.Transport(transport)}).Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("agent returned status %d", resp.StatusCode)
+	}
+	var decoded struct {
+		Results []pluginspec.HookResult `json:"results"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&decoded); err != nil {
+		return nil, fmt.Errorf("decode hook results: %w", err)
+	}
+	return decoded.Results, nil
+}
+
+// agentTransport returns a transport reaching the guest agent of vm and the
+// base URL to address it with.
+func agentTransport(vm db.VM) (*http.Transport, string, error) {
+	transport := &http.Transport{}
+	host := strings.TrimSpace(vm.IPAddress)
+	switch {
+	case host != "":
+	case vm.VsockCID != 0:
+		// vsock-only guests: the URL host is a placeholder, every connection
+		// goes to the agent's vsock port.
+		host = "agent"
+		transport.DialContext = func(context.Context, string, string) (net.Conn, error) {
+			return vsock.Dial(vm.VsockCID, AgentPort, nil)
+		}
+	default:
+		return nil, "", fmt.Errorf("guest agent unreachable: vm has no ip or vsock cid")
+	}
+	return transport, fmt.Sprintf("http://%s", net.JoinHostPort(host, strconv.Itoa(AgentPort))), nil
+}
@@ -0,0 +1,55 @@
+// Copyright (c) 2025 HYPR. PTE. LTD.
+//
+// Business Source License 1.1
+// See LICENSE file in the project root for details.
+
+package orchestrator
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/volantvm/volant/internal/pluginspec"
+	"github.com/volantvm/volant/internal/server/orchestrator/hostfeatures"
+)
+
+// HostFeatureError reports host kernel features a plugin requires but this
+// host lacks. It wraps ErrHostFeaturesMissing.
+type HostFeatureError struct {
+	Plugin  string
+	Missing []hostfeatures.Feature
+}
+
+func (e *HostFeatureError) Error() string {
+	parts := make([]string, 0, len(e.Missing))
+	for _, feature := range e.Missing {
+		if feature.Detail != "" {
+			parts = append(parts, fmt.Sprintf("%s (%s)", feature.Name, feature.Detail))
+		} else {
+			parts = append(parts, feature.Name)
+		}
+	}
+	return fmt.Sprintf("plugin %s requires host features this host lacks: %s", e.Plugin, strings.Join(parts, ", "))
+}
+
+func (e *HostFeatureError) Unwrap() error { return ErrHostFeaturesMissing }
+
+// HostFeatures returns the features probed at startup, or nil when the
+// engine was started without probing.
+func (e *engine) HostFeatures() *hostfeatures.Report {
+	return e.hostFeatures
+}
+
+// CheckHostFeatures fails with a *HostFeatureError when the manifest requires
+// features the host lacks.
+func (e *engine) CheckHostFeatures(manifest *pluginspec.Manifest) error {
+	required := manifest.RequiredHostFeatures()
+	if len(required) == 0 {
+		return nil
+	}
+	missing := e.hostFeatures.Missing(required)
+	if len(missing) == 0 {
+		return nil
+	}
+	return &HostFeatureError{Plugin: manifest.Name, Missing: missing}
+}
@@ -0,0 +1,83 @@
+// Copyright (c) 2025 HYPR. PTE. LTD.
+//
+// Business Source License 1.1
+// See LICENSE file in the project root for details.
+
+// Package hostfeatures probes the host kernel for the features plugins may
+// require, so missing support is reported before a VM is launched.
+package hostfeatures
+
+import (
+	"time"
+
+	"github.com/volantvm/volant/internal/pluginspec"
+)
+
+// Feature is the probe result for one host feature.
+type Feature struct {
+	Name      string `json:"name"`
+	Available bool   `json:"available"`
+	Detail    string `json:"detail,omitempty"`
+	Hint      string `json:"hint,omitempty"`
+}
+
+// Report holds the features probed at startup.
+type Report struct {
+	ProbedAt time.Time `json:"probed_at"`
+	Features []Feature `json:"features"`
+}
+
+var remediationHints = map[string]string{
+	pluginspec.HostFeatureVhostVsock: "Load the vhost_vsock module (modprobe vhost_vsock) so /dev/vhost-vsock exists.",
+	pluginspec.HostFeatureTun:        "Load the tun module (modprobe tun) so /dev/net/tun exists.",
+	pluginspec.HostFeatureIOUring:    "Run a 5.1+ kernel with io_uring enabled (sysctl kernel.io_uring_disabled=0).",
+	pluginspec.HostFeatureVirtiofs:   "Install virtiofsd and make sure it is on volantd's PATH.",
+	pluginspec.HostFeatureHugepages:  "Reserve huge pages, e.g. sysctl vm.nr_hugepages=1024.",
+}
+
+// Probe inspects the running host once for every known feature.
+func Probe() *Report {
+	report := &Report{ProbedAt: time.Now().UTC()}
+	for _, name := range pluginspec.KnownHostFeatures {
+		available, detail := probe(name)
+		feature := Feature{Name: name, Available: available, Detail: detail}
+		if !available {
+			feature.Hint = remediationHints[name]
+		}
+		report.Features = append(report.Features, feature)
+	}
+	return report
+}
+
+// Lookup returns the probe result for name.
+func (r *Report) Lookup(name string) (Feature, bool) {
+	if r == nil {
+		return Feature{}, false
+	}
+	for _, feature := range r.Features {
+		if feature.Name == name {
+			return feature, true
+		}
+	}
+	return Feature{}, false
+}
+
+// Missing returns the required features the host lacks, in the order given.
+// A nil report has not been probed and reports nothing missing.
+func (r *Report) Missing(required []string) []Feature {
+	if r == nil {
+		return nil
+	}
+	var missing []Feature
+	for _, name := range required {
+		feature, ok := r.Lookup(name)
+		if !ok {
+			missing = append(missing, Feature{Name: name, Detail: "not probed by this volantd"})
+			continue
+		}
+		if !feature.Available {
+			missing = append(missing, feature)
+		}
+	}
+	return missing
+}
@@ -0,0 +1,112 @@
+// Copyright (c) 2025 HYPR. PTE. LTD.
+//
+// Business Source License 1.1
+// See LICENSE file in the project root for details.
+
+//go:build linux
+// +build linux
+
+package hostfeatures
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+
+	"golang.org/x/sys/unix"
+
+	"github.com/volantvm/volant/internal/pluginspec"
+)
+
+// virtiofsdPaths are checked when virtiofsd is not on PATH; distributions
+// install it outside the default search path.
+var virtiofsdPaths = []string{
+	"/usr/libexec/virtiofsd",
+	"/usr/lib/qemu/virtiofsd",
+	"/usr/lib/virtiofsd",
+}
+
+func probe(name string) (bool, string) {
+	switch name {
+	case pluginspec.HostFeatureVhostVsock:
+		return probeCharDevice("/dev/vhost-vsock")
+	case pluginspec.HostFeatureTun:
+		return probeCharDevice("/dev/net/tun")
+	case pluginspec.HostFeatureIOUring:
+		return probeIOUring()
+	case pluginspec.HostFeatureVirtiofs:
+		return probeVirtiofsd()
+	case pluginspec.HostFeatureHugepages:
+		return probeHugepages()
+	}
+	return false, "unknown feature"
+}
+
+func probeCharDevice(path string) (bool, string) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return false, err.Error()
+	}
+	if info.Mode()&os.ModeCharDevice == 0 {
+		return false, path + " is not a character device"
+	}
+	return true, path
+}
+
+// probeIOUring calls io_uring_setup with invalid arguments: a kernel with
+// io_uring rejects them with EFAULT or EINVAL, one without returns ENOSYS and
+// one with it disabled by sysctl returns EPERM.
+func probeIOUring() (bool, string) {
+	_, _, errno := unix.Syscall(unix.SYS_IO_URING_SETUP, 0, 0, 0)
+	switch {
+	case errors.Is(errno, unix.EFAULT), errors.Is(errno, unix.EINVAL):
+		return true, "io_uring_setup available"
+	case errors.Is(errno, unix.ENOSYS):
+		return false, "kernel built without io_uring"
+	case errors.Is(errno, unix.EPERM):
+		return false, "io_uring disabled by kernel.io_uring_disabled"
+	default:
+		return false, fmt.Sprintf("io_uring_setup: %v", errno)
+	}
+}
+
+func probeVirtiofsd() (bool, string) {
+	if path, err := exec.LookPath("virtiofsd"); err == nil {
+		return true, path
+	}
+	for _, path := range virtiofsdPaths {
+		if info, err := os.Stat(path); err == nil && info.Mode().IsRegular() && info.Mode()&0o111 != 0 {
+			return true, path
+		}
+	}
+	return false, "virtiofsd not found"
+}
+
+func probeHugepages() (bool, string) {
+	f, err := os.Open("/proc/meminfo")
+	if err != nil {
+		return false, err.Error()
+	}
+	defer f.Close()
+
+	values := make(map[string]string)
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		key, value, ok := strings.Cut(scanner.Text(), ":")
+		if ok && (strings.HasPrefix(key, "HugePages_") || key == "Hugepagesize") {
+			values[key] = strings.TrimSpace(value)
+		}
+	}
+	total, err := strconv.Atoi(values["HugePages_Total"])
+	if err != nil {
+		return false, "kernel built without hugetlbfs"
+	}
+	if total == 0 {
+		return false, "no huge pages reserved"
+	}
+	return true, fmt.Sprintf("%s of %d free (%s pages)", values["HugePages_Free"], total, values["Hugepagesize"])
+}
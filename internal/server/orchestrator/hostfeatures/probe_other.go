@@ -0,0 +1,14 @@
+// Copyright (c) 2025 HYPR. PTE. LTD.
+//
+// Business Source License 1.1
+// See LICENSE file in the project root for details.
+
+//go:build !linux
+
+package hostfeatures
+
+import "runtime"
+
+func probe(name string) (bool, string) {
+	return false, "not supported on " + runtime.GOOS
+}
@@ -0,0 +1,300 @@
+// Copyright (c) 2025 HYPR. PTE. LTD.
+//
+// Business Source License 1.1
+// See LICENSE file in the project root for details.
+
+package orchestrator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/volantvm/volant/internal/server/db"
+)
+
+// HostMaintenanceWindow names the maintenance window host maintenance mode
+// keeps open. It covers every target, so control loops hold their actions
+// until the host leaves maintenance.
+const HostMaintenanceWindow = "host"
+
+// MaintenanceSourceHost records the VMs host maintenance stopped; exiting
+// maintenance replays their restart.
+const MaintenanceSourceHost = "host"
+
+// DefaultMaintenanceConcurrency is how many VMs host maintenance stops or
+// restarts at once when the request does not say.
+const DefaultMaintenanceConcurrency = 4
+
+// hostMaintenanceSpan stands in for "until exited": windows need an end.
+const hostMaintenanceSpan = 100 * 365 * 24 * time.Hour
+
+// ErrHostMaintenance indicates the host is in maintenance mode and refuses
+// new VMs.
+var ErrHostMaintenance = errors.New("orchestrator: host in maintenance")
+
+// HostMaintenanceRequest engages host maintenance mode.
+type HostMaintenanceRequest struct {
+	Reason string
+	// Concurrency caps how many VMs are stopped at once; zero means
+	// DefaultMaintenanceConcurrency.
+	Concurrency int
+}
+
+// HostMaintenance reports host maintenance mode and the VMs it stopped.
+type HostMaintenance struct {
+	Engaged bool       `json:"engaged"`
+	Reason  string     `json:"reason,omitempty"`
+	Since   *time.Time `json:"since,omitempty"`
+	// EndedAt is set once maintenance was exited.
+	EndedAt *time.Time          `json:"ended_at,omitempty"`
+	VMs     []HostMaintenanceVM `json:"vms"`
+}
+
+// HostMaintenanceVM is a VM host maintenance stopped.
+type HostMaintenanceVM struct {
+	Name      string    `json:"name"`
+	StoppedAt time.Time `json:"stopped_at"`
+	// RestoredAt and Outcome are set once exiting maintenance restarted the
+	// VM or gave up on it.
+	RestoredAt *time.Time `json:"restored_at,omitempty"`
+	Outcome    string     `json:"outcome,omitempty"`
+	// Error is why the VM could not be stopped or restarted by the request
+	// that returned it.
+	Error string `json:"error,omitempty"`
+}
+
+// HostMaintenance returns the state of host maintenance mode, including the
+// VMs of the last maintenance after it was exited.
+func (e *engine) HostMaintenance(ctx context.Context) (*HostMaintenance, error) {
+	window, err := e.store.Queries().Maintenance().GetByName(ctx, HostMaintenanceWindow)
+	if err != nil {
+		return nil, err
+	}
+	return e.hostMaintenanceStatus(ctx, window, nil)
+}
+
+// EnterHostMaintenance opens the host maintenance window, which blocks new
+// VMs and holds control loops, then gracefully stops every running VM,
+// req.Concurrency at a time. Each VM is recorded before it is stopped so a
+// daemon restart mid-way still restores it. Entering again while engaged
+// stops VMs started in the meantime.
+func (e *engine) EnterHostMaintenance(ctx context.Context, req HostMaintenanceRequest) (*HostMaintenance, error) {
+	e.hostMaintenanceMu.Lock()
+	defer e.hostMaintenanceMu.Unlock()
+
+	window, err := e.openHostMaintenance(ctx, strings.TrimSpace(req.Reason))
+	if err != nil {
+		return nil, err
+	}
+	vms, err := e.store.Queries().VirtualMachines().List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var running []string
+	for _, vm := range vms {
+		if vm.Status == db.VMStatusRunning || vm.Status == db.VMStatusStarting {
+			running = append(running, vm.Name)
+		}
+	}
+	e.logger.Info("host maintenance engaged", "reason", window.Reason, "stopping", len(running))
+
+	failures := make(map[string]string)
+	var failMu sync.Mutex
+	forEachLimited(running, req.Concurrency, func(name string) {
+		err := e.store.Queries().Maintenance().RecordIntent(ctx, db.MaintenanceIntent{
+			WindowID:   window.ID,
+			Source:     MaintenanceSourceHost,
+			Target:     name,
+			Action:     "start",
+			RecordedAt: time.Now().UTC(),
+		})
+		if err == nil {
+			_, err = e.StopVM(ctx, name)
+		}
+		if err != nil {
+			e.logger.Error("stop vm for host maintenance", "vm", name, "error", err)
+			failMu.Lock()
+			failures[name] = err.Error()
+			failMu.Unlock()
+		}
+	})
+	return e.hostMaintenanceStatus(ctx, window, failures)
+}
+
+// ExitHostMaintenance ends the host maintenance window, lets held control
+// loop actions replay and restarts the VMs maintenance stopped, concurrency
+// at a time. It holds hostMaintenanceMu throughout, so an enter racing it
+// cannot reopen the window between the check and the restarts.
+func (e *engine) ExitHostMaintenance(ctx context.Context, concurrency int) (*HostMaintenance, error) {
+	e.hostMaintenanceMu.Lock()
+	defer e.hostMaintenanceMu.Unlock()
+
+	window, err := e.store.Queries().Maintenance().GetByName(ctx, HostMaintenanceWindow)
+	if err != nil {
+		return nil, err
+	}
+	if window == nil || !MaintenanceWindowOpen(*window, time.Now()) {
+		return nil, fmt.Errorf("%w: %s (host is not in maintenance)", ErrMaintenanceWindowNotFound, HostMaintenanceWindow)
+	}
+	if _, err := e.EndMaintenanceWindow(ctx, HostMaintenanceWindow); err != nil {
+		return nil, err
+	}
+	e.logger.Info("host maintenance exited", "reason", window.Reason)
+	failures := e.restoreHostMaintenanceLocked(ctx, concurrency)
+	window, err = e.store.Queries().Maintenance().GetByName(ctx, HostMaintenanceWindow)
+	if err != nil {
+		return nil, err
+	}
+	return e.hostMaintenanceStatus(ctx, window, failures)
+}
+
+// checkHostMaintenance refuses new VMs while the host is in maintenance.
+func (e *engine) checkHostMaintenance(ctx context.Context) error {
+	window, err := e.store.Queries().Maintenance().GetByName(ctx, HostMaintenanceWindow)
+	if err != nil {
+		return err
+	}
+	if window != nil && MaintenanceWindowOpen(*window, time.Now()) {
+		return fmt.Errorf("%w: new vms are refused until maintenance is exited", ErrHostMaintenance)
+	}
+	return nil
+}
+
+// openHostMaintenance returns the open host maintenance window, replacing
+// the window of a previous, fully restored maintenance.
+func (e *engine) openHostMaintenance(ctx context.Context, reason string) (*db.MaintenanceWindow, error) {
+	existing, err := e.store.Queries().Maintenance().GetByName(ctx, HostMaintenanceWindow)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		if MaintenanceWindowOpen(*existing, time.Now()) {
+			return existing, nil
+		}
+		if err := e.DeleteMaintenanceWindow(ctx, HostMaintenanceWindow); err != nil {
+			return nil, err
+		}
+	}
+	if reason == "" {
+		reason = "host maintenance"
+	}
+	now := time.Now().UTC()
+	return e.CreateMaintenanceWindow(ctx, CreateMaintenanceWindowRequest{
+		Name:     HostMaintenanceWindow,
+		Scope:    MaintenanceScopeAll,
+		Reason:   reason,
+		StartsAt: now,
+		EndsAt:   now.Add(hostMaintenanceSpan),
+	})
+}
+
+// restoreHostMaintenance restarts the VMs whose restart is due, that is,
+// those recorded by a host maintenance that has since been exited. It runs
+// on exit and on every replay pass, so restarts cut short by a daemon
+// restart are finished later.
+func (e *engine) restoreHostMaintenance(ctx context.Context, concurrency int) map[string]string {
+	e.hostMaintenanceMu.Lock()
+	defer e.hostMaintenanceMu.Unlock()
+	return e.restoreHostMaintenanceLocked(ctx, concurrency)
+}
+
+// restoreHostMaintenanceLocked is restoreHostMaintenance for callers holding
+// hostMaintenanceMu.
+func (e *engine) restoreHostMaintenanceLocked(ctx context.Context, concurrency int) map[string]string {
+	due, err := DueMaintenanceIntents(ctx, e.store, MaintenanceSourceHost, time.Now())
+	if err != nil {
+		e.logger.Error("list vms stopped for host maintenance", "error", err)
+		return nil
+	}
+	intents := make(map[string]db.MaintenanceIntent, len(due))
+	names := make([]string, 0, len(due))
+	for _, intent := range due {
+		intents[intent.Target] = intent
+		names = append(names, intent.Target)
+	}
+
+	failures := make(map[string]string)
+	var failMu sync.Mutex
+	forEachLimited(names, concurrency, func(name string) {
+		outcome := IntentReplayed
+		vm, err := e.GetVM(ctx, name)
+		switch {
+		case err != nil:
+		case vm == nil:
+			outcome = IntentSkipped + ": vm removed"
+		case vm.Status == db.VMStatusRunning || vm.Status == db.VMStatusStarting:
+			outcome = IntentSkipped + ": already running"
+		default:
+			_, err = e.StartVM(ctx, name)
+		}
+		if err != nil {
+			e.logger.Error("restart vm after host maintenance", "vm", name, "error", err)
+			outcome = IntentFailed + ": " + err.Error()
+			failMu.Lock()
+			failures[name] = err.Error()
+			failMu.Unlock()
+		}
+		if err := e.store.Queries().Maintenance().MarkReplayed(ctx, intents[name].ID, outcome, time.Now().UTC()); err != nil {
+			e.logger.Error("mark maintenance intent replayed", "vm", name, "error", err)
+		}
+	})
+	return failures
+}
+
+func (e *engine) hostMaintenanceStatus(ctx context.Context, window *db.MaintenanceWindow, failures map[string]string) (*HostMaintenance, error) {
+	status := &HostMaintenance{VMs: []HostMaintenanceVM{}}
+	if window == nil {
+		return status, nil
+	}
+	now := time.Now()
+	status.Engaged = MaintenanceWindowOpen(*window, now)
+	status.Reason = window.Reason
+	since := window.StartsAt
+	status.Since = &since
+	if !status.Engaged {
+		ended := window.EndsAt
+		status.EndedAt = &ended
+	}
+	intents, err := e.store.Queries().Maintenance().Intents(ctx, window.ID)
+	if err != nil {
+		return nil, err
+	}
+	for _, intent := range intents {
+		if intent.Source != MaintenanceSourceHost {
+			continue
+		}
+		status.VMs = append(status.VMs, HostMaintenanceVM{
+			Name:       intent.Target,
+			StoppedAt:  intent.RecordedAt,
+			RestoredAt: intent.ReplayedAt,
+			Outcome:    intent.Outcome,
+			Error:      failures[intent.Target],
+		})
+	}
+	sort.Slice(status.VMs, func(i, j int) bool { return status.VMs[i].Name < status.VMs[j].Name })
+	return status, nil
+}
+
+// forEachLimited calls fn for every name, at most limit at a time.
+func forEachLimited(names []string, limit int, fn func(name string)) {
+	if limit <= 0 {
+		limit = DefaultMaintenanceConcurrency
+	}
+	sem := make(chan struct{}, limit)
+	var wg sync.WaitGroup
+	for _, name := range names {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+			fn(name)
+		}()
+	}
+	wg.Wait()
+}
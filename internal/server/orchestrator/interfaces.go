@@ -0,0 +1,76 @@
+// Copyright (c) 2025 HYPR. PTE. LTD.
+//
+// Business Source License 1.1
+// See LICENSE file in the project root for details.
+
+package orchestrator
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/volantvm/volant/internal/pluginspec"
+	"github.com/volantvm/volant/internal/server/orchestrator/network"
+	"github.com/volantvm/volant/internal/server/orchestrator/runtime"
+)
+
+// prepareInterfaces creates taps for the VM's additional NICs and returns the
+// launch devices together with the kernel arguments the agent uses to
+// configure them. Interfaces are numbered from 1; the primary NIC is 0.
+func (e *engine) prepareInterfaces(ctx context.Context, vmName string, netCfg *pluginspec.NetworkConfig) ([]runtime.NetInterface, map[string]string, error) {
+	if netCfg == nil || len(netCfg.Interfaces) == 0 {
+		return nil, nil, nil
+	}
+	attacher, ok := e.network.(network.InterfaceAttacher)
+	if !ok {
+		return nil, nil, fmt.Errorf("orchestrator: vm %s declares additional interfaces but the network manager cannot attach them", vmName)
+	}
+
+	nics := make([]runtime.NetInterface, 0, len(netCfg.Interfaces))
+	args := make(map[string]string, len(netCfg.Interfaces))
+	taps := make([]string, 0, len(netCfg.Interfaces))
+	for i, iface := range netCfg.Interfaces {
+		index := i + 1
+		mac := strings.ToLower(strings.TrimSpace(iface.MAC))
+		if mac == "" {
+			mac = deriveMAC(vmName, fmt.Sprintf("nic%d", index))
+		}
+		tap, err := attacher.PrepareInterface(ctx, vmName, index, iface.Bridge, mac)
+		if err != nil {
+			for _, created := range taps {
+				_ = e.network.CleanupTap(ctx, created)
+			}
+			return nil, nil, runtime.NewLaunchError(runtime.ErrorTapCreate, fmt.Errorf("interface %d on %s: %w", index, iface.Bridge, err))
+		}
+		taps = append(taps, tap)
+		nics = append(nics, runtime.NetInterface{TapDevice: tap, MACAddress: mac})
+
+		value := mac
+		if iface.Mode != pluginspec.NetworkModeDHCP {
+			value += "," + strings.TrimSpace(iface.IPAddress)
+			if gw := strings.TrimSpace(iface.Gateway); gw != "" {
+				value += "," + gw
+			}
+		}
+		args[fmt.Sprintf("%s%d", pluginspec.InterfaceKeyPrefix, index)] = value
+	}
+
+	e.mu.Lock()
+	e.nics[vmName] = taps
+	e.mu.Unlock()
+	return nics, args, nil
+}
+
+// releaseInterfaces removes the taps created for the VM's additional NICs.
+func (e *engine) releaseInterfaces(ctx context.Context, vmName string) {
+	e.mu.Lock()
+	taps := e.nics[vmName]
+	delete(e.nics, vmName)
+	e.mu.Unlock()
+	for _, tap := range taps {
+		if err := e.network.CleanupTap(ctx, tap); err != nil {
+			e.logger.Warn("cleanup interface tap", "vm", vmName, "tap", tap, "error", err)
+		}
+	}
+}
@@ -0,0 +1,89 @@
+// Copyright (c) 2025 HYPR. PTE. LTD.
+//
+// Business Source License 1.1
+// See LICENSE file in the project root for details.
+
+package ipam
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const requestTimeout = 10 * time.Second
+
+// apiClient is the JSON-over-HTTP plumbing shared by the external backends.
+type apiClient struct {
+	baseURL    *url.URL
+	authHeader string
+	authValue  string
+	httpClient *http.Client
+}
+
+func newAPIClient(endpoint, authHeader, authValue string) (*apiClient, error) {
+	parsed, err := url.Parse(strings.TrimSpace(endpoint))
+	if err != nil {
+		return nil, fmt.Errorf("ipam: parse url: %w", err)
+	}
+	if parsed.Scheme == "" || parsed.Host == "" {
+		return nil, fmt.Errorf("ipam: url must include scheme and host")
+	}
+	parsed.Path = strings.TrimRight(parsed.Path, "/")
+	return &apiClient{
+		baseURL:    parsed,
+		authHeader: authHeader,
+		authValue:  authValue,
+		httpClient: &http.Client{Timeout: requestTimeout},
+	}, nil
+}
+
+// do sends in as a JSON body (when non-nil) and decodes the response into out
+// (when non-nil). The suffix is appended verbatim so APIs that insist on
+// trailing slashes keep them.
+func (c *apiClient) do(ctx context.Context, method, suffix string, query url.Values, in, out any) error {
+	full := *c.baseURL
+	full.Path = c.baseURL.Path + suffix
+	full.RawQuery = query.Encode()
+
+	var body io.Reader
+	if in != nil {
+		payload, err := json.Marshal(in)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(payload)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, full.String(), body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	if in != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.authValue != "" {
+		req.Header.Set(c.authHeader, c.authValue)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return fmt.Errorf("%s %s: status %d: %s", method, full.Path, resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+	if out == nil || resp.StatusCode == http.StatusNoContent {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
@@ -0,0 +1,206 @@
+// Copyright (c) 2025 HYPR. PTE. LTD.
+//
+// Business Source License 1.1
+// See LICENSE file in the project root for details.
+
+// Package ipam hands out guest addresses on the host bridge subnet, either from
+// the daemon's internal pool or from an external IP address management system.
+package ipam
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"strings"
+
+	"github.com/volantvm/volant/internal/server/db"
+)
+
+// Backend names accepted by New.
+const (
+	BackendInternal = "internal"
+	BackendWebhook  = "webhook"
+	BackendNetbox   = "netbox"
+	BackendPHPIPAM  = "phpipam"
+)
+
+// Request identifies the VM an address is leased for or released from.
+type Request struct {
+	VMName    string
+	IPAddress string
+	// Family selects the address family to lease (db.IPFamilyV4 or
+	// db.IPFamilyV6); zero means IPv4.
+	Family int
+}
+
+// Allocator leases and releases guest addresses. Calls run inside the
+// orchestrator's store transaction and must record the lease in the
+// ip_allocations table so the local state stays consistent.
+type Allocator interface {
+	Lease(ctx context.Context, q db.Queries, req Request) (string, error)
+	Release(ctx context.Context, q db.Queries, req Request) error
+}
+
+// Backend is an external system of record for guest addresses.
+type Backend interface {
+	Allocate(ctx context.Context, vmName string) (net.IP, error)
+	Free(ctx context.Context, vmName string, ip net.IP) error
+}
+
+// Options configures the allocator built by New.
+type Options struct {
+	// Backend selects the allocator; empty means BackendInternal.
+	Backend string
+	URL     string
+	Token   string
+	// Pool is the external subnet or prefix identifier (phpIPAM subnet id,
+	// NetBox prefix id).
+	Pool string
+	// App is the phpIPAM API application id.
+	App    string
+	Subnet *net.IPNet
+	Logger *slog.Logger
+}
+
+// New builds the allocator selected by opts.
+func New(opts Options) (Allocator, error) {
+	switch strings.ToLower(strings.TrimSpace(opts.Backend)) {
+	case "", BackendInternal:
+		return NewPool(), nil
+	}
+
+	if opts.Subnet == nil {
+		return nil, fmt.Errorf("ipam: subnet is required")
+	}
+	if strings.TrimSpace(opts.URL) == "" {
+		return nil, fmt.Errorf("ipam: %s backend requires a url", opts.Backend)
+	}
+
+	var (
+		backend Backend
+		err     error
+	)
+	switch strings.ToLower(strings.TrimSpace(opts.Backend)) {
+	case BackendWebhook:
+		backend, err = NewWebhook(opts.URL, opts.Token, opts.Subnet)
+	case BackendNetbox:
+		backend, err = NewNetbox(opts.URL, opts.Token, opts.Pool)
+	case BackendPHPIPAM:
+		backend, err = NewPHPIPAM(opts.URL, opts.App, opts.Token, opts.Pool)
+	default:
+		return nil, fmt.Errorf("ipam: unknown backend %q", opts.Backend)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return NewExternal(backend, opts.Subnet, opts.Logger), nil
+}
+
+// Pool leases addresses from the internal ip_allocations pool.
+type Pool struct{}
+
+// NewPool returns the default allocator backed by the local database.
+func NewPool() *Pool { return &Pool{} }
+
+// Lease reserves the lowest available address in the pool.
+func (p *Pool) Lease(ctx context.Context, q db.Queries, req Request) (string, error) {
+	family := req.Family
+	if family == 0 {
+		family = db.IPFamilyV4
+	}
+	allocation, err := q.IPAllocations().LeaseNextAvailable(ctx, family)
+	if err != nil {
+		return "", err
+	}
+	return allocation.IPAddress, nil
+}
+
+// Release returns the address to the pool.
+func (p *Pool) Release(ctx context.Context, q db.Queries, req Request) error {
+	return q.IPAllocations().Release(ctx, req.IPAddress)
+}
+
+// External leases addresses from a Backend and mirrors them into the local
+// ip_allocations table, which guards against handing one address to two VMs.
+type External struct {
+	backend Backend
+	subnet  *net.IPNet
+	logger  *slog.Logger
+}
+
+// NewExternal wraps backend as an Allocator restricted to subnet.
+func NewExternal(backend Backend, subnet *net.IPNet, logger *slog.Logger) *External {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &External{backend: backend, subnet: subnet, logger: logger.With("component", "ipam")}
+}
+
+// Lease asks the backend for an address and records it locally. The backend
+// lease is freed again when the address cannot be used.
+func (e *External) Lease(ctx context.Context, q db.Queries, req Request) (string, error) {
+	ip, err := e.backend.Allocate(ctx, req.VMName)
+	if err != nil {
+		return "", fmt.Errorf("ipam: allocate: %w", err)
+	}
+	address := ip.String()
+
+	if err := e.record(ctx, q, ip); err != nil {
+		if freeErr := e.backend.Free(ctx, req.VMName, ip); freeErr != nil {
+			e.logger.Warn("free rejected lease", "vm", req.VMName, "ip", address, "error", freeErr)
+		}
+		return "", err
+	}
+	return address, nil
+}
+
+func (e *External) record(ctx context.Context, q db.Queries, ip net.IP) error {
+	if !e.subnet.Contains(ip) {
+		return fmt.Errorf("ipam: leased address %s not in subnet %s", ip, e.subnet)
+	}
+	address := ip.String()
+	if err := q.IPAllocations().EnsurePool(ctx, []string{address}); err != nil {
+		return err
+	}
+	if _, err := q.IPAllocations().LeaseSpecific(ctx, address); err != nil {
+		if errors.Is(err, db.ErrNoAvailableIPs) {
+			return fmt.Errorf("ipam: leased address %s already in use", address)
+		}
+		return err
+	}
+	return nil
+}
+
+// Release frees the address locally and in the backend. Backend failures are
+// logged rather than returned so an unreachable IPAM system cannot block VM
+// deletion.
+func (e *External) Release(ctx context.Context, q db.Queries, req Request) error {
+	if strings.TrimSpace(req.IPAddress) == "" {
+		return nil
+	}
+	if err := q.IPAllocations().Release(ctx, req.IPAddress); err != nil {
+		return err
+	}
+	ip := net.ParseIP(req.IPAddress)
+	if ip == nil {
+		return nil
+	}
+	if err := e.backend.Free(ctx, req.VMName, ip); err != nil {
+		e.logger.Warn("free external lease", "vm", req.VMName, "ip", req.IPAddress, "error", err)
+	}
+	return nil
+}
+
+func parseAddress(raw string) (net.IP, error) {
+	raw = strings.TrimSpace(raw)
+	if host, _, err := net.ParseCIDR(raw); err == nil {
+		return host, nil
+	}
+	ip := net.ParseIP(raw)
+	if ip == nil {
+		return nil, fmt.Errorf("invalid address %q", raw)
+	}
+	return ip, nil
+}
@@ -0,0 +1,79 @@
+// Copyright (c) 2025 HYPR. PTE. LTD.
+//
+// Business Source License 1.1
+// See LICENSE file in the project root for details.
+
+package ipam
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// Netbox leases addresses from a NetBox prefix through its REST API.
+type Netbox struct {
+	client   *apiClient
+	prefixID string
+}
+
+// NewNetbox constructs a NetBox backend allocating from the prefix with the given id.
+func NewNetbox(endpoint, token, prefixID string) (*Netbox, error) {
+	prefixID = strings.TrimSpace(prefixID)
+	if prefixID == "" {
+		return nil, fmt.Errorf("ipam: netbox backend requires a prefix id")
+	}
+	authValue := ""
+	if token != "" {
+		authValue = "Token " + token
+	}
+	client, err := newAPIClient(endpoint, "Authorization", authValue)
+	if err != nil {
+		return nil, err
+	}
+	return &Netbox{client: client, prefixID: prefixID}, nil
+}
+
+type netboxAddress struct {
+	ID      int64  `json:"id"`
+	Address string `json:"address"`
+}
+
+// Allocate creates the next available IP address in the prefix.
+func (n *Netbox) Allocate(ctx context.Context, vmName string) (net.IP, error) {
+	body := map[string]string{
+		"description": "volant vm " + vmName,
+		"status":      "active",
+	}
+	var created netboxAddress
+	suffix := "/api/ipam/prefixes/" + url.PathEscape(n.prefixID) + "/available-ips/"
+	if err := n.client.do(ctx, http.MethodPost, suffix, nil, body, &created); err != nil {
+		return nil, err
+	}
+	ip, err := parseAddress(created.Address)
+	if err != nil {
+		return nil, fmt.Errorf("netbox lease: %w", err)
+	}
+	return ip, nil
+}
+
+// Free deletes the IP address object created for the VM.
+func (n *Netbox) Free(ctx context.Context, vmName string, ip net.IP) error {
+	var list struct {
+		Results []netboxAddress `json:"results"`
+	}
+	query := url.Values{"address": []string{ip.String()}, "parent_id": []string{n.prefixID}}
+	if err := n.client.do(ctx, http.MethodGet, "/api/ipam/ip-addresses/", query, nil, &list); err != nil {
+		return err
+	}
+	for _, addr := range list.Results {
+		suffix := fmt.Sprintf("/api/ipam/ip-addresses/%d/", addr.ID)
+		if err := n.client.do(ctx, http.MethodDelete, suffix, nil, nil, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,87 @@
+// Copyright (c) 2025 HYPR. PTE. LTD.
+//
+// Business Source License 1.1
+// See LICENSE file in the project root for details.
+
+package ipam
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// PHPIPAM leases addresses from a phpIPAM subnet using an app token.
+type PHPIPAM struct {
+	client   *apiClient
+	app      string
+	subnetID string
+}
+
+// NewPHPIPAM constructs a phpIPAM backend for the given API app and subnet id.
+func NewPHPIPAM(endpoint, app, token, subnetID string) (*PHPIPAM, error) {
+	app = strings.TrimSpace(app)
+	subnetID = strings.TrimSpace(subnetID)
+	if app == "" {
+		return nil, fmt.Errorf("ipam: phpipam backend requires an app id")
+	}
+	if subnetID == "" {
+		return nil, fmt.Errorf("ipam: phpipam backend requires a subnet id")
+	}
+	client, err := newAPIClient(endpoint, "token", token)
+	if err != nil {
+		return nil, err
+	}
+	return &PHPIPAM{client: client, app: app, subnetID: subnetID}, nil
+}
+
+type phpipamResponse struct {
+	Success bool   `json:"success"`
+	Message string `json:"message"`
+	Data    any    `json:"data"`
+}
+
+// Allocate reserves the first free address in the subnet for the VM.
+func (p *PHPIPAM) Allocate(ctx context.Context, vmName string) (net.IP, error) {
+	body := map[string]string{
+		"hostname":    vmName,
+		"description": "volant vm " + vmName,
+	}
+	var resp phpipamResponse
+	if err := p.client.do(ctx, http.MethodPost, p.path("addresses", "first_free", p.subnetID), nil, body, &resp); err != nil {
+		return nil, err
+	}
+	if !resp.Success {
+		return nil, fmt.Errorf("phpipam lease: %s", resp.Message)
+	}
+	address, _ := resp.Data.(string)
+	ip, err := parseAddress(address)
+	if err != nil {
+		return nil, fmt.Errorf("phpipam lease: %w", err)
+	}
+	return ip, nil
+}
+
+// Free removes the address from the subnet.
+func (p *PHPIPAM) Free(ctx context.Context, vmName string, ip net.IP) error {
+	var resp phpipamResponse
+	if err := p.client.do(ctx, http.MethodDelete, p.path("addresses", ip.String(), p.subnetID), nil, nil, &resp); err != nil {
+		return err
+	}
+	if !resp.Success {
+		return fmt.Errorf("phpipam release: %s", resp.Message)
+	}
+	return nil
+}
+
+func (p *PHPIPAM) path(segments ...string) string {
+	escaped := make([]string, 0, len(segments)+2)
+	escaped = append(escaped, "api", url.PathEscape(p.app))
+	for _, segment := range segments {
+		escaped = append(escaped, url.PathEscape(segment))
+	}
+	return "/" + strings.Join(escaped, "/") + "/"
+}
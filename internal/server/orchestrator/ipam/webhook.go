@@ -0,0 +1,63 @@
+// Copyright (c) 2025 HYPR. PTE. LTD.
+//
+// Business Source License 1.1
+// See LICENSE file in the project root for details.
+
+package ipam
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+)
+
+// Webhook delegates leases to a simple HTTP service:
+//
+//	POST {url}/lease   {"vm": "...", "subnet": "..."} -> {"ip": "..."}
+//	POST {url}/release {"vm": "...", "ip": "..."}
+//
+// The token, when set, is sent as a bearer token.
+type Webhook struct {
+	client *apiClient
+	subnet string
+}
+
+// NewWebhook constructs a webhook backend.
+func NewWebhook(endpoint, token string, subnet *net.IPNet) (*Webhook, error) {
+	authValue := ""
+	if token != "" {
+		authValue = "Bearer " + token
+	}
+	client, err := newAPIClient(endpoint, "Authorization", authValue)
+	if err != nil {
+		return nil, err
+	}
+	return &Webhook{client: client, subnet: subnet.String()}, nil
+}
+
+type webhookRequest struct {
+	VM     string `json:"vm"`
+	Subnet string `json:"subnet,omitempty"`
+	IP     string `json:"ip,omitempty"`
+}
+
+// Allocate requests a new lease for the VM.
+func (w *Webhook) Allocate(ctx context.Context, vmName string) (net.IP, error) {
+	var resp struct {
+		IP string `json:"ip"`
+	}
+	if err := w.client.do(ctx, http.MethodPost, "/lease", nil, webhookRequest{VM: vmName, Subnet: w.subnet}, &resp); err != nil {
+		return nil, err
+	}
+	ip, err := parseAddress(resp.IP)
+	if err != nil {
+		return nil, fmt.Errorf("webhook lease: %w", err)
+	}
+	return ip, nil
+}
+
+// Free releases the VM's lease.
+func (w *Webhook) Free(ctx context.Context, vmName string, ip net.IP) error {
+	return w.client.do(ctx, http.MethodPost, "/release", nil, webhookRequest{VM: vmName, Subnet: w.subnet, IP: ip.String()}, nil)
+}
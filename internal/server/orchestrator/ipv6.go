@@ -0,0 +1,111 @@
+// Copyright (c) 2025 HYPR. PTE. LTD.
+//
+// Business Source License 1.1
+// See LICENSE file in the project root for details.
+
+package orchestrator
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/volantvm/volant/internal/pluginspec"
+	"github.com/volantvm/volant/internal/server/db"
+	"github.com/volantvm/volant/internal/server/orchestrator/network"
+)
+
+// maxIPv6PoolSize caps the addresses seeded from an IPv6 subnet; even a /64
+// holds far more guests than a single host can run.
+const maxIPv6PoolSize = 4096
+
+// deriveIPv6Pool lists assignable addresses from the start of subnet, skipping
+// the subnet-router anycast address and the host address.
+func deriveIPv6Pool(subnet *net.IPNet, hostIP net.IP) ([]string, error) {
+	ones, bits := subnet.Mask.Size()
+	if bits != 8*net.IPv6len || ones >= bits {
+		return nil, fmt.Errorf("invalid subnet mask: %s", subnet.Mask)
+	}
+
+	pool := make([]string, 0, 64)
+	for ip := nextIP(subnet.IP.Mask(subnet.Mask)); subnet.Contains(ip) && len(pool) < maxIPv6PoolSize; ip = nextIP(ip) {
+		if ip.Equal(hostIP) {
+			continue
+		}
+		pool = append(pool, ip.String())
+	}
+	if len(pool) == 0 {
+		return nil, fmt.Errorf("no assignable IPs in subnet %s", subnet)
+	}
+	return pool, nil
+}
+
+func nextIP(ip net.IP) net.IP {
+	next := make(net.IP, len(ip))
+	copy(next, ip)
+	for i := len(next) - 1; i >= 0; i-- {
+		next[i]++
+		if next[i] != 0 {
+			break
+		}
+	}
+	return next
+}
+
+func isIPv6(addr string) bool {
+	ip := net.ParseIP(strings.TrimSpace(addr))
+	return ip != nil && ip.To4() == nil
+}
+
+func ipv6KernelArgs(ip, prefix, gateway string) string {
+	return fmt.Sprintf("%s=%s/%s %s=%s", pluginspec.IPv6AddressKey, ip, prefix, pluginspec.IPv6GatewayKey, gateway)
+}
+
+// guestKernelCmdline builds the base kernel command line for a VM, adding the
+// secondary IPv6 address on dual-stack hosts. netw is the VM's user network,
+// or nil for the default bridge.
+func (e *engine) guestKernelCmdline(netw *db.Network, ip, ipv6, hostname, extra string) string {
+	if ipv6 != "" && e.subnet6 != nil {
+		extra = strings.TrimSpace(ipv6KernelArgs(ipv6, formatNetmask(e.subnet6.Mask), e.hostIP6.String()) + " " + extra)
+	}
+	gateway, netmask := e.guestAddressing(netw)
+	return buildKernelCmdline(ip, gateway, netmask, hostname, extra)
+}
+
+// guestIPv6Addresses returns every IPv6 address assigned to the VM.
+func guestIPv6Addresses(vm db.VM) []net.IP {
+	var addrs []net.IP
+	for _, raw := range []string{vm.IPAddress, vm.IPv6Address} {
+		if isIPv6(raw) {
+			addrs = append(addrs, net.ParseIP(strings.TrimSpace(raw)))
+		}
+	}
+	return addrs
+}
+
+// addNeighborProxies publishes the VM's IPv6 addresses on the uplink so the
+// upstream router can reach guests on a routed, non-NATed prefix.
+func (e *engine) addNeighborProxies(ctx context.Context, vm db.VM) {
+	proxy, ok := e.network.(network.NeighborProxy)
+	if !ok {
+		return
+	}
+	for _, ip := range guestIPv6Addresses(vm) {
+		if err := proxy.AddNeighborProxy(ctx, ip); err != nil {
+			e.logger.Warn("add ndp proxy entry", "vm", vm.Name, "ip", ip, "error", err)
+		}
+	}
+}
+
+func (e *engine) removeNeighborProxies(ctx context.Context, vm db.VM) {
+	proxy, ok := e.network.(network.NeighborProxy)
+	if !ok {
+		return
+	}
+	for _, ip := range guestIPv6Addresses(vm) {
+		if err := proxy.RemoveNeighborProxy(ctx, ip); err != nil {
+			e.logger.Debug("remove ndp proxy entry", "vm", vm.Name, "ip", ip, "error", err)
+		}
+	}
+}
@@ -0,0 +1,64 @@
+// Copyright (c) 2025 HYPR. PTE. LTD.
+//
+// Business Source License 1.1
+// See LICENSE file in the project root for details.
+
+package orchestrator
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/volantvm/volant/internal/server/db"
+	"github.com/volantvm/volant/internal/server/labels"
+)
+
+// DeploymentLabel is set on every replica to the name of its deployment.
+const DeploymentLabel = "volant.deployment"
+
+func (e *engine) UpdateVMLabels(ctx context.Context, name string, patch map[string]*string) (*db.VM, error) {
+	var updated *db.VM
+	err := e.store.WithTx(ctx, func(q db.Queries) error {
+		vmRepo := q.VirtualMachines()
+		vm, err := vmRepo.GetByName(ctx, name)
+		if err != nil {
+			return err
+		}
+		if vm == nil {
+			return fmt.Errorf("%w: %s", ErrVMNotFound, name)
+		}
+		next := labels.Merge(vm.Labels, nil)
+		if next == nil {
+			next = make(map[string]string, len(patch))
+		}
+		for key, value := range patch {
+			if value == nil {
+				delete(next, key)
+				continue
+			}
+			next[key] = *value
+		}
+		if err := labels.Validate(next); err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidLabels, err)
+		}
+		if err := vmRepo.SetLabels(ctx, vm.ID, next); err != nil {
+			return err
+		}
+		updated, err = vmRepo.GetByName(ctx, name)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	e.refreshLogTarget(*updated)
+	e.logger.Info("vm labels updated", "vm", name, "labels", len(updated.Labels))
+	return updated, nil
+}
+
+// duplicateLabels copies a VM's labels for a duplicate, which never belongs
+// to the source's deployment.
+func duplicateLabels(source map[string]string) map[string]string {
+	out := labels.Merge(source, nil)
+	delete(out, DeploymentLabel)
+	return out
+}
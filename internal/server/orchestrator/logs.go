@@ -0,0 +1,265 @@
+// Copyright (c) 2025 HYPR. PTE. LTD.
+//
+// Business Source License 1.1
+// See LICENSE file in the project root for details.
+
+package orchestrator
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/volantvm/volant/internal/pluginspec"
+	"github.com/volantvm/volant/internal/server/db"
+	"github.com/volantvm/volant/internal/server/logship"
+	"github.com/volantvm/volant/internal/server/orchestrator/runtime"
+	"github.com/volantvm/volant/internal/server/orchestrator/vmlogs"
+)
+
+const (
+	agentLogStreamPath = "/v1/logs/stream"
+	// logPollInterval is how often raw hypervisor output is ingested.
+	logPollInterval = time.Second
+	// agentLogRetry is the pause before following the agent's log stream
+	// again after it ends.
+	agentLogRetry = 2 * time.Second
+)
+
+// captureLogs points the hypervisor's stdout and stderr at the raw files the
+// log store ingests.
+func (e *engine) captureLogs(spec *runtime.LaunchSpec) {
+	if e.logs == nil {
+		return
+	}
+	spec.StdoutLog = e.logs.RawPath(spec.Name, vmlogs.StreamStdout)
+	spec.StderrLog = e.logs.RawPath(spec.Name, vmlogs.StreamStderr)
+}
+
+// collectLogs persists the hypervisor output and guest agent logs of a
+// launched instance until exited is closed.
+func (e *engine) collectLogs(name string, handle processHandle, exited <-chan struct{}) {
+	if e.logs == nil {
+		return
+	}
+	go func() {
+		if vm, err := e.store.Queries().VirtualMachines().GetByName(context.Background(), name); err == nil && vm != nil {
+			e.refreshLogTarget(*vm)
+		}
+		follower := e.logs.Follow(name)
+		ticker := time.NewTicker(logPollInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-exited:
+				if err := follower.Poll(true); err != nil {
+					e.logger.Warn("ingest hypervisor output", "vm", name, "error", err)
+				}
+				return
+			case <-ticker.C:
+				if err := follower.Poll(false); err != nil {
+					e.logger.Debug("ingest hypervisor output", "vm", name, "error", err)
+				}
+			}
+		}
+	}()
+	go e.followAgentLogs(name, handle, exited)
+}
+
+// followAgentLogs records the guest agent's log stream once the instance is
+// ready, reconnecting whenever the stream ends while the instance runs.
+func (e *engine) followAgentLogs(name string, handle processHandle, exited <-chan struct{}) {
+	ctx, cancel := context.WithCancel(e.launchContext())
+	defer cancel()
+	go func() {
+		select {
+		case <-exited:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	ticker := time.NewTicker(readinessPollInterval)
+	defer ticker.Stop()
+	for {
+		if !e.isCurrentInstance(name, handle) {
+			return
+		}
+		if e.isReady(name) {
+			if err := e.streamAgentLogs(ctx, name); err != nil && ctx.Err() == nil {
+				e.logger.Debug("agent log stream", "vm", name, "error", err)
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(agentLogRetry):
+			}
+			continue
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// streamAgentLogs appends the guest agent's log events to the VM's log until
+// the stream ends or ctx is done.
+func (e *engine) streamAgentLogs(ctx context.Context, name string) error {
+	vm, err := e.store.Queries().VirtualMachines().GetByName(ctx, name)
+	if err != nil || vm == nil {
+		return err
+	}
+	transport, base, err := agentTransport(*vm)
+	if err != nil {
+		return err
+	}
+	defer transport.CloseIdleConnections()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, base+agentLogStreamPath, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "text/event-stream")
+	resp, err := (&http.Client{Transport: transport}).Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("agent returned status %d", resp.StatusCode)
+	}
+
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	var data strings.Builder
+	flush := func() error {
+		if data.Len() == 0 {
+			return nil
+		}
+		var event struct {
+			Stream    string    `json:"stream"`
+			Line      string    `json:"line"`
+			Timestamp time.Time `json:"timestamp"`
+		}
+		payload := data.String()
+		data.Reset()
+		if err := json.Unmarshal([]byte(payload), &event); err != nil {
+			return nil
+		}
+		if event.Timestamp.IsZero() {
+			event.Timestamp = time.Now()
+		}
+		return e.logs.Append(name, vmlogs.Entry{
+			Timestamp: event.Timestamp.UTC(),
+			Source:    vmlogs.SourceAgent,
+			Stream:    event.Stream,
+			Line:      event.Line,
+		})
+	}
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" {
+			if err := flush(); err != nil {
+				return err
+			}
+			continue
+		}
+		if !strings.HasPrefix(line, "data:") {
+			continue
+		}
+		if data.Len() > 0 {
+			data.WriteByte('\n')
+		}
+		data.WriteString(strings.TrimSpace(strings.TrimPrefix(line, "data:")))
+	}
+	if err := flush(); err != nil {
+		return err
+	}
+	return scanner.Err()
+}
+
+// logTarget is where the logs of a VM are shipped and the VM context sent
+// along.
+type logTarget struct {
+	plugin     string
+	deployment string
+	exporters  []string
+}
+
+// refreshLogTarget resolves the exporters receiving the logs of vm from its
+// labels, which carry the plugin manifest's labels.
+func (e *engine) refreshLogTarget(vm db.VM) {
+	if e.shipper == nil {
+		return
+	}
+	target := logTarget{
+		plugin:     vm.Labels[pluginspec.PluginKey],
+		deployment: vm.Labels[DeploymentLabel],
+		exporters:  e.shipper.Targets(vm.Labels),
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.logTargets == nil {
+		e.logTargets = make(map[string]logTarget)
+	}
+	e.logTargets[vm.Name] = target
+}
+
+// shipLogs forwards recorded entries to the VM's exporters.
+func (e *engine) shipLogs(name string, entries []vmlogs.Entry) {
+	e.mu.Lock()
+	target, ok := e.logTargets[name]
+	e.mu.Unlock()
+	if !ok || len(target.exporters) == 0 {
+		return
+	}
+	records := make([]logship.Record, 0, len(entries))
+	for _, entry := range entries {
+		records = append(records, logship.Record{
+			Timestamp:  entry.Timestamp,
+			VM:         name,
+			Plugin:     target.plugin,
+			Deployment: target.deployment,
+			Source:     entry.Source,
+			Stream:     entry.Stream,
+			Line:       entry.Line,
+		})
+	}
+	e.shipper.Ship(target.exporters, records...)
+}
+
+// dropLogs discards the persisted logs of a deleted VM.
+func (e *engine) dropLogs(name string) {
+	e.mu.Lock()
+	delete(e.logTargets, name)
+	e.mu.Unlock()
+	if e.logs == nil {
+		return
+	}
+	if err := e.logs.Remove(name); err != nil {
+		e.logger.Warn("remove vm logs", "vm", name, "error", err)
+	}
+}
+
+// VMLogs returns the persisted log entries of a VM matching query, oldest
+// first. Logs survive restarts of the VM and of volantd until retention
+// removes them or the VM is deleted.
+func (e *engine) VMLogs(ctx context.Context, name string, query vmlogs.Query) ([]vmlogs.Entry, error) {
+	vm, err := e.store.Queries().VirtualMachines().GetByName(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	if vm == nil {
+		return nil, fmt.Errorf("%w: %s", ErrVMNotFound, name)
+	}
+	if e.logs == nil {
+		return []vmlogs.Entry{}, nil
+	}
+	return e.logs.Read(name, query)
+}
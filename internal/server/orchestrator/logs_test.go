@@ -0,0 +1,160 @@
+// Copyright (c) 2025 HYPR. PTE. LTD.
+//
+// Business Source License 1.1
+// See LICENSE file in the project root for details.
+
+package orchestrator
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/volantvm/volant/internal/pluginspec"
+	"github.com/volantvm/volant/internal/server/orchestrator/vmlogs"
+)
+
+func TestVMLogsPersisted(t *testing.T) {
+	ctx := context.Background()
+	store := openTestStore(t)
+	defer func() { _ = store.Close(ctx) }()
+
+	subnet, host := testSubnet(t)
+	logDir := t.TempDir()
+	logs := vmlogs.New(logDir, vmlogs.Retention{MaxFileBytes: 1 << 10, MaxFiles: 2})
+	defer logs.Close()
+	launcher := &testLauncher{}
+	engine, err := New(Params{
+		Store:            store,
+		Logger:           slog.New(slog.NewTextHandler(io.Discard, nil)),
+		Subnet:           subnet,
+		HostIP:           host,
+		APIListenAddr:    "127.0.0.1:7777",
+		APIAdvertiseAddr: "127.0.0.1:7777",
+		RuntimeDir:       t.TempDir(),
+		Launcher:         launcher,
+		Network:          &testNetworkManager{},
+		Logs:             logs,
+	})
+	if err != nil {
+		t.Fatalf("new engine: %v", err)
+	}
+	if err := engine.Start(ctx); err != nil {
+		t.Fatalf("engine start: %v", err)
+	}
+	defer func() { _ = engine.Stop(ctx) }()
+
+	if _, err := engine.CreateVM(ctx, CreateVMRequest{
+		Name:     "demo",
+		CPUCores: 1,
+		MemoryMB: 512,
+		Manifest: &pluginspec.Manifest{Name: "demo", Runtime: "demo"},
+	}); err != nil {
+		t.Fatalf("create vm: %v", err)
+	}
+	spec := launcher.calls[len(launcher.calls)-1]
+	if spec.StdoutLog != logs.RawPath("demo", vmlogs.StreamStdout) || spec.StderrLog != logs.RawPath("demo", vmlogs.StreamStderr) {
+		t.Fatalf("launch spec logs = %q, %q", spec.StdoutLog, spec.StderrLog)
+	}
+
+	// Stand in for the hypervisor writing to its raw output files.
+	writeRaw := func(path, data string) {
+		t.Helper()
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer file.Close()
+		if _, err := file.WriteString(data); err != nil {
+			t.Fatal(err)
+		}
+	}
+	writeRaw(spec.StdoutLog, "booting\n")
+	writeRaw(spec.StderrLog, "warning: slow disk\npartial")
+
+	waitFor := func(query vmlogs.Query, want int) []vmlogs.Entry {
+		t.Helper()
+		deadline := time.Now().Add(5 * time.Second)
+		for {
+			entries, err := engine.VMLogs(ctx, "demo", query)
+			if err != nil {
+				t.Fatalf("vm logs: %v", err)
+			}
+			if len(entries) == want {
+				return entries
+			}
+			if time.Now().After(deadline) {
+				t.Fatalf("vm logs %+v = %+v, want %d entries", query, entries, want)
+			}
+			time.Sleep(20 * time.Millisecond)
+		}
+	}
+	waitFor(vmlogs.Query{}, 2)
+	stderr := waitFor(vmlogs.Query{Stream: vmlogs.StreamStderr}, 1)
+	if stderr[0].Line != "warning: slow disk" || stderr[0].Source != vmlogs.SourceHypervisor {
+		t.Fatalf("stderr entry = %+v", stderr[0])
+	}
+	if info, err := os.Stat(spec.StdoutLog); err != nil || info.Size() != 0 {
+		t.Fatalf("raw stdout not truncated after ingest: %v", err)
+	}
+
+	// The partial line is flushed once the instance exits, and the logs
+	// outlive it.
+	launcher.crash("demo")
+	entries := waitFor(vmlogs.Query{}, 3)
+	if entries[2].Line != "partial" {
+		t.Fatalf("last entry = %+v", entries[2])
+	}
+	if future := waitFor(vmlogs.Query{Since: time.Now().Add(time.Hour)}, 0); len(future) != 0 {
+		t.Fatalf("since filter returned %+v", future)
+	}
+
+	if err := engine.DestroyVM(ctx, "demo"); err != nil {
+		t.Fatalf("destroy vm: %v", err)
+	}
+	if _, err := engine.VMLogs(ctx, "demo", vmlogs.Query{}); !errors.Is(err, ErrVMNotFound) {
+		t.Fatalf("expected ErrVMNotFound, got %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(logDir, "demo")); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("logs of deleted vm kept: %v", err)
+	}
+}
+
+func TestVMLogsRotation(t *testing.T) {
+	logs := vmlogs.New(t.TempDir(), vmlogs.Retention{MaxFileBytes: 200, MaxFiles: 2, MaxAge: time.Hour})
+	defer logs.Close()
+	base := time.Now().UTC()
+	for i := 0; i < 20; i++ {
+		entry := vmlogs.Entry{Timestamp: base.Add(time.Duration(i) * time.Second), Source: vmlogs.SourceAgent, Stream: vmlogs.StreamStdout, Line: "line"}
+		if err := logs.Append("demo", entry); err != nil {
+			t.Fatalf("append: %v", err)
+		}
+	}
+	entries, err := logs.Read("demo", vmlogs.Query{})
+	if err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	if len(entries) == 0 || len(entries) >= 20 {
+		t.Fatalf("expected rotation to drop old entries, kept %d", len(entries))
+	}
+	if !entries[len(entries)-1].Timestamp.Equal(base.Add(19 * time.Second)) {
+		t.Fatalf("newest entry lost: %+v", entries[len(entries)-1])
+	}
+	last, err := logs.Read("demo", vmlogs.Query{Limit: 3})
+	if err != nil || len(last) != 3 || !last[2].Timestamp.Equal(entries[len(entries)-1].Timestamp) {
+		t.Fatalf("limit read = %+v, %v", last, err)
+	}
+
+	logs.Prune(time.Now().Add(2 * time.Hour))
+	if entries, _ := logs.Read("demo", vmlogs.Query{}); len(entries) != 0 {
+		t.Fatalf("expected pruned logs, kept %d", len(entries))
+	}
+}
@@ -0,0 +1,342 @@
+// Copyright (c) 2025 HYPR. PTE. LTD.
+//
+// Business Source License 1.1
+// See LICENSE file in the project root for details.
+
+package orchestrator
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/volantvm/volant/internal/server/db"
+	"github.com/volantvm/volant/internal/server/orchestrator/vmconfig"
+)
+
+// Maintenance window scopes. A window scoped to "all" covers every target.
+const (
+	MaintenanceScopeAll        = "all"
+	MaintenanceScopeVM         = "vm"
+	MaintenanceScopeDeployment = "deployment"
+	MaintenanceScopePlugin     = "plugin"
+)
+
+// Control loops that record intents while a maintenance window is open.
+const (
+	MaintenanceSourceDeployment = "deployment"
+	MaintenanceSourceSchedule   = "schedule"
+)
+
+// Outcomes recorded when a held intent is replayed.
+const (
+	IntentReplayed = "replayed"
+	IntentFailed   = "failed"
+	IntentDeferred = "deferred"
+	IntentSkipped  = "skipped"
+)
+
+const maintenanceReplayInterval = 10 * time.Second
+
+// CreateMaintenanceWindowRequest defines a new maintenance window. A zero
+// StartsAt opens the window immediately.
+type CreateMaintenanceWindowRequest struct {
+	Name     string
+	Scope    string
+	Target   string
+	Reason   string
+	StartsAt time.Time
+	EndsAt   time.Time
+}
+
+// MaintenanceTarget names something a maintenance window may cover.
+type MaintenanceTarget struct {
+	Kind string
+	Name string
+}
+
+// MaintenanceWindowOpen reports whether window is in effect at now.
+func MaintenanceWindowOpen(window db.MaintenanceWindow, now time.Time) bool {
+	return !now.Before(window.StartsAt) && now.Before(window.EndsAt)
+}
+
+func maintenanceWindowCovers(window db.MaintenanceWindow, targets []MaintenanceTarget) bool {
+	if window.Scope == MaintenanceScopeAll {
+		return true
+	}
+	for _, target := range targets {
+		if target.Kind == window.Scope && target.Name != "" && target.Name == window.Target {
+			return true
+		}
+	}
+	return false
+}
+
+// DueMaintenanceIntents returns the pending intents from source whose window
+// has closed and which are therefore ready to be replayed.
+func DueMaintenanceIntents(ctx context.Context, store db.Store, source string, now time.Time) ([]db.MaintenanceIntent, error) {
+	repo := store.Queries().Maintenance()
+	pending, err := repo.PendingIntents(ctx, source)
+	if err != nil || len(pending) == 0 {
+		return nil, err
+	}
+	windows, err := repo.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	endsAt := make(map[int64]time.Time, len(windows))
+	for _, window := range windows {
+		endsAt[window.ID] = window.EndsAt
+	}
+	due := make([]db.MaintenanceIntent, 0, len(pending))
+	for _, intent := range pending {
+		if end, ok := endsAt[intent.WindowID]; ok && now.Before(end) {
+			continue
+		}
+		due = append(due, intent)
+	}
+	return due, nil
+}
+
+func (e *engine) CreateMaintenanceWindow(ctx context.Context, req CreateMaintenanceWindowRequest) (*db.MaintenanceWindow, error) {
+	window := db.MaintenanceWindow{
+		Name:     strings.TrimSpace(req.Name),
+		Scope:    strings.ToLower(strings.TrimSpace(req.Scope)),
+		Target:   strings.TrimSpace(req.Target),
+		Reason:   strings.TrimSpace(req.Reason),
+		StartsAt: req.StartsAt.UTC(),
+		EndsAt:   req.EndsAt.UTC(),
+	}
+	if window.Scope == "" {
+		window.Scope = MaintenanceScopeAll
+	}
+	if req.StartsAt.IsZero() {
+		window.StartsAt = time.Now().UTC()
+	}
+	if err := validateMaintenanceWindow(window); err != nil {
+		return nil, err
+	}
+
+	var created *db.MaintenanceWindow
+	err := e.store.WithTx(ctx, func(q db.Queries) error {
+		existing, err := q.Maintenance().GetByName(ctx, window.Name)
+		if err != nil {
+			return err
+		}
+		if existing != nil {
+			return fmt.Errorf("%w: %s", ErrMaintenanceWindowExists, window.Name)
+		}
+		if _, err := q.Maintenance().Create(ctx, &window); err != nil {
+			return err
+		}
+		created, err = q.Maintenance().GetByName(ctx, window.Name)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	e.logger.Info("maintenance window scheduled", "window", created.Name, "scope", created.Scope, "target", created.Target, "starts_at", created.StartsAt, "ends_at", created.EndsAt)
+	return created, nil
+}
+
+func validateMaintenanceWindow(window db.MaintenanceWindow) error {
+	if window.Name == "" {
+		return fmt.Errorf("%w: name is required", ErrInvalidMaintenanceWindow)
+	}
+	switch window.Scope {
+	case MaintenanceScopeAll:
+		if window.Target != "" {
+			return fmt.Errorf("%w: target is not allowed for scope all", ErrInvalidMaintenanceWindow)
+		}
+	case MaintenanceScopeVM, MaintenanceScopeDeployment, MaintenanceScopePlugin:
+		if window.Target == "" {
+			return fmt.Errorf("%w: scope %s requires a target", ErrInvalidMaintenanceWindow, window.Scope)
+		}
+	default:
+		return fmt.Errorf("%w: unknown scope %q", ErrInvalidMaintenanceWindow, window.Scope)
+	}
+	if window.EndsAt.IsZero() || !window.EndsAt.After(window.StartsAt) {
+		return fmt.Errorf("%w: ends_at must be after starts_at", ErrInvalidMaintenanceWindow)
+	}
+	return nil
+}
+
+func (e *engine) ListMaintenanceWindows(ctx context.Context) ([]db.MaintenanceWindow, error) {
+	return e.store.Queries().Maintenance().List(ctx)
+}
+
+func (e *engine) GetMaintenanceWindow(ctx context.Context, name string) (*db.MaintenanceWindow, error) {
+	window, err := e.store.Queries().Maintenance().GetByName(ctx, strings.TrimSpace(name))
+	if err != nil {
+		return nil, err
+	}
+	if window == nil {
+		return nil, fmt.Errorf("%w: %s", ErrMaintenanceWindowNotFound, name)
+	}
+	return window, nil
+}
+
+// MaintenanceIntents lists the actions the window held back, including those
+// already replayed.
+func (e *engine) MaintenanceIntents(ctx context.Context, name string) ([]db.MaintenanceIntent, error) {
+	window, err := e.GetMaintenanceWindow(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	return e.store.Queries().Maintenance().Intents(ctx, window.ID)
+}
+
+// EndMaintenanceWindow closes the window early. Held intents are replayed on
+// the next pass of their control loop.
+func (e *engine) EndMaintenanceWindow(ctx context.Context, name string) (*db.MaintenanceWindow, error) {
+	window, err := e.GetMaintenanceWindow(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now().UTC()
+	if !now.Before(window.EndsAt) {
+		return window, nil
+	}
+	if now.Before(window.StartsAt) {
+		// Ending a window that has not opened yet collapses it to nothing.
+		now = window.StartsAt
+	}
+	if err := e.store.Queries().Maintenance().SetEndsAt(ctx, window.ID, now); err != nil {
+		return nil, err
+	}
+	e.logger.Info("maintenance window ended", "window", window.Name)
+	e.replayMaintenanceIntents(ctx, now)
+	return e.GetMaintenanceWindow(ctx, name)
+}
+
+// DeleteMaintenanceWindow removes a window and its intent history. Open
+// windows and windows with intents still waiting to replay must be ended and
+// drained first so no held action is lost.
+func (e *engine) DeleteMaintenanceWindow(ctx context.Context, name string) error {
+	window, err := e.GetMaintenanceWindow(ctx, name)
+	if err != nil {
+		return err
+	}
+	if MaintenanceWindowOpen(*window, time.Now()) {
+		return fmt.Errorf("%w: %s is open; end it first", ErrMaintenanceWindowActive, window.Name)
+	}
+	intents, err := e.store.Queries().Maintenance().Intents(ctx, window.ID)
+	if err != nil {
+		return err
+	}
+	for _, intent := range intents {
+		if intent.ReplayedAt == nil {
+			return fmt.Errorf("%w: %s still has intents waiting to replay", ErrMaintenanceWindowActive, window.Name)
+		}
+	}
+	return e.store.Queries().Maintenance().Delete(ctx, window.ID)
+}
+
+// HoldForMaintenance records an intent and returns true when an open window
+// covers any of targets; callers skip the action in that case.
+func (e *engine) HoldForMaintenance(ctx context.Context, source, target, action string, targets []MaintenanceTarget) (bool, error) {
+	repo := e.store.Queries().Maintenance()
+	windows, err := repo.List(ctx)
+	if err != nil {
+		return false, err
+	}
+	now := time.Now().UTC()
+	var holder *db.MaintenanceWindow
+	for i := range windows {
+		window := windows[i]
+		if !MaintenanceWindowOpen(window, now) || !maintenanceWindowCovers(window, targets) {
+			continue
+		}
+		// Park the intent on the window that stays open longest.
+		if holder == nil || window.EndsAt.After(holder.EndsAt) {
+			holder = &windows[i]
+		}
+	}
+	if holder == nil {
+		return false, nil
+	}
+	if err := repo.RecordIntent(ctx, db.MaintenanceIntent{
+		WindowID:   holder.ID,
+		Source:     source,
+		Target:     target,
+		Action:     action,
+		RecordedAt: now,
+	}); err != nil {
+		return false, err
+	}
+	e.logger.Info("action held for maintenance", "window", holder.Name, "source", source, "target", target, "action", action)
+	return true, nil
+}
+
+// reconcileOrHold queues a reconcile of the deployment unless a maintenance
+// window covering it is open, in which case the reconcile is recorded for
+// replay.
+func (e *engine) reconcileOrHold(ctx context.Context, groupID int64) (bool, error) {
+	group, err := e.store.Queries().VMGroups().GetByID(ctx, groupID)
+	if err != nil {
+		return false, err
+	}
+	if group == nil {
+		return false, fmt.Errorf("%w: id=%d", ErrDeploymentNotFound, groupID)
+	}
+	targets := []MaintenanceTarget{{Kind: MaintenanceScopeDeployment, Name: group.Name}}
+	if cfg, err := vmconfig.Unmarshal(group.ConfigJSON); err == nil {
+		targets = append(targets, MaintenanceTarget{Kind: MaintenanceScopePlugin, Name: cfg.Plugin})
+	}
+	held, err := e.HoldForMaintenance(ctx, MaintenanceSourceDeployment, group.Name, "reconcile", targets)
+	if err != nil || held {
+		return held, err
+	}
+	e.enqueueReconcile(group.ID)
+	return false, nil
+}
+
+// runMaintenanceReplay periodically replays deployment reconciles held by
+// windows that have since closed, and finishes restarting VMs stopped for a
+// host maintenance that has been exited.
+func (e *engine) runMaintenanceReplay(ctx context.Context) {
+	ticker := time.NewTicker(maintenanceReplayInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			e.replayMaintenanceIntents(ctx, now)
+			e.restoreHostMaintenance(ctx, DefaultMaintenanceConcurrency)
+		}
+	}
+}
+
+func (e *engine) replayMaintenanceIntents(ctx context.Context, now time.Time) {
+	repo := e.store.Queries().Maintenance()
+	due, err := DueMaintenanceIntents(ctx, e.store, MaintenanceSourceDeployment, now)
+	if err != nil {
+		e.logger.Error("list held maintenance intents", "error", err)
+		return
+	}
+	for _, intent := range due {
+		outcome := IntentReplayed
+		group, err := e.store.Queries().VMGroups().GetByName(ctx, intent.Target)
+		switch {
+		case err != nil:
+			e.logger.Error("replay held reconcile", "deployment", intent.Target, "error", err)
+			continue
+		case group == nil:
+			outcome = IntentSkipped + ": deployment removed"
+		default:
+			held, recErr := e.reconcileOrHold(ctx, group.ID)
+			if recErr != nil {
+				outcome = IntentFailed + ": " + recErr.Error()
+			} else if held {
+				outcome = IntentDeferred
+			}
+		}
+		if err := repo.MarkReplayed(ctx, intent.ID, outcome, now); err != nil {
+			e.logger.Error("mark maintenance intent replayed", "deployment", intent.Target, "error", err)
+			continue
+		}
+		e.logger.Info("replayed held reconcile", "deployment", intent.Target, "outcome", outcome)
+	}
+}
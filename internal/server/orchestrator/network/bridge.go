@@ -13,7 +13,10 @@ import (
 	"encoding/hex"
 	"fmt"
 	"net"
+	"os"
+	"path/filepath"
 	"strings"
+	"sync"
 
 	"github.com/vishvananda/netlink"
 )
@@ -21,19 +24,29 @@ import (
 // BridgeManager provisions tap devices and attaches them to a Linux bridge.
 type BridgeManager struct {
 	BridgeName string
+	// ProxyInterface is the uplink on which guest IPv6 addresses are
+	// published via NDP proxy. Empty disables neighbor proxying.
+	ProxyInterface string
+
+	// poolMu guards the spare taps; fillMu serializes topping them up.
+	poolMu   sync.Mutex
+	fillMu   sync.Mutex
+	pool     []string
+	poolSize int
+	poolSeq  int
 }
 
 // NewBridgeManager constructs a bridge-backed network manager.
-func NewBridgeManager(bridge string) *BridgeManager {
-	return &BridgeManager{BridgeName: bridge}
+func NewBridgeManager(bridge, proxyIface string) *BridgeManager {
+	return &BridgeManager{BridgeName: bridge, ProxyInterface: proxyIface}
 }
 
 // ensureBridge ensures the bridge device exists and is up.
-func (b *BridgeManager) ensureBridge(ctx context.Context) error {
+func (b *BridgeManager) ensureBridge(ctx context.Context, name string) error {
 	// Get bridge link by name
-	link, err := netlink.LinkByName(b.BridgeName)
+	link, err := netlink.LinkByName(name)
 	if err != nil {
-		return fmt.Errorf("bridge %s not present: %w", b.BridgeName, err)
+		return fmt.Errorf("bridge %s not present: %w", name, err)
 	}
 
 	// Bring bridge up if not already
@@ -46,11 +59,24 @@ func (b *BridgeManager) ensureBridge(ctx context.Context) error {
 	return nil
 }
 
-// PrepareTap creates a tap device, attaches it to the bridge, and brings it up.
+// PrepareTap creates a tap device, attaches it to the bridge, and brings it
+// up. A spare tap from the pool is used when one is available.
 func (b *BridgeManager) PrepareTap(ctx context.Context, vmName, mac string) (string, error) {
 	tap := tapNameFrom(vmName)
+	if b.claimPooledTap(tap, mac) {
+		return tap, nil
+	}
+	return b.attachTap(ctx, tap, b.BridgeName, mac)
+}
 
-	if err := b.ensureBridge(ctx); err != nil {
+// PrepareInterface creates the tap for the VM's additional NIC number index
+// and attaches it to bridge.
+func (b *BridgeManager) PrepareInterface(ctx context.Context, vmName string, index int, bridge, mac string) (string, error) {
+	return b.attachTap(ctx, interfaceTapName(vmName, index), bridge, mac)
+}
+
+func (b *BridgeManager) attachTap(ctx context.Context, tap, bridgeName, mac string) (string, error) {
+	if err := b.ensureBridge(ctx, bridgeName); err != nil {
 		return "", err
 	}
 
@@ -84,7 +110,7 @@ func (b *BridgeManager) PrepareTap(ctx context.Context, vmName, mac string) (str
 	}
 
 	// Get bridge link
-	bridge, err := netlink.LinkByName(b.BridgeName)
+	bridge, err := netlink.LinkByName(bridgeName)
 	if err != nil {
 		_ = netlink.LinkDel(tuntap)
 		return "", fmt.Errorf("get bridge link: %w", err)
@@ -105,24 +131,101 @@ func (b *BridgeManager) PrepareTap(ctx context.Context, vmName, mac string) (str
 	return tap, nil
 }
 
-// CleanupTap detaches and deletes the tap device.
+// CleanupTap detaches and deletes the tap device and drops its egress policy.
 func (b *BridgeManager) CleanupTap(ctx context.Context, tap string) error {
 	link, err := netlink.LinkByName(tap)
+	if err == nil {
+		// Bring tap down
+		if err := netlink.LinkSetDown(link); err != nil {
+			return fmt.Errorf("tap down: %w", err)
+		}
+
+		// Delete tap
+		if err := netlink.LinkDel(link); err != nil {
+			return fmt.Errorf("delete tap: %w", err)
+		}
+	}
+
+	if tap == "" {
+		return nil
+	}
+	return b.RemoveEgressPolicy(ctx, tap)
+}
+
+// ListTaps returns the tap devices named like the ones PrepareTap and
+// PrepareInterface create.
+func (b *BridgeManager) ListTaps(ctx context.Context) ([]string, error) {
+	links, err := netlink.LinkList()
 	if err != nil {
-		// Already gone, consider it cleaned up
+		return nil, fmt.Errorf("list links: %w", err)
+	}
+	var taps []string
+	for _, link := range links {
+		if _, ok := link.(*netlink.Tuntap); !ok {
+			continue
+		}
+		if name := link.Attrs().Name; isVolantTap(name) {
+			taps = append(taps, name)
+		}
+	}
+	return taps, nil
+}
+
+// AddNeighborProxy publishes ip on the proxy interface so the upstream router
+// can resolve guest addresses. It is a no-op without a proxy interface.
+func (b *BridgeManager) AddNeighborProxy(ctx context.Context, ip net.IP) error {
+	if b.ProxyInterface == "" {
 		return nil
 	}
+	link, err := netlink.LinkByName(b.ProxyInterface)
+	if err != nil {
+		return fmt.Errorf("proxy interface %s not present: %w", b.ProxyInterface, err)
+	}
+	if err := enableNDPProxy(b.ProxyInterface); err != nil {
+		return err
+	}
+	if err := netlink.NeighSet(proxyNeigh(link, ip)); err != nil {
+		return fmt.Errorf("add ndp proxy %s: %w", ip, err)
+	}
+	return nil
+}
 
-	// Bring tap down
-	if err := netlink.LinkSetDown(link); err != nil {
-		return fmt.Errorf("tap down: %w", err)
+// RemoveNeighborProxy withdraws ip from the proxy interface.
+func (b *BridgeManager) RemoveNeighborProxy(ctx context.Context, ip net.IP) error {
+	if b.ProxyInterface == "" {
+		return nil
+	}
+	link, err := netlink.LinkByName(b.ProxyInterface)
+	if err != nil {
+		// Interface gone, nothing left to withdraw
+		return nil
 	}
+	if err := netlink.NeighDel(proxyNeigh(link, ip)); err != nil {
+		return fmt.Errorf("remove ndp proxy %s: %w", ip, err)
+	}
+	return nil
+}
 
-	// Delete tap
-	if err := netlink.LinkDel(link); err != nil {
-		return fmt.Errorf("delete tap: %w", err)
+func proxyNeigh(link netlink.Link, ip net.IP) *netlink.Neigh {
+	return &netlink.Neigh{
+		LinkIndex: link.Attrs().Index,
+		Family:    netlink.FAMILY_V6,
+		Flags:     netlink.NTF_PROXY,
+		IP:        ip,
 	}
+}
 
+// enableNDPProxy turns on neighbor proxying for iface and IPv6 forwarding.
+func enableNDPProxy(iface string) error {
+	settings := map[string]string{
+		filepath.Join("/proc/sys/net/ipv6/conf", iface, "proxy_ndp"): "1",
+		"/proc/sys/net/ipv6/conf/all/forwarding":                     "1",
+	}
+	for path, value := range settings {
+		if err := os.WriteFile(path, []byte(value), 0o644); err != nil {
+			return fmt.Errorf("write %s: %w", path, err)
+		}
+	}
 	return nil
 }
 
@@ -132,6 +235,15 @@ const (
 )
 
 func tapNameFrom(vmName string) string {
+	return tapNameWithPrefix(tapPrefix, vmName)
+}
+
+// interfaceTapName names the tap of an additional NIC, e.g. "vtn1-web".
+func interfaceTapName(vmName string, index int) string {
+	return tapNameWithPrefix(fmt.Sprintf("vtn%d-", index), vmName)
+}
+
+func tapNameWithPrefix(tapPrefix, vmName string) string {
 	sanitized := sanitize(vmName)
 	if sanitized == "" {
 		sanitized = "vm"
@@ -169,6 +281,28 @@ func tapNameFrom(vmName string) string {
 	return tapPrefix + prefix + hashStr
 }
 
+// isVolantTap reports whether name has the prefix of a primary ("vttap-")
+// or additional ("vtnN-") tap.
+func isVolantTap(name string) bool {
+	if strings.HasPrefix(name, tapPrefix) {
+		return true
+	}
+	rest, ok := strings.CutPrefix(name, "vtn")
+	if !ok {
+		return false
+	}
+	index, _, ok := strings.Cut(rest, "-")
+	if !ok || index == "" {
+		return false
+	}
+	for _, r := range index {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
 func sanitize(input string) string {
 	var b strings.Builder
 	for _, r := range input {
@@ -8,7 +8,7 @@ package network
 
 // NewBridgeManager returns a no-op manager on non-Linux hosts so that
 // non-Linux builds can compile without Linux-specific netlink symbols.
-func NewBridgeManager(bridge string) Manager { // bridge kept for API symmetry
-	_ = bridge
+func NewBridgeManager(bridge, proxyIface string) Manager { // arguments kept for API symmetry
+	_, _ = bridge, proxyIface
 	return NewNoop()
 }
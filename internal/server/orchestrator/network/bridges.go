@@ -0,0 +1,98 @@
+// Copyright (c) 2025 HYPR. PTE. LTD.
+//
+// Business Source License 1.1
+// See LICENSE file in the project root for details.
+//go:build linux
+// +build linux
+
+package network
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+
+	"github.com/vishvananda/netlink"
+)
+
+// EnsureBridge creates the bridge if needed, assigns the gateway address and
+// brings it up. It is idempotent so networks can be restored at startup.
+func (b *BridgeManager) EnsureBridge(ctx context.Context, name string, gateway *net.IPNet, nat bool) error {
+	link, err := netlink.LinkByName(name)
+	if err != nil {
+		var notFound netlink.LinkNotFoundError
+		if !errors.As(err, &notFound) {
+			return fmt.Errorf("lookup bridge %s: %w", name, err)
+		}
+		bridge := &netlink.Bridge{LinkAttrs: netlink.LinkAttrs{Name: name}}
+		if err := netlink.LinkAdd(bridge); err != nil {
+			return fmt.Errorf("create bridge %s: %w", name, err)
+		}
+		if link, err = netlink.LinkByName(name); err != nil {
+			return fmt.Errorf("lookup bridge %s: %w", name, err)
+		}
+	}
+	if _, ok := link.(*netlink.Bridge); !ok {
+		return fmt.Errorf("%s exists and is not a bridge", name)
+	}
+	if err := netlink.AddrReplace(link, &netlink.Addr{IPNet: gateway}); err != nil {
+		return fmt.Errorf("assign %s to %s: %w", gateway, name, err)
+	}
+	if err := netlink.LinkSetUp(link); err != nil {
+		return fmt.Errorf("bring bridge %s up: %w", name, err)
+	}
+
+	for _, spec := range bridgeRules(name, gateway, nat) {
+		check := append([]string{"-t", spec.table, "-C"}, spec.args...)
+		if runIptables(ctx, gateway.IP, check) == nil {
+			continue
+		}
+		add := append([]string{"-t", spec.table, "-A"}, spec.args...)
+		if err := runIptables(ctx, gateway.IP, add); err != nil {
+			return fmt.Errorf("configure bridge %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// DeleteBridge removes the rules installed by EnsureBridge and the bridge
+// itself. A missing bridge is not an error.
+func (b *BridgeManager) DeleteBridge(ctx context.Context, name string, gateway *net.IPNet, nat bool) error {
+	var errs []error
+	for _, spec := range bridgeRules(name, gateway, nat) {
+		check := append([]string{"-t", spec.table, "-C"}, spec.args...)
+		if runIptables(ctx, gateway.IP, check) != nil {
+			continue
+		}
+		del := append([]string{"-t", spec.table, "-D"}, spec.args...)
+		if err := runIptables(ctx, gateway.IP, del); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	link, err := netlink.LinkByName(name)
+	if err == nil {
+		if err := netlink.LinkDel(link); err != nil {
+			errs = append(errs, fmt.Errorf("delete bridge %s: %w", name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// bridgeRules lets guests on the bridge reach each other and, unless the
+// network is internal, the outside world through the host.
+func bridgeRules(name string, gateway *net.IPNet, nat bool) []iptablesRule {
+	comment := []string{"-m", "comment", "--comment", "volant:net:" + name}
+	subnet := &net.IPNet{IP: gateway.IP.Mask(gateway.Mask), Mask: gateway.Mask}
+	rules := []iptablesRule{
+		{table: "filter", args: append([]string{"FORWARD", "-i", name, "-o", name, "-j", "ACCEPT"}, comment...)},
+	}
+	if nat {
+		rules = append(rules,
+			iptablesRule{table: "nat", args: append([]string{"POSTROUTING", "-s", subnet.String(), "!", "-o", name, "-j", "MASQUERADE"}, comment...)},
+			iptablesRule{table: "filter", args: append([]string{"FORWARD", "-i", name, "!", "-o", name, "-j", "ACCEPT"}, comment...)},
+			iptablesRule{table: "filter", args: append([]string{"FORWARD", "-o", name, "-m", "conntrack", "--ctstate", "RELATED,ESTABLISHED", "-j", "ACCEPT"}, comment...)},
+		)
+	}
+	return rules
+}
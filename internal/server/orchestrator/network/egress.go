@@ -0,0 +1,92 @@
+// Copyright (c) 2025 HYPR. PTE. LTD.
+//
+// Business Source License 1.1
+// See LICENSE file in the project root for details.
+
+package network
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// egressTable holds the forward hook and one chain per filtered tap. The hook
+// dispatches on the ingress tap through the egressTaps verdict map, so traffic
+// from unfiltered taps and to host-local services is never touched.
+const (
+	egressTable = "volant_egress"
+	egressTaps  = "taps"
+)
+
+func egressChain(tap string) string {
+	return "tap_" + strings.ReplaceAll(tap, "-", "_")
+}
+
+// egressScript renders an nft script that (re)installs the policy for tap.
+func egressScript(tap string, rules []EgressRule, defaultDeny bool) string {
+	chain := egressChain(tap)
+	var b strings.Builder
+	fmt.Fprintf(&b, "add table inet %s\n", egressTable)
+	fmt.Fprintf(&b, "add map inet %s %s { type ifname : verdict; }\n", egressTable, egressTaps)
+	fmt.Fprintf(&b, "add chain inet %s forward { type filter hook forward priority 0; policy accept; }\n", egressTable)
+	fmt.Fprintf(&b, "flush chain inet %s forward\n", egressTable)
+	fmt.Fprintf(&b, "add rule inet %s forward iifname vmap @%s\n", egressTable, egressTaps)
+	fmt.Fprintf(&b, "add chain inet %s %s\n", egressTable, chain)
+	fmt.Fprintf(&b, "flush chain inet %s %s\n", egressTable, chain)
+	fmt.Fprintf(&b, "add rule inet %s %s ct state established,related accept\n", egressTable, chain)
+	for _, rule := range rules {
+		for _, match := range egressMatches(rule) {
+			verdict := "drop"
+			if rule.Allow {
+				verdict = "accept"
+			}
+			fmt.Fprintf(&b, "add rule inet %s %s %s%s\n", egressTable, chain, match, verdict)
+		}
+	}
+	if defaultDeny {
+		fmt.Fprintf(&b, "add rule inet %s %s drop\n", egressTable, chain)
+	}
+	fmt.Fprintf(&b, "add element inet %s %s { \"%s\" : jump %s }\n", egressTable, egressTaps, tap, chain)
+	return b.String()
+}
+
+// egressMatches returns the match expressions for rule, one per address
+// family it targets. Each ends with a space so the verdict can be appended.
+func egressMatches(rule EgressRule) []string {
+	ports := ""
+	if len(rule.Ports) > 0 {
+		values := make([]string, 0, len(rule.Ports))
+		for _, port := range rule.Ports {
+			values = append(values, strconv.Itoa(port))
+		}
+		set := "{ " + strings.Join(values, ", ") + " }"
+		if rule.Proto != "" {
+			ports = fmt.Sprintf("%s dport %s ", rule.Proto, set)
+		} else {
+			ports = fmt.Sprintf("meta l4proto { tcp, udp } th dport %s ", set)
+		}
+	} else if rule.Proto != "" {
+		ports = fmt.Sprintf("meta l4proto %s ", rule.Proto)
+	}
+
+	if len(rule.Networks) == 0 {
+		return []string{ports}
+	}
+	var v4, v6 []string
+	for _, network := range rule.Networks {
+		if network.IP.To4() != nil {
+			v4 = append(v4, network.String())
+		} else {
+			v6 = append(v6, network.String())
+		}
+	}
+	var matches []string
+	if len(v4) > 0 {
+		matches = append(matches, "ip daddr { "+strings.Join(v4, ", ")+" } "+ports)
+	}
+	if len(v6) > 0 {
+		matches = append(matches, "ip6 daddr { "+strings.Join(v6, ", ")+" } "+ports)
+	}
+	return matches
+}
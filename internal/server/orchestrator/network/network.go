@@ -5,10 +5,82 @@
 
 package network
 
-import "context"
+import (
+	"context"
+	"net"
+)
 
 // Manager prepares host networking resources (tap devices, bridge attachments) for microVMs.
 type Manager interface {
 	PrepareTap(ctx context.Context, vmName, mac string) (string, error)
 	CleanupTap(ctx context.Context, tapName string) error
 }
+
+// TapLister is implemented by managers that can enumerate the tap devices
+// they created, so taps left behind by a previous volantd can be removed.
+type TapLister interface {
+	ListTaps(ctx context.Context) ([]string, error)
+}
+
+// TapPool is implemented by managers that can create primary tap devices
+// ahead of time, so PrepareTap renames a spare tap instead of creating one.
+// FillTapPool keeps size spares, adopting the ones a previous volantd left;
+// zero removes them all.
+type TapPool interface {
+	FillTapPool(ctx context.Context, size int) error
+}
+
+// NeighborProxy is implemented by managers that can answer IPv6 neighbor
+// solicitations for guest addresses on the host uplink (NDP proxy), so routed
+// IPv6 prefixes reach guests without NAT.
+type NeighborProxy interface {
+	AddNeighborProxy(ctx context.Context, ip net.IP) error
+	RemoveNeighborProxy(ctx context.Context, ip net.IP) error
+}
+
+// PortForward publishes a host port on a guest address.
+type PortForward struct {
+	// VMName tags the rules so they can be traced back to the VM.
+	VMName    string
+	Proto     string
+	HostPort  int
+	GuestIP   net.IP
+	GuestPort int
+}
+
+// PortForwarder is implemented by managers that can program host DNAT rules
+// for published VM ports.
+type PortForwarder interface {
+	AddPortForward(ctx context.Context, rule PortForward) error
+	RemovePortForward(ctx context.Context, rule PortForward) error
+}
+
+// EgressRule is a resolved egress match. Empty Networks matches any
+// destination; empty Proto with Ports matches both tcp and udp.
+type EgressRule struct {
+	Allow    bool
+	Networks []*net.IPNet
+	Ports    []int
+	Proto    string
+}
+
+// EgressFirewall is implemented by managers that can filter traffic leaving a
+// VM's tap device. Policies are removed together with the tap.
+type EgressFirewall interface {
+	ApplyEgressPolicy(ctx context.Context, tap string, rules []EgressRule, defaultDeny bool) error
+	RemoveEgressPolicy(ctx context.Context, tap string) error
+}
+
+// InterfaceAttacher is implemented by managers that can attach additional NICs
+// to bridges other than the default one. Taps are removed with CleanupTap.
+type InterfaceAttacher interface {
+	PrepareInterface(ctx context.Context, vmName string, index int, bridge, mac string) (string, error)
+}
+
+// BridgeProvisioner is implemented by managers that can create and remove the
+// bridges backing user-defined networks. gateway is the host address on the
+// bridge in CIDR form; nat enables outbound masquerading for the subnet.
+type BridgeProvisioner interface {
+	EnsureBridge(ctx context.Context, name string, gateway *net.IPNet, nat bool) error
+	DeleteBridge(ctx context.Context, name string, gateway *net.IPNet, nat bool) error
+}
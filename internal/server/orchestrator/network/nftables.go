@@ -0,0 +1,42 @@
+// Copyright (c) 2025 HYPR. PTE. LTD.
+//
+// Business Source License 1.1
+// See LICENSE file in the project root for details.
+//go:build linux
+// +build linux
+
+package network
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// ApplyEgressPolicy installs or replaces the nftables egress policy for tap.
+func (b *BridgeManager) ApplyEgressPolicy(ctx context.Context, tap string, rules []EgressRule, defaultDeny bool) error {
+	cmd := exec.CommandContext(ctx, "nft", "-f", "-")
+	cmd.Stdin = strings.NewReader(egressScript(tap, rules, defaultDeny))
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("apply egress policy for %s: %w: %s", tap, err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// RemoveEgressPolicy detaches and deletes the policy chain for tap. Missing
+// policies are ignored.
+func (b *BridgeManager) RemoveEgressPolicy(ctx context.Context, tap string) error {
+	chain := egressChain(tap)
+	list := exec.CommandContext(ctx, "nft", "list", "chain", "inet", egressTable, chain)
+	if err := list.Run(); err != nil {
+		return nil
+	}
+	script := fmt.Sprintf("delete element inet %s %s { \"%s\" }\ndelete chain inet %s %s\n", egressTable, egressTaps, tap, egressTable, chain)
+	cmd := exec.CommandContext(ctx, "nft", "-f", "-")
+	cmd.Stdin = strings.NewReader(script)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("remove egress policy for %s: %w: %s", tap, err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
@@ -8,6 +8,7 @@ package network
 import (
 	"context"
 	"fmt"
+	"net"
 	"regexp"
 )
 
@@ -29,6 +30,26 @@ func (n *NoopManager) PrepareTap(ctx context.Context, vmName, mac string) (strin
 	return fmt.Sprintf("volar-tap-%s", sanitized), nil
 }
 
+// PrepareInterface returns a sanitized tap name for an additional NIC.
+func (n *NoopManager) PrepareInterface(ctx context.Context, vmName string, index int, bridge, mac string) (string, error) {
+	_ = ctx
+	sanitized := nonAlnum.ReplaceAllString(vmName, "")
+	if sanitized == "" {
+		sanitized = "vm"
+	}
+	return fmt.Sprintf("volar-tap%d-%s", index, sanitized), nil
+}
+
+// EnsureBridge is a no-op.
+func (n *NoopManager) EnsureBridge(ctx context.Context, name string, gateway *net.IPNet, nat bool) error {
+	return nil
+}
+
+// DeleteBridge is a no-op.
+func (n *NoopManager) DeleteBridge(ctx context.Context, name string, gateway *net.IPNet, nat bool) error {
+	return nil
+}
+
 // CleanupTap is a no-op for the development manager.
 func (n *NoopManager) CleanupTap(ctx context.Context, tapName string) error {
 	_ = ctx
@@ -0,0 +1,91 @@
+// Copyright (c) 2025 HYPR. PTE. LTD.
+//
+// Business Source License 1.1
+// See LICENSE file in the project root for details.
+//go:build linux
+// +build linux
+
+package network
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// AddPortForward installs DNAT rules sending host traffic on rule.HostPort to
+// the guest, for both external (PREROUTING) and host-local (OUTPUT) clients,
+// and accepts the forwarded flow.
+func (b *BridgeManager) AddPortForward(ctx context.Context, rule PortForward) error {
+	for _, spec := range portForwardRules(rule) {
+		check := append([]string{"-t", spec.table, "-C"}, spec.args...)
+		if runIptables(ctx, rule.GuestIP, check) == nil {
+			continue
+		}
+		add := append([]string{"-t", spec.table, "-A"}, spec.args...)
+		if err := runIptables(ctx, rule.GuestIP, add); err != nil {
+			_ = b.RemovePortForward(ctx, rule)
+			return fmt.Errorf("add port forward %d/%s: %w", rule.HostPort, rule.Proto, err)
+		}
+	}
+	return nil
+}
+
+// RemovePortForward deletes the rules installed by AddPortForward. Missing
+// rules are ignored.
+func (b *BridgeManager) RemovePortForward(ctx context.Context, rule PortForward) error {
+	var firstErr error
+	for _, spec := range portForwardRules(rule) {
+		check := append([]string{"-t", spec.table, "-C"}, spec.args...)
+		if runIptables(ctx, rule.GuestIP, check) != nil {
+			continue
+		}
+		del := append([]string{"-t", spec.table, "-D"}, spec.args...)
+		if err := runIptables(ctx, rule.GuestIP, del); err != nil && firstErr == nil {
+			firstErr = fmt.Errorf("remove port forward %d/%s: %w", rule.HostPort, rule.Proto, err)
+		}
+	}
+	return firstErr
+}
+
+type iptablesRule struct {
+	table string
+	args  []string
+}
+
+func portForwardRules(rule PortForward) []iptablesRule {
+	proto := strings.ToLower(rule.Proto)
+	hostPort := strconv.Itoa(rule.HostPort)
+	guestPort := strconv.Itoa(rule.GuestPort)
+	destination := net.JoinHostPort(rule.GuestIP.String(), guestPort)
+	comment := []string{"-m", "comment", "--comment", "volant:" + rule.VMName}
+
+	dnat := []string{"-p", proto, "--dport", hostPort, "-m", "addrtype", "--dst-type", "LOCAL"}
+	dnat = append(dnat, comment...)
+	dnat = append(dnat, "-j", "DNAT", "--to-destination", destination)
+
+	forward := []string{"FORWARD", "-p", proto, "-d", rule.GuestIP.String(), "--dport", guestPort}
+	forward = append(forward, comment...)
+	forward = append(forward, "-j", "ACCEPT")
+
+	return []iptablesRule{
+		{table: "nat", args: append([]string{"PREROUTING"}, dnat...)},
+		{table: "nat", args: append([]string{"OUTPUT"}, dnat...)},
+		{table: "filter", args: forward},
+	}
+}
+
+func runIptables(ctx context.Context, guest net.IP, args []string) error {
+	binary := "iptables"
+	if guest.To4() == nil {
+		binary = "ip6tables"
+	}
+	out, err := exec.CommandContext(ctx, binary, append([]string{"-w"}, args...)...).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("%s %s: %w: %s", binary, strings.Join(args, " "), err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
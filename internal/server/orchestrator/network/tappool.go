@@ -0,0 +1,194 @@
+// Copyright (c) 2025 HYPR. PTE. LTD.
+//
+// Business Source License 1.1
+// See LICENSE file in the project root for details.
+//go:build linux
+// +build linux
+
+package network
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"syscall"
+
+	"github.com/vishvananda/netlink"
+)
+
+// poolTapPrefix names spare taps. It is not a VM tap prefix, so ListTaps and
+// the sweeps built on it leave spares alone.
+const poolTapPrefix = "vtpool-"
+
+// FillTapPool keeps size spare taps attached to the default bridge and down.
+// Claiming one for a VM only renames it, sets its MAC and brings it up,
+// and the pool is topped up again in the background.
+func (b *BridgeManager) FillTapPool(ctx context.Context, size int) error {
+	if size < 0 {
+		size = 0
+	}
+	b.poolMu.Lock()
+	b.poolSize = size
+	b.poolMu.Unlock()
+	if err := b.adoptPooledTaps(); err != nil {
+		return err
+	}
+	return b.fillTapPool(ctx)
+}
+
+// adoptPooledTaps takes over the spares a previous volantd left behind.
+func (b *BridgeManager) adoptPooledTaps() error {
+	links, err := netlink.LinkList()
+	if err != nil {
+		return fmt.Errorf("list links: %w", err)
+	}
+	b.poolMu.Lock()
+	defer b.poolMu.Unlock()
+	known := make(map[string]bool, len(b.pool))
+	for _, name := range b.pool {
+		known[name] = true
+	}
+	for _, link := range links {
+		if _, ok := link.(*netlink.Tuntap); !ok {
+			continue
+		}
+		name := link.Attrs().Name
+		seq, ok := poolTapSeq(name)
+		if !ok || known[name] {
+			continue
+		}
+		b.pool = append(b.pool, name)
+		b.poolSeq = max(b.poolSeq, seq+1)
+	}
+	return nil
+}
+
+// fillTapPool creates or removes spares until the pool holds poolSize.
+func (b *BridgeManager) fillTapPool(ctx context.Context) error {
+	b.fillMu.Lock()
+	defer b.fillMu.Unlock()
+	for {
+		b.poolMu.Lock()
+		var name string
+		switch {
+		case len(b.pool) > b.poolSize:
+			name = b.pool[len(b.pool)-1]
+			b.pool = b.pool[:len(b.pool)-1]
+			b.poolMu.Unlock()
+			if link, err := netlink.LinkByName(name); err == nil {
+				_ = netlink.LinkDel(link)
+			}
+			continue
+		case len(b.pool) == b.poolSize:
+			b.poolMu.Unlock()
+			return nil
+		}
+		name = fmt.Sprintf("%s%d", poolTapPrefix, b.poolSeq)
+		b.poolSeq++
+		b.poolMu.Unlock()
+
+		if err := b.createPooledTap(ctx, name); err != nil {
+			if errors.Is(err, syscall.EEXIST) {
+				continue
+			}
+			return err
+		}
+		b.poolMu.Lock()
+		b.pool = append(b.pool, name)
+		b.poolMu.Unlock()
+	}
+}
+
+func (b *BridgeManager) createPooledTap(ctx context.Context, name string) error {
+	if err := b.ensureBridge(ctx, b.BridgeName); err != nil {
+		return err
+	}
+	bridge, err := netlink.LinkByName(b.BridgeName)
+	if err != nil {
+		return fmt.Errorf("get bridge link: %w", err)
+	}
+	la := netlink.NewLinkAttrs()
+	la.Name = name
+	tuntap := &netlink.Tuntap{
+		LinkAttrs: la,
+		Mode:      netlink.TUNTAP_MODE_TAP,
+		Flags:     netlink.TUNTAP_DEFAULTS | netlink.TUNTAP_VNET_HDR,
+	}
+	if err := netlink.LinkAdd(tuntap); err != nil {
+		return fmt.Errorf("create tap %s: %w", name, err)
+	}
+	if err := netlink.LinkSetMaster(tuntap, bridge); err != nil {
+		_ = netlink.LinkDel(tuntap)
+		return fmt.Errorf("attach tap to bridge: %w", err)
+	}
+	return nil
+}
+
+// claimPooledTap turns a spare into tap with mac. It reports false when no
+// spare is available or the spare could not be set up, in which case the
+// caller creates the tap itself.
+func (b *BridgeManager) claimPooledTap(tap, mac string) bool {
+	b.poolMu.Lock()
+	if len(b.pool) == 0 {
+		b.poolMu.Unlock()
+		return false
+	}
+	name := b.pool[0]
+	b.pool = b.pool[1:]
+	b.poolMu.Unlock()
+	go func() { _ = b.fillTapPool(context.Background()) }()
+
+	hwAddr, err := net.ParseMAC(mac)
+	if err != nil {
+		return false
+	}
+	link, err := netlink.LinkByName(name)
+	if err != nil {
+		return false
+	}
+	if existing, err := netlink.LinkByName(tap); err == nil {
+		_ = netlink.LinkSetDown(existing)
+		_ = netlink.LinkDel(existing)
+	}
+	if err := setupPooledTap(link, tap, hwAddr, b.BridgeName); err != nil {
+		_ = netlink.LinkDel(link)
+		return false
+	}
+	return true
+}
+
+func setupPooledTap(link netlink.Link, tap string, hwAddr net.HardwareAddr, bridgeName string) error {
+	if err := netlink.LinkSetDown(link); err != nil {
+		return err
+	}
+	if err := netlink.LinkSetName(link, tap); err != nil {
+		return err
+	}
+	if err := netlink.LinkSetHardwareAddr(link, hwAddr); err != nil {
+		return err
+	}
+	// The bridge may have been recreated since the spare was attached.
+	bridge, err := netlink.LinkByName(bridgeName)
+	if err != nil {
+		return err
+	}
+	if link.Attrs().MasterIndex != bridge.Attrs().Index {
+		if err := netlink.LinkSetMaster(link, bridge); err != nil {
+			return err
+		}
+	}
+	return netlink.LinkSetUp(link)
+}
+
+// poolTapSeq returns the sequence number of a spare tap name.
+func poolTapSeq(name string) (int, bool) {
+	rest, ok := strings.CutPrefix(name, poolTapPrefix)
+	if !ok {
+		return 0, false
+	}
+	seq, err := strconv.Atoi(rest)
+	return seq, err == nil && seq >= 0
+}
@@ -6,6 +6,8 @@
 package orchestrator
 
 import (
+	"context"
+	"net"
 	"testing"
 
 	"github.com/volantvm/volant/internal/pluginspec"
@@ -212,3 +214,77 @@ func TestNeedsTapDevice(t *testing.T) {
 		})
 	}
 }
+
+func TestDeriveIPPoolIPv6(t *testing.T) {
+	_, subnet, err := net.ParseCIDR("fd00:7::/120")
+	if err != nil {
+		t.Fatalf("parse subnet: %v", err)
+	}
+	pool, err := deriveIPPool(subnet, net.ParseIP("fd00:7::1"))
+	if err != nil {
+		t.Fatalf("deriveIPPool: %v", err)
+	}
+	if len(pool) != 254 {
+		t.Fatalf("expected 254 addresses, got %d", len(pool))
+	}
+	if pool[0] != "fd00:7::2" || pool[len(pool)-1] != "fd00:7::ff" {
+		t.Fatalf("unexpected pool bounds %s..%s", pool[0], pool[len(pool)-1])
+	}
+
+	_, wide, _ := net.ParseCIDR("fd00:7::/64")
+	pool, err = deriveIPPool(wide, net.ParseIP("fd00:7::1"))
+	if err != nil {
+		t.Fatalf("deriveIPPool /64: %v", err)
+	}
+	if len(pool) != maxIPv6PoolSize {
+		t.Fatalf("expected pool capped at %d, got %d", maxIPv6PoolSize, len(pool))
+	}
+}
+
+func TestResolveEgressRules(t *testing.T) {
+	rules, err := resolveEgressRules(context.Background(), []pluginspec.EgressRule{
+		{Action: "allow", CIDRs: []string{"10.20.0.0/16", "2001:db8::1"}, Ports: []int{443}, Proto: "TCP"},
+		{Action: "deny", Ports: []int{25}},
+	})
+	if err != nil {
+		t.Fatalf("resolveEgressRules: %v", err)
+	}
+	if len(rules) != 2 {
+		t.Fatalf("expected 2 rules, got %d", len(rules))
+	}
+	if !rules[0].Allow || rules[0].Proto != "tcp" || len(rules[0].Networks) != 2 {
+		t.Fatalf("unexpected first rule %+v", rules[0])
+	}
+	if got := rules[0].Networks[1].String(); got != "2001:db8::1/128" {
+		t.Fatalf("expected bare address as host network, got %s", got)
+	}
+	if rules[1].Allow || len(rules[1].Networks) != 0 {
+		t.Fatalf("unexpected second rule %+v", rules[1])
+	}
+}
+
+func TestBuildNetwork(t *testing.T) {
+	_, defaultSubnet, _ := net.ParseCIDR("192.168.127.0/24")
+	e := &engine{subnet: defaultSubnet}
+
+	netw, _, gateway, err := e.buildNetwork(CreateNetworkRequest{Name: "backend", Subnet: "10.20.0.0/24"})
+	if err != nil {
+		t.Fatalf("buildNetwork: %v", err)
+	}
+	if netw.Bridge != "vn-backend" || netw.Gateway != "10.20.0.1" || gateway.String() != "10.20.0.1/24" {
+		t.Fatalf("unexpected defaults: %+v gateway=%s", netw, gateway)
+	}
+
+	invalid := []CreateNetworkRequest{
+		{Name: "Backend", Subnet: "10.20.0.0/24"},
+		{Name: "backend", Subnet: "192.168.0.0/16"},
+		{Name: "backend", Subnet: "10.20.0.0/30"},
+		{Name: "backend", Subnet: "10.20.0.0/24", Gateway: "10.20.0.255"},
+		{Name: "backend", Subnet: "10.20.0.0/24", Gateway: "10.21.0.1"},
+	}
+	for _, req := range invalid {
+		if _, _, _, err := e.buildNetwork(req); err == nil {
+			t.Errorf("expected %+v to be rejected", req)
+		}
+	}
+}
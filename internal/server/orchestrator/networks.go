@@ -0,0 +1,368 @@
+// Copyright (c) 2025 HYPR. PTE. LTD.
+//
+// Business Source License 1.1
+// See LICENSE file in the project root for details.
+
+package orchestrator
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"regexp"
+	"strings"
+
+	"github.com/volantvm/volant/internal/pluginspec"
+	"github.com/volantvm/volant/internal/server/db"
+	"github.com/volantvm/volant/internal/server/orchestrator/network"
+)
+
+const (
+	maxNetworkNameLength = 12
+	// Bounds on user network prefixes: /16 keeps the candidate scan cheap and
+	// /29 leaves room for a handful of guests.
+	minNetworkPrefix = 16
+	maxNetworkPrefix = 29
+)
+
+var networkNamePattern = regexp.MustCompile(`^[a-z0-9]([a-z0-9-]*[a-z0-9])?$`)
+
+// CreateNetworkRequest defines a user network. Bridge defaults to "vn-<name>"
+// and Gateway to the first host address of Subnet.
+type CreateNetworkRequest struct {
+	Name     string
+	Subnet   string
+	Gateway  string
+	Bridge   string
+	Internal bool
+}
+
+func (e *engine) CreateNetwork(ctx context.Context, req CreateNetworkRequest) (*db.Network, error) {
+	netw, subnet, gateway, err := e.buildNetwork(req)
+	if err != nil {
+		return nil, err
+	}
+
+	var created *db.Network
+	err = e.store.WithTx(ctx, func(q db.Queries) error {
+		existing, err := q.Networks().List(ctx)
+		if err != nil {
+			return err
+		}
+		for _, other := range existing {
+			if other.Name == netw.Name {
+				return fmt.Errorf("%w: %s", ErrNetworkExists, netw.Name)
+			}
+			if other.Bridge == netw.Bridge {
+				return fmt.Errorf("%w: bridge %s is used by network %s", ErrInvalidNetwork, netw.Bridge, other.Name)
+			}
+			if _, otherSubnet, err := net.ParseCIDR(other.Subnet); err == nil && subnetsOverlap(subnet, otherSubnet) {
+				return fmt.Errorf("%w: subnet %s overlaps network %s (%s)", ErrInvalidNetwork, subnet, other.Name, other.Subnet)
+			}
+		}
+		if _, err := q.Networks().Create(ctx, &netw); err != nil {
+			return err
+		}
+		created, err = q.Networks().GetByName(ctx, netw.Name)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := e.ensureNetworkBridge(ctx, *created, gateway); err != nil {
+		if delErr := e.store.Queries().Networks().Delete(ctx, created.ID); delErr != nil {
+			e.logger.Warn("remove network after bridge failure", "network", created.Name, "error", delErr)
+		}
+		return nil, err
+	}
+	e.logger.Info("network created", "network", created.Name, "bridge", created.Bridge, "subnet", created.Subnet, "internal", created.Internal)
+	return created, nil
+}
+
+func (e *engine) buildNetwork(req CreateNetworkRequest) (db.Network, *net.IPNet, *net.IPNet, error) {
+	netw := db.Network{
+		Name:     strings.TrimSpace(req.Name),
+		Bridge:   strings.TrimSpace(req.Bridge),
+		Internal: req.Internal,
+	}
+	if !networkNamePattern.MatchString(netw.Name) || len(netw.Name) > maxNetworkNameLength {
+		return netw, nil, nil, fmt.Errorf("%w: name must be 1-%d lowercase letters, digits or '-'", ErrInvalidNetwork, maxNetworkNameLength)
+	}
+	if netw.Bridge == "" {
+		netw.Bridge = "vn-" + netw.Name
+	}
+	if len(netw.Bridge) > 15 {
+		return netw, nil, nil, fmt.Errorf("%w: bridge name %s exceeds 15 characters", ErrInvalidNetwork, netw.Bridge)
+	}
+
+	_, subnet, err := net.ParseCIDR(strings.TrimSpace(req.Subnet))
+	if err != nil || subnet.IP.To4() == nil {
+		return netw, nil, nil, fmt.Errorf("%w: subnet must be an IPv4 CIDR", ErrInvalidNetwork)
+	}
+	if ones, _ := subnet.Mask.Size(); ones < minNetworkPrefix || ones > maxNetworkPrefix {
+		return netw, nil, nil, fmt.Errorf("%w: subnet prefix must be between /%d and /%d", ErrInvalidNetwork, minNetworkPrefix, maxNetworkPrefix)
+	}
+	if subnetsOverlap(subnet, e.subnet) {
+		return netw, nil, nil, fmt.Errorf("%w: subnet %s overlaps the default subnet %s", ErrInvalidNetwork, subnet, e.subnet)
+	}
+
+	gatewayIP := nextIP(subnet.IP.To4())
+	if raw := strings.TrimSpace(req.Gateway); raw != "" {
+		gatewayIP = net.ParseIP(raw).To4()
+		if gatewayIP == nil || !subnet.Contains(gatewayIP) {
+			return netw, nil, nil, fmt.Errorf("%w: gateway %s is not inside %s", ErrInvalidNetwork, raw, subnet)
+		}
+	}
+	if gatewayIP.Equal(subnet.IP.To4()) || gatewayIP.Equal(broadcastAddress(subnet)) {
+		return netw, nil, nil, fmt.Errorf("%w: gateway %s is not a host address of %s", ErrInvalidNetwork, gatewayIP, subnet)
+	}
+
+	netw.Subnet = subnet.String()
+	netw.Gateway = gatewayIP.String()
+	return netw, subnet, &net.IPNet{IP: gatewayIP, Mask: subnet.Mask}, nil
+}
+
+func (e *engine) ListNetworks(ctx context.Context) ([]db.Network, error) {
+	return e.store.Queries().Networks().List(ctx)
+}
+
+func (e *engine) GetNetwork(ctx context.Context, name string) (*db.Network, error) {
+	netw, err := e.store.Queries().Networks().GetByName(ctx, strings.TrimSpace(name))
+	if err != nil {
+		return nil, err
+	}
+	if netw == nil {
+		return nil, fmt.Errorf("%w: %s", ErrNetworkNotFound, name)
+	}
+	return netw, nil
+}
+
+// NetworkLeases lists the addresses handed out on the network.
+func (e *engine) NetworkLeases(ctx context.Context, name string) ([]db.NetworkLease, error) {
+	netw, err := e.GetNetwork(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	return e.store.Queries().Networks().Leases(ctx, netw.ID)
+}
+
+// DeleteNetwork removes the network and its bridge. Networks that still hold
+// leases are refused; delete their VMs first.
+func (e *engine) DeleteNetwork(ctx context.Context, name string) error {
+	netw, err := e.GetNetwork(ctx, name)
+	if err != nil {
+		return err
+	}
+	leases, err := e.store.Queries().Networks().Leases(ctx, netw.ID)
+	if err != nil {
+		return err
+	}
+	if len(leases) > 0 {
+		return fmt.Errorf("%w: %s has %d attached vm(s)", ErrNetworkInUse, netw.Name, len(leases))
+	}
+	if err := e.store.Queries().Networks().Delete(ctx, netw.ID); err != nil {
+		return err
+	}
+	if provisioner, ok := e.network.(network.BridgeProvisioner); ok {
+		if gateway, err := networkGateway(*netw); err == nil {
+			if err := provisioner.DeleteBridge(ctx, netw.Bridge, gateway, !netw.Internal); err != nil {
+				e.logger.Warn("delete network bridge", "network", netw.Name, "bridge", netw.Bridge, "error", err)
+			}
+		}
+	}
+	e.logger.Info("network deleted", "network", netw.Name)
+	return nil
+}
+
+// restoreNetworks recreates the bridges of stored networks, e.g. after a host
+// reboot. Failures are logged so one broken network does not block startup.
+func (e *engine) restoreNetworks(ctx context.Context) error {
+	networks, err := e.store.Queries().Networks().List(ctx)
+	if err != nil {
+		return err
+	}
+	for _, netw := range networks {
+		gateway, err := networkGateway(netw)
+		if err != nil {
+			e.logger.Error("restore network", "network", netw.Name, "error", err)
+			continue
+		}
+		if err := e.ensureNetworkBridge(ctx, netw, gateway); err != nil {
+			e.logger.Error("restore network", "network", netw.Name, "error", err)
+		}
+	}
+	return nil
+}
+
+func (e *engine) ensureNetworkBridge(ctx context.Context, netw db.Network, gateway *net.IPNet) error {
+	provisioner, ok := e.network.(network.BridgeProvisioner)
+	if !ok {
+		return fmt.Errorf("orchestrator: network manager cannot provision bridges for network %s", netw.Name)
+	}
+	if err := provisioner.EnsureBridge(ctx, netw.Bridge, gateway, !netw.Internal); err != nil {
+		return fmt.Errorf("orchestrator: provision bridge %s: %w", netw.Bridge, err)
+	}
+	return nil
+}
+
+// vmNetwork returns the user network the VM's primary NIC attaches to, or nil
+// when it uses the default bridge.
+func vmNetwork(ctx context.Context, q db.Queries, netCfg *pluginspec.NetworkConfig) (*db.Network, error) {
+	if netCfg == nil || netCfg.Name == "" {
+		return nil, nil
+	}
+	netw, err := q.Networks().GetByName(ctx, netCfg.Name)
+	if err != nil {
+		return nil, err
+	}
+	if netw == nil {
+		return nil, fmt.Errorf("%w: %s", ErrNetworkNotFound, netCfg.Name)
+	}
+	return netw, nil
+}
+
+// leaseNetworkAddress picks the lowest free address on the network.
+func leaseNetworkAddress(ctx context.Context, q db.Queries, netw db.Network) (string, error) {
+	gateway, err := networkGateway(netw)
+	if err != nil {
+		return "", err
+	}
+	subnet := &net.IPNet{IP: gateway.IP.Mask(gateway.Mask), Mask: gateway.Mask}
+	pool, err := deriveIPPool(subnet, gateway.IP)
+	if err != nil {
+		return "", err
+	}
+	leases, err := q.Networks().Leases(ctx, netw.ID)
+	if err != nil {
+		return "", err
+	}
+	taken := make(map[string]struct{}, len(leases))
+	for _, lease := range leases {
+		taken[lease.IPAddress] = struct{}{}
+	}
+	for _, ip := range pool {
+		if _, ok := taken[ip]; !ok {
+			return ip, nil
+		}
+	}
+	return "", fmt.Errorf("%w: network %s has no free addresses", ErrIPPoolExhausted, netw.Name)
+}
+
+// guestAddressing returns the gateway and netmask guests use on netw, or on
+// the default bridge when netw is nil.
+func (e *engine) guestAddressing(netw *db.Network) (string, string) {
+	if netw == nil {
+		return e.hostIP.String(), formatNetmask(e.subnet.Mask)
+	}
+	gateway, err := networkGateway(*netw)
+	if err != nil {
+		return netw.Gateway, formatNetmask(e.subnet.Mask)
+	}
+	return netw.Gateway, formatNetmask(gateway.Mask)
+}
+
+// preparePrimaryTap creates the VM's primary tap on the default bridge or on
+// its user network's bridge.
+func (e *engine) preparePrimaryTap(ctx context.Context, vm db.VM, netw *db.Network) (string, error) {
+	if netw == nil {
+		return e.network.PrepareTap(ctx, vm.Name, vm.MACAddress)
+	}
+	if gateway, err := networkGateway(*netw); err == nil && vm.IPAddress != "" && !gateway.Contains(net.ParseIP(vm.IPAddress)) {
+		return "", fmt.Errorf("%w: vm %s address %s is not on network %s", ErrInvalidNetwork, vm.Name, vm.IPAddress, netw.Name)
+	}
+	attacher, ok := e.network.(network.InterfaceAttacher)
+	if !ok {
+		return "", fmt.Errorf("orchestrator: network manager cannot attach vm %s to network %s", vm.Name, netw.Name)
+	}
+	return attacher.PrepareInterface(ctx, vm.Name, 0, netw.Bridge, vm.MACAddress)
+}
+
+// fillTapPool creates the spare taps primary taps are claimed from, or
+// removes the ones left behind when the pool is disabled.
+func (e *engine) fillTapPool(ctx context.Context) {
+	pool, ok := e.network.(network.TapPool)
+	if !ok {
+		return
+	}
+	if err := pool.FillTapPool(ctx, e.tapPoolSize); err != nil {
+		e.logger.Warn("fill tap pool", "size", e.tapPoolSize, "error", err)
+		return
+	}
+	if e.tapPoolSize > 0 {
+		e.logger.Info("tap pool ready", "size", e.tapPoolSize)
+	}
+}
+
+// pendingTap is a primary tap being prepared in the background.
+type pendingTap struct {
+	network network.Manager
+	done    chan struct{}
+	name    string
+	err     error
+}
+
+// startPrimaryTap prepares the VM's primary tap, when its network mode needs
+// one, in the background. The tap does not depend on the cloud-init seed, so
+// the launch paths build the seed meanwhile and wait for the tap afterwards.
+func (e *engine) startPrimaryTap(ctx context.Context, vm db.VM, netw *db.Network, netCfg *pluginspec.NetworkConfig) *pendingTap {
+	pending := &pendingTap{network: e.network, done: make(chan struct{})}
+	if !needsTapDevice(netCfg) {
+		close(pending.done)
+		return pending
+	}
+	go func() {
+		defer close(pending.done)
+		pending.name, pending.err = e.preparePrimaryTap(ctx, vm, netw)
+	}()
+	return pending
+}
+
+// wait returns the prepared tap, or "" when the VM needs none.
+func (p *pendingTap) wait() (string, error) {
+	<-p.done
+	return p.name, p.err
+}
+
+// discard waits for the tap and removes it, for launches abandoned before
+// the tap was used.
+func (p *pendingTap) discard(ctx context.Context) {
+	if tap, err := p.wait(); err == nil && tap != "" {
+		_ = p.network.CleanupTap(ctx, tap)
+	}
+}
+
+func networkGateway(netw db.Network) (*net.IPNet, error) {
+	_, subnet, err := net.ParseCIDR(netw.Subnet)
+	if err != nil {
+		return nil, fmt.Errorf("orchestrator: network %s subnet %q: %w", netw.Name, netw.Subnet, err)
+	}
+	ip := net.ParseIP(netw.Gateway)
+	if ip == nil {
+		return nil, fmt.Errorf("orchestrator: network %s gateway %q is invalid", netw.Name, netw.Gateway)
+	}
+	return &net.IPNet{IP: ip, Mask: subnet.Mask}, nil
+}
+
+func broadcastAddress(subnet *net.IPNet) net.IP {
+	base := subnet.IP.To4()
+	broadcast := make(net.IP, net.IPv4len)
+	for i := range broadcast {
+		broadcast[i] = base[i] | ^subnet.Mask[i]
+	}
+	return broadcast
+}
+
+func subnetsOverlap(a, b *net.IPNet) bool {
+	if a == nil || b == nil {
+		return false
+	}
+	return a.Contains(b.IP) || b.Contains(a.IP)
+}
+
+func networkName(netCfg *pluginspec.NetworkConfig) string {
+	if netCfg == nil {
+		return ""
+	}
+	return netCfg.Name
+}
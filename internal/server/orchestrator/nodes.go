@@ -0,0 +1,152 @@
+// Copyright (c) 2025 HYPR. PTE. LTD.
+//
+// Business Source License 1.1
+// See LICENSE file in the project root for details.
+
+package orchestrator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/volantvm/volant/internal/pluginspec"
+	orchestratorevents "github.com/volantvm/volant/internal/server/orchestrator/events"
+	"github.com/volantvm/volant/internal/server/orchestrator/runtime"
+	"github.com/volantvm/volant/internal/server/orchestrator/vmconfig"
+)
+
+// LocalNode names this host in node-scoped APIs regardless of its hostname.
+const LocalNode = "local"
+
+// NodeName returns the name this host answers to in node-scoped APIs.
+func NodeName() string {
+	if name, err := os.Hostname(); err == nil && name != "" {
+		return name
+	}
+	return LocalNode
+}
+
+// NodeArtifacts lists the boot artifacts already staged on node. volantd
+// manages a single host, so only LocalNode and this host's name resolve.
+func (e *engine) NodeArtifacts(ctx context.Context, node string) ([]runtime.Artifact, error) {
+	if node != LocalNode && node != NodeName() {
+		return nil, fmt.Errorf("%w: %s", ErrNodeNotFound, node)
+	}
+	lister, ok := e.launcher.(runtime.ArtifactLister)
+	if !ok {
+		return nil, nil
+	}
+	return lister.Artifacts()
+}
+
+// OpenNodeArtifact opens a cached boot artifact on node by the name
+// NodeArtifacts reports, for peer hosts to pull instead of downloading it
+// from its origin.
+func (e *engine) OpenNodeArtifact(ctx context.Context, node, name string) (*os.File, error) {
+	if node != LocalNode && node != NodeName() {
+		return nil, fmt.Errorf("%w: %s", ErrNodeNotFound, node)
+	}
+	opener, ok := e.launcher.(runtime.ArtifactOpener)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrArtifactNotFound, name)
+	}
+	f, err := opener.OpenArtifact(name)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("%w: %s", ErrArtifactNotFound, name)
+	}
+	return f, err
+}
+
+// startArtifactStaging stages the boot artifacts of every enabled plugin, then
+// of each plugin installed, enabled or upgraded later, so the first VM of a
+// plugin does not wait for its images to download. It subscribes before
+// returning so events published right after Start are not missed.
+func (e *engine) startArtifactStaging(ctx context.Context) {
+	stager, ok := e.launcher.(runtime.ArtifactStager)
+	if !ok {
+		return
+	}
+	var events chan any
+	unsubscribe := func() {}
+	if e.bus != nil {
+		events = make(chan any, 64)
+		var err error
+		if unsubscribe, err = e.bus.Subscribe(orchestratorevents.TopicPluginEvents, events); err != nil {
+			e.logger.Error("subscribe to plugin events", "error", err)
+			return
+		}
+	}
+	go func() {
+		defer unsubscribe()
+		plugins, err := e.store.Queries().Plugins().List(ctx)
+		if err != nil {
+			if ctx.Err() == nil {
+				e.logger.Error("list plugins to stage", "error", err)
+			}
+		}
+		for _, plugin := range plugins {
+			if plugin.Enabled {
+				e.stagePluginArtifacts(ctx, stager, plugin.Name)
+			}
+		}
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case event := <-events:
+				pluginEvent, ok := event.(orchestratorevents.PluginEvent)
+				if !ok || !pluginEvent.Enabled {
+					continue
+				}
+				switch pluginEvent.Type {
+				case orchestratorevents.TypePluginInstalled, orchestratorevents.TypePluginEnabled, orchestratorevents.TypePluginUpgraded:
+					e.stagePluginArtifacts(ctx, stager, pluginEvent.Name)
+				}
+			}
+		}
+	}()
+}
+
+// stagePluginArtifacts stages the boot artifacts of an installed plugin.
+// Failures are logged; the first launch fetches whatever is missing.
+func (e *engine) stagePluginArtifacts(ctx context.Context, stager runtime.ArtifactStager, name string) {
+	manifest, err := e.installedManifest(ctx, name)
+	if err != nil {
+		e.logger.Warn("stage plugin artifacts", "plugin", name, "error", err)
+		return
+	}
+	sources := pluginArtifactSources(manifest)
+	if len(sources) == 0 {
+		return
+	}
+	if err := stager.StageArtifacts(ctx, sources); err != nil {
+		if ctx.Err() == nil {
+			e.logger.Warn("stage plugin artifacts", "plugin", name, "error", err)
+		}
+		return
+	}
+	e.logger.Info("staged plugin artifacts", "plugin", name, "count", len(sources))
+}
+
+// pluginArtifactSources returns the cached boot artifacts a plugin's VMs
+// boot from when their configs override nothing. A writable root image is
+// copied for every VM, so it has no cache to fill.
+func pluginArtifactSources(manifest *pluginspec.Manifest) []runtime.ArtifactSource {
+	var sources []runtime.ArtifactSource
+	if !manifest.FirmwareBoot() {
+		if url, checksum := effectiveKernel(manifest, vmconfig.Config{}); url != "" {
+			sources = append(sources, runtime.ArtifactSource{Kind: runtime.ArtifactKernel, URL: url, Checksum: checksum})
+		}
+		if url := strings.TrimSpace(manifest.Initramfs.URL); url != "" {
+			sources = append(sources, runtime.ArtifactSource{Kind: runtime.ArtifactInitramfs, URL: url, Checksum: strings.TrimSpace(manifest.Initramfs.Checksum)})
+		}
+	}
+	root := effectiveRootFS(manifest, vmconfig.Config{})
+	if url := strings.TrimSpace(manifest.RootFS.URL); url != "" && (root.Cloned() || root.OverlayKind() != "") {
+		sources = append(sources, runtime.ArtifactSource{Kind: runtime.ArtifactRootFS, URL: url, Checksum: strings.TrimSpace(manifest.RootFS.Checksum), Clone: root.Cloned()})
+	}
+	return sources
+}
@@ -0,0 +1,157 @@
+// Copyright (c) 2025 HYPR. PTE. LTD.
+//
+// Business Source License 1.1
+// See LICENSE file in the project root for details.
+
+package orchestrator
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/volantvm/volant/internal/pluginspec"
+	"github.com/volantvm/volant/internal/server/db"
+	"github.com/volantvm/volant/internal/server/eventbus/memory"
+	orchestratorevents "github.com/volantvm/volant/internal/server/orchestrator/events"
+	"github.com/volantvm/volant/internal/server/orchestrator/runtime"
+)
+
+// stagingLauncher records the artifacts it is asked to stage.
+type stagingLauncher struct {
+	testLauncher
+	stageMu sync.Mutex
+	staged  [][]runtime.ArtifactSource
+}
+
+func (l *stagingLauncher) StageArtifacts(ctx context.Context, sources []runtime.ArtifactSource) error {
+	l.stageMu.Lock()
+	defer l.stageMu.Unlock()
+	l.staged = append(l.staged, sources)
+	return nil
+}
+
+func (l *stagingLauncher) stagedURLs() []string {
+	l.stageMu.Lock()
+	defer l.stageMu.Unlock()
+	var urls []string
+	for _, sources := range l.staged {
+		for _, source := range sources {
+			urls = append(urls, source.URL)
+		}
+	}
+	return urls
+}
+
+func TestPluginArtifactSources(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		manifest pluginspec.Manifest
+		want     []runtime.ArtifactSource
+	}{
+		{
+			name: "kernel and initramfs",
+			manifest: pluginspec.Manifest{
+				Kernel:    &pluginspec.Kernel{URL: "https://example.com/vmlinux", Checksum: "sha256:aa"},
+				Initramfs: pluginspec.Initramfs{URL: "https://example.com/initramfs"},
+			},
+			want: []runtime.ArtifactSource{
+				{Kind: runtime.ArtifactKernel, URL: "https://example.com/vmlinux", Checksum: "sha256:aa"},
+				{Kind: runtime.ArtifactInitramfs, URL: "https://example.com/initramfs"},
+			},
+		},
+		{
+			name:     "read-only root",
+			manifest: pluginspec.Manifest{RootFS: pluginspec.RootFS{URL: "https://example.com/root.img", ReadOnly: true}},
+			want:     []runtime.ArtifactSource{{Kind: runtime.ArtifactRootFS, URL: "https://example.com/root.img"}},
+		},
+		{
+			name:     "cloned root",
+			manifest: pluginspec.Manifest{RootFS: pluginspec.RootFS{URL: "https://example.com/root.qcow2", Format: "qcow2"}},
+			want:     []runtime.ArtifactSource{{Kind: runtime.ArtifactRootFS, URL: "https://example.com/root.qcow2", Clone: true}},
+		},
+		{
+			name:     "writable root is copied per vm",
+			manifest: pluginspec.Manifest{RootFS: pluginspec.RootFS{URL: "https://example.com/root.img"}},
+		},
+		{
+			name: "firmware boot never loads the kernel",
+			manifest: pluginspec.Manifest{
+				BootMode: pluginspec.BootModeFirmware,
+				Kernel:   &pluginspec.Kernel{URL: "https://example.com/vmlinux"},
+				RootFS:   pluginspec.RootFS{URL: "https://example.com/disk.qcow2", Format: "qcow2"},
+			},
+			want: []runtime.ArtifactSource{{Kind: runtime.ArtifactRootFS, URL: "https://example.com/disk.qcow2", Clone: true}},
+		},
+	} {
+		if got := pluginArtifactSources(&tc.manifest); !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("%s: got %+v, want %+v", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestArtifactStaging(t *testing.T) {
+	ctx := context.Background()
+	store := openTestStore(t)
+	defer func() { _ = store.Close(ctx) }()
+
+	installPlugin := func(name string, enabled bool) {
+		t.Helper()
+		metadata, err := json.Marshal(pluginspec.Manifest{
+			Name:      name,
+			Version:   "1.0.0",
+			Runtime:   name,
+			Initramfs: pluginspec.Initramfs{URL: "https://example.com/" + name + ".initramfs"},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := store.Queries().Plugins().Upsert(ctx, db.Plugin{Name: name, Version: "1.0.0", Enabled: enabled, Metadata: metadata}); err != nil {
+			t.Fatalf("install plugin %s: %v", name, err)
+		}
+	}
+	installPlugin("worker", true)
+	installPlugin("idle", false)
+
+	subnet, host := testSubnet(t)
+	bus := memory.New()
+	launcher := &stagingLauncher{}
+	e, err := New(Params{
+		Store:            store,
+		Logger:           slog.New(slog.NewTextHandler(io.Discard, nil)),
+		Subnet:           subnet,
+		HostIP:           host,
+		APIListenAddr:    "127.0.0.1:7777",
+		APIAdvertiseAddr: "127.0.0.1:7777",
+		RuntimeDir:       t.TempDir(),
+		Launcher:         launcher,
+		Network:          &testNetworkManager{},
+		Bus:              bus,
+	})
+	if err != nil {
+		t.Fatalf("new engine: %v", err)
+	}
+	if err := e.Start(ctx); err != nil {
+		t.Fatalf("engine start: %v", err)
+	}
+	defer func() { _ = e.Stop(ctx) }()
+
+	waitFor(t, func() bool { return len(launcher.stagedURLs()) == 1 })
+	if got := launcher.stagedURLs(); got[0] != "https://example.com/worker.initramfs" {
+		t.Fatalf("expected only the enabled plugin staged at start, got %v", got)
+	}
+
+	installPlugin("browser", true)
+	if err := bus.Publish(ctx, orchestratorevents.TopicPluginEvents, orchestratorevents.PluginEvent{Type: orchestratorevents.TypePluginInstalled, Name: "browser", Enabled: true, Timestamp: time.Now()}); err != nil {
+		t.Fatalf("publish plugin event: %v", err)
+	}
+	waitFor(t, func() bool { return len(launcher.stagedURLs()) == 2 })
+	if got := launcher.stagedURLs(); got[1] != "https://example.com/browser.initramfs" {
+		t.Fatalf("expected the installed plugin staged, got %v", got)
+	}
+}
@@ -0,0 +1,148 @@
+// Copyright (c) 2025 HYPR. PTE. LTD.
+//
+// Business Source License 1.1
+// See LICENSE file in the project root for details.
+
+package orchestrator
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/volantvm/volant/internal/server/db"
+	orchestratorevents "github.com/volantvm/volant/internal/server/orchestrator/events"
+	"github.com/volantvm/volant/internal/server/orchestrator/runtime"
+	"github.com/volantvm/volant/internal/server/tracing"
+)
+
+// OperationKindCreateVM is the kind of operations started by CreateVMAsync.
+const OperationKindCreateVM = "vm.create"
+
+// operationInterruptedMessage is recorded on operations still unfinished when
+// the engine starts, since the daemon that ran them is gone.
+const operationInterruptedMessage = "interrupted by daemon restart"
+
+// ErrOperationNotFound indicates the requested operation does not exist.
+var ErrOperationNotFound = errors.New("orchestrator: operation not found")
+
+// CreateVMAsync validates req, records a pending operation and creates the VM
+// in the background. Image downloads and the hypervisor boot happen off the
+// caller's request; progress is published on TopicOperationEvents.
+func (e *engine) CreateVMAsync(ctx context.Context, req CreateVMRequest) (*db.Operation, error) {
+	if err := validateCreateRequest(req); err != nil {
+		return nil, err
+	}
+	if err := e.checkHostMaintenance(ctx); err != nil {
+		return nil, err
+	}
+	if req.Manifest != nil {
+		if err := e.CheckHostFeatures(req.Manifest); err != nil {
+			return nil, err
+		}
+	}
+	existing, err := e.store.Queries().VirtualMachines().GetByName(ctx, req.Name)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return nil, fmt.Errorf("%w: %s", ErrVMExists, req.Name)
+	}
+
+	now := time.Now().UTC()
+	op := db.Operation{
+		ID:        newOperationID(),
+		Kind:      OperationKindCreateVM,
+		Target:    req.Name,
+		Status:    db.OperationStatusPending,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := e.store.Queries().Operations().Create(ctx, op); err != nil {
+		return nil, err
+	}
+
+	// Run on the engine's lifetime, not the request's: the client is not
+	// waiting for the launch to finish. The request's trace still covers it.
+	go e.runCreateVM(tracing.ContextWithSpan(e.launchContext(), ctx), op, req)
+	return &op, nil
+}
+
+func (e *engine) runCreateVM(ctx context.Context, op db.Operation, req CreateVMRequest) {
+	op.Status = db.OperationStatusRunning
+	e.updateOperation(ctx, op, orchestratorevents.TypeOperationStarted, "creating vm")
+
+	vm, err := e.CreateVM(ctx, req)
+	if err != nil {
+		e.logger.Error("async create vm", "vm", req.Name, "operation", op.ID, "error", err)
+		op.Status = db.OperationStatusFailed
+		op.Error = err.Error()
+		if launchErr, ok := runtime.AsLaunchError(err); ok {
+			op.Code = string(launchErr.Code)
+			op.Hint = launchErr.Hint
+		}
+		e.updateOperation(ctx, op, orchestratorevents.TypeOperationFailed, op.Error)
+		return
+	}
+	op.Status = db.OperationStatusSucceeded
+	e.updateOperation(ctx, op, orchestratorevents.TypeOperationSucceeded, fmt.Sprintf("vm %s %s", vm.Name, vm.Status))
+}
+
+func (e *engine) updateOperation(ctx context.Context, op db.Operation, typ, message string) {
+	op.UpdatedAt = time.Now().UTC()
+	// The engine context is cancelled on shutdown; still record the outcome.
+	if err := e.store.Queries().Operations().Update(context.WithoutCancel(ctx), op); err != nil {
+		e.logger.Error("update operation", "operation", op.ID, "status", op.Status, "error", err)
+	}
+	if e.bus == nil {
+		return
+	}
+	event := orchestratorevents.OperationEvent{
+		Type:      typ,
+		ID:        op.ID,
+		Kind:      op.Kind,
+		Target:    op.Target,
+		Status:    string(op.Status),
+		Timestamp: op.UpdatedAt,
+		Message:   message,
+		Code:      op.Code,
+		Hint:      op.Hint,
+	}
+	if err := e.bus.Publish(context.WithoutCancel(ctx), orchestratorevents.TopicOperationEvents, event); err != nil {
+		e.logger.Error("publish operation event", "type", typ, "operation", op.ID, "error", err)
+	}
+}
+
+// GetOperation returns an operation by ID.
+func (e *engine) GetOperation(ctx context.Context, id string) (*db.Operation, error) {
+	op, err := e.store.Queries().Operations().Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if op == nil {
+		return nil, fmt.Errorf("%w: %s", ErrOperationNotFound, id)
+	}
+	return op, nil
+}
+
+// failInterruptedOperations marks operations left unfinished by a previous
+// run as failed so clients polling them do not wait forever.
+func (e *engine) failInterruptedOperations(ctx context.Context) error {
+	n, err := e.store.Queries().Operations().FailUnfinished(ctx, operationInterruptedMessage, time.Now().UTC())
+	if err != nil {
+		return err
+	}
+	if n > 0 {
+		e.logger.Warn("failed operations interrupted by restart", "count", n)
+	}
+	return nil
+}
+
+func newOperationID() string {
+	var buf [16]byte
+	_, _ = rand.Read(buf[:])
+	return "op-" + hex.EncodeToString(buf[:])
+}
@@ -21,6 +21,7 @@ import (
 	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/volantvm/volant/internal/drift/routes"
@@ -29,11 +30,20 @@ import (
 	"github.com/volantvm/volant/internal/server/devicemanager"
 	"github.com/volantvm/volant/internal/server/driftclient"
 	"github.com/volantvm/volant/internal/server/eventbus"
+	"github.com/volantvm/volant/internal/server/labels"
+	"github.com/volantvm/volant/internal/server/logship"
+	"github.com/volantvm/volant/internal/server/orchestrator/capacity"
 	"github.com/volantvm/volant/internal/server/orchestrator/cloudinit"
 	orchestratorevents "github.com/volantvm/volant/internal/server/orchestrator/events"
+	"github.com/volantvm/volant/internal/server/orchestrator/hostfeatures"
+	"github.com/volantvm/volant/internal/server/orchestrator/ipam"
 	"github.com/volantvm/volant/internal/server/orchestrator/network"
 	"github.com/volantvm/volant/internal/server/orchestrator/runtime"
+	"github.com/volantvm/volant/internal/server/orchestrator/topology"
 	"github.com/volantvm/volant/internal/server/orchestrator/vmconfig"
+	"github.com/volantvm/volant/internal/server/orchestrator/vmlogs"
+	"github.com/volantvm/volant/internal/server/secrets"
+	"github.com/volantvm/volant/internal/server/tracing"
 )
 
 // Engine represents the VM orchestration core.
@@ -42,24 +52,86 @@ type Engine interface {
 	Stop(ctx context.Context) error
 
 	CreateVM(ctx context.Context, req CreateVMRequest) (*db.VM, error)
+	// CreateVMAsync records an operation and creates the VM in the
+	// background; poll GetOperation or watch TopicOperationEvents.
+	CreateVMAsync(ctx context.Context, req CreateVMRequest) (*db.Operation, error)
+	GetOperation(ctx context.Context, id string) (*db.Operation, error)
 	DestroyVM(ctx context.Context, name string) error
 	ListVMs(ctx context.Context) ([]db.VM, error)
+	// ListVMsPage filters, sorts and pages VMs in the store and reports the
+	// total number of matches.
+	ListVMsPage(ctx context.Context, opts db.VMListOptions) ([]db.VM, int, error)
 	GetVM(ctx context.Context, name string) (*db.VM, error)
+	// UpdateVMLabels merges patch into a VM's labels; a nil value removes
+	// the key.
+	UpdateVMLabels(ctx context.Context, name string, patch map[string]*string) (*db.VM, error)
 	GetVMConfig(ctx context.Context, name string) (*vmconfig.Versioned, error)
 	UpdateVMConfig(ctx context.Context, name string, patch vmconfig.Patch) (*vmconfig.Versioned, error)
+	ApplyVMConfig(ctx context.Context, name string, patch vmconfig.Patch, opts VMConfigUpdateOptions) (*vmconfig.Versioned, error)
 	GetVMConfigHistory(ctx context.Context, name string, limit int) ([]vmconfig.HistoryEntry, error)
 	StartVM(ctx context.Context, name string) (*db.VM, error)
 	StopVM(ctx context.Context, name string) (*db.VM, error)
 	RestartVM(ctx context.Context, name string) (*db.VM, error)
+	SnapshotVM(ctx context.Context, name string) (*Snapshot, error)
+	DuplicateVM(ctx context.Context, source string, req DuplicateVMRequest) (*db.VM, error)
+	VMPorts(ctx context.Context, name string) ([]PortBinding, error)
+	AttachConsole(name string, replayLines int) (*ConsoleSession, error)
+	ConsoleLog(ctx context.Context, name string, tail int) ([]byte, error)
+	VMLogs(ctx context.Context, name string, query vmlogs.Query) ([]vmlogs.Entry, error)
+	// VMEvents returns a VM's recorded lifecycle events, oldest first.
+	VMEvents(ctx context.Context, name string, query db.VMEventQuery) ([]db.VMEvent, error)
 	CreateDeployment(ctx context.Context, req CreateDeploymentRequest) (*Deployment, error)
 	ListDeployments(ctx context.Context) ([]Deployment, error)
 	GetDeployment(ctx context.Context, name string) (*Deployment, error)
 	ScaleDeployment(ctx context.Context, name string, replicas int) (*Deployment, error)
+	UpdateDeploymentConfig(ctx context.Context, name string, req UpdateDeploymentConfigRequest) (*Deployment, error)
 	DeleteDeployment(ctx context.Context, name string) error
+	WarmPools(ctx context.Context) ([]WarmPool, error)
+	GetWarmPool(ctx context.Context, plugin string) (*WarmPool, error)
+	SetWarmPool(ctx context.Context, req SetWarmPoolRequest) (*WarmPool, error)
+	DeleteWarmPool(ctx context.Context, plugin string) error
+	ClaimVM(ctx context.Context, req ClaimVMRequest) (*db.VM, error)
+	CreateMaintenanceWindow(ctx context.Context, req CreateMaintenanceWindowRequest) (*db.MaintenanceWindow, error)
+	ListMaintenanceWindows(ctx context.Context) ([]db.MaintenanceWindow, error)
+	GetMaintenanceWindow(ctx context.Context, name string) (*db.MaintenanceWindow, error)
+	MaintenanceIntents(ctx context.Context, name string) ([]db.MaintenanceIntent, error)
+	EndMaintenanceWindow(ctx context.Context, name string) (*db.MaintenanceWindow, error)
+	DeleteMaintenanceWindow(ctx context.Context, name string) error
+	HostMaintenance(ctx context.Context) (*HostMaintenance, error)
+	EnterHostMaintenance(ctx context.Context, req HostMaintenanceRequest) (*HostMaintenance, error)
+	ExitHostMaintenance(ctx context.Context, concurrency int) (*HostMaintenance, error)
+	HoldForMaintenance(ctx context.Context, source, target, action string, targets []MaintenanceTarget) (bool, error)
+	CreateNetwork(ctx context.Context, req CreateNetworkRequest) (*db.Network, error)
+	ListNetworks(ctx context.Context) ([]db.Network, error)
+	GetNetwork(ctx context.Context, name string) (*db.Network, error)
+	NetworkLeases(ctx context.Context, name string) ([]db.NetworkLease, error)
+	DeleteNetwork(ctx context.Context, name string) error
+	NodeArtifacts(ctx context.Context, node string) ([]runtime.Artifact, error)
+	OpenNodeArtifact(ctx context.Context, node, name string) (*os.File, error)
+	LastGC() *GCReport
+	ScratchUsage() ScratchUsage
+	CreateConfigBundle(ctx context.Context, bundle pluginspec.Bundle) (*ConfigBundle, error)
+	ListConfigBundles(ctx context.Context) ([]ConfigBundle, error)
+	GetConfigBundle(ctx context.Context, name string) (*ConfigBundle, error)
+	UpdateConfigBundle(ctx context.Context, bundle pluginspec.Bundle) (*ConfigBundleRotation, error)
+	DeleteConfigBundle(ctx context.Context, name string) error
+	VMBundles(ctx context.Context, name string) ([]pluginspec.Bundle, error)
+	VMSecrets(ctx context.Context, name, clientIP string) ([]pluginspec.MountedSecret, error)
+	VMSSH(ctx context.Context, name string) (*SSHAccess, error)
+	VMSSHKey(ctx context.Context, name, clientIP string) (string, error)
+	DialSSH(ctx context.Context, name string) (net.Conn, error)
+	RollPluginUpgrade(ctx context.Context, manifest pluginspec.Manifest) (*PluginUpgradeRollout, error)
+	PluginVMVersions(ctx context.Context, plugin string) ([]PluginVMVersion, error)
+	VMRoutes(ctx context.Context) ([]VMRoute, error)
+	SyncRoutes(ctx context.Context) ([]VMRoute, error)
+	CheckRouteChange(ctx context.Context, protocol string, hostPort uint16) error
 	Store() db.Store
 	ControlPlaneListenAddr() string
 	ControlPlaneAdvertiseAddr() string
 	HostIP() net.IP
+	HostFeatures() *hostfeatures.Report
+	CheckHostFeatures(manifest *pluginspec.Manifest) error
+	Capacity(ctx context.Context) (*capacity.Report, error)
 }
 
 // CreateVMRequest captures the inputs required to instantiate a VM lifecycle.
@@ -75,6 +147,8 @@ type CreateVMRequest struct {
 	APIPort           string
 	Config            *vmconfig.Config
 	GroupID           *int64
+	// Labels are merged over the manifest's labels.
+	Labels map[string]string
 }
 
 // Deployment represents a managed group of VM replicas.
@@ -82,7 +156,10 @@ type Deployment struct {
 	Name            string
 	DesiredReplicas int
 	ReadyReplicas   int
+	MaxSurge        int
 	Config          vmconfig.Config
+	Reconcile       ReconcileStatus
+	Condition       string
 	CreatedAt       time.Time
 	UpdatedAt       time.Time
 }
@@ -91,15 +168,22 @@ type Deployment struct {
 type CreateDeploymentRequest struct {
 	Name     string
 	Replicas int
+	// MaxSurge caps how many replicas may boot at once during scale-up;
+	// zero launches every missing replica immediately.
+	MaxSurge int
 	Config   vmconfig.Config
 }
 
 // Params wires dependencies for the native orchestrator engine.
 type Params struct {
-	Store            db.Store
-	Logger           *slog.Logger
-	Subnet           *net.IPNet
-	HostIP           net.IP
+	Store  db.Store
+	Logger *slog.Logger
+	Subnet *net.IPNet
+	HostIP net.IP
+	// Subnet6 and HostIP6 enable dual-stack networking: every bridged VM also
+	// receives an address from this IPv6 subnet. Requires an IPv4 Subnet.
+	Subnet6          *net.IPNet
+	HostIP6          net.IP
 	APIListenAddr    string
 	APIAdvertiseAddr string
 	RuntimeDir       string
@@ -107,6 +191,50 @@ type Params struct {
 	Network          network.Manager
 	Bus              eventbus.Bus
 	Drift            *driftclient.Client
+	Readiness        ReadinessProbe
+	IPAM             ipam.Allocator
+	// DHCP reports that the embedded DHCP server is running on the bridge,
+	// so dhcp-mode VMs get an address reserved from Subnet.
+	DHCP bool
+	// HostFeatures is the startup probe of host kernel features; manifests
+	// requiring missing ones are rejected. Nil skips the check.
+	HostFeatures *hostfeatures.Report
+	// Topology is the host CPU, NUMA and huge page layout that pinned VMs
+	// are checked against. Nil rejects VMs that request placement.
+	Topology *topology.Topology
+	// PassthroughDevices is the host allowlist of PCI addresses handed to
+	// VMs whose device config requests devices by vendor or class.
+	PassthroughDevices []string
+	// Capacity admits new VMs against the host's CPU and memory. Nil reports
+	// the running host without admission thresholds.
+	Capacity *capacity.Manager
+	// Logs persists hypervisor and guest agent output per VM. Nil keeps the
+	// launcher's log files only.
+	Logs *vmlogs.Store
+	// LogShipper forwards the entries recorded in Logs to external log
+	// systems. Nil ships nothing.
+	LogShipper *logship.Shipper
+	// Secrets opens the secrets cloud-init templates reference and VMs
+	// mount, and keeps the SSH keys generated for VMs. Nil fails templates
+	// that use them, serves no mounts and refuses to generate SSH keys.
+	Secrets SecretStore
+	// AutostartConcurrency caps how many autostart VMs are started at once
+	// when the engine starts; zero means DefaultAutostartConcurrency.
+	AutostartConcurrency int
+	// SeedCacheSize caps how many built cloud-init seed images are kept for
+	// reuse by VMs started with unchanged inputs; zero disables the cache.
+	SeedCacheSize int
+	// TapPoolSize is how many spare tap devices are kept on the default
+	// bridge when the network manager supports it; zero keeps none.
+	TapPoolSize int
+}
+
+// SecretStore opens a named secret for a VM, auditing the access, and
+// stores the secrets the engine generates under reserved names.
+type SecretStore interface {
+	Open(ctx context.Context, name string, access secrets.Access) (string, error)
+	PutReserved(ctx context.Context, name, value string) (*secrets.Info, bool, error)
+	DeleteReserved(ctx context.Context, name string) error
 }
 
 // New constructs the production orchestrator engine.
@@ -141,6 +269,18 @@ func New(params Params) (Engine, error) {
 	if params.Network == nil {
 		params.Network = network.NewNoop()
 	}
+	if params.Readiness == nil {
+		params.Readiness = NewAgentReadinessProbe()
+	}
+	if params.IPAM == nil {
+		params.IPAM = ipam.NewPool()
+	}
+	if params.AutostartConcurrency <= 0 {
+		params.AutostartConcurrency = DefaultAutostartConcurrency
+	}
+	if params.Capacity == nil {
+		params.Capacity = capacity.New(capacity.ReadHost(), capacity.Thresholds{})
+	}
 	if !params.Subnet.Contains(params.HostIP) {
 		return nil, fmt.Errorf("orchestrator: host IP %s not in subnet %s", params.HostIP, params.Subnet)
 	}
@@ -149,6 +289,22 @@ func New(params Params) (Engine, error) {
 	if err != nil {
 		return nil, fmt.Errorf("orchestrator: derive ip pool: %w", err)
 	}
+	var pool6 []string
+	if params.Subnet6 != nil {
+		if params.Subnet.IP.To4() == nil {
+			return nil, fmt.Errorf("orchestrator: dual-stack requires an IPv4 primary subnet, got %s", params.Subnet)
+		}
+		if params.Subnet6.IP.To4() != nil {
+			return nil, fmt.Errorf("orchestrator: secondary subnet %s is not IPv6", params.Subnet6)
+		}
+		if params.HostIP6 == nil || !params.Subnet6.Contains(params.HostIP6) {
+			return nil, fmt.Errorf("orchestrator: host IPv6 %s not in subnet %s", params.HostIP6, params.Subnet6)
+		}
+		pool6, err = deriveIPPool(params.Subnet6, params.HostIP6)
+		if err != nil {
+			return nil, fmt.Errorf("orchestrator: derive ipv6 pool: %w", err)
+		}
+	}
 
 	runtimeDir := strings.TrimSpace(params.RuntimeDir)
 	if runtimeDir == "" {
@@ -181,23 +337,54 @@ func New(params Params) (Engine, error) {
 		runtimeDir = absRuntime
 	}
 
-	return &engine{
+	e := &engine{
 		store:                params.Store,
 		logger:               params.Logger.With("component", "orchestrator"),
 		subnet:               params.Subnet,
 		hostIP:               params.HostIP,
+		subnet6:              params.Subnet6,
+		hostIP6:              params.HostIP6,
 		controlListenAddr:    listenAddr,
 		controlAdvertiseAddr: advertiseAddr,
 		controlPort:          advertisePort,
 		ipPool:               pool,
+		ipPool6:              pool6,
 		runtimeDir:           runtimeDir,
 		launcher:             params.Launcher,
 		network:              params.Network,
 		bus:                  params.Bus,
 		drift:                params.Drift,
+		readiness:            params.Readiness,
+		ipam:                 params.IPAM,
+		ipam6:                ipam.NewPool(),
+		dhcp:                 params.DHCP,
+		hostFeatures:         params.HostFeatures,
+		topology:             params.Topology,
+		capacity:             params.Capacity,
+		logs:                 params.Logs,
+		shipper:              params.LogShipper,
+		secrets:              params.Secrets,
+		autostartConcurrency: params.AutostartConcurrency,
+		tapPoolSize:          params.TapPoolSize,
+		devicePool:           append([]string(nil), params.PassthroughDevices...),
 		vfioMgr:              devicemanager.NewVFIOManager(params.Logger),
 		instances:            make(map[string]processHandle),
-	}, nil
+		rollouts:             make(map[int64]bool),
+		deployWork:           make(map[int64]*deploymentWork),
+		reconcileRetry:       defaultReconcileRetry,
+		configUpdates:        make(map[string]struct{}),
+		ports:                make(map[string][]network.PortForward),
+		nics:                 make(map[string][]string),
+		devices:              make(map[string][]string),
+		warmKick:             make(chan struct{}, 1),
+	}
+	if params.SeedCacheSize > 0 {
+		e.seeds = cloudinit.NewCache(filepath.Join(runtimeDir, "cloudinit", "cache"), params.SeedCacheSize)
+	}
+	if e.logs != nil && e.shipper != nil {
+		e.logs.SetSink(e.shipLogs)
+	}
+	return e, nil
 }
 
 type engine struct {
@@ -205,21 +392,58 @@ type engine struct {
 	logger               *slog.Logger
 	subnet               *net.IPNet
 	hostIP               net.IP
+	subnet6              *net.IPNet
+	hostIP6              net.IP
 	controlListenAddr    string
 	controlAdvertiseAddr string
 	controlPort          string
 	ipPool               []string
+	ipPool6              []string
 	runtimeDir           string
 	launcher             runtime.Launcher
 	network              network.Manager
 	bus                  eventbus.Bus
 	drift                *driftclient.Client
+	readiness            ReadinessProbe
+	ipam                 ipam.Allocator
+	ipam6                ipam.Allocator
 	vfioMgr              devicemanager.VFIOManager
-
-	mu         sync.Mutex
-	instances  map[string]processHandle
-	procCtx    context.Context
-	procCancel context.CancelFunc
+	dhcp                 bool
+	hostFeatures         *hostfeatures.Report
+	topology             *topology.Topology
+	capacity             *capacity.Manager
+	logs                 *vmlogs.Store
+	shipper              *logship.Shipper
+	secrets              SecretStore
+	devicePool           []string
+	autostartConcurrency int
+	tapPoolSize          int
+	seeds                *cloudinit.Cache
+	// reconcileRetry is fixed after New; tests shorten it.
+	reconcileRetry reconcileRetryPolicy
+
+	mu            sync.Mutex
+	instances     map[string]processHandle
+	rollouts      map[int64]bool // running rollouts; true when a reconcile pass waits on one
+	deployWork    map[int64]*deploymentWork
+	configUpdates map[string]struct{}
+	ports         map[string][]network.PortForward
+	nics          map[string][]string
+	devices       map[string][]string
+	procCtx       context.Context
+	procCancel    context.CancelFunc
+	gcSuspects    map[gcCandidate]struct{}
+	consoles      map[string]*consoleLog
+	logTargets    map[string]logTarget
+	lastGC        *GCReport
+
+	// hostMaintenanceMu serializes entering and exiting host maintenance,
+	// and stopping VMs for it with restoring them.
+	hostMaintenanceMu sync.Mutex
+
+	// warmMu serializes claiming warm pool VMs with replenishing the pools.
+	warmMu   sync.Mutex
+	warmKick chan struct{}
 }
 
 type processHandle struct {
@@ -227,6 +451,7 @@ type processHandle struct {
 	tapName  string
 	serial   string
 	seedPath string
+	ready    *atomic.Bool
 }
 
 var (
@@ -234,18 +459,96 @@ var (
 	ErrVMExists = errors.New("orchestrator: vm already exists")
 	// ErrVMNotFound indicates the requested VM does not exist.
 	ErrVMNotFound = errors.New("orchestrator: vm not found")
+	// ErrVMNotRunning indicates the operation requires a running VM instance.
+	ErrVMNotRunning = errors.New("orchestrator: vm not running")
 	// ErrDeploymentExists indicates a deployment with the same name already exists.
 	ErrDeploymentExists = errors.New("orchestrator: deployment already exists")
 	// ErrDeploymentNotFound indicates the requested deployment does not exist.
 	ErrDeploymentNotFound = errors.New("orchestrator: deployment not found")
+	// ErrRolloutInProgress indicates another rollout is already reshaping the deployment.
+	ErrRolloutInProgress = errors.New("orchestrator: deployment rollout in progress")
+	// ErrConfigUpdateInProgress indicates a health-checked config update is still being verified.
+	ErrConfigUpdateInProgress = errors.New("orchestrator: vm config update in progress")
+	// ErrHostPortInUse indicates another VM already publishes the host port.
+	ErrHostPortInUse = errors.New("orchestrator: host port already mapped")
+	// ErrRouteManaged indicates a drift route belongs to a running VM's expose rules.
+	ErrRouteManaged = errors.New("orchestrator: drift route managed by vm")
+	// ErrMaintenanceWindowNotFound indicates the requested maintenance window does not exist.
+	ErrMaintenanceWindowNotFound = errors.New("orchestrator: maintenance window not found")
+	// ErrMaintenanceWindowExists indicates a maintenance window with the same name already exists.
+	ErrMaintenanceWindowExists = errors.New("orchestrator: maintenance window already exists")
+	// ErrInvalidMaintenanceWindow indicates the maintenance window definition failed validation.
+	ErrInvalidMaintenanceWindow = errors.New("orchestrator: invalid maintenance window")
+	// ErrMaintenanceWindowActive indicates the window is open or still holds intents.
+	ErrMaintenanceWindowActive = errors.New("orchestrator: maintenance window active")
+	// ErrNetworkNotFound indicates the requested network does not exist.
+	ErrNetworkNotFound = errors.New("orchestrator: network not found")
+	// ErrNetworkExists indicates a network with the same name already exists.
+	ErrNetworkExists = errors.New("orchestrator: network already exists")
+	// ErrInvalidNetwork indicates the network definition failed validation.
+	ErrInvalidNetwork = errors.New("orchestrator: invalid network")
+	// ErrNetworkInUse indicates VMs are still attached to the network.
+	ErrNetworkInUse = errors.New("orchestrator: network in use")
+	// ErrConfigBundleNotFound indicates the requested config bundle does not exist.
+	ErrConfigBundleNotFound = errors.New("orchestrator: config bundle not found")
+	// ErrConfigBundleExists indicates a config bundle with the same name already exists.
+	ErrConfigBundleExists = errors.New("orchestrator: config bundle already exists")
+	// ErrInvalidConfigBundle indicates the config bundle failed validation.
+	ErrInvalidConfigBundle = errors.New("orchestrator: invalid config bundle")
+	// ErrConfigBundleInUse indicates deployments still reference the bundle.
+	ErrConfigBundleInUse = errors.New("orchestrator: config bundle in use")
+	// ErrSecretAccessDenied indicates a VM's secrets were requested by a
+	// caller other than the VM itself.
+	ErrSecretAccessDenied = errors.New("orchestrator: secret access denied")
+	// ErrSSHKeyNotFound indicates the VM has no SSH key generated by volantd.
+	ErrSSHKeyNotFound = errors.New("orchestrator: ssh key not found")
+	// ErrHostFeaturesMissing indicates a plugin requires host kernel features
+	// this host lacks; see HostFeatureError.
+	ErrHostFeaturesMissing = errors.New("orchestrator: host features missing")
+	// ErrPlacementUnsatisfiable indicates CPU pinning, NUMA or huge page
+	// settings the host cannot provide.
+	ErrPlacementUnsatisfiable = errors.New("orchestrator: placement unsatisfiable")
+	// ErrPluginQuotaExceeded indicates a request exceeds a limit declared by
+	// its plugin; see QuotaError.
+	ErrPluginQuotaExceeded = errors.New("orchestrator: plugin quota exceeded")
+	// ErrInsufficientCapacity indicates a request would commit more host CPU
+	// or memory than the capacity thresholds allow; see CapacityError.
+	ErrInsufficientCapacity = errors.New("orchestrator: insufficient host capacity")
+	// ErrDevicesUnavailable indicates no free passthrough devices match a
+	// device request.
+	ErrDevicesUnavailable = errors.New("orchestrator: passthrough devices unavailable")
+	// ErrDeviceClaimed indicates a passthrough device, or another device in
+	// its IOMMU group, is already assigned to another VM.
+	ErrDeviceClaimed = errors.New("orchestrator: device already assigned")
+	// ErrInvalidLabels indicates a label key or value failed validation.
+	ErrInvalidLabels = errors.New("orchestrator: invalid labels")
+	// ErrNodeNotFound indicates the requested node is not this host.
+	ErrNodeNotFound = errors.New("orchestrator: node not found")
+	// ErrArtifactNotFound indicates the node has no cached artifact by the
+	// requested name.
+	ErrArtifactNotFound = errors.New("orchestrator: artifact not found")
+	// ErrIPPoolExhausted indicates a network has no free address to lease.
+	ErrIPPoolExhausted = errors.New("orchestrator: ip pool exhausted")
+	// ErrVMNotDuplicable indicates a VM holds state its copy cannot take
+	// over, such as passthrough devices.
+	ErrVMNotDuplicable = errors.New("orchestrator: vm cannot be duplicated")
 )
 
 func (e *engine) Start(ctx context.Context) error {
 	if err := e.store.WithTx(ctx, func(q db.Queries) error {
-		return q.IPAllocations().EnsurePool(ctx, e.ipPool)
+		if err := q.IPAllocations().EnsurePool(ctx, e.ipPool); err != nil {
+			return err
+		}
+		return q.IPAllocations().EnsurePool(ctx, e.ipPool6)
 	}); err != nil {
 		return err
 	}
+	if err := e.restoreNetworks(ctx); err != nil {
+		return err
+	}
+	if err := e.failInterruptedOperations(ctx); err != nil {
+		return err
+	}
 
 	parent := context.Background()
 	if ctx != nil {
@@ -261,6 +564,19 @@ func (e *engine) Start(ctx context.Context) error {
 	e.procCancel = cancel
 	e.mu.Unlock()
 
+	if err := e.reconcileRuntime(ctx); err != nil {
+		return err
+	}
+
+	e.startEventHistory(procCtx)
+	e.startArtifactStaging(procCtx)
+	go e.fillTapPool(procCtx)
+	go e.runAutostart(procCtx)
+	go e.runMaintenanceReplay(procCtx)
+	go e.runGarbageCollection(procCtx)
+	go e.runWarmPools(procCtx)
+	go e.runTTLReaper(procCtx)
+
 	return nil
 }
 
@@ -278,6 +594,35 @@ func (e *engine) Stop(ctx context.Context) error {
 		}
 		delete(e.instances, name)
 	}
+	for name, rules := range e.ports {
+		if forwarder, ok := e.network.(network.PortForwarder); ok {
+			for _, rule := range rules {
+				if err := forwarder.RemovePortForward(ctx, rule); err != nil {
+					errs = append(errs, fmt.Errorf("remove port mapping %s: %w", name, err))
+				}
+			}
+		}
+		delete(e.ports, name)
+	}
+	for name, taps := range e.nics {
+		for _, tap := range taps {
+			if err := e.network.CleanupTap(ctx, tap); err != nil {
+				errs = append(errs, fmt.Errorf("cleanup interface tap %s: %w", tap, err))
+			}
+		}
+		delete(e.nics, name)
+	}
+	for name, addrs := range e.devices {
+		if err := e.vfioMgr.UnbindDevices(addrs); err != nil {
+			errs = append(errs, fmt.Errorf("release devices of %s: %w", name, err))
+		}
+		delete(e.devices, name)
+	}
+	if pool, ok := e.network.(network.TapPool); ok {
+		if err := pool.FillTapPool(ctx, 0); err != nil {
+			errs = append(errs, fmt.Errorf("remove spare taps: %w", err))
+		}
+	}
 
 	if e.procCancel != nil {
 		e.procCancel()
@@ -292,9 +637,24 @@ func (e *engine) Stop(ctx context.Context) error {
 }
 
 func (e *engine) CreateVM(ctx context.Context, req CreateVMRequest) (*db.VM, error) {
+	ctx, span := tracing.Start(ctx, "orchestrator.CreateVM", tracing.String("vm.name", req.Name))
+	vm, err := e.createVM(ctx, req)
+	span.End(err)
+	return vm, err
+}
+
+func (e *engine) createVM(ctx context.Context, req CreateVMRequest) (*db.VM, error) {
 	if err := validateCreateRequest(req); err != nil {
 		return nil, err
 	}
+	if err := e.checkHostMaintenance(ctx); err != nil {
+		return nil, err
+	}
+	if req.Config != nil {
+		if err := e.startDependencies(ctx, req.Name, req.Config.DependsOn); err != nil {
+			return nil, err
+		}
+	}
 
 	var manifestRuntime string
 	pluginName := ""
@@ -302,6 +662,9 @@ func (e *engine) CreateVM(ctx context.Context, req CreateVMRequest) (*db.VM, err
 		req.Manifest.Normalize()
 		manifestRuntime = strings.TrimSpace(req.Manifest.Runtime)
 		pluginName = strings.TrimSpace(req.Manifest.Name)
+		if err := e.CheckHostFeatures(req.Manifest); err != nil {
+			return nil, err
+		}
 	}
 
 	req.Runtime = strings.TrimSpace(req.Runtime)
@@ -318,7 +681,15 @@ func (e *engine) CreateVM(ctx context.Context, req CreateVMRequest) (*db.VM, err
 		return nil, fmt.Errorf("orchestrator: runtime mismatch between request (%s) and manifest (%s)", req.Runtime, manifestRuntime)
 	}
 
-	netmask := formatNetmask(e.subnet.Mask)
+	var manifestLabels map[string]string
+	if req.Manifest != nil {
+		manifestLabels = req.Manifest.Labels
+	}
+	vmLabels := labels.Merge(manifestLabels, req.Labels)
+	if err := labels.Validate(vmLabels); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidLabels, err)
+	}
+
 	hostname := sanitizeHostname(req.Name)
 
 	var (
@@ -329,6 +700,11 @@ func (e *engine) CreateVM(ctx context.Context, req CreateVMRequest) (*db.VM, err
 	// Resolve effective network configuration
 	networkCfg := resolveNetworkConfig(req.Manifest, req.Config)
 
+	var (
+		leasedIP, leasedIPv6 string
+		userNetwork          *db.Network
+	)
+	_, leaseSpan := tracing.Start(ctx, "orchestrator.CreateVM.lease")
 	err := e.store.WithTx(ctx, func(q db.Queries) error {
 		vmRepo := q.VirtualMachines()
 		existing, err := vmRepo.GetByName(ctx, req.Name)
@@ -338,15 +714,65 @@ func (e *engine) CreateVM(ctx context.Context, req CreateVMRequest) (*db.VM, err
 		if existing != nil {
 			return fmt.Errorf("%w: %s", ErrVMExists, req.Name)
 		}
+		quotaPlugin := pluginName
+		if quotaPlugin == "" {
+			quotaPlugin = strings.TrimSpace(req.Plugin)
+		}
+		limits, err := pluginLimits(ctx, q, quotaPlugin, req.Manifest)
+		if err != nil {
+			return err
+		}
+		if err := checkResourceLimits(quotaPlugin, limits, req.CPUCores, req.MemoryMB); err != nil {
+			return err
+		}
+		// Deployment replicas are counted by their deployment's replica
+		// count, which also leaves room for rolling-update surges.
+		if req.GroupID == nil {
+			if err := checkVMQuota(ctx, q, quotaPlugin, limits, 1, 0); err != nil {
+				return err
+			}
+			if err := e.checkCapacity(ctx, q, req.CPUCores, req.MemoryMB); err != nil {
+				return err
+			}
+		}
+		userNetwork, err = vmNetwork(ctx, q, networkCfg)
+		if err != nil {
+			return err
+		}
 
 		// Conditionally allocate IP based on network mode
-		var ipAddress string
-		if needsIPAllocation(networkCfg) {
-			allocation, err := q.IPAllocations().LeaseNextAvailable(ctx)
+		var ipAddress, ipv6Address string
+		if userNetwork != nil {
+			// User networks run their own IPAM; leases are recorded once
+			// the VM row exists.
+			ipAddress, err = leaseNetworkAddress(ctx, q, *userNetwork)
 			if err != nil {
 				return err
 			}
-			ipAddress = allocation.IPAddress
+		} else if e.servesDHCP(networkCfg) {
+			// The embedded DHCP server hands this address to the guest.
+			ipAddress, err = e.ipam.Lease(ctx, q, ipam.Request{VMName: req.Name, Family: db.IPFamilyV4})
+			if err != nil {
+				return err
+			}
+			leasedIP = ipAddress
+		} else if needsIPAllocation(networkCfg) {
+			family := db.IPFamilyV4
+			if e.subnet.IP.To4() == nil {
+				family = db.IPFamilyV6
+			}
+			ipAddress, err = e.ipam.Lease(ctx, q, ipam.Request{VMName: req.Name, Family: family})
+			if err != nil {
+				return err
+			}
+			leasedIP = ipAddress
+			if e.subnet6 != nil {
+				ipv6Address, err = e.ipam6.Lease(ctx, q, ipam.Request{VMName: req.Name, Family: db.IPFamilyV6})
+				if err != nil {
+					return err
+				}
+				leasedIPv6 = ipv6Address
+			}
 		} else {
 			// vsock or dhcp mode: no host-managed IP
 			ipAddress = ""
@@ -361,7 +787,7 @@ func (e *engine) CreateVM(ctx context.Context, req CreateVMRequest) (*db.VM, err
 		}
 
 		mac := deriveMAC(req.Name, ipAddress)
-		baseCmdline := buildKernelCmdline(ipAddress, e.hostIP.String(), netmask, hostname, req.KernelCmdlineHint)
+		baseCmdline := e.guestKernelCmdline(userNetwork, staticAddress(networkCfg, ipAddress), ipv6Address, hostname, req.KernelCmdlineHint)
 		fullCmdline := appendKernelArgs(baseCmdline, map[string]string{})
 
 		vm := &db.VM{
@@ -369,20 +795,30 @@ func (e *engine) CreateVM(ctx context.Context, req CreateVMRequest) (*db.VM, err
 			Status:        db.VMStatusStarting,
 			Runtime:       req.Runtime,
 			IPAddress:     ipAddress,
+			IPv6Address:   ipv6Address,
 			MACAddress:    mac,
 			VsockCID:      vsockCID,
 			CPUCores:      req.CPUCores,
 			MemoryMB:      req.MemoryMB,
 			KernelCmdline: fullCmdline,
 			GroupID:       req.GroupID,
+			Labels:        vmLabels,
 		}
 
 		id, err := vmRepo.Create(ctx, vm)
 		if err != nil {
 			return err
 		}
-		if ipAddress != "" {
-			if err := q.IPAllocations().Assign(ctx, ipAddress, id); err != nil {
+		if userNetwork != nil {
+			if err := q.Networks().Lease(ctx, userNetwork.ID, ipAddress, id); err != nil {
+				return err
+			}
+		}
+		for _, addr := range []string{leasedIP, ipv6Address} {
+			if addr == "" {
+				continue
+			}
+			if err := q.IPAllocations().Assign(ctx, addr, id); err != nil {
 				return err
 			}
 		}
@@ -391,7 +827,23 @@ func (e *engine) CreateVM(ctx context.Context, req CreateVMRequest) (*db.VM, err
 		vmRecord = vm
 		return nil
 	})
+	if err == nil {
+		leaseSpan.SetAttributes(tracing.String("vm.ip", vmRecord.IPAddress))
+	}
+	leaseSpan.End(err)
 	if err != nil {
+		if leasedIP != "" {
+			// The transaction rolled back the local lease; give external
+			// backends the chance to free theirs too.
+			if releaseErr := e.ipam.Release(ctx, e.store.Queries(), ipam.Request{VMName: req.Name, IPAddress: leasedIP}); releaseErr != nil {
+				e.logger.Warn("release ip after failed create", "vm", req.Name, "ip", leasedIP, "error", releaseErr)
+			}
+		}
+		if leasedIPv6 != "" {
+			if releaseErr := e.ipam6.Release(ctx, e.store.Queries(), ipam.Request{VMName: req.Name, IPAddress: leasedIPv6}); releaseErr != nil {
+				e.logger.Warn("release ipv6 after failed create", "vm", req.Name, "ip", leasedIPv6, "error", releaseErr)
+			}
+		}
 		return nil, err
 	}
 
@@ -438,10 +890,8 @@ func (e *engine) CreateVM(ctx context.Context, req CreateVMRequest) (*db.VM, err
 		extraCmdline = strings.TrimSpace(req.Config.KernelCmdline)
 	}
 	configToStore.KernelCmdline = extraCmdline
-	configToStore.Resources = vmconfig.Resources{
-		CPUCores: vmRecord.CPUCores,
-		MemoryMB: vmRecord.MemoryMB,
-	}
+	configToStore.Resources.CPUCores = vmRecord.CPUCores
+	configToStore.Resources.MemoryMB = vmRecord.MemoryMB
 	configToStore.API = vmconfig.API{
 		Host: apiHost,
 		Port: apiPort,
@@ -463,8 +913,12 @@ func (e *engine) CreateVM(ctx context.Context, req CreateVMRequest) (*db.VM, err
 		overrideCopy.Normalize()
 		overrideCloudInit = &overrideCopy
 	}
-	effectiveCloudInit, record, preparedSeedDisk, err := e.prepareCloudInitSeed(ctx, vmRecord, manifestForConfig, overrideCloudInit)
+	pendingTap := e.startPrimaryTap(ctx, *vmRecord, userNetwork, networkCfg)
+	seedCtx, seedSpan := tracing.Start(ctx, "orchestrator.CreateVM.seed")
+	effectiveCloudInit, record, preparedSeedDisk, err := e.prepareCloudInitSeed(seedCtx, vmRecord, manifestForConfig, overrideCloudInit, e.firmwareNetwork(manifestForConfig, vmRecord, networkCfg, userNetwork), configToStore.API, configToStore.SSH)
+	seedSpan.End(err)
 	if err != nil {
+		pendingTap.discard(ctx)
 		e.rollbackCreate(ctx, vmRecord)
 		return nil, err
 	}
@@ -477,6 +931,7 @@ func (e *engine) CreateVM(ctx context.Context, req CreateVMRequest) (*db.VM, err
 		if seedDisk != nil {
 			_ = os.Remove(seedDisk.Path)
 		}
+		pendingTap.discard(ctx)
 		e.rollbackCreate(ctx, vmRecord)
 		return nil, err
 	}
@@ -497,20 +952,37 @@ func (e *engine) CreateVM(ctx context.Context, req CreateVMRequest) (*db.VM, err
 		if seedDisk != nil {
 			_ = os.Remove(seedDisk.Path)
 		}
+		pendingTap.discard(ctx)
 		e.rollbackCreate(ctx, vmRecord)
 		return nil, err
 	}
 
-	// Conditionally prepare tap device based on network mode
-	tapName := ""
-	if needsTapDevice(networkCfg) {
-		tap, err := e.network.PrepareTap(ctx, vmRecord.Name, vmRecord.MACAddress)
-		if err != nil {
-			e.rollbackCreate(ctx, vmRecord)
-			return nil, err
-		}
-		tapName = tap
+	tapName, err := pendingTap.wait()
+	if err != nil {
+		err = runtime.NewLaunchError(runtime.ErrorTapCreate, err)
+		e.publishLaunchFailure(ctx, vmRecord, err)
+		e.rollbackCreate(ctx, vmRecord)
+		return nil, err
+	}
+	if err := e.applyEgressPolicy(ctx, vmRecord.Name, tapName, networkCfg); err != nil {
+		_ = e.network.CleanupTap(ctx, tapName)
+		e.rollbackCreate(ctx, vmRecord)
+		return nil, err
 	}
+	extraNICs, nicArgs, err := e.prepareInterfaces(ctx, vmRecord.Name, networkCfg)
+	if err != nil {
+		_ = e.network.CleanupTap(ctx, tapName)
+		e.publishLaunchFailure(ctx, vmRecord, err)
+		e.rollbackCreate(ctx, vmRecord)
+		return nil, err
+	}
+	launched := false
+	defer func() {
+		if !launched {
+			e.releaseInterfaces(ctx, vmRecord.Name)
+			e.releaseDevices(ctx, vmRecord.Name)
+		}
+	}()
 
 	serialPath := filepath.Join(e.runtimeDir, fmt.Sprintf("%s.serial", vmRecord.Name))
 	serialPath = filepath.Clean(serialPath)
@@ -523,6 +995,7 @@ func (e *engine) CreateVM(ctx context.Context, req CreateVMRequest) (*db.VM, err
 		serialPath = absSerial
 	}
 
+	gateway, netmask := e.guestAddressing(userNetwork)
 	spec := runtime.LaunchSpec{
 		Name:          vmRecord.Name,
 		CPUCores:      vmRecord.CPUCores,
@@ -531,11 +1004,12 @@ func (e *engine) CreateVM(ctx context.Context, req CreateVMRequest) (*db.VM, err
 		TapDevice:     tapName,
 		MACAddress:    vmRecord.MACAddress,
 		IPAddress:     vmRecord.IPAddress,
-		Gateway:       e.hostIP.String(),
+		Gateway:       gateway,
 		Netmask:       netmask,
 		VsockCID:      vmRecord.VsockCID,
 		SerialSocket:  serialPath,
 	}
+	e.captureLogs(&spec)
 	spec.Disks = additionalDisks
 	if seedDisk != nil {
 		spec.SeedDisk = seedDisk
@@ -549,6 +1023,12 @@ func (e *engine) CreateVM(ctx context.Context, req CreateVMRequest) (*db.VM, err
 	if pluginName != "" {
 		cmdArgs[pluginspec.PluginKey] = pluginName
 	}
+	if len(configToStore.Bundles) > 0 {
+		cmdArgs[pluginspec.VMNameKey] = vmRecord.Name
+	}
+	for key, value := range secretMountArgs(vmRecord.Name, resolveSecretMounts(req.Manifest, &configToStore)) {
+		cmdArgs[key] = value
+	}
 	if req.Manifest != nil {
 		encodedManifest, err := pluginspec.Encode(*req.Manifest)
 		if err != nil {
@@ -557,7 +1037,16 @@ func (e *engine) CreateVM(ctx context.Context, req CreateVMRequest) (*db.VM, err
 		}
 		cmdArgs[pluginspec.CmdlineKey] = encodedManifest
 	}
+	for key, value := range nicArgs {
+		cmdArgs[key] = value
+	}
+	shares, shareArgs := launchShares(resolveShares(req.Manifest, &configToStore))
+	for key, value := range shareArgs {
+		cmdArgs[key] = value
+	}
 	spec.Args = cmdArgs
+	spec.Interfaces = extraNICs
+	spec.Shares = shares
 
 	if req.Manifest != nil {
 		// Start from manifest defaults; allow both initramfs and rootfs when provided
@@ -583,8 +1072,16 @@ func (e *engine) CreateVM(ctx context.Context, req CreateVMRequest) (*db.VM, err
 			spec.RootFSChecksum = strings.TrimSpace(configToStore.RootFS.Checksum)
 		}
 	}
-	// Kernel override per-VM
-	spec.KernelOverride = strings.TrimSpace(configToStore.KernelOverride)
+	// Kernel: per-VM path override, then the VM config's or manifest's kernel
+	spec.KernelOverride, spec.KernelChecksum = effectiveKernel(req.Manifest, configToStore)
+	if err := applyFirmwareBoot(req.Manifest, effectiveRootFS(req.Manifest, configToStore), &spec); err != nil {
+		if seedDisk != nil {
+			_ = os.Remove(seedDisk.Path)
+		}
+		_ = e.network.CleanupTap(ctx, tapName)
+		e.rollbackCreate(ctx, vmRecord)
+		return nil, err
+	}
 	// If RootFS is set, ensure default device/fstype args unless already supplied by the runtime
 	if spec.RootFS != "" {
 		if _, ok := cmdArgs[pluginspec.RootFSDeviceKey]; !ok {
@@ -594,6 +1091,22 @@ func (e *engine) CreateVM(ctx context.Context, req CreateVMRequest) (*db.VM, err
 			cmdArgs[pluginspec.RootFSFSTypeKey] = "ext4"
 		}
 	}
+	if err := e.applyScratchDisk(ctx, req.Name, configToStore.ScratchDisk, &spec, cmdArgs); err != nil {
+		if seedDisk != nil {
+			_ = os.Remove(seedDisk.Path)
+		}
+		_ = e.network.CleanupTap(ctx, tapName)
+		e.rollbackCreate(ctx, vmRecord)
+		return nil, err
+	}
+	if err := e.applyRootFSOverlay(ctx, req.Name, effectiveRootFS(req.Manifest, configToStore), &spec, cmdArgs); err != nil {
+		if seedDisk != nil {
+			_ = os.Remove(seedDisk.Path)
+		}
+		_ = e.network.CleanupTap(ctx, tapName)
+		e.rollbackCreate(ctx, vmRecord)
+		return nil, err
+	}
 
 	// Handle VFIO GPU/device passthrough if configured (prefer VM-level overrides)
 	var devCfg *pluginspec.DeviceConfig
@@ -602,8 +1115,24 @@ func (e *engine) CreateVM(ctx context.Context, req CreateVMRequest) (*db.VM, err
 	} else if req.Manifest != nil && req.Manifest.Devices != nil {
 		devCfg = req.Manifest.Devices
 	}
-	if devCfg != nil && len(devCfg.PCIPassthrough) > 0 {
-		pciAddrs := devCfg.PCIPassthrough
+	pciAddrs, err := e.allocateDevices(ctx, req.Name, devCfg, configToStore.Resources.NUMANode)
+	if err != nil {
+		if seedDisk != nil {
+			_ = os.Remove(seedDisk.Path)
+		}
+		_ = e.network.CleanupTap(ctx, tapName)
+		e.rollbackCreate(ctx, vmRecord)
+		return nil, err
+	}
+	if err := e.resolvePlacement(configToStore.Resources, pciAddrs, &spec); err != nil {
+		if seedDisk != nil {
+			_ = os.Remove(seedDisk.Path)
+		}
+		_ = e.network.CleanupTap(ctx, tapName)
+		e.rollbackCreate(ctx, vmRecord)
+		return nil, err
+	}
+	if len(pciAddrs) > 0 {
 		allowlist := devCfg.Allowlist
 
 		e.logger.Info("vfio passthrough requested", "vm", req.Name, "devices", pciAddrs)
@@ -619,6 +1148,15 @@ func (e *engine) CreateVM(ctx context.Context, req CreateVMRequest) (*db.VM, err
 			return nil, fmt.Errorf("device validation failed: %w", err)
 		}
 
+		if err := e.claimDevices(ctx, vmRecord, pciAddrs); err != nil {
+			if seedDisk != nil {
+				_ = os.Remove(seedDisk.Path)
+			}
+			_ = e.network.CleanupTap(ctx, tapName)
+			e.rollbackCreate(ctx, vmRecord)
+			return nil, err
+		}
+
 		// Bind devices to vfio-pci driver
 		if err := e.vfioMgr.BindDevices(pciAddrs); err != nil {
 			e.logger.Error("vfio device binding failed", "vm", req.Name, "error", err)
@@ -651,12 +1189,15 @@ func (e *engine) CreateVM(ctx context.Context, req CreateVMRequest) (*db.VM, err
 
 	launchCtx := e.launchContext()
 
+	_, launchSpan := tracing.Start(ctx, "orchestrator.CreateVM.launch")
 	instance, err := e.launcher.Launch(launchCtx, spec)
+	launchSpan.End(err)
 	if err != nil {
 		if seedDisk != nil {
 			_ = os.Remove(seedDisk.Path)
 		}
 		_ = e.network.CleanupTap(ctx, tapName)
+		e.publishLaunchFailure(ctx, vmRecord, err)
 		e.rollbackCreate(ctx, vmRecord)
 		return nil, err
 	}
@@ -678,25 +1219,57 @@ func (e *engine) CreateVM(ctx context.Context, req CreateVMRequest) (*db.VM, err
 		return nil, err
 	}
 
+	if e.drift != nil && len(configToStore.Expose) > 0 {
+		if err := e.applyDriftRoutes(ctx, *vmRecord, networkCfg, configToStore.Expose); err != nil {
+			_ = instance.Stop(ctx)
+			_ = e.network.CleanupTap(ctx, tapName)
+			if seedDisk != nil {
+				_ = os.Remove(seedDisk.Path)
+			}
+			e.rollbackCreate(ctx, vmRecord)
+			return nil, err
+		}
+	}
+
+	if err := e.applyPortForwards(ctx, *vmRecord, configToStore.Ports); err != nil {
+		_ = instance.Stop(ctx)
+		_ = e.network.CleanupTap(ctx, tapName)
+		if seedDisk != nil {
+			_ = os.Remove(seedDisk.Path)
+		}
+		if len(configToStore.Expose) > 0 {
+			e.removeDriftRoutes(ctx, vmRecord.Name, configToStore.Expose)
+		}
+		e.rollbackCreate(ctx, vmRecord)
+		return nil, err
+	}
+
+	e.addNeighborProxies(ctx, *vmRecord)
+
 	e.mu.Lock()
 	seedPath := ""
 	if seedDisk != nil {
 		seedPath = seedDisk.Path
 	}
-	handle := processHandle{instance: instance, tapName: tapName, serial: spec.SerialSocket, seedPath: seedPath}
+	handle := processHandle{instance: instance, tapName: tapName, serial: spec.SerialSocket, seedPath: seedPath, ready: new(atomic.Bool)}
 	e.instances[vmRecord.Name] = handle
 	e.mu.Unlock()
+	launched = true
 
 	e.monitorInstance(vmRecord.Name, handle)
+	e.watchReadiness(*vmRecord, handle, spec.BootFirmware)
 
 	vmRecord.Status = db.VMStatusRunning
 	vmRecord.PID = &pid
+	e.armTTL(ctx, vmRecord, configToStore)
 	e.publishEvent(ctx, orchestratorevents.TypeVMRunning, orchestratorevents.VMStatusRunning, vmRecord, "vm running")
 	return vmRecord, nil
 }
 
 func (e *engine) DestroyVM(ctx context.Context, name string) error {
+	ctx, span := tracing.Start(ctx, "orchestrator.DestroyVM", tracing.String("vm.name", name))
 	_, err := e.destroyVM(ctx, name, true)
+	span.End(err)
 	return err
 }
 
@@ -705,6 +1278,8 @@ func (e *engine) destroyVM(ctx context.Context, name string, reconcile bool) (*d
 		vmRecord    *db.VM
 		cloudRecord *db.VMCloudInit
 		expose      []vmconfig.Expose
+		hooks       []pluginspec.Hook
+		keepClone   bool
 	)
 	err := e.store.WithTx(ctx, func(q db.Queries) error {
 		vmRepo := q.VirtualMachines()
@@ -719,6 +1294,8 @@ func (e *engine) destroyVM(ctx context.Context, name string, reconcile bool) (*d
 		if cfgRecord, cfgErr := q.VMConfigs().GetCurrent(ctx, vm.ID); cfgErr == nil && cfgRecord != nil {
 			if versioned, convErr := vmconfig.FromDB(*cfgRecord); convErr == nil {
 				expose = append([]vmconfig.Expose(nil), versioned.Config.Expose...)
+				hooks = preStopHooks(versioned.Config)
+				keepClone = effectiveRootFS(versioned.Config.Manifest, versioned.Config).KeepClone
 			}
 		}
 		if record, err := q.VMCloudInit().Get(ctx, vm.ID); err == nil {
@@ -729,7 +1306,7 @@ func (e *engine) destroyVM(ctx context.Context, name string, reconcile bool) (*d
 		if err := vmRepo.Delete(ctx, vm.ID); err != nil {
 			return err
 		}
-		if err := q.IPAllocations().Release(ctx, vm.IPAddress); err != nil {
+		if err := e.releaseAddresses(ctx, q, *vm); err != nil {
 			return err
 		}
 		if err := q.VMCloudInit().Delete(ctx, vm.ID); err != nil {
@@ -748,7 +1325,9 @@ func (e *engine) destroyVM(ctx context.Context, name string, reconcile bool) (*d
 	}
 	e.mu.Unlock()
 
+	var hookResults []pluginspec.HookResult
 	if exists {
+		hookResults = e.runPreStopHooks(ctx, *vmRecord, hooks)
 		if err := handle.instance.Stop(ctx); err != nil {
 			e.logger.Error("stop instance", "vm", name, "error", err)
 		}
@@ -777,6 +1356,21 @@ func (e *engine) destroyVM(ctx context.Context, name string, reconcile bool) (*d
 			e.logger.Debug("remove seed image", "path", seedPath, "error", err)
 		}
 	}
+	if err := os.RemoveAll(cloneDir(e.runtimeDir, name)); err != nil {
+		e.logger.Debug("remove cloned disks", "vm", name, "error", err)
+	}
+	e.deleteSSHKey(ctx, name)
+	if err := os.Remove(overlayDiskPath(e.runtimeDir, name)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		e.logger.Debug("remove rootfs overlay", "vm", name, "error", err)
+	}
+	if err := os.Remove(scratchDiskPath(e.runtimeDir, name)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		e.logger.Debug("remove scratch disk", "vm", name, "error", err)
+	}
+	if keepClone {
+		e.logger.Info("keeping rootfs clone", "vm", name, "path", rootFSClonePath(e.runtimeDir, name))
+	} else if err := os.Remove(rootFSClonePath(e.runtimeDir, name)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		e.logger.Debug("remove rootfs clone", "vm", name, "error", err)
+	}
 
 	// Unbind VFIO devices if this VM had GPU passthrough
 	if vmRecord != nil && vmRecord.ID > 0 {
@@ -803,6 +1397,12 @@ func (e *engine) destroyVM(ctx context.Context, name string, reconcile bool) (*d
 	}
 
 	if vmRecord != nil {
+		if exists {
+			e.removeNeighborProxies(ctx, *vmRecord)
+			e.removePortForwards(ctx, name)
+			e.releaseInterfaces(ctx, name)
+			e.releaseDevices(ctx, name)
+		}
 		vmRecord.Status = db.VMStatusStopped
 		vmRecord.PID = nil
 	}
@@ -811,10 +1411,13 @@ func (e *engine) destroyVM(ctx context.Context, name string, reconcile bool) (*d
 		e.removeDriftRoutes(ctx, name, expose)
 	}
 
-	e.publishEvent(ctx, orchestratorevents.TypeVMDeleted, orchestratorevents.VMStatusStopped, vmRecord, "vm deleted")
+	e.dropConsole(name)
+	e.dropLogs(name)
+	e.publishStopEvent(ctx, orchestratorevents.TypeVMDeleted, orchestratorevents.VMStatusStopped, vmRecord, "vm deleted", hookResults)
+	e.stopDependents(name)
 
 	if reconcile && vmRecord != nil && vmRecord.GroupID != nil {
-		if _, recErr := e.reconcileDeploymentByID(ctx, *vmRecord.GroupID); recErr != nil {
+		if _, recErr := e.reconcileOrHold(ctx, *vmRecord.GroupID); recErr != nil {
 			e.logger.Error("reconcile deployment after vm delete", "vm", name, "error", recErr)
 		}
 	}
@@ -826,6 +1429,10 @@ func (e *engine) ListVMs(ctx context.Context) ([]db.VM, error) {
 	return e.store.Queries().VirtualMachines().List(ctx)
 }
 
+func (e *engine) ListVMsPage(ctx context.Context, opts db.VMListOptions) ([]db.VM, int, error) {
+	return e.store.Queries().VirtualMachines().ListPage(ctx, opts)
+}
+
 func (e *engine) GetVM(ctx context.Context, name string) (*db.VM, error) {
 	return e.store.Queries().VirtualMachines().GetByName(ctx, name)
 }
@@ -878,7 +1485,32 @@ func (e *engine) GetVMConfigHistory(ctx context.Context, name string, limit int)
 }
 
 func (e *engine) UpdateVMConfig(ctx context.Context, name string, patch vmconfig.Patch) (*vmconfig.Versioned, error) {
-	var updated vmconfig.Versioned
+	updated, err := e.writeVMConfig(ctx, name, patch.Apply)
+	if err != nil {
+		return nil, err
+	}
+	// Port mappings are host-side only, so running VMs pick them up without
+	// a restart.
+	if patch.Ports != nil && e.hasInstance(name) {
+		vm, err := e.GetVM(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+		if vm != nil {
+			if err := e.applyPortForwards(ctx, *vm, updated.Config.Ports); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return updated, nil
+}
+
+// writeVMConfig stores the result of mutate as the VM's next configuration version.
+func (e *engine) writeVMConfig(ctx context.Context, name string, mutate func(vmconfig.Config) (vmconfig.Config, error)) (*vmconfig.Versioned, error) {
+	var (
+		updated vmconfig.Versioned
+		vmRec   *db.VM
+	)
 
 	err := e.store.WithTx(ctx, func(q db.Queries) error {
 		vmRepo := q.VirtualMachines()
@@ -900,12 +1532,32 @@ func (e *engine) UpdateVMConfig(ctx context.Context, name string, patch vmconfig
 		if err != nil {
 			return err
 		}
-		merged, err := patch.Apply(current.Config)
+		merged, err := mutate(current.Config)
+		if err != nil {
+			return err
+		}
+		// Only resizes are checked, so VMs created before their plugin's
+		// limits were tightened can still be reconfigured otherwise.
+		if merged.Resources.CPUCores != current.Config.Resources.CPUCores || merged.Resources.MemoryMB != current.Config.Resources.MemoryMB {
+			limits, err := pluginLimits(ctx, q, merged.Plugin, merged.Manifest)
+			if err != nil {
+				return err
+			}
+			if err := checkResourceLimits(merged.Plugin, limits, merged.Resources.CPUCores, merged.Resources.MemoryMB); err != nil {
+				return err
+			}
+		}
+		currentNet := resolveNetworkConfig(current.Config.Manifest, &current.Config)
+		mergedNet := resolveNetworkConfig(merged.Manifest, &merged)
+		if networkName(currentNet) != networkName(mergedNet) {
+			return fmt.Errorf("%w: the network of vm %s cannot be changed; recreate it instead", ErrInvalidNetwork, name)
+		}
+		userNetwork, err := vmNetwork(ctx, q, mergedNet)
 		if err != nil {
 			return err
 		}
 		extraCmdline := strings.TrimSpace(merged.KernelCmdline)
-		finalCmdline := buildKernelCmdline(vm.IPAddress, e.hostIP.String(), formatNetmask(e.subnet.Mask), sanitizeHostname(vm.Name), extraCmdline)
+		finalCmdline := e.guestKernelCmdline(userNetwork, staticAddress(mergedNet, vm.IPAddress), vm.IPv6Address, sanitizeHostname(vm.Name), extraCmdline)
 		merged.KernelCmdline = extraCmdline
 		payload, err := vmconfig.Marshal(merged)
 		if err != nil {
@@ -928,15 +1580,24 @@ func (e *engine) UpdateVMConfig(ctx context.Context, name string, patch vmconfig
 		vm.CPUCores = merged.Resources.CPUCores
 		vm.MemoryMB = merged.Resources.MemoryMB
 		vm.KernelCmdline = finalCmdline
+		vmRec = vm
 		return nil
 	})
 	if err != nil {
 		return nil, err
 	}
+	e.publishConfigUpdated(ctx, vmRec, updated.Version)
 	return &updated, nil
 }
 
 func (e *engine) StartVM(ctx context.Context, name string) (*db.VM, error) {
+	ctx, span := tracing.Start(ctx, "orchestrator.StartVM", tracing.String("vm.name", name))
+	vm, err := e.startVM(ctx, name)
+	span.End(err)
+	return vm, err
+}
+
+func (e *engine) startVM(ctx context.Context, name string) (*db.VM, error) {
 	e.mu.Lock()
 	if _, exists := e.instances[name]; exists {
 		e.mu.Unlock()
@@ -960,6 +1621,10 @@ func (e *engine) StartVM(ctx context.Context, name string) (*db.VM, error) {
 		if vm == nil {
 			return fmt.Errorf("%w: %s", ErrVMNotFound, name)
 		}
+		if vm.Status == db.VMStatusStarting {
+			// Another caller is starting it, for example as a dependency.
+			return fmt.Errorf("orchestrator: vm %s already starting", name)
+		}
 		record, err := q.VMConfigs().GetCurrent(ctx, vm.ID)
 		if err != nil {
 			return err
@@ -986,6 +1651,10 @@ func (e *engine) StartVM(ctx context.Context, name string) (*db.VM, error) {
 	if err != nil {
 		return nil, err
 	}
+	if err := e.startDependencies(ctx, name, cfg.DependsOn); err != nil {
+		e.setVMState(ctx, vmRecord.ID, db.VMStatusStopped, nil)
+		return nil, err
+	}
 
 	apiHost := strings.TrimSpace(cfg.API.Host)
 	apiPort := strings.TrimSpace(cfg.API.Port)
@@ -1010,49 +1679,72 @@ func (e *engine) StartVM(ctx context.Context, name string) (*db.VM, error) {
 
 	// Resolve network configuration for this VM
 	networkCfg := resolveNetworkConfig(cfg.Manifest, &cfg)
-
-	// Conditionally prepare tap device based on network mode
-	tapName := ""
-	if needsTapDevice(networkCfg) {
-		tap, err := e.network.PrepareTap(ctx, vmRecord.Name, vmRecord.MACAddress)
-		if err != nil {
-			e.setVMState(ctx, vmRecord.ID, db.VMStatusStopped, nil)
-			return nil, err
-		}
-		tapName = tap
-	}
-
-	serialPath := filepath.Join(e.runtimeDir, fmt.Sprintf("%s.serial", vmRecord.Name))
-	serialPath = filepath.Clean(serialPath)
-	if !filepath.IsAbs(serialPath) {
-		absSerial, absErr := filepath.Abs(serialPath)
-		if absErr != nil {
-			e.setVMState(ctx, vmRecord.ID, db.VMStatusStopped, nil)
-			_ = e.network.CleanupTap(ctx, tapName)
-			return nil, fmt.Errorf("orchestrator: resolve serial socket path: %w", absErr)
-		}
-		serialPath = absSerial
+	userNetwork, err := vmNetwork(ctx, e.store.Queries(), networkCfg)
+	if err != nil {
+		e.setVMState(ctx, vmRecord.ID, db.VMStatusStopped, nil)
+		return nil, err
 	}
 
 	manifest := cfg.Manifest
 	if manifest == nil {
-		_ = e.network.CleanupTap(ctx, tapName)
 		e.setVMState(ctx, vmRecord.ID, db.VMStatusStopped, nil)
 		return nil, fmt.Errorf("orchestrator: manifest missing in configuration for vm %s", name)
 	}
 
+	pendingTap := e.startPrimaryTap(ctx, *vmRecord, userNetwork, networkCfg)
 	additionalDisks := buildAdditionalDisks(manifest)
 	overrideCloudInit := cfg.CloudInit
-	mergedCloudInit, record, seedDisk, err := e.prepareCloudInitSeed(ctx, vmRecord, manifest, overrideCloudInit)
+	seedCtx, seedSpan := tracing.Start(ctx, "orchestrator.StartVM.seed")
+	mergedCloudInit, record, seedDisk, err := e.prepareCloudInitSeed(seedCtx, vmRecord, manifest, overrideCloudInit, e.firmwareNetwork(manifest, vmRecord, networkCfg, userNetwork), vmconfig.API{Host: apiHost, Port: apiPort}, cfg.SSH)
+	seedSpan.End(err)
 	if err != nil {
-		_ = e.network.CleanupTap(ctx, tapName)
+		pendingTap.discard(ctx)
 		e.setVMState(ctx, vmRecord.ID, db.VMStatusStopped, nil)
 		return nil, err
 	}
 	cfg.CloudInit = mergedCloudInit
 	cloudInitToStore = record
 
-	netmask := formatNetmask(e.subnet.Mask)
+	tapName, err := pendingTap.wait()
+	if err != nil {
+		err = runtime.NewLaunchError(runtime.ErrorTapCreate, err)
+		e.publishLaunchFailure(ctx, vmRecord, err)
+		e.setVMState(ctx, vmRecord.ID, db.VMStatusStopped, nil)
+		return nil, err
+	}
+	if err := e.applyEgressPolicy(ctx, vmRecord.Name, tapName, networkCfg); err != nil {
+		_ = e.network.CleanupTap(ctx, tapName)
+		e.setVMState(ctx, vmRecord.ID, db.VMStatusStopped, nil)
+		return nil, err
+	}
+	extraNICs, nicArgs, err := e.prepareInterfaces(ctx, vmRecord.Name, networkCfg)
+	if err != nil {
+		_ = e.network.CleanupTap(ctx, tapName)
+		e.publishLaunchFailure(ctx, vmRecord, err)
+		e.setVMState(ctx, vmRecord.ID, db.VMStatusStopped, nil)
+		return nil, err
+	}
+	launched := false
+	defer func() {
+		if !launched {
+			e.releaseInterfaces(ctx, vmRecord.Name)
+			e.releaseDevices(ctx, vmRecord.Name)
+		}
+	}()
+
+	serialPath := filepath.Join(e.runtimeDir, fmt.Sprintf("%s.serial", vmRecord.Name))
+	serialPath = filepath.Clean(serialPath)
+	if !filepath.IsAbs(serialPath) {
+		absSerial, absErr := filepath.Abs(serialPath)
+		if absErr != nil {
+			e.setVMState(ctx, vmRecord.ID, db.VMStatusStopped, nil)
+			_ = e.network.CleanupTap(ctx, tapName)
+			return nil, fmt.Errorf("orchestrator: resolve serial socket path: %w", absErr)
+		}
+		serialPath = absSerial
+	}
+
+	gateway, netmask := e.guestAddressing(userNetwork)
 	spec := runtime.LaunchSpec{
 		Name:          vmRecord.Name,
 		CPUCores:      cfg.Resources.CPUCores,
@@ -1061,11 +1753,12 @@ func (e *engine) StartVM(ctx context.Context, name string) (*db.VM, error) {
 		TapDevice:     tapName,
 		MACAddress:    vmRecord.MACAddress,
 		IPAddress:     vmRecord.IPAddress,
-		Gateway:       e.hostIP.String(),
+		Gateway:       gateway,
 		Netmask:       netmask,
 		VsockCID:      vmRecord.VsockCID,
 		SerialSocket:  serialPath,
 	}
+	e.captureLogs(&spec)
 	spec.Disks = additionalDisks
 	if seedDisk != nil {
 		spec.SeedDisk = seedDisk
@@ -1080,6 +1773,12 @@ func (e *engine) StartVM(ctx context.Context, name string) (*db.VM, error) {
 	if pluginName != "" {
 		cmdArgs[pluginspec.PluginKey] = pluginName
 	}
+	if len(cfg.Bundles) > 0 {
+		cmdArgs[pluginspec.VMNameKey] = vmRecord.Name
+	}
+	for key, value := range secretMountArgs(vmRecord.Name, resolveSecretMounts(manifest, &cfg)) {
+		cmdArgs[key] = value
+	}
 	encodedManifest, err := pluginspec.Encode(*manifest)
 	if err != nil {
 		_ = e.network.CleanupTap(ctx, tapName)
@@ -1087,7 +1786,16 @@ func (e *engine) StartVM(ctx context.Context, name string) (*db.VM, error) {
 		return nil, fmt.Errorf("orchestrator: encode manifest: %w", err)
 	}
 	cmdArgs[pluginspec.CmdlineKey] = encodedManifest
+	for key, value := range nicArgs {
+		cmdArgs[key] = value
+	}
+	shares, shareArgs := launchShares(resolveShares(manifest, &cfg))
+	for key, value := range shareArgs {
+		cmdArgs[key] = value
+	}
 	spec.Args = cmdArgs
+	spec.Interfaces = extraNICs
+	spec.Shares = shares
 	// Allow both initramfs and rootfs to be provided by the manifest
 	if url := strings.TrimSpace(manifest.Initramfs.URL); url != "" {
 		spec.Initramfs = url
@@ -1110,7 +1818,15 @@ func (e *engine) StartVM(ctx context.Context, name string) (*db.VM, error) {
 			spec.RootFSChecksum = strings.TrimSpace(cfg.RootFS.Checksum)
 		}
 	}
-	spec.KernelOverride = strings.TrimSpace(cfg.KernelOverride)
+	spec.KernelOverride, spec.KernelChecksum = effectiveKernel(manifest, cfg)
+	if err := applyFirmwareBoot(manifest, effectiveRootFS(manifest, cfg), &spec); err != nil {
+		_ = e.network.CleanupTap(ctx, tapName)
+		if seedDisk != nil {
+			_ = os.Remove(seedDisk.Path)
+		}
+		e.setVMState(ctx, vmRecord.ID, db.VMStatusStopped, nil)
+		return nil, err
+	}
 	if spec.RootFS != "" {
 		if _, ok := cmdArgs[pluginspec.RootFSDeviceKey]; !ok {
 			cmdArgs[pluginspec.RootFSDeviceKey] = "vda"
@@ -1119,6 +1835,22 @@ func (e *engine) StartVM(ctx context.Context, name string) (*db.VM, error) {
 			cmdArgs[pluginspec.RootFSFSTypeKey] = "ext4"
 		}
 	}
+	if err := e.applyScratchDisk(ctx, vmRecord.Name, cfg.ScratchDisk, &spec, cmdArgs); err != nil {
+		_ = e.network.CleanupTap(ctx, tapName)
+		if seedDisk != nil {
+			_ = os.Remove(seedDisk.Path)
+		}
+		e.setVMState(ctx, vmRecord.ID, db.VMStatusStopped, nil)
+		return nil, err
+	}
+	if err := e.applyRootFSOverlay(ctx, vmRecord.Name, effectiveRootFS(manifest, cfg), &spec, cmdArgs); err != nil {
+		_ = e.network.CleanupTap(ctx, tapName)
+		if seedDisk != nil {
+			_ = os.Remove(seedDisk.Path)
+		}
+		e.setVMState(ctx, vmRecord.ID, db.VMStatusStopped, nil)
+		return nil, err
+	}
 
 	// Handle VFIO device passthrough if configured (prefer VM-level overrides)
 	var devCfg *pluginspec.DeviceConfig
@@ -1127,8 +1859,24 @@ func (e *engine) StartVM(ctx context.Context, name string) (*db.VM, error) {
 	} else if manifest != nil && manifest.Devices != nil {
 		devCfg = manifest.Devices
 	}
-	if devCfg != nil && len(devCfg.PCIPassthrough) > 0 {
-		pciAddrs := devCfg.PCIPassthrough
+	pciAddrs, err := e.allocateDevices(ctx, vmRecord.Name, devCfg, cfg.Resources.NUMANode)
+	if err != nil {
+		_ = e.network.CleanupTap(ctx, tapName)
+		if seedDisk != nil {
+			_ = os.Remove(seedDisk.Path)
+		}
+		e.setVMState(ctx, vmRecord.ID, db.VMStatusStopped, nil)
+		return nil, err
+	}
+	if err := e.resolvePlacement(cfg.Resources, pciAddrs, &spec); err != nil {
+		_ = e.network.CleanupTap(ctx, tapName)
+		if seedDisk != nil {
+			_ = os.Remove(seedDisk.Path)
+		}
+		e.setVMState(ctx, vmRecord.ID, db.VMStatusStopped, nil)
+		return nil, err
+	}
+	if len(pciAddrs) > 0 {
 		allowlist := devCfg.Allowlist
 		e.logger.Info("vfio passthrough requested", "vm", vmRecord.Name, "devices", pciAddrs)
 		if err := e.vfioMgr.ValidateDevices(pciAddrs, allowlist); err != nil {
@@ -1139,6 +1887,14 @@ func (e *engine) StartVM(ctx context.Context, name string) (*db.VM, error) {
 			e.setVMState(ctx, vmRecord.ID, db.VMStatusStopped, nil)
 			return nil, fmt.Errorf("device validation failed: %w", err)
 		}
+		if err := e.claimDevices(ctx, vmRecord, pciAddrs); err != nil {
+			_ = e.network.CleanupTap(ctx, tapName)
+			if seedDisk != nil {
+				_ = os.Remove(seedDisk.Path)
+			}
+			e.setVMState(ctx, vmRecord.ID, db.VMStatusStopped, nil)
+			return nil, err
+		}
 		if err := e.vfioMgr.BindDevices(pciAddrs); err != nil {
 			_ = e.network.CleanupTap(ctx, tapName)
 			if seedDisk != nil {
@@ -1189,12 +1945,15 @@ func (e *engine) StartVM(ctx context.Context, name string) (*db.VM, error) {
 	}
 
 	launchCtx := e.launchContext()
+	_, launchSpan := tracing.Start(ctx, "orchestrator.StartVM.launch")
 	instance, err := e.launcher.Launch(launchCtx, spec)
+	launchSpan.End(err)
 	if err != nil {
 		if seedDisk != nil {
 			_ = os.Remove(seedDisk.Path)
 		}
 		_ = e.network.CleanupTap(ctx, tapName)
+		e.publishLaunchFailure(ctx, vmRecord, err)
 		e.setVMState(ctx, vmRecord.ID, db.VMStatusStopped, nil)
 		return nil, err
 	}
@@ -1227,33 +1986,59 @@ func (e *engine) StartVM(ctx context.Context, name string) (*db.VM, error) {
 		}
 	}
 
+	if err := e.applyPortForwards(ctx, *vmRecord, cfg.Ports); err != nil {
+		_ = instance.Stop(ctx)
+		_ = e.network.CleanupTap(ctx, tapName)
+		if seedDisk != nil {
+			_ = os.Remove(seedDisk.Path)
+		}
+		if len(cfg.Expose) > 0 {
+			e.removeDriftRoutes(ctx, vmRecord.Name, cfg.Expose)
+		}
+		e.setVMState(ctx, vmRecord.ID, db.VMStatusStopped, nil)
+		return nil, err
+	}
+
+	e.addNeighborProxies(ctx, *vmRecord)
+
 	e.mu.Lock()
 	seedPath := ""
 	if seedDisk != nil {
 		seedPath = seedDisk.Path
 	}
-	handle := processHandle{instance: instance, tapName: tapName, serial: spec.SerialSocket, seedPath: seedPath}
+	handle := processHandle{instance: instance, tapName: tapName, serial: spec.SerialSocket, seedPath: seedPath, ready: new(atomic.Bool)}
 	e.instances[vmRecord.Name] = handle
 	e.mu.Unlock()
+	launched = true
 
 	e.monitorInstance(vmRecord.Name, handle)
+	e.watchReadiness(*vmRecord, handle, spec.BootFirmware)
 
 	vmRecord.Status = db.VMStatusRunning
 	vmRecord.PID = &pid
 	vmRecord.SerialSocket = spec.SerialSocket
 	vmRecord.CPUCores = cfg.Resources.CPUCores
 	vmRecord.MemoryMB = cfg.Resources.MemoryMB
+	e.armTTL(ctx, vmRecord, cfg)
 
 	e.publishEvent(ctx, orchestratorevents.TypeVMRunning, orchestratorevents.VMStatusRunning, vmRecord, "vm started")
 	return vmRecord, nil
 }
 
 func (e *engine) StopVM(ctx context.Context, name string) (*db.VM, error) {
+	ctx, span := tracing.Start(ctx, "orchestrator.StopVM", tracing.String("vm.name", name))
+	vm, err := e.stopVM(ctx, name)
+	span.End(err)
+	return vm, err
+}
+
+func (e *engine) stopVM(ctx context.Context, name string) (*db.VM, error) {
 	var (
 		handle   processHandle
 		exists   bool
 		vmRecord *db.VM
 		expose   []vmconfig.Expose
+		hooks    []pluginspec.Hook
 	)
 
 	e.mu.Lock()
@@ -1277,7 +2062,14 @@ func (e *engine) StopVM(ctx context.Context, name string) (*db.VM, error) {
 		if cfgRecord, cfgErr := q.VMConfigs().GetCurrent(ctx, vm.ID); cfgErr == nil && cfgRecord != nil {
 			if versioned, convErr := vmconfig.FromDB(*cfgRecord); convErr == nil {
 				expose = append([]vmconfig.Expose(nil), versioned.Config.Expose...)
+				hooks = preStopHooks(versioned.Config)
+			}
+		}
+		if vm.ExpiresAt != nil {
+			if err := vmRepo.SetExpiresAt(ctx, vm.ID, nil); err != nil {
+				return err
 			}
+			vm.ExpiresAt = nil
 		}
 		return vmRepo.UpdateRuntimeState(ctx, vm.ID, db.VMStatusStopped, nil)
 	})
@@ -1291,7 +2083,9 @@ func (e *engine) StopVM(ctx context.Context, name string) (*db.VM, error) {
 		return nil, err
 	}
 
+	var hookResults []pluginspec.HookResult
 	if exists {
+		hookResults = e.runPreStopHooks(ctx, *vmRecord, hooks)
 		if stopErr := handle.instance.Stop(ctx); stopErr != nil {
 			e.logger.Error("stop instance", "vm", "name", "error", stopErr)
 		}
@@ -1309,6 +2103,12 @@ func (e *engine) StopVM(ctx context.Context, name string) (*db.VM, error) {
 	}
 
 	if vmRecord != nil {
+		if exists {
+			e.removeNeighborProxies(ctx, *vmRecord)
+			e.removePortForwards(ctx, name)
+			e.releaseInterfaces(ctx, name)
+			e.releaseDevices(ctx, name)
+		}
 		vmRecord.Status = db.VMStatusStopped
 		vmRecord.PID = nil
 	}
@@ -1317,7 +2117,10 @@ func (e *engine) StopVM(ctx context.Context, name string) (*db.VM, error) {
 		e.removeDriftRoutes(ctx, name, expose)
 	}
 
-	e.publishEvent(ctx, orchestratorevents.TypeVMStopped, orchestratorevents.VMStatusStopped, vmRecord, "vm stopped")
+	e.publishStopEvent(ctx, orchestratorevents.TypeVMStopped, orchestratorevents.VMStatusStopped, vmRecord, "vm stopped", hookResults)
+	if exists {
+		e.stopDependents(name)
+	}
 	return vmRecord, nil
 }
 
@@ -1336,6 +2139,9 @@ func (e *engine) CreateDeployment(ctx context.Context, req CreateDeploymentReque
 	if req.Replicas < 0 {
 		return nil, fmt.Errorf("orchestrator: replicas must be >= 0")
 	}
+	if req.MaxSurge < 0 {
+		return nil, fmt.Errorf("orchestrator: max surge must be >= 0")
+	}
 
 	config, err := e.normalizeDeploymentConfig(ctx, req.Config)
 	if err != nil {
@@ -1356,10 +2162,21 @@ func (e *engine) CreateDeployment(ctx context.Context, req CreateDeploymentReque
 		if existing != nil {
 			return fmt.Errorf("%w: %s", ErrDeploymentExists, name)
 		}
+		limits, err := pluginLimits(ctx, q, config.Plugin, config.Manifest)
+		if err != nil {
+			return err
+		}
+		if err := checkVMQuota(ctx, q, config.Plugin, limits, req.Replicas, 0); err != nil {
+			return err
+		}
+		if err := e.checkCapacity(ctx, q, req.Replicas*config.Resources.CPUCores, req.Replicas*config.Resources.MemoryMB); err != nil {
+			return err
+		}
 		group := db.VMGroup{
 			Name:       name,
 			ConfigJSON: configPayload,
 			Replicas:   req.Replicas,
+			MaxSurge:   req.MaxSurge,
 		}
 		id, err := repo.Create(ctx, &group)
 		if err != nil {
@@ -1371,7 +2188,7 @@ func (e *engine) CreateDeployment(ctx context.Context, req CreateDeploymentReque
 		return nil, err
 	}
 
-	return e.reconcileDeploymentByID(ctx, groupID)
+	return e.reconcileAndWait(ctx, groupID)
 }
 
 func (e *engine) ListDeployments(ctx context.Context) ([]Deployment, error) {
@@ -1420,6 +2237,23 @@ func (e *engine) ScaleDeployment(ctx context.Context, name string, replicas int)
 		if group == nil {
 			return fmt.Errorf("%w: %s", ErrDeploymentNotFound, name)
 		}
+		if replicas > group.Replicas {
+			config, err := vmconfig.Unmarshal(group.ConfigJSON)
+			if err != nil {
+				return err
+			}
+			limits, err := pluginLimits(ctx, q, config.Plugin, config.Manifest)
+			if err != nil {
+				return err
+			}
+			if err := checkVMQuota(ctx, q, config.Plugin, limits, replicas, group.ID); err != nil {
+				return err
+			}
+			added := replicas - group.Replicas
+			if err := e.checkCapacity(ctx, q, added*config.Resources.CPUCores, added*config.Resources.MemoryMB); err != nil {
+				return err
+			}
+		}
 		if err := repo.UpdateReplicas(ctx, group.ID, replicas); err != nil {
 			return err
 		}
@@ -1429,7 +2263,7 @@ func (e *engine) ScaleDeployment(ctx context.Context, name string, replicas int)
 		return nil, err
 	}
 
-	return e.reconcileDeploymentByID(ctx, groupID)
+	return e.reconcileAndWait(ctx, groupID)
 }
 
 func (e *engine) DeleteDeployment(ctx context.Context, name string) error {
@@ -1460,15 +2294,23 @@ func (e *engine) DeleteDeployment(ctx context.Context, name string) error {
 		return err
 	}
 
+	unlock := e.lockDeployment(group.ID)
 	for _, vmName := range vmNames {
 		if _, err := e.destroyVM(ctx, vmName, false); err != nil {
 			e.logger.Error("delete deployment vm", "deployment", name, "vm", vmName, "error", err)
 		}
 	}
 
-	return e.store.WithTx(ctx, func(q db.Queries) error {
+	if err := e.store.WithTx(ctx, func(q db.Queries) error {
 		return q.VMGroups().Delete(ctx, group.ID)
-	})
+	}); err != nil {
+		unlock()
+		return err
+	}
+	unlock()
+	e.forgetDeployment(group.ID)
+	e.publishDeploymentEvent(ctx, orchestratorevents.TypeDeploymentDeleted, Deployment{Name: group.Name})
+	return nil
 }
 
 func (e *engine) Store() db.Store {
@@ -1495,13 +2337,33 @@ func (e *engine) rollbackCreate(ctx context.Context, vm *db.VM) {
 		if err := q.VirtualMachines().Delete(ctx, vm.ID); err != nil {
 			return err
 		}
-		return q.IPAllocations().Release(ctx, vm.IPAddress)
+		return e.releaseAddresses(ctx, q, *vm)
 	}); err != nil {
 		e.logger.Error("rollback create", "vm", vm.Name, "error", err)
 	}
+	_ = os.Remove(scratchDiskPath(e.runtimeDir, vm.Name))
+	e.deleteSSHKey(ctx, vm.Name)
+}
+
+func (e *engine) releaseAddresses(ctx context.Context, q db.Queries, vm db.VM) error {
+	if ip := net.ParseIP(vm.IPAddress); ip != nil && !e.subnet.Contains(ip) {
+		// Addresses on user networks are leased per network and dropped
+		// together with the VM row.
+		return nil
+	}
+	if err := e.ipam.Release(ctx, q, ipam.Request{VMName: vm.Name, IPAddress: vm.IPAddress}); err != nil {
+		return err
+	}
+	if vm.IPv6Address != "" {
+		return e.ipam6.Release(ctx, q, ipam.Request{VMName: vm.Name, IPAddress: vm.IPv6Address})
+	}
+	return nil
 }
 
 func (e *engine) monitorInstance(name string, handle processHandle) {
+	exited := make(chan struct{})
+	go e.teeConsole(name, handle.serial, exited)
+	e.collectLogs(name, handle, exited)
 	go func() {
 		var expose []vmconfig.Expose
 		waitCh := handle.instance.Wait()
@@ -1511,6 +2373,7 @@ func (e *engine) monitorInstance(name string, handle processHandle) {
 				exitErr = result
 			}
 		}
+		close(exited)
 
 		e.mu.Lock()
 		stored, exists := e.instances[name]
@@ -1520,6 +2383,7 @@ func (e *engine) monitorInstance(name string, handle processHandle) {
 		}
 		delete(e.instances, name)
 		e.mu.Unlock()
+		defer e.stopDependents(name)
 
 		ctx := context.Background()
 		status := db.VMStatusStopped
@@ -1564,6 +2428,9 @@ func (e *engine) monitorInstance(name string, handle processHandle) {
 		if len(expose) > 0 {
 			e.removeDriftRoutes(ctx, name, expose)
 		}
+		e.removePortForwards(ctx, name)
+		e.releaseInterfaces(ctx, name)
+		e.releaseDevices(ctx, name)
 
 		if exitErr != nil {
 			e.logger.Warn("vm exited unexpectedly", "vm", name, "error", exitErr)
@@ -1572,10 +2439,13 @@ func (e *engine) monitorInstance(name string, handle processHandle) {
 				vmRecord.PID = nil
 			}
 			e.publishEvent(ctx, orchestratorevents.TypeVMCrashed, orchestratorevents.VMStatusCrashed, vmRecord, exitErr.Error())
+			if vmRecord != nil {
+				e.replaceCrashedReplica(ctx, *vmRecord)
+			}
 		} else {
 
 			if vmRecord != nil && vmRecord.GroupID != nil {
-				if _, err := e.reconcileDeploymentByID(ctx, *vmRecord.GroupID); err != nil {
+				if _, err := e.reconcileOrHold(ctx, *vmRecord.GroupID); err != nil {
 					e.logger.Error("reconcile deployment after vm exit", "vm", name, "error", err)
 				}
 			}
@@ -1590,6 +2460,11 @@ func (e *engine) monitorInstance(name string, handle processHandle) {
 }
 
 func (e *engine) publishEvent(ctx context.Context, typ string, status orchestratorevents.VMStatus, vm *db.VM, message string) {
+	e.publishStopEvent(ctx, typ, status, vm, message, nil)
+}
+
+// publishStopEvent is publishEvent with the results of pre-stop hooks attached.
+func (e *engine) publishStopEvent(ctx context.Context, typ string, status orchestratorevents.VMStatus, vm *db.VM, message string, hooks []pluginspec.HookResult) {
 	if e.bus == nil || vm == nil {
 		return
 	}
@@ -1602,6 +2477,7 @@ func (e *engine) publishEvent(ctx context.Context, typ string, status orchestrat
 		MAC:       vm.MACAddress,
 		Timestamp: time.Now().UTC(),
 		Message:   message,
+		Hooks:     hooks,
 	}
 	if vm.PID != nil {
 		pid := *vm.PID
@@ -1612,6 +2488,51 @@ func (e *engine) publishEvent(ctx context.Context, typ string, status orchestrat
 	}
 }
 
+// publishConfigUpdated reports that a VM moved to a new configuration version.
+func (e *engine) publishConfigUpdated(ctx context.Context, vm *db.VM, version int) {
+	if e.bus == nil || vm == nil {
+		return
+	}
+	event := orchestratorevents.VMEvent{
+		Type:          orchestratorevents.TypeVMConfigUpdated,
+		Name:          vm.Name,
+		Status:        orchestratorevents.VMStatus(vm.Status),
+		IPAddress:     vm.IPAddress,
+		MAC:           vm.MACAddress,
+		PID:           vm.PID,
+		Timestamp:     time.Now().UTC(),
+		Message:       fmt.Sprintf("configuration version %d", version),
+		ConfigVersion: version,
+	}
+	if err := e.bus.Publish(ctx, orchestratorevents.TopicVMEvents, event); err != nil {
+		e.logger.Error("publish vm event", "type", event.Type, "vm", vm.Name, "error", err)
+	}
+}
+
+// publishLaunchFailure reports a VM that failed to boot, including the failure
+// code and remediation hint when the error was classified.
+func (e *engine) publishLaunchFailure(ctx context.Context, vm *db.VM, err error) {
+	if e.bus == nil || vm == nil || err == nil {
+		return
+	}
+	event := orchestratorevents.VMEvent{
+		Type:      orchestratorevents.TypeVMFailed,
+		Name:      vm.Name,
+		Status:    orchestratorevents.VMStatusStopped,
+		IPAddress: vm.IPAddress,
+		MAC:       vm.MACAddress,
+		Timestamp: time.Now().UTC(),
+		Message:   err.Error(),
+	}
+	if launchErr, ok := runtime.AsLaunchError(err); ok {
+		event.Code = string(launchErr.Code)
+		event.Hint = launchErr.Hint
+	}
+	if pubErr := e.bus.Publish(ctx, orchestratorevents.TopicVMEvents, event); pubErr != nil {
+		e.logger.Error("publish vm event", "type", event.Type, "vm", vm.Name, "error", pubErr)
+	}
+}
+
 func (e *engine) reconcileDeploymentByID(ctx context.Context, groupID int64) (*Deployment, error) {
 	group, err := e.store.Queries().VMGroups().GetByID(ctx, groupID)
 	if err != nil {
@@ -1621,10 +2542,10 @@ func (e *engine) reconcileDeploymentByID(ctx context.Context, groupID int64) (*D
 		return nil, fmt.Errorf("%w: id=%d", ErrDeploymentNotFound, groupID)
 	}
 	deployment, err := e.reconcileDeployment(ctx, *group)
-	if err != nil {
+	if deployment.Name == "" {
 		return nil, err
 	}
-	return &deployment, nil
+	return &deployment, err
 }
 
 func (e *engine) reconcileDeployment(ctx context.Context, group db.VMGroup) (Deployment, error) {
@@ -1645,7 +2566,9 @@ func (e *engine) reconcileDeployment(ctx context.Context, group db.VMGroup) (Dep
 	current := len(vms)
 	desired := group.Replicas
 
-	if current > desired {
+	if current > desired && e.deferToRollout(group.ID) {
+		e.logger.Debug("scale down deferred to rollout", "deployment", group.Name, "replicas", current, "desired", desired)
+	} else if current > desired {
 		sort.Slice(vms, func(i, j int) bool {
 			iIdx, _ := parseReplicaIndex(group.Name, vms[i].Name)
 			jIdx, _ := parseReplicaIndex(group.Name, vms[j].Name)
@@ -1662,46 +2585,12 @@ func (e *engine) reconcileDeployment(ctx context.Context, group db.VMGroup) (Dep
 		}
 	}
 
+	var replicaErr error
 	if desired > len(vms) {
-		existing := make(map[int]bool, len(vms))
-		for _, vm := range vms {
-			if idx, ok := parseReplicaIndex(group.Name, vm.Name); ok {
-				existing[idx] = true
-			}
-		}
-		groupID := group.ID
-		for i := 1; len(existing) < desired; i++ {
-			if existing[i] {
-				continue
-			}
-			vmName := replicaName(group.Name, i)
-			manifestCopy := *config.Manifest
-			manifestCopy.Normalize()
-			cfgClone := config.Clone()
-			cfgClone.Normalize()
-			request := CreateVMRequest{
-				Name:              vmName,
-				Plugin:            cfgClone.Plugin,
-				Runtime:           cfgClone.Runtime,
-				CPUCores:          cfgClone.Resources.CPUCores,
-				MemoryMB:          cfgClone.Resources.MemoryMB,
-				KernelCmdlineHint: cfgClone.KernelCmdline,
-				Manifest:          &manifestCopy,
-				APIHost:           cfgClone.API.Host,
-				APIPort:           cfgClone.API.Port,
-				Config:            &cfgClone,
-			}
-			request.GroupID = &groupID
-			if _, err := e.CreateVM(ctx, request); err != nil {
-				e.logger.Error("scale up deployment", "deployment", group.Name, "vm", vmName, "error", err)
-				break
-			} else {
-				existing[i] = true
-			}
-		}
-		vms, err = vmRepo.ListByGroupID(ctx, group.ID)
-		if err != nil {
-			return Deployment{}, err
+		if group.MaxSurge > 0 {
+			e.startRollout(group.ID)
+		} else if created, err := e.createReplicas(ctx, group, config, vms, desired-len(vms)); err != nil {
+			replicaErr = errReplicasFailed(group.Name, desired-len(vms)-len(created), err)
 		}
 	}
 
@@ -1709,7 +2598,87 @@ func (e *engine) reconcileDeployment(ctx context.Context, group db.VMGroup) (Dep
 	if err != nil {
 		return Deployment{}, err
 	}
-	return deployment, nil
+	e.publishDeploymentEvent(ctx, orchestratorevents.TypeDeploymentReconciled, deployment)
+	return deployment, replicaErr
+}
+
+func (e *engine) publishDeploymentEvent(ctx context.Context, typ string, deployment Deployment) {
+	e.publishDeploymentMessage(ctx, typ, deployment, "")
+}
+
+func (e *engine) publishDeploymentMessage(ctx context.Context, typ string, deployment Deployment, message string) {
+	if e.bus == nil {
+		return
+	}
+	event := orchestratorevents.DeploymentEvent{
+		Type:            typ,
+		Name:            deployment.Name,
+		DesiredReplicas: deployment.DesiredReplicas,
+		ReadyReplicas:   deployment.ReadyReplicas,
+		Timestamp:       time.Now().UTC(),
+		Message:         message,
+	}
+	if err := e.bus.Publish(ctx, orchestratorevents.TopicDeploymentEvents, event); err != nil {
+		e.logger.Error("publish deployment event", "type", typ, "deployment", deployment.Name, "error", err)
+	}
+}
+
+// createReplicas launches count new replicas for the group, filling the lowest
+// free indices first. It stops at the first failure and returns the names of
+// the replicas that were created along with that failure.
+func (e *engine) createReplicas(ctx context.Context, group db.VMGroup, config vmconfig.Config, vms []db.VM, count int) ([]string, error) {
+	existing := make(map[int]bool, len(vms))
+	for _, vm := range vms {
+		if idx, ok := parseReplicaIndex(group.Name, vm.Name); ok {
+			existing[idx] = true
+		}
+	}
+	var placement *spreadPlacement
+	if config.Spread != nil {
+		var err error
+		placement, err = e.newSpreadPlacement(ctx, *config.Spread, vms)
+		if err != nil {
+			e.logger.Error("scale up deployment", "deployment", group.Name, "error", err)
+			return nil, err
+		}
+	}
+	groupID := group.ID
+	var created []string
+	for i := 1; len(created) < count; i++ {
+		if existing[i] {
+			continue
+		}
+		vmName := replicaName(group.Name, i)
+		manifestCopy := *config.Manifest
+		manifestCopy.Normalize()
+		cfgClone := config.Clone()
+		cfgClone.Normalize()
+		cfgClone.Spread = nil
+		if placement != nil {
+			placement.place(&cfgClone)
+		}
+		request := CreateVMRequest{
+			Name:              vmName,
+			Plugin:            cfgClone.Plugin,
+			Runtime:           cfgClone.Runtime,
+			CPUCores:          cfgClone.Resources.CPUCores,
+			MemoryMB:          cfgClone.Resources.MemoryMB,
+			KernelCmdlineHint: cfgClone.KernelCmdline,
+			Manifest:          &manifestCopy,
+			APIHost:           cfgClone.API.Host,
+			APIPort:           cfgClone.API.Port,
+			Config:            &cfgClone,
+		}
+		request.GroupID = &groupID
+		request.Labels = map[string]string{DeploymentLabel: group.Name}
+		if _, err := e.CreateVM(ctx, request); err != nil {
+			e.logger.Error("scale up deployment", "deployment", group.Name, "vm", vmName, "error", err)
+			return created, err
+		}
+		existing[i] = true
+		created = append(created, vmName)
+	}
+	return created, nil
 }
 
 func (e *engine) buildDeployment(ctx context.Context, group db.VMGroup) (Deployment, error) {
@@ -1723,18 +2692,22 @@ func (e *engine) buildDeployment(ctx context.Context, group db.VMGroup) (Deploym
 	}
 	ready := 0
 	for _, vm := range vms {
-		if vm.Status == db.VMStatusRunning {
+		if vm.Status == db.VMStatusRunning && e.isReady(vm.Name) {
 			ready++
 		}
 	}
-	return Deployment{
+	deployment := Deployment{
 		Name:            group.Name,
 		DesiredReplicas: group.Replicas,
 		ReadyReplicas:   ready,
+		MaxSurge:        group.MaxSurge,
 		Config:          config,
+		Reconcile:       e.reconcileStatus(group.ID),
 		CreatedAt:       group.CreatedAt,
 		UpdatedAt:       group.UpdatedAt,
-	}, nil
+	}
+	deployment.Condition = deploymentCondition(deployment)
+	return deployment, nil
 }
 
 func (e *engine) normalizeDeploymentConfig(ctx context.Context, cfg vmconfig.Config) (vmconfig.Config, error) {
@@ -1749,21 +2722,11 @@ func (e *engine) normalizeDeploymentConfig(ctx context.Context, cfg vmconfig.Con
 	// If plugin is specified but manifest is missing, load manifest from installed plugin
 	pluginName := strings.TrimSpace(clone.Plugin)
 	if pluginName != "" && clone.Manifest == nil {
-		plugin, err := e.store.Queries().Plugins().GetByName(ctx, pluginName)
+		manifest, err := e.installedManifest(ctx, pluginName)
 		if err != nil {
-			return vmconfig.Config{}, fmt.Errorf("lookup plugin %s: %w", pluginName, err)
-		}
-		if plugin == nil {
-			return vmconfig.Config{}, fmt.Errorf("plugin %s not installed", pluginName)
-		}
-
-		// Parse manifest from plugin metadata
-		var manifest pluginspec.Manifest
-		if err := json.Unmarshal(plugin.Metadata, &manifest); err != nil {
-			return vmconfig.Config{}, fmt.Errorf("parse plugin %s manifest: %w", pluginName, err)
+			return vmconfig.Config{}, err
 		}
-		manifest.Normalize()
-		clone.Manifest = &manifest
+		clone.Manifest = manifest
 	}
 
 	// Infer plugin name from manifest if not set
@@ -1774,13 +2737,59 @@ func (e *engine) normalizeDeploymentConfig(ctx context.Context, cfg vmconfig.Con
 	if err := clone.Validate(); err != nil {
 		return vmconfig.Config{}, err
 	}
+	limits, err := pluginLimits(ctx, e.store.Queries(), clone.Plugin, clone.Manifest)
+	if err != nil {
+		return vmconfig.Config{}, err
+	}
+	if err := checkResourceLimits(clone.Plugin, limits, clone.Resources.CPUCores, clone.Resources.MemoryMB); err != nil {
+		return vmconfig.Config{}, err
+	}
+	if err := e.checkConfigBundles(ctx, clone.Bundles); err != nil {
+		return vmconfig.Config{}, err
+	}
+	if err := e.checkSpread(ctx, clone); err != nil {
+		return vmconfig.Config{}, err
+	}
+	if err := e.CheckHostFeatures(clone.Manifest); err != nil {
+		return vmconfig.Config{}, err
+	}
 	return clone, nil
 }
 
-func (e *engine) prepareCloudInitSeed(ctx context.Context, vm *db.VM, manifest *pluginspec.Manifest, override *pluginspec.CloudInit) (*pluginspec.CloudInit, *db.VMCloudInit, *runtime.Disk, error) {
+// installedManifest returns the manifest of an installed plugin.
+func (e *engine) installedManifest(ctx context.Context, pluginName string) (*pluginspec.Manifest, error) {
+	plugin, err := e.store.Queries().Plugins().GetByName(ctx, pluginName)
+	if err != nil {
+		return nil, fmt.Errorf("lookup plugin %s: %w", pluginName, err)
+	}
+	if plugin == nil {
+		return nil, fmt.Errorf("plugin %s not installed", pluginName)
+	}
+
+	// Parse manifest from plugin metadata
+	var manifest pluginspec.Manifest
+	if err := json.Unmarshal(plugin.Metadata, &manifest); err != nil {
+		return nil, fmt.Errorf("parse plugin %s manifest: %w", pluginName, err)
+	}
+	manifest.Normalize()
+	return &manifest, nil
+}
+
+// prepareCloudInitSeed builds the VM's NoCloud seed image from the manifest's
+// cloud-init config merged with override. Firmware-booted VMs always get a
+// seed, since cloud-init is what configures them, and so do VMs with SSH
+// keys; networkConfig is seeded when the merged config has none. Template
+// documents and SSH keys are rendered into the seed only; the returned
+// config and record keep the templates, so secret values are never stored
+// with the VM.
+func (e *engine) prepareCloudInitSeed(ctx context.Context, vm *db.VM, manifest *pluginspec.Manifest, override *pluginspec.CloudInit, networkConfig string, api vmconfig.API, ssh *vmconfig.SSH) (*pluginspec.CloudInit, *db.VMCloudInit, *runtime.Disk, error) {
 	if vm == nil {
 		return nil, nil, nil, fmt.Errorf("prepare cloud-init: vm required")
 	}
+	publicKeys, err := e.sshAuthorizedKeys(ctx, vm, ssh)
+	if err != nil {
+		return nil, nil, nil, err
+	}
 
 	base := (*pluginspec.CloudInit)(nil)
 	if manifest != nil && manifest.CloudInit != nil {
@@ -1789,6 +2798,9 @@ func (e *engine) prepareCloudInitSeed(ctx context.Context, vm *db.VM, manifest *
 		base = &copy
 	}
 	merged := mergeCloudInit(base, override)
+	if merged == nil && (manifest.FirmwareBoot() || len(publicKeys) > 0) {
+		merged = &pluginspec.CloudInit{Datasource: "NoCloud", SeedMode: "vfat"}
+	}
 	if merged == nil {
 		if vm.ID != 0 {
 			queries := e.store.Queries()
@@ -1823,7 +2835,7 @@ func (e *engine) prepareCloudInitSeed(ctx context.Context, vm *db.VM, manifest *
 	if err := os.MkdirAll(seedsDir, 0o755); err != nil {
 		return nil, nil, nil, fmt.Errorf("prepare cloud-init: ensure seeds dir: %w", err)
 	}
-	seedPath := filepath.Join(seedsDir, fmt.Sprintf("%s-seed.img", vm.Name))
+	seedPath := filepath.Join(seedsDir, vm.Name+seedImageSuffix)
 
 	input := cloudinit.SeedInput{
 		InstanceID:    fmt.Sprintf("volant-%d", vm.ID),
@@ -1831,8 +2843,16 @@ func (e *engine) prepareCloudInitSeed(ctx context.Context, vm *db.VM, manifest *
 		UserData:      strings.TrimSpace(merged.UserData.Content),
 		MetaData:      strings.TrimSpace(merged.MetaData.Content),
 		NetworkConfig: strings.TrimSpace(merged.NetworkCfg.Content),
+		PublicKeys:    publicKeys,
+	}
+	if input.NetworkConfig == "" {
+		input.NetworkConfig = strings.TrimSpace(networkConfig)
 	}
-	if err := cloudinit.Build(ctx, input, seedPath); err != nil {
+	seed, err := e.renderCloudInit(ctx, vm, manifest, merged, input, api)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if err := e.seeds.Build(ctx, seed, seedPath); err != nil {
 		return nil, nil, nil, fmt.Errorf("cloud-init build: %w", err)
 	}
 
@@ -1860,6 +2880,54 @@ func (e *engine) prepareCloudInitSeed(ctx context.Context, vm *db.VM, manifest *
 	return merged, record, seedDisk, nil
 }
 
+// renderCloudInit renders the documents of input that merged marks as
+// templates.
+func (e *engine) renderCloudInit(ctx context.Context, vm *db.VM, manifest *pluginspec.Manifest, merged *pluginspec.CloudInit, input cloudinit.SeedInput, api vmconfig.API) (cloudinit.SeedInput, error) {
+	if !merged.UserData.Template && !merged.MetaData.Template && !merged.NetworkCfg.Template {
+		return input, nil
+	}
+	data := cloudinit.TemplateData{
+		Name:       vm.Name,
+		Hostname:   vm.Name,
+		InstanceID: input.InstanceID,
+		Plugin:     vm.Runtime,
+		IP:         vm.IPAddress,
+		IPv6:       vm.IPv6Address,
+		MAC:        vm.MACAddress,
+		APIHost:    api.Host,
+		APIPort:    api.Port,
+		Labels:     vm.Labels,
+	}
+	if manifest != nil && manifest.Name != "" {
+		data.Plugin = manifest.Name
+	}
+	secret := func(name string) (string, error) {
+		if e.secrets == nil {
+			return "", errors.New("secrets unavailable")
+		}
+		return e.openConfigSecret(ctx, name, secrets.Access{VM: vm.Name, Via: "cloud-init"})
+	}
+	for _, doc := range []struct {
+		field    string
+		template bool
+		content  *string
+	}{
+		{"user_data", merged.UserData.Template, &input.UserData},
+		{"meta_data", merged.MetaData.Template, &input.MetaData},
+		{"network_config", merged.NetworkCfg.Template, &input.NetworkConfig},
+	} {
+		if !doc.template {
+			continue
+		}
+		rendered, err := cloudinit.Render(doc.field, *doc.content, data, secret)
+		if err != nil {
+			return input, fmt.Errorf("cloud-init render: %w", err)
+		}
+		*doc.content = rendered
+	}
+	return input, nil
+}
+
 func mergeCloudInit(base, override *pluginspec.CloudInit) *pluginspec.CloudInit {
 	if base == nil && override == nil {
 		return nil
@@ -1948,6 +3016,14 @@ func validateCreateRequest(req CreateVMRequest) error {
 	if req.MemoryMB <= 0 {
 		return fmt.Errorf("orchestrator: memory must be > 0")
 	}
+	if req.Config != nil && req.Config.Spread != nil {
+		return fmt.Errorf("orchestrator: spread applies to deployments, not single vms")
+	}
+	if req.Config != nil {
+		if err := pluginspec.ValidateTTL(req.Config.TTLSeconds, req.Config.TTLAction); err != nil {
+			return fmt.Errorf("orchestrator: %w", err)
+		}
+	}
 	return nil
 }
 
@@ -1970,7 +3046,13 @@ func sanitizeHostname(name string) string {
 }
 
 func buildKernelCmdline(ip, gateway, netmask, hostname, extra string) string {
-	base := fmt.Sprintf("console=ttyS0 reboot=k panic=1 quiet loglevel=1 i8042.noaux i8042.nokbd pci=lastbus=0 ip=%s::%s:%s:%s:eth0:off", ip, gateway, netmask, hostname)
+	addressing := fmt.Sprintf("ip=%s::%s:%s:%s:eth0:off", ip, gateway, netmask, hostname)
+	if isIPv6(ip) {
+		// The kernel's ip= autoconfiguration is IPv4-only; the agent applies
+		// IPv6 addresses itself.
+		addressing = ipv6KernelArgs(ip, netmask, gateway)
+	}
+	base := "console=ttyS0 reboot=k panic=1 quiet loglevel=1 i8042.noaux i8042.nokbd pci=lastbus=0 " + addressing
 	extra = strings.TrimSpace(extra)
 	if extra == "" {
 		return base
@@ -2018,7 +3100,7 @@ func cloneArgs(args map[string]string) map[string]string {
 func deriveIPPool(subnet *net.IPNet, hostIP net.IP) ([]string, error) {
 	ipv4 := subnet.IP.To4()
 	if ipv4 == nil {
-		return nil, fmt.Errorf("ipv6 subnets are not supported: %s", subnet)
+		return deriveIPv6Pool(subnet, hostIP)
 	}
 
 	ones, bits := subnet.Mask.Size()
@@ -2058,7 +3140,13 @@ func deriveIPPool(subnet *net.IPNet, hostIP net.IP) ([]string, error) {
 	return pool, nil
 }
 
+// formatNetmask renders an IPv4 mask in dotted form and an IPv6 mask as its
+// prefix length.
 func formatNetmask(mask net.IPMask) string {
+	if len(mask) == net.IPv6len {
+		ones, _ := mask.Size()
+		return strconv.Itoa(ones)
+	}
 	if len(mask) != 4 {
 		return "255.255.255.0"
 	}
@@ -2153,6 +3241,29 @@ func needsIPAllocation(netCfg *pluginspec.NetworkConfig) bool {
 	return mode == pluginspec.NetworkModeBridged || mode == ""
 }
 
+// isDHCPMode reports whether the guest configures its address over DHCP.
+func isDHCPMode(netCfg *pluginspec.NetworkConfig) bool {
+	if netCfg == nil {
+		return false
+	}
+	return pluginspec.NetworkMode(strings.ToLower(strings.TrimSpace(string(netCfg.Mode)))) == pluginspec.NetworkModeDHCP
+}
+
+// servesDHCP reports whether volantd reserves the address of a dhcp-mode VM
+// itself. Without the embedded server, addressing is left to external infra.
+func (e *engine) servesDHCP(netCfg *pluginspec.NetworkConfig) bool {
+	return e.dhcp && isDHCPMode(netCfg) && e.subnet.IP.To4() != nil
+}
+
+// staticAddress returns the address to pin on the kernel command line; dhcp
+// guests learn theirs from the DHCP server instead.
+func staticAddress(netCfg *pluginspec.NetworkConfig, ip string) string {
+	if isDHCPMode(netCfg) {
+		return ""
+	}
+	return ip
+}
+
 // needsTapDevice returns true if the network mode requires a tap device.
 func needsTapDevice(netCfg *pluginspec.NetworkConfig) bool {
 	if netCfg == nil {
@@ -2193,7 +3304,7 @@ func (e *engine) computeDriftRoutes(vm db.VM, netCfg *pluginspec.NetworkConfig,
 			protocol = "tcp"
 		}
 		switch protocol {
-		case "tcp", "udp":
+		case "tcp", "udp", "unix":
 		default:
 			return nil, fmt.Errorf("unsupported expose protocol %q", rule.Protocol)
 		}
@@ -2231,8 +3342,8 @@ func (e *engine) computeDriftRoutes(vm db.VM, netCfg *pluginspec.NetworkConfig,
 			backend.Type = routes.BackendBridge
 			backend.IP = parsed.To4().String()
 		case "vsock":
-			if protocol != "tcp" {
-				return nil, fmt.Errorf("vsock exposures require tcp protocol")
+			if protocol == "udp" {
+				return nil, fmt.Errorf("vsock exposures require tcp or unix protocol")
 			}
 			if vm.VsockCID == 0 {
 				return nil, fmt.Errorf("vm %s has no vsock cid assigned", vm.Name)
@@ -2242,6 +3353,9 @@ func (e *engine) computeDriftRoutes(vm db.VM, netCfg *pluginspec.NetworkConfig,
 		default:
 			return nil, fmt.Errorf("unsupported expose mode %q", rule.Mode)
 		}
+		if protocol == "unix" && backend.Type != routes.BackendVsock {
+			return nil, fmt.Errorf("unix exposures require vsock mode")
+		}
 
 		key := fmt.Sprintf("%s/%d", protocol, hostPort)
 		if _, ok := seen[key]; ok {
@@ -2253,6 +3367,7 @@ func (e *engine) computeDriftRoutes(vm db.VM, netCfg *pluginspec.NetworkConfig,
 			HostPort: uint16(hostPort),
 			Protocol: protocol,
 			Backend:  backend,
+			Socket:   strings.TrimSpace(rule.Socket),
 		})
 	}
 
@@ -2320,26 +3435,24 @@ func (e *engine) removeDriftRoutes(ctx context.Context, vmName string, exposes [
 // allocateNextCID finds the next available vsock CID starting from 3.
 // CIDs 0-2 are reserved: 0=hypervisor, 1=local, 2=host.
 func (e *engine) allocateNextCID(ctx context.Context, vmRepo db.VMRepository) (uint32, error) {
-	vms, err := vmRepo.List(ctx)
+	used, err := vmRepo.VsockCIDs(ctx)
 	if err != nil {
 		return 0, err
 	}
 
-	// Collect all used CIDs
-	used := make(map[uint32]bool)
-	for _, vm := range vms {
-		if vm.VsockCID > 0 {
-			used[vm.VsockCID] = true
+	// used is sorted, so the first gap from 3 is the lowest free CID.
+	next := uint32(3)
+	for _, cid := range used {
+		if cid < next {
+			continue
 		}
-	}
-
-	// Find first available CID starting from 3
-	// CIDs 0-2 are reserved
-	for cid := uint32(3); cid < 1<<32-1; cid++ {
-		if !used[cid] {
-			return cid, nil
+		if cid > next {
+			break
 		}
+		next++
 	}
-
-	return 0, fmt.Errorf("no available vsock CIDs")
+	if next == 1<<32-1 {
+		return 0, fmt.Errorf("no available vsock CIDs")
+	}
+	return next, nil
 }
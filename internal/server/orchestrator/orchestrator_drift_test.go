@@ -1,11 +1,22 @@
 package orchestrator
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
 	"testing"
 
 	driftroutes "github.com/volantvm/volant/internal/drift/routes"
 	"github.com/volantvm/volant/internal/pluginspec"
 	"github.com/volantvm/volant/internal/server/db"
+	"github.com/volantvm/volant/internal/server/driftclient"
 	"github.com/volantvm/volant/internal/server/orchestrator/vmconfig"
 )
 
@@ -47,6 +58,33 @@ func TestComputeDriftRoutes_VsockRequiresTCP(t *testing.T) {
 	}
 }
 
+func TestComputeDriftRoutes_VsockUnixSocket(t *testing.T) {
+	eng := &engine{}
+	vm := db.VM{Name: "vm-4", VsockCID: 34}
+	netCfg := &pluginspec.NetworkConfig{Mode: pluginspec.NetworkModeVsock}
+	exposes := []vmconfig.Expose{{HostPort: 9001, Port: 5432, Protocol: "unix", Socket: "db.sock"}}
+
+	computed, err := eng.computeDriftRoutes(vm, netCfg, exposes)
+	if err != nil {
+		t.Fatalf("computeDriftRoutes returned error: %v", err)
+	}
+	if len(computed) != 1 {
+		t.Fatalf("expected 1 route, got %d", len(computed))
+	}
+	route := computed[0]
+	if route.Protocol != "unix" || route.Socket != "db.sock" {
+		t.Fatalf("unexpected route: %+v", route)
+	}
+	if route.Backend.Type != driftroutes.BackendVsock || route.Backend.CID != 34 || route.Backend.Port != 5432 {
+		t.Fatalf("unexpected backend data: %+v", route.Backend)
+	}
+
+	vm.IPAddress = "10.0.0.11"
+	if _, err := eng.computeDriftRoutes(vm, &pluginspec.NetworkConfig{Mode: pluginspec.NetworkModeBridged}, exposes); err == nil {
+		t.Fatalf("expected error for unix exposure in bridged mode")
+	}
+}
+
 func TestComputeDriftRoutes_DeduplicatesHostPorts(t *testing.T) {
 	eng := &engine{}
 	vm := db.VM{Name: "vm-3", IPAddress: "10.0.0.10"}
@@ -64,3 +102,137 @@ func TestComputeDriftRoutes_DeduplicatesHostPorts(t *testing.T) {
 		t.Fatalf("expected deduplicated routes, got %d", len(computed))
 	}
 }
+
+// fakeDrift is an in-memory driftd route API.
+type fakeDrift struct {
+	mu     sync.Mutex
+	routes map[string]driftroutes.Route
+}
+
+func (f *fakeDrift) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	switch {
+	case r.Method == http.MethodGet && r.URL.Path == "/routes":
+		items := make([]driftroutes.Route, 0, len(f.routes))
+		for _, route := range f.routes {
+			items = append(items, route)
+		}
+		_ = json.NewEncoder(w).Encode(items)
+	case r.Method == http.MethodPost && r.URL.Path == "/routes":
+		var route driftroutes.Route
+		if err := json.NewDecoder(r.Body).Decode(&route); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		f.routes[fmt.Sprintf("%s/%d", route.Protocol, route.HostPort)] = route
+		w.WriteHeader(http.StatusCreated)
+		_ = json.NewEncoder(w).Encode(route)
+	case r.Method == http.MethodDelete && strings.HasPrefix(r.URL.Path, "/routes/"):
+		key := strings.TrimPrefix(r.URL.Path, "/routes/")
+		if _, ok := f.routes[key]; !ok {
+			http.NotFound(w, r)
+			return
+		}
+		delete(f.routes, key)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func (f *fakeDrift) count() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return len(f.routes)
+}
+
+func TestVMRoutesFollowLifecycle(t *testing.T) {
+	ctx := context.Background()
+	store := openTestStore(t)
+	defer func() { _ = store.Close(ctx) }()
+
+	drift := &fakeDrift{routes: map[string]driftroutes.Route{}}
+	server := httptest.NewServer(drift)
+	defer server.Close()
+	client, err := driftclient.New(server.URL, "", server.Client())
+	if err != nil {
+		t.Fatalf("drift client: %v", err)
+	}
+
+	subnet, host := testSubnet(t)
+	engine, err := New(Params{
+		Store:            store,
+		Logger:           slog.New(slog.NewTextHandler(io.Discard, nil)),
+		Subnet:           subnet,
+		HostIP:           host,
+		APIListenAddr:    "127.0.0.1:7777",
+		APIAdvertiseAddr: "127.0.0.1:7777",
+		RuntimeDir:       t.TempDir(),
+		Launcher:         &testLauncher{},
+		Network:          &testNetworkManager{},
+		Drift:            client,
+	})
+	if err != nil {
+		t.Fatalf("new engine: %v", err)
+	}
+	if err := engine.Start(ctx); err != nil {
+		t.Fatalf("engine start: %v", err)
+	}
+
+	manifest := &pluginspec.Manifest{Name: "web", Runtime: "web"}
+	if _, err := engine.CreateVM(ctx, CreateVMRequest{
+		Name:     "web-1",
+		Plugin:   "web",
+		Runtime:  "web",
+		CPUCores: 1,
+		MemoryMB: 512,
+		Manifest: manifest,
+		Config: &vmconfig.Config{
+			Plugin:   "web",
+			Manifest: manifest,
+			Expose:   []vmconfig.Expose{{Port: 80, HostPort: 8080}},
+		},
+	}); err != nil {
+		t.Fatalf("create vm: %v", err)
+	}
+	if got := drift.count(); got != 1 {
+		t.Fatalf("expected the expose rule to be published, driftd holds %d routes", got)
+	}
+
+	owned, err := engine.VMRoutes(ctx)
+	if err != nil {
+		t.Fatalf("vm routes: %v", err)
+	}
+	if len(owned) != 1 || owned[0].VM != "web-1" || owned[0].Route.HostPort != 8080 {
+		t.Fatalf("unexpected vm routes: %+v", owned)
+	}
+	if err := engine.CheckRouteChange(ctx, "TCP", 8080); !errors.Is(err, ErrRouteManaged) {
+		t.Fatalf("expected ErrRouteManaged for a vm route, got %v", err)
+	}
+	if err := engine.CheckRouteChange(ctx, "tcp", 9090); err != nil {
+		t.Fatalf("unowned route rejected: %v", err)
+	}
+
+	// driftd restarted without its routes.
+	drift.mu.Lock()
+	drift.routes = map[string]driftroutes.Route{}
+	drift.mu.Unlock()
+	synced, err := engine.SyncRoutes(ctx)
+	if err != nil {
+		t.Fatalf("sync routes: %v", err)
+	}
+	if len(synced) != 1 || drift.count() != 1 {
+		t.Fatalf("expected sync to republish the route, got %+v and %d held", synced, drift.count())
+	}
+
+	if _, err := engine.StopVM(ctx, "web-1"); err != nil {
+		t.Fatalf("stop vm: %v", err)
+	}
+	if got := drift.count(); got != 0 {
+		t.Fatalf("expected the route to be retracted on stop, driftd holds %d", got)
+	}
+	if owned, err := engine.VMRoutes(ctx); err != nil || len(owned) != 0 {
+		t.Fatalf("expected no routes for a stopped vm, got %+v (%v)", owned, err)
+	}
+}
@@ -7,15 +7,26 @@ package orchestrator
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"io"
 	"log/slog"
 	"net"
+	"os"
 	"path/filepath"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/volantvm/volant/internal/pluginspec"
+	"github.com/volantvm/volant/internal/server/db"
 	"github.com/volantvm/volant/internal/server/db/sqlite"
+	"github.com/volantvm/volant/internal/server/eventbus/memory"
+	"github.com/volantvm/volant/internal/server/orchestrator/capacity"
+	orchestratorevents "github.com/volantvm/volant/internal/server/orchestrator/events"
+	"github.com/volantvm/volant/internal/server/orchestrator/hostfeatures"
 	"github.com/volantvm/volant/internal/server/orchestrator/network"
 	"github.com/volantvm/volant/internal/server/orchestrator/runtime"
 	"github.com/volantvm/volant/internal/server/orchestrator/vmconfig"
@@ -104,6 +115,938 @@ func TestEngineCreateAndDestroyVM(t *testing.T) {
 	}
 }
 
+func TestCreateVMRejectsMissingHostFeatures(t *testing.T) {
+	ctx := context.Background()
+	store := openTestStore(t)
+	defer func() { _ = store.Close(ctx) }()
+
+	subnet, host := testSubnet(t)
+	engine, err := New(Params{
+		Store:            store,
+		Logger:           slog.New(slog.NewTextHandler(io.Discard, nil)),
+		Subnet:           subnet,
+		HostIP:           host,
+		APIListenAddr:    "127.0.0.1:7777",
+		APIAdvertiseAddr: "127.0.0.1:7777",
+		RuntimeDir:       t.TempDir(),
+		Launcher:         &testLauncher{},
+		Network:          &testNetworkManager{},
+		HostFeatures: &hostfeatures.Report{Features: []hostfeatures.Feature{
+			{Name: pluginspec.HostFeatureTun, Available: true},
+			{Name: pluginspec.HostFeatureHugepages, Detail: "no huge pages reserved"},
+		}},
+	})
+	if err != nil {
+		t.Fatalf("new engine: %v", err)
+	}
+	if err := engine.Start(ctx); err != nil {
+		t.Fatalf("engine start: %v", err)
+	}
+
+	_, err = engine.CreateVM(ctx, CreateVMRequest{
+		Name:     "vm-hugepages",
+		CPUCores: 1,
+		MemoryMB: 512,
+		Manifest: &pluginspec.Manifest{
+			Name:         "db",
+			Runtime:      "db",
+			HostFeatures: []string{pluginspec.HostFeatureTun, pluginspec.HostFeatureHugepages},
+		},
+	})
+	if !errors.Is(err, ErrHostFeaturesMissing) {
+		t.Fatalf("expected ErrHostFeaturesMissing, got %v", err)
+	}
+	var featureErr *HostFeatureError
+	if !errors.As(err, &featureErr) || len(featureErr.Missing) != 1 || featureErr.Missing[0].Name != pluginspec.HostFeatureHugepages {
+		t.Fatalf("expected only hugepages missing, got %+v", featureErr)
+	}
+	if vm, _ := engine.GetVM(ctx, "vm-hugepages"); vm != nil {
+		t.Fatalf("vm should not have been created")
+	}
+}
+
+func TestPluginQuotaEnforcement(t *testing.T) {
+	ctx := context.Background()
+	store := openTestStore(t)
+	defer func() { _ = store.Close(ctx) }()
+
+	subnet, host := testSubnet(t)
+	probe := &testReadinessProbe{}
+	probe.ready.Store(true)
+	engine, err := New(Params{
+		Store:            store,
+		Logger:           slog.New(slog.NewTextHandler(io.Discard, nil)),
+		Subnet:           subnet,
+		HostIP:           host,
+		APIListenAddr:    "127.0.0.1:7777",
+		APIAdvertiseAddr: "127.0.0.1:7777",
+		RuntimeDir:       t.TempDir(),
+		Launcher:         &testLauncher{},
+		Network:          &testNetworkManager{},
+		Readiness:        probe,
+	})
+	if err != nil {
+		t.Fatalf("new engine: %v", err)
+	}
+	if err := engine.Start(ctx); err != nil {
+		t.Fatalf("engine start: %v", err)
+	}
+	defer func() { _ = engine.Stop(ctx) }()
+
+	installed := pluginspec.Manifest{
+		Name:      "worker",
+		Version:   "1.0.0",
+		Runtime:   "worker",
+		Resources: pluginspec.ResourceSpec{CPUCores: 1, MemoryMB: 512},
+		Limits:    &pluginspec.Limits{MaxCPUCores: 2, MaxMemoryMB: 1024, MaxVMs: 3},
+	}
+	metadata, err := json.Marshal(installed)
+	if err != nil {
+		t.Fatalf("marshal manifest: %v", err)
+	}
+	if err := store.Queries().Plugins().Upsert(ctx, db.Plugin{Name: "worker", Version: "1.0.0", Enabled: true, Metadata: metadata}); err != nil {
+		t.Fatalf("install plugin: %v", err)
+	}
+
+	// An inline manifest without limits cannot lift the installed ones.
+	inline := &pluginspec.Manifest{Name: "worker", Version: "1.0.0", Runtime: "worker"}
+	_, err = engine.CreateVM(ctx, CreateVMRequest{Name: "big", CPUCores: 4, MemoryMB: 512, Manifest: inline})
+	var quotaErr *QuotaError
+	if !errors.Is(err, ErrPluginQuotaExceeded) || !errors.As(err, &quotaErr) || quotaErr.Constraint != pluginspec.LimitMaxCPUCores || quotaErr.Limit != 2 || quotaErr.Requested != 4 {
+		t.Fatalf("expected max_cpu_cores violation, got %v", err)
+	}
+
+	config := vmconfig.Config{
+		Plugin:    "worker",
+		Runtime:   "worker",
+		Resources: vmconfig.Resources{CPUCores: 1, MemoryMB: 512},
+		Manifest:  &installed,
+	}
+	if _, err := engine.CreateDeployment(ctx, CreateDeploymentRequest{Name: "pool", Replicas: 2, Config: config}); err != nil {
+		t.Fatalf("create deployment: %v", err)
+	}
+	if _, err := engine.CreateVM(ctx, CreateVMRequest{Name: "solo", CPUCores: 1, MemoryMB: 512, Manifest: &installed}); err != nil {
+		t.Fatalf("create vm within quota: %v", err)
+	}
+	if _, err := engine.CreateVM(ctx, CreateVMRequest{Name: "extra", CPUCores: 1, MemoryMB: 512, Manifest: &installed}); !errors.As(err, &quotaErr) || quotaErr.Constraint != pluginspec.LimitMaxVMs || quotaErr.Requested != 4 {
+		t.Fatalf("expected max_vms violation, got %v", err)
+	}
+	if _, err := engine.ScaleDeployment(ctx, "pool", 3); !errors.As(err, &quotaErr) || quotaErr.Constraint != pluginspec.LimitMaxVMs {
+		t.Fatalf("expected scale past max_vms refused, got %v", err)
+	}
+	if _, err := engine.ScaleDeployment(ctx, "pool", 1); err != nil {
+		t.Fatalf("scale down: %v", err)
+	}
+	if _, err := engine.CreateVM(ctx, CreateVMRequest{Name: "extra", CPUCores: 1, MemoryMB: 512, Manifest: &installed}); err != nil {
+		t.Fatalf("create vm after scale down: %v", err)
+	}
+}
+
+func TestCapacityAdmission(t *testing.T) {
+	ctx := context.Background()
+	store := openTestStore(t)
+	defer func() { _ = store.Close(ctx) }()
+
+	subnet, host := testSubnet(t)
+	probe := &testReadinessProbe{}
+	probe.ready.Store(true)
+	engine, err := New(Params{
+		Store:            store,
+		Logger:           slog.New(slog.NewTextHandler(io.Discard, nil)),
+		Subnet:           subnet,
+		HostIP:           host,
+		APIListenAddr:    "127.0.0.1:7777",
+		APIAdvertiseAddr: "127.0.0.1:7777",
+		RuntimeDir:       t.TempDir(),
+		Launcher:         &testLauncher{},
+		Network:          &testNetworkManager{},
+		Readiness:        probe,
+		Capacity:         capacity.New(capacity.Host{CPUs: 4, MemoryMB: 4096}, capacity.Thresholds{CPUPercent: 100, MemoryPercent: 50}),
+	})
+	if err != nil {
+		t.Fatalf("new engine: %v", err)
+	}
+	if err := engine.Start(ctx); err != nil {
+		t.Fatalf("engine start: %v", err)
+	}
+	defer func() { _ = engine.Stop(ctx) }()
+
+	manifest := &pluginspec.Manifest{Name: "worker", Version: "1.0.0", Runtime: "worker"}
+	if _, err := engine.CreateVM(ctx, CreateVMRequest{Name: "solo", CPUCores: 1, MemoryMB: 1024, Manifest: manifest}); err != nil {
+		t.Fatalf("create vm: %v", err)
+	}
+	config := vmconfig.Config{
+		Plugin:    "worker",
+		Runtime:   "worker",
+		Resources: vmconfig.Resources{CPUCores: 1, MemoryMB: 512},
+		Manifest:  manifest,
+	}
+	if _, err := engine.CreateDeployment(ctx, CreateDeploymentRequest{Name: "pool", Replicas: 2, Config: config}); err != nil {
+		t.Fatalf("create deployment: %v", err)
+	}
+
+	report, err := engine.Capacity(ctx)
+	if err != nil {
+		t.Fatalf("capacity: %v", err)
+	}
+	if report.CommittedVMs != 3 || report.CommittedCPUs != 3 || report.CommittedMemoryMB != 2048 {
+		t.Fatalf("unexpected committed usage: %+v", report)
+	}
+	if report.AvailableCPUs == nil || *report.AvailableCPUs != 1 || report.AvailableMemoryMB == nil || *report.AvailableMemoryMB != 0 {
+		t.Fatalf("unexpected available capacity: %+v", report)
+	}
+
+	var capacityErr *CapacityError
+	_, err = engine.CreateVM(ctx, CreateVMRequest{Name: "extra", CPUCores: 1, MemoryMB: 256, Manifest: manifest})
+	if !errors.Is(err, ErrInsufficientCapacity) || !errors.As(err, &capacityErr) || capacityErr.Resource != capacity.ResourceMemory || capacityErr.Allowed != 2048 || capacityErr.Requested != 2304 {
+		t.Fatalf("expected memory capacity refusal, got %v", err)
+	}
+	if _, err := engine.ScaleDeployment(ctx, "pool", 3); !errors.As(err, &capacityErr) {
+		t.Fatalf("expected scale-up refused, got %v", err)
+	}
+	if _, err := engine.StopVM(ctx, "solo"); err != nil {
+		t.Fatalf("stop vm: %v", err)
+	}
+	if _, err := engine.ScaleDeployment(ctx, "pool", 3); err != nil {
+		t.Fatalf("scale up after stop: %v", err)
+	}
+}
+
+func TestCreateVMAsyncTracksOperation(t *testing.T) {
+	ctx := context.Background()
+	store := openTestStore(t)
+	defer func() { _ = store.Close(ctx) }()
+
+	subnet, host := testSubnet(t)
+	launcher := &testLauncher{}
+	engine, err := New(Params{
+		Store:            store,
+		Logger:           slog.New(slog.NewTextHandler(io.Discard, nil)),
+		Subnet:           subnet,
+		HostIP:           host,
+		APIListenAddr:    "127.0.0.1:7777",
+		APIAdvertiseAddr: "127.0.0.1:7777",
+		RuntimeDir:       t.TempDir(),
+		Launcher:         launcher,
+		Network:          &testNetworkManager{},
+	})
+	if err != nil {
+		t.Fatalf("new engine: %v", err)
+	}
+	if err := engine.Start(ctx); err != nil {
+		t.Fatalf("engine start: %v", err)
+	}
+
+	req := CreateVMRequest{
+		Name:     "vm-async",
+		CPUCores: 1,
+		MemoryMB: 512,
+		Manifest: &pluginspec.Manifest{Name: "browser", Runtime: "browser"},
+	}
+	op, err := engine.CreateVMAsync(ctx, req)
+	if err != nil {
+		t.Fatalf("create vm async: %v", err)
+	}
+	if op.Kind != OperationKindCreateVM || op.Target != "vm-async" || op.Status != db.OperationStatusPending {
+		t.Fatalf("unexpected operation %+v", op)
+	}
+
+	waitFor(t, func() bool {
+		got, err := engine.GetOperation(ctx, op.ID)
+		return err == nil && got.Status == db.OperationStatusSucceeded
+	})
+	if vm, _ := engine.GetVM(ctx, "vm-async"); vm == nil || vm.Status != db.VMStatusRunning {
+		t.Fatalf("expected running vm, got %+v", vm)
+	}
+	if launcher.launches() != 1 {
+		t.Fatalf("expected one launch, got %d", launcher.launches())
+	}
+
+	// Name conflicts are reported before an operation is recorded.
+	if _, err := engine.CreateVMAsync(ctx, req); !errors.Is(err, ErrVMExists) {
+		t.Fatalf("expected ErrVMExists, got %v", err)
+	}
+	if _, err := engine.GetOperation(ctx, "op-missing"); !errors.Is(err, ErrOperationNotFound) {
+		t.Fatalf("expected ErrOperationNotFound, got %v", err)
+	}
+}
+
+func TestDuplicateVMReidentifies(t *testing.T) {
+	ctx := context.Background()
+	store := openTestStore(t)
+	defer func() { _ = store.Close(ctx) }()
+
+	subnet, host := testSubnet(t)
+	engine, err := New(Params{
+		Store:            store,
+		Logger:           slog.New(slog.NewTextHandler(io.Discard, nil)),
+		Subnet:           subnet,
+		HostIP:           host,
+		APIListenAddr:    "127.0.0.1:7777",
+		APIAdvertiseAddr: "127.0.0.1:7777",
+		RuntimeDir:       t.TempDir(),
+		Launcher:         &testLauncher{},
+		Network:          &testNetworkManager{},
+	})
+	if err != nil {
+		t.Fatalf("new engine: %v", err)
+	}
+	if err := engine.Start(ctx); err != nil {
+		t.Fatalf("engine start: %v", err)
+	}
+
+	manifest := &pluginspec.Manifest{Name: "browser", Runtime: "browser"}
+	source, err := engine.CreateVM(ctx, CreateVMRequest{
+		Name:     "web",
+		Plugin:   "browser",
+		Runtime:  "browser",
+		CPUCores: 1,
+		MemoryMB: 512,
+		Manifest: manifest,
+		Config: &vmconfig.Config{
+			Plugin:    "browser",
+			Runtime:   "browser",
+			Resources: vmconfig.Resources{CPUCores: 1, MemoryMB: 512},
+			Manifest:  manifest,
+			Ports:     []vmconfig.PortMapping{{Host: 18080, Guest: 80, Proto: "tcp"}},
+		},
+	})
+	if err != nil {
+		t.Fatalf("create vm: %v", err)
+	}
+	if _, err := engine.StopVM(ctx, "web"); err != nil {
+		t.Fatalf("stop vm: %v", err)
+	}
+
+	clone, err := engine.DuplicateVM(ctx, "web", DuplicateVMRequest{Name: "web-debug"})
+	if err != nil {
+		t.Fatalf("duplicate vm: %v", err)
+	}
+	if clone.IPAddress == source.IPAddress || clone.MACAddress == source.MACAddress || clone.VsockCID == source.VsockCID {
+		t.Fatalf("clone shares identity with source: %+v vs %+v", clone, source)
+	}
+	cfg, err := engine.GetVMConfig(ctx, "web-debug")
+	if err != nil {
+		t.Fatalf("get clone config: %v", err)
+	}
+	if len(cfg.Config.Ports) != 0 {
+		t.Fatalf("expected port mappings to be dropped, got %+v", cfg.Config.Ports)
+	}
+
+	if _, err := engine.DuplicateVM(ctx, "web", DuplicateVMRequest{Name: "web-debug"}); !errors.Is(err, ErrVMExists) {
+		t.Fatalf("expected ErrVMExists, got %v", err)
+	}
+}
+
+func TestDuplicateVMConfigOnly(t *testing.T) {
+	ctx := context.Background()
+	store := openTestStore(t)
+	defer func() { _ = store.Close(ctx) }()
+
+	subnet, host := testSubnet(t)
+	runtimeDir := t.TempDir()
+	engine, err := New(Params{
+		Store:            store,
+		Logger:           slog.New(slog.NewTextHandler(io.Discard, nil)),
+		Subnet:           subnet,
+		HostIP:           host,
+		APIListenAddr:    "127.0.0.1:7777",
+		APIAdvertiseAddr: "127.0.0.1:7777",
+		RuntimeDir:       runtimeDir,
+		Launcher:         &testLauncher{},
+		Network:          &testNetworkManager{},
+	})
+	if err != nil {
+		t.Fatalf("new engine: %v", err)
+	}
+	if err := engine.Start(ctx); err != nil {
+		t.Fatalf("engine start: %v", err)
+	}
+
+	// An existing image keeps the engine from formatting one.
+	sourceScratch := scratchDiskPath(runtimeDir, "tuned")
+	if err := os.MkdirAll(filepath.Dir(sourceScratch), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(sourceScratch, []byte("warm cache"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	manifest := &pluginspec.Manifest{Name: "browser", Runtime: "browser"}
+	if _, err := engine.CreateVM(ctx, CreateVMRequest{
+		Name:     "tuned",
+		Plugin:   "browser",
+		Runtime:  "browser",
+		CPUCores: 1,
+		MemoryMB: 512,
+		Manifest: manifest,
+		Config: &vmconfig.Config{
+			Plugin:      "browser",
+			Runtime:     "browser",
+			Resources:   vmconfig.Resources{CPUCores: 1, MemoryMB: 512},
+			Manifest:    manifest,
+			ScratchDisk: &vmconfig.ScratchDisk{SizeMB: 16, Persist: true},
+		},
+	}); err != nil {
+		t.Fatalf("create vm: %v", err)
+	}
+	if _, err := engine.StopVM(ctx, "tuned"); err != nil {
+		t.Fatalf("stop vm: %v", err)
+	}
+
+	if _, err := engine.DuplicateVM(ctx, "tuned", DuplicateVMRequest{Name: "tuned-b", ConfigOnly: true, Scratch: true}); err != nil {
+		t.Fatalf("clone vm: %v", err)
+	}
+	if data, err := os.ReadFile(scratchDiskPath(runtimeDir, "tuned-b")); err != nil || string(data) != "warm cache" {
+		t.Fatalf("expected scratch disk to be copied, got %q, %v", data, err)
+	}
+	cfg, err := engine.GetVMConfig(ctx, "tuned-b")
+	if err != nil {
+		t.Fatalf("get clone config: %v", err)
+	}
+	if cfg.Config.ScratchDisk == nil || !cfg.Config.ScratchDisk.Persist {
+		t.Fatalf("expected scratch disk config to be kept, got %+v", cfg.Config.ScratchDisk)
+	}
+
+	disk := filepath.Join(t.TempDir(), "data.img")
+	if err := os.WriteFile(disk, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	withDisk := &pluginspec.Manifest{Name: "browser", Runtime: "browser", Disks: []pluginspec.Disk{{Name: "data", Source: disk}}}
+	if _, err := engine.CreateVM(ctx, CreateVMRequest{
+		Name:     "db",
+		Plugin:   "browser",
+		Runtime:  "browser",
+		CPUCores: 1,
+		MemoryMB: 512,
+		Manifest: withDisk,
+	}); err != nil {
+		t.Fatalf("create vm: %v", err)
+	}
+	if _, err := engine.DuplicateVM(ctx, "db", DuplicateVMRequest{Name: "db-b", ConfigOnly: true}); !errors.Is(err, ErrVMNotDuplicable) {
+		t.Fatalf("expected ErrVMNotDuplicable for a writable disk, got %v", err)
+	}
+}
+
+func TestHostMaintenanceStopsAndRestores(t *testing.T) {
+	ctx := context.Background()
+	store := openTestStore(t)
+	defer func() { _ = store.Close(ctx) }()
+
+	subnet, host := testSubnet(t)
+	engine, err := New(Params{
+		Store:            store,
+		Logger:           slog.New(slog.NewTextHandler(io.Discard, nil)),
+		Subnet:           subnet,
+		HostIP:           host,
+		APIListenAddr:    "127.0.0.1:7777",
+		APIAdvertiseAddr: "127.0.0.1:7777",
+		RuntimeDir:       t.TempDir(),
+		Launcher:         &testLauncher{},
+		Network:          &testNetworkManager{},
+	})
+	if err != nil {
+		t.Fatalf("new engine: %v", err)
+	}
+	if err := engine.Start(ctx); err != nil {
+		t.Fatalf("engine start: %v", err)
+	}
+
+	manifest := &pluginspec.Manifest{Name: "browser", Runtime: "browser"}
+	req := func(name string) CreateVMRequest {
+		return CreateVMRequest{Name: name, Plugin: "browser", Runtime: "browser", CPUCores: 1, MemoryMB: 512, Manifest: manifest}
+	}
+	for _, name := range []string{"web", "worker", "idle"} {
+		if _, err := engine.CreateVM(ctx, req(name)); err != nil {
+			t.Fatalf("create vm %s: %v", name, err)
+		}
+	}
+	if _, err := engine.StopVM(ctx, "idle"); err != nil {
+		t.Fatalf("stop vm: %v", err)
+	}
+
+	status, err := engine.EnterHostMaintenance(ctx, HostMaintenanceRequest{Reason: "kernel upgrade", Concurrency: 1})
+	if err != nil {
+		t.Fatalf("enter maintenance: %v", err)
+	}
+	if !status.Engaged || len(status.VMs) != 2 || status.VMs[0].Name != "web" || status.VMs[1].Name != "worker" {
+		t.Fatalf("unexpected maintenance status: %+v", status)
+	}
+	for _, name := range []string{"web", "worker"} {
+		if vm, _ := engine.GetVM(ctx, name); vm == nil || vm.Status != db.VMStatusStopped {
+			t.Fatalf("expected %s stopped, got %+v", name, vm)
+		}
+	}
+	if _, err := engine.CreateVM(ctx, req("late")); !errors.Is(err, ErrHostMaintenance) {
+		t.Fatalf("expected ErrHostMaintenance, got %v", err)
+	}
+	if _, err := engine.CreateVMAsync(ctx, req("late")); !errors.Is(err, ErrHostMaintenance) {
+		t.Fatalf("expected ErrHostMaintenance from async create, got %v", err)
+	}
+
+	status, err = engine.ExitHostMaintenance(ctx, 0)
+	if err != nil {
+		t.Fatalf("exit maintenance: %v", err)
+	}
+	if status.Engaged || status.EndedAt == nil {
+		t.Fatalf("expected maintenance to be over, got %+v", status)
+	}
+	for _, vm := range status.VMs {
+		if vm.RestoredAt == nil || vm.Outcome != IntentReplayed {
+			t.Fatalf("expected %s restored, got %+v", vm.Name, vm)
+		}
+	}
+	for name, want := range map[string]db.VMStatus{"web": db.VMStatusRunning, "worker": db.VMStatusRunning, "idle": db.VMStatusStopped} {
+		if vm, _ := engine.GetVM(ctx, name); vm == nil || vm.Status != want {
+			t.Fatalf("expected %s %s, got %+v", name, want, vm)
+		}
+	}
+	if _, err := engine.ExitHostMaintenance(ctx, 0); !errors.Is(err, ErrMaintenanceWindowNotFound) {
+		t.Fatalf("expected ErrMaintenanceWindowNotFound, got %v", err)
+	}
+
+	// A later maintenance replaces the finished one.
+	status, err = engine.EnterHostMaintenance(ctx, HostMaintenanceRequest{})
+	if err != nil {
+		t.Fatalf("enter maintenance again: %v", err)
+	}
+	if !status.Engaged || len(status.VMs) != 2 || status.VMs[0].RestoredAt != nil {
+		t.Fatalf("unexpected second maintenance status: %+v", status)
+	}
+}
+
+func TestVMDependencies(t *testing.T) {
+	ctx := context.Background()
+	store := openTestStore(t)
+	defer func() { _ = store.Close(ctx) }()
+
+	subnet, host := testSubnet(t)
+	probe := &testReadinessProbe{}
+	probe.ready.Store(true)
+	engine, err := New(Params{
+		Store:            store,
+		Logger:           slog.New(slog.NewTextHandler(io.Discard, nil)),
+		Subnet:           subnet,
+		HostIP:           host,
+		APIListenAddr:    "127.0.0.1:7777",
+		APIAdvertiseAddr: "127.0.0.1:7777",
+		RuntimeDir:       t.TempDir(),
+		Launcher:         &testLauncher{},
+		Network:          &testNetworkManager{},
+		Readiness:        probe,
+	})
+	if err != nil {
+		t.Fatalf("new engine: %v", err)
+	}
+	if err := engine.Start(ctx); err != nil {
+		t.Fatalf("engine start: %v", err)
+	}
+	defer func() { _ = engine.Stop(ctx) }()
+
+	manifest := &pluginspec.Manifest{Name: "browser", Runtime: "browser"}
+	req := func(name string, deps ...vmconfig.Dependency) CreateVMRequest {
+		return CreateVMRequest{Name: name, Plugin: "browser", Runtime: "browser", CPUCores: 1, MemoryMB: 512, Manifest: manifest,
+			Config: &vmconfig.Config{DependsOn: deps}}
+	}
+	if _, err := engine.CreateVM(ctx, req("db")); err != nil {
+		t.Fatalf("create db: %v", err)
+	}
+	if _, err := engine.StopVM(ctx, "db"); err != nil {
+		t.Fatalf("stop db: %v", err)
+	}
+	if _, err := engine.CreateVM(ctx, req("orphan", vmconfig.Dependency{VM: "missing"})); !errors.Is(err, ErrDependencyUnavailable) {
+		t.Fatalf("expected ErrDependencyUnavailable, got %v", err)
+	}
+
+	// Creating the dependent starts the stopped dependency first.
+	if _, err := engine.CreateVM(ctx, req("app", vmconfig.Dependency{VM: "db", StopWith: true})); err != nil {
+		t.Fatalf("create app: %v", err)
+	}
+	if vm, _ := engine.GetVM(ctx, "db"); vm == nil || vm.Status != db.VMStatusRunning {
+		t.Fatalf("expected db running, got %+v", vm)
+	}
+
+	// Stopping the dependency stops the dependent that asked for it.
+	if _, err := engine.StopVM(ctx, "db"); err != nil {
+		t.Fatalf("stop db: %v", err)
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		vm, _ := engine.GetVM(ctx, "app")
+		if vm != nil && vm.Status == db.VMStatusStopped {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("expected app stopped with db, got %+v", vm)
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+
+	// Starting the dependent again brings the dependency back up.
+	if _, err := engine.StartVM(ctx, "app"); err != nil {
+		t.Fatalf("start app: %v", err)
+	}
+	if vm, _ := engine.GetVM(ctx, "db"); vm == nil || vm.Status != db.VMStatusRunning {
+		t.Fatalf("expected db running, got %+v", vm)
+	}
+
+	// A dependency cycle fails the start rather than recursing.
+	deps := []vmconfig.Dependency{{VM: "app", Condition: vmconfig.DependencyStarted}}
+	if _, err := engine.UpdateVMConfig(ctx, "db", vmconfig.Patch{DependsOn: &deps}); err != nil {
+		t.Fatalf("update db config: %v", err)
+	}
+	for _, name := range []string{"app", "db"} {
+		if _, err := engine.StopVM(ctx, name); err != nil {
+			t.Fatalf("stop %s: %v", name, err)
+		}
+	}
+	if _, err := engine.StartVM(ctx, "app"); !errors.Is(err, ErrDependencyUnavailable) || !strings.Contains(err.Error(), "cycle") {
+		t.Fatalf("expected dependency cycle error, got %v", err)
+	}
+	for _, name := range []string{"app", "db"} {
+		if vm, _ := engine.GetVM(ctx, name); vm == nil || vm.Status != db.VMStatusStopped {
+			t.Fatalf("expected %s stopped after failed start, got %+v", name, vm)
+		}
+	}
+}
+
+func TestAutostartOnEngineStart(t *testing.T) {
+	ctx := context.Background()
+	store := openTestStore(t)
+	defer func() { _ = store.Close(ctx) }()
+
+	subnet, host := testSubnet(t)
+	newEngine := func() Engine {
+		probe := &testReadinessProbe{}
+		probe.ready.Store(true)
+		engine, err := New(Params{
+			Store:            store,
+			Logger:           slog.New(slog.NewTextHandler(io.Discard, nil)),
+			Subnet:           subnet,
+			HostIP:           host,
+			APIListenAddr:    "127.0.0.1:7777",
+			APIAdvertiseAddr: "127.0.0.1:7777",
+			RuntimeDir:       t.TempDir(),
+			Launcher:         &testLauncher{},
+			Network:          &testNetworkManager{},
+			Readiness:        probe,
+		})
+		if err != nil {
+			t.Fatalf("new engine: %v", err)
+		}
+		if err := engine.Start(ctx); err != nil {
+			t.Fatalf("engine start: %v", err)
+		}
+		return engine
+	}
+
+	first := newEngine()
+	manifest := &pluginspec.Manifest{Name: "browser", Runtime: "browser"}
+	configs := map[string]vmconfig.Config{
+		"db":     {},
+		"web":    {Autostart: true, DependsOn: []vmconfig.Dependency{{VM: "db"}}},
+		"batch":  {Autostart: true},
+		"manual": {},
+	}
+	for _, name := range []string{"db", "web", "batch", "manual"} {
+		cfg := configs[name]
+		if _, err := first.CreateVM(ctx, CreateVMRequest{Name: name, Plugin: "browser", Runtime: "browser", CPUCores: 1, MemoryMB: 512, Manifest: manifest, Config: &cfg}); err != nil {
+			t.Fatalf("create vm %s: %v", name, err)
+		}
+	}
+	if err := first.Stop(ctx); err != nil {
+		t.Fatalf("stop engine: %v", err)
+	}
+
+	// A new engine finds every VM stopped and starts the flagged ones,
+	// along with the dependency of web.
+	second := newEngine()
+	defer func() { _ = second.Stop(ctx) }()
+	want := map[string]db.VMStatus{"db": db.VMStatusRunning, "web": db.VMStatusRunning, "batch": db.VMStatusRunning, "manual": db.VMStatusStopped}
+	deadline := time.Now().Add(5 * time.Second)
+	for name, status := range want {
+		for {
+			vm, _ := second.GetVM(ctx, name)
+			if vm != nil && vm.Status == status {
+				break
+			}
+			if time.Now().After(deadline) {
+				t.Fatalf("expected %s %s after engine start, got %+v", name, status, vm)
+			}
+			time.Sleep(20 * time.Millisecond)
+		}
+	}
+}
+
+func TestWarmPoolClaim(t *testing.T) {
+	ctx := context.Background()
+	store := openTestStore(t)
+	defer func() { _ = store.Close(ctx) }()
+
+	subnet, host := testSubnet(t)
+	probe := &testReadinessProbe{}
+	probe.ready.Store(true)
+	engine, err := New(Params{
+		Store:            store,
+		Logger:           slog.New(slog.NewTextHandler(io.Discard, nil)),
+		Subnet:           subnet,
+		HostIP:           host,
+		APIListenAddr:    "127.0.0.1:7777",
+		APIAdvertiseAddr: "127.0.0.1:7777",
+		RuntimeDir:       t.TempDir(),
+		Launcher:         &testLauncher{},
+		Network:          &testNetworkManager{},
+		Readiness:        probe,
+	})
+	if err != nil {
+		t.Fatalf("new engine: %v", err)
+	}
+	if err := engine.Start(ctx); err != nil {
+		t.Fatalf("engine start: %v", err)
+	}
+	defer func() { _ = engine.Stop(ctx) }()
+
+	metadata, err := json.Marshal(pluginspec.Manifest{
+		Name:      "browser",
+		Version:   "1.0.0",
+		Runtime:   "browser",
+		Resources: pluginspec.ResourceSpec{CPUCores: 1, MemoryMB: 512},
+	})
+	if err != nil {
+		t.Fatalf("marshal manifest: %v", err)
+	}
+	if err := store.Queries().Plugins().Upsert(ctx, db.Plugin{Name: "browser", Version: "1.0.0", Enabled: true, Metadata: metadata}); err != nil {
+		t.Fatalf("install plugin: %v", err)
+	}
+
+	if _, err := engine.ClaimVM(ctx, ClaimVMRequest{Plugin: "browser"}); !errors.Is(err, ErrWarmPoolNotFound) {
+		t.Fatalf("expected claim without a pool refused, got %v", err)
+	}
+	pool, err := engine.SetWarmPool(ctx, SetWarmPoolRequest{Plugin: "browser", Size: 2})
+	if err != nil {
+		t.Fatalf("set warm pool: %v", err)
+	}
+	if pool.Config.Resources.CPUCores != 1 || pool.Config.Resources.MemoryMB != 512 {
+		t.Fatalf("expected manifest resources, got %+v", pool.Config.Resources)
+	}
+	waitReady := func(want int) {
+		t.Helper()
+		deadline := time.Now().Add(5 * time.Second)
+		for {
+			pool, err := engine.GetWarmPool(ctx, "browser")
+			if err != nil {
+				t.Fatalf("get warm pool: %v", err)
+			}
+			if pool.Ready == want && pool.Starting == 0 {
+				return
+			}
+			if time.Now().After(deadline) {
+				t.Fatalf("expected %d ready pool vms, got %+v", want, pool)
+			}
+			time.Sleep(20 * time.Millisecond)
+		}
+	}
+	waitReady(2)
+
+	claimed, err := engine.ClaimVM(ctx, ClaimVMRequest{Plugin: "browser", Labels: map[string]string{"team": "qa"}})
+	if err != nil {
+		t.Fatalf("claim vm: %v", err)
+	}
+	if _, ok := claimed.Labels[WarmPoolLabel]; ok || claimed.Labels["team"] != "qa" || claimed.Status != db.VMStatusRunning {
+		t.Fatalf("expected running claimed vm labeled team=qa, got %+v", claimed)
+	}
+
+	// The pool refills to its size without the claimed VM.
+	waitReady(2)
+	vms, err := engine.ListVMs(ctx)
+	if err != nil {
+		t.Fatalf("list vms: %v", err)
+	}
+	if len(vms) != 3 {
+		t.Fatalf("expected 2 pool vms and the claimed one, got %d", len(vms))
+	}
+
+	if err := engine.DeleteWarmPool(ctx, "browser"); err != nil {
+		t.Fatalf("delete warm pool: %v", err)
+	}
+	vms, err = engine.ListVMs(ctx)
+	if err != nil {
+		t.Fatalf("list vms: %v", err)
+	}
+	if len(vms) != 1 || vms[0].Name != claimed.Name {
+		t.Fatalf("expected only the claimed vm to remain, got %+v", vms)
+	}
+}
+
+func TestVMEventHistory(t *testing.T) {
+	ctx := context.Background()
+	store := openTestStore(t)
+	defer func() { _ = store.Close(ctx) }()
+
+	subnet, host := testSubnet(t)
+	bus := memory.New()
+	fakeLauncher := &testLauncher{}
+	created, err := New(Params{
+		Store:            store,
+		Logger:           slog.New(slog.NewTextHandler(io.Discard, nil)),
+		Subnet:           subnet,
+		HostIP:           host,
+		APIListenAddr:    "127.0.0.1:7777",
+		APIAdvertiseAddr: "127.0.0.1:7777",
+		RuntimeDir:       t.TempDir(),
+		Launcher:         fakeLauncher,
+		Network:          &testNetworkManager{},
+		Bus:              bus,
+	})
+	if err != nil {
+		t.Fatalf("new engine: %v", err)
+	}
+	e := created.(*engine)
+	if err := e.Start(ctx); err != nil {
+		t.Fatalf("engine start: %v", err)
+	}
+	defer func() { _ = e.Stop(ctx) }()
+
+	if _, err := e.VMEvents(ctx, "web", db.VMEventQuery{}); !errors.Is(err, ErrVMNotFound) {
+		t.Fatalf("expected unknown vm without history to be not found, got %v", err)
+	}
+
+	if _, err := e.CreateVM(ctx, CreateVMRequest{Name: "web", CPUCores: 1, MemoryMB: 512, Manifest: &pluginspec.Manifest{Name: "web", Runtime: "web"}}); err != nil {
+		t.Fatalf("create vm: %v", err)
+	}
+	if err := bus.Publish(ctx, orchestratorevents.TopicVMEvents, orchestratorevents.VMEvent{Type: orchestratorevents.TypeVMLog, Name: "web", Line: "hello", Timestamp: time.Now()}); err != nil {
+		t.Fatalf("publish log line: %v", err)
+	}
+	if _, err := e.StopVM(ctx, "web"); err != nil {
+		t.Fatalf("stop vm: %v", err)
+	}
+	if err := e.DestroyVM(ctx, "web"); err != nil {
+		t.Fatalf("delete vm: %v", err)
+	}
+
+	var events []db.VMEvent
+	waitFor(t, func() bool {
+		events, err = e.VMEvents(ctx, "web", db.VMEventQuery{})
+		return err == nil && len(events) > 0 && events[len(events)-1].Type == orchestratorevents.TypeVMDeleted
+	})
+	var types []string
+	for _, event := range events {
+		types = append(types, event.Type)
+	}
+	want := []string{orchestratorevents.TypeVMCreated, orchestratorevents.TypeVMRunning, orchestratorevents.TypeVMStopped, orchestratorevents.TypeVMDeleted}
+	if strings.Join(types, ",") != strings.Join(want, ",") {
+		t.Fatalf("expected history %v without log lines, got %v", want, types)
+	}
+
+	stops, err := e.VMEvents(ctx, "web", db.VMEventQuery{Types: []string{orchestratorevents.TypeVMStopped}})
+	if err != nil || len(stops) != 1 || stops[0].Status != string(orchestratorevents.VMStatusStopped) {
+		t.Fatalf("expected one VM_STOPPED event, got %+v (%v)", stops, err)
+	}
+	if recent, err := e.VMEvents(ctx, "web", db.VMEventQuery{Since: time.Now().Add(time.Minute)}); err != nil || len(recent) != 0 {
+		t.Fatalf("expected no events in the future, got %+v (%v)", recent, err)
+	}
+
+	if _, err := store.Queries().VMEvents().Record(ctx, db.VMEvent{VMName: "old", Type: orchestratorevents.TypeVMCrashed, CreatedAt: time.Now().Add(-vmEventRetention - time.Hour)}); err != nil {
+		t.Fatalf("record old event: %v", err)
+	}
+	e.pruneVMEvents(ctx)
+	if _, err := e.VMEvents(ctx, "old", db.VMEventQuery{}); !errors.Is(err, ErrVMNotFound) {
+		t.Fatalf("expected expired history to be pruned, got %v", err)
+	}
+	if kept, err := e.VMEvents(ctx, "web", db.VMEventQuery{}); err != nil || len(kept) != len(events) {
+		t.Fatalf("expected recent history kept, got %d events (%v)", len(kept), err)
+	}
+}
+
+func TestVMTTLExpiry(t *testing.T) {
+	ctx := context.Background()
+	store := openTestStore(t)
+	defer func() { _ = store.Close(ctx) }()
+
+	subnet, host := testSubnet(t)
+	probe := &testReadinessProbe{}
+	probe.ready.Store(true)
+	created, err := New(Params{
+		Store:            store,
+		Logger:           slog.New(slog.NewTextHandler(io.Discard, nil)),
+		Subnet:           subnet,
+		HostIP:           host,
+		APIListenAddr:    "127.0.0.1:7777",
+		APIAdvertiseAddr: "127.0.0.1:7777",
+		RuntimeDir:       t.TempDir(),
+		Launcher:         &testLauncher{},
+		Network:          &testNetworkManager{},
+		Readiness:        probe,
+	})
+	if err != nil {
+		t.Fatalf("new engine: %v", err)
+	}
+	e := created.(*engine)
+	if err := e.Start(ctx); err != nil {
+		t.Fatalf("engine start: %v", err)
+	}
+	defer func() { _ = e.Stop(ctx) }()
+
+	manifest := func(ttl int, action string) *pluginspec.Manifest {
+		return &pluginspec.Manifest{Name: "job", Runtime: "job", TTLSeconds: ttl, TTLAction: action}
+	}
+	before := time.Now()
+	stopped, err := e.CreateVM(ctx, CreateVMRequest{Name: "job-stop", CPUCores: 1, MemoryMB: 512, Manifest: manifest(0, ""),
+		Config: &vmconfig.Config{TTLSeconds: 60}})
+	if err != nil {
+		t.Fatalf("create job-stop: %v", err)
+	}
+	if stopped.ExpiresAt == nil || stopped.ExpiresAt.Before(before.Add(60*time.Second)) || stopped.ExpiresAt.After(time.Now().Add(60*time.Second)) {
+		t.Fatalf("expected expiry a minute after start, got %v", stopped.ExpiresAt)
+	}
+	deleted, err := e.CreateVM(ctx, CreateVMRequest{Name: "job-delete", CPUCores: 1, MemoryMB: 512, Manifest: manifest(30, "delete")})
+	if err != nil {
+		t.Fatalf("create job-delete: %v", err)
+	}
+	if deleted.ExpiresAt == nil {
+		t.Fatalf("expected the manifest default ttl to apply")
+	}
+	kept, err := e.CreateVM(ctx, CreateVMRequest{Name: "keep", CPUCores: 1, MemoryMB: 512, Manifest: manifest(0, "")})
+	if err != nil {
+		t.Fatalf("create keep: %v", err)
+	}
+	if kept.ExpiresAt != nil {
+		t.Fatalf("expected no expiry without a ttl, got %v", kept.ExpiresAt)
+	}
+	if _, err := e.CreateVM(ctx, CreateVMRequest{Name: "bad", CPUCores: 1, MemoryMB: 512, Manifest: manifest(0, ""),
+		Config: &vmconfig.Config{TTLSeconds: 60, TTLAction: "pause"}}); err == nil {
+		t.Fatalf("expected unknown ttl_action refused")
+	}
+
+	e.expireVMs(ctx, time.Now().Add(45*time.Second))
+	vms := e.store.Queries().VirtualMachines()
+	if vm, err := vms.GetByName(ctx, "job-delete"); err != nil || vm != nil {
+		t.Fatalf("expected job-delete deleted, got %+v (%v)", vm, err)
+	}
+	if vm, err := vms.GetByName(ctx, "job-stop"); err != nil || vm.Status != db.VMStatusRunning {
+		t.Fatalf("expected job-stop still running before its ttl, got %+v (%v)", vm, err)
+	}
+
+	e.expireVMs(ctx, time.Now().Add(2*time.Minute))
+	vm, err := vms.GetByName(ctx, "job-stop")
+	if err != nil || vm == nil {
+		t.Fatalf("expected job-stop kept, got %v", err)
+	}
+	if vm.Status != db.VMStatusStopped || vm.ExpiresAt != nil {
+		t.Fatalf("expected job-stop stopped with its expiry cleared, got %s %v", vm.Status, vm.ExpiresAt)
+	}
+	if vm, err := vms.GetByName(ctx, "keep"); err != nil || vm.Status != db.VMStatusRunning {
+		t.Fatalf("expected keep running, got %+v (%v)", vm, err)
+	}
+
+	restarted, err := e.StartVM(ctx, "job-stop")
+	if err != nil {
+		t.Fatalf("start job-stop: %v", err)
+	}
+	if restarted.ExpiresAt == nil || restarted.ExpiresAt.Before(time.Now().Add(50*time.Second)) {
+		t.Fatalf("expected the ttl to restart with the vm, got %v", restarted.ExpiresAt)
+	}
+}
+
 func TestDeploymentScaling(t *testing.T) {
 	ctx := context.Background()
 	store := openTestStore(t)
@@ -113,7 +1056,304 @@ func TestDeploymentScaling(t *testing.T) {
 	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
 
 	fakeLauncher := &testLauncher{}
-	fakeNetwork := &testNetworkManager{}
+	fakeNetwork := &testNetworkManager{}
+
+	engine, err := New(Params{
+		Store:            store,
+		Logger:           logger,
+		Subnet:           subnet,
+		HostIP:           host,
+		APIListenAddr:    "127.0.0.1:7777",
+		APIAdvertiseAddr: "127.0.0.1:7777",
+		RuntimeDir:       t.TempDir(),
+		Launcher:         fakeLauncher,
+		Network:          fakeNetwork,
+	})
+	if err != nil {
+		t.Fatalf("new engine: %v", err)
+	}
+
+	if err := engine.Start(ctx); err != nil {
+		t.Fatalf("engine start: %v", err)
+	}
+
+	config := vmconfig.Config{
+		Plugin:  "browser",
+		Runtime: "browser",
+		Resources: vmconfig.Resources{
+			CPUCores: 1,
+			MemoryMB: 512,
+		},
+		Manifest: &pluginspec.Manifest{Name: "browser", Runtime: "browser"},
+	}
+
+	deployment, err := engine.CreateDeployment(ctx, CreateDeploymentRequest{
+		Name:     "demo",
+		Replicas: 2,
+		Config:   config,
+	})
+	if err != nil {
+		t.Fatalf("create deployment: %v", err)
+	}
+	if deployment.DesiredReplicas != 2 {
+		t.Fatalf("expected desired replicas 2, got %d", deployment.DesiredReplicas)
+	}
+	if len(fakeLauncher.calls) != 2 {
+		t.Fatalf("expected 2 launch calls, got %d", len(fakeLauncher.calls))
+	}
+
+	deployment, err = engine.ScaleDeployment(ctx, "demo", 3)
+	if err != nil {
+		t.Fatalf("scale deployment up: %v", err)
+	}
+	if deployment.DesiredReplicas != 3 {
+		t.Fatalf("expected desired replicas 3, got %d", deployment.DesiredReplicas)
+	}
+
+	deployment, err = engine.ScaleDeployment(ctx, "demo", 1)
+	if err != nil {
+		t.Fatalf("scale deployment down: %v", err)
+	}
+	if deployment.DesiredReplicas != 1 {
+		t.Fatalf("expected desired replicas 1, got %d", deployment.DesiredReplicas)
+	}
+
+	if err := engine.DeleteDeployment(ctx, "demo"); err != nil {
+		t.Fatalf("delete deployment: %v", err)
+	}
+
+	if _, err := engine.GetDeployment(ctx, "demo"); err == nil {
+		t.Fatalf("expected error fetching deleted deployment")
+	}
+}
+
+func TestDeploymentReconcileQueue(t *testing.T) {
+	ctx := context.Background()
+	store := openTestStore(t)
+	defer func() { _ = store.Close(ctx) }()
+
+	subnet, host := testSubnet(t)
+	fakeLauncher := &testLauncher{}
+	engine, err := New(Params{
+		Store:            store,
+		Logger:           slog.New(slog.NewTextHandler(io.Discard, nil)),
+		Subnet:           subnet,
+		HostIP:           host,
+		APIListenAddr:    "127.0.0.1:7777",
+		APIAdvertiseAddr: "127.0.0.1:7777",
+		RuntimeDir:       t.TempDir(),
+		Launcher:         fakeLauncher,
+		Network:          &testNetworkManager{},
+	})
+	if err != nil {
+		t.Fatalf("new engine: %v", err)
+	}
+	if err := engine.Start(ctx); err != nil {
+		t.Fatalf("engine start: %v", err)
+	}
+	defer func() { _ = engine.Stop(ctx) }()
+
+	config := vmconfig.Config{
+		Plugin:    "browser",
+		Runtime:   "browser",
+		Resources: vmconfig.Resources{CPUCores: 1, MemoryMB: 256},
+		Manifest:  &pluginspec.Manifest{Name: "browser", Runtime: "browser"},
+	}
+	if _, err := engine.CreateDeployment(ctx, CreateDeploymentRequest{Name: "queue", Replicas: 2, Config: config}); err != nil {
+		t.Fatalf("create deployment: %v", err)
+	}
+	group, err := store.Queries().VMGroups().GetByName(ctx, "queue")
+	if err != nil || group == nil {
+		t.Fatalf("get deployment group: %v", err)
+	}
+
+	waitSynced := func(want int) *Deployment {
+		t.Helper()
+		deadline := time.Now().Add(10 * time.Second)
+		for {
+			deployment, err := engine.GetDeployment(ctx, "queue")
+			if err != nil {
+				t.Fatalf("get deployment: %v", err)
+			}
+			vms, err := store.Queries().VirtualMachines().ListByGroupID(ctx, group.ID)
+			if err != nil {
+				t.Fatalf("list replicas: %v", err)
+			}
+			if deployment.Reconcile.State == ReconcileStateSynced && len(vms) == want {
+				names := make(map[string]bool, len(vms))
+				for _, vm := range vms {
+					if names[vm.Name] {
+						t.Fatalf("duplicate replica %s", vm.Name)
+					}
+					names[vm.Name] = true
+				}
+				return deployment
+			}
+			if time.Now().After(deadline) {
+				t.Fatalf("expected %d replicas and a synced deployment, got %d (%+v)", want, len(vms), deployment.Reconcile)
+			}
+			time.Sleep(20 * time.Millisecond)
+		}
+	}
+
+	// Scale requests racing with replica deletions and crashes must leave
+	// exactly the last requested replica count.
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(2)
+		go func(replicas int) {
+			defer wg.Done()
+			if _, err := engine.ScaleDeployment(ctx, "queue", replicas); err != nil {
+				t.Errorf("scale deployment: %v", err)
+			}
+		}(i%4 + 1)
+		go func(index int) {
+			defer wg.Done()
+			name := replicaName("queue", index)
+			if index%2 == 0 {
+				fakeLauncher.mu.Lock()
+				_, launched := fakeLauncher.instances[name]
+				fakeLauncher.mu.Unlock()
+				if launched {
+					fakeLauncher.crash(name)
+				}
+				return
+			}
+			_ = engine.DestroyVM(ctx, name)
+		}(i%3 + 1)
+	}
+	wg.Wait()
+	if _, err := engine.ScaleDeployment(ctx, "queue", 3); err != nil {
+		t.Fatalf("scale deployment: %v", err)
+	}
+	waitSynced(3)
+
+	fakeLauncher.setFailure(errors.New("image broken"))
+	deployment, err := engine.ScaleDeployment(ctx, "queue", 4)
+	if err != nil {
+		t.Fatalf("expected a failed pass reported on the deployment, got %v", err)
+	}
+	status := deployment.Reconcile
+	if status.State != ReconcileStateRetrying || status.Failures != 1 || !strings.Contains(status.LastError, "image broken") || status.NextRetryAt == nil {
+		t.Fatalf("unexpected reconcile status after a failed pass: %+v", status)
+	}
+
+	fakeLauncher.setFailure(nil)
+	deployment = waitSynced(4)
+	if deployment.Reconcile.LastError != "" || deployment.Reconcile.Failures != 0 {
+		t.Fatalf("expected the retry to clear the error, got %+v", deployment.Reconcile)
+	}
+}
+
+func TestDeploymentRetryBudget(t *testing.T) {
+	policy := reconcileRetryPolicy{base: time.Second, max: 8 * time.Second, budget: 3}
+	for failures, want := range map[int]time.Duration{1: time.Second, 2: 2 * time.Second, 4: 8 * time.Second, 9: 8 * time.Second} {
+		for i := 0; i < 20; i++ {
+			if delay := policy.delay(failures); delay < want/2 || delay > want {
+				t.Fatalf("delay after %d failures = %s, want between %s and %s", failures, delay, want/2, want)
+			}
+		}
+	}
+
+	ctx := context.Background()
+	store := openTestStore(t)
+	defer func() { _ = store.Close(ctx) }()
+
+	subnet, host := testSubnet(t)
+	fakeLauncher := &testLauncher{}
+	probe := &testReadinessProbe{}
+	probe.ready.Store(true)
+	created, err := New(Params{
+		Store:            store,
+		Logger:           slog.New(slog.NewTextHandler(io.Discard, nil)),
+		Subnet:           subnet,
+		HostIP:           host,
+		APIListenAddr:    "127.0.0.1:7777",
+		APIAdvertiseAddr: "127.0.0.1:7777",
+		RuntimeDir:       t.TempDir(),
+		Launcher:         fakeLauncher,
+		Network:          &testNetworkManager{},
+		Readiness:        probe,
+	})
+	if err != nil {
+		t.Fatalf("new engine: %v", err)
+	}
+	e := created.(*engine)
+	e.reconcileRetry = reconcileRetryPolicy{base: 10 * time.Millisecond, max: 20 * time.Millisecond, budget: 3}
+	if err := e.Start(ctx); err != nil {
+		t.Fatalf("engine start: %v", err)
+	}
+	defer func() { _ = e.Stop(ctx) }()
+
+	config := vmconfig.Config{
+		Plugin:    "browser",
+		Runtime:   "browser",
+		Resources: vmconfig.Resources{CPUCores: 1, MemoryMB: 256},
+		Manifest:  &pluginspec.Manifest{Name: "browser", Runtime: "browser"},
+	}
+	fakeLauncher.setFailure(errors.New("image broken"))
+	if _, err := e.CreateDeployment(ctx, CreateDeploymentRequest{Name: "budget", Replicas: 2, Config: config}); err != nil {
+		t.Fatalf("create deployment: %v", err)
+	}
+	group, err := store.Queries().VMGroups().GetByName(ctx, "budget")
+	if err != nil || group == nil {
+		t.Fatalf("get deployment group: %v", err)
+	}
+
+	waitState := func(state string) *Deployment {
+		t.Helper()
+		deadline := time.Now().Add(10 * time.Second)
+		for {
+			deployment, err := e.GetDeployment(ctx, "budget")
+			if err != nil {
+				t.Fatalf("get deployment: %v", err)
+			}
+			if deployment.Reconcile.State == state {
+				return deployment
+			}
+			if time.Now().After(deadline) {
+				t.Fatalf("expected reconcile state %s, got %+v", state, deployment.Reconcile)
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+	}
+
+	deployment := waitState(ReconcileStateFailed)
+	if deployment.Condition != DeploymentFailed || deployment.Reconcile.Failures != 3 || deployment.Reconcile.FailedAt == nil || deployment.Reconcile.NextRetryAt != nil {
+		t.Fatalf("unexpected failed deployment: condition %s, %+v", deployment.Condition, deployment.Reconcile)
+	}
+
+	// Events must not revive a failed deployment.
+	fakeLauncher.setFailure(nil)
+	e.enqueueReconcile(group.ID)
+	time.Sleep(100 * time.Millisecond)
+	if deployment := waitState(ReconcileStateFailed); deployment.Reconcile.Failures != 3 {
+		t.Fatalf("expected no further passes, got %+v", deployment.Reconcile)
+	}
+
+	deployment, err = e.ScaleDeployment(ctx, "budget", 2)
+	if err != nil {
+		t.Fatalf("scale deployment: %v", err)
+	}
+	if deployment.Reconcile.State != ReconcileStateSynced || deployment.Reconcile.Failures != 0 || deployment.Reconcile.FailedAt != nil {
+		t.Fatalf("expected scaling to restore the budget, got %+v", deployment.Reconcile)
+	}
+	waitFor(t, func() bool {
+		dep, err := e.GetDeployment(ctx, "budget")
+		return err == nil && dep.Condition == DeploymentAvailable
+	})
+}
+
+func TestDeploymentMaxSurgeWaitsForReadiness(t *testing.T) {
+	ctx := context.Background()
+	store := openTestStore(t)
+	defer func() { _ = store.Close(ctx) }()
+
+	subnet, host := testSubnet(t)
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	fakeLauncher := &testLauncher{}
+	probe := &testReadinessProbe{}
 
 	engine, err := New(Params{
 		Store:            store,
@@ -124,63 +1364,358 @@ func TestDeploymentScaling(t *testing.T) {
 		APIAdvertiseAddr: "127.0.0.1:7777",
 		RuntimeDir:       t.TempDir(),
 		Launcher:         fakeLauncher,
-		Network:          fakeNetwork,
+		Network:          &testNetworkManager{},
+		Readiness:        probe,
 	})
 	if err != nil {
 		t.Fatalf("new engine: %v", err)
 	}
+	if err := engine.Start(ctx); err != nil {
+		t.Fatalf("engine start: %v", err)
+	}
+	defer func() { _ = engine.Stop(ctx) }()
+
+	deployment, err := engine.CreateDeployment(ctx, CreateDeploymentRequest{
+		Name:     "surge",
+		Replicas: 3,
+		MaxSurge: 1,
+		Config: vmconfig.Config{
+			Plugin:    "browser",
+			Runtime:   "browser",
+			Resources: vmconfig.Resources{CPUCores: 1, MemoryMB: 512},
+			Manifest:  &pluginspec.Manifest{Name: "browser", Runtime: "browser"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("create deployment: %v", err)
+	}
+	if deployment.ReadyReplicas != 0 {
+		t.Fatalf("expected no ready replicas before probe passes, got %d", deployment.ReadyReplicas)
+	}
+
+	waitFor(t, func() bool { return fakeLauncher.launches() == 1 })
+	time.Sleep(2 * readinessPollInterval)
+	if got := fakeLauncher.launches(); got != 1 {
+		t.Fatalf("expected rollout to wait for readiness, got %d launches", got)
+	}
+
+	probe.ready.Store(true)
+	waitFor(t, func() bool {
+		dep, err := engine.GetDeployment(ctx, "surge")
+		return err == nil && dep.ReadyReplicas == 3
+	})
+	if got := fakeLauncher.launches(); got != 3 {
+		t.Fatalf("expected 3 launches, got %d", got)
+	}
+}
+
+func TestDeploymentRollingUpdate(t *testing.T) {
+	ctx := context.Background()
+	store := openTestStore(t)
+	defer func() { _ = store.Close(ctx) }()
 
+	subnet, host := testSubnet(t)
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	probe := &testReadinessProbe{}
+	probe.ready.Store(true)
+
+	engine, err := New(Params{
+		Store:            store,
+		Logger:           logger,
+		Subnet:           subnet,
+		HostIP:           host,
+		APIListenAddr:    "127.0.0.1:7777",
+		APIAdvertiseAddr: "127.0.0.1:7777",
+		RuntimeDir:       t.TempDir(),
+		Launcher:         &testLauncher{},
+		Network:          &testNetworkManager{},
+		Readiness:        probe,
+	})
+	if err != nil {
+		t.Fatalf("new engine: %v", err)
+	}
 	if err := engine.Start(ctx); err != nil {
 		t.Fatalf("engine start: %v", err)
 	}
+	defer func() { _ = engine.Stop(ctx) }()
 
 	config := vmconfig.Config{
-		Plugin:  "browser",
-		Runtime: "browser",
-		Resources: vmconfig.Resources{
-			CPUCores: 1,
-			MemoryMB: 512,
-		},
-		Manifest: &pluginspec.Manifest{Name: "browser", Runtime: "browser"},
+		Plugin:    "browser",
+		Runtime:   "browser",
+		Resources: vmconfig.Resources{CPUCores: 1, MemoryMB: 512},
+		Manifest:  &pluginspec.Manifest{Name: "browser", Runtime: "browser"},
+	}
+	if _, err := engine.CreateDeployment(ctx, CreateDeploymentRequest{Name: "roll", Replicas: 2, Config: config}); err != nil {
+		t.Fatalf("create deployment: %v", err)
 	}
 
-	deployment, err := engine.CreateDeployment(ctx, CreateDeploymentRequest{
-		Name:     "demo",
-		Replicas: 2,
-		Config:   config,
+	updated := config.Clone()
+	updated.Resources.MemoryMB = 1024
+	if _, err := engine.UpdateDeploymentConfig(ctx, "roll", UpdateDeploymentConfigRequest{Config: updated, MaxSurge: 1}); err != nil {
+		t.Fatalf("update deployment config: %v", err)
+	}
+
+	waitFor(t, func() bool {
+		vms, err := engine.ListVMs(ctx)
+		if err != nil || len(vms) != 2 {
+			return false
+		}
+		for _, vm := range vms {
+			if vm.MemoryMB != 1024 {
+				return false
+			}
+		}
+		return true
+	})
+}
+
+func TestRollPluginUpgrade(t *testing.T) {
+	ctx := context.Background()
+	store := openTestStore(t)
+	defer func() { _ = store.Close(ctx) }()
+
+	subnet, host := testSubnet(t)
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	probe := &testReadinessProbe{}
+	probe.ready.Store(true)
+
+	engine, err := New(Params{
+		Store:            store,
+		Logger:           logger,
+		Subnet:           subnet,
+		HostIP:           host,
+		APIListenAddr:    "127.0.0.1:7777",
+		APIAdvertiseAddr: "127.0.0.1:7777",
+		RuntimeDir:       t.TempDir(),
+		Launcher:         &testLauncher{},
+		Network:          &testNetworkManager{},
+		Readiness:        probe,
 	})
 	if err != nil {
+		t.Fatalf("new engine: %v", err)
+	}
+	if err := engine.Start(ctx); err != nil {
+		t.Fatalf("engine start: %v", err)
+	}
+	defer func() { _ = engine.Stop(ctx) }()
+
+	config := vmconfig.Config{
+		Plugin:    "browser",
+		Runtime:   "browser",
+		Resources: vmconfig.Resources{CPUCores: 1, MemoryMB: 768},
+		Manifest:  &pluginspec.Manifest{Name: "browser", Version: "1.0.0", Runtime: "browser"},
+	}
+	if _, err := engine.CreateDeployment(ctx, CreateDeploymentRequest{Name: "web", Replicas: 2, Config: config}); err != nil {
 		t.Fatalf("create deployment: %v", err)
 	}
-	if deployment.DesiredReplicas != 2 {
-		t.Fatalf("expected desired replicas 2, got %d", deployment.DesiredReplicas)
+
+	rollout, err := engine.RollPluginUpgrade(ctx, pluginspec.Manifest{Name: "browser", Version: "1.1.0", Runtime: "browser"})
+	if err != nil {
+		t.Fatalf("roll plugin upgrade: %v", err)
 	}
-	if len(fakeLauncher.calls) != 2 {
-		t.Fatalf("expected 2 launch calls, got %d", len(fakeLauncher.calls))
+	if len(rollout.Restarted) != 1 || rollout.Restarted[0] != "web" || len(rollout.Skipped) != 0 {
+		t.Fatalf("expected deployment web rolled, got %+v", rollout)
 	}
 
-	deployment, err = engine.ScaleDeployment(ctx, "demo", 3)
+	waitFor(t, func() bool {
+		versions, err := engine.PluginVMVersions(ctx, "browser")
+		if err != nil || len(versions) != 2 {
+			return false
+		}
+		for _, entry := range versions {
+			if entry.Version != "1.1.0" || entry.Deployment != "web" {
+				return false
+			}
+			cfg, err := engine.GetVMConfig(ctx, entry.VM)
+			if err != nil || cfg.Config.UpgradedFrom != "" || cfg.Config.Resources.MemoryMB != 768 {
+				return false
+			}
+		}
+		return true
+	})
+
+	deployment, err := engine.GetDeployment(ctx, "web")
 	if err != nil {
-		t.Fatalf("scale deployment up: %v", err)
+		t.Fatalf("get deployment: %v", err)
 	}
-	if deployment.DesiredReplicas != 3 {
-		t.Fatalf("expected desired replicas 3, got %d", deployment.DesiredReplicas)
+	if deployment.Config.Manifest.Version != "1.1.0" || deployment.Config.UpgradedFrom != "1.0.0" {
+		t.Fatalf("expected deployment on 1.1.0 upgraded from 1.0.0, got %+v", deployment.Config)
 	}
+}
 
-	deployment, err = engine.ScaleDeployment(ctx, "demo", 1)
+func TestDeploymentScaleDuringRollingUpdate(t *testing.T) {
+	ctx := context.Background()
+	store := openTestStore(t)
+	defer func() { _ = store.Close(ctx) }()
+
+	subnet, host := testSubnet(t)
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	probe := &testReadinessProbe{}
+	probe.ready.Store(true)
+
+	engine, err := New(Params{
+		Store:            store,
+		Logger:           logger,
+		Subnet:           subnet,
+		HostIP:           host,
+		APIListenAddr:    "127.0.0.1:7777",
+		APIAdvertiseAddr: "127.0.0.1:7777",
+		RuntimeDir:       t.TempDir(),
+		Launcher:         &testLauncher{},
+		Network:          &testNetworkManager{},
+		Readiness:        probe,
+	})
 	if err != nil {
-		t.Fatalf("scale deployment down: %v", err)
+		t.Fatalf("new engine: %v", err)
 	}
-	if deployment.DesiredReplicas != 1 {
-		t.Fatalf("expected desired replicas 1, got %d", deployment.DesiredReplicas)
+	if err := engine.Start(ctx); err != nil {
+		t.Fatalf("engine start: %v", err)
 	}
+	defer func() { _ = engine.Stop(ctx) }()
 
-	if err := engine.DeleteDeployment(ctx, "demo"); err != nil {
-		t.Fatalf("delete deployment: %v", err)
+	config := vmconfig.Config{
+		Plugin:    "browser",
+		Runtime:   "browser",
+		Resources: vmconfig.Resources{CPUCores: 1, MemoryMB: 512},
+		Manifest:  &pluginspec.Manifest{Name: "browser", Runtime: "browser"},
+	}
+	if _, err := engine.CreateDeployment(ctx, CreateDeploymentRequest{Name: "roll", Replicas: 3, Config: config}); err != nil {
+		t.Fatalf("create deployment: %v", err)
 	}
+	waitFor(t, func() bool {
+		dep, err := engine.GetDeployment(ctx, "roll")
+		return err == nil && dep.ReadyReplicas == 3
+	})
 
-	if _, err := engine.GetDeployment(ctx, "demo"); err == nil {
-		t.Fatalf("expected error fetching deleted deployment")
+	// Hold the surge replica in its readiness wait.
+	probe.ready.Store(false)
+	updated := config.Clone()
+	updated.Resources.MemoryMB = 1024
+	if _, err := engine.UpdateDeploymentConfig(ctx, "roll", UpdateDeploymentConfigRequest{Config: updated, MaxSurge: 1}); err != nil {
+		t.Fatalf("update deployment config: %v", err)
+	}
+	surge := replicaName("roll", 4)
+	waitFor(t, func() bool {
+		vm, err := engine.GetVM(ctx, surge)
+		return err == nil && vm != nil
+	})
+
+	if _, err := engine.ScaleDeployment(ctx, "roll", 2); err != nil {
+		t.Fatalf("scale deployment: %v", err)
+	}
+	vms, err := engine.ListVMs(ctx)
+	if err != nil {
+		t.Fatalf("list vms: %v", err)
+	}
+	if len(vms) != 4 {
+		t.Fatalf("expected the scale-down deferred while the rollout surges, got %d vms", len(vms))
+	}
+	if vm, err := engine.GetVM(ctx, surge); err != nil || vm == nil {
+		t.Fatalf("expected the surge replica kept: %v", err)
+	}
+
+	probe.ready.Store(true)
+	waitFor(t, func() bool {
+		vms, err := engine.ListVMs(ctx)
+		if err != nil || len(vms) != 2 {
+			return false
+		}
+		for _, vm := range vms {
+			if vm.MemoryMB != 1024 {
+				return false
+			}
+		}
+		return true
+	})
+}
+
+func TestDeploymentScaleUpDuringRollingUpdate(t *testing.T) {
+	ctx := context.Background()
+	store := openTestStore(t)
+	defer func() { _ = store.Close(ctx) }()
+
+	subnet, host := testSubnet(t)
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	probe := &testReadinessProbe{}
+	probe.ready.Store(true)
+
+	engine, err := New(Params{
+		Store:            store,
+		Logger:           logger,
+		Subnet:           subnet,
+		HostIP:           host,
+		APIListenAddr:    "127.0.0.1:7777",
+		APIAdvertiseAddr: "127.0.0.1:7777",
+		RuntimeDir:       t.TempDir(),
+		Launcher:         &testLauncher{},
+		Network:          &testNetworkManager{},
+		Readiness:        probe,
+	})
+	if err != nil {
+		t.Fatalf("new engine: %v", err)
+	}
+	if err := engine.Start(ctx); err != nil {
+		t.Fatalf("engine start: %v", err)
+	}
+	defer func() { _ = engine.Stop(ctx) }()
+
+	config := vmconfig.Config{
+		Plugin:    "browser",
+		Runtime:   "browser",
+		Resources: vmconfig.Resources{CPUCores: 1, MemoryMB: 512},
+		Manifest:  &pluginspec.Manifest{Name: "browser", Runtime: "browser"},
+	}
+	if _, err := engine.CreateDeployment(ctx, CreateDeploymentRequest{Name: "roll", Replicas: 2, MaxSurge: 1, Config: config}); err != nil {
+		t.Fatalf("create deployment: %v", err)
+	}
+	waitFor(t, func() bool {
+		dep, err := engine.GetDeployment(ctx, "roll")
+		return err == nil && dep.ReadyReplicas == 2
+	})
+
+	// Hold the surge replica in its readiness wait.
+	probe.ready.Store(false)
+	updated := config.Clone()
+	updated.Resources.MemoryMB = 1024
+	if _, err := engine.UpdateDeploymentConfig(ctx, "roll", UpdateDeploymentConfigRequest{Config: updated, MaxSurge: 1}); err != nil {
+		t.Fatalf("update deployment config: %v", err)
+	}
+	waitFor(t, func() bool {
+		vm, err := engine.GetVM(ctx, replicaName("roll", 3))
+		return err == nil && vm != nil
+	})
+
+	if _, err := engine.ScaleDeployment(ctx, "roll", 4); err != nil {
+		t.Fatalf("scale deployment: %v", err)
+	}
+
+	probe.ready.Store(true)
+	waitFor(t, func() bool {
+		vms, err := engine.ListVMs(ctx)
+		if err != nil || len(vms) != 4 {
+			return false
+		}
+		for _, vm := range vms {
+			if vm.MemoryMB != 1024 {
+				return false
+			}
+		}
+		return true
+	})
+}
+
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(10 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("condition not met before deadline")
+		}
+		time.Sleep(50 * time.Millisecond)
 	}
 }
 
@@ -209,14 +1744,20 @@ func testSubnet(t *testing.T) (*net.IPNet, net.IP) {
 
 // testLauncher implements runtime.Launcher for unit tests.
 type testLauncher struct {
-	mu    sync.Mutex
-	pid   int
-	calls []runtime.LaunchSpec
+	mu        sync.Mutex
+	pid       int
+	calls     []runtime.LaunchSpec
+	instances map[string]*testInstance
+	// failure, when set, fails every launch.
+	failure error
 }
 
 func (t *testLauncher) Launch(ctx context.Context, spec runtime.LaunchSpec) (runtime.Instance, error) {
 	t.mu.Lock()
 	defer t.mu.Unlock()
+	if t.failure != nil {
+		return nil, t.failure
+	}
 	t.pid++
 	t.calls = append(t.calls, spec)
 	inst := &testInstance{
@@ -224,9 +1765,36 @@ func (t *testLauncher) Launch(ctx context.Context, spec runtime.LaunchSpec) (run
 		pid:  t.pid,
 		done: make(chan error, 1),
 	}
+	if t.instances == nil {
+		t.instances = make(map[string]*testInstance)
+	}
+	t.instances[spec.Name] = inst
 	return inst, nil
 }
 
+// crash makes the latest instance launched for name exit with an error.
+func (t *testLauncher) crash(name string) {
+	t.mu.Lock()
+	inst := t.instances[name]
+	t.mu.Unlock()
+	inst.once.Do(func() {
+		inst.done <- errors.New("exit status 1")
+		close(inst.done)
+	})
+}
+
+func (t *testLauncher) setFailure(err error) {
+	t.mu.Lock()
+	t.failure = err
+	t.mu.Unlock()
+}
+
+func (t *testLauncher) launches() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return len(t.calls)
+}
+
 type testInstance struct {
 	name string
 	pid  int
@@ -260,6 +1828,22 @@ func (n *testNetworkManager) CleanupTap(ctx context.Context, tapName string) err
 	return nil
 }
 
+// testReadinessProbe reports every VM ready once ready is set.
+type testReadinessProbe struct {
+	ready atomic.Bool
+}
+
+func (p *testReadinessProbe) Ready(ctx context.Context, vm db.VM) bool {
+	return p.ready.Load()
+}
+
 var _ runtime.Launcher = (*testLauncher)(nil)
 var _ runtime.Instance = (*testInstance)(nil)
 var _ network.Manager = (*testNetworkManager)(nil)
+var _ ReadinessProbe = (*testReadinessProbe)(nil)
+
+func TestAgentReadinessProbeNeedsAnAddress(t *testing.T) {
+	if NewAgentReadinessProbe().Ready(context.Background(), db.VM{Name: "web-1", Status: db.VMStatusRunning}) {
+		t.Fatalf("expected a vm with no ip or vsock cid reported not ready")
+	}
+}
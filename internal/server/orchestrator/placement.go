@@ -0,0 +1,87 @@
+// Copyright (c) 2025 HYPR. PTE. LTD.
+//
+// Business Source License 1.1
+// See LICENSE file in the project root for details.
+
+package orchestrator
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/volantvm/volant/internal/server/orchestrator/runtime"
+	"github.com/volantvm/volant/internal/server/orchestrator/topology"
+	"github.com/volantvm/volant/internal/server/orchestrator/vmconfig"
+)
+
+// resolvePlacement checks the CPU pinning, NUMA and huge page settings in res
+// against the host topology and copies the result into spec. A NUMA node
+// without a cpuset pins the vCPUs to that node's CPUs, and passthrough
+// devices in pciAddrs must sit on the requested node so a GPU and the memory
+// it DMAs into stay local. Errors wrap ErrPlacementUnsatisfiable.
+func (e *engine) resolvePlacement(res vmconfig.Resources, pciAddrs []string, spec *runtime.LaunchSpec) error {
+	if !res.Pinned() {
+		return nil
+	}
+	topo := e.topology
+	if topo == nil {
+		return fmt.Errorf("%w: host topology unavailable", ErrPlacementUnsatisfiable)
+	}
+
+	cpus, err := topology.ParseCPUSet(res.CPUSet)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrPlacementUnsatisfiable, err)
+	}
+	if offline := cpus.Without(topo.CPUs); len(offline) > 0 {
+		return fmt.Errorf("%w: cpus %s are not online (online: %s)", ErrPlacementUnsatisfiable, offline, topo.CPUs)
+	}
+
+	freePages := topo.FreeHugepages
+	if res.NUMANode != nil {
+		node, ok := topo.Node(*res.NUMANode)
+		if !ok {
+			return fmt.Errorf("%w: numa node %d does not exist", ErrPlacementUnsatisfiable, *res.NUMANode)
+		}
+		if len(cpus) == 0 {
+			cpus = node.CPUs
+		} else if remote := cpus.Without(node.CPUs); len(remote) > 0 {
+			return fmt.Errorf("%w: cpus %s are not on numa node %d (node cpus: %s)", ErrPlacementUnsatisfiable, remote, node.ID, node.CPUs)
+		}
+		for _, addr := range pciAddrs {
+			info, err := e.vfioMgr.GetDeviceInfo(addr)
+			if err != nil || info == nil {
+				continue
+			}
+			deviceNode, err := strconv.Atoi(strings.TrimSpace(info.NumaNode))
+			if err != nil || deviceNode < 0 {
+				continue
+			}
+			if deviceNode != node.ID {
+				return fmt.Errorf("%w: device %s is on numa node %d, not %d", ErrPlacementUnsatisfiable, addr, deviceNode, node.ID)
+			}
+		}
+		freePages = node.FreeHugepages
+		spec.NUMANode = &node.ID
+	}
+	if len(cpus) > 0 && len(cpus) < res.CPUCores {
+		e.logger.Warn("vcpus share pinned host cpus", "vm", spec.Name, "vcpus", res.CPUCores, "cpuset", cpus.String())
+	}
+	spec.CPUSet = cpus.String()
+
+	if res.Hugepages {
+		sizeKB, err := topology.PageSizeKB(res.HugepageSize)
+		if err != nil {
+			return fmt.Errorf("%w: %v", ErrPlacementUnsatisfiable, err)
+		}
+		free, ok := freePages[sizeKB]
+		if !ok {
+			return fmt.Errorf("%w: host has no %dKiB huge page pool", ErrPlacementUnsatisfiable, sizeKB)
+		}
+		if need := res.MemoryMB * 1024 / sizeKB; free < need {
+			return fmt.Errorf("%w: need %d free %dKiB huge pages, %d available", ErrPlacementUnsatisfiable, need, sizeKB, free)
+		}
+		spec.HugepageSizeKB = sizeKB
+	}
+	return nil
+}
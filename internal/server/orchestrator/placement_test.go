@@ -0,0 +1,107 @@
+// Copyright (c) 2025 HYPR. PTE. LTD.
+//
+// Business Source License 1.1
+// See LICENSE file in the project root for details.
+
+package orchestrator
+
+import (
+	"errors"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/volantvm/volant/internal/server/devicemanager"
+	"github.com/volantvm/volant/internal/server/orchestrator/runtime"
+	"github.com/volantvm/volant/internal/server/orchestrator/topology"
+	"github.com/volantvm/volant/internal/server/orchestrator/vmconfig"
+)
+
+func TestResolvePlacement(t *testing.T) {
+	sysfs := t.TempDir()
+	writeSysfs(t, sysfs, map[string]string{
+		"devices/system/cpu/online":                                           "0-7\n",
+		"devices/system/node/node0/cpulist":                                   "0-3\n",
+		"devices/system/node/node1/cpulist":                                   "4-7\n",
+		"devices/system/node/node0/hugepages/hugepages-2048kB/free_hugepages": "0\n",
+		"devices/system/node/node1/hugepages/hugepages-2048kB/free_hugepages": "512\n",
+		"kernel/mm/hugepages/hugepages-2048kB/free_hugepages":                 "512\n",
+	})
+	topo, err := topology.Read(sysfs)
+	if err != nil {
+		t.Fatalf("read topology: %v", err)
+	}
+	e := &engine{
+		logger:   slog.New(slog.NewTextHandler(io.Discard, nil)),
+		topology: topo,
+		vfioMgr:  numaDevices{"0000:01:00.0": "0", "0000:81:00.0": "1"},
+	}
+	node := func(id int) *int { return &id }
+
+	spec := runtime.LaunchSpec{Name: "gpu"}
+	res := vmconfig.Resources{CPUCores: 2, MemoryMB: 1024, NUMANode: node(1), Hugepages: true}
+	if err := e.resolvePlacement(res, []string{"0000:81:00.0"}, &spec); err != nil {
+		t.Fatalf("resolve placement: %v", err)
+	}
+	if spec.CPUSet != "4-7" || spec.NUMANode == nil || *spec.NUMANode != 1 || spec.HugepageSizeKB != 2048 {
+		t.Fatalf("unexpected placement cpuset=%q numa=%v hugepages=%d", spec.CPUSet, spec.NUMANode, spec.HugepageSizeKB)
+	}
+
+	unpinned := runtime.LaunchSpec{}
+	if err := (&engine{}).resolvePlacement(vmconfig.Resources{CPUCores: 1, MemoryMB: 512}, nil, &unpinned); err != nil || unpinned.CPUSet != "" {
+		t.Fatalf("expected unpinned vm untouched, got %+v %v", unpinned, err)
+	}
+
+	failures := map[string]struct {
+		res     vmconfig.Resources
+		devices []string
+	}{
+		"offline cpu":       {vmconfig.Resources{CPUCores: 1, MemoryMB: 512, CPUSet: "6-9"}, nil},
+		"cpu off node":      {vmconfig.Resources{CPUCores: 1, MemoryMB: 512, CPUSet: "2-5", NUMANode: node(1)}, nil},
+		"unknown node":      {vmconfig.Resources{CPUCores: 1, MemoryMB: 512, NUMANode: node(3)}, nil},
+		"remote device":     {vmconfig.Resources{CPUCores: 1, MemoryMB: 512, NUMANode: node(1)}, []string{"0000:01:00.0"}},
+		"no free pages":     {vmconfig.Resources{CPUCores: 1, MemoryMB: 512, NUMANode: node(0), Hugepages: true}, nil},
+		"missing page size": {vmconfig.Resources{CPUCores: 1, MemoryMB: 1024, Hugepages: true, HugepageSize: "1G"}, nil},
+	}
+	for name, tc := range failures {
+		spec := runtime.LaunchSpec{}
+		if err := e.resolvePlacement(tc.res, tc.devices, &spec); !errors.Is(err, ErrPlacementUnsatisfiable) {
+			t.Fatalf("%s: expected ErrPlacementUnsatisfiable, got %v", name, err)
+		}
+	}
+
+	noTopology := &engine{}
+	if err := noTopology.resolvePlacement(vmconfig.Resources{CPUCores: 1, MemoryMB: 512, CPUSet: "0"}, nil, &spec); !errors.Is(err, ErrPlacementUnsatisfiable) {
+		t.Fatalf("expected placement refused without topology, got %v", err)
+	}
+}
+
+func writeSysfs(t *testing.T, root string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		path := filepath.Join(root, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("mkdir %s: %v", name, err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatalf("write %s: %v", name, err)
+		}
+	}
+}
+
+// numaDevices reports the NUMA node of each PCI address.
+type numaDevices map[string]string
+
+func (d numaDevices) GetDeviceInfo(addr string) (*devicemanager.PCIDevice, error) {
+	return &devicemanager.PCIDevice{Address: addr, NumaNode: d[addr]}, nil
+}
+
+func (numaDevices) ValidateDevices([]string, []string) error { return nil }
+func (numaDevices) CheckIOMMUGroups([]string) ([]devicemanager.IOMMUGroup, error) {
+	return nil, nil
+}
+func (numaDevices) BindDevices([]string) error                   { return nil }
+func (numaDevices) UnbindDevices([]string) error                 { return nil }
+func (numaDevices) GetVFIOGroupPaths([]string) ([]string, error) { return nil, nil }
@@ -0,0 +1,133 @@
+// Copyright (c) 2025 HYPR. PTE. LTD.
+//
+// Business Source License 1.1
+// See LICENSE file in the project root for details.
+
+package orchestrator
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/volantvm/volant/internal/server/db"
+	"github.com/volantvm/volant/internal/server/orchestrator/network"
+	"github.com/volantvm/volant/internal/server/orchestrator/vmconfig"
+)
+
+// PortBinding describes a declared port mapping and whether volantd currently
+// has host rules installed for it.
+type PortBinding struct {
+	Host    int    `json:"host"`
+	Guest   int    `json:"guest"`
+	Proto   string `json:"proto"`
+	GuestIP string `json:"guest_ip,omitempty"`
+	Active  bool   `json:"active"`
+}
+
+// applyPortForwards installs host DNAT rules for the VM's port mappings,
+// replacing any rules installed for a previous configuration.
+func (e *engine) applyPortForwards(ctx context.Context, vm db.VM, mappings []vmconfig.PortMapping) error {
+	e.removePortForwards(ctx, vm.Name)
+	if len(mappings) == 0 {
+		return nil
+	}
+	forwarder, ok := e.network.(network.PortForwarder)
+	if !ok {
+		e.logger.Warn("network manager does not support port mappings, skipping", "vm", vm.Name)
+		return nil
+	}
+	guestIP := net.ParseIP(strings.TrimSpace(vm.IPAddress))
+	if guestIP == nil {
+		return fmt.Errorf("orchestrator: vm %s has no address for port mappings", vm.Name)
+	}
+
+	rules := make([]network.PortForward, 0, len(mappings))
+	for _, mapping := range mappings {
+		rules = append(rules, network.PortForward{
+			VMName:    vm.Name,
+			Proto:     mapping.Proto,
+			HostPort:  mapping.Host,
+			GuestIP:   guestIP,
+			GuestPort: mapping.Guest,
+		})
+	}
+
+	e.mu.Lock()
+	for owner, active := range e.ports {
+		if owner == vm.Name {
+			continue
+		}
+		for _, existing := range active {
+			for _, rule := range rules {
+				if existing.HostPort == rule.HostPort && existing.Proto == rule.Proto {
+					e.mu.Unlock()
+					return fmt.Errorf("%w: %d/%s used by vm %s", ErrHostPortInUse, rule.HostPort, rule.Proto, owner)
+				}
+			}
+		}
+	}
+	// Reserve the ports before programming so concurrent starts cannot race.
+	e.ports[vm.Name] = rules
+	e.mu.Unlock()
+
+	for i, rule := range rules {
+		if err := forwarder.AddPortForward(ctx, rule); err != nil {
+			for _, added := range rules[:i] {
+				_ = forwarder.RemovePortForward(ctx, added)
+			}
+			e.mu.Lock()
+			delete(e.ports, vm.Name)
+			e.mu.Unlock()
+			return err
+		}
+	}
+	return nil
+}
+
+// removePortForwards deletes the host rules installed for the VM.
+func (e *engine) removePortForwards(ctx context.Context, name string) {
+	e.mu.Lock()
+	rules := e.ports[name]
+	delete(e.ports, name)
+	e.mu.Unlock()
+	if len(rules) == 0 {
+		return
+	}
+	forwarder, ok := e.network.(network.PortForwarder)
+	if !ok {
+		return
+	}
+	for _, rule := range rules {
+		if err := forwarder.RemovePortForward(ctx, rule); err != nil {
+			e.logger.Warn("remove port mapping", "vm", name, "host_port", rule.HostPort, "proto", rule.Proto, "error", err)
+		}
+	}
+}
+
+// VMPorts reports the VM's declared port mappings and which of them are live.
+func (e *engine) VMPorts(ctx context.Context, name string) ([]PortBinding, error) {
+	current, err := e.GetVMConfig(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	e.mu.Lock()
+	active := append([]network.PortForward(nil), e.ports[name]...)
+	e.mu.Unlock()
+
+	bindings := make([]PortBinding, 0, len(current.Config.Ports))
+	for _, mapping := range current.Config.Ports {
+		binding := PortBinding{Host: mapping.Host, Guest: mapping.Guest, Proto: mapping.Proto}
+		for _, rule := range active {
+			if rule.HostPort == mapping.Host && rule.GuestPort == mapping.Guest && rule.Proto == mapping.Proto {
+				binding.GuestIP = rule.GuestIP.String()
+				binding.Active = true
+				break
+			}
+		}
+		bindings = append(bindings, binding)
+	}
+	return bindings, nil
+}
@@ -0,0 +1,107 @@
+// Copyright (c) 2025 HYPR. PTE. LTD.
+//
+// Business Source License 1.1
+// See LICENSE file in the project root for details.
+
+package orchestrator
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/volantvm/volant/internal/pluginspec"
+	"github.com/volantvm/volant/internal/server/db"
+	"github.com/volantvm/volant/internal/server/orchestrator/vmconfig"
+)
+
+// QuotaError reports a request exceeding a limit declared by its plugin. It
+// wraps ErrPluginQuotaExceeded.
+type QuotaError struct {
+	Plugin string
+	// Constraint is the violated limit, one of the pluginspec.LimitMax*
+	// names.
+	Constraint string
+	Limit      int
+	Requested  int
+}
+
+func (e *QuotaError) Error() string {
+	return fmt.Sprintf("plugin %s limits %s to %d, requested %d", e.Plugin, e.Constraint, e.Limit, e.Requested)
+}
+
+func (e *QuotaError) Unwrap() error { return ErrPluginQuotaExceeded }
+
+// pluginLimits returns the limits of the installed plugin, or those of
+// manifest when the plugin is not installed. The installed manifest wins so
+// a client cannot lift the limits with an inline manifest.
+func pluginLimits(ctx context.Context, q db.Queries, plugin string, manifest *pluginspec.Manifest) (*pluginspec.Limits, error) {
+	if plugin = strings.TrimSpace(plugin); plugin != "" {
+		installed, err := q.Plugins().GetByName(ctx, plugin)
+		if err != nil {
+			return nil, fmt.Errorf("lookup plugin %s: %w", plugin, err)
+		}
+		if installed != nil && len(installed.Metadata) > 0 {
+			var stored pluginspec.Manifest
+			if err := json.Unmarshal(installed.Metadata, &stored); err != nil {
+				return nil, fmt.Errorf("parse plugin %s manifest: %w", plugin, err)
+			}
+			return stored.Limits, nil
+		}
+	}
+	if manifest == nil {
+		return nil, nil
+	}
+	return manifest.Limits, nil
+}
+
+// checkResourceLimits fails with a *QuotaError when a VM of plugin asks for
+// more than its per-VM caps.
+func checkResourceLimits(plugin string, limits *pluginspec.Limits, cpuCores, memoryMB int) error {
+	constraint, limit, requested := limits.Exceeded(cpuCores, memoryMB)
+	if constraint == "" {
+		return nil
+	}
+	return &QuotaError{Plugin: plugin, Constraint: constraint, Limit: limit, Requested: requested}
+}
+
+// checkVMQuota fails with a *QuotaError when adding VMs to plugin would take
+// it past max_vms. Standalone VMs count once and deployments count their
+// desired replicas; the deployment with ID skipGroup is left out so a scale
+// can count its new replica count instead.
+func checkVMQuota(ctx context.Context, q db.Queries, plugin string, limits *pluginspec.Limits, adding int, skipGroup int64) error {
+	if limits == nil || limits.MaxVMs <= 0 || adding <= 0 {
+		return nil
+	}
+	vms, _, err := q.VirtualMachines().ListPage(ctx, db.VMListOptions{Plugin: plugin, Limit: -1})
+	if err != nil {
+		return err
+	}
+	desired := adding
+	for _, vm := range vms {
+		if vm.GroupID == nil {
+			desired++
+		}
+	}
+	groups, err := q.VMGroups().List(ctx)
+	if err != nil {
+		return err
+	}
+	for _, group := range groups {
+		if group.ID == skipGroup {
+			continue
+		}
+		config, err := vmconfig.Unmarshal(group.ConfigJSON)
+		if err != nil {
+			return err
+		}
+		if strings.EqualFold(strings.TrimSpace(config.Plugin), plugin) {
+			desired += group.Replicas
+		}
+	}
+	if desired > limits.MaxVMs {
+		return &QuotaError{Plugin: plugin, Constraint: pluginspec.LimitMaxVMs, Limit: limits.MaxVMs, Requested: desired}
+	}
+	return nil
+}
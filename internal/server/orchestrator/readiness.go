@@ -0,0 +1,234 @@
+// Copyright (c) 2025 HYPR. PTE. LTD.
+//
+// Business Source License 1.1
+// See LICENSE file in the project root for details.
+
+package orchestrator
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/volantvm/volant/internal/server/db"
+	"github.com/volantvm/volant/internal/server/orchestrator/vmconfig"
+)
+
+// AgentPort is the port the guest agent listens on, over TCP and vsock.
+const AgentPort = 8080
+
+const (
+	agentHealthPath         = "/healthz"
+	readinessProbeTimeout   = 2 * time.Second
+	readinessPollInterval   = 500 * time.Millisecond
+	defaultReadinessTimeout = 2 * time.Minute
+)
+
+// ReadinessProbe reports whether a running VM is able to serve requests.
+type ReadinessProbe interface {
+	Ready(ctx context.Context, vm db.VM) bool
+}
+
+// agentReadinessProbe treats a VM as ready once its guest agent answers
+// /healthz, over vsock for guests without an IP.
+type agentReadinessProbe struct{}
+
+// NewAgentReadinessProbe returns a probe that polls the guest agent health endpoint.
+func NewAgentReadinessProbe() ReadinessProbe {
+	return agentReadinessProbe{}
+}
+
+// Ready reports false for a VM with neither an IP nor a vsock CID: with no
+// agent to ask, its readiness cannot be confirmed.
+func (agentReadinessProbe) Ready(ctx context.Context, vm db.VM) bool {
+	transport, base, err := agentTransport(vm)
+	if err != nil {
+		return false
+	}
+	defer transport.CloseIdleConnections()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, base+agentHealthPath, nil)
+	if err != nil {
+		return false
+	}
+	resp, err := (&http.Client{Timeout: readinessProbeTimeout, Transport: transport}).Do(req)
+	if err != nil {
+		return false
+	}
+	_ = resp.Body.Close()
+	return resp.StatusCode >= 200 && resp.StatusCode < 300
+}
+
+// watchReadiness polls the readiness probe until the instance reports ready,
+// the instance goes away, or the engine shuts down. Firmware-booted guests
+// run no agent to probe, so they count as ready once launched.
+func (e *engine) watchReadiness(vm db.VM, handle processHandle, firmware bool) {
+	if handle.ready == nil {
+		return
+	}
+	if firmware {
+		handle.ready.Store(true)
+		return
+	}
+	ctx := e.launchContext()
+	go func() {
+		ticker := time.NewTicker(readinessPollInterval)
+		defer ticker.Stop()
+		for {
+			if !e.isCurrentInstance(vm.Name, handle) {
+				return
+			}
+			if e.readiness.Ready(ctx, vm) {
+				e.runPostUpgradeHooks(ctx, vm)
+				handle.ready.Store(true)
+				e.logger.Info("vm ready", "vm", vm.Name)
+				return
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+func (e *engine) isCurrentInstance(name string, handle processHandle) bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	stored, ok := e.instances[name]
+	return ok && stored.instance == handle.instance
+}
+
+func (e *engine) hasInstance(name string) bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	_, ok := e.instances[name]
+	return ok
+}
+
+// isReady reports whether the named VM has a live instance that passed its readiness probe.
+func (e *engine) isReady(name string) bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	handle, ok := e.instances[name]
+	return ok && handle.ready != nil && handle.ready.Load()
+}
+
+// waitForReady blocks until every named VM is ready or has exited, or the
+// timeout elapses.
+func (e *engine) waitForReady(ctx context.Context, names []string, timeout time.Duration) error {
+	if len(names) == 0 {
+		return nil
+	}
+	if timeout <= 0 {
+		timeout = defaultReadinessTimeout
+	}
+	waitCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	ticker := time.NewTicker(readinessPollInterval)
+	defer ticker.Stop()
+	for {
+		pending := make([]string, 0, len(names))
+		for _, name := range names {
+			if e.hasInstance(name) && !e.isReady(name) {
+				pending = append(pending, name)
+			}
+		}
+		if len(pending) == 0 {
+			return nil
+		}
+		select {
+		case <-waitCtx.Done():
+			return fmt.Errorf("orchestrator: replicas not ready after %s: %s", timeout, strings.Join(pending, ", "))
+		case <-ticker.C:
+		}
+		names = pending
+	}
+}
+
+// startRollout scales a surge-limited deployment up in the background so API
+// callers are not blocked while replicas boot. Only one rollout runs per group;
+// it re-reads the desired replica count on every step.
+func (e *engine) startRollout(groupID int64) {
+	for !e.claimRollout(groupID) {
+		// The running rollout may be a rolling update, which does not scale
+		// up; leave the scale-up to the pass that follows it.
+		if e.deferToRollout(groupID) {
+			return
+		}
+	}
+
+	ctx := e.launchContext()
+	go func() {
+		defer e.releaseRollout(groupID)
+		if err := e.runRollout(ctx, groupID); err != nil {
+			e.logger.Error("deployment rollout", "group_id", groupID, "error", err)
+		}
+	}()
+}
+
+func (e *engine) runRollout(ctx context.Context, groupID int64) error {
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		group, err := e.store.Queries().VMGroups().GetByID(ctx, groupID)
+		if err != nil {
+			return err
+		}
+		if group == nil {
+			return nil
+		}
+		config, err := vmconfig.Unmarshal(group.ConfigJSON)
+		if err != nil {
+			return err
+		}
+		if config.Manifest == nil {
+			return fmt.Errorf("deployment %s missing manifest", group.Name)
+		}
+		vms, err := e.store.Queries().VirtualMachines().ListByGroupID(ctx, group.ID)
+		if err != nil {
+			return err
+		}
+
+		if len(vms) >= group.Replicas {
+			return nil
+		}
+
+		var booting []string
+		for _, vm := range vms {
+			if vm.Status == db.VMStatusRunning && !e.isReady(vm.Name) {
+				booting = append(booting, vm.Name)
+			}
+		}
+		if len(booting) >= group.MaxSurge {
+			if err := e.waitForReady(ctx, booting, readinessTimeout(config)); err != nil {
+				return fmt.Errorf("deployment %s: %w", group.Name, err)
+			}
+			continue
+		}
+
+		count := group.MaxSurge - len(booting)
+		if missing := group.Replicas - len(vms); missing < count {
+			count = missing
+		}
+		created, err := e.addReplicas(ctx, *group, config, count, true)
+		if err != nil && len(created) == 0 {
+			return fmt.Errorf("deployment %s: failed to create replicas: %w", group.Name, err)
+		}
+	}
+}
+
+// readinessTimeout honours a plugin health check timeout when it exceeds the default.
+func readinessTimeout(config vmconfig.Config) time.Duration {
+	timeout := defaultReadinessTimeout
+	if config.Manifest != nil {
+		if hc := time.Duration(config.Manifest.HealthCheck.Timeout) * time.Millisecond; hc > timeout {
+			timeout = hc
+		}
+	}
+	return timeout
+}
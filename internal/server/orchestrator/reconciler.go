@@ -0,0 +1,364 @@
+// Copyright (c) 2025 HYPR. PTE. LTD.
+//
+// Business Source License 1.1
+// See LICENSE file in the project root for details.
+
+package orchestrator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"sync"
+	"time"
+
+	"github.com/volantvm/volant/internal/server/db"
+	orchestratorevents "github.com/volantvm/volant/internal/server/orchestrator/events"
+	"github.com/volantvm/volant/internal/server/orchestrator/vmconfig"
+)
+
+// Reconcile states reported on a deployment.
+const (
+	// ReconcileStateSynced means the last pass succeeded and none is queued.
+	ReconcileStateSynced = "synced"
+	// ReconcileStatePending means a pass is queued.
+	ReconcileStatePending = "pending"
+	// ReconcileStateReconciling means a pass is running.
+	ReconcileStateReconciling = "reconciling"
+	// ReconcileStateRetrying means the last pass failed and a retry is
+	// scheduled for NextRetryAt.
+	ReconcileStateRetrying = "retrying"
+	// ReconcileStateFailed means the retry budget is used up. Events no
+	// longer trigger passes; scaling the deployment or updating its config
+	// starts over with a fresh budget.
+	ReconcileStateFailed = "failed"
+)
+
+// Deployment conditions.
+const (
+	// DeploymentAvailable means every desired replica is ready.
+	DeploymentAvailable = "available"
+	// DeploymentProgressing means replicas are still being created or are
+	// booting.
+	DeploymentProgressing = "progressing"
+	// DeploymentFailed means reconciling gave up; see ReconcileStateFailed.
+	DeploymentFailed = "failed"
+)
+
+// reconcileRetryPolicy paces the retries of failed passes.
+type reconcileRetryPolicy struct {
+	// base is the delay after the first failure; it doubles with every
+	// failure in a row up to max.
+	base time.Duration
+	max  time.Duration
+	// budget is how many passes may fail in a row before the deployment is
+	// marked failed.
+	budget int
+}
+
+var defaultReconcileRetry = reconcileRetryPolicy{base: time.Second, max: time.Minute, budget: 10}
+
+// ReconcileStatus describes the reconciler's view of a deployment. It is
+// kept in memory and starts over when volantd restarts.
+type ReconcileStatus struct {
+	State            string
+	LastReconciledAt *time.Time
+	// LastError is the error of the last pass; empty once a pass succeeds.
+	LastError string
+	// Failures counts the passes that have failed in a row.
+	Failures    int
+	NextRetryAt *time.Time
+	// FailedAt is when the retry budget ran out; nil unless State is failed.
+	FailedAt *time.Time
+}
+
+// deploymentWork is the queue entry of one deployment. A deployment has at
+// most one pass running and one queued; requests that arrive while a pass is
+// queued are merged into it.
+type deploymentWork struct {
+	// write serialises everything that adds or removes the deployment's
+	// replicas: reconcile passes, rollout steps and deletion.
+	write   sync.Mutex
+	running bool
+	queued  bool
+	waiters []chan reconcileResult
+	retry   *time.Timer
+	status  ReconcileStatus
+}
+
+type reconcileResult struct {
+	deployment *Deployment
+	err        error
+}
+
+// deploymentWorkLocked returns the queue entry of groupID. e.mu must be held.
+func (e *engine) deploymentWorkLocked(groupID int64) *deploymentWork {
+	work, ok := e.deployWork[groupID]
+	if !ok {
+		work = &deploymentWork{status: ReconcileStatus{State: ReconcileStateSynced}}
+		e.deployWork[groupID] = work
+	}
+	return work
+}
+
+// lockDeployment takes the writer lock of a deployment; the caller must
+// call the returned function to release it.
+func (e *engine) lockDeployment(groupID int64) func() {
+	e.mu.Lock()
+	work := e.deploymentWorkLocked(groupID)
+	e.mu.Unlock()
+	work.write.Lock()
+	return work.write.Unlock
+}
+
+// enqueueReconcile queues a pass for the deployment and returns without
+// waiting for it. While a retry is scheduled the request is merged into it,
+// so a failing deployment is not reconciled on every event, and a failed
+// deployment ignores it.
+func (e *engine) enqueueReconcile(groupID int64) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	work := e.deploymentWorkLocked(groupID)
+	if work.retry != nil || work.status.State == ReconcileStateFailed {
+		return
+	}
+	e.queueReconcileLocked(groupID, work)
+}
+
+// reconcileAndWait queues a pass for the deployment, skipping any scheduled
+// retry and restoring the retry budget, and waits for it. Failures of the
+// pass are reported on the returned deployment's Reconcile status; an error
+// is returned only when the deployment cannot be read.
+func (e *engine) reconcileAndWait(ctx context.Context, groupID int64) (*Deployment, error) {
+	done := make(chan reconcileResult, 1)
+	e.mu.Lock()
+	work := e.deploymentWorkLocked(groupID)
+	e.resetRetriesLocked(work)
+	work.waiters = append(work.waiters, done)
+	e.queueReconcileLocked(groupID, work)
+	e.mu.Unlock()
+
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case result := <-done:
+		return result.deployment, result.err
+	}
+}
+
+// retryDeployment restores the retry budget of a deployment and queues a
+// pass, reviving a failed one.
+func (e *engine) retryDeployment(groupID int64) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	work := e.deploymentWorkLocked(groupID)
+	e.resetRetriesLocked(work)
+	e.queueReconcileLocked(groupID, work)
+}
+
+// resetRetriesLocked cancels a scheduled retry and restores the retry
+// budget. e.mu must be held.
+func (e *engine) resetRetriesLocked(work *deploymentWork) {
+	if work.retry != nil {
+		work.retry.Stop()
+		work.retry = nil
+	}
+	work.status.Failures = 0
+	work.status.NextRetryAt = nil
+	work.status.FailedAt = nil
+	if work.status.State == ReconcileStateFailed || work.status.State == ReconcileStateRetrying {
+		work.status.State = ReconcileStatePending
+	}
+}
+
+// queueReconcileLocked marks a pass as queued and starts the deployment's
+// worker if it is idle. e.mu must be held.
+func (e *engine) queueReconcileLocked(groupID int64, work *deploymentWork) {
+	work.queued = true
+	if !work.running {
+		work.status.State = ReconcileStatePending
+		work.running = true
+		go e.runReconciles(groupID, work)
+	}
+}
+
+// runReconciles is the deployment's single worker: it runs queued passes
+// one at a time until none is left.
+func (e *engine) runReconciles(groupID int64, work *deploymentWork) {
+	ctx := e.launchContext()
+	for {
+		e.mu.Lock()
+		if !work.queued || ctx.Err() != nil {
+			work.running = false
+			e.mu.Unlock()
+			return
+		}
+		work.queued = false
+		waiters := work.waiters
+		work.waiters = nil
+		work.status.State = ReconcileStateReconciling
+		e.mu.Unlock()
+
+		work.write.Lock()
+		deployment, err := e.reconcileDeploymentByID(ctx, groupID)
+		work.write.Unlock()
+
+		e.mu.Lock()
+		failed := e.recordReconcileLocked(groupID, work, err)
+		e.mu.Unlock()
+
+		result := reconcileResult{deployment: deployment, err: err}
+		if deployment != nil {
+			deployment.Reconcile = e.reconcileStatus(groupID)
+			deployment.Condition = deploymentCondition(*deployment)
+			result.err = nil
+			if failed {
+				e.publishDeploymentMessage(ctx, orchestratorevents.TypeDeploymentFailed, *deployment, deployment.Reconcile.LastError)
+			}
+		}
+		for _, waiter := range waiters {
+			waiter <- result
+		}
+	}
+}
+
+// recordReconcileLocked updates the status after a pass and schedules a
+// retry when it failed. It reports whether the pass used up the retry
+// budget. e.mu must be held.
+func (e *engine) recordReconcileLocked(groupID int64, work *deploymentWork, err error) bool {
+	now := time.Now().UTC()
+	work.status.LastReconciledAt = &now
+	if err == nil {
+		work.status.LastError = ""
+		work.status.Failures = 0
+		work.status.NextRetryAt = nil
+		work.status.State = ReconcileStateSynced
+		if work.queued {
+			work.status.State = ReconcileStatePending
+		}
+		return false
+	}
+	work.status.LastError = err.Error()
+	work.status.Failures++
+	work.status.NextRetryAt = nil
+	switch {
+	case e.deployWork[groupID] != work || errors.Is(err, ErrDeploymentNotFound):
+		// The deployment is gone; there is nothing to retry.
+		work.status.State = ReconcileStateSynced
+		return false
+	case work.status.Failures >= e.reconcileRetry.budget && len(work.waiters) == 0:
+		work.queued = false
+		work.status.State = ReconcileStateFailed
+		work.status.FailedAt = &now
+		e.logger.Error("deployment reconcile failed, giving up", "group_id", groupID, "failures", work.status.Failures, "error", err)
+		return true
+	case work.queued:
+		work.status.State = ReconcileStatePending
+		return false
+	}
+	delay := e.reconcileRetry.delay(work.status.Failures)
+	next := now.Add(delay)
+	work.status.NextRetryAt = &next
+	work.status.State = ReconcileStateRetrying
+	work.retry = time.AfterFunc(delay, func() {
+		e.mu.Lock()
+		defer e.mu.Unlock()
+		if e.deployWork[groupID] != work {
+			return
+		}
+		work.retry = nil
+		e.queueReconcileLocked(groupID, work)
+	})
+	e.logger.Warn("deployment reconcile failed", "group_id", groupID, "failures", work.status.Failures, "retry_in", delay, "error", err)
+	return false
+}
+
+// delay returns how long to wait after the given number of failures in a
+// row. Half of it is random, so deployments that failed together do not
+// retry in lockstep.
+func (p reconcileRetryPolicy) delay(failures int) time.Duration {
+	delay := p.base
+	for i := 1; i < failures && delay < p.max; i++ {
+		delay *= 2
+	}
+	delay = min(delay, p.max)
+	return delay/2 + rand.N(delay/2+1)
+}
+
+// deploymentCondition summarises a deployment for clients.
+func deploymentCondition(deployment Deployment) string {
+	switch {
+	case deployment.Reconcile.State == ReconcileStateFailed:
+		return DeploymentFailed
+	case deployment.ReadyReplicas >= deployment.DesiredReplicas:
+		return DeploymentAvailable
+	default:
+		return DeploymentProgressing
+	}
+}
+
+// reconcileStatus returns a copy of the deployment's reconcile status.
+func (e *engine) reconcileStatus(groupID int64) ReconcileStatus {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	work, ok := e.deployWork[groupID]
+	if !ok {
+		return ReconcileStatus{State: ReconcileStateSynced}
+	}
+	status := work.status
+	if status.LastReconciledAt != nil {
+		at := *status.LastReconciledAt
+		status.LastReconciledAt = &at
+	}
+	if status.NextRetryAt != nil {
+		at := *status.NextRetryAt
+		status.NextRetryAt = &at
+	}
+	if status.FailedAt != nil {
+		at := *status.FailedAt
+		status.FailedAt = &at
+	}
+	return status
+}
+
+// forgetDeployment drops the queue entry of a deleted deployment and cancels
+// its retry. A pass that is still running finds the deployment gone.
+func (e *engine) forgetDeployment(groupID int64) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	work, ok := e.deployWork[groupID]
+	if !ok {
+		return
+	}
+	if work.retry != nil {
+		work.retry.Stop()
+		work.retry = nil
+	}
+	delete(e.deployWork, groupID)
+}
+
+// addReplicas creates up to count replicas under the deployment's writer
+// lock, listing the existing ones under the same lock so the indices it
+// picks cannot collide with a concurrent pass. With upToDesired it creates no
+// more than the deployment is missing.
+func (e *engine) addReplicas(ctx context.Context, group db.VMGroup, config vmconfig.Config, count int, upToDesired bool) ([]string, error) {
+	unlock := e.lockDeployment(group.ID)
+	defer unlock()
+	vms, err := e.store.Queries().VirtualMachines().ListByGroupID(ctx, group.ID)
+	if err != nil {
+		return nil, err
+	}
+	if upToDesired {
+		count = min(count, group.Replicas-len(vms))
+	}
+	if count <= 0 {
+		return nil, nil
+	}
+	return e.createReplicas(ctx, group, config, vms, count)
+}
+
+// errReplicasFailed reports replicas a pass could not create.
+func errReplicasFailed(name string, missing int, err error) error {
+	return fmt.Errorf("deployment %s: %d replica(s) not created: %w", name, missing, err)
+}
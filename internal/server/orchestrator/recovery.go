@@ -0,0 +1,197 @@
+// Copyright (c) 2025 HYPR. PTE. LTD.
+//
+// Business Source License 1.1
+// See LICENSE file in the project root for details.
+
+package orchestrator
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+
+	"github.com/volantvm/volant/internal/server/db"
+	orchestratorevents "github.com/volantvm/volant/internal/server/orchestrator/events"
+	"github.com/volantvm/volant/internal/server/orchestrator/network"
+	"github.com/volantvm/volant/internal/server/orchestrator/runtime"
+	"github.com/volantvm/volant/internal/server/orchestrator/vmconfig"
+)
+
+const seedImageSuffix = "-seed.img"
+
+// reconcileRuntime brings stored VM state in line with the hypervisor
+// processes that survived a volantd restart. Live instances are adopted and
+// monitored again; VMs recorded as running without a process are marked
+// stopped; processes, taps and seed images nothing owns are removed.
+// Failures are logged so one broken VM does not block startup.
+func (e *engine) reconcileRuntime(ctx context.Context) error {
+	vms, err := e.store.Queries().VirtualMachines().List(ctx)
+	if err != nil {
+		return err
+	}
+	attacher, _ := e.launcher.(runtime.Attacher)
+
+	adopted := make(map[string]struct{})
+	keepTaps := make(map[string]struct{})
+	for _, vm := range vms {
+		if e.hasInstance(vm.Name) {
+			adopted[vm.Name] = struct{}{}
+			continue
+		}
+		if attacher != nil {
+			attachment, err := attacher.Attach(ctx, vm.Name)
+			if err == nil {
+				e.adoptInstance(ctx, vm, attachment)
+				adopted[vm.Name] = struct{}{}
+				if attachment.TapDevice != "" {
+					keepTaps[attachment.TapDevice] = struct{}{}
+				}
+				for _, tap := range attachment.Interfaces {
+					keepTaps[tap] = struct{}{}
+				}
+				continue
+			}
+			if !errors.Is(err, runtime.ErrInstanceNotFound) {
+				e.logger.Warn("adopt vm instance", "vm", vm.Name, "error", err)
+			}
+		}
+		if err := e.store.Queries().DeviceAssignments().ReleaseVM(ctx, vm.ID); err != nil {
+			e.logger.Warn("release device assignments", "vm", vm.Name, "error", err)
+		}
+		if vm.Status == db.VMStatusRunning || vm.Status == db.VMStatusStarting {
+			e.setVMState(ctx, vm.ID, db.VMStatusStopped, nil)
+			vm.Status = db.VMStatusStopped
+			vm.PID = nil
+			e.logger.Info("vm not running after restart, marked stopped", "vm", vm.Name)
+			e.publishEvent(ctx, orchestratorevents.TypeVMStopped, orchestratorevents.VMStatusStopped, &vm, "vm was not running when volantd restarted")
+		}
+	}
+
+	if attacher != nil {
+		e.discardLeftovers(ctx, attacher, adopted)
+	}
+	e.sweepTaps(ctx, keepTaps)
+	e.sweepSeedImages(adopted)
+	return nil
+}
+
+// adoptInstance registers a surviving instance as if StartVM had launched it.
+func (e *engine) adoptInstance(ctx context.Context, vm db.VM, attachment *runtime.Attachment) {
+	seedPath := ""
+	if record, err := e.store.Queries().VMCloudInit().Get(ctx, vm.ID); err == nil && record != nil {
+		seedPath = strings.TrimSpace(record.SeedPath)
+	}
+	serial := attachment.SerialSocket
+	if serial == "" {
+		serial = vm.SerialSocket
+	}
+	handle := processHandle{instance: attachment.Instance, tapName: attachment.TapDevice, serial: serial, seedPath: seedPath, ready: new(atomic.Bool)}
+
+	e.mu.Lock()
+	e.instances[vm.Name] = handle
+	if len(attachment.Interfaces) > 0 {
+		e.nics[vm.Name] = append([]string(nil), attachment.Interfaces...)
+	}
+	if pooled := e.pooledDevices(attachment.Devices); len(pooled) > 0 {
+		e.devices[vm.Name] = pooled
+	}
+	e.mu.Unlock()
+
+	pid := int64(attachment.Instance.PID())
+	if vm.Status != db.VMStatusRunning || vm.PID == nil || *vm.PID != pid {
+		e.setVMState(ctx, vm.ID, db.VMStatusRunning, &pid)
+	}
+	vm.Status = db.VMStatusRunning
+	vm.PID = &pid
+
+	firmware := false
+	// Port mappings, drift routes and neighbor proxies are idempotent;
+	// re-apply them in case the firewall was reloaded or driftd lost its
+	// routes while volantd was down.
+	if record, err := e.store.Queries().VMConfigs().GetCurrent(ctx, vm.ID); err == nil && record != nil {
+		if versioned, err := vmconfig.FromDB(*record); err == nil {
+			cfg := versioned.Config
+			firmware = cfg.Manifest.FirmwareBoot()
+			if err := e.applyPortForwards(ctx, vm, cfg.Ports); err != nil {
+				e.logger.Warn("restore port mappings", "vm", vm.Name, "error", err)
+			}
+			if err := e.applyDriftRoutes(ctx, vm, resolveNetworkConfig(cfg.Manifest, &cfg), cfg.Expose); err != nil {
+				e.logger.Warn("restore drift routes", "vm", vm.Name, "error", err)
+			}
+		}
+	}
+	e.addNeighborProxies(ctx, vm)
+
+	e.monitorInstance(vm.Name, handle)
+	e.watchReadiness(vm, handle, firmware)
+	e.logger.Info("adopted running vm", "vm", vm.Name, "pid", pid)
+	e.publishEvent(ctx, orchestratorevents.TypeVMRunning, orchestratorevents.VMStatusRunning, &vm, "vm adopted after volantd restart")
+}
+
+// discardLeftovers stops processes whose VM no longer exists and removes the
+// runtime state of instances that are gone.
+func (e *engine) discardLeftovers(ctx context.Context, attacher runtime.Attacher, adopted map[string]struct{}) {
+	names, err := attacher.Leftovers()
+	if err != nil {
+		e.logger.Warn("list leftover instances", "error", err)
+		return
+	}
+	for _, name := range names {
+		if _, ok := adopted[name]; ok {
+			continue
+		}
+		if attachment, err := attacher.Attach(ctx, name); err == nil {
+			e.logger.Warn("stopping orphaned vm process", "vm", name, "pid", attachment.Instance.PID())
+			if err := attachment.Instance.Stop(ctx); err != nil {
+				e.logger.Warn("stop orphaned vm process", "vm", name, "error", err)
+			}
+		}
+		if err := attacher.Discard(name); err != nil {
+			e.logger.Warn("discard instance state", "vm", name, "error", err)
+		}
+	}
+}
+
+// sweepTaps deletes volantd-created taps that no adopted instance uses.
+func (e *engine) sweepTaps(ctx context.Context, keep map[string]struct{}) {
+	lister, ok := e.network.(network.TapLister)
+	if !ok {
+		return
+	}
+	taps, err := lister.ListTaps(ctx)
+	if err != nil {
+		e.logger.Warn("list taps", "error", err)
+		return
+	}
+	for _, tap := range taps {
+		if _, ok := keep[tap]; ok {
+			continue
+		}
+		if err := e.network.CleanupTap(ctx, tap); err != nil {
+			e.logger.Warn("remove stale tap", "tap", tap, "error", err)
+			continue
+		}
+		e.logger.Info("removed stale tap", "tap", tap)
+	}
+}
+
+// sweepSeedImages removes cloud-init seed images of VMs that are not running;
+// StartVM builds a fresh one on every boot.
+func (e *engine) sweepSeedImages(running map[string]struct{}) {
+	matches, err := filepath.Glob(filepath.Join(e.runtimeDir, "cloudinit", "*"+seedImageSuffix))
+	if err != nil {
+		return
+	}
+	for _, path := range matches {
+		name := strings.TrimSuffix(filepath.Base(path), seedImageSuffix)
+		if _, ok := running[name]; ok {
+			continue
+		}
+		if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			e.logger.Warn("remove stale seed image", "path", path, "error", err)
+		}
+	}
+}
@@ -0,0 +1,157 @@
+// Copyright (c) 2025 HYPR. PTE. LTD.
+//
+// Business Source License 1.1
+// See LICENSE file in the project root for details.
+
+package orchestrator
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/volantvm/volant/internal/pluginspec"
+	"github.com/volantvm/volant/internal/server/db"
+	"github.com/volantvm/volant/internal/server/orchestrator/runtime"
+)
+
+func TestStartReconcilesSurvivingInstances(t *testing.T) {
+	ctx := context.Background()
+	store := openTestStore(t)
+	defer func() { _ = store.Close(ctx) }()
+	subnet, host := testSubnet(t)
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	runtimeDir := t.TempDir()
+
+	newEngine := func(launcher runtime.Launcher, netw *recoveryNetworkManager) Engine {
+		engine, err := New(Params{
+			Store:            store,
+			Logger:           logger,
+			Subnet:           subnet,
+			HostIP:           host,
+			APIListenAddr:    "127.0.0.1:7777",
+			APIAdvertiseAddr: "127.0.0.1:7777",
+			RuntimeDir:       runtimeDir,
+			Launcher:         launcher,
+			Network:          netw,
+		})
+		if err != nil {
+			t.Fatalf("new engine: %v", err)
+		}
+		if err := engine.Start(ctx); err != nil {
+			t.Fatalf("engine start: %v", err)
+		}
+		return engine
+	}
+
+	first := newEngine(&testLauncher{}, &recoveryNetworkManager{})
+	for _, name := range []string{"alive", "dead"} {
+		if _, err := first.CreateVM(ctx, CreateVMRequest{
+			Name:     name,
+			Plugin:   "browser",
+			Runtime:  "browser",
+			CPUCores: 1,
+			MemoryMB: 512,
+			Manifest: &pluginspec.Manifest{Name: "browser", Runtime: "browser"},
+		}); err != nil {
+			t.Fatalf("create vm %s: %v", name, err)
+		}
+	}
+	seeds := filepath.Join(runtimeDir, "cloudinit")
+	if err := os.MkdirAll(seeds, 0o755); err != nil {
+		t.Fatalf("mkdir seeds: %v", err)
+	}
+	for _, name := range []string{"alive", "dead"} {
+		if err := os.WriteFile(filepath.Join(seeds, name+seedImageSuffix), nil, 0o644); err != nil {
+			t.Fatalf("write seed: %v", err)
+		}
+	}
+
+	// Simulate a restart: a new engine over the same store, with only one
+	// hypervisor process and one orphan left behind.
+	launcher := &attachingLauncher{
+		live:      map[string]*testInstance{"alive": {name: "alive", pid: 4242, done: make(chan error, 1)}, "orphan": {name: "orphan", pid: 4343, done: make(chan error, 1)}},
+		leftovers: []string{"alive", "dead", "orphan"},
+	}
+	netw := &recoveryNetworkManager{taps: []string{"tap-alive", "tap-dead"}}
+	second := newEngine(launcher, netw)
+
+	alive, err := second.GetVM(ctx, "alive")
+	if err != nil || alive.Status != db.VMStatusRunning || alive.PID == nil || *alive.PID != 4242 {
+		t.Fatalf("expected adopted vm running with pid 4242, got %+v %v", alive, err)
+	}
+	dead, err := second.GetVM(ctx, "dead")
+	if err != nil || dead.Status != db.VMStatusStopped || dead.PID != nil {
+		t.Fatalf("expected dead vm stopped, got %+v %v", dead, err)
+	}
+
+	select {
+	case <-launcher.live["orphan"].done:
+	default:
+		t.Fatalf("expected orphaned process to be stopped")
+	}
+	if got := launcher.discarded; len(got) != 2 || got[0] != "dead" || got[1] != "orphan" {
+		t.Fatalf("expected dead and orphan discarded, got %v", got)
+	}
+	if len(netw.removed) != 1 || netw.removed[0] != "tap-dead" {
+		t.Fatalf("expected only the stale tap removed, got %v", netw.removed)
+	}
+	if _, err := os.Stat(filepath.Join(seeds, "alive"+seedImageSuffix)); err != nil {
+		t.Fatalf("expected adopted vm seed kept: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(seeds, "dead"+seedImageSuffix)); !os.IsNotExist(err) {
+		t.Fatalf("expected stale seed removed, got %v", err)
+	}
+
+	// The adopted instance is monitored: its exit is recorded.
+	if err := launcher.live["alive"].Stop(ctx); err != nil {
+		t.Fatalf("stop adopted instance: %v", err)
+	}
+	waitFor(t, func() bool {
+		vm, err := second.GetVM(ctx, "alive")
+		return err == nil && vm.Status == db.VMStatusStopped
+	})
+}
+
+// attachingLauncher adopts the instances in live, as if their processes
+// survived a volantd restart.
+type attachingLauncher struct {
+	testLauncher
+	live      map[string]*testInstance
+	leftovers []string
+	discarded []string
+}
+
+func (l *attachingLauncher) Attach(ctx context.Context, name string) (*runtime.Attachment, error) {
+	inst, ok := l.live[name]
+	if !ok {
+		return nil, runtime.ErrInstanceNotFound
+	}
+	return &runtime.Attachment{Instance: inst, TapDevice: "tap-" + name}, nil
+}
+
+func (l *attachingLauncher) Leftovers() ([]string, error) { return l.leftovers, nil }
+
+func (l *attachingLauncher) Discard(name string) error {
+	l.discarded = append(l.discarded, name)
+	return nil
+}
+
+// recoveryNetworkManager reports a fixed set of existing taps.
+type recoveryNetworkManager struct {
+	testNetworkManager
+	taps    []string
+	removed []string
+}
+
+func (n *recoveryNetworkManager) ListTaps(ctx context.Context) ([]string, error) { return n.taps, nil }
+
+func (n *recoveryNetworkManager) CleanupTap(ctx context.Context, tap string) error {
+	n.removed = append(n.removed, tap)
+	return nil
+}
+
+var _ runtime.Attacher = (*attachingLauncher)(nil)
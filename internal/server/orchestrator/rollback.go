@@ -0,0 +1,304 @@
+// Copyright (c) 2025 HYPR. PTE. LTD.
+//
+// Business Source License 1.1
+// See LICENSE file in the project root for details.
+
+package orchestrator
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/volantvm/volant/internal/server/db"
+	orchestratorevents "github.com/volantvm/volant/internal/server/orchestrator/events"
+	"github.com/volantvm/volant/internal/server/orchestrator/runtime"
+	"github.com/volantvm/volant/internal/server/orchestrator/vmconfig"
+)
+
+// VMConfigUpdateOptions controls how ApplyVMConfig rolls a configuration
+// change onto a VM.
+type VMConfigUpdateOptions struct {
+	// Restart restarts the VM so the new configuration takes effect.
+	Restart bool
+	// Rollback snapshots the running VM before restarting and restores the
+	// snapshot when the VM does not pass its readiness probe within
+	// HealthWindow. Requires Restart.
+	Rollback bool
+	// HealthWindow bounds how long the restarted VM has to become ready; zero
+	// uses the plugin health check timeout or the readiness default.
+	HealthWindow time.Duration
+}
+
+// ApplyVMConfig stores a configuration patch and optionally restarts the VM
+// onto it. With Rollback set the restart and health check run in the
+// background; the outcome is recorded against the new version in the config
+// history.
+func (e *engine) ApplyVMConfig(ctx context.Context, name string, patch vmconfig.Patch, opts VMConfigUpdateOptions) (*vmconfig.Versioned, error) {
+	if opts.Rollback && !opts.Restart {
+		return nil, fmt.Errorf("orchestrator: rollback requires restart")
+	}
+	if !opts.Restart {
+		return e.UpdateVMConfig(ctx, name, patch)
+	}
+	if !e.claimConfigUpdate(name) {
+		return nil, fmt.Errorf("%w: %s", ErrConfigUpdateInProgress, name)
+	}
+
+	if !opts.Rollback {
+		defer e.releaseConfigUpdate(name)
+		updated, err := e.UpdateVMConfig(ctx, name, patch)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := e.RestartVM(ctx, name); err != nil {
+			return nil, err
+		}
+		return updated, nil
+	}
+
+	previous, err := e.GetVMConfig(ctx, name)
+	if err != nil {
+		e.releaseConfigUpdate(name)
+		return nil, err
+	}
+	snapshot, err := e.SnapshotVM(ctx, name)
+	if err != nil {
+		e.releaseConfigUpdate(name)
+		return nil, err
+	}
+	updated, err := e.UpdateVMConfig(ctx, name, patch)
+	if err != nil {
+		e.releaseConfigUpdate(name)
+		_ = os.RemoveAll(snapshot.Path)
+		return nil, err
+	}
+	e.setConfigOutcome(ctx, name, updated.Version, vmconfig.OutcomePending, "")
+
+	window := opts.HealthWindow
+	if window <= 0 {
+		window = readinessTimeout(updated.Config)
+	}
+	superviseCtx := e.launchContext()
+	go func() {
+		defer e.releaseConfigUpdate(name)
+		defer os.RemoveAll(snapshot.Path)
+		e.superviseConfigUpdate(superviseCtx, name, previous.Config, updated.Version, snapshot, window)
+	}()
+	return updated, nil
+}
+
+// superviseConfigUpdate restarts the VM onto the new configuration and rolls
+// back to the snapshot if it does not become healthy within window.
+func (e *engine) superviseConfigUpdate(ctx context.Context, name string, previous vmconfig.Config, version int, snapshot *Snapshot, window time.Duration) {
+	var failure error
+	if _, err := e.RestartVM(ctx, name); err != nil {
+		failure = fmt.Errorf("restart failed: %w", err)
+	} else if err := e.waitForHealthy(ctx, name, window); err != nil {
+		failure = err
+	}
+	if failure == nil {
+		e.logger.Info("vm config update healthy", "vm", name, "version", version)
+		e.setConfigOutcome(ctx, name, version, vmconfig.OutcomeHealthy, "")
+		return
+	}
+
+	e.logger.Warn("vm config update unhealthy, rolling back", "vm", name, "version", version, "error", failure)
+	outcome, message := e.rollbackConfigUpdate(ctx, name, previous, version, snapshot, failure)
+	e.setConfigOutcome(ctx, name, version, outcome, message)
+}
+
+// rollbackConfigUpdate writes the previous configuration back as a new version
+// and resumes the VM from the snapshot, falling back to a cold start when the
+// runtime cannot restore it.
+func (e *engine) rollbackConfigUpdate(ctx context.Context, name string, previous vmconfig.Config, version int, snapshot *Snapshot, cause error) (string, string) {
+	restored, err := e.writeVMConfig(ctx, name, func(vmconfig.Config) (vmconfig.Config, error) {
+		return previous.Clone(), nil
+	})
+	if err != nil {
+		return vmconfig.OutcomeRollbackFailed, fmt.Sprintf("%v; write previous config: %v", cause, err)
+	}
+	restoredNote := fmt.Sprintf("rollback of version %d", version)
+
+	if _, err := e.StopVM(ctx, name); err != nil {
+		e.setConfigOutcome(ctx, name, restored.Version, vmconfig.OutcomeRollbackFailed, restoredNote)
+		return vmconfig.OutcomeRollbackFailed, fmt.Sprintf("%v; stop vm: %v", cause, err)
+	}
+
+	message := fmt.Sprintf("%v; restored snapshot as version %d", cause, restored.Version)
+	if _, err := e.restoreVM(ctx, name, snapshot); err != nil {
+		e.logger.Warn("restore snapshot failed, cold starting previous config", "vm", name, "error", err)
+		if _, startErr := e.StartVM(ctx, name); startErr != nil {
+			e.setConfigOutcome(ctx, name, restored.Version, vmconfig.OutcomeRollbackFailed, restoredNote)
+			return vmconfig.OutcomeRollbackFailed, fmt.Sprintf("%v; restore snapshot: %v; start previous config: %v", cause, err, startErr)
+		}
+		message = fmt.Sprintf("%v; snapshot restore failed (%v), started previous config as version %d", cause, err, restored.Version)
+	}
+	e.setConfigOutcome(ctx, name, restored.Version, vmconfig.OutcomeRestored, restoredNote)
+	return vmconfig.OutcomeRolledBack, message
+}
+
+// waitForHealthy blocks until the VM passes its readiness probe, exits, or
+// window elapses.
+func (e *engine) waitForHealthy(ctx context.Context, name string, window time.Duration) error {
+	waitCtx, cancel := context.WithTimeout(ctx, window)
+	defer cancel()
+
+	ticker := time.NewTicker(readinessPollInterval)
+	defer ticker.Stop()
+	for {
+		if !e.hasInstance(name) {
+			return fmt.Errorf("vm exited before becoming healthy")
+		}
+		if e.isReady(name) {
+			return nil
+		}
+		select {
+		case <-waitCtx.Done():
+			return fmt.Errorf("vm not healthy within %s", window)
+		case <-ticker.C:
+		}
+	}
+}
+
+// restoreVM resumes a stopped VM from a snapshot taken by SnapshotVM.
+func (e *engine) restoreVM(ctx context.Context, name string, snapshot *Snapshot) (*db.VM, error) {
+	restorer, ok := e.launcher.(runtime.Restorer)
+	if !ok {
+		return nil, fmt.Errorf("orchestrator: runtime does not support snapshot restore")
+	}
+	if e.hasInstance(name) {
+		return nil, fmt.Errorf("orchestrator: vm %s already running", name)
+	}
+
+	vmRecord, err := e.GetVM(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	if vmRecord == nil {
+		return nil, fmt.Errorf("%w: %s", ErrVMNotFound, name)
+	}
+	current, err := e.GetVMConfig(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	cfg := current.Config
+	networkCfg := resolveNetworkConfig(cfg.Manifest, &cfg)
+	userNetwork, err := vmNetwork(ctx, e.store.Queries(), networkCfg)
+	if err != nil {
+		return nil, err
+	}
+
+	tapName := ""
+	if needsTapDevice(networkCfg) {
+		tap, err := e.preparePrimaryTap(ctx, *vmRecord, userNetwork)
+		if err != nil {
+			return nil, runtime.NewLaunchError(runtime.ErrorTapCreate, err)
+		}
+		tapName = tap
+	}
+	if err := e.applyEgressPolicy(ctx, vmRecord.Name, tapName, networkCfg); err != nil {
+		_ = e.network.CleanupTap(ctx, tapName)
+		return nil, err
+	}
+	extraNICs, _, err := e.prepareInterfaces(ctx, vmRecord.Name, networkCfg)
+	if err != nil {
+		_ = e.network.CleanupTap(ctx, tapName)
+		return nil, err
+	}
+	launched := false
+	defer func() {
+		if !launched {
+			e.releaseInterfaces(ctx, vmRecord.Name)
+		}
+	}()
+
+	spec := runtime.LaunchSpec{
+		Name:         vmRecord.Name,
+		TapDevice:    tapName,
+		MACAddress:   vmRecord.MACAddress,
+		IPAddress:    vmRecord.IPAddress,
+		VsockCID:     vmRecord.VsockCID,
+		SerialSocket: vmRecord.SerialSocket,
+		Interfaces:   extraNICs,
+	}
+	e.captureLogs(&spec)
+	spec.Shares, _ = launchShares(resolveShares(cfg.Manifest, &cfg))
+	spec.BootFirmware = cfg.Manifest.FirmwareBoot()
+	instance, err := restorer.Restore(e.launchContext(), spec, snapshot.Path)
+	if err != nil {
+		_ = e.network.CleanupTap(ctx, tapName)
+		return nil, err
+	}
+
+	pid := int64(instance.PID())
+	if err := e.store.WithTx(ctx, func(q db.Queries) error {
+		return q.VirtualMachines().UpdateRuntimeState(ctx, vmRecord.ID, db.VMStatusRunning, &pid)
+	}); err != nil {
+		_ = instance.Stop(ctx)
+		_ = e.network.CleanupTap(ctx, tapName)
+		return nil, err
+	}
+
+	if e.drift != nil && len(cfg.Expose) > 0 {
+		if err := e.applyDriftRoutes(ctx, *vmRecord, networkCfg, cfg.Expose); err != nil {
+			e.logger.Error("apply drift routes after restore", "vm", name, "error", err)
+		}
+	}
+
+	if err := e.applyPortForwards(ctx, *vmRecord, cfg.Ports); err != nil {
+		e.logger.Error("apply port mappings after restore", "vm", name, "error", err)
+	}
+
+	e.addNeighborProxies(ctx, *vmRecord)
+
+	seedPath := ""
+	if record, err := e.store.Queries().VMCloudInit().Get(ctx, vmRecord.ID); err == nil && record != nil {
+		seedPath = strings.TrimSpace(record.SeedPath)
+	}
+
+	e.mu.Lock()
+	handle := processHandle{instance: instance, tapName: tapName, serial: spec.SerialSocket, seedPath: seedPath, ready: new(atomic.Bool)}
+	e.instances[vmRecord.Name] = handle
+	e.mu.Unlock()
+	launched = true
+
+	e.monitorInstance(vmRecord.Name, handle)
+	e.watchReadiness(*vmRecord, handle, spec.BootFirmware)
+
+	vmRecord.Status = db.VMStatusRunning
+	vmRecord.PID = &pid
+
+	e.publishEvent(ctx, orchestratorevents.TypeVMRunning, orchestratorevents.VMStatusRunning, vmRecord, "vm restored from snapshot")
+	return vmRecord, nil
+}
+
+func (e *engine) setConfigOutcome(ctx context.Context, name string, version int, outcome, message string) {
+	vm, err := e.GetVM(ctx, name)
+	if err != nil || vm == nil {
+		e.logger.Error("record config outcome", "vm", name, "version", version, "error", err)
+		return
+	}
+	if err := e.store.Queries().VMConfigs().SetOutcome(ctx, vm.ID, version, outcome, message); err != nil {
+		e.logger.Error("record config outcome", "vm", name, "version", version, "error", err)
+	}
+}
+
+func (e *engine) claimConfigUpdate(name string) bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if _, running := e.configUpdates[name]; running {
+		return false
+	}
+	e.configUpdates[name] = struct{}{}
+	return true
+}
+
+func (e *engine) releaseConfigUpdate(name string) {
+	e.mu.Lock()
+	delete(e.configUpdates, name)
+	e.mu.Unlock()
+}
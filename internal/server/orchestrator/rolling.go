@@ -0,0 +1,198 @@
+// Copyright (c) 2025 HYPR. PTE. LTD.
+//
+// Business Source License 1.1
+// See LICENSE file in the project root for details.
+
+package orchestrator
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/volantvm/volant/internal/server/db"
+	"github.com/volantvm/volant/internal/server/orchestrator/vmconfig"
+)
+
+// UpdateDeploymentConfigRequest replaces a deployment's config and controls how
+// existing replicas are rolled onto it.
+type UpdateDeploymentConfigRequest struct {
+	Config vmconfig.Config
+	// MaxSurge is the number of extra replicas that may run above the desired
+	// count while the update is in progress.
+	MaxSurge int
+	// MaxUnavailable is the number of replicas that may be taken down before
+	// their replacements are ready.
+	MaxUnavailable int
+}
+
+func (e *engine) UpdateDeploymentConfig(ctx context.Context, name string, req UpdateDeploymentConfigRequest) (*Deployment, error) {
+	if req.MaxSurge < 0 || req.MaxUnavailable < 0 {
+		return nil, fmt.Errorf("orchestrator: max surge and max unavailable must be >= 0")
+	}
+	if req.MaxSurge == 0 && req.MaxUnavailable == 0 {
+		return nil, fmt.Errorf("orchestrator: max surge and max unavailable cannot both be 0")
+	}
+
+	config, err := e.normalizeDeploymentConfig(ctx, req.Config)
+	if err != nil {
+		return nil, err
+	}
+	configPayload, err := vmconfig.Marshal(config)
+	if err != nil {
+		return nil, err
+	}
+
+	group, err := e.store.Queries().VMGroups().GetByName(ctx, strings.TrimSpace(name))
+	if err != nil {
+		return nil, err
+	}
+	if group == nil {
+		return nil, fmt.Errorf("%w: %s", ErrDeploymentNotFound, name)
+	}
+	if !e.claimRollout(group.ID) {
+		return nil, fmt.Errorf("%w: %s", ErrRolloutInProgress, name)
+	}
+
+	var outdated []string
+	if err := e.store.WithTx(ctx, func(q db.Queries) error {
+		vms, err := q.VirtualMachines().ListByGroupID(ctx, group.ID)
+		if err != nil {
+			return err
+		}
+		for _, vm := range vms {
+			outdated = append(outdated, vm.Name)
+		}
+		return q.VMGroups().Update(ctx, group.ID, configPayload, group.Replicas)
+	}); err != nil {
+		e.releaseRollout(group.ID)
+		return nil, err
+	}
+
+	sort.Slice(outdated, func(i, j int) bool {
+		iIdx, _ := parseReplicaIndex(group.Name, outdated[i])
+		jIdx, _ := parseReplicaIndex(group.Name, outdated[j])
+		return iIdx < jIdx
+	})
+
+	rolloutCtx := e.launchContext()
+	go func() {
+		defer e.releaseRollout(group.ID)
+		if err := e.rollReplicas(rolloutCtx, group.ID, outdated, req.MaxSurge, req.MaxUnavailable); err != nil {
+			e.logger.Error("rolling update", "deployment", group.Name, "error", err)
+			return
+		}
+		e.logger.Info("rolling update complete", "deployment", group.Name, "replaced", len(outdated))
+	}()
+
+	// A new config may fix what made the deployment fail.
+	e.retryDeployment(group.ID)
+
+	group.ConfigJSON = configPayload
+	deployment, err := e.buildDeployment(ctx, *group)
+	if err != nil {
+		return nil, err
+	}
+	return &deployment, nil
+}
+
+// rollReplicas replaces the outdated replicas in batches: it surges up to
+// maxSurge new replicas, waits for them to become ready, then retires up to
+// maxSurge+maxUnavailable old ones and backfills any shortfall.
+func (e *engine) rollReplicas(ctx context.Context, groupID int64, outdated []string, maxSurge, maxUnavailable int) error {
+	for len(outdated) > 0 {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		group, err := e.store.Queries().VMGroups().GetByID(ctx, groupID)
+		if err != nil {
+			return err
+		}
+		if group == nil {
+			return nil
+		}
+		config, err := vmconfig.Unmarshal(group.ConfigJSON)
+		if err != nil {
+			return err
+		}
+		if config.Manifest == nil {
+			return fmt.Errorf("deployment %s missing manifest", group.Name)
+		}
+		timeout := readinessTimeout(config)
+
+		surge := min(maxSurge, len(outdated))
+		var created []string
+		if surge > 0 {
+			created, err = e.addReplicas(ctx, *group, config, surge, false)
+			if len(created) == 0 {
+				return fmt.Errorf("deployment %s: failed to create surge replicas: %w", group.Name, err)
+			}
+			if err := e.waitForReady(ctx, created, timeout); err != nil {
+				return fmt.Errorf("deployment %s: %w", group.Name, err)
+			}
+		}
+
+		retire := min(len(created)+maxUnavailable, len(outdated))
+		unlock := e.lockDeployment(group.ID)
+		for _, vmName := range outdated[:retire] {
+			if _, err := e.destroyVM(ctx, vmName, false); err != nil {
+				e.logger.Error("rolling update retire replica", "deployment", group.Name, "vm", vmName, "error", err)
+			}
+		}
+		unlock()
+		outdated = outdated[retire:]
+
+		if backfill := retire - len(created); backfill > 0 {
+			replacements, err := e.addReplicas(ctx, *group, config, backfill, true)
+			if err != nil && len(replacements) == 0 {
+				return fmt.Errorf("deployment %s: failed to create replacement replicas: %w", group.Name, err)
+			}
+			if len(replacements) > 0 {
+				if err := e.waitForReady(ctx, replacements, timeout); err != nil {
+					return fmt.Errorf("deployment %s: %w", group.Name, err)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// claimRollout marks a background rollout as running for the group. It returns
+// false when another rollout already owns the group.
+func (e *engine) claimRollout(groupID int64) bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if _, running := e.rollouts[groupID]; running {
+		return false
+	}
+	e.rollouts[groupID] = false
+	return true
+}
+
+// releaseRollout ends the group's rollout and queues a reconcile pass when
+// one was deferred while it ran.
+func (e *engine) releaseRollout(groupID int64) {
+	e.mu.Lock()
+	deferred := e.rollouts[groupID]
+	delete(e.rollouts, groupID)
+	e.mu.Unlock()
+	if deferred {
+		e.enqueueReconcile(groupID)
+	}
+}
+
+// deferToRollout reports whether a rollout owns the group and, if so, has the
+// group reconciled again once it ends. A rolling update's surge replicas run
+// above the desired count on purpose and it does not scale up, so passes that
+// would scale the group while it runs wait for it instead. Callers hold the
+// deployment's writer lock, under which rollouts create their replicas.
+func (e *engine) deferToRollout(groupID int64) bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if _, running := e.rollouts[groupID]; !running {
+		return false
+	}
+	e.rollouts[groupID] = true
+	return true
+}
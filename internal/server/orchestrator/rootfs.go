@@ -0,0 +1,149 @@
+// Copyright (c) 2025 HYPR. PTE. LTD.
+//
+// Business Source License 1.1
+// See LICENSE file in the project root for details.
+
+package orchestrator
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/volantvm/volant/internal/pluginspec"
+	"github.com/volantvm/volant/internal/server/orchestrator/runtime"
+	"github.com/volantvm/volant/internal/server/orchestrator/vmconfig"
+)
+
+// defaultOverlaySizeMB sizes disk-backed overlays when none is configured.
+const defaultOverlaySizeMB = 1024
+
+// effectiveRootFS returns the root filesystem settings a VM boots with: the
+// manifest's, with the image and overlay options replaced by any per-VM
+// override. An override without a URL only changes the overlay options.
+func effectiveRootFS(manifest *pluginspec.Manifest, cfg vmconfig.Config) pluginspec.RootFS {
+	var root pluginspec.RootFS
+	if manifest != nil {
+		root = manifest.RootFS
+	}
+	if cfg.RootFS == nil {
+		return root
+	}
+	if strings.TrimSpace(cfg.RootFS.URL) != "" {
+		return *cfg.RootFS
+	}
+	root.ReadOnly = cfg.RootFS.ReadOnly
+	root.Overlay = cfg.RootFS.Overlay
+	root.OverlaySizeMB = cfg.RootFS.OverlaySizeMB
+	root.KeepClone = cfg.RootFS.KeepClone
+	return root
+}
+
+// effectiveKernel returns the kernel a VM boots and its checksum: the VM's
+// kernel_override path, else the VM config's kernel, else the manifest's.
+// An empty source leaves the choice to the launcher's defaults.
+func effectiveKernel(manifest *pluginspec.Manifest, cfg vmconfig.Config) (string, string) {
+	if path := strings.TrimSpace(cfg.KernelOverride); path != "" {
+		return path, ""
+	}
+	if cfg.Kernel != nil && strings.TrimSpace(cfg.Kernel.URL) != "" {
+		return strings.TrimSpace(cfg.Kernel.URL), strings.TrimSpace(cfg.Kernel.Checksum)
+	}
+	if manifest != nil && manifest.Kernel != nil {
+		return strings.TrimSpace(manifest.Kernel.URL), strings.TrimSpace(manifest.Kernel.Checksum)
+	}
+	return "", ""
+}
+
+// applyRootFSOverlay attaches the root image read-only and tells the agent
+// where the writable upper layer lives. Disk overlays are created on first
+// boot and kept until the VM is destroyed; tmpfs overlays start empty on
+// every boot. Writable qcow2 roots get their upper layer from the image
+// format instead: the VM boots a copy-on-write clone of the shared image.
+func (e *engine) applyRootFSOverlay(ctx context.Context, name string, root pluginspec.RootFS, spec *runtime.LaunchSpec, cmdArgs map[string]string) error {
+	if spec.RootFS != "" && root.Cloned() {
+		spec.RootFSClone = rootFSClonePath(e.runtimeDir, name)
+		return nil
+	}
+	kind := root.OverlayKind()
+	if spec.RootFS == "" || kind == "" {
+		return nil
+	}
+	spec.RootFSReadOnly = true
+	switch kind {
+	case pluginspec.RootFSOverlayTmpfs:
+		cmdArgs[pluginspec.RootFSOverlayKey] = pluginspec.RootFSOverlayTmpfs
+	case pluginspec.RootFSOverlayDisk:
+		sizeMB := root.OverlaySizeMB
+		if sizeMB <= 0 {
+			sizeMB = defaultOverlaySizeMB
+		}
+		path := overlayDiskPath(e.runtimeDir, name)
+		if err := ensureDiskImage(ctx, path, "volant-overlay", sizeMB); err != nil {
+			return fmt.Errorf("orchestrator: prepare rootfs overlay for vm %s: %w", name, err)
+		}
+		spec.OverlayDisk = &runtime.Disk{Name: "overlay", Path: path}
+		// The launcher attaches the root image first and the overlay last.
+		index := 1 + len(spec.Disks)
+		if spec.SeedDisk != nil {
+			index++
+		}
+		cmdArgs[pluginspec.RootFSOverlayKey] = virtioDiskName(index)
+	default:
+		return fmt.Errorf("orchestrator: rootfs overlay %q not supported", root.Overlay)
+	}
+	return nil
+}
+
+// ensureDiskImage creates a sparse ext4 image labelled label at path unless
+// one exists.
+func ensureDiskImage(ctx context.Context, path, label string, sizeMB int) error {
+	if _, err := os.Stat(path); err == nil {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	file, err := os.Create(tmp)
+	if err != nil {
+		return err
+	}
+	if err := file.Truncate(int64(sizeMB) << 20); err != nil {
+		file.Close()
+		_ = os.Remove(tmp)
+		return err
+	}
+	if err := file.Close(); err != nil {
+		_ = os.Remove(tmp)
+		return err
+	}
+	if out, err := exec.CommandContext(ctx, "mkfs.ext4", "-q", "-F", "-L", label, tmp).CombinedOutput(); err != nil {
+		_ = os.Remove(tmp)
+		return fmt.Errorf("mkfs.ext4: %w: %s", err, strings.TrimSpace(string(out)))
+	}
+	return os.Rename(tmp, path)
+}
+
+// overlayDiskPath holds the persistent upper layer of a VM with a
+// disk-backed read-only root.
+func overlayDiskPath(runtimeDir, name string) string {
+	return filepath.Join(runtimeDir, "overlays", name+".img")
+}
+
+// rootFSClonePath holds the copy-on-write clone of a VM with a writable
+// qcow2 root.
+func rootFSClonePath(runtimeDir, name string) string {
+	return filepath.Join(runtimeDir, "rootfs", name+".qcow2")
+}
+
+// virtioDiskName returns the guest device name of the index-th virtio disk.
+func virtioDiskName(index int) string {
+	if index < 26 {
+		return "vd" + string(rune('a'+index))
+	}
+	return "vd" + string(rune('a'+index/26-1)) + string(rune('a'+index%26))
+}
@@ -0,0 +1,77 @@
+// Copyright (c) 2025 HYPR. PTE. LTD.
+//
+// Business Source License 1.1
+// See LICENSE file in the project root for details.
+
+package orchestrator
+
+import (
+	"context"
+	"testing"
+
+	"github.com/volantvm/volant/internal/pluginspec"
+	"github.com/volantvm/volant/internal/server/orchestrator/runtime"
+	"github.com/volantvm/volant/internal/server/orchestrator/vmconfig"
+)
+
+func TestApplyRootFSOverlay(t *testing.T) {
+	manifest := &pluginspec.Manifest{RootFS: pluginspec.RootFS{URL: "/images/base.img"}}
+	cfg := vmconfig.Config{RootFS: &pluginspec.RootFS{ReadOnly: true}}
+
+	root := effectiveRootFS(manifest, cfg)
+	if root.URL != "/images/base.img" || root.OverlayKind() != pluginspec.RootFSOverlayTmpfs {
+		t.Fatalf("unexpected effective rootfs: %+v", root)
+	}
+
+	e := &engine{runtimeDir: t.TempDir()}
+	spec := runtime.LaunchSpec{RootFS: root.URL}
+	args := map[string]string{}
+	if err := e.applyRootFSOverlay(context.Background(), "web", root, &spec, args); err != nil {
+		t.Fatalf("apply overlay: %v", err)
+	}
+	if !spec.RootFSReadOnly || spec.OverlayDisk != nil || args[pluginspec.RootFSOverlayKey] != "tmpfs" {
+		t.Fatalf("unexpected tmpfs overlay: readonly=%t disk=%v args=%v", spec.RootFSReadOnly, spec.OverlayDisk, args)
+	}
+
+	writable := runtime.LaunchSpec{RootFS: root.URL}
+	if err := e.applyRootFSOverlay(context.Background(), "web", manifest.RootFS, &writable, map[string]string{}); err != nil || writable.RootFSReadOnly {
+		t.Fatalf("writable root changed: readonly=%t err=%v", writable.RootFSReadOnly, err)
+	}
+
+	qcow := pluginspec.RootFS{URL: "/images/jammy.qcow2", Format: "qcow2"}
+	cloned := runtime.LaunchSpec{RootFS: qcow.URL}
+	if err := e.applyRootFSOverlay(context.Background(), "web", qcow, &cloned, map[string]string{}); err != nil || cloned.RootFSClone != rootFSClonePath(e.runtimeDir, "web") || cloned.RootFSReadOnly {
+		t.Fatalf("expected writable qcow2 root cloned, got clone=%q readonly=%t err=%v", cloned.RootFSClone, cloned.RootFSReadOnly, err)
+	}
+	qcow.ReadOnly = true
+	shared := runtime.LaunchSpec{RootFS: qcow.URL}
+	if err := e.applyRootFSOverlay(context.Background(), "web", qcow, &shared, map[string]string{}); err != nil || shared.RootFSClone != "" || !shared.RootFSReadOnly {
+		t.Fatalf("expected read-only qcow2 root shared, got clone=%q readonly=%t err=%v", shared.RootFSClone, shared.RootFSReadOnly, err)
+	}
+	if err := (pluginspec.RootFS{URL: "/images/base.img", KeepClone: true}).Validate(); err == nil {
+		t.Fatal("expected keep_clone refused for raw images")
+	}
+
+	if got := virtioDiskName(3); got != "vdd" {
+		t.Fatalf("virtioDiskName(3) = %s", got)
+	}
+}
+
+func TestEffectiveKernel(t *testing.T) {
+	manifest := &pluginspec.Manifest{Kernel: &pluginspec.Kernel{URL: "https://example.com/vmlinux-6.8", Checksum: "sha256:abc"}}
+
+	if src, sum := effectiveKernel(manifest, vmconfig.Config{}); src != "https://example.com/vmlinux-6.8" || sum != "sha256:abc" {
+		t.Fatalf("expected manifest kernel, got %s %s", src, sum)
+	}
+	cfg := vmconfig.Config{Kernel: &pluginspec.Kernel{URL: "/opt/kernels/gpu"}}
+	if src, sum := effectiveKernel(manifest, cfg); src != "/opt/kernels/gpu" || sum != "" {
+		t.Fatalf("expected vm config kernel, got %s %s", src, sum)
+	}
+	cfg.KernelOverride = "/boot/vmlinux"
+	if src, _ := effectiveKernel(manifest, cfg); src != "/boot/vmlinux" {
+		t.Fatalf("expected kernel_override to win, got %s", src)
+	}
+	if src, _ := effectiveKernel(nil, vmconfig.Config{}); src != "" {
+		t.Fatalf("expected daemon default, got %s", src)
+	}
+}
@@ -0,0 +1,126 @@
+// Copyright (c) 2025 HYPR. PTE. LTD.
+//
+// Business Source License 1.1
+// See LICENSE file in the project root for details.
+
+package orchestrator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/volantvm/volant/internal/drift/routes"
+	"github.com/volantvm/volant/internal/server/db"
+	"github.com/volantvm/volant/internal/server/driftclient"
+	"github.com/volantvm/volant/internal/server/orchestrator/vmconfig"
+)
+
+// VMRoute is a drift route published for an expose rule of a running VM.
+type VMRoute struct {
+	VM    string
+	Route routes.Route
+}
+
+// VMRoutes returns the drift routes the expose rules of running VMs call
+// for, whether or not driftd currently holds them.
+func (e *engine) VMRoutes(ctx context.Context) ([]VMRoute, error) {
+	vms, err := e.store.Queries().VirtualMachines().List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var result []VMRoute
+	for _, vm := range vms {
+		if vm.Status != db.VMStatusRunning {
+			continue
+		}
+		computed, err := e.vmDriftRoutes(ctx, vm)
+		if err != nil {
+			e.logger.Warn("compute drift routes", "vm", vm.Name, "error", err)
+			continue
+		}
+		for _, route := range computed {
+			result = append(result, VMRoute{VM: vm.Name, Route: route})
+		}
+	}
+	return result, nil
+}
+
+// SyncRoutes publishes the drift routes of every running VM again, for
+// when driftd lost or was missing them, and returns the routes published.
+func (e *engine) SyncRoutes(ctx context.Context) ([]VMRoute, error) {
+	if e.drift == nil || !e.drift.Enabled() {
+		return nil, driftclient.ErrDisabled
+	}
+	vms, err := e.store.Queries().VirtualMachines().List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var (
+		result []VMRoute
+		errs   []error
+	)
+	for _, vm := range vms {
+		if vm.Status != db.VMStatusRunning {
+			continue
+		}
+		computed, err := e.vmDriftRoutes(ctx, vm)
+		if err == nil {
+			err = e.upsertDriftRoutes(ctx, vm.Name, computed)
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("vm %s: %w", vm.Name, err))
+			continue
+		}
+		for _, route := range computed {
+			result = append(result, VMRoute{VM: vm.Name, Route: route})
+		}
+	}
+	return result, errors.Join(errs...)
+}
+
+// CheckRouteChange returns ErrRouteManaged when the drift route of protocol
+// and hostPort is published for a running VM, whose expose rules own it.
+func (e *engine) CheckRouteChange(ctx context.Context, protocol string, hostPort uint16) error {
+	owned, err := e.VMRoutes(ctx)
+	if err != nil {
+		return err
+	}
+	for _, route := range owned {
+		if route.Route.HostPort == hostPort && strings.EqualFold(route.Route.Protocol, protocol) {
+			return fmt.Errorf("%w: %s/%d is published for vm %s", ErrRouteManaged, route.Route.Protocol, hostPort, route.VM)
+		}
+	}
+	return nil
+}
+
+// vmDriftRoutes computes the drift routes of vm's current configuration.
+func (e *engine) vmDriftRoutes(ctx context.Context, vm db.VM) ([]routes.Route, error) {
+	record, err := e.store.Queries().VMConfigs().GetCurrent(ctx, vm.ID)
+	if err != nil || record == nil {
+		return nil, err
+	}
+	versioned, err := vmconfig.FromDB(*record)
+	if err != nil {
+		return nil, err
+	}
+	cfg := versioned.Config
+	if len(cfg.Expose) == 0 {
+		return nil, nil
+	}
+	return e.computeDriftRoutes(vm, resolveNetworkConfig(cfg.Manifest, &cfg), cfg.Expose)
+}
+
+// upsertDriftRoutes publishes computed routes of a VM, stopping at the first
+// failure. Upserts are idempotent, so routes driftd still holds are
+// rewritten unchanged.
+func (e *engine) upsertDriftRoutes(ctx context.Context, vmName string, computed []routes.Route) error {
+	for _, route := range computed {
+		if _, err := e.drift.UpsertRoute(ctx, route); err != nil {
+			return fmt.Errorf("apply drift route %s/%d: %w", route.Protocol, route.HostPort, err)
+		}
+		e.logger.Debug("published drift route", "vm", vmName, "protocol", route.Protocol, "host_port", route.HostPort)
+	}
+	return nil
+}
@@ -0,0 +1,34 @@
+// Copyright (c) 2025 HYPR. PTE. LTD.
+//
+// Business Source License 1.1
+// See LICENSE file in the project root for details.
+
+package runtime
+
+import (
+	"io"
+	"os"
+)
+
+// CopyDisk copies the disk image at src to dst. On filesystems that support
+// reflinks (btrfs, XFS) the copy shares blocks with src until either side
+// writes them, so it is instant and takes no space; elsewhere the bytes are
+// copied.
+func CopyDisk(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	out, err := os.OpenFile(dst, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o644)
+	if err != nil {
+		return err
+	}
+	if reflink(out, in) != nil {
+		if _, err := io.Copy(out, in); err != nil {
+			out.Close()
+			return err
+		}
+	}
+	return out.Close()
+}
@@ -0,0 +1,89 @@
+// Copyright (c) 2025 HYPR. PTE. LTD.
+//
+// Business Source License 1.1
+// See LICENSE file in the project root for details.
+
+package runtime
+
+import "errors"
+
+// ErrInstanceNotFound indicates no live hypervisor process exists for a VM.
+var ErrInstanceNotFound = errors.New("runtime: instance not found")
+
+// ErrorCode classifies launch failures that operators can fix on the host.
+type ErrorCode string
+
+const (
+	ErrorBinaryMissing    ErrorCode = "HYPERVISOR_BINARY_MISSING"
+	ErrorKVMUnavailable   ErrorCode = "KVM_UNAVAILABLE"
+	ErrorKVMPermission    ErrorCode = "KVM_PERMISSION_DENIED"
+	ErrorTapCreate        ErrorCode = "TAP_CREATE_FAILED"
+	ErrorKernelNotFound   ErrorCode = "KERNEL_NOT_FOUND"
+	ErrorChecksumMismatch ErrorCode = "CHECKSUM_MISMATCH"
+	ErrorVirtiofsdMissing ErrorCode = "VIRTIOFSD_MISSING"
+	ErrorFirmwareNotFound ErrorCode = "FIRMWARE_NOT_FOUND"
+	ErrorQemuImgMissing   ErrorCode = "QEMU_IMG_MISSING"
+)
+
+var remediationHints = map[ErrorCode]string{
+	ErrorBinaryMissing:    "Install cloud-hypervisor (volar setup does this) or point VOLANT_HYPERVISOR at the binary.",
+	ErrorKVMUnavailable:   "Enable hardware virtualization in firmware and load the kvm_intel or kvm_amd module; nested guests need nested virtualization enabled.",
+	ErrorKVMPermission:    "Run volantd as root or add its user to the kvm group so it can open /dev/kvm read-write.",
+	ErrorTapCreate:        "volantd needs CAP_NET_ADMIN and an existing bridge; run volar setup or check VOLANT_BRIDGE.",
+	ErrorKernelNotFound:   "Install the guest kernel at VOLANT_KERNEL_BZIMAGE / VOLANT_KERNEL_VMLINUX or fix the VM's kernel_override path or the plugin's kernel url.",
+	ErrorChecksumMismatch: "The downloaded artifact does not match its declared checksum; re-publish the image or update the checksum in the manifest.",
+	ErrorVirtiofsdMissing: "Install virtiofsd (packaged as virtiofsd or with qemu) so VMs with shares can be served.",
+	ErrorFirmwareNotFound: "Install rust-hypervisor-fw or the Cloud Hypervisor OVMF build (CLOUDHV.fd) at VOLANT_FIRMWARE to boot firmware-mode plugins.",
+	ErrorQemuImgMissing:   "Install qemu-img (packaged as qemu-utils or qemu-img) so qcow2 root images can be cloned per VM.",
+}
+
+// ErrorCodes returns every launch failure code.
+func ErrorCodes() []ErrorCode {
+	return []ErrorCode{
+		ErrorBinaryMissing,
+		ErrorKVMUnavailable,
+		ErrorKVMPermission,
+		ErrorTapCreate,
+		ErrorKernelNotFound,
+		ErrorChecksumMismatch,
+		ErrorVirtiofsdMissing,
+		ErrorFirmwareNotFound,
+		ErrorQemuImgMissing,
+	}
+}
+
+// Hint returns the remediation hint of code.
+func Hint(code ErrorCode) string {
+	return remediationHints[code]
+}
+
+// LaunchError is a classified launch failure carrying a remediation hint for
+// API responses and events.
+type LaunchError struct {
+	Code ErrorCode
+	Hint string
+	Err  error
+}
+
+// NewLaunchError wraps err with code and the code's standard hint.
+func NewLaunchError(code ErrorCode, err error) *LaunchError {
+	return &LaunchError{Code: code, Hint: remediationHints[code], Err: err}
+}
+
+func (e *LaunchError) Error() string {
+	if e.Err == nil {
+		return string(e.Code)
+	}
+	return e.Err.Error()
+}
+
+func (e *LaunchError) Unwrap() error { return e.Err }
+
+// AsLaunchError returns the classified failure wrapped in err, if any.
+func AsLaunchError(err error) (*LaunchError, bool) {
+	var launchErr *LaunchError
+	if errors.As(err, &launchErr) {
+		return launchErr, true
+	}
+	return nil, false
+}
@@ -0,0 +1,17 @@
+// Copyright (c) 2025 HYPR. PTE. LTD.
+//
+// Business Source License 1.1
+// See LICENSE file in the project root for details.
+
+//go:build !linux
+
+package runtime
+
+import (
+	"errors"
+	"os"
+)
+
+func reflink(dst, src *os.File) error {
+	return errors.ErrUnsupported
+}
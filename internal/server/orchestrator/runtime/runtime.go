@@ -5,7 +5,11 @@
 
 package runtime
 
-import "context"
+import (
+	"context"
+	"os"
+	"time"
+)
 
 // LaunchSpec contains the information required to boot a microVM.
 type LaunchSpec struct {
@@ -15,7 +19,13 @@ type LaunchSpec struct {
 	KernelCmdline string
 	// KernelOverride allows per-VM kernel selection; when empty, the launcher chooses
 	// a default based on the presence of Initramfs (vmlinux) or RootFS (bzImage).
+	// It is a local path or an http(s) URL, which is downloaded once per host.
 	KernelOverride string
+	// KernelChecksum, when set, is verified against the override kernel.
+	KernelChecksum string
+	// BootFirmware boots RootFS through the launcher's firmware instead of a
+	// kernel; KernelCmdline, Args and the kernel fields are then unused.
+	BootFirmware   bool
 	TapDevice      string
 	MACAddress     string
 	IPAddress      string
@@ -25,15 +35,56 @@ type LaunchSpec struct {
 	Args           map[string]string
 	RootFS         string
 	RootFSChecksum string
+	// RootFSReadOnly attaches the root image read-only. The launcher then
+	// stages one copy per image and shares it between VMs.
+	RootFSReadOnly bool
+	// OverlayDisk holds the writable upper layer of a read-only root. It is
+	// attached after every other disk.
+	OverlayDisk *Disk
+	// RootFSClone, when set, is the caller-owned copy-on-write clone the VM
+	// boots instead of a private copy of a qcow2 RootFS. The launcher stages
+	// the image once per host as its backing file and creates the clone when
+	// missing; the instance never removes it.
+	RootFSClone string
 	// Initramfs, when set, is fetched and used as the initramfs image for the VM.
 	// If provided, the launcher will prefer a vmlinux kernel (unless KernelOverride is set).
 	Initramfs         string
 	InitramfsChecksum string
 	SerialSocket      string
-	Disks             []Disk
-	SeedDisk          *Disk
+	// StdoutLog and StderrLog, when set, receive the hypervisor's stdout and
+	// stderr instead of the launcher's per-VM log file. They are opened for
+	// appending, so the caller may truncate them as it ingests them.
+	StdoutLog string
+	StderrLog string
+	Disks     []Disk
+	SeedDisk  *Disk
 	// VFIODevicePaths contains /dev/vfio/GROUP_NUMBER paths for GPU/device passthrough
 	VFIODevicePaths []string
+	// Interfaces are additional NICs attached after the primary tap.
+	Interfaces []NetInterface
+	// CPUSet pins every vCPU to these host CPUs, in cpu list format.
+	CPUSet string
+	// NUMANode, when set, allocates guest memory on this host NUMA node.
+	NUMANode *int
+	// HugepageSizeKB backs guest memory with huge pages of this size; zero
+	// uses normal pages.
+	HugepageSizeKB int
+	// Shares are host directories exposed to the guest over virtio-fs.
+	Shares []Share
+}
+
+// NetInterface is an additional tap-backed NIC.
+type NetInterface struct {
+	TapDevice  string
+	MACAddress string
+}
+
+// Share is a host directory served to the guest under Tag by a virtio-fs
+// daemon the launcher runs for the VM.
+type Share struct {
+	Tag      string
+	HostPath string
+	ReadOnly bool
 }
 
 type Disk struct {
@@ -56,3 +107,101 @@ type Instance interface {
 type Launcher interface {
 	Launch(ctx context.Context, spec LaunchSpec) (Instance, error)
 }
+
+// Snapshotter is implemented by instances that can checkpoint their memory,
+// device and disk state into a directory without stopping the guest.
+type Snapshotter interface {
+	Snapshot(ctx context.Context, dir string) error
+}
+
+// ClonedDisks maps the host files of an instance to their copies.
+type ClonedDisks struct {
+	// RootFS is the copy of the staged root filesystem, if the instance has one.
+	RootFS string
+	// Disks maps each writable disk path to its copy.
+	Disks map[string]string
+}
+
+// DiskCloner is implemented by instances that can copy their writable disks
+// while the guest is briefly paused, yielding a crash-consistent image.
+type DiskCloner interface {
+	CloneDisks(ctx context.Context, dir string) (*ClonedDisks, error)
+}
+
+// Artifact kinds reported by ArtifactLister.
+const (
+	ArtifactKernel    = "kernel"
+	ArtifactInitramfs = "initramfs"
+	ArtifactRootFS    = "rootfs"
+)
+
+// Artifact is a boot artifact already present on the host.
+type Artifact struct {
+	Kind string
+	// Name is the artifact's path in the launcher's cache, such as
+	// images/<sha256>.qcow2; peers fetch it by this name. It is empty for
+	// artifacts outside the cache, such as the default kernels.
+	Name       string
+	Path       string
+	SizeBytes  int64
+	ModifiedAt time.Time
+}
+
+// ArtifactLister is implemented by launchers that can report the boot
+// artifacts they have available locally.
+type ArtifactLister interface {
+	Artifacts() ([]Artifact, error)
+}
+
+// ArtifactOpener is implemented by launchers that can hand a cached artifact
+// to other hosts. Unknown or malformed names return an error wrapping
+// os.ErrNotExist.
+type ArtifactOpener interface {
+	OpenArtifact(name string) (*os.File, error)
+}
+
+// ArtifactSource is a boot artifact to fetch before any VM needs it.
+type ArtifactSource struct {
+	Kind     string
+	URL      string
+	Checksum string
+	// Clone marks a root image booted through copy-on-write clones.
+	Clone bool
+}
+
+// ArtifactStager is implemented by launchers that cache boot artifacts and
+// can fill the cache ahead of the first launch that needs them.
+type ArtifactStager interface {
+	StageArtifacts(ctx context.Context, sources []ArtifactSource) error
+}
+
+// Restorer is implemented by launchers that can resume an instance from a
+// directory written by Snapshotter. The spec supplies the host-side resources
+// (tap device, serial socket) the restored guest reattaches to.
+type Restorer interface {
+	Restore(ctx context.Context, spec LaunchSpec, dir string) (Instance, error)
+}
+
+// Attachment is a hypervisor process adopted from a previous volantd run,
+// with the host resources it was launched with.
+type Attachment struct {
+	Instance     Instance
+	TapDevice    string
+	Interfaces   []string
+	SerialSocket string
+	// Devices lists the PCI addresses passed through to the instance.
+	Devices []string
+}
+
+// Attacher is implemented by launchers that record enough state on disk to
+// adopt their instances after volantd restarts.
+type Attacher interface {
+	// Attach adopts the running instance for name. It returns
+	// ErrInstanceNotFound when no live process survives.
+	Attach(ctx context.Context, name string) (*Attachment, error)
+	// Leftovers lists the names that still have runtime state on disk,
+	// whether or not their process is alive.
+	Leftovers() ([]string, error)
+	// Discard removes the runtime state of a dead instance.
+	Discard(name string) error
+}
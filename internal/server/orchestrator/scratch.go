@@ -0,0 +1,74 @@
+// Copyright (c) 2025 HYPR. PTE. LTD.
+//
+// Business Source License 1.1
+// See LICENSE file in the project root for details.
+
+package orchestrator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/volantvm/volant/internal/pluginspec"
+	"github.com/volantvm/volant/internal/server/orchestrator/runtime"
+	"github.com/volantvm/volant/internal/server/orchestrator/vmconfig"
+)
+
+// ScratchUsage totals the scratch disks on the host.
+type ScratchUsage struct {
+	Disks int `json:"disks"`
+	// SizeMB sums the disk sizes, i.e. the space guests may fill.
+	SizeMB int64 `json:"size_mb"`
+}
+
+// applyScratchDisk attaches the VM's scratch disk after its data disks and
+// tells the agent where to mount it. A persistent disk is created on first
+// boot and reused afterwards; otherwise it is recreated empty on every boot.
+// A VM without a scratch disk has any left-over image removed.
+func (e *engine) applyScratchDisk(ctx context.Context, name string, scratch *vmconfig.ScratchDisk, spec *runtime.LaunchSpec, cmdArgs map[string]string) error {
+	path := scratchDiskPath(e.runtimeDir, name)
+	if scratch == nil || !scratch.Persist {
+		if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("orchestrator: remove scratch disk for vm %s: %w", name, err)
+		}
+		if scratch == nil {
+			return nil
+		}
+	} else if info, err := os.Stat(path); err == nil && info.Size() != int64(scratch.SizeMB)<<20 {
+		e.logger.Warn("persistent scratch disk keeps its original size", "vm", name, "size_mb", info.Size()>>20, "requested_mb", scratch.SizeMB)
+	}
+	if err := ensureDiskImage(ctx, path, "volant-scratch", scratch.SizeMB); err != nil {
+		return fmt.Errorf("orchestrator: prepare scratch disk for vm %s: %w", name, err)
+	}
+	// The launcher attaches the root image first, then the data disks.
+	index := len(spec.Disks)
+	if spec.RootFS != "" {
+		index++
+	}
+	spec.Disks = append(spec.Disks, runtime.Disk{Name: "scratch", Path: path})
+	cmdArgs[pluginspec.ScratchDiskKey] = virtioDiskName(index) + "," + scratch.Target()
+	return nil
+}
+
+// ScratchUsage reports the scratch disks present in the runtime directory.
+func (e *engine) ScratchUsage() ScratchUsage {
+	var usage ScratchUsage
+	matches, _ := filepath.Glob(filepath.Join(e.runtimeDir, "scratch", "*.img"))
+	for _, path := range matches {
+		info, err := os.Stat(path)
+		if err != nil {
+			continue
+		}
+		usage.Disks++
+		usage.SizeMB += info.Size() >> 20
+	}
+	return usage
+}
+
+// scratchDiskPath holds the scratch disk of a VM.
+func scratchDiskPath(runtimeDir, name string) string {
+	return filepath.Join(runtimeDir, "scratch", name+".img")
+}
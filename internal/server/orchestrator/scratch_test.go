@@ -0,0 +1,65 @@
+// Copyright (c) 2025 HYPR. PTE. LTD.
+//
+// Business Source License 1.1
+// See LICENSE file in the project root for details.
+
+package orchestrator
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"os"
+	"os/exec"
+	"testing"
+
+	"github.com/volantvm/volant/internal/pluginspec"
+	"github.com/volantvm/volant/internal/server/orchestrator/runtime"
+	"github.com/volantvm/volant/internal/server/orchestrator/vmconfig"
+)
+
+func TestApplyScratchDisk(t *testing.T) {
+	if _, err := exec.LookPath("mkfs.ext4"); err != nil {
+		t.Skip("mkfs.ext4 not available")
+	}
+	ctx := context.Background()
+	e := &engine{runtimeDir: t.TempDir(), logger: slog.New(slog.NewTextHandler(io.Discard, nil))}
+	path := scratchDiskPath(e.runtimeDir, "data")
+
+	spec := runtime.LaunchSpec{RootFS: "rootfs.img", Disks: []runtime.Disk{{Name: "extra", Path: "/srv/extra.img"}}}
+	args := map[string]string{}
+	scratch := &vmconfig.ScratchDisk{SizeMB: 16, Persist: true}
+	if err := e.applyScratchDisk(ctx, "data", scratch, &spec, args); err != nil {
+		t.Fatalf("apply scratch disk: %v", err)
+	}
+	if len(spec.Disks) != 2 || spec.Disks[1].Path != path || args[pluginspec.ScratchDiskKey] != "vdc,/scratch" {
+		t.Fatalf("unexpected scratch attachment %+v %v", spec.Disks, args)
+	}
+	if usage := e.ScratchUsage(); usage.Disks != 1 || usage.SizeMB != 16 {
+		t.Fatalf("unexpected usage %+v", usage)
+	}
+
+	// A persistent disk is reused across boots, keeping its size; others
+	// are recreated.
+	scratch.SizeMB = 32
+	if err := e.applyScratchDisk(ctx, "data", scratch, &runtime.LaunchSpec{}, map[string]string{}); err != nil {
+		t.Fatalf("reapply persistent disk: %v", err)
+	}
+	if info, err := os.Stat(path); err != nil || info.Size() != 16<<20 {
+		t.Fatalf("expected persistent scratch disk reused, got %v %v", info, err)
+	}
+	scratch.Persist = false
+	if err := e.applyScratchDisk(ctx, "data", scratch, &runtime.LaunchSpec{}, map[string]string{}); err != nil {
+		t.Fatalf("reapply ephemeral disk: %v", err)
+	}
+	if info, err := os.Stat(path); err != nil || info.Size() != 32<<20 {
+		t.Fatalf("expected ephemeral scratch disk recreated, got %v %v", info, err)
+	}
+
+	if err := e.applyScratchDisk(ctx, "data", nil, &runtime.LaunchSpec{}, map[string]string{}); err != nil {
+		t.Fatalf("drop scratch disk: %v", err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Fatalf("expected scratch disk removed once unconfigured, got %v", err)
+	}
+}
@@ -0,0 +1,94 @@
+// Copyright (c) 2025 HYPR. PTE. LTD.
+//
+// Business Source License 1.1
+// See LICENSE file in the project root for details.
+
+package orchestrator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/volantvm/volant/internal/pluginspec"
+	"github.com/volantvm/volant/internal/server/orchestrator/vmconfig"
+	"github.com/volantvm/volant/internal/server/secrets"
+)
+
+// resolveSecretMounts returns the secret mounts of a VM: the VM config's
+// mounts when set, otherwise the manifest's.
+func resolveSecretMounts(manifest *pluginspec.Manifest, vmConfig *vmconfig.Config) []pluginspec.SecretMount {
+	if vmConfig != nil && len(vmConfig.Secrets) > 0 {
+		return vmConfig.Secrets
+	}
+	if manifest != nil {
+		return manifest.Secrets
+	}
+	return nil
+}
+
+// secretMountArgs returns the kernel arguments telling the agent to fetch
+// the VM's mounted secrets, or none when it mounts none.
+func secretMountArgs(name string, mounts []pluginspec.SecretMount) map[string]string {
+	if len(mounts) == 0 {
+		return nil
+	}
+	return map[string]string{
+		pluginspec.VMNameKey:       name,
+		pluginspec.SecretMountsKey: strconv.Itoa(len(mounts)),
+	}
+}
+
+// openConfigSecret opens a secret named by a VM's config. Reserved names are
+// refused so one VM's config cannot read the SSH key generated for another.
+func (e *engine) openConfigSecret(ctx context.Context, name string, access secrets.Access) (string, error) {
+	if pluginspec.ReservedSecretName(name) {
+		return "", fmt.Errorf("%w: secret %s is reserved", ErrSecretAccessDenied, name)
+	}
+	return e.secrets.Open(ctx, name, access)
+}
+
+// VMSecrets opens the secrets mounted into a VM for its agent. Only the VM
+// itself, calling from one of its addresses, may read them; every value
+// opened is audited. Secrets deleted since the VM was configured are
+// skipped.
+func (e *engine) VMSecrets(ctx context.Context, name, clientIP string) ([]pluginspec.MountedSecret, error) {
+	vm, err := e.GetVM(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	if vm == nil {
+		return nil, fmt.Errorf("%w: %s", ErrVMNotFound, name)
+	}
+	if clientIP == "" || (clientIP != vm.IPAddress && clientIP != vm.IPv6Address) {
+		return nil, fmt.Errorf("%w: %s may only be read by the vm", ErrSecretAccessDenied, name)
+	}
+	versioned, err := e.GetVMConfig(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	if versioned == nil {
+		return nil, nil
+	}
+	mounts := resolveSecretMounts(versioned.Config.Manifest, &versioned.Config)
+	if len(mounts) == 0 {
+		return nil, nil
+	}
+	if e.secrets == nil {
+		return nil, errors.New("orchestrator: secrets unavailable")
+	}
+	mounted := make([]pluginspec.MountedSecret, 0, len(mounts))
+	for _, mount := range mounts {
+		value, err := e.openConfigSecret(ctx, mount.Name, secrets.Access{VM: name, Via: "agent", ClientIP: clientIP})
+		if err != nil {
+			if errors.Is(err, secrets.ErrSecretNotFound) {
+				e.logger.Warn("vm mounts missing secret", "vm", name, "secret", mount.Name)
+				continue
+			}
+			return nil, err
+		}
+		mounted = append(mounted, pluginspec.MountedSecret{SecretMount: mount, Value: value})
+	}
+	return mounted, nil
+}
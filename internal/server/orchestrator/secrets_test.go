@@ -0,0 +1,108 @@
+// Copyright (c) 2025 HYPR. PTE. LTD.
+//
+// Business Source License 1.1
+// See LICENSE file in the project root for details.
+
+package orchestrator
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+
+	"github.com/volantvm/volant/internal/pluginspec"
+	"github.com/volantvm/volant/internal/server/db"
+	"github.com/volantvm/volant/internal/server/orchestrator/vmconfig"
+	"github.com/volantvm/volant/internal/server/secrets"
+)
+
+func TestVMSecrets(t *testing.T) {
+	ctx := context.Background()
+	store := openTestStore(t)
+	t.Cleanup(func() { _ = store.Close(ctx) })
+	manager, err := secrets.New(store, make([]byte, secrets.KeySize))
+	if err != nil {
+		t.Fatalf("new secrets manager: %v", err)
+	}
+	if _, _, err := manager.Put(ctx, "db-password", "hunter2"); err != nil {
+		t.Fatalf("put secret: %v", err)
+	}
+
+	vmID, err := store.Queries().VirtualMachines().Create(ctx, &db.VM{Name: "web-1", Status: db.VMStatusRunning, Runtime: "nginx", IPAddress: "192.168.127.10", MACAddress: "02:00:00:00:00:0a", CPUCores: 1, MemoryMB: 128})
+	if err != nil {
+		t.Fatalf("create vm: %v", err)
+	}
+	payload, err := vmconfig.Marshal(vmconfig.Config{
+		Plugin:    "nginx",
+		Runtime:   "nginx",
+		Resources: vmconfig.Resources{CPUCores: 1, MemoryMB: 128},
+		Manifest:  &pluginspec.Manifest{Secrets: []pluginspec.SecretMount{{Name: "from-manifest"}}},
+		Secrets: []pluginspec.SecretMount{
+			{Name: "db-password", Path: "/etc/app/db-password", Env: "DB_PASSWORD"},
+			{Name: "deleted"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("marshal config: %v", err)
+	}
+	if _, err := store.Queries().VMConfigs().Upsert(ctx, vmID, payload); err != nil {
+		t.Fatalf("store config: %v", err)
+	}
+
+	e := &engine{store: store, logger: slog.New(slog.DiscardHandler), secrets: manager}
+	if _, err := e.VMSecrets(ctx, "web-1", "192.168.127.99"); !errors.Is(err, ErrSecretAccessDenied) {
+		t.Fatalf("expected another address to be refused, got %v", err)
+	}
+
+	mounted, err := e.VMSecrets(ctx, "web-1", "192.168.127.10")
+	if err != nil {
+		t.Fatalf("vm secrets: %v", err)
+	}
+	if len(mounted) != 1 || mounted[0].Name != "db-password" || mounted[0].Value != "hunter2" || mounted[0].Env != "DB_PASSWORD" {
+		t.Fatalf("expected the config's mount with its value and the deleted secret skipped, got %+v", mounted)
+	}
+
+	for _, name := range []string{"db-password", "deleted"} {
+		events, err := store.Queries().AuditEvents().List(ctx, name, 10)
+		if err != nil {
+			t.Fatalf("list audit events: %v", err)
+		}
+		if len(events) != 1 || events[0].Action != secrets.AuditAccess || events[0].Actor != "vm/web-1" || events[0].ClientIP != "192.168.127.10" {
+			t.Errorf("expected one audited open of %s, got %+v", name, events)
+		}
+	}
+
+	if args := secretMountArgs("web-1", resolveSecretMounts(nil, &vmconfig.Config{})); args != nil {
+		t.Fatalf("expected no kernel args without mounts, got %v", args)
+	}
+	if err := pluginspec.ValidateSecretMounts([]pluginspec.SecretMount{{Name: "a"}, {Name: "b", Path: "/run/volant/secrets/a"}}); err == nil {
+		t.Fatal("expected mounts sharing a path refused")
+	}
+}
+
+func TestReservedSecretsRefused(t *testing.T) {
+	ctx := context.Background()
+	store := openTestStore(t)
+	t.Cleanup(func() { _ = store.Close(ctx) })
+	manager, err := secrets.New(store, make([]byte, secrets.KeySize))
+	if err != nil {
+		t.Fatalf("new secrets manager: %v", err)
+	}
+	if _, _, err := manager.PutReserved(ctx, sshKeySecret("db-1"), "private key"); err != nil {
+		t.Fatalf("put ssh key: %v", err)
+	}
+
+	if err := pluginspec.ValidateSecretMounts([]pluginspec.SecretMount{{Name: sshKeySecret("db-1")}}); err == nil {
+		t.Fatal("expected a mount of a generated ssh key refused")
+	}
+
+	e := &engine{store: store, logger: slog.New(slog.DiscardHandler), secrets: manager}
+	if _, err := e.openConfigSecret(ctx, " "+sshKeySecret("db-1"), secrets.Access{VM: "web-1", Via: "cloud-init"}); !errors.Is(err, ErrSecretAccessDenied) {
+		t.Fatalf("expected templates refused another vm's ssh key, got %v", err)
+	}
+	events, err := store.Queries().AuditEvents().List(ctx, sshKeySecret("db-1"), 10)
+	if err != nil || len(events) != 0 {
+		t.Fatalf("expected no access audited: %+v %v", events, err)
+	}
+}
@@ -0,0 +1,41 @@
+// Copyright (c) 2025 HYPR. PTE. LTD.
+//
+// Business Source License 1.1
+// See LICENSE file in the project root for details.
+
+package orchestrator
+
+import (
+	"fmt"
+
+	"github.com/volantvm/volant/internal/pluginspec"
+	"github.com/volantvm/volant/internal/server/orchestrator/runtime"
+	"github.com/volantvm/volant/internal/server/orchestrator/vmconfig"
+)
+
+// resolveShares returns the virtio-fs shares of a VM: the VM config's shares
+// when set, otherwise the manifest's.
+func resolveShares(manifest *pluginspec.Manifest, vmConfig *vmconfig.Config) []pluginspec.Share {
+	if vmConfig != nil && len(vmConfig.Shares) > 0 {
+		return vmConfig.Shares
+	}
+	if manifest != nil {
+		return manifest.Shares
+	}
+	return nil
+}
+
+// launchShares converts shares into launch devices and returns the kernel
+// arguments the agent uses to mount them. Shares are numbered from 1.
+func launchShares(shares []pluginspec.Share) ([]runtime.Share, map[string]string) {
+	if len(shares) == 0 {
+		return nil, nil
+	}
+	devices := make([]runtime.Share, 0, len(shares))
+	args := make(map[string]string, len(shares))
+	for i, share := range shares {
+		devices = append(devices, runtime.Share{Tag: share.Tag, HostPath: share.HostPath, ReadOnly: share.ReadOnly})
+		args[fmt.Sprintf("%s%d", pluginspec.ShareKeyPrefix, i+1)] = pluginspec.ShareArg(share)
+	}
+	return devices, args
+}
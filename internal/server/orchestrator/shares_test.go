@@ -0,0 +1,56 @@
+// Copyright (c) 2025 HYPR. PTE. LTD.
+//
+// Business Source License 1.1
+// See LICENSE file in the project root for details.
+
+package orchestrator
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/volantvm/volant/internal/pluginspec"
+	"github.com/volantvm/volant/internal/server/orchestrator/runtime"
+	"github.com/volantvm/volant/internal/server/orchestrator/vmconfig"
+)
+
+func TestLaunchShares(t *testing.T) {
+	manifest := &pluginspec.Manifest{Shares: []pluginspec.Share{
+		{Tag: "models", HostPath: "/srv/models", ReadOnly: true},
+		{Tag: "scratch", HostPath: "/srv/scratch", MountPath: "/data"},
+	}}
+
+	shares, args := launchShares(resolveShares(manifest, &vmconfig.Config{}))
+	wantShares := []runtime.Share{
+		{Tag: "models", HostPath: "/srv/models", ReadOnly: true},
+		{Tag: "scratch", HostPath: "/srv/scratch"},
+	}
+	if !reflect.DeepEqual(shares, wantShares) {
+		t.Fatalf("unexpected launch shares %+v", shares)
+	}
+	wantArgs := map[string]string{
+		"volant.share1": "models,/mnt/models,ro",
+		"volant.share2": "scratch,/data",
+	}
+	if !reflect.DeepEqual(args, wantArgs) {
+		t.Fatalf("unexpected share args %v", args)
+	}
+	for key, value := range args {
+		tag, target, _, err := pluginspec.ParseShareArg(value)
+		if err != nil || tag == "" || target == "" {
+			t.Fatalf("%s=%s does not round-trip: %v", key, value, err)
+		}
+	}
+
+	override := &vmconfig.Config{Shares: []pluginspec.Share{{Tag: "vm", HostPath: "/srv/vm"}}}
+	if got := resolveShares(manifest, override); len(got) != 1 || got[0].Tag != "vm" {
+		t.Fatalf("expected vm config shares to replace the manifest's, got %+v", got)
+	}
+
+	if err := pluginspec.ValidateShares([]pluginspec.Share{{Tag: "a", HostPath: "/x"}, {Tag: "a", HostPath: "/y"}}); err == nil {
+		t.Fatal("expected duplicate tags refused")
+	}
+	if err := pluginspec.ValidateShares([]pluginspec.Share{{Tag: "a", HostPath: "relative"}}); err == nil {
+		t.Fatal("expected relative host path refused")
+	}
+}
@@ -0,0 +1,61 @@
+// Copyright (c) 2025 HYPR. PTE. LTD.
+//
+// Business Source License 1.1
+// See LICENSE file in the project root for details.
+
+package orchestrator
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/volantvm/volant/internal/server/orchestrator/runtime"
+)
+
+const snapshotTimestampLayout = "20060102T150405Z"
+
+// Snapshot describes a point-in-time hypervisor snapshot stored on the host.
+type Snapshot struct {
+	VM        string
+	Path      string
+	CreatedAt time.Time
+}
+
+// SnapshotVM checkpoints a running VM under the runtime directory without
+// stopping it.
+func (e *engine) SnapshotVM(ctx context.Context, name string) (*Snapshot, error) {
+	vm, err := e.GetVM(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	if vm == nil {
+		return nil, fmt.Errorf("%w: %s", ErrVMNotFound, name)
+	}
+
+	e.mu.Lock()
+	handle, ok := e.instances[name]
+	e.mu.Unlock()
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrVMNotRunning, name)
+	}
+	snapshotter, ok := handle.instance.(runtime.Snapshotter)
+	if !ok {
+		return nil, fmt.Errorf("orchestrator: runtime for vm %s does not support snapshots", name)
+	}
+
+	createdAt := time.Now().UTC()
+	dir := filepath.Join(e.runtimeDir, "snapshots", name, createdAt.Format(snapshotTimestampLayout))
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("orchestrator: create snapshot dir: %w", err)
+	}
+	if err := snapshotter.Snapshot(ctx, dir); err != nil {
+		_ = os.RemoveAll(dir)
+		return nil, fmt.Errorf("orchestrator: snapshot vm %s: %w", name, err)
+	}
+
+	e.logger.Info("vm snapshot created", "vm", name, "path", dir)
+	return &Snapshot{VM: name, Path: dir, CreatedAt: createdAt}, nil
+}
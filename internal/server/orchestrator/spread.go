@@ -0,0 +1,142 @@
+// Copyright (c) 2025 HYPR. PTE. LTD.
+//
+// Business Source License 1.1
+// See LICENSE file in the project root for details.
+
+package orchestrator
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/volantvm/volant/internal/pluginspec"
+	"github.com/volantvm/volant/internal/server/db"
+	"github.com/volantvm/volant/internal/server/orchestrator/vmconfig"
+)
+
+// checkSpread fails when a deployment spreads over NUMA nodes the host does
+// not have, networks that do not exist, or networks while its replicas do
+// not use bridged networking.
+func (e *engine) checkSpread(ctx context.Context, cfg vmconfig.Config) error {
+	spread := cfg.Spread
+	if spread == nil {
+		return nil
+	}
+	for _, id := range spread.NUMANodes {
+		if _, ok := e.topology.Node(id); !ok {
+			return fmt.Errorf("%w: spread numa node %d does not exist", ErrPlacementUnsatisfiable, id)
+		}
+	}
+	if len(spread.Networks) == 0 {
+		return nil
+	}
+	if netCfg := resolveNetworkConfig(cfg.Manifest, &cfg); netCfg != nil {
+		if mode := pluginspec.NetworkMode(strings.ToLower(strings.TrimSpace(string(netCfg.Mode)))); mode != pluginspec.NetworkModeBridged {
+			return fmt.Errorf("orchestrator: spread networks require bridged networking, not %s", mode)
+		}
+	}
+	for _, name := range spread.Networks {
+		netw, err := e.store.Queries().Networks().GetByName(ctx, name)
+		if err != nil {
+			return err
+		}
+		if netw == nil {
+			return fmt.Errorf("%w: %s", ErrNetworkNotFound, name)
+		}
+	}
+	return nil
+}
+
+// spreadPlacement counts the replicas of a deployment on each spread target
+// so new replicas can go to the least used.
+type spreadPlacement struct {
+	spread   vmconfig.Spread
+	nodes    map[int]int
+	networks map[string]int
+}
+
+// newSpreadPlacement counts the spread targets used by the current configs
+// of vms.
+func (e *engine) newSpreadPlacement(ctx context.Context, spread vmconfig.Spread, vms []db.VM) (*spreadPlacement, error) {
+	placement := &spreadPlacement{
+		spread:   spread,
+		nodes:    make(map[int]int, len(spread.NUMANodes)),
+		networks: make(map[string]int, len(spread.Networks)),
+	}
+	repo := e.store.Queries().VMConfigs()
+	for _, vm := range vms {
+		record, err := repo.GetCurrent(ctx, vm.ID)
+		if err != nil {
+			return nil, err
+		}
+		if record == nil {
+			continue
+		}
+		versioned, err := vmconfig.FromDB(*record)
+		if err != nil {
+			return nil, err
+		}
+		cfg := versioned.Config
+		if cfg.Resources.NUMANode != nil {
+			placement.nodes[*cfg.Resources.NUMANode]++
+		}
+		if cfg.Network != nil && cfg.Network.Name != "" {
+			placement.networks[cfg.Network.Name]++
+		}
+	}
+	return placement, nil
+}
+
+// place assigns cfg, a replica config without a spread, to the least used
+// NUMA node and network and counts it there.
+func (p *spreadPlacement) place(cfg *vmconfig.Config) {
+	if len(p.spread.NUMANodes) > 0 {
+		node := p.spread.NUMANodes[0]
+		for _, candidate := range p.spread.NUMANodes[1:] {
+			if p.nodes[candidate] < p.nodes[node] {
+				node = candidate
+			}
+		}
+		p.nodes[node]++
+		cfg.Resources.NUMANode = &node
+	}
+	if len(p.spread.Networks) > 0 {
+		name := p.spread.Networks[0]
+		for _, candidate := range p.spread.Networks[1:] {
+			if p.networks[candidate] < p.networks[name] {
+				name = candidate
+			}
+		}
+		p.networks[name]++
+		netCfg := pluginspec.NetworkConfig{Mode: pluginspec.NetworkModeBridged}
+		if base := resolveNetworkConfig(cfg.Manifest, cfg); base != nil {
+			netCfg = *base
+			netCfg.Interfaces = append([]pluginspec.NetworkInterface(nil), base.Interfaces...)
+		}
+		netCfg.Name = name
+		netCfg.Subnet, netCfg.Gateway, netCfg.AutoAssign = "", "", false
+		cfg.Network = &netCfg
+	}
+}
+
+// replaceCrashedReplica deletes a crashed replica of a deployment with a
+// spread policy; the reconcile that follows creates its replacement on the
+// least used spread target.
+func (e *engine) replaceCrashedReplica(ctx context.Context, vm db.VM) {
+	if vm.GroupID == nil {
+		return
+	}
+	group, err := e.store.Queries().VMGroups().GetByID(ctx, *vm.GroupID)
+	if err != nil || group == nil {
+		return
+	}
+	config, err := vmconfig.Unmarshal(group.ConfigJSON)
+	if err != nil || config.Spread == nil {
+		return
+	}
+	e.logger.Info("replacing crashed replica", "deployment", group.Name, "vm", vm.Name)
+	if _, err := e.destroyVM(ctx, vm.Name, true); err != nil {
+		e.logger.Error("replace crashed replica", "deployment", group.Name, "vm", vm.Name, "error", err)
+	}
+}
@@ -0,0 +1,101 @@
+// Copyright (c) 2025 HYPR. PTE. LTD.
+//
+// Business Source License 1.1
+// See LICENSE file in the project root for details.
+
+package orchestrator
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/volantvm/volant/internal/pluginspec"
+	"github.com/volantvm/volant/internal/server/orchestrator/topology"
+	"github.com/volantvm/volant/internal/server/orchestrator/vmconfig"
+)
+
+func TestDeploymentSpreadAcrossNUMANodes(t *testing.T) {
+	ctx := context.Background()
+	store := openTestStore(t)
+	defer func() { _ = store.Close(ctx) }()
+
+	subnet, host := testSubnet(t)
+	probe := &testReadinessProbe{}
+	probe.ready.Store(true)
+	launcher := &testLauncher{}
+	engine, err := New(Params{
+		Store:            store,
+		Logger:           slog.New(slog.NewTextHandler(io.Discard, nil)),
+		Subnet:           subnet,
+		HostIP:           host,
+		APIListenAddr:    "127.0.0.1:7777",
+		APIAdvertiseAddr: "127.0.0.1:7777",
+		RuntimeDir:       t.TempDir(),
+		Launcher:         launcher,
+		Network:          &testNetworkManager{},
+		Readiness:        probe,
+		Topology: &topology.Topology{
+			CPUs:  topology.CPUSet{0, 1, 2, 3},
+			Nodes: []topology.Node{{ID: 0, CPUs: topology.CPUSet{0, 1}}, {ID: 1, CPUs: topology.CPUSet{2, 3}}},
+		},
+	})
+	if err != nil {
+		t.Fatalf("new engine: %v", err)
+	}
+	if err := engine.Start(ctx); err != nil {
+		t.Fatalf("engine start: %v", err)
+	}
+	defer func() { _ = engine.Stop(ctx) }()
+
+	config := vmconfig.Config{
+		Plugin:    "worker",
+		Runtime:   "worker",
+		Resources: vmconfig.Resources{CPUCores: 1, MemoryMB: 512},
+		Manifest:  &pluginspec.Manifest{Name: "worker", Version: "1.0.0", Runtime: "worker"},
+		Spread:    &vmconfig.Spread{NUMANodes: []int{2}},
+	}
+	if _, err := engine.CreateDeployment(ctx, CreateDeploymentRequest{Name: "pool", Replicas: 1, Config: config}); !errors.Is(err, ErrPlacementUnsatisfiable) {
+		t.Fatalf("expected unknown spread node refused, got %v", err)
+	}
+	config.Spread = &vmconfig.Spread{NUMANodes: []int{0, 1}}
+	if _, err := engine.CreateDeployment(ctx, CreateDeploymentRequest{Name: "pool", Replicas: 3, Config: config}); err != nil {
+		t.Fatalf("create deployment: %v", err)
+	}
+
+	replicaNode := func(name string) int {
+		t.Helper()
+		versioned, err := engine.GetVMConfig(ctx, name)
+		if err != nil || versioned == nil {
+			t.Fatalf("get %s config: %v", name, err)
+		}
+		if versioned.Config.Spread != nil {
+			t.Fatalf("%s config kept the deployment spread", name)
+		}
+		if versioned.Config.Resources.NUMANode == nil {
+			t.Fatalf("%s has no numa node", name)
+		}
+		return *versioned.Config.Resources.NUMANode
+	}
+	for name, want := range map[string]int{"pool-1": 0, "pool-2": 1, "pool-3": 0} {
+		if got := replicaNode(name); got != want {
+			t.Fatalf("%s on numa node %d, want %d", name, got, want)
+		}
+	}
+
+	launches := launcher.launches()
+	launcher.crash("pool-2")
+	deadline := time.Now().Add(5 * time.Second)
+	for launcher.launches() == launches {
+		if time.Now().After(deadline) {
+			t.Fatalf("crashed replica was not replaced")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if got := replicaNode("pool-2"); got != 1 {
+		t.Fatalf("replacement on numa node %d, want 1", got)
+	}
+}
@@ -0,0 +1,154 @@
+// Copyright (c) 2025 HYPR. PTE. LTD.
+//
+// Business Source License 1.1
+// See LICENSE file in the project root for details.
+
+package orchestrator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+
+	"github.com/volantvm/volant/internal/pluginspec"
+	"github.com/volantvm/volant/internal/server/db"
+	"github.com/volantvm/volant/internal/server/orchestrator/vmconfig"
+	"github.com/volantvm/volant/internal/server/secrets"
+	"github.com/volantvm/volant/internal/server/sshkey"
+)
+
+// SSHPort is the guest port SSH sessions connect to.
+const SSHPort = 22
+
+// SSHAccess describes how to open an SSH session to a VM.
+type SSHAccess struct {
+	User           string   `json:"user"`
+	Host           string   `json:"host,omitempty"`
+	Port           int      `json:"port"`
+	AuthorizedKeys []string `json:"authorized_keys,omitempty"`
+	// GeneratedKey reports whether volantd holds a private key for the VM,
+	// served by VMSSHKey.
+	GeneratedKey bool `json:"generated_key"`
+}
+
+// sshKeySecret names the secret holding the private key generated for a VM.
+func sshKeySecret(name string) string {
+	return pluginspec.ReservedSecretPrefix + name
+}
+
+func sshKeyComment(name string) string {
+	return "volant-" + name
+}
+
+// sshAuthorizedKeys returns the keys injected into a VM's seed: the
+// configured authorized keys followed, when GenerateKey is set, by the
+// public half of the VM's generated key, created on first use.
+func (e *engine) sshAuthorizedKeys(ctx context.Context, vm *db.VM, cfg *vmconfig.SSH) ([]string, error) {
+	if cfg == nil {
+		return nil, nil
+	}
+	keys := append([]string(nil), cfg.AuthorizedKeys...)
+	if !cfg.GenerateKey {
+		return keys, nil
+	}
+	if e.secrets == nil {
+		return nil, errors.New("orchestrator: ssh generate_key requires the secrets store")
+	}
+	secretName := sshKeySecret(vm.Name)
+	privateKey, err := e.secrets.Open(ctx, secretName, secrets.Access{VM: vm.Name, Via: "ssh-seed"})
+	switch {
+	case errors.Is(err, secrets.ErrSecretNotFound):
+		var public string
+		privateKey, public, err = sshkey.Generate(sshKeyComment(vm.Name))
+		if err != nil {
+			return nil, err
+		}
+		if _, _, err := e.secrets.PutReserved(ctx, secretName, privateKey); err != nil {
+			return nil, fmt.Errorf("orchestrator: store ssh key: %w", err)
+		}
+		e.logger.Info("generated ssh key", "vm", vm.Name, "secret", secretName)
+		return append(keys, public), nil
+	case err != nil:
+		return nil, err
+	}
+	public, err := sshkey.AuthorizedKey(privateKey, sshKeyComment(vm.Name))
+	if err != nil {
+		return nil, fmt.Errorf("orchestrator: ssh key for %s: %w", vm.Name, err)
+	}
+	return append(keys, public), nil
+}
+
+// deleteSSHKey removes the private key generated for a VM, if any.
+func (e *engine) deleteSSHKey(ctx context.Context, name string) {
+	if e.secrets == nil {
+		return
+	}
+	if err := e.secrets.DeleteReserved(ctx, sshKeySecret(name)); err != nil && !errors.Is(err, secrets.ErrSecretNotFound) {
+		e.logger.Warn("delete ssh key", "vm", name, "error", err)
+	}
+}
+
+// VMSSH describes how to reach a VM over SSH. VMs without SSH settings
+// report the default user and no keys; their image may still accept logins
+// configured by hand.
+func (e *engine) VMSSH(ctx context.Context, name string) (*SSHAccess, error) {
+	versioned, err := e.GetVMConfig(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	vm, err := e.GetVM(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	if vm == nil {
+		return nil, fmt.Errorf("%w: %s", ErrVMNotFound, name)
+	}
+	cfg := versioned.Config.SSH
+	access := &SSHAccess{
+		User: cfg.LoginUser(),
+		Host: vm.IPAddress,
+		Port: SSHPort,
+	}
+	if cfg != nil {
+		access.AuthorizedKeys = append([]string(nil), cfg.AuthorizedKeys...)
+		access.GeneratedKey = cfg.GenerateKey
+	}
+	return access, nil
+}
+
+// VMSSHKey opens the private key generated for a VM. Every read is audited
+// against clientIP.
+func (e *engine) VMSSHKey(ctx context.Context, name, clientIP string) (string, error) {
+	access, err := e.VMSSH(ctx, name)
+	if err != nil {
+		return "", err
+	}
+	if !access.GeneratedKey || e.secrets == nil {
+		return "", fmt.Errorf("%w: %s", ErrSSHKeyNotFound, name)
+	}
+	privateKey, err := e.secrets.Open(ctx, sshKeySecret(name), secrets.Access{VM: name, Via: "ssh", ClientIP: clientIP})
+	if errors.Is(err, secrets.ErrSecretNotFound) {
+		// The key is generated when the seed is first built.
+		return "", fmt.Errorf("%w: %s", ErrSSHKeyNotFound, name)
+	}
+	return privateKey, err
+}
+
+// DialSSH connects to the SSH port of a running VM from the host, for
+// clients that cannot reach the VM network directly.
+func (e *engine) DialSSH(ctx context.Context, name string) (net.Conn, error) {
+	vm, err := e.GetVM(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	if vm == nil {
+		return nil, fmt.Errorf("%w: %s", ErrVMNotFound, name)
+	}
+	if vm.Status != db.VMStatusRunning || vm.IPAddress == "" {
+		return nil, fmt.Errorf("%w: %s", ErrVMNotRunning, name)
+	}
+	var dialer net.Dialer
+	return dialer.DialContext(ctx, "tcp", net.JoinHostPort(vm.IPAddress, strconv.Itoa(SSHPort)))
+}
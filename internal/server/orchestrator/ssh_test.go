@@ -0,0 +1,130 @@
+// Copyright (c) 2025 HYPR. PTE. LTD.
+//
+// Business Source License 1.1
+// See LICENSE file in the project root for details.
+
+package orchestrator
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/volantvm/volant/internal/pluginspec"
+	"github.com/volantvm/volant/internal/server/db"
+	"github.com/volantvm/volant/internal/server/orchestrator/vmconfig"
+	"github.com/volantvm/volant/internal/server/sshkey"
+)
+
+func TestSSHAuthorizedKeys(t *testing.T) {
+	ctx := context.Background()
+	_, userKey, err := sshkey.Generate("alice@laptop")
+	if err != nil {
+		t.Fatalf("generate: %v", err)
+	}
+	store := fakeSecrets{}
+	e := &engine{logger: slog.New(slog.DiscardHandler), secrets: store}
+	vm := &db.VM{Name: "web-1"}
+
+	keys, err := e.sshAuthorizedKeys(ctx, vm, &vmconfig.SSH{AuthorizedKeys: []string{userKey}, GenerateKey: true})
+	if err != nil {
+		t.Fatalf("authorized keys: %v", err)
+	}
+	if len(keys) != 2 || keys[0] != userKey || !strings.HasSuffix(keys[1], " volant-web-1") {
+		t.Fatalf("expected the configured key then the generated one, got %v", keys)
+	}
+	if _, ok := store["ssh.web-1"]; !ok {
+		t.Fatal("expected the generated private key stored as ssh.web-1")
+	}
+
+	again, err := e.sshAuthorizedKeys(ctx, vm, &vmconfig.SSH{GenerateKey: true})
+	if err != nil {
+		t.Fatalf("authorized keys: %v", err)
+	}
+	if len(again) != 1 || again[0] != keys[1] {
+		t.Fatalf("expected the stored key reused, got %v", again)
+	}
+
+	e.deleteSSHKey(ctx, "web-1")
+	if _, ok := store["ssh.web-1"]; ok {
+		t.Fatal("expected the generated key deleted")
+	}
+
+	if keys, err := e.sshAuthorizedKeys(ctx, vm, nil); err != nil || keys != nil {
+		t.Fatalf("expected no keys without ssh settings, got %v, %v", keys, err)
+	}
+	e.secrets = nil
+	if _, err := e.sshAuthorizedKeys(ctx, vm, &vmconfig.SSH{GenerateKey: true}); err == nil {
+		t.Fatal("expected key generation refused without a secrets store")
+	}
+}
+
+func TestVMSSHKey(t *testing.T) {
+	ctx := context.Background()
+	store := openTestStore(t)
+	t.Cleanup(func() { _ = store.Close(ctx) })
+
+	secretStore := fakeSecrets{}
+	e := &engine{store: store, logger: slog.New(slog.DiscardHandler), secrets: secretStore}
+	for _, vm := range []struct {
+		name string
+		ip   string
+		mac  string
+		ssh  *vmconfig.SSH
+	}{
+		{name: "web-1", ip: "192.168.127.10", mac: "02:00:00:00:00:0a", ssh: &vmconfig.SSH{User: "ubuntu", GenerateKey: true}},
+		{name: "web-2", ip: "192.168.127.11", mac: "02:00:00:00:00:0b"},
+	} {
+		vmID, err := store.Queries().VirtualMachines().Create(ctx, &db.VM{Name: vm.name, Status: db.VMStatusStopped, Runtime: "nginx", IPAddress: vm.ip, MACAddress: vm.mac, CPUCores: 1, MemoryMB: 128})
+		if err != nil {
+			t.Fatalf("create vm: %v", err)
+		}
+		payload, err := vmconfig.Marshal(vmconfig.Config{
+			Plugin:    "nginx",
+			Runtime:   "nginx",
+			Resources: vmconfig.Resources{CPUCores: 1, MemoryMB: 128},
+			Manifest:  &pluginspec.Manifest{},
+			SSH:       vm.ssh,
+		})
+		if err != nil {
+			t.Fatalf("marshal config: %v", err)
+		}
+		if _, err := store.Queries().VMConfigs().Upsert(ctx, vmID, payload); err != nil {
+			t.Fatalf("store config: %v", err)
+		}
+	}
+
+	if _, err := e.VMSSHKey(ctx, "web-1", "10.0.0.5"); !errors.Is(err, ErrSSHKeyNotFound) {
+		t.Fatalf("expected no key before the seed is built, got %v", err)
+	}
+	if _, err := e.sshAuthorizedKeys(ctx, &db.VM{Name: "web-1"}, &vmconfig.SSH{GenerateKey: true}); err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+	key, err := e.VMSSHKey(ctx, "web-1", "10.0.0.5")
+	if err != nil || key != secretStore["ssh.web-1"] {
+		t.Fatalf("expected the generated key, got %v", err)
+	}
+
+	access, err := e.VMSSH(ctx, "web-1")
+	if err != nil {
+		t.Fatalf("vm ssh: %v", err)
+	}
+	if access.User != "ubuntu" || access.Host != "192.168.127.10" || access.Port != SSHPort || !access.GeneratedKey {
+		t.Fatalf("unexpected access %+v", access)
+	}
+	access, err = e.VMSSH(ctx, "web-2")
+	if err != nil {
+		t.Fatalf("vm ssh: %v", err)
+	}
+	if access.User != vmconfig.DefaultSSHUser || access.GeneratedKey {
+		t.Fatalf("expected defaults without ssh settings, got %+v", access)
+	}
+	if _, err := e.VMSSHKey(ctx, "web-2", "10.0.0.5"); !errors.Is(err, ErrSSHKeyNotFound) {
+		t.Fatalf("expected no key for web-2, got %v", err)
+	}
+	if _, err := e.DialSSH(ctx, "web-1"); !errors.Is(err, ErrVMNotRunning) {
+		t.Fatalf("expected a stopped vm refused, got %v", err)
+	}
+}
@@ -0,0 +1,197 @@
+// Copyright (c) 2025 HYPR. PTE. LTD.
+//
+// Business Source License 1.1
+// See LICENSE file in the project root for details.
+
+// Package topology reads the host CPU, NUMA and huge page layout from sysfs
+// so VM placement requests can be checked before a launch.
+package topology
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// DefaultSysfs is where the kernel exposes the topology.
+const DefaultSysfs = "/sys"
+
+// CPUSet is a sorted list of distinct host CPU numbers.
+type CPUSet []int
+
+// ParseCPUSet parses the kernel cpu list format, e.g. "0-3,8,10-11".
+func ParseCPUSet(list string) (CPUSet, error) {
+	list = strings.TrimSpace(list)
+	if list == "" {
+		return nil, nil
+	}
+	seen := make(map[int]struct{})
+	for _, part := range strings.Split(list, ",") {
+		part = strings.TrimSpace(part)
+		lo, hi, isRange := strings.Cut(part, "-")
+		start, err := strconv.Atoi(strings.TrimSpace(lo))
+		if err != nil || start < 0 {
+			return nil, fmt.Errorf("topology: invalid cpu %q in %q", part, list)
+		}
+		end := start
+		if isRange {
+			end, err = strconv.Atoi(strings.TrimSpace(hi))
+			if err != nil || end < start {
+				return nil, fmt.Errorf("topology: invalid cpu range %q in %q", part, list)
+			}
+		}
+		for cpu := start; cpu <= end; cpu++ {
+			seen[cpu] = struct{}{}
+		}
+	}
+	set := make(CPUSet, 0, len(seen))
+	for cpu := range seen {
+		set = append(set, cpu)
+	}
+	sort.Ints(set)
+	return set, nil
+}
+
+// String formats the set in the kernel cpu list format.
+func (s CPUSet) String() string {
+	var parts []string
+	for i := 0; i < len(s); {
+		j := i
+		for j+1 < len(s) && s[j+1] == s[j]+1 {
+			j++
+		}
+		if i == j {
+			parts = append(parts, strconv.Itoa(s[i]))
+		} else {
+			parts = append(parts, fmt.Sprintf("%d-%d", s[i], s[j]))
+		}
+		i = j + 1
+	}
+	return strings.Join(parts, ",")
+}
+
+// Without returns the CPUs of s that are not in other.
+func (s CPUSet) Without(other CPUSet) CPUSet {
+	exclude := make(map[int]struct{}, len(other))
+	for _, cpu := range other {
+		exclude[cpu] = struct{}{}
+	}
+	var rest CPUSet
+	for _, cpu := range s {
+		if _, ok := exclude[cpu]; !ok {
+			rest = append(rest, cpu)
+		}
+	}
+	return rest
+}
+
+// Node is one NUMA node.
+type Node struct {
+	ID   int
+	CPUs CPUSet
+	// FreeHugepages maps a page size in KiB to the free pages on this node.
+	FreeHugepages map[int]int
+}
+
+// Topology is the host layout relevant to placement.
+type Topology struct {
+	// CPUs lists the online CPUs.
+	CPUs  CPUSet
+	Nodes []Node
+	// FreeHugepages maps a page size in KiB to the free pages host-wide.
+	FreeHugepages map[int]int
+}
+
+// Node returns the NUMA node with the given id.
+func (t *Topology) Node(id int) (Node, bool) {
+	if t == nil {
+		return Node{}, false
+	}
+	for _, node := range t.Nodes {
+		if node.ID == id {
+			return node, true
+		}
+	}
+	return Node{}, false
+}
+
+// Read loads the topology below sysfs, normally DefaultSysfs. Hosts without
+// NUMA support report a single node 0 holding every online CPU.
+func Read(sysfs string) (*Topology, error) {
+	online, err := readCPUList(filepath.Join(sysfs, "devices", "system", "cpu", "online"))
+	if err != nil {
+		return nil, err
+	}
+	topo := &Topology{
+		CPUs:          online,
+		FreeHugepages: readHugepages(filepath.Join(sysfs, "kernel", "mm", "hugepages")),
+	}
+
+	dirs, _ := filepath.Glob(filepath.Join(sysfs, "devices", "system", "node", "node[0-9]*"))
+	for _, dir := range dirs {
+		id, err := strconv.Atoi(strings.TrimPrefix(filepath.Base(dir), "node"))
+		if err != nil {
+			continue
+		}
+		cpus, err := readCPUList(filepath.Join(dir, "cpulist"))
+		if err != nil {
+			return nil, err
+		}
+		topo.Nodes = append(topo.Nodes, Node{ID: id, CPUs: cpus, FreeHugepages: readHugepages(filepath.Join(dir, "hugepages"))})
+	}
+	if len(topo.Nodes) == 0 {
+		topo.Nodes = []Node{{ID: 0, CPUs: online, FreeHugepages: topo.FreeHugepages}}
+	}
+	sort.Slice(topo.Nodes, func(i, j int) bool { return topo.Nodes[i].ID < topo.Nodes[j].ID })
+	return topo, nil
+}
+
+func readCPUList(path string) (CPUSet, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("topology: %w", err)
+	}
+	return ParseCPUSet(string(data))
+}
+
+// readHugepages reads the free page count of every hugepages-<size>kB pool
+// in dir. A missing directory means no huge page support.
+func readHugepages(dir string) map[int]int {
+	pools, _ := filepath.Glob(filepath.Join(dir, "hugepages-*kB"))
+	free := make(map[int]int, len(pools))
+	for _, pool := range pools {
+		size, err := strconv.Atoi(strings.TrimSuffix(strings.TrimPrefix(filepath.Base(pool), "hugepages-"), "kB"))
+		if err != nil {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(pool, "free_hugepages"))
+		if err != nil {
+			continue
+		}
+		count, err := strconv.Atoi(strings.TrimSpace(string(data)))
+		if err != nil {
+			continue
+		}
+		free[size] = count
+	}
+	return free
+}
+
+// ErrUnknownPageSize indicates a huge page size other than 2M or 1G.
+var ErrUnknownPageSize = errors.New("topology: hugepage size must be 2M or 1G")
+
+// PageSizeKB converts a huge page size as written in VM configs ("2M",
+// "1G") to KiB. An empty size selects 2M, the x86 default.
+func PageSizeKB(size string) (int, error) {
+	switch strings.ToUpper(strings.TrimSpace(size)) {
+	case "", "2M":
+		return 2048, nil
+	case "1G":
+		return 1024 * 1024, nil
+	}
+	return 0, fmt.Errorf("%w: %q", ErrUnknownPageSize, size)
+}
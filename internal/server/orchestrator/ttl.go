@@ -0,0 +1,105 @@
+// Copyright (c) 2025 HYPR. PTE. LTD.
+//
+// Business Source License 1.1
+// See LICENSE file in the project root for details.
+
+package orchestrator
+
+import (
+	"context"
+	"time"
+
+	"github.com/volantvm/volant/internal/pluginspec"
+	"github.com/volantvm/volant/internal/server/db"
+	orchestratorevents "github.com/volantvm/volant/internal/server/orchestrator/events"
+	"github.com/volantvm/volant/internal/server/orchestrator/vmconfig"
+)
+
+// ttlInterval is how often the TTL reaper looks for expired VMs.
+const ttlInterval = time.Second
+
+// ttlDeadline returns when a VM started at now runs out of its TTL, or nil
+// when cfg sets none.
+func ttlDeadline(cfg vmconfig.Config, now time.Time) *time.Time {
+	ttl, _ := cfg.TTL()
+	if ttl <= 0 {
+		return nil
+	}
+	deadline := now.UTC().Add(ttl)
+	return &deadline
+}
+
+// armTTL records the TTL deadline of a VM that has just been launched. The
+// TTL of warm pool VMs starts when they are claimed instead.
+func (e *engine) armTTL(ctx context.Context, vm *db.VM, cfg vmconfig.Config) {
+	if _, pooled := vm.Labels[WarmPoolLabel]; pooled {
+		return
+	}
+	expiresAt := ttlDeadline(cfg, time.Now())
+	if expiresAt == nil && vm.ExpiresAt == nil {
+		return
+	}
+	if err := e.store.Queries().VirtualMachines().SetExpiresAt(ctx, vm.ID, expiresAt); err != nil {
+		e.logger.Error("set vm expiry", "vm", vm.Name, "error", err)
+		return
+	}
+	vm.ExpiresAt = expiresAt
+}
+
+func (e *engine) runTTLReaper(ctx context.Context) {
+	ticker := time.NewTicker(ttlInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			e.expireVMs(ctx, now)
+		}
+	}
+}
+
+// expireVMs applies the TTL action of every VM whose deadline has passed.
+func (e *engine) expireVMs(ctx context.Context, now time.Time) {
+	vms, err := e.store.Queries().VirtualMachines().ListExpired(ctx, now.UTC())
+	if err != nil {
+		e.logger.Error("list expired vms", "error", err)
+		return
+	}
+	for i := range vms {
+		if ctx.Err() != nil {
+			return
+		}
+		e.expireVM(ctx, &vms[i])
+	}
+}
+
+// expireVM clears the deadline first, so a failed stop or delete is reported
+// once rather than retried every tick.
+func (e *engine) expireVM(ctx context.Context, vm *db.VM) {
+	action := pluginspec.TTLActionStop
+	if record, err := e.store.Queries().VMConfigs().GetCurrent(ctx, vm.ID); err == nil && record != nil {
+		if versioned, err := vmconfig.FromDB(*record); err == nil {
+			if _, configured := versioned.Config.TTL(); configured != "" {
+				action = configured
+			}
+		}
+	}
+	if err := e.store.Queries().VirtualMachines().SetExpiresAt(ctx, vm.ID, nil); err != nil {
+		e.logger.Error("clear vm expiry", "vm", vm.Name, "error", err)
+		return
+	}
+
+	e.logger.Info("vm ttl expired", "vm", vm.Name, "action", action)
+	e.publishEvent(ctx, orchestratorevents.TypeVMExpired, orchestratorevents.VMStatus(vm.Status), vm, "ttl expired ("+action+")")
+	switch {
+	case action == pluginspec.TTLActionDelete:
+		if err := e.DestroyVM(ctx, vm.Name); err != nil {
+			e.logger.Error("delete expired vm", "vm", vm.Name, "error", err)
+		}
+	case e.hasInstance(vm.Name):
+		if _, err := e.StopVM(ctx, vm.Name); err != nil {
+			e.logger.Error("stop expired vm", "vm", vm.Name, "error", err)
+		}
+	}
+}
@@ -0,0 +1,133 @@
+// Copyright (c) 2025 HYPR. PTE. LTD.
+//
+// Business Source License 1.1
+// See LICENSE file in the project root for details.
+
+package orchestrator
+
+import (
+	"context"
+	"strings"
+
+	"github.com/volantvm/volant/internal/pluginspec"
+	"github.com/volantvm/volant/internal/server/db"
+	"github.com/volantvm/volant/internal/server/orchestrator/vmconfig"
+)
+
+// PluginUpgradeRollout reports the deployments rolled onto a new plugin
+// version.
+type PluginUpgradeRollout struct {
+	// Restarted lists deployments whose replicas are being rolled.
+	Restarted []string
+	// Skipped maps deployments that could not be rolled to the reason.
+	Skipped map[string]string
+}
+
+// PluginVMVersion reports the plugin version a VM's current config runs.
+type PluginVMVersion struct {
+	VM string
+	// Deployment is empty for standalone VMs.
+	Deployment string
+	Version    string
+}
+
+// RollPluginUpgrade rolls every deployment of manifest.Name that runs another
+// version onto manifest. Replicas keep their config overrides, only the
+// embedded manifest changes, and record the version they came from so the
+// manifest's post_upgrade hooks run once they are ready.
+func (e *engine) RollPluginUpgrade(ctx context.Context, manifest pluginspec.Manifest) (*PluginUpgradeRollout, error) {
+	groups, err := e.store.Queries().VMGroups().List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	rollout := &PluginUpgradeRollout{}
+	for _, group := range groups {
+		config, err := vmconfig.Unmarshal(group.ConfigJSON)
+		if err != nil {
+			return nil, err
+		}
+		if strings.TrimSpace(config.Plugin) != manifest.Name {
+			continue
+		}
+		var from string
+		if config.Manifest != nil {
+			from = config.Manifest.Version
+		}
+		if from == manifest.Version {
+			continue
+		}
+		upgraded := manifest
+		config.Manifest = &upgraded
+		config.UpgradedFrom = from
+		maxSurge := group.MaxSurge
+		if maxSurge <= 0 {
+			maxSurge = 1
+		}
+		_, err = e.UpdateDeploymentConfig(ctx, group.Name, UpdateDeploymentConfigRequest{
+			Config:   config,
+			MaxSurge: maxSurge,
+		})
+		if err != nil {
+			if rollout.Skipped == nil {
+				rollout.Skipped = make(map[string]string)
+			}
+			rollout.Skipped[group.Name] = err.Error()
+			e.logger.Warn("plugin upgrade rollout", "plugin", manifest.Name, "deployment", group.Name, "error", err)
+			continue
+		}
+		rollout.Restarted = append(rollout.Restarted, group.Name)
+	}
+	e.logger.Info("plugin upgrade", "plugin", manifest.Name, "version", manifest.Version, "restarted", len(rollout.Restarted))
+	return rollout, nil
+}
+
+// PluginVMVersions lists the VMs configured with plugin and the plugin
+// version each one runs.
+func (e *engine) PluginVMVersions(ctx context.Context, plugin string) ([]PluginVMVersion, error) {
+	plugin = strings.TrimSpace(plugin)
+	var result []PluginVMVersion
+	err := e.store.WithTx(ctx, func(q db.Queries) error {
+		groups, err := q.VMGroups().List(ctx)
+		if err != nil {
+			return err
+		}
+		groupNames := make(map[int64]string, len(groups))
+		for _, group := range groups {
+			groupNames[group.ID] = group.Name
+		}
+		vms, err := q.VirtualMachines().List(ctx)
+		if err != nil {
+			return err
+		}
+		for _, vm := range vms {
+			record, err := q.VMConfigs().GetCurrent(ctx, vm.ID)
+			if err != nil {
+				return err
+			}
+			if record == nil {
+				continue
+			}
+			versioned, err := vmconfig.FromDB(*record)
+			if err != nil {
+				return err
+			}
+			cfg := versioned.Config
+			if strings.TrimSpace(cfg.Plugin) != plugin {
+				continue
+			}
+			entry := PluginVMVersion{VM: vm.Name}
+			if cfg.Manifest != nil {
+				entry.Version = cfg.Manifest.Version
+			}
+			if vm.GroupID != nil {
+				entry.Deployment = groupNames[*vm.GroupID]
+			}
+			result = append(result, entry)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
@@ -8,17 +8,57 @@ package vmconfig
 import (
 	"encoding/json"
 	"fmt"
+	"path/filepath"
 	"strings"
 	"time"
 
 	"github.com/volantvm/volant/internal/pluginspec"
 	"github.com/volantvm/volant/internal/server/db"
+	"github.com/volantvm/volant/internal/server/orchestrator/topology"
+	"github.com/volantvm/volant/internal/server/sshkey"
 )
 
 // Resources captures compute resource settings for a VM.
 type Resources struct {
 	CPUCores int `json:"cpu_cores"`
 	MemoryMB int `json:"memory_mb"`
+	// CPUSet pins every vCPU to these host CPUs, in cpu list format ("2-5,8").
+	CPUSet string `json:"cpuset,omitempty"`
+	// NUMANode allocates guest memory on this host NUMA node and, without a
+	// CPUSet, pins the vCPUs to the node's CPUs.
+	NUMANode *int `json:"numa_node,omitempty"`
+	// Hugepages backs guest memory with huge pages of HugepageSize ("2M" or
+	// "1G", default 2M).
+	Hugepages    bool   `json:"hugepages,omitempty"`
+	HugepageSize string `json:"hugepage_size,omitempty"`
+}
+
+// Pinned reports whether any placement setting is requested.
+func (r Resources) Pinned() bool {
+	return strings.TrimSpace(r.CPUSet) != "" || r.NUMANode != nil || r.Hugepages
+}
+
+func (r Resources) validate() error {
+	if _, err := topology.ParseCPUSet(r.CPUSet); err != nil {
+		return fmt.Errorf("vmconfig: invalid cpuset %q", r.CPUSet)
+	}
+	if r.NUMANode != nil && *r.NUMANode < 0 {
+		return fmt.Errorf("vmconfig: numa_node must not be negative")
+	}
+	if !r.Hugepages {
+		if strings.TrimSpace(r.HugepageSize) != "" {
+			return fmt.Errorf("vmconfig: hugepage_size requires hugepages")
+		}
+		return nil
+	}
+	sizeKB, err := topology.PageSizeKB(r.HugepageSize)
+	if err != nil {
+		return fmt.Errorf("vmconfig: %w", err)
+	}
+	if (r.MemoryMB*1024)%sizeKB != 0 {
+		return fmt.Errorf("vmconfig: memory_mb must be a multiple of the %dMiB hugepage size", sizeKB/1024)
+	}
+	return nil
 }
 
 // API stores host-side connectivity preferences for the VM agent.
@@ -34,6 +74,212 @@ type Expose struct {
 	Port     int    `json:"port"`
 	HostPort int    `json:"host_port,omitempty"`
 	Mode     string `json:"mode,omitempty"`
+	// Socket names the unix socket driftd forwards to the guest port for
+	// vsock exposures of the unix protocol; host_port then only identifies
+	// the route.
+	Socket string `json:"socket,omitempty"`
+}
+
+// PortMapping publishes a guest port on the host through a DNAT rule.
+type PortMapping struct {
+	Host  int    `json:"host"`
+	Guest int    `json:"guest"`
+	Proto string `json:"proto,omitempty"`
+}
+
+// MaxScratchDiskMB caps the size of a scratch disk.
+const MaxScratchDiskMB = 1 << 20
+
+// DefaultScratchMountPath is where the agent mounts a scratch disk without a
+// mount_path.
+const DefaultScratchMountPath = "/scratch"
+
+// ScratchDisk is a sparse ext4 disk volantd creates for the VM. Its size is
+// the VM's quota: the guest cannot write more than SizeMB to it.
+type ScratchDisk struct {
+	SizeMB int `json:"size_mb"`
+	// Persist keeps the disk across restarts; otherwise it is recreated
+	// empty on every boot. Either way it is deleted with the VM.
+	Persist bool `json:"persist,omitempty"`
+	// MountPath is where the agent mounts the disk; defaults to /scratch.
+	MountPath string `json:"mount_path,omitempty"`
+}
+
+// Target returns the guest mount point of the disk.
+func (s ScratchDisk) Target() string {
+	if path := strings.TrimSpace(s.MountPath); path != "" {
+		return path
+	}
+	return DefaultScratchMountPath
+}
+
+func (s ScratchDisk) validate() error {
+	if s.SizeMB <= 0 {
+		return fmt.Errorf("vmconfig: scratch_disk size_mb must be greater than zero")
+	}
+	if s.SizeMB > MaxScratchDiskMB {
+		return fmt.Errorf("vmconfig: scratch_disk size_mb must be <= %d", MaxScratchDiskMB)
+	}
+	if target := s.Target(); !strings.HasPrefix(target, "/") || target == "/" || strings.ContainsAny(target, ", ") {
+		return fmt.Errorf("vmconfig: scratch_disk mount_path %q must be an absolute path other than / without commas or spaces", target)
+	}
+	return nil
+}
+
+// Spread distributes a deployment's replicas across host NUMA nodes and user
+// networks. Each new replica, including the replacement for a crashed one,
+// goes to the node and network running the fewest replicas, ties going to
+// the earliest listed.
+type Spread struct {
+	// NUMANodes sets each replica's resources.numa_node.
+	NUMANodes []int `json:"numa_nodes,omitempty"`
+	// Networks sets each replica's network.name; the bridges of these user
+	// networks carry the replicas' primary NICs.
+	Networks []string `json:"networks,omitempty"`
+}
+
+func (s Spread) validate(c Config) error {
+	if len(s.NUMANodes) == 0 && len(s.Networks) == 0 {
+		return fmt.Errorf("vmconfig: spread needs numa_nodes or networks")
+	}
+	seenNodes := make(map[int]bool, len(s.NUMANodes))
+	for _, node := range s.NUMANodes {
+		if node < 0 {
+			return fmt.Errorf("vmconfig: spread numa_nodes must not be negative")
+		}
+		if seenNodes[node] {
+			return fmt.Errorf("vmconfig: spread numa node %d listed twice", node)
+		}
+		seenNodes[node] = true
+	}
+	if len(s.NUMANodes) > 0 && (c.Resources.NUMANode != nil || strings.TrimSpace(c.Resources.CPUSet) != "") {
+		return fmt.Errorf("vmconfig: spread numa_nodes cannot be combined with resources numa_node or cpuset")
+	}
+	seenNetworks := make(map[string]bool, len(s.Networks))
+	for _, name := range s.Networks {
+		if name == "" {
+			return fmt.Errorf("vmconfig: spread networks must not be empty")
+		}
+		if seenNetworks[name] {
+			return fmt.Errorf("vmconfig: spread network %s listed twice", name)
+		}
+		seenNetworks[name] = true
+	}
+	if len(s.Networks) > 0 && c.Network != nil && strings.TrimSpace(c.Network.Name) != "" {
+		return fmt.Errorf("vmconfig: spread networks cannot be combined with network name")
+	}
+	return nil
+}
+
+// Dependency conditions: the dependency VM must be running, or running and
+// past its readiness probe.
+const (
+	DependencyStarted = "started"
+	DependencyReady   = "ready"
+)
+
+// Dependency names a VM that must be up before this one starts. In JSON it is
+// either an object or just the VM name, which waits for readiness.
+type Dependency struct {
+	VM string `json:"vm"`
+	// Condition is DependencyStarted or DependencyReady, the default.
+	Condition string `json:"condition,omitempty"`
+	// StopWith stops this VM when the dependency stops, exits or is deleted.
+	StopWith bool `json:"stop_with,omitempty"`
+}
+
+// UnmarshalJSON accepts a bare VM name as well as the object form.
+func (d *Dependency) UnmarshalJSON(data []byte) error {
+	var name string
+	if err := json.Unmarshal(data, &name); err == nil {
+		*d = Dependency{VM: name}
+		return nil
+	}
+	type plain Dependency
+	var decoded plain
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return err
+	}
+	*d = Dependency(decoded)
+	return nil
+}
+
+// WaitsForReady reports whether the dependency must pass its readiness
+// probe rather than merely run.
+func (d Dependency) WaitsForReady() bool {
+	return d.Condition != DependencyStarted
+}
+
+func validateDependencies(deps []Dependency) error {
+	seen := make(map[string]bool, len(deps))
+	for _, dep := range deps {
+		if dep.VM == "" {
+			return fmt.Errorf("vmconfig: depends_on vm is required")
+		}
+		if dep.Condition != "" && dep.Condition != DependencyStarted && dep.Condition != DependencyReady {
+			return fmt.Errorf("vmconfig: depends_on condition %q not supported (want started or ready)", dep.Condition)
+		}
+		if seen[dep.VM] {
+			return fmt.Errorf("vmconfig: depends_on vm %s listed twice", dep.VM)
+		}
+		seen[dep.VM] = true
+	}
+	return nil
+}
+
+// Console restricts access to the VM serial console.
+type Console struct {
+	// Disabled rejects every console session for the VM.
+	Disabled bool `json:"disabled,omitempty"`
+	// Users limits console sessions to these console identities; empty allows
+	// any caller holding the console permission.
+	Users []string `json:"users,omitempty"`
+}
+
+// DefaultSSHUser is the login user `volar vms ssh` connects as when SSH
+// names none.
+const DefaultSSHUser = "root"
+
+// SSH configures key-based SSH access. The keys are injected into the VM's
+// cloud-init meta-data, so only guests running cloud-init pick them up.
+type SSH struct {
+	// User is the login user clients connect as; it does not create the
+	// user in the guest.
+	User string `json:"user,omitempty"`
+	// AuthorizedKeys are authorized_keys lines installed for the image's
+	// default user.
+	AuthorizedKeys []string `json:"authorized_keys,omitempty"`
+	// GenerateKey has volantd generate a keypair for the VM and keep the
+	// private key in the secrets store until the VM is deleted.
+	GenerateKey bool `json:"generate_key,omitempty"`
+}
+
+// LoginUser returns the user clients connect as.
+func (s *SSH) LoginUser() string {
+	if s == nil || s.User == "" {
+		return DefaultSSHUser
+	}
+	return s.User
+}
+
+func (s *SSH) clone() *SSH {
+	sshCopy := *s
+	if len(s.AuthorizedKeys) > 0 {
+		sshCopy.AuthorizedKeys = append([]string(nil), s.AuthorizedKeys...)
+	}
+	return &sshCopy
+}
+
+func (s *SSH) validate() error {
+	if strings.ContainsAny(s.User, " \t\r\n@:") {
+		return fmt.Errorf("vmconfig: ssh user %q is invalid", s.User)
+	}
+	for _, key := range s.AuthorizedKeys {
+		if err := sshkey.ValidAuthorizedKey(key); err != nil {
+			return fmt.Errorf("vmconfig: ssh authorized key: %w", err)
+		}
+	}
+	return nil
 }
 
 // Config represents the persisted, user-editable configuration of a VM.
@@ -52,7 +298,34 @@ type Config struct {
 	CloudInit *pluginspec.CloudInit     `json:"cloud_init,omitempty"`
 	Network   *pluginspec.NetworkConfig `json:"network,omitempty"`
 	Initramfs *pluginspec.Initramfs     `json:"initramfs,omitempty"`
+	Kernel    *pluginspec.Kernel        `json:"kernel,omitempty"`
 	RootFS    *pluginspec.RootFS        `json:"rootfs,omitempty"`
+	Console   *Console                  `json:"console,omitempty"`
+	SSH       *SSH                      `json:"ssh,omitempty"`
+	Ports     []PortMapping             `json:"ports,omitempty"`
+	// Bundles names the config bundles injected into the VM. Deployments set
+	// it for every replica.
+	Bundles []string `json:"bundles,omitempty"`
+	// Shares overrides the manifest's virtio-fs shares when set.
+	Shares []pluginspec.Share `json:"shares,omitempty"`
+	// Secrets overrides the manifest's secret mounts when set.
+	Secrets []pluginspec.SecretMount `json:"secrets,omitempty"`
+	// ScratchDisk attaches a writable disk of the given size.
+	ScratchDisk *ScratchDisk `json:"scratch_disk,omitempty"`
+	// UpgradedFrom is the plugin version a plugin upgrade rolled the VM off.
+	// It is cleared once the manifest's post_upgrade hooks have run.
+	UpgradedFrom string `json:"upgraded_from,omitempty"`
+	// Spread places a deployment's replicas; replicas are created without it.
+	Spread *Spread `json:"spread,omitempty"`
+	// DependsOn lists the VMs started before this one; see Dependency.
+	DependsOn []Dependency `json:"depends_on,omitempty"`
+	// Autostart starts the VM when volantd starts, unless it is already
+	// running.
+	Autostart bool `json:"autostart,omitempty"`
+	// TTLSeconds stops the VM, or deletes it when TTLAction is delete, that
+	// many seconds after each start. 0 falls back to the manifest default.
+	TTLSeconds int    `json:"ttl_seconds,omitempty"`
+	TTLAction  string `json:"ttl_action,omitempty"`
 }
 
 // Versioned associates a configuration with its version metadata.
@@ -62,12 +335,24 @@ type Versioned struct {
 	Config    Config    `json:"config"`
 }
 
+// Outcomes recorded against a configuration version applied with health-checked
+// rollback.
+const (
+	OutcomePending        = "pending"
+	OutcomeHealthy        = "healthy"
+	OutcomeRolledBack     = "rolled_back"
+	OutcomeRollbackFailed = "rollback_failed"
+	OutcomeRestored       = "restored"
+)
+
 // HistoryEntry captures an historical configuration snapshot.
 type HistoryEntry struct {
-	ID        int64     `json:"id"`
-	Version   int       `json:"version"`
-	UpdatedAt time.Time `json:"updated_at"`
-	Config    Config    `json:"config"`
+	ID             int64     `json:"id"`
+	Version        int       `json:"version"`
+	UpdatedAt      time.Time `json:"updated_at"`
+	Config         Config    `json:"config"`
+	Outcome        string    `json:"outcome,omitempty"`
+	OutcomeMessage string    `json:"outcome_message,omitempty"`
 }
 
 // Patch represents a partial configuration update request.
@@ -85,13 +370,35 @@ type Patch struct {
 	// Optional boot media overrides
 	KernelOverride *string               `json:"kernel_override,omitempty"`
 	Initramfs      *pluginspec.Initramfs `json:"initramfs,omitempty"`
+	Kernel         *pluginspec.Kernel    `json:"kernel,omitempty"`
 	RootFS         *pluginspec.RootFS    `json:"rootfs,omitempty"`
+	Console        *Console              `json:"console,omitempty"`
+	// SSH replaces the SSH settings; an empty object removes them.
+	SSH     *SSH                      `json:"ssh,omitempty"`
+	Ports   *[]PortMapping            `json:"ports,omitempty"`
+	Shares  *[]pluginspec.Share       `json:"shares,omitempty"`
+	Secrets *[]pluginspec.SecretMount `json:"secrets,omitempty"`
+	// ScratchDisk replaces the scratch disk settings; size_mb 0 removes the
+	// disk.
+	ScratchDisk *ScratchDisk `json:"scratch_disk,omitempty"`
+	// DependsOn replaces the dependencies; an empty list removes them.
+	DependsOn *[]Dependency `json:"depends_on,omitempty"`
+	Autostart *bool         `json:"autostart,omitempty"`
+	// TTLSeconds applies from the next start; 0 falls back to the manifest
+	// default.
+	TTLSeconds *int    `json:"ttl_seconds,omitempty"`
+	TTLAction  *string `json:"ttl_action,omitempty"`
 }
 
-// ResourcesPatch allows partial updates of compute resources.
+// ResourcesPatch allows partial updates of compute resources. An empty
+// CPUSet or a negative NUMANode clears the setting.
 type ResourcesPatch struct {
-	CPUCores *int `json:"cpu_cores,omitempty"`
-	MemoryMB *int `json:"memory_mb,omitempty"`
+	CPUCores     *int    `json:"cpu_cores,omitempty"`
+	MemoryMB     *int    `json:"memory_mb,omitempty"`
+	CPUSet       *string `json:"cpuset,omitempty"`
+	NUMANode     *int    `json:"numa_node,omitempty"`
+	Hugepages    *bool   `json:"hugepages,omitempty"`
+	HugepageSize *string `json:"hugepage_size,omitempty"`
 }
 
 // APIPatch allows partial API host/port updates.
@@ -103,6 +410,10 @@ type APIPatch struct {
 // Clone creates a deep copy of the configuration.
 func (c Config) Clone() Config {
 	clone := c
+	if c.Resources.NUMANode != nil {
+		node := *c.Resources.NUMANode
+		clone.Resources.NUMANode = &node
+	}
 	if c.Manifest != nil {
 		manifestCopy := *c.Manifest
 		clone.Manifest = &manifestCopy
@@ -120,6 +431,10 @@ func (c Config) Clone() Config {
 		initCopy := *c.Initramfs
 		clone.Initramfs = &initCopy
 	}
+	if c.Kernel != nil {
+		kernelCopy := *c.Kernel
+		clone.Kernel = &kernelCopy
+	}
 	if c.RootFS != nil {
 		rootCopy := *c.RootFS
 		clone.RootFS = &rootCopy
@@ -136,9 +451,69 @@ func (c Config) Clone() Config {
 		copy(exposeCopy, c.Expose)
 		clone.Expose = exposeCopy
 	}
+	if c.Console != nil {
+		clone.Console = c.Console.clone()
+	}
+	if c.SSH != nil {
+		clone.SSH = c.SSH.clone()
+	}
+	if len(c.Ports) > 0 {
+		portsCopy := make([]PortMapping, len(c.Ports))
+		copy(portsCopy, c.Ports)
+		clone.Ports = portsCopy
+	}
+	if len(c.Bundles) > 0 {
+		clone.Bundles = append([]string(nil), c.Bundles...)
+	}
+	if len(c.Shares) > 0 {
+		clone.Shares = append([]pluginspec.Share(nil), c.Shares...)
+	}
+	if len(c.Secrets) > 0 {
+		clone.Secrets = append([]pluginspec.SecretMount(nil), c.Secrets...)
+	}
+	if c.ScratchDisk != nil {
+		scratchCopy := *c.ScratchDisk
+		clone.ScratchDisk = &scratchCopy
+	}
+	if c.Spread != nil {
+		clone.Spread = &Spread{
+			NUMANodes: append([]int(nil), c.Spread.NUMANodes...),
+			Networks:  append([]string(nil), c.Spread.Networks...),
+		}
+	}
+	if len(c.DependsOn) > 0 {
+		clone.DependsOn = append([]Dependency(nil), c.DependsOn...)
+	}
 	return clone
 }
 
+func (c *Console) clone() *Console {
+	consoleCopy := *c
+	if len(c.Users) > 0 {
+		consoleCopy.Users = append([]string(nil), c.Users...)
+	}
+	return &consoleCopy
+}
+
+// AllowsUser reports whether user may open a console session on the VM.
+func (c *Console) AllowsUser(user string) bool {
+	if c == nil {
+		return true
+	}
+	if c.Disabled {
+		return false
+	}
+	if len(c.Users) == 0 {
+		return true
+	}
+	for _, allowed := range c.Users {
+		if allowed == user {
+			return true
+		}
+	}
+	return false
+}
+
 // Normalize trims fields and normalizes embedded manifests.
 func (c *Config) Normalize() {
 	if c == nil {
@@ -158,6 +533,42 @@ func (c *Config) Normalize() {
 		}
 		c.Expose[i].Mode = strings.TrimSpace(strings.ToLower(c.Expose[i].Mode))
 	}
+	for i := range c.Ports {
+		c.Ports[i].Proto = strings.TrimSpace(strings.ToLower(c.Ports[i].Proto))
+		if c.Ports[i].Proto == "" {
+			c.Ports[i].Proto = "tcp"
+		}
+	}
+	if len(c.Bundles) > 0 {
+		seen := make(map[string]bool, len(c.Bundles))
+		bundles := make([]string, 0, len(c.Bundles))
+		for _, name := range c.Bundles {
+			name = strings.TrimSpace(name)
+			if name == "" || seen[name] {
+				continue
+			}
+			seen[name] = true
+			bundles = append(bundles, name)
+		}
+		c.Bundles = bundles
+	}
+	pluginspec.NormalizeShares(c.Shares)
+	pluginspec.NormalizeSecretMounts(c.Secrets)
+	if c.ScratchDisk != nil {
+		c.ScratchDisk.MountPath = strings.TrimSpace(c.ScratchDisk.MountPath)
+	}
+	if c.Spread != nil {
+		for i := range c.Spread.Networks {
+			c.Spread.Networks[i] = strings.TrimSpace(c.Spread.Networks[i])
+		}
+	}
+	for i := range c.DependsOn {
+		c.DependsOn[i].VM = strings.TrimSpace(c.DependsOn[i].VM)
+		c.DependsOn[i].Condition = strings.TrimSpace(strings.ToLower(c.DependsOn[i].Condition))
+	}
+	if c.TTLAction != "" {
+		c.TTLAction = pluginspec.NormalizeTTLAction(c.TTLAction)
+	}
 	if c.Manifest != nil {
 		manifestCopy := *c.Manifest
 		manifestCopy.Normalize()
@@ -174,13 +585,43 @@ func (c *Config) Normalize() {
 		initCopy.Checksum = strings.TrimSpace(initCopy.Checksum)
 		c.Initramfs = &initCopy
 	}
+	if c.Kernel != nil {
+		kernelCopy := *c.Kernel
+		kernelCopy.URL = strings.TrimSpace(kernelCopy.URL)
+		kernelCopy.Checksum = strings.TrimSpace(kernelCopy.Checksum)
+		c.Kernel = &kernelCopy
+	}
 	if c.RootFS != nil {
 		rootCopy := *c.RootFS
 		rootCopy.URL = strings.TrimSpace(rootCopy.URL)
 		rootCopy.Checksum = strings.TrimSpace(rootCopy.Checksum)
 		rootCopy.Format = strings.TrimSpace(strings.ToLower(rootCopy.Format))
+		rootCopy.Overlay = strings.TrimSpace(strings.ToLower(rootCopy.Overlay))
 		c.RootFS = &rootCopy
 	}
+	if c.Console != nil {
+		consoleCopy := c.Console.clone()
+		users := consoleCopy.Users[:0]
+		for _, user := range consoleCopy.Users {
+			if user = strings.TrimSpace(user); user != "" {
+				users = append(users, user)
+			}
+		}
+		consoleCopy.Users = users
+		c.Console = consoleCopy
+	}
+	if c.SSH != nil {
+		sshCopy := c.SSH.clone()
+		sshCopy.User = strings.TrimSpace(sshCopy.User)
+		keys := sshCopy.AuthorizedKeys[:0]
+		for _, key := range sshCopy.AuthorizedKeys {
+			if key = strings.TrimSpace(key); key != "" {
+				keys = append(keys, key)
+			}
+		}
+		sshCopy.AuthorizedKeys = keys
+		c.SSH = sshCopy
+	}
 }
 
 // Validate performs semantic validation on the configuration.
@@ -200,6 +641,39 @@ func (c Config) Validate() error {
 	if c.Resources.MemoryMB <= 0 {
 		return fmt.Errorf("vmconfig: memory_mb must be greater than zero")
 	}
+	if err := c.Resources.validate(); err != nil {
+		return err
+	}
+	if err := c.Devices.Validate(); err != nil {
+		return fmt.Errorf("vmconfig: %w", err)
+	}
+	if err := pluginspec.ValidateShares(c.Shares); err != nil {
+		return fmt.Errorf("vmconfig: %w", err)
+	}
+	if err := pluginspec.ValidateSecretMounts(c.Secrets); err != nil {
+		return fmt.Errorf("vmconfig: %w", err)
+	}
+	if c.SSH != nil {
+		if err := c.SSH.validate(); err != nil {
+			return err
+		}
+	}
+	if c.ScratchDisk != nil {
+		if err := c.ScratchDisk.validate(); err != nil {
+			return err
+		}
+	}
+	if c.Spread != nil {
+		if err := c.Spread.validate(c); err != nil {
+			return err
+		}
+	}
+	if err := validateDependencies(c.DependsOn); err != nil {
+		return err
+	}
+	if err := pluginspec.ValidateTTL(c.TTLSeconds, c.TTLAction); err != nil {
+		return fmt.Errorf("vmconfig: %w", err)
+	}
 	for _, rule := range c.Expose {
 		if rule.Port <= 0 {
 			return fmt.Errorf("vmconfig: expose port must be greater than zero")
@@ -214,13 +688,44 @@ func (c Config) Validate() error {
 			return fmt.Errorf("vmconfig: expose host_port must be <= 65535")
 		}
 		protocol := strings.TrimSpace(strings.ToLower(rule.Protocol))
-		if protocol != "tcp" && protocol != "udp" && protocol != "" {
+		if protocol != "tcp" && protocol != "udp" && protocol != "unix" && protocol != "" {
 			return fmt.Errorf("vmconfig: expose protocol %q not supported", rule.Protocol)
 		}
 		mode := strings.TrimSpace(strings.ToLower(rule.Mode))
 		if mode != "" && mode != "bridged" && mode != "bridge" && mode != "vsock" && mode != "dhcp" {
 			return fmt.Errorf("vmconfig: expose mode %q not supported", rule.Mode)
 		}
+		if protocol == "unix" && mode != "" && mode != "vsock" {
+			return fmt.Errorf("vmconfig: expose protocol unix requires vsock mode")
+		}
+		socket := strings.TrimSpace(rule.Socket)
+		if socket != "" && protocol != "unix" {
+			return fmt.Errorf("vmconfig: expose socket requires the unix protocol")
+		}
+		if socket != "" && (socket != filepath.Base(socket) || socket == "." || socket == "..") {
+			return fmt.Errorf("vmconfig: expose socket must be a file name, not a path")
+		}
+	}
+	published := make(map[string]struct{}, len(c.Ports))
+	for _, mapping := range c.Ports {
+		if mapping.Host <= 0 || mapping.Host > 65535 {
+			return fmt.Errorf("vmconfig: ports host must be between 1 and 65535")
+		}
+		if mapping.Guest <= 0 || mapping.Guest > 65535 {
+			return fmt.Errorf("vmconfig: ports guest must be between 1 and 65535")
+		}
+		proto := strings.TrimSpace(strings.ToLower(mapping.Proto))
+		if proto == "" {
+			proto = "tcp"
+		}
+		if proto != "tcp" && proto != "udp" {
+			return fmt.Errorf("vmconfig: ports proto %q not supported", mapping.Proto)
+		}
+		key := fmt.Sprintf("%d/%s", mapping.Host, proto)
+		if _, dup := published[key]; dup {
+			return fmt.Errorf("vmconfig: host port %s mapped more than once", key)
+		}
+		published[key] = struct{}{}
 	}
 	if c.CloudInit != nil {
 		if err := c.CloudInit.Validate(); err != nil {
@@ -243,9 +748,29 @@ func (c Config) Validate() error {
 			return fmt.Errorf("vmconfig: %w", err)
 		}
 	}
+	if err := c.Kernel.Validate(); err != nil {
+		return fmt.Errorf("vmconfig: %w", err)
+	}
 	return nil
 }
 
+// TTL returns how long the VM may run after a start and what happens then:
+// the config's ttl_seconds, or the manifest default when that is 0. A zero
+// duration means the VM has no TTL.
+func (c Config) TTL() (time.Duration, string) {
+	seconds, action := c.TTLSeconds, c.TTLAction
+	if seconds <= 0 && c.Manifest != nil {
+		seconds = c.Manifest.TTLSeconds
+		if action == "" {
+			action = c.Manifest.TTLAction
+		}
+	}
+	if seconds <= 0 {
+		return 0, ""
+	}
+	return time.Duration(seconds) * time.Second, pluginspec.NormalizeTTLAction(action)
+}
+
 // Marshal serialises the configuration to JSON with normalization and validation.
 func Marshal(c Config) ([]byte, error) {
 	clone := c.Clone()
@@ -292,6 +817,21 @@ func (p Patch) Apply(base Config) (Config, error) {
 		if p.Resources.MemoryMB != nil {
 			updated.Resources.MemoryMB = *p.Resources.MemoryMB
 		}
+		if p.Resources.CPUSet != nil {
+			updated.Resources.CPUSet = strings.TrimSpace(*p.Resources.CPUSet)
+		}
+		if p.Resources.NUMANode != nil {
+			updated.Resources.NUMANode = nil
+			if node := *p.Resources.NUMANode; node >= 0 {
+				updated.Resources.NUMANode = &node
+			}
+		}
+		if p.Resources.Hugepages != nil {
+			updated.Resources.Hugepages = *p.Resources.Hugepages
+		}
+		if p.Resources.HugepageSize != nil {
+			updated.Resources.HugepageSize = strings.TrimSpace(*p.Resources.HugepageSize)
+		}
 	}
 	if p.API != nil {
 		if p.API.Host != nil {
@@ -335,14 +875,84 @@ func (p Patch) Apply(base Config) (Config, error) {
 		cloudCopy.Normalize()
 		updated.CloudInit = &cloudCopy
 	}
+	if p.Network != nil {
+		networkCopy := *p.Network
+		networkCopy.Normalize()
+		updated.Network = &networkCopy
+	}
 	if p.Initramfs != nil {
 		initCopy := *p.Initramfs
 		updated.Initramfs = &initCopy
 	}
+	if p.Kernel != nil {
+		if strings.TrimSpace(p.Kernel.URL) == "" {
+			updated.Kernel = nil
+		} else {
+			kernelCopy := *p.Kernel
+			updated.Kernel = &kernelCopy
+		}
+	}
 	if p.RootFS != nil {
 		rootCopy := *p.RootFS
 		updated.RootFS = &rootCopy
 	}
+	if p.Console != nil {
+		updated.Console = p.Console.clone()
+	}
+	if p.SSH != nil {
+		if p.SSH.User == "" && len(p.SSH.AuthorizedKeys) == 0 && !p.SSH.GenerateKey {
+			updated.SSH = nil
+		} else {
+			updated.SSH = p.SSH.clone()
+		}
+	}
+	if p.Ports != nil {
+		if len(*p.Ports) == 0 {
+			updated.Ports = nil
+		} else {
+			portsCopy := make([]PortMapping, len(*p.Ports))
+			copy(portsCopy, *p.Ports)
+			updated.Ports = portsCopy
+		}
+	}
+	if p.Shares != nil {
+		if len(*p.Shares) == 0 {
+			updated.Shares = nil
+		} else {
+			updated.Shares = append([]pluginspec.Share(nil), (*p.Shares)...)
+		}
+	}
+	if p.Secrets != nil {
+		if len(*p.Secrets) == 0 {
+			updated.Secrets = nil
+		} else {
+			updated.Secrets = append([]pluginspec.SecretMount(nil), (*p.Secrets)...)
+		}
+	}
+	if p.ScratchDisk != nil {
+		if p.ScratchDisk.SizeMB == 0 {
+			updated.ScratchDisk = nil
+		} else {
+			scratchCopy := *p.ScratchDisk
+			updated.ScratchDisk = &scratchCopy
+		}
+	}
+	if p.Autostart != nil {
+		updated.Autostart = *p.Autostart
+	}
+	if p.TTLSeconds != nil {
+		updated.TTLSeconds = *p.TTLSeconds
+	}
+	if p.TTLAction != nil {
+		updated.TTLAction = *p.TTLAction
+	}
+	if p.DependsOn != nil {
+		if len(*p.DependsOn) == 0 {
+			updated.DependsOn = nil
+		} else {
+			updated.DependsOn = append([]Dependency(nil), (*p.DependsOn)...)
+		}
+	}
 
 	updated.Normalize()
 	if err := updated.Validate(); err != nil {
@@ -371,9 +981,11 @@ func FromHistory(entry db.VMConfigHistoryEntry) (HistoryEntry, error) {
 		return HistoryEntry{}, err
 	}
 	return HistoryEntry{
-		ID:        entry.ID,
-		Version:   entry.Version,
-		UpdatedAt: entry.UpdatedAt,
-		Config:    cfg,
+		ID:             entry.ID,
+		Version:        entry.Version,
+		UpdatedAt:      entry.UpdatedAt,
+		Config:         cfg,
+		Outcome:        entry.Outcome,
+		OutcomeMessage: entry.OutcomeMessage,
 	}, nil
 }
@@ -0,0 +1,96 @@
+// Copyright (c) 2025 HYPR. PTE. LTD.
+//
+// Business Source License 1.1
+// See LICENSE file in the project root for details.
+
+package vmlogs
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"strings"
+	"time"
+)
+
+// Follower moves what a VM's hypervisor writes to its raw output files into
+// the VM's log. Consumed output is truncated away, so the raw files stay
+// small and a follower started after a volantd restart resumes where the
+// previous one stopped.
+type Follower struct {
+	store  *Store
+	name   string
+	cursor map[string]int64
+}
+
+// Follow returns a follower for the raw output of name.
+func (s *Store) Follow(name string) *Follower {
+	return &Follower{store: s, name: name, cursor: make(map[string]int64)}
+}
+
+// Poll ingests the complete lines written since the last poll. final also
+// ingests a trailing partial line, for use once the hypervisor has exited.
+func (f *Follower) Poll(final bool) error {
+	for _, stream := range []string{StreamStdout, StreamStderr} {
+		if err := f.poll(stream, final); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (f *Follower) poll(stream string, final bool) error {
+	path := f.store.RawPath(f.name, stream)
+	file, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	defer file.Close()
+	info, err := file.Stat()
+	if err != nil {
+		return err
+	}
+	offset := f.cursor[stream]
+	size := info.Size()
+	if size < offset {
+		offset = 0
+	}
+	if size == offset {
+		return nil
+	}
+	data := make([]byte, size-offset)
+	if _, err := file.ReadAt(data, offset); err != nil && err != io.EOF {
+		return err
+	}
+	consumed := len(data)
+	if !final {
+		consumed = bytes.LastIndexByte(data, '\n') + 1
+	}
+	if consumed == 0 {
+		return nil
+	}
+
+	now := time.Now().UTC()
+	lines := strings.Split(strings.TrimSuffix(string(data[:consumed]), "\n"), "\n")
+	entries := make([]Entry, 0, len(lines))
+	for _, line := range lines {
+		entries = append(entries, Entry{Timestamp: now, Source: SourceHypervisor, Stream: stream, Line: strings.TrimSuffix(line, "\r")})
+	}
+	if err := f.store.Append(f.name, entries...); err != nil {
+		return err
+	}
+	offset += int64(consumed)
+	f.cursor[stream] = offset
+
+	// Truncate once everything written so far is consumed. Output appended
+	// between the stat and the truncate is lost; the window is one syscall.
+	if info, err := os.Stat(path); err == nil && info.Size() == offset {
+		if err := os.Truncate(path, 0); err == nil {
+			f.cursor[stream] = 0
+		}
+	}
+	return nil
+}
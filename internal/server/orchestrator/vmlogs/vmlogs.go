@@ -0,0 +1,337 @@
+// Copyright (c) 2025 HYPR. PTE. LTD.
+//
+// Business Source License 1.1
+// See LICENSE file in the project root for details.
+
+// Package vmlogs persists the output of VM hypervisors and guest agents to
+// rotated per-VM files, so logs outlive restarts of the VM and of volantd.
+package vmlogs
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Sources of log entries.
+const (
+	SourceHypervisor = "hypervisor"
+	SourceAgent      = "agent"
+)
+
+// Streams of log entries.
+const (
+	StreamStdout = "stdout"
+	StreamStderr = "stderr"
+)
+
+// currentFile is the file entries are appended to; rotated files carry a
+// numeric suffix, .1 being the newest.
+const currentFile = "vm.log"
+
+// Entry is one line of VM output.
+type Entry struct {
+	Timestamp time.Time `json:"timestamp"`
+	Source    string    `json:"source"`
+	Stream    string    `json:"stream"`
+	Line      string    `json:"line"`
+}
+
+// Retention bounds the logs kept per VM.
+type Retention struct {
+	// MaxFileBytes rotates the current file once it grows past this size.
+	MaxFileBytes int64
+	// MaxFiles is the number of rotated files kept per VM.
+	MaxFiles int
+	// MaxAge removes files whose newest entry is older than this; zero keeps
+	// them until MaxFiles pushes them out.
+	MaxAge time.Duration
+}
+
+// Query selects log entries. Zero fields match everything.
+type Query struct {
+	Since  time.Time
+	Source string
+	Stream string
+	// Limit keeps the last Limit matching entries.
+	Limit int
+}
+
+func (q Query) matches(entry Entry) bool {
+	if !q.Since.IsZero() && entry.Timestamp.Before(q.Since) {
+		return false
+	}
+	if q.Source != "" && entry.Source != q.Source {
+		return false
+	}
+	return q.Stream == "" || entry.Stream == q.Stream
+}
+
+// Store keeps the logs of every VM under one directory, one subdirectory
+// per VM.
+type Store struct {
+	dir       string
+	retention Retention
+
+	mu    sync.Mutex
+	files map[string]*logFile
+	sink  Sink
+}
+
+type logFile struct {
+	file *os.File
+	size int64
+}
+
+// New returns a store rooted at dir.
+func New(dir string, retention Retention) *Store {
+	return &Store{dir: dir, retention: retention, files: make(map[string]*logFile)}
+}
+
+func (s *Store) vmDir(name string) string {
+	return filepath.Join(s.dir, name)
+}
+
+// RawPath is where the hypervisor of a VM writes stream until Follower
+// ingests it.
+func (s *Store) RawPath(name, stream string) string {
+	return filepath.Join(s.vmDir(name), "hypervisor."+stream)
+}
+
+// Sink receives the entries of a VM once they are recorded. It must not
+// block.
+type Sink func(name string, entries []Entry)
+
+// SetSink registers sink for entries appended from now on.
+func (s *Store) SetSink(sink Sink) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sink = sink
+}
+
+// Append records entries for a VM, rotating its current file when full.
+func (s *Store) Append(name string, entries ...Entry) error {
+	if len(entries) == 0 {
+		return nil
+	}
+	s.mu.Lock()
+	err := s.appendLocked(name, entries)
+	sink := s.sink
+	s.mu.Unlock()
+	if err == nil && sink != nil {
+		sink(name, entries)
+	}
+	return err
+}
+
+func (s *Store) appendLocked(name string, entries []Entry) error {
+	current, err := s.openLocked(name)
+	if err != nil {
+		return err
+	}
+	var buf []byte
+	for _, entry := range entries {
+		encoded, err := json.Marshal(entry)
+		if err != nil {
+			return err
+		}
+		buf = append(append(buf, encoded...), '\n')
+	}
+	n, err := current.file.Write(buf)
+	current.size += int64(n)
+	if err != nil {
+		return err
+	}
+	if s.retention.MaxFileBytes > 0 && current.size >= s.retention.MaxFileBytes {
+		return s.rotateLocked(name)
+	}
+	return nil
+}
+
+func (s *Store) openLocked(name string) (*logFile, error) {
+	if current, ok := s.files[name]; ok {
+		return current, nil
+	}
+	if err := os.MkdirAll(s.vmDir(name), 0o755); err != nil {
+		return nil, err
+	}
+	file, err := os.OpenFile(filepath.Join(s.vmDir(name), currentFile), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		_ = file.Close()
+		return nil, err
+	}
+	current := &logFile{file: file, size: info.Size()}
+	s.files[name] = current
+	return current, nil
+}
+
+func (s *Store) closeLocked(name string) {
+	if current, ok := s.files[name]; ok {
+		_ = current.file.Close()
+		delete(s.files, name)
+	}
+}
+
+// rotateLocked shifts the rotated files of name up by one, dropping those
+// past MaxFiles, and starts a new current file.
+func (s *Store) rotateLocked(name string) error {
+	s.closeLocked(name)
+	dir := s.vmDir(name)
+	rotated, err := rotatedFiles(dir)
+	if err != nil {
+		return err
+	}
+	for i := len(rotated) - 1; i >= 0; i-- {
+		if rotated[i].index >= s.retention.MaxFiles {
+			_ = os.Remove(rotated[i].path)
+			continue
+		}
+		if err := os.Rename(rotated[i].path, rotatedPath(dir, rotated[i].index+1)); err != nil {
+			return err
+		}
+	}
+	if s.retention.MaxFiles < 1 {
+		return os.Remove(filepath.Join(dir, currentFile))
+	}
+	return os.Rename(filepath.Join(dir, currentFile), rotatedPath(dir, 1))
+}
+
+type rotatedFile struct {
+	path  string
+	index int
+}
+
+func rotatedPath(dir string, index int) string {
+	return filepath.Join(dir, currentFile+"."+strconv.Itoa(index))
+}
+
+// rotatedFiles lists the rotated files in dir, newest first.
+func rotatedFiles(dir string) ([]rotatedFile, error) {
+	matches, err := filepath.Glob(filepath.Join(dir, currentFile+".*"))
+	if err != nil {
+		return nil, err
+	}
+	files := make([]rotatedFile, 0, len(matches))
+	for _, path := range matches {
+		index, err := strconv.Atoi(strings.TrimPrefix(filepath.Ext(path), "."))
+		if err != nil || index < 1 {
+			continue
+		}
+		files = append(files, rotatedFile{path: path, index: index})
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].index < files[j].index })
+	return files, nil
+}
+
+// Read returns the entries of a VM matching q, oldest first.
+func (s *Store) Read(name string, q Query) ([]Entry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	dir := s.vmDir(name)
+	rotated, err := rotatedFiles(dir)
+	if err != nil {
+		return nil, err
+	}
+	paths := make([]string, 0, len(rotated)+1)
+	for i := len(rotated) - 1; i >= 0; i-- {
+		paths = append(paths, rotated[i].path)
+	}
+	paths = append(paths, filepath.Join(dir, currentFile))
+
+	entries := []Entry{}
+	for _, path := range paths {
+		if err := readFile(path, q, &entries); err != nil {
+			return nil, err
+		}
+	}
+	if q.Limit > 0 && len(entries) > q.Limit {
+		entries = entries[len(entries)-q.Limit:]
+	}
+	return entries, nil
+}
+
+func readFile(path string, q Query, entries *[]Entry) error {
+	file, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	defer file.Close()
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var entry Entry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			continue
+		}
+		if !q.matches(entry) {
+			continue
+		}
+		*entries = append(*entries, entry)
+		if q.Limit > 0 && len(*entries) > 2*q.Limit {
+			*entries = append((*entries)[:0], (*entries)[len(*entries)-q.Limit:]...)
+		}
+	}
+	return scanner.Err()
+}
+
+// Remove discards every log of a VM.
+func (s *Store) Remove(name string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.closeLocked(name)
+	return os.RemoveAll(s.vmDir(name))
+}
+
+// Prune removes log files last written before now minus MaxAge. Raw
+// hypervisor output is left to its follower.
+func (s *Store) Prune(now time.Time) {
+	if s.retention.MaxAge <= 0 {
+		return
+	}
+	cutoff := now.Add(-s.retention.MaxAge)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	dirs, err := os.ReadDir(s.dir)
+	if err != nil {
+		return
+	}
+	for _, dir := range dirs {
+		if !dir.IsDir() {
+			continue
+		}
+		name := dir.Name()
+		paths, _ := filepath.Glob(filepath.Join(s.vmDir(name), currentFile+"*"))
+		for _, path := range paths {
+			info, err := os.Stat(path)
+			if err != nil || !info.ModTime().Before(cutoff) {
+				continue
+			}
+			if filepath.Base(path) == currentFile {
+				s.closeLocked(name)
+			}
+			_ = os.Remove(path)
+		}
+	}
+}
+
+// Close closes the open log files.
+func (s *Store) Close() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for name := range s.files {
+		s.closeLocked(name)
+	}
+}
@@ -0,0 +1,464 @@
+// Copyright (c) 2025 HYPR. PTE. LTD.
+//
+// Business Source License 1.1
+// See LICENSE file in the project root for details.
+
+package orchestrator
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/volantvm/volant/internal/server/db"
+	"github.com/volantvm/volant/internal/server/labels"
+	"github.com/volantvm/volant/internal/server/orchestrator/vmconfig"
+)
+
+// WarmPoolLabel is set to the plugin on the unclaimed VMs a warm pool keeps
+// ready. Claiming a VM removes it, which takes the VM out of the pool.
+const WarmPoolLabel = "volant.pool"
+
+const (
+	warmPoolInterval    = 5 * time.Second
+	warmPoolConcurrency = 4
+)
+
+var (
+	// ErrWarmPoolNotFound indicates the plugin has no warm pool.
+	ErrWarmPoolNotFound = errors.New("orchestrator: warm pool not found")
+	// ErrInvalidWarmPool indicates the warm pool definition failed validation.
+	ErrInvalidWarmPool = errors.New("orchestrator: invalid warm pool")
+	// ErrWarmPoolEmpty indicates the warm pool has no ready VM to hand out;
+	// it is being replenished in the background.
+	ErrWarmPoolEmpty = errors.New("orchestrator: warm pool empty")
+)
+
+// WarmPool keeps Size booted, unclaimed VMs of a plugin ready for ClaimVM.
+type WarmPool struct {
+	Plugin string
+	Size   int
+	// Ready counts the pool's VMs that can be claimed now; Starting those
+	// still booting.
+	Ready     int
+	Starting  int
+	Config    vmconfig.Config
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// SetWarmPoolRequest creates or resizes the warm pool of a plugin.
+type SetWarmPoolRequest struct {
+	Plugin string
+	Size   int
+	// Config is what the pool's VMs are created from. Nil uses the plugin's
+	// manifest and default resources.
+	Config *vmconfig.Config
+}
+
+// ClaimVMRequest takes a ready VM out of the warm pool of Plugin.
+type ClaimVMRequest struct {
+	Plugin string
+	// Labels are merged into the claimed VM's labels.
+	Labels map[string]string
+}
+
+func (e *engine) WarmPools(ctx context.Context) ([]WarmPool, error) {
+	pools, err := e.store.Queries().WarmPools().List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]WarmPool, 0, len(pools))
+	for _, pool := range pools {
+		view, err := e.buildWarmPool(ctx, pool)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, view)
+	}
+	return out, nil
+}
+
+func (e *engine) GetWarmPool(ctx context.Context, plugin string) (*WarmPool, error) {
+	pool, err := e.store.Queries().WarmPools().GetByPlugin(ctx, plugin)
+	if err != nil {
+		return nil, err
+	}
+	if pool == nil {
+		return nil, fmt.Errorf("%w: %s", ErrWarmPoolNotFound, plugin)
+	}
+	view, err := e.buildWarmPool(ctx, *pool)
+	if err != nil {
+		return nil, err
+	}
+	return &view, nil
+}
+
+// SetWarmPool creates the warm pool of a plugin or changes its size and
+// config. Unclaimed VMs created from a previous config are replaced.
+func (e *engine) SetWarmPool(ctx context.Context, req SetWarmPoolRequest) (*WarmPool, error) {
+	plugin := strings.TrimSpace(req.Plugin)
+	if plugin == "" {
+		return nil, fmt.Errorf("%w: plugin required", ErrInvalidWarmPool)
+	}
+	if req.Size < 0 {
+		return nil, fmt.Errorf("%w: size must be >= 0", ErrInvalidWarmPool)
+	}
+	var cfg vmconfig.Config
+	if req.Config != nil {
+		cfg = req.Config.Clone()
+	}
+	if name := strings.TrimSpace(cfg.Plugin); name != "" && name != plugin {
+		return nil, fmt.Errorf("%w: config plugin %s does not match pool plugin %s", ErrInvalidWarmPool, name, plugin)
+	}
+	cfg.Plugin = plugin
+	if cfg.Manifest == nil {
+		manifest, err := e.installedManifest(ctx, plugin)
+		if err != nil {
+			return nil, err
+		}
+		cfg.Manifest = manifest
+	}
+	if cfg.Resources.CPUCores <= 0 {
+		cfg.Resources.CPUCores = cfg.Manifest.Resources.CPUCores
+	}
+	if cfg.Resources.MemoryMB <= 0 {
+		cfg.Resources.MemoryMB = cfg.Manifest.Resources.MemoryMB
+	}
+	config, err := e.normalizeDeploymentConfig(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+	payload, err := vmconfig.Marshal(config)
+	if err != nil {
+		return nil, err
+	}
+
+	e.warmMu.Lock()
+	var replaced bool
+	err = e.store.WithTx(ctx, func(q db.Queries) error {
+		repo := q.WarmPools()
+		existing, err := repo.GetByPlugin(ctx, plugin)
+		if err != nil {
+			return err
+		}
+		replaced = existing != nil && !bytes.Equal(existing.ConfigJSON, payload)
+		return repo.Upsert(ctx, &db.WarmPool{Plugin: plugin, Size: req.Size, ConfigJSON: payload})
+	})
+	if err == nil && replaced {
+		e.drainWarmPool(ctx, plugin)
+	}
+	e.warmMu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+	e.logger.Info("warm pool set", "plugin", plugin, "size", req.Size)
+	e.kickWarmPools()
+	return e.GetWarmPool(ctx, plugin)
+}
+
+// DeleteWarmPool removes the warm pool of a plugin and destroys its
+// unclaimed VMs. Claimed VMs are left alone.
+func (e *engine) DeleteWarmPool(ctx context.Context, plugin string) error {
+	e.warmMu.Lock()
+	defer e.warmMu.Unlock()
+	repo := e.store.Queries().WarmPools()
+	pool, err := repo.GetByPlugin(ctx, plugin)
+	if err != nil {
+		return err
+	}
+	if pool == nil {
+		return fmt.Errorf("%w: %s", ErrWarmPoolNotFound, plugin)
+	}
+	if err := repo.Delete(ctx, pool.ID); err != nil {
+		return err
+	}
+	e.drainWarmPool(ctx, plugin)
+	e.logger.Info("warm pool deleted", "plugin", plugin)
+	return nil
+}
+
+// ClaimVM hands out a running, ready VM from the warm pool of req.Plugin.
+// The VM keeps its name; its pool label is replaced by req.Labels, and the
+// pool is replenished in the background.
+func (e *engine) ClaimVM(ctx context.Context, req ClaimVMRequest) (*db.VM, error) {
+	plugin := strings.TrimSpace(req.Plugin)
+	if plugin == "" {
+		return nil, fmt.Errorf("%w: plugin required", ErrInvalidWarmPool)
+	}
+	pool, err := e.store.Queries().WarmPools().GetByPlugin(ctx, plugin)
+	if err != nil {
+		return nil, err
+	}
+	if pool == nil {
+		return nil, fmt.Errorf("%w: %s", ErrWarmPoolNotFound, plugin)
+	}
+
+	e.warmMu.Lock()
+	defer e.warmMu.Unlock()
+	vms, err := e.warmPoolVMs(ctx, plugin)
+	if err != nil {
+		return nil, err
+	}
+	var claimed *db.VM
+	for _, vm := range vms {
+		if vm.Status != db.VMStatusRunning || !e.isReady(vm.Name) {
+			continue
+		}
+		claimed, err = e.claimWarmVM(ctx, vm.Name, plugin, req.Labels)
+		if err != nil {
+			return nil, err
+		}
+		if claimed != nil {
+			break
+		}
+	}
+	e.kickWarmPools()
+	if claimed == nil {
+		return nil, fmt.Errorf("%w: %s", ErrWarmPoolEmpty, plugin)
+	}
+	e.refreshLogTarget(*claimed)
+	e.logger.Info("vm claimed from warm pool", "vm", claimed.Name, "plugin", plugin)
+	return claimed, nil
+}
+
+// claimWarmVM removes the pool label from name and merges extra into its
+// labels. It returns nil when the VM has left the pool in the meantime.
+func (e *engine) claimWarmVM(ctx context.Context, name, plugin string, extra map[string]string) (*db.VM, error) {
+	var claimed *db.VM
+	err := e.store.WithTx(ctx, func(q db.Queries) error {
+		vmRepo := q.VirtualMachines()
+		vm, err := vmRepo.GetByName(ctx, name)
+		if err != nil || vm == nil || vm.Labels[WarmPoolLabel] != plugin {
+			return err
+		}
+		next := labels.Merge(vm.Labels, extra)
+		delete(next, WarmPoolLabel)
+		if err := labels.Validate(next); err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidLabels, err)
+		}
+		if err := vmRepo.SetLabels(ctx, vm.ID, next); err != nil {
+			return err
+		}
+		// The TTL of a pooled VM starts with its claim.
+		if record, err := q.VMConfigs().GetCurrent(ctx, vm.ID); err == nil && record != nil {
+			if versioned, err := vmconfig.FromDB(*record); err == nil {
+				if expiresAt := ttlDeadline(versioned.Config, time.Now()); expiresAt != nil {
+					if err := vmRepo.SetExpiresAt(ctx, vm.ID, expiresAt); err != nil {
+						return err
+					}
+				}
+			}
+		}
+		claimed, err = vmRepo.GetByName(ctx, name)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return claimed, nil
+}
+
+func (e *engine) buildWarmPool(ctx context.Context, pool db.WarmPool) (WarmPool, error) {
+	config, err := vmconfig.Unmarshal(pool.ConfigJSON)
+	if err != nil {
+		return WarmPool{}, err
+	}
+	vms, err := e.warmPoolVMs(ctx, pool.Plugin)
+	if err != nil {
+		return WarmPool{}, err
+	}
+	view := WarmPool{
+		Plugin:    pool.Plugin,
+		Size:      pool.Size,
+		Config:    config,
+		CreatedAt: pool.CreatedAt,
+		UpdatedAt: pool.UpdatedAt,
+	}
+	for _, vm := range vms {
+		switch {
+		case vm.Status == db.VMStatusRunning && e.isReady(vm.Name):
+			view.Ready++
+		case vm.Status == db.VMStatusRunning || vm.Status == db.VMStatusStarting:
+			view.Starting++
+		}
+	}
+	return view, nil
+}
+
+// warmPoolVMs lists the unclaimed VMs of a plugin's pool, oldest first.
+func (e *engine) warmPoolVMs(ctx context.Context, plugin string) ([]db.VM, error) {
+	vms, _, err := e.store.Queries().VirtualMachines().ListPage(ctx, db.VMListOptions{
+		Selector: labels.Selector{{Key: WarmPoolLabel, Operator: labels.Equals, Value: plugin}},
+		SortBy:   db.VMSortCreatedAt,
+		Limit:    -1,
+	})
+	return vms, err
+}
+
+// drainWarmPool destroys the unclaimed VMs of a plugin's pool. Callers hold
+// warmMu.
+func (e *engine) drainWarmPool(ctx context.Context, plugin string) {
+	vms, err := e.warmPoolVMs(ctx, plugin)
+	if err != nil {
+		e.logger.Error("list warm pool vms", "plugin", plugin, "error", err)
+		return
+	}
+	for _, vm := range vms {
+		if err := e.DestroyVM(ctx, vm.Name); err != nil && !errors.Is(err, ErrVMNotFound) {
+			e.logger.Error("destroy warm pool vm", "plugin", plugin, "vm", vm.Name, "error", err)
+		}
+	}
+}
+
+// kickWarmPools wakes the replenish loop without waiting for it.
+func (e *engine) kickWarmPools() {
+	select {
+	case e.warmKick <- struct{}{}:
+	default:
+	}
+}
+
+// runWarmPools replenishes the warm pools periodically and whenever a VM was
+// claimed or a pool changed.
+func (e *engine) runWarmPools(ctx context.Context) {
+	ticker := time.NewTicker(warmPoolInterval)
+	defer ticker.Stop()
+	for {
+		e.replenishWarmPools(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-e.warmKick:
+		}
+	}
+}
+
+// replenishWarmPools brings every pool back to its size. Nothing is created
+// while the host is in maintenance or a window covering the plugin is open.
+func (e *engine) replenishWarmPools(ctx context.Context) {
+	pools, err := e.store.Queries().WarmPools().List(ctx)
+	if err != nil {
+		e.logger.Error("list warm pools", "error", err)
+		return
+	}
+	if len(pools) == 0 || e.checkHostMaintenance(ctx) != nil {
+		return
+	}
+	windows, err := e.store.Queries().Maintenance().List(ctx)
+	if err != nil {
+		e.logger.Error("list maintenance windows for warm pools", "error", err)
+		return
+	}
+	now := time.Now().UTC()
+	for _, pool := range pools {
+		targets := []MaintenanceTarget{{Kind: MaintenanceScopePlugin, Name: pool.Plugin}}
+		held := false
+		for _, window := range windows {
+			if MaintenanceWindowOpen(window, now) && maintenanceWindowCovers(window, targets) {
+				held = true
+				break
+			}
+		}
+		if !held {
+			e.replenishWarmPool(ctx, pool)
+		}
+	}
+}
+
+func (e *engine) replenishWarmPool(ctx context.Context, pool db.WarmPool) {
+	config, err := vmconfig.Unmarshal(pool.ConfigJSON)
+	if err != nil {
+		e.logger.Error("decode warm pool config", "plugin", pool.Plugin, "error", err)
+		return
+	}
+
+	e.warmMu.Lock()
+	vms, err := e.warmPoolVMs(ctx, pool.Plugin)
+	if err != nil {
+		e.warmMu.Unlock()
+		e.logger.Error("list warm pool vms", "plugin", pool.Plugin, "error", err)
+		return
+	}
+	// Pool VMs that exited are replaced rather than restarted, so a claim
+	// always gets a freshly booted VM.
+	var live, spare []string
+	for _, vm := range vms {
+		switch {
+		case e.hasInstance(vm.Name) || vm.Status == db.VMStatusStarting:
+			if vm.Status == db.VMStatusRunning && e.isReady(vm.Name) {
+				live = append(live, vm.Name)
+			} else {
+				// Booting VMs are trimmed first when the pool shrinks.
+				spare = append(spare, vm.Name)
+			}
+		default:
+			if err := e.DestroyVM(ctx, vm.Name); err != nil && !errors.Is(err, ErrVMNotFound) {
+				e.logger.Error("destroy exited warm pool vm", "plugin", pool.Plugin, "vm", vm.Name, "error", err)
+			}
+		}
+	}
+	live = append(spare, live...)
+	for len(live) > pool.Size {
+		name := live[0]
+		live = live[1:]
+		if err := e.DestroyVM(ctx, name); err != nil && !errors.Is(err, ErrVMNotFound) {
+			e.logger.Error("trim warm pool vm", "plugin", pool.Plugin, "vm", name, "error", err)
+		}
+	}
+	e.warmMu.Unlock()
+
+	missing := pool.Size - len(live)
+	if missing <= 0 {
+		return
+	}
+	// Requests are built up front: normalizing a config clone writes to the
+	// manifest it shares with config.
+	names := make([]string, missing)
+	requests := make(map[string]CreateVMRequest, missing)
+	for i := range names {
+		names[i] = warmPoolVMName(pool.Plugin)
+		requests[names[i]] = warmPoolRequest(names[i], config)
+	}
+	e.logger.Info("replenishing warm pool", "plugin", pool.Plugin, "vms", missing)
+	forEachLimited(names, warmPoolConcurrency, func(name string) {
+		if _, err := e.CreateVM(ctx, requests[name]); err != nil {
+			e.logger.Error("create warm pool vm", "plugin", pool.Plugin, "vm", name, "error", err)
+		}
+	})
+}
+
+// warmPoolRequest builds the request creating pool VM name from config.
+func warmPoolRequest(name string, config vmconfig.Config) CreateVMRequest {
+	cfg := config.Clone()
+	cfg.Normalize()
+	manifest := *cfg.Manifest
+	manifest.Normalize()
+	return CreateVMRequest{
+		Name:              name,
+		Plugin:            cfg.Plugin,
+		Runtime:           cfg.Runtime,
+		CPUCores:          cfg.Resources.CPUCores,
+		MemoryMB:          cfg.Resources.MemoryMB,
+		KernelCmdlineHint: cfg.KernelCmdline,
+		Manifest:          &manifest,
+		APIHost:           cfg.API.Host,
+		APIPort:           cfg.API.Port,
+		Config:            &cfg,
+		Labels:            map[string]string{WarmPoolLabel: cfg.Plugin},
+	}
+}
+
+// warmPoolVMName returns a fresh name for a pool VM. Claimed VMs keep it:
+// a running VM's name is bound to its tap, sockets and seed.
+func warmPoolVMName(plugin string) string {
+	var buf [3]byte
+	_, _ = rand.Read(buf[:])
+	return plugin + "-" + hex.EncodeToString(buf[:])
+}
@@ -10,12 +10,21 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"net/http"
+	"strings"
 	"sync"
 
 	"github.com/volantvm/volant/internal/pluginspec"
 	"github.com/volantvm/volant/internal/server/db"
 )
 
+var (
+	// ErrPluginNotFound indicates no plugin with the name is installed.
+	ErrPluginNotFound = errors.New("plugins: plugin not found")
+	// ErrActionNotFound indicates the plugin declares no such action.
+	ErrActionNotFound = errors.New("plugins: action not found")
+)
+
 // Registry manages plugin manifests at runtime.
 type Registry struct {
 	mu        sync.RWMutex
@@ -54,15 +63,41 @@ func (r *Registry) List() []string {
 func (r *Registry) ResolveAction(plugin, action string) (pluginspec.Manifest, pluginspec.Action, error) {
 	manifest, ok := r.Get(plugin)
 	if !ok {
-		return pluginspec.Manifest{}, pluginspec.Action{}, fmt.Errorf("plugin %s not found", plugin)
+		return pluginspec.Manifest{}, pluginspec.Action{}, fmt.Errorf("%w: %s", ErrPluginNotFound, plugin)
 	}
 	actionSpec, ok := manifest.Actions[action]
 	if !ok {
-		return pluginspec.Manifest{}, pluginspec.Action{}, fmt.Errorf("action %s not found", action)
+		return pluginspec.Manifest{}, pluginspec.Action{}, fmt.Errorf("%w: %s", ErrActionNotFound, action)
 	}
 	return manifest, actionSpec, nil
 }
 
+// MatchAction finds the action of a plugin for runtime that is served at
+// method and path, so requests proxied to an agent get the action's policy.
+func (r *Registry) MatchAction(runtime, method, path string) (pluginspec.Action, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	for _, manifest := range r.manifests {
+		if manifest.Runtime != runtime {
+			continue
+		}
+		for _, action := range manifest.Actions {
+			actionMethod := strings.ToUpper(strings.TrimSpace(action.Method))
+			if actionMethod == "" {
+				actionMethod = http.MethodPost
+			}
+			actionPath := strings.TrimSpace(action.Path)
+			if !strings.HasPrefix(actionPath, "/") {
+				actionPath = "/" + actionPath
+			}
+			if actionMethod == method && actionPath == path {
+				return action, true
+			}
+		}
+	}
+	return pluginspec.Action{}, false
+}
+
 func (r *Registry) Fetch(ctx context.Context, name string) (pluginspec.Manifest, error) {
 	if r.backend == nil {
 		return pluginspec.Manifest{}, errors.New("registry backend not configured")
@@ -72,7 +107,7 @@ func (r *Registry) Fetch(ctx context.Context, name string) (pluginspec.Manifest,
 		return pluginspec.Manifest{}, err
 	}
 	if plugin == nil {
-		return pluginspec.Manifest{}, fmt.Errorf("plugin %s not found", name)
+		return pluginspec.Manifest{}, fmt.Errorf("%w: %s", ErrPluginNotFound, name)
 	}
 	var manifest pluginspec.Manifest
 	if len(plugin.Metadata) > 0 {
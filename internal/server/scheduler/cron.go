@@ -0,0 +1,186 @@
+// Copyright (c) 2025 HYPR. PTE. LTD.
+//
+// Business Source License 1.1
+// See LICENSE file in the project root for details.
+
+package scheduler
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// cronSearchLimit bounds how far ahead Next looks before giving up on an
+// expression that can never fire (e.g. 30 February).
+const cronSearchLimit = 5 * 366 * 24 * time.Hour
+
+var cronMacros = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+var monthNames = map[string]int{
+	"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6,
+	"jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12,
+}
+
+var weekdayNames = map[string]int{
+	"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6,
+}
+
+// CronSpec is a parsed five-field cron expression:
+// minute hour day-of-month month day-of-week.
+type CronSpec struct {
+	minute, hour, dom, month, dow uint64
+	domAny, dowAny                bool
+}
+
+// ParseCron parses a standard five-field cron expression. Fields accept `*`,
+// lists, ranges and steps; months and weekdays also accept three-letter names.
+// The @hourly, @daily, @weekly, @monthly and @yearly macros are supported.
+func ParseCron(expr string) (CronSpec, error) {
+	expr = strings.TrimSpace(expr)
+	if macro, ok := cronMacros[strings.ToLower(expr)]; ok {
+		expr = macro
+	}
+	fields := strings.Fields(expr)
+	if len(fields) != 5 {
+		return CronSpec{}, fmt.Errorf("cron %q: expected 5 fields, got %d", expr, len(fields))
+	}
+
+	var (
+		spec CronSpec
+		err  error
+	)
+	if spec.minute, err = parseCronField(fields[0], 0, 59, nil); err != nil {
+		return CronSpec{}, fmt.Errorf("cron %q minute: %w", expr, err)
+	}
+	if spec.hour, err = parseCronField(fields[1], 0, 23, nil); err != nil {
+		return CronSpec{}, fmt.Errorf("cron %q hour: %w", expr, err)
+	}
+	if spec.dom, err = parseCronField(fields[2], 1, 31, nil); err != nil {
+		return CronSpec{}, fmt.Errorf("cron %q day of month: %w", expr, err)
+	}
+	if spec.month, err = parseCronField(fields[3], 1, 12, monthNames); err != nil {
+		return CronSpec{}, fmt.Errorf("cron %q month: %w", expr, err)
+	}
+	if spec.dow, err = parseCronField(fields[4], 0, 7, weekdayNames); err != nil {
+		return CronSpec{}, fmt.Errorf("cron %q day of week: %w", expr, err)
+	}
+	// 7 is an alias for Sunday.
+	if spec.dow&(1<<7) != 0 {
+		spec.dow |= 1
+	}
+	spec.domAny = fields[2] == "*"
+	spec.dowAny = fields[4] == "*"
+	return spec, nil
+}
+
+func parseCronField(field string, min, max int, names map[string]int) (uint64, error) {
+	var bits uint64
+	for _, part := range strings.Split(field, ",") {
+		rangePart, stepPart, hasStep := strings.Cut(part, "/")
+		step := 1
+		if hasStep {
+			value, err := strconv.Atoi(stepPart)
+			if err != nil || value <= 0 {
+				return 0, fmt.Errorf("invalid step %q", stepPart)
+			}
+			step = value
+		}
+
+		lo, hi := min, max
+		switch {
+		case rangePart == "*":
+		case strings.Contains(rangePart, "-"):
+			loRaw, hiRaw, _ := strings.Cut(rangePart, "-")
+			var err error
+			if lo, err = parseCronValue(loRaw, names); err != nil {
+				return 0, err
+			}
+			if hi, err = parseCronValue(hiRaw, names); err != nil {
+				return 0, err
+			}
+		default:
+			value, err := parseCronValue(rangePart, names)
+			if err != nil {
+				return 0, err
+			}
+			lo = value
+			if !hasStep {
+				hi = value
+			}
+		}
+		if lo < min || hi > max || lo > hi {
+			return 0, fmt.Errorf("value %q out of range %d-%d", part, min, max)
+		}
+		for v := lo; v <= hi; v += step {
+			bits |= 1 << uint(v)
+		}
+	}
+	return bits, nil
+}
+
+func parseCronValue(raw string, names map[string]int) (int, error) {
+	if value, ok := names[strings.ToLower(raw)]; ok {
+		return value, nil
+	}
+	value, err := strconv.Atoi(raw)
+	if err != nil {
+		return 0, fmt.Errorf("invalid value %q", raw)
+	}
+	return value, nil
+}
+
+// Next returns the first activation strictly after t, in t's location. It
+// returns the zero time when the expression never fires.
+func (s CronSpec) Next(t time.Time) time.Time {
+	loc := t.Location()
+	next := t.Truncate(time.Minute).Add(time.Minute)
+	limit := t.Add(cronSearchLimit)
+
+	for next.Before(limit) {
+		if s.month&(1<<uint(next.Month())) == 0 {
+			next = time.Date(next.Year(), next.Month()+1, 1, 0, 0, 0, 0, loc)
+			continue
+		}
+		if !s.dayMatches(next) {
+			next = time.Date(next.Year(), next.Month(), next.Day()+1, 0, 0, 0, 0, loc)
+			continue
+		}
+		if s.hour&(1<<uint(next.Hour())) == 0 {
+			next = time.Date(next.Year(), next.Month(), next.Day(), next.Hour()+1, 0, 0, 0, loc)
+			continue
+		}
+		if s.minute&(1<<uint(next.Minute())) == 0 {
+			next = next.Add(time.Minute)
+			continue
+		}
+		return next
+	}
+	return time.Time{}
+}
+
+// dayMatches follows cron semantics: when both day fields are restricted a
+// day matching either one fires.
+func (s CronSpec) dayMatches(t time.Time) bool {
+	domMatch := s.dom&(1<<uint(t.Day())) != 0
+	dowMatch := s.dow&(1<<uint(t.Weekday())) != 0
+	switch {
+	case s.domAny && s.dowAny:
+		return true
+	case s.domAny:
+		return dowMatch
+	case s.dowAny:
+		return domMatch
+	default:
+		return domMatch || dowMatch
+	}
+}
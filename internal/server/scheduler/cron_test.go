@@ -0,0 +1,44 @@
+// Copyright (c) 2025 HYPR. PTE. LTD.
+//
+// Business Source License 1.1
+// See LICENSE file in the project root for details.
+
+package scheduler
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCronNext(t *testing.T) {
+	base := time.Date(2025, time.March, 14, 10, 30, 0, 0, time.UTC) // Friday
+	cases := []struct {
+		expr string
+		want time.Time
+	}{
+		{"*/15 * * * *", time.Date(2025, time.March, 14, 10, 45, 0, 0, time.UTC)},
+		{"@hourly", time.Date(2025, time.March, 14, 11, 0, 0, 0, time.UTC)},
+		{"0 8 * * 1-5", time.Date(2025, time.March, 17, 8, 0, 0, 0, time.UTC)},
+		{"0 22 * * *", time.Date(2025, time.March, 14, 22, 0, 0, 0, time.UTC)},
+		{"0 0 1 jan *", time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC)},
+		{"30 9 15 * sun", time.Date(2025, time.March, 15, 9, 30, 0, 0, time.UTC)},
+		{"0 0 * * 7", time.Date(2025, time.March, 16, 0, 0, 0, 0, time.UTC)},
+	}
+	for _, tc := range cases {
+		spec, err := ParseCron(tc.expr)
+		if err != nil {
+			t.Fatalf("parse %q: %v", tc.expr, err)
+		}
+		if got := spec.Next(base); !got.Equal(tc.want) {
+			t.Errorf("%q: next = %s, want %s", tc.expr, got, tc.want)
+		}
+	}
+}
+
+func TestParseCronRejectsInvalid(t *testing.T) {
+	for _, expr := range []string{"", "* * * *", "60 * * * *", "* 24 * * *", "*/0 * * * *", "5-1 * * * *", "* * * foo *"} {
+		if _, err := ParseCron(expr); err == nil {
+			t.Errorf("expected error for %q", expr)
+		}
+	}
+}
@@ -0,0 +1,509 @@
+// Copyright (c) 2025 HYPR. PTE. LTD.
+//
+// Business Source License 1.1
+// See LICENSE file in the project root for details.
+
+// Package scheduler runs cron-style lifecycle rules (start, stop, restart,
+// snapshot, scale) against VMs, deployments and plugin fleets.
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/volantvm/volant/internal/server/db"
+	"github.com/volantvm/volant/internal/server/eventbus"
+	"github.com/volantvm/volant/internal/server/orchestrator"
+)
+
+// Supported schedule actions.
+const (
+	ActionStart    = "start"
+	ActionStop     = "stop"
+	ActionRestart  = "restart"
+	ActionSnapshot = "snapshot"
+	ActionScale    = "scale"
+)
+
+// Supported schedule target kinds.
+const (
+	TargetVM         = "vm"
+	TargetDeployment = "deployment"
+	TargetPlugin     = "plugin"
+)
+
+// Run statuses recorded in schedule history.
+const (
+	RunSucceeded = "succeeded"
+	RunFailed    = "failed"
+)
+
+// TopicScheduleEvents is the event bus topic for schedule executions.
+const TopicScheduleEvents = "scheduler.events"
+
+// Event types published on TopicScheduleEvents.
+const (
+	TypeScheduleSucceeded = "SCHEDULE_SUCCEEDED"
+	TypeScheduleFailed    = "SCHEDULE_FAILED"
+)
+
+const tickInterval = 10 * time.Second
+
+var (
+	// ErrScheduleNotFound indicates the requested schedule does not exist.
+	ErrScheduleNotFound = errors.New("scheduler: schedule not found")
+	// ErrScheduleExists indicates a schedule with the same name already exists.
+	ErrScheduleExists = errors.New("scheduler: schedule already exists")
+	// ErrInvalidSchedule indicates the schedule definition failed validation.
+	ErrInvalidSchedule = errors.New("scheduler: invalid schedule")
+)
+
+// Event describes the outcome of a schedule execution.
+type Event struct {
+	Type       string    `json:"type"`
+	Schedule   string    `json:"schedule"`
+	Action     string    `json:"action"`
+	TargetKind string    `json:"target_kind"`
+	Target     string    `json:"target"`
+	Status     string    `json:"status"`
+	Message    string    `json:"message,omitempty"`
+	Timestamp  time.Time `json:"timestamp"`
+}
+
+// CreateRequest defines a new schedule.
+type CreateRequest struct {
+	Name       string
+	Cron       string
+	Action     string
+	TargetKind string
+	Target     string
+	// Replicas is the desired replica count for scale actions.
+	Replicas *int
+	Disabled bool
+}
+
+// Scheduler evaluates stored schedules and applies their actions through the
+// orchestrator engine.
+type Scheduler struct {
+	engine orchestrator.Engine
+	bus    eventbus.Bus
+	logger *slog.Logger
+	now    func() time.Time
+
+	mu      sync.Mutex
+	next    map[int64]time.Time
+	running map[int64]struct{}
+}
+
+// New constructs a scheduler bound to the engine's store.
+func New(engine orchestrator.Engine, bus eventbus.Bus, logger *slog.Logger) *Scheduler {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &Scheduler{
+		engine:  engine,
+		bus:     bus,
+		logger:  logger.With("component", "scheduler"),
+		now:     time.Now,
+		next:    make(map[int64]time.Time),
+		running: make(map[int64]struct{}),
+	}
+}
+
+// Run evaluates schedules until ctx is cancelled. Activations missed while the
+// daemon was down are not replayed; activations held by a maintenance window
+// are, once the window closes.
+func (s *Scheduler) Run(ctx context.Context) {
+	ticker := time.NewTicker(tickInterval)
+	defer ticker.Stop()
+	for {
+		now := s.now()
+		s.replayHeld(ctx, now)
+		s.tick(ctx, now)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *Scheduler) tick(ctx context.Context, now time.Time) {
+	schedules, err := s.store().Queries().Schedules().List(ctx)
+	if err != nil {
+		s.logger.Error("list schedules", "error", err)
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	seen := make(map[int64]struct{}, len(schedules))
+	for _, schedule := range schedules {
+		if !schedule.Enabled {
+			continue
+		}
+		seen[schedule.ID] = struct{}{}
+		spec, err := ParseCron(schedule.Cron)
+		if err != nil {
+			continue
+		}
+		next, ok := s.next[schedule.ID]
+		if !ok {
+			s.next[schedule.ID] = spec.Next(now)
+			continue
+		}
+		if next.IsZero() || now.Before(next) {
+			continue
+		}
+		s.next[schedule.ID] = spec.Next(now)
+		if _, busy := s.running[schedule.ID]; busy {
+			s.logger.Warn("skip schedule, previous run still active", "schedule", schedule.Name)
+			continue
+		}
+		s.launch(ctx, schedule)
+	}
+	for id := range s.next {
+		if _, ok := seen[id]; !ok {
+			delete(s.next, id)
+		}
+	}
+}
+
+// launch runs the schedule in the background unless a maintenance window
+// covering its target is open. Callers hold s.mu.
+func (s *Scheduler) launch(ctx context.Context, schedule db.Schedule) {
+	s.running[schedule.ID] = struct{}{}
+	go func() {
+		defer func() {
+			s.mu.Lock()
+			delete(s.running, schedule.ID)
+			s.mu.Unlock()
+		}()
+		targets := []orchestrator.MaintenanceTarget{{Kind: schedule.TargetKind, Name: schedule.Target}}
+		held, err := s.engine.HoldForMaintenance(ctx, orchestrator.MaintenanceSourceSchedule, schedule.Name, schedule.Action, targets)
+		if err != nil {
+			s.logger.Error("check maintenance windows", "schedule", schedule.Name, "error", err)
+		}
+		if held {
+			return
+		}
+		s.execute(ctx, schedule)
+	}()
+}
+
+// replayHeld runs schedule activations that maintenance windows held back,
+// once per schedule however often it fired during the window.
+func (s *Scheduler) replayHeld(ctx context.Context, now time.Time) {
+	due, err := orchestrator.DueMaintenanceIntents(ctx, s.store(), orchestrator.MaintenanceSourceSchedule, now)
+	if err != nil {
+		s.logger.Error("list held schedule runs", "error", err)
+		return
+	}
+	repo := s.store().Queries().Maintenance()
+	for _, intent := range due {
+		schedule, err := s.store().Queries().Schedules().GetByName(ctx, intent.Target)
+		if err != nil {
+			s.logger.Error("replay held schedule", "schedule", intent.Target, "error", err)
+			continue
+		}
+		outcome := orchestrator.IntentReplayed
+		s.mu.Lock()
+		switch {
+		case schedule == nil:
+			outcome = orchestrator.IntentSkipped + ": schedule removed"
+		case !schedule.Enabled:
+			outcome = orchestrator.IntentSkipped + ": schedule disabled"
+		default:
+			if _, busy := s.running[schedule.ID]; busy {
+				s.mu.Unlock()
+				continue
+			}
+			s.launch(ctx, *schedule)
+		}
+		s.mu.Unlock()
+		if err := repo.MarkReplayed(ctx, intent.ID, outcome, now); err != nil {
+			s.logger.Error("mark held schedule replayed", "schedule", intent.Target, "error", err)
+			continue
+		}
+		s.logger.Info("replayed held schedule", "schedule", intent.Target, "outcome", outcome)
+	}
+}
+
+// Create validates and stores a new schedule.
+func (s *Scheduler) Create(ctx context.Context, req CreateRequest) (*db.Schedule, error) {
+	schedule := db.Schedule{
+		Name:       strings.TrimSpace(req.Name),
+		Cron:       strings.TrimSpace(req.Cron),
+		Action:     strings.ToLower(strings.TrimSpace(req.Action)),
+		TargetKind: strings.ToLower(strings.TrimSpace(req.TargetKind)),
+		Target:     strings.TrimSpace(req.Target),
+		Replicas:   req.Replicas,
+		Enabled:    !req.Disabled,
+	}
+	if err := validate(schedule); err != nil {
+		return nil, err
+	}
+
+	var created *db.Schedule
+	err := s.store().WithTx(ctx, func(q db.Queries) error {
+		existing, err := q.Schedules().GetByName(ctx, schedule.Name)
+		if err != nil {
+			return err
+		}
+		if existing != nil {
+			return fmt.Errorf("%w: %s", ErrScheduleExists, schedule.Name)
+		}
+		if _, err := q.Schedules().Create(ctx, &schedule); err != nil {
+			return err
+		}
+		created, err = q.Schedules().GetByName(ctx, schedule.Name)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return created, nil
+}
+
+func validate(schedule db.Schedule) error {
+	if schedule.Name == "" {
+		return fmt.Errorf("%w: name is required", ErrInvalidSchedule)
+	}
+	if _, err := ParseCron(schedule.Cron); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidSchedule, err)
+	}
+	switch schedule.TargetKind {
+	case TargetVM, TargetDeployment, TargetPlugin:
+	default:
+		return fmt.Errorf("%w: unknown target kind %q", ErrInvalidSchedule, schedule.TargetKind)
+	}
+	if schedule.Target == "" {
+		return fmt.Errorf("%w: target is required", ErrInvalidSchedule)
+	}
+	switch schedule.Action {
+	case ActionStart, ActionStop, ActionRestart, ActionSnapshot:
+		if schedule.Replicas != nil {
+			return fmt.Errorf("%w: replicas only applies to scale actions", ErrInvalidSchedule)
+		}
+	case ActionScale:
+		if schedule.TargetKind != TargetDeployment {
+			return fmt.Errorf("%w: scale requires a deployment target", ErrInvalidSchedule)
+		}
+		if schedule.Replicas == nil || *schedule.Replicas < 0 {
+			return fmt.Errorf("%w: scale requires replicas >= 0", ErrInvalidSchedule)
+		}
+	default:
+		return fmt.Errorf("%w: unknown action %q", ErrInvalidSchedule, schedule.Action)
+	}
+	return nil
+}
+
+// List returns all schedules ordered by name.
+func (s *Scheduler) List(ctx context.Context) ([]db.Schedule, error) {
+	return s.store().Queries().Schedules().List(ctx)
+}
+
+// Get returns the named schedule.
+func (s *Scheduler) Get(ctx context.Context, name string) (*db.Schedule, error) {
+	schedule, err := s.store().Queries().Schedules().GetByName(ctx, strings.TrimSpace(name))
+	if err != nil {
+		return nil, err
+	}
+	if schedule == nil {
+		return nil, fmt.Errorf("%w: %s", ErrScheduleNotFound, name)
+	}
+	return schedule, nil
+}
+
+// Delete removes the named schedule and its history.
+func (s *Scheduler) Delete(ctx context.Context, name string) error {
+	schedule, err := s.Get(ctx, name)
+	if err != nil {
+		return err
+	}
+	return s.store().Queries().Schedules().Delete(ctx, schedule.ID)
+}
+
+// SetEnabled pauses or resumes the named schedule.
+func (s *Scheduler) SetEnabled(ctx context.Context, name string, enabled bool) (*db.Schedule, error) {
+	schedule, err := s.Get(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.store().Queries().Schedules().SetEnabled(ctx, schedule.ID, enabled); err != nil {
+		return nil, err
+	}
+	return s.Get(ctx, name)
+}
+
+// History returns the most recent executions of the named schedule.
+func (s *Scheduler) History(ctx context.Context, name string, limit int) ([]db.ScheduleRun, error) {
+	schedule, err := s.Get(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	return s.store().Queries().Schedules().Runs(ctx, schedule.ID, limit)
+}
+
+// Trigger executes the named schedule immediately, regardless of its cron
+// expression or enabled state.
+func (s *Scheduler) Trigger(ctx context.Context, name string) (*db.ScheduleRun, error) {
+	schedule, err := s.Get(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	run := s.execute(ctx, *schedule)
+	return &run, nil
+}
+
+// NextRun reports when the schedule fires next, or the zero time when it is
+// disabled or never fires.
+func (s *Scheduler) NextRun(schedule db.Schedule) time.Time {
+	if !schedule.Enabled {
+		return time.Time{}
+	}
+	s.mu.Lock()
+	next, ok := s.next[schedule.ID]
+	s.mu.Unlock()
+	if ok {
+		return next
+	}
+	spec, err := ParseCron(schedule.Cron)
+	if err != nil {
+		return time.Time{}
+	}
+	return spec.Next(s.now())
+}
+
+func (s *Scheduler) execute(ctx context.Context, schedule db.Schedule) db.ScheduleRun {
+	run := db.ScheduleRun{ScheduleID: schedule.ID, StartedAt: s.now().UTC()}
+	err := s.apply(ctx, schedule)
+	run.FinishedAt = s.now().UTC()
+
+	event := Event{
+		Type:       TypeScheduleSucceeded,
+		Schedule:   schedule.Name,
+		Action:     schedule.Action,
+		TargetKind: schedule.TargetKind,
+		Target:     schedule.Target,
+		Status:     RunSucceeded,
+		Timestamp:  run.FinishedAt,
+	}
+	if err != nil {
+		run.Status = RunFailed
+		run.Message = err.Error()
+		event.Type = TypeScheduleFailed
+		event.Status = RunFailed
+		event.Message = run.Message
+		s.logger.Error("schedule failed", "schedule", schedule.Name, "action", schedule.Action, "target", schedule.Target, "error", err)
+	} else {
+		run.Status = RunSucceeded
+		s.logger.Info("schedule executed", "schedule", schedule.Name, "action", schedule.Action, "target", schedule.Target)
+	}
+
+	id, recordErr := s.store().Queries().Schedules().RecordRun(ctx, run)
+	if recordErr != nil {
+		s.logger.Error("record schedule run", "schedule", schedule.Name, "error", recordErr)
+	}
+	run.ID = id
+
+	if s.bus != nil {
+		if pubErr := s.bus.Publish(ctx, TopicScheduleEvents, event); pubErr != nil {
+			s.logger.Error("publish schedule event", "schedule", schedule.Name, "error", pubErr)
+		}
+	}
+	return run
+}
+
+func (s *Scheduler) apply(ctx context.Context, schedule db.Schedule) error {
+	if schedule.Action == ActionScale {
+		if schedule.Replicas == nil {
+			return fmt.Errorf("scale schedule %s missing replicas", schedule.Name)
+		}
+		_, err := s.engine.ScaleDeployment(ctx, schedule.Target, *schedule.Replicas)
+		return err
+	}
+
+	vms, err := s.targetVMs(ctx, schedule)
+	if err != nil {
+		return err
+	}
+	var errs []error
+	for _, vm := range vms {
+		if err := s.applyToVM(ctx, schedule.Action, vm); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", vm.Name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (s *Scheduler) applyToVM(ctx context.Context, action string, vm db.VM) error {
+	var err error
+	switch action {
+	case ActionStart:
+		if vm.Status == db.VMStatusRunning {
+			return nil
+		}
+		_, err = s.engine.StartVM(ctx, vm.Name)
+	case ActionStop:
+		if vm.Status == db.VMStatusStopped {
+			return nil
+		}
+		_, err = s.engine.StopVM(ctx, vm.Name)
+	case ActionRestart:
+		_, err = s.engine.RestartVM(ctx, vm.Name)
+	case ActionSnapshot:
+		_, err = s.engine.SnapshotVM(ctx, vm.Name)
+	default:
+		err = fmt.Errorf("unsupported action %q", action)
+	}
+	return err
+}
+
+func (s *Scheduler) targetVMs(ctx context.Context, schedule db.Schedule) ([]db.VM, error) {
+	switch schedule.TargetKind {
+	case TargetVM:
+		vm, err := s.engine.GetVM(ctx, schedule.Target)
+		if err != nil {
+			return nil, err
+		}
+		if vm == nil {
+			return nil, fmt.Errorf("%w: %s", orchestrator.ErrVMNotFound, schedule.Target)
+		}
+		return []db.VM{*vm}, nil
+	case TargetDeployment:
+		q := s.store().Queries()
+		group, err := q.VMGroups().GetByName(ctx, schedule.Target)
+		if err != nil {
+			return nil, err
+		}
+		if group == nil {
+			return nil, fmt.Errorf("%w: %s", orchestrator.ErrDeploymentNotFound, schedule.Target)
+		}
+		return q.VirtualMachines().ListByGroupID(ctx, group.ID)
+	case TargetPlugin:
+		all, err := s.engine.ListVMs(ctx)
+		if err != nil {
+			return nil, err
+		}
+		var vms []db.VM
+		for _, vm := range all {
+			if vm.Runtime == schedule.Target {
+				vms = append(vms, vm)
+			}
+		}
+		return vms, nil
+	default:
+		return nil, fmt.Errorf("unsupported target kind %q", schedule.TargetKind)
+	}
+}
+
+func (s *Scheduler) store() db.Store {
+	return s.engine.Store()
+}
@@ -0,0 +1,385 @@
+// Copyright (c) 2025 HYPR. PTE. LTD.
+//
+// Business Source License 1.1
+// See LICENSE file in the project root for details.
+
+// Package secrets stores named values that cloud-init templates reference and
+// VMs mount. Values are sealed with AES-256-GCM under a host key before they
+// reach the database, and are only opened for a VM, which is audited.
+package secrets
+
+import (
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/volantvm/volant/internal/pluginspec"
+	"github.com/volantvm/volant/internal/server/db"
+)
+
+// KeySize is the length of the sealing key in bytes.
+const KeySize = 32
+
+// MaxValueSize bounds a secret's value.
+const MaxValueSize = 64 << 10
+
+// AuditAccess is the audit action recorded each time a secret is opened for
+// a VM.
+const AuditAccess = "secret.access"
+
+var (
+	// ErrSecretNotFound indicates the requested secret does not exist.
+	ErrSecretNotFound = errors.New("secrets: secret not found")
+	// ErrInvalidSecret indicates the secret's name or value failed validation.
+	ErrInvalidSecret = errors.New("secrets: invalid secret")
+)
+
+// Info describes a stored secret without its value.
+type Info struct {
+	Name      string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// Access identifies what a secret is opened for, for the audit log.
+type Access struct {
+	// VM is the VM the value is delivered to.
+	VM string
+	// Via names the delivery path, such as "cloud-init" or "agent".
+	Via      string
+	ClientIP string
+}
+
+// Manager seals secrets into the store and opens them for rendering.
+type Manager struct {
+	store db.Store
+	aead  cipher.AEAD
+}
+
+// New constructs a manager sealing values with key, which must be KeySize
+// bytes.
+func New(store db.Store, key []byte) (*Manager, error) {
+	if len(key) != KeySize {
+		return nil, fmt.Errorf("secrets: key must be %d bytes, got %d", KeySize, len(key))
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("secrets: %w", err)
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("secrets: %w", err)
+	}
+	return &Manager{store: store, aead: aead}, nil
+}
+
+// LoadKey reads the hex-encoded key at path, generating one readable only by
+// its owner when the file does not exist. Daemons sharing a database must
+// share the key file.
+func LoadKey(path string) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return generateKey(path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("secrets: read key: %w", err)
+	}
+	return parseKey(string(data), "key file "+path)
+}
+
+// keyCommandTimeout bounds a key command, which may call out to a KMS.
+const keyCommandTimeout = 30 * time.Second
+
+// KeySource says where the sealing key comes from. The first field set
+// wins: an inline hex key, a shell command printing one (for keys kept in a
+// KMS or encrypted with age), or a key file generated on first use.
+type KeySource struct {
+	Key     string
+	Command string
+	File    string
+}
+
+// Load returns the key from the first source set.
+func (s KeySource) Load(ctx context.Context) ([]byte, error) {
+	switch {
+	case strings.TrimSpace(s.Key) != "":
+		return parseKey(s.Key, "inline key")
+	case strings.TrimSpace(s.Command) != "":
+		ctx, cancel := context.WithTimeout(ctx, keyCommandTimeout)
+		defer cancel()
+		var stderr bytes.Buffer
+		cmd := exec.CommandContext(ctx, "/bin/sh", "-c", s.Command)
+		cmd.Stderr = &stderr
+		out, err := cmd.Output()
+		if err != nil {
+			return nil, fmt.Errorf("secrets: key command: %w: %s", err, strings.TrimSpace(stderr.String()))
+		}
+		return parseKey(string(out), "key command output")
+	case s.File != "":
+		return LoadKey(s.File)
+	default:
+		return nil, errors.New("secrets: no key source configured")
+	}
+}
+
+func parseKey(raw, origin string) ([]byte, error) {
+	key, err := hex.DecodeString(strings.TrimSpace(raw))
+	if err != nil || len(key) != KeySize {
+		return nil, fmt.Errorf("secrets: %s must hold %d hex-encoded bytes", origin, KeySize)
+	}
+	return key, nil
+}
+
+func generateKey(path string) ([]byte, error) {
+	key := make([]byte, KeySize)
+	if _, err := rand.Read(key); err != nil {
+		return nil, fmt.Errorf("secrets: generate key: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return nil, fmt.Errorf("secrets: create key directory: %w", err)
+	}
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+	if errors.Is(err, fs.ErrExist) {
+		// Another daemon sharing the directory wrote it first.
+		return LoadKey(path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("secrets: write key: %w", err)
+	}
+	if _, err := f.WriteString(hex.EncodeToString(key) + "\n"); err != nil {
+		f.Close()
+		return nil, fmt.Errorf("secrets: write key: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return nil, fmt.Errorf("secrets: write key: %w", err)
+	}
+	return key, nil
+}
+
+// ValidName reports whether name can name a secret.
+func ValidName(name string) bool {
+	return pluginspec.ValidSecretName(name)
+}
+
+// Reserved reports whether name is reserved for a secret volantd generates
+// and so cannot be written or deleted by clients.
+func Reserved(name string) bool {
+	return pluginspec.ReservedSecretName(name)
+}
+
+// normalizeName is applied to the name at every entry point, so a name
+// with stray whitespace finds the same row and opens with the same AAD.
+func normalizeName(name string) string {
+	return strings.TrimSpace(name)
+}
+
+// Put stores value under name, replacing any existing value, and reports
+// whether the secret is new. Reserved names are refused.
+func (m *Manager) Put(ctx context.Context, name, value string) (*Info, bool, error) {
+	name = normalizeName(name)
+	if err := checkUnreserved(name); err != nil {
+		return nil, false, err
+	}
+	return m.put(ctx, name, value, true)
+}
+
+// PutReserved stores a secret volantd generates, such as a VM's SSH key,
+// under a reserved name.
+func (m *Manager) PutReserved(ctx context.Context, name, value string) (*Info, bool, error) {
+	name = normalizeName(name)
+	if !Reserved(name) {
+		return nil, false, fmt.Errorf("%w: %s is not a reserved name", ErrInvalidSecret, name)
+	}
+	return m.put(ctx, name, value, true)
+}
+
+// Update replaces the value of an existing secret. Reserved names are
+// refused.
+func (m *Manager) Update(ctx context.Context, name, value string) (*Info, error) {
+	name = normalizeName(name)
+	if err := checkUnreserved(name); err != nil {
+		return nil, err
+	}
+	info, _, err := m.put(ctx, name, value, false)
+	return info, err
+}
+
+func checkUnreserved(name string) error {
+	if Reserved(name) {
+		return fmt.Errorf("%w: names starting with %q are reserved", ErrInvalidSecret, pluginspec.ReservedSecretPrefix)
+	}
+	return nil
+}
+
+func (m *Manager) put(ctx context.Context, name, value string, create bool) (*Info, bool, error) {
+	if !ValidName(name) {
+		return nil, false, fmt.Errorf("%w: name must be 1-128 letters, digits, '.', '_' or '-', starting with a letter or digit", ErrInvalidSecret)
+	}
+	if value == "" {
+		return nil, false, fmt.Errorf("%w: value is required", ErrInvalidSecret)
+	}
+	if len(value) > MaxValueSize {
+		return nil, false, fmt.Errorf("%w: value exceeds %d bytes", ErrInvalidSecret, MaxValueSize)
+	}
+	sealed, err := m.seal(name, value)
+	if err != nil {
+		return nil, false, err
+	}
+
+	var (
+		stored  *db.Secret
+		created bool
+	)
+	err = m.store.WithTx(ctx, func(q db.Queries) error {
+		existing, err := q.Secrets().GetByName(ctx, name)
+		if err != nil {
+			return err
+		}
+		if existing == nil && !create {
+			return fmt.Errorf("%w: %s", ErrSecretNotFound, name)
+		}
+		if existing == nil {
+			created = true
+			_, err = q.Secrets().Create(ctx, &db.Secret{Name: name, Sealed: sealed})
+		} else {
+			err = q.Secrets().Update(ctx, existing.ID, sealed)
+		}
+		if err != nil {
+			return err
+		}
+		stored, err = q.Secrets().GetByName(ctx, name)
+		return err
+	})
+	if err != nil {
+		return nil, false, err
+	}
+	return infoOf(*stored), created, nil
+}
+
+// List returns every secret, by name, without values.
+func (m *Manager) List(ctx context.Context) ([]Info, error) {
+	stored, err := m.store.Queries().Secrets().List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	infos := make([]Info, 0, len(stored))
+	for _, secret := range stored {
+		infos = append(infos, *infoOf(secret))
+	}
+	return infos, nil
+}
+
+// Get describes the named secret without its value.
+func (m *Manager) Get(ctx context.Context, name string) (*Info, error) {
+	name = normalizeName(name)
+	secret, err := m.store.Queries().Secrets().GetByName(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	if secret == nil {
+		return nil, fmt.Errorf("%w: %s", ErrSecretNotFound, name)
+	}
+	return infoOf(*secret), nil
+}
+
+// Delete removes the named secret. VMs already rendered with it keep the
+// value in their seed image until it is rebuilt. Reserved names are refused.
+func (m *Manager) Delete(ctx context.Context, name string) error {
+	name = normalizeName(name)
+	if err := checkUnreserved(name); err != nil {
+		return err
+	}
+	return m.delete(ctx, name)
+}
+
+// DeleteReserved removes a secret stored with PutReserved.
+func (m *Manager) DeleteReserved(ctx context.Context, name string) error {
+	name = normalizeName(name)
+	if !Reserved(name) {
+		return fmt.Errorf("%w: %s is not a reserved name", ErrInvalidSecret, name)
+	}
+	return m.delete(ctx, name)
+}
+
+func (m *Manager) delete(ctx context.Context, name string) error {
+	secret, err := m.store.Queries().Secrets().GetByName(ctx, name)
+	if err != nil {
+		return err
+	}
+	if secret == nil {
+		return fmt.Errorf("%w: %s", ErrSecretNotFound, name)
+	}
+	return m.store.Queries().Secrets().Delete(ctx, secret.ID)
+}
+
+// Resolve returns the value of the named secret.
+func (m *Manager) Resolve(ctx context.Context, name string) (string, error) {
+	name = normalizeName(name)
+	secret, err := m.store.Queries().Secrets().GetByName(ctx, name)
+	if err != nil {
+		return "", err
+	}
+	if secret == nil {
+		return "", fmt.Errorf("%w: %s", ErrSecretNotFound, name)
+	}
+	return m.open(secret.Name, secret.Sealed)
+}
+
+// Open returns the value of the named secret for access.VM and records the
+// access in the audit log.
+func (m *Manager) Open(ctx context.Context, name string, access Access) (string, error) {
+	name = normalizeName(name)
+	value, err := m.Resolve(ctx, name)
+	message := "opened for " + access.Via
+	if err != nil {
+		message = "open for " + access.Via + " failed: " + err.Error()
+	}
+	if _, auditErr := m.store.Queries().AuditEvents().Record(ctx, db.AuditEvent{
+		Action:   AuditAccess,
+		Target:   name,
+		Actor:    "vm/" + access.VM,
+		ClientIP: access.ClientIP,
+		Message:  message,
+	}); auditErr != nil && err == nil {
+		// An access that cannot be audited is refused.
+		return "", fmt.Errorf("secrets: audit access to %s: %w", name, auditErr)
+	}
+	return value, err
+}
+
+// seal encrypts value, binding it to name so a sealed value copied to
+// another secret does not open.
+func (m *Manager) seal(name, value string) ([]byte, error) {
+	nonce := make([]byte, m.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("secrets: nonce: %w", err)
+	}
+	return m.aead.Seal(nonce, nonce, []byte(value), []byte(name)), nil
+}
+
+func (m *Manager) open(name string, sealed []byte) (string, error) {
+	size := m.aead.NonceSize()
+	if len(sealed) < size {
+		return "", fmt.Errorf("secrets: %s: sealed value truncated", name)
+	}
+	value, err := m.aead.Open(nil, sealed[:size], sealed[size:], []byte(name))
+	if err != nil {
+		return "", fmt.Errorf("secrets: %s: cannot open sealed value; was the key file changed?", name)
+	}
+	return string(value), nil
+}
+
+func infoOf(secret db.Secret) *Info {
+	return &Info{Name: secret.Name, CreatedAt: secret.CreatedAt, UpdatedAt: secret.UpdatedAt}
+}
@@ -0,0 +1,248 @@
+// Copyright (c) 2025 HYPR. PTE. LTD.
+//
+// Business Source License 1.1
+// See LICENSE file in the project root for details.
+
+package secrets
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/volantvm/volant/internal/server/db"
+	"github.com/volantvm/volant/internal/server/db/sqlite"
+)
+
+func newTestManager(t *testing.T) (*Manager, db.Store) {
+	t.Helper()
+	ctx := context.Background()
+	store, err := sqlite.Open(ctx, filepath.Join(t.TempDir(), "volant.db"))
+	if err != nil {
+		t.Fatalf("open store: %v", err)
+	}
+	t.Cleanup(func() { _ = store.Close(ctx) })
+	key, err := LoadKey(filepath.Join(t.TempDir(), "secrets.key"))
+	if err != nil {
+		t.Fatalf("load key: %v", err)
+	}
+	m, err := New(store, key)
+	if err != nil {
+		t.Fatalf("new manager: %v", err)
+	}
+	return m, store
+}
+
+func TestPutResolveDelete(t *testing.T) {
+	ctx := context.Background()
+	m, store := newTestManager(t)
+
+	info, created, err := m.Put(ctx, "db-password", "hunter2")
+	if err != nil || !created || info.Name != "db-password" {
+		t.Fatalf("put: %+v %v %v", info, created, err)
+	}
+	stored, err := store.Queries().Secrets().GetByName(ctx, "db-password")
+	if err != nil || stored == nil {
+		t.Fatalf("get stored: %+v %v", stored, err)
+	}
+	if bytes.Contains(stored.Sealed, []byte("hunter2")) {
+		t.Fatalf("value stored in plain text")
+	}
+	if value, err := m.Resolve(ctx, "db-password"); err != nil || value != "hunter2" {
+		t.Fatalf("resolve: %q %v", value, err)
+	}
+
+	if _, created, err := m.Put(ctx, "db-password", "correct horse"); err != nil || created {
+		t.Fatalf("replace: %v %v", created, err)
+	}
+	if value, _ := m.Resolve(ctx, "db-password"); value != "correct horse" {
+		t.Fatalf("expected replaced value, got %q", value)
+	}
+	if list, err := m.List(ctx); err != nil || len(list) != 1 || list[0].Name != "db-password" {
+		t.Fatalf("list: %+v %v", list, err)
+	}
+
+	if err := m.Delete(ctx, "db-password"); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if _, err := m.Resolve(ctx, "db-password"); !errors.Is(err, ErrSecretNotFound) {
+		t.Fatalf("expected not found after delete, got %v", err)
+	}
+	if err := m.Delete(ctx, "db-password"); !errors.Is(err, ErrSecretNotFound) {
+		t.Fatalf("expected not found deleting twice, got %v", err)
+	}
+}
+
+func TestPutValidates(t *testing.T) {
+	m, _ := newTestManager(t)
+	for _, tc := range []struct{ name, value string }{
+		{"", "x"},
+		{"../etc", "x"},
+		{"has space", "x"},
+		{"empty", ""},
+		{"huge", string(make([]byte, MaxValueSize+1))},
+	} {
+		if _, _, err := m.Put(context.Background(), tc.name, tc.value); !errors.Is(err, ErrInvalidSecret) {
+			t.Errorf("put %q: expected ErrInvalidSecret, got %v", tc.name, err)
+		}
+	}
+}
+
+func TestSealedValueBoundToName(t *testing.T) {
+	ctx := context.Background()
+	m, store := newTestManager(t)
+	if _, _, err := m.Put(ctx, "a", "alpha"); err != nil {
+		t.Fatalf("put a: %v", err)
+	}
+	if _, _, err := m.Put(ctx, "b", "beta"); err != nil {
+		t.Fatalf("put b: %v", err)
+	}
+	a, _ := store.Queries().Secrets().GetByName(ctx, "a")
+	b, _ := store.Queries().Secrets().GetByName(ctx, "b")
+	if err := store.Queries().Secrets().Update(ctx, b.ID, a.Sealed); err != nil {
+		t.Fatalf("swap sealed value: %v", err)
+	}
+	if _, err := m.Resolve(ctx, "b"); err == nil {
+		t.Fatalf("expected value sealed for another name to be rejected")
+	}
+}
+
+func TestLoadKeyPersists(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nested", "secrets.key")
+	first, err := LoadKey(path)
+	if err != nil {
+		t.Fatalf("generate: %v", err)
+	}
+	info, err := os.Stat(path)
+	if err != nil || info.Mode().Perm() != 0o600 {
+		t.Fatalf("expected key file with mode 0600, got %v %v", info, err)
+	}
+	second, err := LoadKey(path)
+	if err != nil || !bytes.Equal(first, second) {
+		t.Fatalf("expected the same key on reload: %v", err)
+	}
+
+	if err := os.WriteFile(path, []byte("not hex"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadKey(path); err == nil {
+		t.Fatalf("expected malformed key file to be rejected")
+	}
+}
+
+func TestKeySourcePrecedence(t *testing.T) {
+	ctx := context.Background()
+	inline := strings.Repeat("ab", KeySize)
+	fromCommand := strings.Repeat("cd", KeySize)
+	file := filepath.Join(t.TempDir(), "secrets.key")
+
+	key, err := KeySource{Key: inline, Command: "echo " + fromCommand, File: file}.Load(ctx)
+	if err != nil || hex.EncodeToString(key) != inline {
+		t.Fatalf("expected the inline key first: %x %v", key, err)
+	}
+	key, err = KeySource{Command: "echo " + fromCommand, File: file}.Load(ctx)
+	if err != nil || hex.EncodeToString(key) != fromCommand {
+		t.Fatalf("expected the command's key: %x %v", key, err)
+	}
+	if _, err := os.Stat(file); err == nil {
+		t.Fatalf("expected no key file generated while another source is set")
+	}
+	if _, err := (KeySource{Command: "echo not-a-key"}).Load(ctx); err == nil {
+		t.Fatalf("expected malformed command output to be rejected")
+	}
+	if _, err := (KeySource{Command: "exit 3"}).Load(ctx); err == nil {
+		t.Fatalf("expected a failing command to be rejected")
+	}
+	if _, err := (KeySource{File: file}).Load(ctx); err != nil {
+		t.Fatalf("expected a key file generated: %v", err)
+	}
+}
+
+func TestUpdateAndOpen(t *testing.T) {
+	ctx := context.Background()
+	m, store := newTestManager(t)
+
+	if _, err := m.Update(ctx, "token", "x"); !errors.Is(err, ErrSecretNotFound) {
+		t.Fatalf("expected updating a missing secret to fail, got %v", err)
+	}
+	if _, _, err := m.Put(ctx, "token", "one"); err != nil {
+		t.Fatalf("put: %v", err)
+	}
+	if _, err := m.Update(ctx, "token", "two"); err != nil {
+		t.Fatalf("update: %v", err)
+	}
+	value, err := m.Open(ctx, "token", Access{VM: "web-1", Via: "agent", ClientIP: "192.168.127.10"})
+	if err != nil || value != "two" {
+		t.Fatalf("open: %q %v", value, err)
+	}
+	events, err := store.Queries().AuditEvents().List(ctx, "token", 10)
+	if err != nil || len(events) != 1 {
+		t.Fatalf("expected one audit event: %+v %v", events, err)
+	}
+	if events[0].Action != AuditAccess || events[0].Actor != "vm/web-1" || strings.Contains(events[0].Message, "two") {
+		t.Fatalf("unexpected audit event %+v", events[0])
+	}
+}
+
+func TestNamesNormalizedEverywhere(t *testing.T) {
+	ctx := context.Background()
+	m, _ := newTestManager(t)
+
+	if _, _, err := m.Put(ctx, "token", "one"); err != nil {
+		t.Fatalf("put: %v", err)
+	}
+	if _, err := m.Update(ctx, " token\n", "two"); err != nil {
+		t.Fatalf("update with padded name: %v", err)
+	}
+	if info, err := m.Get(ctx, " token"); err != nil || info.Name != "token" {
+		t.Fatalf("get with padded name: %+v %v", info, err)
+	}
+	value, err := m.Open(ctx, "token ", Access{VM: "web-1", Via: "agent"})
+	if err != nil || value != "two" {
+		t.Fatalf("open with padded name: %q %v", value, err)
+	}
+	if err := m.Delete(ctx, "\ttoken"); err != nil {
+		t.Fatalf("delete with padded name: %v", err)
+	}
+	if _, err := m.Resolve(ctx, "token"); !errors.Is(err, ErrSecretNotFound) {
+		t.Fatalf("expected secret deleted, got %v", err)
+	}
+}
+
+func TestReservedNames(t *testing.T) {
+	ctx := context.Background()
+	m, _ := newTestManager(t)
+
+	for _, name := range []string{"ssh.web-1", " ssh.web-1"} {
+		if _, _, err := m.Put(ctx, name, "key"); !errors.Is(err, ErrInvalidSecret) {
+			t.Errorf("put %q: expected ErrInvalidSecret, got %v", name, err)
+		}
+	}
+	if _, _, err := m.PutReserved(ctx, "ssh.web-1", "key"); err != nil {
+		t.Fatalf("put reserved: %v", err)
+	}
+	if _, err := m.Update(ctx, "ssh.web-1", "other"); !errors.Is(err, ErrInvalidSecret) {
+		t.Fatalf("expected updating a reserved secret refused, got %v", err)
+	}
+	if err := m.Delete(ctx, "ssh.web-1"); !errors.Is(err, ErrInvalidSecret) {
+		t.Fatalf("expected deleting a reserved secret refused, got %v", err)
+	}
+	if value, err := m.Resolve(ctx, "ssh.web-1"); err != nil || value != "key" {
+		t.Fatalf("expected the reserved secret kept: %q %v", value, err)
+	}
+
+	if _, _, err := m.PutReserved(ctx, "token", "x"); !errors.Is(err, ErrInvalidSecret) {
+		t.Fatalf("expected PutReserved limited to reserved names, got %v", err)
+	}
+	if err := m.DeleteReserved(ctx, "ssh.web-1"); err != nil {
+		t.Fatalf("delete reserved: %v", err)
+	}
+	if _, err := m.Resolve(ctx, "ssh.web-1"); !errors.Is(err, ErrSecretNotFound) {
+		t.Fatalf("expected reserved secret deleted, got %v", err)
+	}
+}
@@ -0,0 +1,184 @@
+// Copyright (c) 2025 HYPR. PTE. LTD.
+//
+// Business Source License 1.1
+// See LICENSE file in the project root for details.
+
+// Package sshkey generates the ed25519 keypairs volantd injects into VMs and
+// validates authorized_keys lines. Keys are encoded in the OpenSSH formats
+// ssh and ssh-keygen read, without encryption.
+package sshkey
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+const (
+	keyTypeEd25519 = "ssh-ed25519"
+	privateKeyType = "OPENSSH PRIVATE KEY"
+	privateMagic   = "openssh-key-v1\x00"
+)
+
+// supportedTypes lists the public key algorithms accepted in authorized
+// keys.
+var supportedTypes = map[string]bool{
+	"ssh-ed25519":                        true,
+	"ssh-rsa":                            true,
+	"ecdsa-sha2-nistp256":                true,
+	"ecdsa-sha2-nistp384":                true,
+	"ecdsa-sha2-nistp521":                true,
+	"sk-ssh-ed25519@openssh.com":         true,
+	"sk-ecdsa-sha2-nistp256@openssh.com": true,
+}
+
+// Generate creates an ed25519 keypair. It returns the PEM-encoded OpenSSH
+// private key and the matching authorized_keys line carrying comment.
+func Generate(comment string) (string, string, error) {
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return "", "", fmt.Errorf("sshkey: generate: %w", err)
+	}
+	var check [4]byte
+	if _, err := rand.Read(check[:]); err != nil {
+		return "", "", fmt.Errorf("sshkey: generate: %w", err)
+	}
+	pubBlob := publicBlob(pub)
+
+	var private bytes.Buffer
+	private.Write(check[:])
+	private.Write(check[:])
+	writeString(&private, []byte(keyTypeEd25519))
+	writeString(&private, pub)
+	writeString(&private, priv)
+	writeString(&private, []byte(comment))
+	for pad := byte(1); private.Len()%8 != 0; pad++ {
+		private.WriteByte(pad)
+	}
+
+	var body bytes.Buffer
+	body.WriteString(privateMagic)
+	writeString(&body, []byte("none"))
+	writeString(&body, []byte("none"))
+	writeString(&body, nil)
+	_ = binary.Write(&body, binary.BigEndian, uint32(1))
+	writeString(&body, pubBlob)
+	writeString(&body, private.Bytes())
+
+	privatePEM := pem.EncodeToMemory(&pem.Block{Type: privateKeyType, Bytes: body.Bytes()})
+	return string(privatePEM), authorizedLine(pubBlob, comment), nil
+}
+
+// AuthorizedKey returns the authorized_keys line, carrying comment, of a
+// private key produced by Generate.
+func AuthorizedKey(privatePEM, comment string) (string, error) {
+	block, _ := pem.Decode([]byte(privatePEM))
+	if block == nil || block.Type != privateKeyType {
+		return "", errors.New("sshkey: not an OpenSSH private key")
+	}
+	rest, ok := bytes.CutPrefix(block.Bytes, []byte(privateMagic))
+	if !ok {
+		return "", errors.New("sshkey: unsupported private key format")
+	}
+	var cipherName, kdfName []byte
+	if cipherName, rest, ok = readString(rest); !ok {
+		return "", errors.New("sshkey: truncated private key")
+	}
+	if kdfName, rest, ok = readString(rest); !ok {
+		return "", errors.New("sshkey: truncated private key")
+	}
+	if string(cipherName) != "none" || string(kdfName) != "none" {
+		return "", errors.New("sshkey: encrypted private keys are not supported")
+	}
+	if _, rest, ok = readString(rest); !ok || len(rest) < 4 {
+		return "", errors.New("sshkey: truncated private key")
+	}
+	if count := binary.BigEndian.Uint32(rest); count != 1 {
+		return "", fmt.Errorf("sshkey: private key holds %d keys, want 1", count)
+	}
+	pubBlob, _, ok := readString(rest[4:])
+	if !ok {
+		return "", errors.New("sshkey: truncated private key")
+	}
+	if err := checkBlob(keyTypeEd25519, pubBlob); err != nil {
+		return "", err
+	}
+	return authorizedLine(pubBlob, comment), nil
+}
+
+// ValidAuthorizedKey checks that line is a single authorized_keys entry of
+// the form "<type> <base64 key> [comment]". Options before the key type are
+// not accepted.
+func ValidAuthorizedKey(line string) error {
+	if strings.ContainsAny(line, "\r\n") {
+		return errors.New("sshkey: authorized key must be a single line")
+	}
+	fields := strings.Fields(line)
+	if len(fields) < 2 {
+		return errors.New("sshkey: authorized key must be \"<type> <key> [comment]\"")
+	}
+	keyType := fields[0]
+	if !supportedTypes[keyType] {
+		return fmt.Errorf("sshkey: unsupported key type %q", keyType)
+	}
+	blob, err := base64.StdEncoding.DecodeString(fields[1])
+	if err != nil {
+		return fmt.Errorf("sshkey: decode %s key: %w", keyType, err)
+	}
+	return checkBlob(keyType, blob)
+}
+
+// checkBlob verifies that a public key blob is of keyType and, for ed25519,
+// holds a key of the right size.
+func checkBlob(keyType string, blob []byte) error {
+	name, rest, ok := readString(blob)
+	if !ok || string(name) != keyType {
+		return fmt.Errorf("sshkey: key data is not a %s key", keyType)
+	}
+	if keyType == keyTypeEd25519 {
+		key, rest, ok := readString(rest)
+		if !ok || len(key) != ed25519.PublicKeySize || len(rest) != 0 {
+			return errors.New("sshkey: malformed ssh-ed25519 key")
+		}
+	}
+	return nil
+}
+
+func publicBlob(pub ed25519.PublicKey) []byte {
+	var blob bytes.Buffer
+	writeString(&blob, []byte(keyTypeEd25519))
+	writeString(&blob, pub)
+	return blob.Bytes()
+}
+
+func authorizedLine(pubBlob []byte, comment string) string {
+	line := keyTypeEd25519 + " " + base64.StdEncoding.EncodeToString(pubBlob)
+	if comment = strings.TrimSpace(comment); comment != "" {
+		line += " " + comment
+	}
+	return line
+}
+
+// writeString appends an SSH wire-format string: a big-endian uint32 length
+// followed by the bytes.
+func writeString(buf *bytes.Buffer, data []byte) {
+	_ = binary.Write(buf, binary.BigEndian, uint32(len(data)))
+	buf.Write(data)
+}
+
+func readString(data []byte) ([]byte, []byte, bool) {
+	if len(data) < 4 {
+		return nil, nil, false
+	}
+	size := binary.BigEndian.Uint32(data)
+	if uint64(size) > uint64(len(data)-4) {
+		return nil, nil, false
+	}
+	return data[4 : 4+size], data[4+size:], true
+}
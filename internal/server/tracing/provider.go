@@ -0,0 +1,313 @@
+// Copyright (c) 2025 HYPR. PTE. LTD.
+//
+// Business Source License 1.1
+// See LICENSE file in the project root for details.
+
+package tracing
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"math"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	otlpTracesPath = "/v1/traces"
+	queueSize      = 4096
+	batchSize      = 512
+	flushInterval  = 5 * time.Second
+	exportTimeout  = 10 * time.Second
+)
+
+// Options configures a Provider.
+type Options struct {
+	// Endpoint is the OTLP/HTTP collector base URL; /v1/traces is used when
+	// it has no path.
+	Endpoint string
+	// ServiceName and Hostname identify this process in exported resources.
+	ServiceName string
+	Hostname    string
+	// SampleRatio is the fraction of new traces recorded, 0 to 1. Traces
+	// continued from a caller follow the caller's decision.
+	SampleRatio float64
+}
+
+// ValidateEndpoint checks an OTLP/HTTP collector URL.
+func ValidateEndpoint(endpoint string) error {
+	parsed, err := url.Parse(endpoint)
+	if err != nil {
+		return fmt.Errorf("tracing: endpoint %q: %w", endpoint, err)
+	}
+	if parsed.Scheme != "http" && parsed.Scheme != "https" {
+		return fmt.Errorf("tracing: endpoint %q: scheme must be http or https", endpoint)
+	}
+	return nil
+}
+
+// Provider samples spans and exports finished ones in batches.
+type Provider struct {
+	logger    *slog.Logger
+	endpoint  string
+	resource  []otlpAttribute
+	threshold uint64
+	client    *http.Client
+
+	spans    chan spanData
+	dropped  int64
+	mu       sync.Mutex
+	done     chan struct{}
+	wg       sync.WaitGroup
+	shutdown sync.Once
+}
+
+type spanData struct {
+	span *Span
+	end  time.Time
+}
+
+// New returns a provider exporting to opts.Endpoint. Install it with
+// SetProvider.
+func New(logger *slog.Logger, opts Options) (*Provider, error) {
+	if err := ValidateEndpoint(opts.Endpoint); err != nil {
+		return nil, err
+	}
+	if opts.SampleRatio < 0 || opts.SampleRatio > 1 {
+		return nil, fmt.Errorf("tracing: sample ratio %v must be between 0 and 1", opts.SampleRatio)
+	}
+	p := &Provider{
+		logger:   logger.With("component", "tracing"),
+		endpoint: withDefaultPath(opts.Endpoint, otlpTracesPath),
+		resource: []otlpAttribute{
+			attribute(String("service.name", opts.ServiceName)),
+			attribute(String("host.name", opts.Hostname)),
+		},
+		client: &http.Client{},
+		spans:  make(chan spanData, queueSize),
+		done:   make(chan struct{}),
+	}
+	// Sample on the low 63 bits of the trace ID, as OpenTelemetry's
+	// TraceIDRatioBased sampler does.
+	switch {
+	case opts.SampleRatio >= 1:
+		p.threshold = math.MaxUint64
+	case opts.SampleRatio > 0:
+		p.threshold = uint64(opts.SampleRatio * (1 << 63))
+	}
+	p.wg.Add(1)
+	go p.run()
+	return p, nil
+}
+
+func (p *Provider) sample(id TraceID) bool {
+	if p.threshold == math.MaxUint64 {
+		return true
+	}
+	return binary.BigEndian.Uint64(id[8:])>>1 < p.threshold
+}
+
+func (p *Provider) enqueue(span *Span, end time.Time) {
+	select {
+	case p.spans <- spanData{span: span, end: end}:
+	default:
+		p.mu.Lock()
+		p.dropped++
+		p.mu.Unlock()
+	}
+}
+
+func (p *Provider) run() {
+	defer p.wg.Done()
+	ticker := time.NewTicker(flushInterval)
+	defer ticker.Stop()
+	batch := make([]spanData, 0, batchSize)
+	flush := func() {
+		if len(batch) > 0 {
+			ctx, cancel := context.WithTimeout(context.Background(), exportTimeout)
+			if err := p.export(ctx, batch); err != nil {
+				p.logger.Warn("export spans", "spans", len(batch), "error", err)
+			}
+			cancel()
+			batch = batch[:0]
+		}
+		p.mu.Lock()
+		dropped := p.dropped
+		p.dropped = 0
+		p.mu.Unlock()
+		if dropped > 0 {
+			p.logger.Warn("span queue full; spans dropped", "dropped", dropped)
+		}
+	}
+	for {
+		select {
+		case data := <-p.spans:
+			batch = append(batch, data)
+			if len(batch) >= batchSize {
+				flush()
+			}
+		case <-ticker.C:
+			flush()
+		case <-p.done:
+			for {
+				select {
+				case data := <-p.spans:
+					batch = append(batch, data)
+					if len(batch) >= batchSize {
+						flush()
+					}
+				default:
+					flush()
+					return
+				}
+			}
+		}
+	}
+}
+
+// Close exports the spans ended so far and stops the provider. Spans ended
+// afterwards are dropped.
+func (p *Provider) Close() {
+	p.shutdown.Do(func() {
+		close(p.done)
+		p.wg.Wait()
+		p.client.CloseIdleConnections()
+	})
+}
+
+type otlpValue struct {
+	StringValue *string `json:"stringValue,omitempty"`
+	IntValue    *string `json:"intValue,omitempty"`
+	BoolValue   *bool   `json:"boolValue,omitempty"`
+}
+
+type otlpAttribute struct {
+	Key   string    `json:"key"`
+	Value otlpValue `json:"value"`
+}
+
+type otlpStatus struct {
+	Code    int    `json:"code"`
+	Message string `json:"message,omitempty"`
+}
+
+type otlpSpan struct {
+	TraceID           string          `json:"traceId"`
+	SpanID            string          `json:"spanId"`
+	ParentSpanID      string          `json:"parentSpanId,omitempty"`
+	Name              string          `json:"name"`
+	Kind              Kind            `json:"kind"`
+	StartTimeUnixNano string          `json:"startTimeUnixNano"`
+	EndTimeUnixNano   string          `json:"endTimeUnixNano"`
+	Attributes        []otlpAttribute `json:"attributes,omitempty"`
+	Status            otlpStatus      `json:"status"`
+}
+
+// OTLP status codes.
+const (
+	otlpStatusUnset = 0
+	otlpStatusError = 2
+)
+
+func attribute(attr Attribute) otlpAttribute {
+	out := otlpAttribute{Key: attr.Key}
+	switch value := attr.Value.(type) {
+	case int64:
+		encoded := strconv.FormatInt(value, 10)
+		out.Value.IntValue = &encoded
+	case bool:
+		out.Value.BoolValue = &value
+	default:
+		encoded := fmt.Sprint(value)
+		out.Value.StringValue = &encoded
+	}
+	return out
+}
+
+func (p *Provider) export(ctx context.Context, batch []spanData) error {
+	spans := make([]otlpSpan, 0, len(batch))
+	for _, data := range batch {
+		span := data.span
+		span.mu.Lock()
+		out := otlpSpan{
+			TraceID:           span.sc.TraceID.String(),
+			SpanID:            span.sc.SpanID.String(),
+			Name:              span.name,
+			Kind:              span.kind,
+			StartTimeUnixNano: strconv.FormatInt(span.start.UnixNano(), 10),
+			EndTimeUnixNano:   strconv.FormatInt(data.end.UnixNano(), 10),
+			Status:            otlpStatus{Code: otlpStatusUnset},
+		}
+		if span.parent.IsValid() {
+			out.ParentSpanID = span.parent.String()
+		}
+		for _, attr := range span.attrs {
+			out.Attributes = append(out.Attributes, attribute(attr))
+		}
+		if span.failed {
+			out.Status = otlpStatus{Code: otlpStatusError, Message: span.status}
+		}
+		span.mu.Unlock()
+		spans = append(spans, out)
+	}
+
+	type scopeSpans struct {
+		Scope struct {
+			Name string `json:"name"`
+		} `json:"scope"`
+		Spans []otlpSpan `json:"spans"`
+	}
+	type resourceSpans struct {
+		Resource struct {
+			Attributes []otlpAttribute `json:"attributes"`
+		} `json:"resource"`
+		ScopeSpans []scopeSpans `json:"scopeSpans"`
+	}
+	scope := scopeSpans{Spans: spans}
+	scope.Scope.Name = "volant"
+	resource := resourceSpans{ScopeSpans: []scopeSpans{scope}}
+	resource.Resource.Attributes = p.resource
+	payload := struct {
+		ResourceSpans []resourceSpans `json:"resourceSpans"`
+	}{ResourceSpans: []resourceSpans{resource}}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		detail, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("%s returned %d: %s", p.endpoint, resp.StatusCode, strings.TrimSpace(string(detail)))
+	}
+	_, _ = io.Copy(io.Discard, resp.Body)
+	return nil
+}
+
+// withDefaultPath appends path to endpoint when it has none.
+func withDefaultPath(endpoint, path string) string {
+	parsed, err := url.Parse(endpoint)
+	if err != nil || strings.Trim(parsed.Path, "/") != "" {
+		return endpoint
+	}
+	parsed.Path = path
+	return parsed.String()
+}
@@ -0,0 +1,299 @@
+// Copyright (c) 2025 HYPR. PTE. LTD.
+//
+// Business Source License 1.1
+// See LICENSE file in the project root for details.
+
+// Package tracing records OpenTelemetry-compatible spans for volantd and
+// exports them over OTLP/HTTP. Trace context crosses process boundaries in
+// W3C traceparent headers, so traces continue into the guest agent and the
+// workloads behind it.
+//
+// Until a Provider is installed with SetProvider, Start returns nil spans and
+// tracing costs nothing; every Span method is safe on nil.
+package tracing
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"math/rand/v2"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// HeaderTraceparent carries the W3C trace context.
+const HeaderTraceparent = "traceparent"
+
+// TraceID identifies a trace.
+type TraceID [16]byte
+
+// String returns the lowercase hex encoding of the ID.
+func (t TraceID) String() string { return hex.EncodeToString(t[:]) }
+
+// IsValid reports whether the ID is not all zeros.
+func (t TraceID) IsValid() bool { return t != TraceID{} }
+
+// SpanID identifies a span within a trace.
+type SpanID [8]byte
+
+// String returns the lowercase hex encoding of the ID.
+func (s SpanID) String() string { return hex.EncodeToString(s[:]) }
+
+// IsValid reports whether the ID is not all zeros.
+func (s SpanID) IsValid() bool { return s != SpanID{} }
+
+// SpanContext is the part of a span that propagates to children.
+type SpanContext struct {
+	TraceID TraceID
+	SpanID  SpanID
+	Sampled bool
+}
+
+// IsValid reports whether both IDs are set.
+func (sc SpanContext) IsValid() bool { return sc.TraceID.IsValid() && sc.SpanID.IsValid() }
+
+// Traceparent renders sc as a W3C traceparent header value.
+func (sc SpanContext) Traceparent() string {
+	flags := "00"
+	if sc.Sampled {
+		flags = "01"
+	}
+	return "00-" + sc.TraceID.String() + "-" + sc.SpanID.String() + "-" + flags
+}
+
+// ParseTraceparent parses a W3C traceparent header value.
+func ParseTraceparent(value string) (SpanContext, bool) {
+	parts := strings.Split(strings.TrimSpace(value), "-")
+	if len(parts) < 4 || len(parts[0]) != 2 || parts[0] == "ff" || len(parts[1]) != 32 || len(parts[2]) != 16 || len(parts[3]) != 2 {
+		return SpanContext{}, false
+	}
+	// Version 00 has exactly four fields; later versions may append more.
+	if parts[0] == "00" && len(parts) != 4 {
+		return SpanContext{}, false
+	}
+	var sc SpanContext
+	if _, err := hex.Decode(sc.TraceID[:], []byte(parts[1])); err != nil {
+		return SpanContext{}, false
+	}
+	if _, err := hex.Decode(sc.SpanID[:], []byte(parts[2])); err != nil {
+		return SpanContext{}, false
+	}
+	flags, err := hex.DecodeString(parts[3])
+	if err != nil || !sc.IsValid() {
+		return SpanContext{}, false
+	}
+	sc.Sampled = flags[0]&1 == 1
+	return sc, true
+}
+
+// Kind is the OTLP span kind.
+type Kind int
+
+// Span kinds.
+const (
+	KindInternal Kind = 1
+	KindServer   Kind = 2
+	KindClient   Kind = 3
+)
+
+// Attribute is a span attribute; Value is a string, int64 or bool.
+type Attribute struct {
+	Key   string
+	Value any
+}
+
+// String returns a string attribute.
+func String(key, value string) Attribute { return Attribute{Key: key, Value: value} }
+
+// Int returns an integer attribute.
+func Int(key string, value int) Attribute { return Attribute{Key: key, Value: int64(value)} }
+
+// Bool returns a boolean attribute.
+func Bool(key string, value bool) Attribute { return Attribute{Key: key, Value: value} }
+
+// Span is one timed operation of a trace.
+type Span struct {
+	provider *Provider
+	name     string
+	kind     Kind
+	sc       SpanContext
+	parent   SpanID
+	start    time.Time
+
+	mu     sync.Mutex
+	attrs  []Attribute
+	status string
+	failed bool
+	ended  bool
+}
+
+// Context returns the span's propagated context.
+func (s *Span) Context() SpanContext {
+	if s == nil {
+		return SpanContext{}
+	}
+	return s.sc
+}
+
+// SetAttributes adds attributes to the span.
+func (s *Span) SetAttributes(attrs ...Attribute) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.attrs = append(s.attrs, attrs...)
+}
+
+// End finishes the span, marking it failed when err is not nil. Only the
+// first call has an effect.
+func (s *Span) End(err error) {
+	if s == nil {
+		return
+	}
+	end := time.Now()
+	s.mu.Lock()
+	if s.ended {
+		s.mu.Unlock()
+		return
+	}
+	s.ended = true
+	if err != nil {
+		s.failed = true
+		s.status = err.Error()
+	}
+	s.mu.Unlock()
+	if s.sc.Sampled {
+		s.provider.enqueue(s, end)
+	}
+}
+
+type spanKey struct{}
+
+// SpanFromContext returns the span carried by ctx, or nil.
+func SpanFromContext(ctx context.Context) *Span {
+	span, _ := ctx.Value(spanKey{}).(*Span)
+	return span
+}
+
+// ContextWithSpan returns ctx carrying the span of from, so work detached
+// from a request still joins the request's trace.
+func ContextWithSpan(ctx, from context.Context) context.Context {
+	if span := SpanFromContext(from); span != nil {
+		return context.WithValue(ctx, spanKey{}, span)
+	}
+	return ctx
+}
+
+var global atomic.Pointer[Provider]
+
+// SetProvider installs p as the provider of new spans; nil disables tracing.
+func SetProvider(p *Provider) {
+	global.Store(p)
+}
+
+// Start begins an internal span as a child of the span in ctx.
+func Start(ctx context.Context, name string, attrs ...Attribute) (context.Context, *Span) {
+	return start(ctx, KindInternal, name, SpanContext{}, attrs)
+}
+
+// StartServer begins a span for an incoming request, continuing the trace
+// named by its traceparent header when ctx carries no span.
+func StartServer(ctx context.Context, header http.Header, name string, attrs ...Attribute) (context.Context, *Span) {
+	remote, _ := ParseTraceparent(header.Get(HeaderTraceparent))
+	return start(ctx, KindServer, name, remote, attrs)
+}
+
+func start(ctx context.Context, kind Kind, name string, remote SpanContext, attrs []Attribute) (context.Context, *Span) {
+	p := global.Load()
+	if p == nil {
+		return ctx, nil
+	}
+	span := &Span{provider: p, name: name, kind: kind, start: time.Now(), attrs: attrs}
+	parent := remote
+	if current := SpanFromContext(ctx); current != nil {
+		parent = current.sc
+	}
+	if parent.IsValid() {
+		span.sc.TraceID = parent.TraceID
+		span.sc.Sampled = parent.Sampled
+		span.parent = parent.SpanID
+	} else {
+		span.sc.TraceID = newTraceID()
+		span.sc.Sampled = p.sample(span.sc.TraceID)
+	}
+	span.sc.SpanID = newSpanID()
+	return context.WithValue(ctx, spanKey{}, span), span
+}
+
+// Inject sets the traceparent header for the span in ctx.
+func Inject(ctx context.Context, header http.Header) {
+	if span := SpanFromContext(ctx); span != nil {
+		header.Set(HeaderTraceparent, span.sc.Traceparent())
+	}
+}
+
+func newTraceID() TraceID {
+	var id TraceID
+	binary.BigEndian.PutUint64(id[:8], rand.Uint64())
+	binary.BigEndian.PutUint64(id[8:], rand.Uint64())
+	return id
+}
+
+func newSpanID() SpanID {
+	var id SpanID
+	for !id.IsValid() {
+		binary.BigEndian.PutUint64(id[:], rand.Uint64())
+	}
+	return id
+}
+
+// Transport wraps base so requests made within a span get a client span and
+// carry its traceparent. Requests outside any span pass through unchanged,
+// keeping background polling out of traces.
+func Transport(base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &transport{base: base}
+}
+
+type transport struct {
+	base http.RoundTripper
+}
+
+func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if SpanFromContext(req.Context()) == nil {
+		return t.base.RoundTrip(req)
+	}
+	ctx, span := start(req.Context(), KindClient, req.Method+" "+req.URL.Path, SpanContext{}, []Attribute{
+		String("http.request.method", req.Method),
+		String("server.address", req.URL.Host),
+		String("url.path", req.URL.Path),
+	})
+	req = req.Clone(ctx)
+	Inject(ctx, req.Header)
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		span.End(err)
+		return nil, err
+	}
+	span.SetAttributes(Int("http.response.status_code", resp.StatusCode))
+	if resp.StatusCode >= 500 {
+		span.End(fmt.Errorf("status %d", resp.StatusCode))
+	} else {
+		span.End(nil)
+	}
+	return resp, nil
+}
+
+// CloseIdleConnections forwards to the wrapped transport.
+func (t *transport) CloseIdleConnections() {
+	if closer, ok := t.base.(interface{ CloseIdleConnections() }); ok {
+		closer.CloseIdleConnections()
+	}
+}
@@ -0,0 +1,222 @@
+// Copyright (c) 2025 HYPR. PTE. LTD.
+//
+// Business Source License 1.1
+// See LICENSE file in the project root for details.
+
+package tracing
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+)
+
+func TestTraceparentRoundTrip(t *testing.T) {
+	const value = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+	sc, ok := ParseTraceparent(value)
+	if !ok {
+		t.Fatalf("ParseTraceparent(%q) failed", value)
+	}
+	if !sc.Sampled || sc.TraceID.String() != "4bf92f3577b34da6a3ce929d0e0e4736" || sc.SpanID.String() != "00f067aa0ba902b7" {
+		t.Fatalf("unexpected span context %+v", sc)
+	}
+	if got := sc.Traceparent(); got != value {
+		t.Errorf("Traceparent() = %q, want %q", got, value)
+	}
+
+	for _, bad := range []string{
+		"",
+		"00-00000000000000000000000000000000-00f067aa0ba902b7-01",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-0000000000000000-01",
+		"ff-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-extra",
+		"00-xyz92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+	} {
+		if _, ok := ParseTraceparent(bad); ok {
+			t.Errorf("ParseTraceparent(%q) succeeded", bad)
+		}
+	}
+}
+
+func TestStartWithoutProviderIsNoop(t *testing.T) {
+	SetProvider(nil)
+	ctx, span := Start(context.Background(), "noop")
+	if span != nil || SpanFromContext(ctx) != nil {
+		t.Fatalf("expected no span without a provider")
+	}
+	span.SetAttributes(String("k", "v"))
+	span.End(errors.New("ignored"))
+	header := http.Header{}
+	Inject(ctx, header)
+	if header.Get(HeaderTraceparent) != "" {
+		t.Errorf("injected %q without a span", header.Get(HeaderTraceparent))
+	}
+}
+
+type exportedSpan struct {
+	TraceID      string `json:"traceId"`
+	SpanID       string `json:"spanId"`
+	ParentSpanID string `json:"parentSpanId"`
+	Name         string `json:"name"`
+	Kind         Kind   `json:"kind"`
+	Status       struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	} `json:"status"`
+}
+
+type collector struct {
+	mu    sync.Mutex
+	spans []exportedSpan
+}
+
+func (c *collector) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var payload struct {
+		ResourceSpans []struct {
+			ScopeSpans []struct {
+				Spans []exportedSpan `json:"spans"`
+			} `json:"scopeSpans"`
+		} `json:"resourceSpans"`
+	}
+	if r.URL.Path != otlpTracesPath || json.NewDecoder(r.Body).Decode(&payload) != nil {
+		http.Error(w, "bad export", http.StatusBadRequest)
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, resource := range payload.ResourceSpans {
+		for _, scope := range resource.ScopeSpans {
+			c.spans = append(c.spans, scope.Spans...)
+		}
+	}
+}
+
+func (c *collector) byName() map[string]exportedSpan {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	spans := make(map[string]exportedSpan, len(c.spans))
+	for _, span := range c.spans {
+		spans[span.Name] = span
+	}
+	return spans
+}
+
+func TestSpansPropagateAndExport(t *testing.T) {
+	spans := &collector{}
+	otlp := httptest.NewServer(spans)
+	defer otlp.Close()
+
+	provider, err := New(slog.New(slog.NewTextHandler(io.Discard, nil)), Options{Endpoint: otlp.URL, ServiceName: "volantd", SampleRatio: 1})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	SetProvider(provider)
+	defer SetProvider(nil)
+
+	var agentTraceparent string
+	agent := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		agentTraceparent = r.Header.Get(HeaderTraceparent)
+	}))
+	defer agent.Close()
+
+	const incoming = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+	header := http.Header{}
+	header.Set(HeaderTraceparent, incoming)
+	ctx, server := StartServer(context.Background(), header, "GET /api/v1/vms/:name")
+	ctx, op := Start(ctx, "orchestrator.StopVM")
+	req, _ := http.NewRequestWithContext(ctx, http.MethodPost, agent.URL+"/v1/hooks/pre-stop", http.NoBody)
+	resp, err := (&http.Client{Transport: Transport(nil)}).Do(req)
+	if err != nil {
+		t.Fatalf("agent request: %v", err)
+	}
+	resp.Body.Close()
+	op.End(errors.New("stop failed"))
+	server.End(nil)
+	provider.Close()
+
+	got := spans.byName()
+	client, ok := got["POST /v1/hooks/pre-stop"]
+	if !ok || len(got) != 3 {
+		t.Fatalf("exported spans %v", got)
+	}
+	if want := (SpanContext{TraceID: server.Context().TraceID, SpanID: mustSpanID(t, client.SpanID), Sampled: true}).Traceparent(); agentTraceparent != want {
+		t.Errorf("agent saw traceparent %q, want %q", agentTraceparent, want)
+	}
+	if got["GET /api/v1/vms/:name"].ParentSpanID != "00f067aa0ba902b7" || got["GET /api/v1/vms/:name"].Kind != KindServer {
+		t.Errorf("server span did not continue the caller: %+v", got["GET /api/v1/vms/:name"])
+	}
+	stop := got["orchestrator.StopVM"]
+	if stop.ParentSpanID != server.Context().SpanID.String() || stop.Status.Code != otlpStatusError || stop.Status.Message != "stop failed" {
+		t.Errorf("unexpected orchestrator span %+v", stop)
+	}
+	if client.ParentSpanID != stop.SpanID || client.Kind != KindClient {
+		t.Errorf("unexpected client span %+v", client)
+	}
+	for name, span := range got {
+		if span.TraceID != "4bf92f3577b34da6a3ce929d0e0e4736" {
+			t.Errorf("span %s in trace %s", name, span.TraceID)
+		}
+	}
+}
+
+func TestTransportSkipsRequestsOutsideSpans(t *testing.T) {
+	provider, err := New(slog.New(slog.NewTextHandler(io.Discard, nil)), Options{Endpoint: "http://127.0.0.1:1", SampleRatio: 1})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	SetProvider(provider)
+	defer SetProvider(nil)
+	defer provider.Close()
+
+	var traceparent string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		traceparent = r.Header.Get(HeaderTraceparent)
+	}))
+	defer srv.Close()
+	resp, err := (&http.Client{Transport: Transport(nil)}).Get(srv.URL)
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	resp.Body.Close()
+	if traceparent != "" {
+		t.Errorf("background request carried traceparent %q", traceparent)
+	}
+}
+
+func TestSampleRatio(t *testing.T) {
+	provider, err := New(slog.New(slog.NewTextHandler(io.Discard, nil)), Options{Endpoint: "http://127.0.0.1:1", SampleRatio: 0})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	SetProvider(provider)
+	defer SetProvider(nil)
+	defer provider.Close()
+
+	_, span := Start(context.Background(), "root")
+	if span.Context().Sampled {
+		t.Errorf("root span sampled at ratio 0")
+	}
+	header := http.Header{}
+	header.Set(HeaderTraceparent, "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	if _, span := StartServer(context.Background(), header, "continued"); !span.Context().Sampled {
+		t.Errorf("continued span ignored the caller's sampling decision")
+	}
+	if _, err := New(slog.Default(), Options{Endpoint: "http://collector", SampleRatio: 1.5}); err == nil {
+		t.Errorf("New accepted sample ratio 1.5")
+	}
+}
+
+func mustSpanID(t *testing.T, value string) SpanID {
+	t.Helper()
+	sc, ok := ParseTraceparent("00-4bf92f3577b34da6a3ce929d0e0e4736-" + value + "-01")
+	if !ok {
+		t.Fatalf("invalid span id %q", value)
+	}
+	return sc.SpanID
+}
@@ -0,0 +1,453 @@
+// Copyright (c) 2025 HYPR. PTE. LTD.
+//
+// Business Source License 1.1
+// See LICENSE file in the project root for details.
+
+// Package webhooks delivers VM and deployment lifecycle events to registered
+// HTTP endpoints. Each delivery is signed with the webhook's secret, retried
+// with exponential backoff, and every attempt is kept for debugging.
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/volantvm/volant/internal/server/db"
+	"github.com/volantvm/volant/internal/server/eventbus"
+	orchestratorevents "github.com/volantvm/volant/internal/server/orchestrator/events"
+)
+
+// Headers sent with every delivery.
+const (
+	HeaderEvent    = "X-Volant-Event"
+	HeaderDelivery = "X-Volant-Delivery"
+	// HeaderSignature is "sha256=" followed by the hex HMAC-SHA256 of the
+	// body keyed with the webhook secret.
+	HeaderSignature = "X-Volant-Signature"
+)
+
+const (
+	// maxAttempts bounds how often one event is sent to one webhook.
+	maxAttempts = 6
+	// baseDelay doubles after every failed attempt, up to maxDelay.
+	baseDelay       = 2 * time.Second
+	maxDelay        = 5 * time.Minute
+	deliveryTimeout = 10 * time.Second
+	workers         = 4
+	queueSize       = 1024
+	// retention is how long delivery attempts are kept.
+	retention     = 7 * 24 * time.Hour
+	pruneInterval = time.Hour
+)
+
+var (
+	// ErrWebhookNotFound indicates the requested webhook does not exist.
+	ErrWebhookNotFound = errors.New("webhooks: webhook not found")
+	// ErrWebhookExists indicates a webhook with the same name already exists.
+	ErrWebhookExists = errors.New("webhooks: webhook already exists")
+	// ErrInvalidWebhook indicates the webhook definition failed validation.
+	ErrInvalidWebhook = errors.New("webhooks: invalid webhook")
+)
+
+// EventTypes returns the event types a webhook can subscribe to.
+func EventTypes() []string {
+	return []string{
+		orchestratorevents.TypeVMCreated,
+		orchestratorevents.TypeVMRunning,
+		orchestratorevents.TypeVMStopped,
+		orchestratorevents.TypeVMCrashed,
+		orchestratorevents.TypeVMDeleted,
+		orchestratorevents.TypeVMFailed,
+		orchestratorevents.TypeVMConfigUpdated,
+		orchestratorevents.TypeVMExpired,
+		orchestratorevents.TypeDeploymentReconciled,
+		orchestratorevents.TypeDeploymentDeleted,
+		orchestratorevents.TypeDeploymentFailed,
+	}
+}
+
+// Payload is the JSON body of a delivery. Data is the VMEvent or
+// DeploymentEvent that triggered it.
+type Payload struct {
+	ID        string    `json:"id"`
+	Type      string    `json:"type"`
+	Timestamp time.Time `json:"timestamp"`
+	Data      any       `json:"data"`
+}
+
+// Sign returns the HeaderSignature value for body.
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// CreateRequest defines a new webhook.
+type CreateRequest struct {
+	Name string
+	URL  string
+	// Events filters the event types delivered; empty delivers all of them.
+	Events []string
+	// Secret keys delivery signatures; one is generated when empty.
+	Secret   string
+	Disabled bool
+}
+
+type job struct {
+	webhook    db.Webhook
+	deliveryID string
+	eventType  string
+	body       []byte
+	attempt    int
+}
+
+// Manager stores webhooks and delivers bus events to them.
+type Manager struct {
+	store  db.Store
+	bus    eventbus.Bus
+	logger *slog.Logger
+	client *http.Client
+	now    func() time.Time
+	// delay returns the wait before retrying after the given attempt.
+	delay func(attempt int) time.Duration
+
+	jobs chan job
+}
+
+// New constructs a manager for store. Deliveries start with Run.
+func New(store db.Store, bus eventbus.Bus, logger *slog.Logger) *Manager {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &Manager{
+		store:  store,
+		bus:    bus,
+		logger: logger.With("component", "webhooks"),
+		client: &http.Client{Timeout: deliveryTimeout},
+		now:    time.Now,
+		delay:  backoff,
+		jobs:   make(chan job, queueSize),
+	}
+}
+
+func backoff(attempt int) time.Duration {
+	delay := baseDelay
+	for i := 1; i < attempt && delay < maxDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, maxDelay)
+}
+
+// Run delivers VM and deployment events until ctx is cancelled. Retries still
+// waiting when it returns are dropped.
+func (m *Manager) Run(ctx context.Context) {
+	events := make(chan any, 256)
+	if m.bus != nil {
+		for _, topic := range []string{orchestratorevents.TopicVMEvents, orchestratorevents.TopicDeploymentEvents} {
+			unsubscribe, err := m.bus.Subscribe(topic, events)
+			if err != nil {
+				m.logger.Error("subscribe to events", "topic", topic, "error", err)
+				continue
+			}
+			defer unsubscribe()
+		}
+	}
+	for i := 0; i < workers; i++ {
+		go m.work(ctx)
+	}
+	m.prune(ctx)
+	ticker := time.NewTicker(pruneInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case event := <-events:
+			m.dispatch(ctx, event)
+		case <-ticker.C:
+			m.prune(ctx)
+		}
+	}
+}
+
+// dispatch queues a delivery of event to every enabled webhook subscribed to
+// its type.
+func (m *Manager) dispatch(ctx context.Context, event any) {
+	var (
+		eventType string
+		timestamp time.Time
+	)
+	switch e := event.(type) {
+	case orchestratorevents.VMEvent:
+		eventType, timestamp = e.Type, e.Timestamp
+	case orchestratorevents.DeploymentEvent:
+		eventType, timestamp = e.Type, e.Timestamp
+	default:
+		return
+	}
+	if !slices.Contains(EventTypes(), eventType) {
+		return
+	}
+	webhooks, err := m.store.Queries().Webhooks().List(ctx)
+	if err != nil {
+		m.logger.Error("list webhooks", "error", err)
+		return
+	}
+	for _, webhook := range webhooks {
+		if !webhook.Enabled || (len(webhook.Events) > 0 && !slices.Contains(webhook.Events, eventType)) {
+			continue
+		}
+		id := newID()
+		body, err := json.Marshal(Payload{ID: id, Type: eventType, Timestamp: timestamp, Data: event})
+		if err != nil {
+			m.logger.Error("encode webhook payload", "webhook", webhook.Name, "type", eventType, "error", err)
+			continue
+		}
+		m.enqueue(ctx, job{webhook: webhook, deliveryID: id, eventType: eventType, body: body, attempt: 1})
+	}
+}
+
+func (m *Manager) enqueue(ctx context.Context, j job) {
+	select {
+	case m.jobs <- j:
+	case <-ctx.Done():
+	default:
+		m.logger.Warn("webhook queue full; delivery dropped", "webhook", j.webhook.Name, "type", j.eventType, "delivery", j.deliveryID)
+	}
+}
+
+func (m *Manager) work(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case j := <-m.jobs:
+			m.deliver(ctx, j)
+		}
+	}
+}
+
+// deliver makes one attempt and schedules the next when it failed and may
+// succeed on retry.
+func (m *Manager) deliver(ctx context.Context, j job) {
+	if j.attempt > 1 {
+		// Stop retrying webhooks removed or disabled since the first attempt.
+		current, err := m.store.Queries().Webhooks().GetByName(ctx, j.webhook.Name)
+		if err != nil {
+			m.logger.Error("reload webhook", "webhook", j.webhook.Name, "error", err)
+		} else if current == nil || current.ID != j.webhook.ID || !current.Enabled {
+			return
+		} else {
+			j.webhook = *current
+		}
+	}
+	record := db.WebhookDelivery{
+		WebhookID:  j.webhook.ID,
+		DeliveryID: j.deliveryID,
+		EventType:  j.eventType,
+		Attempt:    j.attempt,
+		Payload:    j.body,
+		CreatedAt:  m.now().UTC(),
+	}
+	retry := m.send(ctx, j, &record)
+	record.Duration = m.now().Sub(record.CreatedAt)
+	if _, err := m.store.Queries().Webhooks().RecordDelivery(context.WithoutCancel(ctx), record); err != nil {
+		m.logger.Error("record webhook delivery", "webhook", j.webhook.Name, "error", err)
+	}
+	if record.Succeeded || ctx.Err() != nil {
+		return
+	}
+	if !retry || j.attempt >= maxAttempts {
+		m.logger.Warn("webhook delivery failed", "webhook", j.webhook.Name, "type", j.eventType, "delivery", j.deliveryID, "attempts", j.attempt, "status", record.StatusCode, "error", record.Error)
+		return
+	}
+	next := j
+	next.attempt++
+	delay := m.delay(j.attempt)
+	go func() {
+		timer := time.NewTimer(delay)
+		defer timer.Stop()
+		select {
+		case <-ctx.Done():
+		case <-timer.C:
+			m.enqueue(ctx, next)
+		}
+	}()
+}
+
+// send posts the payload and fills in the outcome on record. It reports
+// whether a failure is worth retrying: transport errors, timeouts, rate
+// limits and server errors are; other client errors are not.
+func (m *Manager) send(ctx context.Context, j job, record *db.WebhookDelivery) bool {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, j.webhook.URL, bytes.NewReader(j.body))
+	if err != nil {
+		record.Error = err.Error()
+		return false
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "volantd-webhooks")
+	req.Header.Set(HeaderEvent, j.eventType)
+	req.Header.Set(HeaderDelivery, j.deliveryID)
+	req.Header.Set(HeaderSignature, Sign(j.webhook.Secret, j.body))
+	resp, err := m.client.Do(req)
+	if err != nil {
+		record.Error = err.Error()
+		return true
+	}
+	defer resp.Body.Close()
+	record.StatusCode = resp.StatusCode
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		record.Succeeded = true
+		_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+		return false
+	}
+	detail, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+	record.Error = strings.TrimSpace(fmt.Sprintf("%s: %s", resp.Status, detail))
+	return resp.StatusCode >= 500 || resp.StatusCode == http.StatusRequestTimeout || resp.StatusCode == http.StatusTooManyRequests
+}
+
+func (m *Manager) prune(ctx context.Context) {
+	n, err := m.store.Queries().Webhooks().DeleteDeliveriesBefore(ctx, m.now().Add(-retention))
+	if err != nil {
+		m.logger.Error("prune webhook deliveries", "error", err)
+		return
+	}
+	if n > 0 {
+		m.logger.Info("pruned webhook deliveries", "deleted", n)
+	}
+}
+
+// Create validates and stores a new webhook.
+func (m *Manager) Create(ctx context.Context, req CreateRequest) (*db.Webhook, error) {
+	webhook := db.Webhook{
+		Name:    strings.TrimSpace(req.Name),
+		URL:     strings.TrimSpace(req.URL),
+		Secret:  req.Secret,
+		Enabled: !req.Disabled,
+	}
+	for _, event := range req.Events {
+		event = strings.ToUpper(strings.TrimSpace(event))
+		if event != "" && !slices.Contains(webhook.Events, event) {
+			webhook.Events = append(webhook.Events, event)
+		}
+	}
+	if err := validate(webhook); err != nil {
+		return nil, err
+	}
+	if webhook.Secret == "" {
+		webhook.Secret = newSecret()
+	}
+
+	var created *db.Webhook
+	err := m.store.WithTx(ctx, func(q db.Queries) error {
+		existing, err := q.Webhooks().GetByName(ctx, webhook.Name)
+		if err != nil {
+			return err
+		}
+		if existing != nil {
+			return fmt.Errorf("%w: %s", ErrWebhookExists, webhook.Name)
+		}
+		if _, err := q.Webhooks().Create(ctx, &webhook); err != nil {
+			return err
+		}
+		created, err = q.Webhooks().GetByName(ctx, webhook.Name)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return created, nil
+}
+
+func validate(webhook db.Webhook) error {
+	if webhook.Name == "" {
+		return fmt.Errorf("%w: name is required", ErrInvalidWebhook)
+	}
+	parsed, err := url.Parse(webhook.URL)
+	if err != nil {
+		return fmt.Errorf("%w: url: %v", ErrInvalidWebhook, err)
+	}
+	if (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return fmt.Errorf("%w: url %q must be an absolute http or https URL", ErrInvalidWebhook, webhook.URL)
+	}
+	for _, event := range webhook.Events {
+		if !slices.Contains(EventTypes(), event) {
+			return fmt.Errorf("%w: unknown event type %q", ErrInvalidWebhook, event)
+		}
+	}
+	return nil
+}
+
+// List returns all webhooks ordered by name.
+func (m *Manager) List(ctx context.Context) ([]db.Webhook, error) {
+	return m.store.Queries().Webhooks().List(ctx)
+}
+
+// Get returns the named webhook.
+func (m *Manager) Get(ctx context.Context, name string) (*db.Webhook, error) {
+	webhook, err := m.store.Queries().Webhooks().GetByName(ctx, strings.TrimSpace(name))
+	if err != nil {
+		return nil, err
+	}
+	if webhook == nil {
+		return nil, fmt.Errorf("%w: %s", ErrWebhookNotFound, name)
+	}
+	return webhook, nil
+}
+
+// Delete removes the named webhook and its delivery history.
+func (m *Manager) Delete(ctx context.Context, name string) error {
+	webhook, err := m.Get(ctx, name)
+	if err != nil {
+		return err
+	}
+	return m.store.Queries().Webhooks().Delete(ctx, webhook.ID)
+}
+
+// SetEnabled pauses or resumes deliveries to the named webhook. Pausing also
+// drops pending retries.
+func (m *Manager) SetEnabled(ctx context.Context, name string, enabled bool) (*db.Webhook, error) {
+	webhook, err := m.Get(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	if err := m.store.Queries().Webhooks().SetEnabled(ctx, webhook.ID, enabled); err != nil {
+		return nil, err
+	}
+	return m.Get(ctx, name)
+}
+
+// Deliveries returns the most recent delivery attempts of the named webhook.
+func (m *Manager) Deliveries(ctx context.Context, name string, limit int) ([]db.WebhookDelivery, error) {
+	webhook, err := m.Get(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	return m.store.Queries().Webhooks().Deliveries(ctx, webhook.ID, limit)
+}
+
+func newID() string {
+	var b [16]byte
+	_, _ = rand.Read(b[:])
+	return hex.EncodeToString(b[:])
+}
+
+func newSecret() string {
+	var b [32]byte
+	_, _ = rand.Read(b[:])
+	return hex.EncodeToString(b[:])
+}
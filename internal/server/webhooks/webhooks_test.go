@@ -0,0 +1,197 @@
+// Copyright (c) 2025 HYPR. PTE. LTD.
+//
+// Business Source License 1.1
+// See LICENSE file in the project root for details.
+
+package webhooks
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/volantvm/volant/internal/server/db"
+	"github.com/volantvm/volant/internal/server/db/sqlite"
+	orchestratorevents "github.com/volantvm/volant/internal/server/orchestrator/events"
+)
+
+type receiver struct {
+	mu       sync.Mutex
+	statuses []int
+	requests []*http.Request
+	bodies   [][]byte
+}
+
+func (r *receiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	body, _ := io.ReadAll(req.Body)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.requests = append(r.requests, req)
+	r.bodies = append(r.bodies, body)
+	status := http.StatusNoContent
+	if len(r.statuses) > 0 {
+		status, r.statuses = r.statuses[0], r.statuses[1:]
+	}
+	w.WriteHeader(status)
+}
+
+func newTestManager(t *testing.T) (*Manager, context.Context) {
+	t.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+	store, err := sqlite.Open(ctx, filepath.Join(t.TempDir(), "volant.db"))
+	if err != nil {
+		t.Fatalf("open store: %v", err)
+	}
+	t.Cleanup(func() {
+		cancel()
+		_ = store.Close(context.Background())
+	})
+	m := New(store, nil, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	m.delay = func(int) time.Duration { return time.Millisecond }
+	for i := 0; i < workers; i++ {
+		go m.work(ctx)
+	}
+	return m, ctx
+}
+
+// waitForDeliveries polls the history of webhook until it holds n attempts.
+func waitForDeliveries(t *testing.T, m *Manager, webhook string, n int) []db.WebhookDelivery {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		deliveries, err := m.Deliveries(context.Background(), webhook, 0)
+		if err != nil {
+			t.Fatalf("deliveries: %v", err)
+		}
+		if len(deliveries) >= n {
+			return deliveries
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("expected %d deliveries to %s, got %+v", n, webhook, deliveries)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestDispatchSignsAndFilters(t *testing.T) {
+	m, ctx := newTestManager(t)
+	recv := &receiver{}
+	srv := httptest.NewServer(recv)
+	defer srv.Close()
+
+	if _, err := m.Create(ctx, CreateRequest{Name: "crashes", URL: srv.URL, Events: []string{"vm_crashed"}, Secret: "s3cret"}); err != nil {
+		t.Fatalf("create crashes: %v", err)
+	}
+	all, err := m.Create(ctx, CreateRequest{Name: "all", URL: srv.URL})
+	if err != nil {
+		t.Fatalf("create all: %v", err)
+	}
+	if len(all.Secret) != 64 {
+		t.Fatalf("expected a generated secret, got %q", all.Secret)
+	}
+
+	m.dispatch(ctx, orchestratorevents.VMEvent{Type: orchestratorevents.TypeVMRunning, Name: "web-1", Status: orchestratorevents.VMStatusRunning, Timestamp: time.Now().UTC()})
+	m.dispatch(ctx, orchestratorevents.VMEvent{Type: orchestratorevents.TypeVMLog, Name: "web-1", Line: "ignored"})
+	m.dispatch(ctx, orchestratorevents.VMEvent{Type: orchestratorevents.TypeVMCrashed, Name: "web-1", Status: orchestratorevents.VMStatusCrashed})
+
+	waitForDeliveries(t, m, "all", 2)
+	crashes := waitForDeliveries(t, m, "crashes", 1)
+	if len(crashes) != 1 || crashes[0].EventType != orchestratorevents.TypeVMCrashed || !crashes[0].Succeeded || crashes[0].StatusCode != http.StatusNoContent {
+		t.Fatalf("unexpected crash deliveries %+v", crashes)
+	}
+
+	recv.mu.Lock()
+	defer recv.mu.Unlock()
+	if len(recv.requests) != 3 {
+		t.Fatalf("expected 3 requests, got %d", len(recv.requests))
+	}
+	var signed int
+	for i, req := range recv.requests {
+		var payload struct {
+			ID   string                     `json:"id"`
+			Type string                     `json:"type"`
+			Data orchestratorevents.VMEvent `json:"data"`
+		}
+		if err := json.Unmarshal(recv.bodies[i], &payload); err != nil {
+			t.Fatalf("decode payload: %v", err)
+		}
+		if payload.Type != req.Header.Get(HeaderEvent) || payload.ID != req.Header.Get(HeaderDelivery) || payload.Data.Name != "web-1" {
+			t.Errorf("headers %v do not match payload %+v", req.Header, payload)
+		}
+		if req.Header.Get(HeaderSignature) == Sign("s3cret", recv.bodies[i]) {
+			signed++
+			if payload.Type != orchestratorevents.TypeVMCrashed {
+				t.Errorf("crashes webhook received %s", payload.Type)
+			}
+		}
+	}
+	if signed != 1 {
+		t.Errorf("expected one delivery signed with the crashes secret, got %d", signed)
+	}
+}
+
+func TestDeliverRetriesServerErrors(t *testing.T) {
+	m, ctx := newTestManager(t)
+	recv := &receiver{statuses: []int{http.StatusBadGateway, http.StatusTooManyRequests, http.StatusOK, http.StatusBadRequest}}
+	srv := httptest.NewServer(recv)
+	defer srv.Close()
+
+	if _, err := m.Create(ctx, CreateRequest{Name: "flaky", URL: srv.URL}); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	m.dispatch(ctx, orchestratorevents.DeploymentEvent{Type: orchestratorevents.TypeDeploymentReconciled, Name: "web", DesiredReplicas: 2})
+	deliveries := waitForDeliveries(t, m, "flaky", 3)
+	if deliveries[0].Attempt != 3 || !deliveries[0].Succeeded || deliveries[2].StatusCode != http.StatusBadGateway || deliveries[2].Error == "" {
+		t.Fatalf("unexpected attempts %+v", deliveries)
+	}
+	if deliveries[0].DeliveryID != deliveries[2].DeliveryID {
+		t.Errorf("retries used different delivery ids")
+	}
+
+	// Client errors other than 408 and 429 are not retried.
+	m.dispatch(ctx, orchestratorevents.DeploymentEvent{Type: orchestratorevents.TypeDeploymentDeleted, Name: "web"})
+	deliveries = waitForDeliveries(t, m, "flaky", 4)
+	time.Sleep(50 * time.Millisecond)
+	if deliveries, _ = m.Deliveries(ctx, "flaky", 0); len(deliveries) != 4 || deliveries[0].StatusCode != http.StatusBadRequest || deliveries[0].Succeeded {
+		t.Fatalf("expected a single failed attempt for the 400, got %+v", deliveries)
+	}
+}
+
+func TestCreateValidates(t *testing.T) {
+	m, ctx := newTestManager(t)
+	for _, req := range []CreateRequest{
+		{URL: "https://hooks.example"},
+		{Name: "relative", URL: "/hooks"},
+		{Name: "ftp", URL: "ftp://hooks.example"},
+		{Name: "unknown", URL: "https://hooks.example", Events: []string{"VM_EXPLODED"}},
+	} {
+		if _, err := m.Create(ctx, req); !errors.Is(err, ErrInvalidWebhook) {
+			t.Errorf("Create(%+v) = %v, want ErrInvalidWebhook", req, err)
+		}
+	}
+	if _, err := m.Create(ctx, CreateRequest{Name: "ops", URL: "https://hooks.example"}); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	if _, err := m.Create(ctx, CreateRequest{Name: "ops", URL: "https://hooks.example"}); !errors.Is(err, ErrWebhookExists) {
+		t.Errorf("duplicate create = %v, want ErrWebhookExists", err)
+	}
+	if err := m.Delete(ctx, "missing"); !errors.Is(err, ErrWebhookNotFound) {
+		t.Errorf("delete missing = %v, want ErrWebhookNotFound", err)
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	for attempt, want := range map[int]time.Duration{1: 2 * time.Second, 2: 4 * time.Second, 5: 32 * time.Second, 20: maxDelay} {
+		if got := backoff(attempt); got != want {
+			t.Errorf("backoff(%d) = %v, want %v", attempt, got, want)
+		}
+	}
+}
@@ -0,0 +1,124 @@
+// Copyright (c) 2025 HYPR. PTE. LTD.
+//
+// Business Source License 1.1
+// See LICENSE file in the project root for details.
+
+package setup
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultHypervisorVersion is the cloud-hypervisor release volantd is
+	// tested against.
+	DefaultHypervisorVersion = "v44.0"
+	// DefaultHypervisorPath is where a downloaded cloud-hypervisor goes.
+	DefaultHypervisorPath = "/usr/local/bin/cloud-hypervisor"
+
+	hypervisorReleaseURL = "https://github.com/cloud-hypervisor/cloud-hypervisor/releases/download"
+	downloadTimeout      = 5 * time.Minute
+)
+
+// installHypervisor downloads the static cloud-hypervisor build of version
+// to path, unless that version is already installed there.
+func installHypervisor(ctx context.Context, path, version, sum string, dryRun bool, res *Result) error {
+	if installed, ok := hypervisorVersion(ctx, path); ok && sameVersion(installed, version) {
+		return nil
+	}
+	asset := "cloud-hypervisor-static"
+	switch runtime.GOARCH {
+	case "amd64":
+	case "arm64":
+		asset += "-aarch64"
+	default:
+		return fmt.Errorf("no cloud-hypervisor build for %s", runtime.GOARCH)
+	}
+	url := fmt.Sprintf("%s/%s/%s", hypervisorReleaseURL, version, asset)
+	res.Commands = append(res.Commands, fmt.Sprintf("download %s to %s", url, path))
+	if dryRun {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, downloadTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("download cloud-hypervisor %s: %w", version, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("download cloud-hypervisor %s: %s", version, resp.Status)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("create %s: %w", filepath.Dir(path), err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".cloud-hypervisor-*")
+	if err != nil {
+		return fmt.Errorf("download cloud-hypervisor: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	hash := sha256.New()
+	if _, err := io.Copy(io.MultiWriter(tmp, hash), resp.Body); err != nil {
+		tmp.Close()
+		return fmt.Errorf("download cloud-hypervisor %s: %w", version, err)
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if got := hex.EncodeToString(hash.Sum(nil)); sum != "" && !strings.EqualFold(got, sum) {
+		return fmt.Errorf("cloud-hypervisor %s checksum mismatch: got sha256 %s, want %s", version, got, sum)
+	}
+	if err := os.Chmod(tmp.Name(), 0o755); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("install cloud-hypervisor: %w", err)
+	}
+	return nil
+}
+
+// hypervisorVersion returns the version cloud-hypervisor at path reports,
+// such as v44.0.0.
+func hypervisorVersion(ctx context.Context, path string) (string, bool) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	out, err := exec.CommandContext(ctx, path, "--version").Output()
+	if err != nil {
+		return "", false
+	}
+	fields := strings.Fields(string(out))
+	if len(fields) == 0 {
+		return "", false
+	}
+	return fields[len(fields)-1], true
+}
+
+// sameVersion compares release versions, ignoring the v prefix and
+// trailing .0 components, so v44.0 matches v44.0.0.
+func sameVersion(a, b string) bool {
+	normalize := func(v string) string {
+		v = strings.TrimPrefix(strings.TrimSpace(v), "v")
+		for strings.HasSuffix(v, ".0") {
+			v = strings.TrimSuffix(v, ".0")
+		}
+		return v
+	}
+	return normalize(a) == normalize(b)
+}
@@ -0,0 +1,76 @@
+// Copyright (c) 2025 HYPR. PTE. LTD.
+//
+// Business Source License 1.1
+// See LICENSE file in the project root for details.
+
+package setup
+
+import (
+	"fmt"
+	"os"
+	"os/user"
+	"strconv"
+)
+
+// owner is the account volantd runs as.
+type owner struct {
+	user, group string
+	uid, gid    int
+}
+
+// lookupOwner resolves the user and group volantd runs as; the group
+// defaults to the user's primary group.
+func lookupOwner(name, group string) (owner, error) {
+	if name == "" {
+		name = "root"
+	}
+	u, err := user.Lookup(name)
+	if err != nil {
+		return owner{}, fmt.Errorf("lookup user %s: %w", name, err)
+	}
+	o := owner{user: name}
+	if o.uid, err = strconv.Atoi(u.Uid); err != nil {
+		return owner{}, fmt.Errorf("user %s: uid %q: %w", name, u.Uid, err)
+	}
+	gid := u.Gid
+	if group != "" {
+		g, err := user.LookupGroup(group)
+		if err != nil {
+			return owner{}, fmt.Errorf("lookup group %s: %w", group, err)
+		}
+		gid = g.Gid
+		o.group = group
+	} else {
+		g, err := user.LookupGroupId(gid)
+		if err != nil {
+			return owner{}, fmt.Errorf("lookup group %s of user %s: %w", gid, name, err)
+		}
+		o.group = g.Name
+	}
+	if o.gid, err = strconv.Atoi(gid); err != nil {
+		return owner{}, fmt.Errorf("group %s: gid %q: %w", o.group, gid, err)
+	}
+	return o, nil
+}
+
+// chown hands dir to the owner unless it already holds it.
+func (o owner) chown(dir string, dryRun bool, res *Result) error {
+	uid, gid, ok := fileOwner(dir)
+	if ok && uid == o.uid && gid == o.gid {
+		return nil
+	}
+	if dryRun {
+		// A directory setup is about to create as root needs no chown
+		// when root owns it.
+		if !ok && o.uid == 0 && o.gid == 0 {
+			return nil
+		}
+		res.Commands = append(res.Commands, fmt.Sprintf("chown %s:%s %s", o.user, o.group, dir))
+		return nil
+	}
+	if err := os.Chown(dir, o.uid, o.gid); err != nil {
+		return fmt.Errorf("chown %s: %w", dir, err)
+	}
+	res.Commands = append(res.Commands, fmt.Sprintf("chown %s:%s %s", o.user, o.group, dir))
+	return nil
+}
@@ -0,0 +1,12 @@
+// Copyright (c) 2025 HYPR. PTE. LTD.
+//
+// Business Source License 1.1
+// See LICENSE file in the project root for details.
+
+//go:build windows
+
+package setup
+
+func fileOwner(path string) (int, int, bool) {
+	return 0, 0, false
+}
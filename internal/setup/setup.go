@@ -10,6 +10,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"net"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -18,14 +19,21 @@ import (
 
 // Options controls the behaviour of the setup routine.
 type Options struct {
-	BridgeName  string
-	SubnetCIDR  string
-	HostCIDR    string
-	DryRun      bool
-	RuntimeDir  string
-	LogDir      string
-	ServicePath string
-	BinaryPath  string
+	BridgeName string
+	SubnetCIDR string
+	HostCIDR   string
+	// Subnet6CIDR and HostCIDR6 add an IPv6 subnet to the bridge for
+	// dual-stack guests. Both empty leaves the host IPv4-only.
+	Subnet6CIDR string
+	HostCIDR6   string
+	// NDPProxyInterface publishes guest IPv6 addresses on this uplink instead
+	// of masquerading them, for hosts with a routed IPv6 prefix.
+	NDPProxyInterface string
+	DryRun            bool
+	RuntimeDir        string
+	LogDir            string
+	ServicePath       string
+	BinaryPath        string
 	// BZImagePath points to the bzImage kernel used for rootfs-based boot.
 	// Example: /var/lib/volant/kernel/bzImage
 	BZImagePath string
@@ -35,6 +43,20 @@ type Options struct {
 	// WorkDir is the WorkingDirectory for the volantd systemd unit.
 	// Example: /var/lib/volant
 	WorkDir string
+	// User and Group own the runtime, log and work directories and run the
+	// volantd unit. Empty means root.
+	User  string
+	Group string
+	// ConfigFile is passed to volantd with --config by the unit.
+	ConfigFile string
+	// HypervisorPath is the cloud-hypervisor binary volantd runs; empty
+	// means cloud-hypervisor on PATH.
+	HypervisorPath string
+	// HypervisorVersion, when set, installs that cloud-hypervisor release
+	// at HypervisorPath (default DefaultHypervisorPath) unless it is already
+	// there. HypervisorSHA256 pins the expected checksum of the download.
+	HypervisorVersion string
+	HypervisorSHA256  string
 }
 
 // Result collects output and executed commands.
@@ -73,7 +95,7 @@ func Run(ctx context.Context, opts Options) (*Result, error) {
 
 	if !opts.DryRun {
 		if os.Geteuid() != 0 {
-			return nil, errors.New("volar setup must be run as root (use --dry-run to preview)")
+			return nil, errors.New("setup must be run as root (use --dry-run to preview)")
 		}
 	}
 
@@ -126,16 +148,19 @@ func Run(ctx context.Context, opts Options) (*Result, error) {
 		return nil, fmt.Errorf("expand binary path: %w", err)
 	}
 
-	// Ensure directories exist.
-	if err := ensureDir(runtimeDir, opts.DryRun, res); err != nil {
-		return nil, err
-	}
-	if err := ensureDir(logDir, opts.DryRun, res); err != nil {
+	owner, err := lookupOwner(opts.User, opts.Group)
+	if err != nil {
 		return nil, err
 	}
-	// Ensure working directory (and kernel dir) exist
-	if err := ensureDir(workDir, opts.DryRun, res); err != nil {
-		return nil, err
+
+	// Ensure directories exist, owned by the user volantd runs as.
+	for _, dir := range []string{runtimeDir, logDir, workDir} {
+		if err := ensureDir(dir, opts.DryRun, res); err != nil {
+			return nil, err
+		}
+		if err := owner.chown(dir, opts.DryRun, res); err != nil {
+			return nil, err
+		}
 	}
 	if err := ensureDir(filepath.Dir(bzImagePath), opts.DryRun, res); err != nil {
 		return nil, err
@@ -144,8 +169,24 @@ func Run(ctx context.Context, opts Options) (*Result, error) {
 		return nil, err
 	}
 
+	if opts.HypervisorVersion != "" {
+		if opts.HypervisorPath == "" {
+			opts.HypervisorPath = DefaultHypervisorPath
+		}
+		if err := installHypervisor(ctx, opts.HypervisorPath, opts.HypervisorVersion, opts.HypervisorSHA256, opts.DryRun, res); err != nil {
+			return nil, err
+		}
+	}
+
 	// Ensure ip, iptables, cloud-hypervisor binaries exist.
-	required := []string{"ip", "iptables", "cloud-hypervisor"}
+	hypervisor := opts.HypervisorPath
+	if hypervisor == "" {
+		hypervisor = "cloud-hypervisor"
+	}
+	required := []string{"ip", "iptables"}
+	if !opts.DryRun || opts.HypervisorVersion == "" {
+		required = append(required, hypervisor)
+	}
 	for _, bin := range required {
 		if err := ensureBinary(bin); err != nil {
 			return nil, err
@@ -167,6 +208,11 @@ func Run(ctx context.Context, opts Options) (*Result, error) {
 	if err := writeFile("/proc/sys/net/ipv4/ip_forward", "1\n", opts.DryRun, res); err != nil {
 		return nil, err
 	}
+	if opts.Subnet6CIDR != "" {
+		if err := setupIPv6(ctx, opts, res); err != nil {
+			return nil, err
+		}
+	}
 
 	// iptables rules (idempotent).
 	iptablesRules := [][]string{
@@ -187,7 +233,7 @@ func Run(ctx context.Context, opts Options) (*Result, error) {
 	}
 
 	if opts.ServicePath != "" {
-		if err := writeServiceFile(binaryPath, opts, runtimeDir, logDir, workDir, bzImagePath, vmlinuxPath, opts.DryRun, res); err != nil {
+		if err := writeServiceFile(binaryPath, opts, owner, runtimeDir, logDir, workDir, bzImagePath, vmlinuxPath, opts.DryRun, res); err != nil {
 			return nil, err
 		}
 		// Optionally enable and start the service automatically
@@ -203,6 +249,21 @@ func Run(ctx context.Context, opts Options) (*Result, error) {
 	return res, nil
 }
 
+// HostCIDR returns the host address with the prefix length of subnet, as
+// assigned to the bridge.
+func HostCIDR(subnet, host string) (string, error) {
+	_, network, err := net.ParseCIDR(subnet)
+	if err != nil {
+		return "", fmt.Errorf("invalid subnet %s: %w", subnet, err)
+	}
+	ip := net.ParseIP(host)
+	if ip == nil {
+		return "", fmt.Errorf("invalid host ip %s", host)
+	}
+	mask, _ := network.Mask.Size()
+	return fmt.Sprintf("%s/%d", ip.String(), mask), nil
+}
+
 func ensureBinary(name string) error {
 	if _, err := exec.LookPath(name); err != nil {
 		return fmt.Errorf("required binary %s not found in PATH", name)
@@ -263,20 +324,40 @@ func writeFile(path, data string, dryRun bool, res *Result) error {
 	return os.WriteFile(path, []byte(data), 0o644)
 }
 
-func writeServiceFile(binaryPath string, opts Options, runtimeDir, logDir, workDir, bzImagePath, vmlinuxPath string, dryRun bool, res *Result) error {
+func writeServiceFile(binaryPath string, opts Options, owner owner, runtimeDir, logDir, workDir, bzImagePath, vmlinuxPath string, dryRun bool, res *Result) error {
 	if binaryPath == "" {
 		return errors.New("server binary path required when writing service file")
 	}
+	execStart := binaryPath
+	if opts.ConfigFile != "" {
+		execStart += " --config " + opts.ConfigFile
+	}
 
 	logFile := filepath.Join(logDir, "volantd.log")
+	var extraEnv strings.Builder
+	if opts.Subnet6CIDR != "" {
+		fmt.Fprintf(&extraEnv, "Environment=VOLANT_SUBNET6=%s\n", opts.Subnet6CIDR)
+		fmt.Fprintf(&extraEnv, "Environment=VOLANT_HOST_IP6=%s\n", strings.SplitN(opts.HostCIDR6, "/", 2)[0])
+		if opts.NDPProxyInterface != "" {
+			fmt.Fprintf(&extraEnv, "Environment=VOLANT_NDP_PROXY_IFACE=%s\n", opts.NDPProxyInterface)
+		}
+	}
+	if opts.HypervisorPath != "" {
+		fmt.Fprintf(&extraEnv, "Environment=VOLANT_HYPERVISOR=%s\n", opts.HypervisorPath)
+	}
+	if owner.user != "root" {
+		// Taps, bridges and nftables rules need CAP_NET_ADMIN; the user
+		// must also be able to open /dev/kvm (usually the kvm group).
+		extraEnv.WriteString("AmbientCapabilities=CAP_NET_ADMIN CAP_NET_RAW\n")
+	}
 	service := fmt.Sprintf(`[Unit]
 Description=VOLANT Control Plane
 After=network.target
 
 [Service]
 Type=simple
-User=root
-Group=root
+User=%s
+Group=%s
 WorkingDirectory=%s
 Environment=VOLANT_BRIDGE=%s
 Environment=VOLANT_SUBNET=%s
@@ -284,7 +365,7 @@ Environment=VOLANT_RUNTIME_DIR=%s
 Environment=VOLANT_LOG_DIR=%s
 Environment=VOLANT_KERNEL_BZIMAGE=%s
 Environment=VOLANT_KERNEL_VMLINUX=%s
-ExecStart=%s
+%sExecStart=%s
 Restart=always
 RestartSec=5
 StandardOutput=append:%s
@@ -293,6 +374,8 @@ StandardError=append:%s
 [Install]
 WantedBy=multi-user.target
 `,
+		owner.user,
+		owner.group,
 		workDir,
 		opts.BridgeName,
 		opts.SubnetCIDR,
@@ -300,7 +383,8 @@ WantedBy=multi-user.target
 		logDir,
 		bzImagePath,
 		vmlinuxPath,
-		binaryPath,
+		extraEnv.String(),
+		execStart,
 		logFile,
 		logFile,
 	)
@@ -326,3 +410,40 @@ WantedBy=multi-user.target
 	}
 	return nil
 }
+
+// setupIPv6 assigns the host IPv6 address to the bridge and enables IPv6
+// forwarding. Guests are masqueraded unless an NDP proxy uplink is set, in
+// which case the subnet is expected to be routed to this host.
+func setupIPv6(ctx context.Context, opts Options, res *Result) error {
+	if opts.HostCIDR6 == "" {
+		return fmt.Errorf("ipv6 subnet %s requires a host ipv6 address", opts.Subnet6CIDR)
+	}
+	if err := runCommand(ctx, []string{"ip", "-6", "addr", "replace", opts.HostCIDR6, "dev", opts.BridgeName}, opts.DryRun, res, false); err != nil {
+		return err
+	}
+	if err := writeFile("/proc/sys/net/ipv6/conf/all/forwarding", "1\n", opts.DryRun, res); err != nil {
+		return err
+	}
+	if opts.NDPProxyInterface != "" {
+		if err := writeFile(filepath.Join("/proc/sys/net/ipv6/conf", opts.NDPProxyInterface, "proxy_ndp"), "1\n", opts.DryRun, res); err != nil {
+			return err
+		}
+	} else {
+		check := []string{"ip6tables", "-t", "nat", "-C", "POSTROUTING", "-s", opts.Subnet6CIDR, "!", "-o", opts.BridgeName, "-j", "MASQUERADE"}
+		add := []string{"ip6tables", "-t", "nat", "-A", "POSTROUTING", "-s", opts.Subnet6CIDR, "!", "-o", opts.BridgeName, "-j", "MASQUERADE"}
+		if err := ensureIptablesRule(ctx, check, add, opts.DryRun, res); err != nil {
+			return err
+		}
+	}
+	forwardRules := [][]string{
+		{"ip6tables", "-C", "FORWARD", "-i", opts.BridgeName, "-j", "ACCEPT"},
+		{"ip6tables", "-C", "FORWARD", "-o", opts.BridgeName, "-j", "ACCEPT"},
+	}
+	for _, check := range forwardRules {
+		add := append([]string{"ip6tables", "-A"}, check[2:]...)
+		if err := ensureIptablesRule(ctx, check, add, opts.DryRun, res); err != nil {
+			return err
+		}
+	}
+	return nil
+}
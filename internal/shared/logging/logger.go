@@ -6,12 +6,37 @@
 package logging
 
 import (
+	"fmt"
 	"log/slog"
 	"os"
+	"strings"
 )
 
+// level is shared by every logger New returns, so SetLevel changes them all,
+// including loggers derived with With.
+var level slog.LevelVar
+
 // New returns a slog.Logger configured for structured, JSON-oriented output.
 func New(subsystem string) *slog.Logger {
-	handler := slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{AddSource: true})
+	handler := slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{AddSource: true, Level: &level})
 	return slog.New(handler).With("subsystem", subsystem)
 }
+
+// ParseLevel parses debug, info, warn or error, case-insensitively. Empty
+// means info.
+func ParseLevel(value string) (slog.Level, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return slog.LevelInfo, nil
+	}
+	var parsed slog.Level
+	if err := parsed.UnmarshalText([]byte(value)); err != nil {
+		return 0, fmt.Errorf("invalid log level %q (want debug, info, warn or error)", value)
+	}
+	return parsed, nil
+}
+
+// SetLevel sets the minimum level of the loggers New returned.
+func SetLevel(l slog.Level) {
+	level.Set(l)
+}
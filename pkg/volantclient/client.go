@@ -0,0 +1,2782 @@
+// Copyright (c) 2025 HYPR. PTE. LTD.
+//
+// Business Source License 1.1
+// See LICENSE file in the project root for details.
+
+package volantclient
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	"github.com/volantvm/volant/internal/pluginspec"
+	"github.com/volantvm/volant/internal/server/apiauth"
+	orchestratorevents "github.com/volantvm/volant/internal/server/orchestrator/events"
+	"github.com/volantvm/volant/internal/vsock"
+)
+
+// Client wraps REST access to the volantd API.
+type Client struct {
+	baseURL    *url.URL
+	httpClient *http.Client
+	// socketPath is set when the API is reached over a Unix domain socket.
+	socketPath string
+	apiKey     string
+	tlsConfig  *tls.Config
+}
+
+// Options configures how a Client authenticates to volantd.
+type Options struct {
+	// APIKey is sent with every request when set.
+	APIKey string
+	// TLS configures https and wss connections; nil uses the system roots.
+	TLS *tls.Config
+}
+
+// New creates a client with the provided base URL (e.g. http://127.0.0.1:7777
+// or unix:///run/volant.sock).
+func New(rawURL string) (*Client, error) {
+	return NewWithOptions(rawURL, Options{})
+}
+
+// NewWithOptions creates a client for rawURL that authenticates with opts.
+func NewWithOptions(rawURL string, opts Options) (*Client, error) {
+	if rawURL == "" {
+		rawURL = "http://127.0.0.1:7777"
+	}
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("client: parse url: %w", err)
+	}
+	c := &Client{
+		baseURL: parsed,
+		httpClient: &http.Client{
+			Timeout: 30 * time.Second,
+		},
+		apiKey:    opts.APIKey,
+		tlsConfig: opts.TLS,
+	}
+	if parsed.Scheme == "unix" {
+		if parsed.Path == "" {
+			return nil, fmt.Errorf("client: unix url %q has no socket path", rawURL)
+		}
+		c.socketPath = parsed.Path
+		c.baseURL = &url.URL{Scheme: "http", Host: "volantd"}
+		transport := http.DefaultTransport.(*http.Transport).Clone()
+		transport.Proxy = nil
+		transport.DialContext = c.dialSocket
+		c.httpClient.Transport = transport
+	} else if opts.TLS != nil {
+		transport := http.DefaultTransport.(*http.Transport).Clone()
+		transport.TLSClientConfig = opts.TLS
+		c.httpClient.Transport = transport
+	}
+	if opts.APIKey != "" {
+		c.httpClient.Transport = &apiKeyTransport{base: c.httpClient.Transport, key: opts.APIKey}
+	}
+	return c, nil
+}
+
+// apiKeyTransport adds the API key header to every request.
+type apiKeyTransport struct {
+	base http.RoundTripper
+	key  string
+}
+
+func (t *apiKeyTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := t.base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	req = req.Clone(req.Context())
+	req.Header.Set(apiauth.Header, t.key)
+	return base.RoundTrip(req)
+}
+
+func (c *Client) dialSocket(ctx context.Context, _, _ string) (net.Conn, error) {
+	var dialer net.Dialer
+	return dialer.DialContext(ctx, "unix", c.socketPath)
+}
+
+// VM represents the API response for a microVM.
+type VM struct {
+	ID            int64             `json:"id"`
+	Name          string            `json:"name"`
+	Status        string            `json:"status"`
+	Runtime       string            `json:"runtime"`
+	PID           *int64            `json:"pid,omitempty"`
+	IPAddress     string            `json:"ip_address"`
+	IPv6Address   string            `json:"ipv6_address,omitempty"`
+	MACAddress    string            `json:"mac_address"`
+	VsockCID      uint32            `json:"vsock_cid"`
+	CPUCores      int               `json:"cpu_cores"`
+	MemoryMB      int               `json:"memory_mb"`
+	KernelCmdline string            `json:"kernel_cmdline,omitempty"`
+	SerialSocket  string            `json:"serial_socket,omitempty"`
+	ConsoleSocket string            `json:"console_socket,omitempty"`
+	Labels        map[string]string `json:"labels,omitempty"`
+	// ExpiresAt is when the VM's TTL runs out; nil when it has none.
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
+
+// CreateVMRequest contains creation parameters.
+type CreateVMRequest struct {
+	Name          string            `json:"name"`
+	Plugin        string            `json:"plugin"`
+	Runtime       string            `json:"runtime,omitempty"`
+	CPUCores      int               `json:"cpu_cores"`
+	MemoryMB      int               `json:"memory_mb"`
+	KernelCmdline string            `json:"kernel_cmdline,omitempty"`
+	APIHost       string            `json:"api_host,omitempty"`
+	APIPort       string            `json:"api_port,omitempty"`
+	Config        *VMConfig         `json:"config,omitempty"`
+	Labels        map[string]string `json:"labels,omitempty"`
+	// RequestID makes the create idempotent: retries with the same ID and
+	// payload return the first response instead of creating another VM.
+	RequestID string `json:"request_id,omitempty"`
+	// TTLSeconds stops the VM after it has run that long, or deletes it when
+	// TTLAction is "delete". 0 uses the plugin's default.
+	TTLSeconds int    `json:"ttl_seconds,omitempty"`
+	TTLAction  string `json:"ttl_action,omitempty"`
+}
+
+// BulkVMResult reports the outcome of a bulk action for one VM.
+type BulkVMResult struct {
+	Name  string `json:"name"`
+	Error string `json:"error,omitempty"`
+}
+
+// BulkVMResponse summarises a bulk action over the VMs matching a selector.
+type BulkVMResponse struct {
+	Action    string         `json:"action"`
+	Selector  string         `json:"selector"`
+	Matched   int            `json:"matched"`
+	Succeeded int            `json:"succeeded"`
+	Failed    int            `json:"failed"`
+	Results   []BulkVMResult `json:"results"`
+}
+
+// Deployment represents a VM deployment group. Condition is available,
+// progressing or failed.
+type Deployment struct {
+	Name            string          `json:"name"`
+	DesiredReplicas int             `json:"desired_replicas"`
+	ReadyReplicas   int             `json:"ready_replicas"`
+	MaxSurge        int             `json:"max_surge,omitempty"`
+	Config          VMConfig        `json:"config"`
+	Condition       string          `json:"condition"`
+	Reconcile       ReconcileStatus `json:"reconcile"`
+	CreatedAt       time.Time       `json:"created_at"`
+	UpdatedAt       time.Time       `json:"updated_at"`
+}
+
+// ReconcileStatus reports the deployment reconciler's progress. State is
+// synced, pending, reconciling, retrying or failed; LastError is the error of
+// the last pass until one succeeds.
+type ReconcileStatus struct {
+	State            string     `json:"state"`
+	LastReconciledAt *time.Time `json:"last_reconciled_at,omitempty"`
+	LastError        string     `json:"last_error,omitempty"`
+	Failures         int        `json:"failures,omitempty"`
+	NextRetryAt      *time.Time `json:"next_retry_at,omitempty"`
+	FailedAt         *time.Time `json:"failed_at,omitempty"`
+}
+
+// CreateDeploymentRequest captures deployment creation inputs.
+type CreateDeploymentRequest struct {
+	Name     string   `json:"name"`
+	Replicas int      `json:"replicas"`
+	MaxSurge int      `json:"max_surge,omitempty"`
+	Config   VMConfig `json:"config"`
+	// RequestID makes the create idempotent; see CreateVMRequest.RequestID.
+	RequestID string `json:"request_id,omitempty"`
+}
+
+const (
+	VMEventTypeCreated = orchestratorevents.TypeVMCreated
+	VMEventTypeRunning = orchestratorevents.TypeVMRunning
+	VMEventTypeStopped = orchestratorevents.TypeVMStopped
+	VMEventTypeCrashed = orchestratorevents.TypeVMCrashed
+	VMEventTypeDeleted = orchestratorevents.TypeVMDeleted
+	VMEventTypeLog     = orchestratorevents.TypeVMLog
+	// VMEventTypeConfigUpdated reports a new configuration version.
+	VMEventTypeConfigUpdated = orchestratorevents.TypeVMConfigUpdated
+	// VMEventTypeExpired reports that a VM's TTL ran out.
+	VMEventTypeExpired = orchestratorevents.TypeVMExpired
+)
+
+const (
+	VMLogStreamStdout = orchestratorevents.LogStreamStdout
+	VMLogStreamStderr = orchestratorevents.LogStreamStderr
+)
+
+// VMEvent represents a lifecycle event streamed from the server.
+type VMEvent = orchestratorevents.VMEvent
+
+// VMLogEvent represents a single log line emitted by a VM or agent process.
+type VMLogEvent struct {
+	Name      string    `json:"name"`
+	Stream    string    `json:"stream"`
+	Line      string    `json:"line"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+type DevToolsInfo struct {
+	WebSocketURL   string `json:"websocket_url"`
+	WebSocketPath  string `json:"websocket_path"`
+	BrowserVersion string `json:"browser_version"`
+	UserAgent      string `json:"user_agent"`
+	Address        string `json:"address"`
+	Port           int    `json:"port"`
+}
+
+// SyncFile is a file pushed into the guest by the dev sync channel.
+type SyncFile struct {
+	Path    string      `json:"path"`
+	Mode    os.FileMode `json:"mode,omitempty"`
+	Content []byte      `json:"content"`
+}
+
+// SyncBatch groups file writes and deletions relative to a guest directory.
+type SyncBatch struct {
+	Root            string     `json:"root"`
+	Files           []SyncFile `json:"files,omitempty"`
+	Deletes         []string   `json:"deletes,omitempty"`
+	RestartWorkload bool       `json:"restart_workload,omitempty"`
+}
+
+// SyncResult reports what the agent applied for a batch.
+type SyncResult struct {
+	Written   int  `json:"written"`
+	Deleted   int  `json:"deleted"`
+	Restarted bool `json:"restarted"`
+}
+
+// MCPRequest is a JSON-RPC 2.0 request to the MCP endpoint.
+type MCPRequest struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      int64           `json:"id"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+}
+
+// MCPResponse is a JSON-RPC 2.0 response from the MCP endpoint.
+type MCPResponse struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  json.RawMessage `json:"result,omitempty"`
+	Error   *MCPError       `json:"error,omitempty"`
+}
+
+// MCPError is a JSON-RPC 2.0 error object.
+type MCPError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+type Plugin = pluginspec.Manifest
+
+func (c *Client) ListVMs(ctx context.Context) ([]VM, error) {
+	req, err := c.newRequest(ctx, http.MethodGet, "/api/v1/vms", nil)
+	if err != nil {
+		return nil, err
+	}
+	var vms []VM
+	if err := c.do(req, &vms); err != nil {
+		return nil, err
+	}
+	return vms, nil
+}
+
+// ListVMsBySelector lists the VMs whose labels match a label selector.
+func (c *Client) ListVMsBySelector(ctx context.Context, selector string) ([]VM, error) {
+	req, err := c.newRequest(ctx, http.MethodGet, "/api/v1/vms?selector="+url.QueryEscape(selector), nil)
+	if err != nil {
+		return nil, err
+	}
+	var vms []VM
+	if err := c.do(req, &vms); err != nil {
+		return nil, err
+	}
+	return vms, nil
+}
+
+// UpdateVMLabels merges patch into a VM's labels; nil values remove keys.
+func (c *Client) UpdateVMLabels(ctx context.Context, name string, patch map[string]*string) (*VM, error) {
+	req, err := c.newRequest(ctx, http.MethodPatch, "/api/v1/vms/"+url.PathEscape(name)+"/labels", patch)
+	if err != nil {
+		return nil, err
+	}
+	var vm VM
+	if err := c.do(req, &vm); err != nil {
+		return nil, err
+	}
+	return &vm, nil
+}
+
+// BulkVMAction runs start, stop, restart or delete on every VM matching
+// selector.
+func (c *Client) BulkVMAction(ctx context.Context, action, selector string) (*BulkVMResponse, error) {
+	req, err := c.newRequest(ctx, http.MethodPost, "/api/v1/bulk/vms/"+url.PathEscape(action), map[string]string{"selector": selector})
+	if err != nil {
+		return nil, err
+	}
+	var resp BulkVMResponse
+	if err := c.do(req, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+func (c *Client) GetVM(ctx context.Context, name string) (*VM, error) {
+	req, err := c.newRequest(ctx, http.MethodGet, "/api/v1/vms/"+url.PathEscape(name), nil)
+	if err != nil {
+		return nil, err
+	}
+	var vm VM
+	if err := c.do(req, &vm); err != nil {
+		return nil, err
+	}
+	return &vm, nil
+}
+
+// GetVMOpenAPI fetches the raw OpenAPI specification document for the VM's plugin.
+// Returns the document bytes and content type (application/json or application/yaml).
+func (c *Client) GetVMOpenAPI(ctx context.Context, name string) ([]byte, string, error) {
+	path := "/api/v1/vms/" + url.PathEscape(name) + "/openapi"
+	req, err := c.newRequest(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return nil, "", err
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, "", fmt.Errorf("client: get openapi: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, "", fmt.Errorf("client: get openapi http %d: %s", resp.StatusCode, string(body))
+	}
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, "", fmt.Errorf("client: read openapi: %w", err)
+	}
+
+	contentType := resp.Header.Get("Content-Type")
+	return data, contentType, nil
+}
+
+func (c *Client) CreateVM(ctx context.Context, payload CreateVMRequest) (*VM, error) {
+	req, err := c.newRequest(ctx, http.MethodPost, "/api/v1/vms", payload)
+	if err != nil {
+		return nil, err
+	}
+	var vm VM
+	if err := c.do(req, &vm); err != nil {
+		return nil, err
+	}
+	return &vm, nil
+}
+
+// Operation reports the progress of an asynchronous request.
+type Operation struct {
+	ID          string     `json:"id"`
+	Kind        string     `json:"kind"`
+	Target      string     `json:"target"`
+	Status      string     `json:"status"`
+	Done        bool       `json:"done"`
+	Error       string     `json:"error,omitempty"`
+	Code        string     `json:"code,omitempty"`
+	Hint        string     `json:"hint,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+}
+
+// CreateVMAsync submits a VM create without waiting for the boot and returns
+// the operation tracking it.
+func (c *Client) CreateVMAsync(ctx context.Context, payload CreateVMRequest) (*Operation, error) {
+	req, err := c.newRequest(ctx, http.MethodPost, "/api/v1/vms?async=true", payload)
+	if err != nil {
+		return nil, err
+	}
+	var op Operation
+	if err := c.do(req, &op); err != nil {
+		return nil, err
+	}
+	return &op, nil
+}
+
+func (c *Client) GetOperation(ctx context.Context, id string) (*Operation, error) {
+	req, err := c.newRequest(ctx, http.MethodGet, "/api/v1/operations/"+url.PathEscape(id), nil)
+	if err != nil {
+		return nil, err
+	}
+	var op Operation
+	if err := c.do(req, &op); err != nil {
+		return nil, err
+	}
+	return &op, nil
+}
+
+func (c *Client) GetVMConfig(ctx context.Context, name string) (*VersionedVMConfig, error) {
+	path := "/api/v1/vms/" + url.PathEscape(name) + "/config"
+	req, err := c.newRequest(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return nil, err
+	}
+	var config VersionedVMConfig
+	if err := c.do(req, &config); err != nil {
+		return nil, err
+	}
+	return &config, nil
+}
+
+// ConfigUpdateOptions controls whether a config update restarts the VM and
+// rolls back to a snapshot when the restarted VM is not healthy in time.
+type ConfigUpdateOptions struct {
+	Restart      bool
+	Rollback     bool
+	HealthWindow time.Duration
+}
+
+func (o ConfigUpdateOptions) query() string {
+	values := url.Values{}
+	if o.Restart {
+		values.Set("restart", "true")
+	}
+	if o.Rollback {
+		values.Set("rollback", "true")
+	}
+	if o.HealthWindow > 0 {
+		values.Set("health_window", o.HealthWindow.String())
+	}
+	if len(values) == 0 {
+		return ""
+	}
+	return "?" + values.Encode()
+}
+
+func (c *Client) UpdateVMConfig(ctx context.Context, name string, patch VMConfigPatch) (*VersionedVMConfig, error) {
+	return c.ApplyVMConfig(ctx, name, patch, ConfigUpdateOptions{})
+}
+
+// ApplyVMConfig updates the VM configuration and, depending on opts, restarts
+// the VM onto it.
+func (c *Client) ApplyVMConfig(ctx context.Context, name string, patch VMConfigPatch, opts ConfigUpdateOptions) (*VersionedVMConfig, error) {
+	path := "/api/v1/vms/" + url.PathEscape(name) + "/config" + opts.query()
+	req, err := c.newRequest(ctx, http.MethodPatch, path, patch)
+	if err != nil {
+		return nil, err
+	}
+	var config VersionedVMConfig
+	if err := c.do(req, &config); err != nil {
+		return nil, err
+	}
+	return &config, nil
+}
+
+func (c *Client) UpdateVMConfigRaw(ctx context.Context, name string, raw []byte) (*VersionedVMConfig, error) {
+	return c.ApplyVMConfigRaw(ctx, name, raw, ConfigUpdateOptions{})
+}
+
+// ApplyVMConfigRaw is ApplyVMConfig for a pre-encoded configuration payload.
+func (c *Client) ApplyVMConfigRaw(ctx context.Context, name string, raw []byte, opts ConfigUpdateOptions) (*VersionedVMConfig, error) {
+	path := "/api/v1/vms/" + url.PathEscape(name) + "/config" + opts.query()
+	var payload any
+	if len(raw) > 0 {
+		payload = json.RawMessage(raw)
+	}
+	req, err := c.newRequest(ctx, http.MethodPatch, path, payload)
+	if err != nil {
+		return nil, err
+	}
+	var config VersionedVMConfig
+	if err := c.do(req, &config); err != nil {
+		return nil, err
+	}
+	return &config, nil
+}
+
+func (c *Client) GetVMConfigHistory(ctx context.Context, name string, limit int) ([]VMConfigHistoryEntry, error) {
+	path := "/api/v1/vms/" + url.PathEscape(name) + "/config/history"
+	if limit > 0 {
+		path = path + "?limit=" + strconv.Itoa(limit)
+	}
+	req, err := c.newRequest(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return nil, err
+	}
+	var entries []VMConfigHistoryEntry
+	if err := c.do(req, &entries); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// GetVMSysInfo returns the guest system report gathered by the VM's agent.
+func (c *Client) GetVMSysInfo(ctx context.Context, name string) (map[string]any, error) {
+	path := "/api/v1/vms/" + url.PathEscape(name) + "/sysinfo"
+	req, err := c.newRequest(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return nil, err
+	}
+	var info map[string]any
+	if err := c.do(req, &info); err != nil {
+		return nil, err
+	}
+	return info, nil
+}
+
+// GetVMGuestMetrics samples the VM's CPU, memory, disk and process usage as
+// seen inside the guest.
+func (c *Client) GetVMGuestMetrics(ctx context.Context, name string) (*GuestMetrics, error) {
+	path := "/api/v1/vms/" + url.PathEscape(name) + "/guest-metrics"
+	req, err := c.newRequest(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return nil, err
+	}
+	var metrics GuestMetrics
+	if err := c.do(req, &metrics); err != nil {
+		return nil, err
+	}
+	return &metrics, nil
+}
+
+// FileTransfer describes a file written to a guest.
+type FileTransfer struct {
+	Path   string `json:"path"`
+	Size   int64  `json:"size"`
+	SHA256 string `json:"sha256"`
+}
+
+// FileInfo describes a file downloaded from a guest.
+type FileInfo struct {
+	Size int64
+	// SHA256 is the agent's hex digest of the whole file.
+	SHA256 string
+	// Mode holds the file's octal permission bits, e.g. "0644".
+	Mode string
+}
+
+// fileRequest builds a request for a guest file. The guest path is escaped
+// here, as newRequest would take a "?" in it for a query.
+func (c *Client) fileRequest(ctx context.Context, method, name, guestPath string, body io.Reader) (*http.Request, error) {
+	ref := &url.URL{Path: "/api/v1/vms/" + name + "/files/" + strings.TrimPrefix(guestPath, "/")}
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL.ResolveReference(ref).String(), body)
+	if err != nil {
+		return nil, fmt.Errorf("client: new request: %w", err)
+	}
+	return req, nil
+}
+
+// transferClient is httpClient without its overall timeout, which a large
+// file would outlast; ctx bounds transfers instead.
+func (c *Client) transferClient() *http.Client {
+	client := *c.httpClient
+	client.Timeout = 0
+	return &client
+}
+
+// UploadFile streams size bytes from r to guestPath in the VM. A non-empty
+// sha256 makes the guest reject the file unless it matches; mode, when not
+// empty, sets its octal permission bits.
+func (c *Client) UploadFile(ctx context.Context, name, guestPath string, r io.Reader, size int64, sha256, mode string) (*FileTransfer, error) {
+	req, err := c.fileRequest(ctx, http.MethodPut, name, guestPath, r)
+	if err != nil {
+		return nil, err
+	}
+	req.ContentLength = size
+	req.Header.Set("Content-Type", "application/octet-stream")
+	if sha256 != "" {
+		req.Header.Set("X-Volant-Checksum-Sha256", sha256)
+	}
+	if mode != "" {
+		req.Header.Set("X-Volant-File-Mode", mode)
+	}
+	resp, err := c.transferClient().Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("client: upload file: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		data, _ := io.ReadAll(resp.Body)
+		return nil, decodeAPIError(resp.StatusCode, data)
+	}
+	var written FileTransfer
+	if err := json.NewDecoder(resp.Body).Decode(&written); err != nil {
+		return nil, fmt.Errorf("client: decode response: %w", err)
+	}
+	return &written, nil
+}
+
+// DownloadFile streams guestPath in the VM to w. Callers verify the returned
+// checksum against what they wrote.
+func (c *Client) DownloadFile(ctx context.Context, name, guestPath string, w io.Writer) (*FileInfo, error) {
+	req, err := c.fileRequest(ctx, http.MethodGet, name, guestPath, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.transferClient().Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("client: download file: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		data, _ := io.ReadAll(resp.Body)
+		return nil, decodeAPIError(resp.StatusCode, data)
+	}
+	n, err := io.Copy(w, resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("client: download file: %w", err)
+	}
+	return &FileInfo{Size: n, SHA256: resp.Header.Get("X-Volant-Checksum-Sha256"), Mode: resp.Header.Get("X-Volant-File-Mode")}, nil
+}
+
+// Exec runs a command in the VM and waits for it, returning its exit code
+// and buffered output. The command's timeout bounds the call.
+func (c *Client) Exec(ctx context.Context, name string, execReq ExecRequest) (*ExecResult, error) {
+	path := "/api/v1/vms/" + url.PathEscape(name) + "/exec"
+	req, err := c.newRequest(ctx, http.MethodPost, path, execReq)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.transferClient().Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("client: exec: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		data, _ := io.ReadAll(resp.Body)
+		return nil, decodeAPIError(resp.StatusCode, data)
+	}
+	var result ExecResult
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("client: decode response: %w", err)
+	}
+	return &result, nil
+}
+
+// ExecStream runs a command in the VM over a WebSocket, copying stdin to it
+// and its output to stdout and stderr as it is produced. A nil stdin closes
+// the command's stdin right away. A command refused by the guest is reported
+// through the result's Error and Code.
+func (c *Client) ExecStream(ctx context.Context, name string, execReq ExecRequest, stdin io.Reader, stdout, stderr io.Writer) (*ExecResult, error) {
+	if name == "" {
+		return nil, fmt.Errorf("client: vm name required")
+	}
+	conn, err := c.dialWebSocket(ctx, fmt.Sprintf("/ws/v1/vms/%s/exec", url.PathEscape(name)), "")
+	if err != nil {
+		return nil, fmt.Errorf("client: exec dial: %w", err)
+	}
+	defer conn.Close()
+	stop := context.AfterFunc(ctx, func() { _ = conn.Close() })
+	defer stop()
+
+	data, err := json.Marshal(execReq)
+	if err != nil {
+		return nil, fmt.Errorf("client: encode body: %w", err)
+	}
+	if err := conn.WriteMessage(websocket.TextMessage, data); err != nil {
+		return nil, fmt.Errorf("client: exec: %w", err)
+	}
+	// Only this goroutine writes data messages; the close below is a control
+	// message, which may be sent concurrently.
+	go func() {
+		if stdin != nil {
+			buf := make([]byte, 32<<10)
+			for {
+				n, err := stdin.Read(buf)
+				if n > 0 {
+					if conn.WriteMessage(websocket.BinaryMessage, append([]byte{pluginspec.ExecStreamStdin}, buf[:n]...)) != nil {
+						return
+					}
+				}
+				if err != nil {
+					break
+				}
+			}
+		}
+		_ = conn.WriteMessage(websocket.BinaryMessage, []byte{pluginspec.ExecStreamStdin})
+	}()
+
+	var result *ExecResult
+	for {
+		messageType, payload, err := conn.ReadMessage()
+		if err != nil {
+			if result != nil {
+				return result, nil
+			}
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			return nil, fmt.Errorf("client: exec: %w", err)
+		}
+		switch {
+		case messageType == websocket.TextMessage:
+			var final ExecResult
+			if err := json.Unmarshal(payload, &final); err != nil {
+				return nil, fmt.Errorf("client: decode result: %w", err)
+			}
+			result = &final
+			_ = conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""), time.Now().Add(time.Second))
+			return result, nil
+		case len(payload) == 0:
+		case payload[0] == pluginspec.ExecStreamStdout:
+			if _, err := stdout.Write(payload[1:]); err != nil {
+				return nil, err
+			}
+		case payload[0] == pluginspec.ExecStreamStderr:
+			if _, err := stderr.Write(payload[1:]); err != nil {
+				return nil, err
+			}
+		}
+	}
+}
+
+// PortBinding is a VM port mapping as reported by volantd.
+type PortBinding struct {
+	Host    int    `json:"host"`
+	Guest   int    `json:"guest"`
+	Proto   string `json:"proto"`
+	GuestIP string `json:"guest_ip,omitempty"`
+	Active  bool   `json:"active"`
+}
+
+func (c *Client) GetVMPorts(ctx context.Context, name string) ([]PortBinding, error) {
+	path := "/api/v1/vms/" + url.PathEscape(name) + "/ports"
+	req, err := c.newRequest(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return nil, err
+	}
+	var ports []PortBinding
+	if err := c.do(req, &ports); err != nil {
+		return nil, err
+	}
+	return ports, nil
+}
+
+// SSHAccess describes how to open an SSH session to a VM.
+type SSHAccess struct {
+	User           string   `json:"user"`
+	Host           string   `json:"host,omitempty"`
+	Port           int      `json:"port"`
+	AuthorizedKeys []string `json:"authorized_keys,omitempty"`
+	GeneratedKey   bool     `json:"generated_key"`
+}
+
+func (c *Client) GetVMSSH(ctx context.Context, name string) (*SSHAccess, error) {
+	path := "/api/v1/vms/" + url.PathEscape(name) + "/ssh"
+	req, err := c.newRequest(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return nil, err
+	}
+	var access SSHAccess
+	if err := c.do(req, &access); err != nil {
+		return nil, err
+	}
+	return &access, nil
+}
+
+// GetVMSSHKey fetches the private key volantd generated for a VM.
+func (c *Client) GetVMSSHKey(ctx context.Context, name string) (string, error) {
+	path := "/api/v1/vms/" + url.PathEscape(name) + "/ssh/key"
+	req, err := c.newRequest(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return "", err
+	}
+	var resp struct {
+		PrivateKey string `json:"private_key"`
+	}
+	if err := c.do(req, &resp); err != nil {
+		return "", err
+	}
+	return resp.PrivateKey, nil
+}
+
+func (c *Client) StartVM(ctx context.Context, name string) (*VM, error) {
+	path := "/api/v1/vms/" + url.PathEscape(name) + "/start"
+	req, err := c.newRequest(ctx, http.MethodPost, path, nil)
+	if err != nil {
+		return nil, err
+	}
+	var vm VM
+	if err := c.do(req, &vm); err != nil {
+		return nil, err
+	}
+	return &vm, nil
+}
+
+func (c *Client) StopVM(ctx context.Context, name string) (*VM, error) {
+	path := "/api/v1/vms/" + url.PathEscape(name) + "/stop"
+	req, err := c.newRequest(ctx, http.MethodPost, path, nil)
+	if err != nil {
+		return nil, err
+	}
+	var vm VM
+	if err := c.do(req, &vm); err != nil {
+		return nil, err
+	}
+	return &vm, nil
+}
+
+// DuplicateVM creates newName from the source VM's config and disk state.
+func (c *Client) DuplicateVM(ctx context.Context, name, newName string) (*VM, error) {
+	path := "/api/v1/vms/" + url.PathEscape(name) + "/duplicate"
+	req, err := c.newRequest(ctx, http.MethodPost, path, map[string]string{"name": newName})
+	if err != nil {
+		return nil, err
+	}
+	var vm VM
+	if err := c.do(req, &vm); err != nil {
+		return nil, err
+	}
+	return &vm, nil
+}
+
+// CloneVM creates newName from the source VM's config. scratch also copies
+// the source's persistent scratch disk.
+func (c *Client) CloneVM(ctx context.Context, name, newName string, scratch bool) (*VM, error) {
+	path := "/api/v1/vms/" + url.PathEscape(name) + "/clone"
+	body := map[string]any{"name": newName, "scratch": scratch}
+	req, err := c.newRequest(ctx, http.MethodPost, path, body)
+	if err != nil {
+		return nil, err
+	}
+	var vm VM
+	if err := c.do(req, &vm); err != nil {
+		return nil, err
+	}
+	return &vm, nil
+}
+
+func (c *Client) RestartVM(ctx context.Context, name string) (*VM, error) {
+	path := "/api/v1/vms/" + url.PathEscape(name) + "/restart"
+	req, err := c.newRequest(ctx, http.MethodPost, path, nil)
+	if err != nil {
+		return nil, err
+	}
+	var vm VM
+	if err := c.do(req, &vm); err != nil {
+		return nil, err
+	}
+	return &vm, nil
+}
+
+func (c *Client) CreateDeployment(ctx context.Context, payload CreateDeploymentRequest) (*Deployment, error) {
+	req, err := c.newRequest(ctx, http.MethodPost, "/api/v1/deployments", payload)
+	if err != nil {
+		return nil, err
+	}
+	var deployment Deployment
+	if err := c.do(req, &deployment); err != nil {
+		return nil, err
+	}
+	return &deployment, nil
+}
+
+func (c *Client) ListDeployments(ctx context.Context) ([]Deployment, error) {
+	req, err := c.newRequest(ctx, http.MethodGet, "/api/v1/deployments", nil)
+	if err != nil {
+		return nil, err
+	}
+	var deployments []Deployment
+	if err := c.do(req, &deployments); err != nil {
+		return nil, err
+	}
+	return deployments, nil
+}
+
+func (c *Client) GetDeployment(ctx context.Context, name string) (*Deployment, error) {
+	path := "/api/v1/deployments/" + url.PathEscape(name)
+	req, err := c.newRequest(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return nil, err
+	}
+	var deployment Deployment
+	if err := c.do(req, &deployment); err != nil {
+		return nil, err
+	}
+	return &deployment, nil
+}
+
+func (c *Client) DeleteDeployment(ctx context.Context, name string) error {
+	path := "/api/v1/deployments/" + url.PathEscape(name)
+	req, err := c.newRequest(ctx, http.MethodDelete, path, nil)
+	if err != nil {
+		return err
+	}
+	return c.do(req, nil)
+}
+
+func (c *Client) ScaleDeployment(ctx context.Context, name string, replicas int) (*Deployment, error) {
+	path := "/api/v1/deployments/" + url.PathEscape(name)
+	payload := map[string]int{"replicas": replicas}
+	req, err := c.newRequest(ctx, http.MethodPatch, path, payload)
+	if err != nil {
+		return nil, err
+	}
+	var deployment Deployment
+	if err := c.do(req, &deployment); err != nil {
+		return nil, err
+	}
+	return &deployment, nil
+}
+
+// UpdateDeploymentConfigRequest starts a rolling update of a deployment.
+type UpdateDeploymentConfigRequest struct {
+	Config         VMConfig `json:"config"`
+	MaxSurge       *int     `json:"max_surge,omitempty"`
+	MaxUnavailable *int     `json:"max_unavailable,omitempty"`
+}
+
+func (c *Client) UpdateDeploymentConfig(ctx context.Context, name string, payload UpdateDeploymentConfigRequest) (*Deployment, error) {
+	path := "/api/v1/deployments/" + url.PathEscape(name) + "/config"
+	req, err := c.newRequest(ctx, http.MethodPut, path, payload)
+	if err != nil {
+		return nil, err
+	}
+	var deployment Deployment
+	if err := c.do(req, &deployment); err != nil {
+		return nil, err
+	}
+	return &deployment, nil
+}
+
+// Schedule is a cron-style lifecycle rule.
+type Schedule struct {
+	Name       string     `json:"name"`
+	Cron       string     `json:"cron"`
+	Action     string     `json:"action"`
+	TargetKind string     `json:"target_kind"`
+	Target     string     `json:"target"`
+	Replicas   *int       `json:"replicas,omitempty"`
+	Enabled    bool       `json:"enabled"`
+	LastRunAt  *time.Time `json:"last_run_at,omitempty"`
+	NextRunAt  *time.Time `json:"next_run_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+}
+
+// CreateScheduleRequest captures schedule creation inputs.
+type CreateScheduleRequest struct {
+	Name       string `json:"name"`
+	Cron       string `json:"cron"`
+	Action     string `json:"action"`
+	TargetKind string `json:"target_kind"`
+	Target     string `json:"target"`
+	Replicas   *int   `json:"replicas,omitempty"`
+	Disabled   bool   `json:"disabled,omitempty"`
+}
+
+// ScheduleRun is a single execution recorded in schedule history.
+type ScheduleRun struct {
+	Status     string    `json:"status"`
+	Message    string    `json:"message,omitempty"`
+	StartedAt  time.Time `json:"started_at"`
+	FinishedAt time.Time `json:"finished_at"`
+}
+
+func (c *Client) ListSchedules(ctx context.Context) ([]Schedule, error) {
+	req, err := c.newRequest(ctx, http.MethodGet, "/api/v1/schedules", nil)
+	if err != nil {
+		return nil, err
+	}
+	var schedules []Schedule
+	if err := c.do(req, &schedules); err != nil {
+		return nil, err
+	}
+	return schedules, nil
+}
+
+func (c *Client) CreateSchedule(ctx context.Context, payload CreateScheduleRequest) (*Schedule, error) {
+	req, err := c.newRequest(ctx, http.MethodPost, "/api/v1/schedules", payload)
+	if err != nil {
+		return nil, err
+	}
+	var schedule Schedule
+	if err := c.do(req, &schedule); err != nil {
+		return nil, err
+	}
+	return &schedule, nil
+}
+
+// GetSchedule returns one schedule.
+func (c *Client) GetSchedule(ctx context.Context, name string) (*Schedule, error) {
+	req, err := c.newRequest(ctx, http.MethodGet, "/api/v1/schedules/"+url.PathEscape(name), nil)
+	if err != nil {
+		return nil, err
+	}
+	var schedule Schedule
+	if err := c.do(req, &schedule); err != nil {
+		return nil, err
+	}
+	return &schedule, nil
+}
+
+func (c *Client) DeleteSchedule(ctx context.Context, name string) error {
+	req, err := c.newRequest(ctx, http.MethodDelete, "/api/v1/schedules/"+url.PathEscape(name), nil)
+	if err != nil {
+		return err
+	}
+	return c.do(req, nil)
+}
+
+func (c *Client) SetScheduleEnabled(ctx context.Context, name string, enabled bool) (*Schedule, error) {
+	path := "/api/v1/schedules/" + url.PathEscape(name) + "/enabled"
+	req, err := c.newRequest(ctx, http.MethodPost, path, map[string]bool{"enabled": enabled})
+	if err != nil {
+		return nil, err
+	}
+	var schedule Schedule
+	if err := c.do(req, &schedule); err != nil {
+		return nil, err
+	}
+	return &schedule, nil
+}
+
+func (c *Client) RunSchedule(ctx context.Context, name string) (*ScheduleRun, error) {
+	path := "/api/v1/schedules/" + url.PathEscape(name) + "/run"
+	req, err := c.newRequest(ctx, http.MethodPost, path, nil)
+	if err != nil {
+		return nil, err
+	}
+	var run ScheduleRun
+	if err := c.do(req, &run); err != nil {
+		return nil, err
+	}
+	return &run, nil
+}
+
+func (c *Client) ScheduleRuns(ctx context.Context, name string, limit int) ([]ScheduleRun, error) {
+	path := "/api/v1/schedules/" + url.PathEscape(name) + "/runs"
+	if limit > 0 {
+		path += "?limit=" + strconv.Itoa(limit)
+	}
+	req, err := c.newRequest(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return nil, err
+	}
+	var runs []ScheduleRun
+	if err := c.do(req, &runs); err != nil {
+		return nil, err
+	}
+	return runs, nil
+}
+
+// Webhook is an HTTP endpoint that receives lifecycle events.
+type Webhook struct {
+	Name      string    `json:"name"`
+	URL       string    `json:"url"`
+	Events    []string  `json:"events,omitempty"`
+	Enabled   bool      `json:"enabled"`
+	Secret    string    `json:"secret,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// CreateWebhookRequest captures webhook registration inputs.
+type CreateWebhookRequest struct {
+	Name     string   `json:"name"`
+	URL      string   `json:"url"`
+	Events   []string `json:"events,omitempty"`
+	Secret   string   `json:"secret,omitempty"`
+	Disabled bool     `json:"disabled,omitempty"`
+}
+
+// WebhookDelivery is a single delivery attempt.
+type WebhookDelivery struct {
+	DeliveryID string    `json:"delivery_id"`
+	EventType  string    `json:"event_type"`
+	Attempt    int       `json:"attempt"`
+	StatusCode int       `json:"status_code,omitempty"`
+	Succeeded  bool      `json:"succeeded"`
+	Error      string    `json:"error,omitempty"`
+	DurationMS int64     `json:"duration_ms"`
+	Payload    string    `json:"payload,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+func (c *Client) ListWebhooks(ctx context.Context) ([]Webhook, error) {
+	req, err := c.newRequest(ctx, http.MethodGet, "/api/v1/webhooks", nil)
+	if err != nil {
+		return nil, err
+	}
+	var webhooks []Webhook
+	if err := c.do(req, &webhooks); err != nil {
+		return nil, err
+	}
+	return webhooks, nil
+}
+
+func (c *Client) CreateWebhook(ctx context.Context, payload CreateWebhookRequest) (*Webhook, error) {
+	req, err := c.newRequest(ctx, http.MethodPost, "/api/v1/webhooks", payload)
+	if err != nil {
+		return nil, err
+	}
+	var webhook Webhook
+	if err := c.do(req, &webhook); err != nil {
+		return nil, err
+	}
+	return &webhook, nil
+}
+
+// GetWebhook returns one webhook. Its signing secret is only returned when
+// it is created.
+func (c *Client) GetWebhook(ctx context.Context, name string) (*Webhook, error) {
+	req, err := c.newRequest(ctx, http.MethodGet, "/api/v1/webhooks/"+url.PathEscape(name), nil)
+	if err != nil {
+		return nil, err
+	}
+	var webhook Webhook
+	if err := c.do(req, &webhook); err != nil {
+		return nil, err
+	}
+	return &webhook, nil
+}
+
+func (c *Client) DeleteWebhook(ctx context.Context, name string) error {
+	req, err := c.newRequest(ctx, http.MethodDelete, "/api/v1/webhooks/"+url.PathEscape(name), nil)
+	if err != nil {
+		return err
+	}
+	return c.do(req, nil)
+}
+
+func (c *Client) SetWebhookEnabled(ctx context.Context, name string, enabled bool) (*Webhook, error) {
+	path := "/api/v1/webhooks/" + url.PathEscape(name) + "/enabled"
+	req, err := c.newRequest(ctx, http.MethodPost, path, map[string]bool{"enabled": enabled})
+	if err != nil {
+		return nil, err
+	}
+	var webhook Webhook
+	if err := c.do(req, &webhook); err != nil {
+		return nil, err
+	}
+	return &webhook, nil
+}
+
+func (c *Client) WebhookDeliveries(ctx context.Context, name string, limit int) ([]WebhookDelivery, error) {
+	path := "/api/v1/webhooks/" + url.PathEscape(name) + "/deliveries"
+	if limit > 0 {
+		path += "?limit=" + strconv.Itoa(limit)
+	}
+	req, err := c.newRequest(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return nil, err
+	}
+	var deliveries []WebhookDelivery
+	if err := c.do(req, &deliveries); err != nil {
+		return nil, err
+	}
+	return deliveries, nil
+}
+
+// Secret describes a stored secret. Its value is never returned.
+type Secret struct {
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func (c *Client) ListSecrets(ctx context.Context) ([]Secret, error) {
+	req, err := c.newRequest(ctx, http.MethodGet, "/api/v1/secrets", nil)
+	if err != nil {
+		return nil, err
+	}
+	var secrets []Secret
+	if err := c.do(req, &secrets); err != nil {
+		return nil, err
+	}
+	return secrets, nil
+}
+
+// PutSecret stores value under name, replacing an existing value.
+func (c *Client) PutSecret(ctx context.Context, name, value string) (*Secret, error) {
+	req, err := c.newRequest(ctx, http.MethodPost, "/api/v1/secrets", map[string]string{"name": name, "value": value})
+	if err != nil {
+		return nil, err
+	}
+	var secret Secret
+	if err := c.do(req, &secret); err != nil {
+		return nil, err
+	}
+	return &secret, nil
+}
+
+// GetSecret returns the metadata of one secret; values are never returned.
+func (c *Client) GetSecret(ctx context.Context, name string) (*Secret, error) {
+	req, err := c.newRequest(ctx, http.MethodGet, "/api/v1/secrets/"+url.PathEscape(name), nil)
+	if err != nil {
+		return nil, err
+	}
+	var secret Secret
+	if err := c.do(req, &secret); err != nil {
+		return nil, err
+	}
+	return &secret, nil
+}
+
+// UpdateSecret replaces the value of an existing secret.
+func (c *Client) UpdateSecret(ctx context.Context, name, value string) (*Secret, error) {
+	req, err := c.newRequest(ctx, http.MethodPut, "/api/v1/secrets/"+url.PathEscape(name), map[string]string{"value": value})
+	if err != nil {
+		return nil, err
+	}
+	var secret Secret
+	if err := c.do(req, &secret); err != nil {
+		return nil, err
+	}
+	return &secret, nil
+}
+
+func (c *Client) DeleteSecret(ctx context.Context, name string) error {
+	req, err := c.newRequest(ctx, http.MethodDelete, "/api/v1/secrets/"+url.PathEscape(name), nil)
+	if err != nil {
+		return err
+	}
+	return c.do(req, nil)
+}
+
+// Backup describes a control plane database backup.
+type Backup struct {
+	Name      string    `json:"name"`
+	Size      int64     `json:"size"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// RestoreResult reports a completed database restore.
+type RestoreResult struct {
+	Restored Backup `json:"restored"`
+	Safety   Backup `json:"safety"`
+	Message  string `json:"message"`
+}
+
+func (c *Client) ListBackups(ctx context.Context) ([]Backup, error) {
+	req, err := c.newRequest(ctx, http.MethodGet, "/api/v1/system/backups", nil)
+	if err != nil {
+		return nil, err
+	}
+	var backups []Backup
+	if err := c.do(req, &backups); err != nil {
+		return nil, err
+	}
+	return backups, nil
+}
+
+func (c *Client) CreateBackup(ctx context.Context) (*Backup, error) {
+	req, err := c.newRequest(ctx, http.MethodPost, "/api/v1/system/backups", nil)
+	if err != nil {
+		return nil, err
+	}
+	var backup Backup
+	if err := c.do(req, &backup); err != nil {
+		return nil, err
+	}
+	return &backup, nil
+}
+
+func (c *Client) RestoreBackup(ctx context.Context, name string) (*RestoreResult, error) {
+	req, err := c.newRequest(ctx, http.MethodPost, "/api/v1/system/restore", map[string]string{"backup": name})
+	if err != nil {
+		return nil, err
+	}
+	var result RestoreResult
+	if err := c.do(req, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// AgentArtifact describes an agent binary volantd serves to guests.
+type AgentArtifact struct {
+	Version    string    `json:"version"`
+	Arch       string    `json:"arch"`
+	SizeBytes  int64     `json:"size_bytes"`
+	SHA256     string    `json:"sha256"`
+	ModifiedAt time.Time `json:"modified_at"`
+}
+
+func (c *Client) ListAgentArtifacts(ctx context.Context) ([]AgentArtifact, error) {
+	req, err := c.newRequest(ctx, http.MethodGet, "/api/v1/agent/artifacts", nil)
+	if err != nil {
+		return nil, err
+	}
+	var artifacts []AgentArtifact
+	if err := c.do(req, &artifacts); err != nil {
+		return nil, err
+	}
+	return artifacts, nil
+}
+
+// UploadAgentArtifact streams size bytes from r as the agent binary of
+// version for arch. A non-empty sha256 makes volantd reject the upload
+// unless it matches.
+func (c *Client) UploadAgentArtifact(ctx context.Context, version, arch string, r io.Reader, size int64, sha256 string) (*AgentArtifact, error) {
+	path := "/api/v1/agent/artifacts/" + url.PathEscape(version) + "/" + url.PathEscape(arch)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, c.baseURL.ResolveReference(&url.URL{Path: path}).String(), r)
+	if err != nil {
+		return nil, fmt.Errorf("client: new request: %w", err)
+	}
+	req.ContentLength = size
+	req.Header.Set("Content-Type", "application/octet-stream")
+	if sha256 != "" {
+		req.Header.Set("X-Volant-Checksum-Sha256", sha256)
+	}
+	resp, err := c.transferClient().Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("client: upload agent: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		data, _ := io.ReadAll(resp.Body)
+		return nil, decodeAPIError(resp.StatusCode, data)
+	}
+	var artifact AgentArtifact
+	if err := json.NewDecoder(resp.Body).Decode(&artifact); err != nil {
+		return nil, fmt.Errorf("client: decode response: %w", err)
+	}
+	return &artifact, nil
+}
+
+func (c *Client) DeleteAgentArtifact(ctx context.Context, version, arch string) error {
+	path := "/api/v1/agent/artifacts/" + url.PathEscape(version) + "/" + url.PathEscape(arch)
+	req, err := c.newRequest(ctx, http.MethodDelete, path, nil)
+	if err != nil {
+		return err
+	}
+	return c.do(req, nil)
+}
+
+// GCRun describes one host garbage collection pass.
+type GCRun struct {
+	StartedAt  time.Time `json:"started_at"`
+	FinishedAt time.Time `json:"finished_at"`
+	Taps       []string  `json:"taps"`
+	Sockets    []string  `json:"sockets"`
+	SeedImages []string  `json:"seed_images"`
+	Pending    int       `json:"pending"`
+	Errors     []string  `json:"errors,omitempty"`
+}
+
+// GCStatus reports the garbage collection interval and the last pass.
+type GCStatus struct {
+	IntervalSeconds int    `json:"interval_seconds"`
+	LastRun         *GCRun `json:"last_run"`
+}
+
+func (c *Client) GarbageCollection(ctx context.Context) (*GCStatus, error) {
+	req, err := c.newRequest(ctx, http.MethodGet, "/api/v1/system/gc", nil)
+	if err != nil {
+		return nil, err
+	}
+	var status GCStatus
+	if err := c.do(req, &status); err != nil {
+		return nil, err
+	}
+	return &status, nil
+}
+
+// DoctorCheck is the result of one host prerequisite check: status is
+// pass, warn or fail, and Hint says how to fix a check that did not pass.
+type DoctorCheck struct {
+	Name   string `json:"name"`
+	Status string `json:"status"`
+	Detail string `json:"detail,omitempty"`
+	Hint   string `json:"hint,omitempty"`
+}
+
+// DoctorReport holds the host checks; Healthy is false when any failed.
+type DoctorReport struct {
+	CheckedAt time.Time     `json:"checked_at"`
+	Healthy   bool          `json:"healthy"`
+	Checks    []DoctorCheck `json:"checks"`
+}
+
+// Doctor checks the host prerequisites of the daemon.
+func (c *Client) Doctor(ctx context.Context) (*DoctorReport, error) {
+	req, err := c.newRequest(ctx, http.MethodGet, "/api/v1/system/doctor", nil)
+	if err != nil {
+		return nil, err
+	}
+	var report DoctorReport
+	if err := c.do(req, &report); err != nil {
+		return nil, err
+	}
+	return &report, nil
+}
+
+// HostMaintenanceVM is a VM host maintenance stopped. RestoredAt and Outcome
+// are set once exiting maintenance restarted it; Error reports a failed stop
+// or restart of the request that returned it.
+type HostMaintenanceVM struct {
+	Name       string     `json:"name"`
+	StoppedAt  time.Time  `json:"stopped_at"`
+	RestoredAt *time.Time `json:"restored_at,omitempty"`
+	Outcome    string     `json:"outcome,omitempty"`
+	Error      string     `json:"error,omitempty"`
+}
+
+// HostMaintenance reports host maintenance mode.
+type HostMaintenance struct {
+	Engaged bool                `json:"engaged"`
+	Reason  string              `json:"reason,omitempty"`
+	Since   *time.Time          `json:"since,omitempty"`
+	EndedAt *time.Time          `json:"ended_at,omitempty"`
+	VMs     []HostMaintenanceVM `json:"vms"`
+}
+
+// HostMaintenance returns the state of host maintenance mode.
+func (c *Client) HostMaintenance(ctx context.Context) (*HostMaintenance, error) {
+	req, err := c.newRequest(ctx, http.MethodGet, "/api/v1/system/maintenance", nil)
+	if err != nil {
+		return nil, err
+	}
+	var status HostMaintenance
+	if err := c.do(req, &status); err != nil {
+		return nil, err
+	}
+	return &status, nil
+}
+
+// EnterHostMaintenance stops every running VM, concurrency at a time (zero
+// for the server default), and refuses new VMs until maintenance is exited.
+func (c *Client) EnterHostMaintenance(ctx context.Context, reason string, concurrency int) (*HostMaintenance, error) {
+	payload := map[string]any{"reason": reason, "concurrency": concurrency}
+	req, err := c.newRequest(ctx, http.MethodPost, "/api/v1/system/maintenance", payload)
+	if err != nil {
+		return nil, err
+	}
+	var status HostMaintenance
+	if err := c.do(req, &status); err != nil {
+		return nil, err
+	}
+	return &status, nil
+}
+
+// ExitHostMaintenance ends host maintenance and restarts the VMs it stopped.
+func (c *Client) ExitHostMaintenance(ctx context.Context, concurrency int) (*HostMaintenance, error) {
+	payload := map[string]any{"concurrency": concurrency}
+	req, err := c.newRequest(ctx, http.MethodPost, "/api/v1/system/maintenance/exit", payload)
+	if err != nil {
+		return nil, err
+	}
+	var status HostMaintenance
+	if err := c.do(req, &status); err != nil {
+		return nil, err
+	}
+	return &status, nil
+}
+
+// ReloadResult names the settings a configuration reload changed.
+type ReloadResult struct {
+	ReloadedAt      time.Time `json:"reloaded_at"`
+	File            string    `json:"file,omitempty"`
+	Applied         []string  `json:"applied"`
+	RestartRequired []string  `json:"restart_required"`
+}
+
+// ReloadConfig makes volantd re-read its configuration file.
+func (c *Client) ReloadConfig(ctx context.Context) (*ReloadResult, error) {
+	req, err := c.newRequest(ctx, http.MethodPost, "/api/v1/system/reload", nil)
+	if err != nil {
+		return nil, err
+	}
+	var result ReloadResult
+	if err := c.do(req, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// Capacity reports host CPU and memory against the resources committed to
+// VMs. The Allowed and Available fields are nil without a threshold.
+type Capacity struct {
+	TotalCPUs              int  `json:"total_cpus"`
+	TotalMemoryMB          int  `json:"total_memory_mb"`
+	CPUThresholdPercent    int  `json:"cpu_threshold_percent"`
+	MemoryThresholdPercent int  `json:"memory_threshold_percent"`
+	AllowedCPUs            *int `json:"allowed_cpus"`
+	AllowedMemoryMB        *int `json:"allowed_memory_mb"`
+	CommittedVMs           int  `json:"committed_vms"`
+	CommittedCPUs          int  `json:"committed_cpus"`
+	CommittedMemoryMB      int  `json:"committed_memory_mb"`
+	AvailableCPUs          *int `json:"available_cpus"`
+	AvailableMemoryMB      *int `json:"available_memory_mb"`
+}
+
+func (c *Client) Capacity(ctx context.Context) (*Capacity, error) {
+	req, err := c.newRequest(ctx, http.MethodGet, "/api/v1/system/capacity", nil)
+	if err != nil {
+		return nil, err
+	}
+	var capacity Capacity
+	if err := c.do(req, &capacity); err != nil {
+		return nil, err
+	}
+	return &capacity, nil
+}
+
+// MaintenanceWindow suspends automatic reconciliation for a scope.
+type MaintenanceWindow struct {
+	Name      string              `json:"name"`
+	Scope     string              `json:"scope"`
+	Target    string              `json:"target,omitempty"`
+	Reason    string              `json:"reason,omitempty"`
+	StartsAt  time.Time           `json:"starts_at"`
+	EndsAt    time.Time           `json:"ends_at"`
+	Active    bool                `json:"active"`
+	CreatedAt time.Time           `json:"created_at"`
+	Intents   []MaintenanceIntent `json:"intents,omitempty"`
+}
+
+// MaintenanceIntent is an automatic action held back by a maintenance window.
+type MaintenanceIntent struct {
+	Source     string     `json:"source"`
+	Target     string     `json:"target"`
+	Action     string     `json:"action"`
+	Count      int        `json:"count"`
+	RecordedAt time.Time  `json:"recorded_at"`
+	ReplayedAt *time.Time `json:"replayed_at,omitempty"`
+	Outcome    string     `json:"outcome,omitempty"`
+}
+
+// CreateMaintenanceWindowRequest captures maintenance window inputs.
+type CreateMaintenanceWindowRequest struct {
+	Name     string     `json:"name"`
+	Scope    string     `json:"scope,omitempty"`
+	Target   string     `json:"target,omitempty"`
+	Reason   string     `json:"reason,omitempty"`
+	StartsAt *time.Time `json:"starts_at,omitempty"`
+	EndsAt   *time.Time `json:"ends_at,omitempty"`
+	Duration string     `json:"duration,omitempty"`
+}
+
+func (c *Client) ListMaintenanceWindows(ctx context.Context) ([]MaintenanceWindow, error) {
+	req, err := c.newRequest(ctx, http.MethodGet, "/api/v1/maintenance", nil)
+	if err != nil {
+		return nil, err
+	}
+	var windows []MaintenanceWindow
+	if err := c.do(req, &windows); err != nil {
+		return nil, err
+	}
+	return windows, nil
+}
+
+func (c *Client) CreateMaintenanceWindow(ctx context.Context, payload CreateMaintenanceWindowRequest) (*MaintenanceWindow, error) {
+	req, err := c.newRequest(ctx, http.MethodPost, "/api/v1/maintenance", payload)
+	if err != nil {
+		return nil, err
+	}
+	var window MaintenanceWindow
+	if err := c.do(req, &window); err != nil {
+		return nil, err
+	}
+	return &window, nil
+}
+
+func (c *Client) GetMaintenanceWindow(ctx context.Context, name string) (*MaintenanceWindow, error) {
+	req, err := c.newRequest(ctx, http.MethodGet, "/api/v1/maintenance/"+url.PathEscape(name), nil)
+	if err != nil {
+		return nil, err
+	}
+	var window MaintenanceWindow
+	if err := c.do(req, &window); err != nil {
+		return nil, err
+	}
+	return &window, nil
+}
+
+func (c *Client) EndMaintenanceWindow(ctx context.Context, name string) (*MaintenanceWindow, error) {
+	req, err := c.newRequest(ctx, http.MethodPost, "/api/v1/maintenance/"+url.PathEscape(name)+"/end", nil)
+	if err != nil {
+		return nil, err
+	}
+	var window MaintenanceWindow
+	if err := c.do(req, &window); err != nil {
+		return nil, err
+	}
+	return &window, nil
+}
+
+func (c *Client) DeleteMaintenanceWindow(ctx context.Context, name string) error {
+	req, err := c.newRequest(ctx, http.MethodDelete, "/api/v1/maintenance/"+url.PathEscape(name), nil)
+	if err != nil {
+		return err
+	}
+	return c.do(req, nil)
+}
+
+// Network is a user-defined bridge with its own subnet.
+type Network struct {
+	Name      string         `json:"name"`
+	Bridge    string         `json:"bridge"`
+	Subnet    string         `json:"subnet"`
+	Gateway   string         `json:"gateway"`
+	Internal  bool           `json:"internal"`
+	CreatedAt time.Time      `json:"created_at"`
+	Leases    []NetworkLease `json:"leases,omitempty"`
+}
+
+// NetworkLease is an address handed to a VM on a network.
+type NetworkLease struct {
+	IPAddress string    `json:"ip_address"`
+	VM        string    `json:"vm"`
+	LeasedAt  time.Time `json:"leased_at"`
+}
+
+// CreateNetworkRequest captures network inputs.
+type CreateNetworkRequest struct {
+	Name     string `json:"name"`
+	Subnet   string `json:"subnet"`
+	Gateway  string `json:"gateway,omitempty"`
+	Bridge   string `json:"bridge,omitempty"`
+	Internal bool   `json:"internal,omitempty"`
+}
+
+func (c *Client) ListNetworks(ctx context.Context) ([]Network, error) {
+	req, err := c.newRequest(ctx, http.MethodGet, "/api/v1/networks", nil)
+	if err != nil {
+		return nil, err
+	}
+	var networks []Network
+	if err := c.do(req, &networks); err != nil {
+		return nil, err
+	}
+	return networks, nil
+}
+
+func (c *Client) CreateNetwork(ctx context.Context, payload CreateNetworkRequest) (*Network, error) {
+	req, err := c.newRequest(ctx, http.MethodPost, "/api/v1/networks", payload)
+	if err != nil {
+		return nil, err
+	}
+	var network Network
+	if err := c.do(req, &network); err != nil {
+		return nil, err
+	}
+	return &network, nil
+}
+
+func (c *Client) GetNetwork(ctx context.Context, name string) (*Network, error) {
+	req, err := c.newRequest(ctx, http.MethodGet, "/api/v1/networks/"+url.PathEscape(name), nil)
+	if err != nil {
+		return nil, err
+	}
+	var network Network
+	if err := c.do(req, &network); err != nil {
+		return nil, err
+	}
+	return &network, nil
+}
+
+func (c *Client) DeleteNetwork(ctx context.Context, name string) error {
+	req, err := c.newRequest(ctx, http.MethodDelete, "/api/v1/networks/"+url.PathEscape(name), nil)
+	if err != nil {
+		return err
+	}
+	return c.do(req, nil)
+}
+
+// WarmPool keeps booted, unclaimed VMs of a plugin ready to be claimed.
+type WarmPool struct {
+	Plugin    string    `json:"plugin"`
+	Size      int       `json:"size"`
+	Ready     int       `json:"ready"`
+	Starting  int       `json:"starting"`
+	Config    VMConfig  `json:"config"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// SetWarmPoolRequest sizes a plugin's warm pool. A nil Config uses the
+// plugin's manifest and default resources.
+type SetWarmPoolRequest struct {
+	Size   int       `json:"size"`
+	Config *VMConfig `json:"config,omitempty"`
+}
+
+func (c *Client) ListWarmPools(ctx context.Context) ([]WarmPool, error) {
+	req, err := c.newRequest(ctx, http.MethodGet, "/api/v1/warm-pools", nil)
+	if err != nil {
+		return nil, err
+	}
+	var pools []WarmPool
+	if err := c.do(req, &pools); err != nil {
+		return nil, err
+	}
+	return pools, nil
+}
+
+func (c *Client) GetWarmPool(ctx context.Context, plugin string) (*WarmPool, error) {
+	req, err := c.newRequest(ctx, http.MethodGet, "/api/v1/warm-pools/"+url.PathEscape(plugin), nil)
+	if err != nil {
+		return nil, err
+	}
+	var pool WarmPool
+	if err := c.do(req, &pool); err != nil {
+		return nil, err
+	}
+	return &pool, nil
+}
+
+func (c *Client) SetWarmPool(ctx context.Context, plugin string, payload SetWarmPoolRequest) (*WarmPool, error) {
+	req, err := c.newRequest(ctx, http.MethodPut, "/api/v1/warm-pools/"+url.PathEscape(plugin), payload)
+	if err != nil {
+		return nil, err
+	}
+	var pool WarmPool
+	if err := c.do(req, &pool); err != nil {
+		return nil, err
+	}
+	return &pool, nil
+}
+
+func (c *Client) DeleteWarmPool(ctx context.Context, plugin string) error {
+	req, err := c.newRequest(ctx, http.MethodDelete, "/api/v1/warm-pools/"+url.PathEscape(plugin), nil)
+	if err != nil {
+		return err
+	}
+	return c.do(req, nil)
+}
+
+// ClaimVM takes a ready VM out of a plugin's warm pool and merges labels
+// into its labels.
+func (c *Client) ClaimVM(ctx context.Context, plugin string, labels map[string]string) (*VM, error) {
+	path := "/api/v1/vms/claim?" + url.Values{"plugin": {plugin}}.Encode()
+	payload := struct {
+		Labels map[string]string `json:"labels,omitempty"`
+	}{Labels: labels}
+	req, err := c.newRequest(ctx, http.MethodPost, path, payload)
+	if err != nil {
+		return nil, err
+	}
+	var vm VM
+	if err := c.do(req, &vm); err != nil {
+		return nil, err
+	}
+	return &vm, nil
+}
+
+// ConfigBundle describes a config bundle. Values are never returned.
+type ConfigBundle struct {
+	Name      string            `json:"name"`
+	Version   int               `json:"version"`
+	Keys      []string          `json:"keys"`
+	Files     []string          `json:"files"`
+	CreatedAt time.Time         `json:"created_at"`
+	UpdatedAt time.Time         `json:"updated_at"`
+	Restarted []string          `json:"restarted,omitempty"`
+	Skipped   map[string]string `json:"skipped,omitempty"`
+}
+
+// ConfigBundleRequest carries bundle contents for create and update.
+type ConfigBundleRequest struct {
+	Name  string            `json:"name,omitempty"`
+	Data  map[string]string `json:"data,omitempty"`
+	Files []BundleFile      `json:"files,omitempty"`
+}
+
+func (c *Client) ListConfigBundles(ctx context.Context) ([]ConfigBundle, error) {
+	req, err := c.newRequest(ctx, http.MethodGet, "/api/v1/config-bundles", nil)
+	if err != nil {
+		return nil, err
+	}
+	var bundles []ConfigBundle
+	if err := c.do(req, &bundles); err != nil {
+		return nil, err
+	}
+	return bundles, nil
+}
+
+func (c *Client) CreateConfigBundle(ctx context.Context, payload ConfigBundleRequest) (*ConfigBundle, error) {
+	req, err := c.newRequest(ctx, http.MethodPost, "/api/v1/config-bundles", payload)
+	if err != nil {
+		return nil, err
+	}
+	var bundle ConfigBundle
+	if err := c.do(req, &bundle); err != nil {
+		return nil, err
+	}
+	return &bundle, nil
+}
+
+func (c *Client) GetConfigBundle(ctx context.Context, name string) (*ConfigBundle, error) {
+	req, err := c.newRequest(ctx, http.MethodGet, "/api/v1/config-bundles/"+url.PathEscape(name), nil)
+	if err != nil {
+		return nil, err
+	}
+	var bundle ConfigBundle
+	if err := c.do(req, &bundle); err != nil {
+		return nil, err
+	}
+	return &bundle, nil
+}
+
+// UpdateConfigBundle replaces the bundle contents; deployments using it are
+// rolled onto the new version.
+func (c *Client) UpdateConfigBundle(ctx context.Context, name string, payload ConfigBundleRequest) (*ConfigBundle, error) {
+	payload.Name = ""
+	req, err := c.newRequest(ctx, http.MethodPut, "/api/v1/config-bundles/"+url.PathEscape(name), payload)
+	if err != nil {
+		return nil, err
+	}
+	var bundle ConfigBundle
+	if err := c.do(req, &bundle); err != nil {
+		return nil, err
+	}
+	return &bundle, nil
+}
+
+func (c *Client) DeleteConfigBundle(ctx context.Context, name string) error {
+	req, err := c.newRequest(ctx, http.MethodDelete, "/api/v1/config-bundles/"+url.PathEscape(name), nil)
+	if err != nil {
+		return err
+	}
+	return c.do(req, nil)
+}
+
+// TransactionStep is one operation in a transaction; Spec is the body the
+// operation's standalone endpoint accepts.
+type TransactionStep struct {
+	Op   string          `json:"op"`
+	Spec json.RawMessage `json:"spec"`
+}
+
+// TransactionRequest is a batch of dependent operations applied atomically.
+type TransactionRequest struct {
+	Steps []TransactionStep `json:"steps"`
+}
+
+// TransactionStepResult reports the outcome of one step.
+type TransactionStepResult struct {
+	Index  int             `json:"index"`
+	Op     string          `json:"op"`
+	Name   string          `json:"name,omitempty"`
+	Status string          `json:"status"`
+	Error  string          `json:"error,omitempty"`
+	Code   string          `json:"code,omitempty"`
+	Result json.RawMessage `json:"result,omitempty"`
+}
+
+// TransactionResult is "committed" or "rolled_back" with per-step results.
+type TransactionResult struct {
+	Status string                  `json:"status"`
+	Steps  []TransactionStepResult `json:"steps"`
+}
+
+// ApplyTransaction applies a transaction. A rolled-back transaction returns
+// its per-step results together with an error.
+func (c *Client) ApplyTransaction(ctx context.Context, payload TransactionRequest) (*TransactionResult, error) {
+	req, err := c.newRequest(ctx, http.MethodPost, "/api/v1/transactions", payload)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("client: do request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("client: read response: %w", err)
+	}
+	var body struct {
+		TransactionResult
+		Error string `json:"error"`
+	}
+	if err := json.Unmarshal(data, &body); err != nil {
+		return nil, fmt.Errorf("client: http %d", resp.StatusCode)
+	}
+	if body.Error != "" {
+		return nil, decodeAPIError(resp.StatusCode, data)
+	}
+	result := body.TransactionResult
+	if resp.StatusCode >= 300 {
+		return &result, fmt.Errorf("client: http %d: transaction %s", resp.StatusCode, result.Status)
+	}
+	return &result, nil
+}
+
+// Resource is a declared plugin, VM or deployment. Spec is a plugin
+// manifest, {config, labels, restart} for a VM, or {replicas, max_surge,
+// max_unavailable, config} for a deployment.
+type Resource struct {
+	Kind string          `json:"kind"`
+	Name string          `json:"name"`
+	Spec json.RawMessage `json:"spec"`
+}
+
+// ApplyRequest declares resources to create or update. With DryRun the
+// server only reports what would change.
+type ApplyRequest struct {
+	Resources []Resource `json:"resources"`
+	DryRun    bool       `json:"dry_run,omitempty"`
+}
+
+// ResourceChange is one field apply changes, or would change.
+type ResourceChange struct {
+	Field string `json:"field"`
+	From  any    `json:"from,omitempty"`
+	To    any    `json:"to,omitempty"`
+}
+
+// ResourceResult reports what apply did to one resource. Action is create,
+// update or unchanged; Status is planned, applied, failed or skipped.
+type ResourceResult struct {
+	Index   int              `json:"index"`
+	Kind    string           `json:"kind"`
+	Name    string           `json:"name"`
+	Action  string           `json:"action,omitempty"`
+	Changes []ResourceChange `json:"changes,omitempty"`
+	Status  string           `json:"status"`
+	Error   string           `json:"error,omitempty"`
+	Code    string           `json:"code,omitempty"`
+}
+
+// ApplyResult holds the per-resource results of an apply.
+type ApplyResult struct {
+	DryRun    bool             `json:"dry_run"`
+	Resources []ResourceResult `json:"resources"`
+}
+
+// Apply creates or updates the declared resources. When a resource fails,
+// the per-resource results are returned together with an error.
+func (c *Client) Apply(ctx context.Context, payload ApplyRequest) (*ApplyResult, error) {
+	req, err := c.newRequest(ctx, http.MethodPost, "/api/v1/apply", payload)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("client: do request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("client: read response: %w", err)
+	}
+	var body struct {
+		ApplyResult
+		Error string `json:"error"`
+	}
+	if err := json.Unmarshal(data, &body); err != nil {
+		return nil, fmt.Errorf("client: http %d", resp.StatusCode)
+	}
+	if body.Error != "" {
+		return nil, decodeAPIError(resp.StatusCode, data)
+	}
+	result := body.ApplyResult
+	if resp.StatusCode >= 300 {
+		for _, res := range result.Resources {
+			if res.Status == "failed" {
+				return &result, fmt.Errorf("client: http %d: %s %s: %s", resp.StatusCode, res.Kind, res.Name, res.Error)
+			}
+		}
+		return &result, fmt.Errorf("client: http %d", resp.StatusCode)
+	}
+	return &result, nil
+}
+
+// AuditEvent is an entry in the daemon audit trail.
+type AuditEvent struct {
+	ID         int64     `json:"id"`
+	Action     string    `json:"action"`
+	Target     string    `json:"target"`
+	Actor      string    `json:"actor"`
+	ClientIP   string    `json:"client_ip,omitempty"`
+	Message    string    `json:"message,omitempty"`
+	DurationMS int64     `json:"duration_ms,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// AuditEvents lists recent audit events, optionally filtered to one VM.
+func (c *Client) AuditEvents(ctx context.Context, target string, limit int) ([]AuditEvent, error) {
+	query := url.Values{}
+	if target != "" {
+		query.Set("target", target)
+	}
+	if limit > 0 {
+		query.Set("limit", strconv.Itoa(limit))
+	}
+	path := "/api/v1/audit"
+	if encoded := query.Encode(); encoded != "" {
+		path += "?" + encoded
+	}
+	req, err := c.newRequest(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return nil, err
+	}
+	var events []AuditEvent
+	if err := c.do(req, &events); err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
+func (c *Client) DeleteVM(ctx context.Context, name string) error {
+	req, err := c.newRequest(ctx, http.MethodDelete, "/api/v1/vms/"+url.PathEscape(name), nil)
+	if err != nil {
+		return err
+	}
+	return c.do(req, nil)
+}
+
+func (c *Client) WatchVMLogs(ctx context.Context, name string, handler func(VMLogEvent)) error {
+	if name == "" {
+		return fmt.Errorf("client: vm name required")
+	}
+	if handler == nil {
+		return fmt.Errorf("client: handler required")
+	}
+
+	conn, err := c.dialWebSocket(ctx, fmt.Sprintf("/ws/v1/vms/%s/logs", url.PathEscape(name)), "")
+	if err != nil {
+		return fmt.Errorf("client: watch vm logs dial: %w", err)
+	}
+	defer conn.Close()
+
+	done := make(chan struct{})
+	go func() {
+		select {
+		case <-ctx.Done():
+			_ = conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""), time.Now().Add(time.Second))
+			_ = conn.Close()
+		case <-done:
+		}
+	}()
+
+	for {
+		var event VMLogEvent
+		if err := conn.ReadJSON(&event); err != nil {
+			if websocket.IsCloseError(err, websocket.CloseNormalClosure, websocket.CloseGoingAway) || errors.Is(err, context.Canceled) {
+				close(done)
+				return nil
+			}
+			close(done)
+			return fmt.Errorf("client: read vm log: %w", err)
+		}
+		handler(event)
+	}
+}
+
+// VMWatchMessage is one message on a VM watch stream. Data holds a VM on
+// status snapshots, a VMEvent on status transitions, a versioned config on the
+// config channel, a heartbeat or a log line.
+type VMWatchMessage struct {
+	Channel   string          `json:"channel"`
+	Type      string          `json:"type"`
+	Name      string          `json:"name"`
+	Timestamp time.Time       `json:"timestamp"`
+	Data      json.RawMessage `json:"data,omitempty"`
+}
+
+// WatchVM streams status, config, heartbeat and, when requested, log
+// messages for one VM over a single connection. Empty channels selects the
+// server default (everything except logs).
+func (c *Client) WatchVM(ctx context.Context, name string, channels []string, handler func(VMWatchMessage)) error {
+	if name == "" {
+		return fmt.Errorf("client: vm name required")
+	}
+	if handler == nil {
+		return fmt.Errorf("client: handler required")
+	}
+	var rawQuery string
+	if len(channels) > 0 {
+		rawQuery = url.Values{"channels": {strings.Join(channels, ",")}}.Encode()
+	}
+	conn, err := c.dialWebSocket(ctx, fmt.Sprintf("/ws/v1/vms/%s/watch", url.PathEscape(name)), rawQuery)
+	if err != nil {
+		return fmt.Errorf("client: watch vm dial: %w", err)
+	}
+	defer conn.Close()
+
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+			_ = conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""), time.Now().Add(time.Second))
+			_ = conn.Close()
+		case <-done:
+		}
+	}()
+
+	for {
+		var msg VMWatchMessage
+		if err := conn.ReadJSON(&msg); err != nil {
+			if websocket.IsCloseError(err, websocket.CloseNormalClosure, websocket.CloseGoingAway) || ctx.Err() != nil {
+				return nil
+			}
+			return fmt.Errorf("client: read vm watch: %w", err)
+		}
+		handler(msg)
+	}
+}
+
+// VMLogEntry is one persisted line of hypervisor or guest agent output.
+type VMLogEntry struct {
+	Timestamp time.Time `json:"timestamp"`
+	Source    string    `json:"source"`
+	Stream    string    `json:"stream"`
+	Line      string    `json:"line"`
+}
+
+// VMLogsQuery filters persisted VM logs. Since is an RFC 3339 time or a
+// duration back from now; empty fields match everything.
+type VMLogsQuery struct {
+	Since  string
+	Stream string
+	Source string
+	Limit  int
+}
+
+// GetVMLogs fetches the persisted logs of a VM, oldest first.
+func (c *Client) GetVMLogs(ctx context.Context, name string, query VMLogsQuery) ([]VMLogEntry, error) {
+	if name == "" {
+		return nil, fmt.Errorf("client: vm name required")
+	}
+	values := url.Values{}
+	if query.Since != "" {
+		values.Set("since", query.Since)
+	}
+	if query.Stream != "" {
+		values.Set("stream", query.Stream)
+	}
+	if query.Source != "" {
+		values.Set("source", query.Source)
+	}
+	if query.Limit > 0 {
+		values.Set("limit", strconv.Itoa(query.Limit))
+	}
+	path := fmt.Sprintf("/api/v1/vms/%s/logs", url.PathEscape(name))
+	if encoded := values.Encode(); encoded != "" {
+		path += "?" + encoded
+	}
+	req, err := c.newRequest(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return nil, err
+	}
+	var resp struct {
+		Entries []VMLogEntry `json:"entries"`
+	}
+	if err := c.do(req, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Entries, nil
+}
+
+// VMEventRecord is a recorded lifecycle event from a VM's event history.
+type VMEventRecord struct {
+	ID        int64     `json:"id"`
+	Type      string    `json:"type"`
+	Status    string    `json:"status,omitempty"`
+	Message   string    `json:"message,omitempty"`
+	Code      string    `json:"code,omitempty"`
+	Hint      string    `json:"hint,omitempty"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// VMEventsQuery filters a VM's event history. Since is an RFC 3339 time or a
+// duration back from now; empty fields match everything.
+type VMEventsQuery struct {
+	Since string
+	Types []string
+	Limit int
+}
+
+// GetVMEvents fetches the recorded lifecycle events of a VM, oldest first.
+// History is kept for a while after the VM is deleted.
+func (c *Client) GetVMEvents(ctx context.Context, name string, query VMEventsQuery) ([]VMEventRecord, error) {
+	if name == "" {
+		return nil, fmt.Errorf("client: vm name required")
+	}
+	values := url.Values{}
+	if query.Since != "" {
+		values.Set("since", query.Since)
+	}
+	if len(query.Types) > 0 {
+		values.Set("type", strings.Join(query.Types, ","))
+	}
+	if query.Limit > 0 {
+		values.Set("limit", strconv.Itoa(query.Limit))
+	}
+	path := fmt.Sprintf("/api/v1/vms/%s/events", url.PathEscape(name))
+	if encoded := values.Encode(); encoded != "" {
+		path += "?" + encoded
+	}
+	req, err := c.newRequest(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return nil, err
+	}
+	var events []VMEventRecord
+	if err := c.do(req, &events); err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
+// ConsoleLog downloads the recent serial console output of a VM. tail limits
+// the output to the last lines; 0 returns everything the server kept.
+func (c *Client) ConsoleLog(ctx context.Context, name string, tail int) ([]byte, error) {
+	if name == "" {
+		return nil, fmt.Errorf("client: vm name required")
+	}
+	path := fmt.Sprintf("/api/v1/vms/%s/console/log?tail=%d", url.PathEscape(name), tail)
+	req, err := c.newRequest(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("client: console log: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		data, _ := io.ReadAll(resp.Body)
+		return nil, decodeAPIError(resp.StatusCode, data)
+	}
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("client: read console log: %w", err)
+	}
+	return data, nil
+}
+
+// AttachConsole opens the serial console of a VM through the API. The stream
+// first replays up to replay recent output lines, then carries live output;
+// writes go to the guest.
+func (c *Client) AttachConsole(ctx context.Context, name string, replay int) (io.ReadWriteCloser, error) {
+	if name == "" {
+		return nil, fmt.Errorf("client: vm name required")
+	}
+	rawQuery := url.Values{"replay": {strconv.Itoa(replay)}}.Encode()
+	conn, err := c.dialWebSocket(ctx, fmt.Sprintf("/ws/v1/vms/%s/console", url.PathEscape(name)), rawQuery)
+	if err != nil {
+		return nil, fmt.Errorf("client: console dial: %w", err)
+	}
+	return &consoleStream{conn: conn}, nil
+}
+
+// DialSSH opens a tunnel through volantd to the SSH port of a VM.
+func (c *Client) DialSSH(ctx context.Context, name string) (io.ReadWriteCloser, error) {
+	if name == "" {
+		return nil, fmt.Errorf("client: vm name required")
+	}
+	conn, err := c.dialWebSocket(ctx, fmt.Sprintf("/ws/v1/vms/%s/ssh", url.PathEscape(name)), "")
+	if err != nil {
+		return nil, fmt.Errorf("client: ssh dial: %w", err)
+	}
+	return &consoleStream{conn: conn}, nil
+}
+
+// consoleStream adapts a console or SSH tunnel WebSocket to a byte stream.
+type consoleStream struct {
+	conn   *websocket.Conn
+	reader io.Reader
+}
+
+func (s *consoleStream) Read(p []byte) (int, error) {
+	for {
+		if s.reader == nil {
+			_, reader, err := s.conn.NextReader()
+			if err != nil {
+				if websocket.IsCloseError(err, websocket.CloseNormalClosure, websocket.CloseGoingAway) {
+					return 0, io.EOF
+				}
+				return 0, err
+			}
+			s.reader = reader
+		}
+		n, err := s.reader.Read(p)
+		if errors.Is(err, io.EOF) {
+			s.reader = nil
+			if n == 0 {
+				continue
+			}
+			err = nil
+		}
+		return n, err
+	}
+}
+
+func (s *consoleStream) Write(p []byte) (int, error) {
+	if err := s.conn.WriteMessage(websocket.BinaryMessage, p); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+func (s *consoleStream) Close() error {
+	_ = s.conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""), time.Now().Add(time.Second))
+	return s.conn.Close()
+}
+
+// dialWebSocket opens a WebSocket to path on the API, over the Unix socket
+// when the client uses one.
+func (c *Client) dialWebSocket(ctx context.Context, path, rawQuery string) (*websocket.Conn, error) {
+	wsURL := c.baseURL.ResolveReference(&url.URL{Path: path, RawQuery: rawQuery})
+	switch wsURL.Scheme {
+	case "http":
+		wsURL.Scheme = "ws"
+	case "https":
+		wsURL.Scheme = "wss"
+	case "ws", "wss":
+	default:
+		return nil, fmt.Errorf("unsupported scheme %q", wsURL.Scheme)
+	}
+
+	dialer := websocket.Dialer{
+		Proxy:            http.ProxyFromEnvironment,
+		HandshakeTimeout: 30 * time.Second,
+		TLSClientConfig:  c.tlsConfig,
+	}
+	if c.socketPath != "" {
+		dialer.Proxy = nil
+		dialer.NetDialContext = c.dialSocket
+	}
+	header := http.Header{}
+	if c.apiKey != "" {
+		header.Set(apiauth.Header, c.apiKey)
+	}
+
+	conn, resp, err := dialer.DialContext(ctx, wsURL.String(), header)
+	if resp != nil && resp.Body != nil {
+		defer resp.Body.Close()
+	}
+	if err != nil {
+		if resp != nil && resp.StatusCode >= 300 {
+			return nil, fmt.Errorf("http %d", resp.StatusCode)
+		}
+		return nil, err
+	}
+	return conn, nil
+}
+
+func (c *Client) AgentRequest(ctx context.Context, vmName, method, path string, body any, out any) error {
+	if strings.TrimSpace(vmName) == "" {
+		return fmt.Errorf("client: vm name required")
+	}
+	if method == "" {
+		method = http.MethodGet
+	}
+	if path == "" {
+		path = "/"
+	}
+	var rawQuery string
+	if idx := strings.Index(path, "?"); idx >= 0 {
+		rawQuery = path[idx+1:]
+		path = path[:idx]
+	}
+	if !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+	basePath := fmt.Sprintf("/api/v1/vms/%s/agent%s", url.PathEscape(vmName), path)
+
+	resolved := c.baseURL.ResolveReference(&url.URL{
+		Path:     basePath,
+		RawQuery: rawQuery,
+	})
+
+	var buf bytes.Buffer
+	if body != nil {
+		if err := json.NewEncoder(&buf).Encode(body); err != nil {
+			return fmt.Errorf("client: encode body: %w", err)
+		}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, resolved.String(), &buf)
+	if err != nil {
+		return fmt.Errorf("client: agent request: %w", err)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	return c.do(req, out)
+}
+
+// AgentRequestVsock sends a request directly to the VM agent over vsock.
+// This is used for vsock-only VMs that don't have IP networking.
+// The default CID is 3 and port is 8080 (assuming the volant agent listens on vsock port 8080).
+func (c *Client) AgentRequestVsock(ctx context.Context, cid, port uint32, method, path string, body any, out any) error {
+	if method == "" {
+		method = http.MethodGet
+	}
+	if path == "" {
+		path = "/"
+	}
+	if !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+
+	vsockClient := vsock.NewClient(cid, port)
+	return vsockClient.DoJSON(ctx, method, path, body, out)
+}
+
+// AgentRequestVsockDefault is a convenience wrapper that uses the default CID (3) and port (8080).
+func (c *Client) AgentRequestVsockDefault(ctx context.Context, method, path string, body any, out any) error {
+	const defaultCID = 3
+	const defaultPort = 8080
+	return c.AgentRequestVsock(ctx, defaultCID, defaultPort, method, path, body, out)
+}
+
+func (c *Client) GetAgentDevTools(ctx context.Context, vmName string) (*DevToolsInfo, error) {
+	var info DevToolsInfo
+	if err := c.AgentRequest(ctx, vmName, http.MethodGet, "/v1/devtools", nil, &info); err != nil {
+		return nil, err
+	}
+	return &info, nil
+}
+
+// SyncFiles pushes a batch of file changes into the VM through its agent.
+func (c *Client) SyncFiles(ctx context.Context, vmName string, batch SyncBatch) (*SyncResult, error) {
+	var result SyncResult
+	if err := c.AgentRequest(ctx, vmName, http.MethodPost, "/v1/dev/sync", batch, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+func (c *Client) BaseURL() *url.URL {
+	if c.baseURL == nil {
+		return nil
+	}
+	clone := *c.baseURL
+	return &clone
+}
+
+// MCP sends one JSON-RPC request to the MCP endpoint. JSONRPC defaults to
+// "2.0" and ID to 1.
+func (c *Client) MCP(ctx context.Context, request MCPRequest) (*MCPResponse, error) {
+	if request.JSONRPC == "" {
+		request.JSONRPC = "2.0"
+	}
+	if request.ID == 0 {
+		request.ID = 1
+	}
+	var response MCPResponse
+	req, err := c.newRequest(ctx, http.MethodPost, "/api/v1/mcp", request)
+	if err != nil {
+		return nil, err
+	}
+	if err := c.do(req, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+func (c *Client) GetVMOpenAPISpec(ctx context.Context, name string) ([]byte, string, error) {
+	path := fmt.Sprintf("/api/v1/vms/%s/openapi", url.PathEscape(name))
+	req, err := c.newRequest(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return nil, "", err
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, "", fmt.Errorf("client: do request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	data, readErr := io.ReadAll(resp.Body)
+	if readErr != nil {
+		return nil, "", fmt.Errorf("client: read response: %w", readErr)
+	}
+
+	if resp.StatusCode >= 300 {
+		return nil, "", decodeAPIError(resp.StatusCode, data)
+	}
+
+	return data, resp.Header.Get("Content-Type"), nil
+}
+
+func (c *Client) ProxyVM(ctx context.Context, vmName, method, path string, query url.Values, body []byte, headers http.Header) (*http.Response, error) {
+	if method == "" {
+		method = http.MethodGet
+	}
+	targetPath := fmt.Sprintf("/api/v1/vms/%s/agent%s", url.PathEscape(vmName), ensureLeadingSlash(path))
+	ref := &url.URL{Path: targetPath}
+	if query != nil {
+		ref.RawQuery = query.Encode()
+	}
+	resolved := c.baseURL.ResolveReference(ref)
+
+	var reader io.Reader
+	if len(body) > 0 {
+		reader = bytes.NewReader(body)
+	} else {
+		reader = http.NoBody
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, resolved.String(), reader)
+	if err != nil {
+		return nil, fmt.Errorf("client: new request: %w", err)
+	}
+
+	if headers != nil {
+		for key, values := range headers {
+			for _, value := range values {
+				req.Header.Add(key, value)
+			}
+		}
+	}
+	if len(body) > 0 && req.Header.Get("Content-Type") == "" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("client: do request: %w", err)
+	}
+	return resp, nil
+}
+
+func (c *Client) newRequest(ctx context.Context, method, path string, body any) (*http.Request, error) {
+	ref := &url.URL{Path: path}
+	if idx := strings.Index(path, "?"); idx >= 0 {
+		ref.Path, ref.RawQuery = path[:idx], path[idx+1:]
+	}
+	resolved := c.baseURL.ResolveReference(ref)
+	var buf bytes.Buffer
+	if body != nil {
+		if err := json.NewEncoder(&buf).Encode(body); err != nil {
+			return nil, fmt.Errorf("client: encode body: %w", err)
+		}
+	}
+	req, err := http.NewRequestWithContext(ctx, method, resolved.String(), &buf)
+	if err != nil {
+		return nil, fmt.Errorf("client: new request: %w", err)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	return req, nil
+}
+
+func (c *Client) do(req *http.Request, out any) error {
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("client: do request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		data, _ := io.ReadAll(resp.Body)
+		return decodeAPIError(resp.StatusCode, data)
+	}
+
+	if out == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("client: decode response: %w", err)
+	}
+	return nil
+}
+
+// post sends body and decodes the response into out.
+func (c *Client) post(ctx context.Context, path string, body, out any) error {
+	req, err := c.newRequest(ctx, http.MethodPost, path, body)
+	if err != nil {
+		return err
+	}
+	return c.do(req, out)
+}
+
+// SystemStatus represents the system metrics.
+type SystemStatus struct {
+	VMCount int     `json:"vm_count"`
+	CPU     float64 `json:"cpu_percent"`
+	MEM     float64 `json:"mem_percent"`
+}
+
+func ensureLeadingSlash(path string) string {
+	if path == "" {
+		return "/"
+	}
+	if !strings.HasPrefix(path, "/") {
+		return "/" + path
+	}
+	return path
+}
+
+// GetSystemStatus fetches system metrics.
+func (c *Client) GetSystemStatus(ctx context.Context) (*SystemStatus, error) {
+	req, err := c.newRequest(ctx, http.MethodGet, "/api/v1/system/status", nil)
+	if err != nil {
+		return nil, err
+	}
+	var status SystemStatus
+	if err := c.do(req, &status); err != nil {
+		return nil, err
+	}
+	return &status, nil
+}
+
+// ListPluginNames returns the names of the installed plugins without
+// fetching their manifests.
+func (c *Client) ListPluginNames(ctx context.Context) ([]string, error) {
+	req, err := c.newRequest(ctx, http.MethodGet, "/api/v1/plugins", nil)
+	if err != nil {
+		return nil, err
+	}
+	var response struct {
+		Plugins []string `json:"plugins"`
+	}
+	if err := c.do(req, &response); err != nil {
+		return nil, err
+	}
+	return response.Plugins, nil
+}
+
+func (c *Client) ListPlugins(ctx context.Context) ([]Plugin, error) {
+	names, err := c.ListPluginNames(ctx)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]Plugin, 0, len(names))
+	for _, name := range names {
+		plugin, err := c.GetPlugin(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+		if plugin != nil {
+			result = append(result, *plugin)
+		}
+	}
+	return result, nil
+}
+
+func (c *Client) GetPlugin(ctx context.Context, name string) (*Plugin, error) {
+	req, err := c.newRequest(ctx, http.MethodGet, "/api/v1/plugins/"+url.PathEscape(name), nil)
+	if err != nil {
+		return nil, err
+	}
+	var manifest Plugin
+	if err := c.do(req, &manifest); err != nil {
+		return nil, err
+	}
+	return &manifest, nil
+}
+
+func (c *Client) DescribePlugin(ctx context.Context, name string) (*Plugin, error) {
+	return c.GetPlugin(ctx, name)
+}
+
+func (c *Client) InstallPlugin(ctx context.Context, manifest Plugin) error {
+	req, err := c.newRequest(ctx, http.MethodPost, "/api/v1/plugins", manifest)
+	if err != nil {
+		return err
+	}
+	return c.do(req, nil)
+}
+
+// StagePluginVersion installs another version of an installed plugin without
+// making it the active one; UpgradePlugin switches to it.
+func (c *Client) StagePluginVersion(ctx context.Context, manifest Plugin) error {
+	req, err := c.newRequest(ctx, http.MethodPost, "/api/v1/plugins?activate=false", manifest)
+	if err != nil {
+		return err
+	}
+	return c.do(req, nil)
+}
+
+// PluginVersion is an installed version of a plugin.
+type PluginVersion struct {
+	Version     string    `json:"version"`
+	Active      bool      `json:"active"`
+	InstalledAt time.Time `json:"installed_at"`
+}
+
+// PluginVMVersion reports the plugin version a VM runs.
+type PluginVMVersion struct {
+	VM         string `json:"vm"`
+	Deployment string `json:"deployment,omitempty"`
+	Version    string `json:"version"`
+}
+
+// PluginVersions lists the installed versions of a plugin and the VMs still
+// running a version other than the active one.
+type PluginVersions struct {
+	Plugin   string            `json:"plugin"`
+	Active   string            `json:"active"`
+	Versions []PluginVersion   `json:"versions"`
+	VMs      []PluginVMVersion `json:"vms"`
+	Drift    []string          `json:"drift"`
+}
+
+// PluginUpgrade reports the outcome of switching a plugin's active version.
+type PluginUpgrade struct {
+	Plugin    string            `json:"plugin"`
+	From      string            `json:"from"`
+	To        string            `json:"to"`
+	Restarted []string          `json:"restarted,omitempty"`
+	Skipped   map[string]string `json:"skipped,omitempty"`
+	Drift     []string          `json:"drift"`
+}
+
+func (c *Client) ListPluginVersions(ctx context.Context, name string) (*PluginVersions, error) {
+	req, err := c.newRequest(ctx, http.MethodGet, "/api/v1/plugins/"+url.PathEscape(name)+"/versions", nil)
+	if err != nil {
+		return nil, err
+	}
+	var versions PluginVersions
+	if err := c.do(req, &versions); err != nil {
+		return nil, err
+	}
+	return &versions, nil
+}
+
+// UpgradePlugin makes an installed version the active one, optionally rolling
+// the plugin's deployments onto it.
+func (c *Client) UpgradePlugin(ctx context.Context, name, version string, rollDeployments bool) (*PluginUpgrade, error) {
+	payload := map[string]any{"version": version, "roll_deployments": rollDeployments}
+	req, err := c.newRequest(ctx, http.MethodPost, "/api/v1/plugins/"+url.PathEscape(name)+"/upgrade", payload)
+	if err != nil {
+		return nil, err
+	}
+	var upgrade PluginUpgrade
+	if err := c.do(req, &upgrade); err != nil {
+		return nil, err
+	}
+	return &upgrade, nil
+}
+
+func (c *Client) RemovePlugin(ctx context.Context, name string) error {
+	req, err := c.newRequest(ctx, http.MethodDelete, "/api/v1/plugins/"+url.PathEscape(name), nil)
+	if err != nil {
+		return err
+	}
+	return c.do(req, nil)
+}
+
+func (c *Client) SetPluginEnabled(ctx context.Context, name string, enabled bool) error {
+	payload := map[string]any{"enabled": enabled}
+	req, err := c.newRequest(ctx, http.MethodPost, "/api/v1/plugins/"+url.PathEscape(name)+"/enabled", payload)
+	if err != nil {
+		return err
+	}
+	return c.do(req, nil)
+}
@@ -0,0 +1,31 @@
+// Copyright (c) 2025 HYPR. PTE. LTD.
+//
+// Business Source License 1.1
+// See LICENSE file in the project root for details.
+
+// Package volantclient is the Go client of the volantd API. volar is built
+// on it, and other programs can import it instead of speaking HTTP
+// themselves:
+//
+//	api, err := volantclient.NewWithOptions("https://volant.example:7777", volantclient.Options{
+//		APIKey: os.Getenv("VOLANT_API_KEY"),
+//	})
+//	if err != nil {
+//		return err
+//	}
+//	for vm, err := range api.AllVMs(ctx, volantclient.VMListOptions{Selector: "tier=web"}) {
+//		if err != nil {
+//			return err
+//		}
+//		fmt.Println(vm.Name, vm.Status)
+//	}
+//
+// Every call takes a context that bounds it. Plain calls also time out after
+// 30 seconds; file transfers, streams and watches run until their context is
+// done. Failed calls return an *APIError whose Code is stable across
+// releases; IsCode tests for one.
+//
+// WatchEvents, WatchVMEvents, WatchVM, WatchVMLogs and WatchAGUIEvents
+// stream events to a handler over WebSocket or server-sent events, and
+// ListVMsPage and AllVMs page through VMs.
+package volantclient
@@ -0,0 +1,69 @@
+// Copyright (c) 2025 HYPR. PTE. LTD.
+//
+// Business Source License 1.1
+// See LICENSE file in the project root for details.
+
+package volantclient
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// APIError is an error response from volantd. Code is stable across
+// releases; branch on it rather than on Message.
+type APIError struct {
+	Status  int
+	Code    string
+	Message string
+	Details map[string]any
+}
+
+func (e *APIError) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("client: http %d", e.Status)
+	}
+	msg := fmt.Sprintf("client: http %d: %s", e.Status, e.Message)
+	if hint, ok := e.Details["hint"].(string); ok && hint != "" {
+		msg += "\nhint: " + hint
+	}
+	return msg
+}
+
+// IsCode reports whether err is an API error with the given code.
+func IsCode(err error, code string) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.Code == code
+}
+
+// decodeAPIError parses an error response body. Servers predating codes
+// send only error, with launch hints at the top level.
+func decodeAPIError(status int, data []byte) error {
+	var body struct {
+		Code    string         `json:"code"`
+		Message string         `json:"message"`
+		Details map[string]any `json:"details"`
+		Error   string         `json:"error"`
+		Hint    string         `json:"hint"`
+	}
+	apiErr := &APIError{Status: status}
+	if len(data) == 0 || json.Unmarshal(data, &body) != nil {
+		return apiErr
+	}
+	apiErr.Code = body.Code
+	apiErr.Message = body.Message
+	if apiErr.Message == "" {
+		apiErr.Message = body.Error
+	}
+	apiErr.Details = body.Details
+	if body.Hint != "" {
+		if apiErr.Details == nil {
+			apiErr.Details = map[string]any{}
+		}
+		if _, ok := apiErr.Details["hint"]; !ok {
+			apiErr.Details["hint"] = body.Hint
+		}
+	}
+	return apiErr
+}
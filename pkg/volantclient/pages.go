@@ -0,0 +1,138 @@
+// Copyright (c) 2025 HYPR. PTE. LTD.
+//
+// Business Source License 1.1
+// See LICENSE file in the project root for details.
+
+package volantclient
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"iter"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// defaultVMPageSize is how many VMs AllVMs fetches per request when the
+// options set no limit.
+const defaultVMPageSize = 100
+
+// VMListOptions filters, sorts and pages VMs. Zero fields are unset.
+type VMListOptions struct {
+	// Status keeps VMs in any of these statuses.
+	Status  []string
+	Runtime string
+	// Group keeps the replicas of the named deployment.
+	Group  string
+	Plugin string
+	// Query searches VM names, IP addresses and runtimes.
+	Query string
+	// Selector is a label selector such as "tier=web,env!=dev".
+	Selector string
+	// Sort is one of name, status, runtime, created_at or updated_at.
+	Sort       string
+	Descending bool
+	// Limit caps the VMs returned; 0 returns every match.
+	Limit  int
+	Offset int
+}
+
+func (o VMListOptions) query() url.Values {
+	values := url.Values{}
+	if len(o.Status) > 0 {
+		values.Set("status", strings.Join(o.Status, ","))
+	}
+	for key, value := range map[string]string{
+		"runtime":  o.Runtime,
+		"group":    o.Group,
+		"plugin":   o.Plugin,
+		"q":        o.Query,
+		"selector": o.Selector,
+		"sort":     o.Sort,
+	} {
+		if value != "" {
+			values.Set(key, value)
+		}
+	}
+	if o.Descending {
+		values.Set("order", "desc")
+	}
+	if o.Limit > 0 {
+		values.Set("limit", strconv.Itoa(o.Limit))
+	}
+	if o.Offset > 0 {
+		values.Set("offset", strconv.Itoa(o.Offset))
+	}
+	return values
+}
+
+// VMPage is one page of VMs. Total counts every VM matching the filters,
+// across all pages.
+type VMPage struct {
+	VMs    []VM
+	Total  int
+	Offset int
+}
+
+// ListVMsPage returns the page of VMs selected by opts.
+func (c *Client) ListVMsPage(ctx context.Context, opts VMListOptions) (*VMPage, error) {
+	path := "/api/v1/vms"
+	if query := opts.query(); len(query) > 0 {
+		path += "?" + query.Encode()
+	}
+	req, err := c.newRequest(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("client: do request: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		data, _ := io.ReadAll(resp.Body)
+		return nil, decodeAPIError(resp.StatusCode, data)
+	}
+	page := &VMPage{Offset: opts.Offset}
+	if err := json.NewDecoder(resp.Body).Decode(&page.VMs); err != nil {
+		return nil, fmt.Errorf("client: decode response: %w", err)
+	}
+	page.Total = len(page.VMs) + opts.Offset
+	if total, err := strconv.Atoi(resp.Header.Get("X-Total-Count")); err == nil {
+		page.Total = total
+	}
+	return page, nil
+}
+
+// AllVMs iterates over the VMs matching opts, fetching opts.Limit of them
+// per request (100 when unset) from opts.Offset on. The first failed
+// request is yielded as an error and ends the iteration. VMs created or
+// deleted meanwhile may shift the pages, so a VM can be skipped or seen
+// twice.
+func (c *Client) AllVMs(ctx context.Context, opts VMListOptions) iter.Seq2[VM, error] {
+	return func(yield func(VM, error) bool) {
+		if opts.Limit <= 0 {
+			opts.Limit = defaultVMPageSize
+		}
+		for {
+			page, err := c.ListVMsPage(ctx, opts)
+			if err != nil {
+				yield(VM{}, err)
+				return
+			}
+			for _, vm := range page.VMs {
+				if !yield(vm, nil) {
+					return
+				}
+			}
+			opts.Offset += len(page.VMs)
+			if len(page.VMs) < opts.Limit || opts.Offset >= page.Total {
+				return
+			}
+		}
+	}
+}
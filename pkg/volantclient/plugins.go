@@ -0,0 +1,214 @@
+// Copyright (c) 2025 HYPR. PTE. LTD.
+//
+// Business Source License 1.1
+// See LICENSE file in the project root for details.
+
+package volantclient
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// PluginArtifact records a file a plugin version boots from, such as its
+// root filesystem. The server sends the fields under their Go names.
+type PluginArtifact struct {
+	ID           int64
+	PluginName   string
+	Version      string
+	ArtifactName string
+	Kind         string
+	SourceURL    string
+	Checksum     string
+	Format       string
+	LocalPath    string
+	SizeBytes    int64
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+}
+
+// PluginArtifactRequest registers or replaces an artifact of a plugin
+// version.
+type PluginArtifactRequest struct {
+	Version      string `json:"version"`
+	ArtifactName string `json:"artifact_name"`
+	Kind         string `json:"kind,omitempty"`
+	SourceURL    string `json:"source_url,omitempty"`
+	Checksum     string `json:"checksum,omitempty"`
+	Format       string `json:"format,omitempty"`
+	LocalPath    string `json:"local_path,omitempty"`
+	SizeBytes    int64  `json:"size_bytes,omitempty"`
+}
+
+// GetPluginManifest returns the manifest of an enabled plugin; disabled
+// plugins are refused with code PLUGIN_DISABLED.
+func (c *Client) GetPluginManifest(ctx context.Context, name string) (*Plugin, error) {
+	req, err := c.newRequest(ctx, http.MethodGet, "/api/v1/plugins/"+url.PathEscape(name)+"/manifest", nil)
+	if err != nil {
+		return nil, err
+	}
+	var manifest Plugin
+	if err := c.do(req, &manifest); err != nil {
+		return nil, err
+	}
+	return &manifest, nil
+}
+
+// CallPluginAction invokes an action of a plugin's manifest outside any VM.
+// A nil result means the action was accepted without a response body.
+func (c *Client) CallPluginAction(ctx context.Context, plugin, action string, payload map[string]any) (map[string]any, error) {
+	return c.callAction(ctx, "/api/v1/plugins/"+url.PathEscape(plugin)+"/actions/"+url.PathEscape(action), payload)
+}
+
+// CallVMPluginAction invokes a plugin action inside a running VM.
+func (c *Client) CallVMPluginAction(ctx context.Context, vmName, plugin, action string, payload map[string]any) (map[string]any, error) {
+	return c.callAction(ctx, vmActionPath(vmName, plugin, action), payload)
+}
+
+// StreamVMPluginAction invokes a streaming plugin action inside a running
+// VM and returns the response body as the agent writes it; the caller must
+// close it. ctx bounds the whole stream.
+func (c *Client) StreamVMPluginAction(ctx context.Context, vmName, plugin, action string, payload map[string]any) (io.ReadCloser, error) {
+	if payload == nil {
+		payload = map[string]any{}
+	}
+	req, err := c.newRequest(ctx, http.MethodPost, vmActionPath(vmName, plugin, action), payload)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.transferClient().Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("client: do request: %w", err)
+	}
+	if resp.StatusCode >= 300 {
+		defer resp.Body.Close()
+		data, _ := io.ReadAll(resp.Body)
+		return nil, decodeAPIError(resp.StatusCode, data)
+	}
+	return resp.Body, nil
+}
+
+func vmActionPath(vmName, plugin, action string) string {
+	return "/api/v1/vms/" + url.PathEscape(vmName) + "/actions/" + url.PathEscape(plugin) + "/" + url.PathEscape(action)
+}
+
+func (c *Client) callAction(ctx context.Context, path string, payload map[string]any) (map[string]any, error) {
+	if payload == nil {
+		payload = map[string]any{}
+	}
+	req, err := c.newRequest(ctx, http.MethodPost, path, payload)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("client: do request: %w", err)
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if resp.StatusCode >= 300 {
+		return nil, decodeAPIError(resp.StatusCode, data)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("client: read response: %w", err)
+	}
+	if len(bytes.TrimSpace(data)) == 0 {
+		return nil, nil
+	}
+	var result map[string]any
+	if err := json.Unmarshal(data, &result); err != nil {
+		return nil, fmt.Errorf("client: decode response: %w", err)
+	}
+	return result, nil
+}
+
+// ListPluginArtifacts lists the artifacts of a plugin, of one version when
+// version is set.
+func (c *Client) ListPluginArtifacts(ctx context.Context, plugin, version string) ([]PluginArtifact, error) {
+	req, err := c.newRequest(ctx, http.MethodGet, pluginArtifactsPath(plugin, version), nil)
+	if err != nil {
+		return nil, err
+	}
+	var artifacts []PluginArtifact
+	if err := c.do(req, &artifacts); err != nil {
+		return nil, err
+	}
+	return artifacts, nil
+}
+
+// GetPluginArtifact returns one artifact of a plugin version.
+func (c *Client) GetPluginArtifact(ctx context.Context, plugin, version, artifact string) (*PluginArtifact, error) {
+	path := "/api/v1/plugins/" + url.PathEscape(plugin) + "/artifacts/" + url.PathEscape(artifact) + "?" + url.Values{"version": {version}}.Encode()
+	req, err := c.newRequest(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return nil, err
+	}
+	var record PluginArtifact
+	if err := c.do(req, &record); err != nil {
+		return nil, err
+	}
+	return &record, nil
+}
+
+// PutPluginArtifact registers an artifact of a plugin version, replacing
+// one of the same name.
+func (c *Client) PutPluginArtifact(ctx context.Context, plugin string, payload PluginArtifactRequest) error {
+	req, err := c.newRequest(ctx, http.MethodPost, pluginArtifactsPath(plugin, ""), payload)
+	if err != nil {
+		return err
+	}
+	return c.do(req, nil)
+}
+
+// DeletePluginArtifacts removes the artifacts of a plugin, of one version
+// when version is set.
+func (c *Client) DeletePluginArtifacts(ctx context.Context, plugin, version string) error {
+	req, err := c.newRequest(ctx, http.MethodDelete, pluginArtifactsPath(plugin, version), nil)
+	if err != nil {
+		return err
+	}
+	return c.do(req, nil)
+}
+
+func pluginArtifactsPath(plugin, version string) string {
+	path := "/api/v1/plugins/" + url.PathEscape(plugin) + "/artifacts"
+	if version != "" {
+		path += "?" + url.Values{"version": {version}}.Encode()
+	}
+	return path
+}
+
+// GetVMBundles returns the config bundles of a VM with their file contents,
+// as its agent fetches them at boot.
+func (c *Client) GetVMBundles(ctx context.Context, vmName string) ([]Bundle, error) {
+	req, err := c.newRequest(ctx, http.MethodGet, "/api/v1/vms/"+url.PathEscape(vmName)+"/bundles", nil)
+	if err != nil {
+		return nil, err
+	}
+	var bundles []Bundle
+	if err := c.do(req, &bundles); err != nil {
+		return nil, err
+	}
+	return bundles, nil
+}
+
+// GetVMSecrets returns the secrets mounted into a VM, values included. The
+// server only answers the VM itself, so this is for programs running in the
+// guest.
+func (c *Client) GetVMSecrets(ctx context.Context, vmName string) ([]MountedSecret, error) {
+	req, err := c.newRequest(ctx, http.MethodGet, "/api/v1/vms/"+url.PathEscape(vmName)+"/secrets", nil)
+	if err != nil {
+		return nil, err
+	}
+	var secrets []MountedSecret
+	if err := c.do(req, &secrets); err != nil {
+		return nil, err
+	}
+	return secrets, nil
+}
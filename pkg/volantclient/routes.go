@@ -0,0 +1,71 @@
+// Copyright (c) 2025 HYPR. PTE. LTD.
+//
+// Business Source License 1.1
+// See LICENSE file in the project root for details.
+
+package volantclient
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// RouteStatus is a drift route and the VM whose expose rules publish it,
+// if any.
+type RouteStatus struct {
+	Route
+	VM string `json:"vm,omitempty"`
+	// Published is false for a route a running VM calls for but driftd does
+	// not hold; SyncRoutes publishes it.
+	Published bool `json:"published"`
+}
+
+// ListRoutes lists the host port routes of the drift proxy, or only those
+// of vm when it is set.
+func (c *Client) ListRoutes(ctx context.Context, vm string) ([]RouteStatus, error) {
+	path := "/api/v1/routes"
+	if vm != "" {
+		path += "?" + url.Values{"vm": {vm}}.Encode()
+	}
+	req, err := c.newRequest(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return nil, err
+	}
+	var routes []RouteStatus
+	if err := c.do(req, &routes); err != nil {
+		return nil, err
+	}
+	return routes, nil
+}
+
+// SyncRoutes publishes the routes of every running VM again and returns
+// them.
+func (c *Client) SyncRoutes(ctx context.Context) ([]RouteStatus, error) {
+	var routes []RouteStatus
+	if err := c.post(ctx, "/api/v1/routes/sync", nil, &routes); err != nil {
+		return nil, err
+	}
+	return routes, nil
+}
+
+// UpsertRoute creates or replaces the drift route of a host port and
+// protocol. Routes of running VMs fail with ROUTE_MANAGED.
+func (c *Client) UpsertRoute(ctx context.Context, route Route) (*Route, error) {
+	var updated Route
+	if err := c.post(ctx, "/api/v1/routes", route, &updated); err != nil {
+		return nil, err
+	}
+	return &updated, nil
+}
+
+// DeleteRoute removes the drift route of a host port and protocol.
+func (c *Client) DeleteRoute(ctx context.Context, protocol string, hostPort uint16) error {
+	path := "/api/v1/routes/" + url.PathEscape(protocol) + "/" + strconv.FormatUint(uint64(hostPort), 10)
+	req, err := c.newRequest(ctx, http.MethodDelete, path, nil)
+	if err != nil {
+		return err
+	}
+	return c.do(req, nil)
+}
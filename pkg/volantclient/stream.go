@@ -0,0 +1,290 @@
+// Copyright (c) 2025 HYPR. PTE. LTD.
+//
+// Business Source License 1.1
+// See LICENSE file in the project root for details.
+
+package volantclient
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// Topics of the events stream.
+const (
+	EventTopicVM         = "vm"
+	EventTopicDeployment = "deployment"
+	EventTopicPlugin     = "plugin"
+	EventTopicSchedule   = "schedule"
+	EventTopicOperation  = "operation"
+	EventTopicSystem     = "system"
+)
+
+// Event is one message on the events stream. Data holds the event itself,
+// by Topic: a VMEvent, DeploymentEvent, PluginEvent, ScheduleEvent,
+// OperationEvent or GCEvent.
+type Event struct {
+	Topic     string          `json:"topic"`
+	Type      string          `json:"type"`
+	Name      string          `json:"name,omitempty"`
+	Timestamp time.Time       `json:"timestamp"`
+	Data      json.RawMessage `json:"data"`
+}
+
+// Decode unmarshals the event's data into v.
+func (e Event) Decode(v any) error {
+	if err := json.Unmarshal(e.Data, v); err != nil {
+		return fmt.Errorf("client: decode %s event: %w", e.Topic, err)
+	}
+	return nil
+}
+
+// ScheduleEvent reports a schedule firing.
+type ScheduleEvent struct {
+	Type       string    `json:"type"`
+	Schedule   string    `json:"schedule"`
+	Action     string    `json:"action"`
+	TargetKind string    `json:"target_kind"`
+	Target     string    `json:"target"`
+	Status     string    `json:"status"`
+	Message    string    `json:"message,omitempty"`
+	Timestamp  time.Time `json:"timestamp"`
+}
+
+// EventFilter narrows the events stream. Each field matches any of its
+// values and an empty field matches everything. VM log lines are only sent
+// when Types asks for VM_LOG, and VMs keeps only events about those VMs.
+type EventFilter struct {
+	Topics []string
+	Types  []string
+	VMs    []string
+}
+
+func (f EventFilter) query() string {
+	values := url.Values{}
+	if len(f.Topics) > 0 {
+		values.Set("topic", strings.Join(f.Topics, ","))
+	}
+	if len(f.Types) > 0 {
+		values.Set("type", strings.Join(f.Types, ","))
+	}
+	if len(f.VMs) > 0 {
+		values.Set("vm", strings.Join(f.VMs, ","))
+	}
+	return values.Encode()
+}
+
+// WatchEvents streams VM, deployment, plugin, schedule, operation and system
+// events matching filter until ctx is done or the server closes the stream.
+func (c *Client) WatchEvents(ctx context.Context, filter EventFilter, handler func(Event)) error {
+	if handler == nil {
+		return fmt.Errorf("client: handler required")
+	}
+	conn, err := c.dialWebSocket(ctx, "/ws/v1/events", filter.query())
+	if err != nil {
+		return fmt.Errorf("client: watch events dial: %w", err)
+	}
+	defer conn.Close()
+
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+			_ = conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""), time.Now().Add(time.Second))
+			_ = conn.Close()
+		case <-done:
+		}
+	}()
+
+	for {
+		var event Event
+		if err := conn.ReadJSON(&event); err != nil {
+			if websocket.IsCloseError(err, websocket.CloseNormalClosure, websocket.CloseGoingAway) || ctx.Err() != nil {
+				return nil
+			}
+			return fmt.Errorf("client: read event: %w", err)
+		}
+		handler(event)
+	}
+}
+
+// WatchVMEvents streams VM lifecycle events until ctx is done or the server
+// closes the stream.
+func (c *Client) WatchVMEvents(ctx context.Context, handler func(VMEvent)) error {
+	resp, err := c.openStream(ctx, "/api/v1/events/vms", nil)
+	if err != nil {
+		return streamResult(ctx, err)
+	}
+	defer resp.Body.Close()
+
+	err = readSSE(resp.Body, func(msg sseMessage) error {
+		var event VMEvent
+		if err := json.Unmarshal([]byte(msg.data), &event); err != nil {
+			return fmt.Errorf("client: decode event: %w", err)
+		}
+		if handler != nil {
+			handler(event)
+		}
+		return nil
+	})
+	return streamResult(ctx, err)
+}
+
+// AGUIStreamOptions narrows the AG-UI stream. LastEventID resumes after the
+// event with that Seq, replaying what the server still buffers.
+type AGUIStreamOptions struct {
+	VMs         []string
+	Types       []string
+	LastEventID uint64
+}
+
+// WatchAGUIEvents streams the AG-UI events that automation inside VMs
+// reports, until ctx is done or the server closes the stream. Each event's
+// Event field is the AG-UI event object, with vm added.
+func (c *Client) WatchAGUIEvents(ctx context.Context, opts AGUIStreamOptions, handler func(AGUIEvent)) error {
+	if handler == nil {
+		return fmt.Errorf("client: handler required")
+	}
+	values := url.Values{}
+	if len(opts.VMs) > 0 {
+		values.Set("vm", strings.Join(opts.VMs, ","))
+	}
+	if len(opts.Types) > 0 {
+		values.Set("type", strings.Join(opts.Types, ","))
+	}
+	header := http.Header{}
+	if opts.LastEventID > 0 {
+		header.Set("Last-Event-ID", strconv.FormatUint(opts.LastEventID, 10))
+	}
+	path := "/api/v1/agui/stream"
+	if len(values) > 0 {
+		path += "?" + values.Encode()
+	}
+	resp, err := c.openStream(ctx, path, header)
+	if err != nil {
+		return streamResult(ctx, err)
+	}
+	defer resp.Body.Close()
+
+	err = readSSE(resp.Body, func(msg sseMessage) error {
+		var fields struct {
+			VM   string `json:"vm"`
+			Type string `json:"type"`
+		}
+		if err := json.Unmarshal([]byte(msg.data), &fields); err != nil {
+			return fmt.Errorf("client: decode agui event: %w", err)
+		}
+		seq, _ := strconv.ParseUint(msg.id, 10, 64)
+		handler(AGUIEvent{Seq: seq, VM: fields.VM, Type: fields.Type, Event: json.RawMessage(msg.data)})
+		return nil
+	})
+	return streamResult(ctx, err)
+}
+
+// PostAGUIEvents reports AG-UI events on behalf of a VM. Each event must be
+// a JSON object with a type; the server adds vm and, when missing,
+// timestamp. It returns the number of events accepted.
+func (c *Client) PostAGUIEvents(ctx context.Context, vmName string, events []any) (int, error) {
+	if vmName == "" {
+		return 0, fmt.Errorf("client: vm name required")
+	}
+	req, err := c.newRequest(ctx, http.MethodPost, "/api/v1/vms/"+url.PathEscape(vmName)+"/agui/events", events)
+	if err != nil {
+		return 0, err
+	}
+	var resp struct {
+		Accepted int `json:"accepted"`
+	}
+	if err := c.do(req, &resp); err != nil {
+		return 0, err
+	}
+	return resp.Accepted, nil
+}
+
+// openStream starts a long-lived GET. It uses transferClient, since a
+// stream outlasts httpClient's timeout; ctx ends it instead.
+func (c *Client) openStream(ctx context.Context, path string, header http.Header) (*http.Response, error) {
+	req, err := c.newRequest(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return nil, err
+	}
+	for key, values := range header {
+		req.Header[key] = values
+	}
+	req.Header.Set("Accept", "text/event-stream")
+	resp, err := c.transferClient().Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("client: do request: %w", err)
+	}
+	if resp.StatusCode >= 300 {
+		defer resp.Body.Close()
+		data, _ := io.ReadAll(resp.Body)
+		return nil, decodeAPIError(resp.StatusCode, data)
+	}
+	return resp, nil
+}
+
+// sseMessage is one server-sent event.
+type sseMessage struct {
+	id   string
+	data string
+}
+
+// readSSE calls fn for every event read from r until r ends or fn fails.
+// Comments and events without data, such as keepalives, are skipped.
+func readSSE(r io.Reader, fn func(sseMessage) error) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1<<20)
+	var (
+		msg  sseMessage
+		data []string
+	)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" {
+			if len(data) > 0 {
+				msg.data = strings.Join(data, "\n")
+				if err := fn(msg); err != nil {
+					return err
+				}
+			}
+			msg, data = sseMessage{}, nil
+			continue
+		}
+		if strings.HasPrefix(line, ":") {
+			continue
+		}
+		field, value, _ := strings.Cut(line, ":")
+		value = strings.TrimPrefix(value, " ")
+		switch field {
+		case "id":
+			msg.id = value
+		case "data":
+			data = append(data, value)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("client: read stream: %w", err)
+	}
+	return nil
+}
+
+// streamResult reports why a stream ended: ctx's error once it is done,
+// since cancelling breaks the read, or else err.
+func streamResult(ctx context.Context, err error) error {
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	return err
+}
@@ -0,0 +1,291 @@
+// Copyright (c) 2025 HYPR. PTE. LTD.
+//
+// Business Source License 1.1
+// See LICENSE file in the project root for details.
+
+package volantclient
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// HostFeature is whether the host offers one kernel feature plugins may
+// need, with a hint at enabling it when it does not.
+type HostFeature struct {
+	Name      string `json:"name"`
+	Available bool   `json:"available"`
+	Detail    string `json:"detail,omitempty"`
+	Hint      string `json:"hint,omitempty"`
+}
+
+// HostFeatures is the host feature probe volantd ran at startup.
+type HostFeatures struct {
+	ProbedAt time.Time     `json:"probed_at"`
+	Features []HostFeature `json:"features"`
+}
+
+// SystemInfo describes where volantd listens and what its host supports.
+type SystemInfo struct {
+	Status           string        `json:"status"`
+	APIListenAddr    string        `json:"api_listen_addr"`
+	APIAdvertiseAddr string        `json:"api_advertise_addr"`
+	HostIP           string        `json:"host_ip"`
+	HostFeatures     *HostFeatures `json:"host_features,omitempty"`
+}
+
+// PluginSummary is one installed plugin in a SystemSummary.
+type PluginSummary struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+	Enabled bool   `json:"enabled"`
+}
+
+// ScratchUsage totals the scratch disks on the host.
+type ScratchUsage struct {
+	Disks  int   `json:"disks"`
+	SizeMB int64 `json:"size_mb"`
+}
+
+// SystemSummary counts VMs and plugins.
+type SystemSummary struct {
+	TotalVMs       int             `json:"total_vms"`
+	ByStatus       map[string]int  `json:"by_status"`
+	ByRuntime      map[string]int  `json:"by_runtime"`
+	TotalPlugins   int             `json:"total_plugins"`
+	EnabledPlugins int             `json:"enabled_plugins"`
+	Plugins        []PluginSummary `json:"plugins"`
+	Scratch        ScratchUsage    `json:"scratch"`
+}
+
+// AgentStats is the connection and circuit breaker state of one VM's agent.
+type AgentStats struct {
+	VM                  string     `json:"vm,omitempty"`
+	Host                string     `json:"host"`
+	State               string     `json:"state"`
+	InFlight            int        `json:"in_flight"`
+	Requests            uint64     `json:"requests"`
+	Failures            uint64     `json:"failures"`
+	Rejected            uint64     `json:"rejected"`
+	ConsecutiveFailures int        `json:"consecutive_failures"`
+	RetryAfterSeconds   int        `json:"retry_after_seconds,omitempty"`
+	LastError           string     `json:"last_error,omitempty"`
+	LastFailureAt       *time.Time `json:"last_failure_at,omitempty"`
+}
+
+// Dashboard is the overview the web UI renders, in one response.
+type Dashboard struct {
+	GeneratedAt  time.Time             `json:"generated_at"`
+	VMs          DashboardVMs          `json:"vms"`
+	Deployments  []DashboardDeployment `json:"deployments"`
+	RecentEvents []VMEvent             `json:"recent_events"`
+	Plugins      []DashboardPlugin     `json:"plugins"`
+	Host         HostUtilization       `json:"host"`
+}
+
+// DashboardVMs counts the VMs by status and lists them.
+type DashboardVMs struct {
+	Total    int            `json:"total"`
+	ByStatus map[string]int `json:"by_status"`
+	Items    []DashboardVM  `json:"items"`
+}
+
+// DashboardVM is one VM on the dashboard.
+type DashboardVM struct {
+	Name      string `json:"name"`
+	Status    string `json:"status"`
+	Runtime   string `json:"runtime"`
+	IPAddress string `json:"ip_address,omitempty"`
+	CPUCores  int    `json:"cpu_cores"`
+	MemoryMB  int    `json:"memory_mb"`
+}
+
+// DashboardDeployment is one deployment on the dashboard. State is "ready"
+// when every desired replica is ready and "progressing" otherwise.
+type DashboardDeployment struct {
+	Name            string `json:"name"`
+	DesiredReplicas int    `json:"desired_replicas"`
+	ReadyReplicas   int    `json:"ready_replicas"`
+	State           string `json:"state"`
+}
+
+// DashboardPlugin is one plugin on the dashboard with the health of its
+// VMs: healthy, degraded or disabled.
+type DashboardPlugin struct {
+	Name       string `json:"name"`
+	Version    string `json:"version"`
+	Enabled    bool   `json:"enabled"`
+	Health     string `json:"health"`
+	VMs        int    `json:"vms"`
+	RunningVMs int    `json:"running_vms"`
+	CrashedVMs int    `json:"crashed_vms"`
+}
+
+// HostUtilization is the host's load next to what running VMs were
+// allocated.
+type HostUtilization struct {
+	CPUs              int       `json:"cpus"`
+	LoadAverage       []float64 `json:"load_average,omitempty"`
+	MemoryTotalMB     int       `json:"memory_total_mb,omitempty"`
+	MemoryAvailableMB int       `json:"memory_available_mb,omitempty"`
+	AllocatedCPUs     int       `json:"allocated_cpus"`
+	AllocatedMemoryMB int       `json:"allocated_memory_mb"`
+}
+
+// DHCPLease is an address the built-in DHCP server handed to a VM.
+type DHCPLease struct {
+	MACAddress string    `json:"mac_address"`
+	IPAddress  string    `json:"ip_address"`
+	VM         string    `json:"vm"`
+	Hostname   string    `json:"hostname,omitempty"`
+	ExpiresAt  time.Time `json:"expires_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// NodeArtifact is a boot artifact staged on a node, such as a kernel or a
+// root filesystem image.
+type NodeArtifact struct {
+	Kind       string    `json:"kind"`
+	Path       string    `json:"path"`
+	SizeBytes  int64     `json:"size_bytes"`
+	ModifiedAt time.Time `json:"modified_at"`
+}
+
+// NodeArtifacts lists the artifacts of one node.
+type NodeArtifacts struct {
+	Node      string         `json:"node"`
+	Artifacts []NodeArtifact `json:"artifacts"`
+}
+
+// Health checks that volantd is up.
+func (c *Client) Health(ctx context.Context) error {
+	req, err := c.newRequest(ctx, http.MethodGet, "/healthz", nil)
+	if err != nil {
+		return err
+	}
+	return c.do(req, nil)
+}
+
+// GetSystemInfo returns the addresses volantd uses and its host features.
+func (c *Client) GetSystemInfo(ctx context.Context) (*SystemInfo, error) {
+	req, err := c.newRequest(ctx, http.MethodGet, "/api/v1/system/info", nil)
+	if err != nil {
+		return nil, err
+	}
+	var info SystemInfo
+	if err := c.do(req, &info); err != nil {
+		return nil, err
+	}
+	return &info, nil
+}
+
+// GetSystemSummary counts VMs by status and runtime and lists the plugins.
+func (c *Client) GetSystemSummary(ctx context.Context) (*SystemSummary, error) {
+	req, err := c.newRequest(ctx, http.MethodGet, "/api/v1/system/summary", nil)
+	if err != nil {
+		return nil, err
+	}
+	var summary SystemSummary
+	if err := c.do(req, &summary); err != nil {
+		return nil, err
+	}
+	return &summary, nil
+}
+
+// GetAgentStats reports the agent connection state of the named VMs, or of
+// every VM when vms is empty.
+func (c *Client) GetAgentStats(ctx context.Context, vms ...string) ([]AgentStats, error) {
+	path := "/api/v1/system/agents"
+	if len(vms) > 0 {
+		path += "?" + url.Values{"vm": {strings.Join(vms, ",")}}.Encode()
+	}
+	req, err := c.newRequest(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return nil, err
+	}
+	var resp struct {
+		Agents []AgentStats `json:"agents"`
+	}
+	if err := c.do(req, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Agents, nil
+}
+
+// GetDashboard returns VMs, deployments, plugins, recent events and host
+// load in one call.
+func (c *Client) GetDashboard(ctx context.Context) (*Dashboard, error) {
+	req, err := c.newRequest(ctx, http.MethodGet, "/api/v1/dashboard", nil)
+	if err != nil {
+		return nil, err
+	}
+	var dashboard Dashboard
+	if err := c.do(req, &dashboard); err != nil {
+		return nil, err
+	}
+	return &dashboard, nil
+}
+
+// ListDHCPLeases lists the leases of the built-in DHCP server.
+func (c *Client) ListDHCPLeases(ctx context.Context) ([]DHCPLease, error) {
+	req, err := c.newRequest(ctx, http.MethodGet, "/api/v1/dhcp/leases", nil)
+	if err != nil {
+		return nil, err
+	}
+	var leases []DHCPLease
+	if err := c.do(req, &leases); err != nil {
+		return nil, err
+	}
+	return leases, nil
+}
+
+// ListNodeArtifacts lists the boot artifacts staged on node: "local" or the
+// host's name.
+func (c *Client) ListNodeArtifacts(ctx context.Context, node string) (*NodeArtifacts, error) {
+	req, err := c.newRequest(ctx, http.MethodGet, "/api/v1/nodes/"+url.PathEscape(node)+"/artifacts", nil)
+	if err != nil {
+		return nil, err
+	}
+	var artifacts NodeArtifacts
+	if err := c.do(req, &artifacts); err != nil {
+		return nil, err
+	}
+	return &artifacts, nil
+}
+
+// GetMetrics returns the guest metrics of every VM in the Prometheus text
+// format.
+func (c *Client) GetMetrics(ctx context.Context) ([]byte, error) {
+	return c.getRaw(ctx, "/metrics")
+}
+
+// GetOpenAPI returns the OpenAPI document of the volantd API as JSON.
+func (c *Client) GetOpenAPI(ctx context.Context) ([]byte, error) {
+	return c.getRaw(ctx, "/openapi")
+}
+
+// getRaw returns the body of a GET unparsed.
+func (c *Client) getRaw(ctx context.Context, path string) ([]byte, error) {
+	req, err := c.newRequest(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("client: do request: %w", err)
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if resp.StatusCode >= 300 {
+		return nil, decodeAPIError(resp.StatusCode, data)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("client: read response: %w", err)
+	}
+	return data, nil
+}
@@ -0,0 +1,98 @@
+// Copyright (c) 2025 HYPR. PTE. LTD.
+//
+// Business Source License 1.1
+// See LICENSE file in the project root for details.
+
+package volantclient
+
+import (
+	"github.com/volantvm/volant/internal/drift/routes"
+	"github.com/volantvm/volant/internal/pluginspec"
+	orchestratorevents "github.com/volantvm/volant/internal/server/orchestrator/events"
+	"github.com/volantvm/volant/internal/server/orchestrator/vmconfig"
+)
+
+// The API shares these types with volantd. They are aliased here so programs
+// outside this module, which cannot import volantd's internal packages, can
+// name them.
+
+// VM configuration.
+type (
+	VMConfig             = vmconfig.Config
+	VMConfigPatch        = vmconfig.Patch
+	VersionedVMConfig    = vmconfig.Versioned
+	VMConfigHistoryEntry = vmconfig.HistoryEntry
+	VMResources          = vmconfig.Resources
+	VMResourcesPatch     = vmconfig.ResourcesPatch
+	VMAPI                = vmconfig.API
+	VMAPIPatch           = vmconfig.APIPatch
+	VMConsole            = vmconfig.Console
+	VMSSH                = vmconfig.SSH
+	Expose               = vmconfig.Expose
+	PortMapping          = vmconfig.PortMapping
+	ScratchDisk          = vmconfig.ScratchDisk
+	Spread               = vmconfig.Spread
+)
+
+// Plugin manifests. Plugin itself is the manifest.
+type (
+	RootFS            = pluginspec.RootFS
+	Initramfs         = pluginspec.Initramfs
+	Kernel            = pluginspec.Kernel
+	Disk              = pluginspec.Disk
+	Share             = pluginspec.Share
+	SecretMount       = pluginspec.SecretMount
+	MountedSecret     = pluginspec.MountedSecret
+	ResourceSpec      = pluginspec.ResourceSpec
+	PluginLimits      = pluginspec.Limits
+	PluginAction      = pluginspec.Action
+	PluginHealthCheck = pluginspec.HealthCheck
+	PluginHooks       = pluginspec.Hooks
+	PluginHook        = pluginspec.Hook
+	PluginExec        = pluginspec.Exec
+	PluginMetrics     = pluginspec.Metrics
+	PluginAgent       = pluginspec.Agent
+	PluginWorkload    = pluginspec.Workload
+	CloudInit         = pluginspec.CloudInit
+	CloudInitDoc      = pluginspec.CloudInitDoc
+	NetworkConfig     = pluginspec.NetworkConfig
+	NetworkMode       = pluginspec.NetworkMode
+	NetworkInterface  = pluginspec.NetworkInterface
+	EgressPolicy      = pluginspec.EgressPolicy
+	EgressRule        = pluginspec.EgressRule
+	DeviceConfig      = pluginspec.DeviceConfig
+	DeviceRequest     = pluginspec.DeviceRequest
+	Bundle            = pluginspec.Bundle
+	BundleFile        = pluginspec.BundleFile
+)
+
+// Guest agent calls.
+type (
+	ExecRequest    = pluginspec.ExecRequest
+	ExecResult     = pluginspec.ExecResult
+	GuestMetrics   = pluginspec.GuestMetrics
+	GuestCPU       = pluginspec.GuestCPU
+	GuestMemory    = pluginspec.GuestMemory
+	GuestDisk      = pluginspec.GuestDisk
+	GuestProcesses = pluginspec.GuestProcesses
+)
+
+// Events.
+type (
+	DeploymentEvent = orchestratorevents.DeploymentEvent
+	PluginEvent     = orchestratorevents.PluginEvent
+	OperationEvent  = orchestratorevents.OperationEvent
+	GCEvent         = orchestratorevents.GCEvent
+	AGUIEvent       = orchestratorevents.AGUIEvent
+)
+
+// Drift routes.
+type (
+	Route            = routes.Route
+	RouteBackend     = routes.Backend
+	RouteBackendType = routes.BackendType
+	RouteL7          = routes.L7
+	RouteL7Rule      = routes.L7Rule
+	RouteL7Target    = routes.L7Target
+	RouteHealthCheck = routes.HealthCheck
+)
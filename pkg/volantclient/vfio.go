@@ -0,0 +1,133 @@
+// Copyright (c) 2025 HYPR. PTE. LTD.
+//
+// Business Source License 1.1
+// See LICENSE file in the project root for details.
+
+package volantclient
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// VFIODevice describes a PCI device on the host.
+type VFIODevice struct {
+	Address    string `json:"address"`
+	Vendor     string `json:"vendor"`
+	Device     string `json:"device"`
+	Driver     string `json:"driver"`
+	IOMMUGroup string `json:"iommu_group"`
+	NumaNode   string `json:"numa_node"`
+}
+
+// VFIOValidation reports whether devices can be passed through.
+type VFIOValidation struct {
+	Valid   bool     `json:"valid"`
+	Message string   `json:"message,omitempty"`
+	Errors  []string `json:"errors,omitempty"`
+}
+
+// IOMMUGroup lists the devices sharing an IOMMU group, which can only be
+// passed through together.
+type IOMMUGroup struct {
+	ID      string   `json:"id"`
+	Devices []string `json:"devices"`
+}
+
+// VFIOBindResult reports binding devices to or unbinding them from the
+// vfio-pci driver. Devices lists the addresses affected.
+type VFIOBindResult struct {
+	Success bool     `json:"success"`
+	Message string   `json:"message,omitempty"`
+	Devices []string `json:"bound_devices,omitempty"`
+}
+
+// VFIOAssignment records a device passed through to a VM.
+type VFIOAssignment struct {
+	PCIAddress string    `json:"pci_address"`
+	VM         string    `json:"vm"`
+	IOMMUGroup string    `json:"iommu_group,omitempty"`
+	AssignedAt time.Time `json:"assigned_at"`
+}
+
+// GetVFIODevice describes the PCI device at pciAddress, e.g. 0000:01:00.0.
+func (c *Client) GetVFIODevice(ctx context.Context, pciAddress string) (*VFIODevice, error) {
+	var device VFIODevice
+	if err := c.post(ctx, "/api/v1/vfio/devices/info", map[string]string{"pci_address": pciAddress}, &device); err != nil {
+		return nil, err
+	}
+	return &device, nil
+}
+
+// ValidateVFIODevices checks that devices exist and can be passed through.
+// A non-empty allowlist also requires each device's vendor:device ID to be
+// on it, either exactly (10de:2204) or by vendor (10de:*). Invalid devices
+// are reported in the result, not as an error.
+func (c *Client) ValidateVFIODevices(ctx context.Context, pciAddresses, allowlist []string) (*VFIOValidation, error) {
+	body := map[string][]string{"pci_addresses": pciAddresses}
+	if len(allowlist) > 0 {
+		body["allowlist"] = allowlist
+	}
+	var result VFIOValidation
+	if err := c.post(ctx, "/api/v1/vfio/devices/validate", body, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// VFIOIOMMUGroups returns the IOMMU groups of devices.
+func (c *Client) VFIOIOMMUGroups(ctx context.Context, pciAddresses []string) ([]IOMMUGroup, error) {
+	var groups []IOMMUGroup
+	if err := c.post(ctx, "/api/v1/vfio/devices/iommu-groups", map[string][]string{"pci_addresses": pciAddresses}, &groups); err != nil {
+		return nil, err
+	}
+	return groups, nil
+}
+
+// BindVFIODevices binds devices to the vfio-pci driver.
+func (c *Client) BindVFIODevices(ctx context.Context, pciAddresses []string) (*VFIOBindResult, error) {
+	var result VFIOBindResult
+	if err := c.post(ctx, "/api/v1/vfio/devices/bind", map[string][]string{"pci_addresses": pciAddresses}, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// UnbindVFIODevices returns devices to their host drivers. Devices assigned
+// to a VM are refused with code DEVICE_CLAIMED.
+func (c *Client) UnbindVFIODevices(ctx context.Context, pciAddresses []string) (*VFIOBindResult, error) {
+	var result struct {
+		Success bool     `json:"success"`
+		Message string   `json:"message,omitempty"`
+		Devices []string `json:"unbound_devices,omitempty"`
+	}
+	if err := c.post(ctx, "/api/v1/vfio/devices/unbind", map[string][]string{"pci_addresses": pciAddresses}, &result); err != nil {
+		return nil, err
+	}
+	return &VFIOBindResult{Success: result.Success, Message: result.Message, Devices: result.Devices}, nil
+}
+
+// VFIOGroupPaths returns the /dev/vfio group files of devices.
+func (c *Client) VFIOGroupPaths(ctx context.Context, pciAddresses []string) ([]string, error) {
+	var result struct {
+		GroupPaths []string `json:"group_paths"`
+	}
+	if err := c.post(ctx, "/api/v1/vfio/devices/group-paths", map[string][]string{"pci_addresses": pciAddresses}, &result); err != nil {
+		return nil, err
+	}
+	return result.GroupPaths, nil
+}
+
+// ListVFIOAssignments lists the devices passed through to VMs.
+func (c *Client) ListVFIOAssignments(ctx context.Context) ([]VFIOAssignment, error) {
+	req, err := c.newRequest(ctx, http.MethodGet, "/api/v1/vfio/assignments", nil)
+	if err != nil {
+		return nil, err
+	}
+	var assignments []VFIOAssignment
+	if err := c.do(req, &assignments); err != nil {
+		return nil, err
+	}
+	return assignments, nil
+}